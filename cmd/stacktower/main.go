@@ -17,23 +17,36 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
-	if err := run(ctx); err != nil {
-		fmt.Fprintln(os.Stderr, ui.StyleError.Render("Error:")+fmt.Sprintf(" %s", err))
-		os.Exit(cli.ExitCodeForError(err))
+	err := run(ctx)
+	code := cli.ExitCodeForError(err)
+	ui.PrintWarningsSummary(code)
+	if err != nil {
+		if ui.IsJSON() {
+			ui.Event("error", map[string]any{"msg": err.Error(), "exit_code": code})
+		} else {
+			fmt.Fprintln(os.Stderr, ui.StyleError.Render("Error:")+fmt.Sprintf(" %s", err))
+		}
+		os.Exit(code)
 	}
 }
 
 func run(ctx context.Context) error {
 	var (
-		verbose bool
-		quiet   bool
+		verbose   bool
+		quiet     bool
+		strict    bool
+		logFormat string
 	)
 
 	c := cli.New(os.Stderr, cli.LogInfo)
+	c.LoadPlugins()
 	root := c.RootCommand()
 
 	root.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose logging")
 	root.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "suppress non-essential output")
+	root.PersistentFlags().BoolVar(&strict, "strict", false, "exit with code 7 when the run succeeds with warnings")
+	root.PersistentFlags().StringVar(&logFormat, "log-format", "text", "log and status output on stderr: text or json (NDJSON events)")
+	_ = root.RegisterFlagCompletionFunc("log-format", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
 
 	originalPreRun := root.PersistentPreRunE
 	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
@@ -43,6 +56,13 @@ func run(ctx context.Context) error {
 		}
 		c.SetLogLevel(level)
 		c.SetQuiet(quiet)
+		switch logFormat {
+		case "text":
+		case "json":
+			c.SetJSONLogs(true)
+		default:
+			return cli.NewUserError(fmt.Sprintf("invalid log format %q", logFormat), "Use text or json.")
+		}
 
 		if originalPreRun != nil {
 			return originalPreRun(cmd, args)
@@ -50,5 +70,11 @@ func run(ctx context.Context) error {
 		return nil
 	}
 
-	return root.ExecuteContext(ctx)
+	if err := root.ExecuteContext(ctx); err != nil {
+		return err
+	}
+	if n := len(ui.Warnings()); strict && n > 0 {
+		return &cli.WarningsError{Count: n}
+	}
+	return nil
 }
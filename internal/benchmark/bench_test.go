@@ -0,0 +1,133 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/dag/gen"
+	"github.com/stacktower-io/stacktower/pkg/core/dag/transform"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/ordering"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/sink"
+)
+
+const (
+	frameWidth  = 1200
+	frameHeight = 800
+)
+
+// optimalTimeout bounds OptimalSearch well below the CLI's 60 seconds.
+// Graphs whose search runs into it measure the timeout rather than the
+// search, which is why the large graphs are skipped.
+const optimalTimeout = 5 * time.Second
+
+// eachGraph runs fn as a sub-benchmark per corpus graph, passing the raw
+// graph. fn must not modify it; clone first.
+func eachGraph(b *testing.B, fn func(b *testing.B, c Graph, g *dag.DAG)) {
+	b.Helper()
+	for _, c := range Corpus() {
+		g, err := c.Load()
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(c.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			fn(b, c, g)
+		})
+	}
+}
+
+// normalized returns a normalized copy of g.
+func normalized(b *testing.B, g *dag.DAG) *dag.DAG {
+	b.Helper()
+	n := g.Clone()
+	if _, err := transform.Normalize(n); err != nil {
+		b.Fatal(err)
+	}
+	return n
+}
+
+func BenchmarkResolve(b *testing.B) {
+	ctx := context.Background()
+	eachGraph(b, func(b *testing.B, _ Graph, g *dag.DAG) {
+		resolver := NewRegistry(g).Resolver()
+		root := g.Sources()[0].ID
+		for b.Loop() {
+			if _, err := resolver.Resolve(ctx, root, deps.Options{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkNormalize(b *testing.B) {
+	eachGraph(b, func(b *testing.B, _ Graph, g *dag.DAG) {
+		for b.Loop() {
+			b.StopTimer()
+			work := g.Clone()
+			b.StartTimer()
+			if _, err := transform.Normalize(work); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkOrderBarycentric(b *testing.B) {
+	eachGraph(b, func(b *testing.B, _ Graph, g *dag.DAG) {
+		work := normalized(b, g)
+		for b.Loop() {
+			ordering.Barycentric{}.OrderRows(work)
+		}
+	})
+}
+
+func BenchmarkOrderOptimal(b *testing.B) {
+	eachGraph(b, func(b *testing.B, c Graph, g *dag.DAG) {
+		if c.Size == Large {
+			b.Skip("exceeds the search budget")
+		}
+		work := normalized(b, g)
+		for b.Loop() {
+			ordering.OptimalSearch{Timeout: optimalTimeout}.OrderRows(work)
+		}
+	})
+}
+
+func BenchmarkLayout(b *testing.B) {
+	eachGraph(b, func(b *testing.B, _ Graph, g *dag.DAG) {
+		work := normalized(b, g)
+		for b.Loop() {
+			layout.Build(work, frameWidth, frameHeight, layout.WithOrderer(ordering.Barycentric{}))
+		}
+	})
+}
+
+func BenchmarkRenderSVG(b *testing.B) {
+	eachGraph(b, func(b *testing.B, _ Graph, g *dag.DAG) {
+		work := normalized(b, g)
+		l := layout.Build(work, frameWidth, frameHeight, layout.WithOrderer(ordering.Barycentric{}))
+		for b.Loop() {
+			sink.RenderSVG(l, sink.WithGraph(work), sink.WithPopups())
+		}
+	})
+}
+
+// BenchmarkOrderBarycentricTangle orders generated graphs of equal size
+// and rising tangle density, which the corpus cannot vary on its own.
+func BenchmarkOrderBarycentricTangle(b *testing.B) {
+	for _, tangle := range []float64{0, 0.25, 0.5} {
+		g := gen.Generate(gen.Options{Depth: 8, Width: 12, Tangle: tangle, Seed: 1, Now: time.Unix(0, 0)})
+		b.Run(fmt.Sprintf("tangle-%.2f", tangle), func(b *testing.B) {
+			b.ReportAllocs()
+			work := normalized(b, g)
+			for b.Loop() {
+				ordering.Barycentric{}.OrderRows(work)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package benchmark
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+//go:embed testdata
+var corpusFS embed.FS
+
+// Size classes of corpus graphs.
+const (
+	Small  = "small"  // the original graph, under 25 packages
+	Medium = "medium" // about 150 packages
+	Large  = "large"  // about 1000 packages
+)
+
+var sizeOrder = []string{Small, Medium, Large}
+
+// Graph is a corpus entry.
+type Graph struct {
+	Name      string // ecosystem and size, like "python-large"
+	Ecosystem string
+	Size      string
+	file      string
+}
+
+// Corpus returns the corpus graphs, by ecosystem and then by size.
+func Corpus() []Graph {
+	entries, err := fs.ReadDir(corpusFS, "testdata")
+	if err != nil {
+		panic(err) // embedded, so only a build problem gets here
+	}
+	var graphs []Graph
+	for _, e := range entries {
+		name := strings.TrimSuffix(strings.TrimSuffix(e.Name(), ".gz"), ".json")
+		eco, size, ok := strings.Cut(name, "-")
+		if !ok {
+			continue
+		}
+		graphs = append(graphs, Graph{Name: name, Ecosystem: eco, Size: size, file: "testdata/" + e.Name()})
+	}
+	slices.SortFunc(graphs, func(a, b Graph) int {
+		if c := strings.Compare(a.Ecosystem, b.Ecosystem); c != 0 {
+			return c
+		}
+		return slices.Index(sizeOrder, a.Size) - slices.Index(sizeOrder, b.Size)
+	})
+	return graphs
+}
+
+// Load decodes the graph. Each call returns a fresh DAG.
+func (g Graph) Load() (*dag.DAG, error) {
+	f, err := corpusFS.Open(g.file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	d, err := graph.ReadGraph(f)
+	if err != nil {
+		return nil, fmt.Errorf("corpus %s: %w", g.Name, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,73 @@
+package benchmark
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag/transform"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+)
+
+func TestCorpus(t *testing.T) {
+	sizes := map[string][2]int{Small: {1, 25}, Medium: {100, 300}, Large: {800, 1500}}
+	perEcosystem := map[string]int{}
+
+	for _, c := range Corpus() {
+		t.Run(c.Name, func(t *testing.T) {
+			g, err := c.Load()
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got := len(g.Sources()); got != 1 {
+				t.Errorf("got %d roots, want 1", got)
+			}
+			// Real graphs may have cycles (cobra's does); Normalize breaks them.
+			norm := g.Clone()
+			if _, err := transform.Normalize(norm); err != nil {
+				t.Fatalf("Normalize() error = %v", err)
+			}
+			if err := norm.Validate(); err != nil {
+				t.Errorf("Validate() after Normalize error = %v", err)
+			}
+			bounds, ok := sizes[c.Size]
+			if !ok {
+				t.Fatalf("unknown size %q", c.Size)
+			}
+			if n := g.NodeCount(); n < bounds[0] || n > bounds[1] {
+				t.Errorf("NodeCount() = %d, want %d..%d for %s", n, bounds[0], bounds[1], c.Size)
+			}
+		})
+		perEcosystem[c.Ecosystem]++
+	}
+
+	if len(perEcosystem) < 7 {
+		t.Errorf("corpus covers %d ecosystems, want 7", len(perEcosystem))
+	}
+	for eco, n := range perEcosystem {
+		if n != len(sizeOrder) {
+			t.Errorf("%s has %d graphs, want %d", eco, n, len(sizeOrder))
+		}
+	}
+}
+
+func TestRegistry_ResolvesCorpusGraph(t *testing.T) {
+	for _, c := range Corpus() {
+		if c.Size == Large {
+			continue
+		}
+		t.Run(c.Name, func(t *testing.T) {
+			g, err := c.Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := NewRegistry(g).Resolver().Resolve(context.Background(), g.Sources()[0].ID, deps.Options{})
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if got.NodeCount() != g.NodeCount() || got.EdgeCount() != g.EdgeCount() {
+				t.Errorf("resolved %d nodes and %d edges, want %d and %d",
+					got.NodeCount(), got.EdgeCount(), g.NodeCount(), g.EdgeCount())
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// Package benchmark holds the performance regression suite: a corpus of
+// anonymized real-world dependency graphs and Go benchmarks for each stage
+// of the pipeline.
+//
+// # Corpus
+//
+// [Corpus] lists one small, medium and large graph per ecosystem. The small
+// graphs are the resolved packages under examples/real with package names
+// replaced by stable pseudonyms and metadata reduced to what the renderers
+// read (version, license, downloads, stars and activity dates). The medium
+// (~150 packages) and large (~1000 packages) graphs stitch copies of the
+// small one together: each copy hangs below a package of an earlier copy
+// and shares about a third of its packages with them, so the graphs keep
+// the fan-out, depth and diamonds of the original instead of growing flat.
+//
+// # Benchmarks
+//
+// The benchmarks cover mocked resolution, normalization, row ordering,
+// layout and SVG rendering, each as one sub-benchmark per corpus graph:
+//
+//	go test -run '^$' -bench . -benchmem ./internal/benchmark
+//	go test -run '^$' -bench 'Order.*/large' ./internal/benchmark
+//
+// BenchmarkOrderBarycentricTangle adds graphs from the gen package whose
+// tangle density rises at a fixed size.
+//
+// Compare runs before and after a change with benchstat. Resolution uses a
+// [Registry] built from the graph itself, so it measures the resolver and
+// not the network.
+package benchmark
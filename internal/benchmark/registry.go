@@ -0,0 +1,73 @@
+package benchmark
+
+import (
+	"context"
+
+	"github.com/contriboss/pubgrub-go"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+// Registry is an in-memory package registry built from a graph: every node
+// is a package with a single version whose dependencies are its children.
+// It implements [deps.Fetcher] and [deps.VersionLister], so resolving the
+// graph's root with it reproduces the graph without network access.
+type Registry struct {
+	packages map[string]*deps.Package
+}
+
+// NewRegistry returns a registry serving the packages of g.
+func NewRegistry(g *dag.DAG) *Registry {
+	r := &Registry{packages: make(map[string]*deps.Package, g.NodeCount())}
+	for _, n := range g.NodesIter() {
+		version, _ := n.Meta["version"].(string)
+		if version == "" {
+			version = "1.0.0"
+		}
+		pkg := &deps.Package{Name: n.ID, Version: version}
+		for _, c := range g.Children(n.ID) {
+			pkg.Dependencies = append(pkg.Dependencies, deps.Dependency{Name: c})
+		}
+		r.packages[n.ID] = pkg
+	}
+	return r
+}
+
+// Resolver returns a PubGrub resolver backed by the registry.
+func (r *Registry) Resolver() deps.Resolver {
+	res, err := deps.NewPubGrubResolver("corpus", r, anyVersion{})
+	if err != nil {
+		panic(err) // only fails for a nil fetcher or parser
+	}
+	return res
+}
+
+func (r *Registry) Fetch(_ context.Context, name string, _ bool) (*deps.Package, error) {
+	if pkg, ok := r.packages[name]; ok {
+		return pkg, nil
+	}
+	return nil, integrations.ErrNotFound
+}
+
+func (r *Registry) FetchVersion(_ context.Context, name, version string, _ bool) (*deps.Package, error) {
+	if pkg, ok := r.packages[name]; ok && pkg.Version == version {
+		return pkg, nil
+	}
+	return nil, integrations.ErrNotFound
+}
+
+func (r *Registry) ListVersions(_ context.Context, name string, _ bool) ([]string, error) {
+	if pkg, ok := r.packages[name]; ok {
+		return []string{pkg.Version}, nil
+	}
+	return nil, integrations.ErrNotFound
+}
+
+// anyVersion parses versions as opaque strings and accepts every version,
+// since each registry package has exactly one.
+type anyVersion struct{}
+
+func (anyVersion) ParseVersion(v string) pubgrub.Version    { return pubgrub.SimpleVersion(v) }
+func (anyVersion) ParseConstraint(string) pubgrub.Condition { return nil }
@@ -126,7 +126,7 @@ func (c *CLI) cacheStatsCommand() *cobra.Command {
 			)
 
 			err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-				if err != nil || info.IsDir() {
+				if err != nil || info.IsDir() || path == filepath.Join(dir, packageIndexFile) {
 					return nil
 				}
 				count++
@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacktower-io/stacktower/internal/cli/ui"
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/languages"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+	"github.com/stacktower-io/stacktower/pkg/policy"
+)
+
+// checkFlags holds how check resolves a manifest before evaluating it.
+type checkFlags struct {
+	noCache bool
+	scan    bool
+	enrich  bool
+}
+
+func (c *CLI) checkCommand() *cobra.Command {
+	var (
+		policyFile   string
+		maxBrittle   int
+		failOnVuln   string
+		minHealth    int
+		denyLicenses []string
+		maxDepth     int
+		maxNodes     int
+		format       string
+		output       string
+		resolve      checkFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "check [manifest|graph.json|-]",
+		Short: "Check dependencies against a policy",
+		Long: `Evaluate dependencies against policy thresholds and exit non-zero when
+any rule fails, so the check can gate CI pipelines.
+
+The input is a manifest (poetry.lock, package.json, go.mod, ...), which is
+resolved first without rendering anything, or a graph saved by parse.
+The result starts with a summary of the graph, followed by each rule.
+
+Rules come from a YAML policy file (--policy) and/or flags; flags override
+the file. Available rules:
+
+  max_brittle        most brittle dependencies allowed
+  fail_on_vuln       lowest advisory severity that fails (needs --security-scan)
+  min_health         lowest health score (1-100) a dependency may have
+  deny_licenses      forbidden SPDX identifiers, globs allowed (e.g. GPL-*)
+  deny_license_risk  forbidden risk categories (copyleft, proprietary, ...)
+  max_depth          longest dependency chain allowed below the root
+  max_nodes          most direct and transitive dependencies allowed
+
+When a manifest is checked against fail_on_vuln, it is scanned for
+vulnerabilities even without --security-scan.
+
+Exit codes: 0 when every rule passes, 4 when a rule fails, 2 for invalid
+policies, 1 for other errors.`,
+		Example: `  # Resolve a lockfile and gate CI on a checked-in policy
+  stacktower check --policy policy.yaml poetry.lock
+
+  # Fail on critical advisories and GPL dependencies
+  stacktower check graph.json --fail-on-vuln critical --deny-license 'GPL-*'
+
+  # Use a checked-in policy with JSON output for CI annotations
+  stacktower check graph.json --policy .stacktower-policy.yaml -f json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := loadPolicy(policyFile)
+			if err != nil {
+				return err
+			}
+			flags := cmd.Flags()
+			if flags.Changed("max-brittle") {
+				p.MaxBrittle = &maxBrittle
+			}
+			if flags.Changed("fail-on-vuln") {
+				p.FailOnVuln = failOnVuln
+			}
+			if flags.Changed("min-health") {
+				p.MinHealth = minHealth
+			}
+			if flags.Changed("deny-license") {
+				p.DenyLicenses = denyLicenses
+			}
+			if flags.Changed("max-depth") {
+				p.MaxDepth = maxDepth
+			}
+			if flags.Changed("max-nodes") {
+				p.MaxNodes = maxNodes
+			}
+			if err := p.Validate(); err != nil {
+				return WrapUserError(err, "invalid policy", "")
+			}
+			if p.IsEmpty() {
+				return NewUserError("no policy rules configured", "Pass --policy FILE or at least one rule flag such as --fail-on-vuln critical.")
+			}
+			g, err := c.loadCheckInput(cmd.Context(), args[0], *p, resolve)
+			if err != nil {
+				return err
+			}
+			return c.runCheck(g, *p, format, output)
+		},
+	}
+
+	cmd.Flags().StringVar(&policyFile, "policy", "", "YAML policy file")
+	cmd.Flags().IntVar(&maxBrittle, "max-brittle", 0, "Most brittle dependencies allowed")
+	cmd.Flags().StringVar(&failOnVuln, "fail-on-vuln", "", "Fail on advisories at or above this severity: critical, high, medium, low")
+	cmd.Flags().IntVar(&minHealth, "min-health", 0, "Lowest health score (1-100) a dependency may have")
+	cmd.Flags().StringArrayVar(&denyLicenses, "deny-license", nil, "Forbidden SPDX license or glob (repeatable)")
+	cmd.Flags().IntVar(&maxDepth, "max-depth", 0, "Longest dependency chain allowed")
+	cmd.Flags().IntVar(&maxNodes, "max-nodes", 0, "Most dependencies allowed")
+	cmd.Flags().BoolVar(&resolve.enrich, "enrich", true, "Enrich a manifest with GitHub metadata (needed for brittleness and health)")
+	cmd.Flags().BoolVar(&resolve.scan, "security-scan", false, "Scan a manifest for known vulnerabilities (OSV.dev)")
+	cmd.Flags().BoolVar(&resolve.noCache, "no-cache", false, "Disable caching")
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format: text, json")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file (stdout if empty)")
+
+	return cmd
+}
+
+// PolicyError is returned when a policy check fails.
+// It maps to ExitCodePolicy (4) via ExitCodeForError.
+type PolicyError struct {
+	Failed int
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("%d policy rules failed", e.Failed)
+}
+
+// loadPolicy reads a policy file for --policy. An empty path returns an
+// empty policy for flags to fill in.
+func loadPolicy(path string) (*policy.Policy, error) {
+	if path == "" {
+		return &policy.Policy{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, WrapUserError(err, "failed to read policy file", "Check that the file path exists and is readable.")
+	}
+	p, err := policy.Parse(data)
+	if err != nil {
+		return nil, WrapUserError(err, "invalid policy file", "Policy files are YAML with keys max_brittle, fail_on_vuln, min_health, deny_licenses, deny_license_risk, max_depth, and max_nodes.")
+	}
+	return p, nil
+}
+
+// loadCheckInput returns the graph to check: a supported manifest is
+// resolved through the pipeline, anything else is loaded as a graph file.
+func (c *CLI) loadCheckInput(ctx context.Context, input string, p policy.Policy, flags checkFlags) (*dag.DAG, error) {
+	filename := filepath.Base(input)
+	langName := deps.GetManifestLanguage(filename, languages.All)
+	if input == "-" || langName == "" {
+		g, err := loadGraph(input)
+		if err != nil {
+			return nil, WrapSystemError(err, "failed to load graph", "")
+		}
+		return g, nil
+	}
+
+	lang := languages.Find(langName)
+	if lang == nil {
+		return nil, NewSystemError(fmt.Sprintf("language %q not found", langName), "This is an internal error. Please report this issue.")
+	}
+	content, err := os.ReadFile(input)
+	if err != nil {
+		return nil, WrapUserError(err, "failed to read manifest file", "Check that the file path exists and is readable.")
+	}
+
+	// Resolve past the policy's limits, or the resolver's own caps would
+	// hide the graphs that break them.
+	opts := pipeline.Options{
+		Language:         lang.Name,
+		Manifest:         string(content),
+		ManifestFilename: filename,
+		ManifestPath:     input,
+		MaxDepth:         max(pipeline.DefaultMaxDepth, p.MaxDepth+1),
+		MaxNodes:         max(pipeline.DefaultMaxNodes, p.MaxNodes+1),
+		SkipEnrich:       !flags.enrich,
+	}
+	scan := flags.scan || p.FailOnVuln != ""
+
+	result, err := c.runParseWithProgress(ctx, opts, flags.noCache, scan,
+		fmt.Sprintf("Resolving %s...", filename), opts.MaxNodes)
+	if err != nil {
+		return nil, wrapParseFailure("resolve "+filename, err)
+	}
+	name := strings.TrimSuffix(filename, filepath.Ext(filename))
+	result.Graph.RenameNode(graph.ProjectRootNodeID, name) //nolint:errcheck // non-critical rename
+	return result.Graph, nil
+}
+
+func (c *CLI) runCheck(g *dag.DAG, p policy.Policy, format, output string) error {
+	res := policy.Evaluate(g, p, time.Now())
+
+	w := os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return WrapSystemError(err, "failed to create output file", "")
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(res); err != nil {
+			return WrapSystemError(err, "failed to write JSON output", "")
+		}
+	default:
+		ui.WriteCheck(w, res)
+	}
+
+	if ui.IsJSON() {
+		ui.Event("result", map[string]any{
+			"command":      "check",
+			"passed":       res.Passed,
+			"failed":       len(res.Failed()),
+			"package":      res.Summary.Root,
+			"dependencies": res.Summary.Dependencies,
+			"max_depth":    res.Summary.MaxDepth,
+			"brittle":      res.Summary.Brittle,
+			"vulnerable":   res.Summary.Vulnerable,
+		})
+	}
+
+	if !res.Passed {
+		return &PolicyError{Failed: len(res.Failed())}
+	}
+	return nil
+}
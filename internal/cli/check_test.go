@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/policy"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	if p, err := loadPolicy(""); err != nil || !p.IsEmpty() {
+		t.Fatalf("empty path = %+v, %v; want empty policy", p, err)
+	}
+
+	dir := t.TempDir()
+	good := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(good, []byte("fail_on_vuln: critical\ndeny_licenses: [AGPL-*]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := loadPolicy(good)
+	if err != nil {
+		t.Fatalf("loadPolicy(%s) error = %v", good, err)
+	}
+	if p.FailOnVuln != "critical" || len(p.DenyLicenses) != 1 {
+		t.Errorf("got %+v", p)
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("min_health: 500\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = loadPolicy(bad)
+	if ExitCodeForError(err) != ExitCodeUsage {
+		t.Errorf("invalid policy exit code = %d, want %d", ExitCodeForError(err), ExitCodeUsage)
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	dir := t.TempDir()
+	graph := filepath.Join(dir, "graph.json")
+	data := `{"nodes": [
+		{"id": "app"},
+		{"id": "left-pad", "meta": {"license": "GPL-3.0-only", "vuln_severity": "critical"}}
+	], "edges": [{"from": "app", "to": "left-pad"}]}`
+	if err := os.WriteFile(graph, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(os.Stderr, LogInfo)
+	p, _ := loadPolicy("")
+	p.DenyLicenses = []string{"MIT"}
+	g, err := c.loadCheckInput(context.Background(), graph, *p, checkFlags{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.runCheck(g, *p, "json", filepath.Join(dir, "pass.json")); err != nil {
+		t.Fatalf("passing policy error = %v", err)
+	}
+
+	p.FailOnVuln = "high"
+	err = c.runCheck(g, *p, "json", filepath.Join(dir, "fail.json"))
+	var policyErr *PolicyError
+	if !errors.As(err, &policyErr) || policyErr.Failed != 1 {
+		t.Fatalf("failing policy error = %v, want PolicyError with 1 failed rule", err)
+	}
+	if ExitCodeForError(err) != ExitCodePolicy {
+		t.Errorf("exit code = %d, want %d", ExitCodeForError(err), ExitCodePolicy)
+	}
+}
+
+func TestLoadCheckInput_Manifest(t *testing.T) {
+	c := New(os.Stderr, LogInfo)
+	g, err := c.loadCheckInput(context.Background(), "../../examples/manifest/poetry.lock", policy.Policy{}, checkFlags{noCache: true})
+	if err != nil {
+		t.Fatalf("loadCheckInput error = %v", err)
+	}
+	if _, ok := g.Node("poetry"); !ok {
+		t.Error("project root was not named after the manifest")
+	}
+
+	out := filepath.Join(t.TempDir(), "check.json")
+	err = c.runCheck(g, policy.Policy{MaxNodes: 1}, "json", out)
+	if ExitCodeForError(err) != ExitCodePolicy {
+		t.Fatalf("max_nodes 1 error = %v, want policy failure", err)
+	}
+	data, _ := os.ReadFile(out)
+	var res policy.Result
+	if err := json.Unmarshal(data, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Summary.Root != "poetry" || res.Summary.Dependencies < 2 {
+		t.Errorf("summary = %+v", res.Summary)
+	}
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/stacktower-io/stacktower/internal/cli/ui"
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	corerender "github.com/stacktower-io/stacktower/pkg/core/render"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+// Files written by tower --ci. The names are stable so workflows can
+// upload, publish or comment them without knowing the package.
+const (
+	ciTowerFile     = "tower.svg"
+	ciThumbnailFile = "tower.png"
+	ciGraphFile     = "graph.json"
+	ciSummaryFile   = "summary.md"
+	ciCommentFile   = "comment.md"
+)
+
+// defaultCIDir is where tower --ci writes its files.
+const defaultCIDir = "stacktower"
+
+// ciThumbnailScale shrinks the rendered tower for the PNG thumbnail.
+const ciThumbnailScale = 0.5
+
+// writeCIArtifacts writes the graph, a thumbnail of the tower already
+// rendered to dir, and the Markdown summary into dir, plus the pull
+// request comment when prComment is set. When the GITHUB_STEP_SUMMARY
+// environment variable names a file, the summary is appended to it so it
+// shows on the workflow run. It returns the paths written.
+//
+// A thumbnail that fails to render is skipped with a warning.
+func writeCIArtifacts(g *dag.DAG, dir string, prComment bool) ([]string, error) {
+	var paths []string
+
+	graphPath := filepath.Join(dir, ciGraphFile)
+	if err := graph.ExportJSONFile(g, graphPath, graph.ExportOptions{}); err != nil {
+		return nil, WrapSystemError(err, "failed to write graph", "Check that the CI directory is writable.")
+	}
+	paths = append(paths, graphPath)
+
+	svg, err := os.ReadFile(filepath.Join(dir, ciTowerFile))
+	if err != nil {
+		return nil, WrapSystemError(err, "failed to read rendered tower", "This is an internal error. Please report this issue.")
+	}
+	if png, err := corerender.ToPNG(svg, ciThumbnailScale); err != nil {
+		ui.PrintWarning("Skipped the PNG thumbnail: %v", err)
+	} else {
+		thumbPath := filepath.Join(dir, ciThumbnailFile)
+		if err := writeFile(png, thumbPath); err != nil {
+			return nil, err
+		}
+		paths = append(paths, thumbPath)
+	}
+
+	report := analyzeGraph(g).report
+
+	var summary bytes.Buffer
+	ui.WriteStatsMarkdown(&summary, report, ciTowerFile)
+	summaryPath := filepath.Join(dir, ciSummaryFile)
+	if err := writeFile(summary.Bytes(), summaryPath); err != nil {
+		return nil, err
+	}
+	paths = append(paths, summaryPath)
+
+	if prComment {
+		var comment bytes.Buffer
+		ui.WritePRComment(&comment, report)
+		commentPath := filepath.Join(dir, ciCommentFile)
+		if err := writeFile(comment.Bytes(), commentPath); err != nil {
+			return nil, err
+		}
+		paths = append(paths, commentPath)
+	}
+
+	if stepSummary := os.Getenv("GITHUB_STEP_SUMMARY"); stepSummary != "" {
+		// The job summary cannot show files from the workspace, so it
+		// gets the report without the embedded tower.
+		var b bytes.Buffer
+		ui.WriteStatsMarkdown(&b, report, "")
+		if err := appendFile(stepSummary, b.Bytes()); err != nil {
+			ui.PrintWarning("Could not write the job summary: %v", err)
+		}
+	}
+	return paths, nil
+}
+
+// appendFile appends data to the file at path, creating it if needed.
+func appendFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// validateCIFlags rejects flags that conflict with tower --ci, which
+// picks its own output names and formats.
+func validateCIFlags(ci, prComment, outputSet, formatSet bool) error {
+	if prComment && !ci {
+		return NewUserError("--pr-comment requires --ci", "Add --ci to write the CI files.")
+	}
+	if ci && (outputSet || formatSet) {
+		return NewUserError(
+			"--ci writes fixed file names and formats",
+			fmt.Sprintf("Drop -o and -f; use --ci-dir to choose the directory (default %q).", defaultCIDir),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacktower-io/stacktower/internal/cli/ui"
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+)
+
+func TestRunTowerCI(t *testing.T) {
+	stepSummary := filepath.Join(t.TempDir(), "step-summary.md")
+	t.Setenv("GITHUB_STEP_SUMMARY", stepSummary)
+
+	c := New(os.Stderr, LogInfo)
+	flags := towerFlags{ci: true, prComment: true, ciDir: filepath.Join(t.TempDir(), "ci")}
+	setCLIDefaults(&flags.Options)
+	flags.MaxDepth = pipeline.DefaultMaxDepth
+	flags.MaxNodes = pipeline.DefaultMaxNodes
+	flags.Ordering = "barycentric"
+	flags.render.noCache = true
+	flags.render.maxCrossings = -1
+	if err := flags.render.apply(&cobra.Command{}, &flags.Options); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.runTower(context.Background(), &flags, []string{"../../examples/manifest/poetry.lock"}); err != nil {
+		t.Fatalf("runTower error = %v", err)
+	}
+
+	read := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(flags.ciDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	if !strings.Contains(read(ciTowerFile), "<svg") {
+		t.Error("tower is not an SVG")
+	}
+	if !strings.Contains(read(ciGraphFile), `"nodes"`) {
+		t.Error("graph is not graph JSON")
+	}
+	if summary := read(ciSummaryFile); !strings.Contains(summary, "](tower.svg)") || !strings.Contains(summary, "### Overview") {
+		t.Errorf("summary = %q", summary)
+	}
+	if !strings.HasPrefix(read(ciCommentFile), ui.PRCommentMarker) {
+		t.Error("comment does not start with the marker")
+	}
+
+	data, err := os.ReadFile(stepSummary)
+	if err != nil {
+		t.Fatalf("job summary was not written: %v", err)
+	}
+	if strings.Contains(string(data), "](tower.svg)") {
+		t.Error("job summary embeds a workspace file")
+	}
+}
+
+func TestValidateCIFlags(t *testing.T) {
+	tests := []struct {
+		name                                string
+		ci, prComment, outputSet, formatSet bool
+		wantErr                             bool
+	}{
+		{"off", false, false, true, true, false},
+		{"ci", true, true, false, false, false},
+		{"comment without ci", false, true, false, false, true},
+		{"ci with output", true, false, true, false, true},
+		{"ci with format", true, false, false, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCIFlags(tt.ci, tt.prComment, tt.outputSet, tt.formatSet)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateCIFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && ExitCodeForError(err) != ExitCodeUsage {
+				t.Errorf("exit code = %d, want usage", ExitCodeForError(err))
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -19,6 +20,7 @@ import (
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	"github.com/stacktower-io/stacktower/pkg/observability"
 	"github.com/stacktower-io/stacktower/pkg/pipeline"
+	"github.com/stacktower-io/stacktower/pkg/plugin"
 	"github.com/stacktower-io/stacktower/pkg/security"
 )
 
@@ -46,15 +48,19 @@ const (
 
 // CLI holds shared state for all commands.
 type CLI struct {
-	Logger *log.Logger
-	Quiet  bool // suppress non-essential output (success messages, stats, next steps)
+	Logger   *log.Logger
+	Quiet    bool // suppress non-essential output (success messages, stats, next steps)
+	JSONLogs bool // write logs and status output as NDJSON events
+
+	plugins []*plugin.Plugin // loaded by LoadPlugins
 }
 
-// New creates a new CLI instance with a default logger.
+// New creates a new CLI instance with a default logger, whose warnings are
+// collected for the summary at the end of the run.
 // Registers observability hooks for pipeline and security events.
 func New(w io.Writer, level log.Level) *CLI {
 	c := &CLI{
-		Logger: log.NewWithOptions(w, log.Options{
+		Logger: log.NewWithOptions(ui.NewWarningTap(w), log.Options{
 			ReportTimestamp: true,
 			TimeFormat:      "15:04:05.00",
 			Level:           level,
@@ -65,6 +71,12 @@ func New(w io.Writer, level log.Level) *CLI {
 	return c
 }
 
+// structuredLogger adapts a CLI logger to the structured logger taken by
+// the pipeline, the resolvers and the server.
+func structuredLogger(l *log.Logger) observability.Logger {
+	return slog.New(l)
+}
+
 // SetLogLevel updates the logger's level.
 func (c *CLI) SetLogLevel(level log.Level) {
 	c.Logger.SetLevel(level)
@@ -76,6 +88,18 @@ func (c *CLI) SetQuiet(q bool) {
 	ui.SetQuiet(q)
 }
 
+// SetJSONLogs switches logs and status output on stderr to NDJSON events,
+// so wrappers and CI systems can follow progress without parsing text.
+func (c *CLI) SetJSONLogs(j bool) {
+	c.JSONLogs = j
+	ui.SetJSON(j)
+	if j {
+		c.Logger.SetFormatter(log.JSONFormatter)
+	} else {
+		c.Logger.SetFormatter(log.TextFormatter)
+	}
+}
+
 // RootCommand creates the root cobra command with all subcommands registered.
 func (c *CLI) RootCommand() *cobra.Command {
 	root := &cobra.Command{
@@ -89,6 +113,19 @@ func (c *CLI) RootCommand() *cobra.Command {
 
 	root.SetVersionTemplate(buildinfo.Template())
 
+	var (
+		configPath string
+		noConfig   bool
+	)
+	root.PersistentFlags().StringVar(&configPath, "config", "", "config file (default ./.stacktower.yaml, then ~/.config/stacktower/config.yaml)")
+	root.PersistentFlags().BoolVar(&noConfig, "no-config", false, "ignore configuration files")
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if noConfig {
+			return nil
+		}
+		return c.applyConfig(cmd, configPath)
+	}
+
 	// Custom styled help and usage output
 	root.SetHelpFunc(func(cmd *cobra.Command, args []string) {
 		fmt.Fprint(os.Stderr, ui.RenderHelp(cmd))
@@ -107,14 +144,25 @@ func (c *CLI) RootCommand() *cobra.Command {
 	root.AddCommand(c.layoutCommand())
 	root.AddCommand(c.visualizeCommand())
 	root.AddCommand(c.renderCommand())
+	root.AddCommand(c.towerCommand())
 	root.AddCommand(c.cacheCommand())
 	root.AddCommand(c.pqtreeCommand())
 	root.AddCommand(c.githubCommand())
 	root.AddCommand(c.completionCommand())
 	root.AddCommand(c.whyCommand())
+	root.AddCommand(c.explainCommand())
 	root.AddCommand(c.statsCommand())
+	root.AddCommand(c.tuiCommand())
+	root.AddCommand(c.checkCommand())
 	root.AddCommand(c.diffCommand())
 	root.AddCommand(c.sbomCommand())
+	root.AddCommand(c.exportCommand())
+	root.AddCommand(c.generateCommand())
+	root.AddCommand(c.validateCommand())
+	root.AddCommand(c.serveCommand())
+	root.AddCommand(c.pluginsCommand())
+
+	registerCompletions(root)
 
 	return root
 }
@@ -136,7 +184,7 @@ func (c *CLI) newRunner(noCache bool, securityScan bool) (*pipeline.Runner, erro
 		scanner = security.NewOSVScanner(nil) // default HTTP client
 	}
 
-	return pipeline.NewRunnerWithScanner(cc, nil, c.Logger, scanner), nil
+	return pipeline.NewRunnerWithScanner(cc, nil, structuredLogger(c.Logger), scanner), nil
 }
 
 func newCache(noCache bool) (cache.Cache, error) {
@@ -218,9 +266,12 @@ func (c *CLI) runParseWithProgress(ctx context.Context, opts pipeline.Options, n
 
 	pv := ui.NewProgressView(ctx, progressMsg, maxNodes)
 
-	pvLogger := log.New(ui.NewProgressWriter(pv, os.Stderr))
+	pvLogger := log.New(ui.NewWarningTap(ui.NewProgressWriter(pv, os.Stderr)))
 	pvLogger.SetLevel(c.Logger.GetLevel())
-	opts.Logger = pvLogger
+	if c.JSONLogs {
+		pvLogger.SetFormatter(log.JSONFormatter)
+	}
+	opts.Logger = structuredLogger(pvLogger)
 
 	pv.Start()
 
@@ -235,6 +286,10 @@ func (c *CLI) runParseWithProgress(ctx context.Context, opts pipeline.Options, n
 		return nil, ctx.Err()
 	}
 
+	if !noCache && opts.Manifest == "" && opts.Package != "" {
+		rememberPackage(opts.Language, opts.Package)
+	}
+
 	return &parseResult{
 		Graph:          result.Graph,
 		CacheHit:       result.CacheHit,
@@ -1,9 +1,19 @@
 package cli
 
 import (
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/languages"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
 )
 
 // completionCommand creates the completion command for generating shell completions.
@@ -67,3 +77,191 @@ PowerShell:
 
 	return cmd
 }
+
+// packageIndexFile lists the registry packages resolved with the cache on,
+// one "language/package" per line, most recent last. Cache keys are
+// hashed, so completion reads package names from here instead.
+const packageIndexFile = "packages.txt"
+
+// maxIndexedPackages bounds the package index.
+const maxIndexedPackages = 500
+
+// rememberPackage adds a resolved package to the package index. It is
+// best-effort: completion is a convenience, so failures are ignored.
+func rememberPackage(language, pkg string) {
+	dir, err := cacheDir()
+	if err != nil {
+		return
+	}
+	path := filepath.Join(dir, packageIndexFile)
+	entry := language + "/" + pkg
+
+	lines, _ := readBatchFile(path)
+	lines = slices.DeleteFunc(lines, func(l string) bool { return l == entry })
+	lines = append(lines, entry)
+	if len(lines) > maxIndexedPackages {
+		lines = lines[len(lines)-maxIndexedPackages:]
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// cachedPackages returns the indexed packages of a language, most recent
+// first.
+func cachedPackages(language string) []string {
+	dir, err := cacheDir()
+	if err != nil {
+		return nil
+	}
+	lines, err := readBatchFile(filepath.Join(dir, packageIndexFile))
+	if err != nil {
+		return nil
+	}
+	var pkgs []string
+	for _, l := range slices.Backward(lines) {
+		if pkg, ok := strings.CutPrefix(l, language+"/"); ok {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	return pkgs
+}
+
+// flagCompletions lists the values offered for enumerated flags, by flag
+// name. Flags taking comma-separated lists complete each element.
+var flagCompletions = map[string]func() []string{
+	"style":            styles.Names,
+	"quality":          func() []string { return slices.Sorted(maps.Keys(qualityPresets)) },
+	"type":             func() []string { return slices.Sorted(maps.Keys(pipeline.ValidVizTypes)) },
+	"format":           func() []string { return slices.Sorted(maps.Keys(pipeline.ValidFormats)) },
+	"ordering":         func() []string { return []string{"optimal", "barycentric"} },
+	"edge-routing":     func() []string { return slices.Sorted(maps.Keys(pipeline.ValidEdgeRoutings)) },
+	"engine":           func() []string { return slices.Sorted(maps.Keys(pipeline.ValidEngines)) },
+	"color-by":         func() []string { return slices.Sorted(maps.Keys(pipeline.ValidColorBy)) },
+	"palette":          styles.PaletteNames,
+	"weight":           func() []string { return []string{"deps", "downloads", "size"} },
+	"nebraska-by":      func() []string { return []string{"maintainer", "org"} },
+	"dependency-scope": func() []string { return []string{deps.DependencyScopeProdOnly, deps.DependencyScopeAll} },
+}
+
+// commaListFlags take comma-separated values.
+var commaListFlags = map[string]bool{"format": true}
+
+// registerCompletions adds dynamic completions to root and its commands:
+// values for the enumerated flags, and languages, manifest files and
+// cached package names for the commands that take them.
+func registerCompletions(root *cobra.Command) {
+	seen := map[*pflag.Flag]bool{}
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		register := func(f *pflag.Flag) {
+			values, ok := flagCompletions[f.Name]
+			if !ok || seen[f] {
+				return
+			}
+			seen[f] = true
+			_ = cmd.RegisterFlagCompletionFunc(f.Name, completeValues(values, commaListFlags[f.Name]))
+		}
+		cmd.PersistentFlags().VisitAll(register)
+		cmd.LocalNonPersistentFlags().VisitAll(register)
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+
+	for _, cmd := range root.Commands() {
+		switch cmd.Name() {
+		case "parse":
+			cmd.ValidArgsFunction = completeFirstArg(func() []string { return manifestFiles(nil) })
+			for _, sub := range cmd.Commands() {
+				if lang := languages.Find(sub.Name()); lang != nil {
+					sub.ValidArgsFunction = completeFirstArg(func() []string {
+						return append(cachedPackages(lang.Name), manifestFiles(lang)...)
+					})
+				}
+			}
+		case "list":
+			for _, sub := range cmd.Commands() {
+				if lang := languages.Find(sub.Name()); lang != nil {
+					sub.ValidArgsFunction = completeFirstArg(func() []string { return cachedPackages(lang.Name) })
+				}
+			}
+		case "resolve", "tower":
+			cmd.ValidArgsFunction = completeLanguageArgs
+		}
+	}
+}
+
+// completeValues completes a flag from a fixed list of values. For list
+// flags it completes the element after the last comma, skipping values
+// already given.
+func completeValues(values func() []string, list bool) cobra.CompletionFunc {
+	return func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if !list {
+			return values(), cobra.ShellCompDirectiveNoFileComp
+		}
+		prefix := ""
+		if i := strings.LastIndex(toComplete, ","); i >= 0 {
+			prefix = toComplete[:i+1]
+		}
+		chosen := strings.Split(prefix, ",")
+		var out []string
+		for _, v := range values() {
+			if !slices.Contains(chosen, v) {
+				out = append(out, prefix+v)
+			}
+		}
+		return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+}
+
+// completeFirstArg completes the first positional argument with
+// candidates, keeping file completion for paths elsewhere.
+func completeFirstArg(candidates func() []string) cobra.CompletionFunc {
+	return func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return candidates(), cobra.ShellCompDirectiveDefault
+	}
+}
+
+// completeLanguageArgs completes "<manifest>" or "<language> <package>"
+// for resolve and tower.
+func completeLanguageArgs(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		var out []string
+		for _, lang := range languages.All {
+			out = append(out, lang.Name)
+		}
+		return append(out, manifestFiles(nil)...), cobra.ShellCompDirectiveDefault
+	case 1:
+		lang := languages.Find(args[0])
+		if lang == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return append(cachedPackages(lang.Name), manifestFiles(lang)...), cobra.ShellCompDirectiveDefault
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// manifestFiles returns the manifest files in the working directory that
+// lang can parse, or that any language can parse when lang is nil.
+func manifestFiles(lang *deps.Language) []string {
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, e := range entries {
+		name := deps.GetManifestLanguage(e.Name(), languages.All)
+		if e.IsDir() || name == "" || (lang != nil && name != lang.Name) {
+			continue
+		}
+		out = append(out, e.Name())
+	}
+	return out
+}
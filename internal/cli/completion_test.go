@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// complete runs cobra's hidden completion command and returns the
+// suggestions, without descriptions.
+func complete(t *testing.T, args ...string) []string {
+	t.Helper()
+	root := New(os.Stderr, LogInfo).RootCommand()
+	var out strings.Builder
+	root.SetOut(&out)
+	root.SetArgs(append([]string{cobra.ShellCompRequestCmd}, args...))
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if line == "" || strings.HasPrefix(line, ":") || strings.HasPrefix(line, "Completion ended") {
+			continue
+		}
+		name, _, _ := strings.Cut(line, "\t")
+		got = append(got, name)
+	}
+	return got
+}
+
+func TestCompletions(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"poetry.lock", "Cargo.toml", "notes.txt"} {
+		writeConfig(t, filepath.Join(dir, name), "")
+	}
+	t.Chdir(dir)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	rememberPackage("python", "requests")
+	rememberPackage("rust", "serde")
+	rememberPackage("python", "flask")
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		notWant []string
+	}{
+		{"style", []string{"render", "--style", ""}, []string{"simple", "handdrawn"}, nil},
+		{"quality", []string{"tower", "--quality", ""}, []string{"fast", "balanced", "best"}, nil},
+		{"format list", []string{"render", "--format", "svg,"}, []string{"svg,png"}, []string{"svg,svg"}},
+		{"tower languages and manifests", []string{"tower", ""}, []string{"python", "rust", "poetry.lock", "Cargo.toml"}, []string{"notes.txt"}},
+		{"tower packages", []string{"tower", "python", ""}, []string{"flask", "requests", "poetry.lock"}, []string{"serde", "Cargo.toml"}},
+		{"resolve packages", []string{"resolve", "rust", ""}, []string{"serde", "Cargo.toml"}, []string{"requests"}},
+		{"parse manifests", []string{"parse", ""}, []string{"python", "poetry.lock", "Cargo.toml"}, nil},
+		{"parse language", []string{"parse", "python", ""}, []string{"flask", "requests", "poetry.lock"}, []string{"Cargo.toml"}},
+		{"list packages", []string{"list", "rust", ""}, []string{"serde"}, []string{"requests"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := complete(t, tt.args...)
+			for _, w := range tt.want {
+				if !slices.Contains(got, w) {
+					t.Errorf("missing %q in %v", w, got)
+				}
+			}
+			for _, w := range tt.notWant {
+				if slices.Contains(got, w) {
+					t.Errorf("unexpected %q in %v", w, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCachedPackages(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	if got := cachedPackages("python"); len(got) != 0 {
+		t.Fatalf("empty index = %v", got)
+	}
+	rememberPackage("python", "requests")
+	rememberPackage("python", "flask")
+	rememberPackage("python", "requests")
+
+	want := []string{"requests", "flask"} // most recent first, no duplicates
+	if got := cachedPackages("python"); !slices.Equal(got, want) {
+		t.Errorf("cachedPackages() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// projectConfigFile is the per-project configuration file, looked up from
+// the working directory upward.
+const projectConfigFile = ".stacktower.yaml"
+
+// Config holds flag defaults read from a configuration file. Zero values
+// leave the command's own default in place, and flags given on the
+// command line always win.
+type Config struct {
+	// Resolution, for parse, resolve and tower.
+	MaxDepth          int      `yaml:"max_depth"`
+	MaxNodes          int      `yaml:"max_nodes"`
+	MaxDuration       string   `yaml:"max_duration"` // e.g. "30s"
+	DependencyScope   string   `yaml:"dependency_scope"`
+	IncludePrerelease *bool    `yaml:"include_prerelease"`
+	Exclude           []string `yaml:"exclude"`
+	Internal          []string `yaml:"internal"` // first-party package globs
+
+	// Providers lists the metadata sources to use: github (stars,
+	// maintainers), contributors, icons and osv (vulnerabilities). When
+	// set, sources not listed are turned off.
+	Providers []string `yaml:"providers"`
+
+	// Rendering, for render, tower, layout and visualize.
+	Type     string   `yaml:"type"`
+	Style    string   `yaml:"style"`
+	Theme    string   `yaml:"theme"` // theme file, relative to the config file
+	Quality  string   `yaml:"quality"`
+	Formats  []string `yaml:"formats"`
+	Palette  string   `yaml:"palette"`
+	ColorBy  string   `yaml:"color_by"`
+	Branding string   `yaml:"branding"`
+	Footer   *bool    `yaml:"footer"`
+	Popups   *bool    `yaml:"popups"`
+
+	dir string // directory of the file, for relative paths
+}
+
+// providerFlags maps each provider name to the flag that turns it on.
+var providerFlags = map[string]string{
+	"github":       "enrich",
+	"contributors": "contributors",
+	"icons":        "icons",
+	"osv":          "security-scan",
+}
+
+// Config sections and the commands they apply to, by top-level command name.
+const (
+	sectionResolve = 1 << iota
+	sectionProviders
+	sectionRender
+)
+
+var configSections = map[string]int{
+	"parse":     sectionResolve | sectionProviders,
+	"resolve":   sectionResolve | sectionProviders,
+	"tower":     sectionResolve | sectionProviders | sectionRender,
+	"check":     sectionProviders,
+	"render":    sectionRender,
+	"layout":    sectionRender,
+	"visualize": sectionRender,
+}
+
+// loadConfigFile reads and validates a configuration file.
+func loadConfigFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	cfg.dir = filepath.Dir(path)
+	return &cfg, nil
+}
+
+func (cfg *Config) validate() error {
+	if cfg.MaxDepth < 0 || cfg.MaxNodes < 0 {
+		return errors.New("max_depth and max_nodes must not be negative")
+	}
+	if d, err := time.ParseDuration(cfg.MaxDuration); cfg.MaxDuration != "" && (err != nil || d < 0) {
+		return fmt.Errorf("invalid max_duration %q (use a duration such as 30s or 2m)", cfg.MaxDuration)
+	}
+	if _, ok := qualityPresets[cfg.Quality]; cfg.Quality != "" && !ok {
+		return fmt.Errorf("invalid quality %q (use fast, balanced, or best)", cfg.Quality)
+	}
+	for _, p := range cfg.Providers {
+		if _, ok := providerFlags[p]; !ok {
+			return fmt.Errorf("unknown provider %q (use github, contributors, icons, or osv)", p)
+		}
+	}
+	return nil
+}
+
+// flagValues returns the flag values the configuration sets for the
+// given top-level command, keyed by flag name.
+func (cfg *Config) flagValues(command string) map[string]string {
+	sections := configSections[command]
+	values := map[string]string{}
+	set := func(name, value string) {
+		if value != "" {
+			values[name] = value
+		}
+	}
+	setInt := func(name string, v int) {
+		if v != 0 {
+			values[name] = strconv.Itoa(v)
+		}
+	}
+	setBool := func(name string, v *bool) {
+		if v != nil {
+			values[name] = strconv.FormatBool(*v)
+		}
+	}
+
+	if sections&sectionResolve != 0 {
+		setInt("max-depth", cfg.MaxDepth)
+		setInt("max-nodes", cfg.MaxNodes)
+		set("max-duration", cfg.MaxDuration)
+		set("dependency-scope", cfg.DependencyScope)
+		setBool("include-prerelease", cfg.IncludePrerelease)
+		set("exclude", strings.Join(cfg.Exclude, ","))
+		set("internal", strings.Join(cfg.Internal, ","))
+	}
+	if sections&sectionProviders != 0 && cfg.Providers != nil {
+		for p, name := range providerFlags {
+			values[name] = strconv.FormatBool(slices.Contains(cfg.Providers, p))
+		}
+	}
+	if sections&sectionRender != 0 {
+		set("type", cfg.Type)
+		set("style", cfg.Style)
+		if cfg.Theme != "" {
+			theme := cfg.Theme
+			if !filepath.IsAbs(theme) {
+				theme = filepath.Join(cfg.dir, theme)
+			}
+			values["theme-file"] = theme
+		}
+		set("quality", cfg.Quality)
+		set("format", strings.Join(cfg.Formats, ","))
+		set("palette", cfg.Palette)
+		set("color-by", cfg.ColorBy)
+		set("branding", cfg.Branding)
+		setBool("footer", cfg.Footer)
+		setBool("popups", cfg.Popups)
+	}
+	return values
+}
+
+// applyFlagValues sets each flag in values that the command has and that
+// was not given on the command line. Flags keep their unchanged state, so
+// explicit-flag checks such as --ordering overriding --quality still work.
+func applyFlagValues(cmd *cobra.Command, values map[string]string) error {
+	for name, value := range values {
+		f := cmd.Flags().Lookup(name)
+		if f == nil || f.Changed {
+			continue
+		}
+		if err := f.Value.Set(value); err != nil {
+			return fmt.Errorf("%s: invalid value %q: %w", name, value, err)
+		}
+	}
+	return nil
+}
+
+// configPaths returns the configuration files to read, most specific
+// first: the project's .stacktower.yaml and the user's config.yaml.
+// An explicit path replaces both.
+func configPaths(explicit string) ([]string, error) {
+	if explicit != "" {
+		return []string{explicit}, nil
+	}
+	var paths []string
+	if wd, err := os.Getwd(); err == nil {
+		if p := findProjectConfig(wd); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	if dir, err := configDir(); err == nil {
+		p := filepath.Join(dir, "config.yaml")
+		if _, err := os.Stat(p); err == nil {
+			paths = append(paths, p)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// findProjectConfig looks for .stacktower.yaml in dir and its parents.
+func findProjectConfig(dir string) string {
+	for {
+		p := filepath.Join(dir, projectConfigFile)
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			return p
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// configDir returns the configuration directory using XDG standard
+// (~/.config/stacktower/).
+func configDir() (string, error) {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return filepath.Join(configHome, appName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", appName), nil
+}
+
+// applyConfig loads the configuration files and applies them to cmd.
+// Settings from the project file win over the user file.
+func (c *CLI) applyConfig(cmd *cobra.Command, explicit string) error {
+	command := topLevelName(cmd)
+	if _, ok := configSections[command]; !ok {
+		return nil
+	}
+
+	paths, err := configPaths(explicit)
+	if err != nil {
+		return WrapSystemError(err, "failed to look up configuration", "Check the permissions of your config directory, or pass --no-config.")
+	}
+
+	values := map[string]string{}
+	for _, p := range paths {
+		cfg, err := loadConfigFile(p)
+		if err != nil {
+			return WrapUserError(err, "invalid configuration file "+p, "Fix the file, or pass --no-config to ignore it.")
+		}
+		c.Logger.Debug("loaded config", "path", p)
+		for name, value := range cfg.flagValues(command) {
+			if _, ok := values[name]; !ok {
+				values[name] = value
+			}
+		}
+	}
+
+	if err := applyFlagValues(cmd, values); err != nil {
+		return WrapUserError(err, "invalid configuration value", "Fix the configuration file, or pass --no-config to ignore it.")
+	}
+	return nil
+}
+
+// topLevelName returns the name of the command directly below the root,
+// so "parse python" and "parse" share their configuration.
+func topLevelName(cmd *cobra.Command) string {
+	for cmd.HasParent() && cmd.Parent().HasParent() {
+		cmd = cmd.Parent()
+	}
+	return cmd.Name()
+}
@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"valid", "style: simple\nquality: best\nproviders: [github, osv]\nexclude: ['pytest*']\n", false},
+		{"unknown key", "colour: red\n", true},
+		{"bad quality", "quality: perfect\n", true},
+		{"bad provider", "providers: [gitlab]\n", true},
+		{"negative depth", "max_depth: -1\n", true},
+		{"duration", "max_duration: 30s\n", false},
+		{"bad duration", "max_duration: soon\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".yaml")
+			writeConfig(t, path, tt.data)
+			_, err := loadConfigFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loadConfigFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigFlagValues(t *testing.T) {
+	depth := 4
+	footer := false
+	cfg := &Config{
+		MaxDepth:  depth,
+		Exclude:   []string{"pytest*", "black"},
+		Internal:  []string{"acme-*"},
+		Providers: []string{"github"},
+		Style:     "simple",
+		Theme:     "theme.yaml",
+		Formats:   []string{"svg", "png"},
+		Footer:    &footer,
+		dir:       "/repo",
+	}
+
+	render := cfg.flagValues("render")
+	if render["style"] != "simple" || render["format"] != "svg,png" || render["footer"] != "false" {
+		t.Errorf("render values = %v", render)
+	}
+	if render["theme-file"] != filepath.Join("/repo", "theme.yaml") {
+		t.Errorf("theme-file = %q, want it relative to the config file", render["theme-file"])
+	}
+	if _, ok := render["max-depth"]; ok {
+		t.Error("render should not get resolution settings")
+	}
+
+	parse := cfg.flagValues("parse")
+	if parse["max-depth"] != "4" || parse["exclude"] != "pytest*,black" || parse["internal"] != "acme-*" {
+		t.Errorf("parse values = %v", parse)
+	}
+	if parse["enrich"] != "true" || parse["security-scan"] != "false" || parse["contributors"] != "false" {
+		t.Errorf("providers should turn listed sources on and others off, got %v", parse)
+	}
+	if _, ok := parse["style"]; ok {
+		t.Error("parse should not get render settings")
+	}
+
+	check := cfg.flagValues("check")
+	if _, ok := check["max-depth"]; ok {
+		t.Error("check should not treat max_depth as a policy limit")
+	}
+
+	if got := cfg.flagValues("version"); len(got) != 0 {
+		t.Errorf("version values = %v, want none", got)
+	}
+}
+
+func TestApplyConfig(t *testing.T) {
+	project := t.TempDir()
+	writeConfig(t, filepath.Join(project, projectConfigFile), "style: simple\nquality: fast\n")
+	user := t.TempDir()
+	writeConfig(t, filepath.Join(user, appName, "config.yaml"), "style: handdrawn\npalette: neon\n")
+
+	sub := filepath.Join(project, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(sub)
+	t.Setenv("XDG_CONFIG_HOME", user)
+
+	run := func(t *testing.T, args ...string) map[string]string {
+		t.Helper()
+		root := New(os.Stderr, LogInfo).RootCommand()
+		cmd, rest, err := root.Find(append([]string{"render"}, args...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cmd.ParseFlags(rest); err != nil {
+			t.Fatal(err)
+		}
+		if err := root.PersistentPreRunE(cmd, nil); err != nil {
+			t.Fatalf("PersistentPreRunE() error = %v", err)
+		}
+		got := map[string]string{}
+		for _, name := range []string{"style", "palette", "quality", "ordering"} {
+			got[name] = cmd.Flags().Lookup(name).Value.String()
+		}
+		return got
+	}
+
+	t.Run("project wins over user", func(t *testing.T) {
+		got := run(t)
+		if got["style"] != "simple" || got["palette"] != "neon" || got["quality"] != "fast" {
+			t.Errorf("got %v", got)
+		}
+	})
+
+	t.Run("flags win over config", func(t *testing.T) {
+		got := run(t, "--style", "handdrawn", "--quality", "best")
+		if got["style"] != "handdrawn" || got["quality"] != "best" {
+			t.Errorf("got %v", got)
+		}
+	})
+
+	t.Run("no-config", func(t *testing.T) {
+		got := run(t, "--no-config")
+		if got["style"] == "simple" || got["palette"] == "neon" {
+			t.Errorf("--no-config still applied configuration: %v", got)
+		}
+	})
+
+	t.Run("explicit config", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ci.yaml")
+		writeConfig(t, path, "palette: mono\n")
+		got := run(t, "--config", path)
+		if got["palette"] != "mono" || got["style"] == "simple" {
+			t.Errorf("--config should replace discovery, got %v", got)
+		}
+	})
+
+	t.Run("invalid config is a usage error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bad.yaml")
+		writeConfig(t, path, "colour: red\n")
+		root := New(os.Stderr, LogInfo).RootCommand()
+		cmd, _, _ := root.Find([]string{"render"})
+		if err := cmd.ParseFlags([]string{"--config", path}); err != nil {
+			t.Fatal(err)
+		}
+		err := root.PersistentPreRunE(cmd, nil)
+		if ExitCodeForError(err) != ExitCodeUsage {
+			t.Errorf("exit code = %d, want %d (err %v)", ExitCodeForError(err), ExitCodeUsage, err)
+		}
+	})
+}
+
+func TestApplyQuality(t *testing.T) {
+	c := New(os.Stderr, LogInfo)
+	cmd := c.layoutCommand()
+	if err := cmd.ParseFlags([]string{"--ordering-timeout", "5"}); err != nil {
+		t.Fatal(err)
+	}
+	ordering, timeout := "optimal", 5
+	if err := applyQuality(cmd, "fast", &ordering, &timeout); err != nil {
+		t.Fatal(err)
+	}
+	if ordering != "barycentric" || timeout != 5 {
+		t.Errorf("ordering = %q, timeout = %d; want barycentric and the explicit 5", ordering, timeout)
+	}
+	if err := applyQuality(cmd, "perfect", &ordering, &timeout); ExitCodeForError(err) != ExitCodeUsage {
+		t.Errorf("invalid quality error = %v, want a usage error", err)
+	}
+}
@@ -13,6 +13,17 @@ const (
 	ExitCodeUsage = 2
 	// ExitCodeVuln signals that new vulnerabilities were detected (used by diff --fail-on-vuln).
 	ExitCodeVuln = 3
+	// ExitCodePolicy signals that a policy rule failed (used by check).
+	ExitCodePolicy = 4
+	// ExitCodeResolve signals that dependencies could not be resolved: an
+	// unknown package, a registry failure or a version conflict.
+	ExitCodeResolve = 5
+	// ExitCodeCrossings signals that the rendered layout kept more edge
+	// crossings than --max-crossings allows.
+	ExitCodeCrossings = 6
+	// ExitCodeWarnings signals that the run succeeded with warnings (used
+	// with --strict).
+	ExitCodeWarnings = 7
 	// ExitCodeInterrupted follows shell convention for SIGINT/SIGTERM.
 	ExitCodeInterrupted = 130
 )
@@ -21,8 +32,9 @@ const (
 type ErrorKind string
 
 const (
-	ErrorKindUser   ErrorKind = "user"
-	ErrorKindSystem ErrorKind = "system"
+	ErrorKindUser    ErrorKind = "user"
+	ErrorKindSystem  ErrorKind = "system"
+	ErrorKindResolve ErrorKind = "resolve"
 )
 
 // CLIError is a structured CLI-facing error.
@@ -86,6 +98,40 @@ func WrapSystemError(cause error, message string, hint string) error {
 	}
 }
 
+// WrapResolveError wraps a cause as a dependency resolution failure.
+func WrapResolveError(cause error, message string, hint string) error {
+	return &CLIError{
+		Kind:    ErrorKindResolve,
+		Message: message,
+		Hint:    hint,
+		Cause:   cause,
+	}
+}
+
+// CrossingsError is returned when a layout keeps more edge crossings than
+// --max-crossings allows. It maps to ExitCodeCrossings (6).
+type CrossingsError struct {
+	Crossings int
+	Max       int
+}
+
+func (e *CrossingsError) Error() string {
+	return fmt.Sprintf("layout has %d edge crossings, more than the %d allowed", e.Crossings, e.Max)
+}
+
+// WarningsError is returned under --strict when a run that otherwise
+// succeeded reported warnings. It maps to ExitCodeWarnings (7).
+type WarningsError struct {
+	Count int
+}
+
+func (e *WarningsError) Error() string {
+	if e.Count == 1 {
+		return "1 warning reported (--strict)"
+	}
+	return fmt.Sprintf("%d warnings reported (--strict)", e.Count)
+}
+
 // ExitCodeForError maps errors to stable process exit codes.
 func ExitCodeForError(err error) int {
 	if err == nil {
@@ -100,9 +146,29 @@ func ExitCodeForError(err error) int {
 		return ExitCodeVuln
 	}
 
+	var policyErr *PolicyError
+	if errors.As(err, &policyErr) {
+		return ExitCodePolicy
+	}
+
+	var crossingsErr *CrossingsError
+	if errors.As(err, &crossingsErr) {
+		return ExitCodeCrossings
+	}
+
+	var warningsErr *WarningsError
+	if errors.As(err, &warningsErr) {
+		return ExitCodeWarnings
+	}
+
 	var cliErr *CLIError
-	if errors.As(err, &cliErr) && cliErr.Kind == ErrorKindUser {
-		return ExitCodeUsage
+	if errors.As(err, &cliErr) {
+		switch cliErr.Kind {
+		case ErrorKindUser:
+			return ExitCodeUsage
+		case ErrorKindResolve:
+			return ExitCodeResolve
+		}
 	}
 
 	return ExitCodeFailure
@@ -29,6 +29,14 @@ func TestExitCodeForError(t *testing.T) {
 		{"CLIError system kind returns 1", NewSystemError("network failed", ""), ExitCodeFailure},
 		{"wrapped CLIError system kind returns 1", WrapSystemError(errors.New("cause"), "network failed", ""), ExitCodeFailure},
 
+		// Failed checks
+		{"VulnError returns 3", &VulnError{Count: 1}, ExitCodeVuln},
+		{"PolicyError returns 4", &PolicyError{Failed: 2}, ExitCodePolicy},
+		{"wrapped PolicyError returns 4", fmt.Errorf("check: %w", &PolicyError{Failed: 1}), ExitCodePolicy},
+		{"resolve error returns 5", WrapResolveError(errors.New("not found"), "resolve failed", ""), ExitCodeResolve},
+		{"CrossingsError returns 6", &CrossingsError{Crossings: 3, Max: 0}, ExitCodeCrossings},
+		{"WarningsError returns 7", &WarningsError{Count: 2}, ExitCodeWarnings},
+
 		// Plain errors (exit code 1)
 		{"plain error returns 1", errors.New("something went wrong"), ExitCodeFailure},
 		{"fmt.Errorf returns 1", fmt.Errorf("formatted error"), ExitCodeFailure},
@@ -192,6 +200,12 @@ func TestExitCodeConstants(t *testing.T) {
 	if ExitCodeUsage != 2 {
 		t.Errorf("ExitCodeUsage = %d, want 2", ExitCodeUsage)
 	}
+	if ExitCodePolicy != 4 {
+		t.Errorf("ExitCodePolicy = %d, want 4", ExitCodePolicy)
+	}
+	if ExitCodeResolve != 5 || ExitCodeCrossings != 6 || ExitCodeWarnings != 7 {
+		t.Errorf("resolve, crossings and warnings codes = %d, %d, %d, want 5, 6, 7", ExitCodeResolve, ExitCodeCrossings, ExitCodeWarnings)
+	}
 	if ExitCodeInterrupted != 130 {
 		t.Errorf("ExitCodeInterrupted = %d, want 130 (128 + SIGINT)", ExitCodeInterrupted)
 	}
@@ -217,3 +231,24 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestCheckCrossings(t *testing.T) {
+	tests := []struct {
+		crossings, max int
+		wantErr        bool
+	}{
+		{5, -1, false},
+		{0, 0, false},
+		{3, 3, false},
+		{4, 3, true},
+	}
+	for _, tt := range tests {
+		err := checkCrossings(tt.crossings, tt.max)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkCrossings(%d, %d) = %v, wantErr %v", tt.crossings, tt.max, err, tt.wantErr)
+		}
+		if err != nil && ExitCodeForError(err) != ExitCodeCrossings {
+			t.Errorf("exit code = %d, want %d", ExitCodeForError(err), ExitCodeCrossings)
+		}
+	}
+}
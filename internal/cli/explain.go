@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacktower-io/stacktower/internal/cli/ui"
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+func (c *CLI) explainCommand() *cobra.Command {
+	var (
+		format   string
+		output   string
+		maxPaths int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "explain [graph.json|-] <package>",
+		Short: "Explain why a package is in the graph and how to drop it",
+		Long: `Explain one package of a parsed graph in a single report: every path from
+the roots that pulls it in, how many packages depend on it, its metadata
+and health, and which direct dependency to remove to drop it.
+
+Health and maintenance details need a graph parsed with --enrich, and
+vulnerabilities one parsed with --security-scan.`,
+		Example: `  stacktower explain flask.json markupsafe
+  stacktower explain flask.json markupsafe -f json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.runExplain(args[0], args[1], format, output, maxPaths)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format: text, json")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file (stdout if empty)")
+	cmd.Flags().IntVar(&maxPaths, "max-paths", 10, "Maximum paths to show (0 for all)")
+
+	return cmd
+}
+
+type explainJSON struct {
+	Package              string                `json:"package"`
+	Version              string                `json:"version,omitempty"`
+	Root                 bool                  `json:"root"`
+	Paths                [][]string            `json:"paths"`
+	ShortestDepth        int                   `json:"shortest_depth"`
+	DirectDependents     []string              `json:"direct_dependents"`
+	TransitiveDependents int                   `json:"transitive_dependents"`
+	Dependencies         int                   `json:"dependencies"`
+	Health               feature.PackageHealth `json:"health"`
+	Brittle              bool                  `json:"brittle"`
+	Vulnerabilities      []graph.VulnFinding   `json:"vulnerabilities,omitempty"`
+	PulledInBy           []string              `json:"pulled_in_by"`
+	Drops                int                   `json:"drops,omitempty"`
+	Meta                 map[string]any        `json:"meta,omitempty"`
+}
+
+func (c *CLI) runExplain(input, target, format, output string, maxPaths int) error {
+	if format != "text" && format != "json" {
+		return NewUserError(fmt.Sprintf("unknown format %q", format), "Use --format text or json.")
+	}
+
+	g, err := loadGraph(input)
+	if err != nil {
+		return WrapSystemError(err, "failed to load graph", "")
+	}
+	n, ok := g.Node(target)
+	if !ok {
+		return NewUserError(
+			fmt.Sprintf("package %q not found in the graph", target),
+			fmt.Sprintf("Run `stacktower resolve %s` to see all packages.", input),
+		)
+	}
+
+	health := feature.NodeHealth(n, feature.HealthConfig{}, time.Now(), feature.BrittleThresholds{})
+	r := explainPackage(g, n, health, maxPaths)
+
+	w := os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return WrapSystemError(err, "failed to create output file", "")
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if format == "text" {
+		ui.WriteExplain(w, r)
+		return nil
+	}
+
+	out := explainJSON{
+		Package:              r.Package,
+		Version:              r.Version,
+		Root:                 r.IsRoot,
+		Paths:                r.Paths,
+		ShortestDepth:        r.ShortestDepth,
+		DirectDependents:     r.DirectDependents,
+		TransitiveDependents: r.TransitiveDependents,
+		Dependencies:         r.Dependencies,
+		Health:               health,
+		Brittle:              r.Brittle,
+		Vulnerabilities:      graph.SecurityOf(n.Meta).Findings,
+		PulledInBy:           r.PulledInBy,
+		Drops:                r.Drops,
+		Meta:                 n.Meta,
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return WrapSystemError(err, "failed to write JSON output", "")
+	}
+	return nil
+}
+
+// explainPackage gathers the explain report for n, showing at most
+// maxPaths paths (0 for all).
+func explainPackage(g *dag.DAG, n *dag.Node, health feature.PackageHealth, maxPaths int) ui.ExplainReport {
+	repo := graph.RepoOf(n.Meta)
+	sec := graph.SecurityOf(n.Meta)
+	desc, _ := n.Meta["description"].(string)
+
+	r := ui.ExplainReport{
+		Package:              n.ID,
+		Version:              nodeVersion(g, n.ID),
+		IsRoot:               g.InDegree(n.ID) == 0,
+		ShortestDepth:        -1,
+		DirectDependents:     slices.Sorted(slices.Values(g.Parents(n.ID))),
+		TransitiveDependents: len(ancestors(g, n.ID)),
+		Dependencies:         g.OutDegree(n.ID),
+		Description:          cmp.Or(desc, repo.Description),
+		License:              cmp.Or(sec.License, repo.License),
+		Repository:           repo.URL,
+		Stars:                repo.Stars,
+		LastCommit:           repo.LastCommit,
+		Maintainers:          repo.Maintainers,
+		HealthScore:          health.Score,
+		HealthLevel:          health.Level,
+		Brittle:              feature.IsBrittle(n),
+		VulnSeverity:         sec.VulnSeverity,
+		PulledInBy:           feature.PulledInBy(g, n.ID),
+	}
+	for _, f := range sec.Findings {
+		r.Advisories = append(r.Advisories, fmt.Sprintf("%s (%s)", f.ID, f.Severity))
+	}
+
+	if !r.IsRoot {
+		for _, root := range ui.FindRoots(g) {
+			limit := 0
+			if maxPaths > 0 {
+				if len(r.Paths) >= maxPaths {
+					break
+				}
+				limit = maxPaths - len(r.Paths)
+			}
+			r.Paths = append(r.Paths, dag.FindPaths(g, root, n.ID, limit)...)
+		}
+		r.ShortestDepth = dag.ShortestDepth(r.Paths)
+	}
+
+	if len(r.PulledInBy) == 1 {
+		r.Drops = feature.WeightsByPackage(g)[r.PulledInBy[0]].Exclusive
+	}
+	return r
+}
+
+// ancestors returns every package that depends on id, directly or not.
+func ancestors(g *dag.DAG, id string) map[string]bool {
+	seen := map[string]bool{}
+	stack := []string{id}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, p := range g.Parents(cur) {
+			if !seen[p] {
+				seen[p] = true
+				stack = append(stack, p)
+			}
+		}
+	}
+	return seen
+}
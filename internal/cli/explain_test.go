@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/internal/cli/ui"
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+)
+
+func buildExplainGraph() *dag.DAG {
+	// app -> web -> templates -> markup
+	// app -> cli -> markup
+	g := dag.New(nil)
+	for _, id := range []string{"app", "web", "cli", "templates", "markup"} {
+		_ = g.AddNode(dag.Node{ID: id, Meta: dag.Metadata{"version": "1.0"}})
+	}
+	for _, e := range [][2]string{
+		{"app", "web"}, {"app", "cli"},
+		{"web", "templates"}, {"templates", "markup"}, {"cli", "markup"},
+	} {
+		_ = g.AddEdge(dag.Edge{From: e[0], To: e[1]})
+	}
+	return g
+}
+
+func TestExplainPackage(t *testing.T) {
+	g := buildExplainGraph()
+	explain := func(id string, maxPaths int) ui.ExplainReport {
+		n, _ := g.Node(id)
+		return explainPackage(g, n, feature.PackageHealth{}, maxPaths)
+	}
+
+	r := explain("markup", 10)
+	if len(r.Paths) != 2 || r.ShortestDepth != 2 {
+		t.Errorf("paths = %v, shortest %d; want 2 paths at depth 2", r.Paths, r.ShortestDepth)
+	}
+	if !slices.Equal(r.DirectDependents, []string{"cli", "templates"}) || r.TransitiveDependents != 4 {
+		t.Errorf("dependents = %v (%d in total)", r.DirectDependents, r.TransitiveDependents)
+	}
+	if !slices.Equal(r.PulledInBy, []string{"cli", "web"}) || r.Drops != 0 {
+		t.Errorf("pulled in by %v, drops %d; want cli and web, no single removal", r.PulledInBy, r.Drops)
+	}
+
+	r = explain("templates", 10)
+	if !slices.Equal(r.PulledInBy, []string{"web"}) || r.Drops != 2 {
+		t.Errorf("pulled in by %v, drops %d; want web dropping 2", r.PulledInBy, r.Drops)
+	}
+
+	if r := explain("markup", 1); len(r.Paths) != 1 {
+		t.Errorf("--max-paths 1 gave %d paths", len(r.Paths))
+	}
+	if r := explain("app", 10); !r.IsRoot || r.Paths != nil {
+		t.Errorf("root report = %+v", r)
+	}
+
+	var buf bytes.Buffer
+	ui.WriteExplain(&buf, explain("templates", 10))
+	for _, want := range []string{"Why it is here", "app → web → templates", "Remove web to drop it"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("text output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacktower-io/stacktower/pkg/graph"
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+)
+
+func (c *CLI) exportCommand() *cobra.Command {
+	var (
+		format string
+		output string
+		opts   graph.ExportOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export [graph.json|-]",
+		Short: "Export dependency graph as GraphML, GEXF or trimmed JSON",
+		Long: `Export the parsed dependency graph as GraphML or GEXF for graph tooling
+such as Gephi, yEd, Cytoscape, and NetworkX, or as graph JSON.
+
+Package metadata becomes typed node attributes, and version constraints
+become edge attributes. Both formats can be read back: every command that
+takes graph.json also accepts GraphML and GEXF files.
+
+The projection flags trim what is exported, in any format: keep only some
+metadata keys, drop bulky metadata or URLs before publishing a graph,
+anonymize a graph of internal code so its shape can be shared, or drop
+the synthetic nodes layout transforms insert.`,
+		Example: `  stacktower export graph.json -f graphml -o graph.graphml
+  stacktower export graph.json -f gexf -o graph.gexf
+
+  # Publish a graph without internal URLs or license texts
+  stacktower export graph.json -f json --strip-urls --strip-heavy -o public.json
+
+  # Share the shape of an internal tower in a bug report
+  stacktower export internal.json -f json --anonymize -o anonymous.json
+
+  # Minimal payload for a web frontend
+  stacktower export graph.json -f json --meta-keys version,repo_stars --compact`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.runExport(args[0], format, output, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", graph.FormatGraphML, "Export format: graphml, gexf, json")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file (stdout if empty)")
+	cmd.Flags().StringSliceVar(&opts.MetaKeys, "meta-keys", nil, "Keep only these node metadata keys (comma-separated)")
+	cmd.Flags().BoolVar(&opts.StripHeavy, "strip-heavy", false, "Drop bulky metadata: license texts, advisories, icons, descriptions")
+	cmd.Flags().BoolVar(&opts.StripURLs, "strip-urls", false, "Drop repository, homepage and icon URLs")
+	cmd.Flags().BoolVar(&opts.Anonymize, "anonymize", false, "Rename nodes to pkg1, pkg2, ... and drop package names, URLs and people")
+	cmd.Flags().BoolVar(&opts.ExcludeSynthetic, "exclude-synthetic", false, "Drop subdivider and auxiliary nodes, reconnecting their edges")
+	cmd.Flags().BoolVar(&opts.Compact, "compact", false, "Write JSON without indentation (json format only)")
+	cmd.Flags().BoolVar(&opts.Canonical, "canonical", false, "Write canonical JSON for minimal diffs (json format only)")
+
+	return cmd
+}
+
+func (c *CLI) runExport(input, format, output string, opts graph.ExportOptions) error {
+	g, err := loadGraph(input)
+	if err != nil {
+		return WrapSystemError(err, "failed to load graph", "")
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case graph.FormatGraphML:
+		err = graph.ExportGraphML(graph.Project(g, opts), &buf)
+	case graph.FormatGEXF:
+		err = graph.ExportGEXF(graph.Project(g, opts), &buf)
+	case pipeline.FormatJSON:
+		err = graph.ExportJSON(g, &buf, opts)
+	default:
+		return NewUserError(fmt.Sprintf("unknown export format %q", format), "Use --format graphml, gexf or json.")
+	}
+	if err != nil {
+		return WrapSystemError(err, "failed to export graph", "")
+	}
+
+	if output != "" {
+		if err := os.WriteFile(output, buf.Bytes(), 0644); err != nil {
+			return WrapSystemError(err, "failed to write export file", "")
+		}
+		return nil
+	}
+	if _, err := os.Stdout.Write(buf.Bytes()); err != nil {
+		return WrapSystemError(err, "failed to write export to stdout", "")
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag/gen"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+func TestRunGenerate(t *testing.T) {
+	c := New(os.Stderr, LogInfo)
+	out := filepath.Join(t.TempDir(), "demo.json")
+	if err := c.runGenerate(gen.Options{Depth: 3, Width: 4, FanOut: 2, Metadata: true, Seed: 9}, out); err != nil {
+		t.Fatal(err)
+	}
+	g, err := graph.ReadGraphFile(out)
+	if err != nil {
+		t.Fatalf("generated graph does not read back: %v", err)
+	}
+	if len(g.Sources()) != 1 || g.NodeCount() < 4 {
+		t.Errorf("unexpected graph: %d sources, %d nodes", len(g.Sources()), g.NodeCount())
+	}
+
+	if err := c.runGenerate(gen.Options{Depth: 3, Width: 4, FanOut: 2, Tangle: 2}, out); ExitCodeForError(err) != ExitCodeUsage {
+		t.Errorf("tangle out of range: exit code %d, want %d", ExitCodeForError(err), ExitCodeUsage)
+	}
+}
@@ -20,6 +20,10 @@ func (c *CLI) layoutCommand() *cobra.Command {
 		output       string
 		noCache      bool
 		orderTimeout int
+		scoringFile  string
+		nebraskaBy   string
+		healthFile   string
+		quality      string
 	)
 	opts := pipeline.Options{}
 	setCLIDefaults(&opts)
@@ -38,6 +42,22 @@ Supports both tower (-t tower) and nodelink (-t nodelink) visualization types.
 Results are cached locally for faster subsequent runs.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyQuality(cmd, quality, &opts.Ordering, &orderTimeout); err != nil {
+				return err
+			}
+			if err := pipeline.ValidateColorBy(opts.ColorBy); err != nil {
+				return err
+			}
+			scoring, err := loadNebraskaScoring(scoringFile, nebraskaBy)
+			if err != nil {
+				return err
+			}
+			opts.NebraskaScoring = scoring
+			health, err := loadHealthConfig(healthFile)
+			if err != nil {
+				return err
+			}
+			opts.Health = health
 			return c.runLayout(cmd.Context(), args[0], opts, output, noCache, orderTimeout)
 		},
 	}
@@ -47,16 +67,27 @@ Results are cached locally for faster subsequent runs.`,
 	cmd.Flags().BoolVar(&noCache, "no-cache", false, "disable caching")
 
 	// Layout flags
-	cmd.Flags().StringVarP(&opts.VizType, "type", "t", opts.VizType, "visualization type: tower (default), nodelink")
+	cmd.Flags().StringVarP(&opts.VizType, "type", "t", opts.VizType, "visualization type: tower (default), nodelink, sunburst, treemap, dsm")
 	cmd.Flags().BoolVar(&opts.Normalize, "normalize", opts.Normalize, "apply graph normalization")
+	cmd.Flags().BoolVar(&opts.FixedRows, "fixed-rows", opts.FixedRows, "keep the rows set in the input graph when normalizing, failing if they conflict (tower)")
 	cmd.Flags().Float64Var(&opts.Width, "width", opts.Width, "frame width")
 	cmd.Flags().Float64Var(&opts.Height, "height", opts.Height, "frame height")
 	cmd.Flags().StringVar(&opts.Ordering, "ordering", opts.Ordering, "ordering algorithm: optimal (default), barycentric")
+	cmd.Flags().StringVar(&opts.ColorBy, "color-by", opts.ColorBy, "fill nodes by health score (nodelink; tower colours are chosen at render time)")
+	cmd.Flags().StringVar(&opts.ClusterBy, "cluster-by", opts.ClusterBy, "group nodes into boxes by metadata: owner, language, or any key such as workspace (nodelink)")
+	cmd.Flags().BoolVar(&opts.EdgeLabels, "edge-labels", opts.EdgeLabels, "label edges with their version constraints (nodelink)")
+	cmd.Flags().BoolVar(&opts.EdgeKinds, "edge-kinds", opts.EdgeKinds, "draw dev, optional and peer dependency edges dashed or dotted (nodelink)")
 	cmd.Flags().BoolVar(&opts.Randomize, "randomize", opts.Randomize, "randomize block widths (tower)")
+	cmd.Flags().Uint64Var(&opts.Seed, "seed", opts.Seed, "seed for ordering restarts, --randomize and the handdrawn style; equal seeds give identical output")
+	cmd.Flags().IntVar(&opts.Restarts, "restarts", opts.Restarts, "extra barycentric ordering runs from seeded shuffled orders (tower)")
 	cmd.Flags().BoolVar(&opts.Merge, "merge", opts.Merge, "merge subdivider blocks (tower)")
 	cmd.Flags().BoolVar(&opts.Nebraska, "nebraska", opts.Nebraska, "show Nebraska maintainer ranking (tower)")
+	cmd.Flags().StringVar(&scoringFile, "nebraska-scoring", "", "YAML/JSON file with Nebraska role weights, depth curve, star damping, and org filters")
+	cmd.Flags().StringVar(&nebraskaBy, "nebraska-by", "", "rank Nebraska by maintainer (default) or org")
+	cmd.Flags().StringVar(&healthFile, "health-weights", "", "YAML/JSON file weighting the health score: stars, maintainers, cadence, vulns, scorecard")
 	cmd.Flags().IntVar(&orderTimeout, "ordering-timeout", defaultOrderTimeout, "timeout in seconds for optimal ordering search")
-	cmd.Flags().StringVar(&opts.Style, "style", opts.Style, "visual style: handdrawn (default), simple")
+	cmd.Flags().StringVar(&quality, "quality", "", qualityUsage)
+	cmd.Flags().StringVar(&opts.Style, "style", opts.Style, styleUsage())
 
 	// Security flags
 	cmd.Flags().BoolVar(&opts.ShowVulns, "show-vulns", opts.ShowVulns, "show vulnerability severity colours (requires scanned graph)")
@@ -70,7 +101,7 @@ Results are cached locally for faster subsequent runs.`,
 func (c *CLI) runLayout(ctx context.Context, input string, opts pipeline.Options, output string, noCache bool, orderTimeout int) error {
 	start := time.Now()
 
-	g, err := graph.ReadGraphFile(input)
+	g, err := loadGraph(input)
 	if err != nil {
 		return WrapSystemError(err, fmt.Sprintf("failed to load graph %s", input), "Check that the file exists and is valid JSON.")
 	}
@@ -81,9 +112,9 @@ func (c *CLI) runLayout(ctx context.Context, input string, opts pipeline.Options
 	}
 	defer runner.Close()
 
-	opts.Logger = c.Logger
+	opts.Logger = structuredLogger(c.Logger)
 	if opts.NeedsOptimalOrderer() {
-		opts.Orderer = c.newOptimalOrderer(orderTimeout)
+		opts.Orderer = c.newOptimalOrderer(orderTimeout, opts)
 	}
 
 	workGraph, err := runner.PrepareGraph(g, opts)
@@ -26,11 +26,13 @@ import (
 // parseFlags holds parse command options.
 type parseFlags struct {
 	pipeline.Options
-	output  string
-	noCache bool
-	name    string // project name override for manifest parsing
-	scan    bool   // run vulnerability scan after parsing
-	enrich  bool   // enrich with GitHub metadata (default true for parse)
+	output    string
+	noCache   bool
+	canonical bool   // write canonical, diff-friendly JSON
+	name      string // project name override for manifest parsing
+	scan      bool   // run vulnerability scan after parsing
+	enrich    bool   // enrich with GitHub metadata (default true for parse)
+	merge     bool   // merge every manifest found in a directory
 }
 
 // parseCommand creates the parse command with language-specific subcommands.
@@ -48,11 +50,15 @@ func (c *CLI) parseCommand() *cobra.Command {
 		Long: `Parse dependency graphs from package managers or local manifest files.
 
 The command auto-detects the language from manifest filenames when given a file path.
+Given a directory, it finds the manifests inside (preferring lockfiles) and parses
+the top-level one, or all of them merged into one graph with --merge.
 Use language subcommands (e.g., 'parse python') to parse packages by name.
 Results are cached locally for faster subsequent runs.
 
 Examples:
   stacktower parse poetry.lock                            # Auto-detect language from file
+  stacktower parse .                                      # Detect manifests in a directory
+  stacktower parse . --merge                              # Merge every manifest found
   stacktower parse package.json                           # Auto-detect JavaScript
   stacktower parse python requests                        # Package from PyPI
   stacktower parse python poetry.lock                     # Explicit language + file
@@ -68,16 +74,28 @@ Examples:
 
 	cmd.PersistentFlags().IntVar(&flags.MaxDepth, "max-depth", flags.MaxDepth, "maximum dependency depth")
 	cmd.PersistentFlags().IntVar(&flags.MaxNodes, "max-nodes", flags.MaxNodes, "maximum nodes to fetch")
+	cmd.PersistentFlags().DurationVar(&flags.MaxDuration, "max-duration", 0, "stop resolving after this long and keep the partial graph, e.g. 30s (0 = no limit)")
 	cmd.PersistentFlags().IntVar(&flags.Workers, "workers", flags.Workers, "concurrent fetch workers (default 20)")
 	cmd.PersistentFlags().BoolVar(&flags.enrich, "enrich", true, "enrich with GitHub metadata (stars, maintainers)")
 	cmd.PersistentFlags().BoolVar(&flags.FetchContributors, "contributors", false, "fetch GitHub contributors for Nebraska rankings (slower)")
+	cmd.PersistentFlags().IntVar(&flags.EnrichDepth, "enrich-depth", 0, "enrich only packages up to this depth below the root (0 = all)")
+	cmd.PersistentFlags().IntVar(&flags.EnrichMinDependents, "enrich-min-dependents", 0, "also enrich packages with at least this many dependents")
+	cmd.PersistentFlags().BoolVar(&flags.LocalMetadata, "local-metadata", false, "read metadata of vendored dependencies (node_modules, vendor, site-packages, submodules) from disk")
+	cmd.PersistentFlags().BoolVar(&flags.Icons, "icons", false, "fetch package icons (registry logos, GitHub owner avatars) and embed them in the graph")
 	cmd.PersistentFlags().StringVar(&flags.DependencyScope, "dependency-scope", "prod_only", "dependency scope: prod_only or all")
 	cmd.PersistentFlags().BoolVar(&flags.IncludePrerelease, "include-prerelease", false, "include prerelease versions (alpha/beta/rc/dev/etc.)")
 	cmd.PersistentFlags().StringVar(&flags.RuntimeVersion, "runtime-version", "", "target runtime version for marker evaluation (e.g., '3.11' for Python)")
+	cmd.PersistentFlags().StringVar(&flags.Platform, "platform", "", "target platform for marker evaluation: linux (default), darwin or windows")
+	cmd.PersistentFlags().BoolVar(&flags.IncludeIndirect, "include-indirect", false, "follow '// indirect' requirements of go.mod files (Go only)")
+	cmd.PersistentFlags().BoolVar(&flags.SparseIndex, "sparse-index", false, "resolve crates from the sparse crates.io index instead of the API (Rust only)")
 	cmd.PersistentFlags().StringVarP(&flags.output, "output", "o", "", "output file (stdout if empty)")
 	cmd.PersistentFlags().StringVarP(&flags.name, "name", "n", "", "project name (for manifest parsing)")
 	cmd.PersistentFlags().BoolVar(&flags.noCache, "no-cache", false, "disable caching")
+	cmd.PersistentFlags().BoolVar(&flags.canonical, "canonical", false, "write canonical JSON (fully sorted, unescaped) for minimal diffs in version control")
 	cmd.PersistentFlags().BoolVar(&flags.scan, "security-scan", false, "best-effort scan for known vulnerabilities (OSV.dev)")
+	cmd.PersistentFlags().BoolVar(&flags.merge, "merge", false, "when parsing a directory, merge all manifests found into one graph")
+	cmd.PersistentFlags().StringSliceVar(&flags.Exclude, "exclude", nil, "drop packages matching these globs, with the deps only they pull in (comma-separated)")
+	cmd.PersistentFlags().StringSliceVar(&flags.Internal, "internal", nil, "mark packages matching these globs as first-party code (comma-separated)")
 
 	for _, lang := range languages.All {
 		cmd.AddCommand(c.langCommand(lang, &flags))
@@ -102,6 +120,9 @@ func (c *CLI) langCommand(lang *deps.Language, flags *parseFlags) *cobra.Command
 
 // runParseAutoDetect detects the language from a manifest file and parses it.
 func (c *CLI) runParseAutoDetect(ctx context.Context, flags *parseFlags, path string) error {
+	if isDir(path) {
+		return c.parseDir(ctx, nil, flags, path)
+	}
 	if !looksLikeFile(path) {
 		return NewUserError(
 			fmt.Sprintf("cannot auto-detect language for %q (not a manifest file)", path),
@@ -109,14 +130,13 @@ func (c *CLI) runParseAutoDetect(ctx context.Context, flags *parseFlags, path st
 		)
 	}
 
-	// Look up language from manifest filename
-	manifestMap := deps.SupportedManifests(languages.All)
+	// Look up language from manifest filename and content
 	filename := filepath.Base(path)
-	langName, ok := manifestMap[filename]
-	if !ok {
+	langName := manifestLanguage(path)
+	if langName == "" {
 		return NewUserError(
 			fmt.Sprintf("unsupported manifest file: %s", filename),
-			fmt.Sprintf("Supported manifests: %s", formatSupportedManifests(manifestMap)),
+			fmt.Sprintf("Supported manifests: %s", formatSupportedManifests(deps.SupportedManifests(languages.All))),
 		)
 	}
 
@@ -139,7 +159,7 @@ func formatSupportedManifests(manifestMap map[string]string) string {
 	}
 
 	var parts []string
-	for _, lang := range []string{"python", "javascript", "go", "rust", "ruby", "php", "java"} {
+	for _, lang := range []string{"python", "javascript", "go", "rust", "ruby", "php", "java", "dotnet"} {
 		if files, ok := byLang[lang]; ok {
 			slices.Sort(files)
 			parts = append(parts, strings.Join(files, ", "))
@@ -150,6 +170,9 @@ func formatSupportedManifests(manifestMap map[string]string) string {
 
 // runParse auto-detects whether arg is a manifest file or package name.
 func (c *CLI) runParse(ctx context.Context, lang *deps.Language, flags *parseFlags, arg string) error {
+	if lang.HasManifests() && isDir(arg) {
+		return c.parseDir(ctx, lang, flags, arg)
+	}
 	if lang.HasManifests() && looksLikeFile(arg) {
 		return c.parseManifest(ctx, lang, flags, arg)
 	}
@@ -171,22 +194,7 @@ func (c *CLI) runParse(ctx context.Context, lang *deps.Language, flags *parseFla
 // parsePackageVersion extracts package name and optional version from "package@version" syntax.
 // Returns (package, version) where version is empty if not specified.
 func parsePackageVersion(arg string) (string, string) {
-	// Handle scoped packages like @scope/pkg@version
-	if strings.HasPrefix(arg, "@") {
-		// Find @ after the scope
-		idx := strings.Index(arg[1:], "@")
-		if idx != -1 {
-			idx++ // Account for the leading @
-			return arg[:idx], arg[idx+1:]
-		}
-		return arg, ""
-	}
-
-	// Regular package@version
-	if idx := strings.LastIndex(arg, "@"); idx != -1 {
-		return arg[:idx], arg[idx+1:]
-	}
-	return arg, ""
+	return deps.ParsePackageSpec(arg)
 }
 
 // parsePackage parses a package using the pipeline service.
@@ -219,6 +227,7 @@ func (c *CLI) parsePackage(ctx context.Context, lang *deps.Language, flags *pars
 	return finishParse(finishParseOpts{
 		Graph:          result.Graph,
 		Output:         flags.output,
+		Canonical:      flags.canonical,
 		LangName:       lang.Name,
 		Source:         displayName,
 		CacheHit:       result.CacheHit,
@@ -265,6 +274,7 @@ func (c *CLI) parseManifest(ctx context.Context, lang *deps.Language, flags *par
 	return finishParse(finishParseOpts{
 		Graph:          result.Graph,
 		Output:         flags.output,
+		Canonical:      flags.canonical,
 		LangName:       lang.Name,
 		Source:         filepath.Base(filePath),
 		CacheHit:       result.CacheHit,
@@ -278,6 +288,7 @@ func (c *CLI) parseManifest(ctx context.Context, lang *deps.Language, flags *par
 type finishParseOpts struct {
 	Graph          *dag.DAG
 	Output         string
+	Canonical      bool // write canonical JSON (see graph.ExportOptions)
 	LangName       string
 	Source         string
 	CacheHit       bool
@@ -285,6 +296,7 @@ type finishParseOpts struct {
 	RuntimeVersion string
 	RuntimeSource  string
 	Ref            string // git ref (branch/tag) for GitHub-parsed packages
+	Command        string // parse arguments for the "Save as JSON" hint (default "<lang> <source>")
 }
 
 // finishParse writes output and prints summary.
@@ -301,7 +313,8 @@ func finishParse(opts finishParseOpts) error {
 	cacheHit := opts.CacheHit
 	elapsed := opts.Elapsed
 
-	isTTY := isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
+	// JSON log mode is for wrappers, so it never shows the terminal views.
+	isTTY := !ui.IsJSON() && (isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd()))
 
 	// Always show resolve table + tree on TTY, regardless of output file
 	if isTTY {
@@ -351,7 +364,7 @@ func finishParse(opts finishParseOpts) error {
 	}
 
 	if output != "" {
-		if err := graph.WriteGraphFile(g, output); err != nil {
+		if err := graph.ExportJSONFile(g, output, graph.ExportOptions{Canonical: opts.Canonical}); err != nil {
 			return WrapSystemError(err, "failed to write output file", "Check that the output path is writable.")
 		}
 
@@ -365,18 +378,51 @@ func finishParse(opts finishParseOpts) error {
 		}
 		ui.PrintNewline()
 		ui.PrintNextStep("Render", "stacktower render "+output)
+		parseResultEvent(opts)
 		return nil
 	}
 
 	if !isTTY {
-		return graph.WriteGraph(g, os.Stdout)
+		if err := graph.ExportJSON(g, os.Stdout, graph.ExportOptions{Canonical: opts.Canonical}); err != nil {
+			return err
+		}
+		parseResultEvent(opts)
+		return nil
 	}
 
 	suggested := suggestOutputName(g, opts.Ref)
-	ui.PrintNextStep("Save as JSON", fmt.Sprintf("stacktower parse %s %s -o %s", langName, source, suggested))
+	command := opts.Command
+	if command == "" {
+		command = langName + " " + source
+	}
+	ui.PrintNextStep("Save as JSON", fmt.Sprintf("stacktower parse %s -o %s", command, suggested))
 	return nil
 }
 
+// parseResultEvent writes the "result" event of a parse in JSON log mode.
+func parseResultEvent(opts finishParseOpts) {
+	if !ui.IsJSON() {
+		return
+	}
+	fields := map[string]any{
+		"command":    "parse",
+		"source":     opts.Source,
+		"language":   opts.LangName,
+		"nodes":      opts.Graph.NodeCount(),
+		"edges":      opts.Graph.EdgeCount(),
+		"depth":      ui.GraphDepth(opts.Graph),
+		"cached":     opts.CacheHit,
+		"elapsed_ms": opts.Elapsed.Milliseconds(),
+	}
+	if opts.Output != "" {
+		fields["output"] = opts.Output
+	}
+	if opts.RuntimeVersion != "" {
+		fields["runtime_version"] = opts.RuntimeVersion
+	}
+	ui.Event("result", fields)
+}
+
 // suggestOutputName builds a descriptive filename from the graph's root node.
 // For registry packages it produces "flask-3.1.0.json"; for GitHub-parsed
 // packages with a ref it produces "repo-v2.0.0.json". Falls back to
@@ -423,6 +469,20 @@ func looksLikeFile(arg string) bool {
 	return deps.IsManifestSupported(base, languages.All)
 }
 
+// manifestLanguage returns the language most confident it reads a manifest
+// (see [deps.DetectManifestFile]), or the one matching its filename when the
+// file cannot be read. It returns "" for unsupported files.
+func manifestLanguage(path string) string {
+	candidates, err := deps.DetectManifestFile(path, languages.All)
+	if err != nil {
+		return deps.GetManifestLanguage(filepath.Base(path), languages.All)
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[0].Language.Name
+}
+
 // getGitHubToken returns the GitHub token from environment or stored session.
 // Priority: GITHUB_TOKEN env var > stored CLI session > empty string.
 func getGitHubToken(ctx context.Context) string {
@@ -498,11 +558,13 @@ func validatePackageName(name string) error {
 	return nil
 }
 
+// wrapParseFailure turns a resolution failure into a CLI error with a
+// hint for its likely cause. It maps to ExitCodeResolve.
 func wrapParseFailure(operation string, err error) error {
 	// Check for diamond dependency error first (npm-specific)
 	var diamondErr *deps.DiamondDependencyError
 	if errors.As(err, &diamondErr) {
-		return WrapSystemError(diamondErr, operation+" failed",
+		return WrapResolveError(diamondErr, operation+" failed",
 			fmt.Sprintf(`Dependency conflict: %q requires incompatible versions.
 
 npm allows multiple versions via nested node_modules, but this resolver
@@ -514,14 +576,14 @@ Workaround: Use package-lock.json which has the pre-resolved tree:
 
 	switch {
 	case errors.Is(err, integrations.ErrNotFound):
-		return WrapSystemError(err, operation+" failed", "Package not found. Check the package name and spelling.")
+		return WrapResolveError(err, operation+" failed", "Package not found. Check the package name and spelling.")
 	case integrations.IsRateLimitedError(err):
-		return WrapSystemError(err, operation+" failed", "Rate limit exceeded. Wait and retry, or configure GITHUB_TOKEN for higher limits.")
+		return WrapResolveError(err, operation+" failed", "Rate limit exceeded. Wait and retry, or configure GITHUB_TOKEN for higher limits.")
 	case errors.Is(err, context.DeadlineExceeded):
-		return WrapSystemError(err, operation+" timed out", "Retry with a longer timeout, fewer nodes, or with cache enabled.")
+		return WrapResolveError(err, operation+" timed out", "Retry with a longer timeout, fewer nodes, or with cache enabled.")
 	case errors.Is(err, context.Canceled):
 		return err
 	default:
-		return WrapSystemError(err, operation+" failed", "Re-run with --verbose for diagnostics and check network connectivity.")
+		return WrapResolveError(err, operation+" failed", "Re-run with --verbose for diagnostics and check network connectivity.")
 	}
 }
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/stacktower-io/stacktower/internal/cli/ui"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/languages"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+// isDir reports whether path is an existing directory.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// parseDir finds the manifests in dir and parses the top-level one, or all
+// of them merged into one graph with --merge. A non-nil lang restricts the
+// search to that language.
+func (c *CLI) parseDir(ctx context.Context, lang *deps.Language, flags *parseFlags, dir string) error {
+	langs := languages.All
+	if lang != nil {
+		langs = []*deps.Language{lang}
+	}
+	found, err := deps.FindManifests(dir, langs)
+	if err != nil {
+		return WrapUserError(err, "failed to scan directory", "Check that the directory exists and is readable.")
+	}
+	if len(found) == 0 {
+		return NewUserError(
+			fmt.Sprintf("no supported manifests found in %s", dir),
+			fmt.Sprintf("Supported manifests: %s", formatSupportedManifests(deps.SupportedManifests(langs))),
+		)
+	}
+
+	dirFlags := *flags
+	if dirFlags.name == "" {
+		dirFlags.name = dirName(dir)
+	}
+
+	if !flags.merge || len(found) == 1 {
+		m := found[0]
+		if len(found) > 1 {
+			ui.PrintInfo("Found %d manifests; parsing %s (use --merge to combine them)", len(found), manifestLabel(m))
+		}
+		return c.parseManifest(ctx, languages.Find(m.Language), &dirFlags, m.Path)
+	}
+	return c.parseMerged(ctx, &dirFlags, dir, found)
+}
+
+// parseMerged parses every manifest in found and merges the graphs under
+// one project root named after the directory.
+func (c *CLI) parseMerged(ctx context.Context, flags *parseFlags, dir string, found []deps.FoundManifest) error {
+	start := time.Now()
+
+	if err := validateFlags(flags.MaxDepth, flags.MaxNodes); err != nil {
+		return err
+	}
+
+	projects := make([]deps.Project, 0, len(found))
+	var langNames []string
+	cacheHit := true
+	for i, m := range found {
+		label := manifestLabel(m)
+		content, err := os.ReadFile(m.Path)
+		if err != nil {
+			return WrapUserError(err, "failed to read manifest file "+label, "Check that the file is readable.")
+		}
+
+		opts := flags.Options
+		opts.Language = m.Language
+		opts.Manifest = string(content)
+		opts.ManifestFilename = m.Filename
+		opts.ManifestPath = m.Path
+		opts.SkipEnrich = !flags.enrich
+
+		result, err := c.runParseWithProgress(ctx, opts, flags.noCache, flags.scan,
+			fmt.Sprintf("Parsing %s (%d/%d)...", label, i+1, len(found)), flags.MaxNodes)
+		if err != nil {
+			return wrapParseFailure("parse "+label, err)
+		}
+
+		projects = append(projects, deps.Project{Name: label, Language: m.Language, Graph: result.Graph})
+		if !slices.Contains(langNames, m.Language) {
+			langNames = append(langNames, m.Language)
+		}
+		cacheHit = cacheHit && result.CacheHit
+	}
+
+	g, err := deps.MergeProjects(projects)
+	if err != nil {
+		return WrapSystemError(err, "failed to merge manifests", "Parse the manifests one at a time instead.")
+	}
+	g.RenameNode(graph.ProjectRootNodeID, flags.name) //nolint:errcheck // non-critical rename
+
+	return finishParse(finishParseOpts{
+		Graph:     g,
+		Output:    flags.output,
+		Canonical: flags.canonical,
+		LangName:  strings.Join(langNames, ", "),
+		Source:    fmt.Sprintf("%d manifests in %s", len(found), dir),
+		CacheHit:  cacheHit,
+		Elapsed:   time.Since(start),
+		Command:   dir + " --merge",
+	})
+}
+
+// manifestLabel names a found manifest by its path within the searched
+// directory, e.g. "web/package.json".
+func manifestLabel(m deps.FoundManifest) string {
+	return path.Join(m.Dir, m.Filename)
+}
+
+// dirName returns the base name of dir, resolving "." and "..".
+func dirName(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return filepath.Base(dir)
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+)
+
+func copyManifest(t *testing.T, name, dst string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("../../examples/manifest", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseDir_Merge(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "monorepo")
+	copyManifest(t, "poetry.lock", filepath.Join(dir, "poetry.lock"))
+	copyManifest(t, "pyproject.toml", filepath.Join(dir, "pyproject.toml"))
+	copyManifest(t, "package-lock.json", filepath.Join(dir, "web", "package-lock.json"))
+
+	c := New(os.Stderr, LogInfo)
+	flags := parseFlags{
+		Options: pipeline.Options{MaxDepth: pipeline.DefaultMaxDepth, MaxNodes: pipeline.DefaultMaxNodes},
+		output:  filepath.Join(t.TempDir(), "merged.json"),
+		noCache: true,
+		merge:   true,
+	}
+	if err := c.parseDir(context.Background(), nil, &flags, dir); err != nil {
+		t.Fatalf("parseDir error = %v", err)
+	}
+
+	g, err := loadGraph(flags.output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	children := g.Children("monorepo")
+	if len(children) != 2 || children[0] != "poetry.lock" || children[1] != "web/package-lock.json" {
+		t.Errorf("project children = %v, want [poetry.lock web/package-lock.json]", children)
+	}
+	if len(g.Children("poetry.lock")) == 0 || len(g.Children("web/package-lock.json")) == 0 {
+		t.Error("merged projects have no dependencies")
+	}
+}
+
+func TestParseDir_NoManifests(t *testing.T) {
+	c := New(os.Stderr, LogInfo)
+	err := c.parseDir(context.Background(), nil, &parseFlags{}, t.TempDir())
+	if ExitCodeForError(err) != ExitCodeUsage {
+		t.Errorf("error = %v, want usage error", err)
+	}
+}
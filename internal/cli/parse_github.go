@@ -260,6 +260,7 @@ func (c *CLI) runParseGitHub(ctx context.Context, args []string, flags *parseFla
 	return finishParse(finishParseOpts{
 		Graph:          result.Graph,
 		Output:         flags.output,
+		Canonical:      flags.canonical,
 		LangName:       lang.Name,
 		Source:         filepath.Base(tmpFile),
 		CacheHit:       result.CacheHit,
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacktower-io/stacktower/internal/cli/ui"
+	"github.com/stacktower-io/stacktower/pkg/plugin"
+)
+
+// LoadPlugins discovers plugins in ~/.config/stacktower/plugins and on
+// PATH and registers what they add. It must run before RootCommand, which
+// builds the parse subcommands from the registered languages. Broken or
+// clashing plugins are reported as warnings and skipped.
+func (c *CLI) LoadPlugins() {
+	plugins, errs := plugin.Discover(pluginDirs())
+	errs = append(errs, plugin.Register(plugins)...)
+	for _, err := range errs {
+		ui.PrintWarning("%v", err)
+	}
+	c.plugins = plugins
+}
+
+// pluginDirs returns the directories searched for plugins, in order.
+func pluginDirs() []string {
+	var dirs []string
+	if dir, err := configDir(); err == nil {
+		dirs = append(dirs, filepath.Join(dir, "plugins"))
+	}
+	return append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+// pluginsCommand creates the plugins command, which lists loaded plugins.
+func (c *CLI) pluginsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "plugins",
+		Short: "List installed plugins and what they add",
+		Long: fmt.Sprintf(`List installed plugins and what they add.
+
+Plugins are executables named %s<name>, found in
+~/.config/stacktower/plugins or on PATH. They add languages, manifest
+parsers, metadata providers and output formats.`, plugin.Prefix),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printPlugins(c.plugins)
+			return nil
+		},
+	}
+}
+
+func printPlugins(plugins []*plugin.Plugin) {
+	if len(plugins) == 0 {
+		ui.PrintInfo("No plugins installed")
+		ui.PrintDetail("Put %s<name> executables in ~/.config/stacktower/plugins or on PATH", plugin.Prefix)
+		return
+	}
+	ui.PrintHeader("Plugins")
+	for _, p := range plugins {
+		title := p.Name
+		if p.Version != "" {
+			title += " " + p.Version
+		}
+		ui.PrintKeyValue(title, p.Path)
+		for _, line := range pluginCapabilities(p) {
+			ui.PrintDetail("%s", line)
+		}
+	}
+}
+
+// pluginCapabilities describes what a plugin adds, one line per kind.
+func pluginCapabilities(p *plugin.Plugin) []string {
+	var lines []string
+	for _, l := range p.Languages {
+		line := "language " + l.Name
+		if len(l.Manifests) > 0 {
+			files := make([]string, 0, len(l.Manifests))
+			for f := range l.Manifests {
+				files = append(files, f)
+			}
+			slices.Sort(files)
+			line += " (" + strings.Join(files, ", ") + ")"
+		}
+		lines = append(lines, line)
+	}
+	if len(p.Providers) > 0 {
+		lines = append(lines, "metadata: "+strings.Join(p.Providers, ", "))
+	}
+	if len(p.Sinks) > 0 {
+		lines = append(lines, "formats: "+strings.Join(p.Sinks, ", "))
+	}
+	return lines
+}
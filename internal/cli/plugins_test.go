@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/plugin"
+)
+
+func TestPluginDirs(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/cfg")
+	t.Setenv("PATH", strings.Join([]string{"/usr/bin", "/bin"}, string(filepath.ListSeparator)))
+
+	want := []string{filepath.Join("/cfg", appName, "plugins"), "/usr/bin", "/bin"}
+	if got := pluginDirs(); !slices.Equal(got, want) {
+		t.Errorf("pluginDirs() = %v, want %v", got, want)
+	}
+}
+
+func TestPluginCapabilities(t *testing.T) {
+	p := &plugin.Plugin{Manifest: plugin.Manifest{
+		Name: "demo",
+		Languages: []plugin.Language{{
+			Name:      "cobol",
+			Manifests: map[string]string{"copybook.lock": "copybook-lock", "copybook.toml": "copybook"},
+		}},
+		Providers: []string{"demo-meta"},
+		Sinks:     []string{"txt", "pdf"},
+	}}
+	want := []string{
+		"language cobol (copybook.lock, copybook.toml)",
+		"metadata: demo-meta",
+		"formats: txt, pdf",
+	}
+	if got := pluginCapabilities(p); !slices.Equal(got, want) {
+		t.Errorf("pluginCapabilities() = %q, want %q", got, want)
+	}
+}
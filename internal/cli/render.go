@@ -1,10 +1,13 @@
 package cli
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,78 +16,275 @@ import (
 	"github.com/stacktower-io/stacktower/internal/cli/ui"
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/ordering"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/sink"
 	"github.com/stacktower-io/stacktower/pkg/graph"
 	"github.com/stacktower-io/stacktower/pkg/observability"
 	"github.com/stacktower-io/stacktower/pkg/pipeline"
 )
 
+// renderFlags holds render command options that do not map directly onto
+// pipeline.Options. They are shared by render and tower.
+type renderFlags struct {
+	formatsStr   string
+	output       string
+	noCache      bool
+	orderTimeout int
+	tileSize     string
+	popupTmpl    string
+	themeFile    string
+	scoringFile  string
+	nebraskaBy   string
+	healthFile   string
+	quality      string
+	maxCrossings int
+}
+
 // renderCommand creates the render command for generating visualizations.
 func (c *CLI) renderCommand() *cobra.Command {
 	var (
-		formatsStr   string
-		output       string
-		noCache      bool
-		orderTimeout int
+		flags     renderFlags
+		filter    graphFilter
+		batchFile string
+		bundle    string
+		jobs      int
 	)
 	opts := pipeline.Options{}
 	setCLIDefaults(&opts)
 
 	cmd := &cobra.Command{
-		Use:   "render [graph.json|-]",
+		Use:   "render [graph.json|-]...",
 		Short: "Render a dependency graph to SVG/PNG/PDF (shortcut for layout + visualize)",
 		Long: `Render a dependency graph to visual output.
 
 This command is a shortcut that combines 'layout' and 'visualize' in one step.
 It takes a graph.json file (produced by 'parse') and outputs SVG, PNG, or PDF.
-Use '-' as input to read graph JSON from stdin.
+CycloneDX (JSON/XML) and SPDX (JSON) SBOMs, e.g. from syft or trivy, are
+accepted too and rendered without re-resolving.
+Use '-' as input to read graph JSON or an SBOM from stdin.
 
 Results are cached locally for faster subsequent runs.
 
-If you want to save the intermediate layout, use 'layout' followed by 'visualize'.`,
-		Args: cobra.ExactArgs(1),
+If you want to save the intermediate layout, use 'layout' followed by 'visualize'.
+To resolve and render in one step, use 'tower'.
+
+Several graphs, given as arguments, quoted globs, or listed one per line in
+a --batch file ('-' for stdin), are rendered in parallel with the same
+options. In batch mode -o is a template: {name} is replaced by each input's
+base name and {dir} by its directory, and missing directories are created.
+--bundle also collects each graph with its layout and the render settings
+in one file (a zip archive when it ends in .zip, JSON otherwise).`,
+		Example: `  # Render one graph
+  stacktower render deps.json -o deps.svg
+
+  # Render every graph in a directory into docs/towers
+  stacktower render 'graphs/*.json' -o 'docs/towers/{name}' -f svg,png
+
+  # Render the graphs listed in a file, 8 at a time
+  stacktower render --batch services.txt --jobs 8 -o '{dir}/{name}.svg'
+
+  # Keep the graphs and their layouts together in one bundle
+  stacktower render 'graphs/*.json' --bundle towers.zip`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && batchFile == "" {
+				return NewUserError("missing input graph", "Pass a graph file, '-' for stdin, or --batch FILE.")
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.Formats = parseFormats(formatsStr)
-			if err := pipeline.ValidateFormats(opts.Formats); err != nil {
+			if err := flags.apply(cmd, &opts); err != nil {
 				return err
 			}
-			if err := pipeline.ValidateStyle(opts.Style); err != nil {
+			if err := filter.validate(); err != nil {
 				return err
 			}
-			return c.runRender(cmd.Context(), args[0], opts, output, noCache, orderTimeout)
+			inputs, err := batchInputs(args, batchFile)
+			if err != nil {
+				return err
+			}
+			if len(inputs) == 1 && batchFile == "" && bundle == "" {
+				output := expandOutputTemplate(flags.output, inputs[0])
+				return c.runRender(cmd.Context(), inputs[0], filter, opts, output, flags.noCache, flags.orderTimeout, flags.maxCrossings)
+			}
+			return c.runRenderBatch(cmd.Context(), inputs, filter, opts, flags.output, bundle, flags.noCache, flags.orderTimeout, flags.maxCrossings, jobs)
 		},
 	}
 
+	flags.register(cmd, &opts)
+	filter.register(cmd)
+	cmd.Flags().StringVar(&batchFile, "batch", "", "file listing input graphs, one per line ('-' for stdin)")
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", defaultBatchJobs, "graphs to render in parallel in batch mode")
+	cmd.Flags().StringVar(&bundle, "bundle", "", "also write the graphs, layouts and settings to one bundle file (.json, .json.gz, .json.zst or .zip)")
+
+	return cmd
+}
+
+// register adds the layout, render and security flags to cmd.
+func (f *renderFlags) register(cmd *cobra.Command, opts *pipeline.Options) {
 	// Common flags
-	cmd.Flags().StringVarP(&output, "output", "o", "", "output file (single format) or base path (multiple)")
-	cmd.Flags().BoolVar(&noCache, "no-cache", false, "disable caching")
+	cmd.Flags().StringVarP(&f.output, "output", "o", "", "output file (single format) or base path (multiple)")
+	cmd.Flags().BoolVar(&f.noCache, "no-cache", false, "disable caching")
 
 	// Layout flags
-	cmd.Flags().StringVarP(&opts.VizType, "type", "t", opts.VizType, "visualization type: tower (default), nodelink")
+	cmd.Flags().StringVarP(&opts.VizType, "type", "t", opts.VizType, "visualization type: tower (default), nodelink, sunburst, treemap, dsm")
 	cmd.Flags().BoolVar(&opts.Normalize, "normalize", opts.Normalize, "apply graph normalization")
+	cmd.Flags().BoolVar(&opts.FixedRows, "fixed-rows", opts.FixedRows, "keep the rows set in the input graph when normalizing, failing if they conflict (tower)")
 	cmd.Flags().Float64Var(&opts.Width, "width", opts.Width, "frame width")
 	cmd.Flags().Float64Var(&opts.Height, "height", opts.Height, "frame height")
 	cmd.Flags().StringVar(&opts.Ordering, "ordering", opts.Ordering, "ordering algorithm: optimal (default), barycentric")
+	cmd.Flags().StringVar(&opts.ClusterBy, "cluster-by", opts.ClusterBy, "group nodes into boxes by metadata: owner, language, or any key such as workspace (nodelink)")
+	cmd.Flags().BoolVar(&opts.EdgeLabels, "edge-labels", opts.EdgeLabels, "label edges with their version constraints (nodelink)")
+	cmd.Flags().BoolVar(&opts.EdgeKinds, "edge-kinds", opts.EdgeKinds, "draw dev, optional and peer dependency edges dashed or dotted (nodelink)")
 	cmd.Flags().BoolVar(&opts.Randomize, "randomize", opts.Randomize, "randomize block widths (tower)")
+	cmd.Flags().Uint64Var(&opts.Seed, "seed", opts.Seed, "seed for ordering restarts, --randomize and the handdrawn style; equal seeds give identical output")
+	cmd.Flags().IntVar(&opts.Restarts, "restarts", opts.Restarts, "extra barycentric ordering runs from seeded shuffled orders (tower)")
 	cmd.Flags().BoolVar(&opts.Merge, "merge", opts.Merge, "merge subdivider blocks (tower)")
 	cmd.Flags().BoolVar(&opts.Nebraska, "nebraska", opts.Nebraska, "show Nebraska maintainer ranking (tower)")
-	cmd.Flags().IntVar(&orderTimeout, "ordering-timeout", defaultOrderTimeout, "timeout in seconds for optimal ordering search")
+	cmd.Flags().StringVar(&f.scoringFile, "nebraska-scoring", "", "YAML/JSON file with Nebraska role weights, depth curve, star damping, and org filters")
+	cmd.Flags().StringVar(&f.nebraskaBy, "nebraska-by", "", "rank Nebraska by maintainer (default) or org")
+	cmd.Flags().StringVar(&f.healthFile, "health-weights", "", "YAML/JSON file weighting the health score: stars, maintainers, cadence, vulns, scorecard")
+	cmd.Flags().IntVar(&f.orderTimeout, "ordering-timeout", defaultOrderTimeout, "timeout in seconds for optimal ordering search")
+	cmd.Flags().StringVar(&f.quality, "quality", "", qualityUsage)
+	cmd.Flags().IntVar(&f.maxCrossings, "max-crossings", -1, "exit with code 6 when the layout keeps more edge crossings than this (-1 = no limit)")
 
 	// Render flags
-	cmd.Flags().StringVar(&opts.Style, "style", opts.Style, "visual style: handdrawn (default), simple")
+	cmd.Flags().StringVar(&opts.Style, "style", opts.Style, styleUsage())
 	cmd.Flags().BoolVar(&opts.ShowEdges, "edges", opts.ShowEdges, "show dependency edges (tower)")
+	cmd.Flags().StringVar(&opts.EdgeRouting, "edge-routing", opts.EdgeRouting, "edge routing: straight (default), orthogonal, curved (tower)")
+	cmd.Flags().StringVar(&opts.Engine, "engine", opts.Engine, "layout engine: dot (default, Graphviz) or layered (pure Go, no Graphviz) (nodelink)")
+	cmd.Flags().IntVar(&opts.Rings, "rings", opts.Rings, "dependency levels drawn around the center, 0 for all (sunburst)")
+	cmd.Flags().StringVar(&opts.Weight, "weight", opts.Weight, "what area encodes: deps (default), downloads, size (treemap)")
+	cmd.Flags().IntVar(&opts.EdgeBundling, "edge-bundle", opts.EdgeBundling, "bundle edges of packages with at least N dependencies (tower, 0 = off)")
+	cmd.Flags().StringVar(&opts.ColorBy, "color-by", opts.ColorBy, "fill blocks by metadata: license, language, owner, vuln, staleness, freshness, health (nodelink: health only)")
+	cmd.Flags().IntVar(&opts.StaleAfterDays, "stale-after", opts.StaleAfterDays, "days without commits before a small package counts as brittle (default 365)")
+	cmd.Flags().IntVar(&opts.AbandonedAfterDays, "abandoned-after", opts.AbandonedAfterDays, "days without commits before any package counts as brittle (default 730)")
+	cmd.Flags().StringVar(&opts.Palette, "palette", opts.Palette, "colours for --color-by and isometric: okabe-ito, viridis, pastel, or #hex,#hex,...")
 	cmd.Flags().BoolVar(&opts.Popups, "popups", opts.Popups, "show hover popups with metadata")
-	cmd.Flags().StringVarP(&formatsStr, "format", "f", "", "output format(s): svg (default), pdf, png (comma-separated)")
+	cmd.Flags().BoolVar(&opts.Icons, "icons", opts.Icons, "draw package icons on blocks (tower; needs a graph parsed with --icons)")
+	cmd.Flags().StringSliceVar(&opts.PopupFields, "popup-field", opts.PopupFields, "extra metadata keys to show in popups (repeatable)")
+	cmd.Flags().StringVar(&f.popupTmpl, "popup-template", "", "file with a Go text/template for the popup body")
+	cmd.Flags().StringVar(&f.themeFile, "theme-file", "", "stacktower-theme.yaml/.json defining colors, fonts, strokes, and textures (overrides --style)")
+	cmd.Flags().StringSliceVar(&opts.Highlight, "highlight", opts.Highlight, "emphasize these packages and dim the rest (tower, comma-separated)")
+	cmd.Flags().BoolVar(&opts.Footer, "footer", opts.Footer, "stamp a provenance footer: project, resolve time, version, package counts")
+	cmd.Flags().StringVar(&opts.FooterNote, "footer-note", opts.FooterNote, "extra text for the footer, e.g. a commit SHA")
+	cmd.Flags().StringVar(&opts.Branding, "branding", opts.Branding, "replace the stacktower.io watermark with custom text")
+	cmd.Flags().StringVarP(&f.formatsStr, "format", "f", "", "output format(s): svg (default), pdf, png, pptx, html (tower, nodelink) (comma-separated)")
+	cmd.Flags().StringVar(&f.tileSize, "tile", "", "split large towers into WxH pages, e.g. 1200x900 (tower; svg tiles, multi-page pdf)")
 
 	// Security flags
 	cmd.Flags().BoolVar(&opts.ShowVulns, "show-vulns", opts.ShowVulns, "show vulnerability severity colours (requires scanned graph)")
 	cmd.Flags().BoolVar(&opts.ShowLicenses, "show-licenses", opts.ShowLicenses, "show license compliance indicators (copyleft/unknown borders)")
+	cmd.Flags().BoolVar(&opts.Suspicious, "suspicious", opts.Suspicious, "mark likely typosquats, very young packages and repo/name mismatches")
 	cmd.Flags().BoolVar(&opts.FlagsOnTop, "flags-on-top", opts.FlagsOnTop, "render security flags on top of all blocks")
+}
 
-	return cmd
+// apply validates the flags and loads the files they name into opts.
+func (f *renderFlags) apply(cmd *cobra.Command, opts *pipeline.Options) error {
+	if err := applyQuality(cmd, f.quality, &opts.Ordering, &f.orderTimeout); err != nil {
+		return err
+	}
+	opts.Formats = parseFormats(f.formatsStr)
+	if err := pipeline.ValidateFormats(opts.Formats); err != nil {
+		return err
+	}
+	if err := pipeline.ValidateStyle(opts.Style); err != nil {
+		return err
+	}
+	if err := pipeline.ValidateEdgeRouting(opts.EdgeRouting); err != nil {
+		return err
+	}
+	if err := pipeline.ValidateEngine(opts.Engine); err != nil {
+		return err
+	}
+	if err := pipeline.ValidateWeight(opts.Weight); err != nil {
+		return err
+	}
+	if err := pipeline.ValidateColorBy(opts.ColorBy); err != nil {
+		return err
+	}
+	if err := pipeline.ValidatePalette(opts.Palette); err != nil {
+		return err
+	}
+	tmpl, err := loadPopupTemplate(f.popupTmpl)
+	if err != nil {
+		return err
+	}
+	opts.PopupTemplate = tmpl
+	theme, err := loadThemeFile(f.themeFile)
+	if err != nil {
+		return err
+	}
+	opts.Theme = theme
+	scoring, err := loadNebraskaScoring(f.scoringFile, f.nebraskaBy)
+	if err != nil {
+		return err
+	}
+	opts.NebraskaScoring = scoring
+	health, err := loadHealthConfig(f.healthFile)
+	if err != nil {
+		return err
+	}
+	opts.Health = health
+	if f.tileSize != "" {
+		w, h, err := parseTileSize(f.tileSize)
+		if err != nil {
+			return err
+		}
+		opts.TileWidth, opts.TileHeight = w, h
+	}
+	return nil
+}
+
+// qualityPresets trade layout quality for speed by picking the ordering
+// algorithm and how long the optimal search may run, in seconds.
+var qualityPresets = map[string]struct {
+	ordering string
+	timeout  int
+}{
+	"fast":     {"barycentric", defaultOrderTimeout},
+	"balanced": {"optimal", 10},
+	"best":     {"optimal", 300},
 }
 
-// runRender loads the graph and renders via pipeline.
-func (c *CLI) runRender(ctx context.Context, input string, opts pipeline.Options, output string, noCache bool, orderTimeout int) error {
+const qualityUsage = "layout quality preset: fast (barycentric), balanced (10s optimal search), best (300s optimal search)"
+
+// applyQuality sets ordering and timeout from a --quality preset. Explicit
+// --ordering and --ordering-timeout flags win.
+func applyQuality(cmd *cobra.Command, quality string, ordering *string, timeout *int) error {
+	if quality == "" {
+		return nil
+	}
+	preset, ok := qualityPresets[quality]
+	if !ok {
+		return NewUserError(
+			fmt.Sprintf("invalid quality %q", quality),
+			"Use fast, balanced, or best.",
+		)
+	}
+	if !cmd.Flags().Changed("ordering") {
+		*ordering = preset.ordering
+	}
+	if !cmd.Flags().Changed("ordering-timeout") {
+		*timeout = preset.timeout
+	}
+	return nil
+}
+
+// parseTileSize parses a "WIDTHxHEIGHT" page size such as "1200x900".
+func parseTileSize(s string) (float64, float64, error) {
+	ws, hs, ok := strings.Cut(strings.ToLower(s), "x")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid tile size %q (expected WIDTHxHEIGHT, e.g. 1200x900)", s)
+	}
+	w, errW := strconv.ParseFloat(strings.TrimSpace(ws), 64)
+	h, errH := strconv.ParseFloat(strings.TrimSpace(hs), 64)
+	if errW != nil || errH != nil || w <= 0 || h <= 0 {
+		return 0, 0, fmt.Errorf("invalid tile size %q (expected positive WIDTHxHEIGHT, e.g. 1200x900)", s)
+	}
+	return w, h, nil
+}
+
+// runRender loads the graph, applies filter and renders via pipeline.
+func (c *CLI) runRender(ctx context.Context, input string, filter graphFilter, opts pipeline.Options, output string, noCache bool, orderTimeout, maxCrossings int) error {
 	start := time.Now()
 
 	g, err := readRenderInput(input)
@@ -94,7 +294,17 @@ func (c *CLI) runRender(ctx context.Context, input string, opts pipeline.Options
 		}
 		return WrapSystemError(err, fmt.Sprintf("failed to load graph %s", input), "Check that the file exists and is valid JSON.")
 	}
+	if g, err = filter.apply(g); err != nil {
+		return err
+	}
+
+	return c.renderGraph(ctx, g, input, opts, output, noCache, orderTimeout, maxCrossings, start)
+}
 
+// renderGraph lays out and renders g, then writes the artifacts next to
+// input (or to output) and prints a summary timed from start. The files
+// are written even when the layout exceeds maxCrossings.
+func (c *CLI) renderGraph(ctx context.Context, g *dag.DAG, input string, opts pipeline.Options, output string, noCache bool, orderTimeout, maxCrossings int, start time.Time) error {
 	// Check if Nebraska rankings are requested but contributor data is missing
 	if opts.Nebraska && !dagHasContributorData(g) {
 		ui.PrintWarning("Graph has no contributor data. Nebraska rankings will be limited.")
@@ -107,47 +317,110 @@ func (c *CLI) runRender(ctx context.Context, input string, opts pipeline.Options
 	}
 	defer runner.Close()
 
-	opts.Logger = c.Logger
+	opts.Logger = structuredLogger(c.Logger)
 
 	spinner := ui.NewSpinnerWithContext(ctx, fmt.Sprintf("Rendering %s...", opts.VizType))
+	spinner.Start()
+
+	res, err := c.layoutAndRender(ctx, runner, g, opts, orderTimeout, spinner)
+	if err != nil {
+		return err
+	}
+	spinner.Stop()
+
+	err = writeArtifacts(artifactWriteParams{
+		artifacts:   res.artifacts,
+		formats:     opts.Formats,
+		input:       input,
+		output:      output,
+		nodeCount:   g.NodeCount(),
+		edgeCount:   g.EdgeCount(),
+		tiles:       res.tiles,
+		cacheHit:    res.cacheHit,
+		elapsed:     time.Since(start),
+		renderStats: res.stats,
+	})
+	if err != nil {
+		return err
+	}
+	return checkCrossings(res.stats.Crossings, maxCrossings)
+}
+
+// checkCrossings returns a CrossingsError when a limit is set (not
+// negative) and the layout has more crossings than it allows.
+func checkCrossings(crossings, maxCrossings int) error {
+	if maxCrossings >= 0 && crossings > maxCrossings {
+		return &CrossingsError{Crossings: crossings, Max: maxCrossings}
+	}
+	return nil
+}
+
+// renderResult holds the output of laying out and rendering one graph.
+type renderResult struct {
+	layout    graph.Layout
+	artifacts map[string][]byte
+	tiles     []sink.Tile
+	cacheHit  bool
+	stats     ui.RenderStats
+}
+
+// layoutAndRender normalizes, lays out and renders g. Progress is reported
+// on spinner, which may be nil; on failure the spinner is stopped.
+func (c *CLI) layoutAndRender(ctx context.Context, runner *pipeline.Runner, g *dag.DAG, opts pipeline.Options, orderTimeout int, spinner *ui.Spinner) (*renderResult, error) {
+	update := func(msg string) {
+		if spinner != nil {
+			spinner.UpdateMessage(msg)
+		}
+	}
+	fail := func(msg string, err error) error {
+		if spinner != nil {
+			spinner.StopWithError(msg)
+		}
+		return err
+	}
 
 	var orderer *optimalOrderer
 	if opts.NeedsOptimalOrderer() {
-		orderer = c.newOptimalOrderer(orderTimeout).(*optimalOrderer)
+		orderer = c.newOptimalOrderer(orderTimeout, opts).(*optimalOrderer)
 		orderer.spinner = spinner // Wire spinner for live updates
 		opts.Orderer = orderer
 	}
 
-	spinner.Start()
-	spinner.UpdateMessage("Normalizing graph...")
+	update("Normalizing graph...")
 
 	workGraph, err := runner.PrepareGraph(g, opts)
 	if err != nil {
-		spinner.StopWithError("Normalization failed")
-		return WrapSystemError(err, "graph normalization failed", "The dependency graph may contain invalid structure.")
+		return nil, fail("Normalization failed", WrapSystemError(err, "graph normalization failed", "The dependency graph may contain invalid structure."))
 	}
 
-	spinner.UpdateMessage(fmt.Sprintf("Computing layout (%d nodes)...", workGraph.NodeCount()))
+	update(fmt.Sprintf("Computing layout (%d nodes)...", workGraph.NodeCount()))
 
 	layout, layoutHit, err := runner.GenerateLayoutWithCacheInfo(ctx, workGraph, opts)
 	if err != nil {
-		spinner.StopWithError("Render failed")
-		return WrapSystemError(err, "layout computation failed", "Try reducing max-nodes or simplifying the graph.")
+		return nil, fail("Render failed", WrapSystemError(err, "layout computation failed", "Try reducing max-nodes or simplifying the graph."))
 	}
 
 	if ctx.Err() != nil {
-		spinner.Stop()
-		return ctx.Err()
+		if spinner != nil {
+			spinner.Stop()
+		}
+		return nil, ctx.Err()
 	}
 
-	spinner.UpdateMessage(fmt.Sprintf("Rendering %s...", strings.Join(opts.Formats, ", ")))
+	update(fmt.Sprintf("Rendering %s...", strings.Join(opts.Formats, ", ")))
 
 	artifacts, renderHit, err := runner.RenderWithCacheInfo(ctx, layout, workGraph, opts)
 	if err != nil {
-		spinner.StopWithError("Render failed")
-		return WrapSystemError(err, "rendering failed", "Check the output format and try again.")
+		return nil, fail("Render failed", WrapSystemError(err, "rendering failed", "Check the output format and try again."))
+	}
+
+	var tiles []sink.Tile
+	if opts.IsTiled() && slices.Contains(opts.Formats, pipeline.FormatSVG) {
+		_, tiles, err = pipeline.RenderTiles(layout, workGraph, opts)
+		if err != nil {
+			return nil, fail("Render failed", WrapSystemError(err, "tiled rendering failed", "Check the tile size and try again."))
+		}
 	}
-	spinner.Stop()
 
 	// Get crossings from orderer (computed during layout) or fallback to layout-based count
 	var crossings int
@@ -165,22 +438,18 @@ func (c *CLI) runRender(ctx context.Context, input string, opts pipeline.Options
 		style = "handdrawn"
 	}
 
-	return writeArtifacts(artifactWriteParams{
+	return &renderResult{
+		layout:    layout,
 		artifacts: artifacts,
-		formats:   opts.Formats,
-		input:     input,
-		output:    output,
-		nodeCount: g.NodeCount(),
-		edgeCount: g.EdgeCount(),
+		tiles:     tiles,
 		cacheHit:  layoutHit && renderHit,
-		elapsed:   time.Since(start),
-		renderStats: ui.RenderStats{
+		stats: ui.RenderStats{
 			Layers:    len(layout.Rows),
 			Crossings: crossings,
 			Ordering:  orderingName,
 			Style:     style,
 		},
-	})
+	}, nil
 }
 
 // =============================================================================
@@ -197,13 +466,17 @@ type optimalOrderer struct {
 	rowCount  int         // Number of rows being ordered
 }
 
-// newOptimalOrderer creates an optimal orderer with a timeout.
-func (c *CLI) newOptimalOrderer(timeoutSec int) ordering.Orderer {
+// newOptimalOrderer creates an optimal orderer with a timeout, seeded
+// like the rest of the pipeline from opts.
+func (c *CLI) newOptimalOrderer(timeoutSec int, opts pipeline.Options) ordering.Orderer {
 	o := &optimalOrderer{cli: c}
 	o.OptimalSearch = ordering.OptimalSearch{
 		Timeout:  time.Duration(timeoutSec) * time.Second,
+		Restarts: opts.Restarts,
+		Seed:     cmp.Or(opts.Seed, pipeline.DefaultSeed),
 		Progress: o.onProgress,
 		Debug:    o.onDebug,
+		Logger:   structuredLogger(c.Logger),
 	}
 	return o
 }
@@ -229,17 +502,23 @@ func (o *optimalOrderer) onDebug(info ordering.DebugInfo) {
 
 // OrderRows implements ordering.Orderer.
 func (o *optimalOrderer) OrderRows(g *dag.DAG) map[int][]string {
+	return o.OrderRowsContext(context.Background(), g)
+}
+
+// OrderRowsContext implements ordering.ContextOrderer, so Ctrl-C stops the
+// search with the best ordering found so far.
+func (o *optimalOrderer) OrderRowsContext(ctx context.Context, g *dag.DAG) map[int][]string {
 	o.startTime = time.Now()
 	o.rowCount = g.RowCount()
 
 	// Emit start hook
-	observability.Pipeline().OnOrderingStart(context.Background(), "optimal", o.rowCount)
+	observability.Pipeline().OnOrderingStart(ctx, "optimal", o.rowCount)
 
-	result := o.OptimalSearch.OrderRows(g)
+	result := o.OptimalSearch.OrderRowsContext(ctx, g)
 	o.crossings = dag.CountCrossings(g, result)
 
 	// Emit complete hook
-	observability.Pipeline().OnOrderingComplete(context.Background(), o.crossings, time.Since(o.startTime))
+	observability.Pipeline().OnOrderingComplete(ctx, o.crossings, time.Since(o.startTime))
 
 	o.cli.Logger.Debug("ordering result", "crossings", o.crossings)
 
@@ -258,6 +537,7 @@ type artifactWriteParams struct {
 	output      string
 	nodeCount   int
 	edgeCount   int
+	tiles       []sink.Tile // Optional per-page SVGs written next to the main output
 	cacheHit    bool
 	elapsed     time.Duration
 	renderStats ui.RenderStats
@@ -265,13 +545,38 @@ type artifactWriteParams struct {
 
 // writeArtifacts writes rendered artifacts to files and prints a summary.
 func writeArtifacts(p artifactWriteParams) error {
+	paths, err := writeArtifactFiles(p)
+	if err != nil {
+		return err
+	}
+
+	if p.renderStats.Crossings == 0 {
+		ui.PrintSuccess("Render complete (optimal layout)")
+	} else {
+		ui.PrintInfo("Render complete (%d crossings remaining)", p.renderStats.Crossings)
+	}
+	for _, path := range paths {
+		ui.PrintFile(path)
+	}
+	ui.PrintStats(p.nodeCount, p.edgeCount, 0, p.cacheHit, p.elapsed)
+	ui.PrintRenderStats(p.renderStats)
+	if len(paths) == 1 && (strings.HasSuffix(paths[0], ".svg") || strings.HasSuffix(paths[0], ".html")) {
+		ui.PrintNewline()
+		ui.PrintNextStep("Open", "open "+paths[0])
+	}
+	return nil
+}
+
+// writeArtifactFiles writes rendered artifacts and tiles to files and
+// returns their paths.
+func writeArtifactFiles(p artifactWriteParams) ([]string, error) {
 	base := deriveBasePath(p.input, p.output)
 	var paths []string
 
 	for _, format := range p.formats {
 		data, ok := p.artifacts[format]
 		if !ok {
-			return NewSystemError(
+			return nil, NewSystemError(
 				fmt.Sprintf("missing artifact for format: %s", format),
 				"This is an internal error. Please report this issue.",
 			)
@@ -283,26 +588,19 @@ func writeArtifacts(p artifactWriteParams) error {
 		}
 
 		if err := writeFile(data, path); err != nil {
-			return err
+			return nil, err
 		}
 		paths = append(paths, path)
 	}
 
-	if p.renderStats.Crossings == 0 {
-		ui.PrintSuccess("Render complete (optimal layout)")
-	} else {
-		ui.PrintInfo("Render complete (%d crossings remaining)", p.renderStats.Crossings)
-	}
-	for _, path := range paths {
-		ui.PrintFile(path)
-	}
-	ui.PrintStats(p.nodeCount, p.edgeCount, 0, p.cacheHit, p.elapsed)
-	ui.PrintRenderStats(p.renderStats)
-	if len(paths) == 1 && strings.HasSuffix(paths[0], ".svg") {
-		ui.PrintNewline()
-		ui.PrintNextStep("Open", "open "+paths[0])
+	for i, t := range p.tiles {
+		path := fmt.Sprintf("%s.tile-%02d.svg", base, i+1)
+		if err := writeFile(t.SVG, path); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
 	}
-	return nil
+	return paths, nil
 }
 
 func readRenderInput(input string) (*dag.DAG, error) {
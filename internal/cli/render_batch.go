@@ -0,0 +1,305 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/stacktower-io/stacktower/internal/cli/ui"
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+)
+
+// defaultBatchJobs is how many graphs batch rendering works on at once.
+const defaultBatchJobs = 4
+
+// batchInputs expands the render arguments and the graphs listed in
+// batchFile into a list of inputs. Arguments containing glob characters
+// are matched against the filesystem, so quoted patterns work too.
+func batchInputs(args []string, batchFile string) ([]string, error) {
+	var inputs []string
+	seen := map[string]bool{}
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			inputs = append(inputs, path)
+		}
+	}
+
+	patterns := args
+	if batchFile != "" {
+		listed, err := readBatchFile(batchFile)
+		if err != nil {
+			return nil, WrapUserError(err, "failed to read batch file "+batchFile, "List one graph file per line.")
+		}
+		patterns = append(slices.Clone(args), listed...)
+	}
+
+	for _, p := range patterns {
+		if !strings.ContainsAny(p, "*?[") || fileExists(p) {
+			add(p)
+			continue
+		}
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return nil, WrapUserError(err, fmt.Sprintf("invalid pattern %q", p), "Check the glob syntax.")
+		}
+		if len(matches) == 0 {
+			return nil, NewUserError(fmt.Sprintf("no files match %q", p), "Check the pattern and the working directory.")
+		}
+		for _, m := range matches {
+			add(m)
+		}
+	}
+
+	if len(inputs) > 1 && seen["-"] {
+		return nil, NewUserError("stdin ('-') cannot be rendered in a batch", "Save the graph to a file first.")
+	}
+	if len(inputs) == 0 {
+		return nil, NewUserError("no input graphs", "The batch file lists no graphs.")
+	}
+	return inputs, nil
+}
+
+// readBatchFile returns the non-empty lines of path (or stdin for "-"),
+// skipping comments that start with '#'.
+func readBatchFile(path string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var lines []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, sc.Err()
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// expandOutputTemplate fills the {name} and {dir} placeholders of an
+// output template from input: "docs/{name}.svg" becomes "docs/api.svg"
+// for graphs/api.json.
+func expandOutputTemplate(output, input string) string {
+	if output == "" {
+		return ""
+	}
+	base := deriveBasePath(input, "")
+	return strings.NewReplacer(
+		"{name}", filepath.Base(base),
+		"{dir}", filepath.Dir(input),
+	).Replace(output)
+}
+
+// batchResult is the outcome of rendering one input of a batch.
+type batchResult struct {
+	paths     []string
+	nodes     int
+	crossings int
+	graph     *dag.DAG     // Filtered input graph, kept for the bundle
+	layout    graph.Layout // Layout of graph, kept for the bundle
+	err       error
+}
+
+// runRenderBatch renders inputs with shared options, jobs at a time, and
+// prints one line per input in the order given. Failed inputs do not stop
+// the others. When bundle is set, the rendered graphs are also written
+// there with their layouts and the options. When none failed, inputs over
+// maxCrossings make it return a CrossingsError for the worst of them.
+func (c *CLI) runRenderBatch(ctx context.Context, inputs []string, filter graphFilter, opts pipeline.Options, output, bundle string, noCache bool, orderTimeout, maxCrossings, jobs int) error {
+	start := time.Now()
+
+	if output != "" && !strings.Contains(output, "{name}") {
+		return NewUserError(
+			"batch output must contain {name}",
+			"Each graph needs its own file, e.g. -o 'docs/{name}.svg'.",
+		)
+	}
+	if jobs < 1 {
+		return NewUserError(fmt.Sprintf("invalid --jobs %d", jobs), "Use at least 1.")
+	}
+	if output != "" {
+		taken := map[string]string{}
+		for _, in := range inputs {
+			out := expandOutputTemplate(output, in)
+			if prev, ok := taken[out]; ok {
+				return NewUserError(
+					fmt.Sprintf("%s and %s would both be written to %s", prev, in, out),
+					"Add {dir} to the output template to keep them apart.",
+				)
+			}
+			taken[out] = in
+		}
+	}
+	if bundle != "" {
+		taken := map[string]string{}
+		for _, in := range inputs {
+			name := bundleEntryName(in)
+			if prev, ok := taken[name]; ok {
+				return NewUserError(
+					fmt.Sprintf("%s and %s would both be bundled as %q", prev, in, name),
+					"Rename one of them; bundle entries are named after the input's base name.",
+				)
+			}
+			taken[name] = in
+		}
+	}
+
+	runner, err := c.newRunner(noCache, false)
+	if err != nil {
+		return WrapSystemError(err, "failed to initialize runner", "This may be a cache or configuration issue.")
+	}
+	defer runner.Close()
+
+	opts.Logger = structuredLogger(c.Logger)
+
+	spinner := ui.NewSpinnerWithContext(ctx, fmt.Sprintf("Rendering %d graphs...", len(inputs)))
+	spinner.Start()
+
+	results := make([]batchResult, len(inputs))
+	var done atomic.Int32
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(jobs, len(inputs)) {
+		wg.Go(func() {
+			for i := range next {
+				results[i] = c.renderBatchInput(ctx, runner, inputs[i], filter, opts, output, orderTimeout)
+				spinner.UpdateMessage(fmt.Sprintf("Rendered %d/%d graphs...", done.Add(1), len(inputs)))
+			}
+		})
+	}
+feed:
+	for i := range inputs {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+	spinner.Stop()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	failed, worst := 0, 0
+	for i, r := range results {
+		if r.err != nil {
+			failed++
+			ui.PrintError("%s: %v", inputs[i], r.err)
+			continue
+		}
+		if checkCrossings(r.crossings, maxCrossings) != nil {
+			worst = max(worst, r.crossings)
+		}
+		ui.PrintSuccess("%s (%d nodes, %d crossings)", inputs[i], r.nodes, r.crossings)
+		for _, path := range r.paths {
+			ui.PrintFile(path)
+		}
+	}
+	if bundle != "" && failed < len(inputs) {
+		if err := writeRenderBundle(bundle, inputs, results, opts); err != nil {
+			return WrapSystemError(err, "failed to write bundle "+bundle, "Check the path and its extension.")
+		}
+		ui.PrintFile(bundle)
+	}
+	ui.PrintNewline()
+	ui.PrintInfo("Rendered %d of %d graphs in %s", len(inputs)-failed, len(inputs), ui.FormatDuration(time.Since(start)))
+
+	if failed > 0 {
+		return NewSystemError(
+			fmt.Sprintf("%d of %d graphs failed to render", failed, len(inputs)),
+			"See the errors above; the other graphs were rendered.",
+		)
+	}
+	return checkCrossings(worst, maxCrossings)
+}
+
+// renderBatchInput loads, filters, renders and writes one graph of a batch.
+func (c *CLI) renderBatchInput(ctx context.Context, runner *pipeline.Runner, input string, filter graphFilter, opts pipeline.Options, output string, orderTimeout int) batchResult {
+	g, err := readRenderInput(input)
+	if err != nil {
+		return batchResult{err: fmt.Errorf("load graph: %w", err)}
+	}
+	if g, err = filter.apply(g); err != nil {
+		return batchResult{err: err}
+	}
+
+	res, err := c.layoutAndRender(ctx, runner, g, opts, orderTimeout, nil)
+	if err != nil {
+		return batchResult{err: err}
+	}
+
+	output = expandOutputTemplate(output, input)
+	if output != "" {
+		// A template without an extension names the base, as with several formats.
+		if !pipeline.ValidFormats[strings.TrimPrefix(filepath.Ext(output), ".")] && len(opts.Formats) == 1 {
+			output += "." + opts.Formats[0]
+		}
+		if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+			return batchResult{err: err}
+		}
+	}
+	paths, err := writeArtifactFiles(artifactWriteParams{
+		artifacts: res.artifacts,
+		formats:   opts.Formats,
+		input:     input,
+		output:    output,
+		tiles:     res.tiles,
+	})
+	if err != nil {
+		return batchResult{err: err}
+	}
+	return batchResult{paths: paths, nodes: g.NodeCount(), crossings: res.stats.Crossings, graph: g, layout: res.layout}
+}
+
+// bundleEntryName names the bundle entry of input after its base name:
+// "api" for graphs/api.json.
+func bundleEntryName(input string) string {
+	return filepath.Base(deriveBasePath(graph.TrimCompressionExt(input), ""))
+}
+
+// writeRenderBundle writes the graphs of a batch that rendered to path,
+// each with its layout and opts as the settings.
+func writeRenderBundle(path string, inputs []string, results []batchResult, opts pipeline.Options) error {
+	settings, err := json.Marshal(opts)
+	if err != nil {
+		return fmt.Errorf("encode settings: %w", err)
+	}
+	var b graph.Bundle
+	for i, r := range results {
+		if r.err != nil {
+			continue
+		}
+		entry := b.Add(bundleEntryName(inputs[i]), r.graph)
+		entry.Layout = &r.layout
+		entry.Settings = settings
+	}
+	return graph.WriteBundleFile(&b, path)
+}
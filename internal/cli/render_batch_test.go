@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/graph"
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+)
+
+func TestBatchInputs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.json", "b.json", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list := filepath.Join(dir, "inputs.txt")
+	data := "# services\n" + filepath.Join(dir, "c.txt") + "\n\n" + filepath.Join(dir, "a.json") + "\n"
+	if err := os.WriteFile(list, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := batchInputs([]string{filepath.Join(dir, "*.json")}, list)
+	if err != nil {
+		t.Fatalf("batchInputs() error = %v", err)
+	}
+	want := []string{filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json"), filepath.Join(dir, "c.txt")}
+	if !slices.Equal(got, want) {
+		t.Errorf("batchInputs() = %v, want %v", got, want)
+	}
+
+	if _, err := batchInputs([]string{filepath.Join(dir, "*.yaml")}, ""); ExitCodeForError(err) != ExitCodeUsage {
+		t.Errorf("unmatched glob error = %v, want a usage error", err)
+	}
+	if _, err := batchInputs([]string{"-", filepath.Join(dir, "a.json")}, ""); ExitCodeForError(err) != ExitCodeUsage {
+		t.Errorf("stdin in a batch error = %v, want a usage error", err)
+	}
+}
+
+func TestExpandOutputTemplate(t *testing.T) {
+	tests := []struct {
+		output, input, want string
+	}{
+		{"", "graphs/api.json", ""},
+		{"out.svg", "graphs/api.json", "out.svg"},
+		{"docs/{name}.svg", "graphs/api.json", "docs/api.svg"},
+		{"{dir}/{name}", "graphs/api.layout.json", "graphs/api"},
+	}
+	for _, tt := range tests {
+		if got := expandOutputTemplate(tt.output, tt.input); got != tt.want {
+			t.Errorf("expandOutputTemplate(%q, %q) = %q, want %q", tt.output, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRunRenderBatch(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	graph := `{"nodes":[{"id":"app"},{"id":"dep"}],"edges":[{"from":"app","to":"dep"}]}`
+	var inputs []string
+	for _, name := range []string{"api", "web"} {
+		path := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(path, []byte(graph), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, path)
+	}
+
+	c := New(os.Stderr, LogInfo)
+	opts := pipeline.Options{}
+	setCLIDefaults(&opts)
+	opts.Ordering = "barycentric"
+	opts.Formats = []string{pipeline.FormatSVG}
+	output := filepath.Join(dir, "out", "{name}")
+
+	if err := c.runRenderBatch(context.Background(), inputs, graphFilter{}, opts, output, "", true, 1, -1, 2); err != nil {
+		t.Fatalf("runRenderBatch() error = %v", err)
+	}
+	for _, name := range []string{"api.svg", "web.svg"} {
+		if _, err := os.Stat(filepath.Join(dir, "out", name)); err != nil {
+			t.Errorf("missing %s: %v", name, err)
+		}
+	}
+
+	if err := c.runRenderBatch(context.Background(), inputs, graphFilter{}, opts, filepath.Join(dir, "out.svg"), "", true, 1, -1, 2); ExitCodeForError(err) != ExitCodeUsage {
+		t.Errorf("template without {name} error = %v, want a usage error", err)
+	}
+
+	missing := append(slices.Clone(inputs), filepath.Join(dir, "missing.json"))
+	if err := c.runRenderBatch(context.Background(), missing, graphFilter{}, opts, "", "", true, 1, -1, 2); err == nil {
+		t.Error("expected an error for a missing input")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "api.svg")); err != nil {
+		t.Errorf("other inputs should still render: %v", err)
+	}
+}
+
+func TestRunRenderBatch_Bundle(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	doc := `{"nodes":[{"id":"app"},{"id":"dep"}],"edges":[{"from":"app","to":"dep"}]}`
+	var inputs []string
+	for _, name := range []string{"api.json", "web.json.gz"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, path)
+	}
+
+	c := New(os.Stderr, LogInfo)
+	opts := pipeline.Options{}
+	setCLIDefaults(&opts)
+	opts.Ordering = "barycentric"
+	opts.Formats = []string{pipeline.FormatSVG}
+	bundle := filepath.Join(dir, "towers.zip")
+
+	if err := c.runRenderBatch(context.Background(), inputs, graphFilter{}, opts, "", bundle, true, 1, -1, 2); err != nil {
+		t.Fatalf("runRenderBatch() error = %v", err)
+	}
+	b, err := graph.ReadBundleFile(bundle)
+	if err != nil {
+		t.Fatalf("ReadBundleFile() error = %v", err)
+	}
+	for _, name := range []string{"api", "web"} {
+		e, ok := b.Entry(name)
+		if !ok {
+			t.Fatalf("bundle has no entry %q", name)
+		}
+		if e.Graph.NodeCount() != 2 || e.Layout == nil || len(e.Layout.Blocks) == 0 {
+			t.Errorf("entry %s: %d nodes, layout %v", name, e.Graph.NodeCount(), e.Layout)
+		}
+		var settings pipeline.Options
+		if err := json.Unmarshal(e.Settings, &settings); err != nil || settings.Ordering != "barycentric" {
+			t.Errorf("entry %s settings = %s (%v)", name, e.Settings, err)
+		}
+	}
+
+	same := []string{inputs[0], filepath.Join(dir, "sub", "api.json")}
+	if err := c.runRenderBatch(context.Background(), same, graphFilter{}, opts, "", bundle, true, 1, -1, 2); ExitCodeForError(err) != ExitCodeUsage {
+		t.Errorf("clashing entry names error = %v, want a usage error", err)
+	}
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+)
+
+// graphFilter holds the render flags that trim or group the graph before
+// layout, so users need not pre-process the JSON themselves.
+type graphFilter struct {
+	onlySubtree []string
+	prune       []string
+	maxDepth    int
+	collapse    []string
+}
+
+// register adds the filter flags to cmd.
+func (f *graphFilter) register(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&f.onlySubtree, "only-subtree", nil, "render only these packages and their dependencies (globs, comma-separated)")
+	cmd.Flags().StringSliceVar(&f.prune, "prune", nil, "drop packages matching these globs, with the deps only they pull in (comma-separated)")
+	cmd.Flags().IntVar(&f.maxDepth, "max-depth", 0, "render only this many levels below the roots (0 = all)")
+	cmd.Flags().StringSliceVar(&f.collapse, "collapse", nil, "merge the packages matching each glob into one block, e.g. '@babel/*' (comma-separated)")
+}
+
+// validate checks the depth and the glob patterns.
+func (f *graphFilter) validate() error {
+	if f.maxDepth < 0 {
+		return NewUserError(fmt.Sprintf("invalid --max-depth %d", f.maxDepth), "Use 0 for all levels, or a positive depth.")
+	}
+	for _, patterns := range [][]string{f.onlySubtree, f.prune, f.collapse} {
+		if err := deps.ValidateExcludes(patterns); err != nil {
+			return WrapUserError(err, "invalid package pattern", "Use shell-style globs such as 'types-*' or '@babel/*'.")
+		}
+	}
+	return nil
+}
+
+// apply returns g with the filters applied in a fixed order: the subtrees
+// are selected first, then packages are pruned, the depth is cut relative
+// to the remaining roots, and finally packages are collapsed.
+func (f *graphFilter) apply(g *dag.DAG) (*dag.DAG, error) {
+	if len(f.onlySubtree) > 0 {
+		roots := deps.MatchPackages(g, f.onlySubtree)
+		if len(roots) == 0 {
+			return nil, NewUserError(
+				fmt.Sprintf("no package matches --only-subtree %s", strings.Join(f.onlySubtree, ",")),
+				"Check the package names in the graph; globs such as 'requests*' work too.",
+			)
+		}
+		g = dag.Subgraph(g, roots)
+	}
+	g = deps.ExcludePackages(g, f.prune)
+	if f.maxDepth > 0 {
+		g = dag.LimitDepth(g, f.maxDepth)
+	}
+	return deps.CollapsePackages(g, f.collapse), nil
+}
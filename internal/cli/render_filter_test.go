@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func buildFilterGraph() *dag.DAG {
+	g := dag.New(nil)
+	for _, id := range []string{"app", "requests", "urllib3", "types-requests", "@babel/core", "@babel/types"} {
+		_ = g.AddNode(dag.Node{ID: id})
+	}
+	for _, e := range [][2]string{
+		{"app", "requests"}, {"app", "types-requests"}, {"app", "@babel/core"},
+		{"requests", "urllib3"}, {"@babel/core", "@babel/types"}, {"@babel/types", "urllib3"},
+	} {
+		_ = g.AddEdge(dag.Edge{From: e[0], To: e[1]})
+	}
+	return g
+}
+
+func TestGraphFilterApply(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter graphFilter
+		want   []string
+	}{
+		{"none", graphFilter{}, []string{"@babel/core", "@babel/types", "app", "requests", "types-requests", "urllib3"}},
+		{"only subtree", graphFilter{onlySubtree: []string{"requests"}}, []string{"requests", "urllib3"}},
+		{"prune", graphFilter{prune: []string{"types-*"}}, []string{"@babel/core", "@babel/types", "app", "requests", "urllib3"}},
+		{"max depth", graphFilter{maxDepth: 1}, []string{"@babel/core", "app", "requests", "types-requests"}},
+		{"collapse", graphFilter{collapse: []string{"@babel/*"}}, []string{"@babel/*", "app", "requests", "types-requests", "urllib3"}},
+		{"combined", graphFilter{prune: []string{"requests"}, collapse: []string{"@babel/*"}, maxDepth: 2}, []string{"@babel/*", "app", "types-requests"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := tt.filter.apply(buildFilterGraph())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := dag.NodeIDs(g.Nodes())
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("nodes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	filter := graphFilter{onlySubtree: []string{"flask"}}
+	if _, err := filter.apply(buildFilterGraph()); ExitCodeForError(err) != ExitCodeUsage {
+		t.Errorf("unmatched --only-subtree error = %v, want a usage error", err)
+	}
+}
+
+func TestGraphFilterValidate(t *testing.T) {
+	for _, f := range []graphFilter{{maxDepth: -1}, {prune: []string{"["}}, {collapse: []string{"@babel/["}}} {
+		if err := f.validate(); ExitCodeForError(err) != ExitCodeUsage {
+			t.Errorf("validate(%+v) = %v, want a usage error", f, err)
+		}
+	}
+	f := graphFilter{maxDepth: 3, prune: []string{"types-*"}}
+	if err := f.validate(); err != nil {
+		t.Errorf("validate() = %v", err)
+	}
+}
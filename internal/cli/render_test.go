@@ -2,6 +2,7 @@ package cli
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stacktower-io/stacktower/pkg/graph"
@@ -39,6 +40,27 @@ func TestReadRenderInputStdin(t *testing.T) {
 	}
 }
 
+func TestReadRenderInputSBOM(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bom.json")
+	bom := `{"bomFormat":"CycloneDX","metadata":{"component":{"bom-ref":"app","name":"app"}},
+		"components":[{"bom-ref":"dep","name":"dep","version":"1.0.0","purl":"pkg:npm/dep@1.0.0"}],
+		"dependencies":[{"ref":"app","dependsOn":["dep"]}]}`
+	if err := os.WriteFile(path, []byte(bom), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := readRenderInput(path)
+	if err != nil {
+		t.Fatalf("readRenderInput(%s) error = %v", path, err)
+	}
+	if g.NodeCount() != 2 || g.EdgeCount() != 1 {
+		t.Fatalf("got %d nodes, %d edges; want 2, 1", g.NodeCount(), g.EdgeCount())
+	}
+	if g.Meta()["language"] != "javascript" {
+		t.Errorf("language = %v, want javascript", g.Meta()["language"])
+	}
+}
+
 func TestParseFormats(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -155,3 +177,87 @@ func TestDefaultConstants(t *testing.T) {
 		t.Errorf("pipeline.DefaultSeed = %v, want 42", pipeline.DefaultSeed)
 	}
 }
+
+func TestParseTileSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		w, h    float64
+		wantErr bool
+	}{
+		{"1200x900", 1200, 900, false},
+		{"800X600", 800, 600, false},
+		{" 640 x 480 ", 640, 480, false},
+		{"1200", 0, 0, true},
+		{"0x900", 0, 0, true},
+		{"axb", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			w, h, err := parseTileSize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTileSize(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if w != tt.w || h != tt.h {
+				t.Errorf("parseTileSize(%q) = %.0fx%.0f, want %.0fx%.0f", tt.input, w, h, tt.w, tt.h)
+			}
+		})
+	}
+}
+
+func TestLoadNebraskaScoring(t *testing.T) {
+	if cfg, err := loadNebraskaScoring("", ""); err != nil || cfg != nil {
+		t.Fatalf("empty path = %v, %v; want nil, nil", cfg, err)
+	}
+
+	dir := t.TempDir()
+	good := filepath.Join(dir, "scoring.yaml")
+	if err := os.WriteFile(good, []byte("owner_weight: 5\ndepth_curve: sqrt\nexclude_orgs: [acme]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadNebraskaScoring(good, "org")
+	if err != nil {
+		t.Fatalf("loadNebraskaScoring(%s) error = %v", good, err)
+	}
+	if cfg.OwnerWeight != 5 || cfg.DepthCurve != "sqrt" || len(cfg.ExcludeOrgs) != 1 || cfg.GroupBy != "org" {
+		t.Errorf("got %+v", cfg)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"depth_curve": "cubic"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadNebraskaScoring(bad, ""); err == nil {
+		t.Error("expected error for unknown depth curve")
+	}
+	if _, err := loadNebraskaScoring("", "team"); err == nil {
+		t.Error("expected error for unknown grouping")
+	}
+}
+
+func TestLoadHealthConfig(t *testing.T) {
+	if cfg, err := loadHealthConfig(""); err != nil || cfg != nil {
+		t.Fatalf("empty path = %v, %v; want nil, nil", cfg, err)
+	}
+
+	dir := t.TempDir()
+	good := filepath.Join(dir, "health.yaml")
+	if err := os.WriteFile(good, []byte("vulns: 50\ncadence: 30\nstars: 0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadHealthConfig(good)
+	if err != nil {
+		t.Fatalf("loadHealthConfig(%s) error = %v", good, err)
+	}
+	if cfg.Vulns != 50 || cfg.Cadence != 30 || cfg.Stars != 0 {
+		t.Errorf("got %+v", cfg)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"maintainers": -1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadHealthConfig(bad); err == nil {
+		t.Error("expected error for negative weight")
+	}
+}
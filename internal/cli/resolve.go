@@ -21,6 +21,7 @@ import (
 type resolveFlags struct {
 	maxDepth          int
 	maxNodes          int
+	maxDuration       time.Duration
 	output            string
 	name              string
 	noCache           bool
@@ -28,6 +29,11 @@ type resolveFlags struct {
 	dependencyScope   string
 	includePrerelease bool
 	runtimeVersion    string
+	platform          string
+	includeIndirect   bool
+	sparseIndex       bool
+	exclude           []string
+	internal          []string
 }
 
 // resolveCommand creates the resolve command for quick dependency resolution testing.
@@ -64,6 +70,7 @@ Examples:
 
 	cmd.Flags().IntVar(&flags.maxDepth, "max-depth", flags.maxDepth, "maximum dependency depth")
 	cmd.Flags().IntVar(&flags.maxNodes, "max-nodes", flags.maxNodes, "maximum nodes to fetch")
+	cmd.Flags().DurationVar(&flags.maxDuration, "max-duration", 0, "stop resolving after this long and keep the partial graph, e.g. 30s (0 = no limit)")
 	cmd.Flags().StringVarP(&flags.output, "output", "o", "", "output file (stdout if empty)")
 	cmd.Flags().StringVarP(&flags.name, "name", "n", "", "project name (for manifest parsing)")
 	cmd.Flags().BoolVar(&flags.noCache, "no-cache", false, "disable caching")
@@ -71,6 +78,11 @@ Examples:
 	cmd.Flags().StringVar(&flags.dependencyScope, "dependency-scope", flags.dependencyScope, "dependency scope: prod_only or all")
 	cmd.Flags().BoolVar(&flags.includePrerelease, "include-prerelease", false, "include prerelease versions (alpha/beta/rc/dev/etc.)")
 	cmd.Flags().StringVar(&flags.runtimeVersion, "runtime-version", "", "target runtime version for marker evaluation (e.g., '3.11' for Python)")
+	cmd.Flags().StringVar(&flags.platform, "platform", "", "target platform for marker evaluation: linux (default), darwin or windows")
+	cmd.Flags().BoolVar(&flags.includeIndirect, "include-indirect", false, "follow '// indirect' requirements of go.mod files (Go only)")
+	cmd.Flags().BoolVar(&flags.sparseIndex, "sparse-index", false, "resolve crates from the sparse crates.io index instead of the API (Rust only)")
+	cmd.Flags().StringSliceVar(&flags.exclude, "exclude", nil, "drop packages matching these globs, with the deps only they pull in (comma-separated)")
+	cmd.Flags().StringSliceVar(&flags.internal, "internal", nil, "mark packages matching these globs as first-party code (comma-separated)")
 
 	return cmd
 }
@@ -83,7 +95,7 @@ func (c *CLI) runResolve(ctx context.Context, flags *resolveFlags, args []string
 
 	// Try auto-detecting language from filename
 	filename := filepath.Base(arg)
-	langName := deps.GetManifestLanguage(filename, languages.All)
+	langName := manifestLanguage(arg)
 	if langName != "" {
 		return c.resolveManifest(ctx, flags, langName, arg)
 	}
@@ -131,10 +143,16 @@ func (c *CLI) resolveFromRegistry(ctx context.Context, flags *resolveFlags, lang
 		Version:           version,
 		MaxDepth:          flags.maxDepth,
 		MaxNodes:          flags.maxNodes,
+		MaxDuration:       flags.maxDuration,
 		SkipEnrich:        !flags.enrich,
 		DependencyScope:   flags.dependencyScope,
 		IncludePrerelease: flags.includePrerelease,
 		RuntimeVersion:    flags.runtimeVersion,
+		Platform:          flags.platform,
+		IncludeIndirect:   flags.includeIndirect,
+		SparseIndex:       flags.sparseIndex,
+		Exclude:           flags.exclude,
+		Internal:          flags.internal,
 	}
 
 	displayName := pkg
@@ -178,10 +196,16 @@ func (c *CLI) resolveManifest(ctx context.Context, flags *resolveFlags, langName
 		ManifestPath:      filePath,
 		MaxDepth:          flags.maxDepth,
 		MaxNodes:          flags.maxNodes,
+		MaxDuration:       flags.maxDuration,
 		SkipEnrich:        !flags.enrich,
 		DependencyScope:   flags.dependencyScope,
 		IncludePrerelease: flags.includePrerelease,
 		RuntimeVersion:    flags.runtimeVersion,
+		Platform:          flags.platform,
+		IncludeIndirect:   flags.includeIndirect,
+		SparseIndex:       flags.sparseIndex,
+		Exclude:           flags.exclude,
+		Internal:          flags.internal,
 	}
 
 	result, err := c.runParseWithProgress(ctx, opts, flags.noCache, false,
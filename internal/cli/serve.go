@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacktower-io/stacktower/pkg/server"
+	"github.com/stacktower-io/stacktower/pkg/store"
+)
+
+// shutdownTimeout bounds how long serve waits for open requests on exit.
+const shutdownTimeout = 10 * time.Second
+
+func (c *CLI) serveCommand() *cobra.Command {
+	var (
+		addr         string
+		opts         server.Options
+		orderTimeout int
+		storeURL     string
+		noCache      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the pipeline as an HTTP API server",
+		Long: `Serve the parse → layout → export pipeline as a REST API, so a team can
+share one Stacktower instance and its cache.
+
+Submitting to /api/v1/parse, /api/v1/layout or /api/v1/export returns a job
+ID at once. Poll /api/v1/jobs/{id} until the job has finished, then download
+its artifacts from /api/v1/jobs/{id}/artifacts/{name}. The OpenAPI
+description is served at /api/v1/openapi.json.
+
+Projects registered at /api/v1/projects are re-resolved on a schedule, and
+their webhooks (generic JSON or Slack) are notified when the dependencies
+change materially: a new vulnerability, a newly brittle package, or a change
+in crossing count. Webhooks to localhost or private, loopback and link-local
+addresses are refused unless --allow-private-webhooks is set.
+
+Set STACKTOWER_API_TOKEN (or --token) to require "Authorization: Bearer <token>".
+Jobs are kept in memory and expire --job-ttl after they finish. With --store,
+job artifacts and project graphs are also persisted to a directory, an S3
+bucket (s3://bucket/prefix, credentials from AWS_*) or an SQLite database.`,
+		Example: `  # Serve on localhost:8080
+  stacktower serve
+
+  # Shared service with authentication
+  STACKTOWER_API_TOKEN=secret stacktower serve --addr :8080 --workers 8
+
+  # Keep artifacts in S3 beyond the job TTL
+  stacktower serve --store s3://renders/stacktower
+
+  # Render a tower in one request
+  curl -X POST localhost:8080/api/v1/export \
+    -d '{"language": "python", "package": "requests", "formats": ["svg"]}'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Token == "" {
+				opts.Token = os.Getenv("STACKTOWER_API_TOKEN")
+			}
+			opts.OrderTimeout = time.Duration(orderTimeout) * time.Second
+			if storeURL != "" {
+				st, err := store.Open(storeURL)
+				if err != nil {
+					return WrapUserError(err, "failed to open store "+storeURL, "Use a directory, s3://bucket/prefix or sqlite://path.")
+				}
+				defer st.Close()
+				opts.Store = st
+			}
+			return c.runServe(cmd.Context(), addr, opts, noCache)
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", "localhost:8080", "address to listen on")
+	cmd.Flags().IntVar(&opts.Workers, "workers", server.DefaultWorkers, "jobs to run at the same time")
+	cmd.Flags().DurationVar(&opts.JobTTL, "job-ttl", server.DefaultJobTTL, "how long finished jobs and their artifacts are kept")
+	cmd.Flags().IntVar(&opts.MaxJobs, "max-jobs", server.DefaultMaxJobs, "jobs kept at once before new submissions are refused")
+	cmd.Flags().IntVar(&opts.MaxProjects, "max-projects", server.DefaultMaxProjects, "monitored projects that can be registered")
+	cmd.Flags().BoolVar(&opts.AllowPrivateWebhooks, "allow-private-webhooks", false, "accept webhooks to localhost and private network addresses")
+	cmd.Flags().StringVar(&opts.Token, "token", "", "bearer token required on API routes (default $STACKTOWER_API_TOKEN)")
+	cmd.Flags().IntVar(&orderTimeout, "ordering-timeout", int(server.DefaultOrderTimeout/time.Second), "timeout in seconds for optimal ordering search")
+	cmd.Flags().StringVar(&storeURL, "store", "", "persist artifacts and project graphs to a directory, s3://bucket/prefix or sqlite://path")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "disable caching")
+
+	return cmd
+}
+
+// runServe serves the API on addr until ctx is canceled.
+func (c *CLI) runServe(ctx context.Context, addr string, opts server.Options, noCache bool) error {
+	runner, err := c.newRunner(noCache, true)
+	if err != nil {
+		return WrapSystemError(err, "failed to initialize runner", "This may be a cache or configuration issue.")
+	}
+	defer runner.Close()
+
+	opts.Logger = structuredLogger(c.Logger)
+	srv := server.New(runner, opts)
+	defer srv.Close()
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return WrapUserError(err, "failed to listen on "+addr, "Choose a free address with --addr.")
+	}
+
+	hs := &http.Server{Handler: srv, ReadHeaderTimeout: 10 * time.Second}
+	errc := make(chan error, 1)
+	go func() { errc <- hs.Serve(ln) }()
+
+	c.Logger.Info("serving API", "addr", ln.Addr().String(), "auth", opts.Token != "")
+
+	select {
+	case err := <-errc:
+		return WrapSystemError(err, "server failed", "")
+	case <-ctx.Done():
+	}
+
+	c.Logger.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := hs.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return WrapSystemError(err, "shutdown failed", "")
+	}
+	return nil
+}
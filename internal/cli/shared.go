@@ -1,17 +1,153 @@
 package cli
 
 import (
+	"bufio"
+	"bytes"
+	"io"
 	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
 	"github.com/stacktower-io/stacktower/pkg/graph"
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+	"github.com/stacktower-io/stacktower/pkg/sbom"
 )
 
 // loadGraph reads a dependency graph from a file path or stdin (when input is "-").
-// This is the shared entry point used by why, stats, diff, sbom, and render.
+// This is the shared entry point used by why, stats, diff, sbom, layout, and render.
+// Besides graph.json it accepts CycloneDX and SPDX SBOMs, which are mapped
+// into a graph without re-resolving, GraphML and GEXF documents, and CSV
+// edge lists (from,to per line), so query results can be piped in. Any of
+// them may be gzip- or zstd-compressed. Graph JSON is streamed rather than
+// read whole, so monorepo-sized graphs load without holding the document.
 func loadGraph(input string) (*dag.DAG, error) {
-	if input == "-" {
-		return graph.ReadGraph(os.Stdin)
+	var src io.Reader = os.Stdin
+	if input != "-" {
+		f, err := os.Open(input)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		src = f
+	}
+	r, err := graph.Decompress(src)
+	if err != nil {
+		return nil, err
+	}
+	br := bufio.NewReaderSize(r, sniffSize)
+	head, _ := br.Peek(sniffSize)
+	if trimmed := bytes.TrimSpace(head); len(trimmed) > 0 && trimmed[0] == '{' &&
+		!bytes.Contains(head, []byte(`"bomFormat"`)) && !bytes.Contains(head, []byte(`"spdxVersion"`)) {
+		return graph.ReadGraph(br)
+	}
+
+	data, err := io.ReadAll(br)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := sbom.Detect(data); ok {
+		return sbom.Read(bytes.NewReader(data))
+	}
+	switch format, _ := graph.DetectInterchange(data); format {
+	case graph.FormatGraphML:
+		return graph.ImportGraphML(bytes.NewReader(data))
+	case graph.FormatGEXF:
+		return graph.ImportGEXF(bytes.NewReader(data))
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] != '{' {
+		return graph.ImportCSV(bytes.NewReader(data), nil)
+	}
+	return graph.ReadGraph(bytes.NewReader(data))
+}
+
+// sniffSize is how much of the input loadGraph peeks at to tell graph JSON
+// from SBOMs. SBOM format markers sit at the top of the document.
+const sniffSize = 64 << 10
+
+// styleUsage is the --style help text. It lists the styles in the registry,
+// so styles registered by linked-in packages show up too.
+func styleUsage() string {
+	return "visual style: " + strings.Join(styles.Names(), ", ")
+}
+
+// loadThemeFile reads and validates a theme file for --theme-file. An empty
+// path returns an empty theme (the --style flag applies).
+func loadThemeFile(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", WrapUserError(err, "failed to read theme file", "Check that the file path exists and is readable.")
+	}
+	if err := pipeline.ValidateTheme(string(data)); err != nil {
+		return "", WrapUserError(err, "invalid theme file", "Theme files are YAML or JSON with keys like background, palette, block, text, edge, and popup.")
+	}
+	return string(data), nil
+}
+
+// loadNebraskaScoring reads custom Nebraska weights for --nebraska-scoring
+// and applies --nebraska-by on top. With neither set it returns nil (default
+// weights). YAML is a superset of JSON, so both formats are accepted.
+func loadNebraskaScoring(path, groupBy string) (*feature.ScoringConfig, error) {
+	if path == "" && groupBy == "" {
+		return nil, nil
+	}
+	var cfg feature.ScoringConfig
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, WrapUserError(err, "failed to read Nebraska scoring file", "Check that the file path exists and is readable.")
+		}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, WrapUserError(err, "invalid Nebraska scoring file", "Scoring files are YAML or JSON with keys like owner_weight, lead_weight, maintainer_weight, depth_curve, star_damping, include_orgs, exclude_orgs, and group_by.")
+		}
+	}
+	if groupBy != "" {
+		cfg.GroupBy = feature.Grouping(groupBy)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, WrapUserError(err, "invalid Nebraska scoring", "Use --nebraska-by maintainer or org; check depth_curve and weights in the scoring file.")
+	}
+	return &cfg, nil
+}
+
+// loadHealthConfig reads custom health score weights for --health-weights.
+// An empty path returns nil (default weights).
+func loadHealthConfig(path string) (*feature.HealthConfig, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, WrapUserError(err, "failed to read health weights file", "Check that the file path exists and is readable.")
+	}
+	var cfg feature.HealthConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, WrapUserError(err, "invalid health weights file", "Health weights files are YAML or JSON with numeric keys stars, maintainers, cadence, vulns, and scorecard.")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, WrapUserError(err, "invalid health weights", "Weights must be zero or positive; a zero weight leaves the component out.")
+	}
+	return &cfg, nil
+}
+
+// loadPopupTemplate reads and validates a popup template file for
+// --popup-template. An empty path returns an empty template (default popups).
+func loadPopupTemplate(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", WrapUserError(err, "failed to read popup template", "Check that the file path exists and is readable.")
+	}
+	if err := pipeline.ValidatePopupTemplate(string(data)); err != nil {
+		return "", WrapUserError(err, "invalid popup template", "Popup templates use Go text/template syntax, e.g. {{.ID}} or {{meta . \"owning_team\"}}.")
 	}
-	return graph.ReadGraphFile(input)
+	return string(data), nil
 }
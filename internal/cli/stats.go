@@ -2,8 +2,10 @@ package cli
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -47,10 +49,15 @@ type statsJSON struct {
 
 	Overview statsOverviewJSON `json:"overview"`
 
-	Maintenance *statsMaintenanceJSON `json:"maintenance,omitempty"`
-	Licenses    *statsLicensesJSON    `json:"licenses,omitempty"`
-	Vulns       *statsVulnsJSON       `json:"vulnerabilities,omitempty"`
-	LoadBearing []statsLoadJSON       `json:"load_bearing,omitempty"`
+	Maintenance *statsMaintenanceJSON      `json:"maintenance,omitempty"`
+	BusFactor   *feature.BusFactorReport   `json:"bus_factor,omitempty"`
+	Freshness   []feature.PackageFreshness `json:"freshness,omitempty"`
+	Suspicious  []feature.Suspicion        `json:"suspicious,omitempty"`
+	Health      []feature.PackageHealth    `json:"health,omitempty"`
+	Licenses    *statsLicensesJSON         `json:"licenses,omitempty"`
+	Vulns       *statsVulnsJSON            `json:"vulnerabilities,omitempty"`
+	Weights     []feature.DirectWeight     `json:"weights,omitempty"`
+	LoadBearing []statsLoadJSON            `json:"load_bearing,omitempty"`
 }
 
 type statsOverviewJSON struct {
@@ -62,11 +69,13 @@ type statsOverviewJSON struct {
 }
 
 type statsMaintenanceJSON struct {
-	SingleMaintainerCount int      `json:"single_maintainer_count"`
-	SingleMaintainerPct   float64  `json:"single_maintainer_pct"`
-	Brittle               []string `json:"brittle"`
-	Archived              []string `json:"archived"`
-	MedianLastCommitDays  int      `json:"median_last_commit_days"`
+	SingleMaintainerCount int               `json:"single_maintainer_count"`
+	SingleMaintainerPct   float64           `json:"single_maintainer_pct"`
+	Brittle               []string          `json:"brittle"`
+	Archived              []string          `json:"archived"`
+	Successors            map[string]string `json:"successors,omitempty"`
+	MedianLastCommitDays  int               `json:"median_last_commit_days"`
+	Outdated              int               `json:"outdated,omitempty"`
 }
 
 type statsLicensesJSON struct {
@@ -99,6 +108,39 @@ func (c *CLI) runStats(input, format, output string) error {
 		return WrapSystemError(err, "failed to load graph", "")
 	}
 
+	a := analyzeGraph(g)
+
+	w := os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return WrapSystemError(err, "failed to create output file", "")
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch format {
+	case "json":
+		return writeStatsJSON(w, a.report, a.bus, a.fresh, a.health, a.suspects)
+	default:
+		ui.WriteStats(w, a.report)
+		return nil
+	}
+}
+
+// graphAnalysis is the health report of a graph, shared by stats and the
+// CI summary of tower.
+type graphAnalysis struct {
+	report   ui.StatsReport
+	bus      feature.BusFactorReport
+	fresh    []feature.PackageFreshness
+	health   []feature.PackageHealth
+	suspects []feature.Suspicion
+}
+
+// analyzeGraph computes the health report of g.
+func analyzeGraph(g *dag.DAG) graphAnalysis {
 	graphStats := dag.ComputeStats(g)
 
 	root := dag.FindRoot(g)
@@ -124,8 +166,62 @@ func (c *CLI) runStats(input, format, output string) error {
 		})
 	}
 
+	// Weight attribution of direct dependencies
+	for _, dw := range feature.Weights(g) {
+		report.Weights = append(report.Weights, ui.WeightEntry{
+			Package:        dw.Package,
+			Transitive:     dw.Transitive,
+			Exclusive:      dw.Exclusive,
+			Share:          dw.Share,
+			ExclusiveShare: dw.ExclusiveShare,
+		})
+	}
+
 	// Maintenance analysis from node metadata
 	report.HasMaintenanceData = collectMaintenanceData(g, root, &report)
+	bus := feature.BusFactor(g)
+	if bus.TowerBusFactor > 0 {
+		report.TowerBusFactor = bus.TowerBusFactor
+		report.BusFactorKeyPeople = bus.KeyPeople
+		report.HasMaintenanceData = true
+	}
+	var fresh []feature.PackageFreshness
+	for _, f := range feature.Freshness(g, time.Now(), feature.BrittleThresholds{}) {
+		if f.Package == root || f.Package == "__project__" {
+			continue
+		}
+		fresh = append(fresh, f)
+		if f.VersionsBehind > 0 {
+			report.Outdated++
+			report.HasMaintenanceData = true
+		}
+	}
+
+	// Composite health, least healthy first
+	var health []feature.PackageHealth
+	for _, h := range feature.Health(g, feature.HealthConfig{}, time.Now(), feature.BrittleThresholds{}) {
+		if h.Package == root || h.Package == "__project__" {
+			continue
+		}
+		health = append(health, h)
+		if h.Level == feature.HealthPoor {
+			report.PoorHealth = append(report.PoorHealth, fmt.Sprintf("%s (%d)", h.Package, h.Score))
+			report.HasMaintenanceData = true
+		}
+	}
+
+	// Supply-chain heuristics
+	var suspects []feature.Suspicion
+	for _, s := range feature.Suspicious(g, time.Now()) {
+		if s.Package == root || s.Package == "__project__" {
+			continue
+		}
+		suspects = append(suspects, s)
+		report.Suspicious = append(report.Suspicious, ui.SuspiciousPkg{
+			Package: s.Package,
+			Reasons: strings.Join(s.Reasons, ", "),
+		})
+	}
 
 	// License analysis
 	licReport := security.AnalyzeLicenses(g)
@@ -150,26 +246,10 @@ func (c *CLI) runStats(input, format, output string) error {
 	// Vulnerability data from node metadata (already annotated during parse --security-scan)
 	collectVulnData(g, root, &report)
 
-	w := os.Stdout
-	if output != "" {
-		f, err := os.Create(output)
-		if err != nil {
-			return WrapSystemError(err, "failed to create output file", "")
-		}
-		defer f.Close()
-		w = f
-	}
-
-	switch format {
-	case "json":
-		return writeStatsJSON(w, report)
-	default:
-		ui.WriteStats(w, report)
-		return nil
-	}
+	return graphAnalysis{report: report, bus: bus, fresh: fresh, health: health, suspects: suspects}
 }
 
-func writeStatsJSON(w *os.File, r ui.StatsReport) error {
+func writeStatsJSON(w *os.File, r ui.StatsReport, bus feature.BusFactorReport, fresh []feature.PackageFreshness, health []feature.PackageHealth, suspects []feature.Suspicion) error {
 	out := statsJSON{
 		Root:     r.Root,
 		Version:  r.Version,
@@ -189,10 +269,19 @@ func writeStatsJSON(w *os.File, r ui.StatsReport) error {
 			SingleMaintainerPct:   r.SingleMaintainerPct,
 			Brittle:               r.Brittle,
 			Archived:              r.Archived,
+			Successors:            r.Successors,
 			MedianLastCommitDays:  r.MedianLastCommitDays,
+			Outdated:              r.Outdated,
 		}
 	}
 
+	if bus.TowerBusFactor > 0 {
+		out.BusFactor = &bus
+	}
+	out.Freshness = fresh
+	out.Health = health
+	out.Suspicious = suspects
+
 	if r.HasLicenseData {
 		out.Licenses = &statsLicensesJSON{
 			Summary:   r.LicenseSummary,
@@ -215,6 +304,16 @@ func writeStatsJSON(w *os.File, r ui.StatsReport) error {
 		}
 	}
 
+	for _, e := range r.Weights {
+		out.Weights = append(out.Weights, feature.DirectWeight{
+			Package:        e.Package,
+			Transitive:     e.Transitive,
+			Exclusive:      e.Exclusive,
+			Share:          e.Share,
+			ExclusiveShare: e.ExclusiveShare,
+		})
+	}
+
 	for _, lb := range r.LoadBearing {
 		out.LoadBearing = append(out.LoadBearing, statsLoadJSON{
 			Package:     lb.Package,
@@ -249,6 +348,12 @@ func collectMaintenanceData(g *dag.DAG, root string, r *ui.StatsReport) bool {
 		// Archived check
 		if archived, _ := n.Meta[metadata.RepoArchived].(bool); archived {
 			r.Archived = append(r.Archived, n.ID)
+			if s := feature.Successor(n); s != "" {
+				if r.Successors == nil {
+					r.Successors = make(map[string]string)
+				}
+				r.Successors[n.ID] = s
+			}
 			hasData = true
 		}
 
@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacktower-io/stacktower/internal/cli/ui"
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/languages"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+)
+
+// towerFlags holds tower command options: the parse settings plus
+// everything render accepts.
+type towerFlags struct {
+	pipeline.Options
+	render renderFlags
+	name   string // project name override for manifests
+	scan   bool   // run vulnerability scan after resolving
+	enrich bool   // enrich with GitHub metadata
+
+	ci        bool   // write the CI files into ciDir
+	ciDir     string // directory for the CI files
+	prComment bool   // also write a pull request comment
+}
+
+// towerCommand creates the tower command, which resolves and renders in one step.
+func (c *CLI) towerCommand() *cobra.Command {
+	flags := towerFlags{}
+	setCLIDefaults(&flags.Options)
+	flags.MaxDepth = pipeline.DefaultMaxDepth
+	flags.MaxNodes = pipeline.DefaultMaxNodes
+
+	cmd := &cobra.Command{
+		Use:   "tower [language] <package|manifest>",
+		Short: "Resolve and render dependencies in one step",
+		Long: `Resolve a package or manifest and render it, in one step.
+
+This runs the whole pipeline — resolve, normalize, order, layout, render —
+with the defaults of 'parse' and 'render', and accepts the flags of both.
+Manifests are detected by filename, so the language is only needed for
+registry packages. Nothing but the rendered files is written; use 'parse'
+and 'render' to keep the intermediate graph.
+
+Without -o, output is named after the package or manifest in the current
+directory (requests.svg, poetry.svg). Vulnerabilities are only shown after
+--security-scan, and --nebraska fetches contributors on its own.
+
+--ci writes everything a CI job needs into one directory with stable
+names: tower.svg, a tower.png thumbnail, the graph.json, and a Markdown
+summary.md with the health report, plus comment.md for a pull request
+with --pr-comment. On GitHub Actions the summary is also added to the
+job summary.`,
+		Example: `  # Render a manifest
+  stacktower tower poetry.lock
+
+  # Render a registry package, optionally pinned
+  stacktower tower python requests -o requests.svg
+  stacktower tower javascript express@4.18.2 -f svg,png
+
+  # The usual render flags apply
+  stacktower tower rust serde --style simple --edges --security-scan
+
+  # In CI: tower, thumbnail, graph and reports in ./stacktower
+  stacktower tower poetry.lock --ci --pr-comment --security-scan`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.render.apply(cmd, &flags.Options); err != nil {
+				return err
+			}
+			if err := validateCIFlags(flags.ci, flags.prComment, cmd.Flags().Changed("output"), cmd.Flags().Changed("format")); err != nil {
+				return err
+			}
+			return c.runTower(cmd.Context(), &flags, args)
+		},
+	}
+
+	cmd.Flags().IntVar(&flags.MaxDepth, "max-depth", flags.MaxDepth, "maximum dependency depth")
+	cmd.Flags().IntVar(&flags.MaxNodes, "max-nodes", flags.MaxNodes, "maximum nodes to fetch")
+	cmd.Flags().DurationVar(&flags.MaxDuration, "max-duration", 0, "stop resolving after this long and keep the partial graph, e.g. 30s (0 = no limit)")
+	cmd.Flags().IntVar(&flags.Workers, "workers", flags.Workers, "concurrent fetch workers (default 20)")
+	cmd.Flags().BoolVar(&flags.enrich, "enrich", true, "enrich with GitHub metadata (stars, maintainers)")
+	cmd.Flags().BoolVar(&flags.FetchContributors, "contributors", false, "fetch GitHub contributors for Nebraska rankings (slower)")
+	cmd.Flags().IntVar(&flags.EnrichDepth, "enrich-depth", 0, "enrich only packages up to this depth below the root (0 = all)")
+	cmd.Flags().IntVar(&flags.EnrichMinDependents, "enrich-min-dependents", 0, "also enrich packages with at least this many dependents")
+	cmd.Flags().BoolVar(&flags.LocalMetadata, "local-metadata", false, "read metadata of vendored dependencies (node_modules, vendor, site-packages, submodules) from disk")
+	cmd.Flags().StringVar(&flags.DependencyScope, "dependency-scope", "prod_only", "dependency scope: prod_only or all")
+	cmd.Flags().BoolVar(&flags.IncludePrerelease, "include-prerelease", false, "include prerelease versions (alpha/beta/rc/dev/etc.)")
+	cmd.Flags().StringVar(&flags.RuntimeVersion, "runtime-version", "", "target runtime version for marker evaluation (e.g., '3.11' for Python)")
+	cmd.Flags().StringVar(&flags.Platform, "platform", "", "target platform for marker evaluation: linux (default), darwin or windows")
+	cmd.Flags().BoolVar(&flags.IncludeIndirect, "include-indirect", false, "follow '// indirect' requirements of go.mod files (Go only)")
+	cmd.Flags().BoolVar(&flags.SparseIndex, "sparse-index", false, "resolve crates from the sparse crates.io index instead of the API (Rust only)")
+	cmd.Flags().StringVarP(&flags.name, "name", "n", "", "project name (for manifests)")
+	cmd.Flags().BoolVar(&flags.scan, "security-scan", false, "best-effort scan for known vulnerabilities (OSV.dev)")
+	cmd.Flags().StringSliceVar(&flags.Exclude, "exclude", nil, "drop packages matching these globs, with the deps only they pull in (comma-separated)")
+	cmd.Flags().StringSliceVar(&flags.Internal, "internal", nil, "mark packages matching these globs as first-party code (comma-separated)")
+	cmd.Flags().BoolVar(&flags.ci, "ci", false, "write tower.svg, a PNG thumbnail, graph.json and a Markdown summary to --ci-dir")
+	cmd.Flags().StringVar(&flags.ciDir, "ci-dir", defaultCIDir, "directory for the --ci files")
+	cmd.Flags().BoolVar(&flags.prComment, "pr-comment", false, "with --ci, also write comment.md for a pull request")
+	flags.render.register(cmd, &flags.Options)
+
+	return cmd
+}
+
+// runTower resolves the package or manifest in args and renders it.
+func (c *CLI) runTower(ctx context.Context, flags *towerFlags, args []string) error {
+	start := time.Now()
+
+	if err := validateFlags(flags.MaxDepth, flags.MaxNodes); err != nil {
+		return err
+	}
+
+	opts := flags.Options
+	opts.SkipEnrich = !flags.enrich
+	opts.FetchContributors = opts.FetchContributors || opts.Nebraska
+
+	source, base, err := towerSource(&opts, args, flags.name)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.runParseWithProgress(ctx, opts, flags.render.noCache, flags.scan,
+		fmt.Sprintf("Resolving %s...", source), flags.MaxNodes)
+	if err != nil {
+		return wrapParseFailure("resolve "+source, err)
+	}
+	g := result.Graph
+	if opts.Manifest != "" {
+		g.RenameNode(graph.ProjectRootNodeID, base) //nolint:errcheck // non-critical rename
+	}
+
+	if flags.ci {
+		return c.runTowerCI(ctx, g, source, opts, flags, start)
+	}
+
+	output := flags.render.output
+	if output == "" {
+		output = sanitizeFilenameSegment(base)
+		if len(opts.Formats) == 1 {
+			output += "." + opts.Formats[0]
+		}
+	}
+	return c.renderGraph(ctx, g, source, opts, output, flags.render.noCache, flags.render.orderTimeout, flags.render.maxCrossings, start)
+}
+
+// runTowerCI renders g into the CI directory and writes the other CI
+// files next to it. They are written even when the layout exceeds
+// --max-crossings, whose error is returned afterwards.
+func (c *CLI) runTowerCI(ctx context.Context, g *dag.DAG, source string, opts pipeline.Options, flags *towerFlags, start time.Time) error {
+	if err := os.MkdirAll(flags.ciDir, 0o755); err != nil {
+		return WrapSystemError(err, "failed to create CI directory", "Check the --ci-dir path and its permissions.")
+	}
+	opts.Formats = []string{pipeline.FormatSVG}
+	output := filepath.Join(flags.ciDir, ciTowerFile)
+
+	renderErr := c.renderGraph(ctx, g, source, opts, output, flags.render.noCache, flags.render.orderTimeout, flags.render.maxCrossings, start)
+	var crossings *CrossingsError
+	if renderErr != nil && !errors.As(renderErr, &crossings) {
+		return renderErr
+	}
+
+	paths, err := writeCIArtifacts(g, flags.ciDir, flags.prComment)
+	if err != nil {
+		return err
+	}
+	ui.PrintSuccess("CI files written to %s", flags.ciDir)
+	for _, path := range paths {
+		ui.PrintFile(path)
+	}
+	return renderErr
+}
+
+// towerSource fills the language and package or manifest of opts from the
+// command arguments. It returns how to refer to the source in messages
+// and the base name for output files.
+func towerSource(opts *pipeline.Options, args []string, name string) (source, base string, err error) {
+	arg := args[len(args)-1]
+
+	var lang *deps.Language
+	if len(args) == 2 {
+		if lang = languages.Find(args[0]); lang == nil {
+			return "", "", NewUserError(
+				fmt.Sprintf("unsupported language: %s", args[0]),
+				"Run `stacktower info` to list supported ecosystems.",
+			)
+		}
+	} else {
+		if !looksLikeFile(arg) {
+			return "", "", NewUserError(
+				fmt.Sprintf("cannot auto-detect language for %q (not a manifest file)", arg),
+				fmt.Sprintf("Name the language for packages: stacktower tower python %s", arg),
+			)
+		}
+		langName := manifestLanguage(arg)
+		if langName == "" {
+			return "", "", NewUserError(
+				fmt.Sprintf("unsupported manifest file: %s", filepath.Base(arg)),
+				fmt.Sprintf("Supported manifests: %s", formatSupportedManifests(deps.SupportedManifests(languages.All))),
+			)
+		}
+		lang = languages.Find(langName)
+	}
+	opts.Language = lang.Name
+
+	if lang.HasManifests() && looksLikeFile(arg) {
+		content, err := os.ReadFile(arg)
+		if err != nil {
+			return "", "", WrapUserError(err, "failed to read manifest file", "Check that the file path exists and is readable.")
+		}
+		filename := filepath.Base(arg)
+		opts.Manifest = string(content)
+		opts.ManifestFilename = filename
+		opts.ManifestPath = arg
+		if name == "" {
+			name = strings.TrimSuffix(filename, filepath.Ext(filename))
+		}
+		return filename, name, nil
+	}
+
+	pkg, version := parsePackageVersion(arg)
+	if lang.NormalizeName != nil {
+		pkg = lang.NormalizeName(pkg)
+	}
+	if err := validatePackageName(pkg); err != nil {
+		return "", "", WrapUserError(err, fmt.Sprintf("invalid package name %q", pkg), "Use a registry package identifier without path traversal or control characters.")
+	}
+	opts.Package = pkg
+	if version != "" {
+		opts.Version = version
+	}
+
+	source = lang.Name + "/" + pkg
+	if opts.Version != "" {
+		source += "@" + opts.Version
+	}
+	return source, pkg, nil
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+)
+
+func TestTowerSource(t *testing.T) {
+	var opts pipeline.Options
+	source, base, err := towerSource(&opts, []string{"python", "Requests@2.31.0"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Language != "python" || opts.Package != "requests" || opts.Version != "2.31.0" {
+		t.Errorf("opts = %+v", opts)
+	}
+	if source != "python/requests@2.31.0" || base != "requests" {
+		t.Errorf("source, base = %q, %q", source, base)
+	}
+
+	opts = pipeline.Options{}
+	if _, base, err := towerSource(&opts, []string{"../../examples/manifest/poetry.lock"}, ""); err != nil || base != "poetry" || opts.ManifestFilename != "poetry.lock" {
+		t.Errorf("manifest: base = %q, filename = %q, err = %v", base, opts.ManifestFilename, err)
+	}
+
+	for _, args := range [][]string{{"requests"}, {"cobol", "x"}, {"python", "../etc/passwd"}} {
+		if _, _, err := towerSource(&pipeline.Options{}, args, ""); ExitCodeForError(err) != ExitCodeUsage {
+			t.Errorf("towerSource(%v) error = %v, want usage error", args, err)
+		}
+	}
+}
+
+func TestRunTower(t *testing.T) {
+	c := New(os.Stderr, LogInfo)
+	flags := towerFlags{}
+	setCLIDefaults(&flags.Options)
+	flags.MaxDepth = pipeline.DefaultMaxDepth
+	flags.MaxNodes = pipeline.DefaultMaxNodes
+	flags.Ordering = "barycentric"
+	flags.render.noCache = true
+	flags.render.output = filepath.Join(t.TempDir(), "out.svg")
+	if err := flags.render.apply(&cobra.Command{}, &flags.Options); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.runTower(context.Background(), &flags, []string{"../../examples/manifest/poetry.lock"}); err != nil {
+		t.Fatalf("runTower error = %v", err)
+	}
+	data, err := os.ReadFile(flags.render.output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "<svg") {
+		t.Error("output is not an SVG")
+	}
+}
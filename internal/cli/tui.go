@@ -0,0 +1,83 @@
+package cli
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	"github.com/stacktower-io/stacktower/internal/cli/ui"
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+// tuiCommand creates the tui command, an interactive graph browser.
+func (c *CLI) tuiCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "tui <graph.json>",
+		Short: "Browse and triage a dependency graph interactively",
+		Long: `Browse a dependency graph in the terminal before rendering it.
+
+Packages are listed with vulnerable and brittle ones first. Search by name
+with '/', cycle the filter (all, flagged, vulnerable, brittle) with 'f',
+and the selected package's metadata, advisories and relations are shown
+below the list.
+
+Mark packages with space and press 'x' to export them, with everything
+they depend on, as a new graph. Without marks, 'x' exports the packages
+currently shown.`,
+		Example: `  stacktower tui graph.json
+
+  # Export the triaged subgraph and render it
+  stacktower tui graph.json -o risky.json
+  stacktower render risky.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.runTUI(args[0], output)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "file for the exported subgraph (default: <input>.subset.json)")
+
+	return cmd
+}
+
+// runTUI opens the graph browser on input and writes the subgraph the
+// user exported, if any.
+func (c *CLI) runTUI(input, output string) error {
+	if input == "-" {
+		return NewUserError("tui cannot read the graph from stdin", "Save the graph to a file first; the terminal is needed for input.")
+	}
+	g, err := loadGraph(input)
+	if err != nil {
+		return WrapSystemError(err, "failed to load graph", "Check that the file exists and is a valid graph JSON.")
+	}
+
+	final, err := tea.NewProgram(ui.NewGraphBrowserModel(g), tea.WithAltScreen()).Run()
+	if err != nil {
+		return WrapSystemError(err, "graph browser failed", "The tui command needs an interactive terminal.")
+	}
+	m, ok := final.(*ui.GraphBrowserModel)
+	if !ok || len(m.Export) == 0 {
+		return nil
+	}
+
+	if output == "" {
+		output = tuiOutputPath(input)
+	}
+	sub := dag.Subgraph(g, m.Export)
+	if err := graph.ExportJSONFile(sub, output, graph.ExportOptions{}); err != nil {
+		return WrapSystemError(err, "failed to write subgraph", "")
+	}
+
+	ui.PrintSuccess("Exported %d packages (%d selected, with their dependencies)", sub.NodeCount(), len(m.Export))
+	ui.PrintFile(output)
+	ui.PrintNextStep("Render", "stacktower render "+output)
+	return nil
+}
+
+// tuiOutputPath names the exported subgraph after the input graph:
+// graph.json, graph.json.gz and graph.json.zst become graph.subset.json.
+func tuiOutputPath(input string) string {
+	return deriveBasePath(graph.TrimCompressionExt(input), "") + ".subset.json"
+}
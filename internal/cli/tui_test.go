@@ -0,0 +1,17 @@
+package cli
+
+import "testing"
+
+func TestTUIOutputPath(t *testing.T) {
+	tests := map[string]string{
+		"graph.json":       "graph.subset.json",
+		"out/api.json.gz":  "out/api.subset.json",
+		"graph.graphml":    "graph.subset.json",
+		"deps.layout.json": "deps.subset.json",
+	}
+	for input, want := range tests {
+		if got := tuiOutputPath(input); got != want {
+			t.Errorf("tuiOutputPath(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+// BrowseHints lists the keys of the graph browser.
+const BrowseHints = "↑/↓ navigate  / search  f filter  space mark  x export  q quit"
+
+// BrowseFilter narrows the packages shown by the graph browser.
+type BrowseFilter int
+
+const (
+	BrowseAll BrowseFilter = iota
+	BrowseFlagged
+	BrowseVulnerable
+	BrowseBrittle
+)
+
+func (f BrowseFilter) String() string {
+	switch f {
+	case BrowseFlagged:
+		return "flagged"
+	case BrowseVulnerable:
+		return "vulnerable"
+	case BrowseBrittle:
+		return "brittle"
+	default:
+		return "all"
+	}
+}
+
+// BrowseItem is one package in the graph browser.
+type BrowseItem struct {
+	ID      string
+	Version string
+	Vuln    string // highest vulnerability severity, empty when none
+	Brittle bool
+}
+
+// GraphBrowserModel is the bubbletea model for triaging a dependency
+// graph: it lists packages, searches and filters them, shows the selected
+// package's metadata, and marks packages for export.
+type GraphBrowserModel struct {
+	Graph    *dag.DAG
+	Items    []BrowseItem
+	Filtered []int // indices into Items matching Search and Filter
+	Marked   map[string]bool
+	Search   string
+	Filter   BrowseFilter
+	Cursor   int
+	Height   int
+	Offset   int
+
+	// Searching is true while the search query is being typed.
+	Searching bool
+
+	// Export is set when the user asked to export; it holds the IDs to
+	// export: the marked packages, or every package shown when none are.
+	Export []string
+}
+
+// NewGraphBrowserModel creates a browser over the regular, non-virtual
+// packages of g, flagged packages first.
+func NewGraphBrowserModel(g *dag.DAG) *GraphBrowserModel {
+	var items []BrowseItem
+	for _, n := range g.Nodes() {
+		if n.IsSynthetic() || n.Meta["virtual"] == true {
+			continue
+		}
+		version, _ := n.Meta["version"].(string)
+		items = append(items, BrowseItem{
+			ID:      n.ID,
+			Version: version,
+			Vuln:    graph.SecurityOf(n.Meta).VulnSeverity,
+			Brittle: feature.IsBrittle(n),
+		})
+	}
+	slices.SortFunc(items, func(a, b BrowseItem) int {
+		if fa, fb := a.flagged(), b.flagged(); fa != fb {
+			if fa {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	m := &GraphBrowserModel{
+		Graph:  g,
+		Items:  items,
+		Marked: map[string]bool{},
+		Height: 15,
+	}
+	m.rebuildFilter()
+	return m
+}
+
+func (it BrowseItem) flagged() bool {
+	return it.Vuln != "" || it.Brittle
+}
+
+func (m *GraphBrowserModel) matches(it BrowseItem) bool {
+	switch m.Filter {
+	case BrowseFlagged:
+		if !it.flagged() {
+			return false
+		}
+	case BrowseVulnerable:
+		if it.Vuln == "" {
+			return false
+		}
+	case BrowseBrittle:
+		if !it.Brittle {
+			return false
+		}
+	}
+	return m.Search == "" || strings.Contains(strings.ToLower(it.ID), strings.ToLower(m.Search))
+}
+
+func (m *GraphBrowserModel) rebuildFilter() {
+	m.Filtered = m.Filtered[:0]
+	for i, it := range m.Items {
+		if m.matches(it) {
+			m.Filtered = append(m.Filtered, i)
+		}
+	}
+	if m.Cursor >= len(m.Filtered) {
+		m.Cursor = max(0, len(m.Filtered)-1)
+	}
+	m.Offset = 0
+	m.scroll()
+}
+
+func (m *GraphBrowserModel) scroll() {
+	if m.Cursor < m.Offset {
+		m.Offset = m.Cursor
+	}
+	if m.Cursor >= m.Offset+m.Height {
+		m.Offset = m.Cursor - m.Height + 1
+	}
+}
+
+// Current returns the package under the cursor.
+func (m *GraphBrowserModel) Current() (BrowseItem, bool) {
+	if len(m.Filtered) == 0 {
+		return BrowseItem{}, false
+	}
+	return m.Items[m.Filtered[m.Cursor]], true
+}
+
+func (m *GraphBrowserModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *GraphBrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.Searching {
+			return m.updateSearch(msg)
+		}
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		case "up", "k":
+			if m.Cursor > 0 {
+				m.Cursor--
+				m.scroll()
+			}
+		case "down", "j":
+			if m.Cursor < len(m.Filtered)-1 {
+				m.Cursor++
+				m.scroll()
+			}
+		case "pgup":
+			m.Cursor = max(0, m.Cursor-m.Height)
+			m.scroll()
+		case "pgdown":
+			m.Cursor = max(0, min(len(m.Filtered)-1, m.Cursor+m.Height))
+			m.scroll()
+		case "/":
+			m.Searching = true
+		case "f":
+			m.Filter = (m.Filter + 1) % (BrowseBrittle + 1)
+			m.rebuildFilter()
+		case " ":
+			if it, ok := m.Current(); ok {
+				if m.Marked[it.ID] {
+					delete(m.Marked, it.ID)
+				} else {
+					m.Marked[it.ID] = true
+				}
+			}
+		case "x":
+			m.Export = m.exportIDs()
+			return m, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		m.Height = msg.Height - 18
+		if m.Height < 5 {
+			m.Height = 5
+		}
+		m.scroll()
+	}
+	return m, nil
+}
+
+func (m *GraphBrowserModel) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "enter":
+		m.Searching = false
+	case "esc":
+		m.Searching = false
+		m.Search = ""
+		m.rebuildFilter()
+	case "backspace":
+		if len(m.Search) > 0 {
+			m.Search = m.Search[:len(m.Search)-1]
+			m.rebuildFilter()
+		}
+	default:
+		if msg.Type == tea.KeyRunes {
+			m.Search += string(msg.Runes)
+			m.rebuildFilter()
+		}
+	}
+	return m, nil
+}
+
+func (m *GraphBrowserModel) exportIDs() []string {
+	if len(m.Marked) > 0 {
+		return slices.Sorted(maps.Keys(m.Marked))
+	}
+	ids := make([]string, len(m.Filtered))
+	for i, idx := range m.Filtered {
+		ids[i] = m.Items[idx].ID
+	}
+	return ids
+}
+
+func (m *GraphBrowserModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(StyleTitle.Render("Packages"))
+	fmt.Fprintf(&b, "  %s\n", ListDimStyle.Render(fmt.Sprintf("%d shown · %d marked · filter: %s",
+		len(m.Filtered), len(m.Marked), m.Filter)))
+	b.WriteString(ListDimStyle.Render(BrowseHints))
+	b.WriteString("\n")
+	if m.Searching || m.Search != "" {
+		cursor := ""
+		if m.Searching {
+			cursor = "▏"
+		}
+		fmt.Fprintf(&b, "  search: %s%s\n", StyleHighlight.Render(m.Search), cursor)
+	}
+	b.WriteString("\n")
+
+	if len(m.Filtered) == 0 {
+		b.WriteString(ListDimStyle.Render("  No matching packages"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	width := 0
+	for _, i := range m.Filtered {
+		width = max(width, len(m.Items[i].ID))
+	}
+	width = min(width, 40)
+
+	end := min(m.Offset+m.Height, len(m.Filtered))
+	for vi := m.Offset; vi < end; vi++ {
+		it := m.Items[m.Filtered[vi]]
+		cursor := "  "
+		if vi == m.Cursor {
+			cursor = "▸ "
+		}
+		mark := "  "
+		if m.Marked[it.ID] {
+			mark = StyleHighlight.Render("● ")
+		}
+		line := fmt.Sprintf("%-*s %-12s", width, Truncate(it.ID, width), Truncate(it.Version, 12))
+		if vi == m.Cursor {
+			line = ListSelectedStyle.Render(line)
+		} else {
+			line = ListNormalStyle.Render(line)
+		}
+		b.WriteString(cursor + mark + line + " " + browseFlags(it) + "\n")
+	}
+	b.WriteString(ListDimStyle.Render(fmt.Sprintf("  [%d/%d]", m.Cursor+1, len(m.Filtered))))
+	b.WriteString("\n\n")
+
+	if it, ok := m.Current(); ok {
+		b.WriteString(m.details(it))
+	}
+	return b.String()
+}
+
+func browseFlags(it BrowseItem) string {
+	var flags []string
+	switch it.Vuln {
+	case "":
+	case "critical", "high":
+		flags = append(flags, StyleError.Render(it.Vuln))
+	default:
+		flags = append(flags, StyleWarning.Render(it.Vuln))
+	}
+	if it.Brittle {
+		flags = append(flags, StyleWarning.Render("brittle"))
+	}
+	return strings.Join(flags, " ")
+}
+
+// details renders the metadata of one package.
+func (m *GraphBrowserModel) details(it BrowseItem) string {
+	n, ok := m.Graph.Node(it.ID)
+	if !ok {
+		return ""
+	}
+	repo := graph.RepoOf(n.Meta)
+	sec := graph.SecurityOf(n.Meta)
+
+	var b strings.Builder
+	line := func(key, value string) {
+		if value != "" {
+			b.WriteString(StyleKeyLabel.Render(key) + " " + StyleValue.Render(value) + "\n")
+		}
+	}
+
+	b.WriteString(StyleTitle.Render(it.ID))
+	if it.Version != "" {
+		b.WriteString(" " + StyleDim.Render(it.Version))
+	}
+	b.WriteString("\n")
+	if desc, _ := n.Meta["description"].(string); desc != "" {
+		line("Description", Truncate(desc, 80))
+	} else {
+		line("Description", Truncate(repo.Description, 80))
+	}
+	line("License", sec.License)
+	line("Repository", repo.URL)
+	if repo.Stars > 0 {
+		line("Stars", fmt.Sprintf("%d", repo.Stars))
+	}
+	line("Last commit", repo.LastCommit)
+	if len(repo.Maintainers) > 0 {
+		line("Maintainers", Truncate(strings.Join(repo.Maintainers, ", "), 80))
+	}
+	if flags := browseFlags(it); flags != "" {
+		b.WriteString(StyleKeyLabel.Render("Flags") + " " + flags + "\n")
+	}
+	for _, f := range sec.Findings {
+		line("Advisory", Truncate(fmt.Sprintf("%s (%s) %s", f.ID, f.Severity, f.Summary), 80))
+	}
+	line("Depends on", countAndNames(m.Graph.Children(it.ID)))
+	line("Used by", countAndNames(m.Graph.Parents(it.ID)))
+	return b.String()
+}
+
+func countAndNames(ids []string) string {
+	if len(ids) == 0 {
+		return "0"
+	}
+	shown := slices.Sorted(slices.Values(ids))
+	suffix := ""
+	if len(shown) > maxDisplayedNames {
+		suffix = fmt.Sprintf(" +%d more", len(shown)-maxDisplayedNames)
+		shown = shown[:maxDisplayedNames]
+	}
+	return fmt.Sprintf("%d: %s%s", len(ids), strings.Join(shown, ", "), suffix)
+}
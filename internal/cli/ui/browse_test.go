@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func buildBrowseGraph() *dag.DAG {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Meta: dag.Metadata{"version": "1.0.0"}})
+	g.AddNode(dag.Node{ID: "requests", Meta: dag.Metadata{"version": "2.31.0", "vuln_severity": "high"}})
+	g.AddNode(dag.Node{ID: "urllib3", Meta: dag.Metadata{"version": "2.0.0"}})
+	g.AddNode(dag.Node{ID: "six", Meta: dag.Metadata{"version": "1.16.0", "repo_archived": true}})
+	g.AddNode(dag.Node{ID: "six_sub", Kind: dag.NodeKindSubdivider, MasterID: "six"})
+	g.AddEdge(dag.Edge{From: "app", To: "requests"})
+	g.AddEdge(dag.Edge{From: "app", To: "six"})
+	g.AddEdge(dag.Edge{From: "requests", To: "urllib3"})
+	return g
+}
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func shownIDs(m *GraphBrowserModel) []string {
+	var ids []string
+	for _, i := range m.Filtered {
+		ids = append(ids, m.Items[i].ID)
+	}
+	return ids
+}
+
+func TestGraphBrowserModel_Items(t *testing.T) {
+	m := NewGraphBrowserModel(buildBrowseGraph())
+
+	// Flagged packages come first; synthetic nodes are skipped.
+	want := []string{"requests", "six", "app", "urllib3"}
+	if got := shownIDs(m); !slices.Equal(got, want) {
+		t.Fatalf("items = %v, want %v", got, want)
+	}
+	if it := m.Items[0]; it.Vuln != "high" || it.Brittle {
+		t.Errorf("requests = %+v, want vulnerable and not brittle", it)
+	}
+	if it := m.Items[1]; !it.Brittle {
+		t.Errorf("six = %+v, want brittle", it)
+	}
+}
+
+func TestGraphBrowserModel_Filter(t *testing.T) {
+	m := NewGraphBrowserModel(buildBrowseGraph())
+
+	tests := []struct {
+		filter BrowseFilter
+		want   []string
+	}{
+		{BrowseFlagged, []string{"requests", "six"}},
+		{BrowseVulnerable, []string{"requests"}},
+		{BrowseBrittle, []string{"six"}},
+		{BrowseAll, []string{"requests", "six", "app", "urllib3"}},
+	}
+	for _, tt := range tests {
+		m.Update(key("f"))
+		if m.Filter != tt.filter {
+			t.Fatalf("Filter = %v, want %v", m.Filter, tt.filter)
+		}
+		if got := shownIDs(m); !slices.Equal(got, tt.want) {
+			t.Errorf("filter %v: shown = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestGraphBrowserModel_Search(t *testing.T) {
+	m := NewGraphBrowserModel(buildBrowseGraph())
+
+	m.Update(key("/"))
+	if !m.Searching {
+		t.Fatal("'/' should start a search")
+	}
+	for _, ch := range "URL" {
+		m.Update(key(string(ch)))
+	}
+	if got := shownIDs(m); !slices.Equal(got, []string{"urllib3"}) {
+		t.Errorf("search %q: shown = %v", m.Search, got)
+	}
+
+	// Keys are typed into the query while searching, not run as commands.
+	m.Update(key("f"))
+	if m.Filter != BrowseAll || m.Search != "URLf" {
+		t.Errorf("Filter = %v, Search = %q", m.Filter, m.Search)
+	}
+
+	m.Update(key("esc"))
+	if m.Searching || m.Search != "" || len(m.Filtered) != 4 {
+		t.Errorf("esc should clear the search, got %q with %d shown", m.Search, len(m.Filtered))
+	}
+}
+
+func TestGraphBrowserModel_Export(t *testing.T) {
+	t.Run("marked", func(t *testing.T) {
+		m := NewGraphBrowserModel(buildBrowseGraph())
+		m.Update(key(" "))
+		m.Update(key("down"))
+		m.Update(key("down"))
+		m.Update(key(" "))
+		m.Update(key(" ")) // unmark app again
+		m.Update(key("up"))
+		m.Update(key(" "))
+
+		_, cmd := m.Update(key("x"))
+		if cmd == nil {
+			t.Error("export should quit")
+		}
+		if want := []string{"requests", "six"}; !slices.Equal(m.Export, want) {
+			t.Errorf("Export = %v, want %v", m.Export, want)
+		}
+	})
+
+	t.Run("shown when nothing is marked", func(t *testing.T) {
+		m := NewGraphBrowserModel(buildBrowseGraph())
+		m.Update(key("f"))
+		m.Update(key("x"))
+		if want := []string{"requests", "six"}; !slices.Equal(m.Export, want) {
+			t.Errorf("Export = %v, want %v", m.Export, want)
+		}
+	})
+
+	t.Run("quit without export", func(t *testing.T) {
+		m := NewGraphBrowserModel(buildBrowseGraph())
+		m.Update(key("q"))
+		if m.Export != nil {
+			t.Errorf("Export = %v, want none", m.Export)
+		}
+	})
+}
+
+func TestGraphBrowserModel_View(t *testing.T) {
+	m := NewGraphBrowserModel(buildBrowseGraph())
+	view := m.View()
+	for _, want := range []string{"requests", "2.31.0", "high", "brittle", "Depends on", "urllib3", "Used by"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q", want)
+		}
+	}
+
+	m.Update(key("/"))
+	for _, ch := range "nothing" {
+		m.Update(key(string(ch)))
+	}
+	if !strings.Contains(m.View(), "No matching packages") {
+		t.Error("view should say when nothing matches")
+	}
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/stacktower-io/stacktower/pkg/policy"
+)
+
+var (
+	styleCheckPass = lipgloss.NewStyle().Foreground(ColorGreen)
+	styleCheckFail = lipgloss.NewStyle().Foreground(ColorRed).Bold(true)
+	styleCheckRule = lipgloss.NewStyle().Foreground(ColorWhite)
+	styleCheckPkg  = lipgloss.NewStyle().Foreground(ColorWhite)
+)
+
+// maxCheckViolations caps how many violating packages are listed per rule.
+const maxCheckViolations = 10
+
+// WriteCheck renders a policy result as a summary table of the graph, one
+// line per rule followed by the packages that broke it, and a closing
+// verdict.
+func WriteCheck(w io.Writer, res policy.Result) {
+	sum := res.Summary
+	rows := [][2]string{
+		{"Package", sum.Root},
+		{"Dependencies", fmt.Sprintf("%d (%d direct)", sum.Dependencies, sum.Direct)},
+		{"Max depth", fmt.Sprint(sum.MaxDepth)},
+		{"Brittle", fmt.Sprint(sum.Brittle)},
+		{"Vulnerable", fmt.Sprint(sum.Vulnerable)},
+	}
+	for _, row := range rows {
+		if row[1] == "" {
+			continue
+		}
+		fmt.Fprintf(w, "%s %s\n", StyleDim.Render(fmt.Sprintf("%-13s", row[0])), styleStatsNum.Render(row[1]))
+	}
+	fmt.Fprintln(w)
+
+	for _, rr := range res.Rules {
+		mark := styleCheckPass.Render("✓")
+		if !rr.Passed {
+			mark = styleCheckFail.Render("✗")
+		}
+		fmt.Fprintf(w, "%s %-18s %s %s\n", mark,
+			styleCheckRule.Render(rr.Rule),
+			StyleDim.Render(rr.Limit+":"),
+			rr.Actual,
+		)
+		for i, v := range rr.Violations {
+			if i == maxCheckViolations {
+				fmt.Fprintf(w, "    %s\n", StyleDim.Render(fmt.Sprintf("… and %d more", len(rr.Violations)-i)))
+				break
+			}
+			fmt.Fprintf(w, "    %s %s\n", styleCheckPkg.Render(v.Package), StyleDim.Render(v.Detail))
+		}
+	}
+
+	fmt.Fprintln(w)
+	if res.Passed {
+		fmt.Fprintln(w, styleCheckPass.Render(fmt.Sprintf("Policy passed (%d rules)", len(res.Rules))))
+		return
+	}
+	fmt.Fprintln(w, styleCheckFail.Render(fmt.Sprintf("Policy failed (%d of %d rules)", len(res.Failed()), len(res.Rules))))
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// jsonMode replaces styled status output with NDJSON events on stderr.
+// Set via SetJSON() at startup.
+var jsonMode bool
+
+var (
+	eventMu  sync.Mutex
+	eventOut io.Writer = os.Stderr
+)
+
+// SetJSON switches status output between styled text and NDJSON events.
+func SetJSON(j bool) {
+	jsonMode = j
+}
+
+// IsJSON returns whether status output is written as NDJSON events.
+func IsJSON() bool {
+	return jsonMode
+}
+
+// Event writes one NDJSON event to stderr, such as
+//
+//	{"time":"2026-01-02T15:04:05Z","event":"file","path":"deps.json"}
+//
+// Every status line has an event in JSON mode: "message" (with a level of
+// success, info, warning, error or detail), "file", "stats",
+// "render_stats" and "progress". Commands add their own, like "result".
+func Event(event string, fields map[string]any) {
+	rec := make(map[string]any, len(fields)+2)
+	for k, v := range fields {
+		rec[k] = v
+	}
+	rec["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+	rec["event"] = event
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+
+	eventMu.Lock()
+	defer eventMu.Unlock()
+	eventOut.Write(append(data, '\n')) //nolint:errcheck // best-effort status output
+}
+
+// messageEvent writes a status message as a "message" event, without the
+// styling it would have on a terminal.
+func messageEvent(level, msg string) {
+	Event("message", map[string]any{"level": level, "msg": ansi.Strip(msg)})
+}
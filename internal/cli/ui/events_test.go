@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// captureEvents switches to JSON mode and returns the events written until
+// the returned function is called.
+func captureEvents(t *testing.T) func() []map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	prevOut, prevJSON, prevQuiet := eventOut, jsonMode, quietMode
+	eventOut, jsonMode, quietMode = &buf, true, false
+	t.Cleanup(func() { eventOut, jsonMode, quietMode = prevOut, prevJSON, prevQuiet })
+
+	return func() []map[string]any {
+		var events []map[string]any
+		sc := bufio.NewScanner(&buf)
+		for sc.Scan() {
+			var e map[string]any
+			if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+				t.Fatalf("invalid NDJSON line %q: %v", sc.Text(), err)
+			}
+			events = append(events, e)
+		}
+		return events
+	}
+}
+
+func TestJSONModeStatusOutput(t *testing.T) {
+	events := captureEvents(t)
+
+	PrintSuccess("Resolved %s", StyleHighlight.Render("flask"))
+	PrintFile("flask.json")
+	PrintStats(20, 23, 3, true, 1500*time.Millisecond)
+	PrintNextStep("Render", "stacktower render flask.json")
+	PrintNewline()
+
+	got := events()
+	if len(got) != 3 {
+		t.Fatalf("got %d events, want 3 (next steps and blank lines are dropped): %v", len(got), got)
+	}
+	if got[0]["event"] != "message" || got[0]["level"] != "success" || got[0]["msg"] != "Resolved flask" {
+		t.Errorf("message event = %v", got[0])
+	}
+	if got[1]["event"] != "file" || got[1]["path"] != "flask.json" {
+		t.Errorf("file event = %v", got[1])
+	}
+	if got[2]["event"] != "stats" || got[2]["nodes"] != float64(20) || got[2]["elapsed_ms"] != float64(1500) {
+		t.Errorf("stats event = %v", got[2])
+	}
+	for _, e := range got {
+		if _, ok := e["time"].(string); !ok {
+			t.Errorf("event without time: %v", e)
+		}
+	}
+}
+
+func TestJSONModeProgress(t *testing.T) {
+	events := captureEvents(t)
+
+	s := NewSpinner("Rendering...")
+	s.Start()
+	s.UpdateMessage("Computing layout...")
+	s.Stop()
+
+	pv := NewProgressView(context.Background(), "Resolving...", 10)
+	pv.Start()
+	pv.OnFetchComplete(context.Background(), "flask", 0, 0, nil)
+	pv.Stop()
+
+	got := events()
+	if len(got) < 3 {
+		t.Fatalf("got %d events, want at least 3: %v", len(got), got)
+	}
+	for _, e := range got {
+		if e["event"] != "progress" {
+			t.Errorf("unexpected event %v", e)
+		}
+	}
+	if got[1]["msg"] != "Computing layout..." {
+		t.Errorf("spinner update = %v", got[1])
+	}
+	if got[2]["msg"] != "Resolving..." || got[2]["max_nodes"] != float64(10) {
+		t.Errorf("progress view event = %v", got[2])
+	}
+}
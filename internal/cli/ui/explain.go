@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// ExplainReport holds everything the explain command knows about one
+// package of a graph.
+type ExplainReport struct {
+	Package string
+	Version string
+	IsRoot  bool
+
+	// Paths from the roots to the package, at most the requested number.
+	Paths         [][]string
+	ShortestDepth int // -1 without paths
+
+	// Relations
+	DirectDependents     []string
+	TransitiveDependents int
+	Dependencies         int
+
+	// Metadata
+	Description string
+	License     string
+	Repository  string
+	Stars       int
+	LastCommit  string
+	Maintainers []string
+
+	// Risk
+	HealthScore  int
+	HealthLevel  string
+	Brittle      bool
+	VulnSeverity string
+	Advisories   []string // "ID (severity)"
+
+	// PulledInBy lists the direct dependencies that pull the package in;
+	// Drops counts the packages, the package included, that go away when
+	// the only one of them is removed.
+	PulledInBy []string
+	Drops      int
+}
+
+// WriteExplain renders an explain report to the writer in the styled
+// terminal format.
+func WriteExplain(w io.Writer, r ExplainReport) {
+	header := stylePathTarget.Render(r.Package)
+	if r.Version != "" {
+		header += " " + styleTreeVersion.Render(r.Version)
+	}
+	fmt.Fprintln(w, header)
+	if r.Description != "" {
+		fmt.Fprintln(w, styleStatsLabel.Render(Truncate(r.Description, 80)))
+	}
+	fmt.Fprintln(w)
+
+	if r.IsRoot {
+		writeSection(w, "Why it is here")
+		fmt.Fprintln(w, "  It is a root of the graph: the project or package that was resolved.")
+	} else {
+		title := "Why it is here (" + countNoun(len(r.Paths), "path")
+		if r.ShortestDepth >= 0 {
+			title += fmt.Sprintf(", shortest: depth %d", r.ShortestDepth)
+		}
+		writeSection(w, title+")")
+		arrow := stylePathArrow.Render(" → ")
+		for _, path := range r.Paths {
+			parts := make([]string, len(path))
+			for i, id := range path {
+				if id == r.Package {
+					parts[i] = stylePathTarget.Render(id)
+				} else {
+					parts[i] = stylePathPkg.Render(id)
+				}
+			}
+			fmt.Fprintf(w, "  %s\n", strings.Join(parts, arrow))
+		}
+	}
+
+	fmt.Fprintln(w)
+	writeSection(w, "Dependents")
+	direct := styleStatsNum.Render(fmt.Sprintf("%d", len(r.DirectDependents))) + " direct"
+	if len(r.DirectDependents) > 0 {
+		direct += " " + styleStatsLabel.Render("("+joinLimited(r.DirectDependents, maxDisplayedNames)+")")
+	}
+	fmt.Fprintf(w, "  %s · %s in total\n", direct, styleStatsNum.Render(fmt.Sprintf("%d", r.TransitiveDependents)))
+	fmt.Fprintf(w, "  Depends on %s\n", countNoun(r.Dependencies, "package"))
+
+	stars := ""
+	if r.Stars > 0 {
+		stars = fmt.Sprintf("%d", r.Stars)
+	}
+	meta := [][2]string{
+		{"License", r.License},
+		{"Repository", strings.TrimPrefix(r.Repository, "https://")},
+		{"Stars", stars},
+		{"Last commit", r.LastCommit},
+		{"Maintainers", joinLimited(r.Maintainers, maxDisplayedNames)},
+	}
+	var rows []string
+	for _, kv := range meta {
+		if kv[1] != "" {
+			rows = append(rows, fmt.Sprintf("  %-12s %s", styleStatsLabel.Render(kv[0]), styleStatsPkg.Render(kv[1])))
+		}
+	}
+	if len(rows) > 0 {
+		fmt.Fprintln(w)
+		writeSection(w, "Package")
+		for _, row := range rows {
+			fmt.Fprintln(w, row)
+		}
+	}
+
+	fmt.Fprintln(w)
+	writeSection(w, "Health")
+	if r.HealthLevel == "" || r.HealthLevel == "unknown" {
+		fmt.Fprintf(w, "  %s\n", styleStatsLabel.Render("unknown (parse with --enrich for repository data)"))
+	} else {
+		fmt.Fprintf(w, "  %s/100 · %s\n", styleStatsNum.Render(fmt.Sprintf("%d", r.HealthScore)), r.HealthLevel)
+	}
+	if r.Brittle {
+		fmt.Fprintf(w, "  %s\n", styleStatsWarn.Render("brittle: few maintainers, stale or archived"))
+	}
+	if r.VulnSeverity != "" {
+		fmt.Fprintf(w, "  %s vulnerabilities", styleStatsWarn.Render(r.VulnSeverity))
+		if len(r.Advisories) > 0 {
+			fmt.Fprintf(w, ": %s", styleStatsPkg.Render(strings.Join(r.Advisories, ", ")))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if r.IsRoot {
+		return
+	}
+	fmt.Fprintln(w)
+	writeSection(w, "Removing it")
+	switch {
+	case len(r.PulledInBy) == 0:
+		fmt.Fprintf(w, "  %s\n", styleStatsLabel.Render("The graph has no single root, so no direct dependency to remove."))
+	case len(r.PulledInBy) == 1 && r.PulledInBy[0] == r.Package:
+		fmt.Fprintf(w, "  It is a direct dependency; removing it drops %s.\n", countNoun(r.Drops, "package"))
+	case len(r.PulledInBy) == 1:
+		fmt.Fprintf(w, "  Remove %s to drop it, with %s in total.\n",
+			stylePathTarget.Render(r.PulledInBy[0]), countNoun(r.Drops, "package"))
+	default:
+		others := slices.DeleteFunc(slices.Clone(r.PulledInBy), func(id string) bool { return id == r.Package })
+		names := styleStatsPkg.Render(strings.Join(others, ", "))
+		if len(others) < len(r.PulledInBy) {
+			fmt.Fprintf(w, "  It is a direct dependency, but %s pull it in too; remove them as well to drop it.\n", names)
+		} else {
+			fmt.Fprintf(w, "  No single direct dependency pulls it in: remove all of %s to drop it.\n", names)
+		}
+	}
+}
+
+// countNoun formats a count with its noun, pluralized with an s.
+func countNoun(n int, noun string) string {
+	if n != 1 {
+		noun += "s"
+	}
+	return styleStatsNum.Render(fmt.Sprintf("%d", n)) + " " + noun
+}
+
+// joinLimited joins names, listing at most limit of them.
+func joinLimited(names []string, limit int) string {
+	if len(names) <= limit {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s +%d more", strings.Join(names[:limit], ", "), len(names)-limit)
+}
@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PRCommentMarker starts the pull request comment written by tower --ci,
+// so CI scripts can find and update their previous comment.
+const PRCommentMarker = "<!-- stacktower -->"
+
+// maxMarkdownNames caps the packages listed per line in Markdown reports.
+const maxMarkdownNames = 15
+
+// WriteStatsMarkdown renders a stats report as GitHub-flavored Markdown,
+// for CI job summaries. A non-empty image is embedded below the header.
+func WriteStatsMarkdown(w io.Writer, r StatsReport, image string) {
+	fmt.Fprintf(w, "## %s\n\n", markdownTitle(r))
+	if image != "" {
+		fmt.Fprintf(w, "![Dependency tower of %s](%s)\n\n", r.Root, image)
+	}
+
+	fmt.Fprintln(w, "### Overview")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Packages | Edges | Depth | Direct | Transitive |")
+	fmt.Fprintln(w, "| ---: | ---: | ---: | ---: | ---: |")
+	fmt.Fprintf(w, "| %d | %d | %d | %d | %d |\n", r.TotalPackages, r.TotalEdges, r.MaxDepth, r.DirectDeps, r.TransitiveDeps)
+
+	if r.HasVulnData {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "### Vulnerabilities")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| Critical | High | Medium | Low |")
+		fmt.Fprintln(w, "| ---: | ---: | ---: | ---: |")
+		fmt.Fprintf(w, "| %d | %d | %d | %d |\n", r.VulnCritical, r.VulnHigh, r.VulnMedium, r.VulnLow)
+		if len(r.VulnAffected) > 0 {
+			parts := make([]string, len(r.VulnAffected))
+			for i, v := range r.VulnAffected {
+				parts[i] = fmt.Sprintf("`%s` (%s)", v.Package, v.Severity)
+			}
+			fmt.Fprintf(w, "\nAffected: %s\n", joinLimited(parts, maxMarkdownNames))
+		}
+	}
+
+	if r.HasMaintenanceData {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "### Maintenance")
+		fmt.Fprintln(w)
+		if r.SingleMaintainerCount > 0 {
+			fmt.Fprintf(w, "- %d single-maintainer packages (%.0f%%)\n", r.SingleMaintainerCount, r.SingleMaintainerPct)
+		}
+		if len(r.Brittle) > 0 {
+			fmt.Fprintf(w, "- %d brittle: %s\n", len(r.Brittle), markdownCodes(r.Brittle))
+		}
+		for _, pkg := range r.Archived {
+			line := fmt.Sprintf("- archived: `%s`", pkg)
+			if s := r.Successors[pkg]; s != "" {
+				line += " → " + s
+			}
+			fmt.Fprintln(w, line)
+		}
+		if r.MedianLastCommitDays > 0 {
+			fmt.Fprintf(w, "- Median last commit: %d days ago\n", r.MedianLastCommitDays)
+		}
+		if r.Outdated > 0 {
+			fmt.Fprintf(w, "- %d packages behind their latest version\n", r.Outdated)
+		}
+		if len(r.PoorHealth) > 0 {
+			fmt.Fprintf(w, "- %d in poor health: %s\n", len(r.PoorHealth), joinLimited(r.PoorHealth, maxMarkdownNames))
+		}
+		if r.TowerBusFactor > 0 {
+			fmt.Fprintf(w, "- Tower bus factor: %d (@%s)\n", r.TowerBusFactor, strings.Join(r.BusFactorKeyPeople, ", @"))
+		}
+	}
+
+	if r.HasLicenseData {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "### Licenses")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| Category | Packages |")
+		fmt.Fprintln(w, "| --- | ---: |")
+		for _, cat := range []string{"permissive", "weak-copyleft", "copyleft", "proprietary", "unknown"} {
+			if count := r.LicenseSummary[cat]; count > 0 {
+				fmt.Fprintf(w, "| %s | %d |\n", cat, count)
+			}
+		}
+	}
+
+	if len(r.Suspicious) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "### Supply chain")
+		fmt.Fprintln(w)
+		for _, s := range r.Suspicious {
+			fmt.Fprintf(w, "- `%s`: %s\n", s.Package, s.Reasons)
+		}
+	}
+
+	if len(r.Weights) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "### Direct dependency weight")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| Package | Pulls in | Share | Only via |")
+		fmt.Fprintln(w, "| --- | ---: | ---: | ---: |")
+		limit := min(len(r.Weights), 10)
+		for _, e := range r.Weights[:limit] {
+			fmt.Fprintf(w, "| `%s` | %d | %.0f%% | %d (%.0f%%) |\n",
+				e.Package, e.Transitive, e.Share*100, e.Exclusive, e.ExclusiveShare*100)
+		}
+		if len(r.Weights) > limit {
+			fmt.Fprintf(w, "\n… and %d more\n", len(r.Weights)-limit)
+		}
+	}
+
+	if len(r.LoadBearing) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "### Top load-bearing packages")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| # | Package | Dependents |")
+		fmt.Fprintln(w, "| ---: | --- | ---: |")
+		for i, lb := range r.LoadBearing[:min(len(r.LoadBearing), 5)] {
+			fmt.Fprintf(w, "| %d | `%s` | %d |\n", i+1, lb.Package, lb.ReverseDeps)
+		}
+	}
+}
+
+// WritePRComment renders a short pull request comment: a one-line verdict
+// with the full Markdown report folded below it. It starts with
+// [PRCommentMarker].
+func WritePRComment(w io.Writer, r StatsReport) {
+	fmt.Fprintln(w, PRCommentMarker)
+	fmt.Fprintf(w, "### Dependency tower: %s\n\n", markdownTitle(r))
+
+	parts := []string{fmt.Sprintf("**%d packages** (%d direct, %d transitive)", r.TotalPackages, r.DirectDeps, r.TransitiveDeps),
+		fmt.Sprintf("depth %d", r.MaxDepth)}
+	if n := len(r.VulnAffected); n > 0 {
+		parts = append(parts, fmt.Sprintf("⚠️ %d vulnerable (%d critical, %d high)", n, r.VulnCritical, r.VulnHigh))
+	} else if r.HasVulnData {
+		parts = append(parts, "no known vulnerabilities")
+	}
+	if n := len(r.Brittle); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d brittle", n))
+	}
+	if n := len(r.Suspicious); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d suspicious", n))
+	}
+	if n := r.LicenseSummary["copyleft"] + r.LicenseSummary["proprietary"] + r.LicenseSummary["unknown"]; n > 0 {
+		noun := "licenses"
+		if n == 1 {
+			noun = "license"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s to review", n, noun))
+	}
+	fmt.Fprintln(w, strings.Join(parts, " · "))
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "<details><summary>Full report</summary>")
+	fmt.Fprintln(w)
+	WriteStatsMarkdown(w, r, "")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "</details>")
+}
+
+func markdownTitle(r StatsReport) string {
+	title := "`" + r.Root + "`"
+	if r.Version != "" {
+		title += " " + r.Version
+	}
+	if r.Language != "" {
+		title += " · " + r.Language
+	}
+	return title
+}
+
+func markdownCodes(names []string) string {
+	codes := make([]string, len(names))
+	for i, n := range names {
+		codes[i] = "`" + n + "`"
+	}
+	return joinLimited(codes, maxMarkdownNames)
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func sampleStatsReport() StatsReport {
+	return StatsReport{
+		Root:               "app",
+		Version:            "1.0.0",
+		Language:           "python",
+		TotalPackages:      5,
+		TotalEdges:         6,
+		MaxDepth:           3,
+		DirectDeps:         2,
+		TransitiveDeps:     2,
+		Brittle:            []string{"six"},
+		HasMaintenanceData: true,
+		VulnHigh:           1,
+		VulnAffected:       []VulnAffectedPkg{{Package: "urllib3", Severity: "high"}},
+		HasVulnData:        true,
+		LicenseSummary:     map[string]int{"permissive": 3, "copyleft": 1},
+		HasLicenseData:     true,
+		LoadBearing:        []LoadBearingEntry{{Package: "urllib3", ReverseDeps: 2}},
+	}
+}
+
+func TestWriteStatsMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	WriteStatsMarkdown(&buf, sampleStatsReport(), "tower.svg")
+	out := buf.String()
+
+	for _, want := range []string{
+		"## `app` 1.0.0 · python",
+		"![Dependency tower of app](tower.svg)",
+		"| 5 | 6 | 3 | 2 | 2 |",
+		"Affected: `urllib3` (high)",
+		"- 1 brittle: `six`",
+		"| copyleft | 1 |",
+		"| 1 | `urllib3` | 2 |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "\x1b[") {
+		t.Error("markdown contains ANSI escapes")
+	}
+
+	buf.Reset()
+	WriteStatsMarkdown(&buf, StatsReport{Root: "app"}, "")
+	if strings.Contains(buf.String(), "![") || strings.Contains(buf.String(), "### Vulnerabilities") {
+		t.Errorf("empty report rendered optional parts:\n%s", buf.String())
+	}
+}
+
+func TestWritePRComment(t *testing.T) {
+	var buf bytes.Buffer
+	WritePRComment(&buf, sampleStatsReport())
+	out := buf.String()
+
+	if !strings.HasPrefix(out, PRCommentMarker+"\n") {
+		t.Errorf("comment does not start with the marker:\n%s", out)
+	}
+	for _, want := range []string{
+		"**5 packages** (2 direct, 2 transitive) · depth 3 · ⚠️ 1 vulnerable (0 critical, 1 high) · 1 brittle · 1 license to review",
+		"<details><summary>Full report</summary>",
+		"</details>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("comment missing %q:\n%s", want, out)
+		}
+	}
+}
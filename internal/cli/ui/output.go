@@ -62,18 +62,32 @@ func PrintSuccess(format string, args ...any) {
 		return
 	}
 	msg := fmt.Sprintf(format, args...)
+	if jsonMode {
+		messageEvent("success", msg)
+		return
+	}
 	fmt.Fprintln(os.Stderr, StyleIconSuccess.Render(IconSuccess)+" "+msg)
 }
 
 // PrintError prints an error message to stderr. Never suppressed.
 func PrintError(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
+	if jsonMode {
+		messageEvent("error", msg)
+		return
+	}
 	fmt.Fprintln(os.Stderr, StyleIconError.Render(IconError)+" "+msg)
 }
 
-// PrintWarning prints a warning message to stderr. Never suppressed.
+// PrintWarning prints a warning message to stderr and records it for
+// the summary. Never suppressed.
 func PrintWarning(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
+	recordWarning(msg)
+	if jsonMode {
+		messageEvent("warning", msg)
+		return
+	}
 	fmt.Fprintln(os.Stderr, StyleIconWarning.Render(IconWarning)+" "+StyleWarning.Render(msg))
 }
 
@@ -83,6 +97,10 @@ func PrintInfo(format string, args ...any) {
 		return
 	}
 	msg := fmt.Sprintf(format, args...)
+	if jsonMode {
+		messageEvent("info", msg)
+		return
+	}
 	fmt.Fprintln(os.Stderr, StyleIconInfo.Render(IconInfo)+" "+msg)
 }
 
@@ -92,6 +110,10 @@ func PrintDetail(format string, args ...any) {
 		return
 	}
 	msg := fmt.Sprintf(format, args...)
+	if jsonMode {
+		messageEvent("detail", msg)
+		return
+	}
 	fmt.Fprintln(os.Stderr, "  "+StyleDim.Render(msg))
 }
 
@@ -104,6 +126,10 @@ func PrintFile(path string) {
 	if quietMode {
 		return
 	}
+	if jsonMode {
+		Event("file", map[string]any{"path": path})
+		return
+	}
 	fmt.Fprintln(os.Stderr, "  "+StyleDim.Render(IconArrow)+" "+StyleValue.Render(path))
 }
 
@@ -113,6 +139,10 @@ func PrintFile(path string) {
 
 // PrintKeyValue prints a labeled value to stderr.
 func PrintKeyValue(key, value string) {
+	if jsonMode {
+		Event("value", map[string]any{"key": key, "value": value})
+		return
+	}
 	fmt.Fprintln(os.Stderr, StyleKeyLabel.Render(key)+" "+StyleValue.Render(value))
 }
 
@@ -126,6 +156,17 @@ func PrintStats(nodeCount, edgeCount, depth int, cached bool, elapsed time.Durat
 	if quietMode {
 		return
 	}
+	if jsonMode {
+		fields := map[string]any{"nodes": nodeCount, "edges": edgeCount, "cached": cached}
+		if depth > 0 {
+			fields["depth"] = depth
+		}
+		if elapsed > 0 {
+			fields["elapsed_ms"] = elapsed.Milliseconds()
+		}
+		Event("stats", fields)
+		return
+	}
 	parts := []string{
 		StyleNumber.Render(fmt.Sprintf("%d", nodeCount)) + StyleDim.Render(" packages"),
 		StyleNumber.Render(fmt.Sprintf("%d", edgeCount)) + StyleDim.Render(" edges"),
@@ -175,6 +216,15 @@ func PrintRenderStats(s RenderStats) {
 	if quietMode {
 		return
 	}
+	if jsonMode {
+		Event("render_stats", map[string]any{
+			"layers":    s.Layers,
+			"crossings": s.Crossings,
+			"ordering":  s.Ordering,
+			"style":     s.Style,
+		})
+		return
+	}
 	parts := []string{
 		StyleNumber.Render(fmt.Sprintf("%d", s.Layers)) + StyleDim.Render(" layers"),
 	}
@@ -203,9 +253,9 @@ func JoinDot(parts []string) string {
 
 // PrintHeader prints a styled section header to stderr. Used for multi-phase
 // commands (e.g. GitHub flow) and informational displays (e.g. whoami).
-// Suppressed in quiet mode.
+// Suppressed in quiet and JSON mode.
 func PrintHeader(title string) {
-	if quietMode {
+	if quietMode || jsonMode {
 		return
 	}
 	fmt.Fprintln(os.Stderr, StyleTitle.Render(title))
@@ -216,9 +266,9 @@ func PrintHeader(title string) {
 // Commands & Next Steps
 // =============================================================================
 
-// PrintNextStep prints a suggested next command to stderr. Suppressed in quiet mode.
+// PrintNextStep prints a suggested next command to stderr. Suppressed in quiet and JSON mode.
 func PrintNextStep(description, cmd string) {
-	if quietMode {
+	if quietMode || jsonMode {
 		return
 	}
 	fmt.Fprintln(os.Stderr, StyleDim.Render(description+":"+" ")+StyleCommand.Render(cmd))
@@ -242,12 +292,16 @@ func PrintVersionInfo(version, commit, date string) {
 // PrintInline prints a dim message to stderr without a trailing newline.
 func PrintInline(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
+	if jsonMode {
+		messageEvent("detail", msg)
+		return
+	}
 	fmt.Fprint(os.Stderr, StyleDim.Render(msg))
 }
 
-// PrintNewline prints an empty line to stderr. Suppressed in quiet mode.
+// PrintNewline prints an empty line to stderr. Suppressed in quiet and JSON mode.
 func PrintNewline() {
-	if quietMode {
+	if quietMode || jsonMode {
 		return
 	}
 	fmt.Fprintln(os.Stderr)
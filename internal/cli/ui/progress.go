@@ -20,6 +20,10 @@ import (
 // maxDisplayedNames is the maximum number of package names shown in the "fetching" line.
 const maxDisplayedNames = 5
 
+// progressEventInterval is how often ProgressView writes a "progress"
+// event in JSON mode.
+const progressEventInterval = time.Second
+
 // ProgressView renders live resolver progress on stderr.
 // It implements both observability.ResolverHooks and observability.RateLimitHooks
 // so the crawler and HTTP clients feed it events without any direct coupling to CLI code.
@@ -34,6 +38,7 @@ type ProgressView struct {
 	cancel context.CancelFunc
 	done   chan struct{}
 	isTTY  bool
+	events bool // write "progress" events instead of rendering (JSON mode)
 	active bool
 
 	mu       sync.Mutex
@@ -76,7 +81,8 @@ func NewProgressView(ctx context.Context, message string, maxNodes int) *Progres
 		ctx:           pvCtx,
 		cancel:        cancel,
 		done:          make(chan struct{}),
-		isTTY:         isatty.IsTerminal(os.Stderr.Fd()) || isatty.IsCygwinTerminal(os.Stderr.Fd()),
+		isTTY:         !jsonMode && (isatty.IsTerminal(os.Stderr.Fd()) || isatty.IsCygwinTerminal(os.Stderr.Fd())),
+		events:        jsonMode,
 		inflight:      make(map[string]bool),
 		seenOK:        make(map[string]bool),
 		rateLimited:   make(map[string]rateLimitInfo),
@@ -93,11 +99,13 @@ func (pv *ProgressView) Start() {
 	observability.SetResolverHooks(pv)
 	observability.SetRateLimitHooks(pv)
 	pv.active = true
-	if !pv.isTTY {
-		return
+	switch {
+	case pv.isTTY:
+		pv.render(0)
+		go pv.loop()
+	case pv.events:
+		go pv.eventLoop()
 	}
-	pv.render(0)
-	go pv.loop()
 }
 
 // Stop deregisters hooks, cancels the render loop, and clears the output area.
@@ -105,16 +113,7 @@ func (pv *ProgressView) Stop() {
 	if !pv.active {
 		return
 	}
-	pv.cancel()
-	if pv.isTTY {
-		<-pv.done
-		pv.mu.Lock()
-		pv.clearLinesLocked()
-		pv.mu.Unlock()
-	}
-	observability.SetResolverHooks(nil)
-	observability.SetRateLimitHooks(nil)
-	pv.active = false
+	pv.halt()
 }
 
 // StopWithError deregisters hooks, clears progress, and prints an error.
@@ -122,9 +121,16 @@ func (pv *ProgressView) StopWithError(message string) {
 	if !pv.active {
 		return
 	}
+	pv.halt()
+	PrintError("%s", message)
+}
+
+func (pv *ProgressView) halt() {
 	pv.cancel()
-	if pv.isTTY {
+	if pv.isTTY || pv.events {
 		<-pv.done
+	}
+	if pv.isTTY {
 		pv.mu.Lock()
 		pv.clearLinesLocked()
 		pv.mu.Unlock()
@@ -132,7 +138,6 @@ func (pv *ProgressView) StopWithError(message string) {
 	observability.SetResolverHooks(nil)
 	observability.SetRateLimitHooks(nil)
 	pv.active = false
-	PrintError("%s", message)
 }
 
 // ---------------------------------------------------------------------------
@@ -248,6 +253,38 @@ func (pv *ProgressView) loop() {
 	}
 }
 
+// eventLoop writes a "progress" event now and every progressEventInterval
+// until the view stops.
+func (pv *ProgressView) eventLoop() {
+	defer close(pv.done)
+	ticker := time.NewTicker(progressEventInterval)
+	defer ticker.Stop()
+
+	for {
+		pv.mu.Lock()
+		fields := map[string]any{
+			"msg":      pv.message,
+			"fetched":  pv.fetched,
+			"inflight": len(pv.inflight),
+			"pending":  pv.pending,
+		}
+		if pv.maxNodes > 0 {
+			fields["max_nodes"] = pv.maxNodes
+		}
+		if pv.enriching {
+			fields["enriching"] = pv.enrichProvider
+		}
+		pv.mu.Unlock()
+		Event("progress", fields)
+
+		select {
+		case <-pv.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func (pv *ProgressView) render(frame int) {
 	pv.mu.Lock()
 	defer pv.mu.Unlock()
@@ -47,8 +47,12 @@ func NewSpinnerWithContext(ctx context.Context, message string) *Spinner {
 
 // Start begins the spinner animation.
 // In quiet mode, no animation is shown but Stop() remains callable.
+// In JSON mode, the message and its updates are written as "progress" events.
 func (s *Spinner) Start() {
-	if quietMode {
+	if quietMode || jsonMode {
+		if jsonMode && !quietMode {
+			Event("progress", map[string]any{"msg": s.message})
+		}
 		close(s.stopped)
 		return
 	}
@@ -106,6 +110,9 @@ func (s *Spinner) UpdateMessage(message string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.message = message
+	if jsonMode && !quietMode && s.ctx.Err() == nil {
+		Event("progress", map[string]any{"msg": message})
+	}
 }
 
 // StopWithSuccess stops the spinner and shows a success message.
@@ -34,7 +34,12 @@ type StatsReport struct {
 	SingleMaintainerPct   float64
 	Brittle               []string
 	Archived              []string
+	Successors            map[string]string // Archived package -> maintained fork URL
 	MedianLastCommitDays  int
+	Outdated              int      // Packages behind their latest published version
+	PoorHealth            []string // Packages with a poor health score, as "name (score)"
+	TowerBusFactor        int      // People whose loss orphans most packages (0 = no data)
+	BusFactorKeyPeople    []string // Those people
 	HasMaintenanceData    bool
 
 	// Licenses
@@ -51,16 +56,37 @@ type StatsReport struct {
 	VulnAffected []VulnAffectedPkg
 	HasVulnData  bool
 
+	// Supply chain
+	Suspicious []SuspiciousPkg
+
+	// Weight attribution, heaviest direct dependency first
+	Weights []WeightEntry
+
 	// Load-bearing
 	LoadBearing []LoadBearingEntry
 }
 
+// WeightEntry records how much of the tower a direct dependency accounts for.
+type WeightEntry struct {
+	Package        string
+	Transitive     int     // Packages pulled in below it
+	Exclusive      int     // Packages, itself included, only it pulls in
+	Share          float64 // (1 + Transitive) / all dependencies
+	ExclusiveShare float64 // Exclusive / all dependencies
+}
+
 // VulnAffectedPkg describes a package with a vulnerability.
 type VulnAffectedPkg struct {
 	Package  string
 	Severity string
 }
 
+// SuspiciousPkg describes a package flagged by the supply-chain heuristics.
+type SuspiciousPkg struct {
+	Package string
+	Reasons string
+}
+
 // LoadBearingEntry records a load-bearing package and its reverse dep count.
 type LoadBearingEntry struct {
 	Package     string
@@ -113,12 +139,34 @@ func WriteStats(w io.Writer, r StatsReport) {
 				styleStatsWarn.Render(fmt.Sprintf("%d", len(r.Archived))),
 				styleStatsPkg.Render(strings.Join(r.Archived, ", ")),
 			)
+			for _, pkg := range r.Archived {
+				if s := r.Successors[pkg]; s != "" {
+					fmt.Fprintf(w, "    %s → %s\n", styleStatsPkg.Render(pkg), styleStatsLabel.Render(strings.TrimPrefix(s, "https://")))
+				}
+			}
 		}
 		if r.MedianLastCommitDays > 0 {
 			fmt.Fprintf(w, "  Median last commit: %s days ago\n",
 				styleStatsNum.Render(fmt.Sprintf("%d", r.MedianLastCommitDays)),
 			)
 		}
+		if r.Outdated > 0 {
+			fmt.Fprintf(w, "  %s packages behind their latest version\n",
+				styleStatsWarn.Render(fmt.Sprintf("%d", r.Outdated)),
+			)
+		}
+		if len(r.PoorHealth) > 0 {
+			fmt.Fprintf(w, "  %s in poor health: %s\n",
+				styleStatsWarn.Render(fmt.Sprintf("%d", len(r.PoorHealth))),
+				styleStatsPkg.Render(strings.Join(r.PoorHealth, ", ")),
+			)
+		}
+		if r.TowerBusFactor > 0 {
+			fmt.Fprintf(w, "  Tower bus factor: %s %s\n",
+				styleStatsNum.Render(fmt.Sprintf("%d", r.TowerBusFactor)),
+				styleStatsLabel.Render("(@"+strings.Join(r.BusFactorKeyPeople, ", @")+")"),
+			)
+		}
 	}
 
 	// Licenses
@@ -165,7 +213,44 @@ func WriteStats(w io.Writer, r StatsReport) {
 		}
 	}
 
+	// Supply chain
+	if len(r.Suspicious) > 0 {
+		fmt.Fprintln(w)
+		writeSection(w, "Supply chain")
+		parts := make([]string, len(r.Suspicious))
+		for i, s := range r.Suspicious {
+			parts[i] = fmt.Sprintf("%s (%s)", s.Package, s.Reasons)
+		}
+		fmt.Fprintf(w, "  %s suspicious: %s\n",
+			styleStatsWarn.Render(fmt.Sprintf("%d", len(r.Suspicious))),
+			styleStatsPkg.Render(strings.Join(parts, ", ")),
+		)
+	}
+
 	// Load-bearing
+	if len(r.Weights) > 0 {
+		fmt.Fprintln(w)
+		writeSection(w, "Direct dependency weight (what cutting each one would save)")
+		fmt.Fprintf(w, "  %-24s %8s %7s %10s\n",
+			styleStatsLabel.Render("package"),
+			styleStatsLabel.Render("pulls in"),
+			styleStatsLabel.Render("share"),
+			styleStatsLabel.Render("only via"),
+		)
+		limit := min(len(r.Weights), 10)
+		for _, e := range r.Weights[:limit] {
+			fmt.Fprintf(w, "  %-24s %8s %7s %10s\n",
+				styleStatsPkg.Render(e.Package),
+				styleStatsNum.Render(fmt.Sprintf("%d", e.Transitive)),
+				styleStatsNum.Render(fmt.Sprintf("%.0f%%", e.Share*100)),
+				styleStatsLabel.Render(fmt.Sprintf("%d (%.0f%%)", e.Exclusive, e.ExclusiveShare*100)),
+			)
+		}
+		if len(r.Weights) > limit {
+			fmt.Fprintf(w, "  %s\n", StyleDim.Render(fmt.Sprintf("… and %d more", len(r.Weights)-limit)))
+		}
+	}
+
 	if len(r.LoadBearing) > 0 {
 		fmt.Fprintln(w)
 		writeSection(w, "Top load-bearing packages (most reverse dependencies)")
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// warnings collects the warnings reported during a run, in order and
+// without duplicates, for the summary printed at the end.
+var (
+	warningsMu sync.Mutex
+	warnings   []string
+)
+
+func recordWarning(msg string) {
+	msg = strings.TrimSpace(ansi.Strip(msg))
+	if msg == "" {
+		return
+	}
+	warningsMu.Lock()
+	defer warningsMu.Unlock()
+	if !slices.Contains(warnings, msg) {
+		warnings = append(warnings, msg)
+	}
+}
+
+// Warnings returns the warnings reported so far: those printed with
+// [PrintWarning] and those logged at warn level through a [WarningTap].
+func Warnings() []string {
+	warningsMu.Lock()
+	defer warningsMu.Unlock()
+	return slices.Clone(warnings)
+}
+
+// ResetWarnings forgets the warnings reported so far.
+func ResetWarnings() {
+	warningsMu.Lock()
+	defer warningsMu.Unlock()
+	warnings = nil
+}
+
+// PrintWarningsSummary prints the warnings of the run as one block, so
+// they are not lost among progress output. In JSON mode it writes a
+// "summary" event instead, with the warnings and the exit code, whether
+// or not there were any. Never suppressed.
+func PrintWarningsSummary(exitCode int) {
+	ws := Warnings()
+	if jsonMode {
+		if ws == nil {
+			ws = []string{}
+		}
+		Event("summary", map[string]any{"warnings": ws, "exit_code": exitCode})
+		return
+	}
+	if len(ws) == 0 {
+		return
+	}
+	noun := "warnings"
+	if len(ws) == 1 {
+		noun = "warning"
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, StyleIconWarning.Render(IconWarning)+" "+StyleWarning.Render(fmt.Sprintf("%d %s", len(ws), noun)))
+	for _, w := range ws {
+		fmt.Fprintln(os.Stderr, "  "+StyleDim.Render("•")+" "+w)
+	}
+}
+
+// WarningTap passes log output through to w, recording the entries
+// logged at warn level for [PrintWarningsSummary].
+type WarningTap struct {
+	w io.Writer
+}
+
+// NewWarningTap creates a WarningTap writing to w.
+func NewWarningTap(w io.Writer) *WarningTap {
+	return &WarningTap{w: w}
+}
+
+func (t *WarningTap) Write(p []byte) (int, error) {
+	if msg, ok := logWarning(p); ok {
+		recordWarning(msg)
+	}
+	return t.w.Write(p)
+}
+
+// logWarning returns the message of a warn-level log entry, written by
+// the text formatter ("15:04:05.00 WARN msg key=value") or the JSON one.
+func logWarning(p []byte) (string, bool) {
+	line := strings.TrimSpace(ansi.Strip(string(p)))
+	if strings.HasPrefix(line, "{") {
+		var entry map[string]any
+		if json.Unmarshal([]byte(line), &entry) != nil || entry["level"] != "warn" {
+			return "", false
+		}
+		msg := fmt.Sprint(entry["msg"])
+		if err, ok := entry["err"]; ok {
+			msg += fmt.Sprintf(": %v", err)
+		}
+		return msg, true
+	}
+	fields := strings.Fields(line)
+	for i := 0; i < len(fields) && i < 2; i++ {
+		if fields[i] == "WARN" {
+			_, msg, _ := strings.Cut(line, "WARN ")
+			return msg, true
+		}
+	}
+	return "", false
+}
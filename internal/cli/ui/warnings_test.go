@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/charmbracelet/log"
+)
+
+func TestWarningsCollected(t *testing.T) {
+	events := captureEvents(t)
+	ResetWarnings()
+	t.Cleanup(ResetWarnings)
+
+	PrintWarning("Graph has no contributor data")
+	PrintWarning("Graph has no contributor data")
+
+	text := log.NewWithOptions(NewWarningTap(io.Discard), log.Options{ReportTimestamp: true, TimeFormat: "15:04:05.00"})
+	text.Warn("security scan failed", "err", "timeout")
+	text.Info("not a warning")
+	jsonLog := log.NewWithOptions(NewWarningTap(io.Discard), log.Options{Formatter: log.JSONFormatter})
+	jsonLog.Warn("cache write failed", "err", "disk full")
+	jsonLog.Error("not a warning either")
+
+	want := []string{
+		"Graph has no contributor data",
+		"security scan failed err=timeout",
+		"cache write failed: disk full",
+	}
+	if got := Warnings(); !slices.Equal(got, want) {
+		t.Fatalf("Warnings() = %q, want %q", got, want)
+	}
+
+	events() // drop the warning messages
+	PrintWarningsSummary(7)
+	got := events()
+	if len(got) != 1 || got[0]["event"] != "summary" || got[0]["exit_code"] != float64(7) {
+		t.Fatalf("summary events = %v", got)
+	}
+	if ws, _ := got[0]["warnings"].([]any); len(ws) != 3 {
+		t.Errorf("summary warnings = %v, want 3", got[0]["warnings"])
+	}
+}
+
+func TestWarningsSummaryWithoutWarnings(t *testing.T) {
+	events := captureEvents(t)
+	ResetWarnings()
+
+	PrintWarningsSummary(0)
+	got := events()
+	if len(got) != 1 {
+		t.Fatalf("events = %v, want one summary", got)
+	}
+	if ws, ok := got[0]["warnings"].([]any); !ok || len(ws) != 0 {
+		t.Errorf("warnings = %v, want an empty list", got[0]["warnings"])
+	}
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacktower-io/stacktower/internal/cli/ui"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+func (c *CLI) validateCommand() *cobra.Command {
+	var (
+		format string
+		schema string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "validate [graph.json|layout.json|-]",
+		Short: "Validate a graph or layout file against its JSON Schema",
+		Long: `Check a graph or layout JSON file against the published JSON Schema and
+report every problem with its location, such as /edges/3/to. Graphs are also
+checked for duplicate node IDs and edges to unknown nodes.
+
+Use --schema to print the schema itself, for editors and other tooling.
+
+Exit codes: 0 when the file is valid, 2 when it is not.`,
+		Example: `  stacktower validate graph.json
+  stacktower validate layout.json -f json
+  stacktower validate --schema graph > graph.schema.json`,
+		Args: cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if schema != "" {
+				return printSchema(schema)
+			}
+			if len(args) == 0 {
+				return NewUserError("no file to validate", "Pass a graph or layout file, or --schema graph|layout.")
+			}
+			return c.runValidate(args[0], format)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format: text, json")
+	cmd.Flags().StringVar(&schema, "schema", "", "Print the JSON Schema instead: graph, layout")
+
+	return cmd
+}
+
+func printSchema(name string) error {
+	var data []byte
+	switch name {
+	case "graph":
+		data = graph.GraphSchema()
+	case "layout":
+		data = graph.LayoutSchema()
+	default:
+		return NewUserError(fmt.Sprintf("unknown schema %q", name), "Use --schema graph or --schema layout.")
+	}
+	_, err := os.Stdout.Write(data)
+	return err
+}
+
+func (c *CLI) runValidate(input, format string) error {
+	var src io.Reader = os.Stdin
+	name := "stdin"
+	if input != "-" {
+		name = input
+		f, err := os.Open(input)
+		if err != nil {
+			return WrapUserError(err, "failed to read file", "Check that the file path exists and is readable.")
+		}
+		defer f.Close()
+		src = f
+	}
+	r, err := graph.Decompress(src)
+	if err != nil {
+		return WrapUserError(err, "failed to read file", "")
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return WrapSystemError(err, "failed to read file", "")
+	}
+
+	var problems graph.ValidationErrors
+	if err := graph.Validate(data); err != nil && !errors.As(err, &problems) {
+		return WrapSystemError(err, "failed to validate", "")
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(struct {
+			Valid  bool                   `json:"valid"`
+			Errors graph.ValidationErrors `json:"errors"`
+		}{len(problems) == 0, problems}); err != nil {
+			return WrapSystemError(err, "failed to write JSON output", "")
+		}
+	} else if len(problems) == 0 {
+		ui.PrintSuccess("%s is valid", name)
+	} else {
+		for _, p := range problems {
+			ui.PrintError("%s", p.Error())
+		}
+	}
+
+	switch len(problems) {
+	case 0:
+		return nil
+	case 1:
+		return NewUserError(fmt.Sprintf("%s: 1 problem found", name), "")
+	default:
+		return NewUserError(fmt.Sprintf("%s: %d problems found", name, len(problems)), "")
+	}
+}
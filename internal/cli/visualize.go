@@ -18,6 +18,8 @@ func (c *CLI) visualizeCommand() *cobra.Command {
 		formatsStr string
 		output     string
 		noCache    bool
+		popupTmpl  string
+		themeFile  string
 	)
 	opts := pipeline.Options{}
 	setCLIDefaults(&opts)
@@ -43,6 +45,31 @@ Use 'render' as a shortcut to go directly from graph.json to visual output.`,
 			if err := pipeline.ValidateStyle(opts.Style); err != nil {
 				return err
 			}
+			if err := pipeline.ValidateEdgeRouting(opts.EdgeRouting); err != nil {
+				return err
+			}
+			if err := pipeline.ValidateEngine(opts.Engine); err != nil {
+				return err
+			}
+			if err := pipeline.ValidateWeight(opts.Weight); err != nil {
+				return err
+			}
+			if err := pipeline.ValidateColorBy(opts.ColorBy); err != nil {
+				return err
+			}
+			if err := pipeline.ValidatePalette(opts.Palette); err != nil {
+				return err
+			}
+			tmpl, err := loadPopupTemplate(popupTmpl)
+			if err != nil {
+				return err
+			}
+			opts.PopupTemplate = tmpl
+			theme, err := loadThemeFile(themeFile)
+			if err != nil {
+				return err
+			}
+			opts.Theme = theme
 			return c.runVisualize(cmd.Context(), args[0], opts, output, noCache)
 		},
 	}
@@ -52,14 +79,32 @@ Use 'render' as a shortcut to go directly from graph.json to visual output.`,
 	cmd.Flags().BoolVar(&noCache, "no-cache", false, "disable caching")
 
 	// Render flags
-	cmd.Flags().StringVar(&opts.Style, "style", opts.Style, "visual style: handdrawn (default), simple")
+	cmd.Flags().StringVar(&opts.Style, "style", opts.Style, styleUsage())
 	cmd.Flags().BoolVar(&opts.ShowEdges, "edges", opts.ShowEdges, "show dependency edges (tower)")
+	cmd.Flags().StringVar(&opts.EdgeRouting, "edge-routing", opts.EdgeRouting, "edge routing: straight (default), orthogonal, curved (tower)")
+	cmd.Flags().StringVar(&opts.Engine, "engine", opts.Engine, "layout engine: dot (default, Graphviz) or layered (pure Go, no Graphviz) (nodelink)")
+	cmd.Flags().IntVar(&opts.Rings, "rings", opts.Rings, "dependency levels drawn around the center, 0 for all (sunburst)")
+	cmd.Flags().StringVar(&opts.Weight, "weight", opts.Weight, "what area encodes: deps (default), downloads, size (treemap)")
+	cmd.Flags().IntVar(&opts.EdgeBundling, "edge-bundle", opts.EdgeBundling, "bundle edges of packages with at least N dependencies (tower, 0 = off)")
+	cmd.Flags().StringVar(&opts.ColorBy, "color-by", opts.ColorBy, "fill blocks by metadata: license, language, owner, vuln, staleness, freshness (tower)")
+	cmd.Flags().IntVar(&opts.StaleAfterDays, "stale-after", opts.StaleAfterDays, "days without commits before a small package counts as brittle (default 365)")
+	cmd.Flags().IntVar(&opts.AbandonedAfterDays, "abandoned-after", opts.AbandonedAfterDays, "days without commits before any package counts as brittle (default 730)")
+	cmd.Flags().StringVar(&opts.Palette, "palette", opts.Palette, "colours for --color-by and isometric: okabe-ito, viridis, pastel, or #hex,#hex,...")
 	cmd.Flags().BoolVar(&opts.Popups, "popups", opts.Popups, "show hover popups with metadata")
-	cmd.Flags().StringVarP(&formatsStr, "format", "f", "", "output format(s): svg (default), pdf, png (comma-separated)")
+	cmd.Flags().BoolVar(&opts.Icons, "icons", opts.Icons, "draw package icons on blocks (tower; needs a graph parsed with --icons)")
+	cmd.Flags().StringSliceVar(&opts.PopupFields, "popup-field", opts.PopupFields, "extra metadata keys to show in popups (repeatable)")
+	cmd.Flags().StringVar(&popupTmpl, "popup-template", "", "file with a Go text/template for the popup body")
+	cmd.Flags().StringVar(&themeFile, "theme-file", "", "stacktower-theme.yaml/.json defining colors, fonts, strokes, and textures (overrides --style)")
+	cmd.Flags().StringSliceVar(&opts.Highlight, "highlight", opts.Highlight, "emphasize these packages and dim the rest (tower, comma-separated)")
+	cmd.Flags().BoolVar(&opts.Footer, "footer", opts.Footer, "stamp a provenance footer: project, resolve time, version, package counts")
+	cmd.Flags().StringVar(&opts.FooterNote, "footer-note", opts.FooterNote, "extra text for the footer, e.g. a commit SHA")
+	cmd.Flags().StringVar(&opts.Branding, "branding", opts.Branding, "replace the stacktower.io watermark with custom text")
+	cmd.Flags().StringVarP(&formatsStr, "format", "f", "", "output format(s): svg (default), pdf, png, pptx, html (tower, nodelink) (comma-separated)")
 
 	// Security flags
 	cmd.Flags().BoolVar(&opts.ShowVulns, "show-vulns", opts.ShowVulns, "show vulnerability severity colours (requires scanned graph)")
 	cmd.Flags().BoolVar(&opts.ShowLicenses, "show-licenses", opts.ShowLicenses, "show license compliance indicators (copyleft/unknown borders)")
+	cmd.Flags().BoolVar(&opts.Suspicious, "suspicious", opts.Suspicious, "mark likely typosquats, very young packages and repo/name mismatches")
 	cmd.Flags().BoolVar(&opts.FlagsOnTop, "flags-on-top", opts.FlagsOnTop, "render security flags on top of all blocks")
 
 	return cmd
@@ -87,7 +132,7 @@ func (c *CLI) runVisualize(ctx context.Context, input string, opts pipeline.Opti
 	}
 	defer runner.Close()
 
-	opts.Logger = c.Logger
+	opts.Logger = structuredLogger(c.Logger)
 	if opts.Style == "" && layout.Style != "" {
 		opts.Style = layout.Style
 	}
@@ -71,13 +71,22 @@ type Keyer interface {
 // Different options produce different graphs, so they're part of the cache key.
 // Note: Normalization is applied during layout, not parsing, so it's not here.
 type GraphKeyOpts struct {
-	MaxDepth          int    `json:"max_depth"`
-	MaxNodes          int    `json:"max_nodes"`
-	Enriched          bool   `json:"enriched,omitempty"`           // Whether GitHub metadata enrichment was performed
-	SecurityScan      bool   `json:"security_scan,omitempty"`      // Whether vulnerability scan data is included
-	IncludePrerelease bool   `json:"include_prerelease,omitempty"` // Whether prerelease versions were included
-	DependencyScope   string `json:"dependency_scope,omitempty"`   // Whether graph includes prod-only or all dependency groups
-	RuntimeVersion    string `json:"runtime_version,omitempty"`    // Target runtime version for marker evaluation (e.g., "3.11" for Python)
+	MaxDepth            int    `json:"max_depth"`
+	MaxNodes            int    `json:"max_nodes"`
+	Enriched            bool   `json:"enriched,omitempty"`              // Whether GitHub metadata enrichment was performed
+	SecurityScan        bool   `json:"security_scan,omitempty"`         // Whether vulnerability scan data is included
+	IncludePrerelease   bool   `json:"include_prerelease,omitempty"`    // Whether prerelease versions were included
+	DependencyScope     string `json:"dependency_scope,omitempty"`      // Whether graph includes prod-only or all dependency groups
+	RuntimeVersion      string `json:"runtime_version,omitempty"`       // Target runtime version for marker evaluation (e.g., "3.11" for Python)
+	Platform            string `json:"platform,omitempty"`              // Target platform for marker evaluation (e.g., "windows")
+	IncludeIndirect     bool   `json:"include_indirect,omitempty"`      // Whether "// indirect" go.mod requirements were followed
+	SparseIndex         bool   `json:"sparse_index,omitempty"`          // Whether crates were resolved from the sparse crates.io index
+	Icons               bool   `json:"icons,omitempty"`                 // Whether package icons were fetched and embedded
+	EnrichDepth         int    `json:"enrich_depth,omitempty"`          // Depth cap of enrichment (0 = all packages)
+	EnrichMinDependents int    `json:"enrich_min_dependents,omitempty"` // Dependent count that also qualifies for enrichment
+	LocalMetadata       bool   `json:"local_metadata,omitempty"`        // Whether vendored dependencies were enriched from disk
+	StaleAfterDays      int    `json:"stale_after_days,omitempty"`      // Brittleness threshold the health scores were computed with (0 = default)
+	AbandonedAfterDays  int    `json:"abandoned_after_days,omitempty"`  // Brittleness threshold the health scores were computed with (0 = default)
 }
 
 // LayoutKeyOpts defines parameters that affect layout computation.
@@ -86,11 +95,21 @@ type LayoutKeyOpts struct {
 	VizType   string  `json:"viz_type"`
 	Width     float64 `json:"width"`
 	Height    float64 `json:"height"`
-	Normalize bool    `json:"normalize,omitempty"` // Whether normalization was applied
+	Normalize bool    `json:"normalize,omitempty"`  // Whether normalization was applied
+	FixedRows bool    `json:"fixed_rows,omitempty"` // Whether normalization kept the input rows
 	Ordering  string  `json:"ordering,omitempty"`
 	Merge     bool    `json:"merge,omitempty"`
 	Randomize bool    `json:"randomize,omitempty"`
 	Seed      uint64  `json:"seed,omitempty"`
+	Restarts  int     `json:"restarts,omitempty"`
+	ClusterBy string  `json:"cluster_by,omitempty"`
+	ColorBy   string  `json:"color_by,omitempty"` // Nodelink only: fills are part of the DOT layout
+
+	EdgeLabels bool `json:"edge_labels,omitempty"` // Nodelink only
+	EdgeKinds  bool `json:"edge_kinds,omitempty"`  // Nodelink only
+
+	NebraskaScoring string `json:"nebraska_scoring,omitempty"` // JSON of custom Nebraska weights; empty for defaults
+	Health          string `json:"health,omitempty"`           // JSON of custom health weights; empty for defaults
 }
 
 // ArtifactKeyOpts defines parameters that affect artifact rendering.
@@ -101,23 +120,58 @@ type LayoutKeyOpts struct {
 //   - Style: Visual style (simple/handdrawn) - affects SVG structure
 //   - ShowEdges: Show dependency edges - adds <line> elements
 //   - Popups: Hover popups - adds metadata to SVG
+//   - PopupFields/PopupTemplate: Custom popup content
+//   - Footer/FooterNote/Branding: Provenance footer and watermark text
 //   - Nebraska: Maintainer ranking - adds panel to visualization
 //   - Merge: Edge filtering for subdividers - affects which edges render
-//   - Normalize: Whether graph was normalized - changes node/edge count
+//   - Normalize/FixedRows: Whether and how graph was normalized - changes node/edge count
 //   - ShowVulns: Whether vulnerability colours are rendered
+//   - Suspicious: Whether supply-chain warning markers are rendered
+//   - TileWidth/TileHeight: Page size when PDF output is split into tiles
+//   - Highlight: Emphasized package IDs - dims every other block
+//   - EdgeRouting/EdgeBundling: How dependency edges are drawn
+//   - Engine: Nodelink layout engine (Graphviz or pure Go)
+//   - Rings: Sunburst depth limit
+//   - Weight: What treemap areas encode
+//   - ColorBy/Palette: Metadata field driving block fills, and their colours
+//   - Theme: Theme file contents replacing the named style
+//   - Icons: Package icons drawn on blocks
+//   - StaleAfterDays/AbandonedAfterDays: Brittleness and freshness thresholds
 //
 // When adding new render-time options to pipeline.Options, update this struct!
 type ArtifactKeyOpts struct {
-	Format       string `json:"format"`
-	Style        string `json:"style,omitempty"`
-	ShowEdges    bool   `json:"show_edges,omitempty"`
-	Popups       bool   `json:"popups,omitempty"`
-	Nebraska     bool   `json:"nebraska,omitempty"`
-	Merge        bool   `json:"merge,omitempty"`
-	Normalize    bool   `json:"normalize,omitempty"`
-	ShowVulns    bool   `json:"show_vulns,omitempty"`
-	ShowLicenses bool   `json:"show_licenses,omitempty"`
-	FlagsOnTop   bool   `json:"flags_on_top,omitempty"`
+	Format        string   `json:"format"`
+	Style         string   `json:"style,omitempty"`
+	ShowEdges     bool     `json:"show_edges,omitempty"`
+	Popups        bool     `json:"popups,omitempty"`
+	Nebraska      bool     `json:"nebraska,omitempty"`
+	Merge         bool     `json:"merge,omitempty"`
+	Normalize     bool     `json:"normalize,omitempty"`
+	FixedRows     bool     `json:"fixed_rows,omitempty"`
+	ShowVulns     bool     `json:"show_vulns,omitempty"`
+	ShowLicenses  bool     `json:"show_licenses,omitempty"`
+	Suspicious    bool     `json:"suspicious,omitempty"`
+	FlagsOnTop    bool     `json:"flags_on_top,omitempty"`
+	TileWidth     float64  `json:"tile_width,omitempty"`
+	TileHeight    float64  `json:"tile_height,omitempty"`
+	Highlight     []string `json:"highlight,omitempty"`
+	EdgeRouting   string   `json:"edge_routing,omitempty"`
+	EdgeBundling  int      `json:"edge_bundling,omitempty"`
+	Engine        string   `json:"engine,omitempty"`
+	Rings         int      `json:"rings,omitempty"`
+	Weight        string   `json:"weight,omitempty"`
+	ColorBy       string   `json:"color_by,omitempty"`
+	Palette       string   `json:"palette,omitempty"`
+	Theme         string   `json:"theme,omitempty"`
+	Icons         bool     `json:"icons,omitempty"`
+	PopupFields   []string `json:"popup_fields,omitempty"`
+	PopupTemplate string   `json:"popup_template,omitempty"`
+	Footer        bool     `json:"footer,omitempty"`
+	FooterNote    string   `json:"footer_note,omitempty"`
+	Branding      string   `json:"branding,omitempty"`
+
+	StaleAfterDays     int `json:"stale_after_days,omitempty"`
+	AbandonedAfterDays int `json:"abandoned_after_days,omitempty"`
 }
 
 // DefaultKeyer provides a simple hash-based key generation strategy.
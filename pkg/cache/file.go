@@ -10,6 +10,7 @@ import (
 
 // FileCache implements a file-based cache for CLI usage.
 // Cache entries are stored as files in a directory with metadata (expiration).
+// Expired entries are kept for [StaleRetention] and served by GetStale.
 type FileCache struct {
 	dir string
 }
@@ -31,30 +32,47 @@ type cacheEntry struct {
 
 // Get retrieves a value from the cache.
 func (c *FileCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	entry, ok, err := c.read(key)
+	if !ok || (!entry.ExpiresAt.IsZero() && time.Now().After(entry.ExpiresAt)) {
+		return nil, false, err
+	}
+	return entry.Data, true, nil
+}
+
+// GetStale retrieves a value from the cache even if it has expired.
+func (c *FileCache) GetStale(ctx context.Context, key string) ([]byte, bool, error) {
+	entry, ok, err := c.read(key)
+	if !ok {
+		return nil, false, err
+	}
+	return entry.Data, true, nil
+}
+
+// read loads the entry for key, removing it if it is invalid or has been
+// expired for longer than StaleRetention.
+func (c *FileCache) read(key string) (cacheEntry, bool, error) {
 	path := c.path(key)
 
 	data, err := os.ReadFile(path)
 	if os.IsNotExist(err) {
-		return nil, false, nil
+		return cacheEntry{}, false, nil
 	}
 	if err != nil {
-		return nil, false, err
+		return cacheEntry{}, false, err
 	}
 
 	var entry cacheEntry
 	if err := json.Unmarshal(data, &entry); err != nil {
 		// Invalid cache entry - treat as miss
 		_ = os.Remove(path)
-		return nil, false, nil
+		return cacheEntry{}, false, nil
 	}
 
-	// Check expiration
-	if !entry.ExpiresAt.IsZero() && time.Now().After(entry.ExpiresAt) {
+	if !entry.ExpiresAt.IsZero() && time.Now().After(entry.ExpiresAt.Add(StaleRetention)) {
 		_ = os.Remove(path)
-		return nil, false, nil
+		return cacheEntry{}, false, nil
 	}
-
-	return entry.Data, true, nil
+	return entry, true, nil
 }
 
 // Set stores a value in the cache.
@@ -104,5 +122,8 @@ func (c *FileCache) path(key string) string {
 	return filepath.Join(c.dir, subdir, filename)
 }
 
-// Ensure FileCache implements Cache.
-var _ Cache = (*FileCache)(nil)
+// Ensure FileCache implements Cache and StaleReader.
+var (
+	_ Cache       = (*FileCache)(nil)
+	_ StaleReader = (*FileCache)(nil)
+)
@@ -42,6 +42,15 @@ func (c *InstrumentedCache) Set(ctx context.Context, key string, data []byte, tt
 	return err
 }
 
+// GetStale forwards to the wrapped cache if it is a [StaleReader], and
+// misses otherwise.
+func (c *InstrumentedCache) GetStale(ctx context.Context, key string) ([]byte, bool, error) {
+	if sr, ok := c.inner.(StaleReader); ok {
+		return sr.GetStale(ctx, key)
+	}
+	return nil, false, nil
+}
+
 func (c *InstrumentedCache) Delete(ctx context.Context, key string) error {
 	return c.inner.Delete(ctx, key)
 }
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// StaleRetention is how long [FileCache] keeps entries after they expire,
+// so that they can stand in for fresh data while a registry is down.
+const StaleRetention = 30 * 24 * time.Hour
+
+// StaleReader is implemented by caches that keep expired entries for a
+// while. HTTP clients use it as a last-known-good fallback when refreshing
+// an entry fails.
+type StaleReader interface {
+	// GetStale retrieves a value by key whether or not it has expired.
+	// Returns (data, true, nil) on hit.
+	// Returns (nil, false, nil) on miss.
+	// Returns (nil, false, err) on error.
+	GetStale(ctx context.Context, key string) ([]byte, bool, error)
+}
+
+type staleTrackerKey struct{}
+
+// StaleTracker records whether requests made with a context were answered
+// from expired cache entries. It is safe for concurrent use.
+type StaleTracker struct {
+	used atomic.Bool
+}
+
+// TrackStale returns a context whose requests report stale answers to the
+// returned tracker.
+func TrackStale(ctx context.Context) (context.Context, *StaleTracker) {
+	t := &StaleTracker{}
+	return context.WithValue(ctx, staleTrackerKey{}, t), t
+}
+
+// Used reports whether any request was answered from an expired entry.
+func (t *StaleTracker) Used() bool { return t.used.Load() }
+
+// MarkStale records on the tracker of ctx, if it has one, that a request
+// was answered from an expired entry.
+func MarkStale(ctx context.Context) {
+	if t, ok := ctx.Value(staleTrackerKey{}).(*StaleTracker); ok {
+		t.used.Store(true)
+	}
+}
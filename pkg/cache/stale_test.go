@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFileCache_GetStale(t *testing.T) {
+	ctx := context.Background()
+	c, err := NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set(ctx, "k", []byte("old"), time.Nanosecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+
+	if _, hit, _ := c.Get(ctx, "k"); hit {
+		t.Error("Get() returned an expired entry")
+	}
+	data, hit, err := c.(StaleReader).GetStale(ctx, "k")
+	if err != nil || !hit || string(data) != "old" {
+		t.Errorf("GetStale() = %q, %v, %v; want the expired entry", data, hit, err)
+	}
+	if _, hit, _ := c.(StaleReader).GetStale(ctx, "missing"); hit {
+		t.Error("GetStale() hit a missing key")
+	}
+
+	wrapped := NewInstrumentedCache(c).(StaleReader)
+	if data, hit, _ := wrapped.GetStale(ctx, "k"); !hit || string(data) != "old" {
+		t.Errorf("InstrumentedCache.GetStale() = %q, %v", data, hit)
+	}
+}
+
+func TestStaleTracker(t *testing.T) {
+	MarkStale(context.Background()) // no tracker: nothing to record
+
+	ctx, tracker := TrackStale(context.Background())
+	if tracker.Used() {
+		t.Fatal("tracker used before any request")
+	}
+	MarkStale(ctx)
+	if !tracker.Used() {
+		t.Error("MarkStale() not recorded")
+	}
+}
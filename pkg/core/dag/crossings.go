@@ -1,25 +1,27 @@
 package dag
 
-import (
-	"maps"
-	"slices"
-)
+import "slices"
 
 // CrossingWorkspace provides reusable buffers for crossing calculations to avoid
 // repeated allocations. Create with [NewCrossingWorkspace] and reuse across multiple
-// calls to [CountCrossingsIdx]. This optimization matters when evaluating millions
+// calls to [CountCrossingsIdx] or to the workspace's own CountCrossings and
+// CountLayerCrossings methods. This optimization matters when evaluating millions
 // of candidate orderings during branch-and-bound search.
 //
 // The workspace is not safe for concurrent use - each goroutine should have its own.
 type CrossingWorkspace struct {
 	ft  []int // Fenwick tree for counting inversions
 	pos []int // Position lookup buffer
+
+	rows     []int          // sorted row keys for CountCrossings
+	lowerPos map[string]int // position lookup for the string-based methods
 }
 
 // NewCrossingWorkspace creates a workspace for counting crossings efficiently.
 // The maxWidth parameter should be the maximum number of nodes in any single row
 // across all calls that will use this workspace. Using a workspace smaller than
-// needed will cause CountCrossingsIdx to produce incorrect results.
+// needed will cause CountCrossingsIdx to produce incorrect results; the
+// string-based methods grow the workspace as needed instead.
 //
 // For typical use, set maxWidth to the size of the largest row in your graph:
 //
@@ -32,8 +34,9 @@ type CrossingWorkspace struct {
 //	ws := dag.NewCrossingWorkspace(maxWidth)
 func NewCrossingWorkspace(maxWidth int) *CrossingWorkspace {
 	return &CrossingWorkspace{
-		ft:  make([]int, maxWidth+2),
-		pos: make([]int, maxWidth+2),
+		ft:       make([]int, maxWidth+2),
+		pos:      make([]int, maxWidth+2),
+		lowerPos: make(map[string]int, maxWidth),
 	}
 }
 
@@ -52,13 +55,25 @@ func NewCrossingWorkspace(maxWidth int) *CrossingWorkspace {
 //
 // This function is typically used during optimization to evaluate candidate orderings.
 // It runs in O(R × E log V) time where R is the number of rows, E is edges per layer,
-// and V is nodes per layer.
+// and V is nodes per layer. Loops that evaluate many orderings should use
+// [CrossingWorkspace.CountCrossings], which does not allocate once warmed up.
 func CountCrossings(g *DAG, orders map[int][]string) int {
-	rows := slices.Sorted(maps.Keys(orders))
+	return NewCrossingWorkspace(0).CountCrossings(g, orders)
+}
+
+// CountCrossings is like the package-level [CountCrossings] but reuses the
+// workspace's buffers.
+func (ws *CrossingWorkspace) CountCrossings(g *DAG, orders map[int][]string) int {
+	ws.rows = ws.rows[:0]
+	for r := range orders {
+		ws.rows = append(ws.rows, r)
+	}
+	slices.Sort(ws.rows)
+
 	crossings := 0
-	for i := 0; i < len(rows)-1; i++ {
-		r := rows[i]
-		crossings += CountLayerCrossings(g, orders[r], orders[r+1])
+	for i := 0; i < len(ws.rows)-1; i++ {
+		r := ws.rows[i]
+		crossings += ws.CountLayerCrossings(g, orders[r], orders[r+1])
 	}
 	return crossings
 }
@@ -77,49 +92,56 @@ func CountCrossings(g *DAG, orders map[int][]string) int {
 //
 // Returns 0 if either row is empty or nil, as no crossings can exist without edges.
 func CountLayerCrossings(g *DAG, upper, lower []string) int {
+	return NewCrossingWorkspace(len(lower)).CountLayerCrossings(g, upper, lower)
+}
+
+// CountLayerCrossings is like the package-level [CountLayerCrossings] but
+// reuses the workspace's buffers, growing them when lower is wider than the
+// workspace.
+func (ws *CrossingWorkspace) CountLayerCrossings(g *DAG, upper, lower []string) int {
 	if len(upper) == 0 || len(lower) == 0 {
 		return 0
 	}
-
-	lowerPos := PosMap(lower)
-
-	type edge struct{ upper, lower int }
-	edges := make([]edge, 0, len(upper)*2)
-	for i, nodeID := range upper {
-		for _, child := range g.Children(nodeID) {
-			if pos, ok := lowerPos[child]; ok {
-				edges = append(edges, edge{i, pos})
-			}
-		}
+	if len(ws.ft) < len(lower)+1 {
+		ws.ft = make([]int, len(lower)+2)
 	}
-	if len(edges) < 2 {
-		return 0
+	if ws.lowerPos == nil {
+		ws.lowerPos = make(map[string]int, len(lower))
 	}
 
-	// Sort edges by source position, then by target position
-	slices.SortFunc(edges, func(a, b edge) int {
-		if a.upper != b.upper {
-			return a.upper - b.upper
-		}
-		return a.lower - b.lower
-	})
+	clear(ws.lowerPos)
+	for i, id := range lower {
+		ws.lowerPos[id] = i
+	}
+	limit := len(lower) + 1
+	clear(ws.ft[:limit])
 
-	// Count inversions using Fenwick tree
-	fenwick := make([]int, len(lower)+1)
+	// Edges leave the upper row in position order. All edges of one source
+	// are queried before any is added, so edges sharing a source never
+	// count as crossing each other.
 	crossings, total := 0, 0
-	for _, e := range edges {
-		// Query: count edges seen so far with target <= e.lower
-		lessOrEqual := 0
-		for q := e.lower + 1; q > 0; q -= q & (-q) {
-			lessOrEqual += fenwick[q]
+	for _, nodeID := range upper {
+		children := g.Children(nodeID)
+		for _, child := range children {
+			pos, ok := ws.lowerPos[child]
+			if !ok {
+				continue
+			}
+			lessOrEqual := 0
+			for q := pos + 1; q > 0; q -= q & (-q) {
+				lessOrEqual += ws.ft[q]
+			}
+			crossings += total - lessOrEqual
 		}
-		// Crossings = edges seen so far with target > e.lower
-		crossings += total - lessOrEqual
-
-		// Update: increment count at target position
-		total++
-		for idx := e.lower + 1; idx < len(fenwick); idx += idx & (-idx) {
-			fenwick[idx]++
+		for _, child := range children {
+			pos, ok := ws.lowerPos[child]
+			if !ok {
+				continue
+			}
+			total++
+			for idx := pos + 1; idx < limit; idx += idx & (-idx) {
+				ws.ft[idx]++
+			}
 		}
 	}
 	return crossings
@@ -227,3 +249,40 @@ func CountPairCrossingsWithPos(g *DAG, left, right string, adjPos map[string]int
 	}
 	return crossings
 }
+
+// PairCrossings counts the crossings between the edges of two nodes u and v
+// of the same row to an adjacent row, once with u left of v (uv) and once
+// with v left of u (vu). uAdj and vAdj are the positions of their neighbors
+// in the adjacent row and must be sorted in ascending order. Edges meeting
+// at a shared neighbor do not cross.
+//
+// It merges the two lists in O(len(uAdj) + len(vAdj)) time without
+// allocating.
+func PairCrossings(uAdj, vAdj []int) (uv, vu int) {
+	lt, le := 0, 0 // vAdj[:lt] < a, vAdj[:le] <= a
+	for _, a := range uAdj {
+		for lt < len(vAdj) && vAdj[lt] < a {
+			lt++
+		}
+		for le < len(vAdj) && vAdj[le] <= a {
+			le++
+		}
+		uv += lt
+		vu += len(vAdj) - le
+	}
+	return uv, vu
+}
+
+// SwapDelta returns how the crossings between a row and one adjacent row
+// change when two neighboring nodes of the row, left and right, trade
+// places. leftAdj and rightAdj are the sorted positions of their neighbors
+// in the adjacent row, as for [PairCrossings]. A negative delta means the
+// swap removes crossings.
+//
+// Only the edges of the two nodes change their relative order, so local
+// search can evaluate a swap in O(degree) instead of recounting the layer
+// with [CountLayerCrossings] in O(E log V).
+func SwapDelta(leftAdj, rightAdj []int) int {
+	before, after := PairCrossings(leftAdj, rightAdj)
+	return after - before
+}
@@ -1,6 +1,11 @@
 package dag
 
-import "testing"
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
 
 func TestCountLayerCrossings(t *testing.T) {
 	tests := []struct {
@@ -115,6 +120,89 @@ func TestCountCrossings(t *testing.T) {
 	}
 }
 
+func TestCrossingWorkspace_CountCrossings(t *testing.T) {
+	g, orders := randomLayers(rand.New(rand.NewPCG(1, 2)), 4, 12, 0.3)
+	want := CountCrossings(g, orders)
+
+	ws := NewCrossingWorkspace(0) // grows on first use
+	if got := ws.CountCrossings(g, orders); got != want {
+		t.Fatalf("ws.CountCrossings() = %d, want %d", got, want)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		if got := ws.CountCrossings(g, orders); got != want {
+			t.Fatalf("ws.CountCrossings() = %d on reuse, want %d", got, want)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("ws.CountCrossings() allocated %.0f times per call, want 0", allocs)
+	}
+}
+
+func TestSwapDelta(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	for trial := range 200 {
+		g, orders := randomLayers(rng, 2, 8, 0.4)
+		upper, lower := orders[0], orders[1]
+		lowerPos := PosMap(lower)
+		i := rng.IntN(len(upper) - 1)
+
+		adj := func(id string) []int {
+			var pos []int
+			for _, c := range g.Children(id) {
+				pos = append(pos, lowerPos[c])
+			}
+			slices.Sort(pos)
+			return pos
+		}
+
+		before := CountLayerCrossings(g, upper, lower)
+		swapped := slices.Clone(upper)
+		swapped[i], swapped[i+1] = swapped[i+1], swapped[i]
+		after := CountLayerCrossings(g, swapped, lower)
+
+		if got := SwapDelta(adj(upper[i]), adj(upper[i+1])); got != after-before {
+			t.Fatalf("trial %d: SwapDelta() = %d, want %d", trial, got, after-before)
+		}
+	}
+}
+
+func TestPairCrossings(t *testing.T) {
+	// u reaches 1 and 3, v reaches 0, 1 and 2. With u left of v, (u→1, v→0),
+	// (u→3, v→0), (u→3, v→1) and (u→3, v→2) cross; the shared 1 does not.
+	uv, vu := PairCrossings([]int{1, 3}, []int{0, 1, 2})
+	if uv != 4 || vu != 1 {
+		t.Errorf("PairCrossings() = %d, %d, want 4, 1", uv, vu)
+	}
+	if uv, vu := PairCrossings(nil, []int{0, 1}); uv != 0 || vu != 0 {
+		t.Errorf("PairCrossings(nil, ...) = %d, %d, want 0, 0", uv, vu)
+	}
+}
+
+// randomLayers builds rows of width nodes with each edge between
+// consecutive rows present with probability p, and returns the graph with
+// its rows in insertion order.
+func randomLayers(rng *rand.Rand, rows, width int, p float64) (*DAG, map[int][]string) {
+	g := New(nil)
+	orders := make(map[int][]string, rows)
+	for r := range rows {
+		for i := range width {
+			id := fmt.Sprintf("r%dn%d", r, i)
+			g.AddNode(Node{ID: id, Row: r})
+			orders[r] = append(orders[r], id)
+		}
+	}
+	for r := range rows - 1 {
+		for _, from := range orders[r] {
+			for _, to := range orders[r+1] {
+				if rng.Float64() < p {
+					g.AddEdge(Edge{From: from, To: to})
+				}
+			}
+		}
+	}
+	return g, orders
+}
+
 func TestCountPairCrossings(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -3,7 +3,9 @@ package dag
 import (
 	"errors"
 	"maps"
+	"math"
 	"slices"
+	"sync"
 )
 
 var (
@@ -107,22 +109,63 @@ type Edge struct {
 	Meta Metadata // Arbitrary key-value metadata (never nil after AddEdge)
 }
 
+// Well-known edge metadata keys. Parsers, importers, transforms and
+// renderers agree on these; other keys are carried along uninterpreted.
+const (
+	EdgeMetaKind       = "kind"       // Dependency kind such as "dev", "optional" or "peer"; runtime when absent
+	EdgeMetaWeight     = "weight"     // Positive number; ordering pulls the ends of heavier edges closer
+	EdgeMetaConstraint = "constraint" // Version requirement, such as "^4.17.0"
+	EdgeMetaLabel      = "label"      // Display text, shown instead of the constraint
+)
+
+// Weight returns the edge's [EdgeMetaWeight], or 1 when it is absent or
+// not a positive number.
+func (e Edge) Weight() float64 {
+	var w float64
+	switch v := e.Meta[EdgeMetaWeight].(type) {
+	case float64:
+		w = v
+	case int:
+		w = float64(v)
+	case int64:
+		w = float64(v)
+	}
+	if w > 0 && !math.IsInf(w, 1) {
+		return w
+	}
+	return 1
+}
+
 // DAG is a directed acyclic graph optimized for row-based layered layouts.
 // Nodes are organized into horizontal rows (layers), and edges can only connect
 // nodes in consecutive rows. This structure enables efficient crossing reduction
 // algorithms for tower visualizations.
 //
 // The zero value is not usable - use New to create a valid DAG instance.
-// DAG is not safe for concurrent use without external synchronization.
+// DAG is not safe for concurrent use without external synchronization,
+// except that any number of goroutines may read it at once.
+//
+// Adjacency, rows and edges are indexed, so lookups and removals do not scan
+// the whole graph. Removed edges are compacted lazily, the next time the
+// edge list is read, which keeps long runs of mutations linear.
 type DAG struct {
 	nodes    map[string]*Node
-	edges    []Edge
+	nodeList []*Node             // nodes in insertion order
+	edges    []Edge              // insertion order, including dead slots until compacted
+	dead     []bool              // dead[i] marks edges[i] as removed
+	deadN    int                 // number of dead slots in edges
+	edgeMu   sync.Mutex          // serializes lazy compaction by concurrent readers
+	edgePos  map[edgeKey][]int   // from/to -> indices of live edges
 	outgoing map[string][]string // nodeID -> children IDs
 	incoming map[string][]string // nodeID -> parent IDs
 	rows     map[int][]*Node     // row -> nodes in that row
+	rowIDs   []int               // sorted keys of rows
 	meta     Metadata
 }
 
+// edgeKey indexes edges by their endpoints.
+type edgeKey struct{ from, to string }
+
 // New creates an empty DAG with optional graph-level metadata.
 // The metadata parameter can be nil, in which case an empty map is created.
 // Graph-level metadata is typically used to store rendering options.
@@ -132,6 +175,7 @@ func New(meta Metadata) *DAG {
 	}
 	return &DAG{
 		nodes:    make(map[string]*Node),
+		edgePos:  make(map[edgeKey][]int),
 		outgoing: make(map[string][]string),
 		incoming: make(map[string][]string),
 		rows:     make(map[int][]*Node),
@@ -161,6 +205,10 @@ func (d *DAG) AddNode(n Node) error {
 	}
 	node := &n
 	d.nodes[node.ID] = node
+	d.nodeList = append(d.nodeList, node)
+	if i, found := slices.BinarySearch(d.rowIDs, node.Row); !found {
+		d.rowIDs = slices.Insert(d.rowIDs, i, node.Row)
+	}
 	d.rows[node.Row] = append(d.rows[node.Row], node)
 	return nil
 }
@@ -173,12 +221,13 @@ func (d *DAG) AddNode(n Node) error {
 // is O(N) where N is the total number of nodes.
 func (d *DAG) SetRows(rows map[string]int) {
 	d.rows = make(map[int][]*Node)
-	for _, n := range d.nodes {
+	for _, n := range d.nodeList {
 		if newRow, ok := rows[n.ID]; ok {
 			n.Row = newRow
 		}
 		d.rows[n.Row] = append(d.rows[n.Row], n)
 	}
+	d.rowIDs = slices.Sorted(maps.Keys(d.rows))
 }
 
 // AddEdge adds a directed edge between two existing nodes.
@@ -199,7 +248,10 @@ func (d *DAG) AddEdge(e Edge) error {
 	if e.Meta == nil {
 		e.Meta = Metadata{}
 	}
+	key := edgeKey{e.From, e.To}
+	d.edgePos[key] = append(d.edgePos[key], len(d.edges))
 	d.edges = append(d.edges, e)
+	d.dead = append(d.dead, false)
 	d.outgoing[e.From] = append(d.outgoing[e.From], e.To)
 	d.incoming[e.To] = append(d.incoming[e.To], e.From)
 	return nil
@@ -207,13 +259,50 @@ func (d *DAG) AddEdge(e Edge) error {
 
 // RemoveEdge removes the edge from→to if it exists.
 // No error is returned if the edge does not exist. If multiple edges
-// exist between the same nodes, only the first is removed.
+// exist between the same nodes, all of them are removed.
+//
+// RemoveEdge runs in O(deg(from) + deg(to)); the edge list is compacted on
+// its next read.
 func (d *DAG) RemoveEdge(from, to string) {
-	d.edges = slices.DeleteFunc(d.edges, func(e Edge) bool { return e.From == from && e.To == to })
+	key := edgeKey{from, to}
+	for _, i := range d.edgePos[key] {
+		d.dead[i] = true
+		d.deadN++
+	}
+	delete(d.edgePos, key)
 	d.outgoing[from] = slices.DeleteFunc(d.outgoing[from], func(s string) bool { return s == to })
 	d.incoming[to] = slices.DeleteFunc(d.incoming[to], func(s string) bool { return s == from })
 }
 
+// compactEdges drops removed edges from the edge list and reindexes it.
+func (d *DAG) compactEdges() {
+	d.edgeMu.Lock()
+	defer d.edgeMu.Unlock()
+	if d.deadN == 0 {
+		return
+	}
+	live := d.edges[:0]
+	for i, e := range d.edges {
+		if !d.dead[i] {
+			live = append(live, e)
+		}
+	}
+	clear(d.edges[len(live):]) // release the metadata of removed edges
+	d.edges = live
+	d.dead = make([]bool, len(live))
+	d.deadN = 0
+	d.reindexEdges()
+}
+
+// reindexEdges rebuilds the endpoint index of the compacted edge list.
+func (d *DAG) reindexEdges() {
+	d.edgePos = make(map[edgeKey][]int, len(d.edges))
+	for i, e := range d.edges {
+		key := edgeKey{e.From, e.To}
+		d.edgePos[key] = append(d.edgePos[key], i)
+	}
+}
+
 // RenameNode changes a node's ID, updating all edges and indices.
 // Returns ErrInvalidNodeID if newID is empty, ErrUnknownSourceNode if
 // oldID doesn't exist, or ErrDuplicateNodeID if newID is already in use.
@@ -236,6 +325,7 @@ func (d *DAG) RenameNode(oldID, newID string) error {
 	delete(d.nodes, oldID)
 	d.nodes[newID] = node
 
+	d.compactEdges()
 	for i := range d.edges {
 		if d.edges[i].From == oldID {
 			d.edges[i].From = newID
@@ -265,34 +355,62 @@ func (d *DAG) RenameNode(oldID, newID string) error {
 		}
 	}
 
+	d.reindexEdges()
 	return nil
 }
 
-// Nodes returns all nodes in the graph.
-// The order is not guaranteed. The returned slice contains pointers to
-// the actual node structs, so modifications affect the graph.
-func (d *DAG) Nodes() []*Node {
-	nodes := make([]*Node, 0, len(d.nodes))
-	for _, n := range d.nodes {
-		nodes = append(nodes, n)
-	}
-	return nodes
-}
+// Nodes returns all nodes in the graph, in insertion order.
+// The returned slice contains pointers to the actual node structs, so
+// modifications affect the graph.
+func (d *DAG) Nodes() []*Node { return slices.Clone(d.nodeList) }
+
+// NodesIter returns the nodes in insertion order for read-only iteration
+// without copying. The returned slice must not be modified, and is only
+// valid until the next AddNode. Use Nodes() if you need a mutable copy.
+func (d *DAG) NodesIter() []*Node { return d.nodeList }
 
 // Edges returns a copy of all edges in the graph.
 // The order matches insertion order. Modifications to the returned
 // slice or its edge structs do not affect the graph.
-func (d *DAG) Edges() []Edge { return slices.Clone(d.edges) }
+func (d *DAG) Edges() []Edge {
+	d.compactEdges()
+	return slices.Clone(d.edges)
+}
 
 // EdgesIter returns the edges slice for read-only iteration without copying.
 // The returned slice must not be modified. Use Edges() if you need a mutable copy.
-func (d *DAG) EdgesIter() []Edge { return d.edges }
+func (d *DAG) EdgesIter() []Edge {
+	d.compactEdges()
+	return d.edges
+}
 
 // NodeCount returns the number of nodes in the graph.
 func (d *DAG) NodeCount() int { return len(d.nodes) }
 
 // EdgeCount returns the number of edges in the graph.
-func (d *DAG) EdgeCount() int { return len(d.edges) }
+func (d *DAG) EdgeCount() int {
+	d.edgeMu.Lock()
+	defer d.edgeMu.Unlock()
+	return len(d.edges) - d.deadN
+}
+
+// HasEdge reports whether the graph has an edge from→to, in O(1).
+func (d *DAG) HasEdge(from, to string) bool {
+	d.edgeMu.Lock()
+	defer d.edgeMu.Unlock()
+	return len(d.edgePos[edgeKey{from, to}]) > 0
+}
+
+// Edge returns the edge from→to and whether it exists, in O(1). Of
+// multiple edges between the same nodes, the first added is returned.
+func (d *DAG) Edge(from, to string) (Edge, bool) {
+	d.edgeMu.Lock()
+	defer d.edgeMu.Unlock()
+	if pos := d.edgePos[edgeKey{from, to}]; len(pos) > 0 {
+		return d.edges[pos[0]], true
+	}
+	return Edge{}, false
+}
 
 // Children returns the IDs of nodes that this node has edges to (dependencies).
 // Returns nil if the node has no children or doesn't exist. The returned slice
@@ -346,6 +464,43 @@ func (d *DAG) ParentsInRow(id string, row int) []string {
 	return result
 }
 
+// RealChildren returns the regular nodes id depends on, sorted by ID.
+// Synthetic nodes are looked through, so a dependency reached via a chain
+// of subdividers appears once, as if the edge were direct. Renderers that
+// work on the original graph structure rather than the layered one use it.
+func (d *DAG) RealChildren(id string) []string {
+	return d.realNeighbors(id, d.Children)
+}
+
+// RealParents returns the regular nodes that depend on id, sorted by ID,
+// looking through synthetic nodes like [DAG.RealChildren].
+func (d *DAG) RealParents(id string) []string {
+	return d.realNeighbors(id, d.Parents)
+}
+
+// realNeighbors walks next from id, continuing through synthetic nodes,
+// and returns the regular nodes reached.
+func (d *DAG) realNeighbors(id string, next func(string) []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	stack := slices.Clone(next(id))
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+		if n, ok := d.nodes[c]; ok && n.IsSynthetic() {
+			stack = append(stack, next(c)...)
+			continue
+		}
+		out = append(out, c)
+	}
+	slices.Sort(out)
+	return out
+}
+
 // NodesInRow returns all nodes assigned to the given row.
 // Returns nil if the row is empty or doesn't exist. The returned slice
 // contains pointers to the actual nodes, so modifications affect the graph.
@@ -360,17 +515,20 @@ func (d *DAG) RowCount() int { return len(d.rows) }
 // RowIDs returns all row indices in sorted ascending order.
 // Returns an empty slice for an empty graph. Use this to iterate
 // through rows from top to bottom.
-func (d *DAG) RowIDs() []int {
-	return slices.Sorted(maps.Keys(d.rows))
-}
+func (d *DAG) RowIDs() []int { return slices.Clone(d.RowIDsIter()) }
+
+// RowIDsIter is like RowIDs but returns the graph's cached index for
+// read-only iteration without copying or sorting. The returned slice must
+// not be modified, and is only valid until rows change.
+func (d *DAG) RowIDsIter() []int { return d.rowIDs }
 
 // MaxRow returns the highest row index, or 0 if the graph is empty.
 // For a non-empty graph, this is the bottom-most layer.
 func (d *DAG) MaxRow() int {
-	if len(d.rows) == 0 {
+	rowIDs := d.RowIDsIter()
+	if len(rowIDs) == 0 {
 		return 0
 	}
-	rowIDs := d.RowIDs()
 	return rowIDs[len(rowIDs)-1]
 }
 
@@ -379,7 +537,7 @@ func (d *DAG) MaxRow() int {
 // The order is not guaranteed. Returns nil for an empty graph.
 func (d *DAG) Sources() []*Node {
 	var sources []*Node
-	for _, n := range d.nodes {
+	for _, n := range d.nodeList {
 		if len(d.incoming[n.ID]) == 0 {
 			sources = append(sources, n)
 		}
@@ -392,7 +550,7 @@ func (d *DAG) Sources() []*Node {
 // The order is not guaranteed. Returns nil for an empty graph.
 func (d *DAG) Sinks() []*Node {
 	var sinks []*Node
-	for _, n := range d.nodes {
+	for _, n := range d.nodeList {
 		if len(d.outgoing[n.ID]) == 0 {
 			sinks = append(sinks, n)
 		}
@@ -420,7 +578,7 @@ func (d *DAG) Validate() error {
 }
 
 func (d *DAG) validateEdgeConsistency() error {
-	for _, e := range d.edges {
+	for _, e := range d.EdgesIter() {
 		src, okS := d.nodes[e.From]
 		dst, okD := d.nodes[e.To]
 		if !okS || !okD {
@@ -526,7 +684,7 @@ func (d *DAG) Clone() *DAG {
 	clone := New(meta)
 
 	// Clone all nodes with their metadata
-	for _, n := range d.nodes {
+	for _, n := range d.nodeList {
 		nodeMeta := make(Metadata, len(n.Meta))
 		for k, v := range n.Meta {
 			nodeMeta[k] = v
@@ -541,7 +699,7 @@ func (d *DAG) Clone() *DAG {
 	}
 
 	// Clone all edges with their metadata
-	for _, e := range d.edges {
+	for _, e := range d.EdgesIter() {
 		edgeMeta := make(Metadata, len(e.Meta))
 		for k, v := range e.Meta {
 			edgeMeta[k] = v
@@ -1,6 +1,10 @@
 package dag
 
-import "testing"
+import (
+	"slices"
+	"sync"
+	"testing"
+)
 
 func TestNew(t *testing.T) {
 	g := New(nil)
@@ -179,6 +183,57 @@ func TestRowIDs(t *testing.T) {
 	}
 }
 
+func TestRowIDs_Invalidation(t *testing.T) {
+	g := New(nil)
+	g.AddNode(Node{ID: "a", Row: 0})
+	g.AddNode(Node{ID: "b", Row: 1})
+	if got := g.MaxRow(); got != 1 {
+		t.Fatalf("MaxRow() = %d, want 1", got)
+	}
+
+	g.AddNode(Node{ID: "c", Row: 3})
+	if got := g.RowIDsIter(); !slices.Equal(got, []int{0, 1, 3}) {
+		t.Errorf("RowIDsIter() after AddNode = %v, want [0 1 3]", got)
+	}
+
+	g.SetRows(map[string]int{"a": 0, "b": 0, "c": 2})
+	if got := g.RowIDs(); !slices.Equal(got, []int{0, 2}) {
+		t.Errorf("RowIDs() after SetRows = %v, want [0 2]", got)
+	}
+	if got := g.MaxRow(); got != 2 {
+		t.Errorf("MaxRow() after SetRows = %d, want 2", got)
+	}
+}
+
+func TestConcurrentReads(t *testing.T) {
+	g := New(nil)
+	g.AddNode(Node{ID: "a", Row: 0})
+	g.AddNode(Node{ID: "b", Row: 1})
+	g.AddNode(Node{ID: "c", Row: 1})
+	g.AddEdge(Edge{From: "a", To: "b"})
+	g.AddEdge(Edge{From: "a", To: "c"})
+	g.RemoveEdge("a", "b") // leaves a removed edge to compact
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			if got := len(g.EdgesIter()); got != 1 {
+				t.Errorf("len(EdgesIter()) = %d, want 1", got)
+			}
+			if g.HasEdge("a", "b") || !g.HasEdge("a", "c") {
+				t.Error("HasEdge does not match the remaining edge a→c")
+			}
+			if got := g.EdgeCount(); got != 1 {
+				t.Errorf("EdgeCount() = %d, want 1", got)
+			}
+			if got := g.RowIDsIter(); !slices.Equal(got, []int{0, 1}) {
+				t.Errorf("RowIDsIter() = %v, want [0 1]", got)
+			}
+		})
+	}
+	wg.Wait()
+}
+
 func TestSources(t *testing.T) {
 	g := New(nil)
 	g.AddNode(Node{ID: "a", Row: 0})
@@ -350,6 +405,16 @@ func TestNodes(t *testing.T) {
 	}
 }
 
+func TestNodesIter_InsertionOrder(t *testing.T) {
+	g := New(nil)
+	for _, id := range []string{"c", "a", "b"} {
+		g.AddNode(Node{ID: id})
+	}
+	if got := NodeIDs(g.NodesIter()); !slices.Equal(got, []string{"c", "a", "b"}) {
+		t.Errorf("NodesIter() = %v, want [c a b]", got)
+	}
+}
+
 func TestNode(t *testing.T) {
 	g := New(nil)
 	g.AddNode(Node{ID: "test", Row: 5, Meta: Metadata{"key": "value"}})
@@ -387,6 +452,48 @@ func TestEdges(t *testing.T) {
 	}
 }
 
+func TestEdge(t *testing.T) {
+	g := New(nil)
+	g.AddNode(Node{ID: "a"})
+	g.AddNode(Node{ID: "b"})
+	g.AddNode(Node{ID: "c"})
+	g.AddEdge(Edge{From: "a", To: "b", Meta: Metadata{EdgeMetaWeight: 3.0}})
+	g.AddEdge(Edge{From: "a", To: "c"})
+	g.RemoveEdge("a", "c")
+
+	e, ok := g.Edge("a", "b")
+	if !ok || e.Weight() != 3 {
+		t.Errorf("Edge(a, b) = %+v, %v; want weight 3", e, ok)
+	}
+	if _, ok := g.Edge("a", "c"); ok {
+		t.Error("Edge(a, c) found after removal")
+	}
+}
+
+func TestEdgeWeight(t *testing.T) {
+	tests := []struct {
+		weight any
+		want   float64
+	}{
+		{nil, 1},
+		{2.5, 2.5},
+		{4, 4},
+		{int64(2), 2},
+		{0.0, 1},
+		{-3.0, 1},
+		{"heavy", 1},
+	}
+	for _, tt := range tests {
+		e := Edge{Meta: Metadata{}}
+		if tt.weight != nil {
+			e.Meta[EdgeMetaWeight] = tt.weight
+		}
+		if got := e.Weight(); got != tt.want {
+			t.Errorf("Weight() with %v = %v, want %v", tt.weight, got, tt.want)
+		}
+	}
+}
+
 func TestRemoveEdge(t *testing.T) {
 	g := New(nil)
 	g.AddNode(Node{ID: "a"})
@@ -405,6 +512,58 @@ func TestRemoveEdge(t *testing.T) {
 	}
 }
 
+func TestRemoveEdge_Duplicates(t *testing.T) {
+	g := New(nil)
+	g.AddNode(Node{ID: "a"})
+	g.AddNode(Node{ID: "b"})
+	g.AddNode(Node{ID: "c"})
+	g.AddEdge(Edge{From: "a", To: "b"})
+	g.AddEdge(Edge{From: "a", To: "c"})
+	g.AddEdge(Edge{From: "a", To: "b"})
+
+	g.RemoveEdge("a", "b")
+
+	if got := g.EdgeCount(); got != 1 {
+		t.Errorf("EdgeCount() = %d after removal, want 1", got)
+	}
+	if g.HasEdge("a", "b") {
+		t.Error("HasEdge(a, b) = true after removal")
+	}
+	edges := g.Edges()
+	if len(edges) != 1 || edges[0].To != "c" {
+		t.Errorf("Edges() = %v, want [a→c]", edges)
+	}
+
+	// Edges added after a removal are indexed against the compacted list.
+	g.AddEdge(Edge{From: "b", To: "c"})
+	g.RemoveEdge("a", "c")
+	edges = g.EdgesIter()
+	if len(edges) != 1 || edges[0].From != "b" {
+		t.Errorf("EdgesIter() = %v, want [b→c]", edges)
+	}
+}
+
+func TestRenameNode_ReindexesEdges(t *testing.T) {
+	g := New(nil)
+	g.AddNode(Node{ID: "a"})
+	g.AddNode(Node{ID: "b"})
+	g.AddEdge(Edge{From: "a", To: "b"})
+
+	if err := g.RenameNode("a", "x"); err != nil {
+		t.Fatalf("RenameNode() error = %v", err)
+	}
+	if !g.HasEdge("x", "b") || g.HasEdge("a", "b") {
+		t.Fatal("HasEdge does not follow the rename")
+	}
+	g.RemoveEdge("x", "b")
+	if got := g.EdgeCount(); got != 0 {
+		t.Errorf("EdgeCount() = %d after removal, want 0", got)
+	}
+	if err := g.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
 func TestOutDegree(t *testing.T) {
 	g := New(nil)
 	g.AddNode(Node{ID: "a"})
@@ -473,6 +632,30 @@ func TestParentsInRow(t *testing.T) {
 	}
 }
 
+func TestRealNeighbors(t *testing.T) {
+	g := New(nil)
+	g.AddNode(Node{ID: "app", Row: 0})
+	g.AddNode(Node{ID: "web", Row: 1})
+	g.AddNode(Node{ID: "app_sub_2", Row: 1, Kind: NodeKindSubdivider, MasterID: "app"})
+	g.AddNode(Node{ID: "log", Row: 2})
+	g.AddNode(Node{ID: "db", Row: 2})
+	g.AddEdge(Edge{From: "app", To: "web"})
+	g.AddEdge(Edge{From: "app", To: "app_sub_2"})
+	g.AddEdge(Edge{From: "app_sub_2", To: "log"})
+	g.AddEdge(Edge{From: "web", To: "log"})
+	g.AddEdge(Edge{From: "web", To: "db"})
+
+	if got, want := g.RealChildren("app"), []string{"log", "web"}; !slices.Equal(got, want) {
+		t.Errorf("RealChildren(app) = %v, want %v", got, want)
+	}
+	if got, want := g.RealParents("log"), []string{"app", "web"}; !slices.Equal(got, want) {
+		t.Errorf("RealParents(log) = %v, want %v", got, want)
+	}
+	if got := g.RealChildren("db"); got != nil {
+		t.Errorf("RealChildren(db) = %v, want nil", got)
+	}
+}
+
 func TestSetRows(t *testing.T) {
 	g := New(nil)
 	g.AddNode(Node{ID: "a"})
@@ -49,6 +49,8 @@
 // The [CountCrossings] and [CountLayerCrossings] functions use a Fenwick tree
 // (binary indexed tree) to count inversions in O(E log V) time, enabling
 // fast evaluation of millions of candidate orderings during optimization.
+// A [CrossingWorkspace] reuses their buffers across calls, and [SwapDelta]
+// scores swapping two neighboring nodes from their degrees alone.
 //
 // # Metadata
 //
@@ -0,0 +1,25 @@
+// Package gen generates random layered dependency graphs.
+//
+// # Overview
+//
+// [Generate] builds a DAG shaped like a resolved dependency graph: one root
+// package, rows of packages below it, and edges from each package to
+// packages in lower rows. [Options] tunes the shape:
+//
+//   - Depth and Width bound the number of rows and the packages per row
+//   - FanOut sets the average number of dependencies per package
+//   - Tangle sets the share of edges that jump to a random package of any
+//     lower row, which creates crossings and long edges
+//   - Metadata adds versions, licenses, downloads, stars and activity
+//     dates, like an enriched graph
+//
+// Generated graphs are deterministic: equal options, including Seed and
+// Now, give equal graphs. They serve benchmarks and fuzz tests that need
+// graphs of a given shape, and demo towers that need no network access:
+//
+//	g := gen.Generate(gen.Options{Depth: 6, Width: 10, Tangle: 0.3, Metadata: true, Seed: 42})
+//	graph.WriteGraphFile(g, "demo.json")
+//
+// Package names are random pronounceable words; node rows are set to the
+// generated rows, which transform.Normalize recomputes before layout.
+package gen
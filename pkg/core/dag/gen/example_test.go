@@ -0,0 +1,20 @@
+package gen_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag/gen"
+)
+
+func ExampleGenerate() {
+	g := gen.Generate(gen.Options{
+		Depth:  4,
+		Width:  6,
+		Tangle: 0.2,
+		Seed:   42,
+		Now:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	fmt.Println(len(g.Sources()), len(g.RowIDs()))
+	// Output: 1 5
+}
@@ -0,0 +1,212 @@
+package gen
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
+)
+
+// Default option values, used for zero fields of [Options].
+const (
+	DefaultDepth  = 5
+	DefaultWidth  = 8
+	DefaultFanOut = 3
+)
+
+// Options tunes a generated graph. The zero value generates a graph of the
+// default size without tangles or metadata.
+type Options struct {
+	// Depth is the number of rows below the root (default 5).
+	Depth int
+
+	// Width is the maximum number of packages per row (default 8). Each
+	// row gets between one and Width packages.
+	Width int
+
+	// FanOut is the average number of dependencies per package above the
+	// last row (default 3).
+	FanOut int
+
+	// Tangle, from 0 to 1, is the share of edges that go to a random
+	// package of any lower row instead of a package near the same position
+	// in the next row. Zero gives a tidy tower; higher values give more
+	// crossings and long edges.
+	Tangle float64
+
+	// Metadata adds version, license, download, star and activity
+	// metadata to every package.
+	Metadata bool
+
+	// Seed seeds the random generator.
+	Seed uint64
+
+	// Now is the reference time for activity dates (default: the current
+	// time). Set it for graphs that do not change from day to day.
+	Now time.Time
+}
+
+func (o Options) withDefaults() Options {
+	if o.Depth <= 0 {
+		o.Depth = DefaultDepth
+	}
+	if o.Width <= 0 {
+		o.Width = DefaultWidth
+	}
+	if o.FanOut <= 0 {
+		o.FanOut = DefaultFanOut
+	}
+	o.Tangle = min(max(o.Tangle, 0), 1)
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+	return o
+}
+
+// Generate returns a random layered DAG as opts describes. The root is the
+// only node of row 0, and every other node has at least one parent in the
+// row above it.
+func Generate(opts Options) *dag.DAG {
+	opts = opts.withDefaults()
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x9e3779b97f4a7c15))
+	names := newNamer(rng)
+
+	rows := make([][]string, opts.Depth+1)
+	rows[0] = []string{names.next()}
+	for r := 1; r <= opts.Depth; r++ {
+		rows[r] = make([]string, 1+rng.IntN(opts.Width))
+		for i := range rows[r] {
+			rows[r][i] = names.next()
+		}
+	}
+
+	g := dag.New(nil)
+	for r, row := range rows {
+		for _, id := range row {
+			n := dag.Node{ID: id, Row: r}
+			if opts.Metadata {
+				n.Meta = randomMeta(rng, opts.Now)
+			}
+			_ = g.AddNode(n)
+		}
+	}
+
+	for r := 0; r < opts.Depth; r++ {
+		for i, from := range rows[r] {
+			for range 1 + rng.IntN(2*opts.FanOut-1) {
+				var to string
+				if rng.Float64() < opts.Tangle {
+					lower := rows[r+1+rng.IntN(opts.Depth-r)]
+					to = lower[rng.IntN(len(lower))]
+				} else {
+					to = rows[r+1][nearby(rng, i, len(rows[r]), len(rows[r+1]))]
+				}
+				addEdge(g, from, to)
+			}
+		}
+		// Give packages nothing depends on a parent in the row above.
+		for i, to := range rows[r+1] {
+			if g.InDegree(to) == 0 {
+				addEdge(g, rows[r][nearby(rng, i, len(rows[r+1]), len(rows[r]))], to)
+			}
+		}
+	}
+	return g
+}
+
+// nearby returns a random index of a row of width n close to the position
+// of index i in a row of width m.
+func nearby(rng *rand.Rand, i, m, n int) int {
+	center := (2*i + 1) * n / (2 * m)
+	return min(max(center+rng.IntN(3)-1, 0), n-1)
+}
+
+func addEdge(g *dag.DAG, from, to string) {
+	for _, c := range g.Children(from) {
+		if c == to {
+			return
+		}
+	}
+	_ = g.AddEdge(dag.Edge{From: from, To: to})
+}
+
+// licenses are drawn with the weights of their share in package registries.
+var licenses = []struct {
+	id     string
+	weight int
+}{
+	{"MIT", 50},
+	{"Apache-2.0", 25},
+	{"BSD-3-Clause", 10},
+	{"ISC", 5},
+	{"MPL-2.0", 3},
+	{"LGPL-3.0", 3},
+	{"GPL-3.0", 2},
+	{"", 2},
+}
+
+// randomMeta returns metadata like that of an enriched package, with
+// activity dates up to five years before now.
+func randomMeta(rng *rand.Rand, now time.Time) dag.Metadata {
+	meta := dag.Metadata{
+		"version":             fmt.Sprintf("%d.%d.%d", rng.IntN(4), rng.IntN(20), rng.IntN(10)),
+		"downloads":           logUniform(rng, 1e6),
+		metadata.RepoStars:    logUniform(rng, 1e5),
+		metadata.RepoArchived: rng.IntN(25) == 0,
+	}
+	pick := rng.IntN(100)
+	for _, l := range licenses {
+		if pick -= l.weight; pick < 0 {
+			if l.id != "" {
+				meta["license"] = l.id
+			}
+			break
+		}
+	}
+	commit := now.AddDate(0, 0, -rng.IntN(5*365))
+	meta[metadata.RepoLastCommit] = commit.Format(time.DateOnly)
+	meta[metadata.RepoLastRelease] = commit.AddDate(0, 0, -rng.IntN(365)).Format(time.DateOnly)
+	return meta
+}
+
+// logUniform returns an integer between 1 and limit whose logarithm is
+// uniformly distributed, like popularity counts.
+func logUniform(rng *rand.Rand, limit float64) int {
+	return int(math.Pow(limit, rng.Float64()))
+}
+
+// namer hands out unique pronounceable package names.
+type namer struct {
+	rng  *rand.Rand
+	used map[string]bool
+}
+
+func newNamer(rng *rand.Rand) *namer {
+	return &namer{rng: rng, used: make(map[string]bool)}
+}
+
+const (
+	consonants = "bdfgklmnprstvz"
+	vowels     = "aeiou"
+)
+
+var suffixes = []string{"", "", "", "-core", "-utils", "-io", "-cli", "js", "py"}
+
+func (n *namer) next() string {
+	for {
+		var b strings.Builder
+		for range 2 + n.rng.IntN(2) {
+			b.WriteByte(consonants[n.rng.IntN(len(consonants))])
+			b.WriteByte(vowels[n.rng.IntN(len(vowels))])
+		}
+		b.WriteString(suffixes[n.rng.IntN(len(suffixes))])
+		if name := b.String(); !n.used[name] {
+			n.used[name] = true
+			return name
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package gen
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+var now = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func TestGenerate_Shape(t *testing.T) {
+	opts := Options{Depth: 6, Width: 5, FanOut: 2, Tangle: 0.3, Seed: 7, Now: now}
+	g := Generate(opts)
+
+	if sources := g.Sources(); len(sources) != 1 || sources[0].Row != 0 {
+		t.Fatalf("sources = %v, want a single root in row 0", dag.NodeIDs(sources))
+	}
+	if rows := g.RowIDs(); len(rows) != opts.Depth+1 {
+		t.Errorf("rows = %v, want %d", rows, opts.Depth+1)
+	}
+	for _, r := range g.RowIDs() {
+		if n := len(g.NodesInRow(r)); n > opts.Width {
+			t.Errorf("row %d has %d nodes, more than Width %d", r, n, opts.Width)
+		}
+	}
+	for _, n := range g.Nodes() {
+		for _, c := range g.Children(n.ID) {
+			child, _ := g.Node(c)
+			if child.Row <= n.Row {
+				t.Errorf("edge %s→%s does not go down: rows %d→%d", n.ID, c, n.Row, child.Row)
+			}
+		}
+		if n.Row == 0 {
+			continue
+		}
+		hasParentAbove := false
+		for _, p := range g.Parents(n.ID) {
+			if parent, _ := g.Node(p); parent.Row == n.Row-1 {
+				hasParentAbove = true
+			}
+		}
+		if !hasParentAbove {
+			t.Errorf("%s in row %d has no parent in the row above", n.ID, n.Row)
+		}
+	}
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	opts := Options{Tangle: 0.5, Metadata: true, Seed: 3, Now: now}
+	a, b := Generate(opts), Generate(opts)
+	if !reflect.DeepEqual(dag.NodeIDs(a.Nodes()), dag.NodeIDs(b.Nodes())) || !reflect.DeepEqual(a.Edges(), b.Edges()) {
+		t.Fatal("equal options should generate equal graphs")
+	}
+	for _, n := range a.Nodes() {
+		m, _ := b.Node(n.ID)
+		if !reflect.DeepEqual(n.Meta, m.Meta) {
+			t.Fatalf("metadata of %s differs: %v vs %v", n.ID, n.Meta, m.Meta)
+		}
+	}
+
+	opts.Seed = 4
+	if c := Generate(opts); reflect.DeepEqual(dag.NodeIDs(a.Nodes()), dag.NodeIDs(c.Nodes())) {
+		t.Error("different seeds should generate different graphs")
+	}
+}
+
+func TestGenerate_Tangle(t *testing.T) {
+	longEdges := func(g *dag.DAG) int {
+		count := 0
+		for _, e := range g.Edges() {
+			from, _ := g.Node(e.From)
+			to, _ := g.Node(e.To)
+			if to.Row-from.Row > 1 {
+				count++
+			}
+		}
+		return count
+	}
+	if n := longEdges(Generate(Options{Seed: 1, Now: now})); n != 0 {
+		t.Errorf("untangled graph has %d long edges, want 0", n)
+	}
+	if n := longEdges(Generate(Options{Tangle: 1, Seed: 1, Now: now})); n == 0 {
+		t.Error("fully tangled graph should have long edges")
+	}
+}
+
+func TestGenerate_Metadata(t *testing.T) {
+	for _, n := range Generate(Options{Seed: 5, Now: now}).Nodes() {
+		if len(n.Meta) != 0 {
+			t.Fatalf("%s has metadata without Options.Metadata: %v", n.ID, n.Meta)
+		}
+	}
+	for _, n := range Generate(Options{Metadata: true, Seed: 5, Now: now}).Nodes() {
+		if _, ok := n.Meta["version"].(string); !ok {
+			t.Errorf("%s has no version: %v", n.ID, n.Meta)
+		}
+		commit, err := time.Parse(time.DateOnly, n.Meta["repo_last_commit"].(string))
+		if err != nil || commit.After(now) || commit.Before(now.AddDate(-5, 0, -1)) {
+			t.Errorf("%s last commit %v out of range", n.ID, n.Meta["repo_last_commit"])
+		}
+	}
+}
@@ -43,13 +43,12 @@ const (
 )
 
 type pqNode struct {
-	kind         nodeKind
-	value        int
-	children     []*pqNode
-	parent       *pqNode
-	mark         markKind
-	fullCount    int
-	partialCount int
+	kind      nodeKind
+	value     int
+	children  []*pqNode
+	parent    *pqNode
+	mark      markKind
+	pertinent int // Constrained leaves below the node, during Reduce
 }
 
 // NewPQTree creates a PQ-tree representing all n! permutations of n elements.
@@ -100,11 +99,11 @@ func NewPQTree(n int) *PQTree {
 // further.
 //
 // The constraint slice is not modified. Element indices must be in the range
-// [0, n-1] where n is the value passed to NewPQTree. Out-of-range indices are
-// silently ignored.
+// [0, n-1] where n is the value passed to NewPQTree. Out-of-range and
+// repeated indices are silently ignored.
 //
-// Trivial constraints (length 0, 1, or equal to tree size) are always satisfiable
-// and have no effect on the tree structure.
+// Trivial constraints (zero or one distinct element, or every element) are
+// always satisfiable and have no effect on the tree structure.
 //
 // Example:
 //
@@ -112,22 +111,40 @@ func NewPQTree(n int) *PQTree {
 //	tree.Reduce([]int{1, 2, 3})  // Elements 1, 2, 3 must be consecutive
 //	tree.Reduce([]int{0, 1})     // Elements 0, 1 must be consecutive
 func (t *PQTree) Reduce(constraint []int) bool {
-	if t.root == nil || len(constraint) <= 1 || len(constraint) == len(t.leaves) {
+	if t.root == nil {
 		return true
 	}
 
 	t.clearMarks(t.root)
+	marked := 0
 	for _, elem := range constraint {
-		if elem >= 0 && elem < len(t.leaves) {
+		if elem >= 0 && elem < len(t.leaves) && t.leaves[elem].mark != full {
 			t.leaves[elem].mark = full
+			marked++
 		}
 	}
-
-	if t.bubbleUp(t.root) == empty {
+	if marked <= 1 || marked == len(t.leaves) {
+		t.clearMarks(t.root)
 		return true
 	}
 
-	return t.reduce(t.root)
+	// The pertinent root is the deepest node whose subtree holds every
+	// constrained element; only its subtree changes.
+	t.countPertinent(t.root)
+	root := t.root
+	for descended := true; descended; {
+		descended = false
+		for _, c := range root.children {
+			if c.pertinent == marked {
+				root, descended = c, true
+				break
+			}
+		}
+	}
+
+	ok := t.reduce(root, true)
+	t.clearMarks(t.root)
+	return ok
 }
 
 // Clone creates an independent deep copy of the PQ-tree.
@@ -208,279 +225,225 @@ func (t *PQTree) cloneNode(n *pqNode, nodeMap map[*pqNode]*pqNode) *pqNode {
 
 func (t *PQTree) clearMarks(n *pqNode) {
 	n.mark = unmarked
-	n.fullCount = 0
-	n.partialCount = 0
+	n.pertinent = 0
 	for _, c := range n.children {
 		t.clearMarks(c)
 	}
 }
 
-func (t *PQTree) bubbleUp(n *pqNode) markKind {
+// countPertinent sets the number of constrained leaves below every node.
+func (t *PQTree) countPertinent(n *pqNode) int {
 	if n.kind == leafNode {
-		if n.mark == unmarked {
-			n.mark = empty
+		n.pertinent = 0
+		if n.mark == full {
+			n.pertinent = 1
 		}
-		return n.mark
+		return n.pertinent
 	}
-
-	n.fullCount = 0
-	n.partialCount = 0
+	n.pertinent = 0
 	for _, c := range n.children {
-		switch t.bubbleUp(c) {
-		case full:
-			n.fullCount++
-		case partial:
-			n.partialCount++
-		}
-	}
-
-	switch {
-	case n.fullCount == len(n.children):
-		n.mark = full
-	case n.fullCount == 0 && n.partialCount == 0:
-		n.mark = empty
-	default:
-		n.mark = partial
+		n.pertinent += t.countPertinent(c)
 	}
-	return n.mark
+	return n.pertinent
 }
 
-func (t *PQTree) reduce(n *pqNode) bool {
-	if n.mark == full || n.mark == empty {
-		return true
-	}
-
-	switch n.kind {
-	case leafNode:
+// reduce applies the Booth–Lueker templates to the pertinent subtree of n
+// bottom-up. Afterwards n is full, or partial: a Q-node whose full children
+// sit at one end (at the pertinent root, anywhere as one run).
+func (t *PQTree) reduce(n *pqNode, isRoot bool) bool {
+	if n.kind == leafNode {
 		return true
-	case pNode:
-		return t.reducePNode(n)
-	case qNode:
-		return t.reduceQNode(n)
 	}
-	return false
-}
 
-func (t *PQTree) reducePartialChildren(n *pqNode) bool {
+	var fulls, empties, partials []*pqNode
 	for _, c := range n.children {
-		if c.mark == partial && !t.reduce(c) {
-			return false
+		if c.pertinent == 0 {
+			c.mark = empty
+			empties = append(empties, c)
+			continue
 		}
-	}
-	n.fullCount = 0
-	n.partialCount = 0
-	for _, c := range n.children {
-		switch c.mark {
-		case full:
-			n.fullCount++
-		case partial:
-			n.partialCount++
+		if !t.reduce(c, false) {
+			return false
 		}
-	}
-	return true
-}
-
-func (t *PQTree) reducePNode(n *pqNode) bool {
-	if !t.reducePartialChildren(n) {
-		return false
-	}
-
-	var fullCh, emptyCh, partialCh []*pqNode
-	for _, child := range n.children {
-		switch child.mark {
-		case full:
-			fullCh = append(fullCh, child)
-		case empty:
-			emptyCh = append(emptyCh, child)
-		case partial:
-			partialCh = append(partialCh, child)
+		if c.mark == full {
+			fulls = append(fulls, c)
+		} else {
+			partials = append(partials, c)
 		}
 	}
 
-	if len(partialCh) > 1 {
-		return false
-	}
-
-	if len(fullCh) == 0 {
+	if len(fulls) == len(n.children) {
+		n.mark = full
 		return true
 	}
-
-	if len(partialCh) == 0 {
-		if len(fullCh) > 1 && len(emptyCh) > 0 {
-			t.groupChildren(n, fullCh, pNode)
-		}
-		return true
+	n.mark = partial
+	if n.kind == pNode {
+		return t.reducePNode(n, fulls, empties, partials, isRoot)
 	}
-
-	return t.extendPartialChild(n, partialCh[0], fullCh)
+	return t.reduceQNode(n, isRoot)
 }
 
-func (t *PQTree) reduceQNode(n *pqNode) bool {
-	if !t.reducePartialChildren(n) {
+// reducePNode applies templates P2 to P6 to a partial P-node.
+func (t *PQTree) reducePNode(n *pqNode, fulls, empties, partials []*pqNode, isRoot bool) bool {
+	switch {
+	case len(partials) == 0 && isRoot:
+		// P2: the full children become one P-node.
+		if len(fulls) > 1 {
+			t.replacePertinent(n, t.group(fulls, full))
+		}
+	case len(partials) == 0:
+		// P3: empty and full children become the two ends of a Q-node.
+		n.kind = qNode
+		t.setChildren(n, []*pqNode{t.group(empties, empty), t.group(fulls, full)})
+	case len(partials) == 1 && isRoot:
+		// P4: the full children join the full end of the partial child.
+		seq := fullLast(partials[0].children)
+		if len(fulls) > 0 {
+			seq = append(seq, t.group(fulls, full))
+		}
+		t.replaceWithQ(n, len(empties) > 0, seq)
+	case len(partials) == 1:
+		// P5: n becomes a Q-node with its empty children at one end and its
+		// full children at the other.
+		var seq []*pqNode
+		if len(empties) > 0 {
+			seq = append(seq, t.group(empties, empty))
+		}
+		seq = append(seq, fullLast(partials[0].children)...)
+		if len(fulls) > 0 {
+			seq = append(seq, t.group(fulls, full))
+		}
+		n.kind = qNode
+		t.setChildren(n, seq)
+	case len(partials) == 2 && isRoot:
+		// P6: the full children join the two partial children between
+		// their full ends.
+		seq := fullLast(partials[0].children)
+		if len(fulls) > 0 {
+			seq = append(seq, t.group(fulls, full))
+		}
+		second := fullLast(partials[1].children)
+		slices.Reverse(second)
+		t.replaceWithQ(n, len(empties) > 0, append(seq, second...))
+	default:
 		return false
 	}
+	return true
+}
 
+// reduceQNode applies templates Q2 and Q3 to a partial Q-node, merging its
+// partial children into it.
+func (t *PQTree) reduceQNode(n *pqNode, isRoot bool) bool {
 	first, last := -1, -1
-	var partialIdx []int
-	for i, child := range n.children {
-		switch child.mark {
-		case full:
-			if first < 0 {
-				first = i
-			}
-			last = i
-		case partial:
-			partialIdx = append(partialIdx, i)
+	partials := 0
+	for i, c := range n.children {
+		if c.mark == empty {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		last = i
+		if c.mark == partial {
+			partials++
 		}
 	}
-
-	if first < 0 {
-		return true
-	}
-
-	for i := first; i <= last; i++ {
-		if n.children[i].mark == empty {
+	for i := first + 1; i < last; i++ {
+		if n.children[i].mark != full {
 			return false
 		}
 	}
-
-	for _, idx := range partialIdx {
-		if idx != first-1 && idx != last+1 {
+	if !isRoot {
+		// Q2: the pertinent run must reach an end of n, with at most one
+		// partial child at its inner end.
+		atStart := first == 0 && (n.children[first].mark == full || first == last)
+		atEnd := last == len(n.children)-1 && (n.children[last].mark == full || first == last)
+		if partials > 1 || !(atStart || atEnd) {
 			return false
 		}
+		if first == last && atStart && !atEnd && n.children[first].mark == partial {
+			// A lone partial child at the start faces its full end outwards.
+			seq := fullLast(n.children[first].children)
+			slices.Reverse(seq)
+			t.setChildren(n, slices.Concat(seq, n.children[1:]))
+			return true
+		}
 	}
 
-	for _, idx := range partialIdx {
-		if n.children[idx].kind == qNode {
-			t.mergeQNodes(n, idx)
+	// Q3 at the pertinent root, and Q2 otherwise: partial children face
+	// their full ends towards the run.
+	var seq []*pqNode
+	for i, c := range n.children {
+		if c.mark != partial {
+			seq = append(seq, c)
+			continue
+		}
+		grand := fullLast(c.children)
+		if i == last && first != last {
+			slices.Reverse(grand)
 		}
+		seq = append(seq, grand...)
 	}
+	t.setChildren(n, seq)
 	return true
 }
 
-func (t *PQTree) groupChildren(parent *pqNode, group []*pqNode, kind nodeKind) {
-	if len(group) <= 1 {
-		return
-	}
-
-	node := &pqNode{
-		kind:     kind,
-		children: slices.Clone(group),
-		parent:   parent,
-		mark:     group[0].mark,
-	}
-
-	for _, child := range group {
-		child.parent = node
-	}
-
-	groupSet := make(map[*pqNode]bool, len(group))
-	for _, child := range group {
-		groupSet[child] = true
+// fullLast returns the children of a partial Q-node ordered with the full
+// ones at the end.
+func fullLast(children []*pqNode) []*pqNode {
+	seq := slices.Clone(children)
+	if seq[0].mark == full {
+		slices.Reverse(seq)
 	}
-
-	newChildren := make([]*pqNode, 0, len(parent.children)-len(group)+1)
-	inserted := false
-	for _, child := range parent.children {
-		if groupSet[child] {
-			if !inserted {
-				newChildren = append(newChildren, node)
-				inserted = true
-			}
-		} else {
-			newChildren = append(newChildren, child)
-		}
-	}
-	parent.children = newChildren
+	return seq
 }
 
-func (t *PQTree) extendPartialChild(parent, partialChild *pqNode, fullSiblings []*pqNode) bool {
-	if len(fullSiblings) == 0 {
-		return true
-	}
-
-	var fullInPartial, emptyInPartial []*pqNode
-	for _, child := range partialChild.children {
-		if child.mark == full {
-			fullInPartial = append(fullInPartial, child)
-		} else {
-			emptyInPartial = append(emptyInPartial, child)
-		}
+// group returns nodes as one child: the node itself when there is one, or
+// a new P-node marked mark.
+func (t *PQTree) group(nodes []*pqNode, mark markKind) *pqNode {
+	if len(nodes) == 1 {
+		return nodes[0]
 	}
-
-	children := make([]*pqNode, 0, len(fullInPartial)+len(fullSiblings)+len(emptyInPartial))
-	children = append(children, emptyInPartial...)
-	children = append(children, fullInPartial...)
-	children = append(children, fullSiblings...)
-
-	qnode := &pqNode{
-		kind:     qNode,
-		children: children,
-		parent:   parent,
-		mark:     partial,
-	}
-	for _, child := range children {
-		child.parent = qnode
-	}
-
-	toRemove := make(map[*pqNode]bool, len(fullSiblings)+1)
-	toRemove[partialChild] = true
-	for _, sibling := range fullSiblings {
-		toRemove[sibling] = true
-	}
-
-	newChildren := make([]*pqNode, 0, len(parent.children)-len(toRemove)+1)
-	replaced := false
-	for _, child := range parent.children {
-		if toRemove[child] {
-			if !replaced {
-				newChildren = append(newChildren, qnode)
-				replaced = true
-			}
-		} else {
-			newChildren = append(newChildren, child)
-		}
-	}
-	parent.children = newChildren
-
-	if len(parent.children) == 1 && parent == t.root {
-		t.root = parent.children[0]
-		t.root.parent = nil
-	}
-
-	return true
+	g := &pqNode{kind: pNode, mark: mark}
+	t.setChildren(g, slices.Clone(nodes))
+	return g
 }
 
-func (t *PQTree) mergeQNodes(parent *pqNode, idx int) {
-	child := parent.children[idx]
-	if child.kind != qNode {
+// replaceWithQ turns n into a Q-node of seq when n has no empty children,
+// and otherwise replaces its pertinent children with a Q-node of seq.
+func (t *PQTree) replaceWithQ(n *pqNode, hasEmpty bool, seq []*pqNode) {
+	if !hasEmpty {
+		n.kind = qNode
+		t.setChildren(n, seq)
 		return
 	}
+	q := &pqNode{kind: qNode, mark: partial}
+	t.setChildren(q, seq)
+	t.replacePertinent(n, q)
+}
 
-	reverse := false
-	if idx > 0 && parent.children[idx-1].mark == full {
-		if len(child.children) > 0 && child.children[len(child.children)-1].mark == full {
-			reverse = true
-		}
-	} else if idx < len(parent.children)-1 && parent.children[idx+1].mark == full {
-		if len(child.children) > 0 && child.children[0].mark == full {
-			reverse = true
+// replacePertinent replaces the pertinent children of n with repl, at the
+// position of the first one.
+func (t *PQTree) replacePertinent(n, repl *pqNode) {
+	children := make([]*pqNode, 0, len(n.children))
+	replaced := false
+	for _, c := range n.children {
+		switch {
+		case c.mark == empty:
+			children = append(children, c)
+		case !replaced:
+			children = append(children, repl)
+			replaced = true
 		}
 	}
+	t.setChildren(n, children)
+}
 
-	if reverse {
-		slices.Reverse(child.children)
-	}
-
-	for _, grandchild := range child.children {
-		grandchild.parent = parent
+func (t *PQTree) setChildren(n *pqNode, children []*pqNode) {
+	n.children = children
+	for _, c := range children {
+		c.parent = n
 	}
-
-	newChildren := make([]*pqNode, 0, len(parent.children)+len(child.children)-1)
-	newChildren = append(newChildren, parent.children[:idx]...)
-	newChildren = append(newChildren, child.children...)
-	newChildren = append(newChildren, parent.children[idx+1:]...)
-	parent.children = newChildren
 }
 
 // Enumerate returns all valid permutations represented by the tree.
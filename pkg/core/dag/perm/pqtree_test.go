@@ -308,3 +308,95 @@ func areConsecutive(perm, subset []int) bool {
 	}
 	return true
 }
+
+// FuzzPQTreeReduce applies fuzzed constraints, including duplicate and
+// out-of-range indices, and checks the tree against brute force: the valid
+// permutations are exactly those keeping every accepted constraint
+// consecutive, and a rejected constraint leaves no permutation.
+func FuzzPQTreeReduce(f *testing.F) {
+	f.Add(uint8(5), []byte{1, 2, 3, 0xff, 0, 1})
+	f.Add(uint8(6), []byte{1, 2, 0xff, 2, 3, 0xff, 1, 3})
+	f.Add(uint8(4), []byte{1, 1, 2, 0xff, 15, 3, 4})
+	f.Fuzz(func(t *testing.T, size uint8, data []byte) {
+		n := 1 + int(size%7)
+		data = data[:min(len(data), 64)] // keep the brute force fast
+		var constraints [][]int
+		cur := []int{}
+		for _, b := range data {
+			if b == 0xff {
+				constraints = append(constraints, cur)
+				cur = []int{}
+				continue
+			}
+			cur = append(cur, int(b%16)-1)
+		}
+		constraints = append(constraints, cur)
+
+		tree := NewPQTree(n)
+		var accepted [][]int
+		for _, c := range constraints {
+			if !tree.Reduce(c) {
+				if count := bruteForceCount(n, append(accepted, c)); count != 0 {
+					t.Fatalf("Reduce(%v) rejected a constraint satisfiable with %v (%d permutations)", c, accepted, count)
+				}
+				return
+			}
+			accepted = append(accepted, c)
+		}
+
+		want := bruteForceCount(n, accepted)
+		if got := tree.ValidCount(); got != want {
+			t.Fatalf("ValidCount() = %d after %v, want %d", got, accepted, want)
+		}
+		perms := tree.Enumerate(0)
+		if len(perms) != want {
+			t.Fatalf("Enumerate() returned %d permutations, want %d", len(perms), want)
+		}
+		for _, p := range perms {
+			if !slices.Equal(slices.Sorted(slices.Values(p)), Seq(n)) {
+				t.Fatalf("%v is not a permutation of %d elements", p, n)
+			}
+			for _, c := range accepted {
+				if !consecutive(p, c) {
+					t.Fatalf("%v breaks constraint %v", p, c)
+				}
+			}
+		}
+	})
+}
+
+// bruteForceCount counts the permutations of n elements in which the
+// in-range elements of every constraint are consecutive.
+func bruteForceCount(n int, constraints [][]int) int {
+	count := 0
+	for _, p := range Generate(n, 0) {
+		ok := true
+		for _, c := range constraints {
+			if !consecutive(p, c) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			count++
+		}
+	}
+	return count
+}
+
+// consecutive reports whether the in-range elements of c occupy adjacent
+// positions of p.
+func consecutive(p, c []int) bool {
+	pos := make(map[int]int, len(p))
+	for i, v := range p {
+		pos[v] = i
+	}
+	lo, hi, members := len(p), -1, map[int]bool{}
+	for _, e := range c {
+		if i, ok := pos[e]; ok {
+			members[e] = true
+			lo, hi = min(lo, i), max(hi, i)
+		}
+	}
+	return len(members) == 0 || hi-lo+1 == len(members)
+}
@@ -55,7 +55,7 @@ func ComputeStats(g *DAG) *GraphStats {
 
 	// Count non-synthetic, non-root nodes as total deps
 	totalDeps := 0
-	for _, n := range g.Nodes() {
+	for _, n := range g.NodesIter() {
 		if n.IsSynthetic() || n.ID == root || n.ID == "__project__" {
 			continue
 		}
@@ -94,7 +94,7 @@ func computeReverseDeps(g *DAG, root string) map[string]int {
 
 	// For each non-synthetic, non-root node, walk its children and increment
 	// their reverse-dep count. We use DFS with per-source visited sets.
-	for _, n := range g.Nodes() {
+	for _, n := range g.NodesIter() {
 		if n.IsSynthetic() || n.ID == root || n.ID == "__project__" {
 			continue
 		}
@@ -122,7 +122,7 @@ func computeReverseDeps(g *DAG, root string) map[string]int {
 
 // FindRoot returns the ID of the primary root node (non-synthetic, in-degree 0).
 func FindRoot(g *DAG) string {
-	for _, n := range g.Nodes() {
+	for _, n := range g.NodesIter() {
 		if n.IsSynthetic() || n.ID == "__project__" {
 			continue
 		}
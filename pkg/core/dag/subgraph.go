@@ -0,0 +1,78 @@
+package dag
+
+import "maps"
+
+// Subgraph returns a new graph holding the given roots and everything
+// reachable from them, with the edges among those nodes. Node, edge and
+// graph metadata are copied. IDs not in g are ignored.
+func Subgraph(g *DAG, roots []string) *DAG {
+	keep := make(map[string]bool, len(roots))
+	var queue []string
+	for _, id := range roots {
+		if _, ok := g.Node(id); ok && !keep[id] {
+			keep[id] = true
+			queue = append(queue, id)
+		}
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, child := range g.Children(cur) {
+			if !keep[child] {
+				keep[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+	return induced(g, keep)
+}
+
+// LimitDepth returns a new graph holding the nodes at most depth edges
+// below a source (a node without parents), with the edges among them.
+// Sources are at depth 0. Node, edge and graph metadata are copied.
+func LimitDepth(g *DAG, depth int) *DAG {
+	level := make(map[string]int, g.NodeCount())
+	var queue []string
+	for _, n := range g.Sources() {
+		level[n.ID] = 0
+		queue = append(queue, n.ID)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if level[cur] >= depth {
+			continue
+		}
+		for _, child := range g.Children(cur) {
+			if _, seen := level[child]; !seen {
+				level[child] = level[cur] + 1
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	keep := make(map[string]bool, len(level))
+	for id := range level {
+		keep[id] = true
+	}
+	return induced(g, keep)
+}
+
+// induced returns a copy of g restricted to the nodes in keep.
+func induced(g *DAG, keep map[string]bool) *DAG {
+	sub := New(nil)
+	maps.Copy(sub.Meta(), g.Meta())
+	for _, n := range g.Nodes() {
+		if keep[n.ID] {
+			node := *n
+			node.Meta = maps.Clone(n.Meta)
+			_ = sub.AddNode(node)
+		}
+	}
+	for _, e := range g.Edges() {
+		if keep[e.From] && keep[e.To] {
+			_ = sub.AddEdge(Edge{From: e.From, To: e.To, Meta: maps.Clone(e.Meta)})
+		}
+	}
+	return sub
+}
@@ -0,0 +1,66 @@
+package dag
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSubgraph(t *testing.T) {
+	g := buildPathTestGraph()
+	g.Meta()["name"] = "demo"
+
+	tests := []struct {
+		name      string
+		roots     []string
+		wantNodes []string
+		wantEdges int
+	}{
+		{"leaf", []string{"F"}, []string{"F"}, 0},
+		{"branch", []string{"C"}, []string{"C", "D", "E", "F"}, 3},
+		{"several roots", []string{"B", "E"}, []string{"B", "D", "E", "F"}, 2},
+		{"unknown ignored", []string{"Z", "D"}, []string{"D", "F"}, 1},
+		{"none", nil, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := Subgraph(g, tt.roots)
+			got := NodeIDs(sub.Nodes())
+			slices.Sort(got)
+			if !slices.Equal(got, tt.wantNodes) {
+				t.Errorf("nodes = %v, want %v", got, tt.wantNodes)
+			}
+			if sub.EdgeCount() != tt.wantEdges {
+				t.Errorf("edges = %d, want %d", sub.EdgeCount(), tt.wantEdges)
+			}
+			if sub.Meta()["name"] != "demo" {
+				t.Error("graph metadata was not kept")
+			}
+		})
+	}
+}
+
+func TestLimitDepth(t *testing.T) {
+	g := buildPathTestGraph()
+
+	tests := []struct {
+		depth     int
+		wantNodes []string
+		wantEdges int
+	}{
+		{0, []string{"A"}, 0},
+		{1, []string{"A", "B", "C"}, 2},
+		{2, []string{"A", "B", "C", "D", "E"}, 5},
+		{10, []string{"A", "B", "C", "D", "E", "F"}, 6},
+	}
+	for _, tt := range tests {
+		sub := LimitDepth(g, tt.depth)
+		got := NodeIDs(sub.Nodes())
+		slices.Sort(got)
+		if !slices.Equal(got, tt.wantNodes) {
+			t.Errorf("LimitDepth(%d) nodes = %v, want %v", tt.depth, got, tt.wantNodes)
+		}
+		if sub.EdgeCount() != tt.wantEdges {
+			t.Errorf("LimitDepth(%d) edges = %d, want %d", tt.depth, sub.EdgeCount(), tt.wantEdges)
+		}
+	}
+}
@@ -54,6 +54,8 @@
 // [AssignLayers] computes the row (layer) for each node based on its depth
 // from source nodes (those with no incoming edges). This uses a topological
 // traversal to ensure parents are always in rows above their children.
+// [AssignFixedLayers] instead keeps rows already set on the graph, failing
+// when they put a node at or above one of its parents.
 //
 // # Cycle Breaking
 //
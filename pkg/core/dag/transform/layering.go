@@ -1,6 +1,10 @@
 package transform
 
-import "github.com/stacktower-io/stacktower/pkg/core/dag"
+import (
+	"fmt"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
 
 // AssignLayers assigns nodes to horizontal rows (layers) based on their depth
 // in the graph.
@@ -12,7 +16,8 @@ import "github.com/stacktower-io/stacktower/pkg/core/dag"
 //   - All parents are strictly above their children
 //   - Each node is pushed as deep as necessary to avoid parent conflicts
 //
-// Existing row assignments in the DAG are overwritten.
+// Existing row assignments in the DAG are overwritten. To keep them, use
+// [AssignFixedLayers].
 //
 // # Algorithm
 //
@@ -68,3 +73,65 @@ func AssignLayers(g *dag.DAG) {
 
 	g.SetRows(rows)
 }
+
+// AssignFixedLayers is like [AssignLayers] but keeps the rows already set
+// on the DAG, such as those read from an imported JSON graph, as hard
+// constraints.
+//
+// A node keeps its row unless it is at row 0 and has parents, which marks
+// it as unassigned: unassigned nodes are placed one row below their lowest
+// parent, the highest row they can take. Because that placement is as
+// shallow as possible, the rows are feasible exactly when every edge then
+// points from a lower row to a higher one.
+//
+// AssignFixedLayers returns an error naming the first edge that violates
+// this, in node order, and leaves the DAG unchanged in that case.
+//
+// # Cycles
+//
+// Like [AssignLayers], AssignFixedLayers assumes the graph is acyclic.
+// Unassigned nodes on a cycle stay at row 0 and make it fail.
+func AssignFixedLayers(g *dag.DAG) error {
+	nodes := g.Nodes()
+	inDegree := make(map[string]int, len(nodes))
+	rows := make(map[string]int, len(nodes))
+	fixed := make(map[string]bool, len(nodes))
+	queue := make([]string, 0, len(nodes))
+
+	for _, n := range nodes {
+		degree := g.InDegree(n.ID)
+		inDegree[n.ID] = degree
+		rows[n.ID] = n.Row
+		fixed[n.ID] = n.Row != 0 || degree == 0
+		if degree == 0 {
+			queue = append(queue, n.ID)
+		}
+	}
+
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+
+		for _, child := range g.Children(curr) {
+			if row := rows[curr] + 1; !fixed[child] && row > rows[child] {
+				rows[child] = row
+			}
+			inDegree[child]--
+			if inDegree[child] == 0 {
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	for _, n := range nodes {
+		for _, child := range g.Children(n.ID) {
+			if rows[child] <= rows[n.ID] {
+				return fmt.Errorf("fixed rows: %s (row %d) depends on %s (row %d), which is not below it",
+					n.ID, rows[n.ID], child, rows[child])
+			}
+		}
+	}
+
+	g.SetRows(rows)
+	return nil
+}
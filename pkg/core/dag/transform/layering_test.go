@@ -190,3 +190,49 @@ func TestAssignLayers_FanInFanOut(t *testing.T) {
 	checkRow(t, g, "e", 2)
 	checkRow(t, g, "f", 3)
 }
+
+func TestAssignFixedLayers(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "api"})
+	_ = g.AddNode(dag.Node{ID: "service", Row: 2})
+	_ = g.AddNode(dag.Node{ID: "helper"})
+	_ = g.AddNode(dag.Node{ID: "db", Row: 4})
+	_ = g.AddEdge(dag.Edge{From: "api", To: "service"})
+	_ = g.AddEdge(dag.Edge{From: "api", To: "helper"})
+	_ = g.AddEdge(dag.Edge{From: "service", To: "db"})
+	_ = g.AddEdge(dag.Edge{From: "helper", To: "db"})
+
+	if err := AssignFixedLayers(g); err != nil {
+		t.Fatalf("AssignFixedLayers() error = %v", err)
+	}
+	checkRow(t, g, "api", 0)
+	checkRow(t, g, "service", 2)
+	checkRow(t, g, "helper", 1)
+	checkRow(t, g, "db", 4)
+}
+
+func TestAssignFixedLayers_Infeasible(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []dag.Node
+	}{
+		{"child above parent", []dag.Node{{ID: "a", Row: 3}, {ID: "b"}, {ID: "c", Row: 2}}},
+		{"no room between", []dag.Node{{ID: "a", Row: 1}, {ID: "b"}, {ID: "c", Row: 2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := dag.New(nil)
+			for _, n := range tt.nodes {
+				_ = g.AddNode(n)
+			}
+			_ = g.AddEdge(dag.Edge{From: "a", To: "b"})
+			_ = g.AddEdge(dag.Edge{From: "b", To: "c"})
+
+			if err := AssignFixedLayers(g); err == nil {
+				t.Fatal("expected an error for infeasible rows")
+			}
+			checkRow(t, g, "a", tt.nodes[0].Row)
+			checkRow(t, g, "b", 0)
+		})
+	}
+}
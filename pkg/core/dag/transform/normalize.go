@@ -57,12 +57,13 @@ func Normalize(g *dag.DAG) (*TransformResult, error) {
 //
 //  1. [BreakCycles]: Remove back-edges (unless opts.SkipCycleBreaking)
 //  2. [TransitiveReduction]: Remove redundant edges (unless opts.SkipTransitiveReduction)
-//  3. [AssignLayers]: Assign rows (always applied)
+//  3. [AssignLayers]: Assign rows (always applied; [AssignFixedLayers] if opts.FixedRows)
 //  4. [Subdivide]: Break long edges (always applied)
 //  5. [ResolveSpanOverlaps]: Insert separators (unless opts.SkipSeparators)
 //
 // Layer assignment and edge subdivision are always applied because they are
-// required for valid tower layouts.
+// required for valid tower layouts. With opts.FixedRows, NormalizeWithOptions
+// returns an error when the rows already set on g cannot be kept.
 //
 // # Nil Handling
 //
@@ -96,7 +97,13 @@ func NormalizeWithOptions(g *dag.DAG, opts NormalizeOptions) (result *TransformR
 		result.TransitiveEdgesRemoved = edgesBefore - g.EdgeCount()
 	}
 
-	AssignLayers(g)
+	if opts.FixedRows {
+		if err := AssignFixedLayers(g); err != nil {
+			return nil, fmt.Errorf("normalize: %w", err)
+		}
+	} else {
+		AssignLayers(g)
+	}
 
 	nodesBefore := g.NodeCount()
 	Subdivide(g)
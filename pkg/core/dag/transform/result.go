@@ -51,4 +51,10 @@ type NormalizeOptions struct {
 	// when crossings are acceptable or when the graph structure guarantees
 	// no overlaps.
 	SkipSeparators bool
+
+	// FixedRows keeps the rows already set on nodes as hard constraints,
+	// placing only unassigned nodes (see [AssignFixedLayers]). Normalization
+	// fails if the rows cannot be kept. Use this for hand-made graphs, such
+	// as call graphs or architecture layers, whose layering is intended.
+	FixedRows bool
 }
@@ -40,7 +40,9 @@ import (
 // # Separator Nodes
 //
 // Separator nodes are inserted in a new row between parents and children,
-// shifting all lower rows down. Separator IDs are generated as
+// shifting all lower rows down. Edges that the new row leaves spanning two
+// rows, such as those of parents outside the separator's range, are routed
+// through subdividers as [Subdivide] would. Separator IDs are generated as
 // "Sep_row_firstChild_lastChild" with numeric suffixes if needed for
 // uniqueness.
 //
@@ -75,14 +77,19 @@ import (
 // Space complexity is O(V) for tracking used node IDs.
 func ResolveSpanOverlaps(d *dag.DAG) {
 	usedIDs := nodeIDSet(d.Nodes())
+	inserted := false
 	// Process row boundaries by index (not row number) since separator insertion
 	// shifts row numbers but not our position in the traversal.
 	for i := 1; i < d.RowCount(); i++ {
 		row := d.RowIDs()[i]
 		for insertSeparatorAt(d, row, usedIDs) {
+			inserted = true
 			row = d.RowIDs()[i] // re-fetch: same index, new row number
 		}
 	}
+	if inserted {
+		subdivideLongEdges(d, &idGen{used: usedIDs})
+	}
 }
 
 func insertSeparatorAt(d *dag.DAG, row int, usedIDs map[string]struct{}) bool {
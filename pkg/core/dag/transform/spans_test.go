@@ -427,3 +427,32 @@ func collectSeparatorIDs(g *dag.DAG) []string {
 	}
 	return ids
 }
+
+func TestResolveSpanOverlaps_SubdividesUnaffectedEdges(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "p1", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "p2", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "p3", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "c1", Row: 1})
+	_ = g.AddNode(dag.Node{ID: "c2", Row: 1})
+	_ = g.AddNode(dag.Node{ID: "c3", Row: 1})
+	_ = g.AddEdge(dag.Edge{From: "p1", To: "c1"})
+	_ = g.AddEdge(dag.Edge{From: "p1", To: "c2"})
+	_ = g.AddEdge(dag.Edge{From: "p2", To: "c1"})
+	_ = g.AddEdge(dag.Edge{From: "p2", To: "c2"})
+	_ = g.AddEdge(dag.Edge{From: "p3", To: "c3"}) // outside the separator's range
+
+	ResolveSpanOverlaps(g)
+
+	if err := g.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	sub := g.Children("p3")
+	if len(sub) != 1 {
+		t.Fatalf("Children(p3) = %v, want one subdivider", sub)
+	}
+	n, _ := g.Node(sub[0])
+	if !n.IsSubdivider() || n.MasterID != "p3" {
+		t.Errorf("p3 child = %+v, want a subdivider of p3", n)
+	}
+}
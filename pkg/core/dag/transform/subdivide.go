@@ -2,6 +2,7 @@ package transform
 
 import (
 	"fmt"
+	"maps"
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 )
@@ -36,7 +37,8 @@ import (
 //
 // Subdivide preserves edge metadata only on the final edge in each subdivided
 // chain (the edge entering the original target). Intermediate subdivider edges
-// have no metadata.
+// carry only the [dag.EdgeMetaWeight], so ordering weighs the whole chain
+// like the edge it replaces.
 //
 // # Nil Handling
 //
@@ -64,9 +66,13 @@ func subdivideLongEdges(g *dag.DAG, gen *idGen) {
 		}
 
 		toRemove = append(toRemove, e)
+		var segMeta dag.Metadata
+		if w, ok := e.Meta[dag.EdgeMetaWeight]; ok {
+			segMeta = dag.Metadata{dag.EdgeMetaWeight: w}
+		}
 		prevID := src.ID
 		for row := src.Row + 1; row < dst.Row; row++ {
-			prevID = addSubdivider(g, gen, prevID, src.ID, row)
+			prevID = addSubdivider(g, gen, prevID, src.EffectiveID(), row, segMeta)
 		}
 		if err := g.AddEdge(dag.Edge{From: prevID, To: dst.ID, Meta: e.Meta}); err != nil {
 			panic(err)
@@ -78,7 +84,7 @@ func subdivideLongEdges(g *dag.DAG, gen *idGen) {
 	}
 }
 
-func addSubdivider(g *dag.DAG, gen *idGen, from, master string, row int) string {
+func addSubdivider(g *dag.DAG, gen *idGen, from, master string, row int, edgeMeta dag.Metadata) string {
 	id := gen.next(master, row)
 	if err := g.AddNode(dag.Node{
 		ID:       id,
@@ -88,7 +94,7 @@ func addSubdivider(g *dag.DAG, gen *idGen, from, master string, row int) string
 	}); err != nil {
 		panic(err)
 	}
-	if err := g.AddEdge(dag.Edge{From: from, To: id}); err != nil {
+	if err := g.AddEdge(dag.Edge{From: from, To: id, Meta: maps.Clone(edgeMeta)}); err != nil {
 		panic(err)
 	}
 	return id
@@ -102,7 +108,7 @@ func extendSinksToBottom(g *dag.DAG, gen *idGen) {
 		}
 		prevID := n.ID
 		for row := n.Row + 1; row <= maxRow; row++ {
-			prevID = addSubdivider(g, gen, prevID, n.EffectiveID(), row)
+			prevID = addSubdivider(g, gen, prevID, n.EffectiveID(), row, nil)
 		}
 	}
 }
@@ -166,6 +166,26 @@ func TestSubdivide_PreservesOriginalNodeProperties(t *testing.T) {
 	}
 }
 
+func TestSubdivide_EdgeMetadata(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "a", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "b", Row: 2})
+	_ = g.AddEdge(dag.Edge{From: "a", To: "b", Meta: dag.Metadata{dag.EdgeMetaKind: "dev", dag.EdgeMetaWeight: 3.0}})
+
+	Subdivide(g)
+
+	first, ok := g.Edge("a", "a_sub_1")
+	if !ok {
+		t.Fatalf("edges = %v", g.Edges())
+	}
+	if first.Weight() != 3 || first.Meta[dag.EdgeMetaKind] != nil {
+		t.Errorf("intermediate edge meta = %v, want only the weight", first.Meta)
+	}
+	if last, _ := g.Edge("a_sub_1", "b"); last.Meta[dag.EdgeMetaKind] != "dev" || last.Weight() != 3 {
+		t.Errorf("final edge meta = %v, want all of it", last.Meta)
+	}
+}
+
 func TestSubdivide_SubdividerNaming(t *testing.T) {
 	g := dag.New(nil)
 	_ = g.AddNode(dag.Node{ID: "parent", Row: 0})
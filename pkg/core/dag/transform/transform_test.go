@@ -57,6 +57,29 @@ func TestNormalize_SimpleGraph_AppliesPipeline(t *testing.T) {
 	}
 }
 
+func TestNormalizeWithOptions_FixedRows(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "a"})
+	_ = g.AddNode(dag.Node{ID: "b", Row: 3})
+	_ = g.AddEdge(dag.Edge{From: "a", To: "b"})
+
+	result, err := NormalizeWithOptions(g, NormalizeOptions{FixedRows: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.MaxRow != 3 || result.SubdividersAdded != 2 {
+		t.Errorf("expected b kept at row 3 with 2 subdividers, got %+v", result)
+	}
+
+	g = dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "a", Row: 2})
+	_ = g.AddNode(dag.Node{ID: "b", Row: 1})
+	_ = g.AddEdge(dag.Edge{From: "a", To: "b"})
+	if _, err := NormalizeWithOptions(g, NormalizeOptions{FixedRows: true}); err == nil {
+		t.Error("expected an error for a child above its parent")
+	}
+}
+
 func TestTransitiveReduction_EmptyGraph_Noop(t *testing.T) {
 	g := dag.New(nil)
 	TransitiveReduction(g)
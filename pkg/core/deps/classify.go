@@ -0,0 +1,95 @@
+package deps
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+// MetaInternal is the node metadata key marking first-party packages: code
+// the project owns, as opposed to external dependencies. It holds true and
+// is absent on external packages. See [ClassifyPackages].
+const MetaInternal = "internal"
+
+// cratesIOIndexes are the index URLs Cargo.lock records as the source of
+// crates.io packages, over git and over the sparse protocol.
+var cratesIOIndexes = []string{
+	"https://github.com/rust-lang/crates.io-index",
+	"https://index.crates.io/",
+}
+
+// ValidateInternal reports internal patterns that are not valid globs.
+func ValidateInternal(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := path.Match(strings.ToLower(p), ""); err != nil {
+			return fmt.Errorf("invalid internal pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// ClassifyPackages marks the first-party packages of g with [MetaInternal].
+// A package is internal when its ID matches one of the shell-style glob
+// patterns (e.g. "@acme/*", "github.com/acme/*"), ignoring case, or when
+// its metadata shows it does not come from the public registry (see
+// [FromLocalSource]). Virtual nodes such as the project root are left
+// alone. Previous marks are cleared, so classifying again with other
+// patterns starts afresh.
+func ClassifyPackages(g *dag.DAG, patterns []string) {
+	for _, n := range g.Nodes() {
+		if n.Meta == nil {
+			continue
+		}
+		delete(n.Meta, MetaInternal)
+		if virtual, _ := n.Meta["virtual"].(bool); virtual {
+			continue
+		}
+		if matchAny(patterns, n.ID) || FromLocalSource(n.Meta) {
+			n.Meta[MetaInternal] = true
+		}
+	}
+}
+
+// IsInternal reports whether n is marked as a first-party package.
+func IsInternal(n *dag.Node) bool {
+	if n == nil || n.Meta == nil {
+		return false
+	}
+	internal, _ := n.Meta[MetaInternal].(bool)
+	return internal
+}
+
+// FromLocalSource reports whether node metadata shows that a package was
+// taken from the project itself or from a private registry rather than the
+// public one: editable and path packages of uv.lock, workspace links of
+// package-lock.json, Go modules replaced by a local directory, and
+// Cargo.lock packages from a path or another registry than crates.io.
+func FromLocalSource(meta dag.Metadata) bool {
+	if editable, _ := meta["editable"].(bool); editable {
+		return true
+	}
+	if p, _ := meta["path"].(string); p != "" {
+		return true
+	}
+	if rep, _ := meta["replaced_by"].(string); isLocalPath(rep) {
+		return true
+	}
+	src, _ := meta[MetaSource].(string)
+	switch {
+	case strings.HasPrefix(src, "path+"):
+		return true
+	case strings.HasPrefix(src, "registry+"), strings.HasPrefix(src, "sparse+"):
+		_, index, _ := strings.Cut(src, "+")
+		return !slices.Contains(cratesIOIndexes, index)
+	}
+	return false
+}
+
+// isLocalPath reports whether a replacement target is a directory, which
+// go.mod writes as a relative or absolute path.
+func isLocalPath(s string) bool {
+	return strings.HasPrefix(s, "./") || strings.HasPrefix(s, "../") || strings.HasPrefix(s, "/")
+}
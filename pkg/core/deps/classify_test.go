@@ -0,0 +1,48 @@
+package deps
+
+import (
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestClassifyPackages(t *testing.T) {
+	g := dag.New(nil)
+	for _, n := range []dag.Node{
+		{ID: ProjectRootNodeID, Meta: dag.Metadata{"virtual": true}},
+		{ID: "@Acme/Utils", Meta: dag.Metadata{"version": "1.0.0"}},
+		{ID: "lodash", Meta: dag.Metadata{"version": "4.17.21"}},
+		{ID: "shared", Meta: dag.Metadata{"path": "packages/shared"}},
+		{ID: "example.com/lib", Meta: dag.Metadata{"replaced_by": "../lib"}},
+		{ID: "example.com/fork", Meta: dag.Metadata{"replaced_by": "github.com/me/fork@v1.0.0"}},
+		{ID: "serde", Meta: dag.Metadata{MetaSource: "registry+https://github.com/rust-lang/crates.io-index"}},
+		{ID: "tokio", Meta: dag.Metadata{MetaSource: "sparse+https://index.crates.io/"}},
+		{ID: "acme-core", Meta: dag.Metadata{MetaSource: "sparse+https://crates.acme.dev/index/"}},
+		{ID: "stale", Meta: dag.Metadata{MetaInternal: true}},
+	} {
+		_ = g.AddNode(n)
+	}
+
+	ClassifyPackages(g, []string{"@acme/*"})
+
+	want := map[string]bool{
+		"@Acme/Utils":     true,
+		"shared":          true,
+		"example.com/lib": true,
+		"acme-core":       true,
+	}
+	for _, n := range g.Nodes() {
+		if got := IsInternal(n); got != want[n.ID] {
+			t.Errorf("IsInternal(%s) = %v, want %v", n.ID, got, want[n.ID])
+		}
+	}
+}
+
+func TestValidateInternal(t *testing.T) {
+	if err := ValidateInternal([]string{"@acme/*", "acme-?"}); err != nil {
+		t.Errorf("valid patterns: %v", err)
+	}
+	if err := ValidateInternal([]string{"acme-["}); err == nil {
+		t.Error("expected an error for a malformed pattern")
+	}
+}
@@ -0,0 +1,150 @@
+package deps
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/contriboss/pubgrub-go"
+
+	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/observability"
+)
+
+// crawlEntry is a package waiting in the best-first crawl frontier.
+type crawlEntry struct {
+	name       string
+	fanIn      int // Admitted packages that depend on it
+	popularity int // Downloads of its most downloaded admitted dependent
+	depth      int // Shortest distance from the root
+}
+
+// compareCrawlEntries orders the frontier: the packages most depended upon
+// come first, then those pulled in by the most downloaded packages, then
+// the shallowest, then by name so that the plan is deterministic.
+func compareCrawlEntries(a, b *crawlEntry) int {
+	if c := cmp.Compare(b.fanIn, a.fanIn); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(b.popularity, a.popularity); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.depth, b.depth); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.name, b.name)
+}
+
+// planCrawl chooses which packages to admit when the dependency graph holds
+// more than MaxNodes packages. Solving admits packages in the order the
+// solver happens to reach them, which keeps arbitrary leaves and drops
+// shared foundations; planCrawl instead walks the graph best-first (see
+// [compareCrawlEntries]) and admits packages in that order until the
+// budget is spent.
+//
+// Packages in the first solution are read at their solved version, from the
+// source cache; others are fetched at their latest version. It returns nil
+// when every reachable package fits, or when the crawl was cut short by
+// cancellation or [Options.MaxDuration], so that solving keeps its
+// first-come admission.
+func (s *pubgrubSource) planCrawl(root string, solution pubgrub.Solution) map[string]bool {
+	versions := make(map[string]string, len(solution))
+	for _, nv := range solution {
+		versions[nv.Name.Value()] = nv.Version.String()
+	}
+
+	admitted := make(map[string]bool, s.opts.MaxNodes)
+	frontier := map[string]*crawlEntry{root: {name: root}}
+	for len(admitted) < s.opts.MaxNodes && len(frontier) > 0 {
+		if s.ctx.Err() != nil || s.pastDeadline() {
+			return nil
+		}
+
+		queue := slices.SortedFunc(func(yield func(*crawlEntry) bool) {
+			for _, e := range frontier {
+				if !yield(e) {
+					return
+				}
+			}
+		}, compareCrawlEntries)
+		batch := queue[:min(len(queue), s.opts.Workers, s.opts.MaxNodes-len(admitted))]
+		for _, e := range batch {
+			delete(frontier, e.name)
+			admitted[e.name] = true
+		}
+
+		pkgs := ParallelMapOrdered(s.ctx, s.opts.Workers, batch, func(_ context.Context, e *crawlEntry) *Package {
+			return s.crawlPackage(e.name, versions[e.name])
+		})
+		for i, pkg := range pkgs {
+			parent := batch[i]
+			if pkg == nil || (s.opts.MaxDepth > 0 && parent.depth >= s.opts.MaxDepth) {
+				continue
+			}
+			counted := make(map[string]bool, len(pkg.Dependencies))
+			for _, dep := range pkg.Dependencies {
+				if dep.Name == "" || admitted[dep.Name] || counted[dep.Name] {
+					continue
+				}
+				counted[dep.Name] = true
+				e, ok := frontier[dep.Name]
+				if !ok {
+					e = &crawlEntry{name: dep.Name, depth: parent.depth + 1}
+					frontier[dep.Name] = e
+				}
+				e.fanIn++
+				e.popularity = max(e.popularity, pkg.Downloads)
+				e.depth = min(e.depth, parent.depth+1)
+			}
+		}
+	}
+
+	if len(frontier) == 0 {
+		return nil
+	}
+	return admitted
+}
+
+// crawlPackage returns a package for [pubgrubSource.planCrawl]: the solved
+// version when known, or else the latest release, which is cached for the
+// solver to reuse. Failed fetches return nil; the package is then admitted
+// without expanding its dependencies.
+func (s *pubgrubSource) crawlPackage(name, version string) *Package {
+	if version != "" {
+		pkg, _ := s.getPackage(name, version)
+		return pkg
+	}
+
+	observability.ResolverFromContext(s.ctx).OnFetchStart(s.ctx, name, 0)
+	ctx, stale := cache.TrackStale(s.fetchContext())
+	pkg, err := s.fetcher.Fetch(ctx, name, s.opts.Refresh)
+	depCount := 0
+	if pkg != nil {
+		depCount = len(pkg.Dependencies)
+	}
+	observability.ResolverFromContext(s.ctx).OnFetchComplete(s.ctx, name, 0, depCount, err)
+	if err != nil {
+		return nil
+	}
+	s.markStale(name, stale)
+
+	s.mu.Lock()
+	s.cache[name+"@"+pkg.Version] = pkg
+	s.mu.Unlock()
+	return pkg
+}
+
+// replan resets admission to the packages chosen by
+// [pubgrubSource.planCrawl] before solving again. Fetched packages, version
+// lists and hints are kept, and registry responses come from the HTTP cache
+// filled by the first solve.
+func (s *pubgrubSource) replan(root string, plan map[string]bool) {
+	s.mu.Lock()
+	s.seen = make(map[string]bool, len(plan))
+	s.depth = make(map[string]int, len(plan))
+	s.plan = plan
+	s.capped = false
+	s.mu.Unlock()
+	s.opts.Refresh = false
+	s.allowPackage(root, 0)
+}
@@ -0,0 +1,122 @@
+package deps
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPubGrubResolver_MaxNodesKeepsSharedPackages(t *testing.T) {
+	pkg := func(name string, downloads int, deps ...string) map[string]*Package {
+		p := &Package{Name: name, Version: "1.0.0", Downloads: downloads}
+		for _, d := range deps {
+			p.Dependencies = append(p.Dependencies, Dependency{Name: d, Constraint: ">=1.0.0"})
+		}
+		return map[string]*Package{"1.0.0": p}
+	}
+	fetcher := &mockVersionLister{
+		packages: map[string]map[string]*Package{
+			"root":   pkg("root", 0, "a", "b", "z"),
+			"a":      pkg("a", 10, "shared", "leaf-a"),
+			"b":      pkg("b", 10, "shared"),
+			"z":      pkg("z", 1000, "leaf-z"),
+			"shared": pkg("shared", 0),
+			"leaf-a": pkg("leaf-a", 0),
+			"leaf-z": pkg("leaf-z", 0),
+		},
+	}
+	resolver, err := NewPubGrubResolver("test", fetcher, mockParser{})
+	if err != nil {
+		t.Fatalf("NewPubGrubResolver failed: %v", err)
+	}
+
+	g, err := resolver.Resolve(context.Background(), "root", Options{MaxNodes: 6})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	for _, id := range []string{"root", "a", "b", "z", "shared", "leaf-z"} {
+		if _, ok := g.Node(id); !ok {
+			t.Errorf("expected %q in the truncated graph", id)
+		}
+	}
+	if _, ok := g.Node("leaf-a"); ok {
+		t.Error("leaf-a should give way to the shared package and the dependency of the popular package")
+	}
+	if !g.HasEdge("a", "shared") || !g.HasEdge("b", "shared") {
+		t.Error("edges to the shared package are missing")
+	}
+}
+
+// failingListFetcher fails to list the versions of one package.
+type failingListFetcher struct {
+	*mockVersionLister
+	fail string
+}
+
+func (f *failingListFetcher) ListVersions(ctx context.Context, name string, refresh bool) ([]string, error) {
+	if name == f.fail {
+		return nil, errors.New("registry unavailable")
+	}
+	return f.mockVersionLister.ListVersions(ctx, name, refresh)
+}
+
+func TestPubGrubResolver_MaxNodesKeepsFirstSolutionWhenReplanFails(t *testing.T) {
+	pkg := func(name string, downloads int, deps ...string) map[string]*Package {
+		p := &Package{Name: name, Version: "1.0.0", Downloads: downloads}
+		for _, d := range deps {
+			p.Dependencies = append(p.Dependencies, Dependency{Name: d, Constraint: ">=1.0.0"})
+		}
+		return map[string]*Package{"1.0.0": p}
+	}
+	// leaf-z only joins the graph in the replanned solve, which then fails
+	// because its versions can't be listed
+	fetcher := &failingListFetcher{
+		mockVersionLister: &mockVersionLister{
+			packages: map[string]map[string]*Package{
+				"root":   pkg("root", 0, "a", "b", "z"),
+				"a":      pkg("a", 10, "shared", "leaf-a"),
+				"b":      pkg("b", 10, "shared"),
+				"z":      pkg("z", 1000, "leaf-z"),
+				"shared": pkg("shared", 0),
+				"leaf-a": pkg("leaf-a", 0),
+				"leaf-z": pkg("leaf-z", 0),
+			},
+		},
+		fail: "leaf-z",
+	}
+	resolver, err := NewPubGrubResolver("test", fetcher, mockParser{})
+	if err != nil {
+		t.Fatalf("NewPubGrubResolver failed: %v", err)
+	}
+
+	g, err := resolver.Resolve(context.Background(), "root", Options{MaxNodes: 6})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if g.NodeCount() != 6 {
+		t.Errorf("NodeCount() = %d, want the 6 packages of the first solution", g.NodeCount())
+	}
+	if _, ok := g.Node("leaf-z"); ok {
+		t.Error("leaf-z should not be in the graph when the replanned solve failed")
+	}
+}
+
+func TestCompareCrawlEntries(t *testing.T) {
+	entries := []*crawlEntry{
+		{name: "deep", fanIn: 1, depth: 3},
+		{name: "popular", fanIn: 1, popularity: 500, depth: 5},
+		{name: "shared", fanIn: 3, depth: 4},
+		{name: "b", fanIn: 1, depth: 1},
+		{name: "a", fanIn: 1, depth: 1},
+	}
+	slices.SortFunc(entries, compareCrawlEntries)
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.name)
+	}
+	want := []string{"shared", "popular", "a", "b", "deep"}
+	if !slices.Equal(got, want) {
+		t.Errorf("crawl order = %v, want %v", got, want)
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/observability"
 )
 
 const (
@@ -144,6 +147,32 @@ func ParsePackageID(id string) (name, version, commit string) {
 	return name, versionPart, ""
 }
 
+// ParsePackageSpec splits a "name@version" package specifier, as given to
+// [Resolver.Resolve] or on the command line, into its name and version.
+// Unlike [ParsePackageID], it keeps the leading "@" of scoped npm names.
+//
+// Examples:
+//   - "requests@2.31.0" → ("requests", "2.31.0")
+//   - "@angular/core@17.0.0" → ("@angular/core", "17.0.0")
+//   - "golang.org/x/net@v0.20.0" → ("golang.org/x/net", "v0.20.0")
+//   - "requests" → ("requests", "")
+func ParsePackageSpec(spec string) (name, version string) {
+	if i := strings.LastIndex(spec, versionSeparator); i > 0 {
+		return spec[:i], spec[i+1:]
+	}
+	return spec, ""
+}
+
+// WithPackageSpec returns the name of the package spec designates and
+// opts with the version spec names, if any, as Version.
+func WithPackageSpec(spec string, opts Options) (string, Options) {
+	name, version := ParsePackageSpec(spec)
+	if version != "" {
+		opts.Version = version
+	}
+	return name, opts
+}
+
 // DependencyFromName creates a Dependency with only the name set.
 // This is a convenience function for backward compatibility when only
 // the package name is known.
@@ -193,11 +222,21 @@ type Options struct {
 	// only direct dependencies. Zero or negative values use DefaultMaxDepth (50).
 	MaxDepth int
 
-	// MaxNodes limits the total number of packages to fetch. When this limit
-	// is reached, deeper dependencies are ignored but already-queued packages
-	// may still be fetched. Zero or negative values use DefaultMaxNodes (5000).
+	// MaxNodes limits the total number of packages to fetch. When the graph
+	// holds more, the resolver keeps the packages most depended upon, then
+	// those pulled in by the most downloaded packages, rather than the first
+	// ones it reached, so the truncated graph keeps its shared foundations.
+	// Zero or negative values use DefaultMaxNodes (5000).
 	MaxNodes int
 
+	// MaxDuration limits how long the resolver crawls the dependency tree.
+	// Once it has passed, packages not yet expanded keep no dependencies and
+	// are marked [MetaTruncated], and the resolver returns the graph found
+	// so far. Package fetches and version listings still in flight are
+	// canceled at the deadline; packages with no version known by then are
+	// left out. Zero or negative values mean no limit.
+	MaxDuration time.Duration
+
 	// Workers is the number of concurrent goroutines for fetching packages.
 	// Higher values increase parallelism but may trigger rate limits.
 	// Zero or negative values use DefaultWorkers (20).
@@ -217,11 +256,16 @@ type Options struct {
 	// after fetching each package. Nil or empty is safe.
 	MetadataProviders []MetadataProvider
 
-	// Logger is an optional callback for progress and error messages. If nil,
-	// WithDefaults replaces it with a no-op logger. The format string follows
-	// fmt.Printf conventions. Logger may be called concurrently from multiple
-	// goroutines and must be safe for concurrent use.
-	Logger func(string, ...any)
+	// EnrichFilter limits enrichment by MetadataProviders to the packages
+	// near the root or with many dependents. The zero value enriches every
+	// package.
+	EnrichFilter EnrichFilter
+
+	// Logger receives progress and non-fatal errors as structured records,
+	// such as packages that failed to fetch or enrich. If nil, WithDefaults
+	// replaces it with a no-op logger. Logger is called concurrently from
+	// multiple goroutines and must be safe for concurrent use.
+	Logger observability.Logger
 
 	// IncludePrerelease controls whether prerelease versions (alpha, beta, rc,
 	// dev, etc.) are considered during resolution. When false, the resolver
@@ -234,6 +278,24 @@ type Options struct {
 	// Empty means use the language-specific default (e.g., "3.11" for Python).
 	RuntimeVersion string
 
+	// Platform is the target operating system for environment marker
+	// evaluation: "linux", "darwin" or "windows". For Python, this filters
+	// deps with markers like `sys_platform == "win32"`. Empty means Linux.
+	// For Ruby, it picks the native gems built for the platform, which may
+	// also be a RubyGems platform such as "aarch64-linux"; empty means
+	// pure-Ruby gems only.
+	Platform string
+
+	// IncludeIndirect follows requirements marked "// indirect" in go.mod
+	// files, which are otherwise only used to select versions. Modules
+	// reached only through them are marked "indirect". Only Go uses it.
+	IncludeIndirect bool
+
+	// SparseIndex resolves crates from the sparse crates.io index, which
+	// lists every version's dependencies in one file per crate, instead of
+	// the rate-limited API. Only Rust uses it.
+	SparseIndex bool
+
 	// URLProvider fetches repository URLs from the package registry.
 	// Used by EnrichGraph to populate PackageRef.ProjectURLs for manifest parsing.
 	// If nil, packages without URLs in node metadata won't be enriched with
@@ -247,7 +309,7 @@ type Options struct {
 //   - MaxDepth: DefaultMaxDepth (50)
 //   - MaxNodes: DefaultMaxNodes (5000)
 //   - CacheTTL: DefaultCacheTTL (24h)
-//   - Logger: no-op function if nil
+//   - Logger: no-op logger if nil
 //
 // All other fields (Refresh, MetadataProviders) are preserved as-is, including
 // nil slices. The original Options value is not modified.
@@ -269,7 +331,7 @@ func (o Options) WithDefaults() Options {
 		opts.CacheTTL = DefaultCacheTTL
 	}
 	if opts.Logger == nil {
-		opts.Logger = func(string, ...any) {}
+		opts.Logger = observability.NoopLogger{}
 	}
 	if opts.DependencyScope == "" {
 		opts.DependencyScope = DependencyScopeProdOnly
@@ -317,6 +379,14 @@ type BatchMetadataProvider interface {
 	EnrichBatch(ctx context.Context, pkgs []*PackageRef, refresh bool) (map[string]map[string]any, error)
 }
 
+// PartialProvider is implemented by metadata providers that may leave
+// packages out of enrichment, for instance when an API quota runs low.
+type PartialProvider interface {
+	// Skipped returns the names of the packages left unenriched, or only
+	// partly enriched, so far.
+	Skipped() []string
+}
+
 // URLProvider fetches repository URLs for packages from a registry.
 // Used by EnrichGraph to populate PackageRef.ProjectURLs when parsing manifest files.
 // This enables GitHub enrichment for lock files and other manifests that don't
@@ -362,6 +432,11 @@ type PackageRef struct {
 	// ManifestFile is the associated manifest type (e.g., "poetry", "cargo")
 	// when the package comes from manifest parsing. Empty for registry-only packages.
 	ManifestFile string
+
+	// Dependents is the number of packages that depend on this one, directly
+	// or transitively, set by [RankByDependents]. Providers with a limited
+	// API budget spend it on the packages with the most dependents first.
+	Dependents int
 }
 
 // IncompatibleRuntimeError is returned when a package requires a runtime version
@@ -437,6 +512,29 @@ type Package struct {
 	// on the registry. Zero if unavailable. Not all registries provide this.
 	Downloads int
 
+	// InstallSize is the unpacked size of the package in bytes. Zero if
+	// unavailable; currently only npm publishes it.
+	InstallSize int64
+
+	// Bundled lists the dependencies shipped inside the package itself, like
+	// npm's bundleDependencies. They are not resolved, and their size is
+	// part of InstallSize. May be nil.
+	Bundled []string
+
+	// Yanked reports that the release was withdrawn from the registry, like
+	// PyPI's yanked releases. Resolution only picks yanked releases that are
+	// pinned exactly.
+	Yanked bool
+
+	// Abandoned reports that the maintainers gave up the package, like
+	// Packagist's abandoned flag. Brittle detection treats it like an
+	// archived repository.
+	Abandoned bool
+
+	// Replacement is the package the registry suggests instead of an
+	// abandoned one. May be empty.
+	Replacement string
+
 	// Repository is the source code repository URL (e.g., GitHub, GitLab).
 	// May be empty if not specified in registry metadata.
 	Repository string
@@ -493,10 +591,55 @@ func (p *Package) SetDependenciesFromNames(names []string) {
 	}
 }
 
+// Node metadata keys describing how current a resolved version is. The
+// resolver sets them when the registry lists the package's versions.
+const (
+	MetaLatestVersion  = "latest_version"  // Newest stable version the registry lists
+	MetaVersionsBehind = "versions_behind" // Number of listed versions newer than the resolved one
+)
+
+// MetaStale is the node metadata key the resolver sets to true when a
+// package's registry data could not be fetched and the last cached copy,
+// though expired, was used instead of dropping the package.
+const MetaStale = "stale"
+
+// MetaTruncated is the node metadata key the resolver sets to true on
+// packages whose dependencies were not followed because
+// [Options.MaxDuration] ran out.
+const MetaTruncated = "truncated"
+
+// MetaInstallSize is the node metadata key holding [Package.InstallSize].
+const MetaInstallSize = "install_size"
+
+// MetaBundled is the node metadata key holding [Package.Bundled].
+const MetaBundled = "bundled"
+
+// MetaYanked is the node metadata key holding [Package.Yanked].
+const MetaYanked = "yanked"
+
+// Node metadata keys holding [Package.Abandoned] and [Package.Replacement].
+const (
+	MetaAbandoned   = "abandoned"
+	MetaReplacement = "replacement"
+)
+
+// MetaSource is the node metadata key recording where a node's version came
+// from: [SourceRegistry], [SourceLockfile] or [SourceSBOM].
+const MetaSource = "source"
+
+// Node sources stored under [MetaSource].
+const (
+	SourceRegistry = "registry" // Chosen by resolving against the registry
+	SourceLockfile = "lockfile" // Pinned by a lock file
+	SourceSBOM     = "sbom"     // Listed in a software bill of materials
+)
+
 // Metadata converts Package fields to a map for node metadata.
 //
 // The returned map always contains "version". Optional fields (description,
-// license, author, downloads, commit) are included only if non-empty/non-zero.
+// license, author, downloads, install size, bundled dependencies, yanked and
+// abandoned flags, replacement, commit)
+// are included only if non-empty/non-zero.
 //
 // This map is suitable for use as dag.Node.Meta and can be further enriched
 // by [MetadataProvider] implementations. The map is newly allocated and safe
@@ -521,6 +664,21 @@ func (p *Package) Metadata() map[string]any {
 	if p.Downloads > 0 {
 		m["downloads"] = p.Downloads
 	}
+	if p.InstallSize > 0 {
+		m[MetaInstallSize] = p.InstallSize
+	}
+	if len(p.Bundled) > 0 {
+		m[MetaBundled] = slices.Clone(p.Bundled)
+	}
+	if p.Yanked {
+		m[MetaYanked] = true
+	}
+	if p.Abandoned {
+		m[MetaAbandoned] = true
+	}
+	if p.Replacement != "" {
+		m[MetaReplacement] = p.Replacement
+	}
 	if p.HomePage != "" {
 		m["homepage"] = p.HomePage
 	}
@@ -1,8 +1,14 @@
 package deps
 
 import (
+	"bytes"
+	"log/slog"
+	"slices"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
 )
 
 func TestOptionsWithDefaults(t *testing.T) {
@@ -81,14 +87,43 @@ func TestOptionsWithDefaults(t *testing.T) {
 }
 
 func TestOptionsWithDefaultsPreservesLogger(t *testing.T) {
-	called := false
-	logger := func(string, ...any) { called = true }
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
 
 	opts := Options{Logger: logger}.WithDefaults()
-	opts.Logger("test")
+	opts.Logger.Warn("test", "package", "requests")
+
+	if !strings.Contains(buf.String(), "package=requests") {
+		t.Errorf("custom logger should be preserved, got %q", buf.String())
+	}
+}
 
-	if !called {
-		t.Error("custom logger should be preserved")
+func TestParsePackageSpec(t *testing.T) {
+	tests := []struct{ spec, name, version string }{
+		{"requests", "requests", ""},
+		{"requests@2.31.0", "requests", "2.31.0"},
+		{"@angular/core", "@angular/core", ""},
+		{"@angular/core@17.0.0", "@angular/core", "17.0.0"},
+		{"golang.org/x/net@v0.20.0", "golang.org/x/net", "v0.20.0"},
+		{"com.google.guava:guava@33.0.0-jre", "com.google.guava:guava", "33.0.0-jre"},
+		{"pkg@", "pkg", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if name, version := ParsePackageSpec(tt.spec); name != tt.name || version != tt.version {
+			t.Errorf("ParsePackageSpec(%q) = %q, %q; want %q, %q", tt.spec, name, version, tt.name, tt.version)
+		}
+	}
+}
+
+func TestWithPackageSpec(t *testing.T) {
+	name, opts := WithPackageSpec("serde@1.0.0", Options{Version: "2.0.0"})
+	if name != "serde" || opts.Version != "1.0.0" {
+		t.Errorf("WithPackageSpec() = %q, %q; want the specifier's version", name, opts.Version)
+	}
+	name, opts = WithPackageSpec("serde", Options{Version: "2.0.0"})
+	if name != "serde" || opts.Version != "2.0.0" {
+		t.Errorf("WithPackageSpec() = %q, %q; want Options.Version kept", name, opts.Version)
 	}
 }
 
@@ -111,13 +146,21 @@ func TestPackageMetadata(t *testing.T) {
 				License:     "MIT",
 				Author:      "Test Author",
 				Downloads:   1000,
+				InstallSize: 52_000,
+				Yanked:      true,
+				Abandoned:   true,
+				Replacement: "new/package",
 			},
 			want: map[string]any{
-				"version":     "2.0.0",
-				"description": "A test package",
-				"license":     "MIT",
-				"author":      "Test Author",
-				"downloads":   1000,
+				"version":      "2.0.0",
+				"description":  "A test package",
+				"license":      "MIT",
+				"author":       "Test Author",
+				"downloads":    1000,
+				"install_size": int64(52_000),
+				"yanked":       true,
+				"abandoned":    true,
+				"replacement":  "new/package",
 			},
 		},
 		{
@@ -152,6 +195,18 @@ func TestPackageMetadata(t *testing.T) {
 	}
 }
 
+func TestPackageMetadata_Bundled(t *testing.T) {
+	pkg := Package{Version: "1.0.0", Bundled: []string{"a", "b"}}
+	got, ok := pkg.Metadata()[MetaBundled].([]string)
+	if !ok || !slices.Equal(got, pkg.Bundled) {
+		t.Fatalf("Metadata()[%q] = %v, want %v", MetaBundled, got, pkg.Bundled)
+	}
+	got[0] = "changed"
+	if pkg.Bundled[0] != "a" {
+		t.Error("Metadata() shares the Bundled slice")
+	}
+}
+
 func TestPackageRef(t *testing.T) {
 	tests := []struct {
 		name string
@@ -268,3 +323,22 @@ func TestPackageRefDoesNotModifyOriginal(t *testing.T) {
 		t.Error("Ref() should clone ProjectURLs, not reference original")
 	}
 }
+
+func TestMarkSource(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: ProjectRootNodeID, Meta: dag.Metadata{"virtual": true}})
+	_ = g.AddNode(dag.Node{ID: "serde"})
+	_ = g.AddNode(dag.Node{ID: "tokio", Meta: dag.Metadata{MetaSource: SourceSBOM}})
+
+	MarkSource(g, SourceLockfile)
+
+	if n, _ := g.Node("serde"); n.Meta[MetaSource] != SourceLockfile {
+		t.Errorf("serde source = %v, want %s", n.Meta[MetaSource], SourceLockfile)
+	}
+	if n, _ := g.Node("tokio"); n.Meta[MetaSource] != SourceSBOM {
+		t.Errorf("existing sources should be kept, got %v", n.Meta[MetaSource])
+	}
+	if n, _ := g.Node(ProjectRootNodeID); n.Meta[MetaSource] != nil {
+		t.Errorf("virtual nodes should not get a source, got %v", n.Meta[MetaSource])
+	}
+}
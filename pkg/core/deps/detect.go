@@ -0,0 +1,232 @@
+package deps
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+// skipDirs are directories FindManifests never enters: installed
+// dependencies and build output hold other projects' manifests.
+// Directories starting with a dot (.git, .venv) are skipped as well.
+var skipDirs = map[string]bool{
+	"node_modules":  true,
+	"vendor":        true,
+	"venv":          true,
+	"site-packages": true,
+	"__pycache__":   true,
+	"target":        true,
+	"build":         true,
+	"dist":          true,
+}
+
+// FoundManifest is a manifest file found by [FindManifests].
+type FoundManifest struct {
+	// Path is the manifest's path, joined onto the searched directory.
+	Path string
+
+	// Dir is the manifest's directory relative to the searched one, "." at
+	// the top.
+	Dir string
+
+	// Filename is the manifest's base name (e.g., "poetry.lock").
+	Filename string
+
+	// Language is the language whose parser reads the manifest.
+	Language string
+
+	// Lockfile reports whether the manifest lists the full transitive
+	// closure (see [ManifestParser.IncludesTransitive]).
+	Lockfile bool
+}
+
+// FindManifests walks dir for manifests supported by languages and picks
+// one per directory and language: a lockfile over a requirement file,
+// otherwise the language's first entry in ManifestTypes. A project with
+// both pyproject.toml and poetry.lock is therefore read from poetry.lock.
+//
+// A filename claimed by several languages, like a package.json shared by
+// two JavaScript runtimes, goes to the most confident of them (see
+// [DetectManifests]).
+//
+// Results are ordered shallowest first, then by directory and language,
+// so the first entry is the most likely project root.
+func FindManifests(dir string, languages []*Language) ([]FoundManifest, error) {
+	type key struct{ dir, lang string }
+	best := make(map[key]FoundManifest)
+	rank := func(m FoundManifest, lang *Language) int {
+		typ, _ := lang.ManifestType(m.Filename)
+		r := slices.Index(lang.ManifestTypes, typ)
+		if r < 0 {
+			r = len(lang.ManifestTypes)
+		}
+		if !m.Lockfile {
+			r += len(lang.ManifestTypes) + 1
+		}
+		return r
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || skipDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		claimed := claimants(languages, d.Name())
+		if len(claimed) > 1 {
+			if c, err := DetectManifestFile(path, claimed); err == nil && len(c) > 0 {
+				claimed = []*Language{c[0].Language}
+			}
+		}
+		for _, lang := range claimed {
+			rel, err := filepath.Rel(dir, filepath.Dir(path))
+			if err != nil {
+				return err
+			}
+			m := FoundManifest{Path: path, Dir: filepath.ToSlash(rel), Filename: d.Name(), Language: lang.Name}
+			if p, ok := lang.Manifest(d.Name(), nil); ok {
+				m.Lockfile = p.IncludesTransitive()
+			}
+			k := key{m.Dir, lang.Name}
+			if prev, ok := best[k]; !ok || rank(m, lang) < rank(prev, lang) {
+				best[k] = m
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan %s: %w", dir, err)
+	}
+
+	found := make([]FoundManifest, 0, len(best))
+	for _, m := range best {
+		found = append(found, m)
+	}
+	slices.SortFunc(found, func(a, b FoundManifest) int {
+		if c := depth(a.Dir) - depth(b.Dir); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Dir, b.Dir); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Language, b.Language)
+	})
+	return found, nil
+}
+
+func depth(dir string) int {
+	if dir == "." {
+		return 0
+	}
+	return strings.Count(dir, "/") + 1
+}
+
+// Project is one manifest's dependency graph, for [MergeProjects].
+type Project struct {
+	// Name is the project's node ID in the merged graph, such as
+	// "web/package.json". It must be unique among the projects.
+	Name string
+
+	// Language is the language the graph was resolved for.
+	Language string
+
+	// Graph is the resolved graph, usually rooted at [ProjectRootNodeID].
+	Graph *dag.DAG
+}
+
+// MergeProjects combines the graphs of several manifests into one graph
+// under a virtual [ProjectRootNodeID] root, with each project as a child
+// of the root and a parent of its direct dependencies.
+//
+// Packages shared by projects of the same language become one node. A
+// package whose ID is already taken by another language is renamed
+// "<language>:<id>", so ecosystems never share nodes. The merged graph
+// keeps the first project's metadata; "language" is kept only when every
+// project has the same one.
+func MergeProjects(projects []Project) (*dag.DAG, error) {
+	meta := dag.Metadata{}
+	if len(projects) > 0 {
+		for k, v := range projects[0].Graph.Meta() {
+			meta[k] = v
+		}
+	}
+	delete(meta, "runtime_version")
+	delete(meta, "runtime_source")
+	for _, p := range projects {
+		if p.Language != projects[0].Language {
+			delete(meta, "language")
+			break
+		}
+	}
+
+	merged := dag.New(meta)
+	_ = merged.AddNode(dag.Node{ID: ProjectRootNodeID, Meta: dag.Metadata{"virtual": true}})
+	language := map[string]string{} // node ID → language that owns it
+	for _, p := range projects {
+		if err := merged.AddNode(dag.Node{ID: p.Name, Meta: dag.Metadata{"language": p.Language}}); err != nil {
+			return nil, fmt.Errorf("add project %s: %w", p.Name, err)
+		}
+		language[p.Name] = ""
+		if err := merged.AddEdge(dag.Edge{From: ProjectRootNodeID, To: p.Name}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, p := range projects {
+		ids := map[string]string{ProjectRootNodeID: p.Name}
+		for _, n := range p.Graph.Nodes() {
+			if n.ID == ProjectRootNodeID {
+				continue
+			}
+			id := n.ID
+			if owner, taken := language[id]; taken && owner != p.Language {
+				id = p.Language + ":" + n.ID
+			}
+			ids[n.ID] = id
+			if _, ok := merged.Node(id); ok {
+				continue
+			}
+			node := *n
+			node.ID = id
+			if err := merged.AddNode(node); err != nil {
+				return nil, err
+			}
+			language[id] = p.Language
+		}
+
+		edges := map[[2]string]bool{}
+		for _, e := range merged.Edges() {
+			edges[[2]string{e.From, e.To}] = true
+		}
+		add := func(e dag.Edge) error {
+			if edges[[2]string{e.From, e.To}] {
+				return nil
+			}
+			edges[[2]string{e.From, e.To}] = true
+			return merged.AddEdge(e)
+		}
+		for _, e := range p.Graph.Edges() {
+			if err := add(dag.Edge{From: ids[e.From], To: ids[e.To], Meta: e.Meta}); err != nil {
+				return nil, err
+			}
+		}
+		// Graphs not rooted at the project marker hang off the project.
+		for _, n := range p.Graph.Sources() {
+			if n.ID != ProjectRootNodeID {
+				if err := add(dag.Edge{From: p.Name, To: ids[n.ID]}); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return merged, nil
+}
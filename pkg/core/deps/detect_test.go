@@ -0,0 +1,158 @@
+package deps
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+type lockParser struct{ mockManifestParser }
+
+func (lockParser) IncludesTransitive() bool { return true }
+
+func detectLanguages() []*Language {
+	newManifest := func(name string, _ Resolver) ManifestParser {
+		if strings.HasSuffix(name, "-lock") {
+			return &lockParser{mockManifestParser{typeName: name}}
+		}
+		return &mockManifestParser{typeName: name}
+	}
+	return []*Language{
+		{
+			Name:            "python",
+			ManifestTypes:   []string{"poetry-lock", "pyproject", "requirements"},
+			ManifestAliases: map[string]string{"poetry.lock": "poetry-lock", "pyproject.toml": "pyproject", "requirements.txt": "requirements"},
+			NewManifest:     newManifest,
+		},
+		{
+			Name:            "javascript",
+			ManifestTypes:   []string{"package", "package-lock"},
+			ManifestAliases: map[string]string{"package.json": "package", "package-lock.json": "package-lock"},
+			NewManifest:     newManifest,
+		},
+	}
+}
+
+func TestFindManifests(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{
+		"requirements.txt", "pyproject.toml", "poetry.lock",
+		"web/package.json", "web/package-lock.json",
+		"web/node_modules/left-pad/package.json",
+		".git/package.json",
+		"tools/requirements.txt",
+		"README.md",
+	} {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, err := FindManifests(dir, detectLanguages())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range found {
+		got = append(got, m.Dir+"/"+m.Filename)
+	}
+	want := []string{"./poetry.lock", "tools/requirements.txt", "web/package-lock.json"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("FindManifests = %v, want %v", got, want)
+	}
+	if !found[0].Lockfile || found[1].Lockfile || found[0].Language != "python" || found[2].Language != "javascript" {
+		t.Errorf("found = %+v", found)
+	}
+	if found[2].Path != filepath.Join(dir, "web", "package-lock.json") {
+		t.Errorf("Path = %s", found[2].Path)
+	}
+}
+
+func TestFindManifests_SharedFilename(t *testing.T) {
+	dir := t.TempDir()
+	for f, content := range map[string]string{
+		"app/package.json": `{"dependencies": {"react": "^18"}}`,
+		"cli/package.json": `{"deno": {"tasks": {}}}`,
+	} {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, err := FindManifests(dir, append(detectLanguages(), denoLanguage()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 2 || found[0].Language != "javascript" || found[1].Language != "deno" {
+		t.Errorf("FindManifests = %+v, want app for javascript and cli for deno", found)
+	}
+}
+
+func projectGraph(lang string, edges ...[2]string) *dag.DAG {
+	g := dag.New(dag.Metadata{"language": lang, "runtime_version": "1"})
+	_ = g.AddNode(dag.Node{ID: ProjectRootNodeID})
+	for _, e := range edges {
+		for _, id := range e {
+			if _, ok := g.Node(id); !ok {
+				_ = g.AddNode(dag.Node{ID: id})
+			}
+		}
+		_ = g.AddEdge(dag.Edge{From: e[0], To: e[1]})
+	}
+	return g
+}
+
+func TestMergeProjects(t *testing.T) {
+	merged, err := MergeProjects([]Project{
+		{Name: "poetry.lock", Language: "python", Graph: projectGraph("python",
+			[2]string{ProjectRootNodeID, "requests"}, [2]string{"requests", "urllib3"})},
+		{Name: "tools/requirements.txt", Language: "python", Graph: projectGraph("python",
+			[2]string{ProjectRootNodeID, "urllib3"})},
+		{Name: "web/package.json", Language: "javascript", Graph: projectGraph("javascript",
+			[2]string{ProjectRootNodeID, "requests"})},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range [][2]string{
+		{ProjectRootNodeID, "poetry.lock"},
+		{"poetry.lock", "requests"},
+		{"requests", "urllib3"},
+		{"tools/requirements.txt", "urllib3"},
+		{"web/package.json", "javascript:requests"},
+	} {
+		if !hasEdge(merged, e[0], e[1]) {
+			t.Errorf("missing edge %s → %s", e[0], e[1])
+		}
+	}
+	if merged.NodeCount() != 7 {
+		t.Errorf("NodeCount = %d, want 7", merged.NodeCount())
+	}
+	if _, ok := merged.Meta()["language"]; ok {
+		t.Error("mixed-language graph kept a language")
+	}
+	if _, ok := merged.Meta()["runtime_version"]; ok {
+		t.Error("merged graph kept a runtime version")
+	}
+}
+
+func hasEdge(g *dag.DAG, from, to string) bool {
+	for _, c := range g.Children(from) {
+		if c == to {
+			return true
+		}
+	}
+	return false
+}
@@ -36,6 +36,10 @@
 //	    MaxNodes: 1000,
 //	})
 //
+// The root is the latest version unless a specifier such as
+// "fastapi@0.110.0" or [Options.Version] names another; see
+// [ParsePackageSpec].
+//
 // The resolver crawls dependencies concurrently:
 //
 //  1. Fetches the root package from the registry
@@ -53,7 +57,8 @@
 //   - CacheTTL: HTTP cache duration (default 24h)
 //   - Refresh: Bypass cache to force fresh data
 //   - MetadataProviders: External enrichment sources (e.g., GitHub, GitLab)
-//   - Logger: Progress and error callback (must be goroutine-safe)
+//   - EnrichFilter: Enrich only packages near the root or with many dependents
+//   - Logger: Structured logger for progress and errors (must be goroutine-safe)
 //
 // # Package Data
 //
@@ -66,8 +71,12 @@
 //   - Downloads: Popularity metric (when available from the registry)
 //
 // Use [Package.Metadata] to convert package fields to a map suitable for
-// [dag.Node] metadata. Use [Package.Ref] to create a [PackageRef] for
-// metadata provider lookups.
+// [dag.Node] metadata, and [MarkSource] to record under [MetaSource]
+// whether node versions came from the registry or a lock file. Use
+// [Package.Ref] to create a [PackageRef] for metadata provider lookups.
+// [ClassifyPackages] marks first-party packages with [MetaInternal], by
+// name pattern or because the lock file shows they come from the project
+// itself.
 //
 // # Manifest Parsing
 //
@@ -83,11 +92,19 @@
 // Manifest parsers implement [ManifestParser] and vary in completeness:
 //
 //   - Direct dependencies only: requirements.txt, package.json (base)
-//   - Full transitive closure: poetry.lock, Cargo.lock, package-lock.json
+//   - Full transitive closure: poetry.lock, Cargo.lock, package-lock.json,
+//     yarn.lock, pnpm-lock.yaml
 //
 // Use [ManifestParser.IncludesTransitive] to check if additional resolution
 // is needed. Use [DetectManifest] to find the right parser for a file.
 //
+// When the language is unknown, [DetectManifests] ranks every language that
+// may read a file by confidence, from its filename and, through each
+// language's [Language.SniffManifest], its content:
+//
+//	candidates, _ := deps.DetectManifestFile("requirements-dev.txt", languages.All)
+//	lang, typ := candidates[0].Language, candidates[0].Type
+//
 // # Metadata Enrichment
 //
 // [MetadataProvider] implementations add supplementary data from external sources:
@@ -113,14 +130,20 @@
 //
 //   - [python]: PyPI registry, poetry.lock, requirements.txt, pyproject.toml
 //   - [rust]: crates.io registry, Cargo.toml, Cargo.lock
-//   - [javascript]: npm registry, package.json, package-lock.json
+//   - [javascript]: npm registry, package.json, package-lock.json, yarn.lock,
+//     pnpm-lock.yaml
 //   - [ruby]: RubyGems registry, Gemfile, Gemfile.lock
 //   - [php]: Packagist registry, composer.json, composer.lock
 //   - [java]: Maven Central registry, pom.xml
 //   - [golang]: Go Module Proxy, go.mod
+//   - [dotnet]: NuGet registry, .csproj, packages.config
 //
-// Language subpackages also provide registry-specific [Fetcher] implementations
-// that wrap HTTP clients from the [integrations] package.
+// Language subpackages fetch packages by wrapping an
+// [integrations.RegistryClient] in a [RegistryFetcher], which converts its
+// metadata with [PackageFromInfo] and rejects versions whose runtime
+// constraint excludes the target runtime. Where a registry needs more, such
+// as npm dist-tags or native gem platforms, the language embeds the
+// RegistryFetcher and adds only that step.
 //
 // # Concurrency
 //
@@ -155,5 +178,6 @@
 // [php]: github.com/stacktower-io/stacktower/pkg/core/deps/php
 // [java]: github.com/stacktower-io/stacktower/pkg/core/deps/java
 // [golang]: github.com/stacktower-io/stacktower/pkg/core/deps/golang
+// [dotnet]: github.com/stacktower-io/stacktower/pkg/core/deps/dotnet
 // [metadata.GitHub]: github.com/stacktower-io/stacktower/pkg/core/deps/metadata.GitHub
 package deps
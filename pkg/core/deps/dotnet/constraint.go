@@ -0,0 +1,159 @@
+package dotnet
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/contriboss/pubgrub-go"
+
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/integrations/nuget"
+)
+
+// NuGetMatcher implements constraint matching for NuGet version ranges.
+// NuGet version syntax:
+// - Minimum: 1.0 (1.0 or newer)
+// - Exact: [1.0]
+// - Range: [1.0,2.0), (1.0,2.0], [1.0,), (,2.0]
+// - Floating: 1.*, 1.2.*, *, 1.0.0-*
+type NuGetMatcher struct{}
+
+// Ensure NuGetMatcher implements ConstraintParser
+var _ deps.ConstraintParser = NuGetMatcher{}
+
+// NuGetVersion is a PubGrub Version that orders like NuGet, by
+// [nuget.CompareVersions], so that 10.0.0 is newer than 2.0.0 and
+// 1.0.0-rc.1 older than 1.0.0. It holds the normalized version, the form
+// the registry lists, so "1.0" and "1.0.0.0" are one version.
+type NuGetVersion string
+
+func (v NuGetVersion) String() string { return string(v) }
+
+func (v NuGetVersion) Sort(other pubgrub.Version) int {
+	return nuget.CompareVersions(string(v), other.String())
+}
+
+// ParseVersion converts a NuGet version string to a normalized
+// [NuGetVersion].
+func (NuGetMatcher) ParseVersion(version string) pubgrub.Version {
+	if !nuget.ValidVersion(version) {
+		return nil
+	}
+	return NuGetVersion(nuget.NormalizeVersion(version))
+}
+
+// ParseConstraint converts a NuGet version range to a PubGrub Condition.
+// It returns nil for malformed ranges.
+func (NuGetMatcher) ParseConstraint(constraint string) pubgrub.Condition {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return nil
+	}
+	if set := rangeToSet(constraint); set != nil {
+		return pubgrub.NewVersionSetCondition(set)
+	}
+	return nil
+}
+
+// rangeToSet converts a NuGet version range to a version set, or nil when
+// the range is malformed.
+func rangeToSet(r string) pubgrub.VersionSet {
+	if strings.Contains(r, "*") {
+		return floatingToSet(r)
+	}
+	if !strings.HasPrefix(r, "[") && !strings.HasPrefix(r, "(") {
+		v, ok := parseBound(r)
+		if !ok {
+			return nil
+		}
+		return pubgrub.NewLowerBoundVersionSet(v, true)
+	}
+
+	if len(r) < 2 || !strings.ContainsAny(r[len(r)-1:], "])") {
+		return nil
+	}
+	lowerIncl, upperIncl := r[0] == '[', r[len(r)-1] == ']'
+	inner := r[1 : len(r)-1]
+
+	lower, upper, isRange := strings.Cut(inner, ",")
+	if !isRange {
+		// [1.0] is exact; (1.0) matches nothing and is malformed
+		v, ok := parseBound(inner)
+		if !ok || !lowerIncl || !upperIncl {
+			return nil
+		}
+		return pubgrub.NewVersionRangeSet(v, true, v, true)
+	}
+
+	lower, upper = strings.TrimSpace(lower), strings.TrimSpace(upper)
+	switch {
+	case lower == "" && upper == "":
+		return pubgrub.FullVersionSet()
+	case lower == "":
+		v, ok := parseBound(upper)
+		if !ok {
+			return nil
+		}
+		return pubgrub.NewUpperBoundVersionSet(v, upperIncl)
+	case upper == "":
+		v, ok := parseBound(lower)
+		if !ok {
+			return nil
+		}
+		return pubgrub.NewLowerBoundVersionSet(v, lowerIncl)
+	}
+	lo, okLo := parseBound(lower)
+	hi, okHi := parseBound(upper)
+	if !okLo || !okHi {
+		return nil
+	}
+	return pubgrub.NewVersionRangeSet(lo, lowerIncl, hi, upperIncl)
+}
+
+// floatingToSet converts a floating version to a version set: "*" is any
+// version, "1.*" is [1.0.0, 2.0.0), "1.2.*" is [1.2.0, 1.3.0) and
+// "1.0.0-*" is 1.0.0 or newer, prereleases of 1.0.0 included.
+func floatingToSet(r string) pubgrub.VersionSet {
+	if r == "*" {
+		return pubgrub.FullVersionSet()
+	}
+	if base, ok := strings.CutSuffix(r, "-*"); ok {
+		v, ok := parseBound(base + "-0")
+		if !ok {
+			return nil
+		}
+		return pubgrub.NewLowerBoundVersionSet(v, true)
+	}
+
+	prefix, ok := strings.CutSuffix(r, ".*")
+	if !ok {
+		return nil
+	}
+	nums := strings.Split(prefix, ".")
+	if len(nums) > 3 {
+		return nil
+	}
+	last, err := strconv.Atoi(nums[len(nums)-1])
+	if err != nil {
+		return nil
+	}
+	lo, ok := parseBound(prefix)
+	if !ok {
+		return nil
+	}
+	nums[len(nums)-1] = strconv.Itoa(last + 1)
+	hi, ok := parseBound(strings.Join(nums, "."))
+	if !ok {
+		return nil
+	}
+	return pubgrub.NewVersionRangeSet(lo, true, hi, false)
+}
+
+// parseBound parses a version at a range bound.
+func parseBound(s string) (NuGetVersion, bool) {
+	s = strings.TrimSpace(s)
+	if !nuget.ValidVersion(s) {
+		return "", false
+	}
+	return NuGetVersion(nuget.NormalizeVersion(s)), true
+}
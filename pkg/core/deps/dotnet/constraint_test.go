@@ -0,0 +1,120 @@
+package dotnet
+
+import (
+	"testing"
+)
+
+func TestNuGetMatcher_ParseVersion(t *testing.T) {
+	m := NuGetMatcher{}
+
+	tests := []struct {
+		input string
+		want  string
+		valid bool
+	}{
+		{"13.0.3", "13.0.3", true},
+		{"1.0", "1.0.0", true},       // normalized like the registry
+		{"1.0.0.0", "1.0.0", true},   // zero revision dropped
+		{"4.5.0.1", "4.5.0.1", true}, // non-zero revision kept
+		{"1.0.0-RC1", "1.0.0-rc1", true},
+		{"1.0.0+sha.abc", "1.0.0", true}, // build metadata dropped
+		{"invalid", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v := m.ParseVersion(tt.input)
+			if tt.valid {
+				if v == nil {
+					t.Errorf("ParseVersion(%q) = nil, want non-nil", tt.input)
+					return
+				}
+				if got := v.String(); got != tt.want {
+					t.Errorf("ParseVersion(%q).String() = %q, want %q", tt.input, got, tt.want)
+				}
+			} else if v != nil {
+				t.Errorf("ParseVersion(%q) = %v, want nil", tt.input, v)
+			}
+		})
+	}
+}
+
+func TestNuGetMatcher_ParseConstraint(t *testing.T) {
+	m := NuGetMatcher{}
+
+	tests := []struct {
+		constraint string
+		version    string
+		matches    bool
+	}{
+		// Plain version is a minimum
+		{"1.0.0", "1.0.0", true},
+		{"1.0", "1.0.0", true},
+		{"1.0.0", "2.0.0", true},
+		{"1.0.0", "0.9.0", false},
+
+		// Exact match with brackets
+		{"[1.0.0]", "1.0.0", true},
+		{"[1.0]", "1.0.0.0", true},
+		{"[1.0.0]", "1.0.1", false},
+
+		// Ranges, with or without spaces
+		{"[1.0,2.0)", "1.0.0", true},
+		{"[1.0,2.0)", "1.5.0", true},
+		{"[1.0,2.0)", "2.0.0", false},
+		{"[3.1.1, 4.0.0)", "3.1.1", true},
+		{"[3.1.1, 4.0.0)", "4.0.0", false},
+		{"(1.0,2.0]", "1.0.0", false},
+		{"(1.0,2.0]", "2.0.0", true},
+		{"[1.0,)", "2.0.0", true},
+		{"[1.0,)", "0.9.0", false},
+		{"(,2.0]", "2.0.0", true},
+		{"(,2.0]", "2.0.1", false},
+		{"(,2.0)", "2.0.0", false},
+
+		// Ranges compare like NuGet: numerically, prereleases first
+		{"[1.0,2.0)", "10.0.0", false},
+		{"[2.0,)", "10.0.0", true},
+		{"[1.0,2.0)", "2.0.0-rc.1", true},
+
+		// Floating versions
+		{"*", "99.99.99", true},
+		{"1.*", "1.9.0", true},
+		{"1.*", "2.0.0", false},
+		{"1.*", "0.9.0", false},
+		{"6.0.*", "6.0.36", true},
+		{"6.0.*", "6.1.0", false},
+		{"1.0.0-*", "1.0.0-beta", true},
+		{"1.0.0-*", "1.2.0", true},
+		{"1.0.0-*", "0.9.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+"@"+tt.version, func(t *testing.T) {
+			cond := m.ParseConstraint(tt.constraint)
+			if cond == nil {
+				t.Fatalf("ParseConstraint(%q) = nil", tt.constraint)
+			}
+
+			v := m.ParseVersion(tt.version)
+			if v == nil {
+				t.Fatalf("ParseVersion(%q) = nil", tt.version)
+			}
+
+			if got := cond.Satisfies(v); got != tt.matches {
+				t.Errorf("Constraint %q, version %q: got Match=%v, want %v", tt.constraint, tt.version, got, tt.matches)
+			}
+		})
+	}
+}
+
+func TestNuGetMatcher_ParseConstraint_Invalid(t *testing.T) {
+	m := NuGetMatcher{}
+
+	for _, c := range []string{"", "abc", "[1.0", "(1.0)", "[a,b)", "1.x.*", "1.2.3.4.*"} {
+		if cond := m.ParseConstraint(c); cond != nil {
+			t.Errorf("ParseConstraint(%q) = %v, want nil", c, cond)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package dotnet
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/observability"
+)
+
+// projectExts are the extensions of the MSBuild project files the
+// ProjectParser reads: C#, F# and Visual Basic projects.
+var projectExts = []string{".csproj", ".fsproj", ".vbproj"}
+
+// centralPackagesFile declares package versions for all projects below it
+// when central package management is on.
+const centralPackagesFile = "Directory.Packages.props"
+
+// propertyRE matches MSBuild property references like $(SerilogVersion).
+var propertyRE = regexp.MustCompile(`\$\(([A-Za-z_][A-Za-z0-9_.-]*)\)`)
+
+// ProjectParser parses SDK-style MSBuild project files (.csproj, .fsproj,
+// .vbproj). It reads the PackageReference items, with versions from the
+// item, its VersionOverride or a Directory.Packages.props file, and
+// optionally resolves them via NuGet for the project's target framework.
+type ProjectParser struct {
+	resolver deps.Resolver
+}
+
+func (p *ProjectParser) Type() string             { return "csproj" }
+func (p *ProjectParser) IncludesTransitive() bool { return p.resolver != nil }
+
+func (p *ProjectParser) Supports(name string) bool {
+	for _, ext := range projectExts {
+		if strings.EqualFold(filepath.Ext(name), ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *ProjectParser) Parse(path string, opts deps.Options) (*deps.ManifestResult, error) {
+	opts = opts.WithDefaults()
+
+	proj, err := readProject(path)
+	if err != nil {
+		return nil, err
+	}
+	props := proj.properties()
+
+	central := map[string]string{}
+	if file := findUp(filepath.Dir(path), centralPackagesFile); file != "" {
+		cp, err := readProject(file)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range cp.properties() {
+			if _, ok := props[k]; !ok {
+				props[k] = v
+			}
+		}
+		for _, pv := range cp.packageVersions() {
+			central[NormalizeID(pv.Include)] = pv.version()
+		}
+	}
+
+	directDeps := projectDependencies(proj, props, central, opts.DependencyScope)
+
+	// Emit observability hooks for extracted dependencies
+	hooks := observability.ResolverFromContext(opts.Ctx)
+	for _, dep := range directDeps {
+		hooks.OnFetchStart(opts.Ctx, dep.Name, 0)
+		hooks.OnFetchComplete(opts.Ctx, dep.Name, 0, 0, nil)
+	}
+
+	framework := targetFramework(props)
+
+	var g *dag.DAG
+	if p.resolver != nil {
+		g, err = deps.ResolveAndMerge(opts.Ctx, p.resolverFor(framework, opts), directDeps, opts)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		g = deps.ShallowGraphFromDeps(directDeps)
+	}
+
+	root := cmp.Or(props["packageid"], props["assemblyname"])
+	if root == "" {
+		root = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	return &deps.ManifestResult{
+		Graph:              g,
+		Type:               p.Type(),
+		IncludesTransitive: p.resolver != nil,
+		RootPackage:        root,
+		RuntimeVersion:     framework,
+	}, nil
+}
+
+// resolverFor returns the resolver for a project targeting framework.
+// Packages pick their dependencies by target framework, so unless the
+// runtime is given explicitly, the project's own framework is used.
+func (p *ProjectParser) resolverFor(framework string, opts deps.Options) deps.Resolver {
+	r, ok := p.resolver.(*nugetResolver)
+	if !ok || framework == "" || opts.RuntimeVersion != "" {
+		return p.resolver
+	}
+	pr, err := deps.NewPubGrubResolver("nuget", newFetcher(r.client, framework), NuGetMatcher{})
+	if err != nil {
+		return p.resolver
+	}
+	return pr
+}
+
+// project is the part of an MSBuild project or props file the parser
+// reads. Element names match without regard to the legacy MSBuild
+// namespace.
+type project struct {
+	PropertyGroups []struct {
+		Properties []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"PropertyGroup"`
+	ItemGroups []struct {
+		PackageReferences []packageItem `xml:"PackageReference"`
+		PackageVersions   []packageItem `xml:"PackageVersion"`
+	} `xml:"ItemGroup"`
+}
+
+// packageItem is a PackageReference or PackageVersion item. Metadata may
+// be given as attributes or as child elements.
+type packageItem struct {
+	Include             string `xml:"Include,attr"`
+	VersionAttr         string `xml:"Version,attr"`
+	Version             string `xml:"Version"`
+	VersionOverrideAttr string `xml:"VersionOverride,attr"`
+	VersionOverride     string `xml:"VersionOverride"`
+	PrivateAssetsAttr   string `xml:"PrivateAssets,attr"`
+	PrivateAssets       string `xml:"PrivateAssets"`
+}
+
+func (i packageItem) version() string {
+	return strings.TrimSpace(cmp.Or(i.VersionAttr, i.Version))
+}
+
+func (i packageItem) versionOverride() string {
+	return strings.TrimSpace(cmp.Or(i.VersionOverrideAttr, i.VersionOverride))
+}
+
+// private reports whether the package is a development dependency, one
+// whose assets do not flow to consumers of the project.
+func (i packageItem) private() bool {
+	return strings.EqualFold(strings.TrimSpace(cmp.Or(i.PrivateAssetsAttr, i.PrivateAssets)), "all")
+}
+
+func readProject(path string) (*project, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var proj project
+	if err := xml.Unmarshal(data, &proj); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+	}
+	return &proj, nil
+}
+
+// properties returns the project's properties keyed by lowercase name, as
+// MSBuild property names are case-insensitive. Later definitions win.
+func (p *project) properties() map[string]string {
+	props := make(map[string]string)
+	for _, g := range p.PropertyGroups {
+		for _, prop := range g.Properties {
+			props[strings.ToLower(prop.XMLName.Local)] = strings.TrimSpace(prop.Value)
+		}
+	}
+	return props
+}
+
+func (p *project) packageReferences() []packageItem {
+	var items []packageItem
+	for _, g := range p.ItemGroups {
+		items = append(items, g.PackageReferences...)
+	}
+	return items
+}
+
+func (p *project) packageVersions() []packageItem {
+	var items []packageItem
+	for _, g := range p.ItemGroups {
+		items = append(items, g.PackageVersions...)
+	}
+	return items
+}
+
+// projectDependencies returns the package references of a project. A
+// plain version is the one NuGet restores, so it is pinned; ranges and
+// floating versions stay constraints. With the prod_only scope,
+// references with PrivateAssets="all" (analyzers, build tools, test
+// frameworks' runners) are left out.
+func projectDependencies(proj *project, props, central map[string]string, scope string) []deps.Dependency {
+	var result []deps.Dependency
+	seen := make(map[string]bool)
+	for _, ref := range proj.packageReferences() {
+		name := NormalizeID(ref.Include)
+		if name == "" || seen[name] {
+			continue
+		}
+		if scope == deps.DependencyScopeProdOnly && ref.private() {
+			continue
+		}
+		seen[name] = true
+
+		version := cmp.Or(ref.versionOverride(), ref.version(), central[name])
+		version = interpolate(version, props)
+
+		dep := deps.Dependency{Name: name, Constraint: version}
+		if isPlainVersion(version) {
+			dep.Pinned = version
+		}
+		result = append(result, dep)
+	}
+	return result
+}
+
+// isPlainVersion reports whether a version is a single version rather
+// than a range or a floating version.
+func isPlainVersion(v string) bool {
+	return v != "" && !strings.ContainsAny(v, "[(*$")
+}
+
+// interpolate replaces $(Property) references with the project's
+// properties. Unknown properties are left as they are.
+func interpolate(s string, props map[string]string) string {
+	if !strings.Contains(s, "$(") {
+		return s
+	}
+	return propertyRE.ReplaceAllStringFunc(s, func(ref string) string {
+		name := strings.ToLower(propertyRE.FindStringSubmatch(ref)[1])
+		if v, ok := props[name]; ok {
+			return v
+		}
+		return ref
+	})
+}
+
+// targetFramework returns the framework a project targets: its
+// TargetFramework, or the first of its TargetFrameworks.
+func targetFramework(props map[string]string) string {
+	if tf := props["targetframework"]; tf != "" {
+		return interpolate(tf, props)
+	}
+	first, _, _ := strings.Cut(props["targetframeworks"], ";")
+	return strings.TrimSpace(interpolate(first, props))
+}
+
+// findUp returns the path of the nearest file called name in dir or one
+// of its parents, or "" when there is none.
+func findUp(dir, name string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		candidate := filepath.Join(dir, name)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
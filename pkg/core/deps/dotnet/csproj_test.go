@@ -0,0 +1,192 @@
+package dotnet
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProjectParser_Supports(t *testing.T) {
+	parser := &ProjectParser{}
+
+	tests := []struct {
+		filename string
+		want     bool
+	}{
+		{"MyApp.csproj", true},
+		{"Lib.fsproj", true},
+		{"Legacy.vbproj", true},
+		{"Upper.CSPROJ", true},
+		{"packages.config", false},
+		{"MyApp.sln", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			if got := parser.Supports(tt.filename); got != tt.want {
+				t.Errorf("Supports(%q) = %v, want %v", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectParser_Parse(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "MyApp.csproj")
+	writeFile(t, path, `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+    <AssemblyName>Contoso.App</AssemblyName>
+    <SerilogVersion>3.1.1</SerilogVersion>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" Version="13.0.3" />
+    <PackageReference Include="Serilog" Version="$(SerilogVersion)" />
+    <PackageReference Include="Polly">
+      <Version>[8.0.0, 9.0.0)</Version>
+    </PackageReference>
+    <PackageReference Include="StyleCop.Analyzers" Version="1.1.118" PrivateAssets="all" />
+  </ItemGroup>
+</Project>`)
+
+	result, err := (&ProjectParser{}).Parse(path, deps.Options{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if result.RootPackage != "Contoso.App" || result.RuntimeVersion != "net8.0" || result.Type != "csproj" {
+		t.Errorf("result = %+v", result)
+	}
+	if result.IncludesTransitive {
+		t.Error("IncludesTransitive should be false without a resolver")
+	}
+
+	var names []string
+	for _, n := range result.Graph.Nodes() {
+		if n.ID != deps.ProjectRootNodeID {
+			names = append(names, n.ID)
+		}
+	}
+	slices.Sort(names)
+	if want := []string{"newtonsoft.json", "polly", "serilog"}; !slices.Equal(names, want) {
+		t.Errorf("nodes = %v, want %v (analyzers excluded)", names, want)
+	}
+
+	all, err := (&ProjectParser{}).Parse(path, deps.Options{DependencyScope: deps.DependencyScopeAll})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := all.Graph.Node("stylecop.analyzers"); !ok {
+		t.Error("scope all should include PrivateAssets packages")
+	}
+}
+
+func TestProjectParser_Parse_CentralVersions(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "Directory.Packages.props"), `<Project>
+  <PropertyGroup>
+    <ManagePackageVersionsCentrally>true</ManagePackageVersionsCentrally>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageVersion Include="Dapper" Version="2.1.35" />
+    <PackageVersion Include="xunit" Version="2.9.0" />
+  </ItemGroup>
+</Project>`)
+	path := filepath.Join(root, "src", "Data", "Data.csproj")
+	writeFile(t, path, `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFrameworks>net8.0;netstandard2.0</TargetFrameworks>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="dapper" />
+    <PackageReference Include="xunit" VersionOverride="2.8.1" />
+  </ItemGroup>
+</Project>`)
+
+	proj, err := readProject(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	central := map[string]string{"dapper": "2.1.35", "xunit": "2.9.0"}
+	got := projectDependencies(proj, proj.properties(), central, deps.DependencyScopeAll)
+	want := []deps.Dependency{
+		{Name: "dapper", Constraint: "2.1.35", Pinned: "2.1.35"},
+		{Name: "xunit", Constraint: "2.8.1", Pinned: "2.8.1"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("projectDependencies = %v, want %v", got, want)
+	}
+
+	result, err := (&ProjectParser{}).Parse(path, deps.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RuntimeVersion != "net8.0" || result.RootPackage != "Data" {
+		t.Errorf("result = %+v", result)
+	}
+	edges := result.Graph.Edges()
+	if len(edges) != 2 || edges[0].Meta["constraint"] != "2.1.35" {
+		t.Errorf("edges = %v, want the central versions on the edges from the root", edges)
+	}
+}
+
+func TestProjectParser_Parse_Legacy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Old.csproj")
+	writeFile(t, path, `<?xml version="1.0" encoding="utf-8"?>
+<Project ToolsVersion="15.0" xmlns="http://schemas.microsoft.com/developer/msbuild/2003">
+  <PropertyGroup>
+    <TargetFrameworkVersion>v4.7.2</TargetFrameworkVersion>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="EntityFramework">
+      <Version>6.4.4</Version>
+    </PackageReference>
+  </ItemGroup>
+</Project>`)
+
+	result, err := (&ProjectParser{}).Parse(path, deps.Options{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if _, ok := result.Graph.Node("entityframework"); !ok {
+		t.Error("namespaced project files should be read")
+	}
+}
+
+func TestProjectParser_Parse_InvalidXML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Bad.csproj")
+	writeFile(t, path, `<Project><ItemGroup>`)
+	if _, err := (&ProjectParser{}).Parse(path, deps.Options{}); err == nil {
+		t.Error("Parse should fail on malformed XML")
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	props := map[string]string{"ver": "1.2.3"}
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"$(Ver)", "1.2.3"},
+		{"[$(ver), 2.0)", "[1.2.3, 2.0)"},
+		{"$(Unknown)", "$(Unknown)"},
+		{"4.0.0", "4.0.0"},
+	}
+	for _, tt := range tests {
+		if got := interpolate(tt.input, props); got != tt.want {
+			t.Errorf("interpolate(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Package dotnet provides dependency resolution for NuGet/.NET packages.
+//
+// # Overview
+//
+// This package implements [deps.Language] for .NET, supporting:
+//
+//   - NuGet registry resolution via [nuget] client
+//   - SDK-style project files (.csproj, .fsproj, .vbproj)
+//   - packages.config files
+//
+// # Registry Resolution
+//
+// Use [Language.Resolver] to fetch dependencies from nuget.org:
+//
+//	resolver, _ := dotnet.Language.Resolver(cache.NewNullCache(), deps.Options{})
+//	g, _ := resolver.Resolve(ctx, "Newtonsoft.Json", deps.Options{MaxDepth: 10})
+//
+// Package IDs are case-insensitive and normalized to lowercase by
+// [NormalizeID], so "Newtonsoft.Json" resolves to the node
+// "newtonsoft.json".
+//
+// # Target Frameworks
+//
+// NuGet packages declare dependencies per target framework. The runtime
+// version names the framework to resolve for, as a version ("8.0", the
+// default) or a framework moniker ("net48", "netstandard2.0"); each
+// package's dependency group is picked by [nuget.SelectGroup].
+//
+// # Manifest Parsing
+//
+// Parse project files and packages.config:
+//
+//	parser, _ := dotnet.Language.Manifest("MyApp.csproj", resolver)
+//	result, _ := parser.Parse("MyApp.csproj", deps.Options{})
+//
+// The project file parser reads PackageReference items, taking versions
+// from the item, its VersionOverride or the nearest
+// Directory.Packages.props (central package management), with $(...)
+// properties interpolated. References with PrivateAssets="all" are
+// development dependencies, left out with the prod_only scope. Without
+// an explicit runtime, the project's TargetFramework (or the first of its
+// TargetFrameworks) picks the dependency groups.
+//
+// packages.config lists every package of a project at a pinned version;
+// with a resolver, their .nuspec files are fetched to connect them.
+//
+// # Versions
+//
+// Versions are normalized [NuGetVersion]s ordered by NuGet's rules. A
+// plain version in a range is a minimum ("1.0" means 1.0 or newer),
+// brackets give exact versions and intervals ("[1.0]", "[1.0,2.0)"), and
+// floating versions ("1.*") match a version prefix. Ranges resolve to the
+// newest matching version; versions written in project files are pinned.
+//
+// [nuget]: github.com/stacktower-io/stacktower/pkg/integrations/nuget
+// [nuget.SelectGroup]: github.com/stacktower-io/stacktower/pkg/integrations/nuget.SelectGroup
+// [deps.Language]: github.com/stacktower-io/stacktower/pkg/core/deps.Language
+package dotnet
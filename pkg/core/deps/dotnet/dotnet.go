@@ -0,0 +1,108 @@
+package dotnet
+
+import (
+	"context"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/integrations/nuget"
+)
+
+// Language provides .NET dependency resolution via NuGet.
+// Supports SDK-style project files (.csproj, .fsproj, .vbproj) and
+// packages.config manifest files.
+var Language = &deps.Language{
+	Name:                  "dotnet",
+	DefaultRegistry:       "nuget",
+	DefaultRuntimeVersion: "8.0", // LTS
+	RegistryAliases:       map[string]string{"nuget.org": "nuget"},
+	ManifestTypes:         []string{"csproj", "packages-config"},
+	ManifestAliases: map[string]string{
+		"*.csproj":        "csproj",
+		"*.fsproj":        "csproj",
+		"*.vbproj":        "csproj",
+		"packages.config": "packages-config",
+	},
+	NewResolver:     newResolver,
+	NewManifest:     newManifest,
+	ManifestParsers: manifestParsers,
+	NormalizeName:   NormalizeID,
+}
+
+func newResolver(backend cache.Cache, opts deps.Options) (deps.Resolver, error) {
+	c := nuget.NewClient(backend, opts.CacheTTL)
+	f := newFetcher(c, opts.RuntimeVersion)
+
+	// Use PubGrub for proper SAT-solver-based dependency resolution
+	r, err := deps.NewPubGrubResolver("nuget", f, NuGetMatcher{})
+	if err != nil {
+		return nil, err
+	}
+	return &nugetResolver{PubGrubResolver: r, client: c}, nil
+}
+
+// nugetResolver is a PubGrubResolver that shares its NuGet client with the
+// manifest parsers: the project file parser builds a resolver for the
+// project's target framework with it, and the packages.config parser reads
+// the dependencies of pinned packages through it.
+type nugetResolver struct {
+	*deps.PubGrubResolver
+	client *nuget.Client
+}
+
+// fetcher is a [deps.RegistryFetcher] that fetches packages with the
+// dependencies of one target framework and names them by [NormalizeID], so
+// that IDs written in different cases meet in one node.
+type fetcher struct {
+	deps.RegistryFetcher
+}
+
+// newFetcher returns a fetcher for target framework, the runtime version
+// (e.g., "8.0", "net48", "netstandard2.0").
+func newFetcher(c *nuget.Client, framework string) fetcher {
+	return fetcher{deps.RegistryFetcher{
+		Client:       nuget.NewRegistry(c, framework),
+		ManifestFile: ".nuspec",
+	}}
+}
+
+func (f fetcher) Fetch(ctx context.Context, name string, refresh bool) (*deps.Package, error) {
+	return f.FetchVersion(ctx, name, "", refresh)
+}
+
+func (f fetcher) FetchVersion(ctx context.Context, name, version string, refresh bool) (*deps.Package, error) {
+	pkg, err := f.RegistryFetcher.FetchVersion(ctx, name, version, refresh)
+	if err != nil {
+		return nil, err
+	}
+	pkg.Name = NormalizeID(pkg.Name)
+	for i := range pkg.Dependencies {
+		pkg.Dependencies[i].Name = NormalizeID(pkg.Dependencies[i].Name)
+	}
+	return pkg, nil
+}
+
+// NormalizeID returns the canonical form of a NuGet package ID. IDs are
+// case-insensitive, so "Newtonsoft.Json" becomes "newtonsoft.json".
+func NormalizeID(id string) string {
+	return strings.ToLower(strings.TrimSpace(id))
+}
+
+func newManifest(name string, res deps.Resolver) deps.ManifestParser {
+	switch name {
+	case "csproj":
+		return &ProjectParser{resolver: res}
+	case "packages-config":
+		return &PackagesConfigParser{resolver: res}
+	default:
+		return nil
+	}
+}
+
+func manifestParsers(res deps.Resolver) []deps.ManifestParser {
+	return []deps.ManifestParser{
+		&ProjectParser{resolver: res},
+		&PackagesConfigParser{resolver: res},
+	}
+}
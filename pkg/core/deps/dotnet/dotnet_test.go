@@ -0,0 +1,125 @@
+package dotnet
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+func TestNormalizeID(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Newtonsoft.Json", "newtonsoft.json"},
+		{"  Serilog ", "serilog"},
+		{"xunit", "xunit"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := NormalizeID(tt.input); got != tt.want {
+				t.Errorf("NormalizeID(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewResolver(t *testing.T) {
+	r, err := Language.Resolver(cache.NewNullCache(), deps.Options{})
+	if err != nil {
+		t.Fatalf("Resolver: %v", err)
+	}
+	nr, ok := r.(*nugetResolver)
+	if !ok || nr.client == nil {
+		t.Fatalf("Resolver = %T, want a *nugetResolver with a client", r)
+	}
+	if r.Name() != "nuget" {
+		t.Errorf("Name() = %q, want nuget", r.Name())
+	}
+}
+
+func TestLanguage_Manifests(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     string
+	}{
+		{"MyApp.csproj", "csproj"},
+		{"Lib.fsproj", "csproj"},
+		{"Legacy.vbproj", "csproj"},
+		{"packages.config", "packages-config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			typ, ok := Language.ManifestType(tt.filename)
+			if !ok || typ != tt.want {
+				t.Errorf("ManifestType(%q) = %q, %v, want %q", tt.filename, typ, ok, tt.want)
+			}
+			if _, ok := Language.Manifest(tt.filename, nil); !ok {
+				t.Errorf("Manifest(%q) found no parser", tt.filename)
+			}
+		})
+	}
+	if _, ok := Language.ManifestType("project.json"); ok {
+		t.Error("ManifestType(project.json) should not match")
+	}
+}
+
+// fakeRegistry serves one package as NuGet spells it.
+type fakeRegistry struct {
+	info *integrations.PackageInfo
+}
+
+func (r fakeRegistry) Name() string { return "nuget" }
+
+func (r fakeRegistry) FetchPackage(context.Context, string, string, integrations.FetchOptions) (*integrations.PackageInfo, error) {
+	return r.info, nil
+}
+
+func (r fakeRegistry) ListVersions(context.Context, string, integrations.FetchOptions) ([]string, error) {
+	return []string{r.info.Version}, nil
+}
+
+func TestFetcher_NormalizesIDs(t *testing.T) {
+	f := fetcher{deps.RegistryFetcher{Client: fakeRegistry{&integrations.PackageInfo{
+		Name:         "Serilog.Sinks.Console",
+		Version:      "5.0.1",
+		Author:       "Serilog Contributors",
+		Dependencies: []integrations.Dependency{{Name: "Serilog", Constraint: "[3.1.1, 4.0.0)"}},
+	}}}}
+
+	pkg, err := f.Fetch(context.Background(), "serilog.sinks.console", false)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if pkg.Name != "serilog.sinks.console" || pkg.Version != "5.0.1" || pkg.Author != "Serilog Contributors" {
+		t.Errorf("pkg = %+v", pkg)
+	}
+	want := []deps.Dependency{{Name: "serilog", Constraint: "[3.1.1, 4.0.0)"}}
+	if !slices.Equal(pkg.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want %v", pkg.Dependencies, want)
+	}
+}
+
+func TestProjectParser_ResolverFor(t *testing.T) {
+	r, err := Language.Resolver(cache.NewNullCache(), deps.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &ProjectParser{resolver: r}
+
+	if got := p.resolverFor("net48", deps.Options{}); got == r {
+		t.Error("a project framework should get its own resolver")
+	}
+	if got := p.resolverFor("net48", deps.Options{RuntimeVersion: "8.0"}); got != r {
+		t.Error("an explicit runtime should keep the given resolver")
+	}
+	if got := p.resolverFor("", deps.Options{}); got != r {
+		t.Error("no project framework should keep the given resolver")
+	}
+}
@@ -0,0 +1,178 @@
+package dotnet
+
+import (
+	"cmp"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/integrations/nuget"
+	"github.com/stacktower-io/stacktower/pkg/observability"
+)
+
+// PackagesConfigParser parses packages.config files, the package lists of
+// .NET Framework projects from before PackageReference. The file pins
+// every package the project uses, transitive ones included, but not how
+// they depend on each other: with a resolver, each package's .nuspec is
+// fetched at its pinned version to connect them. Without one, the
+// packages are listed as direct dependencies.
+type PackagesConfigParser struct {
+	resolver deps.Resolver
+}
+
+func (p *PackagesConfigParser) Type() string              { return "packages.config" }
+func (p *PackagesConfigParser) IncludesTransitive() bool  { return p.client() != nil }
+func (p *PackagesConfigParser) Supports(name string) bool { return name == "packages.config" }
+
+// client returns the NuGet client of the parser's resolver, or nil when
+// it has none.
+func (p *PackagesConfigParser) client() *nuget.Client {
+	if r, ok := p.resolver.(*nugetResolver); ok {
+		return r.client
+	}
+	return nil
+}
+
+// packagesConfig is a packages.config document.
+type packagesConfig struct {
+	Packages []configPackage `xml:"package"`
+}
+
+type configPackage struct {
+	ID                    string `xml:"id,attr"`
+	Version               string `xml:"version,attr"`
+	TargetFramework       string `xml:"targetFramework,attr"`
+	DevelopmentDependency bool   `xml:"developmentDependency,attr"`
+}
+
+func (p *PackagesConfigParser) Parse(path string, opts deps.Options) (*deps.ManifestResult, error) {
+	opts = opts.WithDefaults()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg packagesConfig
+	if err := xml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse packages.config: %w", err)
+	}
+
+	var pkgs []configPackage
+	seen := make(map[string]bool)
+	for _, pkg := range cfg.Packages {
+		pkg.ID = NormalizeID(pkg.ID)
+		if pkg.ID == "" || seen[pkg.ID] {
+			continue
+		}
+		if opts.DependencyScope == deps.DependencyScopeProdOnly && pkg.DevelopmentDependency {
+			continue
+		}
+		seen[pkg.ID] = true
+		pkgs = append(pkgs, pkg)
+	}
+
+	var framework string
+	if len(pkgs) > 0 {
+		framework = pkgs[0].TargetFramework
+	}
+
+	var g *dag.DAG
+	if c := p.client(); c != nil {
+		g = buildPackagesConfigGraph(opts.Ctx, c, pkgs, cmp.Or(opts.RuntimeVersion, framework), opts)
+		deps.EnrichGraph(opts.Ctx, g, "packages.config", opts)
+	} else {
+		directDeps := make([]deps.Dependency, len(pkgs))
+		for i, pkg := range pkgs {
+			directDeps[i] = deps.Dependency{Name: pkg.ID, Constraint: "[" + pkg.Version + "]", Pinned: pkg.Version}
+		}
+		g = deps.ShallowGraphFromDeps(directDeps)
+	}
+
+	return &deps.ManifestResult{
+		Graph:              g,
+		Type:               p.Type(),
+		IncludesTransitive: p.client() != nil,
+		RootPackage:        projectName(filepath.Dir(path)),
+		RuntimeVersion:     framework,
+	}, nil
+}
+
+// buildPackagesConfigGraph fetches the pinned packages and connects each
+// to the listed packages it depends on on framework. Packages no other
+// listed package depends on hang off the project root. Packages that fail
+// to fetch become leaves.
+func buildPackagesConfigGraph(ctx context.Context, c *nuget.Client, pkgs []configPackage, framework string, opts deps.Options) *dag.DAG {
+	hooks := observability.ResolverFromContext(ctx)
+	f := newFetcher(c, framework)
+	fetched := deps.ParallelMapOrdered(ctx, opts.Workers, pkgs, func(ctx context.Context, pkg configPackage) *deps.Package {
+		hooks.OnFetchStart(ctx, pkg.ID, 0)
+		p, err := f.FetchVersion(ctx, pkg.ID, pkg.Version, opts.Refresh)
+		hooks.OnFetchComplete(ctx, pkg.ID, 0, 0, err)
+		if err != nil {
+			opts.Logger.Warn("fetch failed", "package", pkg.ID, "version", pkg.Version, "err", err)
+			return nil
+		}
+		return p
+	})
+
+	g := dag.New(nil)
+	for i, pkg := range pkgs {
+		meta := dag.Metadata{"version": nuget.NormalizeVersion(pkg.Version)}
+		if i < len(fetched) && fetched[i] != nil {
+			meta = fetched[i].Metadata()
+		}
+		_ = g.AddNode(dag.Node{ID: pkg.ID, Meta: meta})
+	}
+
+	incoming := make(map[string]bool)
+	for i, pkg := range pkgs {
+		if i >= len(fetched) || fetched[i] == nil {
+			continue
+		}
+		for _, dep := range fetched[i].Dependencies {
+			if _, ok := g.Node(dep.Name); !ok || dep.Name == pkg.ID {
+				continue
+			}
+			edgeMeta := dag.Metadata{}
+			if dep.Constraint != "" {
+				edgeMeta["constraint"] = dep.Constraint
+			}
+			_ = g.AddEdge(dag.Edge{From: pkg.ID, To: dep.Name, Meta: edgeMeta})
+			incoming[dep.Name] = true
+		}
+	}
+
+	_ = g.AddNode(dag.Node{ID: deps.ProjectRootNodeID, Meta: dag.Metadata{"virtual": true}})
+	for _, pkg := range pkgs {
+		if !incoming[pkg.ID] {
+			_ = g.AddEdge(dag.Edge{
+				From: deps.ProjectRootNodeID,
+				To:   pkg.ID,
+				Meta: dag.Metadata{"version": pkg.Version},
+			})
+		}
+	}
+	return g
+}
+
+// projectName returns the name of the project in dir, the base name of
+// its project file, or of dir when there is no single one.
+func projectName(dir string) string {
+	var names []string
+	for _, ext := range projectExts {
+		matches, _ := filepath.Glob(filepath.Join(dir, "*"+ext))
+		names = append(names, matches...)
+	}
+	if len(names) == 1 {
+		return strings.TrimSuffix(filepath.Base(names[0]), filepath.Ext(names[0]))
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		return filepath.Base(abs)
+	}
+	return filepath.Base(dir)
+}
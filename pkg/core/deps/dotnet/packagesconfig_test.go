@@ -0,0 +1,76 @@
+package dotnet
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+)
+
+const testPackagesConfig = `<?xml version="1.0" encoding="utf-8"?>
+<packages>
+  <package id="Newtonsoft.Json" version="12.0.3" targetFramework="net472" />
+  <package id="Serilog" version="2.10.0" targetFramework="net472" />
+  <package id="NUnit" version="3.13.3" targetFramework="net472" developmentDependency="true" />
+</packages>`
+
+func TestPackagesConfigParser_Parse(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "Legacy.Web.csproj"), `<Project />`)
+	path := filepath.Join(dir, "packages.config")
+	writeFile(t, path, testPackagesConfig)
+
+	parser := &PackagesConfigParser{}
+	if !parser.Supports("packages.config") || parser.Supports("packages.json") {
+		t.Error("Supports should match packages.config only")
+	}
+
+	result, err := parser.Parse(path, deps.Options{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if result.RootPackage != "Legacy.Web" || result.RuntimeVersion != "net472" || result.IncludesTransitive {
+		t.Errorf("result = %+v", result)
+	}
+
+	var names []string
+	for _, e := range result.Graph.Edges() {
+		names = append(names, e.To)
+		if e.To == "newtonsoft.json" && e.Meta["constraint"] != "[12.0.3]" {
+			t.Errorf("edge meta = %v, want the pinned version", e.Meta)
+		}
+	}
+	slices.Sort(names)
+	if want := []string{"newtonsoft.json", "serilog"}; !slices.Equal(names, want) {
+		t.Errorf("direct dependencies = %v, want %v", names, want)
+	}
+
+	all, err := parser.Parse(path, deps.Options{DependencyScope: deps.DependencyScopeAll})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := all.Graph.Node("nunit"); !ok {
+		t.Error("scope all should include development dependencies")
+	}
+}
+
+func TestPackagesConfigParser_Parse_InvalidXML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "packages.config")
+	writeFile(t, path, `<packages><package`)
+	if _, err := (&PackagesConfigParser{}).Parse(path, deps.Options{}); err == nil {
+		t.Error("Parse should fail on malformed XML")
+	}
+}
+
+func TestProjectName(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Solution")
+	writeFile(t, filepath.Join(dir, "packages.config"), testPackagesConfig)
+	if got := projectName(dir); got != "Solution" {
+		t.Errorf("projectName without a project file = %q, want the directory name", got)
+	}
+	writeFile(t, filepath.Join(dir, "App.vbproj"), `<Project />`)
+	if got := projectName(dir); got != "App" {
+		t.Errorf("projectName = %q, want App", got)
+	}
+}
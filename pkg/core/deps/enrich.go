@@ -1,9 +1,12 @@
 package deps
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"maps"
+	"math/bits"
+	"slices"
 	"sync"
 
 	"github.com/stacktower-io/stacktower/pkg/cache"
@@ -13,6 +16,173 @@ import (
 
 var enrichAuthHintOnce sync.Once
 
+// EnrichFilter selects the packages worth enriching. On large graphs most
+// packages are leaves whose metadata nobody looks at, while brittleness
+// and Nebraska rankings depend on the packages near the root and the
+// foundations many others rest on. A package is enriched when it passes
+// any threshold that is set; roots always are.
+type EnrichFilter struct {
+	// MaxDepth enriches the packages at most this many levels below a
+	// root (1 = direct dependencies). Zero sets no depth threshold.
+	MaxDepth int
+
+	// MinDependents enriches the packages that at least this many other
+	// packages depend on, directly or transitively. Zero sets no
+	// dependents threshold.
+	MinDependents int
+}
+
+// IsZero reports whether f sets no threshold, so every package is enriched.
+func (f EnrichFilter) IsZero() bool {
+	return f.MaxDepth <= 0 && f.MinDependents <= 0
+}
+
+// Select returns the IDs of the nodes of g to enrich, or nil when f is
+// zero and every node is. Depth is the shortest distance from a node
+// without parents.
+func (f EnrichFilter) Select(g *dag.DAG) map[string]bool {
+	if f.IsZero() {
+		return nil
+	}
+	selected := make(map[string]bool)
+
+	// Breadth-first from the roots, up to MaxDepth levels down.
+	depth := make(map[string]int)
+	var level []string
+	for _, n := range g.Nodes() {
+		if g.InDegree(n.ID) == 0 {
+			depth[n.ID] = 0
+			selected[n.ID] = true
+			level = append(level, n.ID)
+		}
+	}
+	for d := 1; len(level) > 0 && d <= f.MaxDepth; d++ {
+		var next []string
+		for _, id := range level {
+			for _, child := range g.Children(id) {
+				if _, seen := depth[child]; !seen {
+					depth[child] = d
+					selected[child] = true
+					next = append(next, child)
+				}
+			}
+		}
+		level = next
+	}
+
+	if f.MinDependents > 0 {
+		for _, n := range g.Nodes() {
+			if !selected[n.ID] && hasDependents(g, n.ID, f.MinDependents) {
+				selected[n.ID] = true
+			}
+		}
+	}
+	return selected
+}
+
+// hasDependents reports whether at least want nodes reach id. The search
+// upwards stops as soon as it found enough, which keeps it cheap for the
+// many packages with few dependents.
+func hasDependents(g *dag.DAG, id string, want int) bool {
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+	count := 0
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, p := range g.Parents(cur) {
+			if seen[p] {
+				continue
+			}
+			seen[p] = true
+			if count++; count >= want {
+				return true
+			}
+			queue = append(queue, p)
+		}
+	}
+	return false
+}
+
+// RankByDependents sets the Dependents of each ref to the number of
+// packages of g that depend on it, directly or transitively, and sorts
+// refs by it, most depended upon first. Providers short of API budget can
+// then serve the foundations of the graph before its leaves.
+func RankByDependents(g *dag.DAG, refs []*PackageRef) {
+	counts := countDependents(g)
+	for _, ref := range refs {
+		ref.Dependents = counts[ref.Name]
+	}
+	slices.SortStableFunc(refs, func(a, b *PackageRef) int {
+		return cmp.Compare(b.Dependents, a.Dependents)
+	})
+}
+
+// countDependents returns the number of packages reaching each node of g,
+// not counting the virtual project root. Ancestor sets are bitsets merged
+// down the graph in topological order, which keeps this fast on graphs of
+// thousands of nodes.
+func countDependents(g *dag.DAG) map[string]int {
+	nodes := g.Nodes()
+	index := make(map[string]int, len(nodes))
+	pending := make([]int, len(nodes))
+	var queue []int
+	for i, n := range nodes {
+		index[n.ID] = i
+		if pending[i] = g.InDegree(n.ID); pending[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+
+	words := (len(nodes) + 63) / 64
+	ancestors := make([][]uint64, len(nodes))
+	counts := make(map[string]int, len(nodes))
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		set := ancestors[i]
+		if set == nil {
+			set = make([]uint64, words)
+		}
+		count := 0
+		for _, w := range set {
+			count += bits.OnesCount64(w)
+		}
+		counts[nodes[i].ID] = count
+
+		for _, child := range g.Children(nodes[i].ID) {
+			j := index[child]
+			if ancestors[j] == nil {
+				ancestors[j] = make([]uint64, words)
+			}
+			for w := range set {
+				ancestors[j][w] |= set[w]
+			}
+			if nodes[i].ID != ProjectRootNodeID {
+				ancestors[j][i/64] |= 1 << (i % 64)
+			}
+			if pending[j]--; pending[j] == 0 {
+				queue = append(queue, j)
+			}
+		}
+		ancestors[i] = nil // merged into every child
+	}
+	return counts
+}
+
+// SkippedPackages returns the packages that the [PartialProvider]s among
+// providers left out of enrichment, sorted and without duplicates.
+func SkippedPackages(providers []MetadataProvider) []string {
+	var skipped []string
+	for _, p := range providers {
+		if pp, ok := p.(PartialProvider); ok {
+			skipped = append(skipped, pp.Skipped()...)
+		}
+	}
+	slices.Sort(skipped)
+	return slices.Compact(skipped)
+}
+
 // graphEnrichJob represents a single package to enrich in EnrichGraph.
 type graphEnrichJob struct {
 	ref *PackageRef
@@ -43,8 +213,9 @@ type EnrichStats struct {
 }
 
 // EnrichGraph adds external metadata (e.g. GitHub stars) to every non-root graph
-// node. It prefers batch providers (one API call for all packages) and falls
-// back to parallel per-package enrichment using ParallelMapOrdered.
+// node, or to those opts.EnrichFilter selects. It prefers batch providers (one
+// API call for all packages) and falls back to parallel per-package enrichment
+// using ParallelMapOrdered.
 //
 // The manifestFile parameter specifies the manifest file name for PackageRef
 // (e.g., "package.json", "Cargo.toml", "pyproject.toml").
@@ -61,12 +232,15 @@ func EnrichGraph(ctx context.Context, g *dag.DAG, manifestFile string, opts Opti
 		return stats
 	}
 
-	o := opts.WithDefaults()
+	opts = opts.WithDefaults()
 	if ctx == nil {
-		ctx = o.Ctx
+		ctx = opts.Ctx
 	}
 
 	nodes := g.Nodes()
+	if selected := opts.EnrichFilter.Select(g); selected != nil {
+		nodes = slices.DeleteFunc(nodes, func(n *dag.Node) bool { return !selected[n.ID] })
+	}
 
 	// Collect package names (excluding project root)
 	names := make([]string, 0, len(nodes))
@@ -86,7 +260,7 @@ func EnrichGraph(ctx context.Context, g *dag.DAG, manifestFile string, opts Opti
 		var err error
 		urlMap, err = opts.URLProvider.FetchURLs(ctx, names, opts.Refresh)
 		if err != nil {
-			opts.Logger("url fetch failed: %v", err)
+			opts.Logger.Warn("url fetch failed", "err", err)
 		}
 	}
 
@@ -111,36 +285,48 @@ func EnrichGraph(ctx context.Context, g *dag.DAG, manifestFile string, opts Opti
 		}
 		refs = append(refs, ref)
 	}
+	RankByDependents(g, refs)
 	stats.Total = len(refs)
 
 	// Try batch enrichment first (e.g. GitHub GraphQL — one call for all).
 	// This emits OnEnrichStart/OnEnrichComplete hooks for progress UI.
+	// Providers without batch support, and batch providers whose batch
+	// call failed, fall back to per-package enrichment below.
 	hooks := observability.ResolverFromContext(ctx)
+	enriched := make(map[string]bool, len(refs))
+	var remaining []MetadataProvider
 	for _, p := range opts.MetadataProviders {
 		bp, ok := p.(BatchMetadataProvider)
 		if !ok {
+			remaining = append(remaining, p)
 			continue
 		}
 		hooks.OnEnrichStart(ctx, p.Name(), len(refs))
 		batch, err := bp.EnrichBatch(ctx, refs, opts.Refresh)
 		if err != nil {
 			hooks.OnEnrichComplete(ctx, p.Name(), 0, err)
-			opts.Logger("batch enrich (%s): %v", p.Name(), err)
+			opts.Logger.Warn("batch enrich failed", "provider", p.Name(), "err", err)
 			if errors.Is(err, cache.ErrUnauthorized) {
 				stats.AuthError = true
 			}
+			remaining = append(remaining, p)
 			continue
 		}
 		stats.UsedBatch = true
+		count := 0
 		for _, n := range nodes {
 			if extra, ok := batch[n.ID]; ok {
 				maps.Copy(n.Meta, extra)
-				stats.Succeeded++
+				enriched[n.ID] = true
+				count++
 			}
 		}
+		hooks.OnEnrichComplete(ctx, p.Name(), count, nil)
+	}
+	if len(remaining) == 0 {
+		stats.Succeeded = len(enriched)
 		stats.Failed = stats.Total - stats.Succeeded
-		hooks.OnEnrichComplete(ctx, p.Name(), stats.Succeeded, nil)
-		return stats // batch provider handled everything
+		return stats // batch providers handled everything
 	}
 
 	// Fallback: parallel per-package enrichment using ParallelMapOrdered.
@@ -152,25 +338,25 @@ func EnrichGraph(ctx context.Context, g *dag.DAG, manifestFile string, opts Opti
 	var authErrorSeen bool
 	var authMu sync.Mutex
 
-	results := ParallelMapOrdered(ctx, o.Workers, jobs, func(ctx context.Context, j graphEnrichJob) graphEnrichResult {
+	results := ParallelMapOrdered(ctx, opts.Workers, jobs, func(ctx context.Context, j graphEnrichJob) graphEnrichResult {
 		hooks.OnFetchStart(ctx, j.ref.Name, 0)
 		m := make(map[string]any)
 		success := false
-		for _, p := range opts.MetadataProviders {
-			enriched, err := p.Enrich(ctx, j.ref, opts.Refresh)
+		for _, p := range remaining {
+			meta, err := p.Enrich(ctx, j.ref, opts.Refresh)
 			if err != nil {
-				opts.Logger("enrich failed: %s: %v", j.ref.Name, err)
+				opts.Logger.Warn("enrich failed", "package", j.ref.Name, "err", err)
 				if errors.Is(err, cache.ErrUnauthorized) {
 					authMu.Lock()
 					authErrorSeen = true
 					authMu.Unlock()
 					enrichAuthHintOnce.Do(func() {
-						opts.Logger("hint: GitHub token may be expired. Run 'stacktower github logout && stacktower github login' to re-authenticate")
+						opts.Logger.Warn("GitHub token may be expired", "hint", "run 'stacktower github logout && stacktower github login' to re-authenticate")
 					})
 				}
 				continue
 			}
-			maps.Copy(m, enriched)
+			maps.Copy(m, meta)
 			success = true
 		}
 		hooks.OnFetchComplete(ctx, j.ref.Name, 0, 0, nil)
@@ -182,11 +368,11 @@ func EnrichGraph(ctx context.Context, g *dag.DAG, manifestFile string, opts Opti
 			maps.Copy(n.Meta, res.meta)
 		}
 		if res.success {
-			stats.Succeeded++
-		} else {
-			stats.Failed++
+			enriched[res.name] = true
 		}
 	}
-	stats.AuthError = authErrorSeen
+	stats.Succeeded = len(enriched)
+	stats.Failed = stats.Total - stats.Succeeded
+	stats.AuthError = stats.AuthError || authErrorSeen
 	return stats
 }
@@ -0,0 +1,186 @@
+package deps
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+// fakeProvider returns fixed metadata per package, or err.
+type fakeProvider struct {
+	name string
+	meta map[string]map[string]any
+	err  error
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) Enrich(_ context.Context, ref *PackageRef, _ bool) (map[string]any, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.meta[ref.Name], nil
+}
+
+// fakeBatchProvider is a fakeProvider with batch support.
+type fakeBatchProvider struct{ fakeProvider }
+
+func (p *fakeBatchProvider) EnrichBatch(_ context.Context, _ []*PackageRef, _ bool) (map[string]map[string]any, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.meta, nil
+}
+
+func TestEnrichGraph_AllProviders(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: ProjectRootNodeID})
+	_ = g.AddNode(dag.Node{ID: "a", Meta: dag.Metadata{}})
+	_ = g.AddNode(dag.Node{ID: "b", Meta: dag.Metadata{}})
+
+	github := &fakeBatchProvider{fakeProvider{name: "github", meta: map[string]map[string]any{"a": {"stars": 10}}}}
+	plugin := &fakeBatchProvider{fakeProvider{name: "plugin", meta: map[string]map[string]any{"b": {"score": 5}}}}
+	single := &fakeProvider{name: "single", meta: map[string]map[string]any{"a": {"extra": true}}}
+
+	stats := EnrichGraph(context.Background(), g, "", Options{MetadataProviders: []MetadataProvider{github, plugin, single}})
+
+	a, _ := g.Node("a")
+	b, _ := g.Node("b")
+	if a.Meta["stars"] != 10 || a.Meta["extra"] != true || b.Meta["score"] != 5 {
+		t.Errorf("metadata a = %v, b = %v; want every provider applied", a.Meta, b.Meta)
+	}
+	if stats.Total != 2 || stats.Succeeded != 2 || stats.Failed != 0 || !stats.UsedBatch {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestEnrichGraph_FailedBatchFallsBack(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "a", Meta: dag.Metadata{}})
+
+	failing := &fakeBatchProvider{fakeProvider{name: "github", err: errors.New("rate limited")}}
+	stats := EnrichGraph(context.Background(), g, "", Options{MetadataProviders: []MetadataProvider{failing}})
+
+	if stats.Succeeded != 0 || stats.Failed != 1 || stats.UsedBatch {
+		t.Errorf("stats = %+v, want one failure without batch", stats)
+	}
+}
+
+func TestEnrichFilter_Select(t *testing.T) {
+	// app -> a -> c -> d, app -> b -> c
+	g := dag.New(nil)
+	for _, id := range []string{"app", "a", "b", "c", "d"} {
+		_ = g.AddNode(dag.Node{ID: id})
+	}
+	for _, e := range [][2]string{{"app", "a"}, {"app", "b"}, {"a", "c"}, {"b", "c"}, {"c", "d"}} {
+		_ = g.AddEdge(dag.Edge{From: e[0], To: e[1]})
+	}
+
+	tests := []struct {
+		name   string
+		filter EnrichFilter
+		want   []string
+	}{
+		{"zero", EnrichFilter{}, nil},
+		{"depth 1", EnrichFilter{MaxDepth: 1}, []string{"a", "app", "b"}},
+		{"depth 2", EnrichFilter{MaxDepth: 2}, []string{"a", "app", "b", "c"}},
+		{"dependents 3", EnrichFilter{MinDependents: 3}, []string{"app", "c", "d"}},
+		{"dependents 4", EnrichFilter{MinDependents: 4}, []string{"app", "d"}},
+		{"either", EnrichFilter{MaxDepth: 1, MinDependents: 4}, []string{"a", "app", "b", "d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected := tt.filter.Select(g)
+			if tt.want == nil {
+				if selected != nil {
+					t.Errorf("Select = %v, want nil", selected)
+				}
+				return
+			}
+			got := slices.Sorted(maps.Keys(selected))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Select = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnrichGraph_Filter(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: ProjectRootNodeID})
+	for _, id := range []string{"a", "b"} {
+		_ = g.AddNode(dag.Node{ID: id, Meta: dag.Metadata{}})
+	}
+	_ = g.AddEdge(dag.Edge{From: ProjectRootNodeID, To: "a"})
+	_ = g.AddEdge(dag.Edge{From: "a", To: "b"})
+
+	provider := &fakeProvider{name: "github", meta: map[string]map[string]any{"a": {"stars": 1}, "b": {"stars": 2}}}
+	stats := EnrichGraph(context.Background(), g, "", Options{
+		MetadataProviders: []MetadataProvider{provider},
+		EnrichFilter:      EnrichFilter{MaxDepth: 1},
+	})
+
+	a, _ := g.Node("a")
+	b, _ := g.Node("b")
+	if a.Meta["stars"] != 1 || b.Meta["stars"] != nil {
+		t.Errorf("metadata a = %v, b = %v; want only the direct dependency enriched", a.Meta, b.Meta)
+	}
+	if stats.Total != 1 {
+		t.Errorf("stats = %+v, want one package", stats)
+	}
+}
+
+func TestRankByDependents(t *testing.T) {
+	//   root → app → web → core
+	//          app → cli → core → util
+	g := dag.New(nil)
+	for _, id := range []string{ProjectRootNodeID, "app", "web", "cli", "core", "util"} {
+		_ = g.AddNode(dag.Node{ID: id})
+	}
+	for _, e := range [][2]string{
+		{ProjectRootNodeID, "app"}, {"app", "web"}, {"app", "cli"},
+		{"web", "core"}, {"cli", "core"}, {"core", "util"},
+	} {
+		_ = g.AddEdge(dag.Edge{From: e[0], To: e[1]})
+	}
+
+	refs := []*PackageRef{{Name: "app"}, {Name: "web"}, {Name: "util"}, {Name: "cli"}, {Name: "core"}}
+	RankByDependents(g, refs)
+
+	var got []string
+	for _, ref := range refs {
+		got = append(got, ref.Name)
+	}
+	if want := []string{"util", "core", "web", "cli", "app"}; !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	if refs[0].Dependents != 4 || refs[4].Dependents != 0 {
+		t.Errorf("dependents of util = %d, app = %d; want 4 and 0 without the project root", refs[0].Dependents, refs[4].Dependents)
+	}
+}
+
+// partialProvider skips fixed packages.
+type partialProvider struct {
+	fakeProvider
+	skipped []string
+}
+
+func (p *partialProvider) Skipped() []string { return p.skipped }
+
+func TestSkippedPackages(t *testing.T) {
+	providers := []MetadataProvider{
+		&partialProvider{skipped: []string{"b", "a"}},
+		&fakeProvider{name: "complete"},
+		&partialProvider{skipped: []string{"a", "c"}},
+	}
+	if got := SkippedPackages(providers); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("SkippedPackages() = %v", got)
+	}
+	if got := SkippedPackages(providers[1:2]); got != nil {
+		t.Errorf("SkippedPackages() = %v, want none", got)
+	}
+}
@@ -2,6 +2,8 @@ package deps_test
 
 import (
 	"fmt"
+	"log/slog"
+	"os"
 	"time"
 
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
@@ -101,22 +103,24 @@ func Example_constants() {
 }
 
 func ExampleOptions_withLogger() {
-	// Custom logger for progress tracking
-	var logs []string
+	// Any *slog.Logger receives the resolver's structured records
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{} // keep the output stable
+			}
+			return a
+		},
+	})
 	opts := deps.Options{
 		MaxDepth: 5,
-		Logger: func(format string, args ...any) {
-			logs = append(logs, fmt.Sprintf(format, args...))
-		},
+		Logger:   slog.New(handler),
 	}.WithDefaults()
 
 	// Logger is preserved through WithDefaults
-	opts.Logger("Fetching %s", "fastapi")
-	opts.Logger("Found %d dependencies", 12)
-
-	fmt.Println("Log count:", len(logs))
+	opts.Logger.Warn("enrich failed", "package", "fastapi", "provider", "github")
 	// Output:
-	// Log count: 2
+	// level=WARN msg="enrich failed" package=fastapi provider=github
 }
 
 func ExampleOptions_limits() {
@@ -0,0 +1,76 @@
+package deps
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+// ValidateExcludes reports exclude patterns that are not valid globs.
+func ValidateExcludes(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := path.Match(strings.ToLower(p), ""); err != nil {
+			return fmt.Errorf("invalid exclude pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// ExcludePackages removes packages whose ID matches one of the shell-style
+// glob patterns (e.g. "types-*", "@babel/*"), ignoring case, together with
+// every dependency only reachable through them. Roots are always kept. The
+// graph is returned unchanged when there are no patterns.
+func ExcludePackages(g *dag.DAG, patterns []string) *dag.DAG {
+	if len(patterns) == 0 {
+		return g
+	}
+	visited := make(map[string]bool, g.NodeCount())
+	var queue []string
+	for _, n := range g.Nodes() {
+		if g.InDegree(n.ID) == 0 {
+			visited[n.ID] = true
+			queue = append(queue, n.ID)
+		}
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, child := range g.Children(cur) {
+			if visited[child] || matchAny(patterns, child) {
+				continue
+			}
+			visited[child] = true
+			queue = append(queue, child)
+		}
+	}
+
+	filtered := dag.New(nil)
+	maps.Copy(filtered.Meta(), g.Meta())
+	for _, n := range g.Nodes() {
+		if visited[n.ID] {
+			node := *n
+			node.Meta = maps.Clone(n.Meta)
+			_ = filtered.AddNode(node)
+		}
+	}
+	for _, e := range g.Edges() {
+		if visited[e.From] && visited[e.To] {
+			_ = filtered.AddEdge(dag.Edge{From: e.From, To: e.To, Meta: maps.Clone(e.Meta)})
+		}
+	}
+	return filtered
+}
+
+// matchAny reports whether id matches one of the glob patterns, ignoring case.
+func matchAny(patterns []string, id string) bool {
+	id = strings.ToLower(id)
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.ToLower(p), id); ok {
+			return true
+		}
+	}
+	return false
+}
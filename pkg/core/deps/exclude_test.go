@@ -0,0 +1,51 @@
+package deps
+
+import (
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestExcludePackages(t *testing.T) {
+	g := dag.New(dag.Metadata{"language": "python"})
+	for _, id := range []string{"app", "requests", "types-requests", "urllib3", "types-urllib3", "Types-Six"} {
+		_ = g.AddNode(dag.Node{ID: id})
+	}
+	for _, e := range [][2]string{
+		{"app", "requests"}, {"app", "types-requests"}, {"app", "Types-Six"},
+		{"requests", "urllib3"}, {"types-requests", "types-urllib3"}, {"types-requests", "urllib3"},
+	} {
+		_ = g.AddEdge(dag.Edge{From: e[0], To: e[1]})
+	}
+
+	got := ExcludePackages(g, []string{"types-*"})
+	for _, id := range []string{"app", "requests", "urllib3"} {
+		if _, ok := got.Node(id); !ok {
+			t.Errorf("%s was removed", id)
+		}
+	}
+	for _, id := range []string{"types-requests", "types-urllib3", "Types-Six"} {
+		if _, ok := got.Node(id); ok {
+			t.Errorf("%s was kept", id)
+		}
+	}
+	if got.EdgeCount() != 2 || got.Meta()["language"] != "python" {
+		t.Errorf("edges = %d, meta = %v", got.EdgeCount(), got.Meta())
+	}
+
+	if ExcludePackages(g, nil) != g {
+		t.Error("no patterns should return the graph unchanged")
+	}
+	if ExcludePackages(g, []string{"app"}).NodeCount() != g.NodeCount() {
+		t.Error("root was excluded")
+	}
+}
+
+func TestValidateExcludes(t *testing.T) {
+	if err := ValidateExcludes([]string{"types-*", "@babel/*"}); err != nil {
+		t.Error(err)
+	}
+	if err := ValidateExcludes([]string{"["}); err == nil {
+		t.Error("invalid pattern accepted")
+	}
+}
@@ -0,0 +1,109 @@
+package deps
+
+import (
+	"context"
+
+	"github.com/stacktower-io/stacktower/pkg/core/deps/constraints"
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+// RegistryFetcher adapts an [integrations.RegistryClient] to [Fetcher],
+// [VersionLister] and [RuntimeConstraintLister], so a language whose
+// registry needs no special handling can hand its client to
+// [NewPubGrubResolver] without writing its own fetcher.
+type RegistryFetcher struct {
+	// Client is the registry to fetch packages from.
+	Client integrations.RegistryClient
+
+	// RuntimeVersion is the target runtime version. When set, fetching a
+	// package whose runtime constraint excludes it fails with an
+	// [IncompatibleRuntimeError].
+	RuntimeVersion string
+
+	// ManifestFile is the manifest type recorded on fetched packages
+	// (e.g., "Cargo.toml").
+	ManifestFile string
+}
+
+var (
+	_ VersionLister           = RegistryFetcher{}
+	_ RuntimeConstraintLister = RegistryFetcher{}
+)
+
+// Fetch implements [Fetcher].
+func (f RegistryFetcher) Fetch(ctx context.Context, name string, refresh bool) (*Package, error) {
+	return f.FetchVersion(ctx, name, "", refresh)
+}
+
+// FetchVersion implements [Fetcher]. An empty version fetches the latest.
+func (f RegistryFetcher) FetchVersion(ctx context.Context, name, version string, refresh bool) (*Package, error) {
+	info, err := f.Client.FetchPackage(ctx, name, version, integrations.FetchOptions{Refresh: refresh})
+	if err != nil {
+		return nil, err
+	}
+	pkg := PackageFromInfo(info)
+	pkg.ManifestFile = f.ManifestFile
+	if f.RuntimeVersion != "" && pkg.RuntimeConstraint != "" &&
+		!constraints.CheckVersionConstraint(f.RuntimeVersion, pkg.RuntimeConstraint) {
+		return nil, &IncompatibleRuntimeError{
+			Package:           name,
+			Version:           pkg.Version,
+			RuntimeConstraint: pkg.RuntimeConstraint,
+			TargetRuntime:     f.RuntimeVersion,
+		}
+	}
+	return pkg, nil
+}
+
+// ListVersions implements [VersionLister].
+func (f RegistryFetcher) ListVersions(ctx context.Context, name string, refresh bool) ([]string, error) {
+	return f.Client.ListVersions(ctx, name, integrations.FetchOptions{Refresh: refresh})
+}
+
+// ListVersionsWithConstraints implements [RuntimeConstraintLister] with
+// normalized constraints. It returns nil, nil when the client cannot list
+// them.
+func (f RegistryFetcher) ListVersionsWithConstraints(ctx context.Context, name string, refresh bool) (map[string]string, error) {
+	lister, ok := f.Client.(integrations.RuntimeConstraintLister)
+	if !ok {
+		return nil, nil
+	}
+	raw, err := lister.ListVersionsWithConstraints(ctx, name, integrations.FetchOptions{Refresh: refresh})
+	if err != nil {
+		return nil, err
+	}
+	for version, constraint := range raw {
+		raw[version] = constraints.NormalizeRuntimeConstraint(constraint)
+	}
+	return raw, nil
+}
+
+// PackageFromInfo converts registry metadata to a [Package]. Bare runtime
+// constraints, such as a crate's MSRV "1.70", become minimums (">=1.70").
+func PackageFromInfo(info *integrations.PackageInfo) *Package {
+	pkg := &Package{
+		Name:              info.Name,
+		Version:           info.Version,
+		Description:       info.Description,
+		License:           info.License,
+		LicenseText:       info.LicenseText,
+		Author:            info.Author,
+		Downloads:         info.Downloads,
+		InstallSize:       info.InstallSize,
+		Bundled:           info.Bundled,
+		Yanked:            info.Yanked,
+		Abandoned:         info.Abandoned,
+		Replacement:       info.Replacement,
+		Repository:        info.Repository,
+		HomePage:          info.HomePage,
+		ProjectURLs:       info.ProjectURLs,
+		RuntimeConstraint: constraints.NormalizeRuntimeConstraint(info.RuntimeConstraint),
+	}
+	if len(info.Dependencies) > 0 {
+		pkg.Dependencies = make([]Dependency, len(info.Dependencies))
+		for i, d := range info.Dependencies {
+			pkg.Dependencies[i] = Dependency{Name: d.Name, Constraint: d.Constraint}
+		}
+	}
+	return pkg
+}
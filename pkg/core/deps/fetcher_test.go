@@ -0,0 +1,127 @@
+package deps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+// fakeRegistry serves packages from a map keyed by "name@version", where
+// the empty version is the latest.
+type fakeRegistry struct {
+	packages map[string]*integrations.PackageInfo
+}
+
+func (r fakeRegistry) Name() string { return "fake" }
+
+func (r fakeRegistry) FetchPackage(_ context.Context, name, version string, _ integrations.FetchOptions) (*integrations.PackageInfo, error) {
+	info, ok := r.packages[name+"@"+version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s@%s", integrations.ErrNotFound, name, version)
+	}
+	return info, nil
+}
+
+func (r fakeRegistry) ListVersions(_ context.Context, name string, _ integrations.FetchOptions) ([]string, error) {
+	var versions []string
+	for _, info := range r.packages {
+		if info.Name == name {
+			versions = append(versions, info.Version)
+		}
+	}
+	return versions, nil
+}
+
+// fakeConstraintRegistry also lists runtime constraints.
+type fakeConstraintRegistry struct {
+	fakeRegistry
+}
+
+func (r fakeConstraintRegistry) ListVersionsWithConstraints(_ context.Context, name string, _ integrations.FetchOptions) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, info := range r.packages {
+		if info.Name == name {
+			result[info.Version] = info.RuntimeConstraint
+		}
+	}
+	return result, nil
+}
+
+func TestRegistryFetcher_FetchVersion(t *testing.T) {
+	latest := &integrations.PackageInfo{
+		Name:              "serde",
+		Version:           "1.0.200",
+		Dependencies:      []integrations.Dependency{{Name: "serde_derive", Constraint: "=1.0.200"}},
+		License:           "MIT",
+		RuntimeConstraint: "1.61",
+		Abandoned:         true,
+		Replacement:       "serde2",
+	}
+	old := &integrations.PackageInfo{Name: "serde", Version: "1.0.0"}
+	f := RegistryFetcher{
+		Client: fakeRegistry{packages: map[string]*integrations.PackageInfo{
+			"serde@": latest, "serde@1.0.200": latest, "serde@1.0.0": old,
+		}},
+		ManifestFile: "Cargo.toml",
+	}
+	ctx := context.Background()
+
+	pkg, err := f.Fetch(ctx, "serde", false)
+	if err != nil {
+		t.Fatalf("Fetch error: %v", err)
+	}
+	if pkg.Version != "1.0.200" || pkg.License != "MIT" || pkg.ManifestFile != "Cargo.toml" {
+		t.Errorf("unexpected package: %+v", pkg)
+	}
+	if pkg.RuntimeConstraint != ">=1.61" {
+		t.Errorf("RuntimeConstraint = %q, want the normalized >=1.61", pkg.RuntimeConstraint)
+	}
+	if len(pkg.Dependencies) != 1 || pkg.Dependencies[0] != (Dependency{Name: "serde_derive", Constraint: "=1.0.200"}) {
+		t.Errorf("unexpected dependencies: %v", pkg.Dependencies)
+	}
+	if !pkg.Abandoned || pkg.Replacement != "serde2" {
+		t.Errorf("abandoned flag should carry over, got %v %q", pkg.Abandoned, pkg.Replacement)
+	}
+
+	f.RuntimeVersion = "1.56"
+	_, err = f.Fetch(ctx, "serde", false)
+	var incompatible *IncompatibleRuntimeError
+	if !errors.As(err, &incompatible) {
+		t.Fatalf("expected IncompatibleRuntimeError, got %v", err)
+	}
+	if incompatible.RuntimeConstraint != ">=1.61" || incompatible.TargetRuntime != "1.56" {
+		t.Errorf("unexpected error: %+v", incompatible)
+	}
+	if _, err := f.FetchVersion(ctx, "serde", "1.0.0", false); err != nil {
+		t.Errorf("versions without a runtime constraint should fetch: %v", err)
+	}
+	if _, err := f.Fetch(ctx, "missing", false); !errors.Is(err, integrations.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRegistryFetcher_ListVersionsWithConstraints(t *testing.T) {
+	packages := map[string]*integrations.PackageInfo{
+		"pkg@1.0.0": {Name: "pkg", Version: "1.0.0", RuntimeConstraint: "1.70"},
+		"pkg@0.9.0": {Name: "pkg", Version: "0.9.0"},
+	}
+	ctx := context.Background()
+
+	f := RegistryFetcher{Client: fakeConstraintRegistry{fakeRegistry{packages: packages}}}
+	got, err := f.ListVersionsWithConstraints(ctx, "pkg", false)
+	if err != nil {
+		t.Fatalf("ListVersionsWithConstraints error: %v", err)
+	}
+	if got["1.0.0"] != ">=1.70" || got["0.9.0"] != "" {
+		t.Errorf("unexpected constraints: %v", got)
+	}
+
+	f = RegistryFetcher{Client: fakeRegistry{packages: packages}}
+	got, err = f.ListVersionsWithConstraints(ctx, "pkg", false)
+	if got != nil || err != nil {
+		t.Errorf("registries without constraint listing should return nil, nil; got %v, %v", got, err)
+	}
+}
@@ -0,0 +1,96 @@
+package deps
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+// MatchPackages returns the sorted IDs of the packages whose ID matches one
+// of the shell-style glob patterns, ignoring case.
+func MatchPackages(g *dag.DAG, patterns []string) []string {
+	var ids []string
+	for _, n := range g.Nodes() {
+		if matchAny(patterns, n.ID) {
+			ids = append(ids, n.ID)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// CollapsePackages merges the packages matching each glob pattern into a
+// single node named after the pattern, so "@babel/*" draws the whole Babel
+// family as one block. Edges into and out of a group are rewired to the
+// group node and edges inside it are dropped. Roots are never collapsed,
+// and a pattern matching fewer than two packages leaves them as they are.
+// A package matching several patterns joins the first group.
+//
+// Collapsing can introduce cycles (a → @babel/x → b → @babel/y becomes
+// a → @babel/* ⇄ b); normalization breaks them before layout.
+func CollapsePackages(g *dag.DAG, patterns []string) *dag.DAG {
+	if len(patterns) == 0 {
+		return g
+	}
+
+	group := map[string]string{} // member ID → group ID
+	members := map[string][]string{}
+	var groups []string
+	for _, p := range patterns {
+		if _, ok := g.Node(p); ok {
+			continue // a package already has the group's name
+		}
+		var ids []string
+		for _, id := range MatchPackages(g, []string{p}) {
+			if _, taken := group[id]; !taken && g.InDegree(id) > 0 {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) < 2 || members[p] != nil {
+			continue
+		}
+		for _, id := range ids {
+			group[id] = p
+		}
+		members[p] = ids
+		groups = append(groups, p)
+	}
+	if len(groups) == 0 {
+		return g
+	}
+
+	collapsed := dag.New(nil)
+	maps.Copy(collapsed.Meta(), g.Meta())
+	for _, n := range g.Nodes() {
+		if _, ok := group[n.ID]; !ok {
+			node := *n
+			node.Meta = maps.Clone(n.Meta)
+			_ = collapsed.AddNode(node)
+		}
+	}
+	for _, id := range groups {
+		_ = collapsed.AddNode(dag.Node{ID: id, Meta: dag.Metadata{
+			"collapsed":   members[id],
+			"description": fmt.Sprintf("%d packages matching %s", len(members[id]), id),
+		}})
+	}
+
+	seen := map[[2]string]bool{}
+	for _, e := range g.Edges() {
+		from, to := e.From, e.To
+		if id, ok := group[from]; ok {
+			from = id
+		}
+		if id, ok := group[to]; ok {
+			to = id
+		}
+		if from == to || seen[[2]string{from, to}] {
+			continue
+		}
+		seen[[2]string{from, to}] = true
+		_ = collapsed.AddEdge(dag.Edge{From: from, To: to, Meta: maps.Clone(e.Meta)})
+	}
+	return collapsed
+}
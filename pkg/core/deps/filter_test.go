@@ -0,0 +1,71 @@
+package deps
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func buildFilterGraph() *dag.DAG {
+	g := dag.New(dag.Metadata{"language": "javascript"})
+	for _, id := range []string{"app", "@babel/core", "@babel/parser", "@babel/types", "debug", "ms"} {
+		_ = g.AddNode(dag.Node{ID: id})
+	}
+	for _, e := range [][2]string{
+		{"app", "@babel/core"}, {"app", "debug"},
+		{"@babel/core", "@babel/parser"}, {"@babel/core", "@babel/types"}, {"@babel/core", "debug"},
+		{"@babel/parser", "@babel/types"}, {"debug", "ms"},
+	} {
+		_ = g.AddEdge(dag.Edge{From: e[0], To: e[1]})
+	}
+	return g
+}
+
+func TestMatchPackages(t *testing.T) {
+	g := buildFilterGraph()
+	got := MatchPackages(g, []string{"@BABEL/p*", "ms"})
+	if want := []string{"@babel/parser", "ms"}; !slices.Equal(got, want) {
+		t.Errorf("MatchPackages() = %v, want %v", got, want)
+	}
+	if got := MatchPackages(g, []string{"nope"}); len(got) != 0 {
+		t.Errorf("MatchPackages(nope) = %v, want none", got)
+	}
+}
+
+func TestCollapsePackages(t *testing.T) {
+	g := buildFilterGraph()
+
+	got := CollapsePackages(g, []string{"@babel/*"})
+	ids := dag.NodeIDs(got.Nodes())
+	slices.Sort(ids)
+	if want := []string{"@babel/*", "app", "debug", "ms"}; !slices.Equal(ids, want) {
+		t.Fatalf("nodes = %v, want %v", ids, want)
+	}
+	if children := got.Children("@babel/*"); !slices.Equal(children, []string{"debug"}) {
+		t.Errorf("group children = %v, want [debug]", children)
+	}
+	if parents := got.Parents("@babel/*"); !slices.Equal(parents, []string{"app"}) {
+		t.Errorf("group parents = %v, want [app]", parents)
+	}
+	if got.EdgeCount() != 4 {
+		t.Errorf("edges = %d, want 4", got.EdgeCount())
+	}
+	n, _ := got.Node("@babel/*")
+	if members, _ := n.Meta["collapsed"].([]string); len(members) != 3 {
+		t.Errorf("collapsed = %v, want the three Babel packages", n.Meta["collapsed"])
+	}
+	if got.Meta()["language"] != "javascript" {
+		t.Error("graph metadata was not kept")
+	}
+
+	if CollapsePackages(g, nil) != g {
+		t.Error("no patterns should return the graph unchanged")
+	}
+	if CollapsePackages(g, []string{"ms"}) != g {
+		t.Error("a single match should leave the graph unchanged")
+	}
+	if _, ok := CollapsePackages(g, []string{"*"}).Node("app"); !ok {
+		t.Error("the root should not be collapsed")
+	}
+}
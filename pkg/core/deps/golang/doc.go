@@ -16,6 +16,25 @@
 //
 // Package names are full module paths (e.g., "github.com/user/repo").
 //
+// # Minimal Version Selection
+//
+// Versions are selected the way the go command selects them, with minimal
+// version selection (MVS): every module in the requirement graph is used at
+// the highest version any go.mod in the graph requires. When the root module
+// is at go 1.17 or later, the graph is pruned like Go prunes it, so the
+// requirements of go 1.17+ dependencies are not followed further.
+//
+// The replace and exclude directives of the root module's go.mod apply
+// across the graph; those of dependencies are ignored, as in Go. A
+// requirement on an excluded version moves to the next higher version that
+// is not excluded, and is dropped when there is none. Replaced modules keep
+// their path and carry a "replaced_by" metadata entry, and local directory
+// replacements have no dependencies.
+//
+// Requirements marked "// indirect" take part in version selection, but
+// only become edges with [deps.Options].IncludeIndirect. Modules reached
+// only through them are then marked "indirect".
+//
 // # Manifest Parsing
 //
 // Parse go.mod files:
@@ -29,4 +48,5 @@
 //
 // [goproxy]: github.com/stacktower-io/stacktower/pkg/integrations/goproxy
 // [deps.Language]: github.com/stacktower-io/stacktower/pkg/core/deps.Language
+// [deps.Options]: github.com/stacktower-io/stacktower/pkg/core/deps.Options
 package golang
@@ -2,7 +2,9 @@ package golang
 
 import (
 	"context"
+	"maps"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -32,7 +34,13 @@ var Language = &deps.Language{
 
 func newResolver(backend cache.Cache, opts deps.Options) (deps.Resolver, error) {
 	c := goproxy.NewClient(backend, opts.CacheTTL)
-	f := fetcher{client: c, goVersion: opts.RuntimeVersion}
+	f := fetcher{
+		RegistryFetcher: deps.RegistryFetcher{
+			Client:       goproxy.NewRegistry(c),
+			ManifestFile: "go.mod",
+		},
+		client: c,
+	}
 	r, err := deps.NewPubGrubResolver("goproxy", f, GoModMatcher{})
 	if err != nil {
 		return nil, err
@@ -42,12 +50,12 @@ func newResolver(backend cache.Cache, opts deps.Options) (deps.Resolver, error)
 
 // goResolver wraps PubGrubResolver and injects the goproxy fetcher as a
 // MetadataProvider so that license information is fetched post-resolution
-// (enrichment phase) rather than during the PubGrub solving phase.
+// (enrichment phase) rather than during resolution.
 //
-// For Go 1.17+ modules, the resolver uses the module's go.mod indirect deps
-// directly (lockfile-style resolution) instead of recursive PubGrub resolution.
-// This respects Go's module graph pruning and produces results consistent with
-// what `go mod tidy` would generate.
+// Resolve does not use PubGrub: Go selects versions with minimal version
+// selection (MVS), which the resolver runs over the module graph, honoring
+// the replace and exclude directives of the root module's go.mod. The
+// results match the build list the go command computes.
 type goResolver struct {
 	*deps.PubGrubResolver
 	provider fetcher
@@ -55,14 +63,9 @@ type goResolver struct {
 }
 
 func (r *goResolver) Resolve(ctx context.Context, pkg string, opts deps.Options) (*dag.DAG, error) {
+	pkg, opts = deps.WithPackageSpec(pkg, opts.WithDefaults())
 	opts.MetadataProviders = append([]deps.MetadataProvider{r.provider}, opts.MetadataProviders...)
-
-	// First, fetch the root module to check its go version
-	version := ""
-	if idx := strings.Index(pkg, "@"); idx != -1 {
-		version = pkg[idx+1:]
-		pkg = pkg[:idx]
-	}
+	version := opts.Version
 
 	var rootModule *goproxy.ModuleInfo
 	var err error
@@ -74,143 +77,155 @@ func (r *goResolver) Resolve(ctx context.Context, pkg string, opts deps.Options)
 	if err != nil {
 		return nil, err
 	}
-
-	// For Go 1.17+ modules, use lockfile-style resolution.
-	// The go.mod's indirect deps already represent the pruned module graph.
-	if isGo117OrLater(rootModule.GoVersion) {
-		return r.resolveLockfileStyle(ctx, rootModule, opts)
-	}
-
-	// For older modules, fall back to recursive PubGrub resolution
-	pkgWithVersion := pkg
-	if version != "" {
-		pkgWithVersion = pkg + "@" + version
-	}
-	return r.PubGrubResolver.Resolve(ctx, pkgWithVersion, opts)
+	return r.resolveMVS(ctx, rootModule, opts)
 }
 
-// resolveLockfileStyle builds a dependency graph directly from the module's
-// go.mod file, using both direct and indirect dependencies. This is used for
-// Go 1.17+ modules where the indirect deps represent the pruned module graph.
-func (r *goResolver) resolveLockfileStyle(ctx context.Context, root *goproxy.ModuleInfo, opts deps.Options) (*dag.DAG, error) {
-	g := dag.New(nil)
-
-	// Add root node
-	rootMeta := dag.Metadata{"version": root.Version}
-	if root.GoVersion != "" {
-		rootMeta["go_version"] = root.GoVersion
+// resolveMVS builds the dependency graph of root from its build list and
+// enriches it.
+func (r *goResolver) resolveMVS(ctx context.Context, root *goproxy.ModuleInfo, opts deps.Options) (*dag.DAG, error) {
+	fetch := func(ctx context.Context, path, version string) (*goproxy.ModuleInfo, error) {
+		return r.client.FetchModuleVersion(ctx, path, version, opts.Refresh)
 	}
-	_ = g.AddNode(dag.Node{ID: root.Path, Meta: rootMeta})
-
-	// Build lookup of all dependencies
-	allDeps := make(map[string]goproxy.Dependency, len(root.Dependencies)+len(root.IndirectDependencies))
-	directSet := make(map[string]bool, len(root.Dependencies))
-
-	for _, dep := range root.Dependencies {
-		allDeps[dep.Name] = dep
-		directSet[dep.Name] = true
+	list := func(ctx context.Context, path string) ([]string, error) {
+		return r.client.ListVersions(ctx, path, opts.Refresh)
 	}
-	for _, dep := range root.IndirectDependencies {
-		allDeps[dep.Name] = dep
+	g, refs := buildMVSGraph(ctx, newMVS(fetch, list, root, opts), root, opts)
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	// Add all dependency nodes and build package refs for enrichment
-	refs := make([]*deps.PackageRef, 0, len(allDeps)+1)
-	refs = append(refs, makeGoPackageRef(root.Path, root.Version))
-
-	for name, dep := range allDeps {
-		version := strings.TrimPrefix(dep.Constraint, "=")
-		meta := dag.Metadata{}
-		if version != "" {
-			meta["version"] = version
+	// Enrich metadata (licenses, etc.) from metadata providers
+	if len(opts.MetadataProviders) > 0 {
+		if selected := opts.EnrichFilter.Select(g); selected != nil {
+			refs = slices.DeleteFunc(refs, func(ref *deps.PackageRef) bool { return !selected[ref.Name] })
 		}
-		if !directSet[name] {
-			meta["indirect"] = true
+		deps.RankByDependents(g, refs)
+		enriched := r.enrichPackages(ctx, refs, opts)
+		for name, meta := range enriched {
+			if n, ok := g.Node(name); ok {
+				for k, v := range meta {
+					n.Meta[k] = v
+				}
+			}
 		}
-		_ = g.AddNode(dag.Node{ID: name, Meta: meta})
-
-		refs = append(refs, makeGoPackageRef(name, version))
 	}
 
-	// Connect direct deps to root
-	for _, dep := range root.Dependencies {
-		edgeMeta := dag.Metadata{}
-		if dep.Constraint != "" {
-			edgeMeta["constraint"] = dep.Constraint
-		}
-		_ = g.AddEdge(dag.Edge{From: root.Path, To: dep.Name, Meta: edgeMeta})
-	}
+	return g, nil
+}
 
-	// Fetch dependency edges in parallel (like buildGoModGraphWithEdges in gomod.go)
-	type fetchResult struct {
-		name         string
-		dependencies []goproxy.Dependency
-	}
+// buildMVSGraph returns the dependency graph of root, and references to its
+// packages for enrichment. Each module appears at its version in the build
+// list, with edges for the requirements in its go.mod; requirements marked
+// "// indirect" are only followed when opts.IncludeIndirect is set, and the
+// modules reached only through them are marked "indirect". Modules left out
+// of the build list, such as those required only at excluded versions with
+// no higher version to move to, are omitted.
+func buildMVSGraph(ctx context.Context, m *mvs, root *goproxy.ModuleInfo, opts deps.Options) (*dag.DAG, []*deps.PackageRef) {
+	selected := m.buildList(ctx, root)
 
-	// Convert to slice for parallel processing
-	depSlice := make([]goproxy.Dependency, 0, len(allDeps))
-	for _, dep := range allDeps {
-		depSlice = append(depSlice, dep)
+	g := dag.New(nil)
+	rootMeta := dag.Metadata{"version": root.Version}
+	if root.GoVersion != "" {
+		rootMeta["go_version"] = root.GoVersion
 	}
+	_ = g.AddNode(dag.Node{ID: root.Path, Meta: rootMeta})
+	refs := []*deps.PackageRef{makeGoPackageRef(root.Path, root.Version)}
+
+	// Walk the graph breadth-first so MaxDepth and MaxNodes keep the modules
+	// closest to the root
+	level := []*goproxy.ModuleInfo{root}
+	for depth := 1; len(level) > 0 && depth <= opts.MaxDepth; depth++ {
+		var next []string
+		for _, info := range level {
+			reqs := info.Dependencies
+			if opts.IncludeIndirect {
+				reqs = allRequirements(info)
+			}
+			for i, dep := range reqs {
+				version, ok := selected[dep.Name]
+				if !ok || dep.Name == info.Path {
+					continue
+				}
+				indirect := i >= len(info.Dependencies)
+
+				n, exists := g.Node(dep.Name)
+				if !exists {
+					if g.NodeCount() >= opts.MaxNodes {
+						continue
+					}
+					meta := dag.Metadata{"version": version}
+					if indirect {
+						meta["indirect"] = true
+					}
+					if rep, ok := m.replacement(dep.Name, version); ok {
+						meta["replaced_by"] = rep.String()
+					}
+					_ = g.AddNode(dag.Node{ID: dep.Name, Meta: meta})
+					refs = append(refs, makeGoPackageRef(dep.Name, version))
+					next = append(next, dep.Name)
+				} else if !indirect {
+					delete(n.Meta, "indirect")
+				}
 
-	results := deps.ParallelMapOrdered(ctx, opts.Workers, depSlice, func(ctx context.Context, dep goproxy.Dependency) fetchResult {
-		if ctx.Err() != nil {
-			return fetchResult{name: dep.Name}
-		}
-
-		version := strings.TrimPrefix(dep.Constraint, "=")
-		if version == "" {
-			return fetchResult{name: dep.Name}
-		}
-
-		// Fetch the package to get its dependencies
-		pkg, err := r.client.FetchModuleVersion(ctx, dep.Name, version, opts.Refresh)
-		if err != nil {
-			return fetchResult{name: dep.Name}
-		}
-		return fetchResult{name: dep.Name, dependencies: pkg.Dependencies}
-	})
-
-	// Add edges based on fetched dependencies
-	for _, res := range results {
-		for _, childDep := range res.dependencies {
-			// Only add edge if the target exists in our known set
-			if _, exists := allDeps[childDep.Name]; exists {
+				if g.HasEdge(info.Path, dep.Name) {
+					continue
+				}
 				edgeMeta := dag.Metadata{}
-				if childDep.Constraint != "" {
-					edgeMeta["constraint"] = childDep.Constraint
+				if dep.Constraint != "" {
+					edgeMeta["constraint"] = dep.Constraint
 				}
-				_ = g.AddEdge(dag.Edge{From: res.name, To: childDep.Name, Meta: edgeMeta})
+				_ = g.AddEdge(dag.Edge{From: info.Path, To: dep.Name, Meta: edgeMeta})
 			}
 		}
-	}
+		if depth == opts.MaxDepth {
+			break
+		}
 
-	// Enrich metadata (licenses, etc.) from metadata providers
-	if len(opts.MetadataProviders) > 0 {
-		enriched := r.enrichPackages(ctx, refs, opts)
-		for name, meta := range enriched {
-			if n, ok := g.Node(name); ok {
-				for k, v := range meta {
-					n.Meta[k] = v
-				}
+		infos := deps.ParallelMapOrdered(ctx, opts.Workers, next, func(ctx context.Context, name string) *goproxy.ModuleInfo {
+			info := m.load(ctx, name, selected[name])
+			if info == nil {
+				return nil
+			}
+			// The go.mod of a replacement names the replacement's path
+			named := *info
+			named.Path = name
+			return &named
+		})
+		level = nil
+		for _, info := range infos {
+			if info != nil {
+				level = append(level, info)
 			}
 		}
 	}
-
-	return g, nil
+	return g, refs
 }
 
 // enrichPackages calls metadata providers for each package to fetch licenses and other metadata.
 func (r *goResolver) enrichPackages(ctx context.Context, refs []*deps.PackageRef, opts deps.Options) map[string]map[string]any {
-	// Try batch enrichment first (e.g., GitHub GraphQL)
+	// Try batch enrichment first (e.g., GitHub GraphQL), combining the
+	// results of every batch provider that succeeds
+	var combined map[string]map[string]any
 	for _, p := range opts.MetadataProviders {
 		if bp, ok := p.(deps.BatchMetadataProvider); ok {
 			batch, err := bp.EnrichBatch(ctx, refs, opts.Refresh)
-			if err == nil && batch != nil {
-				return batch
+			if err != nil || batch == nil {
+				continue
+			}
+			if combined == nil {
+				combined = make(map[string]map[string]any, len(batch))
+			}
+			for name, meta := range batch {
+				if combined[name] == nil {
+					combined[name] = make(map[string]any, len(meta))
+				}
+				maps.Copy(combined[name], meta)
 			}
 		}
 	}
+	if combined != nil {
+		return combined
+	}
 
 	// Fall back to per-package enrichment
 	type enrichResult struct {
@@ -271,30 +286,27 @@ func (r *goResolver) ListVersions(ctx context.Context, name string, refresh bool
 	return r.PubGrubResolver.ListVersions(ctx, name, refresh)
 }
 
+// fetcher is a [deps.RegistryFetcher] that points fetched modules at their
+// GitHub repositories where it can (see normalizeRepositoryURL), and a
+// deps.MetadataProvider for licenses.
 type fetcher struct {
-	client    *goproxy.Client
-	goVersion string
+	deps.RegistryFetcher
+	client *goproxy.Client
 }
 
 func (f fetcher) Fetch(ctx context.Context, name string, refresh bool) (*deps.Package, error) {
-	m, err := f.client.FetchModule(ctx, name, refresh)
-	if err != nil {
-		return nil, err
-	}
-	return goproxyModuleToDepsPkg(m), nil
+	return f.FetchVersion(ctx, name, "", refresh)
 }
 
 func (f fetcher) FetchVersion(ctx context.Context, name, version string, refresh bool) (*deps.Package, error) {
-	m, err := f.client.FetchModuleVersion(ctx, name, version, refresh)
+	pkg, err := f.RegistryFetcher.FetchVersion(ctx, name, version, refresh)
 	if err != nil {
 		return nil, err
 	}
-	return goproxyModuleToDepsPkg(m), nil
-}
-
-// ListVersions implements deps.VersionLister for constraint-based resolution.
-func (f fetcher) ListVersions(ctx context.Context, name string, refresh bool) ([]string, error) {
-	return f.client.ListVersions(ctx, name, refresh)
+	// License is not set here; it is populated during the post-resolution
+	// enrichment phase via fetcher.Enrich → goproxy.Client.FetchLicense.
+	pkg.Repository = normalizeRepositoryURL(pkg.Name, pkg.Repository)
+	return pkg, nil
 }
 
 // Name implements deps.MetadataProvider.
@@ -317,32 +329,6 @@ func (f fetcher) Enrich(ctx context.Context, ref *deps.PackageRef, refresh bool)
 	return meta, nil
 }
 
-func goproxyModuleToDepsPkg(m *goproxy.ModuleInfo) *deps.Package {
-	pkg := &deps.Package{
-		Name:         m.Path,
-		Version:      m.Version,
-		ManifestFile: "go.mod",
-		// License is not set here; it is populated during the post-resolution
-		// enrichment phase via fetcher.Enrich → goproxy.Client.FetchLicense.
-	}
-	// Convert goproxy.Dependency to deps.Dependency with constraints
-	if len(m.Dependencies) > 0 {
-		pkg.Dependencies = make([]deps.Dependency, len(m.Dependencies))
-		for i, d := range m.Dependencies {
-			pkg.Dependencies[i] = deps.Dependency{
-				Name:       d.Name,
-				Constraint: d.Constraint,
-			}
-		}
-	}
-
-	// Prefer discovered repository, but normalize googlesource URLs to their
-	// GitHub mirrors for downstream metadata enrichment.
-	pkg.Repository = normalizeRepositoryURL(m.Path, m.Repository)
-
-	return pkg
-}
-
 func newManifest(name string, res deps.Resolver) deps.ManifestParser {
 	switch name {
 	case "gomod":
@@ -385,6 +371,9 @@ func inferRepoURL(modulePath string) string {
 	return ""
 }
 
+// normalizeRepositoryURL prefers the discovered repository, but normalizes
+// googlesource URLs to their GitHub mirrors for downstream metadata
+// enrichment, and falls back to the repository inferred from the module path.
 func normalizeRepositoryURL(modulePath, discoveredRepo string) string {
 	// Keep known repo hosts as-is.
 	if discoveredRepo != "" {
@@ -6,7 +6,6 @@ import (
 
 	"github.com/stacktower-io/stacktower/pkg/cache"
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
-	"github.com/stacktower-io/stacktower/pkg/integrations/goproxy"
 )
 
 func TestLanguageDefinition(t *testing.T) {
@@ -279,35 +278,24 @@ func TestInferRepoURL(t *testing.T) {
 	}
 }
 
-func TestGoProxyModuleToDepsPkg_UsesDiscoveredRepository(t *testing.T) {
-	pkg := goproxyModuleToDepsPkg(&goproxy.ModuleInfo{
-		Path:       "gopkg.in/yaml.v3",
-		Version:    "v3.0.1",
-		Repository: "https://github.com/go-yaml/yaml",
-	})
-	if pkg.Repository != "https://github.com/go-yaml/yaml" {
-		t.Fatalf("Repository = %q, want discovered github repo", pkg.Repository)
+func TestNormalizeRepositoryURL_UsesDiscoveredRepository(t *testing.T) {
+	got := normalizeRepositoryURL("gopkg.in/yaml.v3", "https://github.com/go-yaml/yaml")
+	if got != "https://github.com/go-yaml/yaml" {
+		t.Fatalf("Repository = %q, want discovered github repo", got)
 	}
 }
 
-func TestGoProxyModuleToDepsPkg_MirrorsGoogleSourceRepository(t *testing.T) {
-	pkg := goproxyModuleToDepsPkg(&goproxy.ModuleInfo{
-		Path:       "golang.org/x/crypto",
-		Version:    "v0.30.0",
-		Repository: "https://go.googlesource.com/crypto",
-	})
-	if pkg.Repository != "https://github.com/golang/crypto" {
-		t.Fatalf("Repository = %q, want github mirror", pkg.Repository)
+func TestNormalizeRepositoryURL_MirrorsGoogleSourceRepository(t *testing.T) {
+	got := normalizeRepositoryURL("golang.org/x/crypto", "https://go.googlesource.com/crypto")
+	if got != "https://github.com/golang/crypto" {
+		t.Fatalf("Repository = %q, want github mirror", got)
 	}
 }
 
-func TestGoProxyModuleToDepsPkg_FallsBackToGolangXMirrorWhenRepositoryMissing(t *testing.T) {
-	pkg := goproxyModuleToDepsPkg(&goproxy.ModuleInfo{
-		Path:    "golang.org/x/net",
-		Version: "v0.31.0",
-	})
-	if pkg.Repository != "https://github.com/golang/net" {
-		t.Fatalf("Repository = %q, want github mirror fallback", pkg.Repository)
+func TestNormalizeRepositoryURL_FallsBackToGolangXMirrorWhenRepositoryMissing(t *testing.T) {
+	got := normalizeRepositoryURL("golang.org/x/net", "")
+	if got != "https://github.com/golang/net" {
+		t.Fatalf("Repository = %q, want github mirror fallback", got)
 	}
 }
 
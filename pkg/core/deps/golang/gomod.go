@@ -90,7 +90,7 @@ func filterGoModRuntimeDeps(path string, directDeps, indirectDeps []deps.Depende
 	opts = opts.WithDefaults()
 	modules, err := runtimeGoModulesFn(opts.Ctx, filepath.Dir(path))
 	if err != nil {
-		opts.Logger("go runtime dependency filter skipped: %v", err)
+		opts.Logger.Warn("go runtime dependency filter skipped", "err", err)
 		return directDeps, indirectDeps
 	}
 	if len(modules) == 0 {
@@ -312,7 +312,7 @@ func buildGoModGraphWithEdges(ctx context.Context, resolver deps.Resolver, direc
 		// Fetch the package to get its dependencies
 		pkg, err := fetcher.FetchVersion(ctx, dep.Name, version, opts.Refresh)
 		if err != nil {
-			opts.Logger("fetch %s@%s for edges: %v", dep.Name, version, err)
+			opts.Logger.Warn("fetch for edges failed", "package", dep.Name, "version", version, "err", err)
 			return fetchResult{name: dep.Name, err: err}
 		}
 		return fetchResult{name: dep.Name, dependencies: pkg.Dependencies}
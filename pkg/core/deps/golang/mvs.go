@@ -0,0 +1,247 @@
+package golang
+
+import (
+	"cmp"
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/integrations/goproxy"
+)
+
+// mvs computes the build list of a main module with minimal version
+// selection (MVS): each module in the requirement graph is selected at the
+// highest version any go.mod in the graph requires.
+//
+// Like the go command, it applies the replace and exclude directives of the
+// main module, and ignores those of its dependencies. A requirement on an
+// excluded version moves to the next higher version that is not excluded.
+type mvs struct {
+	fetch   moduleFetcher
+	list    moduleLister
+	opts    deps.Options
+	main    string                    // Path of the main module
+	replace map[string]goproxy.Module // Replacements by "path@version", or by path for all versions
+	exclude map[string]bool           // Excluded "path@version"s
+	pruned  bool                      // Main module at go 1.17+, which prunes the graph
+
+	mu    sync.Mutex
+	infos map[string]*goproxy.ModuleInfo // Loaded go.mod files by "path@version"
+	lists map[string][]string            // Listed versions by path
+}
+
+// moduleFetcher fetches the go.mod of a module version.
+type moduleFetcher func(ctx context.Context, path, version string) (*goproxy.ModuleInfo, error)
+
+// moduleLister lists the known versions of a module, oldest first.
+type moduleLister func(ctx context.Context, path string) ([]string, error)
+
+func newMVS(fetch moduleFetcher, list moduleLister, root *goproxy.ModuleInfo, opts deps.Options) *mvs {
+	m := &mvs{
+		fetch:   fetch,
+		list:    list,
+		opts:    opts,
+		main:    root.Path,
+		replace: make(map[string]goproxy.Module, len(root.Replace)),
+		exclude: make(map[string]bool, len(root.Exclude)),
+		pruned:  isGo117OrLater(root.GoVersion),
+		infos:   make(map[string]*goproxy.ModuleInfo),
+		lists:   make(map[string][]string),
+	}
+	for _, r := range root.Replace {
+		m.replace[r.Old.String()] = r.New
+	}
+	for _, e := range root.Exclude {
+		m.exclude[e.String()] = true
+	}
+	return m
+}
+
+// replacement returns the module that replaces path at version.
+func (m *mvs) replacement(path, version string) (goproxy.Module, bool) {
+	if r, ok := m.replace[path+"@"+version]; ok {
+		return r, true
+	}
+	r, ok := m.replace[path]
+	return r, ok
+}
+
+// load returns the go.mod of path at version, or of its replacement. Local
+// replacements are not on the proxy, so they have no requirements. It
+// returns nil when the module cannot be fetched.
+func (m *mvs) load(ctx context.Context, path, version string) *goproxy.ModuleInfo {
+	key := path + "@" + version
+	m.mu.Lock()
+	info, ok := m.infos[key]
+	m.mu.Unlock()
+	if ok {
+		return info
+	}
+
+	src := goproxy.Module{Path: path, Version: version}
+	if r, ok := m.replacement(path, version); ok {
+		src = r
+	}
+	if src.Version == "" {
+		info = &goproxy.ModuleInfo{Path: path, Version: version}
+	} else {
+		var err error
+		info, err = m.fetch(ctx, src.Path, src.Version)
+		if err != nil {
+			m.opts.Logger.Warn("fetch failed", "package", path, "version", version, "err", err)
+			info = nil
+		}
+	}
+
+	m.mu.Lock()
+	m.infos[key] = info
+	m.mu.Unlock()
+	return info
+}
+
+// upgradeExcluded returns the lowest version of path above the excluded
+// version that is not excluded itself, preferring releases to prereleases
+// as the go command does. It returns "" when there is none.
+func (m *mvs) upgradeExcluded(ctx context.Context, path, version string) string {
+	m.mu.Lock()
+	versions, ok := m.lists[path]
+	m.mu.Unlock()
+	if !ok {
+		var err error
+		versions, err = m.list(ctx, path)
+		if err != nil {
+			m.opts.Logger.Warn("list versions failed", "package", path, "err", err)
+		}
+		m.mu.Lock()
+		m.lists[path] = versions
+		m.mu.Unlock()
+	}
+
+	var release, prerelease string
+	for _, v := range versions {
+		if compareGoVersions(v, version) <= 0 || m.exclude[path+"@"+v] {
+			continue
+		}
+		best := &release
+		if parseGoVersion(v).prerelease != "" {
+			best = &prerelease
+		}
+		if *best == "" || compareGoVersions(v, *best) < 0 {
+			*best = v
+		}
+	}
+	return cmp.Or(release, prerelease)
+}
+
+// buildList walks the requirement graph from root and returns the selected
+// version of every module in it, keyed by module path.
+//
+// When the main module is at go 1.17 or later, the graph is pruned as the go
+// command prunes it: the requirements of go 1.17+ dependencies are included,
+// but not followed further, since such modules list everything their
+// packages need.
+func (m *mvs) buildList(ctx context.Context, root *goproxy.ModuleInfo) map[string]string {
+	type item struct{ path, version string }
+
+	selected := make(map[string]string)
+	followed := make(map[string]bool)
+	var queue []item
+
+	require := func(info *goproxy.ModuleInfo, follow bool) {
+		for _, dep := range allRequirements(info) {
+			version := strings.TrimPrefix(dep.Constraint, "=")
+			if m.exclude[dep.Name+"@"+version] {
+				version = m.upgradeExcluded(ctx, dep.Name, version)
+			}
+			if version == "" || dep.Name == m.main {
+				continue
+			}
+			if cur, ok := selected[dep.Name]; !ok || compareGoVersions(version, cur) > 0 {
+				selected[dep.Name] = version
+			}
+			key := dep.Name + "@" + version
+			if follow && !followed[key] {
+				followed[key] = true
+				queue = append(queue, item{dep.Name, version})
+			}
+		}
+	}
+
+	require(root, true)
+	for len(queue) > 0 && ctx.Err() == nil {
+		batch := queue
+		queue = nil
+		infos := deps.ParallelMapOrdered(ctx, m.opts.Workers, batch, func(ctx context.Context, it item) *goproxy.ModuleInfo {
+			return m.load(ctx, it.path, it.version)
+		})
+		for _, info := range infos {
+			if info != nil {
+				require(info, !m.pruned || !isGo117OrLater(info.GoVersion))
+			}
+		}
+	}
+	return selected
+}
+
+// allRequirements returns the direct and indirect requirements of a module,
+// which MVS treats alike.
+func allRequirements(info *goproxy.ModuleInfo) []goproxy.Dependency {
+	reqs := make([]goproxy.Dependency, 0, len(info.Dependencies)+len(info.IndirectDependencies))
+	reqs = append(reqs, info.Dependencies...)
+	return append(reqs, info.IndirectDependencies...)
+}
+
+// compareGoVersions compares two module versions in semver order, in which
+// pseudo-versions sort below the release they are based on. It returns -1,
+// 0 or 1.
+func compareGoVersions(a, b string) int {
+	va, vb := parseGoVersion(a), parseGoVersion(b)
+	if !va.valid || !vb.valid {
+		return strings.Compare(a, b)
+	}
+	if c := cmp.Compare(va.major, vb.major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(va.minor, vb.minor); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(va.patch, vb.patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(va.prerelease, vb.prerelease)
+}
+
+// comparePrerelease compares semver prerelease strings; a release (empty)
+// sorts above any prerelease.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = cmp.Compare(na, nb)
+		case errA == nil:
+			c = -1 // numeric identifiers sort below alphanumeric ones
+		case errB == nil:
+			c = 1
+		default:
+			c = strings.Compare(pa[i], pb[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(pa), len(pb))
+}
@@ -0,0 +1,240 @@
+package golang
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/integrations/goproxy"
+)
+
+// testModules serves go.mod files from a map keyed by "path@version", where
+// each require is "path version" with an optional " // indirect" suffix, and
+// lists the versions of each path in it.
+func testModules(mods map[string][]string, goVersions map[string]string) (moduleFetcher, moduleLister) {
+	fetch := func(_ context.Context, path, version string) (*goproxy.ModuleInfo, error) {
+		key := path + "@" + version
+		reqs, ok := mods[key]
+		if !ok {
+			return nil, errors.New("not found: " + key)
+		}
+		return testModule(path, version, goVersions[key], reqs...), nil
+	}
+	list := func(_ context.Context, path string) ([]string, error) {
+		var versions []string
+		for key := range mods {
+			if p, v, _ := strings.Cut(key, "@"); p == path {
+				versions = append(versions, v)
+			}
+		}
+		slices.SortFunc(versions, compareGoVersions)
+		return versions, nil
+	}
+	return fetch, list
+}
+
+func testModule(path, version, goVersion string, reqs ...string) *goproxy.ModuleInfo {
+	info := &goproxy.ModuleInfo{Path: path, Version: version, GoVersion: goVersion}
+	for _, r := range reqs {
+		line, indirect := strings.CutSuffix(r, " // indirect")
+		name, v, _ := strings.Cut(line, " ")
+		dep := goproxy.Dependency{Name: name, Constraint: "=" + v}
+		if indirect {
+			info.IndirectDependencies = append(info.IndirectDependencies, dep)
+		} else {
+			info.Dependencies = append(info.Dependencies, dep)
+		}
+	}
+	return info
+}
+
+func testOptions() deps.Options {
+	return deps.Options{}.WithDefaults()
+}
+
+func TestMVS_BuildList(t *testing.T) {
+	// The classic example from the MVS paper: B and C require different
+	// versions of D, and the highest wins
+	fetch, list := testModules(map[string][]string{
+		"b@v1.2.0": {"d v1.3.0"},
+		"c@v1.2.0": {"d v1.4.0"},
+		"d@v1.3.0": {"e v1.1.0"},
+		"d@v1.4.0": {"e v1.2.0"},
+		"e@v1.1.0": nil,
+		"e@v1.2.0": nil,
+	}, nil)
+	root := testModule("a", "v1.0.0", "", "b v1.2.0", "c v1.2.0")
+
+	got := newMVS(fetch, list, root, testOptions()).buildList(context.Background(), root)
+	want := map[string]string{"b": "v1.2.0", "c": "v1.2.0", "d": "v1.4.0", "e": "v1.2.0"}
+	if !maps.Equal(got, want) {
+		t.Errorf("buildList() = %v, want %v", got, want)
+	}
+}
+
+func TestMVS_BuildList_Pruned(t *testing.T) {
+	// b is a go 1.17 module: its requirements are in the graph, but their
+	// requirements are not
+	fetch, list := testModules(map[string][]string{
+		"b@v1.0.0": {"c v1.0.0"},
+		"c@v1.0.0": {"d v1.0.0"},
+	}, map[string]string{"b@v1.0.0": "1.17"})
+
+	root := testModule("a", "v1.0.0", "1.21", "b v1.0.0")
+	got := newMVS(fetch, list, root, testOptions()).buildList(context.Background(), root)
+	if want := map[string]string{"b": "v1.0.0", "c": "v1.0.0"}; !maps.Equal(got, want) {
+		t.Errorf("pruned buildList() = %v, want %v", got, want)
+	}
+
+	root = testModule("a", "v1.0.0", "1.16", "b v1.0.0")
+	got = newMVS(fetch, list, root, testOptions()).buildList(context.Background(), root)
+	if want := map[string]string{"b": "v1.0.0", "c": "v1.0.0", "d": "v1.0.0"}; !maps.Equal(got, want) {
+		t.Errorf("unpruned buildList() = %v, want %v", got, want)
+	}
+}
+
+func TestMVS_BuildList_ReplaceExclude(t *testing.T) {
+	fetch, list := testModules(map[string][]string{
+		"b@v1.0.0":      {"c v1.0.0"},
+		"fork/b@v1.1.0": {"c v1.1.0"},
+		"c@v1.0.0":      nil,
+		"c@v1.1.0":      {"old v1.0.0"},
+		"old@v1.0.0":    nil,
+		"d@v1.1.0":      nil,
+	}, nil)
+	root := testModule("a", "v1.0.0", "", "b v1.0.0", "local v1.0.0", "d v1.1.0")
+	root.Replace = []goproxy.Replacement{
+		{Old: goproxy.Module{Path: "b"}, New: goproxy.Module{Path: "fork/b", Version: "v1.1.0"}},
+		{Old: goproxy.Module{Path: "local", Version: "v1.0.0"}, New: goproxy.Module{Path: "../local"}},
+	}
+	root.Exclude = []goproxy.Module{{Path: "old", Version: "v1.0.0"}}
+
+	got := newMVS(fetch, list, root, testOptions()).buildList(context.Background(), root)
+	want := map[string]string{"b": "v1.0.0", "c": "v1.1.0", "local": "v1.0.0", "d": "v1.1.0"}
+	if !maps.Equal(got, want) {
+		t.Errorf("buildList() = %v, want %v", got, want)
+	}
+}
+
+func TestMVS_BuildList_ExcludeMovesUp(t *testing.T) {
+	fetch, list := testModules(map[string][]string{
+		"b@v1.0.0":        nil,
+		"b@v1.1.0":        nil,
+		"b@v1.2.0-rc.1":   nil,
+		"b@v1.2.0":        {"d v1.0.0"},
+		"c@v1.0.0":        nil,
+		"c@v1.1.0-beta.1": nil,
+		"d@v1.0.0":        nil,
+	}, nil)
+	root := testModule("a", "v1.0.0", "", "b v1.0.0", "c v1.0.0")
+	root.Exclude = []goproxy.Module{
+		{Path: "b", Version: "v1.0.0"},
+		{Path: "b", Version: "v1.1.0"},
+		{Path: "c", Version: "v1.0.0"},
+	}
+
+	// b moves past its excluded versions to the next release, and c, which
+	// has no higher release, to its prerelease
+	got := newMVS(fetch, list, root, testOptions()).buildList(context.Background(), root)
+	want := map[string]string{"b": "v1.2.0", "c": "v1.1.0-beta.1", "d": "v1.0.0"}
+	if !maps.Equal(got, want) {
+		t.Errorf("buildList() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildMVSGraph(t *testing.T) {
+	fetch, list := testModules(map[string][]string{
+		"b@v1.0.0": {"c v1.0.0", "d v1.0.0 // indirect"},
+		"c@v1.0.0": nil,
+		"c@v1.1.0": {"a v0.9.0"},
+		"d@v1.0.0": nil,
+	}, nil)
+	root := testModule("a", "v1.0.0", "1.21", "b v1.0.0", "c v1.1.0 // indirect")
+	root.Replace = []goproxy.Replacement{
+		{Old: goproxy.Module{Path: "d"}, New: goproxy.Module{Path: "../d"}},
+	}
+
+	g, refs := buildMVSGraph(context.Background(), newMVS(fetch, list, root, testOptions()), root, testOptions())
+	if got, want := g.NodeCount(), 3; got != want {
+		t.Errorf("NodeCount() = %d, want %d", got, want)
+	}
+	if len(refs) != g.NodeCount() {
+		t.Errorf("got %d refs for %d nodes", len(refs), g.NodeCount())
+	}
+	if n, ok := g.Node("c"); !ok || n.Meta["version"] != "v1.1.0" {
+		t.Errorf("c should be at its selected version v1.1.0, got %v", n)
+	}
+	if g.HasEdge("c", "a") {
+		t.Error("requirements on the main module should not become edges")
+	}
+	if _, ok := g.Node("d"); ok {
+		t.Error("indirect requirement d should only be followed with IncludeIndirect")
+	}
+
+	opts := testOptions()
+	opts.IncludeIndirect = true
+	g, _ = buildMVSGraph(context.Background(), newMVS(fetch, list, root, opts), root, opts)
+	d, ok := g.Node("d")
+	if !ok {
+		t.Fatal("d should be in the graph with IncludeIndirect")
+	}
+	if d.Meta["indirect"] != true {
+		t.Errorf("d should be marked indirect, got %v", d.Meta)
+	}
+	if d.Meta["replaced_by"] != "../d" {
+		t.Errorf("d replaced_by = %v, want ../d", d.Meta["replaced_by"])
+	}
+	c, _ := g.Node("c")
+	if _, ok := c.Meta["indirect"]; ok {
+		t.Error("c is also a direct requirement of b and should not be marked indirect")
+	}
+	if got := g.Children("a"); !slices.Contains(got, "c") {
+		t.Errorf("a should require c with IncludeIndirect, children = %v", got)
+	}
+}
+
+func TestBuildMVSGraph_MaxDepth(t *testing.T) {
+	fetch, list := testModules(map[string][]string{
+		"b@v1.0.0": {"c v1.0.0"},
+		"c@v1.0.0": nil,
+	}, nil)
+	root := testModule("a", "v1.0.0", "", "b v1.0.0")
+	opts := testOptions()
+	opts.MaxDepth = 1
+
+	g, _ := buildMVSGraph(context.Background(), newMVS(fetch, list, root, opts), root, opts)
+	if _, ok := g.Node("c"); ok {
+		t.Error("c is beyond MaxDepth and should be left out")
+	}
+	if _, ok := g.Node("b"); !ok {
+		t.Error("b should be in the graph")
+	}
+}
+
+func TestCompareGoVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "v1.2.3", 0},
+		{"v1.2.3", "v1.10.0", -1},
+		{"v2.0.0+incompatible", "v1.9.9", 1},
+		{"v1.0.0-rc.1", "v1.0.0", -1},
+		{"v1.0.0-rc.2", "v1.0.0-rc.10", -1},
+		{"v0.0.0-20210101000000-abcdef123456", "v0.0.0-20220101000000-abcdef123456", -1},
+		{"v0.1.1-0.20210101000000-abcdef123456", "v0.1.0", 1},
+	}
+
+	for _, tt := range tests {
+		if got := compareGoVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareGoVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := compareGoVersions(tt.b, tt.a); got != -tt.want {
+			t.Errorf("compareGoVersions(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package deps
 
 import (
+	"slices"
+
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 )
 
@@ -20,3 +22,37 @@ func ShallowGraphFromDeps(dependencies []Dependency) *dag.DAG {
 	}
 	return g
 }
+
+// MarkSource sets [MetaSource] to source on every node of g that has no
+// source yet, leaving virtual nodes such as the project root alone.
+func MarkSource(g *dag.DAG, source string) {
+	for _, n := range g.Nodes() {
+		if virtual, _ := n.Meta["virtual"].(bool); virtual {
+			continue
+		}
+		if _, ok := n.Meta[MetaSource]; !ok {
+			n.Meta[MetaSource] = source
+		}
+	}
+}
+
+// StalePackages returns the IDs of the nodes of g marked [MetaStale], in
+// sorted order.
+func StalePackages(g *dag.DAG) []string { return markedPackages(g, MetaStale) }
+
+// TruncatedPackages returns the IDs of the nodes of g marked
+// [MetaTruncated], in sorted order.
+func TruncatedPackages(g *dag.DAG) []string { return markedPackages(g, MetaTruncated) }
+
+// markedPackages returns the sorted IDs of the nodes of g whose key
+// metadata is true.
+func markedPackages(g *dag.DAG, key string) []string {
+	var ids []string
+	for _, n := range g.Nodes() {
+		if marked, _ := n.Meta[key].(bool); marked {
+			ids = append(ids, n.ID)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
@@ -1,7 +1,6 @@
 package java
 
 import (
-	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
@@ -9,6 +8,7 @@ import (
 	"github.com/contriboss/pubgrub-go"
 
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/integrations/maven"
 )
 
 // MavenMatcher implements constraint matching for Maven version ranges.
@@ -65,18 +65,25 @@ func parseMavenVersion(v string) mavenVersion {
 	return mv
 }
 
-// ParseVersion converts a Maven version string to a PubGrub Version.
-// We use SimpleVersion to preserve the full version string including qualifiers
-// like -jre, -android, -SNAPSHOT, etc. This ensures cache key consistency.
+// MavenVersion is a PubGrub Version that orders like Maven, by
+// [maven.CompareVersions], so that 10.0 is newer than 2.0 and 1.0-RC1
+// older than 1.0. It keeps the original string: versions like "33.4.8-jre"
+// must stay intact, or fetches and cache keys miss.
+type MavenVersion string
+
+func (v MavenVersion) String() string { return string(v) }
+
+func (v MavenVersion) Sort(other pubgrub.Version) int {
+	return maven.CompareVersions(string(v), other.String())
+}
+
+// ParseVersion converts a Maven version string to a [MavenVersion].
 func (MavenMatcher) ParseVersion(version string) pubgrub.Version {
 	mv := parseMavenVersion(version)
 	if !mv.valid {
 		return nil
 	}
-	// Use SimpleVersion to preserve the original version string.
-	// This is critical for Maven because versions like "33.4.8-jre" must stay
-	// intact - stripping the qualifier causes cache misses and fetch failures.
-	return pubgrub.SimpleVersion(version)
+	return MavenVersion(version)
 }
 
 // ParseConstraint converts a Maven version range to a PubGrub Condition.
@@ -89,60 +96,45 @@ func (m MavenMatcher) ParseConstraint(constraint string) pubgrub.Condition {
 
 	// Handle wildcard
 	if constraint == "*" {
-		vs, _ := pubgrub.ParseVersionRange("*")
-		return pubgrub.NewVersionSetCondition(vs)
-	}
-
-	// Handle multiple ranges separated by comma (outside brackets)
-	// e.g., "[1.0,2.0),[3.0,4.0)" - we need to find the union
-	ranges := splitMavenRanges(constraint)
-	if len(ranges) > 1 {
-		var rangeStrs []string
-		for _, r := range ranges {
-			if rs := mavenSingleRangeToRange(r); rs != "" {
-				rangeStrs = append(rangeStrs, rs)
-			}
-		}
-		if len(rangeStrs) == 0 {
-			return nil
-		}
-		// Join with || for union
-		rangeStr := strings.Join(rangeStrs, " || ")
-		versionSet, err := pubgrub.ParseVersionRange(rangeStr)
-		if err != nil {
+		return pubgrub.NewVersionSetCondition(pubgrub.FullVersionSet())
+	}
+
+	// Plain version - treat as exact requirement using EqualsCondition
+	// This preserves the full version string including qualifiers like -jre, -android, etc.
+	if !strings.HasPrefix(constraint, "[") && !strings.HasPrefix(constraint, "(") {
+		if !parseMavenVersion(constraint).valid {
 			return nil
 		}
-		return pubgrub.NewVersionSetCondition(versionSet)
+		return pubgrub.EqualsCondition{Version: MavenVersion(constraint)}
 	}
 
-	// Check for exact version match with brackets: [1.0]
+	// Exact match with brackets: [1.0]
 	if strings.HasPrefix(constraint, "[") && strings.HasSuffix(constraint, "]") && !strings.Contains(constraint, ",") {
-		inner := constraint[1 : len(constraint)-1]
-		mv := parseMavenVersion(inner)
-		if mv.valid {
-			// Use EqualsCondition directly with SimpleVersion to preserve the original version string
-			return pubgrub.EqualsCondition{Version: pubgrub.SimpleVersion(inner)}
+		inner := strings.TrimSpace(constraint[1 : len(constraint)-1])
+		if !parseMavenVersion(inner).valid {
+			return nil
 		}
+		return pubgrub.EqualsCondition{Version: MavenVersion(inner)}
 	}
 
-	// Plain version - treat as exact requirement using EqualsCondition
-	// This preserves the full version string including qualifiers like -jre, -android, etc.
-	mv := parseMavenVersion(constraint)
-	if mv.valid {
-		return pubgrub.EqualsCondition{Version: pubgrub.SimpleVersion(constraint)}
-	}
-
-	// Try as a range expression
-	rangeStr := mavenSingleRangeToRange(constraint)
-	if rangeStr == "" {
-		return nil
+	// One or more ranges; several ranges are a union, like
+	// "[1.0,2.0),[3.0,4.0)"
+	var set pubgrub.VersionSet
+	for _, r := range splitMavenRanges(constraint) {
+		rs := mavenRangeToSet(r)
+		if rs == nil {
+			return nil
+		}
+		if set == nil {
+			set = rs
+		} else {
+			set = set.Union(rs)
+		}
 	}
-
-	versionSet, err := pubgrub.ParseVersionRange(rangeStr)
-	if err != nil {
+	if set == nil {
 		return nil
 	}
-	return pubgrub.NewVersionSetCondition(versionSet)
+	return pubgrub.NewVersionSetCondition(set)
 }
 
 // splitMavenRanges splits multiple Maven ranges.
@@ -186,55 +178,30 @@ func splitMavenRanges(s string) []string {
 	return result
 }
 
-// mavenSingleRangeToRange converts a single Maven range/version to PubGrub syntax.
-func mavenSingleRangeToRange(constraint string) string {
-	constraint = strings.TrimSpace(constraint)
-	if constraint == "" {
-		return ""
-	}
-
-	// Check if it's a range expression
-	m := mavenRangeRE.FindStringSubmatch(constraint)
-	if m != nil {
-		leftBracket := m[1]  // [ or (
-		leftVer := m[2]      // lower bound
-		rightVer := m[3]     // upper bound
-		rightBracket := m[4] // ] or )
-
-		var conditions []string
-
-		// Handle lower bound
-		if leftVer != "" {
-			lv := parseMavenVersion(leftVer)
-			if lv.valid {
-				if leftBracket == "[" {
-					conditions = append(conditions, fmt.Sprintf(">=%d.%d.%d", lv.major, lv.minor, lv.patch))
-				} else {
-					conditions = append(conditions, fmt.Sprintf(">%d.%d.%d", lv.major, lv.minor, lv.patch))
-				}
-			}
-		}
-
-		// Handle upper bound
-		if rightVer != "" {
-			rv := parseMavenVersion(rightVer)
-			if rv.valid {
-				if rightBracket == "]" {
-					conditions = append(conditions, fmt.Sprintf("<=%d.%d.%d", rv.major, rv.minor, rv.patch))
-				} else {
-					conditions = append(conditions, fmt.Sprintf("<%d.%d.%d", rv.major, rv.minor, rv.patch))
-				}
-			}
-		}
-
-		if len(conditions) == 0 {
-			return "*" // Unbounded range
-		}
-		return strings.Join(conditions, ", ")
+// mavenRangeToSet converts a single Maven range, like "[1.0,2.0)" or
+// "[1.5]", to a version set ordered by Maven's rules. It returns nil for
+// malformed ranges.
+func mavenRangeToSet(r string) pubgrub.VersionSet {
+	r = strings.TrimSpace(r)
+	if strings.HasPrefix(r, "[") && strings.HasSuffix(r, "]") && !strings.Contains(r, ",") {
+		v := MavenVersion(strings.TrimSpace(r[1 : len(r)-1]))
+		return pubgrub.NewVersionRangeSet(v, true, v, true)
 	}
 
-	// Note: Exact matches ([1.0] and plain versions) are handled directly
-	// in ParseConstraint using EqualsCondition to preserve version strings.
-	// This function should only be called for actual range expressions.
-	return ""
+	m := mavenRangeRE.FindStringSubmatch(r)
+	if m == nil {
+		return nil
+	}
+	lower, upper := strings.TrimSpace(m[2]), strings.TrimSpace(m[3])
+	lowerIncl, upperIncl := m[1] == "[", m[4] == "]"
+	switch {
+	case lower == "" && upper == "":
+		return pubgrub.FullVersionSet()
+	case lower == "":
+		return pubgrub.NewUpperBoundVersionSet(MavenVersion(upper), upperIncl)
+	case upper == "":
+		return pubgrub.NewLowerBoundVersionSet(MavenVersion(lower), lowerIncl)
+	default:
+		return pubgrub.NewVersionRangeSet(MavenVersion(lower), lowerIncl, MavenVersion(upper), upperIncl)
+	}
 }
@@ -90,6 +90,13 @@ func TestMavenMatcher_ParseConstraint(t *testing.T) {
 		{"(,2.0]", "2.0.0", true},
 		{"(,2.0]", "2.0.1", false},
 
+		// Ranges compare like Maven: numerically, with qualifiers
+		{"[1.0,2.0)", "10.0", false},
+		{"[2.0,)", "10.0", true},
+		{"[1.0,2.0)", "2.0-RC1", true},
+		{"[1.0,2.0),[3.0,4.0)", "3.5", true},
+		{"[1.0,2.0),[3.0,4.0)", "2.5", false},
+
 		// Wildcard
 		{"*", "99.99.99", true},
 	}
@@ -186,28 +193,55 @@ func TestSplitMavenRanges(t *testing.T) {
 	}
 }
 
-func TestMavenSingleRangeToRange(t *testing.T) {
-	// Note: mavenSingleRangeToRange is now only used for actual range expressions.
-	// Plain versions and exact matches [1.0.0] are handled directly in ParseConstraint
-	// using EqualsCondition to preserve version strings with qualifiers.
+func TestMavenRangeToSet(t *testing.T) {
 	tests := []struct {
-		input string
-		want  string
+		input   string
+		version string
+		want    bool
 	}{
-		{"[1.0,2.0)", ">=1.0.0, <2.0.0"},
-		{"(1.0,2.0]", ">1.0.0, <=2.0.0"},
-		{"[1.0,)", ">=1.0.0"},
-		{"(,2.0]", "<=2.0.0"},
-		// Plain versions and exact matches return "" since they're handled elsewhere
-		{"1.0.0", ""},
-		{"[1.0.0]", ""},
+		{"[1.0,2.0)", "1.9.9", true},
+		{"[1.0,2.0)", "10.0", false}, // numeric, not lexical
+		{"(1.0,2.0]", "1.0.0", false},
+		{"(1.0,2.0]", "2.0", true},
+		{"[1.0,)", "10.0", true},
+		{"(,2.0]", "2.0-RC1", true}, // release candidates precede the release
+		{"(,)", "1.0", true},
+		{"[1.5]", "1.5.0", true},
+		{"[1.5]", "1.5.1", false},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			got := mavenSingleRangeToRange(tt.input)
-			if got != tt.want {
-				t.Errorf("mavenSingleRangeToRange(%q) = %q, want %q", tt.input, got, tt.want)
+		t.Run(tt.input+"@"+tt.version, func(t *testing.T) {
+			set := mavenRangeToSet(tt.input)
+			if set == nil {
+				t.Fatalf("mavenRangeToSet(%q) = nil", tt.input)
+			}
+			if got := set.Contains(MavenVersion(tt.version)); got != tt.want {
+				t.Errorf("mavenRangeToSet(%q).Contains(%q) = %v, want %v", tt.input, tt.version, got, tt.want)
+			}
+		})
+	}
+
+	if set := mavenRangeToSet("1.0"); set != nil {
+		t.Errorf("mavenRangeToSet(%q) = %v, want nil", "1.0", set)
+	}
+}
+
+func TestMavenVersion_Sort(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"10.0", "2.0", 1},
+		{"1.0", "1.0.0", 0},
+		{"1.0-RC1", "1.0", -1},
+		{"33.4.8-jre", "33.4.8-android", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			if got := MavenVersion(tt.a).Sort(MavenVersion(tt.b)); got != tt.want {
+				t.Errorf("MavenVersion(%q).Sort(%q) = %d, want %d", tt.a, tt.b, got, tt.want)
 			}
 		})
 	}
@@ -23,8 +23,17 @@
 //	parser, _ := java.Language.Manifest("pom", nil)
 //	result, _ := parser.Parse("pom.xml", deps.Options{})
 //
-// The parser extracts dependencies from <dependencies> elements,
-// excluding test and provided scopes.
+// The parser reads the effective POM, excluding test and provided scopes:
+// dependencies and properties are inherited from parent POMs (read from
+// relativePath when on disk, otherwise from Maven Central when a resolver
+// is given), ${...} properties are interpolated, and versions missing from
+// a dependency come from dependencyManagement and imported BOMs.
+//
+// # Versions
+//
+// Versions are [MavenVersion]s, ordered by Maven's rules rather than
+// lexically, so ranges like [1.0,2.0) select the newest matching version
+// and exclude 10.0. Plain versions are exact requirements.
 //
 // [maven]: github.com/stacktower-io/stacktower/pkg/integrations/maven
 // [deps.Language]: github.com/stacktower-io/stacktower/pkg/core/deps.Language
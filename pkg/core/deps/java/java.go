@@ -30,62 +30,44 @@ var Language = &deps.Language{
 
 func newResolver(backend cache.Cache, opts deps.Options) (deps.Resolver, error) {
 	c := maven.NewClient(backend, opts.CacheTTL)
-	f := fetcher{client: c, javaVersion: opts.RuntimeVersion}
+	f := fetcher{deps.RegistryFetcher{
+		Client:         maven.NewRegistry(c),
+		RuntimeVersion: opts.RuntimeVersion,
+		ManifestFile:   "pom.xml",
+	}}
 
 	// Use PubGrub for proper SAT-solver-based dependency resolution
-	return deps.NewPubGrubResolver("maven", f, MavenMatcher{})
+	r, err := deps.NewPubGrubResolver("maven", f, MavenMatcher{})
+	if err != nil {
+		return nil, err
+	}
+	return &mavenResolver{PubGrubResolver: r, client: c}, nil
+}
+
+// mavenResolver is a PubGrubResolver that shares its Maven client with the
+// pom.xml parser, which fetches parent POMs and BOMs through it.
+type mavenResolver struct {
+	*deps.PubGrubResolver
+	client *maven.Client
 }
 
+// fetcher is a [deps.RegistryFetcher] that also accepts filename-safe
+// coordinates (see [NormalizeCoordinate]).
 type fetcher struct {
-	client      *maven.Client
-	javaVersion string
+	deps.RegistryFetcher
 }
 
 func (f fetcher) Fetch(ctx context.Context, name string, refresh bool) (*deps.Package, error) {
-	coord := NormalizeCoordinate(name)
-	a, err := f.client.FetchArtifact(ctx, coord, refresh)
-	if err != nil {
-		return nil, err
-	}
-	return mavenArtifactToDepsPkg(a), nil
+	return f.RegistryFetcher.Fetch(ctx, NormalizeCoordinate(name), refresh)
 }
 
 func (f fetcher) FetchVersion(ctx context.Context, name, version string, refresh bool) (*deps.Package, error) {
-	coord := NormalizeCoordinate(name)
-	a, err := f.client.FetchArtifactVersion(ctx, coord, version, refresh)
-	if err != nil {
-		return nil, err
-	}
-	return mavenArtifactToDepsPkg(a), nil
+	return f.RegistryFetcher.FetchVersion(ctx, NormalizeCoordinate(name), version, refresh)
 }
 
 // ListVersions implements deps.VersionLister for constraint-based resolution.
 func (f fetcher) ListVersions(ctx context.Context, name string, refresh bool) ([]string, error) {
-	coord := NormalizeCoordinate(name)
-	return f.client.ListVersions(ctx, coord, refresh)
-}
-
-func mavenArtifactToDepsPkg(a *maven.ArtifactInfo) *deps.Package {
-	pkg := &deps.Package{
-		Name:         a.Coordinate(),
-		Version:      a.Version,
-		Description:  a.Description,
-		License:      a.License,
-		Repository:   a.Repository,
-		HomePage:     a.HomePage,
-		ManifestFile: "pom.xml",
-	}
-	// Convert maven.Dependency to deps.Dependency with constraints
-	if len(a.Dependencies) > 0 {
-		pkg.Dependencies = make([]deps.Dependency, len(a.Dependencies))
-		for i, d := range a.Dependencies {
-			pkg.Dependencies[i] = deps.Dependency{
-				Name:       d.Name,
-				Constraint: d.Constraint,
-			}
-		}
-	}
-	return pkg
+	return f.RegistryFetcher.ListVersions(ctx, NormalizeCoordinate(name), refresh)
 }
 
 // NormalizeCoordinate converts filename-safe coordinates to Maven format.
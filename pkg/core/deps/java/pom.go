@@ -1,22 +1,24 @@
 package java
 
 import (
-	"encoding/xml"
-	"os"
+	"cmp"
 	"regexp"
 	"strings"
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
 	"github.com/stacktower-io/stacktower/pkg/core/deps/constraints"
+	"github.com/stacktower-io/stacktower/pkg/integrations/maven"
 	"github.com/stacktower-io/stacktower/pkg/observability"
 )
 
 // javaVersionRE extracts numeric Java version (e.g., "17", "11", "1.8")
 var javaVersionRE = regexp.MustCompile(`(\d+(?:\.\d+)?)`)
 
-// POMParser parses Maven pom.xml files. It extracts dependencies and
-// optionally resolves them via Maven Central.
+// POMParser parses Maven pom.xml files. It reads the effective POM, with
+// parents, properties and imported BOMs applied, and optionally resolves
+// the dependencies via Maven Central. Without a resolver only parents on
+// disk are read.
 type POMParser struct {
 	resolver deps.Resolver
 }
@@ -28,17 +30,16 @@ func (p *POMParser) Supports(name string) bool { return name == "pom.xml" }
 func (p *POMParser) Parse(path string, opts deps.Options) (*deps.ManifestResult, error) {
 	opts = opts.WithDefaults()
 
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
+	var client *maven.Client
+	if r, ok := p.resolver.(*mavenResolver); ok {
+		client = r.client
 	}
-
-	var pom pomProject
-	if err := xml.Unmarshal(data, &pom); err != nil {
+	pom, err := maven.ReadPOM(opts.Ctx, client, path)
+	if err != nil {
 		return nil, err
 	}
 
-	directDeps := extractDependenciesWithVersions(&pom)
+	directDeps := directDependencies(pom.Dependencies)
 
 	// Emit observability hooks for extracted dependencies
 	hooks := observability.ResolverFromContext(opts.Ctx)
@@ -58,7 +59,7 @@ func (p *POMParser) Parse(path string, opts deps.Options) (*deps.ManifestResult,
 	}
 
 	// Extract Java version from properties
-	javaVersion := extractJavaVersion(&pom.Properties)
+	javaVersion := extractJavaVersion(pom.Properties)
 
 	return &deps.ManifestResult{
 		Graph:              g,
@@ -71,18 +72,15 @@ func (p *POMParser) Parse(path string, opts deps.Options) (*deps.ManifestResult,
 }
 
 // extractJavaVersion extracts the Java version from pom.xml properties.
-// Priority: maven.compiler.source > maven.compiler.target > java.version
-func extractJavaVersion(props *pomProperties) string {
-	if props.MavenCompilerSource != "" {
-		return props.MavenCompilerSource
-	}
-	if props.MavenCompilerTarget != "" {
-		return props.MavenCompilerTarget
-	}
-	if props.JavaVersion != "" {
-		return props.JavaVersion
-	}
-	return ""
+// Priority: maven.compiler.release > maven.compiler.source >
+// maven.compiler.target > java.version
+func extractJavaVersion(props map[string]string) string {
+	return cmp.Or(
+		props["maven.compiler.release"],
+		props["maven.compiler.source"],
+		props["maven.compiler.target"],
+		props["java.version"],
+	)
 }
 
 // normalizeJavaVersion normalizes Java version strings.
@@ -107,87 +105,16 @@ func normalizeJavaVersion(version string) string {
 	return version
 }
 
-// extractDependenciesWithVersions extracts dependencies with version information
-func extractDependenciesWithVersions(pom *pomProject) []deps.Dependency {
-	var result []deps.Dependency
-	seen := make(map[string]bool)
-
-	for _, dep := range pom.Dependencies {
-		// Skip test and provided scope dependencies
-		if dep.Scope == "test" || dep.Scope == "provided" || dep.Optional == "true" {
-			continue
-		}
-		// Skip dependencies with unresolved Maven properties
-		if strings.HasPrefix(dep.GroupID, "${") || strings.HasPrefix(dep.ArtifactID, "${") {
-			continue
-		}
-		coord := dep.GroupID + ":" + dep.ArtifactID
-		if !seen[coord] {
-			seen[coord] = true
-			d := deps.Dependency{Name: coord}
-			// In Maven, versions are typically pinned (exact)
-			// unless they use version ranges like [1.0,2.0)
-			if dep.Version != "" && !strings.HasPrefix(dep.Version, "${") {
-				d.Pinned = dep.Version
-				d.Constraint = dep.Version
-			}
-			result = append(result, d)
+// directDependencies converts the dependencies of an effective POM. In
+// Maven, versions are typically pinned (exact) unless they use version
+// ranges like [1.0,2.0).
+func directDependencies(mdeps []maven.Dependency) []deps.Dependency {
+	result := make([]deps.Dependency, len(mdeps))
+	for i, d := range mdeps {
+		result[i] = deps.Dependency{Name: d.Name, Constraint: d.Constraint}
+		if !strings.HasPrefix(d.Constraint, "[") && !strings.HasPrefix(d.Constraint, "(") {
+			result[i].Pinned = d.Constraint
 		}
 	}
 	return result
 }
-
-// extractDependencies is kept for backward compatibility
-func extractDependencies(pom *pomProject) []string {
-	var names []string
-	seen := make(map[string]bool)
-
-	for _, dep := range pom.Dependencies {
-		// Skip test and provided scope dependencies
-		if dep.Scope == "test" || dep.Scope == "provided" || dep.Optional == "true" {
-			continue
-		}
-		// Skip dependencies with unresolved Maven properties
-		if strings.HasPrefix(dep.GroupID, "${") || strings.HasPrefix(dep.ArtifactID, "${") {
-			continue
-		}
-		coord := dep.GroupID + ":" + dep.ArtifactID
-		if !seen[coord] {
-			seen[coord] = true
-			names = append(names, coord)
-		}
-	}
-	return names
-}
-
-type pomProject struct {
-	GroupID      string          `xml:"groupId"`
-	ArtifactID   string          `xml:"artifactId"`
-	Version      string          `xml:"version"`
-	Name         string          `xml:"name"`
-	Description  string          `xml:"description"`
-	URL          string          `xml:"url"`
-	Dependencies []pomDependency `xml:"dependencies>dependency"`
-	Parent       *pomParent      `xml:"parent"`
-	Properties   pomProperties   `xml:"properties"`
-}
-
-type pomProperties struct {
-	MavenCompilerSource string `xml:"maven.compiler.source"`
-	MavenCompilerTarget string `xml:"maven.compiler.target"`
-	JavaVersion         string `xml:"java.version"`
-}
-
-type pomParent struct {
-	GroupID    string `xml:"groupId"`
-	ArtifactID string `xml:"artifactId"`
-	Version    string `xml:"version"`
-}
-
-type pomDependency struct {
-	GroupID    string `xml:"groupId"`
-	ArtifactID string `xml:"artifactId"`
-	Version    string `xml:"version"`
-	Scope      string `xml:"scope"`
-	Optional   string `xml:"optional"`
-}
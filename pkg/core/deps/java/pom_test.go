@@ -1,11 +1,14 @@
 package java
 
 import (
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/integrations/maven"
 )
 
 func TestPOMParser_Supports(t *testing.T) {
@@ -121,19 +124,104 @@ func TestPOMParser_IncludesTransitive(t *testing.T) {
 	}
 }
 
-func TestExtractDependencies(t *testing.T) {
-	pom := &pomProject{
-		Dependencies: []pomDependency{
-			{GroupID: "org.apache", ArtifactID: "commons-lang"},
-			{GroupID: "org.apache", ArtifactID: "commons-lang"}, // duplicate
-			{GroupID: "junit", ArtifactID: "junit", Scope: "test"},
-			{GroupID: "${project.groupId}", ArtifactID: "internal"},
-		},
+func TestDirectDependencies(t *testing.T) {
+	got := directDependencies([]maven.Dependency{
+		{Name: "org.apache:commons-lang", Constraint: "3.12.0"},
+		{Name: "org.slf4j:slf4j-api", Constraint: "[1.7,2.0)"},
+		{Name: "org.example:unversioned"},
+	})
+	want := []deps.Dependency{
+		{Name: "org.apache:commons-lang", Constraint: "3.12.0", Pinned: "3.12.0"},
+		{Name: "org.slf4j:slf4j-api", Constraint: "[1.7,2.0)"}, // ranges are not pins
+		{Name: "org.example:unversioned"},
 	}
+	if !slices.Equal(got, want) {
+		t.Errorf("directDependencies() = %+v, want %+v", got, want)
+	}
+}
 
-	deps := extractDependencies(pom)
-	if len(deps) != 1 {
-		t.Errorf("expected 1 dep, got %d: %v", len(deps), deps)
+func TestPOMParser_Parse_LocalParent(t *testing.T) {
+	dir := t.TempDir()
+	parent := `<?xml version="1.0" encoding="UTF-8"?>
+<project>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>2.0.0</version>
+  <packaging>pom</packaging>
+  <properties>
+    <guava.version>32.1.3-jre</guava.version>
+    <maven.compiler.release>21</maven.compiler.release>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.google.guava</groupId>
+        <artifactId>guava</artifactId>
+        <version>${guava.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>2.0.9</version>
+    </dependency>
+  </dependencies>
+</project>`
+	child := `<?xml version="1.0" encoding="UTF-8"?>
+<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>2.0.0</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>${project.groupId}</groupId>
+      <artifactId>core</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+  </dependencies>
+</project>`
+	if err := os.WriteFile(filepath.Join(dir, "pom.xml"), []byte(parent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	pomFile := filepath.Join(dir, "app", "pom.xml")
+	if err := os.WriteFile(pomFile, []byte(child), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := (&POMParser{}).Parse(pomFile, deps.Options{})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if result.RootPackage != "com.example:app" {
+		t.Errorf("RootPackage = %q, want %q", result.RootPackage, "com.example:app")
+	}
+	if result.RuntimeVersion != "21" {
+		t.Errorf("RuntimeVersion = %q, want %q", result.RuntimeVersion, "21")
+	}
+	constraints := map[string]string{}
+	for _, e := range result.Graph.Edges() {
+		constraints[e.To], _ = e.Meta["constraint"].(string)
+	}
+	want := map[string]string{
+		"com.google.guava:guava": "32.1.3-jre", // managed by the parent
+		"com.example:core":       "2.0.0",      // project.* properties
+		"org.slf4j:slf4j-api":    "2.0.9",      // inherited
+	}
+	if !maps.Equal(constraints, want) {
+		t.Errorf("constraints = %v, want %v", constraints, want)
 	}
 }
 
@@ -17,6 +17,13 @@
 // The resolver fetches the "dependencies" field from each package, excluding
 // devDependencies, peerDependencies, and optionalDependencies.
 //
+// Dist-tag constraints, like "next" or "beta", resolve to the versions the
+// tags point to, and so does a dist-tag given as the root version. Alias
+// dependencies ("npm:pkg@^1.0") resolve to the package they install.
+// Bundled dependencies ship inside their parent's tarball: they are not
+// resolved but listed in the parent node's "bundled" metadata, for both
+// registry resolution and package-lock.json.
+//
 // # Manifest Parsing
 //
 // Parse package.json files:
@@ -27,6 +34,15 @@
 // Note: package.json contains direct dependencies only. The resolver fetches
 // transitive dependencies from npm.
 //
+// # Lock Files
+//
+// package-lock.json, yarn.lock (Yarn 1 and Berry) and pnpm-lock.yaml
+// (lockfile versions 5 to 9) hold the full transitive closure, so parsing
+// them needs no registry calls. yarn.lock and pnpm-lock.yaml do not name the
+// project's own dependencies the same way: [YarnLock] reads them from the
+// package.json next to the lock file, [PnpmLock] from the root importer.
+// Workspace packages become nodes carrying their directory under "path".
+//
 // [npm]: github.com/stacktower-io/stacktower/pkg/integrations/npm
 // [deps.Language]: github.com/stacktower-io/stacktower/pkg/core/deps.Language
 package javascript
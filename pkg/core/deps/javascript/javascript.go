@@ -2,25 +2,31 @@ package javascript
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
-	"github.com/stacktower-io/stacktower/pkg/core/deps/constraints"
 	"github.com/stacktower-io/stacktower/pkg/integrations/npm"
 )
 
 // Language provides JavaScript/TypeScript dependency resolution via npm.
-// Supports package.json and package-lock.json manifest files.
+// Supports package.json, package-lock.json, yarn.lock and pnpm-lock.yaml
+// manifest files.
 var Language = &deps.Language{
 	Name:                  "javascript",
 	DefaultRegistry:       "npm",
 	DefaultRuntimeVersion: "20", // Node.js LTS
-	ManifestTypes:         []string{"package", "package-lock"},
+	ManifestTypes:         []string{"package", "package-lock", "yarn-lock", "pnpm-lock"},
 	ManifestAliases: map[string]string{
 		"package.json":      "package",
 		"package-lock.json": "package-lock",
+		"yarn.lock":         "yarn-lock",
+		"pnpm-lock.yaml":    "pnpm-lock",
 	},
+	SniffManifest:   sniffManifest,
 	NewResolver:     newResolver,
 	NewManifest:     newManifest,
 	ManifestParsers: manifestParsers,
@@ -35,6 +41,10 @@ func newManifest(name string, res deps.Resolver) deps.ManifestParser {
 		return &PackageJSON{resolver: res}
 	case "package-lock":
 		return &PackageLock{} // Lock file doesn't need resolver
+	case "yarn-lock":
+		return &YarnLock{}
+	case "pnpm-lock":
+		return &PnpmLock{}
 	default:
 		return nil
 	}
@@ -44,99 +54,96 @@ func manifestParsers(res deps.Resolver) []deps.ManifestParser {
 	// Lock file first (more complete), then manifest
 	return []deps.ManifestParser{
 		&PackageLock{},
+		&YarnLock{},
+		&PnpmLock{},
 		&PackageJSON{resolver: res},
 	}
 }
 
 func newResolver(backend cache.Cache, opts deps.Options) (deps.Resolver, error) {
 	c := npm.NewClient(backend, opts.CacheTTL)
-	f := fetcher{client: c, nodeVersion: opts.RuntimeVersion}
+	f := fetcher{
+		RegistryFetcher: deps.RegistryFetcher{
+			Client:         npm.NewRegistry(c),
+			RuntimeVersion: opts.RuntimeVersion,
+			ManifestFile:   "package.json",
+		},
+		client: c,
+	}
 
 	// Use PubGrub for proper SAT-solver-based dependency resolution
-	return deps.NewPubGrubResolver("npm", f, SemverMatcher{})
-}
-
-type fetcher struct {
-	client      *npm.Client
-	nodeVersion string
-}
-
-func (f fetcher) Fetch(ctx context.Context, name string, refresh bool) (*deps.Package, error) {
-	p, err := f.client.FetchPackage(ctx, name, refresh)
+	r, err := deps.NewPubGrubResolver("npm", f, SemverMatcher{})
 	if err != nil {
 		return nil, err
 	}
-	if err := f.checkCompatibility(p, name); err != nil {
-		return nil, err
-	}
-	return npmPkgToDepsPkg(p), nil
+	return &npmResolver{PubGrubResolver: r, client: c}, nil
 }
 
-func (f fetcher) FetchVersion(ctx context.Context, name, version string, refresh bool) (*deps.Package, error) {
-	p, err := f.client.FetchPackageVersion(ctx, name, version, refresh)
-	if err != nil {
-		return nil, err
-	}
-	if err := f.checkCompatibility(p, name); err != nil {
-		return nil, err
-	}
-	return npmPkgToDepsPkg(p), nil
+// npmResolver wraps PubGrubResolver to accept dist-tags, like "next", as
+// the root version, and shares its npm client with the package.json parser.
+type npmResolver struct {
+	*deps.PubGrubResolver
+	client *npm.Client
 }
 
-func (f fetcher) checkCompatibility(p *npm.PackageInfo, name string) error {
-	if f.nodeVersion == "" || p.RequiredNode == "" {
-		return nil
-	}
-	if !constraints.CheckVersionConstraint(f.nodeVersion, p.RequiredNode) {
-		return &deps.IncompatibleRuntimeError{
-			Package:           name,
-			Version:           p.Version,
-			RuntimeConstraint: p.RequiredNode,
-			TargetRuntime:     f.nodeVersion,
+func (r *npmResolver) Resolve(ctx context.Context, pkg string, opts deps.Options) (*dag.DAG, error) {
+	pkg, opts = deps.WithPackageSpec(pkg, opts)
+	if opts.Version != "" && !parseSemver(opts.Version).valid {
+		tags, err := r.client.FetchDistTags(ctx, pkg, opts.Refresh)
+		if err != nil {
+			return nil, err
 		}
+		v, ok := tags[opts.Version]
+		if !ok {
+			return nil, fmt.Errorf("npm package %s has no version or dist-tag %q", pkg, opts.Version)
+		}
+		opts.Version = v
 	}
-	return nil
+	return r.PubGrubResolver.Resolve(ctx, pkg, opts)
+}
+
+// fetcher is a [deps.RegistryFetcher] that also resolves dist-tag
+// constraints in the dependencies of fetched packages.
+type fetcher struct {
+	deps.RegistryFetcher
+	client *npm.Client
 }
 
-// ListVersions implements deps.VersionLister for constraint-based resolution.
-func (f fetcher) ListVersions(ctx context.Context, name string, refresh bool) ([]string, error) {
-	return f.client.ListVersions(ctx, name, refresh)
+func (f fetcher) Fetch(ctx context.Context, name string, refresh bool) (*deps.Package, error) {
+	return f.FetchVersion(ctx, name, "", refresh)
 }
 
-// ListVersionsWithConstraints implements deps.RuntimeConstraintLister.
-func (f fetcher) ListVersionsWithConstraints(ctx context.Context, name string, refresh bool) (map[string]string, error) {
-	raw, err := f.client.ListVersionsWithConstraints(ctx, name, refresh)
+func (f fetcher) FetchVersion(ctx context.Context, name, version string, refresh bool) (*deps.Package, error) {
+	pkg, err := f.RegistryFetcher.FetchVersion(ctx, name, version, refresh)
 	if err != nil {
 		return nil, err
 	}
-	for version, constraint := range raw {
-		raw[version] = constraints.NormalizeRuntimeConstraint(constraint)
-	}
-	return raw, nil
+	resolveDistTags(ctx, f.client, pkg.Dependencies, refresh)
+	return pkg, nil
 }
 
-func npmPkgToDepsPkg(p *npm.PackageInfo) *deps.Package {
-	pkg := &deps.Package{
-		Name:              p.Name,
-		Version:           p.Version,
-		Description:       p.Description,
-		License:           p.License,
-		LicenseText:       p.LicenseText,
-		Author:            p.Author,
-		Repository:        p.Repository,
-		HomePage:          p.HomePage,
-		ManifestFile:      "package.json",
-		RuntimeConstraint: p.RequiredNode,
-	}
-	// Convert npm.Dependency to deps.Dependency with constraints
-	if len(p.Dependencies) > 0 {
-		pkg.Dependencies = make([]deps.Dependency, len(p.Dependencies))
-		for i, d := range p.Dependencies {
-			pkg.Dependencies[i] = deps.Dependency{
-				Name:       d.Name,
-				Constraint: d.Constraint,
-			}
+// distTagRE matches the names npm allows for dist-tags. npm rejects tags
+// that are valid semver ranges, so constraints that parse are never tags.
+var distTagRE = regexp.MustCompile(`^[A-Za-z][\w.-]*$`)
+
+func isDistTag(constraint string) bool {
+	return distTagRE.MatchString(constraint) && SemverMatcher{}.ParseConstraint(constraint) == nil
+}
+
+// resolveDistTags replaces dist-tag constraints, like "next" or "beta",
+// with the versions the tags point to. Tags that cannot be looked up stay
+// as they are and constrain nothing.
+func resolveDistTags(ctx context.Context, client *npm.Client, dependencies []deps.Dependency, refresh bool) {
+	for i, d := range dependencies {
+		if !isDistTag(d.Constraint) {
+			continue
+		}
+		tags, err := client.FetchDistTags(ctx, d.Name, refresh)
+		if err != nil {
+			continue
+		}
+		if v, ok := tags[d.Constraint]; ok {
+			dependencies[i].Constraint = v
 		}
 	}
-	return pkg
 }
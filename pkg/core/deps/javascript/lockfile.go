@@ -0,0 +1,149 @@
+package javascript
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/observability"
+)
+
+// lockEntry is one locked package of a yarn.lock or pnpm-lock.yaml file,
+// in the form [buildLockGraph] reads. Entries are keyed by a string unique
+// to the package version, such as "lodash@4.17.21".
+type lockEntry struct {
+	name    string
+	version string
+	path    string            // directory of workspace packages, relative to the lock file
+	deps    map[string]string // dependency name → key of the entry it resolves to
+	ranges  map[string]string // dependency name → requested range, when known
+}
+
+// lockRoot is a direct dependency of the project, resolved to an entry.
+type lockRoot struct {
+	name string
+	key  string
+	dev  bool // listed in devDependencies
+}
+
+// buildLockGraph builds the graph of the entries reachable from roots,
+// under a virtual project root. Packages are nodes under their names: when
+// several versions of a package are locked, the node holds the one
+// reached first, nearest the root. Packages only development roots reach
+// are marked "dev", and left out under [deps.DependencyScopeProdOnly].
+func buildLockGraph(entries map[string]*lockEntry, roots []lockRoot, opts deps.Options) *dag.DAG {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: deps.ProjectRootNodeID, Meta: dag.Metadata{"virtual": true}})
+	hooks := observability.ResolverFromContext(opts.Ctx)
+
+	roots = slices.Clone(roots)
+	slices.SortFunc(roots, func(a, b lockRoot) int {
+		if a.dev != b.dev {
+			if a.dev {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Compare(a.name, b.name)
+	})
+
+	addNode := func(e *lockEntry, dev bool) {
+		if _, ok := g.Node(e.name); ok {
+			return
+		}
+		hooks.OnFetchStart(opts.Ctx, e.name, 0)
+		meta := dag.Metadata{"version": e.version}
+		if dev {
+			meta["dev"] = true
+		}
+		if e.path != "" {
+			meta["path"] = e.path
+		}
+		_ = g.AddNode(dag.Node{ID: e.name, Meta: meta})
+		hooks.OnFetchComplete(opts.Ctx, e.name, 0, len(e.deps), nil)
+	}
+
+	visited := make(map[string]bool)
+	var queue []string
+	for _, r := range roots {
+		if r.dev && opts.DependencyScope == deps.DependencyScopeProdOnly {
+			continue
+		}
+		e, ok := entries[r.key]
+		if !ok {
+			continue
+		}
+		addNode(e, r.dev)
+		edgeMeta := dag.Metadata{}
+		if e.version != "" {
+			edgeMeta["constraint"] = "==" + e.version
+		}
+		_ = g.AddEdge(dag.Edge{From: deps.ProjectRootNodeID, To: e.name, Meta: edgeMeta})
+		if !visited[r.key] {
+			visited[r.key] = true
+			queue = append(queue, r.key)
+		}
+
+		// Walk each root's closure before the next one, so production
+		// roots claim their packages before development roots do
+		for len(queue) > 0 {
+			e := entries[queue[0]]
+			queue = queue[1:]
+			for _, name := range slices.Sorted(maps.Keys(e.deps)) {
+				key := e.deps[name]
+				dep, ok := entries[key]
+				if !ok || dep.name == e.name {
+					continue
+				}
+				addNode(dep, r.dev)
+				edgeMeta := dag.Metadata{}
+				if c := e.ranges[name]; c != "" {
+					edgeMeta["constraint"] = c
+				}
+				_ = g.AddEdge(dag.Edge{From: e.name, To: dep.name, Meta: edgeMeta})
+				if !visited[key] {
+					visited[key] = true
+					queue = append(queue, key)
+				}
+			}
+		}
+	}
+	return g
+}
+
+// readPackageJSON reads the package.json in dir, reporting whether there is
+// a valid one.
+func readPackageJSON(dir string) (packageFile, bool) {
+	var pkg packageFile
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return pkg, false
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return packageFile{}, false
+	}
+	return pkg, true
+}
+
+// unreferencedRoots returns the entries no other entry depends on, as the
+// roots of lock files read without their package.json.
+func unreferencedRoots(entries map[string]*lockEntry) []lockRoot {
+	referenced := make(map[string]bool)
+	for _, e := range entries {
+		for _, key := range e.deps {
+			referenced[key] = true
+		}
+	}
+	var roots []lockRoot
+	for key, e := range entries {
+		if !referenced[key] {
+			roots = append(roots, lockRoot{name: e.name, key: key})
+		}
+	}
+	return roots
+}
@@ -17,3 +17,29 @@ func TestNewResolver(t *testing.T) {
 		t.Error("resolver not initialized")
 	}
 }
+
+func TestIsDistTag(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       bool
+	}{
+		{"next", true},
+		{"beta", true},
+		{"canary-v5", true},
+		{"latest", false}, // already means the newest version
+		{"^1.2.3", false},
+		{"1.x", false},
+		{"x", false},
+		{"*", false},
+		{"", false},
+		{">=1.0.0 <2.0.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			if got := isDistTag(tt.constraint); got != tt.want {
+				t.Errorf("isDistTag(%q) = %v, want %v", tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
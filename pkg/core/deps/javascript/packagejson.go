@@ -8,6 +8,7 @@ import (
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/integrations/npm"
 	"github.com/stacktower-io/stacktower/pkg/observability"
 )
 
@@ -38,6 +39,9 @@ func (p *PackageJSON) Parse(path string, opts deps.Options) (*deps.ManifestResul
 	}
 
 	directDeps := extractPackageDepsWithConstraints(pkg, opts.DependencyScope)
+	if r, ok := p.resolver.(*npmResolver); ok {
+		resolveDistTags(opts.Ctx, r.client, directDeps, opts.Refresh)
+	}
 
 	// Emit observability hooks for extracted dependencies
 	hooks := observability.ResolverFromContext(opts.Ctx)
@@ -72,30 +76,39 @@ func (p *PackageJSON) Parse(path string, opts deps.Options) (*deps.ManifestResul
 	}, nil
 }
 
-// extractPackageDepsWithConstraints extracts dependencies with version constraints
+// extractPackageDepsWithConstraints extracts dependencies with version
+// constraints. Aliases ("npm:pkg@^1.0") become dependencies on the package
+// they install.
 func extractPackageDepsWithConstraints(pkg packageFile, scope string) []deps.Dependency {
 	var result []deps.Dependency
-	for name, constraint := range pkg.Dependencies {
+	add := func(name, constraint string) {
+		if real, c, ok := npm.ParseAlias(constraint); ok {
+			name, constraint = real, c
+		}
 		result = append(result, deps.Dependency{Name: name, Constraint: constraint})
 	}
+	for name, constraint := range pkg.Dependencies {
+		add(name, constraint)
+	}
 	if scope == deps.DependencyScopeAll {
 		for name, constraint := range pkg.DevDependencies {
-			result = append(result, deps.Dependency{Name: name, Constraint: constraint})
+			add(name, constraint)
 		}
 	}
 	for name, constraint := range pkg.PeerDependencies {
-		result = append(result, deps.Dependency{Name: name, Constraint: constraint})
+		add(name, constraint)
 	}
 	return result
 }
 
 type packageFile struct {
-	Name             string            `json:"name"`
-	Version          string            `json:"version"`
-	Dependencies     map[string]string `json:"dependencies"`
-	DevDependencies  map[string]string `json:"devDependencies"`
-	PeerDependencies map[string]string `json:"peerDependencies"`
-	Engines          packageEngines    `json:"engines"`
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	Engines              packageEngines    `json:"engines"`
 }
 
 type packageEngines struct {
@@ -3,6 +3,8 @@ package javascript
 import (
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
@@ -83,6 +85,22 @@ func TestPackageJSON_Parse(t *testing.T) {
 	}
 }
 
+func TestExtractPackageDepsWithConstraints_Aliases(t *testing.T) {
+	pkg := packageFile{Dependencies: map[string]string{
+		"sw":    "npm:string-width@^4.2.0",
+		"react": "^18.0.0",
+	}}
+	got := extractPackageDepsWithConstraints(pkg, deps.DependencyScopeProdOnly)
+	slices.SortFunc(got, func(a, b deps.Dependency) int { return strings.Compare(a.Name, b.Name) })
+	want := []deps.Dependency{
+		{Name: "react", Constraint: "^18.0.0"},
+		{Name: "string-width", Constraint: "^4.2.0"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("dependencies = %v, want %v", got, want)
+	}
+}
+
 func TestPackageJSON_Parse_AllScopeIncludesDevDependencies(t *testing.T) {
 	dir := t.TempDir()
 	pkgFile := filepath.Join(dir, "package.json")
@@ -1,13 +1,16 @@
 package javascript
 
 import (
+	"cmp"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/integrations/npm"
 	"github.com/stacktower-io/stacktower/pkg/observability"
 )
 
@@ -84,14 +87,27 @@ type packageLockFile struct {
 
 // packageLockEntry represents a package entry in the "packages" object (v2/v3)
 type packageLockEntry struct {
+	Name         string            `json:"name"` // set for aliased packages, which install under another name
 	Version      string            `json:"version"`
 	Resolved     string            `json:"resolved"`
 	Dev          bool              `json:"dev"`
 	Optional     bool              `json:"optional"`
+	InBundle     bool              `json:"inBundle"` // shipped inside the tarball of the package it is nested in
+	Link         bool              `json:"link"`     // symlink to Resolved, a workspace or local directory
 	Dependencies map[string]string `json:"dependencies"`
 	License      string            `json:"license"`
 }
 
+// packageName returns the name of the package installed at path, which
+// differs from the directory for aliases.
+func (e packageLockEntry) packageName(path string) string {
+	name := extractPackageName(path)
+	if name == "" {
+		return ""
+	}
+	return cmp.Or(e.Name, name)
+}
+
 // packageLockDepV1 represents a dependency entry in v1 format
 type packageLockDepV1 struct {
 	Version      string                      `json:"version"`
@@ -118,10 +134,14 @@ func buildPackageLockGraph(lock packageLockFile, opts deps.Options) *dag.DAG {
 	return g
 }
 
-// buildFromPackages builds the graph from v2/v3 "packages" format
+// buildFromPackages builds the graph from v2/v3 "packages" format.
+// Aliased packages appear under their real names. Bundled packages are not
+// nodes of their own: like the registry resolver, the graph lists them in
+// the bundling package's "bundled" metadata.
 func buildFromPackages(lock packageLockFile, opts deps.Options) *dag.DAG {
 	g := dag.New(nil)
 	pkgs := make(map[string]bool)
+	bundled := make(map[string][]string) // bundling package → bundled names
 	hooks := observability.ResolverFromContext(opts.Ctx)
 
 	// First pass: add all package nodes
@@ -133,10 +153,16 @@ func buildFromPackages(lock packageLockFile, opts deps.Options) *dag.DAG {
 
 		// Extract package name from path (e.g., "node_modules/lodash" -> "lodash")
 		// Handle nested deps: "node_modules/foo/node_modules/bar" -> "bar"
-		name := extractPackageName(path)
+		name := entry.packageName(path)
 		if name == "" {
 			continue
 		}
+		// Packages the project itself bundles are installed like any other
+		if i := strings.LastIndex(path, "/node_modules/"); entry.InBundle && i > 0 {
+			parent := lock.Packages[path[:i]].packageName(path[:i])
+			bundled[parent] = append(bundled[parent], extractPackageName(path))
+			continue
+		}
 
 		// Only add if not already seen (handle duplicate nested paths)
 		if pkgs[name] {
@@ -149,6 +175,11 @@ func buildFromPackages(lock packageLockFile, opts deps.Options) *dag.DAG {
 
 		hooks.OnFetchStart(opts.Ctx, name, 0)
 		meta := dag.Metadata{"version": entry.Version}
+		if entry.Link {
+			// The linked directory has an entry of its own holding the version
+			meta["version"] = lock.Packages[entry.Resolved].Version
+			meta["path"] = entry.Resolved
+		}
 		if entry.Dev {
 			meta["dev"] = true
 		}
@@ -166,12 +197,21 @@ func buildFromPackages(lock packageLockFile, opts deps.Options) *dag.DAG {
 			continue
 		}
 
-		from := extractPackageName(path)
-		if from == "" || !pkgs[from] {
+		from := entry.packageName(path)
+		if from == "" || !pkgs[from] || (entry.InBundle && strings.Contains(path, "/node_modules/")) {
 			continue
 		}
+		if entry.Link {
+			entry.Dependencies = lock.Packages[entry.Resolved].Dependencies
+		}
 
 		for depName, constraint := range entry.Dependencies {
+			if slices.Contains(bundled[from], depName) {
+				continue
+			}
+			if real, c, ok := npm.ParseAlias(constraint); ok {
+				depName, constraint = real, c
+			}
 			if pkgs[depName] {
 				edgeMeta := dag.Metadata{}
 				if constraint != "" {
@@ -183,16 +223,27 @@ func buildFromPackages(lock packageLockFile, opts deps.Options) *dag.DAG {
 		}
 	}
 
+	for name, names := range bundled {
+		if n, ok := g.Node(name); ok {
+			slices.Sort(names)
+			n.Meta[deps.MetaBundled] = slices.Compact(names)
+		}
+	}
+
 	// Add virtual root and connect packages with no incoming edges
 	_ = g.AddNode(dag.Node{ID: deps.ProjectRootNodeID, Meta: dag.Metadata{"virtual": true}})
 
 	// Get direct dependencies from the root entry
 	if rootEntry, ok := lock.Packages[""]; ok {
-		for depName := range rootEntry.Dependencies {
+		for installName, spec := range rootEntry.Dependencies {
+			depName := installName
+			if real, _, ok := npm.ParseAlias(spec); ok {
+				depName = real
+			}
 			if pkgs[depName] {
 				edgeMeta := dag.Metadata{}
 				// Look up the pinned version from the lock file
-				if entry, ok := lock.Packages["node_modules/"+depName]; ok && entry.Version != "" {
+				if entry, ok := lock.Packages["node_modules/"+installName]; ok && entry.Version != "" {
 					edgeMeta["constraint"] = "==" + entry.Version
 				}
 				_ = g.AddEdge(dag.Edge{From: deps.ProjectRootNodeID, To: depName, Meta: edgeMeta})
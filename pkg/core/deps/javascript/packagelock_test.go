@@ -3,6 +3,7 @@ package javascript
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
@@ -256,6 +257,125 @@ func TestPackageLock_ScopedPackages(t *testing.T) {
 	}
 }
 
+func TestPackageLock_AliasesAndBundled(t *testing.T) {
+	content := `{
+  "name": "my-app",
+  "version": "1.0.0",
+  "lockfileVersion": 3,
+  "packages": {
+    "": {
+      "name": "my-app",
+      "version": "1.0.0",
+      "dependencies": {
+        "sw": "npm:string-width@^4.2.0",
+        "tool": "^1.0.0"
+      }
+    },
+    "node_modules/sw": {
+      "name": "string-width",
+      "version": "4.2.3"
+    },
+    "node_modules/tool": {
+      "version": "1.0.0",
+      "dependencies": {
+        "vendored": "^2.0.0"
+      },
+      "bundleDependencies": ["vendored"]
+    },
+    "node_modules/tool/node_modules/vendored": {
+      "version": "2.1.0",
+      "inBundle": true
+    }
+  }
+}`
+
+	tmpDir := t.TempDir()
+	lockPath := filepath.Join(tmpDir, "package-lock.json")
+	if err := os.WriteFile(lockPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := (&PackageLock{}).Parse(lockPath, deps.Options{})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	g := result.Graph
+
+	if _, ok := g.Node("sw"); ok {
+		t.Error("alias sw should appear under its real name")
+	}
+	if !g.HasEdge(deps.ProjectRootNodeID, "string-width") {
+		t.Error("missing edge to aliased string-width")
+	}
+	if _, ok := g.Node("vendored"); ok {
+		t.Error("bundled package should not be a node")
+	}
+	tool, ok := g.Node("tool")
+	if !ok {
+		t.Fatal("tool not found")
+	}
+	if got, _ := tool.Meta[deps.MetaBundled].([]string); !slices.Equal(got, []string{"vendored"}) {
+		t.Errorf("bundled = %v, want [vendored]", got)
+	}
+}
+
+func TestPackageLock_WorkspaceLinks(t *testing.T) {
+	content := `{
+  "name": "monorepo",
+  "lockfileVersion": 3,
+  "packages": {
+    "": {
+      "name": "monorepo",
+      "workspaces": ["packages/*"],
+      "dependencies": {
+        "@acme/utils": "*"
+      }
+    },
+    "node_modules/@acme/utils": {
+      "resolved": "packages/utils",
+      "link": true
+    },
+    "packages/utils": {
+      "name": "@acme/utils",
+      "version": "0.3.0",
+      "dependencies": {
+        "lodash": "^4.17.21"
+      }
+    },
+    "node_modules/lodash": {
+      "version": "4.17.21",
+      "resolved": "https://registry.npmjs.org/lodash/-/lodash-4.17.21.tgz"
+    }
+  }
+}`
+
+	tmpDir := t.TempDir()
+	lockPath := filepath.Join(tmpDir, "package-lock.json")
+	if err := os.WriteFile(lockPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := (&PackageLock{}).Parse(lockPath, deps.Options{})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	g := result.Graph
+
+	utils, ok := g.Node("@acme/utils")
+	if !ok {
+		t.Fatal("@acme/utils not found")
+	}
+	if utils.Meta["version"] != "0.3.0" || utils.Meta["path"] != "packages/utils" {
+		t.Errorf("linked package meta = %v, want version 0.3.0 and path packages/utils", utils.Meta)
+	}
+	if !g.HasEdge("@acme/utils", "lodash") {
+		t.Error("missing edge from linked workspace to its dependency")
+	}
+	if lodash, _ := g.Node("lodash"); lodash.Meta["path"] != nil {
+		t.Errorf("registry package has path %v", lodash.Meta["path"])
+	}
+}
+
 func TestExtractPackageName(t *testing.T) {
 	tests := []struct {
 		path string
@@ -0,0 +1,253 @@
+package javascript
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+)
+
+// PnpmLock parses pnpm-lock.yaml files, lockfile versions 5 to 9. Like
+// package-lock.json, it provides the full transitive closure of the
+// dependency graph without contacting the registry.
+//
+// The roots are the dependencies of the root importer ("."), with
+// devDependencies marked "dev" together with what only they pull in.
+// Workspace packages the root links to ("link:packages/a") are nodes of
+// their own, recorded under "path", with the dependencies of their
+// importer.
+type PnpmLock struct{}
+
+func (p *PnpmLock) Type() string              { return "pnpm-lock.yaml" }
+func (p *PnpmLock) IncludesTransitive() bool  { return true }
+func (p *PnpmLock) Supports(name string) bool { return strings.EqualFold(name, "pnpm-lock.yaml") }
+
+func (p *PnpmLock) Parse(path string, opts deps.Options) (*deps.ManifestResult, error) {
+	opts = opts.WithDefaults()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock pnpmLockFile
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("decode pnpm-lock.yaml: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	entries, roots := lock.graph(dir)
+	g := buildLockGraph(entries, roots, opts)
+	deps.EnrichGraph(opts.Ctx, g, "package.json", opts)
+
+	pkg, _ := readPackageJSON(dir)
+	return &deps.ManifestResult{
+		Graph:              g,
+		Type:               p.Type(),
+		IncludesTransitive: true,
+		RootPackage:        pkg.Name,
+		RuntimeVersion:     extractNodeVersion(pkg.Engines.Node),
+		RuntimeConstraint:  pkg.Engines.Node,
+	}, nil
+}
+
+// pnpmLockFile is the structure of pnpm-lock.yaml. Lock files of
+// single-package projects before version 9 list the importer's
+// dependencies at the top level instead of under importers.
+type pnpmLockFile struct {
+	LockfileVersion string                  `yaml:"lockfileVersion"`
+	Importers       map[string]pnpmImporter `yaml:"importers"`
+	pnpmImporter    `yaml:",inline"`
+	Packages        map[string]pnpmPackage `yaml:"packages"`
+	Snapshots       map[string]pnpmPackage `yaml:"snapshots"` // version 9: dependencies of Packages, per peer set
+}
+
+// pnpmImporter lists the direct dependencies of a project of the
+// workspace.
+type pnpmImporter struct {
+	Dependencies         map[string]pnpmDep `yaml:"dependencies"`
+	DevDependencies      map[string]pnpmDep `yaml:"devDependencies"`
+	OptionalDependencies map[string]pnpmDep `yaml:"optionalDependencies"`
+}
+
+// pnpmDep is the resolved version of a direct dependency: a plain string
+// before version 6, a {specifier, version} map since.
+type pnpmDep struct {
+	Version string
+}
+
+func (d *pnpmDep) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		d.Version = n.Value
+		return nil
+	}
+	var v struct {
+		Version string `yaml:"version"`
+	}
+	if err := n.Decode(&v); err != nil {
+		return err
+	}
+	d.Version = v.Version
+	return nil
+}
+
+// pnpmPackage is a locked package, keyed by its name and version.
+type pnpmPackage struct {
+	Name                 string            `yaml:"name"`    // set for packages not from the registry
+	Version              string            `yaml:"version"` // set for packages not from the registry
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+// legacy reports whether the lock file uses the keys of versions before 6,
+// "/name/version" instead of "/name@version".
+func (l pnpmLockFile) legacy() bool {
+	v, err := strconv.ParseFloat(l.LockfileVersion, 64)
+	return err == nil && v < 6
+}
+
+// packages returns the locked packages with their dependencies: the
+// snapshots of version 9, the packages of earlier versions.
+func (l pnpmLockFile) packages() map[string]pnpmPackage {
+	if len(l.Snapshots) > 0 {
+		return l.Snapshots
+	}
+	return l.Packages
+}
+
+// parseKey returns the name and version of a package key: "name@1.0.0"
+// (version 9), "/name@1.0.0" (versions 6 to 8) or "/name/1.0.0" (before
+// 6), with any peer suffix, "(react@18.2.0)" or "_react@18.2.0", removed.
+func (l pnpmLockFile) parseKey(key string) (name, version string) {
+	key = strings.TrimPrefix(key, "/")
+	if l.legacy() {
+		i := strings.LastIndex(key, "/")
+		if i <= 0 {
+			return key, ""
+		}
+		version, _, _ = strings.Cut(key[i+1:], "_")
+		return key[:i], version
+	}
+	key, _, _ = strings.Cut(key, "(")
+	i := strings.LastIndex(key, "@")
+	if i <= 0 {
+		return key, ""
+	}
+	return key[:i], key[i+1:]
+}
+
+// resolve returns the key of the package name resolves to at ref, the
+// version a dependency lists, or "" if the lock file does not have it.
+// Aliased dependencies list the key of the package they install.
+func (l pnpmLockFile) resolve(pkgs map[string]pnpmPackage, name, ref string) string {
+	candidates := []string{name + "@" + ref, ref}
+	switch {
+	case l.legacy():
+		candidates = []string{"/" + name + "/" + ref, ref}
+	case len(l.Snapshots) == 0:
+		candidates = []string{"/" + name + "@" + ref, ref}
+	}
+	for _, key := range candidates {
+		if _, ok := pkgs[key]; ok {
+			return key
+		}
+	}
+	return ""
+}
+
+// graph converts the lock file to the form [buildLockGraph] reads. dir is
+// the lock file's directory, where workspace packages are read from.
+func (l pnpmLockFile) graph(dir string) (map[string]*lockEntry, []lockRoot) {
+	pkgs := l.packages()
+	b := pnpmGraph{lock: l, pkgs: pkgs, dir: dir, entries: make(map[string]*lockEntry, len(pkgs))}
+	for key, p := range pkgs {
+		name, version := l.parseKey(key)
+		if len(l.Snapshots) > 0 {
+			// Version 9 keeps the name and version of packages not from
+			// the registry under packages, keyed without the peer suffix
+			base, _, _ := strings.Cut(key, "(")
+			p.Name, p.Version = l.Packages[base].Name, l.Packages[base].Version
+		}
+		e := &lockEntry{name: cmp.Or(p.Name, name), version: cmp.Or(p.Version, version), deps: map[string]string{}, ranges: map[string]string{}}
+		for _, m := range []map[string]string{p.Dependencies, p.OptionalDependencies} {
+			for dep, ref := range m {
+				if target := l.resolve(pkgs, dep, ref); target != "" {
+					depName, depVersion := l.parseKey(target)
+					e.deps[depName] = target
+					e.ranges[depName] = "==" + depVersion
+				}
+			}
+		}
+		b.entries[key] = e
+	}
+
+	b.importers = l.Importers
+	if len(b.importers) == 0 {
+		b.importers = map[string]pnpmImporter{".": l.pnpmImporter}
+	}
+	root := b.importers["."]
+	roots := b.importerRoots(".", root.Dependencies, false)
+	roots = append(roots, b.importerRoots(".", root.OptionalDependencies, false)...)
+	roots = append(roots, b.importerRoots(".", root.DevDependencies, true)...)
+	return b.entries, roots
+}
+
+// pnpmGraph holds the state of [pnpmLockFile.graph].
+type pnpmGraph struct {
+	lock      pnpmLockFile
+	pkgs      map[string]pnpmPackage
+	importers map[string]pnpmImporter
+	entries   map[string]*lockEntry
+	dir       string
+}
+
+// importerRoots resolves the dependencies m of the importer at dir, a
+// directory relative to the lock file, adding the workspace packages it
+// links to.
+func (b *pnpmGraph) importerRoots(dir string, m map[string]pnpmDep, dev bool) []lockRoot {
+	var roots []lockRoot
+	for name, d := range m {
+		key := b.lock.resolve(b.pkgs, name, d.Version)
+		if link, ok := strings.CutPrefix(d.Version, "link:"); ok {
+			key = b.link(path.Join(dir, link), name)
+		}
+		if key != "" {
+			roots = append(roots, lockRoot{name: name, key: key, dev: dev})
+		}
+	}
+	return roots
+}
+
+// link adds the entry of the workspace package at ws, a directory relative
+// to the lock file, and returns its key. The package is named after its
+// package.json, falling back to name, the dependency linking to it.
+func (b *pnpmGraph) link(ws, name string) string {
+	key := "link:" + ws
+	if _, ok := b.entries[key]; ok {
+		return key
+	}
+	e := &lockEntry{name: name, path: ws, deps: map[string]string{}, ranges: map[string]string{}}
+	if pkg, ok := readPackageJSON(filepath.Join(b.dir, ws)); ok {
+		e.name = cmp.Or(pkg.Name, name)
+		e.version = pkg.Version
+	}
+	b.entries[key] = e
+	imp := b.importers[ws]
+	for _, m := range []map[string]pnpmDep{imp.Dependencies, imp.OptionalDependencies} {
+		for _, r := range b.importerRoots(ws, m, false) {
+			dep := b.entries[r.key]
+			e.deps[dep.name] = r.key
+			if dep.version != "" {
+				e.ranges[dep.name] = "==" + dep.version
+			}
+		}
+	}
+	return key
+}
@@ -0,0 +1,264 @@
+package javascript
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+)
+
+const pnpmV9Lock = `lockfileVersion: '9.0'
+
+settings:
+  autoInstallPeers: true
+  excludeLinksFromLockfile: false
+
+importers:
+
+  .:
+    dependencies:
+      '@acme/utils':
+        specifier: workspace:*
+        version: link:packages/utils
+      react-dom:
+        specifier: ^18.2.0
+        version: 18.2.0(react@18.2.0)
+      sw:
+        specifier: npm:string-width@^4.2.0
+        version: string-width@4.2.3
+    devDependencies:
+      vitest:
+        specifier: ^1.0.0
+        version: 1.6.0
+
+  packages/utils:
+    dependencies:
+      lodash:
+        specifier: ^4.17.21
+        version: 4.17.21
+
+packages:
+
+  js-tokens@4.0.0:
+    resolution: {integrity: sha512-RdJUflcE3cUzKiMqQgsCu06FPu9UdIJO0beYbPhHN4k6apgJtifcoCtT9bcxOpYBtpD2kCM6Sbzg4CausW/PKQ==}
+
+  lodash@4.17.21:
+    resolution: {integrity: sha512-v2kDEe57lecTulaDIuNTPy3Ry4gLGJ6Z1O3vE1krgXZNrsQ+LFTGHVxVjcXPs17LhbZVGedAJv8XZ1tvj5FvSg==}
+
+  loose-envify@1.4.0:
+    resolution: {integrity: sha512-lyuxPGr/Wfhrlem2CL/UcnUc1zcqKAImBDzukY7Y5F/yQiNdko6+fRLevlw1HgMySw7f611UIY408EtxRSoK3Q==}
+    hasBin: true
+
+  react-dom@18.2.0:
+    resolution: {integrity: sha512-6IMTriUmvsjHUjNtEDudZfuDQUoWXVxKHhlEGSk81n4YFS+r/Kl99wXiwlVXtPBtJenozv2P+hxDsw9eA7Xo6g==}
+    peerDependencies:
+      react: ^18.2.0
+
+  react@18.2.0:
+    resolution: {integrity: sha512-/3IjMdb2L9QbBdWiW5e3P2/npwMBaU9mHCSCUzNln0ZCYbcfTsGbTJrU/kGemdH2IWmB2ioZ+zkxtmq6g09fGQ==}
+
+  string-width@4.2.3:
+    resolution: {integrity: sha512-wKyQRQpjJ0sIp62ErSZdGsjMJWsap5oRNihHhu6G7JVO/9jIB6UyevL+tXuOqrng8j/cxKTWyWUwvSTriiZz/g==}
+
+  vitest@1.6.0:
+    resolution: {integrity: sha512-H5r/dN06swuFnzNFhq/dnz37bPXnq8xB2xB5JOVk8K09rUtoeNN+LHWkoQ0A/i3hvbUKKcCei9KpbxqHMLhLLA==}
+
+snapshots:
+
+  js-tokens@4.0.0: {}
+
+  lodash@4.17.21: {}
+
+  loose-envify@1.4.0:
+    dependencies:
+      js-tokens: 4.0.0
+
+  react-dom@18.2.0(react@18.2.0):
+    dependencies:
+      loose-envify: 1.4.0
+      react: 18.2.0
+
+  react@18.2.0:
+    dependencies:
+      loose-envify: 1.4.0
+
+  string-width@4.2.3: {}
+
+  vitest@1.6.0: {}
+`
+
+func TestPnpmLock_ParseV9(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"pnpm-lock.yaml":              pnpmV9Lock,
+		"package.json":                `{"name": "web", "engines": {"node": ">=20"}}`,
+		"packages/utils/package.json": `{"name": "@acme/utils", "version": "0.3.0"}`,
+	})
+
+	result, err := (&PnpmLock{}).Parse(filepath.Join(dir, "pnpm-lock.yaml"), deps.Options{DependencyScope: deps.DependencyScopeAll})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	g := result.Graph
+	if result.RootPackage != "web" || result.RuntimeVersion != "20" {
+		t.Errorf("result = %+v", result)
+	}
+
+	for id, want := range map[string]string{
+		"react-dom":    "18.2.0",
+		"react":        "18.2.0",
+		"loose-envify": "1.4.0",
+		"js-tokens":    "4.0.0",
+		"string-width": "4.2.3",
+		"vitest":       "1.6.0",
+		"@acme/utils":  "0.3.0",
+		"lodash":       "4.17.21",
+	} {
+		if got := nodeVersion(t, g, id); got != want {
+			t.Errorf("%s version = %q, want %q", id, got, want)
+		}
+	}
+	for _, e := range [][2]string{
+		{deps.ProjectRootNodeID, "react-dom"},
+		{deps.ProjectRootNodeID, "string-width"},
+		{deps.ProjectRootNodeID, "@acme/utils"},
+		{"react-dom", "react"},
+		{"react", "loose-envify"},
+		{"loose-envify", "js-tokens"},
+		{"@acme/utils", "lodash"},
+	} {
+		if !g.HasEdge(e[0], e[1]) {
+			t.Errorf("missing edge %s -> %s", e[0], e[1])
+		}
+	}
+	utils, _ := g.Node("@acme/utils")
+	if utils.Meta["path"] != "packages/utils" {
+		t.Errorf("workspace path = %v", utils.Meta["path"])
+	}
+	vitest, _ := g.Node("vitest")
+	if vitest.Meta["dev"] != true {
+		t.Error("vitest should be dev")
+	}
+
+	prod, err := (&PnpmLock{}).Parse(filepath.Join(dir, "pnpm-lock.yaml"), deps.Options{})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, ok := prod.Graph.Node("vitest"); ok {
+		t.Error("dev dependency in a prod_only graph")
+	}
+}
+
+const pnpmV6Lock = `lockfileVersion: '6.0'
+
+dependencies:
+  react-dom:
+    specifier: ^18.2.0
+    version: 18.2.0(react@18.2.0)
+
+packages:
+
+  /loose-envify@1.4.0:
+    resolution: {integrity: sha512-x}
+    dependencies:
+      js-tokens: 4.0.0
+    dev: false
+
+  /js-tokens@4.0.0:
+    resolution: {integrity: sha512-x}
+    dev: false
+
+  /react-dom@18.2.0(react@18.2.0):
+    resolution: {integrity: sha512-x}
+    peerDependencies:
+      react: ^18.2.0
+    dependencies:
+      loose-envify: 1.4.0
+      react: 18.2.0
+    dev: false
+
+  /react@18.2.0:
+    resolution: {integrity: sha512-x}
+    dependencies:
+      loose-envify: 1.4.0
+    dev: false
+`
+
+const pnpmV5Lock = `lockfileVersion: 5.4
+
+specifiers:
+  '@babel/code-frame': ^7.0.0
+  react-dom: ^18.2.0
+
+dependencies:
+  '@babel/code-frame': 7.12.13
+  react-dom: 18.2.0_react@18.2.0
+
+packages:
+
+  /@babel/code-frame/7.12.13:
+    resolution: {integrity: sha512-x}
+    dev: false
+
+  /loose-envify/1.4.0:
+    resolution: {integrity: sha512-x}
+    dev: false
+
+  /react-dom/18.2.0_react@18.2.0:
+    resolution: {integrity: sha512-x}
+    dependencies:
+      loose-envify: 1.4.0
+      react: 18.2.0
+    dev: false
+
+  /react/18.2.0:
+    resolution: {integrity: sha512-x}
+    dependencies:
+      loose-envify: 1.4.0
+    dev: false
+`
+
+func TestPnpmLock_LegacyVersions(t *testing.T) {
+	for _, tc := range []struct{ name, lock string }{{"v6", pnpmV6Lock}, {"v5", pnpmV5Lock}} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeProject(t, map[string]string{"pnpm-lock.yaml": tc.lock})
+			result, err := (&PnpmLock{}).Parse(filepath.Join(dir, "pnpm-lock.yaml"), deps.Options{})
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			g := result.Graph
+			if got := nodeVersion(t, g, "react-dom"); got != "18.2.0" {
+				t.Errorf("react-dom version = %q, want the peer suffix stripped", got)
+			}
+			for _, e := range [][2]string{
+				{deps.ProjectRootNodeID, "react-dom"},
+				{"react-dom", "react"},
+				{"react", "loose-envify"},
+			} {
+				if !g.HasEdge(e[0], e[1]) {
+					t.Errorf("missing edge %s -> %s", e[0], e[1])
+				}
+			}
+		})
+	}
+}
+
+func TestPnpmLock_ParseKey(t *testing.T) {
+	v9 := pnpmLockFile{LockfileVersion: "9.0"}
+	v5 := pnpmLockFile{LockfileVersion: "5.4"}
+	tests := []struct {
+		lock          pnpmLockFile
+		key           string
+		name, version string
+	}{
+		{v9, "react-dom@18.2.0(react@18.2.0)", "react-dom", "18.2.0"},
+		{v9, "@babel/core@7.24.0", "@babel/core", "7.24.0"},
+		{v9, "/@babel/core@7.24.0", "@babel/core", "7.24.0"},
+		{v5, "/@babel/core/7.24.0", "@babel/core", "7.24.0"},
+		{v5, "/react-dom/18.2.0_react@18.2.0", "react-dom", "18.2.0"},
+	}
+	for _, tt := range tests {
+		if name, version := tt.lock.parseKey(tt.key); name != tt.name || version != tt.version {
+			t.Errorf("parseKey(%q) = %q, %q; want %q, %q", tt.key, name, version, tt.name, tt.version)
+		}
+	}
+}
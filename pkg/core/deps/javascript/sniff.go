@@ -0,0 +1,20 @@
+package javascript
+
+import "regexp"
+
+// dependencySectionRE matches the package.json fields that list
+// dependencies.
+var dependencySectionRE = regexp.MustCompile(`"(dependencies|devDependencies|peerDependencies|optionalDependencies)"\s*:`)
+
+// sniffManifest ranks a package.json without dependencies, such as one
+// that only holds scripts in a Deno project, below manifests that other
+// languages recognize. See [deps.ManifestSniffer].
+func sniffManifest(filename string, content []byte) (string, float64) {
+	if filename != "package.json" {
+		return "", 0
+	}
+	if dependencySectionRE.Match(content) {
+		return "package", 0.95
+	}
+	return "package", 0.3
+}
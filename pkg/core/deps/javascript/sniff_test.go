@@ -0,0 +1,24 @@
+package javascript
+
+import "testing"
+
+func TestSniffManifest(t *testing.T) {
+	tests := []struct {
+		name, filename, content string
+		wantType                string
+		wantConfidence          float64
+	}{
+		{"dependencies", "package.json", `{"name": "app", "dependencies": {"react": "^18"}}`, "package", 0.95},
+		{"dev dependencies only", "package.json", "{\n  \"devDependencies\" : {}\n}", "package", 0.95},
+		{"scripts only", "package.json", `{"name": "app", "scripts": {"dev": "deno task dev"}}`, "package", 0.3},
+		{"lockfile", "package-lock.json", `{"dependencies": {}}`, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ, confidence := sniffManifest(tt.filename, []byte(tt.content))
+			if typ != tt.wantType || confidence != tt.wantConfidence {
+				t.Errorf("sniffManifest() = %q, %v; want %q, %v", typ, confidence, tt.wantType, tt.wantConfidence)
+			}
+		})
+	}
+}
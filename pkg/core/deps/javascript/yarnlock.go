@@ -0,0 +1,326 @@
+package javascript
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/integrations/npm"
+)
+
+// YarnLock parses yarn.lock files, both the custom format of Yarn 1 and
+// the YAML format of Yarn 2+ ("Berry"). Like package-lock.json, it
+// provides the full transitive closure of the dependency graph without
+// contacting the registry.
+//
+// yarn.lock does not record which dependencies the project asks for, so
+// the roots come from the package.json next to it: dependencies and
+// optionalDependencies, and devDependencies, which are marked "dev" with
+// what only they pull in. Without a package.json, the roots are the root
+// workspace's dependencies (Berry) or the packages nothing else depends
+// on (Yarn 1).
+type YarnLock struct{}
+
+func (y *YarnLock) Type() string              { return "yarn.lock" }
+func (y *YarnLock) IncludesTransitive() bool  { return true }
+func (y *YarnLock) Supports(name string) bool { return strings.EqualFold(name, "yarn.lock") }
+
+func (y *YarnLock) Parse(path string, opts deps.Options) (*deps.ManifestResult, error) {
+	opts = opts.WithDefaults()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock yarnLockFile
+	if isYarnBerry(string(data)) {
+		lock, err = parseYarnBerry(data)
+	} else {
+		lock, err = parseYarnV1(string(data))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	pkg, hasPkg := readPackageJSON(dir)
+	entries := lock.entries(dir)
+	var roots []lockRoot
+	switch {
+	case hasPkg:
+		roots = lock.roots(pkg)
+	case lock.workspaceRoot != "":
+		roots = lock.depRoots(entries[lock.workspaceRoot])
+	default:
+		roots = unreferencedRoots(entries)
+	}
+
+	g := buildLockGraph(entries, roots, opts)
+	deps.EnrichGraph(opts.Ctx, g, "package.json", opts)
+
+	return &deps.ManifestResult{
+		Graph:              g,
+		Type:               y.Type(),
+		IncludesTransitive: true,
+		RootPackage:        pkg.Name,
+		RuntimeVersion:     extractNodeVersion(pkg.Engines.Node),
+		RuntimeConstraint:  pkg.Engines.Node,
+	}, nil
+}
+
+// yarnEntry is one package of a yarn.lock file.
+type yarnEntry struct {
+	Version              string            `yaml:"version"`
+	Resolution           string            `yaml:"resolution"` // Berry: "lodash@npm:4.17.21"
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"` // Yarn 1; Berry lists them with the others
+}
+
+// yarnLockFile is a parsed yarn.lock file.
+type yarnLockFile struct {
+	// descriptors maps each descriptor ("lodash@^4.17.0") to the key of
+	// the package it resolves to.
+	descriptors map[string]string
+	packages    map[string]*yarnEntry
+
+	// workspaceRoot is the key of the project's own package (Berry only).
+	workspaceRoot string
+}
+
+// isYarnBerry reports whether a yarn.lock is in the YAML format of Yarn 2+,
+// which starts with a __metadata section.
+func isYarnBerry(data string) bool {
+	for _, line := range strings.Split(data, "\n") {
+		if strings.HasPrefix(line, "__metadata:") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseYarnV1 reads the custom format of Yarn 1:
+//
+//	"@babel/code-frame@^7.0.0", "@babel/code-frame@^7.10.4":
+//	  version "7.12.13"
+//	  resolved "https://registry.yarnpkg.com/..."
+//	  dependencies:
+//	    "@babel/highlight" "^7.12.13"
+func parseYarnV1(data string) (yarnLockFile, error) {
+	lock := yarnLockFile{descriptors: map[string]string{}, packages: map[string]*yarnEntry{}}
+	var cur *yarnEntry
+	var descriptors []string
+	var section map[string]string
+
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		name, _ := splitDescriptor(descriptors[0])
+		if real, _, ok := npm.ParseAlias(descriptorRange(descriptors[0])); ok {
+			name = real
+		}
+		key := name + "@" + cur.Version
+		if _, ok := lock.packages[key]; !ok {
+			lock.packages[key] = cur
+		}
+		for _, d := range descriptors {
+			lock.descriptors[d] = key
+		}
+	}
+
+	sc := bufio.NewScanner(strings.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNum := 1; sc.Scan(); lineNum++ {
+		line := strings.TrimRight(sc.Text(), " \r")
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		switch indent := len(line) - len(trimmed); {
+		case indent == 0:
+			flush()
+			descriptors = nil
+			for _, d := range strings.Split(strings.TrimSuffix(trimmed, ":"), ",") {
+				descriptors = append(descriptors, unquoteYarn(strings.TrimSpace(d)))
+			}
+			cur, section = &yarnEntry{}, nil
+		case cur == nil:
+			return lock, fmt.Errorf("yarn.lock line %d: field outside of a package", lineNum)
+		case indent <= 2:
+			field, value := splitYarnField(trimmed)
+			section = nil
+			switch {
+			case field == "version":
+				cur.Version = value
+			case field == "dependencies:":
+				cur.Dependencies = map[string]string{}
+				section = cur.Dependencies
+			case field == "optionalDependencies:":
+				cur.OptionalDependencies = map[string]string{}
+				section = cur.OptionalDependencies
+			}
+		case section != nil:
+			name, value := splitYarnField(trimmed)
+			section[name] = value
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return lock, err
+	}
+	flush()
+	return lock, nil
+}
+
+// splitYarnField splits a Yarn 1 line into its (unquoted) key and value.
+func splitYarnField(line string) (key, value string) {
+	if strings.HasPrefix(line, `"`) {
+		if end := strings.Index(line[1:], `"`); end >= 0 {
+			key, value = line[1:end+1], line[end+2:]
+		}
+	} else {
+		key, value, _ = strings.Cut(line, " ")
+	}
+	return key, unquoteYarn(strings.TrimSpace(value))
+}
+
+func unquoteYarn(s string) string {
+	if u, err := strconv.Unquote(s); err == nil {
+		return u
+	}
+	return s
+}
+
+// parseYarnBerry reads the YAML format of Yarn 2+:
+//
+//	"lodash@npm:^4.17.20, lodash@npm:^4.17.21":
+//	  version: 4.17.21
+//	  resolution: "lodash@npm:4.17.21"
+//	  dependencies:
+//	    foo: "npm:^1.0.0"
+func parseYarnBerry(data []byte) (yarnLockFile, error) {
+	var raw map[string]*yarnEntry
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return yarnLockFile{}, fmt.Errorf("decode yarn.lock: %w", err)
+	}
+	lock := yarnLockFile{descriptors: map[string]string{}, packages: map[string]*yarnEntry{}}
+	for descs, e := range raw {
+		if descs == "__metadata" || e == nil || e.Resolution == "" {
+			continue
+		}
+		lock.packages[e.Resolution] = e
+		for _, d := range strings.Split(descs, ",") {
+			lock.descriptors[strings.TrimSpace(d)] = e.Resolution
+		}
+		if strings.HasSuffix(e.Resolution, "@workspace:.") {
+			lock.workspaceRoot = e.Resolution
+		}
+	}
+	return lock, nil
+}
+
+// resolve returns the key of the package a dependency resolves to, or ""
+// if the lock file does not list it. Berry records bare ranges under the
+// npm protocol, and workspaces under their path, which "workspace:*"
+// ranges do not give.
+func (l yarnLockFile) resolve(name, rng string) string {
+	if key, ok := l.descriptors[name+"@"+rng]; ok {
+		return key
+	}
+	if key, ok := l.descriptors[name+"@npm:"+rng]; ok {
+		return key
+	}
+	if strings.HasPrefix(rng, "workspace:") {
+		for key := range l.packages {
+			if strings.HasPrefix(key, name+"@workspace:") {
+				return key
+			}
+		}
+	}
+	return ""
+}
+
+// entries converts the packages to the form [buildLockGraph] reads. dir is
+// the lock file's directory, where workspace versions are read from.
+func (l yarnLockFile) entries(dir string) map[string]*lockEntry {
+	entries := make(map[string]*lockEntry, len(l.packages))
+	for key, p := range l.packages {
+		name, ref := splitDescriptor(key)
+		e := &lockEntry{
+			name:    name,
+			version: p.Version,
+			deps:    map[string]string{},
+			ranges:  map[string]string{},
+		}
+		if ws, ok := strings.CutPrefix(ref, "workspace:"); ok {
+			e.path = ws
+			if pkg, ok := readPackageJSON(filepath.Join(dir, ws)); ok {
+				e.version = pkg.Version
+			}
+		} else if p.Resolution == "" {
+			// Yarn 1 keys are "name@version"
+			e.version = ref
+		}
+		for _, m := range []map[string]string{p.Dependencies, p.OptionalDependencies} {
+			for dep, rng := range m {
+				if target := l.resolve(dep, rng); target != "" {
+					depName, _ := splitDescriptor(target)
+					e.deps[depName] = target
+					e.ranges[depName] = strings.TrimPrefix(rng, "npm:")
+				}
+			}
+		}
+		entries[key] = e
+	}
+	return entries
+}
+
+// roots resolves the dependencies pkg lists.
+func (l yarnLockFile) roots(pkg packageFile) []lockRoot {
+	var roots []lockRoot
+	add := func(m map[string]string, dev bool) {
+		for name, rng := range m {
+			if key := l.resolve(name, rng); key != "" {
+				roots = append(roots, lockRoot{name: name, key: key, dev: dev})
+			}
+		}
+	}
+	add(pkg.Dependencies, false)
+	add(pkg.OptionalDependencies, false)
+	add(pkg.DevDependencies, true)
+	return roots
+}
+
+// depRoots turns the dependencies of an entry into roots.
+func (l yarnLockFile) depRoots(e *lockEntry) []lockRoot {
+	if e == nil {
+		return nil
+	}
+	var roots []lockRoot
+	for name, key := range e.deps {
+		roots = append(roots, lockRoot{name: name, key: key})
+	}
+	return roots
+}
+
+// splitDescriptor splits a yarn descriptor or resolution, such as
+// "@babel/core@^7.0.0" or "lodash@npm:4.17.21", into the package name and
+// what follows the "@".
+func splitDescriptor(d string) (name, ref string) {
+	if i := strings.Index(d[min(1, len(d)):], "@"); i >= 0 {
+		return d[:i+1], d[i+2:]
+	}
+	return d, ""
+}
+
+// descriptorRange returns the range part of a descriptor.
+func descriptorRange(d string) string {
+	_, ref := splitDescriptor(d)
+	return ref
+}
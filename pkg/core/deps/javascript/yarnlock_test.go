@@ -0,0 +1,278 @@
+package javascript
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+)
+
+// writeProject writes files, keyed by slash-separated path, to a temporary
+// directory and returns it.
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// nodeVersion returns the version of a node, failing the test if the node
+// is missing.
+func nodeVersion(t *testing.T, g *dag.DAG, id string) string {
+	t.Helper()
+	n, ok := g.Node(id)
+	if !ok {
+		t.Fatalf("node %s not found", id)
+	}
+	v, _ := n.Meta["version"].(string)
+	return v
+}
+
+const yarnV1Lock = `# THIS IS AN AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.
+# yarn lockfile v1
+
+
+"@babel/code-frame@^7.0.0":
+  version "7.12.13"
+  resolved "https://registry.yarnpkg.com/@babel/code-frame/-/code-frame-7.12.13.tgz"
+  dependencies:
+    "@babel/highlight" "^7.10.4"
+
+"@babel/highlight@^7.10.4":
+  version "7.13.10"
+  resolved "https://registry.yarnpkg.com/@babel/highlight/-/highlight-7.13.10.tgz"
+
+chalk@^4.1.0, chalk@^4.1.2:
+  version "4.1.2"
+  resolved "https://registry.yarnpkg.com/chalk/-/chalk-4.1.2.tgz"
+  dependencies:
+    supports-color "^7.1.0"
+  optionalDependencies:
+    fsevents "~2.3.1"
+
+fsevents@~2.3.1:
+  version "2.3.2"
+
+supports-color@^7.1.0:
+  version "7.2.0"
+
+"sw@npm:string-width@^4.2.0":
+  version "4.2.3"
+
+jest@^29.0.0:
+  version "29.7.0"
+  dependencies:
+    chalk "^4.1.2"
+    pretty-format "^29.7.0"
+
+pretty-format@^29.7.0:
+  version "29.7.0"
+`
+
+const yarnPackageJSON = `{
+  "name": "my-app",
+  "engines": {"node": ">=18"},
+  "dependencies": {
+    "@babel/code-frame": "^7.0.0",
+    "chalk": "^4.1.0",
+    "sw": "npm:string-width@^4.2.0"
+  },
+  "devDependencies": {
+    "jest": "^29.0.0"
+  }
+}`
+
+func TestYarnLock_ParseV1(t *testing.T) {
+	dir := writeProject(t, map[string]string{"yarn.lock": yarnV1Lock, "package.json": yarnPackageJSON})
+
+	result, err := (&YarnLock{}).Parse(filepath.Join(dir, "yarn.lock"), deps.Options{DependencyScope: deps.DependencyScopeAll})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	g := result.Graph
+	if result.RootPackage != "my-app" || result.RuntimeVersion != "18" || !result.IncludesTransitive {
+		t.Errorf("result = %+v", result)
+	}
+
+	for id, want := range map[string]string{
+		"@babel/code-frame": "7.12.13",
+		"@babel/highlight":  "7.13.10",
+		"chalk":             "4.1.2",
+		"fsevents":          "2.3.2",
+		"string-width":      "4.2.3",
+		"jest":              "29.7.0",
+		"pretty-format":     "29.7.0",
+	} {
+		if got := nodeVersion(t, g, id); got != want {
+			t.Errorf("%s version = %q, want %q", id, got, want)
+		}
+	}
+	for _, e := range [][2]string{
+		{deps.ProjectRootNodeID, "@babel/code-frame"},
+		{deps.ProjectRootNodeID, "string-width"},
+		{"@babel/code-frame", "@babel/highlight"},
+		{"chalk", "supports-color"},
+		{"chalk", "fsevents"},
+		{"jest", "chalk"},
+	} {
+		if !g.HasEdge(e[0], e[1]) {
+			t.Errorf("missing edge %s -> %s", e[0], e[1])
+		}
+	}
+
+	jest, _ := g.Node("jest")
+	prettyFormat, _ := g.Node("pretty-format")
+	chalk, _ := g.Node("chalk")
+	if jest.Meta["dev"] != true || prettyFormat.Meta["dev"] != true {
+		t.Error("packages only devDependencies pull in should be dev")
+	}
+	if chalk.Meta["dev"] != nil {
+		t.Error("chalk is a production dependency too")
+	}
+}
+
+func TestYarnLock_ProdOnly(t *testing.T) {
+	dir := writeProject(t, map[string]string{"yarn.lock": yarnV1Lock, "package.json": yarnPackageJSON})
+
+	result, err := (&YarnLock{}).Parse(filepath.Join(dir, "yarn.lock"), deps.Options{})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	for _, id := range []string{"jest", "pretty-format"} {
+		if _, ok := result.Graph.Node(id); ok {
+			t.Errorf("dev-only package %s in a prod_only graph", id)
+		}
+	}
+	if _, ok := result.Graph.Node("chalk"); !ok {
+		t.Error("chalk missing")
+	}
+}
+
+func TestYarnLock_WithoutPackageJSON(t *testing.T) {
+	dir := writeProject(t, map[string]string{"yarn.lock": yarnV1Lock})
+
+	result, err := (&YarnLock{}).Parse(filepath.Join(dir, "yarn.lock"), deps.Options{})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	g := result.Graph
+	for _, id := range []string{"@babel/code-frame", "jest", "string-width"} {
+		if !g.HasEdge(deps.ProjectRootNodeID, id) {
+			t.Errorf("%s should be a root without package.json", id)
+		}
+	}
+	if g.HasEdge(deps.ProjectRootNodeID, "chalk") {
+		t.Error("chalk is depended upon and should not be a root")
+	}
+}
+
+const yarnBerryLock = `# This file is generated by running "yarn install" inside your project.
+# Manual changes might be lost - proceed with caution!
+
+__metadata:
+  version: 8
+  cacheKey: 10c0
+
+"@acme/utils@workspace:packages/utils":
+  version: 0.0.0-use.local
+  resolution: "@acme/utils@workspace:packages/utils"
+  dependencies:
+    lodash: "npm:^4.17.21"
+  languageName: unknown
+  linkType: soft
+
+"debug@npm:4.3.4":
+  version: 4.3.4
+  resolution: "debug@npm:4.3.4"
+  dependencies:
+    ms: "npm:2.1.2"
+  peerDependenciesMeta:
+    supports-color:
+      optional: true
+  checksum: 10c0/cedbec45298dd5c501d01b92b119cd3faebe5438c3917ff11ae1bff86a6c722930ac9c8659792824013168ba6db7c4668225d845c633fbdafbbf902a6389f736
+  languageName: node
+  linkType: hard
+
+"lodash@npm:^4.17.20, lodash@npm:^4.17.21":
+  version: 4.17.21
+  resolution: "lodash@npm:4.17.21"
+  languageName: node
+  linkType: hard
+
+"ms@npm:2.1.2":
+  version: 2.1.2
+  resolution: "ms@npm:2.1.2"
+  languageName: node
+  linkType: hard
+
+"my-app@workspace:.":
+  version: 0.0.0-use.local
+  resolution: "my-app@workspace:."
+  dependencies:
+    "@acme/utils": "workspace:*"
+    debug: "npm:4.3.4"
+    lodash: "npm:^4.17.20"
+  languageName: unknown
+  linkType: soft
+`
+
+func TestYarnLock_ParseBerry(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"yarn.lock":                   yarnBerryLock,
+		"packages/utils/package.json": `{"name": "@acme/utils", "version": "0.3.0"}`,
+	})
+
+	result, err := (&YarnLock{}).Parse(filepath.Join(dir, "yarn.lock"), deps.Options{})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	g := result.Graph
+
+	for _, id := range []string{"@acme/utils", "debug", "lodash"} {
+		if !g.HasEdge(deps.ProjectRootNodeID, id) {
+			t.Errorf("%s should be a direct dependency of the root workspace", id)
+		}
+	}
+	if !g.HasEdge("debug", "ms") || !g.HasEdge("@acme/utils", "lodash") {
+		t.Error("missing transitive edges")
+	}
+	if got := nodeVersion(t, g, "lodash"); got != "4.17.21" {
+		t.Errorf("lodash version = %q", got)
+	}
+	utils, _ := g.Node("@acme/utils")
+	if utils.Meta["path"] != "packages/utils" || utils.Meta["version"] != "0.3.0" {
+		t.Errorf("workspace meta = %v", utils.Meta)
+	}
+	if _, ok := g.Node("my-app"); ok {
+		t.Error("the root workspace should not be a package node")
+	}
+	for _, e := range g.Edges() {
+		if e.From == "debug" && e.Meta["constraint"] != "2.1.2" {
+			t.Errorf("debug -> ms constraint = %v, want the npm: protocol stripped", e.Meta["constraint"])
+		}
+	}
+}
+
+func TestSplitDescriptor(t *testing.T) {
+	tests := []struct{ in, name, ref string }{
+		{"lodash@^4.17.0", "lodash", "^4.17.0"},
+		{"@babel/core@npm:7.0.0", "@babel/core", "npm:7.0.0"},
+		{"sw@npm:string-width@^4.2.0", "sw", "npm:string-width@^4.2.0"},
+		{"lodash", "lodash", ""},
+	}
+	for _, tt := range tests {
+		if name, ref := splitDescriptor(tt.in); name != tt.name || ref != tt.ref {
+			t.Errorf("splitDescriptor(%q) = %q, %q; want %q, %q", tt.in, name, ref, tt.name, tt.ref)
+		}
+	}
+}
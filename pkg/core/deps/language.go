@@ -2,6 +2,8 @@ package deps
 
 import (
 	"fmt"
+	"path"
+	"strings"
 
 	"github.com/stacktower-io/stacktower/pkg/cache"
 )
@@ -39,9 +41,17 @@ type Language struct {
 
 	// ManifestAliases maps filenames to manifest types. For example,
 	// {"poetry.lock": "poetry", "requirements.txt": "requirements"}.
+	// Keys may be glob patterns for manifests named after their project,
+	// like {"*.csproj": "csproj"}; see [Language.ManifestType].
 	// Used by DetectManifest to match file paths. May be nil or empty.
 	ManifestAliases map[string]string
 
+	// SniffManifest recognizes manifests by content, for [DetectManifests]:
+	// it may claim files no alias names, like "requirements-dev.txt", or
+	// lower the confidence of a file whose name matches but whose content
+	// belongs elsewhere. May be nil to match on filenames alone.
+	SniffManifest ManifestSniffer
+
 	// NewResolver creates a registry Resolver with the given cache and options.
 	// The cache is used for HTTP response caching (use cache.NullCache{} for no caching).
 	// Options provides language-specific configuration (e.g., RuntimeVersion for Python).
@@ -110,10 +120,33 @@ func (l *Language) Manifest(name string, res Resolver) (ManifestParser, bool) {
 	if l.NewManifest == nil {
 		return nil, false
 	}
-	p := l.NewManifest(l.alias(l.ManifestAliases, name), res)
+	if typ, ok := l.ManifestType(name); ok {
+		name = typ
+	}
+	p := l.NewManifest(name, res)
 	return p, p != nil
 }
 
+// ManifestType returns the manifest type of a filename through
+// ManifestAliases: an exact key first, then a glob pattern key (see
+// [path.Match]). It returns false when no alias matches.
+//
+// Safe to call on a zero Language value.
+func (l *Language) ManifestType(filename string) (string, bool) {
+	if typ, ok := l.ManifestAliases[filename]; ok {
+		return typ, true
+	}
+	for pattern, typ := range l.ManifestAliases {
+		if !strings.ContainsAny(pattern, "*?[") {
+			continue
+		}
+		if ok, _ := path.Match(pattern, filename); ok {
+			return typ, true
+		}
+	}
+	return "", false
+}
+
 // HasManifests reports whether this language supports manifest file parsing.
 //
 // Returns true if NewManifest is non-nil, meaning at least one manifest
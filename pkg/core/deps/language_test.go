@@ -186,6 +186,43 @@ func TestLanguageManifest(t *testing.T) {
 	}
 }
 
+func TestLanguageManifestType(t *testing.T) {
+	lang := &Language{
+		Name: "test",
+		ManifestAliases: map[string]string{
+			"packages.config": "config",
+			"*.csproj":        "project",
+			"special.csproj":  "special",
+		},
+	}
+
+	tests := []struct {
+		filename string
+		want     string
+		wantOK   bool
+	}{
+		{"packages.config", "config", true},
+		{"MyApp.csproj", "project", true},
+		{"special.csproj", "special", true}, // exact keys win over patterns
+		{"dir/MyApp.csproj", "", false},     // patterns match base names only
+		{"MyApp.csproj.user", "", false},
+		{"unknown", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			got, ok := lang.ManifestType(tt.filename)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ManifestType(%q) = %q, %v, want %q, %v", tt.filename, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if got := GetManifestLanguage("MyApp.csproj", []*Language{lang}); got != "test" {
+		t.Errorf("GetManifestLanguage() = %q, want test", got)
+	}
+}
+
 func TestLanguageManifestNilFactory(t *testing.T) {
 	lang := &Language{
 		Name:        "test",
@@ -14,7 +14,10 @@
 package languages
 
 import (
+	"fmt"
+
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/dotnet"
 	"github.com/stacktower-io/stacktower/pkg/core/deps/golang"
 	"github.com/stacktower-io/stacktower/pkg/core/deps/java"
 	"github.com/stacktower-io/stacktower/pkg/core/deps/javascript"
@@ -34,9 +37,29 @@ var All = []*deps.Language{
 	php.Language,
 	java.Language,
 	golang.Language,
+	dotnet.Language,
 }
 
 // Find returns the Language with the given name, or nil if not found.
 func Find(name string) *deps.Language {
 	return deps.FindLanguage(name, All)
 }
+
+// Register adds a language defined outside this module, such as one
+// provided by a plugin, to All. It must be called before All is read
+// concurrently, typically at startup. Names must not clash with
+// registered languages, nor may manifest filenames unless the language
+// has a SniffManifest to tell its files apart (see [deps.DetectManifests]).
+func Register(lang *deps.Language) error {
+	if Find(lang.Name) != nil {
+		return fmt.Errorf("language %q is already registered", lang.Name)
+	}
+	supported := deps.SupportedManifests(All)
+	for filename := range lang.ManifestAliases {
+		if other, ok := supported[filename]; ok && lang.SniffManifest == nil {
+			return fmt.Errorf("manifest %s is already handled by %s", filename, other)
+		}
+	}
+	All = append(All, lang)
+	return nil
+}
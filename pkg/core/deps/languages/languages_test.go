@@ -0,0 +1,92 @@
+package languages
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+)
+
+func TestRegister(t *testing.T) {
+	orig := All
+	t.Cleanup(func() { All = orig })
+	All = slices.Clone(orig)
+
+	cobol := &deps.Language{Name: "cobol", ManifestAliases: map[string]string{"copybook.lock": "copybook"}}
+	if err := Register(cobol); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if Find("cobol") != cobol {
+		t.Error("registered language not found")
+	}
+
+	for _, lang := range []*deps.Language{
+		{Name: "python"},
+		{Name: "cobol"},
+		{Name: "snake", ManifestAliases: map[string]string{"poetry.lock": "poetry"}},
+	} {
+		if err := Register(lang); err == nil {
+			t.Errorf("Register(%s) accepted a clash", lang.Name)
+		}
+	}
+
+	deno := &deps.Language{
+		Name:            "deno",
+		ManifestAliases: map[string]string{"package.json": "package"},
+		SniffManifest:   func(string, []byte) (string, float64) { return "", 0 },
+	}
+	if err := Register(deno); err != nil {
+		t.Errorf("Register() of a sniffing language sharing package.json: %v", err)
+	}
+}
+
+// fuzzManifests are the manifest filenames FuzzManifestParsers writes its
+// input to, one for each file a parser supports.
+var fuzzManifests = []string{
+	"Cargo.lock", "Cargo.toml", "Gemfile", "Gemfile.lock", "build.gradle",
+	"build.gradle.kts", "composer.json", "composer.lock", "go.mod",
+	"package-lock.json", "package.json", "poetry.lock", "pom.xml",
+	"pyproject.toml", "requirements.txt", "uv.lock",
+}
+
+// FuzzManifestParsers feeds arbitrary content to every manifest parser,
+// seeded with the example manifests. Parsers run without a resolver, so
+// no network requests are made; malformed input must produce an error,
+// never a panic or a nil result.
+func FuzzManifestParsers(f *testing.F) {
+	for i, name := range fuzzManifests {
+		data, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "examples", "manifest", name))
+		if err != nil {
+			continue
+		}
+		f.Add(uint8(i), data)
+	}
+	f.Add(uint8(slices.Index(fuzzManifests, "build.gradle")), []byte("dependencies {\n    implementation 'com.google.guava:guava:33.0.0-jre'\n}\n"))
+	f.Add(uint8(slices.Index(fuzzManifests, "build.gradle.kts")), []byte("dependencies {\n    implementation(\"org.slf4j:slf4j-api:2.0.9\")\n}\n"))
+
+	var parsers []deps.ManifestParser
+	for _, lang := range All {
+		if lang.ManifestParsers != nil {
+			parsers = append(parsers, lang.ManifestParsers(nil)...)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, i uint8, data []byte) {
+		name := fuzzManifests[int(i)%len(fuzzManifests)]
+		parser, err := deps.DetectManifest(name, parsers...)
+		if err != nil {
+			t.Fatalf("no parser for %s: %v", name, err)
+		}
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		result, err := parser.Parse(path, deps.Options{Ctx: context.Background()})
+		if err == nil && (result == nil || result.Graph == nil) {
+			t.Fatalf("%s: Parse returned no graph and no error", name)
+		}
+	})
+}
@@ -107,7 +107,8 @@ type ManifestResult struct {
 //	result, err := parser.Parse("poetry.lock", opts)
 //
 // Returns an error if no parser in the list supports the file. An empty
-// parsers list always returns an error.
+// parsers list always returns an error. To choose among languages, or
+// between parsers that claim the same file, use [DetectManifests].
 func DetectManifest(path string, parsers ...ManifestParser) (ManifestParser, error) {
 	name := filepath.Base(path)
 	for _, p := range parsers {
@@ -190,19 +191,15 @@ func SupportedManifests(languages []*Language) map[string]string {
 
 // IsManifestSupported checks if a manifest filename is supported by any of the languages.
 func IsManifestSupported(filename string, languages []*Language) bool {
-	for _, lang := range languages {
-		if _, ok := lang.ManifestAliases[filename]; ok {
-			return true
-		}
-	}
-	return false
+	return GetManifestLanguage(filename, languages) != ""
 }
 
 // GetManifestLanguage returns the language name for a manifest file, if supported.
+// Glob pattern aliases match too (see [Language.ManifestType]).
 // Returns empty string if the manifest is not supported.
 func GetManifestLanguage(filename string, languages []*Language) string {
 	for _, lang := range languages {
-		if _, ok := lang.ManifestAliases[filename]; ok {
+		if _, ok := lang.ManifestType(filename); ok {
 			return lang.Name
 		}
 	}
@@ -364,7 +361,7 @@ func ResolveAndMerge(ctx context.Context, resolver Resolver, dependencies []Depe
 
 	for _, res := range collected {
 		if res.err != nil {
-			opts.Logger("resolve failed: %s: %v", res.dep.Name, res.err)
+			opts.Logger.Warn("resolve failed", "package", res.dep.Name, "err", res.err)
 			_ = merged.AddNode(dag.Node{ID: res.dep.Name})
 			addEdge(dag.Edge{From: ProjectRootNodeID, To: res.dep.Name, Meta: buildEdgeMeta(res.dep)})
 			continue
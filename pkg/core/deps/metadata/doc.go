@@ -26,6 +26,12 @@
 // The provider automatically extracts GitHub URLs from package metadata
 // (ProjectURLs, Repository, HomePage) or falls back to GitHub search.
 //
+// The provider watches the X-RateLimit-* headers of GitHub's responses.
+// When the quota left cannot cover every package, it enriches the packages
+// with the most dependents first and skips the rest, which
+// [GitHub.Skipped] lists; the pipeline then records "enrichment": "partial"
+// in the graph metadata.
+//
 // # Metadata Keys
 //
 // Enriched data is stored in node metadata using these standard keys:
@@ -35,11 +41,49 @@
 //   - [RepoStars]: Star count
 //   - [RepoArchived]: Whether the repo is archived
 //   - [RepoMaintainers]: List of top contributor usernames
+//   - [RepoContributions]: Commit counts of those contributors, in the same
+//     order (only with contributor fetching enabled)
 //   - [RepoLastCommit]: Date of last commit (YYYY-MM-DD)
 //   - [RepoLastRelease]: Date of last release (YYYY-MM-DD)
+//   - [RepoCreated]: Date the repository was created (YYYY-MM-DD)
+//   - [RepoSuccessor]: URL of a maintained fork when the repository is archived
+//   - [Scorecard]: OpenSSF Scorecard score (0–10), when supplied externally
 //   - [RepoLanguage]: Primary repository language
 //   - [RepoTopics]: Repository topic tags
 //
+// # Icons
+//
+// [Icons] is a separate, opt-in pass run after enrichment. It downloads a
+// logo for each package (a registry-provided [IconURL], else the GitHub
+// avatar of [RepoOwner]) and stores it under [Icon] as a base64 data URI,
+// so rendered SVGs embed the image instead of linking to it:
+//
+//	icons := metadata.NewIcons(cache, 24*time.Hour)
+//	icons.EnrichGraph(ctx, g, 0, false)
+//
+// # Summaries
+//
+// [Summaries] is another pass run after enrichment. It stores a short,
+// plain-text [Summary] on every node for popups: the repository or registry
+// description, else the first paragraph of the repository's README, with
+// badges, headings and markup stripped:
+//
+//	summaries := metadata.NewSummaries(cache, token, 24*time.Hour)
+//	summaries.EnrichGraph(ctx, g, 0, false)
+//
+// # Local Provider
+//
+// [Local] reads the metadata of dependencies vendored or installed in the
+// project — node_modules, vendor, a virtual environment's site-packages,
+// git submodules — from their files, and records their directory under
+// [LocalPath]. [LocalFirst] puts it in front of the network providers and
+// [LocalURLs] in front of the registry URL lookup, so that only packages
+// missing on disk are fetched:
+//
+//	local := metadata.NewLocal(projectDir)
+//	opts.MetadataProviders = []deps.MetadataProvider{metadata.LocalFirst(local, github)}
+//	opts.URLProvider = metadata.LocalURLs(local, opts.URLProvider)
+//
 // # Composite Provider
 //
 // [Composite] combines multiple providers, merging their results:
@@ -1,7 +1,12 @@
 package metadata
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/stacktower-io/stacktower/pkg/cache"
@@ -10,12 +15,41 @@ import (
 	"github.com/stacktower-io/stacktower/pkg/integrations/github"
 )
 
-// Compile-time check that GitHub implements BatchMetadataProvider.
-var _ deps.BatchMetadataProvider = (*GitHub)(nil)
+// Compile-time checks that GitHub implements BatchMetadataProvider and
+// PartialProvider.
+var (
+	_ deps.BatchMetadataProvider = (*GitHub)(nil)
+	_ deps.PartialProvider       = (*GitHub)(nil)
+)
+
+// quotaReserve is the part of each GitHub rate limit that enrichment leaves
+// alone, so that the other requests of the session, such as fetching
+// manifests from GitHub, still go through.
+const quotaReserve = 10
+
+// restCallsPerRepo is what [github.Client.Fetch] costs at most: the
+// repository, its latest release, its contributors and, if it is
+// archived, its forks.
+const restCallsPerRepo = 4
 
+// errGraphQLQuota makes callers of EnrichBatch fall back to per-package
+// enrichment, which uses the REST quota instead.
+var errGraphQLQuota = errors.New("github graphql rate limit exhausted")
+
+// GitHub enriches packages with the metrics of their GitHub repositories.
+//
+// It keeps within the GitHub rate limits, as the X-RateLimit-* headers
+// report them: when the quota left cannot cover every package, the
+// packages with the most dependents ([deps.PackageRef.Dependents]) are
+// enriched first and the rest are skipped, and listed by Skipped.
 type GitHub struct {
 	client            *github.Client
 	fetchContributors bool
+
+	quotaOnce sync.Once
+	mu        sync.Mutex
+	inFlight  map[string]int // requests granted to calls not done yet, by resource
+	skipped   map[string]bool
 }
 
 // GitHubOption configures the GitHub metadata provider.
@@ -39,12 +73,84 @@ func NewGitHub(backend cache.Cache, token string, cacheTTL time.Duration, opts .
 
 func (g *GitHub) Name() string { return "github" }
 
+// Skipped returns the packages left out of enrichment, or enriched without
+// their contributors or successor, to stay within the rate limits.
+func (g *GitHub) Skipped() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return slices.Sorted(maps.Keys(g.skipped))
+}
+
+func (g *GitHub) skip(names ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.skipped == nil {
+		g.skipped = make(map[string]bool)
+	}
+	for _, name := range names {
+		g.skipped[name] = true
+	}
+}
+
+// loadQuota reads the rate limits once, before the first metered request.
+// If that fails they stay unknown, and enrichment unbudgeted, until
+// responses report them.
+func (g *GitHub) loadQuota(ctx context.Context) {
+	g.quotaOnce.Do(func() { _ = g.client.FetchQuota(ctx) })
+}
+
+// spare returns how many requests of resource the quota has left beyond
+// the reserve and the requests granted to calls in flight, or -1 when the
+// quota is unknown.
+func (g *GitHub) spare(resource string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.spareLocked(resource)
+}
+
+func (g *GitHub) spareLocked(resource string) int {
+	q, ok := g.client.Quota(resource)
+	if !ok {
+		return -1
+	}
+	return max(0, q.Remaining-quotaReserve-g.inFlight[resource])
+}
+
+// acquire grants n requests of resource to a call, reporting false when
+// the quota cannot afford them. The call gives them back with release once
+// it is done, by when the quota accounts for them.
+func (g *GitHub) acquire(resource string, n int) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if spare := g.spareLocked(resource); spare >= 0 && spare < n {
+		return false
+	}
+	if g.inFlight == nil {
+		g.inFlight = make(map[string]int)
+	}
+	g.inFlight[resource] += n
+	return true
+}
+
+func (g *GitHub) release(resource string, n int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.inFlight[resource] -= n
+}
+
 func (g *GitHub) Enrich(ctx context.Context, pkg *deps.PackageRef, refresh bool) (map[string]any, error) {
 	owner, name, ok := github.ExtractURL(pkg.ProjectURLs, pkg.HomePage)
 	if !ok {
 		return nil, nil
 	}
 
+	g.loadQuota(ctx)
+	if !g.acquire(github.ResourceCore, restCallsPerRepo) {
+		g.skip(pkg.Name)
+		return nil, nil
+	}
+	defer g.release(github.ResourceCore, restCallsPerRepo)
+
 	m, err := g.client.Fetch(ctx, owner, name, refresh)
 	if err != nil {
 		return nil, err
@@ -58,7 +164,16 @@ func (g *GitHub) Enrich(ctx context.Context, pkg *deps.PackageRef, refresh bool)
 // are enriched. Packages without discoverable URLs are silently skipped - we don't
 // use SearchPackageRepo here because it's too slow/rate-limited for batch operations.
 // If WithContributors() was used, additional REST API calls fetch contributor data.
+//
+// When the quota left does not cover every repository, the packages with
+// the most dependents are served first. If the GraphQL quota affords no
+// query at all, EnrichBatch fails so that callers fall back to [GitHub.Enrich].
 func (g *GitHub) EnrichBatch(ctx context.Context, pkgs []*deps.PackageRef, refresh bool) (map[string]map[string]any, error) {
+	pkgs = slices.Clone(pkgs)
+	slices.SortStableFunc(pkgs, func(a, b *deps.PackageRef) int {
+		return cmp.Compare(b.Dependents, a.Dependents)
+	})
+
 	// Phase 1: resolve each package to a GitHub owner/repo
 	type resolved struct {
 		pkg  *deps.PackageRef
@@ -87,16 +202,41 @@ func (g *GitHub) EnrichBatch(ctx context.Context, pkgs []*deps.PackageRef, refre
 		return nil, nil
 	}
 
-	// Phase 2: single batched GraphQL fetch for all repos
+	// Repos the quota cannot afford, in full or in part, in priority order
+	g.loadQuota(ctx)
+	short := make(map[string]bool)
+	defer func() {
+		for _, rp := range resolvedPkgs {
+			if short[rp.repo.Key()] {
+				g.skip(rp.pkg.Name)
+			}
+		}
+	}()
+	afford := func(repos []github.RepoID, n int) []github.RepoID {
+		if n < 0 || n >= len(repos) {
+			return repos
+		}
+		for _, r := range repos[n:] {
+			short[r.Key()] = true
+		}
+		return repos[:n]
+	}
+
+	// Phase 2: batched GraphQL fetch, one query per MaxReposPerQuery repos
+	if spare := g.spare(github.ResourceGraphQL); spare == 0 {
+		return nil, errGraphQLQuota
+	} else if spare > 0 {
+		repoList = afford(repoList, spare*github.MaxReposPerQuery)
+	}
 	metrics, err := g.client.FetchBatch(ctx, repoList, refresh)
 	if err != nil {
 		return nil, err
 	}
 
-	// Phase 2.5: optionally fetch contributors via REST API
+	// Phase 2.5: optionally fetch contributors via REST API, one request per repo
 	var contributors map[string][]integrations.Contributor
 	if g.fetchContributors {
-		contributors = g.client.FetchContributorsBatch(ctx, repoList)
+		contributors = g.client.FetchContributorsBatch(ctx, afford(repoList, g.spare(github.ResourceCore)))
 		// Merge contributors into metrics
 		for key, contribs := range contributors {
 			if m, ok := metrics[key]; ok {
@@ -105,6 +245,19 @@ func (g *GitHub) EnrichBatch(ctx context.Context, pkgs []*deps.PackageRef, refre
 		}
 	}
 
+	// Phase 2.75: suggest maintained forks for archived repos, one request each
+	var archived []github.RepoID
+	for _, repo := range repoList {
+		if m, ok := metrics[repo.Key()]; ok && m.Archived {
+			archived = append(archived, repo)
+		}
+	}
+	successors := make(map[string]*integrations.RepoMetrics, len(archived))
+	for _, repo := range afford(archived, g.spare(github.ResourceCore)) {
+		successors[repo.Key()] = metrics[repo.Key()]
+	}
+	g.client.FetchSuccessors(ctx, successors)
+
 	// Phase 3: map results back to package names
 	result := make(map[string]map[string]any, len(resolvedPkgs))
 	for _, rp := range resolvedPkgs {
@@ -145,12 +298,21 @@ func metricsToMap(m *integrations.RepoMetrics) map[string]any {
 	if m.LastReleaseAt != nil {
 		result[RepoLastRelease] = m.LastReleaseAt.Format("2006-01-02")
 	}
+	if m.CreatedAt != nil {
+		result[RepoCreated] = m.CreatedAt.Format("2006-01-02")
+	}
+	if m.Successor != "" {
+		result[RepoSuccessor] = m.Successor
+	}
 	if len(m.Contributors) > 0 {
 		maintainers := make([]string, len(m.Contributors))
+		contributions := make([]int, len(m.Contributors))
 		for i, c := range m.Contributors {
 			maintainers[i] = c.Login
+			contributions[i] = c.Contributions
 		}
 		result[RepoMaintainers] = maintainers
+		result[RepoContributions] = contributions
 	}
 	return result
 }
@@ -0,0 +1,121 @@
+package metadata
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+const (
+	// Icon is the node metadata key holding a package icon as a base64
+	// data URI, ready to embed in SVG output.
+	Icon = "icon"
+	// IconURL is the node metadata key a registry can set to point at the
+	// package's own logo. It takes precedence over the owner avatar.
+	IconURL = "icon_url"
+
+	iconPixels  = 64        // Requested avatar size; blocks draw icons at ≤20px
+	maxIconSize = 64 * 1024 // Icons larger than this are skipped to keep SVGs small
+)
+
+// Icons fetches package logos and GitHub owner avatars and stores them on
+// graph nodes as data URIs. Downloads go through the shared cache, so each
+// avatar is fetched once per TTL no matter how many graphs use it.
+//
+// It runs after [GitHub] enrichment, which provides the [RepoOwner] used to
+// find avatars.
+type Icons struct {
+	client *integrations.Client
+}
+
+// NewIcons creates an icon fetcher backed by the given cache.
+func NewIcons(backend cache.Cache, cacheTTL time.Duration) *Icons {
+	return &Icons{client: integrations.NewClient(backend, "icons:", cacheTTL, nil)}
+}
+
+// SourceURL returns where to fetch a node's icon from: the registry-provided
+// [IconURL] if present, else the GitHub avatar of the repository owner.
+// It returns "" when neither is known.
+func SourceURL(n *dag.Node) string {
+	if n == nil || n.Meta == nil {
+		return ""
+	}
+	if u, ok := n.Meta[IconURL].(string); ok && strings.HasPrefix(u, "https://") {
+		return u
+	}
+	if owner, ok := n.Meta[RepoOwner].(string); ok && owner != "" {
+		return fmt.Sprintf("https://github.com/%s.png?size=%d", url.PathEscape(owner), iconPixels)
+	}
+	return ""
+}
+
+// EnrichGraph sets [Icon] on every node with a known icon source and
+// returns how many nodes received one. Failed downloads are skipped; icons
+// are decorative and never fail a parse. Zero or negative workers use
+// [deps.DefaultWorkers].
+func (i *Icons) EnrichGraph(ctx context.Context, g *dag.DAG, workers int, refresh bool) int {
+	var nodes []*dag.Node
+	for _, n := range g.Nodes() {
+		if SourceURL(n) != "" {
+			nodes = append(nodes, n)
+		}
+	}
+
+	if workers <= 0 {
+		workers = deps.DefaultWorkers
+	}
+	uris := deps.ParallelMapOrdered(ctx, workers, nodes, func(ctx context.Context, n *dag.Node) string {
+		uri, err := i.Fetch(ctx, SourceURL(n), refresh)
+		if err != nil {
+			return ""
+		}
+		return uri
+	})
+
+	count := 0
+	for idx, uri := range uris {
+		if uri != "" {
+			nodes[idx].Meta[Icon] = uri
+			count++
+		}
+	}
+	return count
+}
+
+// Fetch downloads an image and returns it as a data URI. Only PNG, JPEG,
+// GIF and WebP images up to 64 KiB are accepted.
+func (i *Icons) Fetch(ctx context.Context, src string, refresh bool) (string, error) {
+	var uri string
+	err := i.client.Cached(ctx, src, refresh, &uri, func() error {
+		body, err := i.client.GetText(ctx, src)
+		if err != nil {
+			return err
+		}
+		uri, err = DataURI([]byte(body))
+		return err
+	})
+	return uri, err
+}
+
+// DataURI encodes raw image bytes as a data URI after checking their type
+// and size.
+func DataURI(data []byte) (string, error) {
+	if len(data) > maxIconSize {
+		return "", fmt.Errorf("icon too large: %d bytes", len(data))
+	}
+	switch mime := http.DetectContentType(data); mime {
+	case "image/png", "image/jpeg", "image/gif", "image/webp":
+		return "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+	default:
+		return "", fmt.Errorf("unsupported icon type %s", mime)
+	}
+}
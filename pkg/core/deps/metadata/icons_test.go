@@ -0,0 +1,70 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+// pngHeader is enough of a PNG for content sniffing.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestSourceURL(t *testing.T) {
+	tests := []struct {
+		name string
+		meta dag.Metadata
+		want string
+	}{
+		{"registry logo wins", dag.Metadata{IconURL: "https://cdn.example/logo.png", RepoOwner: "pallets"}, "https://cdn.example/logo.png"},
+		{"owner avatar", dag.Metadata{RepoOwner: "pallets"}, "https://github.com/pallets.png?size=64"},
+		{"insecure logo ignored", dag.Metadata{IconURL: "http://cdn.example/logo.png"}, ""},
+		{"nothing known", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SourceURL(&dag.Node{Meta: tt.meta}); got != tt.want {
+				t.Errorf("SourceURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDataURI(t *testing.T) {
+	uri, err := DataURI(pngHeader)
+	if err != nil || !strings.HasPrefix(uri, "data:image/png;base64,") {
+		t.Errorf("DataURI(png) = %q, %v", uri, err)
+	}
+	if _, err := DataURI([]byte("<html></html>")); err == nil {
+		t.Error("DataURI should reject non-images")
+	}
+	if _, err := DataURI(append(pngHeader, make([]byte, maxIconSize)...)); err == nil {
+		t.Error("DataURI should reject oversized icons")
+	}
+}
+
+func TestIcons_FetchCaches(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write(pngHeader)
+	}))
+	defer srv.Close()
+
+	c, _ := cache.NewFileCache(t.TempDir())
+	icons := NewIcons(c, time.Hour)
+	for range 2 {
+		uri, err := icons.Fetch(context.Background(), srv.URL+"/logo.png", false)
+		if err != nil || !strings.HasPrefix(uri, "data:image/png;base64,") {
+			t.Fatalf("Fetch() = %q, %v", uri, err)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("server hit %d times, want 1 (second fetch should be cached)", hits)
+	}
+}
@@ -0,0 +1,492 @@
+package metadata
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+// Compile-time checks of the local providers.
+var (
+	_ deps.MetadataProvider      = (*Local)(nil)
+	_ deps.BatchMetadataProvider = (*localFirst)(nil)
+	_ deps.URLProvider           = (*localURLs)(nil)
+)
+
+// LocalPath is the node metadata key of the directory a package is
+// vendored or installed in, relative to the project.
+const LocalPath = "local_path"
+
+// licenseFiles are the file names a license is looked for under, in order.
+var licenseFiles = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "LICENCE.md", "COPYING", "license", "license.md"}
+
+// venvDirs are the virtual environment directories searched for
+// site-packages, relative to the project.
+var venvDirs = []string{".venv", "venv", "env"}
+
+// pep503RE matches the separators PEP 503 folds in Python package names.
+var pep503RE = regexp.MustCompile(`[-_.]+`)
+
+// Local reads metadata of dependencies that are vendored or installed
+// inside a project, so that enrichment needs no network for them:
+//
+//   - node_modules/<name>/package.json
+//   - vendor/<name>/ (Go modules and Composer packages, with composer.json)
+//   - site-packages/<name>-<version>.dist-info/METADATA in a virtual environment
+//   - git submodules listed in .gitmodules, matched by their directory name
+//
+// It reports the license, description, author, homepage and repository
+// found there, the license file's text when no license is declared, the
+// submodule commit, and [LocalPath]. Packages not found locally get no
+// metadata.
+//
+// Lookups are cached; Local is safe for concurrent use.
+type Local struct {
+	dir string
+
+	cache sync.Map // name -> map[string]any (nil when not found)
+
+	once        sync.Once
+	distInfo    map[string]string // normalized Python name -> .dist-info directory
+	submodules  map[string]string // lowercase directory name -> submodule path
+	submoduleTo map[string]string // submodule path -> URL
+}
+
+// NewLocal creates a provider reading dependencies installed under dir,
+// the project directory.
+func NewLocal(dir string) *Local {
+	return &Local{dir: dir}
+}
+
+func (l *Local) Name() string { return "local" }
+
+func (l *Local) Enrich(ctx context.Context, pkg *deps.PackageRef, refresh bool) (map[string]any, error) {
+	m, _ := l.Lookup(pkg.Name)
+	return m, nil
+}
+
+// Lookup returns the local metadata of a package, and whether the package
+// is vendored or installed in the project. The returned map must not be
+// modified.
+func (l *Local) Lookup(name string) (map[string]any, bool) {
+	if v, ok := l.cache.Load(name); ok {
+		m, _ := v.(map[string]any)
+		return m, m != nil
+	}
+	m := l.lookup(name)
+	l.cache.Store(name, m)
+	return m, m != nil
+}
+
+func (l *Local) lookup(name string) map[string]any {
+	l.once.Do(l.index)
+	if name == "" || name == deps.ProjectRootNodeID || strings.Contains(name, "..") {
+		return nil
+	}
+
+	if dir := filepath.Join(l.dir, "node_modules", filepath.FromSlash(name)); isFile(filepath.Join(dir, "package.json")) {
+		m := l.found(dir)
+		readPackageJSON(filepath.Join(dir, "package.json"), m)
+		return l.withLicenseFile(dir, m)
+	}
+	if dir := filepath.Join(l.dir, "vendor", filepath.FromSlash(name)); isDir(dir) {
+		m := l.found(dir)
+		readComposerJSON(filepath.Join(dir, "composer.json"), m)
+		return l.withLicenseFile(dir, m)
+	}
+	if info, ok := l.distInfo[normalizePythonName(name)]; ok {
+		m := l.found(info)
+		readPythonMetadata(filepath.Join(info, "METADATA"), m)
+		return l.withLicenseFile(info, m)
+	}
+
+	base := name
+	if i := strings.LastIndexAny(base, "/:"); i >= 0 {
+		base = base[i+1:]
+	}
+	if sub, ok := l.submodules[strings.ToLower(base)]; ok {
+		dir := filepath.Join(l.dir, filepath.FromSlash(sub))
+		m := l.found(dir)
+		if url := integrations.NormalizeRepoURL(l.submoduleTo[sub]); url != "" {
+			m[RepoURL] = url
+		}
+		if commit := submoduleCommit(dir); commit != "" {
+			m["commit"] = commit
+		}
+		return l.withLicenseFile(dir, m)
+	}
+	return nil
+}
+
+// found starts the metadata of a package found in dir.
+func (l *Local) found(dir string) map[string]any {
+	rel, err := filepath.Rel(l.dir, dir)
+	if err != nil {
+		rel = dir
+	}
+	return map[string]any{LocalPath: filepath.ToSlash(rel)}
+}
+
+// withLicenseFile adds the text of dir's license file when m declares no
+// license.
+func (l *Local) withLicenseFile(dir string, m map[string]any) map[string]any {
+	if _, ok := m["license"]; ok {
+		return m
+	}
+	for _, name := range licenseFiles {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			if text := strings.TrimSpace(string(data)); text != "" {
+				m["license_text"] = text
+			}
+			break
+		}
+	}
+	return m
+}
+
+// index finds the installed Python distributions and git submodules of
+// the project, which cannot be found from a package name directly.
+func (l *Local) index() {
+	l.distInfo = make(map[string]string)
+	for _, venv := range venvDirs {
+		patterns := []string{
+			filepath.Join(l.dir, venv, "lib", "python*", "site-packages", "*.dist-info"),
+			filepath.Join(l.dir, venv, "Lib", "site-packages", "*.dist-info"),
+		}
+		for _, pattern := range patterns {
+			matches, _ := filepath.Glob(pattern)
+			for _, info := range matches {
+				// "charset_normalizer-3.3.2.dist-info" -> "charset-normalizer"
+				dist, _, _ := strings.Cut(strings.TrimSuffix(filepath.Base(info), ".dist-info"), "-")
+				if _, ok := l.distInfo[normalizePythonName(dist)]; !ok {
+					l.distInfo[normalizePythonName(dist)] = info
+				}
+			}
+		}
+	}
+
+	l.submodules = make(map[string]string)
+	l.submoduleTo = make(map[string]string)
+	for path, url := range readGitmodules(filepath.Join(l.dir, ".gitmodules")) {
+		l.submodules[strings.ToLower(filepath.Base(path))] = path
+		l.submoduleTo[path] = url
+	}
+}
+
+func normalizePythonName(name string) string {
+	return pep503RE.ReplaceAllString(strings.ToLower(name), "-")
+}
+
+// readPackageJSON adds the metadata of an installed npm package.
+func readPackageJSON(path string, m map[string]any) {
+	var pkg struct {
+		Description string          `json:"description"`
+		License     json.RawMessage `json:"license"`
+		Author      json.RawMessage `json:"author"`
+		Homepage    string          `json:"homepage"`
+		Repository  json.RawMessage `json:"repository"`
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &pkg) != nil {
+		return
+	}
+	set(m, "description", pkg.Description)
+	set(m, "license", stringOrField(pkg.License, "type"))
+	set(m, "author", stringOrField(pkg.Author, "name"))
+	set(m, HomePage, pkg.Homepage)
+	set(m, RepoURL, integrations.NormalizeRepoURL(stringOrField(pkg.Repository, "url")))
+}
+
+// readComposerJSON adds the metadata of a vendored Composer package, if
+// the directory has a composer.json.
+func readComposerJSON(path string, m map[string]any) {
+	var pkg struct {
+		Description string          `json:"description"`
+		License     json.RawMessage `json:"license"`
+		Homepage    string          `json:"homepage"`
+		Authors     []struct {
+			Name string `json:"name"`
+		} `json:"authors"`
+		Support struct {
+			Source string `json:"source"`
+		} `json:"support"`
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &pkg) != nil {
+		return
+	}
+	set(m, "description", pkg.Description)
+	var licenses []string
+	if json.Unmarshal(pkg.License, &licenses) == nil {
+		set(m, "license", strings.Join(licenses, " OR "))
+	} else {
+		set(m, "license", stringOrField(pkg.License, ""))
+	}
+	if len(pkg.Authors) > 0 {
+		set(m, "author", pkg.Authors[0].Name)
+	}
+	set(m, HomePage, pkg.Homepage)
+	set(m, RepoURL, integrations.NormalizeRepoURL(pkg.Support.Source))
+}
+
+// readPythonMetadata adds the metadata of an installed Python
+// distribution from the headers of its METADATA file.
+func readPythonMetadata(path string, m map[string]any) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	var license, licenseExpr, repo string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break // the description body follows the headers
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.HasPrefix(line, " ") {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(key) {
+		case "summary":
+			set(m, "description", value)
+		case "author":
+			set(m, "author", value)
+		case "home-page":
+			set(m, HomePage, value)
+		case "license":
+			license = value
+		case "license-expression":
+			licenseExpr = value
+		case "project-url":
+			label, url, ok := strings.Cut(value, ",")
+			label = strings.ToLower(strings.TrimSpace(label))
+			if ok && repo == "" && (label == "source" || label == "repository" || label == "source code") {
+				repo = strings.TrimSpace(url)
+			}
+		}
+	}
+	if licenseExpr != "" {
+		license = licenseExpr
+	}
+	// Long license texts are pasted into the License field by some
+	// projects; keep only identifiers.
+	if !strings.Contains(license, "\n") && len(license) <= 64 {
+		set(m, "license", license)
+	}
+	set(m, RepoURL, integrations.NormalizeRepoURL(repo))
+}
+
+// readGitmodules returns the submodules of a .gitmodules file, path to
+// URL.
+func readGitmodules(path string) map[string]string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	out := make(map[string]string)
+	var subPath, subURL string
+	flush := func() {
+		if subPath != "" && subURL != "" {
+			out[subPath] = subURL
+		}
+		subPath, subURL = "", ""
+	}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[submodule") {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "path":
+			subPath = strings.TrimSpace(value)
+		case "url":
+			subURL = strings.TrimSpace(value)
+		}
+	}
+	flush()
+	return out
+}
+
+// submoduleCommit returns the commit checked out in a submodule, read
+// from its git directory, or "" when it cannot be read.
+func submoduleCommit(dir string) string {
+	gitDir := filepath.Join(dir, ".git")
+	if data, err := os.ReadFile(gitDir); err == nil {
+		// A submodule's .git is a file pointing into the parent's .git/modules
+		rel, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+		if !ok {
+			return ""
+		}
+		gitDir = strings.TrimSpace(rel)
+		if !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(dir, gitDir)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	head := strings.TrimSpace(string(data))
+	if ref, ok := strings.CutPrefix(head, "ref:"); ok {
+		data, err := os.ReadFile(filepath.Join(gitDir, filepath.FromSlash(strings.TrimSpace(ref))))
+		if err != nil {
+			return ""
+		}
+		head = strings.TrimSpace(string(data))
+	}
+	return head
+}
+
+// stringOrField reads a JSON value that is either a string or an object
+// with the string in field, like npm's "author" and "repository".
+func stringOrField(raw json.RawMessage, field string) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var obj map[string]any
+	if field != "" && json.Unmarshal(raw, &obj) == nil {
+		s, _ := obj[field].(string)
+		return s
+	}
+	return ""
+}
+
+func set(m map[string]any, key, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		m[key] = value
+	}
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// LocalFirst returns a provider that enriches the packages local finds
+// from their installed files and only the rest through providers, so that
+// vendored dependencies cause no network calls. Batch providers among
+// providers still get one batch call.
+func LocalFirst(local *Local, providers ...deps.MetadataProvider) deps.BatchMetadataProvider {
+	return &localFirst{local: local, providers: providers}
+}
+
+type localFirst struct {
+	local     *Local
+	providers []deps.MetadataProvider
+}
+
+func (p *localFirst) Name() string { return "local" }
+
+func (p *localFirst) Enrich(ctx context.Context, pkg *deps.PackageRef, refresh bool) (map[string]any, error) {
+	if m, ok := p.local.Lookup(pkg.Name); ok {
+		return m, nil
+	}
+	return NewComposite(p.providers...).Enrich(ctx, pkg, refresh)
+}
+
+// Skipped returns the packages the providers left out of enrichment.
+func (p *localFirst) Skipped() []string { return deps.SkippedPackages(p.providers) }
+
+// EnrichBatch enriches local packages from disk, then the others with
+// each provider, in one batch call where the provider supports it. A
+// failing batch call fails the whole batch, so the caller can fall back
+// to per-package enrichment.
+func (p *localFirst) EnrichBatch(ctx context.Context, pkgs []*deps.PackageRef, refresh bool) (map[string]map[string]any, error) {
+	out := make(map[string]map[string]any, len(pkgs))
+	var rest []*deps.PackageRef
+	for _, pkg := range pkgs {
+		if m, ok := p.local.Lookup(pkg.Name); ok {
+			out[pkg.Name] = m
+		} else {
+			rest = append(rest, pkg)
+		}
+	}
+	if len(rest) == 0 {
+		return out, nil
+	}
+
+	merge := func(name string, meta map[string]any) {
+		if len(meta) == 0 {
+			return
+		}
+		if out[name] == nil {
+			out[name] = make(map[string]any, len(meta))
+		}
+		for k, v := range meta {
+			out[name][k] = v
+		}
+	}
+	for _, provider := range p.providers {
+		if bp, ok := provider.(deps.BatchMetadataProvider); ok {
+			batch, err := bp.EnrichBatch(ctx, rest, refresh)
+			if err != nil {
+				return nil, err
+			}
+			for name, meta := range batch {
+				merge(name, meta)
+			}
+			continue
+		}
+		metas := deps.ParallelMapOrdered(ctx, deps.DefaultWorkers, rest, func(ctx context.Context, pkg *deps.PackageRef) map[string]any {
+			meta, err := provider.Enrich(ctx, pkg, refresh)
+			if err != nil {
+				return nil
+			}
+			return meta
+		})
+		for i, meta := range metas {
+			merge(rest[i].Name, meta)
+		}
+	}
+	return out, nil
+}
+
+// LocalURLs wraps a URL provider so that it skips the packages local
+// finds: their URLs come from their installed files instead.
+func LocalURLs(local *Local, next deps.URLProvider) deps.URLProvider {
+	return &localURLs{local: local, next: next}
+}
+
+type localURLs struct {
+	local *Local
+	next  deps.URLProvider
+}
+
+func (p *localURLs) FetchURLs(ctx context.Context, names []string, refresh bool) (map[string]deps.PackageURLs, error) {
+	var remote []string
+	for _, name := range names {
+		if _, ok := p.local.Lookup(name); !ok {
+			remote = append(remote, name)
+		}
+	}
+	if len(remote) == 0 || p.next == nil {
+		return nil, nil
+	}
+	return p.next.FetchURLs(ctx, remote, refresh)
+}
@@ -0,0 +1,157 @@
+package metadata
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+)
+
+func writeLocalFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func localProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeLocalFile(t, filepath.Join(dir, "node_modules", "left-pad", "package.json"), `{
+		"name": "left-pad", "description": "String left pad", "license": "WTFPL",
+		"author": {"name": "azer"}, "repository": {"type": "git", "url": "git+https://github.com/stevemao/left-pad.git"}
+	}`)
+	writeLocalFile(t, filepath.Join(dir, "node_modules", "@scope", "util", "package.json"), `{"name": "@scope/util"}`)
+	writeLocalFile(t, filepath.Join(dir, "node_modules", "@scope", "util", "LICENSE"), "Custom license text\n")
+	writeLocalFile(t, filepath.Join(dir, "vendor", "github.com", "pkg", "errors", "LICENSE"), "BSD 2-Clause License")
+	writeLocalFile(t, filepath.Join(dir, "vendor", "monolog", "monolog", "composer.json"), `{
+		"description": "Sends your logs", "license": ["MIT"], "support": {"source": "https://github.com/Seldaek/monolog"}
+	}`)
+	writeLocalFile(t, filepath.Join(dir, ".venv", "lib", "python3.12", "site-packages", "charset_normalizer-3.3.2.dist-info", "METADATA"),
+		"Metadata-Version: 2.1\nName: charset-normalizer\nVersion: 3.3.2\nSummary: The Real First Universal Charset Detector.\n"+
+			"License: MIT\nProject-URL: Source, https://github.com/Ousret/charset_normalizer\n\nLicense: not a header\n")
+	writeLocalFile(t, filepath.Join(dir, ".gitmodules"), "[submodule \"third_party/zlib\"]\n\tpath = third_party/zlib\n\turl = https://github.com/madler/zlib.git\n")
+	writeLocalFile(t, filepath.Join(dir, ".git", "modules", "zlib", "HEAD"), "51b7f2abdade71cd9bb0e7a373ef2610ec6f9daf\n")
+	writeLocalFile(t, filepath.Join(dir, "third_party", "zlib", ".git"), "gitdir: ../../.git/modules/zlib\n")
+	return dir
+}
+
+func TestLocal_Lookup(t *testing.T) {
+	l := NewLocal(localProject(t))
+
+	tests := []struct {
+		name string
+		want map[string]any
+	}{
+		{"left-pad", map[string]any{
+			LocalPath: "node_modules/left-pad", "description": "String left pad", "license": "WTFPL",
+			"author": "azer", RepoURL: "https://github.com/stevemao/left-pad",
+		}},
+		{"@scope/util", map[string]any{LocalPath: "node_modules/@scope/util", "license_text": "Custom license text"}},
+		{"github.com/pkg/errors", map[string]any{LocalPath: "vendor/github.com/pkg/errors", "license_text": "BSD 2-Clause License"}},
+		{"monolog/monolog", map[string]any{
+			LocalPath: "vendor/monolog/monolog", "description": "Sends your logs", "license": "MIT",
+			RepoURL: "https://github.com/Seldaek/monolog",
+		}},
+		{"Charset.Normalizer", map[string]any{
+			LocalPath:     ".venv/lib/python3.12/site-packages/charset_normalizer-3.3.2.dist-info",
+			"description": "The Real First Universal Charset Detector.", "license": "MIT",
+			RepoURL: "https://github.com/Ousret/charset_normalizer",
+		}},
+		{"zlib", map[string]any{
+			LocalPath: "third_party/zlib", RepoURL: "https://github.com/madler/zlib",
+			"commit": "51b7f2abdade71cd9bb0e7a373ef2610ec6f9daf",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := l.Lookup(tt.name)
+			if !ok {
+				t.Fatalf("Lookup(%q) found nothing", tt.name)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("Lookup(%q)[%q] = %v, want %v", tt.name, k, got[k], v)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("Lookup(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	for _, name := range []string{"requests", "../secrets", ""} {
+		if m, ok := l.Lookup(name); ok {
+			t.Errorf("Lookup(%q) = %v, want nothing", name, m)
+		}
+	}
+}
+
+type batchProvider struct {
+	mockProvider
+	calls [][]string
+}
+
+func (b *batchProvider) EnrichBatch(ctx context.Context, pkgs []*deps.PackageRef, refresh bool) (map[string]map[string]any, error) {
+	var names []string
+	out := make(map[string]map[string]any)
+	for _, p := range pkgs {
+		names = append(names, p.Name)
+		out[p.Name] = map[string]any{RepoStars: 10}
+	}
+	b.calls = append(b.calls, names)
+	return out, nil
+}
+
+func TestLocalFirst_EnrichBatch(t *testing.T) {
+	l := NewLocal(localProject(t))
+	batch := &batchProvider{mockProvider: mockProvider{name: "github"}}
+	single := &mockProvider{name: "other", data: map[string]any{"other": true}}
+	p := LocalFirst(l, batch, single)
+
+	refs := []*deps.PackageRef{{Name: "left-pad"}, {Name: "express"}, {Name: "lodash"}}
+	got, err := p.EnrichBatch(context.Background(), refs, false)
+	if err != nil {
+		t.Fatalf("EnrichBatch: %v", err)
+	}
+	if len(batch.calls) != 1 || !slices.Equal(batch.calls[0], []string{"express", "lodash"}) {
+		t.Errorf("batch calls = %v, want one call without the vendored package", batch.calls)
+	}
+	if got["left-pad"][LocalPath] != "node_modules/left-pad" || got["left-pad"][RepoStars] != nil {
+		t.Errorf("left-pad = %v, want local metadata only", got["left-pad"])
+	}
+	if got["express"][RepoStars] != 10 || got["express"]["other"] != true {
+		t.Errorf("express = %v, want metadata from both providers", got["express"])
+	}
+
+	m, err := p.Enrich(context.Background(), &deps.PackageRef{Name: "lodash"}, false)
+	if err != nil || m["other"] != true {
+		t.Errorf("Enrich(lodash) = %v, %v", m, err)
+	}
+}
+
+type recordingURLProvider struct{ names []string }
+
+func (r *recordingURLProvider) FetchURLs(ctx context.Context, names []string, refresh bool) (map[string]deps.PackageURLs, error) {
+	r.names = names
+	return nil, nil
+}
+
+func TestLocalURLs(t *testing.T) {
+	next := &recordingURLProvider{}
+	p := LocalURLs(NewLocal(localProject(t)), next)
+
+	if _, err := p.FetchURLs(context.Background(), []string{"left-pad", "express", "zlib"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(next.names, []string{"express"}) {
+		t.Errorf("remote names = %v, want only the packages not installed", next.names)
+	}
+}
@@ -26,3 +26,6 @@ func (c *Composite) Enrich(ctx context.Context, pkg *deps.PackageRef, refresh bo
 	}
 	return m, nil
 }
+
+// Skipped returns the packages the providers left out of enrichment.
+func (c *Composite) Skipped() []string { return deps.SkippedPackages(c.providers) }
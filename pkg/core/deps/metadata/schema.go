@@ -9,8 +9,18 @@ const (
 	RepoLanguage    = "repo_language"
 	RepoTopics      = "repo_topics"
 	RepoMaintainers = "repo_maintainers"
-	RepoLastCommit  = "repo_last_commit"
-	RepoLastRelease = "repo_last_release"
-	RepoLicense     = "repo_license"
-	HomePage        = "homepage"
+	// RepoContributions holds commit counts parallel to RepoMaintainers;
+	// only set when contributors were fetched.
+	RepoContributions = "repo_contributions"
+	RepoLastCommit    = "repo_last_commit"
+	RepoLastRelease   = "repo_last_release"
+	RepoCreated       = "repo_created"
+	RepoLicense       = "repo_license"
+	HomePage          = "homepage"
+	// RepoSuccessor is the URL of a maintained fork of an archived
+	// repository; only set when one was found.
+	RepoSuccessor = "repo_successor"
+	// Scorecard is the OpenSSF Scorecard aggregate score (0–10). No built-in
+	// provider sets it; graphs enriched by other tools may carry it.
+	Scorecard = "scorecard"
 )
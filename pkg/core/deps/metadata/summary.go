@@ -0,0 +1,196 @@
+package metadata
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/integrations/github"
+)
+
+const (
+	// Summary is the node metadata key holding a short, plain-text
+	// description of a package, ready to show in popups.
+	Summary = "summary"
+
+	maxSummaryLen = 200 // Characters; popups wrap about four lines of this
+)
+
+// Summaries stores a [Summary] on graph nodes. It takes the repository or
+// registry description when enrichment found one, and otherwise the first
+// paragraph of the repository's README, so that popups have something to
+// say about packages whose registry metadata lacks a description.
+//
+// It runs after [GitHub] enrichment, which provides the [RepoURL] READMEs
+// are fetched from. READMEs go through the shared cache and, like
+// enrichment, leave a reserve of the GitHub rate limit alone.
+type Summaries struct {
+	client *github.Client
+}
+
+// NewSummaries creates a summarizer that fetches READMEs from GitHub.
+func NewSummaries(backend cache.Cache, token string, cacheTTL time.Duration) *Summaries {
+	return &Summaries{client: github.NewClient(backend, token, cacheTTL)}
+}
+
+// EnrichGraph sets [Summary] on every node with a description or a README
+// and returns how many nodes received one. Nodes that already have a
+// summary are left alone, and failed README downloads are skipped.
+// Zero or negative workers use [deps.DefaultWorkers].
+func (s *Summaries) EnrichGraph(ctx context.Context, g *dag.DAG, workers int, refresh bool) int {
+	count := 0
+	var readmes []*dag.Node
+	for _, n := range g.Nodes() {
+		if n.Meta == nil {
+			continue
+		}
+		if existing, _ := n.Meta[Summary].(string); existing != "" {
+			continue
+		}
+		if summary := Summarize(description(n)); summary != "" {
+			n.Meta[Summary] = summary
+			count++
+		} else if _, _, ok := repoOf(n); ok {
+			readmes = append(readmes, n)
+		}
+	}
+	if len(readmes) == 0 {
+		return count
+	}
+
+	_ = s.client.FetchQuota(ctx)
+	if q, ok := s.client.Quota(github.ResourceCore); ok {
+		readmes = readmes[:min(len(readmes), max(q.Remaining-quotaReserve, 0))]
+	}
+
+	if workers <= 0 {
+		workers = deps.DefaultWorkers
+	}
+	summaries := deps.ParallelMapOrdered(ctx, workers, readmes, func(ctx context.Context, n *dag.Node) string {
+		owner, repo, _ := repoOf(n)
+		text, err := s.client.FetchReadme(ctx, owner, repo, refresh)
+		if err != nil {
+			return ""
+		}
+		return Summarize(text)
+	})
+	for idx, summary := range summaries {
+		if summary != "" {
+			readmes[idx].Meta[Summary] = summary
+			count++
+		}
+	}
+	return count
+}
+
+// description returns the description enrichment or the registry found
+// for a node, preferring the repository's.
+func description(n *dag.Node) string {
+	if desc, _ := n.Meta[RepoDescription].(string); strings.TrimSpace(desc) != "" {
+		return desc
+	}
+	desc, _ := n.Meta["description"].(string)
+	return desc
+}
+
+// repoOf returns the GitHub repository of a node, if enrichment found one.
+func repoOf(n *dag.Node) (owner, repo string, ok bool) {
+	u, _ := n.Meta[RepoURL].(string)
+	if u == "" {
+		return "", "", false
+	}
+	return github.ExtractURL(map[string]string{"repository": u}, "")
+}
+
+var (
+	mdImage     = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	mdLink      = regexp.MustCompile(`\[([^\]]*)\](?:\([^)]*\)|\[[^\]]*\])`)
+	rstLink     = regexp.MustCompile("`([^`<]+?)\\s*<[^>]+>`_+")
+	htmlTag     = regexp.MustCompile(`<[^>]+>`)
+	markupChars = strings.NewReplacer("**", "", "__", "", "`", "")
+)
+
+// Summarize returns the first paragraph of a description or README as
+// plain text, cut at a word boundary to at most 200 characters. Headings,
+// badges, HTML blocks, code blocks and reStructuredText directives before
+// the first paragraph are skipped, and Markdown links are reduced to their
+// text. It returns "" when the text has no paragraph.
+func Summarize(text string) string {
+	summary := strings.Join(strings.Fields(stripMarkup(firstParagraph(text))), " ")
+	if utf8.RuneCountInString(summary) <= maxSummaryLen {
+		return summary
+	}
+	cut := string([]rune(summary)[:maxSummaryLen])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,;:-") + "…"
+}
+
+// firstParagraph returns the lines of the first prose paragraph of text.
+func firstParagraph(text string) string {
+	var para []string
+	fenced := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			fenced = !fenced
+			continue
+		}
+		switch {
+		case fenced:
+			continue
+		case line == "":
+			if len(para) > 0 {
+				return strings.Join(para, " ")
+			}
+		case isUnderline(line):
+			// A setext or reStructuredText heading: what came before
+			// was its title.
+			para = nil
+		case isMarkupLine(line):
+			if len(para) > 0 {
+				return strings.Join(para, " ")
+			}
+		default:
+			para = append(para, line)
+		}
+	}
+	return strings.Join(para, " ")
+}
+
+// isUnderline reports whether a line is a run of one punctuation character,
+// such as "=====", which underlines or overlines headings.
+func isUnderline(line string) bool {
+	if len(line) < 3 || !strings.ContainsRune("=-~^*+#\"'`", rune(line[0])) {
+		return false
+	}
+	return strings.Trim(line, line[:1]) == ""
+}
+
+// isMarkupLine reports whether a line is a heading, badge, HTML block,
+// table row or directive rather than prose.
+func isMarkupLine(line string) bool {
+	for _, prefix := range []string{"#", "![", "[![", "<", "..", ":", "|", ">"} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	// A line of nothing but images and links is a row of badges.
+	return strings.TrimSpace(mdLink.ReplaceAllString(mdImage.ReplaceAllString(line, ""), "")) == ""
+}
+
+// stripMarkup reduces Markdown and reStructuredText inline markup to its
+// text.
+func stripMarkup(s string) string {
+	s = mdImage.ReplaceAllString(s, "")
+	s = mdLink.ReplaceAllString(s, "$1")
+	s = rstLink.ReplaceAllString(s, "$1")
+	s = htmlTag.ReplaceAllString(s, "")
+	return markupChars.Replace(s)
+}
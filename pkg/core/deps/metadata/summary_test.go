@@ -0,0 +1,74 @@
+package metadata
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name, text, want string
+	}{
+		{"plain description", "  A simple   HTTP library.\n", "A simple HTTP library."},
+		{
+			"markdown readme",
+			"<p align=\"center\"><img src=\"logo.png\"></p>\n\n# Flask\n\n[![PyPI](https://img.shields.io/pypi/v/flask.svg)](https://pypi.org/project/flask) [![CI](https://ci/badge.svg)](https://ci)\n\nFlask is a **lightweight** [WSGI](https://wsgi.readthedocs.io/) web\napplication framework.\n\nIt began as a simple wrapper.",
+			"Flask is a lightweight WSGI web application framework.",
+		},
+		{
+			"restructuredtext readme",
+			".. image:: https://img.shields.io/pypi/v/attrs.svg\n   :target: https://pypi.org/project/attrs\n\n=====\nattrs\n=====\n\n``attrs`` brings back the `joy <https://www.attrs.org/>`_ of writing classes.",
+			"attrs brings back the joy of writing classes.",
+		},
+		{"setext heading", "Requests\n========\n\nHTTP for Humans.", "HTTP for Humans."},
+		{"code before prose", "```sh\npip install foo\n```\n\nFoo does things.", "Foo does things."},
+		{"nothing but markup", "# Title\n\n![logo](logo.png)", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summarize(tt.text); got != tt.want {
+				t.Errorf("Summarize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarize_Truncates(t *testing.T) {
+	got := Summarize(strings.Repeat("lorem ipsum, ", 40))
+	if n := utf8.RuneCountInString(got); n > maxSummaryLen+1 {
+		t.Errorf("summary has %d characters, want at most %d", n, maxSummaryLen+1)
+	}
+	if !strings.HasSuffix(got, "ipsum…") {
+		t.Errorf("Summarize() = %q, want it cut at a word with an ellipsis", got)
+	}
+}
+
+func TestSummaries_EnrichGraph(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "flask", Meta: dag.Metadata{RepoDescription: "The Python micro framework", "description": "A simple framework"}})
+	_ = g.AddNode(dag.Node{ID: "click", Meta: dag.Metadata{"description": "Composable command line interface toolkit"}})
+	_ = g.AddNode(dag.Node{ID: "kept", Meta: dag.Metadata{"description": "new", Summary: "curated"}})
+	_ = g.AddNode(dag.Node{ID: "bare", Meta: dag.Metadata{}})
+
+	c, _ := cache.NewFileCache(t.TempDir())
+	if got := NewSummaries(c, "", time.Hour).EnrichGraph(context.Background(), g, 0, false); got != 2 {
+		t.Errorf("EnrichGraph() = %d, want 2", got)
+	}
+	for id, want := range map[string]any{
+		"flask": "The Python micro framework",
+		"click": "Composable command line interface toolkit",
+		"kept":  "curated",
+		"bare":  nil,
+	} {
+		n, _ := g.Node(id)
+		if n.Meta[Summary] != want {
+			t.Errorf("%s summary = %v, want %v", id, n.Meta[Summary], want)
+		}
+	}
+}
@@ -51,6 +51,12 @@ type NodeMetadata struct {
 	// RepoLastRelease is the date of the most recent release.
 	RepoLastRelease string
 
+	// RepoCreated is the date the repository was created.
+	RepoCreated string
+
+	// RepoSuccessor is the URL of a maintained fork of an archived repository.
+	RepoSuccessor string
+
 	// RepoLicense is the SPDX license identifier (e.g., "MIT").
 	RepoLicense string
 
@@ -118,6 +124,10 @@ func FromMap(m map[string]any) NodeMetadata {
 			typed.RepoLastCommit, _ = v.(string)
 		case RepoLastRelease:
 			typed.RepoLastRelease, _ = v.(string)
+		case RepoCreated:
+			typed.RepoCreated, _ = v.(string)
+		case RepoSuccessor:
+			typed.RepoSuccessor, _ = v.(string)
 		case RepoLicense:
 			typed.RepoLicense, _ = v.(string)
 		default:
@@ -168,6 +178,12 @@ func (n NodeMetadata) ToMap() map[string]any {
 	if n.RepoLastRelease != "" {
 		m[RepoLastRelease] = n.RepoLastRelease
 	}
+	if n.RepoCreated != "" {
+		m[RepoCreated] = n.RepoCreated
+	}
+	if n.RepoSuccessor != "" {
+		m[RepoSuccessor] = n.RepoSuccessor
+	}
 	if n.RepoLicense != "" {
 		m[RepoLicense] = n.RepoLicense
 	}
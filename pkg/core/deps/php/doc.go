@@ -24,6 +24,17 @@
 // Note: composer.json contains direct dependencies in "require". The
 // resolver fetches transitive dependencies from Packagist.
 //
+// # Virtual and Abandoned Packages
+//
+// Requirements on virtual packages, such as "psr/log-implementation", are
+// left out of the graph: they have no releases of their own, and whichever
+// package provides them is required separately. Packages Packagist marks
+// abandoned carry [deps.MetaAbandoned] and, when one is suggested, the
+// replacement package in [deps.MetaReplacement], so brittle detection
+// flags them.
+//
 // [packagist]: github.com/stacktower-io/stacktower/pkg/integrations/packagist
 // [deps.Language]: github.com/stacktower-io/stacktower/pkg/core/deps.Language
+// [deps.MetaAbandoned]: github.com/stacktower-io/stacktower/pkg/core/deps.MetaAbandoned
+// [deps.MetaReplacement]: github.com/stacktower-io/stacktower/pkg/core/deps.MetaReplacement
 package php
@@ -2,11 +2,11 @@ package php
 
 import (
 	"context"
+	"slices"
 	"strings"
 
 	"github.com/stacktower-io/stacktower/pkg/cache"
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
-	"github.com/stacktower-io/stacktower/pkg/core/deps/constraints"
 	"github.com/stacktower-io/stacktower/pkg/integrations/packagist"
 )
 
@@ -50,92 +50,42 @@ func manifestParsers(res deps.Resolver) []deps.ManifestParser {
 
 func newResolver(backend cache.Cache, opts deps.Options) (deps.Resolver, error) {
 	c := packagist.NewClient(backend, opts.CacheTTL)
-	f := fetcher{client: c, phpVersion: opts.RuntimeVersion}
+	f := fetcher{
+		RegistryFetcher: deps.RegistryFetcher{
+			Client:         packagist.NewRegistry(c),
+			RuntimeVersion: opts.RuntimeVersion,
+			ManifestFile:   "composer.json",
+		},
+		client: c,
+	}
 
 	// Use PubGrub for proper SAT-solver-based dependency resolution
 	return deps.NewPubGrubResolver("packagist", f, ComposerMatcher{})
 }
 
+// fetcher fetches packages from Packagist, leaving out requirements on
+// virtual packages such as "psr/log-implementation". Those have no
+// releases to resolve: Composer satisfies them with whichever required
+// package provides them, which the requiring package's users choose
+// themselves.
 type fetcher struct {
-	client     *packagist.Client
-	phpVersion string
+	deps.RegistryFetcher
+	client *packagist.Client
 }
 
 func (f fetcher) Fetch(ctx context.Context, name string, refresh bool) (*deps.Package, error) {
-	p, err := f.client.FetchPackage(ctx, name, refresh)
-	if err != nil {
-		return nil, err
-	}
-	if err := f.checkCompatibility(p, name); err != nil {
-		return nil, err
-	}
-	return packagistPkgToDepsPkg(p), nil
+	return f.FetchVersion(ctx, name, "", refresh)
 }
 
 func (f fetcher) FetchVersion(ctx context.Context, name, version string, refresh bool) (*deps.Package, error) {
-	p, err := f.client.FetchPackageVersion(ctx, name, version, refresh)
+	pkg, err := f.RegistryFetcher.FetchVersion(ctx, name, version, refresh)
 	if err != nil {
 		return nil, err
 	}
-	if err := f.checkCompatibility(p, name); err != nil {
-		return nil, err
-	}
-	return packagistPkgToDepsPkg(p), nil
-}
-
-func (f fetcher) checkCompatibility(p *packagist.PackageInfo, name string) error {
-	if f.phpVersion == "" || p.RequiredPHP == "" {
-		return nil
-	}
-	if !constraints.CheckVersionConstraint(f.phpVersion, p.RequiredPHP) {
-		return &deps.IncompatibleRuntimeError{
-			Package:           name,
-			Version:           p.Version,
-			RuntimeConstraint: p.RequiredPHP,
-			TargetRuntime:     f.phpVersion,
-		}
-	}
-	return nil
-}
-
-// ListVersions implements deps.VersionLister for constraint-based resolution.
-func (f fetcher) ListVersions(ctx context.Context, name string, refresh bool) ([]string, error) {
-	return f.client.ListVersions(ctx, name, refresh)
-}
-
-// ListVersionsWithConstraints implements deps.RuntimeConstraintLister.
-func (f fetcher) ListVersionsWithConstraints(ctx context.Context, name string, refresh bool) (map[string]string, error) {
-	raw, err := f.client.ListVersionsWithConstraints(ctx, name, refresh)
-	if err != nil {
-		return nil, err
-	}
-	for version, constraint := range raw {
-		raw[version] = constraints.NormalizeRuntimeConstraint(constraint)
-	}
-	return raw, nil
-}
-
-func packagistPkgToDepsPkg(p *packagist.PackageInfo) *deps.Package {
-	pkg := &deps.Package{
-		Name:              p.Name,
-		Version:           p.Version,
-		Description:       p.Description,
-		License:           p.License,
-		Author:            p.Author,
-		Repository:        p.Repository,
-		HomePage:          p.HomePage,
-		ManifestFile:      "composer.json",
-		RuntimeConstraint: p.RequiredPHP,
-	}
-	// Convert packagist.Dependency to deps.Dependency with constraints
-	if len(p.Dependencies) > 0 {
-		pkg.Dependencies = make([]deps.Dependency, len(p.Dependencies))
-		for i, d := range p.Dependencies {
-			pkg.Dependencies[i] = deps.Dependency{
-				Name:       d.Name,
-				Constraint: d.Constraint,
-			}
-		}
-	}
-	return pkg
+	// Requirements that cannot be checked are kept for the resolver to report
+	pkg.Dependencies = slices.DeleteFunc(pkg.Dependencies, func(d deps.Dependency) bool {
+		virtual, err := f.client.IsVirtual(ctx, d.Name, refresh)
+		return err == nil && virtual
+	})
+	return pkg, nil
 }
@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"maps"
 	"regexp"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/contriboss/pubgrub-go"
 	"golang.org/x/sync/singleflight"
 
+	"github.com/stacktower-io/stacktower/pkg/cache"
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	"github.com/stacktower-io/stacktower/pkg/core/deps/constraints"
 	"github.com/stacktower-io/stacktower/pkg/observability"
@@ -135,6 +139,7 @@ func (r *PubGrubResolver) ProbeRuntimeConstraint(ctx context.Context, name, vers
 
 // Resolve uses PubGrub to resolve the dependency graph.
 func (r *PubGrubResolver) Resolve(ctx context.Context, pkg string, opts Options) (*dag.DAG, error) {
+	pkg, opts = WithPackageSpec(pkg, opts)
 	resolvedOpts := opts.WithDefaults()
 
 	source := &pubgrubSource{
@@ -148,6 +153,18 @@ func (r *PubGrubResolver) Resolve(ctx context.Context, pkg string, opts Options)
 		seen:           make(map[string]bool),
 		depth:          make(map[string]int),
 		hintedVersions: make(map[string]map[string]bool),
+		listed:         make(map[string][]pubgrub.Version),
+		stale:          make(map[string]bool),
+		truncated:      make(map[string]bool),
+		fetchCtx:       ctx,
+		unlisted:       make(map[string]bool),
+		expanded:       make(map[string]bool),
+	}
+	if resolvedOpts.MaxDuration > 0 {
+		source.deadline = time.Now().Add(resolvedOpts.MaxDuration)
+		var cancel context.CancelFunc
+		source.fetchCtx, cancel = context.WithDeadline(ctx, source.deadline)
+		defer cancel()
 	}
 	// Clear source cache after resolution to free memory from accumulated Package structs
 	defer source.clearCache()
@@ -161,6 +178,9 @@ func (r *PubGrubResolver) Resolve(ctx context.Context, pkg string, opts Options)
 	var rootCondition pubgrub.Condition
 	if opts.Version != "" {
 		rootCondition = pubgrub.EqualsCondition{Version: r.parser.ParseVersion(opts.Version)}
+		// The registry may leave the requested version unlisted, as PyPI
+		// does for yanked releases
+		source.hintVersion(pkg, opts.Version)
 	} else if opts.Constraint != "" {
 		rootCondition = r.parser.ParseConstraint(opts.Constraint)
 		if rootCondition == nil {
@@ -185,6 +205,27 @@ func (r *PubGrubResolver) Resolve(ctx context.Context, pkg string, opts Options)
 	// Create and run solver
 	solver := pubgrub.NewSolver(root, source).EnableIncompatibilityTracking()
 	solution, err := solver.Solve(root.Term())
+	for err != nil && source.dropUnlisted() {
+		// MaxDuration cut off version listings before any version of some
+		// packages was known; solve again without them
+		solver = pubgrub.NewSolver(root, source).EnableIncompatibilityTracking()
+		solution, err = solver.Solve(root.Term())
+	}
+	if err == nil && source.capped {
+		// MaxNodes turned packages away in solver order; solve again with
+		// the most depended-upon packages instead, keeping the first
+		// solution if the new set of packages can't be solved
+		if plan := source.planCrawl(pkg, solution); plan != nil {
+			resolvedOpts.Logger.Debug("max nodes reached: keeping the most depended-upon packages", "max_nodes", resolvedOpts.MaxNodes)
+			source.replan(pkg, plan)
+			solver = pubgrub.NewSolver(root, source).EnableIncompatibilityTracking()
+			if replanned, replanErr := solver.Solve(root.Term()); replanErr == nil {
+				solution = replanned
+			} else {
+				resolvedOpts.Logger.Debug("solve after max nodes replan failed: keeping the first solution", "err", replanErr)
+			}
+		}
+	}
 	if err != nil {
 		if nsErr, ok := err.(*pubgrub.NoSolutionError); ok {
 			// Check if this is a diamond dependency conflict (common in npm)
@@ -218,6 +259,7 @@ func (r *PubGrubResolver) solutionToDAG(
 
 	// Build a map of resolved versions
 	resolved := make(map[string]string) // name -> version
+	resolvedVersions := make(map[string]pubgrub.Version, len(solution))
 	for _, nv := range solution {
 		name := nv.Name.Value()
 		if name == "$$root" {
@@ -225,6 +267,7 @@ func (r *PubGrubResolver) solutionToDAG(
 		}
 		version := nv.Version.String()
 		resolved[name] = version
+		resolvedVersions[name] = nv.Version
 		_ = g.AddNode(dag.Node{ID: name})
 	}
 
@@ -248,6 +291,12 @@ func (r *PubGrubResolver) solutionToDAG(
 		}
 		results := ParallelMapOrdered(ctx, opts.Workers, jobs, func(ctx context.Context, j packageFetchJob) packageFetchResult {
 			pkg, err := source.getPackage(j.name, j.version)
+			if err != nil && (source.isTruncated(j.name) || source.ranOutOfTime()) {
+				// Never fetched before the time ran out: keep the node,
+				// without its registry metadata
+				source.truncate(j.name)
+				pkg, err = &Package{Name: j.name, Version: j.version}, nil
+			}
 			return packageFetchResult{
 				name:    j.name,
 				version: j.version,
@@ -258,14 +307,17 @@ func (r *PubGrubResolver) solutionToDAG(
 
 		for _, res := range results {
 			if res.err != nil {
-				opts.Logger("failed to get package %s@%s for edges: %v", res.name, res.version, res.err)
+				opts.Logger.Warn("fetch for edges failed", "package", res.name, "version", res.version, "err", res.err)
 				continue
 			}
 			packages[res.name] = res.pkg
 		}
 	}
 
-	for name, pkg := range packages {
+	// Add edges in name order so the graph, and the JSON written from it,
+	// is the same on every run.
+	for _, name := range slices.Sorted(maps.Keys(packages)) {
+		pkg := packages[name]
 		for _, dep := range pkg.Dependencies {
 			if _, exists := resolved[dep.Name]; exists {
 				edgeMeta := dag.Metadata{}
@@ -295,17 +347,28 @@ func (r *PubGrubResolver) solutionToDAG(
 	// Enrich metadata -- prefer batch providers (single GraphQL call) over
 	// per-package worker pool (N sequential REST calls).
 	if len(opts.MetadataProviders) > 0 {
-		// Build PackageRef list and base metadata for every resolved package
-		refs := make([]*PackageRef, 0, len(packages))
-		for _, pkg := range packages {
+		// Build PackageRef list for every package the filter selects
+		selected := opts.EnrichFilter.Select(g)
+		toEnrich := packages
+		if selected != nil {
+			toEnrich = make(map[string]*Package, len(selected))
+			for name, pkg := range packages {
+				if selected[name] {
+					toEnrich[name] = pkg
+				}
+			}
+		}
+		refs := make([]*PackageRef, 0, len(toEnrich))
+		for _, pkg := range toEnrich {
 			refs = append(refs, pkg.Ref())
 		}
+		RankByDependents(g, refs)
 
 		// Try batch enrichment first (e.g., GitHub GraphQL)
 		enriched := r.enrichBatch(ctx, refs, opts)
 		if enriched == nil {
 			// No batch provider available -- fall back to per-package worker pool
-			enriched = r.enrichParallel(ctx, packages, depths, opts)
+			enriched = r.enrichParallel(ctx, toEnrich, depths, opts)
 		}
 
 		// Apply enrichment results to DAG nodes, merging with base metadata
@@ -326,6 +389,25 @@ func (r *PubGrubResolver) solutionToDAG(
 		}
 	}
 
+	// Record how far each package trails the newest published version, and
+	// which packages were resolved from stale data or left unexpanded.
+	for name, v := range resolvedVersions {
+		n, ok := g.Node(name)
+		if !ok || n.Meta == nil {
+			continue
+		}
+		if source.isStale(name) {
+			n.Meta[MetaStale] = true
+		}
+		if source.isTruncated(name) {
+			n.Meta[MetaTruncated] = true
+		}
+		if latest, behind, ok := source.versionStatus(name, v); ok {
+			n.Meta[MetaLatestVersion] = latest
+			n.Meta[MetaVersionsBehind] = behind
+		}
+	}
+
 	observability.ResolverFromContext(ctx).OnProgress(ctx, len(resolved), 0, opts.MaxNodes)
 	return pruneResolvedGraph(g, rootPkg, opts.MaxDepth, opts.MaxNodes), nil
 }
@@ -433,7 +515,7 @@ func (r *PubGrubResolver) enrichBatch(
 
 		batch, err := bp.EnrichBatch(ctx, refs, opts.Refresh)
 		if err != nil {
-			opts.Logger("batch enrich (%s): %v", p.Name(), err)
+			opts.Logger.Warn("batch enrich failed", "provider", p.Name(), "err", err)
 			// Fall through -- will return nil so caller uses per-package fallback
 			return nil
 		}
@@ -556,6 +638,35 @@ type pubgrubSource struct {
 	// includes these so PubGrub can find them even if the registry doesn't list them.
 	hintedVersions map[string]map[string]bool // package name -> set of version strings
 
+	// listed stores the registry's versions per package (after prerelease and
+	// runtime filtering) so resolved nodes can report how far behind they are.
+	listed map[string][]pubgrub.Version
+
+	// stale records the packages whose data came from expired cache entries
+	// because the registry could not be reached.
+	stale map[string]bool
+
+	// deadline is when the crawl stops expanding packages (zero = never),
+	// and truncated records the packages it left unexpanded. fetchCtx is
+	// ctx bounded by the deadline, so a package fetch still in flight when
+	// the time runs out is abandoned rather than waited for.
+	deadline  time.Time
+	truncated map[string]bool
+	fetchCtx  context.Context
+
+	// unlisted records the packages left without any version because the
+	// deadline cut their listing off, which the next solve leaves out
+	// (dropped counts those already left out), and expanded the
+	// "name@version"s whose dependencies were returned before the deadline.
+	unlisted map[string]bool
+	dropped  int
+	expanded map[string]bool
+
+	// capped records that MaxNodes turned a package away, and plan, when
+	// set, holds the only packages admitted (see planCrawl).
+	capped bool
+	plan   map[string]bool
+
 	// fetchGroup deduplicates concurrent fetches for the same package@version.
 	fetchGroup singleflight.Group
 }
@@ -566,12 +677,20 @@ func (s *pubgrubSource) GetVersions(name pubgrub.Name) ([]pubgrub.Version, error
 	if s.ctx.Err() != nil {
 		return nil, s.ctx.Err()
 	}
+	if s.pastDeadline() {
+		return s.versionsAtDeadline(name.Value()), nil
+	}
 	observability.ResolverFromContext(s.ctx).OnFetchStart(s.ctx, name.Value(), 0)
-	versions, err := s.lister.ListVersions(s.ctx, name.Value(), s.opts.Refresh)
+	ctx, stale := cache.TrackStale(s.fetchContext())
+	versions, err := s.lister.ListVersions(ctx, name.Value(), s.opts.Refresh)
 	observability.ResolverFromContext(s.ctx).OnFetchComplete(s.ctx, name.Value(), 0, 0, err)
 	if err != nil {
+		if s.ranOutOfTime() {
+			return s.versionsAtDeadline(name.Value()), nil
+		}
 		return nil, err
 	}
+	s.markStale(name.Value(), stale)
 
 	// If the fetcher can provide per-version runtime constraints, filter out
 	// versions that cannot run on the selected runtime before PubGrub explores them.
@@ -579,7 +698,7 @@ func (s *pubgrubSource) GetVersions(name pubgrub.Name) ([]pubgrub.Version, error
 	var runtimeConstraints map[string]string
 	if s.opts.RuntimeVersion != "" {
 		if rcLister, ok := s.fetcher.(RuntimeConstraintLister); ok {
-			if constraints, rcErr := rcLister.ListVersionsWithConstraints(s.ctx, name.Value(), s.opts.Refresh); rcErr == nil {
+			if constraints, rcErr := rcLister.ListVersionsWithConstraints(ctx, name.Value(), s.opts.Refresh); rcErr == nil {
 				runtimeConstraints = constraints
 			}
 		}
@@ -609,10 +728,14 @@ func (s *pubgrubSource) GetVersions(name pubgrub.Name) ([]pubgrub.Version, error
 	// When the registry returns no tagged versions (e.g., gopkg.in/* modules),
 	// fall back to fetching @latest so PubGrub has at least one candidate.
 	if len(result) == 0 {
-		pkg, err := s.fetcher.Fetch(s.ctx, name.Value(), s.opts.Refresh)
+		pkg, err := s.fetcher.Fetch(ctx, name.Value(), s.opts.Refresh)
 		if err != nil {
+			if s.ranOutOfTime() {
+				return s.versionsAtDeadline(name.Value()), nil
+			}
 			return nil, err
 		}
+		s.markStale(name.Value(), stale)
 		if pkg.Version != "" && (s.opts.IncludePrerelease || !IsPrereleaseVersion(pkg.Version)) {
 			pv := s.parser.ParseVersion(pkg.Version)
 			if pv != nil {
@@ -625,6 +748,7 @@ func (s *pubgrubSource) GetVersions(name pubgrub.Name) ([]pubgrub.Version, error
 	// Include hinted versions (exact pins from dependency constraints, e.g.,
 	// Go pseudo-versions) that the registry didn't list.
 	s.mu.Lock()
+	s.listed[name.Value()] = slices.Clone(result)
 	hints := s.hintedVersions[name.Value()]
 	s.mu.Unlock()
 	for vStr := range hints {
@@ -661,12 +785,27 @@ func (s *pubgrubSource) GetDependencies(name pubgrub.Name, version pubgrub.Versi
 
 	pkg, err := s.getPackage(name.Value(), version.String())
 	if err != nil {
-		s.opts.Logger("fetch %s@%s: %v", name.Value(), version.String(), err)
+		if s.ranOutOfTime() {
+			// The fetch ran into the deadline
+			s.truncate(nameStr)
+			return nil, nil
+		}
+		s.opts.Logger.Warn("fetch failed", "package", name.Value(), "version", version.String(), "err", err)
 		return nil, err
 	}
+	key := nameStr + "@" + version.String()
+	if len(pkg.Dependencies) > 0 && s.pastDeadline() && !s.isExpanded(key) {
+		s.truncate(nameStr)
+		return nil, nil
+	}
 
 	terms := make([]pubgrub.Term, 0, len(pkg.Dependencies))
 	for _, dep := range pkg.Dependencies {
+		if s.isUnlisted(dep.Name) {
+			// The deadline left no version of it to select
+			s.truncate(nameStr)
+			continue
+		}
 		childDepth := currDepth + 1
 		if !s.allowPackage(dep.Name, childDepth) {
 			continue
@@ -692,6 +831,9 @@ func (s *pubgrubSource) GetDependencies(name pubgrub.Name, version pubgrub.Versi
 		}
 		terms = append(terms, pubgrub.NewTerm(pubgrub.MakeName(dep.Name), cond))
 	}
+	s.mu.Lock()
+	s.expanded[key] = true
+	s.mu.Unlock()
 	return terms, nil
 }
 
@@ -721,7 +863,11 @@ func (s *pubgrubSource) allowPackage(name string, depth int) bool {
 		return true
 	}
 
+	if s.plan != nil && !s.plan[name] {
+		return false
+	}
 	if s.opts.MaxNodes > 0 && len(s.seen) >= s.opts.MaxNodes {
+		s.capped = true
 		return false
 	}
 
@@ -749,6 +895,150 @@ func (s *pubgrubSource) clearCache() {
 	s.seen = nil
 	s.depth = nil
 	s.hintedVersions = nil
+	s.listed = nil
+	s.plan = nil
+	s.unlisted = nil
+	s.expanded = nil
+}
+
+// versionStatus returns the newest listed version of a package and how many
+// listed versions are newer than v. ok is false when no versions were listed.
+func (s *pubgrubSource) versionStatus(name string, v pubgrub.Version) (latest string, behind int, ok bool) {
+	s.mu.Lock()
+	listed := s.listed[name]
+	s.mu.Unlock()
+	var newest pubgrub.Version
+	for _, lv := range listed {
+		if newest == nil || lv.Sort(newest) > 0 {
+			newest = lv
+		}
+		if lv.Sort(v) > 0 {
+			behind++
+		}
+	}
+	if newest == nil {
+		return "", 0, false
+	}
+	return newest.String(), behind, true
+}
+
+// markStale records that the data of a package is stale if the requests
+// tracker watched were answered from expired cache entries.
+func (s *pubgrubSource) markStale(name string, tracker *cache.StaleTracker) {
+	if !tracker.Used() {
+		return
+	}
+	s.mu.Lock()
+	s.stale[name] = true
+	s.mu.Unlock()
+}
+
+// isStale reports whether any data of a package came from expired cache
+// entries.
+func (s *pubgrubSource) isStale(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stale[name]
+}
+
+// pastDeadline reports whether Options.MaxDuration has run out.
+func (s *pubgrubSource) pastDeadline() bool {
+	return !s.deadline.IsZero() && time.Now().After(s.deadline)
+}
+
+// ranOutOfTime reports whether a failed fetch is due to Options.MaxDuration
+// rather than to ctx or the registry.
+func (s *pubgrubSource) ranOutOfTime() bool {
+	return s.ctx.Err() == nil && s.pastDeadline()
+}
+
+// fetchContext returns the context of package fetches: ctx, bounded by the
+// deadline when Options.MaxDuration is set.
+func (s *pubgrubSource) fetchContext() context.Context {
+	if s.fetchCtx != nil {
+		return s.fetchCtx
+	}
+	return s.ctx
+}
+
+// truncate records that the dependencies of a package were left out
+// because Options.MaxDuration ran out.
+func (s *pubgrubSource) truncate(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.truncated[name] = true
+}
+
+// isTruncated reports whether the dependencies of a package were left out
+// because Options.MaxDuration ran out.
+func (s *pubgrubSource) isTruncated(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.truncated[name]
+}
+
+// versionsAtDeadline returns the versions of a package known without asking
+// the registry, once Options.MaxDuration has run out: those listed or
+// fetched earlier and those hinted by constraints. A package never listed is
+// marked truncated, and unlisted when no version of it is known.
+func (s *pubgrubSource) versionsAtDeadline(name string) []pubgrub.Version {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, listed := slices.Clone(s.listed[name]), s.listed[name] != nil
+	seen := make(map[string]bool, len(result))
+	for _, v := range result {
+		seen[v.String()] = true
+	}
+
+	var candidates []string
+	if !listed {
+		s.truncated[name] = true
+		for _, key := range slices.Sorted(maps.Keys(s.cache)) {
+			if i := strings.LastIndex(key, "@"); i > 0 && key[:i] == name {
+				candidates = append(candidates, key[i+1:])
+			}
+		}
+	}
+	candidates = append(candidates, slices.Sorted(maps.Keys(s.hintedVersions[name]))...)
+	for _, v := range candidates {
+		if !s.opts.IncludePrerelease && IsPrereleaseVersion(v) {
+			continue
+		}
+		if pv := s.parser.ParseVersion(v); pv != nil && !seen[pv.String()] {
+			result = append(result, pv)
+			seen[pv.String()] = true
+		}
+	}
+	if len(result) == 0 {
+		s.unlisted[name] = true
+	}
+	return result
+}
+
+// isUnlisted reports whether the deadline cut off the version listing of a
+// package before any version of it was known.
+func (s *pubgrubSource) isUnlisted(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.unlisted[name]
+}
+
+// dropUnlisted reports whether packages turned unlisted since the last
+// solve, so that solving again without them may succeed.
+func (s *pubgrubSource) dropUnlisted() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	grew := len(s.unlisted) > s.dropped
+	s.dropped = len(s.unlisted)
+	return grew
+}
+
+// isExpanded reports whether the dependencies of a package version were
+// returned to the solver before the deadline.
+func (s *pubgrubSource) isExpanded(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.expanded[key]
 }
 
 // getPackage fetches and caches a package by name and version.
@@ -770,9 +1060,14 @@ func (s *pubgrubSource) getPackage(name, version string) (*Package, error) {
 			return pkg, nil
 		}
 		s.mu.Unlock()
+		fetchCtx := s.fetchContext()
+		if err := fetchCtx.Err(); err != nil {
+			return nil, err
+		}
 
 		observability.ResolverFromContext(s.ctx).OnFetchStart(s.ctx, name, 0)
-		pkg, err := s.fetcher.FetchVersion(s.ctx, name, version, s.opts.Refresh)
+		ctx, stale := cache.TrackStale(fetchCtx)
+		pkg, err := s.fetcher.FetchVersion(ctx, name, version, s.opts.Refresh)
 		depCount := 0
 		if pkg != nil {
 			depCount = len(pkg.Dependencies)
@@ -781,6 +1076,7 @@ func (s *pubgrubSource) getPackage(name, version string) (*Package, error) {
 		if err != nil {
 			return nil, err
 		}
+		s.markStale(name, stale)
 
 		s.mu.Lock()
 		s.cache[key] = pkg
@@ -803,7 +1099,7 @@ func (r *PubGrubResolver) enrich(ctx context.Context, pkg *Package, opts Options
 		if enriched, err := p.Enrich(ctx, ref, opts.Refresh); err == nil {
 			maps.Copy(m, enriched)
 		} else {
-			opts.Logger("enrich failed: %s: %v", pkg.Name, err)
+			opts.Logger.Warn("enrich failed", "package", pkg.Name, "err", err)
 		}
 	}
 	return m
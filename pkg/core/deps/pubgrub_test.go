@@ -3,11 +3,16 @@ package deps
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/contriboss/pubgrub-go"
+
+	"github.com/stacktower-io/stacktower/pkg/cache"
 )
 
 // mockParser implements ConstraintParser for testing
@@ -271,6 +276,307 @@ func TestPubGrubResolver_RespectsRootConstraint(t *testing.T) {
 	if got := rootNode.Meta["version"]; got != "4.18.0" {
 		t.Fatalf("root version = %v, want 4.18.0", got)
 	}
+	if got := rootNode.Meta[MetaLatestVersion]; got != "5.2.1" {
+		t.Errorf("root latest_version = %v, want 5.2.1", got)
+	}
+	if got := rootNode.Meta[MetaVersionsBehind]; got != 1 {
+		t.Errorf("root versions_behind = %v, want 1", got)
+	}
+	childNode, _ := g.Node("child")
+	if got := childNode.Meta[MetaVersionsBehind]; got != 1 {
+		t.Errorf("child versions_behind = %v, want 1", got)
+	}
+}
+
+func TestPubGrubResolver_PackageSpec(t *testing.T) {
+	fetcher := &mockVersionLister{
+		packages: map[string]map[string]*Package{
+			"root": {
+				"1.0.0": {Name: "root", Version: "1.0.0"},
+				"2.0.0": {Name: "root", Version: "2.0.0"},
+			},
+		},
+	}
+	resolver, err := NewPubGrubResolver("test", fetcher, mockParser{})
+	if err != nil {
+		t.Fatalf("NewPubGrubResolver failed: %v", err)
+	}
+
+	g, err := resolver.Resolve(context.Background(), "root@1.0.0", Options{Version: "2.0.0"})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	root, ok := g.Node("root")
+	if !ok {
+		t.Fatalf("root node missing; nodes = %v", g.Nodes())
+	}
+	if got := root.Meta["version"]; got != "1.0.0" {
+		t.Errorf("root version = %v, want the one the specifier names", got)
+	}
+}
+
+// staleFetcher answers for some packages as a registry client does when it
+// falls back to an expired cache entry.
+type staleFetcher struct {
+	*mockVersionLister
+	stale map[string]bool
+}
+
+func (f *staleFetcher) FetchVersion(ctx context.Context, name, version string, refresh bool) (*Package, error) {
+	if f.stale[name] {
+		cache.MarkStale(ctx)
+	}
+	return f.mockVersionLister.FetchVersion(ctx, name, version, refresh)
+}
+
+func TestPubGrubResolver_MarksStalePackages(t *testing.T) {
+	fetcher := &staleFetcher{
+		mockVersionLister: &mockVersionLister{
+			packages: map[string]map[string]*Package{
+				"root": {"1.0.0": {Name: "root", Version: "1.0.0", Dependencies: []Dependency{{Name: "dep", Constraint: ">=1.0.0"}}}},
+				"dep":  {"1.0.0": {Name: "dep", Version: "1.0.0", Dependencies: []Dependency{{Name: "leaf", Constraint: ">=1.0.0"}}}},
+				"leaf": {"1.0.0": {Name: "leaf", Version: "1.0.0"}},
+			},
+		},
+		stale: map[string]bool{"dep": true},
+	}
+	resolver, err := NewPubGrubResolver("test", fetcher, mockParser{})
+	if err != nil {
+		t.Fatalf("NewPubGrubResolver failed: %v", err)
+	}
+
+	g, err := resolver.Resolve(context.Background(), "root", Options{})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if !g.HasEdge("dep", "leaf") {
+		t.Error("the subtree of a stale package should be kept")
+	}
+	if got := StalePackages(g); len(got) != 1 || got[0] != "dep" {
+		t.Errorf("StalePackages() = %v, want [dep]", got)
+	}
+}
+
+func TestPubGrubResolver_MaxDuration(t *testing.T) {
+	fetcher := &mockVersionLister{
+		packages: map[string]map[string]*Package{
+			"root": {"1.0.0": {Name: "root", Version: "1.0.0", Dependencies: []Dependency{{Name: "dep", Constraint: ">=1.0.0"}}}},
+			"dep":  {"1.0.0": {Name: "dep", Version: "1.0.0"}},
+		},
+	}
+	resolver, err := NewPubGrubResolver("test", fetcher, mockParser{})
+	if err != nil {
+		t.Fatalf("NewPubGrubResolver failed: %v", err)
+	}
+
+	g, err := resolver.Resolve(context.Background(), "root", Options{MaxDuration: time.Nanosecond})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if _, ok := g.Node("dep"); ok {
+		t.Error("dependencies were followed after the time limit")
+	}
+	if got := TruncatedPackages(g); len(got) != 1 || got[0] != "root" {
+		t.Errorf("TruncatedPackages() = %v, want [root]", got)
+	}
+
+	g, err = resolver.Resolve(context.Background(), "root", Options{MaxDuration: time.Minute})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if !g.HasEdge("root", "dep") || len(TruncatedPackages(g)) != 0 {
+		t.Errorf("a resolution within the limit should be complete; truncated = %v", TruncatedPackages(g))
+	}
+}
+
+// hangingFetcher blocks fetches of some packages until the context is
+// done, as a registry that stopped answering does.
+type hangingFetcher struct {
+	*mockVersionLister
+	hang map[string]bool
+}
+
+func (f *hangingFetcher) Fetch(ctx context.Context, name string, refresh bool) (*Package, error) {
+	if err := f.wait(ctx, name); err != nil {
+		return nil, err
+	}
+	return f.mockVersionLister.Fetch(ctx, name, refresh)
+}
+
+func (f *hangingFetcher) FetchVersion(ctx context.Context, name, version string, refresh bool) (*Package, error) {
+	if err := f.wait(ctx, name); err != nil {
+		return nil, err
+	}
+	return f.mockVersionLister.FetchVersion(ctx, name, version, refresh)
+}
+
+func (f *hangingFetcher) wait(ctx context.Context, name string) error {
+	if !f.hang[name] {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(10 * time.Second):
+		return fmt.Errorf("fetch of %s was not cancelled", name)
+	}
+}
+
+func TestPubGrubResolver_MaxDurationBoundsFetches(t *testing.T) {
+	fetcher := &hangingFetcher{
+		mockVersionLister: &mockVersionLister{
+			packages: map[string]map[string]*Package{
+				"root": {"1.0.0": {Name: "root", Version: "1.0.0", Dependencies: []Dependency{{Name: "slow", Constraint: ">=1.0.0"}}}},
+				"slow": {"1.0.0": {Name: "slow", Version: "1.0.0", Dependencies: []Dependency{{Name: "leaf", Constraint: ">=1.0.0"}}}},
+				"leaf": {"1.0.0": {Name: "leaf", Version: "1.0.0"}},
+			},
+		},
+		hang: map[string]bool{"slow": true},
+	}
+	resolver, err := NewPubGrubResolver("test", fetcher, mockParser{})
+	if err != nil {
+		t.Fatalf("NewPubGrubResolver failed: %v", err)
+	}
+
+	start := time.Now()
+	g, err := resolver.Resolve(context.Background(), "root", Options{MaxDuration: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Resolve took %s, waiting on a fetch past the time limit", elapsed)
+	}
+	if !g.HasEdge("root", "slow") {
+		t.Error("the package whose fetch ran out of time should stay in the graph")
+	}
+	if _, ok := g.Node("leaf"); ok {
+		t.Error("dependencies of an unfetched package were followed")
+	}
+	if got := TruncatedPackages(g); len(got) != 1 || got[0] != "slow" {
+		t.Errorf("TruncatedPackages() = %v, want [slow]", got)
+	}
+}
+
+func TestPubGrubResolver_MaxDurationBoundsCrawlFetches(t *testing.T) {
+	pkg := func(name string, downloads int, deps ...string) map[string]*Package {
+		p := &Package{Name: name, Version: "1.0.0", Downloads: downloads}
+		for _, d := range deps {
+			p.Dependencies = append(p.Dependencies, Dependency{Name: d, Constraint: ">=1.0.0"})
+		}
+		return map[string]*Package{"1.0.0": p}
+	}
+	// MaxNodes leaves out "shared", which the best-first crawl then fetches
+	// at its latest version
+	fetcher := &hangingFetcher{
+		mockVersionLister: &mockVersionLister{
+			packages: map[string]map[string]*Package{
+				"root":   pkg("root", 0, "a", "b", "c"),
+				"a":      pkg("a", 10, "shared"),
+				"b":      pkg("b", 0, "shared"),
+				"c":      pkg("c", 0),
+				"shared": pkg("shared", 0),
+			},
+		},
+		hang: map[string]bool{"shared": true},
+	}
+	resolver, err := NewPubGrubResolver("test", fetcher, mockParser{})
+	if err != nil {
+		t.Fatalf("NewPubGrubResolver failed: %v", err)
+	}
+
+	start := time.Now()
+	g, err := resolver.Resolve(context.Background(), "root", Options{MaxNodes: 4, Workers: 1, MaxDuration: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Resolve took %s, waiting on a crawl fetch past the time limit", elapsed)
+	}
+	if !g.HasEdge("root", "a") {
+		t.Error("the first solution should be kept when the crawl runs out of time")
+	}
+}
+
+// slowListFetcher delays version listings of some packages until the
+// context is done.
+type slowListFetcher struct {
+	*mockVersionLister
+	slow map[string]bool
+}
+
+func (f *slowListFetcher) ListVersions(ctx context.Context, name string, refresh bool) ([]string, error) {
+	if f.slow[name] {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Second):
+			return nil, fmt.Errorf("listing of %s was not cancelled", name)
+		}
+	}
+	return f.mockVersionLister.ListVersions(ctx, name, refresh)
+}
+
+// pinParser parses "==version" constraints as exact pins, which hint their
+// version to the resolver.
+type pinParser struct{ mockParser }
+
+func (p pinParser) ParseConstraint(constraint string) pubgrub.Condition {
+	if v, ok := strings.CutPrefix(constraint, "=="); ok {
+		return pubgrub.EqualsCondition{Version: p.ParseVersion(v)}
+	}
+	return p.mockParser.ParseConstraint(constraint)
+}
+
+func TestPubGrubResolver_MaxDurationCutsOffVersionListings(t *testing.T) {
+	fetcher := &slowListFetcher{
+		mockVersionLister: &mockVersionLister{
+			packages: map[string]map[string]*Package{
+				"root": {"1.0.0": {Name: "root", Version: "1.0.0", Dependencies: []Dependency{
+					{Name: "capped", Constraint: "==1.0.0"},
+					{Name: "pinned", Constraint: "==1.0.0"},
+					{Name: "ranged", Constraint: ">=1.0.0"},
+				}}},
+				"capped": {"1.0.0": {Name: "capped", Version: "1.0.0", Dependencies: []Dependency{{Name: "deep", Constraint: ">=1.0.0"}}}},
+				"pinned": {"1.0.0": {Name: "pinned", Version: "1.0.0"}},
+				"ranged": {"1.0.0": {Name: "ranged", Version: "1.0.0"}},
+				"deep":   {"1.0.0": {Name: "deep", Version: "1.0.0"}},
+			},
+		},
+		slow: map[string]bool{"pinned": true, "ranged": true},
+	}
+	resolver, err := NewPubGrubResolver("test", fetcher, pinParser{})
+	if err != nil {
+		t.Fatalf("NewPubGrubResolver failed: %v", err)
+	}
+
+	start := time.Now()
+	g, err := resolver.Resolve(context.Background(), "root", Options{MaxDepth: 1, MaxDuration: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Resolve took %s, waiting on a version listing past the time limit", elapsed)
+	}
+
+	// Pinned versions are known without the listing; the ranged one has
+	// none to fall back on and is left out
+	for _, name := range []string{"capped", "pinned"} {
+		if !g.HasEdge("root", name) {
+			t.Errorf("edge root -> %s missing", name)
+		}
+		if n, ok := g.Node(name); !ok || n.Meta["version"] != "1.0.0" {
+			t.Errorf("%s lost its version after the time limit", name)
+		}
+	}
+	if _, ok := g.Node("ranged"); ok {
+		t.Error("ranged has no known version and should be left out")
+	}
+	if _, ok := g.Node("deep"); ok {
+		t.Error("MaxDepth was not applied")
+	}
+	if got := TruncatedPackages(g); !slices.Contains(got, "root") || !slices.Contains(got, "pinned") {
+		t.Errorf("TruncatedPackages() = %v, want root and pinned among them", got)
+	}
 }
 
 func TestPubGrubResolver_FiltersRuntimeIncompatibleVersions(t *testing.T) {
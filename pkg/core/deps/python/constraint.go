@@ -255,6 +255,17 @@ func (PEP440Matcher) ParseVersion(version string) pubgrub.Version {
 	return makePEP440Version(pv)
 }
 
+// HintedVersion implements deps.VersionHinter. PyPI does not list yanked
+// releases, but PEP 592 lets exact pins like "==1.2.3" select them, so the
+// pinned version is hinted to the version lister.
+func (PEP440Matcher) HintedVersion(constraint string) string {
+	m := specRE.FindStringSubmatch(constraint)
+	if m == nil || (m[1] != "==" && m[1] != "===") || strings.Contains(m[2], "*") {
+		return ""
+	}
+	return m[2]
+}
+
 // ParseConstraint converts a PEP 440 constraint to a PubGrub Condition.
 // Returns nil if the constraint is empty or cannot be parsed.
 // Version set bounds are built from pep440Version values so that range
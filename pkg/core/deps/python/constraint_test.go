@@ -321,3 +321,26 @@ func TestParseVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestPEP440Matcher_HintedVersion(t *testing.T) {
+	m := PEP440Matcher{}
+
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{"==1.2.3", "1.2.3"},
+		{"===1.2.3", "1.2.3"},
+		{" == 2.0 ", "2.0"},
+		{"==1.*", ""},
+		{">=1.0", ""},
+		{"==1.0,!=1.0.1", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := m.HintedVersion(tt.constraint); got != tt.want {
+			t.Errorf("HintedVersion(%q) = %q, want %q", tt.constraint, got, tt.want)
+		}
+	}
+}
@@ -15,6 +15,10 @@
 //	resolver, _ := python.Language.Resolver()
 //	g, _ := resolver.Resolve(ctx, "fastapi", deps.Options{MaxDepth: 10})
 //
+// Environment markers are evaluated for [deps.Options.RuntimeVersion] and
+// [deps.Options.Platform]. Yanked releases are only selected when pinned
+// exactly ("==1.2.3"), as PEP 592 prescribes, and carry "yanked" metadata.
+//
 // # Manifest Parsing
 //
 // Parse local manifest files:
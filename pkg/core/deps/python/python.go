@@ -1,8 +1,6 @@
 package python
 
 import (
-	"context"
-
 	"github.com/stacktower-io/stacktower/pkg/cache"
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
 	"github.com/stacktower-io/stacktower/pkg/integrations"
@@ -22,6 +20,7 @@ var Language = &deps.Language{
 		"pyproject.toml":   "pyproject",
 		"requirements.txt": "requirements",
 	},
+	SniffManifest:   sniffManifest,
 	NewResolver:     newResolver,
 	NewManifest:     newManifest,
 	ManifestParsers: manifestParsers,
@@ -29,92 +28,17 @@ var Language = &deps.Language{
 }
 
 func newResolver(backend cache.Cache, opts deps.Options) (deps.Resolver, error) {
-	c := pypi.NewClient(backend, opts.CacheTTL, opts.RuntimeVersion)
-	f := fetcher{client: c, pythonVersion: opts.RuntimeVersion}
+	c := pypi.NewClient(backend, opts.CacheTTL, opts.RuntimeVersion, opts.Platform)
+	f := deps.RegistryFetcher{
+		Client:         pypi.NewRegistry(c),
+		RuntimeVersion: opts.RuntimeVersion,
+		ManifestFile:   "pyproject.toml",
+	}
 
 	// Use PubGrub for proper SAT-solver-based dependency resolution
 	return deps.NewPubGrubResolver("pypi", f, PEP440Matcher{})
 }
 
-type fetcher struct {
-	client        *pypi.Client
-	pythonVersion string
-}
-
-func (f fetcher) Fetch(ctx context.Context, name string, refresh bool) (*deps.Package, error) {
-	p, err := f.client.FetchPackage(ctx, name, refresh)
-	if err != nil {
-		return nil, err
-	}
-	if err := f.checkCompatibility(p, name); err != nil {
-		return nil, err
-	}
-	return pypiPkgToDepsPkg(p), nil
-}
-
-func (f fetcher) FetchVersion(ctx context.Context, name, version string, refresh bool) (*deps.Package, error) {
-	p, err := f.client.FetchPackageVersion(ctx, name, version, refresh)
-	if err != nil {
-		return nil, err
-	}
-	if err := f.checkCompatibility(p, name); err != nil {
-		return nil, err
-	}
-	return pypiPkgToDepsPkg(p), nil
-}
-
-func (f fetcher) checkCompatibility(p *pypi.PackageInfo, name string) error {
-	if f.pythonVersion == "" || p.RequiresPython == "" {
-		return nil
-	}
-	if !p.IsCompatibleWith(f.pythonVersion) {
-		return &deps.IncompatibleRuntimeError{
-			Package:           name,
-			Version:           p.Version,
-			RuntimeConstraint: p.RequiresPython,
-			TargetRuntime:     f.pythonVersion,
-		}
-	}
-	return nil
-}
-
-// ListVersions implements deps.VersionLister for constraint-based resolution.
-func (f fetcher) ListVersions(ctx context.Context, name string, refresh bool) ([]string, error) {
-	return f.client.ListVersions(ctx, name, refresh)
-}
-
-// ListVersionsWithConstraints implements deps.RuntimeConstraintLister.
-// Returns all versions with their requires_python constraints in a single API call.
-func (f fetcher) ListVersionsWithConstraints(ctx context.Context, name string, refresh bool) (map[string]string, error) {
-	return f.client.ListVersionsWithConstraints(ctx, name, refresh)
-}
-
-func pypiPkgToDepsPkg(p *pypi.PackageInfo) *deps.Package {
-	pkg := &deps.Package{
-		Name:              p.Name,
-		Version:           p.Version,
-		Description:       p.Summary,
-		License:           p.License,
-		LicenseText:       p.LicenseText,
-		Author:            p.Author,
-		HomePage:          p.HomePage,
-		ProjectURLs:       p.ProjectURLs,
-		ManifestFile:      "pyproject.toml",
-		RuntimeConstraint: p.RequiresPython,
-	}
-	// Convert pypi.Dependency to deps.Dependency with constraints
-	if len(p.Dependencies) > 0 {
-		pkg.Dependencies = make([]deps.Dependency, len(p.Dependencies))
-		for i, d := range p.Dependencies {
-			pkg.Dependencies[i] = deps.Dependency{
-				Name:       d.Name,
-				Constraint: d.Constraint,
-			}
-		}
-	}
-	return pkg
-}
-
 func newManifest(name string, res deps.Resolver) deps.ManifestParser {
 	switch name {
 	case "uv":
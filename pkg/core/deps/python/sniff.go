@@ -0,0 +1,63 @@
+package python
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// requirementLineRE matches one requirement specifier: a name, optional
+// extras, then a version constraint, environment marker, or URL.
+var requirementLineRE = regexp.MustCompile(`^[a-zA-Z0-9][-a-zA-Z0-9._]*\s*(\[[^\]]*\])?\s*(([<>=!~]=?|===)\s*[^\s;]+.*|;.*|@\s*\S+.*)?$`)
+
+// pyprojectTableRE matches the tables that give pyproject.toml its
+// dependencies.
+var pyprojectTableRE = regexp.MustCompile(`(?m)^\s*\[(project|tool\.poetry)[\].]`)
+
+// sniffManifest recognizes requirement files under any .txt name, like
+// requirements-dev.txt or requirements/base.txt, and pyproject.toml files
+// that only configure tools. See [deps.ManifestSniffer].
+func sniffManifest(filename string, content []byte) (string, float64) {
+	switch {
+	case filename == "pyproject.toml":
+		if pyprojectTableRE.Match(content) {
+			return "pyproject", 0.95
+		}
+		return "pyproject", 0.3
+	case path.Ext(filename) == ".txt":
+		found, constrained := scanRequirements(content)
+		switch {
+		case found && strings.Contains(filename, "requirements"):
+			return "requirements", 0.9
+		case constrained:
+			return "requirements", 0.5
+		}
+	}
+	return "", 0
+}
+
+// scanRequirements reports whether every line of content is blank, a
+// comment, a pip option, or a requirement specifier, with at least one
+// requirement, and whether one of them has a version constraint. A text
+// file not named after requirements needs the constraint to be claimed, so
+// that a list of words is not mistaken for one.
+func scanRequirements(content []byte) (found, constrained bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		switch {
+		case line == "" || line[0] == '#' || line[0] == '-':
+		case requirementLineRE.MatchString(line):
+			found = true
+			constrained = constrained || strings.ContainsAny(line, "<>=~")
+		default:
+			return false, false
+		}
+	}
+	return found, found && constrained
+}
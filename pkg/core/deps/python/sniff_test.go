@@ -0,0 +1,29 @@
+package python
+
+import "testing"
+
+func TestSniffManifest(t *testing.T) {
+	tests := []struct {
+		name, filename, content string
+		wantType                string
+		wantConfidence          float64
+	}{
+		{"requirements variant", "requirements-dev.txt", "-r requirements.txt\npytest>=7  # tests\nblack\n", "requirements", 0.9},
+		{"constrained text file", "deps.txt", "flask==3.0.0\nrequests[socks]>=2.31; python_version >= '3.8'\n", "requirements", 0.5},
+		{"word list", "deps.txt", "x\n", "", 0},
+		{"prose", "notes.txt", "Remember to pin flask>=3 before release.\n", "", 0},
+		{"empty requirements", "requirements.txt", "# nothing yet\n", "", 0},
+		{"project table", "pyproject.toml", "[project]\nname = \"app\"\n", "pyproject", 0.95},
+		{"poetry table", "pyproject.toml", "[tool.poetry.dependencies]\npython = \"^3.11\"\n", "pyproject", 0.95},
+		{"tool config only", "pyproject.toml", "[tool.black]\nline-length = 100\n", "pyproject", 0.3},
+		{"other file", "setup.cfg", "[metadata]\n", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ, confidence := sniffManifest(tt.filename, []byte(tt.content))
+			if typ != tt.wantType || confidence != tt.wantConfidence {
+				t.Errorf("sniffManifest() = %q, %v; want %q, %v", typ, confidence, tt.wantType, tt.wantConfidence)
+			}
+		})
+	}
+}
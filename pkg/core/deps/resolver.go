@@ -105,6 +105,9 @@ type Resolver interface {
 	//
 	// Starting from pkg, the resolver recursively fetches dependencies up to
 	// Options.MaxDepth levels deep and Options.MaxNodes total packages.
+	// pkg may name a version, as in "fastapi@0.110.0" or
+	// "@angular/core@17.0.0", which takes precedence over Options.Version
+	// (see [ParsePackageSpec]); without one, the latest version is used.
 	//
 	// Returns a [dag.DAG] where:
 	//   - Nodes represent packages (ID = package name)
@@ -24,6 +24,16 @@
 // Note: Gemfile parsing extracts gem names from `gem "name"` declarations.
 // Transitive dependencies are resolved via RubyGems.
 //
+// # Platforms
+//
+// Native gems are published once per platform, often with other
+// dependencies than the pure-Ruby gem. With [deps.Options].Platform set,
+// registry resolution uses the gems built for that platform, and
+// Gemfile.lock parsing picks the entries locked for it; nodes of native
+// gems carry a "platform" metadata entry. Without it, the pure-Ruby gems
+// are used.
+//
 // [rubygems]: github.com/stacktower-io/stacktower/pkg/integrations/rubygems
 // [deps.Language]: github.com/stacktower-io/stacktower/pkg/core/deps.Language
+// [deps.Options]: github.com/stacktower-io/stacktower/pkg/core/deps.Options
 package ruby
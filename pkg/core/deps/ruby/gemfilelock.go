@@ -2,6 +2,7 @@ package ruby
 
 import (
 	"bufio"
+	"cmp"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/integrations/rubygems"
 	"github.com/stacktower-io/stacktower/pkg/observability"
 )
 
@@ -29,7 +31,7 @@ func (gl *GemfileLock) Parse(path string, opts deps.Options) (*deps.ManifestResu
 	}
 	defer f.Close()
 
-	lock := parseGemfileLock(f)
+	lock := parseGemfileLock(f, rubygems.Platform(opts.Platform))
 	g := buildGemfileLockGraph(lock, opts)
 	deps.EnrichGraph(opts.Ctx, g, "Gemfile", opts)
 
@@ -54,6 +56,7 @@ type gemfileLockData struct {
 type gemLockEntry struct {
 	name         string
 	version      string
+	platform     string // Platform of a native gem, empty for pure-Ruby gems
 	dependencies []gemLockDep
 }
 
@@ -75,7 +78,10 @@ var (
 	rubyVersionLockPattern = regexp.MustCompile(`^\s+ruby\s+(\d+\.\d+(?:\.\d+)?(?:p\d+)?)`)
 )
 
-func parseGemfileLock(f *os.File) *gemfileLockData {
+// parseGemfileLock parses a Gemfile.lock. Lock files list native gems once
+// per platform, each with its own dependencies; the one for platform, a
+// RubyGems platform, is used, else the pure-Ruby gem, else the first listed.
+func parseGemfileLock(f *os.File, platform string) *gemfileLockData {
 	lock := &gemfileLockData{
 		gems: make(map[string]*gemLockEntry),
 	}
@@ -123,11 +129,15 @@ func parseGemfileLock(f *os.File) *gemfileLockData {
 
 			// Try to match a gem spec line (4 spaces indent)
 			if match := gemSpecPattern.FindStringSubmatch(line); len(match) > 2 {
+				version, gemPlatform := rubygems.SplitPlatform(match[2])
 				currentGem = &gemLockEntry{
-					name:    match[1],
-					version: match[2],
+					name:     match[1],
+					version:  version,
+					platform: gemPlatform,
+				}
+				if prev, ok := lock.gems[currentGem.name]; !ok || preferLockEntry(currentGem, prev, platform) {
+					lock.gems[currentGem.name] = currentGem
 				}
-				lock.gems[currentGem.name] = currentGem
 				continue
 			}
 
@@ -157,6 +167,16 @@ func parseGemfileLock(f *os.File) *gemfileLockData {
 	return lock
 }
 
+// preferLockEntry reports whether entry suits platform better than prev,
+// another entry of the same gem.
+func preferLockEntry(entry, prev *gemLockEntry, platform string) bool {
+	if entry.platform == prev.platform {
+		return false
+	}
+	best := rubygems.BestPlatform([]string{prev.platform, entry.platform}, platform)
+	return best != "" && best == cmp.Or(entry.platform, rubygems.RubyPlatform)
+}
+
 func buildGemfileLockGraph(lock *gemfileLockData, opts deps.Options) *dag.DAG {
 	g := dag.New(nil)
 	hooks := observability.ResolverFromContext(opts.Ctx)
@@ -165,6 +185,9 @@ func buildGemfileLockGraph(lock *gemfileLockData, opts deps.Options) *dag.DAG {
 	for _, gem := range lock.gems {
 		hooks.OnFetchStart(opts.Ctx, gem.name, 0)
 		meta := dag.Metadata{"version": gem.version}
+		if gem.platform != "" {
+			meta["platform"] = gem.platform
+		}
 		_ = g.AddNode(dag.Node{ID: gem.name, Meta: meta})
 		hooks.OnFetchComplete(opts.Ctx, gem.name, 0, len(gem.dependencies), nil)
 	}
@@ -3,6 +3,7 @@ package ruby
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
@@ -234,3 +235,67 @@ BUNDLED WITH
 		t.Error("Edge actionpack -> activesupport not found")
 	}
 }
+
+func TestGemfileLock_ParsePlatforms(t *testing.T) {
+	content := `GEM
+  remote: https://rubygems.org/
+  specs:
+    mini_portile2 (2.8.4)
+    nokogiri (1.15.4)
+      mini_portile2 (~> 2.8.2)
+      racc (~> 1.4)
+    nokogiri (1.15.4-arm64-darwin)
+      racc (~> 1.4)
+    nokogiri (1.15.4-x86_64-linux)
+      racc (~> 1.4)
+    racc (1.7.1)
+
+PLATFORMS
+  arm64-darwin
+  ruby
+  x86_64-linux
+
+DEPENDENCIES
+  nokogiri
+`
+
+	lockPath := filepath.Join(t.TempDir(), "Gemfile.lock")
+	if err := os.WriteFile(lockPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		platform     string
+		wantPlatform string
+		wantPortile  bool
+	}{
+		{"", "", true},
+		{"linux", "x86_64-linux", false},
+		{"darwin", "arm64-darwin", false},
+		{"windows", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			result, err := (&GemfileLock{}).Parse(lockPath, deps.Options{Platform: tt.platform})
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			g := result.Graph
+
+			node, ok := g.Node("nokogiri")
+			if !ok {
+				t.Fatal("Node 'nokogiri' not found")
+			}
+			if v, _ := node.Meta["version"].(string); v != "1.15.4" {
+				t.Errorf("nokogiri version = %q, want 1.15.4", v)
+			}
+			if p, _ := node.Meta["platform"].(string); p != tt.wantPlatform {
+				t.Errorf("nokogiri platform = %q, want %q", p, tt.wantPlatform)
+			}
+			if got := slices.Contains(g.Children("nokogiri"), "mini_portile2"); got != tt.wantPortile {
+				t.Errorf("nokogiri -> mini_portile2 = %v, want %v", got, tt.wantPortile)
+			}
+		})
+	}
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/stacktower-io/stacktower/pkg/cache"
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
-	"github.com/stacktower-io/stacktower/pkg/core/deps/constraints"
 	"github.com/stacktower-io/stacktower/pkg/integrations/rubygems"
 )
 
@@ -51,93 +50,48 @@ func manifestParsers(res deps.Resolver) []deps.ManifestParser {
 
 func newResolver(backend cache.Cache, opts deps.Options) (deps.Resolver, error) {
 	c := rubygems.NewClient(backend, opts.CacheTTL)
-	f := fetcher{client: c, rubyVersion: opts.RuntimeVersion}
+	f := fetcher{
+		RegistryFetcher: deps.RegistryFetcher{
+			Client:         rubygems.NewRegistry(c),
+			RuntimeVersion: opts.RuntimeVersion,
+			ManifestFile:   "Gemfile",
+		},
+		client:   c,
+		platform: rubygems.Platform(opts.Platform),
+	}
 
 	// Use PubGrub for proper SAT-solver-based dependency resolution
 	return deps.NewPubGrubResolver("rubygems", f, GemMatcher{})
 }
 
+// fetcher is a [deps.RegistryFetcher] that picks the native gem built for
+// the target platform when fetching a specific version.
 type fetcher struct {
-	client      *rubygems.Client
-	rubyVersion string
-}
-
-func (f fetcher) Fetch(ctx context.Context, name string, refresh bool) (*deps.Package, error) {
-	g, err := f.client.FetchGem(ctx, name, refresh)
-	if err != nil {
-		return nil, err
-	}
-	if err := f.checkCompatibility(g, name); err != nil {
-		return nil, err
-	}
-	return gemInfoToDepsPkg(g), nil
+	deps.RegistryFetcher
+	client   *rubygems.Client
+	platform string // RubyGems platform to pick native gems for; empty for pure-Ruby gems only
 }
 
 func (f fetcher) FetchVersion(ctx context.Context, name, version string, refresh bool) (*deps.Package, error) {
-	g, err := f.client.FetchGemVersion(ctx, name, version, refresh)
-	if err != nil {
-		return nil, err
-	}
-	if err := f.checkCompatibility(g, name); err != nil {
-		return nil, err
-	}
-	return gemInfoToDepsPkg(g), nil
+	return f.RegistryFetcher.FetchVersion(ctx, name, f.platformVersion(ctx, name, version, refresh), refresh)
 }
 
-func (f fetcher) checkCompatibility(g *rubygems.GemInfo, name string) error {
-	if f.rubyVersion == "" || g.RequiredRubyVersion == "" {
-		return nil
+// platformVersion returns version with the platform of the native gem built
+// for the target platform appended, as in Gemfile.lock files, when there is
+// one. Versions that already name a platform are returned as is.
+func (f fetcher) platformVersion(ctx context.Context, name, version string, refresh bool) string {
+	if f.platform == "" {
+		return version
 	}
-	if !constraints.CheckVersionConstraint(f.rubyVersion, g.RequiredRubyVersion) {
-		return &deps.IncompatibleRuntimeError{
-			Package:           name,
-			Version:           g.Version,
-			RuntimeConstraint: g.RequiredRubyVersion,
-			TargetRuntime:     f.rubyVersion,
-		}
+	if _, platform := rubygems.SplitPlatform(version); platform != "" {
+		return version
 	}
-	return nil
-}
-
-// ListVersions implements deps.VersionLister for constraint-based resolution.
-func (f fetcher) ListVersions(ctx context.Context, name string, refresh bool) ([]string, error) {
-	return f.client.ListVersions(ctx, name, refresh)
-}
-
-// ListVersionsWithConstraints implements deps.RuntimeConstraintLister.
-func (f fetcher) ListVersionsWithConstraints(ctx context.Context, name string, refresh bool) (map[string]string, error) {
-	raw, err := f.client.ListVersionsWithConstraints(ctx, name, refresh)
+	platforms, err := f.client.Platforms(ctx, name, version, refresh)
 	if err != nil {
-		return nil, err
-	}
-	for version, constraint := range raw {
-		raw[version] = constraints.NormalizeRuntimeConstraint(constraint)
-	}
-	return raw, nil
-}
-
-func gemInfoToDepsPkg(g *rubygems.GemInfo) *deps.Package {
-	pkg := &deps.Package{
-		Name:              g.Name,
-		Version:           g.Version,
-		Description:       g.Description,
-		License:           g.License,
-		Author:            g.Authors,
-		Downloads:         g.Downloads,
-		Repository:        g.SourceCodeURI,
-		HomePage:          g.HomepageURI,
-		ManifestFile:      "Gemfile",
-		RuntimeConstraint: g.RequiredRubyVersion,
+		return version
 	}
-	// Convert rubygems.Dependency to deps.Dependency with constraints
-	if len(g.Dependencies) > 0 {
-		pkg.Dependencies = make([]deps.Dependency, len(g.Dependencies))
-		for i, d := range g.Dependencies {
-			pkg.Dependencies[i] = deps.Dependency{
-				Name:       d.Name,
-				Constraint: d.Constraint,
-			}
-		}
+	if p := rubygems.BestPlatform(platforms, f.platform); p != "" && p != rubygems.RubyPlatform {
+		return version + "-" + p
 	}
-	return pkg
+	return version
 }
@@ -14,6 +14,11 @@
 //	resolver, _ := rust.Language.Resolver()
 //	g, _ := resolver.Resolve(ctx, "serde", deps.Options{MaxDepth: 10})
 //
+// With SparseIndex set in [deps.Options], crates are resolved from the
+// sparse crates.io index instead of the API: one CDN request per crate
+// lists every version with its dependency requirements, so large graphs
+// resolve without running into the API's rate limit.
+//
 // # Manifest Parsing
 //
 // Parse Cargo.toml files:
@@ -26,4 +31,5 @@
 //
 // [crates]: github.com/stacktower-io/stacktower/pkg/integrations/crates
 // [deps.Language]: github.com/stacktower-io/stacktower/pkg/core/deps.Language
+// [deps.Options]: github.com/stacktower-io/stacktower/pkg/core/deps.Options
 package rust
@@ -1,12 +1,10 @@
 package rust
 
 import (
-	"context"
 	"strings"
 
 	"github.com/stacktower-io/stacktower/pkg/cache"
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
-	"github.com/stacktower-io/stacktower/pkg/core/deps/constraints"
 	"github.com/stacktower-io/stacktower/pkg/integrations/crates"
 )
 
@@ -53,95 +51,15 @@ func manifestParsers(res deps.Resolver) []deps.ManifestParser {
 
 func newResolver(backend cache.Cache, opts deps.Options) (deps.Resolver, error) {
 	c := crates.NewClient(backend, opts.CacheTTL)
-	f := fetcher{client: c, rustVersion: opts.RuntimeVersion}
-
-	// Use PubGrub for proper SAT-solver-based dependency resolution
-	return deps.NewPubGrubResolver("crates.io", f, CargoMatcher{})
-}
-
-type fetcher struct {
-	client      *crates.Client
-	rustVersion string
-}
-
-func (f fetcher) Fetch(ctx context.Context, name string, refresh bool) (*deps.Package, error) {
-	cr, err := f.client.FetchCrate(ctx, name, refresh)
-	if err != nil {
-		return nil, err
-	}
-	if err := f.checkCompatibility(cr, name); err != nil {
-		return nil, err
-	}
-	return crateInfoToDepsPkg(cr), nil
-}
-
-func (f fetcher) FetchVersion(ctx context.Context, name, version string, refresh bool) (*deps.Package, error) {
-	cr, err := f.client.FetchCrateVersion(ctx, name, version, refresh)
-	if err != nil {
-		return nil, err
-	}
-	if err := f.checkCompatibility(cr, name); err != nil {
-		return nil, err
-	}
-	return crateInfoToDepsPkg(cr), nil
-}
-
-func (f fetcher) checkCompatibility(cr *crates.CrateInfo, name string) error {
-	if f.rustVersion == "" || cr.MSRV == "" {
-		return nil
-	}
-	// MSRV is a minimum version requirement, so check if rustVersion >= MSRV
-	if !constraints.CheckVersionConstraint(f.rustVersion, ">="+cr.MSRV) {
-		return &deps.IncompatibleRuntimeError{
-			Package:           name,
-			Version:           cr.Version,
-			RuntimeConstraint: ">=" + cr.MSRV,
-			TargetRuntime:     f.rustVersion,
-		}
-	}
-	return nil
-}
-
-// ListVersions implements deps.VersionLister for constraint-based resolution.
-func (f fetcher) ListVersions(ctx context.Context, name string, refresh bool) ([]string, error) {
-	return f.client.ListVersions(ctx, name, refresh)
-}
-
-// ListVersionsWithConstraints implements deps.RuntimeConstraintLister.
-func (f fetcher) ListVersionsWithConstraints(ctx context.Context, name string, refresh bool) (map[string]string, error) {
-	raw, err := f.client.ListVersionsWithConstraints(ctx, name, refresh)
-	if err != nil {
-		return nil, err
+	if opts.SparseIndex {
+		c = crates.NewIndexClient(backend, opts.CacheTTL)
 	}
-	for version, constraint := range raw {
-		raw[version] = constraints.NormalizeRuntimeConstraint(constraint)
+	f := deps.RegistryFetcher{
+		Client:         crates.NewRegistry(c),
+		RuntimeVersion: opts.RuntimeVersion,
+		ManifestFile:   "Cargo.toml",
 	}
-	return raw, nil
-}
 
-func crateInfoToDepsPkg(cr *crates.CrateInfo) *deps.Package {
-	runtimeConstraint := constraints.NormalizeRuntimeConstraint(cr.MSRV)
-
-	pkg := &deps.Package{
-		Name:              cr.Name,
-		Version:           cr.Version,
-		Description:       cr.Description,
-		License:           cr.License,
-		Downloads:         cr.Downloads,
-		Repository:        cr.Repository,
-		HomePage:          cr.HomePage,
-		ManifestFile:      "Cargo.toml",
-		RuntimeConstraint: runtimeConstraint,
-	}
-	// Convert crates.Dependency to deps.Dependency with constraints
-	if len(cr.Dependencies) > 0 {
-		pkg.Dependencies = make([]deps.Dependency, len(cr.Dependencies))
-		for i, d := range cr.Dependencies {
-			pkg.Dependencies[i] = deps.Dependency{
-				Name:       d.Name,
-				Constraint: d.Constraint,
-			}
-		}
-	}
-	return pkg
+	// Use PubGrub for proper SAT-solver-based dependency resolution
+	return deps.NewPubGrubResolver("crates.io", f, CargoMatcher{})
 }
@@ -0,0 +1,106 @@
+package deps
+
+import (
+	"cmp"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Confidence of a filename match in [DetectManifests] when no
+// [Language.SniffManifest] weighs in.
+const (
+	ConfidenceFilename = 0.8 // The filename is a ManifestAliases key
+	ConfidencePattern  = 0.6 // The filename matches a glob ManifestAliases key
+)
+
+// SniffSize is how much of a file [DetectManifestFile] reads for sniffing.
+const SniffSize = 64 << 10
+
+// ManifestSniffer inspects the start of a file, up to [SniffSize] bytes, and
+// returns the manifest type its content looks like with a confidence from 0
+// to 1. An empty type means no opinion: the filename match stands.
+type ManifestSniffer func(filename string, content []byte) (typ string, confidence float64)
+
+// ManifestCandidate is a language and manifest type that may read a file,
+// as returned by [DetectManifests].
+type ManifestCandidate struct {
+	// Language is the language whose parser would read the file.
+	Language *Language
+
+	// Type is the manifest type, a value of Language.ManifestTypes, to
+	// pass to [Language.Manifest].
+	Type string
+
+	// Confidence ranges from 0 to 1; see [ConfidenceFilename].
+	Confidence float64
+}
+
+// DetectManifests returns every language that may read a file, most
+// confident first. A language is a candidate when the filename matches its
+// ManifestAliases, or when its SniffManifest recognizes the content, which
+// also lets a language claim variants like "requirements-dev.txt" that no
+// alias names. The sniffer's verdict replaces the filename confidence, so
+// a package.json without dependencies ranks below a language whose markers
+// the file contains.
+//
+// Content may be nil to match on the filename alone. Candidates of equal
+// confidence keep the order of languages, so detection is predictable.
+func DetectManifests(filename string, content []byte, languages []*Language) []ManifestCandidate {
+	filename = filepath.Base(filename)
+	var found []ManifestCandidate
+	for _, lang := range languages {
+		typ, confidence := lang.filenameMatch(filename)
+		if content != nil && lang.SniffManifest != nil {
+			if t, c := lang.SniffManifest(filename, content); t != "" {
+				typ, confidence = t, c
+			}
+		}
+		if typ != "" && confidence > 0 {
+			found = append(found, ManifestCandidate{Language: lang, Type: typ, Confidence: confidence})
+		}
+	}
+	slices.SortStableFunc(found, func(a, b ManifestCandidate) int {
+		return cmp.Compare(b.Confidence, a.Confidence)
+	})
+	return found
+}
+
+// DetectManifestFile is [DetectManifests] for a file on disk, sniffing its
+// first [SniffSize] bytes.
+func DetectManifestFile(file string, languages []*Language) ([]ManifestCandidate, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	content, err := io.ReadAll(io.LimitReader(f, SniffSize))
+	if err != nil {
+		return nil, err
+	}
+	return DetectManifests(file, content, languages), nil
+}
+
+// filenameMatch returns the manifest type of a filename and the confidence
+// of the match, or "" when no ManifestAliases key matches.
+func (l *Language) filenameMatch(filename string) (string, float64) {
+	if typ, ok := l.ManifestAliases[filename]; ok {
+		return typ, ConfidenceFilename
+	}
+	if typ, ok := l.ManifestType(filename); ok {
+		return typ, ConfidencePattern
+	}
+	return "", 0
+}
+
+// claimants returns the languages whose ManifestAliases name a file.
+func claimants(languages []*Language, filename string) []*Language {
+	var found []*Language
+	for _, lang := range languages {
+		if _, ok := lang.ManifestType(filename); ok {
+			found = append(found, lang)
+		}
+	}
+	return found
+}
@@ -0,0 +1,82 @@
+package deps
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// denoLanguage claims package.json files that mention Deno.
+func denoLanguage() *Language {
+	return &Language{
+		Name:            "deno",
+		ManifestAliases: map[string]string{"deno.json": "deno", "package.json": "package"},
+		SniffManifest: func(filename string, content []byte) (string, float64) {
+			if filename == "package.json" && bytes.Contains(content, []byte(`"deno"`)) {
+				return "package", 0.9
+			}
+			return "", 0
+		},
+	}
+}
+
+func TestDetectManifests(t *testing.T) {
+	langs := append(detectLanguages(), denoLanguage())
+	tests := []struct {
+		name, filename, content string
+		want                    []string // language:type, most confident first
+	}{
+		{"exact filename", "poetry.lock", "", []string{"python:poetry-lock"}},
+		{"shared filename keeps language order", "package.json", "{}", []string{"javascript:package", "deno:package"}},
+		{"sniffed content wins", "package.json", `{"deno": {}}`, []string{"deno:package", "javascript:package"}},
+		{"path is reduced to its base", "web/deno.json", "", []string{"deno:deno"}},
+		{"unsupported", "README.md", "# hi", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range DetectManifests(tt.filename, []byte(tt.content), langs) {
+				got = append(got, c.Language.Name+":"+c.Type)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("DetectManifests() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectManifests_SnifferClaimsUnaliasedFile(t *testing.T) {
+	python := detectLanguages()[0]
+	python.SniffManifest = func(filename string, _ []byte) (string, float64) {
+		if filename == "requirements-dev.txt" {
+			return "requirements", 0.9
+		}
+		return "", 0
+	}
+	got := DetectManifests("requirements-dev.txt", []byte("pytest\n"), []*Language{python})
+	if len(got) != 1 || got[0].Type != "requirements" || got[0].Confidence != 0.9 {
+		t.Errorf("DetectManifests() = %+v", got)
+	}
+	if got := DetectManifests("requirements-dev.txt", nil, []*Language{python}); len(got) != 0 {
+		t.Errorf("DetectManifests() without content = %+v, want none", got)
+	}
+}
+
+func TestDetectManifestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "package.json")
+	if err := os.WriteFile(path, []byte(`{"name": "app", "deno": {"tasks": {}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := DetectManifestFile(path, append(detectLanguages(), denoLanguage()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Language.Name != "deno" || got[1].Confidence != ConfidenceFilename {
+		t.Errorf("DetectManifestFile() = %+v", got)
+	}
+	if _, err := DetectManifestFile(filepath.Join(t.TempDir(), "missing.json"), nil); err == nil {
+		t.Error("DetectManifestFile() of a missing file succeeded")
+	}
+}
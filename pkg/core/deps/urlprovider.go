@@ -2,14 +2,17 @@ package deps
 
 import (
 	"context"
+	"maps"
 	"strings"
 	"time"
 
 	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/integrations"
 	"github.com/stacktower-io/stacktower/pkg/integrations/crates"
 	"github.com/stacktower-io/stacktower/pkg/integrations/goproxy"
 	"github.com/stacktower-io/stacktower/pkg/integrations/maven"
 	"github.com/stacktower-io/stacktower/pkg/integrations/npm"
+	"github.com/stacktower-io/stacktower/pkg/integrations/nuget"
 	"github.com/stacktower-io/stacktower/pkg/integrations/packagist"
 	"github.com/stacktower-io/stacktower/pkg/integrations/pypi"
 	"github.com/stacktower-io/stacktower/pkg/integrations/rubygems"
@@ -73,202 +76,102 @@ func fetchURLsChunked(
 	return urlMap
 }
 
-// PyPIURLProvider fetches package URLs from PyPI in parallel.
-// The underlying pypi.Client handles rate limiting (50 req/s, burst 30).
-type PyPIURLProvider struct {
-	client  *pypi.Client
+// RegistryURLProvider fetches package URLs from any registry with an
+// [integrations.RegistryClient], in parallel. The underlying client handles
+// rate limiting.
+type RegistryURLProvider struct {
+	client  integrations.RegistryClient
 	workers int
+	// inferRepo derives a repository URL from a package name when the
+	// registry has none, as Go module paths allow.
+	inferRepo func(name string) string
 }
 
-// NewPyPIURLProvider creates a new PyPI URL provider.
-func NewPyPIURLProvider(c cache.Cache, cacheTTL time.Duration) *PyPIURLProvider {
-	return &PyPIURLProvider{
-		client:  pypi.NewClient(c, cacheTTL, ""),
-		workers: DefaultWorkers,
-	}
-}
-
-// FetchURLs fetches repository URLs for the given package names from PyPI.
-// Packages are processed in chunks of 50 for better progress feedback.
-func (p *PyPIURLProvider) FetchURLs(ctx context.Context, names []string, refresh bool) (map[string]PackageURLs, error) {
-	return fetchURLsChunked(ctx, names, p.workers, defaultChunkSize, func(ctx context.Context, name string) urlFetchResult {
-		pkg, err := p.client.FetchPackage(ctx, name, refresh)
-		if err != nil {
-			return urlFetchResult{name: name}
-		}
-		return urlFetchResult{
-			name: name,
-			urls: PackageURLs{
-				ProjectURLs: pkg.ProjectURLs,
-				HomePage:    pkg.HomePage,
-			},
-			ok: true,
-		}
-	}), nil
+// NewRegistryURLProvider creates a URL provider for a registry.
+func NewRegistryURLProvider(client integrations.RegistryClient) *RegistryURLProvider {
+	return &RegistryURLProvider{client: client, workers: DefaultWorkers}
 }
 
-// NpmURLProvider fetches package URLs from npm in parallel.
-// The underlying npm.Client handles rate limiting (50 req/s, burst 30).
-type NpmURLProvider struct {
-	client  *npm.Client
-	workers int
+// NewPyPIURLProvider creates a new PyPI URL provider.
+func NewPyPIURLProvider(c cache.Cache, cacheTTL time.Duration) *RegistryURLProvider {
+	return NewRegistryURLProvider(pypi.NewRegistry(pypi.NewClient(c, cacheTTL, "", "")))
 }
 
 // NewNpmURLProvider creates a new npm URL provider.
-func NewNpmURLProvider(c cache.Cache, cacheTTL time.Duration) *NpmURLProvider {
-	return &NpmURLProvider{
-		client:  npm.NewClient(c, cacheTTL),
-		workers: DefaultWorkers,
-	}
-}
-
-// FetchURLs fetches repository URLs for the given package names from npm.
-// Packages are processed in chunks of 50 for better progress feedback.
-func (p *NpmURLProvider) FetchURLs(ctx context.Context, names []string, refresh bool) (map[string]PackageURLs, error) {
-	return fetchURLsChunked(ctx, names, p.workers, defaultChunkSize, func(ctx context.Context, name string) urlFetchResult {
-		pkg, err := p.client.FetchPackage(ctx, name, refresh)
-		if err != nil {
-			return urlFetchResult{name: name}
-		}
-		projectURLs := make(map[string]string)
-		if pkg.Repository != "" {
-			projectURLs["repository"] = pkg.Repository
-		}
-		if pkg.HomePage != "" {
-			projectURLs["homepage"] = pkg.HomePage
-		}
-		return urlFetchResult{
-			name: name,
-			urls: PackageURLs{
-				ProjectURLs: projectURLs,
-				HomePage:    pkg.HomePage,
-			},
-			ok: true,
-		}
-	}), nil
-}
-
-// CratesURLProvider fetches package URLs from crates.io in parallel.
-// The underlying crates.Client handles rate limiting (5 req/s, burst 10).
-type CratesURLProvider struct {
-	client  *crates.Client
-	workers int
+func NewNpmURLProvider(c cache.Cache, cacheTTL time.Duration) *RegistryURLProvider {
+	return NewRegistryURLProvider(npm.NewRegistry(npm.NewClient(c, cacheTTL)))
 }
 
 // NewCratesURLProvider creates a new crates.io URL provider.
-func NewCratesURLProvider(c cache.Cache, cacheTTL time.Duration) *CratesURLProvider {
-	return &CratesURLProvider{
-		client:  crates.NewClient(c, cacheTTL),
-		workers: DefaultWorkers,
-	}
+func NewCratesURLProvider(c cache.Cache, cacheTTL time.Duration) *RegistryURLProvider {
+	return NewRegistryURLProvider(crates.NewRegistry(crates.NewClient(c, cacheTTL)))
 }
 
-// FetchURLs fetches repository URLs for the given crate names from crates.io.
-// Packages are processed in chunks of 50 for better progress feedback.
-func (p *CratesURLProvider) FetchURLs(ctx context.Context, names []string, refresh bool) (map[string]PackageURLs, error) {
-	return fetchURLsChunked(ctx, names, p.workers, defaultChunkSize, func(ctx context.Context, name string) urlFetchResult {
-		crate, err := p.client.FetchCrate(ctx, name, refresh)
-		if err != nil {
-			return urlFetchResult{name: name}
-		}
-		projectURLs := make(map[string]string)
-		if crate.Repository != "" {
-			projectURLs["repository"] = crate.Repository
-		}
-		if crate.HomePage != "" {
-			projectURLs["homepage"] = crate.HomePage
-		}
-		return urlFetchResult{
-			name: name,
-			urls: PackageURLs{
-				ProjectURLs: projectURLs,
-				HomePage:    crate.HomePage,
-			},
-			ok: true,
-		}
-	}), nil
-}
-
-// RubyGemsURLProvider fetches package URLs from RubyGems in parallel.
-// The underlying rubygems.Client handles rate limiting (30 req/s, burst 20).
-type RubyGemsURLProvider struct {
-	client  *rubygems.Client
-	workers int
+// NewRubyGemsURLProvider creates a new RubyGems URL provider.
+func NewRubyGemsURLProvider(c cache.Cache, cacheTTL time.Duration) *RegistryURLProvider {
+	return NewRegistryURLProvider(rubygems.NewRegistry(rubygems.NewClient(c, cacheTTL)))
 }
 
-// NewRubyGemsURLProvider creates a new RubyGems URL provider.
-func NewRubyGemsURLProvider(c cache.Cache, cacheTTL time.Duration) *RubyGemsURLProvider {
-	return &RubyGemsURLProvider{
-		client:  rubygems.NewClient(c, cacheTTL),
-		workers: DefaultWorkers,
-	}
+// NewGoProxyURLProvider creates a new Go module proxy URL provider. The
+// proxy leaves the repository empty for modules on known hosting
+// platforms, so it is derived from the module path.
+func NewGoProxyURLProvider(c cache.Cache, cacheTTL time.Duration) *RegistryURLProvider {
+	p := NewRegistryURLProvider(goproxy.NewRegistry(goproxy.NewClient(c, cacheTTL)))
+	p.inferRepo = inferGoRepoURL
+	return p
 }
 
-// FetchURLs fetches repository URLs for the given gem names from RubyGems.
-// Packages are processed in chunks of 50 for better progress feedback.
-func (p *RubyGemsURLProvider) FetchURLs(ctx context.Context, names []string, refresh bool) (map[string]PackageURLs, error) {
-	return fetchURLsChunked(ctx, names, p.workers, defaultChunkSize, func(ctx context.Context, name string) urlFetchResult {
-		gem, err := p.client.FetchGem(ctx, name, refresh)
-		if err != nil {
-			return urlFetchResult{name: name}
-		}
-		projectURLs := make(map[string]string)
-		if gem.SourceCodeURI != "" {
-			projectURLs["repository"] = gem.SourceCodeURI
-		}
-		if gem.HomepageURI != "" {
-			projectURLs["homepage"] = gem.HomepageURI
-		}
-		return urlFetchResult{
-			name: name,
-			urls: PackageURLs{
-				ProjectURLs: projectURLs,
-				HomePage:    gem.HomepageURI,
-			},
-			ok: true,
-		}
-	}), nil
+// NewPackagistURLProvider creates a new Packagist URL provider.
+func NewPackagistURLProvider(c cache.Cache, cacheTTL time.Duration) *RegistryURLProvider {
+	return NewRegistryURLProvider(packagist.NewRegistry(packagist.NewClient(c, cacheTTL)))
 }
 
-// GoProxyURLProvider fetches module URLs from Go module proxy in parallel.
-// The underlying goproxy.Client handles rate limiting.
-type GoProxyURLProvider struct {
-	client  *goproxy.Client
-	workers int
+// NewMavenURLProvider creates a new Maven URL provider. Names are
+// "groupId:artifactId" coordinates.
+func NewMavenURLProvider(c cache.Cache, cacheTTL time.Duration) *RegistryURLProvider {
+	return NewRegistryURLProvider(maven.NewRegistry(maven.NewClient(c, cacheTTL)))
 }
 
-// NewGoProxyURLProvider creates a new Go module proxy URL provider.
-func NewGoProxyURLProvider(c cache.Cache, cacheTTL time.Duration) *GoProxyURLProvider {
-	return &GoProxyURLProvider{
-		client:  goproxy.NewClient(c, cacheTTL),
-		workers: DefaultWorkers,
-	}
+// NewNuGetURLProvider creates a new NuGet URL provider. Names are package
+// IDs, matched without regard to case.
+func NewNuGetURLProvider(c cache.Cache, cacheTTL time.Duration) *RegistryURLProvider {
+	return NewRegistryURLProvider(nuget.NewRegistry(nuget.NewClient(c, cacheTTL), ""))
 }
 
-// FetchURLs fetches repository URLs for the given module paths from Go proxy.
-// Packages are processed in chunks of 50 for better progress feedback.
-func (p *GoProxyURLProvider) FetchURLs(ctx context.Context, names []string, refresh bool) (map[string]PackageURLs, error) {
+// FetchURLs fetches repository URLs for the given package names from the
+// registry. Packages are processed in chunks of 50 for better progress
+// feedback.
+func (p *RegistryURLProvider) FetchURLs(ctx context.Context, names []string, refresh bool) (map[string]PackageURLs, error) {
+	opts := integrations.FetchOptions{Refresh: refresh}
 	return fetchURLsChunked(ctx, names, p.workers, defaultChunkSize, func(ctx context.Context, name string) urlFetchResult {
-		mod, err := p.client.FetchModule(ctx, name, refresh)
+		info, err := p.client.FetchPackage(ctx, name, "", opts)
 		if err != nil {
 			return urlFetchResult{name: name}
 		}
-		// FetchModule leaves Repository empty for known hosting platforms
-		// (github.com, gitlab.com, etc.) since the URL can be derived cheaply.
-		// Derive it here for GitHub enrichment to work.
-		repoURL := mod.Repository
-		if repoURL == "" {
-			repoURL = inferGoRepoURL(name)
+		repoURL, homePage := info.Repository, info.HomePage
+		if p.inferRepo != nil {
+			if repoURL == "" {
+				repoURL = p.inferRepo(name)
+			}
+			if homePage == "" {
+				homePage = repoURL
+			}
+		}
+		projectURLs := maps.Clone(info.ProjectURLs)
+		if projectURLs == nil {
+			projectURLs = make(map[string]string)
 		}
-		projectURLs := make(map[string]string)
 		if repoURL != "" {
 			projectURLs["repository"] = repoURL
 		}
+		if homePage != "" {
+			projectURLs["homepage"] = homePage
+		}
 		return urlFetchResult{
 			name: name,
 			urls: PackageURLs{
 				ProjectURLs: projectURLs,
-				HomePage:    repoURL,
+				HomePage:    homePage,
 			},
 			ok: true,
 		}
@@ -299,86 +202,3 @@ func inferGoRepoURL(modulePath string) string {
 	}
 	return ""
 }
-
-// PackagistURLProvider fetches package URLs from Packagist (PHP) in parallel.
-// The underlying packagist.Client handles rate limiting.
-type PackagistURLProvider struct {
-	client  *packagist.Client
-	workers int
-}
-
-// NewPackagistURLProvider creates a new Packagist URL provider.
-func NewPackagistURLProvider(c cache.Cache, cacheTTL time.Duration) *PackagistURLProvider {
-	return &PackagistURLProvider{
-		client:  packagist.NewClient(c, cacheTTL),
-		workers: DefaultWorkers,
-	}
-}
-
-// FetchURLs fetches repository URLs for the given package names from Packagist.
-// Packages are processed in chunks of 50 for better progress feedback.
-func (p *PackagistURLProvider) FetchURLs(ctx context.Context, names []string, refresh bool) (map[string]PackageURLs, error) {
-	return fetchURLsChunked(ctx, names, p.workers, defaultChunkSize, func(ctx context.Context, name string) urlFetchResult {
-		pkg, err := p.client.FetchPackage(ctx, name, refresh)
-		if err != nil {
-			return urlFetchResult{name: name}
-		}
-		projectURLs := make(map[string]string)
-		if pkg.Repository != "" {
-			projectURLs["repository"] = pkg.Repository
-		}
-		if pkg.HomePage != "" {
-			projectURLs["homepage"] = pkg.HomePage
-		}
-		return urlFetchResult{
-			name: name,
-			urls: PackageURLs{
-				ProjectURLs: projectURLs,
-				HomePage:    pkg.HomePage,
-			},
-			ok: true,
-		}
-	}), nil
-}
-
-// MavenURLProvider fetches artifact URLs from Maven Central in parallel.
-// The underlying maven.Client handles rate limiting.
-type MavenURLProvider struct {
-	client  *maven.Client
-	workers int
-}
-
-// NewMavenURLProvider creates a new Maven URL provider.
-func NewMavenURLProvider(c cache.Cache, cacheTTL time.Duration) *MavenURLProvider {
-	return &MavenURLProvider{
-		client:  maven.NewClient(c, cacheTTL),
-		workers: DefaultWorkers,
-	}
-}
-
-// FetchURLs fetches repository URLs for the given artifact coordinates from Maven.
-// Coordinates should be in the format "groupId:artifactId".
-// Packages are processed in chunks of 50 for better progress feedback.
-func (p *MavenURLProvider) FetchURLs(ctx context.Context, names []string, refresh bool) (map[string]PackageURLs, error) {
-	return fetchURLsChunked(ctx, names, p.workers, defaultChunkSize, func(ctx context.Context, name string) urlFetchResult {
-		artifact, err := p.client.FetchArtifact(ctx, name, refresh)
-		if err != nil {
-			return urlFetchResult{name: name}
-		}
-		projectURLs := make(map[string]string)
-		if artifact.Repository != "" {
-			projectURLs["repository"] = artifact.Repository
-		}
-		if artifact.HomePage != "" {
-			projectURLs["homepage"] = artifact.HomePage
-		}
-		return urlFetchResult{
-			name: name,
-			urls: PackageURLs{
-				ProjectURLs: projectURLs,
-				HomePage:    artifact.HomePage,
-			},
-			ok: true,
-		}
-	}), nil
-}
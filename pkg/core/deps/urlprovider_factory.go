@@ -24,6 +24,8 @@ func NewURLProvider(language string, c cache.Cache, cacheTTL time.Duration) URLP
 		return NewPackagistURLProvider(c, cacheTTL)
 	case "java":
 		return NewMavenURLProvider(c, cacheTTL)
+	case "dotnet":
+		return NewNuGetURLProvider(c, cacheTTL)
 	default:
 		return nil
 	}
@@ -1,6 +1,42 @@
 package deps
 
-import "testing"
+import (
+	"context"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+func TestRegistryURLProvider(t *testing.T) {
+	client := fakeRegistry{packages: map[string]*integrations.PackageInfo{
+		"requests@":               {Name: "requests", Version: "2.31.0", HomePage: "https://requests.readthedocs.io", ProjectURLs: map[string]string{"Source": "https://github.com/psf/requests"}},
+		"github.com/spf13/cobra@": {Name: "github.com/spf13/cobra", Version: "v1.8.0"},
+	}}
+	ctx := context.Background()
+
+	urls, err := NewRegistryURLProvider(client).FetchURLs(ctx, []string{"requests", "missing"}, false)
+	if err != nil {
+		t.Fatalf("FetchURLs error: %v", err)
+	}
+	if _, ok := urls["missing"]; ok {
+		t.Error("packages that fail to fetch should be left out")
+	}
+	got := urls["requests"]
+	if got.HomePage != "https://requests.readthedocs.io" || got.ProjectURLs["Source"] != "https://github.com/psf/requests" {
+		t.Errorf("unexpected URLs: %+v", got)
+	}
+	if _, ok := got.ProjectURLs["repository"]; ok {
+		t.Error("no repository should be made up without inferRepo")
+	}
+
+	p := NewRegistryURLProvider(client)
+	p.inferRepo = inferGoRepoURL
+	urls, _ = p.FetchURLs(ctx, []string{"github.com/spf13/cobra"}, false)
+	got = urls["github.com/spf13/cobra"]
+	if got.ProjectURLs["repository"] != "https://github.com/spf13/cobra" || got.HomePage != "https://github.com/spf13/cobra" {
+		t.Errorf("repository should be inferred from the module path, got %+v", got)
+	}
+}
 
 func TestInferGoRepoURL(t *testing.T) {
 	tests := []struct {
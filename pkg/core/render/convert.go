@@ -2,31 +2,90 @@ package render
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 )
 
 // ToPDF converts SVG bytes to PDF using rsvg-convert.
 // Requires librsvg: brew install librsvg (macOS), apt install librsvg2-bin (Linux).
 func ToPDF(svg []byte) ([]byte, error) {
-	return rsvgConvert(svg, "pdf")
+	return ToPDFContext(context.Background(), svg)
 }
 
-// ToPNG converts SVG bytes to PNG using rsvg-convert with the given scale factor.
+// ToPDFContext is like [ToPDF] but stops rsvg-convert when ctx is done.
+func ToPDFContext(ctx context.Context, svg []byte) ([]byte, error) {
+	return rsvgConvert(ctx, svg, "pdf")
+}
+
+// ToPNG converts SVG bytes to PNG with the given scale factor.
 // Scale of 2.0 produces a 2x resolution image.
-// Requires librsvg: brew install librsvg (macOS), apt install librsvg2-bin (Linux).
+//
+// When rsvg-convert (from librsvg) is on PATH it does the conversion, as
+// the fastest and most faithful option; otherwise, or when it fails, the
+// SVG is drawn in process by [Rasterize], so PNG export needs no external
+// tools.
 func ToPNG(svg []byte, scale float64) ([]byte, error) {
-	return rsvgConvert(svg, "png", "-z", fmt.Sprintf("%.2f", scale))
+	return ToPNGContext(context.Background(), svg, scale)
+}
+
+// ToPNGContext is like [ToPNG] but stops the conversion when ctx is done.
+func ToPNGContext(ctx context.Context, svg []byte, scale float64) ([]byte, error) {
+	if _, err := exec.LookPath("rsvg-convert"); err == nil {
+		png, err := rsvgConvert(ctx, svg, "png", "-z", fmt.Sprintf("%.2f", scale))
+		if err == nil || ctx.Err() != nil {
+			return png, err
+		}
+	}
+	return rasterizePNG(ctx, svg, scale)
+}
+
+// ToPDFPages converts several SVG documents into a single multi-page PDF,
+// one page per SVG in the given order. Each page keeps the size of its SVG.
+// Requires librsvg: brew install librsvg (macOS), apt install librsvg2-bin (Linux).
+func ToPDFPages(svgs [][]byte) ([]byte, error) {
+	return ToPDFPagesContext(context.Background(), svgs)
+}
+
+// ToPDFPagesContext is like [ToPDFPages] but stops rsvg-convert when ctx is
+// done.
+func ToPDFPagesContext(ctx context.Context, svgs [][]byte) ([]byte, error) {
+	switch len(svgs) {
+	case 0:
+		return nil, fmt.Errorf("no pages to convert")
+	case 1:
+		return ToPDFContext(ctx, svgs[0])
+	}
+
+	// rsvg-convert only reads one document from stdin, so multi-page output
+	// goes through temporary files passed as positional arguments.
+	dir, err := os.MkdirTemp("", "stacktower-pages-*")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	args := make([]string, 0, len(svgs))
+	for i, svg := range svgs {
+		path := filepath.Join(dir, fmt.Sprintf("page-%04d.svg", i))
+		if err := os.WriteFile(path, svg, 0600); err != nil {
+			return nil, fmt.Errorf("write page %d: %w", i, err)
+		}
+		args = append(args, path)
+	}
+	return rsvgConvertFiles(ctx, "pdf", args...)
 }
 
 // rsvgConvert shells out to rsvg-convert for format conversion.
-func rsvgConvert(svg []byte, format string, extraArgs ...string) ([]byte, error) {
+func rsvgConvert(ctx context.Context, svg []byte, format string, extraArgs ...string) ([]byte, error) {
 	if _, err := exec.LookPath("rsvg-convert"); err != nil {
 		return nil, fmt.Errorf("%s export requires librsvg. Install with:\n  macOS:  brew install librsvg\n  Linux:  apt install librsvg2-bin", format)
 	}
 
 	args := append([]string{"-f", format}, extraArgs...)
-	cmd := exec.Command("rsvg-convert", args...)
+	cmd := exec.CommandContext(ctx, "rsvg-convert", args...)
 	cmd.Stdin = bytes.NewReader(svg)
 
 	var out, errBuf bytes.Buffer
@@ -34,6 +93,31 @@ func rsvgConvert(svg []byte, format string, extraArgs ...string) ([]byte, error)
 	cmd.Stderr = &errBuf
 
 	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("rsvg-convert: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("rsvg-convert: %v: %s", err, errBuf.String())
+	}
+	return out.Bytes(), nil
+}
+
+// rsvgConvertFiles is like rsvgConvert but reads input documents from files.
+func rsvgConvertFiles(ctx context.Context, format string, files ...string) ([]byte, error) {
+	if _, err := exec.LookPath("rsvg-convert"); err != nil {
+		return nil, fmt.Errorf("%s export requires librsvg. Install with:\n  macOS:  brew install librsvg\n  Linux:  apt install librsvg2-bin", format)
+	}
+
+	args := append([]string{"-f", format}, files...)
+	cmd := exec.CommandContext(ctx, "rsvg-convert", args...)
+
+	var out, errBuf bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errBuf
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("rsvg-convert: %w", ctx.Err())
+		}
 		return nil, fmt.Errorf("rsvg-convert: %v: %s", err, errBuf.String())
 	}
 	return out.Bytes(), nil
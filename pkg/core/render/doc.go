@@ -11,14 +11,19 @@
 //
 // # Format Conversion
 //
-// The [ToPDF] and [ToPNG] functions convert any SVG to other formats using
-// the external rsvg-convert tool (from librsvg). These are used by both
-// tower and node-link renderers.
+// The [ToPDF] and [ToPNG] functions convert any SVG to other formats. These
+// are used by both tower and node-link renderers. PDF needs the external
+// rsvg-convert tool (from librsvg); PNG uses it when installed and otherwise
+// draws the SVG in process with [Rasterize], which supports the shapes,
+// paths, text and CSS the renderers emit.
 //
 //	svg := tower.RenderSVG(layout, opts...)
 //	pdf, err := render.ToPDF(svg)
 //	png, err := render.ToPNG(svg, 2.0)  // 2x scale
 //
+// [ToPPTX] packages rendered images as a PowerPoint deck with speaker notes.
+// It writes Office Open XML directly and needs no external tools.
+//
 // # Tower Visualization
 //
 // The [tower] subpackage renders dependency graphs as stacked physical towers
@@ -40,10 +45,17 @@
 //	svg, err := nodelink.RenderSVG(dot)
 //	pdf, err := render.ToPDF(svg)
 //
+// # Testing
+//
+// The [rendertest] subpackage renders layouts deterministically and compares
+// them against golden SVG files, for visual regression tests of custom
+// styles and sinks.
+//
 // [tower]: github.com/stacktower-io/stacktower/pkg/core/render/tower
 // [tower/layout]: github.com/stacktower-io/stacktower/pkg/core/render/tower/layout
 // [tower/ordering]: github.com/stacktower-io/stacktower/pkg/core/render/tower/ordering
 // [tower/sink]: github.com/stacktower-io/stacktower/pkg/core/render/tower/sink
 // [tower/styles]: github.com/stacktower-io/stacktower/pkg/core/render/tower/styles
 // [nodelink]: github.com/stacktower-io/stacktower/pkg/core/render/nodelink
+// [rendertest]: github.com/stacktower-io/stacktower/pkg/core/render/rendertest
 package render
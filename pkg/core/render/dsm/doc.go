@@ -0,0 +1,30 @@
+// Package dsm draws dependency graphs as design structure matrices.
+//
+// A design structure matrix (DSM) lists every package both down the side
+// and across the top; a filled cell means the row's package depends on the
+// column's. Packages are ordered by layer, so in a clean, layered graph all
+// cells sit just above the diagonal. The matrix makes visible what towers
+// hide by construction:
+//
+//   - Cycles: towers break them before layering, the matrix keeps them
+//     and draws their back edges below the diagonal in red.
+//   - Layering violations: dependencies that reach past the next layer
+//     down, which towers route through subdividers, are drawn in amber.
+//
+// # Architecture
+//
+// Like nodelink, a matrix is computed from the graph at render time:
+//
+//	DSM: DAG → Build() → Matrix → RenderSVG() → SVG
+//
+// [Build] walks through subdividers to the real packages, finds cycles as
+// strongly connected components, and layers a cycle-free copy of the graph
+// to order the rows.
+//
+// # Usage
+//
+//	m := dsm.Build(g)
+//	fmt.Println(m.Count(dsm.CellCycle), "dependencies in cycles")
+//
+//	svg, err := dsm.RenderSVG(g, dsm.Options{CellSize: 12})
+package dsm
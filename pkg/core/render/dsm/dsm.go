@@ -0,0 +1,198 @@
+package dsm
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/dag/transform"
+)
+
+// Cell kinds classify the dependency a cell stands for.
+const (
+	// CellDirect is a dependency on the next layer down: the only kind a
+	// strictly layered architecture allows.
+	CellDirect = "direct"
+
+	// CellSkip is a dependency that reaches past the next layer down, a
+	// violation of strict layering.
+	CellSkip = "skip"
+
+	// CellCycle is a dependency between two packages that depend on each
+	// other, directly or through others.
+	CellCycle = "cycle"
+)
+
+// Options configures the matrix rendering.
+type Options struct {
+	// CellSize is the side of a matrix cell in pixels (default 16).
+	CellSize float64
+}
+
+// Cell is one dependency in the matrix: the package of row Row depends on
+// the package of column Col.
+type Cell struct {
+	Row  int    `json:"row"`
+	Col  int    `json:"col"`
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+
+	// Constraint is the version requirement of the dependency, if known.
+	Constraint string `json:"constraint,omitempty"`
+}
+
+// Matrix is a computed design structure matrix. IDs orders both rows and
+// columns; Layers gives each package's layer, in the same order.
+type Matrix struct {
+	IDs    []string `json:"ids"`
+	Layers []int    `json:"layers"`
+	Cells  []Cell   `json:"cells"`
+
+	// Cycles lists the groups of packages that depend on each other, each
+	// sorted by ID, largest group first.
+	Cycles [][]string `json:"cycles,omitempty"`
+}
+
+// Count returns how many cells are of the given kind.
+func (m Matrix) Count(kind string) int {
+	n := 0
+	for _, c := range m.Cells {
+		if c.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// Build computes the design structure matrix of g. Packages are ordered by
+// layer, dependents before their dependencies, and by ID within a layer,
+// so every dependency of an acyclic, strictly layered graph falls just
+// above the diagonal. Cells further right reach past the next layer
+// ([CellSkip]); cells below the diagonal can only come from cycles
+// ([CellCycle]), which towers break before drawing and the matrix keeps.
+//
+// Subdividers and other synthetic nodes are walked through: a dependency
+// routed through subdividers is one cell between its real endpoints.
+func Build(g *dag.DAG) Matrix {
+	work := dag.New(nil)
+	for _, n := range g.Nodes() {
+		if !n.IsSynthetic() {
+			_ = work.AddNode(dag.Node{ID: n.ID})
+		}
+	}
+	constraints := make(map[[2]string]string)
+	for _, e := range g.EdgesIter() {
+		if s, _ := e.Meta["constraint"].(string); s != "" {
+			constraints[[2]string{e.From, e.To}] = s
+		}
+	}
+	type dep struct{ from, to string }
+	var edges []dep
+	for _, n := range work.Nodes() {
+		for _, to := range g.RealChildren(n.ID) {
+			edges = append(edges, dep{n.ID, to})
+			_ = work.AddEdge(dag.Edge{From: n.ID, To: to})
+		}
+	}
+
+	comp, cycles := components(work)
+	layered := work.Clone()
+	transform.BreakCycles(layered)
+	transform.AssignLayers(layered)
+
+	nodes := layered.Nodes()
+	slices.SortFunc(nodes, func(a, b *dag.Node) int {
+		return cmp.Or(cmp.Compare(a.Row, b.Row), cmp.Compare(a.ID, b.ID))
+	})
+	m := Matrix{IDs: make([]string, len(nodes)), Layers: make([]int, len(nodes)), Cycles: cycles}
+	pos := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		m.IDs[i], m.Layers[i], pos[n.ID] = n.ID, n.Row, i
+	}
+
+	for _, e := range edges {
+		c := Cell{
+			Row: pos[e.from], Col: pos[e.to], From: e.from, To: e.to,
+			Kind: CellDirect, Constraint: constraints[[2]string{e.from, e.to}],
+		}
+		switch {
+		case e.from == e.to || (comp[e.from] == comp[e.to] && comp[e.from] >= 0):
+			c.Kind = CellCycle
+		case m.Layers[c.Col]-m.Layers[c.Row] > 1:
+			c.Kind = CellSkip
+		}
+		m.Cells = append(m.Cells, c)
+	}
+	slices.SortFunc(m.Cells, func(a, b Cell) int {
+		return cmp.Or(cmp.Compare(a.Row, b.Row), cmp.Compare(a.Col, b.Col))
+	})
+	return m
+}
+
+// components finds the strongly connected components of g with Tarjan's
+// algorithm. It returns, for each package, the index of its cycle in the
+// returned list, or -1 when it is not part of one.
+func components(g *dag.DAG) (map[string]int, [][]string) {
+	var (
+		index   = make(map[string]int)
+		low     = make(map[string]int)
+		onStack = make(map[string]bool)
+		stack   []string
+		groups  [][]string
+		next    int
+	)
+	var visit func(string)
+	visit = func(id string) {
+		index[id], low[id] = next, next
+		next++
+		stack = append(stack, id)
+		onStack[id] = true
+		for _, c := range g.Children(id) {
+			if _, seen := index[c]; !seen {
+				visit(c)
+				low[id] = min(low[id], low[c])
+			} else if onStack[c] {
+				low[id] = min(low[id], index[c])
+			}
+		}
+		if low[id] != index[id] {
+			return
+		}
+		var group []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			group = append(group, top)
+			if top == id {
+				break
+			}
+		}
+		if len(group) > 1 {
+			slices.Sort(group)
+			groups = append(groups, group)
+		}
+	}
+	ids := dag.NodeIDs(g.Nodes())
+	slices.Sort(ids)
+	for _, id := range ids {
+		if _, seen := index[id]; !seen {
+			visit(id)
+		}
+	}
+
+	slices.SortFunc(groups, func(a, b []string) int {
+		return cmp.Or(cmp.Compare(len(b), len(a)), cmp.Compare(a[0], b[0]))
+	})
+	comp := make(map[string]int, len(ids))
+	for _, id := range ids {
+		comp[id] = -1
+	}
+	for i, group := range groups {
+		for _, id := range group {
+			comp[id] = i
+		}
+	}
+	return comp, groups
+}
@@ -0,0 +1,107 @@
+package dsm
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func layeredGraph() *dag.DAG {
+	g := dag.New(nil)
+	for _, id := range []string{"app", "web", "orm", "db", "util"} {
+		g.AddNode(dag.Node{ID: id})
+	}
+	g.AddEdge(dag.Edge{From: "app", To: "web", Meta: dag.Metadata{"constraint": "^2.0"}})
+	g.AddEdge(dag.Edge{From: "app", To: "orm"})
+	g.AddEdge(dag.Edge{From: "web", To: "util"})
+	g.AddEdge(dag.Edge{From: "orm", To: "db"})
+	g.AddEdge(dag.Edge{From: "app", To: "db"})
+	return g
+}
+
+func TestBuild(t *testing.T) {
+	m := Build(layeredGraph())
+	if want := []string{"app", "orm", "web", "db", "util"}; !slices.Equal(m.IDs, want) {
+		t.Fatalf("IDs = %v, want %v", m.IDs, want)
+	}
+	if want := []int{0, 1, 1, 2, 2}; !slices.Equal(m.Layers, want) {
+		t.Errorf("Layers = %v, want %v", m.Layers, want)
+	}
+	if len(m.Cells) != 5 || len(m.Cycles) != 0 {
+		t.Fatalf("Build() = %d cells, %d cycles; want 5, 0", len(m.Cells), len(m.Cycles))
+	}
+	for _, c := range m.Cells {
+		if c.Row >= c.Col {
+			t.Errorf("%s -> %s at (%d, %d) is not above the diagonal", c.From, c.To, c.Row, c.Col)
+		}
+		want := CellDirect
+		if c.From == "app" && c.To == "db" {
+			want = CellSkip
+		}
+		if c.Kind != want {
+			t.Errorf("%s -> %s kind = %s, want %s", c.From, c.To, c.Kind, want)
+		}
+	}
+	if c := m.Cells[1]; c.To != "web" || c.Constraint != "^2.0" {
+		t.Errorf("second cell = %+v, want app -> web with its constraint", c)
+	}
+}
+
+func TestBuild_Cycles(t *testing.T) {
+	g := dag.New(nil)
+	for _, id := range []string{"a", "b", "c", "d", "self"} {
+		g.AddNode(dag.Node{ID: id})
+	}
+	g.AddEdge(dag.Edge{From: "a", To: "b"})
+	g.AddEdge(dag.Edge{From: "b", To: "c"})
+	g.AddEdge(dag.Edge{From: "c", To: "a"})
+	g.AddEdge(dag.Edge{From: "c", To: "d"})
+	g.AddEdge(dag.Edge{From: "self", To: "self"})
+
+	m := Build(g)
+	if len(m.IDs) != 5 {
+		t.Fatalf("IDs = %v, want all five packages", m.IDs)
+	}
+	if len(m.Cycles) != 1 || !slices.Equal(m.Cycles[0], []string{"a", "b", "c"}) {
+		t.Errorf("Cycles = %v, want [[a b c]]", m.Cycles)
+	}
+	if got := m.Count(CellCycle); got != 4 {
+		t.Errorf("Count(cycle) = %d, want 4 (three in a-b-c plus the self-loop)", got)
+	}
+	below := 0
+	for _, c := range m.Cells {
+		if c.Row > c.Col {
+			below++
+			if c.Kind != CellCycle {
+				t.Errorf("%s -> %s below the diagonal is %s, want cycle", c.From, c.To, c.Kind)
+			}
+		}
+		if c.From == "c" && c.To == "d" && c.Kind == CellCycle {
+			t.Error("c -> d leaves the cycle and should not be marked")
+		}
+	}
+	if below != 1 {
+		t.Errorf("%d cells below the diagonal, want the one back edge", below)
+	}
+}
+
+func TestBuild_SkipsSubdividers(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app"})
+	g.AddNode(dag.Node{ID: "lib"})
+	g.AddNode(dag.Node{ID: "deep"})
+	g.AddNode(dag.Node{ID: "deep_sub_1", Kind: dag.NodeKindSubdivider, MasterID: "deep"})
+	g.AddEdge(dag.Edge{From: "app", To: "lib"})
+	g.AddEdge(dag.Edge{From: "lib", To: "deep"})
+	g.AddEdge(dag.Edge{From: "app", To: "deep_sub_1"})
+	g.AddEdge(dag.Edge{From: "deep_sub_1", To: "deep"})
+
+	m := Build(g)
+	if !slices.Equal(m.IDs, []string{"app", "lib", "deep"}) {
+		t.Fatalf("IDs = %v, want subdividers dropped", m.IDs)
+	}
+	if m.Count(CellSkip) != 1 || len(m.Cells) != 3 {
+		t.Errorf("cells = %+v, want app -> deep as one skip cell", m.Cells)
+	}
+}
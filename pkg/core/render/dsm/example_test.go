@@ -0,0 +1,35 @@
+package dsm_test
+
+import (
+	"fmt"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/dsm"
+)
+
+func ExampleBuild() {
+	g := dag.New(nil)
+	for _, id := range []string{"app", "api", "db", "log"} {
+		_ = g.AddNode(dag.Node{ID: id})
+	}
+	_ = g.AddEdge(dag.Edge{From: "app", To: "api"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "db"}) // reaches past api's layer
+	_ = g.AddEdge(dag.Edge{From: "api", To: "db"})
+	_ = g.AddEdge(dag.Edge{From: "db", To: "log"})
+	_ = g.AddEdge(dag.Edge{From: "log", To: "db"}) // closes a cycle
+
+	m := dsm.Build(g)
+	fmt.Println(m.IDs)
+	for _, c := range m.Cells {
+		fmt.Printf("%s -> %s: %s\n", c.From, c.To, c.Kind)
+	}
+	fmt.Println("cycles:", m.Cycles)
+	// Output:
+	// [app api db log]
+	// app -> api: direct
+	// app -> db: skip
+	// api -> db: direct
+	// db -> log: cycle
+	// log -> db: cycle
+	// cycles: [[db log]]
+}
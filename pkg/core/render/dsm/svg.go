@@ -0,0 +1,158 @@
+package dsm
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+)
+
+const (
+	defaultCellSize = 16.0
+	fontSize        = 11.0
+	padding         = 10.0
+	legendHeight    = 36.0
+
+	// maxLabelChars caps row and column labels; longer names are
+	// ellipsized and keep their full form in the tooltip.
+	maxLabelChars = 32
+
+	legendNote = "rows depend on columns"
+)
+
+// cellColors are the fills of each cell kind, with their legend text.
+var cellColors = []struct{ kind, fill, label string }{
+	{CellDirect, "#2563eb", "next layer"},
+	{CellSkip, "#f59e0b", "skips layers"},
+	{CellCycle, "#dc2626", "cycle"},
+}
+
+// RenderSVG computes the matrix for g with [Build] and draws it as SVG.
+// Rows depend on columns. Each layer's block on the diagonal is shaded so
+// layering reads at a glance, packages caught in cycles are labelled red,
+// and every cell carries a tooltip naming the dependency and its version
+// constraint. A legend counts the cells of each kind.
+func RenderSVG(g *dag.DAG, opts Options) ([]byte, error) {
+	m := Build(g)
+	if len(m.IDs) == 0 {
+		return nil, fmt.Errorf("dsm: graph has no packages")
+	}
+	cs := opts.CellSize
+	if cs <= 0 {
+		cs = defaultCellSize
+	}
+
+	labels := make([]string, len(m.IDs))
+	var longest float64
+	for i, id := range m.IDs {
+		labels[i] = ellipsize(id, maxLabelChars)
+		longest = max(longest, styles.TextWidth(labels[i], fontSize))
+	}
+	ox, oy := padding+longest+6, padding+longest+6
+	side := float64(len(m.IDs)) * cs
+	legend := make([]string, len(cellColors))
+	legendWidth := 2*padding + styles.TextWidth(legendNote, fontSize)
+	for i, k := range cellColors {
+		legend[i] = fmt.Sprintf("%s (%d)", k.label, m.Count(k.kind))
+		legendWidth += 14 + styles.TextWidth(legend[i], fontSize) + 16
+	}
+	width, height := max(ox+side+padding, legendWidth), oy+side+legendHeight
+
+	inCycle := make(map[string]bool)
+	for _, group := range m.Cycles {
+		for _, id := range group {
+			inCycle[id] = true
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %.0f %.0f" width="%.0f" height="%.0f">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&buf, `  <style>text { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; font-size: %.0fpx; fill: #1f2937; } text.cycle { fill: #dc2626; } .cell:hover { stroke: #111827; stroke-width: 1.5; }</style>`+"\n", fontSize)
+	fmt.Fprintf(&buf, `  <rect width="%.0f" height="%.0f" fill="white"/>`+"\n", width, height)
+
+	// Layer blocks on the diagonal.
+	for start := 0; start < len(m.IDs); {
+		end := start
+		for end+1 < len(m.IDs) && m.Layers[end+1] == m.Layers[start] {
+			end++
+		}
+		fill := "#f3f4f6"
+		if m.Layers[start]%2 == 1 {
+			fill = "#e5e7eb"
+		}
+		fmt.Fprintf(&buf, `  <rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"><title>layer %d</title></rect>`+"\n",
+			ox+float64(start)*cs, oy+float64(start)*cs, float64(end-start+1)*cs, float64(end-start+1)*cs, fill, m.Layers[start])
+		start = end + 1
+	}
+
+	// Grid and labels.
+	buf.WriteString(`  <g stroke="#e5e7eb" stroke-width="0.5">` + "\n")
+	for i := 0; i <= len(m.IDs); i++ {
+		at := float64(i) * cs
+		fmt.Fprintf(&buf, `    <line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f"/>`+"\n", ox, oy+at, ox+side, oy+at)
+		fmt.Fprintf(&buf, `    <line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f"/>`+"\n", ox+at, oy, ox+at, oy+side)
+	}
+	buf.WriteString("  </g>\n")
+	for i, id := range m.IDs {
+		class := ""
+		if inCycle[id] {
+			class = ` class="cycle"`
+		}
+		mid := float64(i)*cs + cs/2
+		fmt.Fprintf(&buf, `  <text x="%.2f" y="%.2f" text-anchor="end" dominant-baseline="central"%s>%s<title>%s</title></text>`+"\n",
+			ox-4, oy+mid, class, styles.EscapeXML(labels[i]), styles.EscapeXML(id))
+		fmt.Fprintf(&buf, `  <text x="%.2f" y="%.2f" dominant-baseline="central" transform="rotate(-90 %.2f %.2f)"%s>%s<title>%s</title></text>`+"\n",
+			ox+mid, oy-4, ox+mid, oy-4, class, styles.EscapeXML(labels[i]), styles.EscapeXML(id))
+	}
+
+	// Cells.
+	for _, c := range m.Cells {
+		fill := cellColors[0].fill
+		for _, k := range cellColors {
+			if k.kind == c.Kind {
+				fill = k.fill
+			}
+		}
+		fmt.Fprintf(&buf, `  <rect class="cell" data-from="%s" data-to="%s" x="%.2f" y="%.2f" width="%.2f" height="%.2f" rx="2" fill="%s"><title>%s</title></rect>`+"\n",
+			styles.EscapeXML(c.From), styles.EscapeXML(c.To),
+			ox+float64(c.Col)*cs+1, oy+float64(c.Row)*cs+1, cs-2, cs-2, fill, styles.EscapeXML(cellTooltip(c)))
+	}
+
+	// Legend.
+	x, y := padding, oy+side+legendHeight/2
+	for i, k := range cellColors {
+		fmt.Fprintf(&buf, `  <rect x="%.2f" y="%.2f" width="10" height="10" rx="2" fill="%s"/>`+"\n", x, y-5, k.fill)
+		fmt.Fprintf(&buf, `  <text x="%.2f" y="%.2f" dominant-baseline="central">%s</text>`+"\n", x+14, y, legend[i])
+		x += 14 + styles.TextWidth(legend[i], fontSize) + 16
+	}
+	fmt.Fprintf(&buf, `  <text x="%.2f" y="%.2f" dominant-baseline="central" style="fill: #6b7280">%s</text>`+"\n", x, y, legendNote)
+	buf.WriteString("</svg>\n")
+	return buf.Bytes(), nil
+}
+
+// cellTooltip describes a dependency: "from → to", its constraint, and why
+// it is flagged.
+func cellTooltip(c Cell) string {
+	s := c.From + " → " + c.To
+	if c.Constraint != "" {
+		s += " (" + c.Constraint + ")"
+	}
+	switch c.Kind {
+	case CellSkip:
+		s += "\nskips layers"
+	case CellCycle:
+		s += "\npart of a dependency cycle"
+	}
+	return s
+}
+
+// ellipsize shortens s to at most n characters, ending in "…".
+func ellipsize(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
@@ -0,0 +1,52 @@
+package dsm
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestRenderSVG(t *testing.T) {
+	g := layeredGraph()
+	g.AddEdge(dag.Edge{From: "db", To: "orm"})
+
+	svg, err := RenderSVG(g, Options{CellSize: 20})
+	if err != nil {
+		t.Fatalf("RenderSVG() error: %v", err)
+	}
+	if err := xml.Unmarshal(svg, new(struct{})); err != nil {
+		t.Fatalf("RenderSVG() produced invalid XML: %v", err)
+	}
+	out := string(svg)
+	for _, want := range []string{
+		`data-from="app" data-to="web"`,
+		"app → web (^2.0)",
+		"skips layers",
+		"part of a dependency cycle",
+		`class="cycle">orm<`,
+		"cycle (2)",
+		"rows depend on columns",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderSVG() missing %s", want)
+		}
+	}
+	if got := strings.Count(out, `class="cell"`); got != 6 {
+		t.Errorf("RenderSVG() drew %d cells, want 6", got)
+	}
+
+	if _, err := RenderSVG(dag.New(nil), Options{}); err == nil {
+		t.Error("RenderSVG() accepted an empty graph")
+	}
+}
+
+func TestEllipsize(t *testing.T) {
+	if got := ellipsize("short", 10); got != "short" {
+		t.Errorf("ellipsize() = %q", got)
+	}
+	if got := ellipsize("@scope/very-long-name", 8); got != "@scope/…" {
+		t.Errorf("ellipsize() = %q", got)
+	}
+}
@@ -40,19 +40,67 @@
 //	dot := nodelink.ToDOT(g, nodelink.Options{})
 //	svg, _ := nodelink.RenderSVG(dot)
 //
-// Via the API:
+// Via the API (see package server):
 //
 //	// Step 1: Parse
-//	POST /api/v1/parse {language: "python", package: "requests"}
-//	// → graph.json
+//	POST /api/v1/parse {"language": "python", "package": "requests"}
+//	// → job A with graph.json
 //
 //	// Step 2: Layout
-//	POST /api/v1/layout {graph_path: "job-123/graph.json", viz_type: "nodelink"}
-//	// → layout.dot
+//	POST /api/v1/layout {"graph_job": "A", "viz_type": "nodelink"}
+//	// → job B with layout.json (embedding the DOT source)
 //
 //	// Step 3: Export
-//	POST /api/v1/export {layout_path: "job-456/layout.dot", formats: ["svg"]}
-//	// → nodelink.svg
+//	POST /api/v1/export {"layout_job": "B", "formats": ["svg"]}
+//	// → job C with nodelink.svg
+//
+// # Clusters
+//
+// Large diagrams are easier to navigate when related packages sit together.
+// Options.ClusterBy groups nodes sharing a metadata value into labeled
+// Graphviz subgraph clusters:
+//
+//	dot := nodelink.ToDOT(g, nodelink.Options{ClusterBy: "owner"})
+//
+// "owner" and "language" read the GitHub enrichment (repo_owner,
+// repo_language); any other value is used as the metadata key, e.g.
+// "workspace" for graphs annotated with workspace members.
+//
+// # Risk Styling
+//
+// Node-link diagrams tell the same risk story as towers. Vulnerable
+// packages are filled dark orange and copyleft packages purple; brittle
+// packages (see feature.IsBrittle) get a thick red outline; and with
+// Options.ColorByHealth the remaining nodes are filled by their health
+// score. Every enriched node carries a tooltip (description, health,
+// vulnerability, license, brittleness) and links to its repository, and a
+// legend below the graph lists the encodings that appear.
+//
+// # Edges
+//
+// Edges are plain arrows by default. Options.EdgeLabels writes each edge's
+// "label" metadata, or else its version constraint (or pinned version,
+// plus any environment marker), as its label, and Options.EdgeKinds draws dev and peer dependencies dashed
+// and optional ones dotted; see EdgeKind for the metadata it reads.
+//
+// # Interactive HTML
+//
+// RenderHTML wraps a rendered SVG in a standalone page for exploring large
+// graphs: hovering a node lights up its dependencies and dependents and
+// dims the rest, a search box filters by package name, and enriched nodes
+// link to their repositories.
+//
+//	svg, _ := nodelink.RenderSVG(dot)
+//	page, _ := nodelink.RenderHTML(svg, "flask")
+//
+// # Layered Engine
+//
+// RenderSVG depends on the Graphviz WASM runtime. RenderLayeredSVG draws
+// the same diagram in pure Go by reusing the tower's layering and
+// barycentric ordering; the pipeline selects it with EngineLayered and
+// falls back to it whenever Graphviz fails:
+//
+//	svg, err := nodelink.RenderLayeredSVG(g, nodelink.Options{EdgeKinds: true})
 //
 // # Subdividers
 //
@@ -14,6 +14,7 @@ import (
 	"github.com/goccy/go-graphviz"
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
 	"github.com/stacktower-io/stacktower/pkg/core/render"
 	"github.com/stacktower-io/stacktower/pkg/fonts"
 	"github.com/stacktower-io/stacktower/pkg/security"
@@ -29,6 +30,27 @@ type Options struct {
 	// Detailed includes row numbers and metadata in node labels.
 	// When false, only the node ID is shown.
 	Detailed bool
+
+	// ClusterBy groups nodes sharing a metadata value into labeled Graphviz
+	// subgraph clusters. "owner" and "language" are shorthands for the
+	// repo_owner and repo_language enrichment keys; any other value, such
+	// as "workspace", names a metadata key directly. Nodes without the key
+	// stay outside every cluster. Empty disables clustering.
+	ClusterBy string
+
+	// ColorByHealth fills nodes by their composite health score (see
+	// [feature.NodeHealth]) unless a vulnerability or license-risk fill
+	// applies.
+	ColorByHealth bool
+
+	// EdgeLabels labels edges with their "label" metadata, or else their
+	// version constraint (or pinned version) and environment marker when
+	// the edge metadata has them.
+	EdgeLabels bool
+
+	// EdgeKinds draws dev and optional dependencies dashed and dotted, and
+	// peer dependencies dashed blue (see [EdgeKind]).
+	EdgeKinds bool
 }
 
 // ToDOT converts a DAG to Graphviz DOT format for node-link visualization.
@@ -36,6 +58,11 @@ type Options struct {
 //
 // Subdivider nodes (created by [dag/transform.Subdivide]) are rendered with dashed
 // outlines and grey fill to distinguish them from regular nodes.
+//
+// Risk metadata is styled as in the tower: vulnerable and copyleft packages
+// are filled, brittle packages get a red outline, every enriched node carries
+// a tooltip and links to its repository, and a legend explains the
+// encodings that appear.
 func ToDOT(g *dag.DAG, opts Options) string {
 	var buf bytes.Buffer
 	buf.WriteString("digraph G {\n")
@@ -46,14 +73,39 @@ func ToDOT(g *dag.DAG, opts Options) string {
 	buf.WriteString("  nodesep=0.3;\n")
 	buf.WriteString("\n")
 
+	clusters, order := clusterNodes(g, opts.ClusterBy)
 	for _, n := range g.Nodes() {
-		label := fmtLabel(*n, opts.Detailed)
-		attrs := fmtAttrs(*n, label)
-		fmt.Fprintf(&buf, "  %q [%s];\n", n.ID, strings.Join(attrs, ", "))
+		if _, ok := clusters[n.ID]; !ok {
+			writeNode(&buf, "  ", n, opts)
+		}
+	}
+	members := make(map[string]int)
+	for _, v := range clusters {
+		members[v]++
 	}
+	for i, value := range order {
+		fmt.Fprintf(&buf, "\n  subgraph \"cluster_%d\" {\n", i)
+		fmt.Fprintf(&buf, "    label=%q;\n", fmt.Sprintf("%s (%d)", value, members[value]))
+		buf.WriteString("    style=\"rounded,dashed\";\n")
+		buf.WriteString("    color=\"#9ca3af\";\n")
+		buf.WriteString("    fontname=\"sans-serif\";\n")
+		buf.WriteString("    fontsize=28;\n")
+		for _, n := range g.Nodes() {
+			if clusters[n.ID] == value {
+				writeNode(&buf, "    ", n, opts)
+			}
+		}
+		buf.WriteString("  }\n")
+	}
+
+	writeLegend(&buf, g, opts)
 
 	buf.WriteString("\n")
 	for _, e := range g.Edges() {
+		if attrs := edgeAttrs(g, e, opts); len(attrs) > 0 {
+			fmt.Fprintf(&buf, "  %q -> %q [%s];\n", e.From, e.To, strings.Join(attrs, ", "))
+			continue
+		}
 		fmt.Fprintf(&buf, "  %q -> %q;\n", e.From, e.To)
 	}
 
@@ -61,6 +113,60 @@ func ToDOT(g *dag.DAG, opts Options) string {
 	return buf.String()
 }
 
+func writeNode(buf *bytes.Buffer, indent string, n *dag.Node, opts Options) {
+	label := fmtLabel(*n, opts.Detailed)
+	attrs := fmtAttrs(*n, label)
+	attrs = append(attrs, riskAttrs(*n, attrs, opts)...)
+	fmt.Fprintf(buf, "%s%q [%s];\n", indent, n.ID, strings.Join(attrs, ", "))
+}
+
+// clusterAliases maps ClusterBy shorthands to metadata keys.
+var clusterAliases = map[string]string{
+	"owner":    metadata.RepoOwner,
+	"language": metadata.RepoLanguage,
+}
+
+// clusterNodes assigns each node to the cluster named by its value for the
+// ClusterBy key and returns the assignment plus the cluster names sorted.
+// Subdividers join the cluster of the node they extend.
+func clusterNodes(g *dag.DAG, by string) (map[string]string, []string) {
+	if by == "" {
+		return nil, nil
+	}
+	key := by
+	if k, ok := clusterAliases[by]; ok {
+		key = k
+	}
+	valueOf := func(n *dag.Node) string {
+		if n.MasterID != "" {
+			if m, ok := g.Node(n.MasterID); ok {
+				n = m
+			}
+		}
+		if v, ok := n.Meta[key]; ok && v != nil {
+			return strings.TrimSpace(fmt.Sprint(v))
+		}
+		return ""
+	}
+
+	clusters := make(map[string]string)
+	seen := make(map[string]bool)
+	var order []string
+	for _, n := range g.Nodes() {
+		v := valueOf(n)
+		if v == "" {
+			continue
+		}
+		clusters[n.ID] = v
+		if !seen[v] {
+			seen[v] = true
+			order = append(order, v)
+		}
+	}
+	slices.Sort(order)
+	return clusters, order
+}
+
 func fmtLabel(n dag.Node, detailed bool) string {
 	if !detailed {
 		return n.ID
@@ -194,8 +300,14 @@ func normalizeViewBox(svg []byte) []byte {
 		return svg
 	}
 
-	newSvg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %.2f %.2f" width="%.0f" height="%.0f">`,
-		w, h, w, h)
+	// Links and tooltips use xlink attributes, whose namespace is declared
+	// on the tag being replaced.
+	xlink := ""
+	if bytes.Contains(svg, []byte("xlink:")) {
+		xlink = ` xmlns:xlink="http://www.w3.org/1999/xlink"`
+	}
+	newSvg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg"%s viewBox="0 0 %.2f %.2f" width="%.0f" height="%.0f">`,
+		xlink, w, h, w, h)
 
 	return svgTagRe.ReplaceAll(svg, []byte(newSvg))
 }
@@ -216,8 +328,6 @@ func RenderPDF(dot string) ([]byte, error) {
 // This is a convenience wrapper around [RenderSVG] and [render.ToPNG].
 //
 // A scale of 2.0 produces a 2x resolution image suitable for high-DPI displays.
-//
-// Requires librsvg: brew install librsvg (macOS), apt install librsvg2-bin (Linux).
 func RenderPNG(dot string, scale float64) ([]byte, error) {
 	svg, err := RenderSVG(dot)
 	if err != nil {
@@ -221,3 +221,40 @@ func TestRenderSVG_InvalidDOT(t *testing.T) {
 		t.Error("RenderSVG() should return error for invalid DOT")
 	}
 }
+
+func TestToDOT_ClusterBy(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Row: 0})
+	g.AddNode(dag.Node{ID: "requests", Row: 1, Meta: dag.Metadata{"repo_owner": "psf"}})
+	g.AddNode(dag.Node{ID: "black", Row: 1, Meta: dag.Metadata{"repo_owner": "psf"}})
+	g.AddNode(dag.Node{ID: "click", Row: 2, Meta: dag.Metadata{"repo_owner": "pallets"}})
+	g.AddNode(dag.Node{ID: "app_sub_1", Row: 1, Kind: dag.NodeKindSubdivider, MasterID: "click"})
+	g.AddEdge(dag.Edge{From: "app", To: "requests"})
+	g.AddEdge(dag.Edge{From: "app", To: "black"})
+	g.AddEdge(dag.Edge{From: "black", To: "click"})
+
+	dot := ToDOT(g, Options{ClusterBy: "owner"})
+
+	for _, want := range []string{
+		`subgraph "cluster_0" {`,
+		`label="pallets (2)";`,
+		`subgraph "cluster_1" {`,
+		`label="psf (2)";`,
+		`"black" -> "click";`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("ToDOT() missing %q:\n%s", want, dot)
+		}
+	}
+	// Unclustered nodes are declared before any cluster.
+	if strings.Index(dot, `"app" [`) > strings.Index(dot, "subgraph") {
+		t.Error("ToDOT() declared unclustered node inside a cluster")
+	}
+	if got := strings.Count(dot, "subgraph"); got != 2 {
+		t.Errorf("ToDOT() has %d clusters, want 2", got)
+	}
+
+	if dot := ToDOT(g, Options{ClusterBy: "workspace"}); strings.Contains(dot, "subgraph") {
+		t.Error("ToDOT() clustered by a key no node has")
+	}
+}
@@ -0,0 +1,108 @@
+package nodelink
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+// Dependency kinds recognized by [EdgeKind].
+const (
+	EdgeKindRuntime  = ""
+	EdgeKindDev      = "dev"
+	EdgeKindOptional = "optional"
+	EdgeKindPeer     = "peer"
+)
+
+// edgeKindStyle is how one non-runtime dependency kind is drawn; sample
+// imitates the line in the legend.
+type edgeKindStyle struct {
+	style, color, sample string
+}
+
+// edgeKindStyles lists the non-runtime dependency kinds in legend order.
+var edgeKindStyles = []struct {
+	kind string
+	edgeKindStyle
+}{
+	{EdgeKindDev, edgeKindStyle{"dashed", "#6b7280", "- - -"}},      // gray-500
+	{EdgeKindOptional, edgeKindStyle{"dotted", "#6b7280", "· · ·"}}, // gray-500
+	{EdgeKindPeer, edgeKindStyle{"dashed", "#2563eb", "- - -"}},     // blue-600
+}
+
+// EdgeKind classifies a dependency edge from its metadata: a "kind",
+// "scope" or "type" string (dev, test, optional, peer, ...) or a true "dev",
+// "optional" or "peer" flag. Edges without either inherit a "dev" flag set
+// on the target node by lock-file parsers. Runtime edges return
+// [EdgeKindRuntime].
+func EdgeKind(g *dag.DAG, e dag.Edge) string {
+	for _, key := range []string{dag.EdgeMetaKind, "scope", "type"} {
+		if s, ok := e.Meta[key].(string); ok {
+			switch strings.ToLower(s) {
+			case "dev", "development", "test", "tests":
+				return EdgeKindDev
+			case "optional", "extra":
+				return EdgeKindOptional
+			case "peer":
+				return EdgeKindPeer
+			}
+		}
+	}
+	for _, kind := range []string{EdgeKindDev, EdgeKindOptional, EdgeKindPeer} {
+		if v, _ := e.Meta[kind].(bool); v {
+			return kind
+		}
+	}
+	if n, ok := g.Node(e.To); ok {
+		if v, _ := n.Meta["dev"].(bool); v {
+			return EdgeKindDev
+		}
+	}
+	return EdgeKindRuntime
+}
+
+// edgeAttrs returns the DOT attributes for an edge: a label with its version
+// constraint when [Options.EdgeLabels] is set, and a line style for its
+// dependency kind when [Options.EdgeKinds] is set.
+func edgeAttrs(g *dag.DAG, e dag.Edge, opts Options) []string {
+	var attrs []string
+	if opts.EdgeLabels {
+		if label := edgeLabel(e); label != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%q", label), "fontsize=16", `fontcolor="#4b5563"`)
+		}
+	}
+	if opts.EdgeKinds {
+		kind := EdgeKind(g, e)
+		for _, s := range edgeKindStyles {
+			if s.kind == kind {
+				attrs = append(attrs,
+					fmt.Sprintf("style=%q", s.style),
+					fmt.Sprintf("color=%q", s.color),
+					fmt.Sprintf("tooltip=%q", kind+" dependency"),
+				)
+			}
+		}
+	}
+	return attrs
+}
+
+// edgeLabel returns the label set on an edge, or else its version
+// constraint, falling back to the pinned version, followed by its
+// environment marker if any.
+func edgeLabel(e dag.Edge) string {
+	if label, _ := e.Meta[dag.EdgeMetaLabel].(string); label != "" {
+		return label
+	}
+	label, _ := e.Meta[dag.EdgeMetaConstraint].(string)
+	if label == "" {
+		label, _ = e.Meta["version"].(string)
+	}
+	if marker, _ := e.Meta["marker"].(string); marker != "" {
+		if label != "" {
+			label += "\n"
+		}
+		label += marker
+	}
+	return label
+}
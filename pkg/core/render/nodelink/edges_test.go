@@ -0,0 +1,84 @@
+package nodelink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func edgeGraph() *dag.DAG {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Row: 0})
+	g.AddNode(dag.Node{ID: "lib", Row: 1})
+	g.AddNode(dag.Node{ID: "jest", Row: 1})
+	g.AddNode(dag.Node{ID: "react", Row: 1})
+	g.AddNode(dag.Node{ID: "colors", Row: 1})
+	g.AddNode(dag.Node{ID: "eslint", Row: 1, Meta: dag.Metadata{"dev": true}})
+	g.AddEdge(dag.Edge{From: "app", To: "lib", Meta: dag.Metadata{"constraint": "^1.2.0"}})
+	g.AddEdge(dag.Edge{From: "app", To: "jest", Meta: dag.Metadata{"scope": "test", "version": "29.7.0"}})
+	g.AddEdge(dag.Edge{From: "app", To: "react", Meta: dag.Metadata{"peer": true}})
+	g.AddEdge(dag.Edge{From: "app", To: "colors", Meta: dag.Metadata{"type": "optional", "constraint": ">=1.4", "marker": "python_version < \"3.9\""}})
+	g.AddEdge(dag.Edge{From: "app", To: "eslint"})
+	return g
+}
+
+func TestEdgeKind(t *testing.T) {
+	g := edgeGraph()
+	want := map[string]string{
+		"lib":    EdgeKindRuntime,
+		"jest":   EdgeKindDev,
+		"react":  EdgeKindPeer,
+		"colors": EdgeKindOptional,
+		"eslint": EdgeKindDev,
+	}
+	for _, e := range g.Edges() {
+		if got := EdgeKind(g, e); got != want[e.To] {
+			t.Errorf("EdgeKind(%s) = %q, want %q", e.To, got, want[e.To])
+		}
+	}
+}
+
+func TestEdgeLabel(t *testing.T) {
+	tests := []struct {
+		meta dag.Metadata
+		want string
+	}{
+		{nil, ""},
+		{dag.Metadata{"constraint": "^1.2.0", "version": "1.4.0"}, "^1.2.0"},
+		{dag.Metadata{"version": "29.7.0"}, "29.7.0"},
+		{dag.Metadata{"constraint": ">=1.4", "marker": "extra == 'x'"}, ">=1.4\nextra == 'x'"},
+		{dag.Metadata{"label": "calls 40x", "constraint": "^1.2.0"}, "calls 40x"},
+	}
+	for _, tt := range tests {
+		if got := edgeLabel(dag.Edge{Meta: tt.meta}); got != tt.want {
+			t.Errorf("edgeLabel(%v) = %q, want %q", tt.meta, got, tt.want)
+		}
+	}
+}
+
+func TestToDOT_Edges(t *testing.T) {
+	g := edgeGraph()
+
+	plain := ToDOT(g, Options{})
+	if !strings.Contains(plain, `"app" -> "jest";`) || strings.Contains(plain, "dependency") {
+		t.Errorf("ToDOT() styled edges without options:\n%s", plain)
+	}
+
+	dot := ToDOT(g, Options{EdgeLabels: true, EdgeKinds: true})
+	for _, want := range []string{
+		`"app" -> "lib" [label="^1.2.0"`,
+		`"app" -> "jest" [label="29.7.0", fontsize=16, fontcolor="#4b5563", style="dashed"`,
+		`"app" -> "react" [style="dashed", color="#2563eb"`,
+		`"app" -> "colors" [label=">=1.4\npython_version < \"3.9\""`,
+		`style="dotted"`,
+		"dev dependency", "optional dependency", "peer dependency",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("ToDOT() missing %s:\n%s", want, dot)
+		}
+	}
+	if _, err := RenderSVG(dot); err != nil {
+		t.Fatalf("RenderSVG() error: %v", err)
+	}
+}
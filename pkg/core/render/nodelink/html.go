@@ -0,0 +1,158 @@
+package nodelink
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// svgGroupRe matches the opening of a Graphviz node or edge group and its
+// title, which holds the node name or "from->to" for edges.
+var svgGroupRe = regexp.MustCompile(`<g id="[^"]*" class="(node|edge)">\s*<title>([^<]*)</title>`)
+
+// RenderHTML wraps a Graphviz SVG from [RenderSVG] in a self-contained HTML
+// page. Hovering a node highlights everything it depends on and everything
+// that depends on it, a search box dims the packages that do not match,
+// and clicking a node opens its repository (the URL attribute written by
+// [ToDOT] for enriched nodes). title names the page; empty uses
+// "Dependencies".
+//
+// The page needs no network access: styles and scripts are inlined, and the
+// SVG keeps its embedded fonts.
+func RenderHTML(svg []byte, title string) ([]byte, error) {
+	start := bytes.Index(svg, []byte("<svg"))
+	if start < 0 {
+		return nil, fmt.Errorf("render html: input is not an SVG document")
+	}
+	if title == "" {
+		title = "Dependencies"
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	buf.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	fmt.Fprintf(&buf, "<title>%s</title>\n", html.EscapeString(title))
+	fmt.Fprintf(&buf, "<style>%s\n</style>\n</head>\n<body>\n", htmlCSS)
+	fmt.Fprintf(&buf, "<header><h1>%s</h1><input id=\"search\" type=\"search\" placeholder=\"Search packages…\" autocomplete=\"off\"><span id=\"matches\"></span></header>\n",
+		html.EscapeString(title))
+	buf.WriteString("<main>\n")
+	buf.Write(annotateSVG(svg[start:]))
+	buf.WriteString("\n</main>\n")
+	fmt.Fprintf(&buf, "<script>%s\n</script>\n</body>\n</html>\n", htmlJS)
+	return buf.Bytes(), nil
+}
+
+// annotateSVG tags Graphviz node groups with data-id and edge groups with
+// data-from and data-to, so the page script can walk the graph without
+// parsing titles. Edge titles are split at the "->" that separates two
+// known node names, which stays correct for names containing "->".
+func annotateSVG(svg []byte) []byte {
+	nodes := make(map[string]bool)
+	for _, m := range svgGroupRe.FindAllSubmatch(svg, -1) {
+		if string(m[1]) == "node" {
+			nodes[html.UnescapeString(string(m[2]))] = true
+		}
+	}
+	return svgGroupRe.ReplaceAllFunc(svg, func(match []byte) []byte {
+		m := svgGroupRe.FindSubmatch(match)
+		name := html.UnescapeString(string(m[2]))
+		var attrs string
+		if string(m[1]) == "node" {
+			attrs = fmt.Sprintf(` data-id="%s"`, html.EscapeString(name))
+		} else if from, to, ok := splitEdgeTitle(name, nodes); ok {
+			attrs = fmt.Sprintf(` data-from="%s" data-to="%s"`, html.EscapeString(from), html.EscapeString(to))
+		}
+		return bytes.Replace(match, []byte(`">`), []byte(`"`+attrs+`>`), 1)
+	})
+}
+
+// splitEdgeTitle splits a Graphviz edge title "from->to" into its endpoints.
+func splitEdgeTitle(title string, nodes map[string]bool) (from, to string, ok bool) {
+	for i := strings.Index(title, "->"); i >= 0; {
+		if nodes[title[:i]] && nodes[title[i+2:]] {
+			return title[:i], title[i+2:], true
+		}
+		next := strings.Index(title[i+1:], "->")
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return "", "", false
+}
+
+const htmlCSS = `
+    body { margin: 0; font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; background: #fff; color: #1f2937; }
+    header { position: sticky; top: 0; z-index: 1; display: flex; gap: 12px; align-items: center; padding: 10px 16px; background: rgba(255,255,255,0.95); border-bottom: 1px solid #e5e7eb; }
+    h1 { margin: 0; font-size: 16px; font-weight: 600; }
+    #search { flex: 0 1 280px; padding: 6px 10px; font-size: 14px; border: 1px solid #d1d5db; border-radius: 6px; }
+    #matches { font-size: 13px; color: #6b7280; }
+    main { padding: 16px; }
+    main svg { display: block; max-width: 100%; height: auto; margin: 0 auto; }
+    .node, .edge { transition: opacity 0.15s; }
+    .node { cursor: default; }
+    .node.linked { cursor: pointer; }
+    svg.focus .node:not(.lit), svg.focus .edge:not(.lit) { opacity: 0.15; }
+    svg.focus .edge.lit path, svg.focus .edge.lit polygon { stroke: #f59e0b; stroke-width: 3; }
+    svg.focus .edge.lit polygon { fill: #f59e0b; }
+    .node.self path, .node.self polygon, .node.self ellipse { stroke: #f59e0b; stroke-width: 4; }`
+
+const htmlJS = `
+    const svg = document.querySelector('main svg');
+    const nodes = new Map();
+    svg.querySelectorAll('.node[data-id]').forEach(el => nodes.set(el.dataset.id, { el, up: [], down: [] }));
+    const edges = [...svg.querySelectorAll('.edge[data-from]')];
+    edges.forEach(el => {
+      const from = nodes.get(el.dataset.from), to = nodes.get(el.dataset.to);
+      if (!from || !to) return;
+      from.down.push(el.dataset.to);
+      to.up.push(el.dataset.from);
+    });
+    function walk(id, dir, seen) {
+      for (const next of nodes.get(id)[dir]) {
+        if (!seen.has(next)) { seen.add(next); walk(next, dir, seen); }
+      }
+      return seen;
+    }
+    function focus(ids, self, up, down) {
+      svg.classList.add('focus');
+      nodes.forEach((n, id) => {
+        n.el.classList.toggle('lit', ids.has(id));
+        n.el.classList.toggle('self', id === self);
+      });
+      edges.forEach(el => {
+        const { from, to } = el.dataset;
+        el.classList.toggle('lit', !!self && ((up.has(from) && up.has(to)) || (down.has(from) && down.has(to))));
+      });
+    }
+    function clear() {
+      svg.classList.remove('focus');
+      svg.querySelectorAll('.lit, .self').forEach(el => el.classList.remove('lit', 'self'));
+    }
+    const search = document.getElementById('search');
+    const matches = document.getElementById('matches');
+    function applySearch() {
+      const q = search.value.trim().toLowerCase();
+      if (!q) { clear(); matches.textContent = ''; return; }
+      const hits = new Set([...nodes.keys()].filter(id => id.toLowerCase().includes(q)));
+      focus(hits);
+      matches.textContent = hits.size === 1 ? '1 match' : hits.size + ' matches';
+    }
+    nodes.forEach((n, id) => {
+      const link = n.el.querySelector('a');
+      if (link && (link.getAttribute('xlink:href') || link.getAttribute('href'))) n.el.classList.add('linked');
+      n.el.addEventListener('mouseenter', () => {
+        const up = walk(id, 'up', new Set([id]));
+        const down = walk(id, 'down', new Set([id]));
+        focus(new Set([...up, ...down]), id, up, down);
+      });
+      n.el.addEventListener('mouseleave', applySearch);
+    });
+    search.addEventListener('input', applySearch);
+    search.addEventListener('keydown', e => {
+      if (e.key !== 'Enter') return;
+      const hit = [...nodes.keys()].find(id => id.toLowerCase().includes(search.value.trim().toLowerCase()));
+      if (hit) nodes.get(hit).el.scrollIntoView({ behavior: 'smooth', block: 'center', inline: 'center' });
+    });`
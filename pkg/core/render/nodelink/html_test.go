@@ -0,0 +1,57 @@
+package nodelink
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderHTML(t *testing.T) {
+	svg, err := RenderSVG(ToDOT(riskGraph(), Options{}))
+	if err != nil {
+		t.Fatalf("RenderSVG() error: %v", err)
+	}
+	page, err := RenderHTML(svg, "app <1.0>")
+	if err != nil {
+		t.Fatalf("RenderHTML() error: %v", err)
+	}
+	out := string(page)
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<title>app &lt;1.0&gt;</title>",
+		`id="search"`,
+		`class="node" data-id="old"`,
+		`class="edge" data-from="app" data-to="old"`,
+		`xlink:href="https://github.com/acme/old"`,
+		"<script>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderHTML() missing %s", want)
+		}
+	}
+	if strings.Contains(out, "<?xml") {
+		t.Error("RenderHTML() kept the XML prolog")
+	}
+
+	if _, err := RenderHTML([]byte("not svg"), ""); err == nil {
+		t.Error("RenderHTML() accepted non-SVG input")
+	}
+}
+
+func TestSplitEdgeTitle(t *testing.T) {
+	nodes := map[string]bool{"a": true, "a->b": true, "c": true}
+	tests := []struct {
+		title, from, to string
+		ok              bool
+	}{
+		{"a->c", "a", "c", true},
+		{"a->b->c", "a->b", "c", true},
+		{"c->a->b", "c", "a->b", true},
+		{"x->y", "", "", false},
+	}
+	for _, tt := range tests {
+		from, to, ok := splitEdgeTitle(tt.title, nodes)
+		if from != tt.from || to != tt.to || ok != tt.ok {
+			t.Errorf("splitEdgeTitle(%q) = %q, %q, %v; want %q, %q, %v", tt.title, from, to, ok, tt.from, tt.to, tt.ok)
+		}
+	}
+}
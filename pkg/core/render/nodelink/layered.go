@@ -0,0 +1,406 @@
+package nodelink
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/dag/transform"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/ordering"
+)
+
+// Rendering engines for node-link diagrams.
+const (
+	// EngineDot lays the diagram out with Graphviz (see [RenderSVG]).
+	EngineDot = "dot"
+
+	// EngineLayered lays the diagram out in pure Go (see [RenderLayeredSVG]).
+	EngineLayered = "layered"
+)
+
+// Geometry of the layered renderer, in SVG user units. The values mirror
+// the Graphviz attributes written by [ToDOT] (fontsize 24, margin 0.4x0.2
+// inches, ranksep 0.5, nodesep 0.3) so both engines produce diagrams of
+// similar proportions.
+const (
+	layeredFontSize   = 24.0
+	layeredCharWidth  = 13.0 // average advance of the label font at layeredFontSize
+	layeredLineHeight = 29.0
+	layeredPadX       = 29.0
+	layeredPadY       = 14.0
+	layeredNodeGap    = 22.0
+	layeredRankGap    = 48.0
+	layeredMargin     = 8.0
+	layeredWaypoint   = 8.0 // width reserved for an edge passing through a row
+	layeredArrowLen   = 10.0
+	layeredPasses     = 4
+)
+
+// layeredBox is a placed node; waypoints are zero-height boxes that long
+// edges pass through.
+type layeredBox struct {
+	node     *dag.Node
+	lines    []string
+	waypoint bool
+	x, y     float64 // center
+	w, h     float64
+}
+
+// RenderLayeredSVG lays out and draws a node-link diagram without Graphviz,
+// for environments where the Graphviz runtime is unavailable or fails.
+//
+// It reuses the tower machinery: cycles are broken and rows assigned with
+// [transform.BreakCycles] and [transform.AssignLayers], edges spanning
+// several rows are routed through waypoints, and rows are ordered with
+// [ordering.Barycentric]. Nodes, edges and the legend are styled as in
+// [ToDOT], and the SVG uses the same node and edge groups as Graphviz, so
+// [RenderHTML] works on either. Options.ClusterBy is ignored.
+func RenderLayeredSVG(g *dag.DAG, opts Options) ([]byte, error) {
+	if g == nil || g.NodeCount() == 0 {
+		return nil, fmt.Errorf("render layered: graph has no nodes")
+	}
+
+	work := g.Clone()
+	transform.BreakCycles(work)
+	transform.AssignLayers(work)
+	edges := work.Edges()
+	routes, err := addWaypoints(work, edges)
+	if err != nil {
+		return nil, fmt.Errorf("render layered: %w", err)
+	}
+	orders := ordering.Barycentric{}.OrderRows(work)
+
+	boxes := make(map[string]*layeredBox, work.NodeCount())
+	for _, n := range work.Nodes() {
+		b := &layeredBox{node: n, waypoint: isWaypoint(n.ID)}
+		if b.waypoint {
+			b.w = layeredWaypoint
+		} else {
+			b.lines = strings.Split(fmtLabel(*n, opts.Detailed), "\n")
+			longest := 0
+			for _, l := range b.lines {
+				longest = max(longest, utf8.RuneCountInString(l))
+			}
+			b.w = float64(longest)*layeredCharWidth + 2*layeredPadX
+			b.h = float64(len(b.lines))*layeredLineHeight + 2*layeredPadY
+		}
+		boxes[n.ID] = b
+	}
+
+	rows := work.RowIDs()
+	width, height := placeBoxes(work, rows, orders, boxes)
+
+	var body bytes.Buffer
+	nodeIdx, edgeIdx := 0, 0
+	for _, r := range rows {
+		for _, id := range orders[r] {
+			b := boxes[id]
+			if b.waypoint {
+				continue
+			}
+			nodeIdx++
+			writeLayeredNode(&body, nodeIdx, b, opts)
+		}
+	}
+	for _, e := range edges {
+		edgeIdx++
+		points := []string{e.From}
+		points = append(points, routes[[2]string{e.From, e.To}]...)
+		points = append(points, e.To)
+		writeLayeredEdge(&body, edgeIdx, g, e, points, boxes, opts)
+	}
+	legendH := writeLayeredLegend(&body, g, opts, height)
+	if legendH > 0 {
+		height += legendH + layeredMargin
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 %.2f %.2f" width="%.0f" height="%.0f">`+"\n",
+		width, height, width, height)
+	buf.WriteString(`<g id="graph0" class="graph">` + "\n")
+	buf.Write(body.Bytes())
+	buf.WriteString("</g>\n</svg>\n")
+	return injectFont(buf.Bytes()), nil
+}
+
+// waypointPrefix marks waypoint IDs; the NUL byte cannot occur in package
+// names.
+const waypointPrefix = "\x00waypoint:"
+
+func isWaypoint(id string) bool { return strings.HasPrefix(id, waypointPrefix) }
+
+// addWaypoints splits every edge spanning more than one row into a chain
+// through one waypoint per intermediate row, so the orderer sees only
+// adjacent-row edges. It returns the waypoints of each split edge, top down.
+func addWaypoints(g *dag.DAG, edges []dag.Edge) (map[[2]string][]string, error) {
+	routes := make(map[[2]string][]string)
+	for _, e := range edges {
+		src, _ := g.Node(e.From)
+		dst, _ := g.Node(e.To)
+		if src == nil || dst == nil || dst.Row <= src.Row+1 {
+			continue
+		}
+		g.RemoveEdge(e.From, e.To)
+		prev := e.From
+		var chain []string
+		for row := src.Row + 1; row < dst.Row; row++ {
+			id := fmt.Sprintf("%s%s->%s@%d", waypointPrefix, e.From, e.To, row)
+			if err := g.AddNode(dag.Node{ID: id, Row: row, Kind: dag.NodeKindSubdivider, MasterID: e.From}); err != nil {
+				return nil, err
+			}
+			if err := g.AddEdge(dag.Edge{From: prev, To: id}); err != nil {
+				return nil, err
+			}
+			chain = append(chain, id)
+			prev = id
+		}
+		if err := g.AddEdge(dag.Edge{From: prev, To: e.To}); err != nil {
+			return nil, err
+		}
+		routes[[2]string{e.From, e.To}] = chain
+	}
+	return routes, nil
+}
+
+// placeBoxes assigns coordinates: rows stack top down, and within a row
+// boxes keep their order while being pulled toward the mean position of
+// their parents. It returns the drawing size.
+func placeBoxes(g *dag.DAG, rows []int, orders map[int][]string, boxes map[string]*layeredBox) (width, height float64) {
+	y := layeredMargin
+	for _, r := range rows {
+		rowH := 0.0
+		for _, id := range orders[r] {
+			rowH = max(rowH, boxes[id].h)
+		}
+		for _, id := range orders[r] {
+			boxes[id].y = y + rowH/2
+		}
+		y += rowH + layeredRankGap
+	}
+	height = y - layeredRankGap + layeredMargin
+
+	for _, r := range rows {
+		packRow(orders[r], boxes, nil)
+	}
+	for range layeredPasses {
+		for _, r := range rows[1:] {
+			packRow(orders[r], boxes, func(id string) []string { return g.Parents(id) })
+		}
+		for i := len(rows) - 2; i >= 0; i-- {
+			packRow(orders[rows[i]], boxes, func(id string) []string { return g.Children(id) })
+		}
+	}
+
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for _, b := range boxes {
+		minX = min(minX, b.x-b.w/2)
+		maxX = max(maxX, b.x+b.w/2)
+	}
+	for _, b := range boxes {
+		b.x += layeredMargin - minX
+	}
+	return maxX - minX + 2*layeredMargin, height
+}
+
+// packRow places a row's boxes left to right without overlap. With
+// neighbors, each box aims for the mean x of its neighbors and the row is
+// then shifted so the boxes sit, on average, where they aimed.
+func packRow(ids []string, boxes map[string]*layeredBox, neighbors func(string) []string) {
+	desired := make([]float64, len(ids))
+	for i, id := range ids {
+		desired[i] = boxes[id].x
+		if neighbors == nil {
+			continue
+		}
+		var sum float64
+		var n int
+		for _, nb := range neighbors(id) {
+			if b, ok := boxes[nb]; ok {
+				sum += b.x
+				n++
+			}
+		}
+		if n > 0 {
+			desired[i] = sum / float64(n)
+		}
+	}
+
+	right := math.Inf(-1)
+	var drift float64
+	for i, id := range ids {
+		b := boxes[id]
+		x := desired[i]
+		if neighbors == nil {
+			x = 0
+		}
+		x = max(x, right+layeredNodeGap+b.w/2)
+		b.x = x
+		right = x + b.w/2
+		drift += x - desired[i]
+	}
+	if neighbors != nil && len(ids) > 0 {
+		drift /= float64(len(ids))
+		for _, id := range ids {
+			boxes[id].x -= drift
+		}
+	}
+}
+
+// parseAttrs reads DOT attributes written by [fmtAttrs] and [riskAttrs]
+// back into a map, so the layered renderer styles nodes identically.
+func parseAttrs(attrs []string) map[string]string {
+	m := make(map[string]string, len(attrs))
+	for _, a := range attrs {
+		k, v, ok := strings.Cut(a, "=")
+		if !ok {
+			continue
+		}
+		if u, err := strconv.Unquote(v); err == nil {
+			v = u
+		}
+		m[k] = v
+	}
+	return m
+}
+
+// dashArray converts a DOT style list to an SVG stroke-dasharray attribute.
+func dashArray(style string) string {
+	switch {
+	case strings.Contains(style, "dashed"):
+		return ` stroke-dasharray="5,2"`
+	case strings.Contains(style, "dotted"):
+		return ` stroke-dasharray="1,3"`
+	}
+	return ""
+}
+
+func writeLayeredNode(buf *bytes.Buffer, idx int, b *layeredBox, opts Options) {
+	n := b.node
+	attrs := fmtAttrs(*n, "")
+	attrs = append(attrs, riskAttrs(*n, attrs, opts)...)
+	a := parseAttrs(attrs)
+
+	fill := cmp.Or(a["fillcolor"], "#f6f8fa")
+	stroke := cmp.Or(a["color"], "black")
+	strokeW := cmp.Or(a["penwidth"], "1")
+	fontColor := cmp.Or(a["fontcolor"], "black")
+
+	fmt.Fprintf(buf, `<g id="node%d" class="node">`+"\n<title>%s</title>\n", idx, xmlEscape(n.ID))
+	url, tip := a["URL"], a["tooltip"]
+	if url != "" || tip != "" {
+		buf.WriteString("<a")
+		if url != "" {
+			fmt.Fprintf(buf, ` xlink:href="%s" target="_blank"`, xmlEscape(url))
+		}
+		if tip != "" {
+			fmt.Fprintf(buf, ` xlink:title="%s"`, xmlEscape(tip))
+		}
+		buf.WriteString(">\n")
+	}
+	fmt.Fprintf(buf, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" rx="8" fill="%s" stroke="%s" stroke-width="%s"%s/>`+"\n",
+		b.x-b.w/2, b.y-b.h/2, b.w, b.h, xmlEscape(fill), xmlEscape(stroke), xmlEscape(strokeW), dashArray(a["style"]))
+	top := b.y - float64(len(b.lines)-1)*layeredLineHeight/2
+	for i, line := range b.lines {
+		fmt.Fprintf(buf, `<text text-anchor="middle" dominant-baseline="central" x="%.2f" y="%.2f" font-size="%.0f" fill="%s">%s</text>`+"\n",
+			b.x, top+float64(i)*layeredLineHeight, layeredFontSize, xmlEscape(fontColor), xmlEscape(line))
+	}
+	if url != "" || tip != "" {
+		buf.WriteString("</a>\n")
+	}
+	buf.WriteString("</g>\n")
+}
+
+func writeLayeredEdge(buf *bytes.Buffer, idx int, g *dag.DAG, e dag.Edge, points []string, boxes map[string]*layeredBox, opts Options) {
+	a := parseAttrs(edgeAttrs(g, e, opts))
+	color := cmp.Or(a["color"], "black")
+
+	from, to := boxes[points[0]], boxes[points[len(points)-1]]
+	xs := []float64{from.x}
+	ys := []float64{from.y + from.h/2}
+	for _, id := range points[1 : len(points)-1] {
+		xs = append(xs, boxes[id].x)
+		ys = append(ys, boxes[id].y)
+	}
+	xs = append(xs, to.x)
+	ys = append(ys, to.y-to.h/2)
+
+	// Shorten the last segment so the arrowhead ends on the target border.
+	last := len(xs) - 1
+	dx, dy := xs[last]-xs[last-1], ys[last]-ys[last-1]
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		length = 1
+	}
+	ux, uy := dx/length, dy/length
+	tipX, tipY := xs[last], ys[last]
+	xs[last] -= ux * layeredArrowLen
+	ys[last] -= uy * layeredArrowLen
+
+	var d strings.Builder
+	for i := range xs {
+		cmd := "L"
+		if i == 0 {
+			cmd = "M"
+		}
+		fmt.Fprintf(&d, "%s%.2f,%.2f ", cmd, xs[i], ys[i])
+	}
+
+	fmt.Fprintf(buf, `<g id="edge%d" class="edge">`+"\n<title>%s</title>\n", idx, xmlEscape(e.From+"->"+e.To))
+	if tip := a["tooltip"]; tip != "" {
+		fmt.Fprintf(buf, `<a xlink:title="%s">`+"\n", xmlEscape(tip))
+	}
+	fmt.Fprintf(buf, `<path fill="none" stroke="%s" d="%s"%s/>`+"\n", xmlEscape(color), strings.TrimSpace(d.String()), dashArray(a["style"]))
+	px, py := -uy*layeredArrowLen/2, ux*layeredArrowLen/2
+	fmt.Fprintf(buf, `<polygon fill="%s" stroke="%s" points="%.2f,%.2f %.2f,%.2f %.2f,%.2f"/>`+"\n",
+		xmlEscape(color), xmlEscape(color),
+		tipX, tipY, xs[last]+px, ys[last]+py, xs[last]-px, ys[last]-py)
+	if label := a["label"]; label != "" {
+		mx, my := (xs[last-1]+xs[last])/2, (ys[last-1]+ys[last])/2
+		for i, line := range strings.Split(label, "\n") {
+			fmt.Fprintf(buf, `<text x="%.2f" y="%.2f" font-size="16" fill="%s">%s</text>`+"\n",
+				mx+6, my+float64(i)*19, xmlEscape(cmp.Or(a["fontcolor"], "black")), xmlEscape(line))
+		}
+	}
+	if a["tooltip"] != "" {
+		buf.WriteString("</a>\n")
+	}
+	buf.WriteString("</g>\n")
+}
+
+// writeLayeredLegend draws the [legendEntries] below the diagram, starting
+// at y, and returns the height used.
+func writeLayeredLegend(buf *bytes.Buffer, g *dag.DAG, opts Options, y float64) float64 {
+	entries := legendEntries(g, opts)
+	if len(entries) == 0 {
+		return 0
+	}
+	const rowH = 26.0
+	fmt.Fprintf(buf, `<g id="%s" class="legend">`+"\n", xmlEscape(legendID))
+	for i, e := range entries {
+		cy := y + float64(i)*rowH + rowH/2
+		if e.line != "" {
+			fmt.Fprintf(buf, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s" stroke-width="2"%s/>`+"\n",
+				layeredMargin, cy, layeredMargin+24, cy, xmlEscape(e.border), dashArray(e.style))
+		} else {
+			border := cmp.Or(e.border, e.fill)
+			fmt.Fprintf(buf, `<rect x="%.2f" y="%.2f" width="24" height="18" fill="%s" stroke="%s" stroke-width="2"/>`+"\n",
+				layeredMargin, cy-9, xmlEscape(e.fill), xmlEscape(border))
+		}
+		fmt.Fprintf(buf, `<text x="%.2f" y="%.2f" dominant-baseline="central" font-size="18">%s</text>`+"\n",
+			layeredMargin+34, cy, xmlEscape(e.label))
+	}
+	buf.WriteString("</g>\n")
+	return float64(len(entries)) * rowH
+}
+
+// xmlEscape escapes s for SVG text and attributes, keeping line breaks in
+// tooltips the way Graphviz writes them.
+func xmlEscape(s string) string {
+	return strings.ReplaceAll(html.EscapeString(s), "\n", "&#10;")
+}
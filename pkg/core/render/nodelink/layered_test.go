@@ -0,0 +1,90 @@
+package nodelink
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestRenderLayeredSVG(t *testing.T) {
+	g := riskGraph()
+	// app -> risky spans two rows and must be routed through a waypoint.
+	g.AddEdge(dag.Edge{From: "app", To: "risky", Meta: dag.Metadata{"scope": "dev"}})
+
+	svg, err := RenderLayeredSVG(g, Options{EdgeKinds: true})
+	if err != nil {
+		t.Fatalf("RenderLayeredSVG() error: %v", err)
+	}
+	if err := xml.Unmarshal(svg, new(struct{})); err != nil {
+		t.Fatalf("RenderLayeredSVG() produced invalid XML: %v", err)
+	}
+	out := string(svg)
+	for _, want := range []string{
+		`<title>old</title>`,
+		`<title>app-&gt;risky</title>`,
+		`xlink:href="https://github.com/acme/old"`,
+		`fill="#c2410c"`,             // vulnerable node
+		`stroke="#b91c1c"`,           // brittle outline
+		`stroke-dasharray="5,2"`,     // dev edge
+		`An old library&#10;health:`, // multi-line tooltip
+		legendID,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderLayeredSVG() missing %s", want)
+		}
+	}
+	if strings.Contains(out, waypointPrefix) {
+		t.Error("RenderLayeredSVG() drew a waypoint")
+	}
+	if got := strings.Count(out, `class="node"`); got != 4 {
+		t.Errorf("RenderLayeredSVG() drew %d nodes, want 4", got)
+	}
+	if got := strings.Count(out, `class="edge"`); got != 4 {
+		t.Errorf("RenderLayeredSVG() drew %d edges, want 4", got)
+	}
+
+	page, err := RenderHTML(svg, "")
+	if err != nil {
+		t.Fatalf("RenderHTML() error: %v", err)
+	}
+	if !strings.Contains(string(page), `data-from="app" data-to="risky"`) {
+		t.Error("RenderHTML() could not annotate layered output")
+	}
+}
+
+func TestRenderLayeredSVG_Cycle(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "a"})
+	g.AddNode(dag.Node{ID: "b"})
+	g.AddEdge(dag.Edge{From: "a", To: "b"})
+	g.AddEdge(dag.Edge{From: "b", To: "a"})
+	if _, err := RenderLayeredSVG(g, Options{}); err != nil {
+		t.Fatalf("RenderLayeredSVG() error: %v", err)
+	}
+	if g.EdgeCount() != 2 {
+		t.Error("RenderLayeredSVG() modified its input")
+	}
+
+	if _, err := RenderLayeredSVG(dag.New(nil), Options{}); err == nil {
+		t.Error("RenderLayeredSVG() accepted an empty graph")
+	}
+}
+
+func TestPackRow(t *testing.T) {
+	boxes := map[string]*layeredBox{
+		"a": {w: 100}, "b": {w: 100},
+		"p": {x: 500},
+	}
+	packRow([]string{"a", "b"}, boxes, nil)
+	if gap := (boxes["b"].x - boxes["b"].w/2) - (boxes["a"].x + boxes["a"].w/2); gap != layeredNodeGap {
+		t.Errorf("packRow() gap = %v, want %v", gap, layeredNodeGap)
+	}
+
+	parents := func(string) []string { return []string{"p"} }
+	packRow([]string{"a", "b"}, boxes, parents)
+	if mid := (boxes["a"].x + boxes["b"].x) / 2; mid != 500 {
+		t.Errorf("packRow() centred children at %v, want 500 under their parent", mid)
+	}
+}
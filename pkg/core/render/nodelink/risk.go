@@ -0,0 +1,186 @@
+package nodelink
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+	"github.com/stacktower-io/stacktower/pkg/security"
+)
+
+// legendID is the DOT node holding the legend table.
+const legendID = "__legend__"
+
+// brittleDOTColor outlines brittle nodes, matching the tower's brittle marks.
+const brittleDOTColor = "#b91c1c"
+
+// healthDOTColors are the fills for [Options.ColorByHealth], the same as the
+// tower's health colouring.
+var healthDOTColors = map[string]string{
+	feature.HealthGood: "#86efac", // green-300
+	feature.HealthFair: "#d9f99d", // lime-200
+	feature.HealthWeak: "#fdba74", // orange-300
+	feature.HealthPoor: "#f87171", // red-400
+}
+
+// riskAttrs returns the attributes that carry tower metadata into the
+// diagram: a health fill (when enabled and no vulnerability or license fill
+// applies), a red outline for brittle packages, a tooltip summarizing the
+// risk signals, and a link to the repository.
+func riskAttrs(n dag.Node, attrs []string, opts Options) []string {
+	if n.IsSynthetic() || len(n.Meta) == 0 {
+		return nil
+	}
+	var out []string
+	filled := slices.ContainsFunc(attrs, func(a string) bool { return strings.HasPrefix(a, "fillcolor=") })
+	if opts.ColorByHealth && !filled {
+		if score, ok := feature.HealthScore(&n); ok {
+			out = append(out, fmt.Sprintf("fillcolor=%q", healthDOTColors[feature.HealthLevel(score)]))
+		}
+	}
+	if feature.IsBrittle(&n) {
+		out = append(out, fmt.Sprintf("color=%q", brittleDOTColor), "penwidth=3")
+	}
+	if tip := tooltip(n); tip != "" {
+		out = append(out, fmt.Sprintf("tooltip=%q", tip))
+	}
+	if url := nodeURL(n); url != "" {
+		out = append(out, fmt.Sprintf("URL=%q", url), `target="_blank"`)
+	}
+	return out
+}
+
+// tooltip summarizes a node's description and risk signals, one per line.
+func tooltip(n dag.Node) string {
+	var lines []string
+	desc, _ := n.Meta[metadata.Summary].(string)
+	if desc == "" {
+		desc, _ = n.Meta[metadata.RepoDescription].(string)
+	}
+	if desc == "" {
+		desc, _ = n.Meta["description"].(string)
+	}
+	if desc != "" {
+		lines = append(lines, desc)
+	}
+	if score, ok := feature.HealthScore(&n); ok {
+		lines = append(lines, fmt.Sprintf("health: %d/100 (%s)", score, feature.HealthLevel(score)))
+	}
+	if sev, _ := n.Meta[security.MetaVulnSeverity].(string); sev != "" {
+		lines = append(lines, "vulnerability: "+sev)
+	}
+	if lic, _ := n.Meta[security.MetaLicense].(string); lic != "" {
+		if risk, _ := n.Meta[security.MetaLicenseRisk].(string); risk != "" && risk != string(security.LicenseRiskPermissive) {
+			lic += " (" + risk + ")"
+		}
+		lines = append(lines, "license: "+lic)
+	}
+	if feature.IsBrittle(&n) {
+		lines = append(lines, "brittle: may be unmaintained")
+	}
+	if s := feature.Successor(&n); s != "" {
+		lines = append(lines, "successor: "+strings.TrimPrefix(s, "https://"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// nodeURL returns the repository URL of a node, or its homepage.
+func nodeURL(n dag.Node) string {
+	for _, key := range []string{metadata.RepoURL, metadata.HomePage} {
+		if u, _ := n.Meta[key].(string); strings.HasPrefix(u, "https://") || strings.HasPrefix(u, "http://") {
+			return u
+		}
+	}
+	return ""
+}
+
+// legendEntry is one row of the DOT legend: a colour swatch, or for edge
+// kinds a line sample drawn in the border colour and line style.
+type legendEntry struct {
+	label, fill, border string
+	line, style         string
+}
+
+// legendEntries lists the risk encodings that appear in g, in display order.
+func legendEntries(g *dag.DAG, opts Options) []legendEntry {
+	var vuln, brittle bool
+	risks := make(map[security.LicenseRisk]bool)
+	levels := make(map[string]bool)
+	for _, n := range g.Nodes() {
+		if n.IsSynthetic() {
+			continue
+		}
+		lr, _ := n.Meta[security.MetaLicenseRisk].(string)
+		risk := security.LicenseRiskFromString(lr)
+		if sev, _ := n.Meta[security.MetaVulnSeverity].(string); sev != "" {
+			vuln = true
+		} else if risk == security.LicenseRiskCopyleft || risk == security.LicenseRiskWeakCopyleft {
+			risks[risk] = true
+		} else if score, ok := feature.HealthScore(n); ok && opts.ColorByHealth {
+			levels[feature.HealthLevel(score)] = true
+		}
+		brittle = brittle || feature.IsBrittle(n)
+	}
+
+	var out []legendEntry
+	if vuln {
+		out = append(out, legendEntry{label: "vulnerable", fill: vulnDOTBgColor})
+	}
+	for _, r := range []security.LicenseRisk{security.LicenseRiskCopyleft, security.LicenseRiskWeakCopyleft} {
+		if risks[r] {
+			out = append(out, legendEntry{label: string(r), fill: r.IconColor()})
+		}
+	}
+	for _, lvl := range feature.HealthLevels {
+		if levels[lvl] {
+			out = append(out, legendEntry{label: "health: " + lvl, fill: healthDOTColors[lvl]})
+		}
+	}
+	if brittle {
+		out = append(out, legendEntry{label: "brittle", fill: "#f6f8fa", border: brittleDOTColor})
+	}
+	if opts.EdgeKinds {
+		kinds := make(map[string]bool)
+		for _, e := range g.Edges() {
+			kinds[EdgeKind(g, e)] = true
+		}
+		for _, s := range edgeKindStyles {
+			if kinds[s.kind] {
+				out = append(out, legendEntry{label: s.kind + " dependency", border: s.color, line: s.sample, style: s.style})
+			}
+		}
+	}
+	return out
+}
+
+// writeLegend appends a legend table below the graph for the risk
+// encodings in use. Nothing is written when the graph has none.
+func writeLegend(buf *bytes.Buffer, g *dag.DAG, opts Options) {
+	entries := legendEntries(g, opts)
+	if len(entries) == 0 {
+		return
+	}
+	var rows strings.Builder
+	for _, e := range entries {
+		if e.line != "" {
+			fmt.Fprintf(&rows, `<tr><td><font color="%s">%s</font></td><td align="left">%s</td></tr>`,
+				e.border, e.line, html.EscapeString(e.label))
+			continue
+		}
+		border := e.fill
+		if e.border != "" {
+			border = e.border
+		}
+		fmt.Fprintf(&rows, `<tr><td width="24" bgcolor="%s" color="%s" border="2"></td><td align="left">%s</td></tr>`,
+			e.fill, border, html.EscapeString(e.label))
+	}
+	buf.WriteString("\n")
+	fmt.Fprintf(buf, "  %q [shape=plain, style=\"\", fontsize=18, label=<<table border=\"0\" cellspacing=\"4\" cellpadding=\"4\">%s</table>>];\n",
+		legendID, rows.String())
+	fmt.Fprintf(buf, "  { rank=sink; %q; }\n", legendID)
+}
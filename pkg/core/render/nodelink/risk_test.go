@@ -0,0 +1,84 @@
+package nodelink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func riskGraph() *dag.DAG {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Row: 0})
+	g.AddNode(dag.Node{ID: "old", Row: 1, Meta: dag.Metadata{
+		"repo_archived": true,
+		"repo_url":      "https://github.com/acme/old",
+		"description":   "An old library",
+		"health_score":  25,
+	}})
+	g.AddNode(dag.Node{ID: "fresh", Row: 1, Meta: dag.Metadata{
+		"health_score": 90,
+		"homepage":     "https://fresh.dev",
+	}})
+	g.AddNode(dag.Node{ID: "risky", Row: 2, Meta: dag.Metadata{
+		"vuln_severity": "critical",
+		"health_score":  95,
+	}})
+	g.AddEdge(dag.Edge{From: "app", To: "old"})
+	g.AddEdge(dag.Edge{From: "app", To: "fresh"})
+	g.AddEdge(dag.Edge{From: "old", To: "risky"})
+	return g
+}
+
+func TestRiskAttrs(t *testing.T) {
+	g := riskGraph()
+	old, _ := g.Node("old")
+	attrs := fmtAttrs(*old, "old")
+	joined := strings.Join(riskAttrs(*old, attrs, Options{ColorByHealth: true}), " ")
+
+	for _, want := range []string{
+		`color="#b91c1c"`,
+		`fillcolor="#f87171"`,
+		`URL="https://github.com/acme/old"`,
+		`tooltip="An old library\nhealth: 25/100 (poor)\nbrittle: may be unmaintained"`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("riskAttrs() missing %s: %s", want, joined)
+		}
+	}
+
+	// Vulnerability fills take precedence over health.
+	risky, _ := g.Node("risky")
+	attrs = fmtAttrs(*risky, "risky")
+	if joined := strings.Join(riskAttrs(*risky, attrs, Options{ColorByHealth: true}), " "); strings.Contains(joined, "fillcolor") {
+		t.Errorf("riskAttrs() overrode the vulnerability fill: %s", joined)
+	}
+
+	// Health fills are opt-in.
+	fresh, _ := g.Node("fresh")
+	if joined := strings.Join(riskAttrs(*fresh, nil, Options{}), " "); strings.Contains(joined, "fillcolor") || !strings.Contains(joined, `URL="https://fresh.dev"`) {
+		t.Errorf("riskAttrs() = %s, want homepage link and no fill", joined)
+	}
+}
+
+func TestToDOT_Legend(t *testing.T) {
+	dot := ToDOT(riskGraph(), Options{ColorByHealth: true})
+	for _, want := range []string{legendID, "vulnerable", "health: good", "health: poor", "brittle", "rank=sink"} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("ToDOT() legend missing %q", want)
+		}
+	}
+	svg, err := RenderSVG(dot)
+	if err != nil {
+		t.Fatalf("RenderSVG() error: %v", err)
+	}
+	if !strings.Contains(string(svg), `xmlns:xlink=`) || !strings.Contains(string(svg), `xlink:href="https://github.com/acme/old"`) {
+		t.Error("RenderSVG() lost the repository links")
+	}
+
+	plain := dag.New(nil)
+	plain.AddNode(dag.Node{ID: "a"})
+	if dot := ToDOT(plain, Options{}); strings.Contains(dot, legendID) {
+		t.Error("ToDOT() added a legend without risk encodings")
+	}
+}
@@ -0,0 +1,321 @@
+package render
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	_ "image/png" // register PNG decoder for image.DecodeConfig
+	"io"
+	"strings"
+)
+
+// Slide dimensions in EMU (English Metric Units, 914400 per inch) for a
+// 16:9 widescreen deck.
+const (
+	slideWidthEMU  = 12192000
+	slideHeightEMU = 6858000
+	slideMarginEMU = 228600 // 0.25"
+	titleHeightEMU = 640080 // 0.7"
+)
+
+// Slide is one slide of a PPTX deck: an optional title above a picture,
+// plus speaker notes.
+type Slide struct {
+	Title string
+	PNG   []byte // Raster image shown on the slide (required)
+	SVG   []byte // Optional vector version; Office 2016+ displays it instead of the PNG
+	Notes string // Speaker notes; newlines start new paragraphs
+}
+
+// ToPPTX builds a PowerPoint deck with one slide per entry. Each picture is
+// scaled to fit the slide while keeping its aspect ratio.
+//
+// The deck is written directly as Office Open XML; no external tools are
+// needed, but PNG images usually come from [ToPNG].
+func ToPPTX(slides []Slide) ([]byte, error) {
+	if len(slides) == 0 {
+		return nil, fmt.Errorf("no slides to export")
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w := pptxWriter{zw: zw}
+
+	w.file("[Content_Types].xml", contentTypesXML(slides))
+	w.file("_rels/.rels", rootRelsXML)
+	w.file("ppt/presentation.xml", presentationXML(len(slides)))
+	w.file("ppt/_rels/presentation.xml.rels", presentationRelsXML(len(slides)))
+	w.file("ppt/slideMasters/slideMaster1.xml", slideMasterXML)
+	w.file("ppt/slideMasters/_rels/slideMaster1.xml.rels", slideMasterRelsXML)
+	w.file("ppt/slideLayouts/slideLayout1.xml", slideLayoutXML)
+	w.file("ppt/slideLayouts/_rels/slideLayout1.xml.rels", slideLayoutRelsXML)
+	w.file("ppt/notesMasters/notesMaster1.xml", notesMasterXML)
+	w.file("ppt/notesMasters/_rels/notesMaster1.xml.rels", notesMasterRelsXML)
+	w.file("ppt/theme/theme1.xml", themeXML)
+	w.file("ppt/theme/theme2.xml", themeXML)
+
+	for i, s := range slides {
+		n := i + 1
+		if len(s.PNG) == 0 {
+			return nil, fmt.Errorf("slide %d: missing PNG image", n)
+		}
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(s.PNG))
+		if err != nil {
+			return nil, fmt.Errorf("slide %d: decode PNG: %w", n, err)
+		}
+
+		w.raw(fmt.Sprintf("ppt/media/image%d.png", n), s.PNG)
+		if len(s.SVG) > 0 {
+			w.raw(fmt.Sprintf("ppt/media/image%d.svg", n), s.SVG)
+		}
+		w.file(fmt.Sprintf("ppt/slides/slide%d.xml", n), slideXML(s, cfg.Width, cfg.Height))
+		w.file(fmt.Sprintf("ppt/slides/_rels/slide%d.xml.rels", n), slideRelsXML(n, len(s.SVG) > 0))
+		w.file(fmt.Sprintf("ppt/notesSlides/notesSlide%d.xml", n), notesSlideXML(s.Notes))
+		w.file(fmt.Sprintf("ppt/notesSlides/_rels/notesSlide%d.xml.rels", n), notesSlideRelsXML(n))
+	}
+
+	if w.err != nil {
+		return nil, fmt.Errorf("write pptx: %w", w.err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("write pptx: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// pptxWriter writes zip entries, remembering the first error.
+type pptxWriter struct {
+	zw  *zip.Writer
+	err error
+}
+
+func (w *pptxWriter) file(name, content string) { w.raw(name, []byte(content)) }
+
+func (w *pptxWriter) raw(name string, data []byte) {
+	if w.err != nil {
+		return
+	}
+	var f io.Writer
+	if f, w.err = w.zw.Create(name); w.err != nil {
+		return
+	}
+	_, w.err = f.Write(data)
+}
+
+// fitPicture scales a w×h image into the area below the title.
+func fitPicture(w, h int, hasTitle bool) (x, y, cx, cy int64) {
+	top := int64(slideMarginEMU)
+	if hasTitle {
+		top += titleHeightEMU
+	}
+	boxW := int64(slideWidthEMU - 2*slideMarginEMU)
+	boxH := int64(slideHeightEMU) - top - slideMarginEMU
+	if w <= 0 || h <= 0 {
+		return slideMarginEMU, top, boxW, boxH
+	}
+
+	cx, cy = boxW, boxW*int64(h)/int64(w)
+	if cy > boxH {
+		cx, cy = boxH*int64(w)/int64(h), boxH
+	}
+	return (slideWidthEMU - cx) / 2, top + (boxH-cy)/2, cx, cy
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+const (
+	nsA = `xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"`
+	nsR = `xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+	nsP = `xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"`
+
+	xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+	relTypeBase = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/"
+	ctBase      = "application/vnd.openxmlformats-officedocument.presentationml."
+
+	emptyTree = `<p:nvGrpSpPr><p:cNvPr id="1" name=""/><p:cNvGrpSpPr/><p:nvPr/></p:nvGrpSpPr><p:grpSpPr/>`
+	clrMap    = `<p:clrMap bg1="lt1" tx1="dk1" bg2="lt2" tx2="dk2" accent1="accent1" accent2="accent2" accent3="accent3" accent4="accent4" accent5="accent5" accent6="accent6" hlink="hlink" folHlink="folHlink"/>`
+)
+
+func relsXML(rels ...string) string {
+	return xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		strings.Join(rels, "") + `</Relationships>`
+}
+
+func rel(id, typ, target string) string {
+	return fmt.Sprintf(`<Relationship Id="%s" Type="%s%s" Target="%s"/>`, id, relTypeBase, typ, target)
+}
+
+func contentTypesXML(slides []Slide) string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Default Extension="png" ContentType="image/png"/>`)
+	b.WriteString(`<Default Extension="svg" ContentType="image/svg+xml"/>`)
+	override := func(part, ct string) {
+		fmt.Fprintf(&b, `<Override PartName="%s" ContentType="%s"/>`, part, ct)
+	}
+	override("/ppt/presentation.xml", ctBase+"presentation.main+xml")
+	override("/ppt/slideMasters/slideMaster1.xml", ctBase+"slideMaster+xml")
+	override("/ppt/slideLayouts/slideLayout1.xml", ctBase+"slideLayout+xml")
+	override("/ppt/notesMasters/notesMaster1.xml", ctBase+"notesMaster+xml")
+	override("/ppt/theme/theme1.xml", "application/vnd.openxmlformats-officedocument.theme+xml")
+	override("/ppt/theme/theme2.xml", "application/vnd.openxmlformats-officedocument.theme+xml")
+	for i := range slides {
+		override(fmt.Sprintf("/ppt/slides/slide%d.xml", i+1), ctBase+"slide+xml")
+		override(fmt.Sprintf("/ppt/notesSlides/notesSlide%d.xml", i+1), ctBase+"notesSlide+xml")
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+var rootRelsXML = relsXML(rel("rId1", "officeDocument", "ppt/presentation.xml"))
+
+func presentationXML(n int) string {
+	var ids strings.Builder
+	for i := range n {
+		fmt.Fprintf(&ids, `<p:sldId id="%d" r:id="rId%d"/>`, 256+i, 4+i)
+	}
+	return xmlHeader + `<p:presentation ` + nsA + ` ` + nsR + ` ` + nsP + `>` +
+		`<p:sldMasterIdLst><p:sldMasterId id="2147483648" r:id="rId1"/></p:sldMasterIdLst>` +
+		`<p:notesMasterIdLst><p:notesMasterId r:id="rId3"/></p:notesMasterIdLst>` +
+		`<p:sldIdLst>` + ids.String() + `</p:sldIdLst>` +
+		fmt.Sprintf(`<p:sldSz cx="%d" cy="%d"/>`, slideWidthEMU, slideHeightEMU) +
+		`<p:notesSz cx="6858000" cy="9144000"/>` +
+		`</p:presentation>`
+}
+
+func presentationRelsXML(n int) string {
+	rels := []string{
+		rel("rId1", "slideMaster", "slideMasters/slideMaster1.xml"),
+		rel("rId2", "theme", "theme/theme1.xml"),
+		rel("rId3", "notesMaster", "notesMasters/notesMaster1.xml"),
+	}
+	for i := range n {
+		rels = append(rels, rel(fmt.Sprintf("rId%d", 4+i), "slide", fmt.Sprintf("slides/slide%d.xml", i+1)))
+	}
+	return relsXML(rels...)
+}
+
+var (
+	slideMasterXML = xmlHeader + `<p:sldMaster ` + nsA + ` ` + nsR + ` ` + nsP + `>` +
+		`<p:cSld><p:spTree>` + emptyTree + `</p:spTree></p:cSld>` + clrMap +
+		`<p:sldLayoutIdLst><p:sldLayoutId id="2147483649" r:id="rId1"/></p:sldLayoutIdLst>` +
+		`</p:sldMaster>`
+	slideMasterRelsXML = relsXML(
+		rel("rId1", "slideLayout", "../slideLayouts/slideLayout1.xml"),
+		rel("rId2", "theme", "../theme/theme1.xml"),
+	)
+
+	slideLayoutXML = xmlHeader + `<p:sldLayout ` + nsA + ` ` + nsR + ` ` + nsP + ` type="blank" preserve="1">` +
+		`<p:cSld name="Blank"><p:spTree>` + emptyTree + `</p:spTree></p:cSld>` +
+		`<p:clrMapOvr><a:masterClrMapping/></p:clrMapOvr></p:sldLayout>`
+	slideLayoutRelsXML = relsXML(rel("rId1", "slideMaster", "../slideMasters/slideMaster1.xml"))
+
+	notesMasterXML = xmlHeader + `<p:notesMaster ` + nsA + ` ` + nsR + ` ` + nsP + `>` +
+		`<p:cSld><p:spTree>` + emptyTree +
+		`<p:sp><p:nvSpPr><p:cNvPr id="2" name="Notes Placeholder"/><p:cNvSpPr><a:spLocks noGrp="1"/></p:cNvSpPr><p:nvPr><p:ph type="body" idx="1"/></p:nvPr></p:nvSpPr>` +
+		`<p:spPr><a:xfrm><a:off x="685800" y="4343400"/><a:ext cx="5486400" cy="4114800"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></p:spPr>` +
+		`<p:txBody><a:bodyPr/><a:lstStyle/><a:p><a:endParaRPr lang="en-US"/></a:p></p:txBody></p:sp>` +
+		`</p:spTree></p:cSld>` + clrMap + `</p:notesMaster>`
+	notesMasterRelsXML = relsXML(rel("rId1", "theme", "../theme/theme2.xml"))
+)
+
+func slideXML(s Slide, imgW, imgH int) string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<p:sld ` + nsA + ` ` + nsR + ` ` + nsP + `><p:cSld><p:spTree>` + emptyTree)
+
+	if s.Title != "" {
+		fmt.Fprintf(&b, `<p:sp><p:nvSpPr><p:cNvPr id="2" name="Title"/><p:cNvSpPr txBox="1"/><p:nvPr/></p:nvSpPr>`+
+			`<p:spPr><a:xfrm><a:off x="%d" y="%d"/><a:ext cx="%d" cy="%d"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></p:spPr>`+
+			`<p:txBody><a:bodyPr anchor="ctr"/><a:lstStyle/><a:p><a:r><a:rPr lang="en-US" sz="2800" b="1"/><a:t>%s</a:t></a:r></a:p></p:txBody></p:sp>`,
+			slideMarginEMU, slideMarginEMU, slideWidthEMU-2*slideMarginEMU, titleHeightEMU, escapeXML(s.Title))
+	}
+
+	x, y, cx, cy := fitPicture(imgW, imgH, s.Title != "")
+	svgExt := ""
+	if len(s.SVG) > 0 {
+		svgExt = `<a:extLst><a:ext uri="{96DAC541-7B7A-43D3-8B79-37D633B846F1}">` +
+			`<asvg:svgBlip xmlns:asvg="http://schemas.microsoft.com/office/drawing/2016/SVG/main" r:embed="rId3"/>` +
+			`</a:ext></a:extLst>`
+	}
+	fmt.Fprintf(&b, `<p:pic><p:nvPicPr><p:cNvPr id="3" name="Tower"/><p:cNvPicPr><a:picLocks noChangeAspect="1"/></p:cNvPicPr><p:nvPr/></p:nvPicPr>`+
+		`<p:blipFill><a:blip r:embed="rId2">%s</a:blip><a:stretch><a:fillRect/></a:stretch></p:blipFill>`+
+		`<p:spPr><a:xfrm><a:off x="%d" y="%d"/><a:ext cx="%d" cy="%d"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></p:spPr></p:pic>`,
+		svgExt, x, y, cx, cy)
+
+	b.WriteString(`</p:spTree></p:cSld><p:clrMapOvr><a:masterClrMapping/></p:clrMapOvr></p:sld>`)
+	return b.String()
+}
+
+func slideRelsXML(n int, hasSVG bool) string {
+	rels := []string{
+		rel("rId1", "slideLayout", "../slideLayouts/slideLayout1.xml"),
+		rel("rId2", "image", fmt.Sprintf("../media/image%d.png", n)),
+	}
+	if hasSVG {
+		rels = append(rels, rel("rId3", "image", fmt.Sprintf("../media/image%d.svg", n)))
+	}
+	rels = append(rels, rel("rId4", "notesSlide", fmt.Sprintf("../notesSlides/notesSlide%d.xml", n)))
+	return relsXML(rels...)
+}
+
+func notesSlideXML(notes string) string {
+	var paras strings.Builder
+	for line := range strings.SplitSeq(notes, "\n") {
+		if line == "" {
+			paras.WriteString(`<a:p><a:endParaRPr lang="en-US"/></a:p>`)
+			continue
+		}
+		fmt.Fprintf(&paras, `<a:p><a:r><a:rPr lang="en-US"/><a:t>%s</a:t></a:r></a:p>`, escapeXML(line))
+	}
+	return xmlHeader + `<p:notes ` + nsA + ` ` + nsR + ` ` + nsP + `><p:cSld><p:spTree>` + emptyTree +
+		`<p:sp><p:nvSpPr><p:cNvPr id="2" name="Notes Placeholder"/><p:cNvSpPr><a:spLocks noGrp="1"/></p:cNvSpPr><p:nvPr><p:ph type="body" idx="1"/></p:nvPr></p:nvSpPr>` +
+		`<p:spPr/><p:txBody><a:bodyPr/><a:lstStyle/>` + paras.String() + `</p:txBody></p:sp>` +
+		`</p:spTree></p:cSld><p:clrMapOvr><a:masterClrMapping/></p:clrMapOvr></p:notes>`
+}
+
+func notesSlideRelsXML(n int) string {
+	return relsXML(
+		rel("rId1", "notesMaster", "../notesMasters/notesMaster1.xml"),
+		rel("rId2", "slide", fmt.Sprintf("../slides/slide%d.xml", n)),
+	)
+}
+
+// themeXML is a minimal Office theme; PowerPoint requires one per master.
+const themeXML = xmlHeader + `<a:theme ` + nsA + ` name="Stacktower">` +
+	`<a:themeElements>` +
+	`<a:clrScheme name="Stacktower">` +
+	`<a:dk1><a:srgbClr val="000000"/></a:dk1><a:lt1><a:srgbClr val="FFFFFF"/></a:lt1>` +
+	`<a:dk2><a:srgbClr val="333333"/></a:dk2><a:lt2><a:srgbClr val="EEEEEE"/></a:lt2>` +
+	`<a:accent1><a:srgbClr val="2563EB"/></a:accent1><a:accent2><a:srgbClr val="F59E0B"/></a:accent2>` +
+	`<a:accent3><a:srgbClr val="10B981"/></a:accent3><a:accent4><a:srgbClr val="EF4444"/></a:accent4>` +
+	`<a:accent5><a:srgbClr val="8B5CF6"/></a:accent5><a:accent6><a:srgbClr val="6B7280"/></a:accent6>` +
+	`<a:hlink><a:srgbClr val="2563EB"/></a:hlink><a:folHlink><a:srgbClr val="7C3AED"/></a:folHlink>` +
+	`</a:clrScheme>` +
+	`<a:fontScheme name="Stacktower">` +
+	`<a:majorFont><a:latin typeface="Calibri"/><a:ea typeface=""/><a:cs typeface=""/></a:majorFont>` +
+	`<a:minorFont><a:latin typeface="Calibri"/><a:ea typeface=""/><a:cs typeface=""/></a:minorFont>` +
+	`</a:fontScheme>` +
+	`<a:fmtScheme name="Stacktower">` +
+	`<a:fillStyleLst>` + themeFill + themeFill + themeFill + `</a:fillStyleLst>` +
+	`<a:lnStyleLst>` + themeLine + themeLine + themeLine + `</a:lnStyleLst>` +
+	`<a:effectStyleLst>` + themeEffect + themeEffect + themeEffect + `</a:effectStyleLst>` +
+	`<a:bgFillStyleLst>` + themeFill + themeFill + themeFill + `</a:bgFillStyleLst>` +
+	`</a:fmtScheme>` +
+	`</a:themeElements></a:theme>`
+
+const (
+	themeFill   = `<a:solidFill><a:schemeClr val="phClr"/></a:solidFill>`
+	themeLine   = `<a:ln w="9525"><a:solidFill><a:schemeClr val="phClr"/></a:solidFill></a:ln>`
+	themeEffect = `<a:effectStyle><a:effectLst/></a:effectStyle>`
+)
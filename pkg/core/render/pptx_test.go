@@ -0,0 +1,125 @@
+package render
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+)
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func readZip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("open zip: %v", err)
+	}
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(b)
+	}
+	return files
+}
+
+func TestToPPTX(t *testing.T) {
+	slides := []Slide{
+		{Title: "flask & friends", PNG: testPNG(t, 400, 300), SVG: []byte("<svg/>"), Notes: "1. alice\n2. bob"},
+		{PNG: testPNG(t, 100, 400)},
+	}
+	data, err := ToPPTX(slides)
+	if err != nil {
+		t.Fatalf("ToPPTX: %v", err)
+	}
+	files := readZip(t, data)
+
+	for _, part := range []string{
+		"[Content_Types].xml",
+		"ppt/presentation.xml",
+		"ppt/slides/slide1.xml",
+		"ppt/slides/slide2.xml",
+		"ppt/media/image1.png",
+		"ppt/media/image1.svg",
+		"ppt/notesSlides/notesSlide1.xml",
+	} {
+		if _, ok := files[part]; !ok {
+			t.Errorf("missing part %s", part)
+		}
+	}
+	if _, ok := files["ppt/media/image2.svg"]; ok {
+		t.Error("slide without SVG should not embed one")
+	}
+
+	// Every XML part must be well-formed.
+	for name, content := range files {
+		if !strings.HasSuffix(name, ".xml") && !strings.HasSuffix(name, ".rels") {
+			continue
+		}
+		dec := xml.NewDecoder(strings.NewReader(content))
+		for {
+			if _, err := dec.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Errorf("%s: malformed XML: %v", name, err)
+				break
+			}
+		}
+	}
+
+	if !strings.Contains(files["ppt/slides/slide1.xml"], "flask &amp; friends") {
+		t.Error("slide title should be escaped")
+	}
+	if !strings.Contains(files["ppt/slides/slide1.xml"], "svgBlip") {
+		t.Error("slide 1 should reference the SVG")
+	}
+	notes := files["ppt/notesSlides/notesSlide1.xml"]
+	if !strings.Contains(notes, "<a:t>1. alice</a:t>") || !strings.Contains(notes, "<a:t>2. bob</a:t>") {
+		t.Errorf("speaker notes missing lines: %s", notes)
+	}
+}
+
+func TestToPPTX_Errors(t *testing.T) {
+	if _, err := ToPPTX(nil); err == nil {
+		t.Error("expected error for empty deck")
+	}
+	if _, err := ToPPTX([]Slide{{Title: "x"}}); err == nil {
+		t.Error("expected error for slide without image")
+	}
+}
+
+func TestFitPicture(t *testing.T) {
+	// Wide image: limited by width.
+	_, _, cx, cy := fitPicture(2000, 100, false)
+	if cx != slideWidthEMU-2*slideMarginEMU {
+		t.Errorf("wide image width = %d, want full content width", cx)
+	}
+	if cy >= cx {
+		t.Errorf("wide image should keep aspect ratio, got %dx%d", cx, cy)
+	}
+
+	// Tall image: limited by height below the title.
+	_, y, cx, cy := fitPicture(100, 2000, true)
+	if y < slideMarginEMU+titleHeightEMU {
+		t.Errorf("picture y = %d overlaps the title", y)
+	}
+	if cx >= cy {
+		t.Errorf("tall image should keep aspect ratio, got %dx%d", cx, cy)
+	}
+}
@@ -0,0 +1,1077 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // decoders for <image> data URIs
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/colornames"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+	"golang.org/x/image/vector"
+)
+
+// maxRasterPixels bounds the images [Rasterize] draws, so that a huge
+// document or scale fails instead of exhausting memory.
+const maxRasterPixels = 1 << 27
+
+// maxUses bounds the <use> references a document expands in total, so that
+// references fanning out over many levels fail instead of drawing forever.
+const maxUses = 10000
+
+// Rasterize draws an SVG document into an image scale times its size, in
+// process and without external tools.
+//
+// It covers what Stacktower's renderers emit: shapes, paths, groups and
+// nested documents with transforms, strokes with dashes, caps and joins,
+// text set in the Go fonts, data URI images, and simple CSS rules from
+// <style> elements. Filters, masks and clip paths are ignored; gradients
+// and patterns paint with their first color. Fonts named by the document
+// are replaced by the Go fonts, so text differs slightly from a browser's.
+func Rasterize(svg []byte, scale float64) (*image.RGBA, error) {
+	return RasterizeContext(context.Background(), svg, scale)
+}
+
+// RasterizeContext is like [Rasterize] but stops drawing when ctx is done.
+func RasterizeContext(ctx context.Context, svg []byte, scale float64) (*image.RGBA, error) {
+	if scale <= 0 {
+		return nil, fmt.Errorf("rasterize: scale must be positive, got %g", scale)
+	}
+	root, err := parseSVG(svg)
+	if err != nil {
+		return nil, fmt.Errorf("rasterize: %w", err)
+	}
+
+	width, height, viewport := rootViewport(root)
+	// Check the size in float64: huge documents overflow int
+	fw, fh := math.Ceil(width*scale), math.Ceil(height*scale)
+	if !(fw > 0 && fh > 0) {
+		return nil, fmt.Errorf("rasterize: document has no size")
+	}
+	if fw*fh > maxRasterPixels {
+		return nil, fmt.Errorf("rasterize: %.0fx%.0f pixels exceeds the limit of %d", fw, fh, maxRasterPixels)
+	}
+	w, h := int(fw), int(fh)
+
+	r := &rasterizer{
+		ctx:      ctx,
+		img:      image.NewRGBA(image.Rect(0, 0, w, h)),
+		rootNode: root,
+		ids:      map[string]*svgNode{},
+	}
+	r.index(root)
+	r.draw(root, scaling(scale, scale).mul(viewport), defaultStyle())
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.img, nil
+}
+
+// rasterizePNG rasterizes svg and encodes it as PNG.
+func rasterizePNG(ctx context.Context, svg []byte, scale float64) ([]byte, error) {
+	img, err := RasterizeContext(ctx, svg, scale)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// svgNode is an element of a parsed SVG document, or character data when
+// name is empty.
+type svgNode struct {
+	name     string
+	attrs    map[string]string
+	children []*svgNode
+	text     string
+}
+
+// parseSVG reads a document into a tree of its elements and returns the
+// root <svg> element.
+func parseSVG(data []byte) (*svgNode, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+
+	var root *svgNode
+	var stack []*svgNode
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse svg: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &svgNode{name: t.Name.Local, attrs: make(map[string]string, len(t.Attr))}
+			for _, a := range t.Attr {
+				n.attrs[a.Name.Local] = a.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			} else if root == nil {
+				root = n
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, &svgNode{text: string(t)})
+			}
+		}
+	}
+	if root == nil || root.name != "svg" {
+		return nil, fmt.Errorf("parse svg: no <svg> root element")
+	}
+	return root, nil
+}
+
+// rootViewport returns the size of a document in pixels and the transform
+// from its viewBox to that size.
+func rootViewport(n *svgNode) (width, height float64, m matrix) {
+	vb, hasViewBox := parseViewBox(n.attrs["viewBox"])
+	width, wok := parseLength(n.attrs["width"], 0)
+	height, hok := parseLength(n.attrs["height"], 0)
+	if !wok || strings.HasSuffix(n.attrs["width"], "%") {
+		width = vb[2]
+	}
+	if !hok || strings.HasSuffix(n.attrs["height"], "%") {
+		height = vb[3]
+	}
+	if !hasViewBox {
+		return width, height, identity()
+	}
+	return width, height, viewBoxTransform(vb, width, height, n.attrs["preserveAspectRatio"])
+}
+
+// rasterizer draws a parsed document into an image.
+type rasterizer struct {
+	ctx      context.Context
+	img      *image.RGBA
+	rootNode *svgNode
+	ids      map[string]*svgNode // elements by id, for <use> and paint servers
+	rules    []cssRule
+	nodes    int
+	uses     int // depth of <use> references being drawn
+	expanded int // <use> references drawn so far
+	err      error
+}
+
+// index records elements by id and collects the rules of <style> elements.
+func (r *rasterizer) index(n *svgNode) {
+	if id := n.attrs["id"]; id != "" {
+		if _, ok := r.ids[id]; !ok {
+			r.ids[id] = n
+		}
+	}
+	if n.name == "style" {
+		var css strings.Builder
+		for _, c := range n.children {
+			css.WriteString(c.text)
+		}
+		r.rules = append(r.rules, parseCSS(css.String())...)
+	}
+	for _, c := range n.children {
+		r.index(c)
+	}
+}
+
+// skippedElements hold definitions or metadata that are not drawn where
+// they appear.
+var skippedElements = map[string]bool{
+	"defs": true, "title": true, "desc": true, "style": true, "metadata": true,
+	"script": true, "pattern": true, "linearGradient": true, "radialGradient": true,
+	"clipPath": true, "mask": true, "filter": true, "marker": true, "symbol": true,
+	"foreignObject": true,
+}
+
+// draw draws an element and its children under transform m.
+func (r *rasterizer) draw(n *svgNode, m matrix, parent svgStyle) {
+	if r.err != nil || n.name == "" || skippedElements[n.name] {
+		return
+	}
+	if r.nodes++; r.nodes%256 == 1 {
+		if err := r.ctx.Err(); err != nil {
+			r.err = fmt.Errorf("rasterize: %w", err)
+			return
+		}
+	}
+
+	decls := r.declarations(n)
+	if decls["display"] == "none" {
+		return
+	}
+	st := parent.inherit()
+	st.apply(decls, r)
+	if t, ok := n.attrs["transform"]; ok {
+		m = m.mul(parseTransform(t))
+	}
+
+	switch n.name {
+	case "svg":
+		if n != r.rootNode {
+			m = m.mul(r.nestedViewport(n))
+		}
+		r.drawChildren(n, m, st)
+	case "g", "a", "switch":
+		r.drawChildren(n, m, st)
+	case "use":
+		ref := r.ids[strings.TrimPrefix(href(n), "#")]
+		if ref == nil || r.uses >= 16 {
+			return
+		}
+		if r.expanded++; r.expanded > maxUses {
+			r.err = fmt.Errorf("rasterize: document expands more than %d <use> references", maxUses)
+			return
+		}
+		r.uses++
+		defer func() { r.uses-- }()
+		x, _ := parseLength(n.attrs["x"], 0)
+		y, _ := parseLength(n.attrs["y"], 0)
+		m = m.mul(translation(x, y))
+		if ref.name == "symbol" {
+			r.drawChildren(ref, m, st)
+			return
+		}
+		r.draw(ref, m, st)
+	case "text":
+		if st.visible {
+			r.drawText(n, m, st)
+		}
+	case "image":
+		if st.visible {
+			r.drawImage(n, m, st)
+		}
+	default:
+		if p := shapePath(n); p != nil && st.visible {
+			r.paint(p, m, st)
+		}
+	}
+}
+
+func (r *rasterizer) drawChildren(n *svgNode, m matrix, st svgStyle) {
+	for _, c := range n.children {
+		r.draw(c, m, st)
+	}
+}
+
+// nestedViewport returns the transform of a nested <svg> element: its
+// position, then its viewBox fitted to its size.
+func (r *rasterizer) nestedViewport(n *svgNode) matrix {
+	x, _ := parseLength(n.attrs["x"], 0)
+	y, _ := parseLength(n.attrs["y"], 0)
+	m := translation(x, y)
+	vb, ok := parseViewBox(n.attrs["viewBox"])
+	w, wok := parseLength(n.attrs["width"], 0)
+	h, hok := parseLength(n.attrs["height"], 0)
+	if ok && wok && hok {
+		m = m.mul(viewBoxTransform(vb, w, h, n.attrs["preserveAspectRatio"]))
+	}
+	return m
+}
+
+// paint fills and strokes a path.
+func (r *rasterizer) paint(p path, m matrix, st svgStyle) {
+	if st.fill.ok {
+		if c, ok := st.fill.with(st.fillOpacity * st.opacity); ok {
+			r.fill(p.flatten(m), c)
+		}
+	}
+	if st.stroke.ok && st.strokeWidth > 0 {
+		if c, ok := st.stroke.with(st.strokeOpacity * st.opacity); ok {
+			s := m.scale()
+			dash := make([]float64, len(st.dash))
+			for i, d := range st.dash {
+				dash[i] = d * s
+			}
+			polys := strokePolylines(p.flatten(m), stroker{
+				width:      st.strokeWidth * s,
+				cap:        st.lineCap,
+				join:       st.lineJoin,
+				miterLimit: st.miterLimit,
+				dash:       dash,
+				dashOffset: st.dashOffset * s,
+			})
+			r.fill(polys, c)
+		}
+	}
+}
+
+// fill draws the union of polygons in one color, rasterizing only their
+// bounding box.
+func (r *rasterizer) fill(polys []polyline, c color.RGBA) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, pl := range polys {
+		for _, p := range pl.pts {
+			minX, minY = math.Min(minX, p.x), math.Min(minY, p.y)
+			maxX, maxY = math.Max(maxX, p.x), math.Max(maxY, p.y)
+		}
+	}
+	box := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX))+1, int(math.Ceil(maxY))+1)
+	box = box.Intersect(r.img.Bounds())
+	if box.Empty() {
+		return
+	}
+
+	var z vector.Rasterizer
+	z.Reset(box.Dx(), box.Dy())
+	ox, oy := float32(box.Min.X), float32(box.Min.Y)
+	for _, pl := range polys {
+		if len(pl.pts) < 3 {
+			continue
+		}
+		z.MoveTo(float32(pl.pts[0].x)-ox, float32(pl.pts[0].y)-oy)
+		for _, p := range pl.pts[1:] {
+			z.LineTo(float32(p.x)-ox, float32(p.y)-oy)
+		}
+		z.ClosePath()
+	}
+	z.DrawOp = draw.Over
+	z.Draw(r.img, box, image.NewUniform(c), image.Point{})
+}
+
+// drawImage draws an <image> element whose href is a data URI holding a
+// PNG, JPEG or GIF, or an SVG document.
+func (r *rasterizer) drawImage(n *svgNode, m matrix, st svgStyle) {
+	w, wok := parseLength(n.attrs["width"], 0)
+	h, hok := parseLength(n.attrs["height"], 0)
+	data, mime, ok := decodeDataURI(href(n))
+	if !ok {
+		return
+	}
+	var img image.Image
+	if mime == "image/svg+xml" {
+		nested, err := parseSVG(data)
+		if err != nil {
+			return
+		}
+		vw, vh, _ := rootViewport(nested)
+		if !wok || !hok {
+			w, h = vw, vh
+		}
+		img, err = RasterizeContext(r.ctx, data, math.Max(1, m.scale()*math.Max(w/math.Max(vw, 1), h/math.Max(vh, 1))))
+		if err != nil {
+			return
+		}
+	} else {
+		var err error
+		if img, _, err = image.Decode(bytes.NewReader(data)); err != nil {
+			return
+		}
+	}
+	b := img.Bounds()
+	if !wok || !hok {
+		w, h = float64(b.Dx()), float64(b.Dy())
+	}
+	if b.Empty() || w <= 0 || h <= 0 {
+		return
+	}
+
+	x, _ := parseLength(n.attrs["x"], 0)
+	y, _ := parseLength(n.attrs["y"], 0)
+	fit := viewBoxTransform([4]float64{0, 0, float64(b.Dx()), float64(b.Dy())}, w, h, n.attrs["preserveAspectRatio"])
+	t := m.mul(translation(x, y)).mul(fit).mul(translation(float64(-b.Min.X), float64(-b.Min.Y)))
+	aff := f64.Aff3{t.a, t.c, t.e, t.b, t.d, t.f}
+
+	var opts *draw.Options
+	if alpha := st.opacity; alpha < 1 {
+		mask := image.NewUniform(color.Alpha{A: uint8(math.Round(alpha * 255))})
+		opts = &draw.Options{SrcMask: mask}
+	}
+	draw.BiLinear.Transform(r.img, aff, img, b, draw.Over, opts)
+}
+
+// href returns the link of a <use> or <image> element.
+func href(n *svgNode) string {
+	if h, ok := n.attrs["href"]; ok {
+		return h
+	}
+	return n.attrs["xlink:href"]
+}
+
+// decodeDataURI decodes a data: URI into its bytes and media type.
+func decodeDataURI(uri string) ([]byte, string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(uri), "data:")
+	if !ok {
+		return nil, "", false
+	}
+	meta, payload, ok := strings.Cut(rest, ",")
+	if !ok {
+		return nil, "", false
+	}
+	mime, _, _ := strings.Cut(meta, ";")
+	if strings.HasSuffix(meta, ";base64") {
+		data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(payload), ""))
+		return data, mime, err == nil
+	}
+	data, err := url.PathUnescape(payload)
+	return []byte(data), mime, err == nil
+}
+
+// shapePath returns the outline of a basic shape or <path>, or nil for
+// other elements.
+func shapePath(n *svgNode) path {
+	num := func(name string) float64 {
+		v, _ := parseLength(n.attrs[name], 0)
+		return v
+	}
+	switch n.name {
+	case "path":
+		return parsePathData(n.attrs["d"])
+	case "rect":
+		rx, rxok := parseLength(n.attrs["rx"], 0)
+		ry, ryok := parseLength(n.attrs["ry"], 0)
+		if !rxok {
+			rx = ry
+		}
+		if !ryok {
+			ry = rx
+		}
+		return rectPath(num("x"), num("y"), num("width"), num("height"), rx, ry)
+	case "circle":
+		r := num("r")
+		return ellipsePath(num("cx"), num("cy"), r, r)
+	case "ellipse":
+		return ellipsePath(num("cx"), num("cy"), num("rx"), num("ry"))
+	case "line":
+		var p path
+		p.moveTo(point{num("x1"), num("y1")})
+		p.lineTo(point{num("x2"), num("y2")})
+		return p
+	case "polyline", "polygon":
+		nums := parseNumbers(n.attrs["points"])
+		var p path
+		for i := 0; i+1 < len(nums); i += 2 {
+			if i == 0 {
+				p.moveTo(point{nums[0], nums[1]})
+			} else {
+				p.lineTo(point{nums[i], nums[i+1]})
+			}
+		}
+		if n.name == "polygon" && len(p) > 0 {
+			p.close()
+		}
+		return p
+	}
+	return nil
+}
+
+// paint is a fill or stroke color; ok is false for none.
+type paint struct {
+	c  color.NRGBA
+	ok bool
+}
+
+// with returns the paint's color premultiplied by opacity, and false when
+// it is fully transparent.
+func (p paint) with(opacity float64) (color.RGBA, bool) {
+	a := float64(p.c.A) / 255 * math.Max(0, math.Min(1, opacity))
+	if !p.ok || a <= 0 {
+		return color.RGBA{}, false
+	}
+	return color.RGBA{
+		R: uint8(math.Round(float64(p.c.R) * a)),
+		G: uint8(math.Round(float64(p.c.G) * a)),
+		B: uint8(math.Round(float64(p.c.B) * a)),
+		A: uint8(math.Round(a * 255)),
+	}, true
+}
+
+// svgStyle is the computed style of an element.
+type svgStyle struct {
+	fill, stroke               paint
+	color                      color.NRGBA
+	fillOpacity, strokeOpacity float64
+	opacity                    float64 // product of the element's and its ancestors' opacity
+	strokeWidth                float64
+	dash                       []float64
+	dashOffset                 float64
+	lineCap, lineJoin          string
+	miterLimit                 float64
+	fontSize                   float64
+	fontFamily, fontWeight     string
+	fontStyle, textAnchor      string
+	baseline                   string
+	visible                    bool
+}
+
+func defaultStyle() svgStyle {
+	black := color.NRGBA{A: 0xff}
+	return svgStyle{
+		fill:          paint{c: black, ok: true},
+		color:         black,
+		fillOpacity:   1,
+		strokeOpacity: 1,
+		opacity:       1,
+		strokeWidth:   1,
+		lineCap:       "butt",
+		lineJoin:      "miter",
+		miterLimit:    4,
+		fontSize:      16,
+		fontFamily:    "sans-serif",
+		fontWeight:    "normal",
+		fontStyle:     "normal",
+		textAnchor:    "start",
+		baseline:      "auto",
+		visible:       true,
+	}
+}
+
+// inherit returns the style a child starts from. Every property is
+// inherited except those that apply per element, like the baseline.
+func (s svgStyle) inherit() svgStyle {
+	s.baseline = "auto"
+	return s
+}
+
+// apply sets the properties of decls.
+func (s *svgStyle) apply(decls map[string]string, r *rasterizer) {
+	for name, value := range decls {
+		if value == "inherit" {
+			continue
+		}
+		switch name {
+		case "color":
+			if c, ok := parseColor(value, s.color); ok {
+				s.color = c
+			}
+		}
+	}
+	for name, value := range decls {
+		if value == "inherit" {
+			continue
+		}
+		switch name {
+		case "fill":
+			if p, ok := r.parsePaint(value, s.color); ok {
+				s.fill = p
+			}
+		case "stroke":
+			if p, ok := r.parsePaint(value, s.color); ok {
+				s.stroke = p
+			}
+		case "fill-opacity":
+			s.fillOpacity = parseOpacity(value, s.fillOpacity)
+		case "stroke-opacity":
+			s.strokeOpacity = parseOpacity(value, s.strokeOpacity)
+		case "opacity":
+			s.opacity *= parseOpacity(value, 1)
+		case "stroke-width":
+			if v, ok := parseLength(value, s.fontSize); ok && v >= 0 {
+				s.strokeWidth = v
+			}
+		case "stroke-dasharray":
+			s.dash = parseDashArray(value)
+		case "stroke-dashoffset":
+			s.dashOffset, _ = parseLength(value, s.fontSize)
+		case "stroke-linecap":
+			s.lineCap = value
+		case "stroke-linejoin":
+			s.lineJoin = value
+		case "stroke-miterlimit":
+			if v, err := strconv.ParseFloat(value, 64); err == nil && v >= 1 {
+				s.miterLimit = v
+			}
+		case "font-size":
+			if v, ok := parseFontSize(value, s.fontSize); ok {
+				s.fontSize = v
+			}
+		case "font-family":
+			s.fontFamily = value
+		case "font-weight":
+			s.fontWeight = value
+		case "font-style":
+			s.fontStyle = value
+		case "text-anchor":
+			s.textAnchor = value
+		case "dominant-baseline", "alignment-baseline":
+			s.baseline = value
+		case "visibility":
+			s.visible = value == "visible"
+		}
+	}
+}
+
+// parsePaint reads a fill or stroke value. Paint servers paint with their
+// first color, or the fallback color given after the reference.
+func (r *rasterizer) parsePaint(value string, current color.NRGBA) (paint, bool) {
+	value = strings.TrimSpace(value)
+	if value == "none" || value == "transparent" {
+		return paint{}, true
+	}
+	if rest, ok := strings.CutPrefix(value, "url("); ok {
+		ref, fallback, _ := strings.Cut(rest, ")")
+		ref = strings.Trim(strings.TrimSpace(ref), `'"`)
+		if c, ok := r.serverColor(strings.TrimPrefix(ref, "#"), current, 0); ok {
+			return c, true
+		}
+		if fallback = strings.TrimSpace(fallback); fallback != "" {
+			return r.parsePaint(fallback, current)
+		}
+		return paint{}, true
+	}
+	c, ok := parseColor(value, current)
+	return paint{c: c, ok: true}, ok
+}
+
+// serverColor returns the color a gradient or pattern paints with: its
+// first stop, or the fill of its first filled shape.
+func (r *rasterizer) serverColor(id string, current color.NRGBA, depth int) (paint, bool) {
+	n := r.ids[id]
+	if n == nil || depth > 8 {
+		return paint{}, false
+	}
+	switch n.name {
+	case "linearGradient", "radialGradient":
+		for _, c := range n.children {
+			if c.name != "stop" {
+				continue
+			}
+			decls := r.declarations(c)
+			col, ok := parseColor(cmpOr(decls["stop-color"], "black"), current)
+			if !ok {
+				return paint{}, false
+			}
+			col.A = uint8(math.Round(float64(col.A) * parseOpacity(decls["stop-opacity"], 1)))
+			return paint{c: col, ok: true}, true
+		}
+		if ref := strings.TrimPrefix(href(n), "#"); ref != "" {
+			return r.serverColor(ref, current, depth+1)
+		}
+	case "pattern":
+		for _, c := range n.children {
+			if c.name == "" || shapePath(c) == nil {
+				continue
+			}
+			if p, ok := r.parsePaint(cmpOr(r.declarations(c)["fill"], "black"), current); ok && p.ok {
+				return p, true
+			}
+		}
+		if ref := strings.TrimPrefix(href(n), "#"); ref != "" {
+			return r.serverColor(ref, current, depth+1)
+		}
+	}
+	return paint{}, false
+}
+
+func cmpOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// declarations returns the style properties set on an element, with
+// presentation attributes overridden by matching CSS rules and those by
+// the style attribute.
+func (r *rasterizer) declarations(n *svgNode) map[string]string {
+	decls := make(map[string]string)
+	for name, value := range n.attrs {
+		if styleProperties[name] {
+			decls[name] = strings.TrimSpace(value)
+		}
+	}
+	type match struct {
+		specificity, order int
+		rule               *cssRule
+	}
+	var matches []match
+	for i := range r.rules {
+		if s, ok := r.rules[i].matches(n); ok {
+			matches = append(matches, match{s, i, &r.rules[i]})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int {
+		if a.specificity != b.specificity {
+			return a.specificity - b.specificity
+		}
+		return a.order - b.order
+	})
+	for _, m := range matches {
+		for _, d := range m.rule.decls {
+			decls[d[0]] = d[1]
+		}
+	}
+	for _, d := range parseDeclarations(n.attrs["style"]) {
+		decls[d[0]] = d[1]
+	}
+	return decls
+}
+
+// styleProperties are the presentation attributes the rasterizer reads.
+var styleProperties = map[string]bool{
+	"fill": true, "stroke": true, "color": true, "fill-opacity": true,
+	"stroke-opacity": true, "opacity": true, "stroke-width": true,
+	"stroke-dasharray": true, "stroke-dashoffset": true, "stroke-linecap": true,
+	"stroke-linejoin": true, "stroke-miterlimit": true, "font-size": true,
+	"font-family": true, "font-weight": true, "font-style": true,
+	"text-anchor": true, "dominant-baseline": true, "alignment-baseline": true,
+	"visibility": true, "display": true, "stop-color": true, "stop-opacity": true,
+}
+
+// cssRule is a rule of a <style> element with simple selectors.
+type cssRule struct {
+	selectors []cssSelector
+	decls     [][2]string
+}
+
+// cssSelector matches elements by type, id and classes, like "rect.block".
+type cssSelector struct {
+	tag, id string
+	classes []string
+}
+
+// matches reports whether a selector of the rule matches n, and the
+// highest specificity among those that do.
+func (rule *cssRule) matches(n *svgNode) (int, bool) {
+	best, found := 0, false
+	var classes []string
+	for _, sel := range rule.selectors {
+		if sel.tag != "" && sel.tag != "*" && sel.tag != n.name {
+			continue
+		}
+		if sel.id != "" && sel.id != n.attrs["id"] {
+			continue
+		}
+		if len(sel.classes) > 0 && classes == nil {
+			classes = strings.Fields(n.attrs["class"])
+		}
+		ok := true
+		for _, c := range sel.classes {
+			if !slices.Contains(classes, c) {
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		s := 10 * len(sel.classes)
+		if sel.id != "" {
+			s += 100
+		}
+		if sel.tag != "" && sel.tag != "*" {
+			s++
+		}
+		best, found = max(best, s), true
+	}
+	return best, found
+}
+
+// parseCSS reads the rules of a style sheet whose selectors name an element
+// by type, id and classes. At-rules and rules with only other selectors,
+// like descendant or pseudo-class ones, are skipped.
+func parseCSS(css string) []cssRule {
+	for {
+		i := strings.Index(css, "/*")
+		if i < 0 {
+			break
+		}
+		j := strings.Index(css[i+2:], "*/")
+		if j < 0 {
+			css = css[:i]
+			break
+		}
+		css = css[:i] + css[i+2+j+2:]
+	}
+
+	var rules []cssRule
+	for {
+		open := strings.IndexByte(css, '{')
+		if open < 0 {
+			return rules
+		}
+		prelude := strings.TrimSpace(css[:open])
+		end := matchingBrace(css, open)
+		body := css[open+1 : end]
+		if end < len(css) {
+			css = css[end+1:]
+		} else {
+			css = ""
+		}
+		if strings.HasPrefix(prelude, "@") {
+			continue
+		}
+		var rule cssRule
+		for _, s := range strings.Split(prelude, ",") {
+			if sel, ok := parseSelector(strings.TrimSpace(s)); ok {
+				rule.selectors = append(rule.selectors, sel)
+			}
+		}
+		if len(rule.selectors) > 0 {
+			rule.decls = parseDeclarations(body)
+			rules = append(rules, rule)
+		}
+	}
+}
+
+// matchingBrace returns the index of the brace closing the one at open, or
+// len(s) when it is missing.
+func matchingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+// parseSelector reads a compound selector of a type, an id and classes.
+func parseSelector(s string) (cssSelector, bool) {
+	if s == "" || strings.ContainsAny(s, " \t\n>+~:[()") {
+		return cssSelector{}, false
+	}
+	var sel cssSelector
+	for len(s) > 0 {
+		end := strings.IndexAny(s[1:], ".#") + 1
+		if end == 0 {
+			end = len(s)
+		}
+		part := s[:end]
+		s = s[end:]
+		switch part[0] {
+		case '.':
+			sel.classes = append(sel.classes, part[1:])
+		case '#':
+			sel.id = part[1:]
+		default:
+			sel.tag = part
+		}
+	}
+	return sel, true
+}
+
+// parseDeclarations reads "name: value; ..." pairs, dropping !important.
+func parseDeclarations(s string) [][2]string {
+	var decls [][2]string
+	for _, d := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(d, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "!important"))
+		decls = append(decls, [2]string{strings.ToLower(strings.TrimSpace(name)), value})
+	}
+	return decls
+}
+
+// parseColor reads a CSS color: a name, #rgb, #rgba, #rrggbb, #rrggbbaa,
+// rgb() or rgba(). currentColor resolves to current.
+func parseColor(s string, current color.NRGBA) (color.NRGBA, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case s == "currentcolor":
+		return current, true
+	case strings.HasPrefix(s, "#"):
+		hex := s[1:]
+		if len(hex) == 3 || len(hex) == 4 {
+			var b strings.Builder
+			for _, c := range hex {
+				b.WriteRune(c)
+				b.WriteRune(c)
+			}
+			hex = b.String()
+		}
+		if len(hex) == 6 {
+			hex += "ff"
+		}
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if len(hex) != 8 || err != nil {
+			return color.NRGBA{}, false
+		}
+		return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, true
+	case strings.HasPrefix(s, "rgb"):
+		open, end := strings.IndexByte(s, '('), strings.IndexByte(s, ')')
+		if open < 0 || end < open {
+			return color.NRGBA{}, false
+		}
+		parts := strings.FieldsFunc(s[open+1:end], func(r rune) bool { return r == ',' || r == ' ' || r == '/' })
+		if len(parts) < 3 {
+			return color.NRGBA{}, false
+		}
+		var ch [3]uint8
+		for i := range ch {
+			v, pct := strings.CutSuffix(parts[i], "%")
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return color.NRGBA{}, false
+			}
+			if pct {
+				f *= 2.55
+			}
+			ch[i] = uint8(math.Round(math.Max(0, math.Min(255, f))))
+		}
+		a := 1.0
+		if len(parts) > 3 {
+			a = parseOpacity(parts[3], 1)
+		}
+		return color.NRGBA{R: ch[0], G: ch[1], B: ch[2], A: uint8(math.Round(a * 255))}, true
+	}
+	if c, ok := colornames.Map[s]; ok {
+		return color.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A}, true
+	}
+	return color.NRGBA{}, false
+}
+
+// parseOpacity reads a number or percentage clamped to [0, 1], or returns
+// fallback.
+func parseOpacity(s string, fallback float64) float64 {
+	s = strings.TrimSpace(s)
+	v, pct := strings.CutSuffix(s, "%")
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fallback
+	}
+	if pct {
+		f /= 100
+	}
+	return math.Max(0, math.Min(1, f))
+}
+
+// parseLength reads a length in user units. Units other than em and
+// absolute ones count as pixels; em is relative to fontSize.
+func parseLength(s string, fontSize float64) (float64, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+	end := len(s)
+	for end > 0 && (s[end-1] >= 'a' && s[end-1] <= 'z' || s[end-1] == '%') {
+		end--
+	}
+	v, err := strconv.ParseFloat(s[:end], 64)
+	if err != nil {
+		return 0, false
+	}
+	switch s[end:] {
+	case "em":
+		v *= fontSize
+	case "pt":
+		v *= 4.0 / 3
+	case "pc":
+		v *= 16
+	case "in":
+		v *= 96
+	case "cm":
+		v *= 96 / 2.54
+	case "mm":
+		v *= 96 / 25.4
+	}
+	return v, true
+}
+
+// parseFontSize reads a font size, with percentages and em relative to the
+// parent's size.
+func parseFontSize(s string, parent float64) (float64, bool) {
+	if v, ok := strings.CutSuffix(strings.TrimSpace(s), "%"); ok {
+		f, err := strconv.ParseFloat(v, 64)
+		return f / 100 * parent, err == nil
+	}
+	v, ok := parseLength(s, parent)
+	return v, ok && v > 0
+}
+
+// parseDashArray reads stroke-dasharray, repeating an odd list as SVG
+// does. It returns nil for none or a pattern of zeros.
+func parseDashArray(s string) []float64 {
+	var dash []float64
+	total := 0.0
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		v, ok := parseLength(f, 0)
+		if !ok || v < 0 {
+			return nil
+		}
+		dash = append(dash, v)
+		total += v
+	}
+	if total == 0 {
+		return nil
+	}
+	if len(dash)%2 == 1 {
+		dash = append(dash, dash...)
+	}
+	return dash
+}
+
+// parseViewBox reads "minX minY width height".
+func parseViewBox(s string) ([4]float64, bool) {
+	nums := parseNumbers(s)
+	if len(nums) != 4 || nums[2] <= 0 || nums[3] <= 0 {
+		return [4]float64{}, false
+	}
+	return [4]float64(nums), true
+}
+
+// viewBoxTransform maps a viewBox onto a viewport of size w×h, honoring
+// preserveAspectRatio's alignment and "none"; "slice" is drawn as "meet".
+func viewBoxTransform(vb [4]float64, w, h float64, aspect string) matrix {
+	sx, sy := w/vb[2], h/vb[3]
+	align := strings.Fields(aspect)
+	if len(align) == 0 {
+		align = []string{"xMidYMid"}
+	}
+	if align[0] == "none" {
+		return scaling(sx, sy).mul(translation(-vb[0], -vb[1]))
+	}
+	s := math.Min(sx, sy)
+	if len(align) > 1 && align[1] == "slice" {
+		s = math.Max(sx, sy)
+	}
+	tx, ty := -vb[0]*s, -vb[1]*s
+	switch {
+	case strings.Contains(align[0], "xMid"):
+		tx += (w - vb[2]*s) / 2
+	case strings.Contains(align[0], "xMax"):
+		tx += w - vb[2]*s
+	}
+	switch {
+	case strings.Contains(align[0], "YMid"):
+		ty += (h - vb[3]*s) / 2
+	case strings.Contains(align[0], "YMax"):
+		ty += h - vb[3]*s
+	}
+	return matrix{s, 0, 0, s, tx, ty}
+}
+
+// parseNumbers reads a list of numbers separated by spaces or commas.
+func parseNumbers(s string) []float64 {
+	var nums []float64
+	sc := numberScanner{s: s}
+	for {
+		v, ok := sc.number()
+		if !ok {
+			return nums
+		}
+		nums = append(nums, v)
+	}
+}
@@ -0,0 +1,672 @@
+package render
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// point is a position in user or device space.
+type point struct{ x, y float64 }
+
+func (p point) add(q point) point      { return point{p.x + q.x, p.y + q.y} }
+func (p point) sub(q point) point      { return point{p.x - q.x, p.y - q.y} }
+func (p point) mul(s float64) point    { return point{p.x * s, p.y * s} }
+func (p point) dist(q point) float64   { return math.Hypot(p.x-q.x, p.y-q.y) }
+func (p point) cross(q point) float64  { return p.x*q.y - p.y*q.x }
+func (p point) normal(w float64) point { return point{-p.y, p.x}.mul(w / math.Hypot(p.x, p.y)) }
+func lerp(p, q point, t float64) point { return point{p.x + (q.x-p.x)*t, p.y + (q.y-p.y)*t} }
+func unit(p point) point               { return p.mul(1 / math.Hypot(p.x, p.y)) }
+
+// matrix is an affine transform [a c e; b d f], as in SVG's matrix().
+type matrix struct{ a, b, c, d, e, f float64 }
+
+func identity() matrix                { return matrix{1, 0, 0, 1, 0, 0} }
+func translation(x, y float64) matrix { return matrix{1, 0, 0, 1, x, y} }
+func scaling(x, y float64) matrix     { return matrix{x, 0, 0, y, 0, 0} }
+
+func rotation(deg float64) matrix {
+	s, c := math.Sincos(deg * math.Pi / 180)
+	return matrix{c, s, -s, c, 0, 0}
+}
+
+// mul returns m applied after n.
+func (m matrix) mul(n matrix) matrix {
+	return matrix{
+		a: m.a*n.a + m.c*n.b,
+		b: m.b*n.a + m.d*n.b,
+		c: m.a*n.c + m.c*n.d,
+		d: m.b*n.c + m.d*n.d,
+		e: m.a*n.e + m.c*n.f + m.e,
+		f: m.b*n.e + m.d*n.f + m.f,
+	}
+}
+
+func (m matrix) apply(p point) point {
+	return point{m.a*p.x + m.c*p.y + m.e, m.b*p.x + m.d*p.y + m.f}
+}
+
+// scale returns the factor by which m scales lengths, on average over
+// directions, for stroke widths and dashes.
+func (m matrix) scale() float64 {
+	return math.Sqrt(math.Abs(m.a*m.d - m.b*m.c))
+}
+
+// parseTransform reads an SVG transform list.
+func parseTransform(s string) matrix {
+	m := identity()
+	for {
+		open := strings.IndexByte(s, '(')
+		end := strings.IndexByte(s, ')')
+		if open < 0 || end < open {
+			return m
+		}
+		name := strings.TrimSpace(strings.Trim(strings.TrimSpace(s[:open]), ","))
+		args := parseNumbers(s[open+1 : end])
+		s = s[end+1:]
+		arg := func(i int, fallback float64) float64 {
+			if i < len(args) {
+				return args[i]
+			}
+			return fallback
+		}
+		switch name {
+		case "matrix":
+			if len(args) == 6 {
+				m = m.mul(matrix{args[0], args[1], args[2], args[3], args[4], args[5]})
+			}
+		case "translate":
+			m = m.mul(translation(arg(0, 0), arg(1, 0)))
+		case "scale":
+			m = m.mul(scaling(arg(0, 1), arg(1, arg(0, 1))))
+		case "rotate":
+			cx, cy := arg(1, 0), arg(2, 0)
+			m = m.mul(translation(cx, cy)).mul(rotation(arg(0, 0))).mul(translation(-cx, -cy))
+		case "skewX":
+			m = m.mul(matrix{1, 0, math.Tan(arg(0, 0) * math.Pi / 180), 1, 0, 0})
+		case "skewY":
+			m = m.mul(matrix{1, math.Tan(arg(0, 0) * math.Pi / 180), 0, 1, 0, 0})
+		}
+	}
+}
+
+// pathOp is a path command in absolute coordinates: 'M' and 'L' use pts[0],
+// 'Q' pts[0:2], 'C' pts[0:3], and 'Z' none.
+type pathOp struct {
+	kind byte
+	pts  [3]point
+}
+
+// path is an outline of lines and Bézier curves in user space.
+type path []pathOp
+
+func (p *path) moveTo(a point)       { *p = append(*p, pathOp{kind: 'M', pts: [3]point{a}}) }
+func (p *path) lineTo(a point)       { *p = append(*p, pathOp{kind: 'L', pts: [3]point{a}}) }
+func (p *path) quadTo(a, b point)    { *p = append(*p, pathOp{kind: 'Q', pts: [3]point{a, b}}) }
+func (p *path) cubeTo(a, b, c point) { *p = append(*p, pathOp{kind: 'C', pts: [3]point{a, b, c}}) }
+func (p *path) close()               { *p = append(*p, pathOp{kind: 'Z'}) }
+
+// arcTo appends an elliptical arc from cur to end as cubic curves,
+// following the endpoint parameterization of SVG's A command.
+func (p *path) arcTo(cur point, rx, ry, rotate float64, large, sweep bool, end point) {
+	if cur == end {
+		return
+	}
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		p.lineTo(end)
+		return
+	}
+	sin, cos := math.Sincos(rotate * math.Pi / 180)
+	dx, dy := (cur.x-end.x)/2, (cur.y-end.y)/2
+	x1 := cos*dx + sin*dy
+	y1 := -sin*dx + cos*dy
+	if l := x1*x1/(rx*rx) + y1*y1/(ry*ry); l > 1 {
+		rx, ry = rx*math.Sqrt(l), ry*math.Sqrt(l)
+	}
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	coef := math.Sqrt(math.Max(0, num/den))
+	if large == sweep {
+		coef = -coef
+	}
+	cx1, cy1 := coef*rx*y1/ry, -coef*ry*x1/rx
+	cx := cos*cx1 - sin*cy1 + (cur.x+end.x)/2
+	cy := sin*cx1 + cos*cy1 + (cur.y+end.y)/2
+
+	angle := func(ux, uy, vx, vy float64) float64 {
+		return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+	}
+	theta := angle(1, 0, (x1-cx1)/rx, (y1-cy1)/ry)
+	delta := angle((x1-cx1)/rx, (y1-cy1)/ry, (-x1-cx1)/rx, (-y1-cy1)/ry)
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+
+	at := func(t float64) (point, point) {
+		st, ct := math.Sincos(t)
+		pos := point{cx + rx*ct*cos - ry*st*sin, cy + rx*ct*sin + ry*st*cos}
+		deriv := point{-rx*st*cos - ry*ct*sin, -rx*st*sin + ry*ct*cos}
+		return pos, deriv
+	}
+	n := int(math.Ceil(math.Abs(delta) / (math.Pi / 2)))
+	step := delta / float64(n)
+	k := 4.0 / 3 * math.Tan(step/4)
+	from, d0 := at(theta)
+	for i := 1; i <= n; i++ {
+		to, d1 := at(theta + step*float64(i))
+		if i == n {
+			to = end
+		}
+		p.cubeTo(from.add(d0.mul(k)), to.sub(d1.mul(k)), to)
+		from, d0 = to, d1
+	}
+}
+
+// rectPath returns a rectangle with corners rounded by rx and ry.
+func rectPath(x, y, w, h, rx, ry float64) path {
+	if w <= 0 || h <= 0 {
+		return nil
+	}
+	rx, ry = math.Min(math.Max(rx, 0), w/2), math.Min(math.Max(ry, 0), h/2)
+	var p path
+	if rx == 0 || ry == 0 {
+		p.moveTo(point{x, y})
+		p.lineTo(point{x + w, y})
+		p.lineTo(point{x + w, y + h})
+		p.lineTo(point{x, y + h})
+		p.close()
+		return p
+	}
+	p.moveTo(point{x + rx, y})
+	p.lineTo(point{x + w - rx, y})
+	p.arcTo(point{x + w - rx, y}, rx, ry, 0, false, true, point{x + w, y + ry})
+	p.lineTo(point{x + w, y + h - ry})
+	p.arcTo(point{x + w, y + h - ry}, rx, ry, 0, false, true, point{x + w - rx, y + h})
+	p.lineTo(point{x + rx, y + h})
+	p.arcTo(point{x + rx, y + h}, rx, ry, 0, false, true, point{x, y + h - ry})
+	p.lineTo(point{x, y + ry})
+	p.arcTo(point{x, y + ry}, rx, ry, 0, false, true, point{x + rx, y})
+	p.close()
+	return p
+}
+
+// ellipsePath returns an ellipse as two arcs.
+func ellipsePath(cx, cy, rx, ry float64) path {
+	if rx <= 0 || ry <= 0 {
+		return nil
+	}
+	var p path
+	left, right := point{cx - rx, cy}, point{cx + rx, cy}
+	p.moveTo(right)
+	p.arcTo(right, rx, ry, 0, false, true, left)
+	p.arcTo(left, rx, ry, 0, false, true, right)
+	p.close()
+	return p
+}
+
+// numberScanner reads the numbers of path data and attribute lists, where
+// separators are optional between numbers like "1-2.5.5".
+type numberScanner struct {
+	s string
+	i int
+}
+
+func (sc *numberScanner) skipSeparators() {
+	for sc.i < len(sc.s) && strings.IndexByte(" \t\r\n,", sc.s[sc.i]) >= 0 {
+		sc.i++
+	}
+}
+
+func (sc *numberScanner) number() (float64, bool) {
+	sc.skipSeparators()
+	start, i := sc.i, sc.i
+	if i < len(sc.s) && (sc.s[i] == '+' || sc.s[i] == '-') {
+		i++
+	}
+	digits, dot := 0, false
+	for ; i < len(sc.s); i++ {
+		c := sc.s[i]
+		if c >= '0' && c <= '9' {
+			digits++
+		} else if c == '.' && !dot {
+			dot = true
+		} else {
+			break
+		}
+	}
+	if digits == 0 {
+		return 0, false
+	}
+	if i < len(sc.s) && (sc.s[i] == 'e' || sc.s[i] == 'E') {
+		j := i + 1
+		if j < len(sc.s) && (sc.s[j] == '+' || sc.s[j] == '-') {
+			j++
+		}
+		if j < len(sc.s) && sc.s[j] >= '0' && sc.s[j] <= '9' {
+			for j < len(sc.s) && sc.s[j] >= '0' && sc.s[j] <= '9' {
+				j++
+			}
+			i = j
+		}
+	}
+	v, err := strconv.ParseFloat(sc.s[start:i], 64)
+	if err != nil {
+		return 0, false
+	}
+	sc.i = i
+	return v, true
+}
+
+// flag reads an arc flag, which may be written without a separator.
+func (sc *numberScanner) flag() (bool, bool) {
+	sc.skipSeparators()
+	if sc.i < len(sc.s) && (sc.s[sc.i] == '0' || sc.s[sc.i] == '1') {
+		sc.i++
+		return sc.s[sc.i-1] == '1', true
+	}
+	return false, false
+}
+
+// parsePathData reads the d attribute of a <path>. Parsing stops at the
+// first error, keeping the commands before it, as SVG requires.
+func parsePathData(d string) path {
+	var p path
+	sc := numberScanner{s: d}
+	var cur, start, ctrl point
+	var prev byte
+	for {
+		sc.skipSeparators()
+		if sc.i >= len(sc.s) {
+			return p
+		}
+		cmd := sc.s[sc.i]
+		if strings.IndexByte("MmLlHhVvCcSsQqTtAaZz", cmd) < 0 {
+			return p
+		}
+		sc.i++
+		rel := cmd >= 'a'
+		upper := cmd &^ 0x20
+		if upper == 'Z' {
+			p.close()
+			cur, prev = start, 'Z'
+			continue
+		}
+
+		for first := true; ; first = false {
+			// Commands repeat while numbers follow; a repeated M is a line.
+			save := sc.i
+			if _, ok := sc.number(); !ok {
+				sc.i = save
+				if first {
+					return p
+				}
+				break
+			}
+			sc.i = save
+
+			pt := func() (point, bool) {
+				x, ok1 := sc.number()
+				y, ok2 := sc.number()
+				q := point{x, y}
+				if rel {
+					q = q.add(cur)
+				}
+				return q, ok1 && ok2
+			}
+			var ok bool
+			switch upper {
+			case 'M':
+				var q point
+				if q, ok = pt(); !ok {
+					return p
+				}
+				if first {
+					p.moveTo(q)
+					start = q
+				} else {
+					p.lineTo(q)
+				}
+				cur, ctrl = q, q
+			case 'L':
+				var q point
+				if q, ok = pt(); !ok {
+					return p
+				}
+				p.lineTo(q)
+				cur, ctrl = q, q
+			case 'H', 'V':
+				v, ok := sc.number()
+				if !ok {
+					return p
+				}
+				q := cur
+				switch {
+				case upper == 'H' && rel:
+					q.x += v
+				case upper == 'H':
+					q.x = v
+				case rel:
+					q.y += v
+				default:
+					q.y = v
+				}
+				p.lineTo(q)
+				cur, ctrl = q, q
+			case 'C':
+				c1, ok1 := pt()
+				c2, ok2 := pt()
+				q, ok3 := pt()
+				if !ok1 || !ok2 || !ok3 {
+					return p
+				}
+				p.cubeTo(c1, c2, q)
+				cur, ctrl = q, c2
+			case 'S':
+				c1 := cur
+				if prev == 'C' || prev == 'S' {
+					c1 = cur.mul(2).sub(ctrl)
+				}
+				c2, ok1 := pt()
+				q, ok2 := pt()
+				if !ok1 || !ok2 {
+					return p
+				}
+				p.cubeTo(c1, c2, q)
+				cur, ctrl = q, c2
+			case 'Q':
+				c, ok1 := pt()
+				q, ok2 := pt()
+				if !ok1 || !ok2 {
+					return p
+				}
+				p.quadTo(c, q)
+				cur, ctrl = q, c
+			case 'T':
+				c := cur
+				if prev == 'Q' || prev == 'T' {
+					c = cur.mul(2).sub(ctrl)
+				}
+				q, ok := pt()
+				if !ok {
+					return p
+				}
+				p.quadTo(c, q)
+				cur, ctrl = q, c
+			case 'A':
+				rx, ok1 := sc.number()
+				ry, ok2 := sc.number()
+				rot, ok3 := sc.number()
+				large, ok4 := sc.flag()
+				sweep, ok5 := sc.flag()
+				q, ok6 := pt()
+				if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 || !ok6 {
+					return p
+				}
+				p.arcTo(cur, rx, ry, rot, large, sweep, q)
+				cur, ctrl = q, q
+			}
+			prev = upper
+		}
+	}
+}
+
+// polyline is a flattened subpath in device space.
+type polyline struct {
+	pts    []point
+	closed bool
+}
+
+// flatten transforms a path to device space and approximates its curves
+// with line segments about a pixel long.
+func (p path) flatten(m matrix) []polyline {
+	var out []polyline
+	var cur *polyline
+	var pos point // current point in device space
+	ensure := func() {
+		if cur == nil {
+			out = append(out, polyline{pts: []point{pos}})
+			cur = &out[len(out)-1]
+		}
+	}
+	for _, op := range p {
+		switch op.kind {
+		case 'M':
+			pos = m.apply(op.pts[0])
+			out = append(out, polyline{pts: []point{pos}})
+			cur = &out[len(out)-1]
+		case 'L':
+			ensure()
+			pos = m.apply(op.pts[0])
+			cur.pts = append(cur.pts, pos)
+		case 'Q', 'C':
+			ensure()
+			c := [4]point{pos, m.apply(op.pts[0]), m.apply(op.pts[1]), m.apply(op.pts[2])}
+			if op.kind == 'Q' {
+				q := c[2]
+				c[2] = lerp(q, c[1], 2.0/3)
+				c[1] = lerp(pos, c[1], 2.0/3)
+				c[3] = q
+			}
+			length := c[0].dist(c[1]) + c[1].dist(c[2]) + c[2].dist(c[3])
+			n := max(1, min(256, int(math.Ceil(length/2))))
+			for i := 1; i <= n; i++ {
+				t := float64(i) / float64(n)
+				a, b, d := lerp(c[0], c[1], t), lerp(c[1], c[2], t), lerp(c[2], c[3], t)
+				cur.pts = append(cur.pts, lerp(lerp(a, b, t), lerp(b, d, t), t))
+			}
+			pos = c[3]
+		case 'Z':
+			if cur != nil {
+				cur.closed = true
+				pos = cur.pts[0]
+				cur = nil
+			}
+		}
+	}
+	return out
+}
+
+// stroker holds the device-space parameters of a stroke.
+type stroker struct {
+	width      float64
+	cap, join  string
+	miterLimit float64
+	dash       []float64
+	dashOffset float64
+}
+
+// strokePolylines returns polygons whose union is the stroke of lines:
+// a quadrilateral per segment plus joins and caps, all wound the same way
+// so that overlaps do not cancel out when filled.
+func strokePolylines(lines []polyline, s stroker) []polyline {
+	var out []polyline
+	hw := s.width / 2
+	add := func(pts ...point) {
+		area := 0.0
+		for i := range pts {
+			area += pts[i].cross(pts[(i+1)%len(pts)])
+		}
+		if math.Abs(area) < 1e-12 {
+			return
+		}
+		if area < 0 {
+			for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+				pts[i], pts[j] = pts[j], pts[i]
+			}
+		}
+		out = append(out, polyline{pts: pts, closed: true})
+	}
+	disc := func(c point) {
+		n := max(8, min(64, int(math.Ceil(hw*2))))
+		pts := make([]point, n)
+		for i := range pts {
+			sin, cos := math.Sincos(2 * math.Pi * float64(i) / float64(n))
+			pts[i] = point{c.x + hw*cos, c.y + hw*sin}
+		}
+		add(pts...)
+	}
+
+	for _, line := range dashLines(lines, s.dash, s.dashOffset) {
+		pts := dedupe(line.pts)
+		if len(pts) == 1 {
+			if s.cap == "round" {
+				disc(pts[0])
+			} else if s.cap == "square" {
+				add(pts[0].add(point{-hw, -hw}), pts[0].add(point{hw, -hw}), pts[0].add(point{hw, hw}), pts[0].add(point{-hw, hw}))
+			}
+			continue
+		}
+		closed := line.closed && len(pts) > 2
+		if closed && pts[0] == pts[len(pts)-1] {
+			pts = pts[:len(pts)-1]
+		}
+		segments := len(pts) - 1
+		if closed {
+			segments = len(pts)
+		}
+		for i := 0; i < segments; i++ {
+			a, b := pts[i], pts[(i+1)%len(pts)]
+			n := b.sub(a).normal(hw)
+			if !closed && s.cap == "square" {
+				along := unit(b.sub(a)).mul(hw)
+				if i == 0 {
+					a = a.sub(along)
+				}
+				if i == segments-1 {
+					b = b.add(along)
+				}
+			}
+			add(a.add(n), b.add(n), b.sub(n), a.sub(n))
+		}
+
+		// Joins at interior vertices, and at every vertex of a closed line.
+		for i := range pts {
+			if !closed && (i == 0 || i == len(pts)-1) {
+				continue
+			}
+			prev, next := pts[(i-1+len(pts))%len(pts)], pts[(i+1)%len(pts)]
+			join(pts[i], prev, next, hw, s, add, disc)
+		}
+		if !closed && s.cap == "round" {
+			disc(pts[0])
+			disc(pts[len(pts)-1])
+		}
+	}
+	return out
+}
+
+// join adds the join at vertex v between the segments from prev and to
+// next.
+func join(v, prev, next point, hw float64, s stroker, add func(...point), disc func(point)) {
+	d0, d1 := unit(v.sub(prev)), unit(next.sub(v))
+	turn := d0.cross(d1)
+	if math.Abs(turn) < 1e-9 && d0.x*d1.x+d0.y*d1.y > 0 {
+		return // straight on
+	}
+	if s.join == "round" {
+		disc(v)
+		return
+	}
+	// The outer side of the turn is where the segments' offsets diverge.
+	side := -1.0
+	if turn < 0 {
+		side = 1
+	}
+	n0 := point{-d0.y, d0.x}.mul(hw * side)
+	n1 := point{-d1.y, d1.x}.mul(hw * side)
+	a, b := v.add(n0), v.add(n1)
+	if s.join != "bevel" {
+		// The miter length over the stroke width is 1/sin(θ/2), θ being
+		// the angle between the segments, which is 1/cos(φ/2) for the
+		// turn φ between their directions.
+		halfTurnCos := math.Sqrt(math.Max(1e-12, (1+d0.x*d1.x+d0.y*d1.y)/2))
+		if 1/halfTurnCos <= s.miterLimit {
+			tip := v.add(unit(n0.add(n1)).mul(hw / halfTurnCos))
+			add(v, a, tip, b)
+			return
+		}
+	}
+	add(v, a, b)
+}
+
+// dedupe drops consecutive duplicate points.
+func dedupe(pts []point) []point {
+	out := pts[:0:0]
+	for i, p := range pts {
+		if i == 0 || p.dist(out[len(out)-1]) > 1e-9 {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// dashLines splits lines into the dashes of a pattern of alternating dash
+// and gap lengths. A nil pattern returns lines unchanged.
+func dashLines(lines []polyline, dash []float64, offset float64) []polyline {
+	if len(dash) == 0 {
+		return lines
+	}
+	total := 0.0
+	for _, d := range dash {
+		total += d
+	}
+	if total <= 0 {
+		return lines
+	}
+
+	var out []polyline
+	for _, line := range lines {
+		pts := line.pts
+		if line.closed && len(pts) > 1 {
+			pts = append(pts[:len(pts):len(pts)], pts[0])
+		}
+		// Find where in the pattern the line starts.
+		i, left := 0, dash[0]
+		for pos := math.Mod(math.Mod(offset, total)+total, total); pos > 0; {
+			if pos < left {
+				left -= pos
+				break
+			}
+			pos -= left
+			i = (i + 1) % len(dash)
+			left = dash[i]
+		}
+
+		var current []point
+		if i%2 == 0 && len(pts) > 0 {
+			current = []point{pts[0]}
+		}
+		for k := 1; k < len(pts); k++ {
+			a, b := pts[k-1], pts[k]
+			seg := a.dist(b)
+			for seg > 0 {
+				step := math.Min(seg, left)
+				q := lerp(a, b, step/seg)
+				if i%2 == 0 {
+					current = append(current, q)
+				}
+				seg -= step
+				left -= step
+				a = q
+				if left <= 1e-9 {
+					if i%2 == 0 && len(current) > 0 {
+						out = append(out, polyline{pts: current})
+					}
+					current = nil
+					i = (i + 1) % len(dash)
+					left = dash[i]
+					if i%2 == 0 {
+						current = []point{a}
+					}
+				}
+			}
+		}
+		if len(current) > 1 {
+			out = append(out, polyline{pts: current})
+		}
+	}
+	return out
+}
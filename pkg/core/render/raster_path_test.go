@@ -0,0 +1,102 @@
+package render
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParsePathData(t *testing.T) {
+	tests := []struct {
+		d     string
+		kinds string
+		end   point
+	}{
+		{"M10,10 L20,10 20,20 Z", "MLLZ", point{}},
+		{"m10 10 h10 v10 h-10z", "MLLLZ", point{}},
+		{"M0,0C1,1 2,2 3,3 4,4 5,5 6,6", "MCC", point{6, 6}},
+		{"M0 0Q5 5 10 0T20 0", "MQQ", point{20, 0}},
+		{"M0 0S5 5 10 0", "MC", point{10, 0}},
+		{"M0,0A5,5 0 0,1 10,0", "MCC", point{10, 0}},
+		{"M0,0a5 5 0 1110 0", "MCC", point{10, 0}},
+		{"M1e1-5L.5.5", "ML", point{.5, .5}},
+		{"M0 0 L10", "M", point{}},
+	}
+	for _, tt := range tests {
+		p := parsePathData(tt.d)
+		kinds := ""
+		for _, op := range p {
+			kinds += string(op.kind)
+		}
+		if kinds != tt.kinds {
+			t.Errorf("parsePathData(%q) ops = %q, want %q", tt.d, kinds, tt.kinds)
+			continue
+		}
+		last := p[len(p)-1]
+		if last.kind == 'Z' {
+			continue
+		}
+		end := last.pts[0]
+		switch last.kind {
+		case 'Q':
+			end = last.pts[1]
+		case 'C':
+			end = last.pts[2]
+		}
+		if end.dist(tt.end) > 1e-9 {
+			t.Errorf("parsePathData(%q) ends at %v, want %v", tt.d, end, tt.end)
+		}
+	}
+}
+
+func TestParseTransform(t *testing.T) {
+	tests := []struct {
+		s    string
+		in   point
+		want point
+	}{
+		{"translate(10 20)", point{1, 1}, point{11, 21}},
+		{"scale(2)", point{1, 3}, point{2, 6}},
+		{"rotate(90)", point{1, 0}, point{0, 1}},
+		{"rotate(180, 5, 5)", point{0, 0}, point{10, 10}},
+		{"translate(10,0) scale(2,3)", point{1, 1}, point{12, 3}},
+		{"matrix(1 0 0 1 4 5)", point{0, 0}, point{4, 5}},
+		{"skewX(45)", point{0, 1}, point{1, 1}},
+		{"bogus(1)", point{1, 1}, point{1, 1}},
+	}
+	for _, tt := range tests {
+		if got := parseTransform(tt.s).apply(tt.in); got.dist(tt.want) > 1e-9 {
+			t.Errorf("parseTransform(%q) maps %v to %v, want %v", tt.s, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDashLines(t *testing.T) {
+	line := []polyline{{pts: []point{{0, 0}, {10, 0}}}}
+	dashes := dashLines(line, []float64{2, 3}, 0)
+	if len(dashes) != 2 {
+		t.Fatalf("got %d dashes, want 2: %v", len(dashes), dashes)
+	}
+	for i, want := range [][2]float64{{0, 2}, {5, 7}} {
+		d := dashes[i].pts
+		if math.Abs(d[0].x-want[0]) > 1e-9 || math.Abs(d[len(d)-1].x-want[1]) > 1e-9 {
+			t.Errorf("dash %d = %v, want x from %g to %g", i, d, want[0], want[1])
+		}
+	}
+}
+
+func TestFlatten_Scaled(t *testing.T) {
+	p := ellipsePath(0, 0, 10, 10)
+	small := p.flatten(identity())
+	large := p.flatten(scaling(10, 10))
+	if len(small) != 1 || len(large) != 1 {
+		t.Fatalf("ellipse flattened to %d and %d polylines, want 1", len(small), len(large))
+	}
+	if len(large[0].pts) <= len(small[0].pts) {
+		t.Errorf("scaled ellipse has %d points, unscaled %d; want more when larger", len(large[0].pts), len(small[0].pts))
+	}
+	for _, q := range large[0].pts {
+		if r := math.Hypot(q.x, q.y); math.Abs(r-100) > 0.5 {
+			t.Fatalf("point %v is %g from the center, want 100", q, r)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/png"
+	"math"
+	"strings"
+	"testing"
+)
+
+func rasterize(t *testing.T, svg string, scale float64) func(x, y int) color.RGBA {
+	t.Helper()
+	img, err := Rasterize([]byte(svg), scale)
+	if err != nil {
+		t.Fatalf("Rasterize: %v", err)
+	}
+	return func(x, y int) color.RGBA { return img.RGBAAt(x, y) }
+}
+
+func near(a, b color.RGBA) bool {
+	d := func(x, y uint8) bool { return math.Abs(float64(x)-float64(y)) <= 8 }
+	return d(a.R, b.R) && d(a.G, b.G) && d(a.B, b.B) && d(a.A, b.A)
+}
+
+var (
+	white       = color.RGBA{255, 255, 255, 255}
+	red         = color.RGBA{255, 0, 0, 255}
+	blue        = color.RGBA{0, 0, 255, 255}
+	transparent = color.RGBA{}
+)
+
+func TestRasterize_Shapes(t *testing.T) {
+	at := rasterize(t, `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="50">
+		<rect width="100%" height="100%" fill="white"/>
+		<rect x="10" y="10" width="30" height="30" fill="red" stroke="#00f" stroke-width="4"/>
+		<circle cx="75" cy="25" r="10" style="fill: rgb(0, 0, 255)"/>
+		<g opacity="0.5"><rect x="50" y="40" width="10" height="10" fill="red"/></g>
+		<rect x="90" y="0" width="10" height="10" display="none" fill="red"/>
+	</svg>`, 1)
+
+	for _, tt := range []struct {
+		name string
+		x, y int
+		want color.RGBA
+	}{
+		{"fill", 25, 25, red},
+		{"stroke", 10, 25, blue},
+		{"circle", 75, 25, blue},
+		{"background", 2, 2, white},
+		{"group opacity", 55, 45, color.RGBA{255, 128, 128, 255}},
+		{"display none", 95, 5, white},
+	} {
+		if got := at(tt.x, tt.y); !near(got, tt.want) {
+			t.Errorf("%s: pixel (%d,%d) = %v, want %v", tt.name, tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestRasterize_ViewBoxAndScale(t *testing.T) {
+	at := rasterize(t, `<svg viewBox="0 0 10 10" width="20" height="20">
+		<rect x="5" y="0" width="5" height="5" fill="red" transform="translate(-5 5)"/>
+	</svg>`, 2)
+
+	if got := at(10, 30); !near(got, red) {
+		t.Errorf("translated rect: pixel = %v, want red", got)
+	}
+	if got := at(30, 10); !near(got, transparent) {
+		t.Errorf("outside: pixel = %v, want transparent", got)
+	}
+}
+
+func TestRasterize_CSS(t *testing.T) {
+	at := rasterize(t, `<svg width="40" height="20">
+		<style>
+			.block { fill: red; }
+			#special { fill: blue; }
+			@media print { rect { fill: white; } }
+		</style>
+		<rect class="block" width="20" height="20"/>
+		<rect class="block" id="special" x="20" width="20" height="20"/>
+	</svg>`, 1)
+
+	if got := at(10, 10); !near(got, red) {
+		t.Errorf("class rule: pixel = %v, want red", got)
+	}
+	if got := at(30, 10); !near(got, blue) {
+		t.Errorf("id rule: pixel = %v, want blue", got)
+	}
+}
+
+func TestRasterize_Text(t *testing.T) {
+	at := rasterize(t, `<svg width="200" height="40">
+		<text x="100" y="20" font-size="24" text-anchor="middle" dominant-baseline="middle">flask</text>
+	</svg>`, 1)
+
+	var left, right, inked int
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 200; x++ {
+			if at(x, y).A > 128 {
+				inked++
+				if x < 100 {
+					left++
+				} else {
+					right++
+				}
+			}
+		}
+	}
+	if inked == 0 {
+		t.Fatal("no text drawn")
+	}
+	if left == 0 || right == 0 {
+		t.Errorf("text not centered on its anchor: %d pixels left, %d right", left, right)
+	}
+}
+
+func TestRasterize_Errors(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		svg   string
+		scale float64
+	}{
+		{"no root", `<html></html>`, 1},
+		{"no size", `<svg></svg>`, 1},
+		{"bad scale", `<svg width="10" height="10"/>`, 0},
+		{"too large", `<svg width="100000" height="100000"/>`, 1},
+		{"too large for int", `<svg width="4294967296" height="4294967296"/>`, 1},
+		{"malformed", `<svg width="10" height="10"><rect`, 1},
+	} {
+		if _, err := Rasterize([]byte(tt.svg), tt.scale); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestRasterize_Use(t *testing.T) {
+	at := rasterize(t, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="40" height="20">
+		<defs><rect id="box" width="10" height="10" fill="red"/></defs>
+		<use href="#box"/>
+		<use xlink:href="#box" x="20"/>
+	</svg>`, 1)
+	if !near(at(5, 5), red) || !near(at(25, 5), red) || !near(at(15, 5), transparent) {
+		t.Errorf("pixels = %v, %v, %v; want the box drawn twice", at(5, 5), at(25, 5), at(15, 5))
+	}
+
+	// Each level draws the previous one ten times: 10^10 draws in all
+	var svg strings.Builder
+	svg.WriteString(`<svg width="10" height="10"><defs><rect id="l0" width="1" height="1"/>`)
+	for level := 1; level <= 10; level++ {
+		fmt.Fprintf(&svg, `<g id="l%d">`, level)
+		for range 10 {
+			fmt.Fprintf(&svg, `<use href="#l%d"/>`, level-1)
+		}
+		svg.WriteString(`</g>`)
+	}
+	svg.WriteString(`</defs><use href="#l10"/></svg>`)
+	if _, err := Rasterize([]byte(svg.String()), 1); err == nil {
+		t.Error("expected an error for a document fanning out <use> references")
+	}
+}
+
+func TestRasterizeContext_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := RasterizeContext(ctx, []byte(`<svg width="10" height="10"><rect width="10" height="10"/></svg>`), 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestToPNG_Rasterized(t *testing.T) {
+	t.Setenv("PATH", "")
+	data, err := ToPNG([]byte(`<svg width="30" height="20"><rect width="30" height="20" fill="red"/></svg>`), 2)
+	if err != nil {
+		t.Fatalf("ToPNG: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 60 || b.Dy() != 40 {
+		t.Errorf("size = %dx%d, want 60x40", b.Dx(), b.Dy())
+	}
+}
@@ -0,0 +1,292 @@
+package render
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// goFonts are the faces text is set in, parsed on first use and keyed by
+// family ("sans" or "mono") and style.
+var goFonts = sync.OnceValue(func() map[string]*sfnt.Font {
+	fonts := make(map[string]*sfnt.Font)
+	for name, ttf := range map[string][]byte{
+		"sans":             goregular.TTF,
+		"sans-bold":        gobold.TTF,
+		"sans-italic":      goitalic.TTF,
+		"sans-bold-italic": gobolditalic.TTF,
+		"mono":             gomono.TTF,
+		"mono-bold":        gomonobold.TTF,
+	} {
+		f, err := sfnt.Parse(ttf)
+		if err != nil {
+			panic("render: parse Go font: " + err.Error())
+		}
+		fonts[name] = f
+	}
+	return fonts
+})
+
+// fontFor picks the Go font closest to a style: monospace for families
+// naming one, bold from weight 600, and italic or oblique.
+func fontFor(st svgStyle) *sfnt.Font {
+	family := strings.ToLower(st.fontFamily)
+	name := "sans"
+	if strings.Contains(family, "mono") || strings.Contains(family, "courier") || strings.Contains(family, "consol") {
+		name = "mono"
+	}
+	bold := st.fontWeight == "bold" || st.fontWeight == "bolder"
+	if w, err := strconv.Atoi(st.fontWeight); err == nil {
+		bold = w >= 600
+	}
+	if bold {
+		name += "-bold"
+	}
+	if (st.fontStyle == "italic" || st.fontStyle == "oblique") && name != "mono" && name != "mono-bold" {
+		name += "-italic"
+	}
+	return goFonts()[name]
+}
+
+// textRun is text set in one style, starting at an absolute position when
+// positioned.
+type textRun struct {
+	text       string
+	st         svgStyle
+	x, y       float64
+	positioned bool
+}
+
+// drawText draws a <text> element and its <tspan> children. Runs that set
+// their own x or y begin a new chunk, which is anchored on its own.
+func (r *rasterizer) drawText(n *svgNode, m matrix, st svgStyle) {
+	x, _ := parseLength(n.attrs["x"], st.fontSize)
+	y, _ := parseLength(n.attrs["y"], st.fontSize)
+	var runs []textRun
+	r.collectText(n, st, &runs)
+	if len(runs) == 0 {
+		return
+	}
+	for i := 1; i < len(runs); i++ {
+		if strings.HasSuffix(runs[i-1].text, " ") {
+			runs[i].text = strings.TrimPrefix(runs[i].text, " ")
+		}
+	}
+	runs[0].text = strings.TrimLeft(runs[0].text, " ")
+	runs[len(runs)-1].text = strings.TrimRight(runs[len(runs)-1].text, " ")
+	if !runs[0].positioned {
+		runs[0].x, runs[0].y, runs[0].positioned = x, y, true
+	}
+
+	var buf sfnt.Buffer
+	for start := 0; start < len(runs); {
+		end := start + 1
+		for end < len(runs) && !runs[end].positioned {
+			end++
+		}
+		chunk := runs[start:end]
+		pen := point{chunk[0].x, chunk[0].y}
+
+		width := 0.0
+		for _, run := range chunk {
+			width += advance(&buf, run)
+		}
+		switch chunk[0].st.textAnchor {
+		case "middle":
+			pen.x -= width / 2
+		case "end":
+			pen.x -= width
+		}
+		for _, run := range chunk {
+			pen.x += r.drawRun(&buf, run, pen, m)
+		}
+		start = end
+	}
+}
+
+// collectText gathers the runs of a text element in document order.
+func (r *rasterizer) collectText(n *svgNode, st svgStyle, runs *[]textRun) {
+	for _, c := range n.children {
+		switch c.name {
+		case "":
+			if text := collapseSpace(c.text); text != "" {
+				*runs = append(*runs, textRun{text: text, st: st})
+			}
+		case "tspan", "a":
+			decls := r.declarations(c)
+			if decls["display"] == "none" {
+				continue
+			}
+			child := st.inherit()
+			child.baseline = st.baseline
+			child.apply(decls, r)
+			if !child.visible {
+				continue
+			}
+			before := len(*runs)
+			r.collectText(c, child, runs)
+			if len(*runs) > before {
+				x, xok := parseLength(c.attrs["x"], child.fontSize)
+				y, yok := parseLength(c.attrs["y"], child.fontSize)
+				if xok || yok {
+					first := &(*runs)[before]
+					first.positioned = true
+					first.x, first.y = x, y
+					if !xok || !yok {
+						first.x, first.y = penAt(*runs, before, x, y, xok, yok)
+					}
+				}
+			}
+		}
+	}
+}
+
+// penAt fills in the coordinate a tspan leaves unset from the previous
+// positioned run.
+func penAt(runs []textRun, i int, x, y float64, xok, yok bool) (float64, float64) {
+	for j := i - 1; j >= 0; j-- {
+		if runs[j].positioned {
+			if !xok {
+				x = runs[j].x
+			}
+			if !yok {
+				y = runs[j].y
+			}
+			break
+		}
+	}
+	return x, y
+}
+
+// collapseSpace turns runs of whitespace into single spaces, as SVG does
+// for text without xml:space="preserve".
+func collapseSpace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, c := range s {
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(c)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// advance returns the width of a run in user units.
+func advance(buf *sfnt.Buffer, run textRun) float64 {
+	f := fontFor(run.st)
+	ppem := fixed.Int26_6(run.st.fontSize * 64)
+	total := fixed.Int26_6(0)
+	prev := sfnt.GlyphIndex(0)
+	for _, ch := range run.text {
+		idx, err := f.GlyphIndex(buf, ch)
+		if err != nil {
+			continue
+		}
+		if prev != 0 {
+			if k, err := f.Kern(buf, prev, idx, ppem, font.HintingNone); err == nil {
+				total += k
+			}
+		}
+		if a, err := f.GlyphAdvance(buf, idx, ppem, font.HintingNone); err == nil {
+			total += a
+		}
+		prev = idx
+	}
+	return float64(total) / 64
+}
+
+// drawRun draws a run with its baseline starting at pen and returns its
+// width. Glyph outlines go through the same transform and fill as paths,
+// so rotated and scaled text is drawn exactly.
+func (r *rasterizer) drawRun(buf *sfnt.Buffer, run textRun, pen point, m matrix) float64 {
+	f := fontFor(run.st)
+	ppem := fixed.Int26_6(run.st.fontSize * 64)
+	if metrics, err := f.Metrics(buf, ppem, font.HintingNone); err == nil {
+		pen.y += baselineShift(run.st.baseline, metrics)
+	}
+
+	var p path
+	start := pen.x
+	prev := sfnt.GlyphIndex(0)
+	for _, ch := range run.text {
+		idx, err := f.GlyphIndex(buf, ch)
+		if err != nil {
+			continue
+		}
+		if prev != 0 {
+			if k, err := f.Kern(buf, prev, idx, ppem, font.HintingNone); err == nil {
+				pen.x += float64(k) / 64
+			}
+		}
+		segments, err := f.LoadGlyph(buf, idx, ppem, nil)
+		if err == nil {
+			at := func(q fixed.Point26_6) point {
+				return point{pen.x + float64(q.X)/64, pen.y + float64(q.Y)/64}
+			}
+			for _, s := range segments {
+				switch s.Op {
+				case sfnt.SegmentOpMoveTo:
+					if len(p) > 0 {
+						p.close()
+					}
+					p.moveTo(at(s.Args[0]))
+				case sfnt.SegmentOpLineTo:
+					p.lineTo(at(s.Args[0]))
+				case sfnt.SegmentOpQuadTo:
+					p.quadTo(at(s.Args[0]), at(s.Args[1]))
+				case sfnt.SegmentOpCubeTo:
+					p.cubeTo(at(s.Args[0]), at(s.Args[1]), at(s.Args[2]))
+				}
+			}
+			if len(p) > 0 {
+				p.close()
+			}
+		}
+		if a, err := f.GlyphAdvance(buf, idx, ppem, font.HintingNone); err == nil {
+			pen.x += float64(a) / 64
+		}
+		prev = idx
+	}
+	if len(p) > 0 {
+		r.paint(p, m, run.st)
+	}
+	return pen.x - start
+}
+
+// baselineShift returns how far down the alphabetic baseline lies from
+// the y of text aligned by dominant-baseline.
+func baselineShift(baseline string, m font.Metrics) float64 {
+	ascent, descent := float64(m.Ascent)/64, float64(m.Descent)/64
+	switch baseline {
+	case "middle":
+		return float64(m.XHeight) / 64 / 2
+	case "central":
+		return (ascent - descent) / 2
+	case "hanging":
+		return ascent * 0.8
+	case "text-before-edge", "text-top", "before-edge":
+		return ascent
+	case "text-after-edge", "text-bottom", "after-edge", "ideographic":
+		return -descent
+	}
+	return 0
+}
@@ -0,0 +1,32 @@
+// Package rendertest supports golden-file tests of tower renderings, so
+// code building custom styles, sinks or transforms can catch unintended
+// visual changes.
+//
+// [Render] lays out a graph the same way on every run and renders it to
+// SVG; [AssertGolden] compares the result against a checked-in SVG:
+//
+//	func TestBrandStyle(t *testing.T) {
+//		svg := rendertest.Render(t, graph, rendertest.WithStyle(brand.Style{}))
+//		rendertest.AssertGolden(t, "testdata/brand.svg", svg)
+//	}
+//
+// Numbers in the SVG are compared with a tolerance ([DefaultTolerance],
+// or [WithTolerance]), so float formatting and sub-pixel layout changes do
+// not fail a test while any change to markup, text or colours does.
+//
+// # Updating Golden Files
+//
+// Run the tests with the [UpdateEnv] environment variable set to rewrite
+// the golden files from the current output, then review them with git diff
+// before committing:
+//
+//	STACKTOWER_UPDATE_GOLDEN=1 go test ./...
+//
+// # Determinism
+//
+// Render normalizes a copy of the graph and orders rows with the
+// barycentric heuristic, which unlike the default optimal search does not
+// depend on a time budget. Avoid inputs that depend on the clock, such as
+// colouring by staleness or freshness, or the output will drift as the
+// golden file ages.
+package rendertest
@@ -0,0 +1,126 @@
+package rendertest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// UpdateEnv is the environment variable that makes [AssertGolden] rewrite
+// golden files instead of comparing against them.
+const UpdateEnv = "STACKTOWER_UPDATE_GOLDEN"
+
+// DefaultTolerance is the largest difference between two numbers in the
+// SVG that still counts as equal.
+const DefaultTolerance = 0.01
+
+// CompareOption configures [AssertGolden].
+type CompareOption func(*compareConfig)
+
+type compareConfig struct {
+	tolerance float64
+}
+
+// WithTolerance sets the numeric tolerance. Zero requires exact numbers.
+func WithTolerance(tol float64) CompareOption {
+	return func(c *compareConfig) { c.tolerance = tol }
+}
+
+// AssertGolden compares got against the golden file at path and fails the
+// test at the first difference. With [UpdateEnv] set, it writes got to
+// path instead, creating directories as needed.
+func AssertGolden(tb testing.TB, path string, got []byte, opts ...CompareOption) {
+	tb.Helper()
+	cfg := compareConfig{tolerance: DefaultTolerance}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			tb.Fatalf("rendertest: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			tb.Fatalf("rendertest: %v", err)
+		}
+		tb.Logf("rendertest: updated %s", path)
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		tb.Fatalf("rendertest: golden file %s does not exist; run with %s=1 to create it", path, UpdateEnv)
+	}
+	if err != nil {
+		tb.Fatalf("rendertest: %v", err)
+	}
+	if err := Compare(want, got, cfg.tolerance); err != nil {
+		tb.Errorf("rendertest: %s: %v\nrun with %s=1 to accept the new output", path, err, UpdateEnv)
+	}
+}
+
+// Mismatch describes the first difference found by [Compare].
+type Mismatch struct {
+	Line      int    // 1-based line in the golden output
+	Want, Got string // the differing lines
+}
+
+func (m *Mismatch) Error() string {
+	return fmt.Sprintf("line %d differs\n  want: %s\n   got: %s", m.Line, m.Want, m.Got)
+}
+
+// number matches the numbers Compare applies the tolerance to.
+var number = regexp.MustCompile(`-?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?`)
+
+// Compare reports whether two SVGs are equal line by line, treating numbers
+// that differ by at most tol as equal. It returns nil or a [*Mismatch].
+func Compare(want, got []byte, tol float64) error {
+	wantLines := bytes.Split(want, []byte("\n"))
+	gotLines := bytes.Split(got, []byte("\n"))
+	for i := range max(len(wantLines), len(gotLines)) {
+		var w, g []byte
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if !linesEqual(w, g, tol) {
+			return &Mismatch{Line: i + 1, Want: string(w), Got: string(g)}
+		}
+	}
+	return nil
+}
+
+// linesEqual compares the text between numbers exactly and the numbers
+// within tol.
+func linesEqual(want, got []byte, tol float64) bool {
+	if bytes.Equal(want, got) {
+		return true
+	}
+	wantNums := number.FindAllIndex(want, -1)
+	gotNums := number.FindAllIndex(got, -1)
+	if len(wantNums) != len(gotNums) {
+		return false
+	}
+	wantEnd, gotEnd := 0, 0
+	for i := range wantNums {
+		w, g := wantNums[i], gotNums[i]
+		if !bytes.Equal(want[wantEnd:w[0]], got[gotEnd:g[0]]) {
+			return false
+		}
+		wv, err1 := strconv.ParseFloat(string(want[w[0]:w[1]]), 64)
+		gv, err2 := strconv.ParseFloat(string(got[g[0]:g[1]]), 64)
+		if err1 != nil || err2 != nil || math.Abs(wv-gv) > tol {
+			return false
+		}
+		wantEnd, gotEnd = w[1], g[1]
+	}
+	return bytes.Equal(want[wantEnd:], got[gotEnd:])
+}
@@ -0,0 +1,85 @@
+package rendertest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name      string
+		want, got string
+		tol       float64
+		wantLine  int // 0 for equal
+	}{
+		{"identical", `<rect x="1.5"/>`, `<rect x="1.5"/>`, 0, 0},
+		{"within tolerance", `<rect x="1.50" y="2"/>`, `<rect x="1.504" y="2.0"/>`, 0.01, 0},
+		{"beyond tolerance", `<rect x="1.50"/>`, `<rect x="1.52"/>`, 0.01, 1},
+		{"exact numbers", `<rect x="1.50"/>`, `<rect x="1.5"/>`, 0, 0},
+		{"text differs", `<text>react</text>`, `<text>preact</text>`, 0.01, 1},
+		{"colour differs", `fill="#fca5a5"`, `fill="#fca5a6"`, 0.01, 1},
+		{"number count differs", `points="1,2 3,4"`, `points="1,2"`, 0.01, 1},
+		{"extra line", "<svg>\n</svg>", "<svg>\n<g/>\n</svg>", 0.01, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Compare([]byte(tt.want), []byte(tt.got), tt.tol)
+			if tt.wantLine == 0 {
+				if err != nil {
+					t.Fatalf("Compare() = %v, want nil", err)
+				}
+				return
+			}
+			var m *Mismatch
+			if !errors.As(err, &m) {
+				t.Fatalf("Compare() = %v, want a *Mismatch", err)
+			}
+			if m.Line != tt.wantLine {
+				t.Errorf("Mismatch.Line = %d, want %d", m.Line, tt.wantLine)
+			}
+		})
+	}
+}
+
+// recorder captures failures so AssertGolden's own failures can be tested.
+type recorder struct {
+	testing.TB
+	failed bool
+	msg    string
+}
+
+func (r *recorder) Helper()             {}
+func (r *recorder) Logf(string, ...any) {}
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failed, r.msg = true, fmt.Sprintf(format, args...)
+}
+func (r *recorder) Fatalf(format string, args ...any) { r.Errorf(format, args...) }
+
+func TestAssertGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "golden.svg")
+	t.Setenv(UpdateEnv, "")
+
+	rec := &recorder{TB: t}
+	AssertGolden(rec, path, []byte(`<svg width="10"/>`))
+	if !rec.failed {
+		t.Fatal("missing golden file did not fail")
+	}
+
+	t.Setenv(UpdateEnv, "1")
+	AssertGolden(t, path, []byte(`<svg width="10"/>`))
+	if data, err := os.ReadFile(path); err != nil || string(data) != `<svg width="10"/>` {
+		t.Fatalf("golden file = %q, %v; want the update", data, err)
+	}
+
+	t.Setenv(UpdateEnv, "")
+	AssertGolden(t, path, []byte(`<svg width="10.001"/>`))
+
+	rec = &recorder{TB: t}
+	AssertGolden(rec, path, []byte(`<svg width="12"/>`), WithTolerance(1))
+	if !rec.failed {
+		t.Fatal("difference beyond the tolerance did not fail")
+	}
+}
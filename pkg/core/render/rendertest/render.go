@@ -0,0 +1,81 @@
+package rendertest
+
+import (
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/dag/transform"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/ordering"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/sink"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+)
+
+// Default frame size of [Render].
+const (
+	DefaultWidth  = 800
+	DefaultHeight = 600
+)
+
+// Option configures [Render].
+type Option func(*config)
+
+type config struct {
+	width, height float64
+	style         styles.Style
+	orderer       ordering.Orderer
+	layoutOpts    []layout.Option
+	svgOpts       []sink.SVGOption
+}
+
+// WithSize sets the frame size.
+func WithSize(width, height float64) Option {
+	return func(c *config) { c.width, c.height = width, height }
+}
+
+// WithStyle sets the style. The default is [styles.Simple].
+func WithStyle(s styles.Style) Option {
+	return func(c *config) { c.style = s }
+}
+
+// WithOrderer replaces the barycentric row ordering. The orderer must be
+// deterministic for golden files to be stable.
+func WithOrderer(o ordering.Orderer) Option {
+	return func(c *config) { c.orderer = o }
+}
+
+// WithLayoutOptions adds options to the layout.
+func WithLayoutOptions(opts ...layout.Option) Option {
+	return func(c *config) { c.layoutOpts = append(c.layoutOpts, opts...) }
+}
+
+// WithSVGOptions adds options to the SVG sink, after the graph and style.
+func WithSVGOptions(opts ...sink.SVGOption) Option {
+	return func(c *config) { c.svgOpts = append(c.svgOpts, opts...) }
+}
+
+// Render normalizes a copy of g, lays it out and renders it to SVG. The
+// same graph and options produce the same bytes on every run. g is not
+// modified; it fails the test when normalization fails.
+func Render(tb testing.TB, g *dag.DAG, opts ...Option) []byte {
+	tb.Helper()
+	cfg := config{
+		width:   DefaultWidth,
+		height:  DefaultHeight,
+		style:   styles.Simple{},
+		orderer: ordering.Barycentric{},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	work := g.Clone()
+	if _, err := transform.Normalize(work); err != nil {
+		tb.Fatalf("rendertest: normalize: %v", err)
+	}
+	layoutOpts := append([]layout.Option{layout.WithOrderer(cfg.orderer)}, cfg.layoutOpts...)
+	l := layout.Build(work, cfg.width, cfg.height, layoutOpts...)
+
+	svgOpts := append([]sink.SVGOption{sink.WithGraph(work), sink.WithStyle(cfg.style)}, cfg.svgOpts...)
+	return sink.RenderSVG(l, svgOpts...)
+}
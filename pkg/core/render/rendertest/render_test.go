@@ -0,0 +1,44 @@
+package rendertest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/sink"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+)
+
+func diamond() *dag.DAG {
+	g := dag.New(nil)
+	for _, id := range []string{"app", "auth", "cache", "db"} {
+		_ = g.AddNode(dag.Node{ID: id, Meta: dag.Metadata{"description": id + " package"}})
+	}
+	_ = g.AddEdge(dag.Edge{From: "app", To: "auth"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "cache"})
+	_ = g.AddEdge(dag.Edge{From: "auth", To: "db"})
+	_ = g.AddEdge(dag.Edge{From: "cache", To: "db"})
+	return g
+}
+
+func TestRender_Deterministic(t *testing.T) {
+	g := diamond()
+	first := Render(t, g, WithSVGOptions(sink.WithPopups()))
+	for range 5 {
+		if again := Render(t, g, WithSVGOptions(sink.WithPopups())); !bytes.Equal(first, again) {
+			t.Fatal("Render() output differs between runs")
+		}
+	}
+	if g.NodeCount() != 4 || g.MaxRow() != 0 {
+		t.Error("Render() modified the input graph")
+	}
+}
+
+func TestRender_Golden(t *testing.T) {
+	AssertGolden(t, "testdata/diamond.svg", Render(t, diamond()))
+	AssertGolden(t, "testdata/diamond-edges.svg", Render(t, diamond(),
+		WithSize(400, 300),
+		WithStyle(styles.Simple{}),
+		WithSVGOptions(sink.WithEdges()),
+	))
+}
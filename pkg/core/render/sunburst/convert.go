@@ -0,0 +1,18 @@
+package sunburst
+
+import (
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+// Export creates a serializable sunburst layout holding the nodes and edges of
+// g; see [graph.ExportGraphLayout].
+func Export(g *dag.DAG, width, height float64, style string) graph.Layout {
+	return graph.ExportGraphLayout(g, graph.VizTypeSunburst, width, height, style)
+}
+
+// Parse rebuilds the graph stored in a serialized sunburst layout; see
+// [graph.ParseGraphLayout].
+func Parse(layout graph.Layout) (*dag.DAG, error) {
+	return graph.ParseGraphLayout(layout, graph.VizTypeSunburst)
+}
@@ -0,0 +1,15 @@
+package sunburst
+
+import (
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+func TestExportParse(t *testing.T) {
+	if l := Export(sharedGraph(), 800, 600, "simple"); l.VizType != graph.VizTypeSunburst {
+		t.Errorf("Export() viz_type = %q, want %q", l.VizType, graph.VizTypeSunburst)
+	} else if _, err := Parse(l); err != nil {
+		t.Errorf("Parse() error: %v", err)
+	}
+}
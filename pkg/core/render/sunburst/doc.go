@@ -0,0 +1,27 @@
+// Package sunburst draws dependency graphs as radial sunburst charts.
+//
+// The root package sits at the center and every ring outward is one more
+// level of dependencies. Each package's arc spans an angle proportional to
+// the number of packages below it, so the heaviest subtrees stand out at a
+// glance. Unlike towers, whose width grows with every package, a sunburst
+// keeps its size fixed and stays readable for graphs with thousands of
+// nodes; small arcs simply lose their labels and keep their tooltips.
+//
+// # Architecture
+//
+// Like nodelink, a sunburst is computed from the graph at render time:
+//
+//	Sunburst: DAG → Build() → Chart → RenderSVG() → SVG
+//
+// [Build] reduces the graph to a breadth-first spanning tree: a package
+// required by several others is drawn once, under the parent closest to
+// the root, and its tooltip counts the other dependents.
+//
+// # Usage
+//
+//	svg, err := sunburst.RenderSVG(g, sunburst.Options{Size: 1000, MaxDepth: 4})
+//
+// Arcs under each direct dependency share one palette colour, fading with
+// depth; vulnerable packages are filled dark orange and brittle packages
+// (see feature.IsBrittle) outlined in red.
+package sunburst
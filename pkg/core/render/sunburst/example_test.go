@@ -0,0 +1,29 @@
+package sunburst_test
+
+import (
+	"fmt"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/sunburst"
+)
+
+func ExampleBuild() {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "web"})
+	_ = g.AddNode(dag.Node{ID: "db"})
+	_ = g.AddNode(dag.Node{ID: "router"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "web"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "db"})
+	_ = g.AddEdge(dag.Edge{From: "web", To: "router"})
+
+	chart := sunburst.Build(g, sunburst.Options{})
+	for _, a := range chart.Arcs {
+		fmt.Printf("%s ring %d: %.2f-%.2f\n", a.ID, a.Depth, a.Start, a.End)
+	}
+	// Output:
+	// app ring 0: 0.00-1.00
+	// web ring 1: 0.00-0.50
+	// router ring 2: 0.00-0.25
+	// db ring 1: 0.50-0.75
+}
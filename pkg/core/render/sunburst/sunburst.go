@@ -0,0 +1,161 @@
+package sunburst
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+// Options configures the sunburst layout and rendering.
+type Options struct {
+	// Size is the diameter of the chart in pixels (default 800).
+	Size float64
+
+	// MaxDepth limits how many rings are drawn below the center; deeper
+	// packages still count toward the size of their ancestors' arcs.
+	// 0 draws every ring.
+	MaxDepth int
+
+	// Palette colours the arcs: each direct dependency takes the next
+	// colour and its subtree lighter shades of it. Nil uses
+	// [styles.PalettePastel].
+	Palette []string
+}
+
+// Arc is one package placed on the chart. Start and End are fractions of
+// the full circle, clockwise from twelve o'clock; Depth is the ring, with
+// the center at 0.
+type Arc struct {
+	ID     string  `json:"id"`
+	Parent string  `json:"parent,omitempty"`
+	Depth  int     `json:"depth"`
+	Start  float64 `json:"start"`
+	End    float64 `json:"end"`
+
+	// Size counts the package and everything placed under it.
+	Size int `json:"size"`
+
+	// Parents counts the packages that depend on this one. A package with
+	// several parents is drawn once, under the first parent reached from
+	// the center.
+	Parents int `json:"parents"`
+
+	// Branch is the index of the direct dependency the arc sits under, or
+	// -1 for the center.
+	Branch int `json:"branch"`
+}
+
+// Chart is a computed sunburst: the arcs in drawing order, center first.
+type Chart struct {
+	// Root is the package at the center, or "" when the graph has several
+	// roots and the center stands for all of them.
+	Root  string `json:"root"`
+	Arcs  []Arc  `json:"arcs"`
+	Depth int    `json:"depth"` // deepest ring drawn
+	Total int    `json:"total"` // packages in the chart, center included
+}
+
+// Build lays the dependency graph out as a sunburst. The graph is reduced
+// to a spanning tree by breadth-first search from the root, so every
+// package appears once, at its shortest distance from the root; arcs are
+// sized by the number of packages below them. Subdividers and other
+// synthetic nodes are walked through and never drawn.
+func Build(g *dag.DAG, opts Options) Chart {
+	root, roots := center(g)
+	if root == "" && len(roots) == 0 {
+		return Chart{}
+	}
+
+	children := make(map[string][]string)
+	parentOf := make(map[string]string)
+	depth := map[string]int{root: 0}
+	queue := []string{root}
+	for _, r := range roots {
+		depth[r] = 1
+		parentOf[r] = root
+		children[root] = append(children[root], r)
+		queue = append(queue, r)
+	}
+	if len(roots) > 0 {
+		queue = queue[1:]
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, c := range g.RealChildren(id) {
+			if _, seen := depth[c]; seen {
+				continue
+			}
+			depth[c] = depth[id] + 1
+			parentOf[c] = id
+			children[id] = append(children[id], c)
+			queue = append(queue, c)
+		}
+	}
+
+	size := make(map[string]int, len(depth))
+	var sizeOf func(string) int
+	sizeOf = func(id string) int {
+		s := 1
+		for _, c := range children[id] {
+			s += sizeOf(c)
+		}
+		size[id] = s
+		return s
+	}
+	sizeOf(root)
+	for id := range children {
+		slices.SortFunc(children[id], func(a, b string) int {
+			return cmp.Or(cmp.Compare(size[b], size[a]), cmp.Compare(a, b))
+		})
+	}
+
+	chart := Chart{Root: root, Total: size[root]}
+	if root == "" {
+		chart.Total--
+	}
+	var place func(id string, start, end float64, branch int)
+	place = func(id string, start, end float64, branch int) {
+		d := depth[id]
+		if opts.MaxDepth > 0 && d > opts.MaxDepth {
+			return
+		}
+		chart.Arcs = append(chart.Arcs, Arc{
+			ID: id, Parent: parentOf[id], Depth: d,
+			Start: start, End: end,
+			Size: size[id], Parents: len(g.RealParents(id)),
+			Branch: branch,
+		})
+		chart.Depth = max(chart.Depth, d)
+		span := end - start
+		at := start
+		for i, c := range children[id] {
+			next := at + span*float64(size[c])/float64(size[id])
+			b := branch
+			if d == 0 {
+				b = i
+			}
+			place(c, at, next, b)
+			at = next
+		}
+	}
+	place(root, 0, 1, -1)
+	return chart
+}
+
+// center returns the root package, or "" plus the list of roots when the
+// graph has more than one package nothing depends on.
+func center(g *dag.DAG) (string, []string) {
+	var roots []string
+	for _, n := range g.Nodes() {
+		if !n.IsSynthetic() && g.InDegree(n.ID) == 0 {
+			roots = append(roots, n.ID)
+		}
+	}
+	if len(roots) == 1 {
+		return roots[0], nil
+	}
+	slices.Sort(roots)
+	return "", roots
+}
@@ -0,0 +1,99 @@
+package sunburst
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func sharedGraph() *dag.DAG {
+	g := dag.New(nil)
+	for _, id := range []string{"app", "a", "b", "shared", "leaf"} {
+		g.AddNode(dag.Node{ID: id})
+	}
+	g.AddEdge(dag.Edge{From: "app", To: "a"})
+	g.AddEdge(dag.Edge{From: "app", To: "b"})
+	g.AddEdge(dag.Edge{From: "a", To: "shared"})
+	g.AddEdge(dag.Edge{From: "b", To: "shared"})
+	g.AddEdge(dag.Edge{From: "shared", To: "leaf"})
+	return g
+}
+
+func arcsByID(c Chart) map[string]Arc {
+	m := make(map[string]Arc, len(c.Arcs))
+	for _, a := range c.Arcs {
+		m[a.ID] = a
+	}
+	return m
+}
+
+func TestBuild(t *testing.T) {
+	chart := Build(sharedGraph(), Options{})
+	if chart.Root != "app" || chart.Total != 5 || chart.Depth != 3 {
+		t.Fatalf("Build() = root %q, total %d, depth %d; want app, 5, 3", chart.Root, chart.Total, chart.Depth)
+	}
+	arcs := arcsByID(chart)
+
+	// shared is drawn once, under the first parent reached.
+	if got := arcs["shared"]; got.Parent != "a" || got.Parents != 2 || got.Depth != 2 {
+		t.Errorf("shared = %+v, want under a with 2 parents at depth 2", got)
+	}
+	// a carries shared and leaf, so it outweighs b.
+	a, b := arcs["a"], arcs["b"]
+	if a.Size != 3 || b.Size != 1 || a.Start != 0 || !near(a.End, 0.6) || !near(b.Start, 0.6) || !near(b.End, 0.8) {
+		t.Errorf("a = %+v, b = %+v", a, b)
+	}
+	if a.Branch != 0 || b.Branch != 1 || arcs["leaf"].Branch != 0 || arcs["app"].Branch != -1 {
+		t.Error("Build() assigned wrong branches")
+	}
+	// Children nest inside their parent's span.
+	for _, arc := range chart.Arcs {
+		if arc.Parent == "" {
+			continue
+		}
+		p := arcs[arc.Parent]
+		if arc.Start < p.Start || arc.End > p.End+1e-9 || arc.Depth != p.Depth+1 {
+			t.Errorf("%s [%v,%v] not inside %s [%v,%v]", arc.ID, arc.Start, arc.End, p.ID, p.Start, p.End)
+		}
+	}
+}
+
+func TestBuild_MaxDepth(t *testing.T) {
+	chart := Build(sharedGraph(), Options{MaxDepth: 1})
+	if len(chart.Arcs) != 3 || chart.Depth != 1 {
+		t.Fatalf("Build(MaxDepth 1) drew %d arcs to depth %d, want 3 to depth 1", len(chart.Arcs), chart.Depth)
+	}
+	// Hidden rings still weigh on the visible arcs.
+	if a := arcsByID(chart)["a"]; a.Size != 3 {
+		t.Errorf("a.Size = %d, want 3", a.Size)
+	}
+}
+
+func TestBuild_MultipleRoots(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "x"})
+	g.AddNode(dag.Node{ID: "y"})
+	g.AddNode(dag.Node{ID: "sub", Kind: dag.NodeKindSubdivider, MasterID: "x"})
+	g.AddNode(dag.Node{ID: "z"})
+	g.AddEdge(dag.Edge{From: "x", To: "sub"})
+	g.AddEdge(dag.Edge{From: "sub", To: "z"})
+
+	chart := Build(g, Options{})
+	if chart.Root != "" || chart.Total != 3 {
+		t.Fatalf("Build() = root %q total %d, want an anonymous center over 3 packages", chart.Root, chart.Total)
+	}
+	arcs := arcsByID(chart)
+	if _, ok := arcs["sub"]; ok {
+		t.Error("Build() drew a subdivider")
+	}
+	if z := arcs["z"]; z.Parent != "x" || z.Depth != 2 {
+		t.Errorf("z = %+v, want under x through the subdivider", z)
+	}
+
+	if chart := Build(dag.New(nil), Options{}); len(chart.Arcs) != 0 {
+		t.Error("Build() of an empty graph drew arcs")
+	}
+}
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
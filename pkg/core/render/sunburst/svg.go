@@ -0,0 +1,183 @@
+package sunburst
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+	"github.com/stacktower-io/stacktower/pkg/security"
+)
+
+const (
+	defaultSize = 800.0
+	padding     = 10.0
+	fontSize    = 11.0
+	charWidth   = 6.2 // average advance of the label font at fontSize
+
+	// Risk colours match the nodelink diagram.
+	vulnFill      = "#c2410c"
+	brittleStroke = "#b91c1c"
+
+	// lightenPerRing is how much each ring below a direct dependency fades
+	// its colour toward white, up to maxLighten.
+	lightenPerRing = 0.18
+	maxLighten     = 0.7
+)
+
+// RenderSVG computes the sunburst for g with [Build] and draws it as SVG.
+// Each arc carries a tooltip with the package's size and how many other
+// packages also depend on it; vulnerable packages are filled dark orange
+// and brittle ones outlined red, as in the other visualizations. Labels are
+// drawn where they fit.
+func RenderSVG(g *dag.DAG, opts Options) ([]byte, error) {
+	chart := Build(g, opts)
+	if len(chart.Arcs) == 0 {
+		return nil, fmt.Errorf("sunburst: graph has no root package")
+	}
+	size := opts.Size
+	if size <= 0 {
+		size = defaultSize
+	}
+	palette := styles.Palette(opts.Palette)
+	if len(palette) == 0 {
+		palette = styles.PalettePastel
+	}
+
+	c := size / 2
+	ring := (size/2 - padding) / float64(chart.Depth+1)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %.0f %.0f" width="%.0f" height="%.0f">`+"\n",
+		size, size, size, size)
+	fmt.Fprintf(&buf, `  <style>text { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; font-size: %.0fpx; pointer-events: none; } .arc:hover path, .arc:hover circle { opacity: 0.8; }</style>`+"\n", fontSize)
+	for _, a := range chart.Arcs {
+		if a.Depth == 0 && a.ID == "" {
+			continue
+		}
+		n, _ := g.Node(a.ID)
+		fill, stroke, strokeW := arcColors(a, n, palette)
+
+		fmt.Fprintf(&buf, `  <g class="arc" data-id="%s">`+"\n", styles.EscapeXML(a.ID))
+		fmt.Fprintf(&buf, "    <title>%s</title>\n", styles.EscapeXML(tooltip(a, n, chart.Total)))
+		if a.Depth == 0 {
+			fmt.Fprintf(&buf, `    <circle cx="%.2f" cy="%.2f" r="%.2f" fill="%s" stroke="%s" stroke-width="%s"/>`+"\n",
+				c, c, ring, fill, stroke, strokeW)
+		} else {
+			fmt.Fprintf(&buf, `    <path d="%s" fill="%s" stroke="%s" stroke-width="%s"/>`+"\n",
+				arcPath(c, float64(a.Depth)*ring, float64(a.Depth+1)*ring, a.Start, a.End), fill, stroke, strokeW)
+		}
+		writeLabel(&buf, a, c, ring, styles.ContrastText(fill))
+		buf.WriteString("  </g>\n")
+	}
+	buf.WriteString("</svg>\n")
+	return buf.Bytes(), nil
+}
+
+// arcColors returns the fill, stroke and stroke width of an arc.
+func arcColors(a Arc, n *dag.Node, palette styles.Palette) (fill, stroke, width string) {
+	fill, stroke, width = "#f6f8fa", "#ffffff", "1"
+	if a.Branch >= 0 {
+		fill = styles.Lighten(palette.Color(a.Branch), min(float64(a.Depth-1)*lightenPerRing, maxLighten))
+	}
+	if n == nil {
+		return fill, stroke, width
+	}
+	if sev, _ := n.Meta[security.MetaVulnSeverity].(string); sev != "" {
+		fill = vulnFill
+	}
+	if feature.IsBrittle(n) {
+		stroke, width = brittleStroke, "2.5"
+	}
+	return fill, stroke, width
+}
+
+// tooltip describes an arc: its package, the share of the chart below it,
+// the other packages that depend on it, and its risk signals.
+func tooltip(a Arc, n *dag.Node, total int) string {
+	lines := []string{a.ID}
+	if below := a.Size - 1; below > 0 && total > 0 {
+		lines = append(lines, fmt.Sprintf("%d packages below (%.0f%% of the chart)", below, 100*float64(a.Size)/float64(total)))
+	}
+	if a.Parents > 1 {
+		lines = append(lines, fmt.Sprintf("also required by %d other packages", a.Parents-1))
+	}
+	if n != nil {
+		if score, ok := feature.HealthScore(n); ok {
+			lines = append(lines, fmt.Sprintf("health: %d/100 (%s)", score, feature.HealthLevel(score)))
+		}
+		if sev, _ := n.Meta[security.MetaVulnSeverity].(string); sev != "" {
+			lines = append(lines, "vulnerability: "+sev)
+		}
+		if feature.IsBrittle(n) {
+			lines = append(lines, "brittle: may be unmaintained")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// point converts a fraction of the circle at radius r to coordinates,
+// starting at twelve o'clock and running clockwise.
+func point(c, r, frac float64) (float64, float64) {
+	angle := 2*math.Pi*frac - math.Pi/2
+	return c + r*math.Cos(angle), c + r*math.Sin(angle)
+}
+
+// arcPath returns the outline of the ring segment between radii r0 and r1
+// and circle fractions start and end.
+func arcPath(c, r0, r1, start, end float64) string {
+	// A full ring cannot be drawn as a single arc; stop just short of it.
+	end = min(end, start+0.99999)
+	large := 0
+	if end-start > 0.5 {
+		large = 1
+	}
+	x0, y0 := point(c, r1, start)
+	x1, y1 := point(c, r1, end)
+	x2, y2 := point(c, r0, end)
+	x3, y3 := point(c, r0, start)
+	return fmt.Sprintf("M%.2f,%.2f A%.2f,%.2f 0 %d 1 %.2f,%.2f L%.2f,%.2f A%.2f,%.2f 0 %d 0 %.2f,%.2f Z",
+		x0, y0, r1, r1, large, x1, y1, x2, y2, r0, r0, large, x3, y3)
+}
+
+// writeLabel draws the package name where it fits: across the center, along
+// the ring for wide arcs, or along the radius for narrow ones. Labels that
+// fit nowhere are left to the tooltip.
+func writeLabel(buf *bytes.Buffer, a Arc, c, ring float64, color string) {
+	width := float64(len(a.ID)) * charWidth
+	if a.Depth == 0 {
+		if width <= 2*ring-8 {
+			fmt.Fprintf(buf, `    <text x="%.2f" y="%.2f" text-anchor="middle" dominant-baseline="central" fill="%s">%s</text>`+"\n",
+				c, c, color, styles.EscapeXML(a.ID))
+		}
+		return
+	}
+
+	mid := (a.Start + a.End) / 2
+	r := (float64(a.Depth) + 0.5) * ring
+	x, y := point(c, r, mid)
+	deg := mid * 360
+
+	var rotate float64
+	switch {
+	case width <= 2*math.Pi*r*(a.End-a.Start)-6 && ring >= fontSize+4:
+		// Along the ring, kept upright on the lower half.
+		rotate = deg
+		if mid > 0.25 && mid < 0.75 {
+			rotate -= 180
+		}
+	case width <= ring-6 && 2*math.Pi*r*(a.End-a.Start) >= fontSize+2:
+		// Along the radius, reading outward on the right half.
+		rotate = deg - 90
+		if mid > 0.5 {
+			rotate += 180
+		}
+	default:
+		return
+	}
+	fmt.Fprintf(buf, `    <text x="%.2f" y="%.2f" text-anchor="middle" dominant-baseline="central" transform="rotate(%.1f %.2f %.2f)" fill="%s">%s</text>`+"\n",
+		x, y, rotate, x, y, color, styles.EscapeXML(a.ID))
+}
@@ -0,0 +1,53 @@
+package sunburst
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestRenderSVG(t *testing.T) {
+	g := sharedGraph()
+	n, _ := g.Node("shared")
+	n.Meta = dag.Metadata{"vuln_severity": "high"}
+
+	svg, err := RenderSVG(g, Options{Size: 400})
+	if err != nil {
+		t.Fatalf("RenderSVG() error: %v", err)
+	}
+	if err := xml.Unmarshal(svg, new(struct{})); err != nil {
+		t.Fatalf("RenderSVG() produced invalid XML: %v", err)
+	}
+	out := string(svg)
+	for _, want := range []string{
+		`viewBox="0 0 400 400"`,
+		`<circle`,
+		`data-id="leaf"`,
+		`fill="#c2410c"`,
+		"also required by 1 other packages",
+		">app</text>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderSVG() missing %s", want)
+		}
+	}
+	if got := strings.Count(out, `class="arc"`); got != 5 {
+		t.Errorf("RenderSVG() drew %d arcs, want 5", got)
+	}
+
+	if _, err := RenderSVG(dag.New(nil), Options{}); err == nil {
+		t.Error("RenderSVG() accepted an empty graph")
+	}
+}
+
+func TestArcPath(t *testing.T) {
+	d := arcPath(100, 10, 20, 0, 0.25)
+	if !strings.HasPrefix(d, "M100.00,80.00 A20.00,20.00 0 0 1 120.00,100.00") {
+		t.Errorf("arcPath() = %s", d)
+	}
+	if d := arcPath(100, 10, 20, 0, 0.75); !strings.Contains(d, " 0 1 1 ") {
+		t.Errorf("arcPath() over half a turn lacks the large-arc flag: %s", d)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
 	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
 )
 
@@ -14,16 +15,70 @@ const (
 	minMaintainerCount = 2
 )
 
+// BrittleThresholds are the limits [IsBrittleWith] classifies packages by.
+// Zero fields fall back to [DefaultBrittleThresholds].
+type BrittleThresholds struct {
+	// Stale is how long without a commit before a package with few
+	// maintainers or stars counts as brittle.
+	Stale time.Duration
+	// Abandoned is how long without a commit before any package counts as
+	// brittle.
+	Abandoned time.Duration
+	// LowStars and FewMaintainers mark small projects: fewer stars than
+	// LowStars, or at most FewMaintainers maintainers.
+	LowStars       int
+	FewMaintainers int
+}
+
+// DefaultBrittleThresholds returns the thresholds [IsBrittle] uses: stale
+// after a year, abandoned after two, under 100 stars, at most 2 maintainers.
+func DefaultBrittleThresholds() BrittleThresholds {
+	return BrittleThresholds{
+		Stale:          staleThreshold,
+		Abandoned:      abandonedThreshold,
+		LowStars:       lowStarCount,
+		FewMaintainers: minMaintainerCount,
+	}
+}
+
+func (t BrittleThresholds) withDefaults() BrittleThresholds {
+	d := DefaultBrittleThresholds()
+	if t.Stale <= 0 {
+		t.Stale = d.Stale
+	}
+	if t.Abandoned <= 0 {
+		t.Abandoned = d.Abandoned
+	}
+	if t.LowStars <= 0 {
+		t.LowStars = d.LowStars
+	}
+	if t.FewMaintainers <= 0 {
+		t.FewMaintainers = d.FewMaintainers
+	}
+	return t
+}
+
 // IsBrittle returns true if a node represents a package that is potentially
 // unmaintained or risky to depend on. It checks for archived repositories,
-// long periods of inactivity, and low maintainer counts.
+// packages their registry marks abandoned, long periods of inactivity, and
+// low maintainer counts. First-party packages (see [deps.IsInternal]) are
+// never brittle: they are not a risk the project takes on from others.
 func IsBrittle(n *dag.Node) bool {
-	if n == nil || n.Meta == nil {
+	return IsBrittleWith(n, DefaultBrittleThresholds())
+}
+
+// IsBrittleWith is [IsBrittle] with custom thresholds.
+func IsBrittleWith(n *dag.Node, t BrittleThresholds) bool {
+	if n == nil || n.Meta == nil || deps.IsInternal(n) {
 		return false
 	}
 	if archived, _ := n.Meta[metadata.RepoArchived].(bool); archived {
 		return true
 	}
+	if abandoned, _ := n.Meta[deps.MetaAbandoned].(bool); abandoned {
+		return true
+	}
+	t = t.withDefaults()
 
 	maintainers := CountMaintainers(n.Meta[metadata.RepoMaintainers])
 	stars, _ := n.Meta[metadata.RepoStars].(int)
@@ -32,24 +87,46 @@ func IsBrittle(n *dag.Node) bool {
 	lastCommit := ParseDate(n.Meta[metadata.RepoLastCommit])
 	if !lastCommit.IsZero() {
 		age := time.Since(lastCommit)
-		if age > abandonedThreshold {
+		if age > t.Abandoned {
 			return true
 		}
-		// If recently updated (< 1 year), not brittle regardless of maintainers/stars
-		if age <= staleThreshold {
+		// If recently updated, not brittle regardless of maintainers/stars
+		if age <= t.Stale {
 			return false
 		}
-		// Stale (1-2 years): brittle if low maintainers or low stars
-		return maintainers == 1 || stars < lowStarCount || maintainers <= minMaintainerCount
+		// Stale: brittle if low maintainers or low stars
+		return maintainers == 1 || stars < t.LowStars || maintainers <= t.FewMaintainers
 	}
 
 	// No last commit date available - fall back to maintainers/stars check
 	// This ensures consistency with the graph serialization logic
-	hasFewMaintainers := maintainers > 0 && maintainers <= minMaintainerCount
-	hasLowStars := stars > 0 && stars < lowStarCount
+	hasFewMaintainers := maintainers > 0 && maintainers <= t.FewMaintainers
+	hasLowStars := stars > 0 && stars < t.LowStars
 	return hasFewMaintainers || hasLowStars
 }
 
+// Successor returns the suggested replacement for an archived or abandoned
+// package: the maintained fork recorded in repo_successor during GitHub
+// enrichment, else the replacement package the registry names for an
+// abandoned one. It returns "" when the package is neither, or nothing
+// qualified. Such a package is brittle either way; a successor tells
+// readers where to go.
+func Successor(n *dag.Node) string {
+	if n == nil || n.Meta == nil {
+		return ""
+	}
+	if archived, _ := n.Meta[metadata.RepoArchived].(bool); archived {
+		if s, _ := n.Meta[metadata.RepoSuccessor].(string); s != "" {
+			return s
+		}
+	}
+	if abandoned, _ := n.Meta[deps.MetaAbandoned].(bool); abandoned {
+		s, _ := n.Meta[deps.MetaReplacement].(string)
+		return s
+	}
+	return ""
+}
+
 func ParseDate(v any) time.Time {
 	s, ok := v.(string)
 	if !ok || s == "" {
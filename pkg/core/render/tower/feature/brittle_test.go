@@ -27,6 +27,20 @@ func TestIsBrittle(t *testing.T) {
 			&dag.Node{ID: "pkg", Meta: dag.Metadata{"repo_archived": true}},
 			true,
 		},
+		{
+			"archived but first-party",
+			&dag.Node{ID: "pkg", Meta: dag.Metadata{"repo_archived": true, "internal": true}},
+			false,
+		},
+		{
+			"abandoned in registry",
+			&dag.Node{ID: "pkg", Meta: dag.Metadata{
+				"abandoned":        true,
+				"repo_last_commit": oneMonthAgo,
+				"repo_stars":       5000,
+			}},
+			true,
+		},
 		{
 			"abandoned (3 years stale)",
 			&dag.Node{ID: "pkg", Meta: dag.Metadata{
@@ -123,3 +137,29 @@ func TestIsBrittle(t *testing.T) {
 		})
 	}
 }
+
+func TestSuccessor(t *testing.T) {
+	fork := "https://github.com/community/repo"
+	cases := []struct {
+		name string
+		node *dag.Node
+		want string
+	}{
+		{"nil node", nil, ""},
+		{"archived without fork", &dag.Node{ID: "pkg", Meta: dag.Metadata{"repo_archived": true}}, ""},
+		{"archived with fork", &dag.Node{ID: "pkg", Meta: dag.Metadata{"repo_archived": true, "repo_successor": fork}}, fork},
+		{"active repo ignores stale successor", &dag.Node{ID: "pkg", Meta: dag.Metadata{"repo_successor": fork}}, ""},
+		{"abandoned with replacement", &dag.Node{ID: "pkg", Meta: dag.Metadata{"abandoned": true, "replacement": "new/pkg"}}, "new/pkg"},
+		{"fork wins over replacement", &dag.Node{ID: "pkg", Meta: dag.Metadata{
+			"repo_archived": true, "repo_successor": fork, "abandoned": true, "replacement": "new/pkg",
+		}}, fork},
+		{"replacement of active package", &dag.Node{ID: "pkg", Meta: dag.Metadata{"replacement": "new/pkg"}}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Successor(tc.node); got != tc.want {
+				t.Errorf("Successor() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
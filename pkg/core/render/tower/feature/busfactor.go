@@ -0,0 +1,198 @@
+package feature
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
+)
+
+// PackageBusFactor estimates how many people a package depends on.
+type PackageBusFactor struct {
+	Package string `json:"package"`
+
+	// BusFactor is the smallest number of contributors who together made
+	// more than half of the commits; 0 means no contributor data.
+	BusFactor int `json:"bus_factor"`
+
+	// Top is the leading contributor and TopShare their share of commits.
+	Top      string  `json:"top_contributor,omitempty"`
+	TopShare float64 `json:"top_share,omitempty"`
+
+	Contributors int `json:"contributors"`
+
+	// KeyPeople are the contributors that make up BusFactor, busiest first.
+	KeyPeople []string `json:"key_people,omitempty"`
+
+	// Estimated is set when commit counts were missing and every listed
+	// maintainer was assumed to contribute equally.
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// BusFactorReport is the bus factor of every package plus a score for the
+// tower as a whole.
+type BusFactorReport struct {
+	// Packages lists the packages with contributor data, most fragile first.
+	Packages []PackageBusFactor `json:"packages"`
+
+	// TowerBusFactor is how many people would have to leave before more
+	// than half of the packages lose all of their key contributors.
+	TowerBusFactor int `json:"tower_bus_factor"`
+
+	// KeyPeople are those people, in the order they were picked.
+	KeyPeople []string `json:"key_people,omitempty"`
+
+	// SinglePerson counts packages with a bus factor of 1.
+	SinglePerson int `json:"single_person"`
+}
+
+// NodeBusFactor computes the bus factor of one package from its enrichment
+// data: contributor commit counts when parsed with contributors, otherwise
+// an estimate from the maintainer list.
+func NodeBusFactor(n *dag.Node) PackageBusFactor {
+	bf := PackageBusFactor{Package: n.ID}
+	if n.Meta == nil {
+		return bf
+	}
+	people := getStringSlice(n.Meta[metadata.RepoMaintainers])
+	counts := getIntSlice(n.Meta[metadata.RepoContributions])
+	if len(people) == 0 {
+		return bf
+	}
+	if len(counts) != len(people) {
+		counts = make([]int, len(people))
+		for i := range counts {
+			counts[i] = 1
+		}
+		bf.Estimated = true
+	}
+
+	// Contributors arrive sorted by commits, but don't rely on it.
+	order := make([]int, len(people))
+	total := 0
+	for i := range order {
+		order[i] = i
+		total += counts[i]
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(counts[b], counts[a]) })
+	if total <= 0 {
+		return bf
+	}
+
+	bf.Contributors = len(people)
+	bf.Top = people[order[0]]
+	bf.TopShare = float64(counts[order[0]]) / float64(total)
+	sum := 0
+	for _, i := range order {
+		sum += counts[i]
+		bf.BusFactor++
+		bf.KeyPeople = append(bf.KeyPeople, people[i])
+		if 2*sum > total {
+			break
+		}
+	}
+	return bf
+}
+
+// BusFactor scores every package in the graph and the tower as a whole.
+//
+// The tower score answers "how many people could we lose before most of our
+// stack is orphaned?". People are removed greedily, each time picking the one
+// whose departure orphans the most packages (a package is orphaned once all
+// contributors behind its bus factor are gone), until more than half of the
+// scored packages are orphaned.
+func BusFactor(g *dag.DAG) BusFactorReport {
+	var report BusFactorReport
+	for _, n := range g.Nodes() {
+		if n.IsSynthetic() || g.InDegree(n.ID) == 0 {
+			continue
+		}
+		if bf := NodeBusFactor(n); bf.BusFactor > 0 {
+			report.Packages = append(report.Packages, bf)
+			if bf.BusFactor == 1 {
+				report.SinglePerson++
+			}
+		}
+	}
+	slices.SortFunc(report.Packages, func(a, b PackageBusFactor) int {
+		if c := cmp.Compare(a.BusFactor, b.BusFactor); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.TopShare, a.TopShare); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Package, b.Package)
+	})
+
+	report.KeyPeople = towerKeyPeople(report.Packages)
+	report.TowerBusFactor = len(report.KeyPeople)
+	return report
+}
+
+// towerKeyPeople greedily picks the people whose loss orphans more than
+// half of the packages.
+func towerKeyPeople(pkgs []PackageBusFactor) []string {
+	remaining := make([]map[string]bool, len(pkgs))
+	for i, p := range pkgs {
+		remaining[i] = make(map[string]bool, len(p.KeyPeople))
+		for _, person := range p.KeyPeople {
+			remaining[i][person] = true
+		}
+	}
+
+	var picked []string
+	orphaned := 0
+	for 2*orphaned <= len(pkgs) && len(pkgs) > 0 {
+		// Score everyone still holding packages up: packages they would
+		// orphan right now first, then packages they are key to at all.
+		type score struct{ orphans, holds int }
+		scores := make(map[string]score)
+		for _, people := range remaining {
+			for person := range people {
+				s := scores[person]
+				s.holds++
+				if len(people) == 1 {
+					s.orphans++
+				}
+				scores[person] = s
+			}
+		}
+		if len(scores) == 0 {
+			break
+		}
+
+		best := ""
+		for person, s := range scores {
+			b := scores[best]
+			if best == "" || s.orphans > b.orphans ||
+				(s.orphans == b.orphans && (s.holds > b.holds || (s.holds == b.holds && person < best))) {
+				best = person
+			}
+		}
+		picked = append(picked, best)
+		for _, people := range remaining {
+			if people[best] {
+				delete(people, best)
+				if len(people) == 0 {
+					orphaned++
+				}
+			}
+		}
+	}
+	return picked
+}
+
+func getIntSlice(v any) []int {
+	switch v := v.(type) {
+	case []int:
+		return v
+	case []any:
+		out := make([]int, 0, len(v))
+		for _, item := range v {
+			out = append(out, AsInt(item))
+		}
+		return out
+	}
+	return nil
+}
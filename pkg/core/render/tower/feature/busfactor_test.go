@@ -0,0 +1,90 @@
+package feature
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestNodeBusFactor(t *testing.T) {
+	tests := []struct {
+		name      string
+		meta      dag.Metadata
+		want      int
+		top       string
+		share     float64
+		estimated bool
+	}{
+		{"dominant", dag.Metadata{
+			"repo_maintainers":   []string{"alice", "bob", "carol"},
+			"repo_contributions": []int{90, 5, 5},
+		}, 1, "alice", 0.9, false},
+		{"spread", dag.Metadata{
+			"repo_maintainers":   []string{"alice", "bob", "carol"},
+			"repo_contributions": []any{40.0, 35.0, 25.0}, // as decoded from JSON
+		}, 2, "alice", 0.4, false},
+		{"unsorted counts", dag.Metadata{
+			"repo_maintainers":   []string{"bob", "alice"},
+			"repo_contributions": []int{10, 30},
+		}, 1, "alice", 0.75, false},
+		{"maintainers only", dag.Metadata{
+			"repo_maintainers": []string{"a", "b", "c", "d"},
+		}, 3, "a", 0.25, true},
+		{"no data", dag.Metadata{"repo_owner": "alice"}, 0, "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bf := NodeBusFactor(&dag.Node{ID: "pkg", Meta: tt.meta})
+			if bf.BusFactor != tt.want || bf.Top != tt.top || bf.TopShare != tt.share || bf.Estimated != tt.estimated {
+				t.Errorf("NodeBusFactor() = %+v, want bus factor %d, top %s (%.2f), estimated %v",
+					bf, tt.want, tt.top, tt.share, tt.estimated)
+			}
+			if len(bf.KeyPeople) != bf.BusFactor {
+				t.Errorf("KeyPeople = %v, want %d people", bf.KeyPeople, bf.BusFactor)
+			}
+		})
+	}
+}
+
+func TestBusFactor_Tower(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app", Meta: dag.Metadata{"repo_maintainers": []string{"me"}}})
+	add := func(id string, people []string, counts []int) {
+		_ = g.AddNode(dag.Node{ID: id, Row: 1, Meta: dag.Metadata{
+			"repo_maintainers": people, "repo_contributions": counts,
+		}})
+		_ = g.AddEdge(dag.Edge{From: "app", To: id})
+	}
+	add("a", []string{"sindre"}, []int{100})
+	add("b", []string{"sindre", "x"}, []int{80, 20})
+	add("c", []string{"tj", "y"}, []int{60, 40})
+	add("d", []string{"y", "z"}, []int{50, 50})
+	add("e", []string{"tj"}, []int{10})
+
+	r := BusFactor(g)
+
+	if len(r.Packages) != 5 {
+		t.Fatalf("root should be excluded, got %d packages", len(r.Packages))
+	}
+	if r.SinglePerson != 4 {
+		t.Errorf("SinglePerson = %d, want 4", r.SinglePerson)
+	}
+	// Losing sindre orphans a and b; tj then orphans c and e: 4 of 5.
+	if r.TowerBusFactor != 2 || !slices.Equal(r.KeyPeople, []string{"sindre", "tj"}) {
+		t.Errorf("tower bus factor = %d %v, want 2 [sindre tj]", r.TowerBusFactor, r.KeyPeople)
+	}
+	if last := r.Packages[len(r.Packages)-1]; last.Package != "d" {
+		t.Errorf("the most resilient package should come last, got %s", last.Package)
+	}
+}
+
+func TestBusFactor_NoData(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "lib", Row: 1})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "lib"})
+	if r := BusFactor(g); r.TowerBusFactor != 0 || len(r.Packages) != 0 {
+		t.Errorf("expected an empty report, got %+v", r)
+	}
+}
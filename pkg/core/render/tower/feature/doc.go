@@ -7,6 +7,14 @@
 //
 //   - Nebraska ranking: Identify critical maintainers (inspired by XKCD #2347)
 //   - Brittle detection: Flag potentially unmaintained dependencies
+//   - Vulnerability report: Rank known advisories and how they are reached
+//   - Bus factor: Estimate how many people each package, and the tower,
+//     depends on
+//   - Freshness: Score how far each package trails its latest release
+//   - Suspicious packages: Cheap typosquat and supply-chain heuristics
+//   - Health: One 0–100 score per package combining the signals above
+//   - Weight attribution: How much of the tower each direct dependency
+//     pulls in
 //
 // # Nebraska Ranking
 //
@@ -36,6 +44,20 @@
 //	    }
 //	}
 //
+// [RankNebraskaWith] takes a [ScoringConfig] for organizations with their own
+// idea of criticality: custom role weights, a [DepthCurve] that flattens the
+// advantage of very deep packages, star damping that discounts popular
+// projects with large communities, and include/exclude lists of repository
+// owners:
+//
+//	cfg := feature.ScoringConfig{DepthCurve: feature.DepthSqrt, ExcludeOrgs: []string{"acme"}}
+//	rankings := feature.RankNebraskaWith(g, 10, cfg)
+//
+// Setting GroupBy to [GroupByOrg] ranks the organizations owning the
+// repositories instead of individuals, for risk reviews that care about
+// companies and foundations. Each package is credited in full to its owner,
+// and [NebraskaRanking.Share] reports the owner's fraction of the tower.
+//
 // # Roles
 //
 // The package recognizes three maintainer roles:
@@ -56,7 +78,81 @@
 //   - Single maintainer with no recent activity
 //
 // Brittle packages are highlighted in visualizations to draw attention to
-// potential maintenance risks in the dependency tree.
+// potential maintenance risks in the dependency tree. [IsBrittleWith] takes
+// [BrittleThresholds] for teams with a stricter or looser idea of "stale".
+//
+// When GitHub enrichment finds an archived repository it also looks for a
+// maintained fork (the most-starred fork that is still active and newer than
+// the upstream) and records it as repo_successor. [Successor] returns it, and
+// popups and stats suggest it as the replacement.
+//
+// # Freshness
+//
+// [NodeFreshness] scores how current a package is from 0 (fresh) to 1
+// (stale): the worse of the time since its last commit or release, measured
+// against the abandonment threshold, and how many published versions are
+// newer than the resolved one (latest_version and versions_behind, recorded
+// by the resolver). [Freshness] scores a whole graph, stalest first, and
+// [FreshnessLevel] buckets scores into the levels used by the freshness
+// heatmap.
+//
+// # Health
+//
+// [NodeHealth] folds stars, maintainers, release cadence, known
+// vulnerabilities and the OpenSSF Scorecard (when a graph carries one) into
+// a single 0–100 score. [HealthConfig] weights the components; components
+// without data are skipped. [AnnotateHealth] stores the score under
+// [MetaHealthScore] so styles, popups and CI checks share one number, and
+// [Health] lists every package least healthy first.
+//
+// # Suspicious Packages
+//
+// [Suspicious] flags packages worth a second look before trusting them:
+// names one edit away from a popular package (a built-in per-language list
+// plus any graph node with a million downloads), repositories created in
+// the last 90 days with little adoption, and repositories whose owner and
+// name share nothing with the package name. Each [Suspicion] lists its
+// reasons; none of them is proof of malice.
+//
+// # Weight Attribution
+//
+// [Weights] answers "who do I owe?": for each direct dependency of the root
+// it counts the packages pulled in below it and the packages only it pulls
+// in, i.e. what cutting it would remove, as counts and as fractions of the
+// tower. Popups show the numbers on direct dependencies and stats prints
+// them as a table:
+//
+//	for _, w := range feature.Weights(g) {
+//	    fmt.Printf("%s: %.0f%% of the tower, %d only via it\n", w.Package, w.Share*100, w.Exclusive)
+//	}
+//
+// # Bus Factor
+//
+// [NodeBusFactor] scores one package: the smallest number of contributors
+// who together made more than half of its commits, using the commit counts
+// fetched with contributor enrichment (repo_contributions). Without counts
+// every listed maintainer is assumed to contribute equally and the result is
+// marked Estimated.
+//
+// [BusFactor] scores every package and the tower as a whole. The tower bus
+// factor greedily removes the person whose departure orphans the most
+// packages until more than half are orphaned, and reports who they were:
+//
+//	report := feature.BusFactor(g)
+//	fmt.Printf("tower bus factor %d: %v\n", report.TowerBusFactor, report.KeyPeople)
+//
+// # Vulnerability Report
+//
+// [Vulnerabilities] turns the annotations of a vulnerability scan into a
+// ranked list of advisories: worst severity first, and within a severity the
+// packages closest to the root first. Each entry names the version that fixes
+// it and the shortest dependency path that pulls the package in:
+//
+//	for _, v := range feature.Vulnerabilities(g) {
+//	    fmt.Printf("%s %s@%s (%s) fixed in %s via %s\n",
+//	        v.ID, v.Package, v.Version, v.Severity, v.FixedVersion,
+//	        strings.Join(v.Path, " > "))
+//	}
 //
 // # Visualization Integration
 //
@@ -0,0 +1,112 @@
+package feature
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
+)
+
+// Freshness levels, from fresh to stale, as returned by [FreshnessLevel].
+const (
+	FreshnessFresh    = "fresh"
+	FreshnessCurrent  = "current"
+	FreshnessAging    = "aging"
+	FreshnessStale    = "stale"
+	FreshnessOutdated = "outdated"
+	FreshnessUnknown  = "unknown"
+)
+
+// FreshnessLevels lists the freshness levels from fresh to stale, excluding
+// [FreshnessUnknown].
+var FreshnessLevels = []string{FreshnessFresh, FreshnessCurrent, FreshnessAging, FreshnessStale, FreshnessOutdated}
+
+// PackageFreshness describes how current one package is.
+type PackageFreshness struct {
+	Package string `json:"package"`
+	Version string `json:"version,omitempty"`
+
+	// LastActivity is the most recent of the last commit and last release,
+	// and AgeDays the days since then.
+	LastActivity time.Time `json:"last_activity,omitzero"`
+	AgeDays      int       `json:"age_days,omitempty"`
+
+	// Latest is the newest published version and VersionsBehind how many
+	// published versions are newer than Version.
+	Latest         string `json:"latest_version,omitempty"`
+	VersionsBehind int    `json:"versions_behind,omitempty"`
+
+	// Score runs from 0 (fresh) to 1 (stale); Level buckets it.
+	Score float64 `json:"score"`
+	Level string  `json:"level"`
+}
+
+// NodeFreshness scores how stale one package is at time now.
+//
+// The score is the worse of two measures: age, the time since the last
+// commit or release relative to the abandonment threshold (reaching 1 at
+// t.Abandoned), and lag, the number of newer published versions (5 behind
+// scores 0.5). Packages with neither measure are [FreshnessUnknown].
+func NodeFreshness(n *dag.Node, now time.Time, t BrittleThresholds) PackageFreshness {
+	f := PackageFreshness{Package: n.ID, Level: FreshnessUnknown}
+	if n.Meta == nil {
+		return f
+	}
+	t = t.withDefaults()
+	f.Version, _ = n.Meta["version"].(string)
+	f.Latest, _ = n.Meta[deps.MetaLatestVersion].(string)
+
+	known := false
+	for _, key := range []string{metadata.RepoLastCommit, metadata.RepoLastRelease} {
+		if d := ParseDate(n.Meta[key]); d.After(f.LastActivity) {
+			f.LastActivity = d
+		}
+	}
+	if !f.LastActivity.IsZero() {
+		known = true
+		age := max(0, now.Sub(f.LastActivity))
+		f.AgeDays = int(age.Hours() / 24)
+		f.Score = min(1, float64(age)/float64(t.Abandoned))
+	}
+	if _, ok := n.Meta[deps.MetaVersionsBehind]; ok {
+		known = true
+		f.VersionsBehind = AsInt(n.Meta[deps.MetaVersionsBehind])
+		lag := float64(f.VersionsBehind) / float64(f.VersionsBehind+5)
+		f.Score = max(f.Score, lag)
+	}
+	if archived, _ := n.Meta[metadata.RepoArchived].(bool); archived {
+		known = true
+		f.Score = 1
+	}
+	if known {
+		f.Level = FreshnessLevel(f.Score)
+	}
+	return f
+}
+
+// FreshnessLevel buckets a freshness score into one of [FreshnessLevels].
+func FreshnessLevel(score float64) string {
+	i := min(int(score*float64(len(FreshnessLevels))), len(FreshnessLevels)-1)
+	return FreshnessLevels[max(0, i)]
+}
+
+// Freshness scores every package in the graph, stalest first. Packages
+// without release, commit or version data are left out.
+func Freshness(g *dag.DAG, now time.Time, t BrittleThresholds) []PackageFreshness {
+	var out []PackageFreshness
+	for _, n := range g.Nodes() {
+		if n.IsSynthetic() {
+			continue
+		}
+		if f := NodeFreshness(n, now, t); f.Level != FreshnessUnknown {
+			out = append(out, f)
+		}
+	}
+	slices.SortFunc(out, func(a, b PackageFreshness) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Package, b.Package))
+	})
+	return out
+}
@@ -0,0 +1,76 @@
+package feature
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestNodeFreshness(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name  string
+		meta  dag.Metadata
+		level string
+	}{
+		{"no data", dag.Metadata{"version": "1.0.0"}, FreshnessUnknown},
+		{"recent release", dag.Metadata{"repo_last_release": "2025-12-01"}, FreshnessFresh},
+		{"one year old", dag.Metadata{"repo_last_commit": "2025-01-01"}, FreshnessAging},
+		{"older commit, newer release", dag.Metadata{"repo_last_commit": "2020-01-01", "repo_last_release": "2025-12-01"}, FreshnessFresh},
+		{"abandoned", dag.Metadata{"repo_last_commit": "2021-01-01"}, FreshnessOutdated},
+		{"versions behind", dag.Metadata{"repo_last_release": "2025-12-01", "versions_behind": 20}, FreshnessOutdated},
+		{"behind from JSON", dag.Metadata{"versions_behind": float64(5)}, FreshnessAging},
+		{"archived", dag.Metadata{"repo_archived": true}, FreshnessOutdated},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NodeFreshness(&dag.Node{ID: "pkg", Meta: tc.meta}, now, BrittleThresholds{})
+			if f.Level != tc.level {
+				t.Errorf("level = %s (score %.2f), want %s", f.Level, f.Score, tc.level)
+			}
+		})
+	}
+}
+
+func TestNodeFreshness_Thresholds(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	n := &dag.Node{ID: "pkg", Meta: dag.Metadata{"repo_last_commit": "2025-07-01"}}
+
+	if f := NodeFreshness(n, now, BrittleThresholds{}); f.Level != FreshnessCurrent {
+		t.Errorf("default thresholds: level = %s, want %s", f.Level, FreshnessCurrent)
+	}
+	strict := BrittleThresholds{Abandoned: 180 * 24 * time.Hour}
+	if f := NodeFreshness(n, now, strict); f.Level != FreshnessOutdated {
+		t.Errorf("six-month abandonment: level = %s, want %s", f.Level, FreshnessOutdated)
+	}
+}
+
+func TestFreshness_SortsStalestFirst(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "new", Meta: dag.Metadata{"repo_last_release": "2025-12-01"}})
+	_ = g.AddNode(dag.Node{ID: "old", Meta: dag.Metadata{"repo_last_release": "2022-01-01"}})
+	_ = g.AddNode(dag.Node{ID: "sub", Kind: dag.NodeKindSubdivider, MasterID: "old"})
+
+	got := Freshness(g, now, BrittleThresholds{})
+	if len(got) != 2 || got[0].Package != "old" || got[1].Package != "new" {
+		t.Fatalf("got %+v, want old then new", got)
+	}
+}
+
+func TestIsBrittleWith_Thresholds(t *testing.T) {
+	eightMonthsAgo := time.Now().AddDate(0, -8, 0).Format("2006-01-02")
+	n := &dag.Node{ID: "pkg", Meta: dag.Metadata{
+		"repo_last_commit": eightMonthsAgo,
+		"repo_stars":       5000,
+		"repo_maintainers": []string{"a", "b", "c", "d"},
+	}}
+	if IsBrittleWith(n, BrittleThresholds{}) {
+		t.Error("eight months without commits should not be brittle by default")
+	}
+	if !IsBrittleWith(n, BrittleThresholds{Abandoned: 180 * 24 * time.Hour}) {
+		t.Error("expected brittle with a six-month abandonment threshold")
+	}
+}
@@ -0,0 +1,252 @@
+package feature
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
+)
+
+// MetaHealthScore is the dag.Node.Meta key [AnnotateHealth] stores each
+// package's 0–100 health score under, as an int.
+const MetaHealthScore = "health_score"
+
+// Health levels, from healthy to poor, as returned by [HealthLevel].
+const (
+	HealthGood    = "good"
+	HealthFair    = "fair"
+	HealthWeak    = "weak"
+	HealthPoor    = "poor"
+	HealthUnknown = "unknown"
+)
+
+// HealthLevels lists the health levels from best to worst, excluding
+// [HealthUnknown].
+var HealthLevels = []string{HealthGood, HealthFair, HealthWeak, HealthPoor}
+
+const (
+	healthStarsFull       = 10_000 // Stars for a full stars component
+	healthMaintainersFull = 5      // Maintainers for a full maintainers component
+	healthCadenceFresh    = 90 * 24 * time.Hour
+)
+
+// HealthConfig weights the components of [NodeHealth]. Weights are
+// relative: only their ratios matter, and components without data are left
+// out and the rest rescaled. A zero config means [DefaultHealth].
+type HealthConfig struct {
+	Stars       float64 `json:"stars,omitempty" yaml:"stars,omitempty"`
+	Maintainers float64 `json:"maintainers,omitempty" yaml:"maintainers,omitempty"`
+	Cadence     float64 `json:"cadence,omitempty" yaml:"cadence,omitempty"`
+	Vulns       float64 `json:"vulns,omitempty" yaml:"vulns,omitempty"`
+	Scorecard   float64 `json:"scorecard,omitempty" yaml:"scorecard,omitempty"`
+}
+
+// DefaultHealth returns the default weights: release cadence and known
+// vulnerabilities count most, followed by maintainers, stars and the
+// OpenSSF Scorecard.
+func DefaultHealth() HealthConfig {
+	return HealthConfig{Stars: 15, Maintainers: 20, Cadence: 25, Vulns: 25, Scorecard: 15}
+}
+
+// Validate reports negative weights. A zero weight drops its component.
+func (c HealthConfig) Validate() error {
+	for _, w := range []struct {
+		name string
+		v    float64
+	}{
+		{"stars", c.Stars}, {"maintainers", c.Maintainers}, {"cadence", c.Cadence},
+		{"vulns", c.Vulns}, {"scorecard", c.Scorecard},
+	} {
+		if w.v < 0 {
+			return fmt.Errorf("health weight %s must not be negative, got %g", w.name, w.v)
+		}
+	}
+	return nil
+}
+
+func (c HealthConfig) withDefaults() HealthConfig {
+	if c == (HealthConfig{}) {
+		return DefaultHealth()
+	}
+	return c
+}
+
+// PackageHealth is the health score of one package and its components.
+// Each component runs from 0 (worst) to 1 (best) and is nil without data.
+type PackageHealth struct {
+	Package string `json:"package"`
+	// Score runs from 0 to 100; Level buckets it. Packages without any
+	// repository data are [HealthUnknown] with a zero score.
+	Score int    `json:"score"`
+	Level string `json:"level"`
+
+	Stars       *float64 `json:"stars,omitempty"`
+	Maintainers *float64 `json:"maintainers,omitempty"`
+	Cadence     *float64 `json:"cadence,omitempty"`
+	Vulns       *float64 `json:"vulns,omitempty"`
+	Scorecard   *float64 `json:"scorecard,omitempty"`
+}
+
+// NodeHealth scores one package at time now as a weighted mean of:
+//
+//   - stars: log-scaled, full at 10,000
+//   - maintainers: full at 5 listed maintainers
+//   - cadence: full when the last release (or commit) is at most 90 days
+//     old, falling to zero at the abandonment threshold of t; archived
+//     repositories score zero
+//   - vulns: 1 without known advisories, down to 0 for a critical one
+//   - scorecard: the OpenSSF Scorecard score (0–10) when the graph carries
+//     one
+//
+// Vulnerabilities alone are not enough to score a package: without stars,
+// maintainers, activity dates or a scorecard it is [HealthUnknown]. So are
+// first-party packages (see [deps.IsInternal]), whose upkeep is the
+// project's own.
+func NodeHealth(n *dag.Node, cfg HealthConfig, now time.Time, t BrittleThresholds) PackageHealth {
+	h := PackageHealth{Package: n.ID, Level: HealthUnknown}
+	if n.Meta == nil || deps.IsInternal(n) {
+		return h
+	}
+	cfg = cfg.withDefaults()
+	t = t.withDefaults()
+
+	if _, ok := n.Meta[metadata.RepoStars]; ok {
+		h.Stars = ptr(min(1, math.Log10(1+float64(AsInt(n.Meta[metadata.RepoStars])))/math.Log10(healthStarsFull)))
+	}
+	if m := CountMaintainers(n.Meta[metadata.RepoMaintainers]); m > 0 {
+		h.Maintainers = ptr(float64(min(m, healthMaintainersFull)) / healthMaintainersFull)
+	}
+	last := ParseDate(n.Meta[metadata.RepoLastRelease])
+	if last.IsZero() {
+		last = ParseDate(n.Meta[metadata.RepoLastCommit])
+	}
+	if archived, _ := n.Meta[metadata.RepoArchived].(bool); archived {
+		h.Cadence = ptr(0)
+	} else if !last.IsZero() {
+		age := now.Sub(last)
+		span := float64(t.Abandoned - healthCadenceFresh)
+		h.Cadence = ptr(min(1, max(0, 1-float64(age-healthCadenceFresh)/span)))
+	}
+	if sc, ok := n.Meta[metadata.Scorecard]; ok {
+		h.Scorecard = ptr(min(1, max(0, asFloat(sc)/10)))
+	}
+	if h.Stars == nil && h.Maintainers == nil && h.Cadence == nil && h.Scorecard == nil {
+		return h
+	}
+	h.Vulns = ptr(vulnHealth(n.Meta["vuln_severity"]))
+
+	var sum, weight float64
+	for _, c := range []struct {
+		v *float64
+		w float64
+	}{
+		{h.Stars, cfg.Stars}, {h.Maintainers, cfg.Maintainers}, {h.Cadence, cfg.Cadence},
+		{h.Vulns, cfg.Vulns}, {h.Scorecard, cfg.Scorecard},
+	} {
+		if c.v != nil && c.w > 0 {
+			sum += *c.v * c.w
+			weight += c.w
+		}
+	}
+	if weight == 0 {
+		return h
+	}
+	h.Score = int(math.Round(100 * sum / weight))
+	h.Level = HealthLevel(h.Score)
+	return h
+}
+
+// HealthLevel buckets a 0–100 health score into one of [HealthLevels].
+func HealthLevel(score int) string {
+	switch {
+	case score >= 80:
+		return HealthGood
+	case score >= 60:
+		return HealthFair
+	case score >= 40:
+		return HealthWeak
+	default:
+		return HealthPoor
+	}
+}
+
+// AnnotateHealth scores every package in g and stores the result under
+// [MetaHealthScore], so styles, popups and CI checks all read the same
+// number. Packages that cannot be scored have any previous score removed.
+func AnnotateHealth(g *dag.DAG, cfg HealthConfig, now time.Time, t BrittleThresholds) {
+	for _, n := range g.Nodes() {
+		if n.IsSynthetic() || n.Meta == nil {
+			continue
+		}
+		if h := NodeHealth(n, cfg, now, t); h.Level != HealthUnknown {
+			n.Meta[MetaHealthScore] = h.Score
+		} else {
+			delete(n.Meta, MetaHealthScore)
+		}
+	}
+}
+
+// Health scores every package in the graph, least healthy first. Packages
+// that cannot be scored are left out.
+func Health(g *dag.DAG, cfg HealthConfig, now time.Time, t BrittleThresholds) []PackageHealth {
+	var out []PackageHealth
+	for _, n := range g.Nodes() {
+		if n.IsSynthetic() {
+			continue
+		}
+		if h := NodeHealth(n, cfg, now, t); h.Level != HealthUnknown {
+			out = append(out, h)
+		}
+	}
+	slices.SortFunc(out, func(a, b PackageHealth) int {
+		return cmp.Or(cmp.Compare(a.Score, b.Score), cmp.Compare(a.Package, b.Package))
+	})
+	return out
+}
+
+// HealthScore returns the stored health score of a package and whether it
+// has one.
+func HealthScore(n *dag.Node) (int, bool) {
+	if n == nil || n.Meta == nil {
+		return 0, false
+	}
+	v, ok := n.Meta[MetaHealthScore]
+	if !ok {
+		return 0, false
+	}
+	return AsInt(v), true
+}
+
+// vulnHealth maps the worst known advisory severity to a health component.
+func vulnHealth(v any) float64 {
+	switch sev, _ := v.(string); sev {
+	case "critical":
+		return 0
+	case "high":
+		return 0.25
+	case "medium":
+		return 0.5
+	case "low":
+		return 0.75
+	case "":
+		return 1
+	}
+	return 0.75
+}
+
+func asFloat(v any) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	}
+	return 0
+}
+
+func ptr(f float64) *float64 { return &f }
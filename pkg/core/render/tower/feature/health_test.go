@@ -0,0 +1,77 @@
+package feature
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestNodeHealth(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	healthy := dag.Metadata{
+		"repo_stars":        20000,
+		"repo_maintainers":  []string{"a", "b", "c", "d", "e"},
+		"repo_last_release": "2025-12-01",
+	}
+	cases := []struct {
+		name  string
+		meta  dag.Metadata
+		level string
+	}{
+		{"no data", dag.Metadata{"version": "1.0.0"}, HealthUnknown},
+		{"vulns alone", dag.Metadata{"vuln_severity": "high"}, HealthUnknown},
+		{"healthy", healthy, HealthGood},
+		{"first-party", with(healthy, "internal", true), HealthUnknown},
+		{"healthy but critical", with(healthy, "vuln_severity", "critical"), HealthFair},
+		{"archived solo project", dag.Metadata{"repo_archived": true, "repo_stars": 3, "repo_maintainers": []string{"a"}}, HealthPoor},
+		{"low scorecard", dag.Metadata{"scorecard": 1.5, "repo_stars": 0, "repo_last_commit": "2022-01-01"}, HealthPoor},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NodeHealth(&dag.Node{ID: "pkg", Meta: tc.meta}, HealthConfig{}, now, BrittleThresholds{})
+			if h.Level != tc.level {
+				t.Errorf("level = %s (score %d), want %s", h.Level, h.Score, tc.level)
+			}
+		})
+	}
+}
+
+func TestNodeHealth_Weights(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	n := &dag.Node{ID: "pkg", Meta: dag.Metadata{"repo_stars": 0, "repo_last_release": "2025-12-01"}}
+
+	if h := NodeHealth(n, HealthConfig{Stars: 1}, now, BrittleThresholds{}); h.Score != 0 {
+		t.Errorf("stars-only score = %d, want 0", h.Score)
+	}
+	if h := NodeHealth(n, HealthConfig{Cadence: 1}, now, BrittleThresholds{}); h.Score != 100 {
+		t.Errorf("cadence-only score = %d, want 100", h.Score)
+	}
+	if err := (HealthConfig{Vulns: -1}).Validate(); err == nil {
+		t.Error("negative weight should fail validation")
+	}
+}
+
+func TestAnnotateHealth(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "scored", Meta: dag.Metadata{"repo_stars": 100, "health_score": 1}})
+	_ = g.AddNode(dag.Node{ID: "bare", Meta: dag.Metadata{"health_score": 1}})
+
+	AnnotateHealth(g, HealthConfig{}, time.Now(), BrittleThresholds{})
+
+	scored, _ := g.Node("scored")
+	if s, ok := HealthScore(scored); !ok || s <= 1 {
+		t.Errorf("scored health = %d, %v; want a fresh score", s, ok)
+	}
+	bare, _ := g.Node("bare")
+	if _, ok := HealthScore(bare); ok {
+		t.Error("unscorable packages should lose a stale score")
+	}
+}
+
+func with(m dag.Metadata, key string, v any) dag.Metadata {
+	out := maps.Clone(m)
+	out[key] = v
+	return out
+}
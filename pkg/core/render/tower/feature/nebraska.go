@@ -24,8 +24,10 @@ type PackageRole struct {
 }
 
 type NebraskaRanking struct {
-	Maintainer string
+	Maintainer string // GitHub login, or organization when Org is set
 	Score      float64
+	Share      float64 // Fraction of the tower's total score
+	Org        bool
 	Packages   []PackageRole
 }
 
@@ -40,26 +42,34 @@ const (
 // on the "depth" of their packages in the tower (i.e., how many things
 // depend on them).
 func RankNebraska(g *dag.DAG, topN int) []NebraskaRanking {
+	return RankNebraskaWith(g, topN, DefaultScoring())
+}
+
+// RankNebraskaWith is [RankNebraska] with custom role weights, depth curve,
+// star damping and organization filters. With [GroupByOrg] it ranks the
+// organizations owning the repositories instead of individual maintainers.
+func RankNebraskaWith(g *dag.DAG, topN int, cfg ScoringConfig) []NebraskaRanking {
+	cfg = cfg.withDefaults()
 	scores := make(map[string]float64)
 	packages := make(map[string][]PackageRole)
 	bestRole := make(map[string]Role)
 	minRow := findMinRow(g)
 
 	for _, n := range g.Nodes() {
-		if n.IsSynthetic() || g.InDegree(n.ID) == 0 {
+		if n.IsSynthetic() || g.InDegree(n.ID) == 0 || !cfg.counts(n) {
 			continue
 		}
 
-		roles := getMaintainerRoles(n)
+		roles := cfg.entities(n)
 		if len(roles) == 0 {
 			continue
 		}
 
 		depth := n.Row - minRow
-		share := float64(depth) / float64(len(roles))
+		share := cfg.depthScore(depth) * cfg.starFactor(n) / float64(len(roles))
 
 		for maintainer, role := range roles {
-			scores[maintainer] += share * roleWeight(role)
+			scores[maintainer] += share * cfg.roleWeight(role)
 
 			if !hasPackage(packages[maintainer], n.ID) {
 				url, _ := n.Meta[metadata.RepoURL].(string)
@@ -77,6 +87,11 @@ func RankNebraska(g *dag.DAG, topN int) []NebraskaRanking {
 		}
 	}
 
+	total := 0.0
+	for _, score := range scores {
+		total += score
+	}
+
 	rankings := make([]NebraskaRanking, 0, len(scores))
 	for m, score := range scores {
 		pkgs := packages[m]
@@ -89,11 +104,16 @@ func RankNebraska(g *dag.DAG, topN int) []NebraskaRanking {
 			}
 			return cmp.Compare(a.Package, b.Package)
 		})
-		rankings = append(rankings, NebraskaRanking{
+		r := NebraskaRanking{
 			Maintainer: m,
 			Score:      score,
+			Org:        cfg.GroupBy == GroupByOrg,
 			Packages:   pkgs,
-		})
+		}
+		if total > 0 {
+			r.Share = score / total
+		}
+		rankings = append(rankings, r)
 	}
 
 	slices.SortFunc(rankings, func(a, b NebraskaRanking) int {
@@ -125,17 +145,6 @@ func roleRank(r Role) int {
 	}
 }
 
-func roleWeight(r Role) float64 {
-	switch r {
-	case RoleOwner:
-		return ownerWeight
-	case RoleLead:
-		return leadWeight
-	default:
-		return maintainerWeight
-	}
-}
-
 func findMinRow(g *dag.DAG) int {
 	minRow := -1
 	for _, n := range g.Nodes() {
@@ -195,3 +195,150 @@ func TestRankNebraska_NoMetadata(t *testing.T) {
 		t.Errorf("expected empty rankings, got %d", len(rankings))
 	}
 }
+
+func TestRankNebraskaWith_DefaultMatchesRankNebraska(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "root", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "pkg", Row: 2, Meta: dag.Metadata{
+		"repo_owner":       "alice",
+		"repo_maintainers": []string{"alice", "bob", "carol"},
+	}})
+	_ = g.AddEdge(dag.Edge{From: "root", To: "pkg"})
+
+	want := RankNebraska(g, 5)
+	got := RankNebraskaWith(g, 5, ScoringConfig{})
+	if len(got) != len(want) {
+		t.Fatalf("got %d rankings, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Maintainer != want[i].Maintainer || got[i].Score != want[i].Score {
+			t.Errorf("ranking %d = %s %.2f, want %s %.2f", i, got[i].Maintainer, got[i].Score, want[i].Maintainer, want[i].Score)
+		}
+	}
+}
+
+func TestRankNebraskaWith_RoleWeights(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "root", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "pkg", Row: 1, Meta: dag.Metadata{
+		"repo_maintainers": []string{"alice", "bob"},
+	}})
+	_ = g.AddEdge(dag.Edge{From: "root", To: "pkg"})
+
+	rankings := RankNebraskaWith(g, 5, ScoringConfig{LeadWeight: 1, MaintainerWeight: 2})
+	if rankings[0].Maintainer != "bob" {
+		t.Errorf("expected bob to rank first with a heavier maintainer weight, got %s", rankings[0].Maintainer)
+	}
+}
+
+func TestRankNebraskaWith_DepthCurve(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "root", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "lib", Row: 1, Meta: dag.Metadata{"repo_maintainers": []string{"alice"}}})
+	_ = g.AddNode(dag.Node{ID: "deep", Row: 4, Meta: dag.Metadata{"repo_maintainers": []string{"bob"}}})
+	_ = g.AddEdge(dag.Edge{From: "root", To: "lib"})
+	_ = g.AddEdge(dag.Edge{From: "lib", To: "deep"})
+
+	rankings := RankNebraskaWith(g, 5, ScoringConfig{DepthCurve: DepthFlat})
+	if len(rankings) != 2 || rankings[0].Score != rankings[1].Score {
+		t.Fatalf("flat curve should score every package the same, got %+v", rankings)
+	}
+
+	rankings = RankNebraskaWith(g, 5, ScoringConfig{DepthCurve: DepthLog})
+	if rankings[0].Maintainer != "bob" || rankings[0].Score >= 4*rankings[1].Score {
+		t.Errorf("log curve should keep order but shrink the gap, got %+v", rankings)
+	}
+}
+
+func TestRankNebraskaWith_StarDamping(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "root", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "popular", Row: 1, Meta: dag.Metadata{
+		"repo_maintainers": []string{"alice"}, "repo_stars": 50000,
+	}})
+	_ = g.AddNode(dag.Node{ID: "obscure", Row: 1, Meta: dag.Metadata{
+		"repo_maintainers": []string{"bob"}, "repo_stars": 3,
+	}})
+	_ = g.AddEdge(dag.Edge{From: "root", To: "popular"})
+	_ = g.AddEdge(dag.Edge{From: "root", To: "obscure"})
+
+	rankings := RankNebraskaWith(g, 5, ScoringConfig{StarDamping: 1})
+	if rankings[0].Maintainer != "bob" || rankings[0].Score <= rankings[1].Score {
+		t.Errorf("expected the obscure package's maintainer first, got %+v", rankings)
+	}
+}
+
+func TestRankNebraskaWith_OrgFilters(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "root", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "ours", Row: 1, Meta: dag.Metadata{
+		"repo_owner": "acme", "repo_maintainers": []string{"alice"},
+	}})
+	_ = g.AddNode(dag.Node{ID: "theirs", Row: 1, Meta: dag.Metadata{
+		"repo_owner": "other", "repo_maintainers": []string{"bob"},
+	}})
+	_ = g.AddEdge(dag.Edge{From: "root", To: "ours"})
+	_ = g.AddEdge(dag.Edge{From: "root", To: "theirs"})
+
+	excluded := RankNebraskaWith(g, 5, ScoringConfig{ExcludeOrgs: []string{"ACME"}})
+	if len(excluded) != 1 || excluded[0].Maintainer != "bob" {
+		t.Errorf("ExcludeOrgs: got %+v, want only bob", excluded)
+	}
+	included := RankNebraskaWith(g, 5, ScoringConfig{IncludeOrgs: []string{"acme"}})
+	if len(included) != 1 || included[0].Maintainer != "alice" {
+		t.Errorf("IncludeOrgs: got %+v, want only alice", included)
+	}
+
+	ours, _ := g.Node("ours")
+	ours.Meta["internal"] = true
+	if got := RankNebraska(g, 5); len(got) != 1 || got[0].Maintainer != "bob" {
+		t.Errorf("internal package: got %+v, want only bob", got)
+	}
+}
+
+func TestScoringConfig_Validate(t *testing.T) {
+	if err := DefaultScoring().Validate(); err != nil {
+		t.Errorf("default scoring: %v", err)
+	}
+	if err := (ScoringConfig{DepthCurve: "cubic"}).Validate(); err == nil {
+		t.Error("expected error for unknown depth curve")
+	}
+	if err := (ScoringConfig{OwnerWeight: -1}).Validate(); err == nil {
+		t.Error("expected error for negative weight")
+	}
+}
+
+func TestRankNebraskaWith_GroupByOrg(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "root", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "a", Row: 1, Meta: dag.Metadata{
+		"repo_owner": "bigco", "repo_maintainers": []string{"alice", "bob"},
+	}})
+	_ = g.AddNode(dag.Node{ID: "b", Row: 1, Meta: dag.Metadata{
+		"repo_owner": "bigco", "repo_maintainers": []string{"carol"},
+	}})
+	_ = g.AddNode(dag.Node{ID: "c", Row: 1, Meta: dag.Metadata{
+		"repo_owner": "solo-dev", "repo_maintainers": []string{"solo-dev"},
+	}})
+	_ = g.AddNode(dag.Node{ID: "unowned", Row: 1, Meta: dag.Metadata{
+		"repo_maintainers": []string{"dave"},
+	}})
+	for _, id := range []string{"a", "b", "c", "unowned"} {
+		_ = g.AddEdge(dag.Edge{From: "root", To: id})
+	}
+
+	rankings := RankNebraskaWith(g, 5, ScoringConfig{GroupBy: GroupByOrg})
+	if len(rankings) != 2 {
+		t.Fatalf("expected 2 orgs, got %+v", rankings)
+	}
+	top := rankings[0]
+	if top.Maintainer != "bigco" || !top.Org || len(top.Packages) != 2 {
+		t.Errorf("expected bigco with 2 packages first, got %+v", top)
+	}
+	if top.Share < 0.66 || top.Share > 0.67 {
+		t.Errorf("bigco share = %.3f, want 2/3", top.Share)
+	}
+	if top.Packages[0].Role != RoleOwner {
+		t.Errorf("org packages should carry the owner role, got %s", top.Packages[0].Role)
+	}
+}
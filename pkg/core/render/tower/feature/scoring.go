@@ -0,0 +1,189 @@
+package feature
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
+)
+
+// DepthCurve controls how a package's depth in the tower turns into score.
+type DepthCurve string
+
+const (
+	DepthLinear DepthCurve = "linear" // Score grows with depth (default)
+	DepthSqrt   DepthCurve = "sqrt"   // Diminishing returns for very deep packages
+	DepthLog    DepthCurve = "log"    // Strongly diminishing returns
+	DepthFlat   DepthCurve = "flat"   // Every non-root package counts the same
+)
+
+// Grouping selects who [RankNebraskaWith] ranks.
+type Grouping string
+
+const (
+	GroupByMaintainer Grouping = "maintainer" // Individual maintainers (default)
+	GroupByOrg        Grouping = "org"        // GitHub organizations / publishing accounts
+)
+
+// ScoringConfig tunes what a "critical maintainer" means for [RankNebraskaWith].
+// The zero value of a field falls back to the default used by [RankNebraska].
+type ScoringConfig struct {
+	// OwnerWeight, LeadWeight and MaintainerWeight multiply a maintainer's
+	// share of a package by their role (defaults 3, 1.5 and 1).
+	OwnerWeight      float64 `json:"owner_weight,omitempty" yaml:"owner_weight,omitempty"`
+	LeadWeight       float64 `json:"lead_weight,omitempty" yaml:"lead_weight,omitempty"`
+	MaintainerWeight float64 `json:"maintainer_weight,omitempty" yaml:"maintainer_weight,omitempty"`
+
+	// DepthCurve maps depth to score; empty means [DepthLinear].
+	DepthCurve DepthCurve `json:"depth_curve,omitempty" yaml:"depth_curve,omitempty"`
+
+	// StarDamping lowers the score of popular packages, whose large
+	// communities make them less likely to be abandoned. A package's score is
+	// divided by 1 + StarDamping*log10(1+stars); 0 disables damping.
+	StarDamping float64 `json:"star_damping,omitempty" yaml:"star_damping,omitempty"`
+
+	// IncludeOrgs, when set, only scores packages whose repository owner is
+	// listed. ExcludeOrgs skips packages owned by the listed organizations,
+	// e.g. your own. Both match case-insensitively. First-party packages
+	// (see [deps.IsInternal]) are never scored.
+	IncludeOrgs []string `json:"include_orgs,omitempty" yaml:"include_orgs,omitempty"`
+	ExcludeOrgs []string `json:"exclude_orgs,omitempty" yaml:"exclude_orgs,omitempty"`
+
+	// GroupBy ranks individual maintainers (default) or the organizations
+	// owning the repositories, answering "how much of my tower rests on a
+	// single company or foundation?".
+	GroupBy Grouping `json:"group_by,omitempty" yaml:"group_by,omitempty"`
+}
+
+// DefaultScoring returns the weights [RankNebraska] uses.
+func DefaultScoring() ScoringConfig {
+	return ScoringConfig{
+		OwnerWeight:      ownerWeight,
+		LeadWeight:       leadWeight,
+		MaintainerWeight: maintainerWeight,
+		DepthCurve:       DepthLinear,
+		GroupBy:          GroupByMaintainer,
+	}
+}
+
+// Validate reports an unknown depth curve or a negative weight.
+func (c ScoringConfig) Validate() error {
+	switch c.DepthCurve {
+	case "", DepthLinear, DepthSqrt, DepthLog, DepthFlat:
+	default:
+		return fmt.Errorf("unknown depth curve %q (want linear, sqrt, log or flat)", c.DepthCurve)
+	}
+	switch c.GroupBy {
+	case "", GroupByMaintainer, GroupByOrg:
+	default:
+		return fmt.Errorf("unknown grouping %q (want maintainer or org)", c.GroupBy)
+	}
+	for _, w := range []struct {
+		name  string
+		value float64
+	}{
+		{"owner_weight", c.OwnerWeight},
+		{"lead_weight", c.LeadWeight},
+		{"maintainer_weight", c.MaintainerWeight},
+		{"star_damping", c.StarDamping},
+	} {
+		if w.value < 0 {
+			return fmt.Errorf("%s must not be negative, got %g", w.name, w.value)
+		}
+	}
+	return nil
+}
+
+func (c ScoringConfig) withDefaults() ScoringConfig {
+	d := DefaultScoring()
+	if c.OwnerWeight == 0 {
+		c.OwnerWeight = d.OwnerWeight
+	}
+	if c.LeadWeight == 0 {
+		c.LeadWeight = d.LeadWeight
+	}
+	if c.MaintainerWeight == 0 {
+		c.MaintainerWeight = d.MaintainerWeight
+	}
+	if c.DepthCurve == "" {
+		c.DepthCurve = d.DepthCurve
+	}
+	if c.GroupBy == "" {
+		c.GroupBy = d.GroupBy
+	}
+	return c
+}
+
+func (c ScoringConfig) roleWeight(r Role) float64 {
+	switch r {
+	case RoleOwner:
+		return c.OwnerWeight
+	case RoleLead:
+		return c.LeadWeight
+	default:
+		return c.MaintainerWeight
+	}
+}
+
+func (c ScoringConfig) depthScore(depth int) float64 {
+	d := float64(max(0, depth))
+	switch c.DepthCurve {
+	case DepthSqrt:
+		return math.Sqrt(d)
+	case DepthLog:
+		return math.Log2(1 + d)
+	case DepthFlat:
+		if d > 0 {
+			return 1
+		}
+		return 0
+	default:
+		return d
+	}
+}
+
+func (c ScoringConfig) starFactor(n *dag.Node) float64 {
+	if c.StarDamping <= 0 {
+		return 1
+	}
+	stars := AsInt(n.Meta[metadata.RepoStars])
+	if stars <= 0 {
+		return 1
+	}
+	return 1 / (1 + c.StarDamping*math.Log10(1+float64(stars)))
+}
+
+// entities returns who gets credit for a package: its maintainers with their
+// roles, or its owning organization.
+func (c ScoringConfig) entities(n *dag.Node) map[string]Role {
+	if c.GroupBy != GroupByOrg {
+		return getMaintainerRoles(n)
+	}
+	if owner, _ := n.Meta[metadata.RepoOwner].(string); owner != "" {
+		return map[string]Role{owner: RoleOwner}
+	}
+	return nil
+}
+
+// counts reports whether a package is external and passes the organization
+// filters.
+func (c ScoringConfig) counts(n *dag.Node) bool {
+	if deps.IsInternal(n) {
+		return false
+	}
+	if len(c.IncludeOrgs) == 0 && len(c.ExcludeOrgs) == 0 {
+		return true
+	}
+	owner, _ := n.Meta[metadata.RepoOwner].(string)
+	match := func(orgs []string) bool {
+		return slices.ContainsFunc(orgs, func(o string) bool { return strings.EqualFold(o, owner) })
+	}
+	if len(c.IncludeOrgs) > 0 && !match(c.IncludeOrgs) {
+		return false
+	}
+	return !match(c.ExcludeOrgs)
+}
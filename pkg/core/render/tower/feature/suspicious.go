@@ -0,0 +1,320 @@
+package feature
+
+import (
+	"cmp"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
+)
+
+// Reasons a package is flagged by [NodeSuspicion].
+const (
+	// SuspicionTyposquat marks a package whose name is one edit away from a
+	// popular package.
+	SuspicionTyposquat = "typosquat"
+	// SuspicionYoung marks a package whose repository is only a few weeks
+	// old and that has barely any downloads or stars.
+	SuspicionYoung = "young"
+	// SuspicionRepoMismatch marks a package whose source repository shares
+	// nothing with its name.
+	SuspicionRepoMismatch = "repo-mismatch"
+)
+
+const (
+	popularDownloads = 1_000_000
+	youngAge         = 90 * 24 * time.Hour
+	youngDownloads   = 1000
+	youngStars       = 10
+	minTyposquatLen  = 4
+)
+
+// Suspicion describes why a package looks like a supply-chain risk. These
+// are cheap heuristics meant to prompt a second look, not verdicts.
+type Suspicion struct {
+	Package string   `json:"package"`
+	Reasons []string `json:"reasons"`
+	// Resembles is the popular package a typosquat candidate is one edit
+	// away from.
+	Resembles string `json:"resembles,omitempty"`
+	// Details holds one human-readable line per reason.
+	Details []string `json:"details"`
+}
+
+// popularPackages lists widely used packages per language, the names
+// typosquatters most often imitate. Graph nodes with at least a million
+// downloads are treated as popular too.
+var popularPackages = map[string][]string{
+	"python": {
+		"requests", "urllib3", "numpy", "pandas", "setuptools", "certifi",
+		"django", "flask", "boto3", "botocore", "pyyaml", "jinja2", "click",
+		"cryptography", "pytest", "scipy", "matplotlib", "pillow", "colorama",
+		"python-dateutil", "attrs", "pydantic", "sqlalchemy", "beautifulsoup4",
+		"tensorflow", "torch", "fastapi", "uvicorn", "httpx", "aiohttp",
+		"pyjwt", "paramiko", "selenium", "openai", "scikit-learn", "six",
+		"idna", "packaging", "typing-extensions", "wheel", "virtualenv",
+	},
+	"javascript": {
+		"react", "react-dom", "lodash", "express", "axios", "chalk",
+		"commander", "debug", "moment", "webpack", "typescript", "vue",
+		"angular", "jquery", "request", "dotenv", "eslint", "prettier",
+		"jest", "mocha", "babel-core", "yargs", "uuid", "async", "bluebird",
+		"underscore", "minimist", "glob", "semver", "colors", "cross-env",
+		"electron", "next", "socket.io", "mongoose", "body-parser", "rxjs",
+		"tslib", "inquirer", "node-fetch", "ws",
+	},
+	"rust": {
+		"serde", "serde_json", "tokio", "rand", "clap", "syn", "quote",
+		"proc-macro2", "libc", "log", "regex", "anyhow", "thiserror",
+		"reqwest", "hyper", "futures", "chrono", "lazy_static", "bitflags",
+		"itertools", "once_cell", "base64", "tracing", "bytes", "uuid",
+	},
+	"ruby": {
+		"rails", "rack", "rake", "bundler", "nokogiri", "json", "rspec",
+		"activesupport", "activerecord", "thor", "puma", "sinatra", "devise",
+		"sidekiq", "faraday", "rest-client", "aws-sdk", "rubocop", "pry",
+	},
+	"go": {
+		"github.com/spf13/cobra", "github.com/spf13/viper",
+		"github.com/sirupsen/logrus", "github.com/gin-gonic/gin",
+		"github.com/stretchr/testify", "github.com/gorilla/mux",
+		"github.com/pkg/errors", "github.com/google/uuid",
+		"go.uber.org/zap", "gopkg.in/yaml.v3", "github.com/golang/protobuf",
+	},
+	"php": {
+		"laravel/framework", "symfony/console", "guzzlehttp/guzzle",
+		"monolog/monolog", "phpunit/phpunit", "doctrine/orm",
+		"symfony/http-foundation", "nesbot/carbon", "league/flysystem",
+	},
+	"java": {
+		"com.google.guava:guava", "org.apache.commons:commons-lang3",
+		"com.fasterxml.jackson.core:jackson-databind", "junit:junit",
+		"org.slf4j:slf4j-api", "org.springframework:spring-core",
+		"org.apache.logging.log4j:log4j-core", "com.google.code.gson:gson",
+		"org.projectlombok:lombok", "org.mockito:mockito-core",
+	},
+	"dotnet": {
+		"newtonsoft.json", "serilog", "automapper", "dapper", "polly",
+		"xunit", "nunit", "moq", "fluentvalidation", "mediatr",
+		"microsoft.extensions.logging", "system.text.json",
+		"swashbuckle.aspnetcore", "microsoft.entityframeworkcore",
+	},
+}
+
+// PopularPackages returns the built-in popular package names for a language,
+// or the names of every language when lang is empty or unknown.
+func PopularPackages(lang string) []string {
+	if names, ok := popularPackages[lang]; ok {
+		return slices.Clone(names)
+	}
+	var all []string
+	for _, names := range popularPackages {
+		all = append(all, names...)
+	}
+	slices.Sort(all)
+	return all
+}
+
+// Suspicious runs [NodeSuspicion] over every package in the graph and
+// returns the flagged ones sorted by package name. The language comparison
+// set comes from the graph's "language" metadata; now anchors the age
+// check.
+func Suspicious(g *dag.DAG, now time.Time) []Suspicion {
+	lang, _ := g.Meta()["language"].(string)
+	popular := make(map[string]string)
+	for _, name := range PopularPackages(lang) {
+		popular[normalizeName(lang, name)] = name
+	}
+	for _, n := range g.Nodes() {
+		if AsInt(n.Meta["downloads"]) >= popularDownloads {
+			popular[normalizeName(lang, n.ID)] = n.ID
+		}
+	}
+
+	var out []Suspicion
+	for _, n := range g.Nodes() {
+		if n.IsSynthetic() {
+			continue
+		}
+		if s := NodeSuspicion(n, lang, popular, now); len(s.Reasons) > 0 {
+			out = append(out, s)
+		}
+	}
+	slices.SortFunc(out, func(a, b Suspicion) int { return cmp.Compare(a.Package, b.Package) })
+	return out
+}
+
+// NodeSuspicion checks one package against the supply-chain heuristics:
+//
+//   - typosquat: the name is one insertion, deletion, substitution or
+//     transposition away from a name in popular (keyed by normalized name,
+//     as built by [Suspicious]); very short and popular names are skipped.
+//   - young: the repository was created within 90 days of now and the
+//     package has under 1,000 downloads, or under 10 stars when the
+//     registry reports no downloads.
+//   - repo-mismatch: neither the repository owner nor its name shares a
+//     word with the package name.
+//
+// A package that matches nothing has no Reasons.
+func NodeSuspicion(n *dag.Node, lang string, popular map[string]string, now time.Time) Suspicion {
+	s := Suspicion{Package: n.ID}
+	downloads := AsInt(n.Meta["downloads"])
+
+	name := normalizeName(lang, n.ID)
+	if _, ok := popular[name]; !ok && downloads < popularDownloads && len(name) >= minTyposquatLen {
+		if target := closestPopular(name, popular); target != "" {
+			s.Reasons = append(s.Reasons, SuspicionTyposquat)
+			s.Resembles = target
+			s.Details = append(s.Details, "name is one edit away from "+target)
+		}
+	}
+
+	if created := ParseDate(n.Meta[metadata.RepoCreated]); !created.IsZero() && now.Sub(created) < youngAge {
+		stars := AsInt(n.Meta[metadata.RepoStars])
+		if (downloads > 0 && downloads < youngDownloads) || (downloads == 0 && stars < youngStars) {
+			s.Reasons = append(s.Reasons, SuspicionYoung)
+			s.Details = append(s.Details, "repository created "+created.Format("2006-01-02")+" with little adoption")
+		}
+	}
+
+	if repoURL, _ := n.Meta[metadata.RepoURL].(string); repoURL != "" {
+		if owner, repo := repoParts(repoURL); repo != "" && !namesRelated(n.ID, owner, repo) {
+			s.Reasons = append(s.Reasons, SuspicionRepoMismatch)
+			s.Details = append(s.Details, "repository "+owner+"/"+repo+" does not match the package name")
+		}
+	}
+	return s
+}
+
+var pep503 = regexp.MustCompile(`[-_.]+`)
+
+// normalizeName folds case and, for Python, applies PEP 503 normalization
+// so that "Typing_Extensions" and "typing-extensions" compare equal.
+func normalizeName(lang, name string) string {
+	name = strings.ToLower(name)
+	if lang == "python" {
+		name = pep503.ReplaceAllString(name, "-")
+	}
+	return name
+}
+
+// closestPopular returns the popular package that name is one edit away
+// from, or "" if there is none. Ties resolve to the alphabetically first
+// candidate.
+func closestPopular(name string, popular map[string]string) string {
+	var best string
+	for norm, orig := range popular {
+		if oneEdit(name, norm) && (best == "" || orig < best) {
+			best = orig
+		}
+	}
+	return best
+}
+
+// oneEdit reports whether a and b are exactly one insertion, deletion,
+// substitution or adjacent transposition apart.
+func oneEdit(a, b string) bool {
+	if a == b {
+		return false
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	switch len(b) - len(a) {
+	case 0:
+		i := 0
+		for i < len(a) && a[i] == b[i] {
+			i++
+		}
+		if a[i+1:] == b[i+1:] {
+			return true
+		}
+		return i+1 < len(a) && a[i] == b[i+1] && a[i+1] == b[i] && a[i+2:] == b[i+2:]
+	case 1:
+		i := 0
+		for i < len(a) && a[i] == b[i] {
+			i++
+		}
+		return a[i:] == b[i+1:]
+	}
+	return false
+}
+
+// repoParts extracts the owner and repository name from a repository URL
+// such as "https://github.com/owner/repo.git".
+func repoParts(repoURL string) (owner, repo string) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return "", ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 {
+		return "", ""
+	}
+	return strings.ToLower(parts[0]), strings.ToLower(strings.TrimSuffix(parts[1], ".git"))
+}
+
+// Ecosystem affixes that commonly differ between a package and its
+// repository, e.g. "requests" in "psf/python-requests".
+var (
+	namePrefixes = []string{"python-", "py-", "node-", "go-", "rust-", "ruby-", "php-"}
+	nameSuffixes = []string{"-python", "-py", "-js", ".js", "-node", "-go", "-rs", "-rust", "-ruby", "-php"}
+)
+
+// namesRelated reports whether a package name plausibly belongs to the
+// repository owner/repo: one of the package's name segments (npm scope,
+// Maven group and artifact, path elements) contains or is contained in the
+// owner or repository name once ecosystem affixes and separators are
+// dropped, or they share a word of at least three letters.
+func namesRelated(pkg, owner, repo string) bool {
+	targets := []string{squash(owner), squash(repo)}
+	targetWords := append(words(owner), words(repo)...)
+	for _, seg := range strings.FieldsFunc(strings.ToLower(pkg), func(r rune) bool {
+		return r == '/' || r == ':' || r == '@'
+	}) {
+		s := squash(seg)
+		if s == "" {
+			continue
+		}
+		for _, t := range targets {
+			if t != "" && (strings.Contains(s, t) || strings.Contains(t, s)) {
+				return true
+			}
+		}
+		for _, w := range words(seg) {
+			if slices.Contains(targetWords, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// squash drops ecosystem affixes and separators from a name.
+func squash(name string) string {
+	name = strings.ToLower(name)
+	for _, p := range namePrefixes {
+		name = strings.TrimPrefix(name, p)
+	}
+	for _, s := range nameSuffixes {
+		name = strings.TrimSuffix(name, s)
+	}
+	return strings.NewReplacer("-", "", "_", "", ".", "").Replace(name)
+}
+
+// words splits a name into its lowercase words of at least three letters.
+func words(name string) []string {
+	var out []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	}) {
+		if len(w) >= 3 {
+			out = append(out, w)
+		}
+	}
+	return out
+}
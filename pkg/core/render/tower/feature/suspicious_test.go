@@ -0,0 +1,75 @@
+package feature
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestOneEdit(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"requests", "requests", false},
+		{"requests", "reqeusts", true},  // transposition
+		{"requests", "request", true},   // deletion
+		{"requests", "requestss", true}, // insertion
+		{"requests", "requasts", true},  // substitution
+		{"requests", "reqests1", false},
+		{"lodash", "lodahs", true},
+		{"lodash", "react", false},
+	}
+	for _, tc := range cases {
+		if got := oneEdit(tc.a, tc.b); got != tc.want {
+			t.Errorf("oneEdit(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestSuspicious(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	g := dag.New(dag.Metadata{"language": "python"})
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "requests", Meta: dag.Metadata{"repo_url": "https://github.com/psf/requests"}})
+	_ = g.AddNode(dag.Node{ID: "reqeusts", Meta: dag.Metadata{"repo_url": "https://github.com/evil/reqeusts"}})
+	_ = g.AddNode(dag.Node{ID: "PyYAML"})
+	_ = g.AddNode(dag.Node{ID: "fresh-lib", Meta: dag.Metadata{"repo_created": "2025-12-01", "repo_stars": 2, "repo_url": "https://github.com/someone/fresh-lib"}})
+	_ = g.AddNode(dag.Node{ID: "popular-young", Meta: dag.Metadata{"repo_created": "2025-12-01", "downloads": 50000}})
+	_ = g.AddNode(dag.Node{ID: "colorful", Meta: dag.Metadata{"repo_url": "https://github.com/acme/widgets"}})
+	_ = g.AddNode(dag.Node{ID: "Typing_Extensions", Meta: dag.Metadata{"repo_url": "https://github.com/python/typing_extensions"}})
+	_ = g.AddNode(dag.Node{ID: "attrs", Meta: dag.Metadata{"repo_url": "https://github.com/python-attrs/attrs"}})
+
+	got := make(map[string]Suspicion)
+	for _, s := range Suspicious(g, now) {
+		got[s.Package] = s
+	}
+
+	if s := got["reqeusts"]; !slices.Contains(s.Reasons, SuspicionTyposquat) || s.Resembles != "requests" {
+		t.Errorf("reqeusts = %+v, want typosquat of requests", s)
+	}
+	if s := got["fresh-lib"]; !slices.Equal(s.Reasons, []string{SuspicionYoung}) {
+		t.Errorf("fresh-lib reasons = %v, want [young]", s.Reasons)
+	}
+	if s := got["colorful"]; !slices.Equal(s.Reasons, []string{SuspicionRepoMismatch}) {
+		t.Errorf("colorful reasons = %v, want [repo-mismatch]", s.Reasons)
+	}
+	for _, id := range []string{"app", "requests", "PyYAML", "popular-young", "Typing_Extensions", "attrs"} {
+		if s, ok := got[id]; ok {
+			t.Errorf("%s flagged: %+v", id, s)
+		}
+	}
+}
+
+func TestSuspicious_DownloadsMakePopular(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "internal-core", Meta: dag.Metadata{"downloads": 5_000_000}})
+	_ = g.AddNode(dag.Node{ID: "internal-cor3"})
+
+	got := Suspicious(g, time.Now())
+	if len(got) != 1 || got[0].Package != "internal-cor3" || got[0].Resembles != "internal-core" {
+		t.Errorf("Suspicious = %+v, want internal-cor3 resembling internal-core", got)
+	}
+}
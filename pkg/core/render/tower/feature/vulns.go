@@ -0,0 +1,155 @@
+package feature
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+// Node metadata keys written by security.AnnotateGraph. They are repeated
+// here because the security package depends on this one through pkg/graph.
+const (
+	metaVulnSeverity = "vuln_severity"
+	metaVulnFindings = "vuln_findings"
+)
+
+// Vulnerability is one advisory affecting a package in the graph.
+type Vulnerability struct {
+	Package      string
+	Version      string
+	ID           string // Advisory ID; empty when only the severity is known
+	Severity     string // critical, high, medium, low or unknown
+	Summary      string
+	FixedVersion string   // Lowest version that fixes it; empty if none is published
+	Path         []string // Shortest dependency chain from a root to Package
+}
+
+// Vulnerabilities lists every advisory recorded on the graph, most urgent
+// first: by severity, then by how close the package sits to a root (direct
+// dependencies are the easiest to upgrade), then by package and ID.
+//
+// It reads the annotations left by a vulnerability scan. Nodes that only
+// carry a severity, as in graphs scanned by older versions, yield a single
+// entry without an ID.
+func Vulnerabilities(g *dag.DAG) []Vulnerability {
+	paths := shortestPaths(g)
+
+	var vulns []Vulnerability
+	for _, n := range g.Nodes() {
+		if n.IsSynthetic() || n.Meta == nil {
+			continue
+		}
+		sev, _ := n.Meta[metaVulnSeverity].(string)
+		if sev == "" {
+			continue
+		}
+		version, _ := n.Meta["version"].(string)
+		base := Vulnerability{Package: n.ID, Version: version, Severity: sev, Path: paths[n.ID]}
+
+		findings := getMapSlice(n.Meta[metaVulnFindings])
+		if len(findings) == 0 {
+			vulns = append(vulns, base)
+			continue
+		}
+		for _, f := range findings {
+			v := base
+			v.ID, _ = f["id"].(string)
+			v.Summary, _ = f["summary"].(string)
+			v.FixedVersion, _ = f["fixed"].(string)
+			if s, _ := f["severity"].(string); s != "" {
+				v.Severity = s
+			}
+			vulns = append(vulns, v)
+		}
+	}
+
+	slices.SortFunc(vulns, func(a, b Vulnerability) int {
+		if c := cmp.Compare(severityWeight(b.Severity), severityWeight(a.Severity)); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(len(a.Path), len(b.Path)); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Package, b.Package); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return vulns
+}
+
+// shortestPaths returns, for every package reachable from a root, the
+// shortest chain of package IDs leading to it. Subdividers are folded into
+// their master package and auxiliary nodes are skipped, so paths read the
+// same on a transformed graph as on the original.
+func shortestPaths(g *dag.DAG) map[string][]string {
+	parent := make(map[string]string)
+	var queue []string
+	for _, n := range g.Nodes() {
+		if !n.IsSynthetic() && g.InDegree(n.ID) == 0 {
+			parent[n.ID] = ""
+			queue = append(queue, n.ID)
+		}
+	}
+	slices.Sort(queue)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, child := range g.Children(id) {
+			if _, seen := parent[child]; !seen {
+				parent[child] = id
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	paths := make(map[string][]string, len(parent))
+	for id := range parent {
+		var path []string
+		for cur := id; cur != ""; cur = parent[cur] {
+			n, _ := g.Node(cur)
+			if n == nil || n.IsAuxiliary() {
+				continue
+			}
+			pkg := n.EffectiveID()
+			if len(path) == 0 || path[len(path)-1] != pkg {
+				path = append(path, pkg)
+			}
+		}
+		slices.Reverse(path)
+		paths[id] = path
+	}
+	return paths
+}
+
+func severityWeight(s string) int {
+	switch s {
+	case "critical":
+		return 4
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	case "low":
+		return 1
+	default:
+		return 0
+	}
+}
+
+func getMapSlice(v any) []map[string]any {
+	switch v := v.(type) {
+	case []map[string]any:
+		return v
+	case []any:
+		out := make([]map[string]any, 0, len(v))
+		for _, item := range v {
+			if m, ok := item.(map[string]any); ok {
+				out = append(out, m)
+			}
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package feature
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func vulnGraph() *dag.DAG {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "web", Row: 1})
+	_ = g.AddNode(dag.Node{ID: "yaml", Row: 1, Meta: dag.Metadata{
+		"version":       "5.3",
+		"vuln_severity": "medium",
+		"vuln_findings": []any{map[string]any{"id": "GHSA-yaml", "severity": "medium", "fixed": "5.4"}},
+	}})
+	_ = g.AddNode(dag.Node{ID: "crypto", Row: 2, Meta: dag.Metadata{
+		"version":       "1.0.0",
+		"vuln_severity": "critical",
+		"vuln_findings": []any{
+			map[string]any{"id": "GHSA-low", "severity": "low"},
+			map[string]any{"id": "GHSA-rce", "severity": "critical", "fixed": "1.0.2", "summary": "RCE"},
+		},
+	}})
+	_ = g.AddNode(dag.Node{ID: "legacy", Row: 2, Meta: dag.Metadata{"vuln_severity": "medium"}})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "web"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "yaml"})
+	_ = g.AddEdge(dag.Edge{From: "web", To: "crypto"})
+	_ = g.AddEdge(dag.Edge{From: "web", To: "legacy"})
+	return g
+}
+
+func TestVulnerabilities_Ranked(t *testing.T) {
+	vulns := Vulnerabilities(vulnGraph())
+
+	var got []string
+	for _, v := range vulns {
+		got = append(got, v.Package+"/"+v.ID)
+	}
+	// Severity first; among the mediums, yaml is a direct dependency.
+	want := []string{"crypto/GHSA-rce", "yaml/GHSA-yaml", "legacy/", "crypto/GHSA-low"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("order = %v, want %v", got, want)
+	}
+
+	top := vulns[0]
+	if top.Severity != "critical" || top.FixedVersion != "1.0.2" || top.Version != "1.0.0" || top.Summary != "RCE" {
+		t.Errorf("unexpected top finding: %+v", top)
+	}
+	if !slices.Equal(top.Path, []string{"app", "web", "crypto"}) {
+		t.Errorf("path = %v, want app > web > crypto", top.Path)
+	}
+}
+
+func TestVulnerabilities_SurvivesJSON(t *testing.T) {
+	g := vulnGraph()
+	n, _ := g.Node("crypto")
+	data, err := json.Marshal(n.Meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.Meta = nil
+	if err := json.Unmarshal(data, &n.Meta); err != nil {
+		t.Fatal(err)
+	}
+	if got := Vulnerabilities(g); len(got) != 4 || got[0].ID != "GHSA-rce" {
+		t.Errorf("findings lost in a JSON round trip: %+v", got)
+	}
+}
+
+func TestVulnerabilities_PathThroughSubdividers(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "app_sub_1", Row: 1, Kind: dag.NodeKindSubdivider, MasterID: "app"})
+	_ = g.AddNode(dag.Node{ID: "lib", Row: 2, Meta: dag.Metadata{"vuln_severity": "high"}})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "app_sub_1"})
+	_ = g.AddEdge(dag.Edge{From: "app_sub_1", To: "lib"})
+
+	vulns := Vulnerabilities(g)
+	if len(vulns) != 1 || !slices.Equal(vulns[0].Path, []string{"app", "lib"}) {
+		t.Errorf("subdividers should fold into their master: %+v", vulns)
+	}
+}
@@ -0,0 +1,131 @@
+package feature
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+// DirectWeight attributes part of the tower to one direct dependency of the
+// root: what it pulls in, and what would go away if it were cut.
+type DirectWeight struct {
+	Package string `json:"package"`
+
+	// Transitive counts the packages reachable below the dependency, not
+	// counting itself.
+	Transitive int `json:"transitive"`
+
+	// Exclusive counts the packages, itself included, that nothing else
+	// pulls in: dropping the dependency removes exactly these.
+	Exclusive int `json:"exclusive"`
+
+	// Share is the dependency and its transitive packages as a fraction of
+	// all dependencies; ExclusiveShare is the same for Exclusive. Shares of
+	// different direct dependencies overlap, ExclusiveShares do not.
+	Share          float64 `json:"share"`
+	ExclusiveShare float64 `json:"exclusive_share"`
+}
+
+// Weights attributes the tower to the root's direct dependencies, heaviest
+// first. The root is the package nothing depends on, or the synthetic
+// "__project__" node of a manifest graph. Subdividers are walked through
+// and never counted. A graph without a root yields nil.
+func Weights(g *dag.DAG) []DirectWeight {
+	root := weightRoot(g)
+	if root == "" {
+		return nil
+	}
+	all := reachable(g, root, "")
+	total := len(all)
+	if total == 0 {
+		return nil
+	}
+
+	var out []DirectWeight
+	for _, d := range g.RealChildren(root) {
+		below := reachable(g, d, "")
+		kept := reachable(g, root, d)
+		w := DirectWeight{
+			Package:    d,
+			Transitive: len(below),
+			Exclusive:  total - len(kept),
+		}
+		w.Share = float64(w.Transitive+1) / float64(total)
+		w.ExclusiveShare = float64(w.Exclusive) / float64(total)
+		out = append(out, w)
+	}
+	slices.SortFunc(out, func(a, b DirectWeight) int {
+		return cmp.Or(cmp.Compare(b.Transitive, a.Transitive), cmp.Compare(b.Exclusive, a.Exclusive), cmp.Compare(a.Package, b.Package))
+	})
+	return out
+}
+
+// WeightsByPackage indexes [Weights] by package ID, for per-block lookups
+// such as popups.
+func WeightsByPackage(g *dag.DAG) map[string]DirectWeight {
+	ws := Weights(g)
+	if len(ws) == 0 {
+		return nil
+	}
+	m := make(map[string]DirectWeight, len(ws))
+	for _, w := range ws {
+		m[w.Package] = w
+	}
+	return m
+}
+
+// PulledInBy returns the root's direct dependencies that pull id into the
+// tower, sorted: id itself when it is one, and every direct dependency it
+// is reachable from. Removing all of them drops id; a single one is the
+// dependency to cut. The root, unknown IDs and a graph without a root
+// yield nil.
+func PulledInBy(g *dag.DAG, id string) []string {
+	root := weightRoot(g)
+	if root == "" || id == root {
+		return nil
+	}
+	if _, ok := g.Node(id); !ok {
+		return nil
+	}
+	var out []string
+	for _, d := range g.RealChildren(root) {
+		if d == id || reachable(g, d, "")[id] {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func weightRoot(g *dag.DAG) string {
+	if root := dag.FindRoot(g); root != "" {
+		return root
+	}
+	if _, ok := g.Node("__project__"); ok && g.InDegree("__project__") == 0 {
+		return "__project__"
+	}
+	return ""
+}
+
+// reachable returns the non-synthetic packages below from, never entering
+// skip. from itself is not included.
+func reachable(g *dag.DAG, from, skip string) map[string]bool {
+	seen := map[string]bool{from: true}
+	out := make(map[string]bool)
+	stack := []string{from}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, c := range g.Children(id) {
+			if c == skip || seen[c] {
+				continue
+			}
+			seen[c] = true
+			stack = append(stack, c)
+			if n, ok := g.Node(c); ok && !n.IsSynthetic() && c != "__project__" {
+				out[c] = true
+			}
+		}
+	}
+	return out
+}
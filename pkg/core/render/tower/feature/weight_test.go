@@ -0,0 +1,91 @@
+package feature
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestWeights(t *testing.T) {
+	// app -> web -> {router, templates -> markup}
+	// app -> cli -> {markup, colors}
+	g := dag.New(nil)
+	for _, id := range []string{"app", "web", "cli", "router", "templates", "markup", "colors"} {
+		_ = g.AddNode(dag.Node{ID: id})
+	}
+	for _, e := range [][2]string{
+		{"app", "web"}, {"app", "cli"},
+		{"web", "router"}, {"web", "templates"}, {"templates", "markup"},
+		{"cli", "markup"}, {"cli", "colors"},
+	} {
+		_ = g.AddEdge(dag.Edge{From: e[0], To: e[1]})
+	}
+
+	ws := Weights(g)
+	if len(ws) != 2 {
+		t.Fatalf("got %d weights, want 2", len(ws))
+	}
+	web, cli := ws[0], ws[1]
+	if web.Package != "web" || web.Transitive != 3 || web.Exclusive != 3 {
+		t.Errorf("web = %+v, want 3 transitive, 3 exclusive", web)
+	}
+	if cli.Package != "cli" || cli.Transitive != 2 || cli.Exclusive != 2 {
+		t.Errorf("cli = %+v, want 2 transitive, 2 exclusive (markup is shared)", cli)
+	}
+	if math.Abs(web.Share-4.0/6) > 1e-9 || math.Abs(cli.ExclusiveShare-2.0/6) > 1e-9 {
+		t.Errorf("shares = %v, %v", web.Share, cli.ExclusiveShare)
+	}
+}
+
+func TestWeights_Subdividers(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "app_sub_1", Row: 1, Kind: dag.NodeKindSubdivider, MasterID: "app"})
+	_ = g.AddNode(dag.Node{ID: "lib", Row: 2})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "app_sub_1"})
+	_ = g.AddEdge(dag.Edge{From: "app_sub_1", To: "lib"})
+
+	ws := Weights(g)
+	if len(ws) != 1 || ws[0].Package != "lib" || ws[0].Transitive != 0 || ws[0].Share != 1 {
+		t.Errorf("Weights = %+v, want lib with the whole tower", ws)
+	}
+	if Weights(dag.New(nil)) != nil {
+		t.Error("empty graph should have no weights")
+	}
+}
+
+func TestPulledInBy(t *testing.T) {
+	// app -> web -> templates -> markup
+	// app -> cli -> markup
+	// app -> markup
+	g := dag.New(nil)
+	for _, id := range []string{"app", "web", "cli", "templates", "markup", "colors"} {
+		_ = g.AddNode(dag.Node{ID: id})
+	}
+	for _, e := range [][2]string{
+		{"app", "web"}, {"app", "cli"}, {"app", "markup"},
+		{"web", "templates"}, {"templates", "markup"},
+		{"cli", "markup"}, {"cli", "colors"},
+	} {
+		_ = g.AddEdge(dag.Edge{From: e[0], To: e[1]})
+	}
+
+	tests := []struct {
+		id   string
+		want []string
+	}{
+		{"templates", []string{"web"}},
+		{"colors", []string{"cli"}},
+		{"web", []string{"web"}},
+		{"markup", []string{"cli", "markup", "web"}},
+		{"app", nil},
+		{"missing", nil},
+	}
+	for _, tt := range tests {
+		if got := PulledInBy(g, tt.id); !slices.Equal(got, tt.want) {
+			t.Errorf("PulledInBy(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
@@ -143,7 +143,9 @@ func extractMeta(n *dag.Node) *graph.BlockMeta {
 	m.LastRelease, _ = n.Meta[metadata.RepoLastRelease].(string)
 	m.Archived, _ = n.Meta[metadata.RepoArchived].(bool)
 
-	if desc, ok := n.Meta[metadata.RepoDescription].(string); ok && desc != "" {
+	if summary, ok := n.Meta[metadata.Summary].(string); ok && summary != "" {
+		m.Description = summary
+	} else if desc, ok := n.Meta[metadata.RepoDescription].(string); ok {
 		m.Description = desc
 	}
 
@@ -215,6 +217,8 @@ func buildNebraska(rankings []feature.NebraskaRanking) []graph.NebraskaRanking {
 		result[i] = graph.NebraskaRanking{
 			Maintainer: r.Maintainer,
 			Score:      r.Score,
+			Share:      r.Share,
+			Org:        r.Org,
 			Packages:   pkgs,
 		}
 	}
@@ -238,6 +242,8 @@ func parseNebraska(data []graph.NebraskaRanking) []feature.NebraskaRanking {
 		result[i] = feature.NebraskaRanking{
 			Maintainer: d.Maintainer,
 			Score:      d.Score,
+			Share:      d.Share,
+			Org:        d.Org,
 			Packages:   pkgs,
 		}
 	}
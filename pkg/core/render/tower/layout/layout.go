@@ -1,6 +1,7 @@
 package layout
 
 import (
+	"context"
 	"slices"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/stacktower-io/stacktower/pkg/core/dag/transform"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/ordering"
+	"github.com/stacktower-io/stacktower/pkg/observability"
 )
 
 const (
@@ -52,6 +54,8 @@ type Layout struct {
 type Option func(*config)
 
 type config struct {
+	ctx         context.Context
+	logger      observability.Logger
 	orderer     ordering.Orderer
 	auxRatio    float64
 	marginRatio float64
@@ -64,6 +68,20 @@ func WithOrderer(o ordering.Orderer) Option {
 	return func(c *config) { c.orderer = o }
 }
 
+// WithContext makes Build stop ordering when ctx is done, keeping the best
+// order found so far, if the orderer is an [ordering.ContextOrderer].
+// Callers check ctx.Err() to tell a cancelled layout from a finished one.
+func WithContext(ctx context.Context) Option {
+	return func(c *config) { c.ctx = ctx }
+}
+
+// WithLogger receives the layout's progress. It is also given to the
+// default orderer, and to an [ordering.OptimalSearch] without a logger of
+// its own.
+func WithLogger(l observability.Logger) Option {
+	return func(c *config) { c.logger = l }
+}
+
 // WithAuxiliaryRatio sets the height of auxiliary rows (separator beams)
 // relative to regular rows. Defaults to 0.2.
 func WithAuxiliaryRatio(r float64) Option {
@@ -101,6 +119,7 @@ func EnsureLayered(g *dag.DAG) {
 // should handle layer assignment.
 func Build(g *dag.DAG, width, height float64, opts ...Option) Layout {
 	cfg := config{
+		ctx:         context.Background(),
 		orderer:     DefaultOrderer,
 		auxRatio:    defaultAuxRatio,
 		marginRatio: defaultMarginRatio,
@@ -109,10 +128,16 @@ func Build(g *dag.DAG, width, height float64, opts ...Option) Layout {
 		opt(&cfg)
 	}
 
+	logger := observability.LoggerOrNoop(cfg.logger)
+	if search, ok := cfg.orderer.(ordering.OptimalSearch); ok && search.Logger == nil {
+		search.Logger = cfg.logger
+		cfg.orderer = search
+	}
+
 	marginX := width * cfg.marginRatio
 	marginY := height * cfg.marginRatio
 
-	orders := cfg.orderer.OrderRows(g)
+	orders := ordering.OrderContext(cfg.ctx, cfg.orderer, g)
 	var widths map[string]float64
 	if cfg.topDownFlow {
 		widths = ComputeWidths(g, orders, width-2*marginX)
@@ -122,6 +147,7 @@ func Build(g *dag.DAG, width, height float64, opts ...Option) Layout {
 	heights := computeRowHeights(g, height-2*marginY, cfg.auxRatio)
 	bottoms := computeRowBottoms(heights)
 	blocks := assembleBlocks(g, orders, widths, heights, bottoms, marginX, marginY)
+	logger.Debug("built tower layout", "rows", len(orders), "blocks", len(blocks), "width", width, "height", height)
 
 	return Layout{
 		FrameWidth:  width,
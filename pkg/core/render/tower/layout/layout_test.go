@@ -1,10 +1,12 @@
 package layout
 
 import (
+	"context"
 	"math"
 	"testing"
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/ordering"
 )
 
 func TestBlock(t *testing.T) {
@@ -360,6 +362,37 @@ func TestBuild_WithOptions(t *testing.T) {
 	}
 }
 
+// ctxOrderer records the context it orders with.
+type ctxOrderer struct{ ctx context.Context }
+
+func (o *ctxOrderer) OrderRows(g *dag.DAG) map[int][]string {
+	return o.OrderRowsContext(context.Background(), g)
+}
+
+func (o *ctxOrderer) OrderRowsContext(ctx context.Context, g *dag.DAG) map[int][]string {
+	o.ctx = ctx
+	return ordering.Barycentric{}.OrderRowsContext(ctx, g)
+}
+
+func TestBuild_WithContext(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "A", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "B", Row: 1})
+	_ = g.AddEdge(dag.Edge{From: "A", To: "B"})
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "build")
+	o := &ctxOrderer{}
+	layout := Build(g, 100, 100, WithOrderer(o), WithContext(ctx))
+
+	if o.ctx == nil || o.ctx.Value(key{}) != "build" {
+		t.Error("orderer did not receive the Build context")
+	}
+	if len(layout.Blocks) != 2 {
+		t.Errorf("want 2 blocks, got %d", len(layout.Blocks))
+	}
+}
+
 func TestBuild_WithMargins(t *testing.T) {
 	g := dag.New(nil)
 	_ = g.AddNode(dag.Node{ID: "A", Row: 0})
@@ -2,6 +2,8 @@ package ordering
 
 import (
 	"cmp"
+	"context"
+	"math/rand/v2"
 	"slices"
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
@@ -12,13 +14,33 @@ const defaultPasses = 24
 // Barycentric implements a fast heuristic for edge crossing minimization
 // based on the Sugiyama framework. It iteratively reorders rows by the
 // average position (barycenter) of their neighbors in adjacent rows.
+// Neighbors count in proportion to the [dag.EdgeMetaWeight] of the edge to
+// them, so heavier edges pull their ends closer together.
+//
+// The result depends only on the graph and the fields below, so the same
+// inputs always give the same ordering.
 type Barycentric struct {
 	Passes int
+
+	// Restarts is the number of extra runs from shuffled starting orders,
+	// kept when they beat the structural start. Restarts escape local
+	// minima at the cost of one run each; zero disables them.
+	Restarts int
+
+	// Seed seeds the shuffles of the restarts.
+	Seed uint64
 }
 
 // OrderRows implements the [Orderer] interface using the barycentric heuristic.
 func (b Barycentric) OrderRows(g *dag.DAG) map[int][]string {
-	rows := g.RowIDs()
+	return b.OrderRowsContext(context.Background(), g)
+}
+
+// OrderRowsContext implements the [ContextOrderer] interface. When ctx is
+// done, the remaining passes are skipped and the best ordering so far is
+// returned.
+func (b Barycentric) OrderRowsContext(ctx context.Context, g *dag.DAG) map[int][]string {
+	rows := g.RowIDsIter()
 	if len(rows) == 0 {
 		return nil
 	}
@@ -39,43 +61,52 @@ func (b Barycentric) OrderRows(g *dag.DAG) map[int][]string {
 		return best
 	}
 
-	if orders, score := runPasses(g, rows, rowNodes, best, passes); score < bestScore {
+	weights := edgeWeights(g)
+	if orders, score := runPasses(ctx, g, weights, rows, rowNodes, best, passes); score < bestScore {
 		best, bestScore = orders, score
 		if bestScore == 0 {
 			return best
 		}
 	}
 
-	if orders, score := runPasses(g, rows, rowNodes, reverseOrders(best, rows), passes); score < bestScore {
-		return orders
+	if orders, score := runPasses(ctx, g, weights, rows, rowNodes, reverseOrders(best, rows), passes); score < bestScore {
+		best, bestScore = orders, score
+	}
+
+	rng := rand.New(rand.NewPCG(b.Seed, b.Seed^0x9e3779b97f4a7c15))
+	for i := 0; i < b.Restarts && bestScore > 0 && ctx.Err() == nil; i++ {
+		if orders, score := runPasses(ctx, g, weights, rows, rowNodes, shuffleOrders(best, rows, rng), passes); score < bestScore {
+			best, bestScore = orders, score
+		}
 	}
 	return best
 }
 
-func runPasses(g *dag.DAG, rows []int, rowNodes map[int][]*dag.Node, init map[int][]string, passes int) (map[int][]string, int) {
+func runPasses(ctx context.Context, g *dag.DAG, weights map[edgeEnds]float64, rows []int, rowNodes map[int][]*dag.Node, init map[int][]string, passes int) (map[int][]string, int) {
 	orders := copyOrders(init)
 	best := copyOrders(orders)
-	bestScore := dag.CountCrossings(g, orders)
+	ws := dag.NewCrossingWorkspace(maxRowLen(rowNodes))
+	bestScore := ws.CountCrossings(g, orders)
 
 	staleCount := 0
-	for pass := 0; pass < passes && bestScore > 0; pass++ {
+	for pass := 0; pass < passes && bestScore > 0 && ctx.Err() == nil; pass++ {
 		prevScore := bestScore
 
 		if pass%2 == 0 {
 			for i := 1; i < len(rows); i++ {
 				r := rows[i]
-				orders[r] = wmedian(g, rowNodes[r], orders[r], orders[r-1], true)
+				orders[r] = wmedian(g, weights, rowNodes[r], orders[r], orders[r-1], true)
 				transpose(g, orders, r, r-1, true)
 			}
 		} else {
 			for i := len(rows) - 2; i >= 0; i-- {
 				r := rows[i]
-				orders[r] = wmedian(g, rowNodes[r], orders[r], orders[r+1], false)
+				orders[r] = wmedian(g, weights, rowNodes[r], orders[r], orders[r+1], false)
 				transpose(g, orders, r, r+1, false)
 			}
 		}
 
-		score := dag.CountCrossings(g, orders)
+		score := ws.CountCrossings(g, orders)
 		if score < bestScore {
 			best = copyOrders(orders)
 			bestScore = score
@@ -105,7 +136,7 @@ func (e nodeEntry) sortKey() int {
 	return e.currentPos
 }
 
-func wmedian(g *dag.DAG, nodes []*dag.Node, current, fixed []string, useParents bool) []string {
+func wmedian(g *dag.DAG, weights map[edgeEnds]float64, nodes []*dag.Node, current, fixed []string, useParents bool) []string {
 	if len(nodes) <= 1 {
 		return dag.NodeIDs(nodes)
 	}
@@ -127,7 +158,7 @@ func wmedian(g *dag.DAG, nodes []*dag.Node, current, fixed []string, useParents
 			pos = p
 		}
 
-		medianPos, hasMedian := weightedMedian(neighbors, fixedPos)
+		medianPos, hasMedian := weightedMedian(n.ID, neighbors, fixedPos, weights, useParents)
 		entries[i] = nodeEntry{n.ID, medianPos, hasMedian, pos}
 	}
 
@@ -151,14 +182,73 @@ func wmedian(g *dag.DAG, nodes []*dag.Node, current, fixed []string, useParents
 	return ids
 }
 
-func weightedMedian(neighbors []string, positions map[string]int) (int, bool) {
-	var pos []int
+// edgeEnds identifies an edge by its source and target.
+type edgeEnds struct{ from, to string }
+
+// edgeWeights returns the weight of every edge whose weight is not 1, or
+// nil when all are, so unweighted graphs skip the lookups.
+func edgeWeights(g *dag.DAG) map[edgeEnds]float64 {
+	var weights map[edgeEnds]float64
+	for _, e := range g.EdgesIter() {
+		if w := e.Weight(); w != 1 {
+			if weights == nil {
+				weights = make(map[edgeEnds]float64)
+			}
+			weights[edgeEnds{e.From, e.To}] = w
+		}
+	}
+	return weights
+}
+
+// weightedMedian returns the median position of the neighbors of id, each
+// counted with the weight of its edge to id: the lowest position at which
+// half of the total weight is reached. With equal weights this is the
+// lower median.
+func weightedMedian(id string, neighbors []string, positions map[string]int, weights map[edgeEnds]float64, useParents bool) (int, bool) {
+	if weights == nil {
+		var pos []int
+		for _, n := range neighbors {
+			if p, ok := positions[n]; ok {
+				pos = append(pos, p)
+			}
+		}
+		return medianPosition(pos)
+	}
+
+	type weighted struct {
+		pos    int
+		weight float64
+	}
+	items := make([]weighted, 0, len(neighbors))
+	total := 0.0
 	for _, n := range neighbors {
-		if p, ok := positions[n]; ok {
-			pos = append(pos, p)
+		p, ok := positions[n]
+		if !ok {
+			continue
+		}
+		ends := edgeEnds{id, n}
+		if useParents {
+			ends = edgeEnds{n, id}
+		}
+		w, ok := weights[ends]
+		if !ok {
+			w = 1
+		}
+		items = append(items, weighted{p, w})
+		total += w
+	}
+	if len(items) == 0 {
+		return 0, false
+	}
+	slices.SortFunc(items, func(a, b weighted) int { return cmp.Compare(a.pos, b.pos) })
+	sum := 0.0
+	for _, it := range items {
+		sum += it.weight
+		if sum >= total/2 {
+			return it.pos, true
 		}
 	}
-	return medianPosition(pos)
+	return items[len(items)-1].pos, true
 }
 
 func transpose(g *dag.DAG, orders map[int][]string, row, adjRow int, useParents bool) {
@@ -167,7 +257,13 @@ func transpose(g *dag.DAG, orders map[int][]string, row, adjRow int, useParents
 		return
 	}
 
+	// The adjacent row stays fixed, so each node's neighbor positions are
+	// computed once and every candidate swap costs O(degree).
 	adjPos := dag.PosMap(orders[adjRow])
+	nbrs := make([][]int, len(order))
+	for i, id := range order {
+		nbrs[i] = neighborPositions(g, id, adjPos, useParents)
+	}
 	for {
 		swapped := false
 		for i := 0; i < len(order)-1; i++ {
@@ -179,9 +275,9 @@ func transpose(g *dag.DAG, orders map[int][]string, row, adjRow int, useParents
 				}
 			}
 
-			if dag.CountPairCrossingsWithPos(g, right, left, adjPos, useParents) <
-				dag.CountPairCrossingsWithPos(g, left, right, adjPos, useParents) {
+			if dag.SwapDelta(nbrs[i], nbrs[i+1]) < 0 {
 				order[i], order[i+1] = right, left
+				nbrs[i], nbrs[i+1] = nbrs[i+1], nbrs[i]
 				swapped = true
 			}
 		}
@@ -191,6 +287,34 @@ func transpose(g *dag.DAG, orders map[int][]string, row, adjRow int, useParents
 	}
 }
 
+// neighborPositions returns the sorted positions in adjPos of the parents,
+// or children, of id.
+func neighborPositions(g *dag.DAG, id string, adjPos map[string]int, useParents bool) []int {
+	var neighbors []string
+	if useParents {
+		neighbors = g.Parents(id)
+	} else {
+		neighbors = g.Children(id)
+	}
+	pos := make([]int, 0, len(neighbors))
+	for _, n := range neighbors {
+		if p, ok := adjPos[n]; ok {
+			pos = append(pos, p)
+		}
+	}
+	slices.Sort(pos)
+	return pos
+}
+
+// maxRowLen returns the number of nodes in the widest row.
+func maxRowLen(rowNodes map[int][]*dag.Node) int {
+	n := 0
+	for _, nodes := range rowNodes {
+		n = max(n, len(nodes))
+	}
+	return n
+}
+
 func reverseOrders(orders map[int][]string, rows []int) map[int][]string {
 	rev := make(map[int][]string, len(orders))
 	for _, r := range rows {
@@ -201,6 +325,17 @@ func reverseOrders(orders map[int][]string, rows []int) map[int][]string {
 	return rev
 }
 
+// shuffleOrders returns a copy of orders with every row shuffled by rng.
+func shuffleOrders(orders map[int][]string, rows []int, rng *rand.Rand) map[int][]string {
+	shuffled := make(map[int][]string, len(orders))
+	for _, r := range rows {
+		row := slices.Clone(orders[r])
+		rng.Shuffle(len(row), func(i, j int) { row[i], row[j] = row[j], row[i] })
+		shuffled[r] = row
+	}
+	return shuffled
+}
+
 func initOrders(g *dag.DAG, rows []int, rowNodes map[int][]*dag.Node) map[int][]string {
 	if len(rows) == 0 {
 		return make(map[int][]string)
@@ -1,10 +1,17 @@
 package ordering
 
 import (
+	"context"
+	"fmt"
+	"maps"
+	"math/rand/v2"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/dag/gen"
+	"github.com/stacktower-io/stacktower/pkg/core/dag/transform"
 )
 
 func TestBarycentric_Diamond(t *testing.T) {
@@ -34,6 +41,24 @@ func TestBarycentric_Diamond(t *testing.T) {
 	}
 }
 
+func TestBarycentric_Cancelled(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "A", Row: 0})
+	g.AddNode(dag.Node{ID: "B", Row: 0})
+	g.AddNode(dag.Node{ID: "C", Row: 1})
+	g.AddNode(dag.Node{ID: "D", Row: 1})
+	g.AddEdge(dag.Edge{From: "A", To: "D"})
+	g.AddEdge(dag.Edge{From: "B", To: "C"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	got := Barycentric{}.OrderRowsContext(ctx, g)
+	if len(got[0]) != 2 || len(got[1]) != 2 {
+		t.Errorf("cancelled ordering is incomplete: %v", got)
+	}
+}
+
 func TestBarycentric_Empty(t *testing.T) {
 	got := Barycentric{}.OrderRows(dag.New(nil))
 	if got != nil {
@@ -118,6 +143,58 @@ func TestBarycentric_TieBreaking(t *testing.T) {
 	}
 }
 
+func TestBarycentric_Restarts(t *testing.T) {
+	g := randomLayered(7, 6, 8, 3)
+
+	plain := Barycentric{}.OrderRows(g)
+	seeded := Barycentric{Restarts: 8, Seed: 42}
+	got := seeded.OrderRows(g)
+
+	if c, p := dag.CountCrossings(g, got), dag.CountCrossings(g, plain); c > p {
+		t.Errorf("restarts gave %d crossings, more than %d without", c, p)
+	}
+	for range 3 {
+		if again := seeded.OrderRows(g); !maps.EqualFunc(got, again, slices.Equal) {
+			t.Fatalf("same seed gave different orderings:\n%v\n%v", got, again)
+		}
+	}
+}
+
+func TestBarycentric_ZeroRestartsIgnoresSeed(t *testing.T) {
+	g := randomLayered(7, 6, 8, 3)
+
+	a := Barycentric{Seed: 1}.OrderRows(g)
+	b := Barycentric{Seed: 2}.OrderRows(g)
+
+	if !maps.EqualFunc(a, b, slices.Equal) {
+		t.Errorf("seed changed the ordering without restarts:\n%v\n%v", a, b)
+	}
+}
+
+// randomLayered returns a graph of rows rows of width nodes, where every
+// node below the top has up to fanIn parents in the row above, chosen by
+// a generator seeded with seed.
+func randomLayered(seed uint64, rows, width, fanIn int) *dag.DAG {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	g := dag.New(nil)
+	id := func(row, col int) string { return fmt.Sprintf("r%dn%d", row, col) }
+	for row := range rows {
+		for col := range width {
+			_ = g.AddNode(dag.Node{ID: id(row, col), Row: row})
+			if row == 0 {
+				continue
+			}
+			for range 1 + rng.IntN(fanIn) {
+				from := id(row-1, rng.IntN(width))
+				if !g.HasEdge(from, id(row, col)) {
+					_ = g.AddEdge(dag.Edge{From: from, To: id(row, col)})
+				}
+			}
+		}
+	}
+	return g
+}
+
 func TestBarycentric_FanOut(t *testing.T) {
 	g := dag.New(nil)
 	g.AddNode(dag.Node{ID: "A", Row: 0})
@@ -168,6 +245,7 @@ func TestWeightedMedian(t *testing.T) {
 		name      string
 		neighbors []string
 		positions map[string]int
+		weights   map[edgeEnds]float64
 		wantMed   int
 		wantHas   bool
 	}{
@@ -206,11 +284,35 @@ func TestWeightedMedian(t *testing.T) {
 			wantMed:   0,
 			wantHas:   false,
 		},
+		{
+			name:      "equal weights",
+			neighbors: []string{"P1", "P2", "P3", "P4"},
+			positions: map[string]int{"P1": 0, "P2": 1, "P3": 3, "P4": 4},
+			weights:   map[edgeEnds]float64{{"P1", "N"}: 1},
+			wantMed:   1, // same as unweighted
+			wantHas:   true,
+		},
+		{
+			name:      "heavy neighbor",
+			neighbors: []string{"P1", "P2", "P3"},
+			positions: map[string]int{"P1": 0, "P2": 1, "P3": 4},
+			weights:   map[edgeEnds]float64{{"P3", "N"}: 5},
+			wantMed:   4,
+			wantHas:   true,
+		},
+		{
+			name:      "weighted neighbors not in positions",
+			neighbors: []string{"X"},
+			positions: map[string]int{},
+			weights:   map[edgeEnds]float64{{"X", "N"}: 2},
+			wantMed:   0,
+			wantHas:   false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotMed, gotHas := weightedMedian(tt.neighbors, tt.positions)
+			gotMed, gotHas := weightedMedian("N", tt.neighbors, tt.positions, tt.weights, true)
 			if gotMed != tt.wantMed {
 				t.Errorf("median: want %d, got %d", tt.wantMed, gotMed)
 			}
@@ -348,3 +450,34 @@ func TestTranspose_ReducesCrossings(t *testing.T) {
 		t.Errorf("transpose should reduce crossings: before=%d after=%d order=%v", before, after, orders[1])
 	}
 }
+
+// FuzzBarycentric orders generated graphs of fuzzed shape and checks that
+// every row comes back as a permutation of its nodes.
+func FuzzBarycentric(f *testing.F) {
+	f.Add(uint64(1), uint8(4), uint8(6), uint8(2), uint8(0))
+	f.Add(uint64(2), uint8(8), uint8(12), uint8(3), uint8(128))
+	f.Add(uint64(3), uint8(3), uint8(20), uint8(5), uint8(255))
+	f.Fuzz(func(t *testing.T, seed uint64, depth, width, fanOut, tangle uint8) {
+		g := gen.Generate(gen.Options{
+			Depth:  1 + int(depth%10),
+			Width:  1 + int(width%16),
+			FanOut: 1 + int(fanOut%5),
+			Tangle: float64(tangle) / 255,
+			Seed:   seed,
+			Now:    time.Unix(0, 0),
+		})
+		if _, err := transform.Normalize(g); err != nil {
+			t.Fatal(err)
+		}
+		orders := Barycentric{Restarts: 1, Seed: seed}.OrderRows(g)
+		for _, r := range g.RowIDs() {
+			want := dag.NodeIDs(g.NodesInRow(r))
+			got := slices.Clone(orders[r])
+			slices.Sort(want)
+			slices.Sort(got)
+			if !slices.Equal(got, want) {
+				t.Fatalf("row %d ordered as %v, want a permutation of %v", r, orders[r], want)
+			}
+		}
+	})
+}
@@ -78,6 +78,16 @@
 //   - [QualityBalanced]: 5s timeout, good for most graphs
 //   - [QualityOptimal]: 60s timeout, for publication-quality output
 //
+// # Determinism
+//
+// Both orderers return the same ordering for the same graph and settings.
+// [Barycentric.Restarts] reruns the heuristic from shuffled starting orders
+// drawn from [Barycentric.Seed], so restarts are reproducible too.
+// [OptimalSearch] breaks ties between equally good orderings by the order
+// of its starting permutations rather than by which goroutine finishes
+// first; only a search cut short by its timeout or context can differ
+// between runs.
+//
 // # Algorithm Selection
 //
 // Use barycentric for:
@@ -3,6 +3,7 @@ package ordering
 import (
 	"cmp"
 	"context"
+	"errors"
 	"runtime"
 	"slices"
 	"sync"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	"github.com/stacktower-io/stacktower/pkg/core/dag/perm"
+	"github.com/stacktower-io/stacktower/pkg/observability"
 )
 
 const maxCandidatesBase = 10000
@@ -24,10 +26,22 @@ const maxRowWidth = 30
 // mathematically optimal horizontal ordering (minimum crossings). It uses
 // PQ-trees to prune the search space to only include orderings that satisfy
 // structural constraints (Consecutive Ones Property).
+//
+// Among orderings with the fewest crossings, the search returns the same
+// one on every run regardless of goroutine scheduling, unless it is cut
+// short by the timeout or ctx.
 type OptimalSearch struct {
 	Progress func(explored, pruned, best int)
 	Timeout  time.Duration
 	Debug    func(info DebugInfo)
+
+	// Restarts and Seed configure the [Barycentric] ordering that bounds
+	// the search and stands in for it on rows too wide to search.
+	Restarts int
+	Seed     uint64
+
+	// Logger receives the search's fallbacks and outcome. Nil discards them.
+	Logger observability.Logger
 }
 
 // DebugInfo contains diagnostic information about the optimal search process.
@@ -46,16 +60,25 @@ type RowDebugInfo struct {
 
 // OrderRows implements the [Orderer] interface by performing an optimal search.
 func (o OptimalSearch) OrderRows(g *dag.DAG) map[int][]string {
-	rows := g.RowIDs()
+	return o.OrderRowsContext(context.Background(), g)
+}
+
+// OrderRowsContext implements the [ContextOrderer] interface. The search
+// stops when ctx is done or the timeout passes, whichever comes first, and
+// returns the best ordering found so far.
+func (o OptimalSearch) OrderRowsContext(ctx context.Context, g *dag.DAG) map[int][]string {
+	rows := g.RowIDsIter()
 	if len(rows) == 0 {
 		return nil
 	}
+	logger := observability.LoggerOrNoop(o.Logger)
 
 	// Check for rows too wide for optimal search - fall back to barycentric
 	// to avoid factorial memory explosion
 	for _, r := range rows {
-		if len(g.NodesInRow(r)) > maxRowWidth {
-			return Barycentric{}.OrderRows(g)
+		if n := len(g.NodesInRow(r)); n > maxRowWidth {
+			logger.Debug("row too wide for optimal search, using barycentric", "row", r, "nodes", n, "max", maxRowWidth)
+			return o.barycentric().OrderRowsContext(ctx, g)
 		}
 	}
 
@@ -64,15 +87,21 @@ func (o OptimalSearch) OrderRows(g *dag.DAG) map[int][]string {
 		timeout = 60 * time.Second
 	}
 
-	initial := Barycentric{}.OrderRows(g)
+	initial := o.barycentric().OrderRowsContext(ctx, g)
 	initialScore := dag.CountCrossings(g, initial)
 	if initialScore == 0 {
 		o.report(1, 0, 0)
 		return initial
 	}
+	if ctx.Err() != nil {
+		logger.Debug("ordering search cancelled before it started", "crossings", initialScore)
+		return initial
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
+	start := time.Now()
 
 	s := &solver{
 		g:         g,
@@ -81,9 +110,7 @@ func (o OptimalSearch) OrderRows(g *dag.DAG) map[int][]string {
 		rowNodes:  make(map[int][]*dag.Node, len(rows)),
 		candLimit: calcCandidateLimit(len(rows)),
 		ctx:       ctx,
-		cancel:    cancel,
 	}
-	s.bestScore.Store(int64(initialScore))
 	s.bestPath.Store(toIndexPath(g, rows, initial))
 
 	for _, r := range rows {
@@ -94,10 +121,25 @@ func (o OptimalSearch) OrderRows(g *dag.DAG) map[int][]string {
 		go s.monitor(o.Progress)
 	}
 
-	s.search()
+	s.search(initialScore)
+
+	explored, pruned, best := s.explored.Load(), s.pruned.Load(), s.bestScore()
+	switch {
+	case parent.Err() != nil:
+		logger.Debug("ordering search cancelled, using the best ordering found", "crossings", best)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		logger.Info("ordering search timed out, using the best ordering found", "timeout", timeout, "crossings", best)
+	}
+	logger.Debug("ordering search finished",
+		"rows", len(rows),
+		"explored", explored,
+		"pruned", pruned,
+		"initial_crossings", initialScore,
+		"crossings", best,
+		"duration", time.Since(start))
 
 	if o.Progress != nil {
-		o.report(int(s.explored.Load()), int(s.pruned.Load()), int(s.bestScore.Load()))
+		o.report(int(explored), int(pruned), int(best))
 	}
 
 	if o.Debug != nil {
@@ -107,6 +149,10 @@ func (o OptimalSearch) OrderRows(g *dag.DAG) map[int][]string {
 	return toStringOrder(s.rowNodes, s.rows, s.bestPath.Load().([][]int))
 }
 
+func (o OptimalSearch) barycentric() Barycentric {
+	return Barycentric{Restarts: o.Restarts, Seed: o.Seed}
+}
+
 func (o OptimalSearch) report(explored, pruned, best int) {
 	if o.Progress != nil {
 		o.Progress(explored, pruned, best)
@@ -120,14 +166,20 @@ type solver struct {
 	rowNodes  map[int][]*dag.Node
 	candLimit int
 
-	bestScore atomic.Int64
-	bestPath  atomic.Value
-	explored  atomic.Int64
-	pruned    atomic.Int64
-	maxDepth  atomic.Int64
-
-	ctx    context.Context
-	cancel context.CancelFunc
+	// best ranks the best path found as score*keys + start, where start
+	// is 1 + the index of the start permutation it descends from, or 0 for
+	// the initial ordering. Ties go to the lower start, and within a start
+	// to the path found first, so the result does not depend on which
+	// worker finishes first.
+	best     atomic.Int64
+	keys     int64
+	bestPath atomic.Value
+	mu       sync.Mutex // serializes updates of best and bestPath
+	explored atomic.Int64
+	pruned   atomic.Int64
+	maxDepth atomic.Int64
+
+	ctx context.Context
 }
 
 func calcCandidateLimit(numRows int) int {
@@ -141,19 +193,38 @@ func calcCandidateLimit(numRows int) int {
 	return max(100, min(2000, limit))
 }
 
-func (s *solver) search() {
+// key returns the rank of a path with the given score descending from the
+// start permutation with the given key.
+func (s *solver) key(score, start int) int64 {
+	return int64(score)*s.keys + int64(start)
+}
+
+// bestScore returns the crossings of the best path found.
+func (s *solver) bestScore() int {
+	return int(s.best.Load() / s.keys)
+}
+
+// prune reports whether paths from start whose crossings are already at
+// score cannot beat the best path found.
+func (s *solver) prune(score, start int) bool {
+	return s.key(score, start) >= s.best.Load()
+}
+
+func (s *solver) search(initialScore int) {
 	workers := runtime.GOMAXPROCS(0)
 	parallelRow := s.findParallelRow()
 
 	prefix, prefixScore := s.buildPrefix(parallelRow)
 	starts := s.generateStartPermutations(parallelRow, prefix, workers*100)
+	s.keys = int64(len(starts)) + 1
+	s.best.Store(s.key(initialScore, 0))
 
 	var wg sync.WaitGroup
 	sem := make(chan struct{}, workers)
 
 dispatch:
-	for _, startPerm := range starts {
-		if s.bestScore.Load() == 0 {
+	for i, startPerm := range starts {
+		if s.prune(0, i+1) {
 			break
 		}
 
@@ -165,7 +236,7 @@ dispatch:
 		}
 
 		wg.Add(1)
-		go func(start []int) {
+		go func(startKey int, start []int) {
 			defer wg.Done()
 			defer func() { <-sem }()
 
@@ -183,13 +254,13 @@ dispatch:
 				score += dag.CountCrossingsIdx(s.fg.edges[parallelRow-1], prefix[parallelRow-1], start, ws)
 			}
 
-			if score >= int(s.bestScore.Load()) {
+			if s.prune(score, startKey) {
 				s.pruned.Add(1)
 				return
 			}
 
-			s.dfs(parallelRow+1, score, path, dag.NewCrossingWorkspace(s.fg.maxRowWidth))
-		}(startPerm)
+			s.dfs(startKey, parallelRow+1, score, path, dag.NewCrossingWorkspace(s.fg.maxRowWidth))
+		}(i+1, startPerm)
 	}
 
 	wg.Wait()
@@ -247,7 +318,7 @@ func (s *solver) generateStartPermutations(parallelRow int, prefix [][]int, work
 	return starts
 }
 
-func (s *solver) dfs(depth, score int, path [][]int, ws *dag.CrossingWorkspace) {
+func (s *solver) dfs(start, depth, score int, path [][]int, ws *dag.CrossingWorkspace) {
 	if s.ctx.Err() != nil {
 		return
 	}
@@ -260,13 +331,13 @@ func (s *solver) dfs(depth, score int, path [][]int, ws *dag.CrossingWorkspace)
 		}
 	}
 
-	if score >= int(s.bestScore.Load()) {
+	if s.prune(score, start) {
 		s.pruned.Add(1)
 		return
 	}
 
 	if depth == len(s.rows) {
-		s.updateBest(path, score)
+		s.updateBest(path, score, start)
 		return
 	}
 
@@ -274,7 +345,7 @@ func (s *solver) dfs(depth, score int, path [][]int, ws *dag.CrossingWorkspace)
 	nodes := s.rowNodes[rowID]
 	if len(nodes) == 0 {
 		path[depth] = nil
-		s.dfs(depth+1, score, path, ws)
+		s.dfs(start, depth+1, score, path, ws)
 		return
 	}
 
@@ -290,15 +361,15 @@ func (s *solver) dfs(depth, score int, path [][]int, ws *dag.CrossingWorkspace)
 
 	for _, candidate := range candidates {
 		newScore := score + dag.CountCrossingsIdx(s.fg.edges[depth-1], prevOrder, candidate, ws)
-		if newScore >= int(s.bestScore.Load()) {
+		if s.prune(newScore, start) {
 			s.pruned.Add(1)
 			continue
 		}
 
 		path[depth] = candidate
-		s.dfs(depth+1, newScore, path, ws)
+		s.dfs(start, depth+1, newScore, path, ws)
 
-		if s.bestScore.Load() == 0 || s.ctx.Err() != nil {
+		if s.prune(0, start) || s.ctx.Err() != nil {
 			return
 		}
 	}
@@ -381,26 +452,25 @@ func (s *solver) fallbackPermutations(n int) [][]int {
 	return perm.Generate(n, s.candLimit)
 }
 
-func (s *solver) updateBest(path [][]int, score int) {
+func (s *solver) updateBest(path [][]int, score, start int) {
 	s.explored.Add(1)
 
-	for {
-		current := int(s.bestScore.Load())
-		if score >= current {
-			return
-		}
-		if s.bestScore.CompareAndSwap(int64(current), int64(score)) {
-			cloned := make([][]int, len(path))
-			for i, p := range path {
-				cloned[i] = slices.Clone(p)
-			}
-			s.bestPath.Store(cloned)
-			if score == 0 {
-				s.cancel()
-			}
-			return
-		}
+	key := s.key(score, start)
+	if key >= s.best.Load() {
+		return
+	}
+	cloned := make([][]int, len(path))
+	for i, p := range path {
+		cloned[i] = slices.Clone(p)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key >= s.best.Load() {
+		return
 	}
+	s.best.Store(key)
+	s.bestPath.Store(cloned)
 }
 
 func (s *solver) collectDebugInfo(initialOrder map[int][]string) DebugInfo {
@@ -443,7 +513,7 @@ func (s *solver) monitor(fn func(int, int, int)) {
 		case <-s.ctx.Done():
 			return
 		case <-ticker.C:
-			fn(int(s.explored.Load()), int(s.pruned.Load()), int(s.bestScore.Load()))
+			fn(int(s.explored.Load()), int(s.pruned.Load()), s.bestScore())
 		}
 	}
 }
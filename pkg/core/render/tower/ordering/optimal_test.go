@@ -1,7 +1,13 @@
 package ordering
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
 	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -235,6 +241,18 @@ func TestOptimalSearch_LargerGraph(t *testing.T) {
 	t.Logf("Score: %d crossings for 5x5 graph", score)
 }
 
+func TestOptimalSearch_Deterministic(t *testing.T) {
+	g := randomLayered(3, 4, 5, 2)
+	opt := OptimalSearch{Timeout: 30 * time.Second}
+
+	want := opt.OrderRows(g)
+	for range 5 {
+		if got := opt.OrderRows(g); !maps.EqualFunc(got, want, slices.Equal) {
+			t.Fatalf("orderings differ between runs:\n%v\n%v", want, got)
+		}
+	}
+}
+
 func TestOptimalSearch_ZeroTimeout(t *testing.T) {
 	g := dag.New(nil)
 	g.AddNode(dag.Node{ID: "A", Row: 0})
@@ -302,6 +320,27 @@ func TestOptimalSearch_WideRowFallback(t *testing.T) {
 	}
 }
 
+func TestOptimalSearch_LogsWideRowFallback(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "sink", Row: 1})
+	for i := range maxRowWidth + 1 {
+		id := fmt.Sprintf("n%d", i)
+		_ = g.AddNode(dag.Node{ID: id, Row: 0})
+		_ = g.AddEdge(dag.Edge{From: id, To: "sink"})
+	}
+
+	var buf bytes.Buffer
+	opt := OptimalSearch{
+		Timeout: time.Second,
+		Logger:  slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+	opt.OrderRows(g)
+
+	if got := buf.String(); !strings.Contains(got, "using barycentric") || !strings.Contains(got, "nodes=31") {
+		t.Errorf("expected a structured fallback record, got %q", got)
+	}
+}
+
 func TestOptimalSearch_NoEdges(t *testing.T) {
 	g := dag.New(nil)
 	g.AddNode(dag.Node{ID: "A", Row: 0})
@@ -445,3 +484,51 @@ func TestOptimalSearch_ConcurrentSafety(t *testing.T) {
 		<-done
 	}
 }
+
+func TestOptimalSearch_Cancelled(t *testing.T) {
+	g := dag.New(nil)
+	for i := 0; i < 8; i++ {
+		g.AddNode(dag.Node{ID: string(rune('A' + i)), Row: 0})
+		g.AddNode(dag.Node{ID: string(rune('a' + i)), Row: 1})
+	}
+	for i := 0; i < 8; i++ {
+		for j := 0; j < 8; j += 3 {
+			g.AddEdge(dag.Edge{From: string(rune('A' + i)), To: string(rune('a' + (i+j)%8))})
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	got := OptimalSearch{Timeout: time.Minute}.OrderRowsContext(ctx, g)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("cancelled search took %v", elapsed)
+	}
+	if len(got[0]) != 8 || len(got[1]) != 8 {
+		t.Errorf("cancelled search returned an incomplete ordering: %v", got)
+	}
+}
+
+// plainOrderer only implements Orderer.
+type plainOrderer struct{ called bool }
+
+func (p *plainOrderer) OrderRows(g *dag.DAG) map[int][]string {
+	p.called = true
+	return Barycentric{}.OrderRows(g)
+}
+
+func TestOrderContext(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "A", Row: 0})
+	g.AddNode(dag.Node{ID: "B", Row: 1})
+	g.AddEdge(dag.Edge{From: "A", To: "B"})
+
+	p := &plainOrderer{}
+	if got := OrderContext(context.Background(), p, g); !p.called || len(got) != 2 {
+		t.Errorf("OrderContext() did not fall back to OrderRows: %v", got)
+	}
+	if got := OrderContext(context.Background(), Barycentric{}, g); !slices.Equal(got[1], []string{"B"}) {
+		t.Errorf("OrderContext(Barycentric) = %v", got)
+	}
+}
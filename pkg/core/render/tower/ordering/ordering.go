@@ -21,6 +21,15 @@ type ContextOrderer interface {
 	OrderRowsContext(ctx context.Context, g *dag.DAG) map[int][]string
 }
 
+// OrderContext orders the rows of g with o, passing ctx along when o is a
+// [ContextOrderer]. Other orderers run to completion.
+func OrderContext(ctx context.Context, o Orderer, g *dag.DAG) map[int][]string {
+	if co, ok := o.(ContextOrderer); ok {
+		return co.OrderRowsContext(ctx, g)
+	}
+	return o.OrderRows(g)
+}
+
 // Quality represents the desired trade-off between ordering speed and quality.
 type Quality int
 
@@ -7,7 +7,8 @@
 //
 //   - SVG: Scalable vector graphics with interactivity
 //   - PDF: Print-ready output (requires rsvg-convert)
-//   - PNG: Raster image output (requires rsvg-convert)
+//   - PNG: Raster image output
+//   - HTML: Self-contained interactive page around the SVG
 //
 // # SVG Output
 //
@@ -32,9 +33,42 @@
 //   - [WithGraph]: Required for edge rendering and metadata access
 //   - [WithStyle]: Visual style ([styles.Simple] or [handdrawn.New])
 //   - [WithEdges]: Show dependency edges as dashed lines
+//   - [WithEdgeRouting]: Route edges orthogonally along row boundaries or as
+//     curves instead of straight center-to-center lines
+//   - [WithEdgeBundling]: Share one trunk for the edges of high fan-out blocks
 //   - [WithMerged]: Merge subdivider blocks into continuous columns
 //   - [WithPopups]: Enable hover popups with package metadata
+//   - [WithPopupFields], [WithPopupTemplate]: Add custom metadata keys to
+//     popups or replace the popup body with a text/template
 //   - [WithNebraska]: Add maintainer ranking panel
+//   - [WithFooter]: Stamp a provenance footer (project, resolve time,
+//     version, package counts) below the tower
+//   - [WithBranding]: Replace the stacktower.io watermark with custom text
+//   - [WithHighlight]: Emphasize selected packages and dim the rest
+//     ([WithHighlightFunc] for a predicate, [WithHighlightColor] for the accent)
+//   - [WithColorBy]: Fill blocks by license family, language, owner,
+//     vulnerability severity, staleness, or freshness and add a legend;
+//     works with every style
+//   - [WithBrittleThresholds]: Replace the one-year/two-year rule behind
+//     brittle blocks and the staleness and freshness colourings
+//   - [WithPalette]: Colours for [WithColorBy], e.g. [styles.PaletteOkabeIto]
+//     or a brand palette from [styles.ParsePalette]
+//   - [WithIcons]: Draw each package's embedded icon in the corner of its block
+//   - [WithVulnerabilities]: Badge affected blocks with their advisory count,
+//     coloured by the worst severity, from [feature.Vulnerabilities]
+//
+// # Tiled Output
+//
+// Towers with hundreds of blocks are unreadable as a single page.
+// [RenderSVGTiles] splits the layout into a grid of standalone SVG pages with
+// overlapping edges and row labels, [RenderSVGOverview] draws the full tower
+// with numbered tile outlines, and [RenderTiledPDF] combines both into one
+// multi-page PDF:
+//
+//	tiles := sink.RenderSVGTiles(layout,
+//	    sink.WithTileSize(1200, 900),
+//	    sink.WithTileSVGOptions(sink.WithGraph(g)),
+//	)
 //
 // # PDF and PNG Output
 //
@@ -44,13 +78,39 @@
 //	pdf, err := sink.RenderPDF(layout, opts...)
 //	png, err := sink.RenderPNG(layout, sink.WithScale(2), opts...)
 //
-// These require librsvg to be installed:
+// PDF requires librsvg to be installed:
 //   - macOS: brew install librsvg
 //   - Linux: apt install librsvg2-bin
 //
+// PNG uses rsvg-convert when it is installed and rasterizes in process
+// otherwise.
+//
 // The conversion functions are shared with [nodelink] so both visualization
 // types can export to PDF/PNG.
 //
+// # Slide Decks
+//
+// [RenderPPTX] produces a PowerPoint deck for architecture reviews: the
+// tower is embedded as SVG with a PNG fallback, the speaker notes list the
+// Nebraska maintainer ranking, and [WithPPTXTiles] gives each tile its own
+// slide:
+//
+//	deck, err := sink.RenderPPTX(layout,
+//	    sink.WithPPTXTitle("flask"),
+//	    sink.WithPPTXSVGOptions(sink.WithGraph(g)),
+//	)
+//
+// # HTML Output
+//
+// [RenderHTML] wraps the SVG in a page that works offline, for towers with
+// hundreds of blocks: it pans and zooms, a search box highlights matching
+// blocks, and clicking a block shows its metadata in a collapsible sidebar:
+//
+//	page, err := sink.RenderHTML(layout,
+//	    sink.WithHTMLTitle("flask"),
+//	    sink.WithHTMLSVGOptions(sink.WithGraph(g), sink.WithPopups()),
+//	)
+//
 // [render.ToPDF]: github.com/stacktower-io/stacktower/pkg/core/render.ToPDF
 // [render.ToPNG]: github.com/stacktower-io/stacktower/pkg/core/render.ToPNG
 // [nodelink]: github.com/stacktower-io/stacktower/pkg/core/render/nodelink
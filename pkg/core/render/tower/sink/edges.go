@@ -0,0 +1,227 @@
+package sink
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+)
+
+// EdgeRouting selects how dependency edges are drawn by [WithEdges].
+type EdgeRouting int
+
+const (
+	// EdgeRoutingStraight draws a line between block centers (the default).
+	EdgeRoutingStraight EdgeRouting = iota
+	// EdgeRoutingOrthogonal draws right-angled edges whose horizontal runs
+	// follow the row boundaries, so they never cross block labels.
+	EdgeRoutingOrthogonal
+	// EdgeRoutingCurved draws S-curves that leave the parent near its lower
+	// edge and enter the child near its upper edge.
+	EdgeRoutingCurved
+)
+
+const (
+	edgePortInset = 12.0 // How far inside a block a routed edge starts/ends
+	edgeLaneGap   = 4.0  // Vertical spacing between parallel orthogonal runs
+)
+
+// ParseEdgeRouting converts a routing name ("straight", "orthogonal",
+// "curved") to an [EdgeRouting]. The empty string maps to straight.
+func ParseEdgeRouting(s string) (EdgeRouting, error) {
+	switch strings.ToLower(s) {
+	case "", "straight":
+		return EdgeRoutingStraight, nil
+	case "orthogonal":
+		return EdgeRoutingOrthogonal, nil
+	case "curved":
+		return EdgeRoutingCurved, nil
+	}
+	return EdgeRoutingStraight, fmt.Errorf("unknown edge routing %q (want straight, orthogonal, or curved)", s)
+}
+
+func (r EdgeRouting) String() string {
+	switch r {
+	case EdgeRoutingOrthogonal:
+		return "orthogonal"
+	case EdgeRoutingCurved:
+		return "curved"
+	default:
+		return "straight"
+	}
+}
+
+// WithEdgeRouting sets how edges are routed. It has no effect unless
+// [WithEdges] is also given.
+func WithEdgeRouting(routing EdgeRouting) SVGOption {
+	return func(r *svgRenderer) { r.edgeRouting = routing }
+}
+
+// WithEdgeBundling merges the outgoing edges of every block with at least
+// minFanOut dependencies into a shared trunk that splits just above the
+// children, which keeps high fan-out packages from drawing a fan of
+// overlapping lines. A value below 2 disables bundling.
+func WithEdgeBundling(minFanOut int) SVGOption {
+	return func(r *svgRenderer) { r.bundleFanOut = minFanOut }
+}
+
+// edges builds the edges for l and applies the configured routing.
+func (r *svgRenderer) edges(l layout.Layout) []styles.Edge {
+	edges := buildEdges(l, r.graph, r.merged)
+	if r.edgeRouting == EdgeRoutingStraight && r.bundleFanOut < 2 {
+		return edges
+	}
+	routeEdges(l, edges, r.edgeRouting, r.bundleFanOut)
+	return edges
+}
+
+// port is the point where a routed edge leaves or enters a block.
+type port struct{ x, y float64 }
+
+func outPort(b layout.Block) port {
+	return port{b.CenterX(), b.Top - min(edgePortInset, b.Height()/4)}
+}
+
+func inPort(b layout.Block) port {
+	return port{b.CenterX(), b.Bottom + min(edgePortInset, b.Height()/4)}
+}
+
+// routeEdges fills in Edge.Path for every edge whose endpoints are in l.
+func routeEdges(l layout.Layout, edges []styles.Edge, routing EdgeRouting, bundleFanOut int) {
+	fanOut := make(map[string]int)
+	for _, e := range edges {
+		fanOut[e.FromID]++
+	}
+	bundled := func(id string) bool { return bundleFanOut >= 2 && fanOut[id] >= bundleFanOut }
+
+	lanes := assignLanes(l, edges)
+	junctions := bundleJunctions(l, edges, routing, bundled)
+
+	for i := range edges {
+		e := &edges[i]
+		if routing == EdgeRoutingStraight && !bundled(e.FromID) {
+			continue // Leave plain lines to the style (e.g. hand-drawn curves)
+		}
+		src, okS := l.Blocks[e.FromID]
+		dst, okD := l.Blocks[e.ToID]
+		if !okS || !okD {
+			continue
+		}
+
+		var (
+			start, end port
+			path       strings.Builder
+		)
+		if routing == EdgeRoutingStraight {
+			start, end = port{e.X1, e.Y1}, port{e.X2, e.Y2}
+		} else {
+			start, end = outPort(src), inPort(dst)
+		}
+		fmt.Fprintf(&path, "M %.2f %.2f", start.x, start.y)
+		e.X1, e.Y1, e.X2, e.Y2 = start.x, start.y, end.x, end.y
+
+		if j, ok := junctions[e.FromID]; ok && bundled(e.FromID) {
+			fmt.Fprintf(&path, " L %.2f %.2f", j.x, j.y)
+			start = j
+		}
+
+		switch routing {
+		case EdgeRoutingOrthogonal:
+			y := laneY(dst, start.y, end.y, lanes[laneKey{e.FromID, dst.Bottom}])
+			fmt.Fprintf(&path, " V %.2f H %.2f V %.2f", y, end.x, end.y)
+		case EdgeRoutingCurved:
+			my := (start.y + end.y) / 2
+			fmt.Fprintf(&path, " C %.2f %.2f %.2f %.2f %.2f %.2f", start.x, my, end.x, my, end.x, end.y)
+		default:
+			fmt.Fprintf(&path, " L %.2f %.2f", end.x, end.y)
+		}
+
+		e.Path = path.String()
+	}
+}
+
+// laneKey identifies one horizontal run: a source crossing a row boundary.
+type laneKey struct {
+	from     string
+	boundary float64
+}
+
+type lane struct{ index, count int }
+
+// assignLanes spreads the horizontal runs of different sources that share a
+// row boundary so that orthogonal edges don't draw on top of each other.
+func assignLanes(l layout.Layout, edges []styles.Edge) map[laneKey]lane {
+	byBoundary := make(map[float64][]string)
+	seen := make(map[laneKey]bool)
+	for _, e := range edges {
+		dst, ok := l.Blocks[e.ToID]
+		if !ok {
+			continue
+		}
+		k := laneKey{e.FromID, dst.Bottom}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		byBoundary[dst.Bottom] = append(byBoundary[dst.Bottom], e.FromID)
+	}
+
+	lanes := make(map[laneKey]lane, len(seen))
+	for boundary, sources := range byBoundary {
+		slices.SortFunc(sources, func(a, b string) int {
+			return cmp.Or(cmp.Compare(l.Blocks[a].CenterX(), l.Blocks[b].CenterX()), cmp.Compare(a, b))
+		})
+		for i, id := range sources {
+			lanes[laneKey{id, boundary}] = lane{i, len(sources)}
+		}
+	}
+	return lanes
+}
+
+// laneY returns the y coordinate of a horizontal run into dst, offset from
+// the row boundary by the lane and kept between the edge's two ports.
+func laneY(dst layout.Block, fromY, toY float64, ln lane) float64 {
+	y := dst.Bottom
+	if ln.count > 1 {
+		y += (float64(ln.index) - float64(ln.count-1)/2) * edgeLaneGap
+	}
+	lo, hi := min(fromY, toY), max(fromY, toY)
+	return math.Max(lo, math.Min(hi, y))
+}
+
+// bundleJunctions returns, for each bundled source, the point where its
+// shared trunk splits into the individual edges.
+func bundleJunctions(l layout.Layout, edges []styles.Edge, routing EdgeRouting, bundled func(string) bool) map[string]port {
+	nearest := make(map[string]float64)
+	for _, e := range edges {
+		if !bundled(e.FromID) {
+			continue
+		}
+		dst, ok := l.Blocks[e.ToID]
+		if !ok {
+			continue
+		}
+		y := dst.CenterY()
+		if routing != EdgeRoutingStraight {
+			y = inPort(dst).y
+		}
+		if cur, ok := nearest[e.FromID]; !ok || y < cur {
+			nearest[e.FromID] = y
+		}
+	}
+
+	junctions := make(map[string]port, len(nearest))
+	for id, nearestY := range nearest {
+		src := l.Blocks[id]
+		from := port{src.CenterX(), src.CenterY()}
+		if routing != EdgeRoutingStraight {
+			from = outPort(src)
+		}
+		junctions[id] = port{from.x, from.y + (nearestY-from.y)/2}
+	}
+	return junctions
+}
@@ -0,0 +1,124 @@
+package sink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+)
+
+func fanOutGraph() *dag.DAG {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "root", Row: 0})
+	for _, id := range []string{"a", "b", "c", "d"} {
+		g.AddNode(dag.Node{ID: id, Row: 1})
+		g.AddEdge(dag.Edge{From: "root", To: id})
+	}
+	return g
+}
+
+func TestParseEdgeRouting(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    EdgeRouting
+		wantErr bool
+	}{
+		{"", EdgeRoutingStraight, false},
+		{"straight", EdgeRoutingStraight, false},
+		{"Orthogonal", EdgeRoutingOrthogonal, false},
+		{"curved", EdgeRoutingCurved, false},
+		{"spline", EdgeRoutingStraight, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseEdgeRouting(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseEdgeRouting(%q) = %v, %v; want %v, err=%v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestEdges_StraightUnchanged(t *testing.T) {
+	g := fanOutGraph()
+	l := layout.Build(g, 400, 300)
+	r := newSVGRenderer(WithGraph(g), WithEdges())
+
+	for _, e := range r.edges(l) {
+		if e.Path != "" {
+			t.Errorf("straight edge %s->%s should have no path, got %q", e.FromID, e.ToID, e.Path)
+		}
+	}
+}
+
+func TestEdges_Orthogonal(t *testing.T) {
+	g := fanOutGraph()
+	l := layout.Build(g, 400, 300)
+	r := newSVGRenderer(WithGraph(g), WithEdges(), WithEdgeRouting(EdgeRoutingOrthogonal))
+
+	edges := r.edges(l)
+	if len(edges) != 4 {
+		t.Fatalf("got %d edges, want 4", len(edges))
+	}
+	root := l.Blocks["root"]
+	for _, e := range edges {
+		if !strings.Contains(e.Path, " V ") || !strings.Contains(e.Path, " H ") {
+			t.Errorf("orthogonal path should use V/H segments: %q", e.Path)
+		}
+		dst := l.Blocks[e.ToID]
+		// Ports sit near the shared boundary, away from the centered labels.
+		if e.Y1 <= root.CenterY() || e.Y2 >= dst.CenterY() {
+			t.Errorf("edge %s->%s ports (%.1f, %.1f) should lie between block centers", e.FromID, e.ToID, e.Y1, e.Y2)
+		}
+	}
+}
+
+func TestEdges_Curved(t *testing.T) {
+	g := fanOutGraph()
+	l := layout.Build(g, 400, 300)
+	r := newSVGRenderer(WithGraph(g), WithEdges(), WithEdgeRouting(EdgeRoutingCurved))
+
+	for _, e := range r.edges(l) {
+		if !strings.Contains(e.Path, " C ") {
+			t.Errorf("curved path should use a cubic segment: %q", e.Path)
+		}
+	}
+}
+
+func TestEdges_Bundling(t *testing.T) {
+	g := fanOutGraph()
+	l := layout.Build(g, 400, 300)
+
+	trunk := func(e styles.Edge) string {
+		// "M x y L jx jy ..." — the first two commands form the shared trunk.
+		f := strings.Fields(e.Path)
+		return strings.Join(f[:6], " ")
+	}
+
+	r := newSVGRenderer(WithGraph(g), WithEdges(), WithEdgeBundling(3))
+	edges := r.edges(l)
+	first := trunk(edges[0])
+	for _, e := range edges[1:] {
+		if trunk(e) != first {
+			t.Errorf("bundled edges should share a trunk: %q vs %q", trunk(e), first)
+		}
+	}
+
+	// Below the threshold, edges are left alone.
+	r = newSVGRenderer(WithGraph(g), WithEdges(), WithEdgeBundling(5))
+	for _, e := range r.edges(l) {
+		if e.Path != "" {
+			t.Errorf("fan-out below threshold should not be bundled: %q", e.Path)
+		}
+	}
+}
+
+func TestRenderSVG_EdgeRoutingWithStyles(t *testing.T) {
+	g := fanOutGraph()
+	l := layout.Build(g, 400, 300)
+
+	svg := string(RenderSVG(l, WithGraph(g), WithEdges(), WithEdgeRouting(EdgeRoutingOrthogonal), WithStyle(styles.Simple{})))
+	if got := strings.Count(svg, `<path class="edge"`); got != 4 {
+		t.Errorf("routed edges drawn = %d, want 4", got)
+	}
+}
@@ -0,0 +1,291 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
+)
+
+// HTMLOption configures HTML rendering.
+type HTMLOption func(*htmlRenderer)
+
+type htmlRenderer struct {
+	svgOpts []SVGOption
+	title   string
+}
+
+// WithHTMLSVGOptions passes options through to the underlying SVG renderer.
+// The sidebar lists metadata only when they include [WithGraph].
+func WithHTMLSVGOptions(opts ...SVGOption) HTMLOption {
+	return func(r *htmlRenderer) { r.svgOpts = opts }
+}
+
+// WithHTMLTitle sets the page title; empty uses "Dependencies".
+func WithHTMLTitle(title string) HTMLOption {
+	return func(r *htmlRenderer) { r.title = title }
+}
+
+// htmlBlock is what the sidebar shows for a block.
+type htmlBlock struct {
+	Label       string      `json:"label"`
+	Version     string      `json:"version,omitempty"`
+	Description string      `json:"description,omitempty"`
+	URL         string      `json:"url,omitempty"`
+	Fields      [][2]string `json:"fields,omitempty"`
+}
+
+// RenderHTML wraps the SVG tower in a self-contained HTML page for towers
+// too large to navigate as a static image. The page pans by dragging and
+// zooms with the mouse wheel or the toolbar, a search box highlights the
+// blocks whose package name matches and Enter centers each of them in turn,
+// and clicking a block lists its metadata in a collapsible sidebar.
+//
+// The page needs no network access: styles, scripts and block metadata are
+// inlined, and the SVG keeps its embedded fonts.
+func RenderHTML(l layout.Layout, opts ...HTMLOption) ([]byte, error) {
+	var r htmlRenderer
+	for _, opt := range opts {
+		opt(&r)
+	}
+	if r.title == "" {
+		r.title = "Dependencies"
+	}
+	svg := RenderSVG(l, r.svgOpts...)
+	data, err := json.Marshal(htmlBlocks(l, newSVGRenderer(r.svgOpts...).graph))
+	if err != nil {
+		return nil, fmt.Errorf("render html: %w", err)
+	}
+
+	title := html.EscapeString(r.title)
+	var buf bytes.Buffer
+	buf.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	buf.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	fmt.Fprintf(&buf, "<title>%s</title>\n", title)
+	fmt.Fprintf(&buf, "<style>%s\n</style>\n</head>\n<body>\n", htmlCSS)
+	fmt.Fprintf(&buf, "<header><h1>%s</h1><input id=\"search\" type=\"search\" placeholder=\"Search packages…\" autocomplete=\"off\"><span id=\"matches\"></span>", title)
+	buf.WriteString("<nav><button id=\"zoom-in\" title=\"Zoom in\">+</button><button id=\"zoom-out\" title=\"Zoom out\">−</button><button id=\"zoom-fit\" title=\"Fit to window\">Fit</button><button id=\"toggle-sidebar\" title=\"Show or hide details\">Details</button></nav></header>\n")
+	buf.WriteString("<div id=\"page\">\n<main id=\"stage\">\n")
+	buf.Write(svg)
+	buf.WriteString("</main>\n<aside id=\"sidebar\"><p class=\"hint\">Click a block to see its details.</p></aside>\n</div>\n")
+	fmt.Fprintf(&buf, "<script type=\"application/json\" id=\"block-data\">%s</script>\n", data)
+	fmt.Fprintf(&buf, "<script>%s\n</script>\n</body>\n</html>\n", htmlJS)
+	return buf.Bytes(), nil
+}
+
+// htmlBlocks collects the sidebar entries of all labelled blocks, keyed by
+// block ID. Subdivider blocks describe the package they belong to.
+func htmlBlocks(l layout.Layout, g *dag.DAG) map[string]htmlBlock {
+	blocks := make(map[string]htmlBlock, len(l.Blocks))
+	for id, b := range l.Blocks {
+		if shouldSkipText(g, id) {
+			continue
+		}
+		entry := htmlBlock{Label: b.NodeID}
+		if g != nil {
+			if n, ok := g.Node(id); ok {
+				if m, ok := g.Node(n.EffectiveID()); ok {
+					n = m
+				}
+				describeBlock(&entry, g, n)
+			}
+		}
+		blocks[id] = entry
+	}
+	return blocks
+}
+
+// describeBlock fills a sidebar entry from a node's metadata, using the
+// same description and rows as the hover popups.
+func describeBlock(entry *htmlBlock, g *dag.DAG, n *dag.Node) {
+	entry.Label = n.ID
+	add := func(label, value string) {
+		if value != "" {
+			entry.Fields = append(entry.Fields, [2]string{label, value})
+		}
+	}
+	if n.Meta != nil {
+		entry.Version, _ = n.Meta["version"].(string)
+		repo, _ := n.Meta[metadata.RepoURL].(string)
+		home, _ := n.Meta[metadata.HomePage].(string)
+		if entry.URL = webURL(repo); entry.URL == "" {
+			entry.URL = webURL(home)
+		}
+	}
+	if p := extractPopupData(n); p != nil {
+		if p.Description != n.ID {
+			entry.Description = p.Description
+		}
+		add("license", p.License)
+		if p.Stars > 0 {
+			add("stars", strconv.Itoa(p.Stars))
+		}
+		add("last release", p.LastRelease)
+		add("last commit", p.LastCommit)
+		add("vulnerabilities", p.VulnSeverity)
+		if p.Archived {
+			add("archived", "yes")
+		}
+		for _, f := range p.Fields {
+			add(f.Label, f.Value)
+		}
+	}
+	add("dependencies", strconv.Itoa(len(g.Children(n.ID))))
+	add("dependents", strconv.Itoa(len(g.Parents(n.ID))))
+}
+
+// webURL returns s if it is an http or https URL, and "" otherwise: the
+// URLs come from registry metadata and end up as link targets.
+func webURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	return s
+}
+
+const htmlCSS = `
+    html, body { height: 100%; }
+    body { margin: 0; display: flex; flex-direction: column; font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; background: #fff; color: #1f2937; }
+    header { display: flex; gap: 12px; align-items: center; padding: 10px 16px; border-bottom: 1px solid #e5e7eb; }
+    h1 { margin: 0; font-size: 16px; font-weight: 600; }
+    #search { flex: 0 1 280px; padding: 6px 10px; font-size: 14px; border: 1px solid #d1d5db; border-radius: 6px; }
+    #matches { font-size: 13px; color: #6b7280; }
+    nav { margin-left: auto; display: flex; gap: 6px; }
+    nav button { min-width: 32px; padding: 5px 10px; font-size: 14px; background: #fff; border: 1px solid #d1d5db; border-radius: 6px; cursor: pointer; }
+    nav button:hover { background: #f3f4f6; }
+    #page { flex: 1; display: flex; min-height: 0; }
+    #stage { flex: 1; position: relative; overflow: hidden; cursor: grab; touch-action: none; }
+    #stage.panning { cursor: grabbing; }
+    #stage > svg { position: absolute; top: 0; left: 0; transform-origin: 0 0; }
+    #sidebar { width: 320px; overflow-y: auto; padding: 16px; border-left: 1px solid #e5e7eb; font-size: 14px; }
+    #sidebar.collapsed { display: none; }
+    #sidebar h2 { margin: 0 0 4px; font-size: 18px; word-break: break-all; }
+    #sidebar .version { margin: 0 0 12px; color: #6b7280; }
+    #sidebar dl { display: grid; grid-template-columns: max-content 1fr; gap: 6px 12px; }
+    #sidebar dt { color: #6b7280; }
+    #sidebar dd { margin: 0; word-break: break-word; }
+    #sidebar .hint { color: #6b7280; }
+    svg.searching .block:not(.match) { opacity: 0.2; }
+    svg.searching .block-text:not(.match) { opacity: 0.35; }
+    .block.match, .block.selected { stroke: #f59e0b; stroke-width: 4; }`
+
+const htmlJS = `
+    const stage = document.getElementById('stage');
+    const svg = stage.querySelector('svg');
+    const blocks = JSON.parse(document.getElementById('block-data').textContent);
+    const sidebar = document.getElementById('sidebar');
+    const width = svg.viewBox.baseVal.width, height = svg.viewBox.baseVal.height;
+    let view = { x: 0, y: 0, k: 1 };
+    function apply() { svg.style.transform = 'translate(' + view.x + 'px,' + view.y + 'px) scale(' + view.k + ')'; }
+    function fit() {
+      const k = Math.min(stage.clientWidth / width, stage.clientHeight / height);
+      view = { k, x: (stage.clientWidth - width * k) / 2, y: (stage.clientHeight - height * k) / 2 };
+      apply();
+    }
+    function zoom(factor, cx, cy) {
+      const k = Math.min(Math.max(view.k * factor, 0.05), 40);
+      view.x = cx - (cx - view.x) * k / view.k;
+      view.y = cy - (cy - view.y) * k / view.k;
+      view.k = k;
+      apply();
+    }
+    function center(id) {
+      const el = document.getElementById('block-' + id);
+      if (!el) return;
+      const box = el.getBBox();
+      const local = new DOMPoint(box.x + box.width / 2, box.y + box.height / 2).matrixTransform(el.getCTM());
+      view.x = stage.clientWidth / 2 - local.x * view.k;
+      view.y = stage.clientHeight / 2 - local.y * view.k;
+      apply();
+    }
+    stage.addEventListener('wheel', e => {
+      e.preventDefault();
+      const rect = stage.getBoundingClientRect();
+      zoom(Math.exp(-e.deltaY * 0.002), e.clientX - rect.left, e.clientY - rect.top);
+    }, { passive: false });
+    let drag = null, moved = false;
+    stage.addEventListener('pointerdown', e => {
+      drag = { x: e.clientX - view.x, y: e.clientY - view.y, sx: e.clientX, sy: e.clientY };
+      moved = false;
+    });
+    window.addEventListener('pointermove', e => {
+      if (!drag) return;
+      if (!moved && Math.hypot(e.clientX - drag.sx, e.clientY - drag.sy) < 4) return;
+      moved = true;
+      stage.classList.add('panning');
+      view.x = e.clientX - drag.x;
+      view.y = e.clientY - drag.y;
+      apply();
+    });
+    window.addEventListener('pointerup', () => { drag = null; stage.classList.remove('panning'); });
+    document.getElementById('zoom-in').addEventListener('click', () => zoom(1.25, stage.clientWidth / 2, stage.clientHeight / 2));
+    document.getElementById('zoom-out').addEventListener('click', () => zoom(0.8, stage.clientWidth / 2, stage.clientHeight / 2));
+    document.getElementById('zoom-fit').addEventListener('click', fit);
+    document.getElementById('toggle-sidebar').addEventListener('click', () => sidebar.classList.toggle('collapsed'));
+
+    function el(tag, text, cls) {
+      const e = document.createElement(tag);
+      if (text !== undefined) e.textContent = text;
+      if (cls) e.className = cls;
+      return e;
+    }
+    function select(id) {
+      const b = blocks[id];
+      if (!b) return;
+      svg.querySelectorAll('.block.selected').forEach(x => x.classList.remove('selected'));
+      const block = document.getElementById('block-' + id);
+      if (block) block.classList.add('selected');
+      sidebar.replaceChildren(el('h2', b.label));
+      if (b.version) sidebar.append(el('p', b.version, 'version'));
+      if (b.description) sidebar.append(el('p', b.description));
+      if (b.fields) {
+        const dl = el('dl');
+        b.fields.forEach(([k, v]) => dl.append(el('dt', k), el('dd', v)));
+        sidebar.append(dl);
+      }
+      if (b.url) {
+        const a = el('a', b.url.replace(/^https?:\/\//, ''));
+        a.href = b.url;
+        a.target = '_blank';
+        a.rel = 'noopener';
+        sidebar.append(a);
+      }
+      sidebar.classList.remove('collapsed');
+    }
+    svg.addEventListener('click', e => {
+      const target = e.target.closest('[data-block], .block');
+      if (!target) return;
+      e.preventDefault();
+      if (moved) return;
+      select(target.dataset.block || target.id.replace('block-', ''));
+    });
+
+    const search = document.getElementById('search');
+    const matches = document.getElementById('matches');
+    let hits = [], next = 0;
+    function applySearch() {
+      const q = search.value.trim().toLowerCase();
+      hits = q ? Object.keys(blocks).filter(id => blocks[id].label.toLowerCase().includes(q)).sort() : [];
+      next = 0;
+      const set = new Set(hits);
+      svg.classList.toggle('searching', !!q);
+      svg.querySelectorAll('.block').forEach(b => b.classList.toggle('match', set.has(b.id.replace('block-', ''))));
+      svg.querySelectorAll('.block-text').forEach(t => t.classList.toggle('match', set.has(t.dataset.block)));
+      matches.textContent = q ? (hits.length === 1 ? '1 match' : hits.length + ' matches') : '';
+    }
+    search.addEventListener('input', applySearch);
+    search.addEventListener('keydown', e => {
+      if (e.key !== 'Enter' || !hits.length) return;
+      const id = hits[next++ % hits.length];
+      center(id);
+      select(id);
+    });
+    window.addEventListener('resize', fit);
+    fit();`
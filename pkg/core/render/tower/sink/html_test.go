@@ -0,0 +1,93 @@
+package sink
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
+)
+
+func TestRenderHTML(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Row: 0})
+	g.AddNode(dag.Node{ID: "lib</script>", Row: 1, Meta: dag.Metadata{
+		"version":  "1.2.0",
+		"license":  "MIT",
+		"summary":  "A library.",
+		"repo_url": "https://github.com/acme/lib",
+	}})
+	g.AddEdge(dag.Edge{From: "app", To: "lib</script>"})
+	l := layout.Build(g, 400, 300)
+
+	out, err := RenderHTML(l, WithHTMLTitle("app & co"), WithHTMLSVGOptions(WithGraph(g)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(out)
+
+	for _, want := range []string{"<!DOCTYPE html>", "<title>app &amp; co</title>", `id="search"`, `id="sidebar"`, "<svg", `id="block-app"`} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page missing %q", want)
+		}
+	}
+	if strings.Count(page, "</script>") != strings.Count(page, "<script") {
+		t.Error("block metadata must not close its script element")
+	}
+
+	m := regexp.MustCompile(`(?s)<script type="application/json" id="block-data">(.*?)</script>`).FindStringSubmatch(page)
+	if m == nil {
+		t.Fatal("page has no block data")
+	}
+	var blocks map[string]htmlBlock
+	if err := json.Unmarshal([]byte(m[1]), &blocks); err != nil {
+		t.Fatalf("invalid block data: %v", err)
+	}
+	lib := blocks["lib</script>"]
+	if lib.Version != "1.2.0" || lib.Description != "A library." || lib.URL != "https://github.com/acme/lib" {
+		t.Errorf("lib = %+v", lib)
+	}
+	fields := map[string]string{}
+	for _, f := range lib.Fields {
+		fields[f[0]] = f[1]
+	}
+	if fields["license"] != "MIT" || fields["dependents"] != "1" {
+		t.Errorf("lib fields = %v", fields)
+	}
+	if app := blocks["app"]; app.Description != "" || app.Label != "app" {
+		t.Errorf("app = %+v, want no description for a bare node", app)
+	}
+}
+
+func TestRenderHTML_WithoutGraph(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "A", Row: 0})
+	out, err := RenderHTML(layout.Build(g, 100, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `{"A":{"label":"A"}}`) {
+		t.Errorf("blocks should still be searchable by label:\n%s", out)
+	}
+}
+
+func TestDescribeBlock_OnlyWebURLs(t *testing.T) {
+	g := dag.New(nil)
+	for _, tt := range []struct {
+		repo, home, want string
+	}{
+		{"javascript:alert(document.cookie)", "", ""},
+		{"JavaScript:alert(1)", "https://lib.dev", "https://lib.dev"},
+		{"git+ssh://git@github.com/acme/lib.git", "http://lib.dev", "http://lib.dev"},
+		{"data:text/html,<script>alert(1)</script>", "vbscript:x", ""},
+		{"https://github.com/acme/lib", "https://lib.dev", "https://github.com/acme/lib"},
+	} {
+		var entry htmlBlock
+		describeBlock(&entry, g, &dag.Node{ID: "lib", Meta: dag.Metadata{"repo_url": tt.repo, "homepage": tt.home}})
+		if entry.URL != tt.want {
+			t.Errorf("repo %q, homepage %q: URL = %q, want %q", tt.repo, tt.home, entry.URL, tt.want)
+		}
+	}
+}
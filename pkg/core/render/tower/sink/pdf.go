@@ -1,6 +1,8 @@
 package sink
 
 import (
+	"context"
+
 	"github.com/stacktower-io/stacktower/pkg/core/render"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
 )
@@ -9,6 +11,7 @@ import (
 type PDFOption func(*pdfRenderer)
 
 type pdfRenderer struct {
+	ctx     context.Context
 	svgOpts []SVGOption
 }
 
@@ -17,13 +20,18 @@ func WithPDFSVGOptions(opts ...SVGOption) PDFOption {
 	return func(r *pdfRenderer) { r.svgOpts = opts }
 }
 
+// WithPDFContext stops the conversion when ctx is done.
+func WithPDFContext(ctx context.Context) PDFOption {
+	return func(r *pdfRenderer) { r.ctx = ctx }
+}
+
 // RenderPDF renders the layout as PDF via SVG conversion.
 // Requires librsvg: brew install librsvg (macOS), apt install librsvg2-bin (Linux).
 func RenderPDF(l layout.Layout, opts ...PDFOption) ([]byte, error) {
-	r := pdfRenderer{}
+	r := pdfRenderer{ctx: context.Background()}
 	for _, opt := range opts {
 		opt(&r)
 	}
 	svg := RenderSVG(l, r.svgOpts...)
-	return render.ToPDF(svg)
+	return render.ToPDFContext(r.ctx, svg)
 }
@@ -1,6 +1,8 @@
 package sink
 
 import (
+	"context"
+
 	"github.com/stacktower-io/stacktower/pkg/core/render"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
 )
@@ -9,6 +11,7 @@ import (
 type PNGOption func(*pngRenderer)
 
 type pngRenderer struct {
+	ctx     context.Context
 	svgOpts []SVGOption
 	scale   float64
 }
@@ -18,18 +21,23 @@ func WithPNGSVGOptions(opts ...SVGOption) PNGOption {
 	return func(r *pngRenderer) { r.svgOpts = opts }
 }
 
+// WithPNGContext stops the conversion when ctx is done.
+func WithPNGContext(ctx context.Context) PNGOption {
+	return func(r *pngRenderer) { r.ctx = ctx }
+}
+
 // WithScale sets the PNG scale factor (default 2.0 for 2x resolution).
 func WithScale(s float64) PNGOption {
 	return func(r *pngRenderer) { r.scale = s }
 }
 
-// RenderPNG renders the layout as PNG via SVG conversion.
-// Requires librsvg: brew install librsvg (macOS), apt install librsvg2-bin (Linux).
+// RenderPNG renders the layout as PNG via SVG conversion with
+// [render.ToPNG], which needs no external tools.
 func RenderPNG(l layout.Layout, opts ...PNGOption) ([]byte, error) {
-	r := pngRenderer{scale: 2.0}
+	r := pngRenderer{ctx: context.Background(), scale: 2.0}
 	for _, opt := range opts {
 		opt(&r)
 	}
 	svg := RenderSVG(l, r.svgOpts...)
-	return render.ToPNG(svg, r.scale)
+	return render.ToPNGContext(r.ctx, svg, r.scale)
 }
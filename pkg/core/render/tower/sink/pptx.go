@@ -0,0 +1,120 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/render"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
+)
+
+// PPTXOption configures PowerPoint rendering.
+type PPTXOption func(*pptxRenderer)
+
+type pptxRenderer struct {
+	ctx      context.Context
+	svgOpts  []SVGOption
+	tileOpts []TileOption
+	tiled    bool
+	title    string
+	scale    float64
+	nebraska []feature.NebraskaRanking
+}
+
+// WithPPTXSVGOptions passes options through to the underlying SVG renderer.
+func WithPPTXSVGOptions(opts ...SVGOption) PPTXOption {
+	return func(r *pptxRenderer) { r.svgOpts = opts }
+}
+
+// WithPPTXTiles splits the tower into one slide per tile (see
+// [RenderSVGTiles]), preceded by an overview slide.
+func WithPPTXTiles(opts ...TileOption) PPTXOption {
+	return func(r *pptxRenderer) { r.tiled, r.tileOpts = true, opts }
+}
+
+// WithPPTXTitle sets the slide title (default none).
+func WithPPTXTitle(title string) PPTXOption {
+	return func(r *pptxRenderer) { r.title = title }
+}
+
+// WithPPTXScale sets the scale of the embedded PNG (default 2.0).
+func WithPPTXScale(s float64) PPTXOption {
+	return func(r *pptxRenderer) { r.scale = s }
+}
+
+// WithPPTXContext stops the PNG conversions when ctx is done.
+func WithPPTXContext(ctx context.Context) PPTXOption {
+	return func(r *pptxRenderer) { r.ctx = ctx }
+}
+
+// WithSpeakerNotes sets the maintainer ranking listed in the speaker notes.
+// By default the layout's own Nebraska ranking is used.
+func WithSpeakerNotes(rankings []feature.NebraskaRanking) PPTXOption {
+	return func(r *pptxRenderer) { r.nebraska = rankings }
+}
+
+// RenderPPTX renders the layout as a PowerPoint deck for architecture
+// reviews. Each slide embeds the tower as SVG with a PNG fallback, and the
+// speaker notes list the Nebraska maintainer ranking.
+func RenderPPTX(l layout.Layout, opts ...PPTXOption) ([]byte, error) {
+	r := pptxRenderer{ctx: context.Background(), scale: 2.0, nebraska: l.Nebraska}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	notes := nebraskaNotes(r.nebraska)
+
+	type page struct {
+		title string
+		svg   []byte
+	}
+	var pages []page
+	if r.tiled {
+		tileOpts := append([]TileOption{WithTileSVGOptions(r.svgOpts...)}, r.tileOpts...)
+		tiles := RenderSVGTiles(l, tileOpts...)
+		if len(tiles) > 1 {
+			pages = append(pages, page{r.title, RenderSVGOverview(l, tiles, r.svgOpts...)})
+		}
+		for i, t := range tiles {
+			title := r.title
+			if len(tiles) > 1 {
+				title = strings.TrimSpace(fmt.Sprintf("%s (%d/%d)", r.title, i+1, len(tiles)))
+			}
+			pages = append(pages, page{title, t.SVG})
+		}
+	} else {
+		pages = append(pages, page{r.title, RenderSVG(l, r.svgOpts...)})
+	}
+
+	slides := make([]render.Slide, 0, len(pages))
+	for _, p := range pages {
+		png, err := render.ToPNGContext(r.ctx, p.svg, r.scale)
+		if err != nil {
+			return nil, err
+		}
+		slides = append(slides, render.Slide{Title: p.title, PNG: png, SVG: p.svg, Notes: notes})
+	}
+	return render.ToPPTX(slides)
+}
+
+// nebraskaNotes formats a maintainer ranking as speaker notes.
+func nebraskaNotes(rankings []feature.NebraskaRanking) string {
+	if len(rankings) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	if rankings[0].Org {
+		b.WriteString("Nebraska ranking — organizations this tower rests on:\n")
+	} else {
+		b.WriteString("Nebraska ranking — maintainers this tower rests on:\n")
+	}
+	for i, r := range rankings {
+		pkgs := make([]string, 0, len(r.Packages))
+		for _, p := range r.Packages {
+			pkgs = append(pkgs, fmt.Sprintf("%s (%s)", p.Package, p.Role))
+		}
+		fmt.Fprintf(&b, "%d. %s — score %.1f: %s\n", i+1, r.Maintainer, r.Score, strings.Join(pkgs, ", "))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
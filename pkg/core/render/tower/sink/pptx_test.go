@@ -0,0 +1,33 @@
+package sink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+)
+
+func TestNebraskaNotes(t *testing.T) {
+	if got := nebraskaNotes(nil); got != "" {
+		t.Errorf("nebraskaNotes(nil) = %q, want empty", got)
+	}
+
+	notes := nebraskaNotes([]feature.NebraskaRanking{
+		{Maintainer: "alice", Score: 12.5, Packages: []feature.PackageRole{
+			{Package: "urllib3", Role: feature.RoleOwner},
+			{Package: "idna", Role: feature.RoleMaintainer},
+		}},
+		{Maintainer: "bob", Score: 3},
+	})
+
+	lines := strings.Split(notes, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want header + 2 rankings:\n%s", len(lines), notes)
+	}
+	if want := "1. alice — score 12.5: urllib3 (owner), idna (maintainer)"; lines[1] != want {
+		t.Errorf("line 1 = %q, want %q", lines[1], want)
+	}
+	if !strings.HasPrefix(lines[2], "2. bob") {
+		t.Errorf("line 2 = %q, want bob second", lines[2])
+	}
+}
@@ -5,13 +5,16 @@ import (
 	"cmp"
 	"fmt"
 	"slices"
+	"text/template"
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
 	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
 	"github.com/stacktower-io/stacktower/pkg/fonts"
+	"github.com/stacktower-io/stacktower/pkg/observability"
 	"github.com/stacktower-io/stacktower/pkg/security"
 )
 
@@ -26,10 +29,10 @@ const blockInteractionJS = `
     function highlight(pkgs) {
       document.querySelectorAll('.block').forEach(b => b.classList.toggle('highlight', pkgs.includes(b.id.replace('block-', ''))));
       document.querySelectorAll('.block-text').forEach(t => t.classList.toggle('highlight', pkgs.includes(t.dataset.block)));
-      document.querySelectorAll('.license-flag, .license-stripe, .vuln-flag').forEach(f => f.classList.toggle('highlight', pkgs.includes(f.dataset.block)));
+      document.querySelectorAll('.license-flag, .license-stripe, .vuln-flag, .vuln-badge, .suspect-marker').forEach(f => f.classList.toggle('highlight', pkgs.includes(f.dataset.block)));
     }
     function clearHighlight() {
-      document.querySelectorAll('.block, .block-text, .license-flag, .license-stripe, .vuln-flag').forEach(el => el.classList.remove('highlight'));
+      document.querySelectorAll('.block, .block-text, .license-flag, .license-stripe, .vuln-flag, .vuln-badge, .suspect-marker').forEach(el => el.classList.remove('highlight'));
     }
     document.querySelectorAll('.block').forEach(el => {
       el.addEventListener('mouseenter', () => highlight([el.id.replace('block-', '')]));
@@ -43,13 +46,38 @@ const blockInteractionJS = `
 type SVGOption func(*svgRenderer)
 
 type svgRenderer struct {
+	logger     observability.Logger
 	graph      *dag.DAG
 	style      styles.Style
 	showEdges  bool
 	merged     bool
 	nebraska   []feature.NebraskaRanking
+	vulns      map[string]*vulnBadge
+	suspects   map[string][]string
 	popups     bool
+	icons      bool
 	flagsOnTop bool
+	overlays   []func(*bytes.Buffer)
+
+	highlight      func(id string) bool
+	highlightColor string
+
+	edgeRouting  EdgeRouting
+	bundleFanOut int
+
+	popupFields   []string
+	popupTemplate *template.Template
+	weights       map[string]feature.DirectWeight
+
+	footer    *Footer
+	branded   bool
+	brandText string
+	brandURL  string
+
+	colorBy ColorBy
+	colors  *colorScale
+	palette styles.Palette
+	brittle feature.BrittleThresholds
 }
 
 func WithGraph(g *dag.DAG) SVGOption     { return func(r *svgRenderer) { r.graph = g } }
@@ -61,25 +89,53 @@ func WithNebraska(rankings []feature.NebraskaRanking) SVGOption {
 }
 func WithPopups() SVGOption { return func(r *svgRenderer) { r.popups = true } }
 
+// WithIcons draws each package's icon (the "icon" data URI set by
+// [metadata.Icons]) in the top-left corner of its block.
+func WithIcons() SVGOption { return func(r *svgRenderer) { r.icons = true } }
+
+// WithBrittleThresholds replaces the fixed one-year/two-year rule used to
+// mark brittle blocks and to bucket the staleness and freshness colourings.
+// Zero fields keep their defaults.
+func WithBrittleThresholds(t feature.BrittleThresholds) SVGOption {
+	return func(r *svgRenderer) { r.brittle = t }
+}
+
+// WithLogger receives problems that do not stop rendering, such as a popup
+// template failing for a block.
+func WithLogger(l observability.Logger) SVGOption {
+	return func(r *svgRenderer) { r.logger = l }
+}
+
 // WithFlagsOnTop controls whether security flags (license, vuln) are rendered
 // in a separate pass after all blocks, ensuring they appear on top.
 // Default is true. Set to false to render flags with their blocks.
 func WithFlagsOnTop(on bool) SVGOption { return func(r *svgRenderer) { r.flagsOnTop = on } }
 
+// withOverlay registers extra SVG content drawn above the tower in layout
+// coordinates. It is used internally by sinks that annotate the full render.
+func withOverlay(fn func(*bytes.Buffer)) SVGOption {
+	return func(r *svgRenderer) { r.overlays = append(r.overlays, fn) }
+}
+
 func RenderSVG(l layout.Layout, opts ...SVGOption) []byte {
 	r := newSVGRenderer(opts...)
 
-	blocks := buildBlocks(l, r.graph, r.popups)
+	blocks := buildBlocks(l, &r)
 	slices.SortFunc(blocks, func(a, b styles.Block) int {
 		return cmp.Compare(a.ID, b.ID)
 	})
 
 	var edges []styles.Edge
 	if r.showEdges {
-		edges = buildEdges(l, r.graph, r.merged)
+		edges = r.edges(l)
 	}
 
 	totalWidth, totalHeight := calculateDimensions(l, r.nebraska)
+	legendH := legendHeight(&r, totalWidth)
+	totalHeight += legendH
+	if r.footer != nil {
+		totalHeight += footerHeight
+	}
 
 	// Pre-size buffer to reduce reallocations: ~500 bytes per block, ~100 per edge
 	estimatedSize := len(blocks)*500 + len(edges)*100 + 8192
@@ -88,11 +144,20 @@ func RenderSVG(l layout.Layout, opts ...SVGOption) []byte {
 		totalWidth, totalHeight, totalWidth, totalHeight)
 
 	r.style.RenderDefs(buf)
+	if bg, _ := themeColors(r.style); bg != "" {
+		fmt.Fprintf(buf, `  <rect class="canvas" x="0" y="0" width="%.1f" height="%.1f" fill="%s"/>`+"\n",
+			l.FrameWidth, l.FrameHeight+watermarkMargin, bg)
+	}
 	renderContent(buf, &r, blocks, edges)
+	renderOverlays(buf, r.overlays)
 	renderBlockInteraction(buf)
 
 	if len(r.nebraska) > 0 {
-		renderNebraskaPanel(buf, l.FrameWidth, l.FrameHeight, r.nebraska)
+		var bus feature.BusFactorReport
+		if r.graph != nil {
+			bus = feature.BusFactor(r.graph)
+		}
+		renderNebraskaPanel(buf, l.FrameWidth, l.FrameHeight, r.nebraska, bus)
 		renderNebraskaScript(buf)
 	}
 
@@ -103,8 +168,14 @@ func RenderSVG(l layout.Layout, opts ...SVGOption) []byte {
 		renderPopupScript(buf)
 	}
 
-	// Add watermark
-	renderWatermark(buf, l.FrameWidth)
+	// Add watermark, colour legend and provenance footer
+	renderWatermark(buf, &r, l.FrameWidth)
+	footerY := totalHeight
+	if r.footer != nil {
+		footerY -= footerHeight
+		renderFooter(buf, &r, 0, footerY, totalWidth)
+	}
+	renderLegend(buf, &r, 0, footerY-legendH, totalWidth)
 
 	buf.WriteString("</svg>\n")
 	return buf.Bytes()
@@ -118,6 +189,11 @@ func newSVGRenderer(opts ...SVGOption) svgRenderer {
 	for _, opt := range opts {
 		opt(&r)
 	}
+	r.logger = observability.LoggerOrNoop(r.logger)
+	r.colors = newColorScale(r.colorBy, r.graph, r.palette, r.brittle)
+	if r.popups && r.graph != nil {
+		r.weights = feature.WeightsByPackage(r.graph)
+	}
 	return r
 }
 
@@ -145,31 +221,54 @@ func renderContent(buf *bytes.Buffer, r *svgRenderer, blocks []styles.Block, edg
 	fmt.Fprintf(buf, `  <g transform="translate(0, %.1f)">`+"\n", watermarkMargin)
 
 	for _, b := range blocks {
-		r.style.RenderBlock(buf, b)
-		if !r.flagsOnTop {
-			// Render flags inline with each block
-			r.style.RenderFlags(buf, b)
-		}
+		r.emphasize(buf, r.isHighlighted(b.ID), func() {
+			styles.RenderShape(buf, r.style, b)
+			styles.RenderIcon(buf, b)
+			if !r.flagsOnTop {
+				// Render flags inline with each block
+				r.style.RenderFlags(buf, b)
+			}
+		})
 	}
+	renderHighlightAccents(buf, r, blocks)
 	for _, e := range edges {
-		r.style.RenderEdge(buf, e)
+		r.emphasize(buf, r.isHighlighted(e.FromID) || r.isHighlighted(e.ToID), func() {
+			r.style.RenderEdge(buf, e)
+		})
 	}
 	for _, b := range blocks {
 		if shouldSkipText(r.graph, b.ID) {
 			continue
 		}
-		r.style.RenderText(buf, b)
+		r.emphasize(buf, r.isHighlighted(b.ID), func() {
+			r.style.RenderText(buf, b)
+		})
 	}
 	if r.flagsOnTop {
 		// Render flags last so they always appear on top of all blocks
 		for _, b := range blocks {
-			r.style.RenderFlags(buf, b)
+			r.emphasize(buf, r.isHighlighted(b.ID), func() {
+				r.style.RenderFlags(buf, b)
+			})
 		}
 	}
+	renderVulnBadges(buf, r, blocks)
+	renderSuspectMarkers(buf, r, blocks)
 
 	buf.WriteString("  </g>\n")
 }
 
+func renderOverlays(buf *bytes.Buffer, overlays []func(*bytes.Buffer)) {
+	if len(overlays) == 0 {
+		return
+	}
+	fmt.Fprintf(buf, `  <g class="overlay" transform="translate(0, %.1f)">`+"\n", watermarkMargin)
+	for _, fn := range overlays {
+		fn(buf)
+	}
+	buf.WriteString("  </g>\n")
+}
+
 func shouldSkipText(g *dag.DAG, id string) bool {
 	if g == nil {
 		return false
@@ -183,7 +282,8 @@ func renderBlockInteraction(buf *bytes.Buffer) {
 	fmt.Fprintf(buf, "  <script type=\"text/javascript\"><![CDATA[%s\n  ]]></script>\n", blockInteractionJS)
 }
 
-func buildBlocks(l layout.Layout, g *dag.DAG, withPopups bool) []styles.Block {
+func buildBlocks(l layout.Layout, r *svgRenderer) []styles.Block {
+	g := r.graph
 	blocks := make([]styles.Block, 0, len(l.Blocks))
 	for id, b := range l.Blocks {
 		blk := styles.Block{
@@ -194,6 +294,15 @@ func buildBlocks(l layout.Layout, g *dag.DAG, withPopups bool) []styles.Block {
 			CX: b.CenterX(), CY: b.CenterY(),
 		}
 		if g != nil {
+			if n, ok := g.Node(id); ok && r.colors != nil {
+				if m, ok := g.Node(n.EffectiveID()); ok {
+					n = m
+				}
+				blk.Fill = r.colors.fill(n)
+			}
+			if n, ok := g.Node(id); ok {
+				blk.Kind = blockKind(n)
+			}
 			if n, ok := g.Node(id); ok && n.Meta != nil {
 				// Prefer repo_url (GitHub), fallback to homepage for packages without repos
 				if url, ok := n.Meta[metadata.RepoURL].(string); ok && url != "" {
@@ -201,7 +310,8 @@ func buildBlocks(l layout.Layout, g *dag.DAG, withPopups bool) []styles.Block {
 				} else if hp, ok := n.Meta[metadata.HomePage].(string); ok && hp != "" {
 					blk.URL = hp
 				}
-				blk.Brittle = feature.IsBrittle(n)
+				blk.Brittle = feature.IsBrittleWith(n, r.brittle)
+				blk.Internal = deps.IsInternal(n)
 				if vs, ok := n.Meta[security.MetaVulnSeverity].(string); ok {
 					blk.VulnSeverity = vs
 				}
@@ -211,8 +321,11 @@ func buildBlocks(l layout.Layout, g *dag.DAG, withPopups bool) []styles.Block {
 				if lr, ok := n.Meta[security.MetaLicenseRisk].(string); ok {
 					blk.LicenseRisk = lr
 				}
-				if withPopups {
-					blk.Popup = extractPopupData(n)
+				if r.popups {
+					blk.Popup = r.popupData(n)
+				}
+				if r.icons {
+					blk.Icon, _ = n.Meta[metadata.Icon].(string)
 				}
 			}
 		}
@@ -221,6 +334,18 @@ func buildBlocks(l layout.Layout, g *dag.DAG, withPopups bool) []styles.Block {
 	return blocks
 }
 
+// blockKind maps a node's kind onto the style hook that draws it.
+func blockKind(n *dag.Node) styles.BlockKind {
+	switch {
+	case n.IsSubdivider():
+		return styles.BlockSubdivider
+	case n.IsAuxiliary():
+		return styles.BlockAuxiliary
+	default:
+		return styles.BlockPackage
+	}
+}
+
 func buildEdges(l layout.Layout, g *dag.DAG, merged bool) []styles.Edge {
 	if g == nil {
 		return nil
@@ -299,27 +424,36 @@ func buildMergedEdges(l layout.Layout, g *dag.DAG) []styles.Edge {
 	return edges
 }
 
-// renderWatermark adds a watermark with the Stacktower logo centered at the top
-func renderWatermark(buf *bytes.Buffer, frameWidth float64) {
+// renderWatermark adds a watermark with the Stacktower logo centered at the top,
+// or the custom text set with WithBranding.
+func renderWatermark(buf *bytes.Buffer, r *svgRenderer, frameWidth float64) {
+	if r.branded {
+		_, fg := themeColors(r.style)
+		renderBranding(buf, r.brandText, r.brandURL, fg, frameWidth)
+		return
+	}
+
+	_, fg := themeColors(r.style)
+
 	// Center horizontally in the reserved watermark space at top
 	x := (frameWidth - 120) / 2     // Center the watermark (icon + text width ~120)
 	y := (watermarkMargin - 10) / 2 // Vertically center in the watermark margin space
 
 	// Stacktower icon (Layers icon from favicon.svg, scaled to match text height)
 	// Original viewBox is 0 0 24 24, we scale it to ~16x16 to match 14px text
-	icon := `<g transform="scale(0.67)">
-      <path d="m12.83 2.18a2 2 0 0 0-1.66 0L2.6 6.08a1 1 0 0 0 0 1.83l8.58 3.91a2 2 0 0 0 1.66 0l8.58-3.9a1 1 0 0 0 0-1.83Z" fill="none" stroke="#000" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"/>
-      <path d="m22 17.65-9.17 4.16a2 2 0 0 1-1.66 0L2 17.65" fill="none" stroke="#000" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"/>
-      <path d="m22 12.65-9.17 4.16a2 2 0 0 1-1.66 0L2 12.65" fill="none" stroke="#000" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"/>
-    </g>`
+	icon := fmt.Sprintf(`<g transform="scale(0.67)">
+      <path d="m12.83 2.18a2 2 0 0 0-1.66 0L2.6 6.08a1 1 0 0 0 0 1.83l8.58 3.91a2 2 0 0 0 1.66 0l8.58-3.9a1 1 0 0 0 0-1.83Z" fill="none" stroke="%[1]s" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"/>
+      <path d="m22 17.65-9.17 4.16a2 2 0 0 1-1.66 0L2 17.65" fill="none" stroke="%[1]s" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"/>
+      <path d="m22 12.65-9.17 4.16a2 2 0 0 1-1.66 0L2 12.65" fill="none" stroke="%[1]s" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"/>
+    </g>`, fg)
 
 	fmt.Fprintf(buf, `  <a href="https://www.stacktower.io" target="_blank" rel="noopener">
     <g transform="translate(%.1f, %.1f)" class="watermark">
       %s
-      <text x="20" y="11" font-family="%s" font-size="14" font-weight="500" fill="#000">stacktower.io</text>
+      <text x="20" y="11" font-family="%s" font-size="14" font-weight="500" fill="%s">stacktower.io</text>
     </g>
   </a>
-`, x, y, icon, fonts.FallbackFontFamily)
+`, x, y, icon, fonts.FallbackFontFamily, fg)
 
 	// Add hover effect
 	buf.WriteString(`  <style>
@@ -328,3 +462,27 @@ func renderWatermark(buf *bytes.Buffer, frameWidth float64) {
   </style>
 `)
 }
+
+// renderBranding draws custom watermark text centered at the top.
+func renderBranding(buf *bytes.Buffer, text, url, color string, frameWidth float64) {
+	if text == "" {
+		return
+	}
+	label := fmt.Sprintf(`<text class="watermark" x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="middle" font-family="%s" font-size="14" font-weight="500" fill="%s">%s</text>`,
+		frameWidth/2, watermarkMargin/2, fonts.FallbackFontFamily, color, styles.EscapeXML(text))
+	if url == "" {
+		buf.WriteString("  " + label + "\n")
+		return
+	}
+	fmt.Fprintf(buf, "  <a href=\"%s\" target=\"_blank\" rel=\"noopener\">%s</a>\n", styles.EscapeXML(url), label)
+}
+
+// themeColors returns the canvas and foreground colors for a style, escaped
+// for use in attributes; the canvas is empty for styles that draw on plain
+// white.
+func themeColors(s styles.Style) (bg, fg string) {
+	if t, ok := s.(styles.Themed); ok {
+		return styles.EscapeXML(t.Background()), styles.EscapeXML(t.Foreground())
+	}
+	return "", "#000"
+}
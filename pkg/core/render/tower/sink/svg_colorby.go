@@ -0,0 +1,389 @@
+package sink
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+	"github.com/stacktower-io/stacktower/pkg/fonts"
+	"github.com/stacktower-io/stacktower/pkg/security"
+)
+
+// ColorBy selects the package metadata that drives block fill colours when
+// passed to [WithColorBy]. Colouring is independent of the style: every
+// style paints blocks with the chosen fill instead of its own.
+type ColorBy int
+
+const (
+	// ColorByNone leaves block colours to the style (the default).
+	ColorByNone ColorBy = iota
+	// ColorByLicense colours blocks by license family (permissive,
+	// weak-copyleft, copyleft, proprietary, unknown).
+	ColorByLicense
+	// ColorByLanguage colours blocks by the repository's primary language.
+	ColorByLanguage
+	// ColorByOwner colours blocks by the repository owner (user or org).
+	ColorByOwner
+	// ColorByVuln colours blocks by their most severe known vulnerability.
+	ColorByVuln
+	// ColorByStaleness colours blocks by time since the last commit.
+	ColorByStaleness
+	// ColorByFreshness colours blocks on a fresh-to-stale gradient combining
+	// time since the last release or commit with versions behind latest
+	// (see [feature.NodeFreshness]).
+	ColorByFreshness
+	// ColorByHealth colours blocks by their composite health score (see
+	// [feature.NodeHealth]), as stored by [feature.AnnotateHealth].
+	ColorByHealth
+)
+
+const (
+	legendRowHeight = 22.0 // Height of one row of legend entries
+	legendPadY      = 6.0  // Padding above and below the legend rows
+	legendSwatch    = 12.0 // Side of a legend colour swatch
+	legendCharWidth = 6.5  // Approximate width of one 11px label character
+	maxCategories   = 8    // Distinct categorical values before grouping into "other"
+
+	colorUnknown = "#e5e7eb" // gray-200 — no metadata
+	colorOther   = "#9ca3af" // gray-400 — long tail of categorical values
+	colorNone    = "#f3f4f6" // gray-100 — no findings
+)
+
+// ParseColorBy converts a colouring name ("license", "language", "owner",
+// "vuln", "staleness", "freshness", "health") to a [ColorBy]. The empty
+// string and "none" map to [ColorByNone].
+func ParseColorBy(s string) (ColorBy, error) {
+	switch strings.ToLower(s) {
+	case "", "none":
+		return ColorByNone, nil
+	case "license":
+		return ColorByLicense, nil
+	case "language":
+		return ColorByLanguage, nil
+	case "owner":
+		return ColorByOwner, nil
+	case "vuln":
+		return ColorByVuln, nil
+	case "staleness":
+		return ColorByStaleness, nil
+	case "freshness":
+		return ColorByFreshness, nil
+	case "health":
+		return ColorByHealth, nil
+	}
+	return ColorByNone, fmt.Errorf("unknown color-by %q (want license, language, owner, vuln, staleness, freshness, or health)", s)
+}
+
+func (c ColorBy) String() string {
+	switch c {
+	case ColorByLicense:
+		return "license"
+	case ColorByLanguage:
+		return "language"
+	case ColorByOwner:
+		return "owner"
+	case ColorByVuln:
+		return "vuln"
+	case ColorByStaleness:
+		return "staleness"
+	case ColorByFreshness:
+		return "freshness"
+	case ColorByHealth:
+		return "health"
+	default:
+		return "none"
+	}
+}
+
+// WithColorBy fills every block according to the given metadata field and
+// adds a legend below the tower. It requires [WithGraph]; without a graph
+// the style's own colours are kept.
+func WithColorBy(c ColorBy) SVGOption {
+	return func(r *svgRenderer) { r.colorBy = c }
+}
+
+// WithPalette sets the colours used by [WithColorBy]. Categorical fields
+// (language, owner) take colours in palette order, most common value first;
+// ordered fields (license, vuln, staleness, freshness, health) map their
+// levels onto the palette in order instead of the built-in traffic-light colours. The
+// default is [styles.PalettePastel] for categorical fields.
+func WithPalette(p styles.Palette) SVGOption {
+	return func(r *svgRenderer) { r.palette = p }
+}
+
+// LegendEntry is one category shown in a colour legend.
+type LegendEntry struct {
+	Label string // Category name, e.g. "copyleft" or "Go"
+	Color string // Fill colour
+	Count int    // Number of packages in the category
+}
+
+// colorScale maps packages to fill colours for one [ColorBy] mode.
+type colorScale struct {
+	category func(n *dag.Node) string
+	colors   map[string]string
+	legend   []LegendEntry
+}
+
+// newColorScale classifies every real package in g and assigns colours from
+// palette, or the built-in colours when palette is empty. It returns nil
+// when colouring is disabled or there is no graph. Staleness and freshness
+// are measured against the brittle thresholds t.
+func newColorScale(by ColorBy, g *dag.DAG, palette styles.Palette, t feature.BrittleThresholds) *colorScale {
+	if by == ColorByNone || g == nil {
+		return nil
+	}
+	s := &colorScale{colors: make(map[string]string)}
+
+	var fixed []LegendEntry // Ordered categories with predefined colours
+	switch by {
+	case ColorByLicense:
+		s.category = licenseCategory
+		fixed = []LegendEntry{
+			{Label: string(security.LicenseRiskPermissive), Color: "#bbf7d0"},   // green-200
+			{Label: string(security.LicenseRiskWeakCopyleft), Color: "#e9d5ff"}, // purple-200
+			{Label: string(security.LicenseRiskCopyleft), Color: "#c084fc"},     // purple-400
+			{Label: string(security.LicenseRiskProprietary), Color: "#fca5a5"},  // red-300
+			{Label: string(security.LicenseRiskUnknown), Color: colorUnknown},
+		}
+	case ColorByVuln:
+		s.category = vulnCategory
+		for _, sev := range []security.Severity{security.SeverityCritical, security.SeverityHigh, security.SeverityMedium, security.SeverityLow} {
+			fixed = append(fixed, LegendEntry{Label: string(sev), Color: sev.Color()})
+		}
+		fixed = append(fixed, LegendEntry{Label: "none", Color: colorNone})
+	case ColorByStaleness:
+		now := time.Now()
+		s.category = func(n *dag.Node) string { return stalenessCategory(n, now, t) }
+		fixed = []LegendEntry{
+			{Label: "active", Color: "#bbf7d0"},    // green-200
+			{Label: "stale", Color: "#fde68a"},     // amber-200
+			{Label: "abandoned", Color: "#fca5a5"}, // red-300
+			{Label: "unknown", Color: colorUnknown},
+		}
+	case ColorByFreshness:
+		now := time.Now()
+		s.category = func(n *dag.Node) string { return feature.NodeFreshness(n, now, t).Level }
+		fixed = []LegendEntry{
+			{Label: feature.FreshnessFresh, Color: "#86efac"},    // green-300
+			{Label: feature.FreshnessCurrent, Color: "#d9f99d"},  // lime-200
+			{Label: feature.FreshnessAging, Color: "#fde68a"},    // amber-200
+			{Label: feature.FreshnessStale, Color: "#fdba74"},    // orange-300
+			{Label: feature.FreshnessOutdated, Color: "#f87171"}, // red-400
+			{Label: feature.FreshnessUnknown, Color: colorUnknown},
+		}
+	case ColorByHealth:
+		s.category = healthCategory
+		fixed = []LegendEntry{
+			{Label: feature.HealthGood, Color: "#86efac"}, // green-300
+			{Label: feature.HealthFair, Color: "#d9f99d"}, // lime-200
+			{Label: feature.HealthWeak, Color: "#fdba74"}, // orange-300
+			{Label: feature.HealthPoor, Color: "#f87171"}, // red-400
+			{Label: feature.HealthUnknown, Color: colorUnknown},
+		}
+	case ColorByLanguage:
+		s.category = metaCategory(metadata.RepoLanguage)
+	case ColorByOwner:
+		s.category = metaCategory(metadata.RepoOwner)
+	default:
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, n := range g.Nodes() {
+		if !n.IsSynthetic() {
+			counts[s.category(n)]++
+		}
+	}
+
+	if fixed == nil {
+		if len(palette) == 0 {
+			palette = styles.PalettePastel
+		}
+		s.legend = categoricalLegend(counts, s.colors, palette)
+		return s
+	}
+	level := 0
+	for _, e := range fixed {
+		// Neutral "no data" levels keep their grey so they never look like a finding.
+		if len(palette) > 0 && e.Color != colorUnknown && e.Color != colorNone {
+			e.Color = palette.Color(level)
+			level++
+		}
+		s.colors[e.Label] = e.Color
+		if e.Count = counts[e.Label]; e.Count > 0 {
+			s.legend = append(s.legend, e)
+		}
+	}
+	return s
+}
+
+// categoricalLegend assigns palette colours to the most common values, most
+// frequent first, recording them in colors. Values beyond the palette are
+// folded into a single "other" entry; "unknown" always comes last.
+func categoricalLegend(counts map[string]int, colors map[string]string, palette styles.Palette) []LegendEntry {
+	cats := make([]string, 0, len(counts))
+	for cat := range counts {
+		if cat != "unknown" {
+			cats = append(cats, cat)
+		}
+	}
+	slices.SortFunc(cats, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+
+	var entries []LegendEntry
+	other := LegendEntry{Label: "other", Color: colorOther}
+	for i, cat := range cats {
+		if i >= min(maxCategories, len(palette)) {
+			colors[cat] = colorOther
+			other.Count += counts[cat]
+			continue
+		}
+		colors[cat] = palette[i]
+		entries = append(entries, LegendEntry{Label: cat, Color: palette[i], Count: counts[cat]})
+	}
+	if other.Count > 0 {
+		entries = append(entries, other)
+	}
+	if n := counts["unknown"]; n > 0 {
+		colors["unknown"] = colorUnknown
+		entries = append(entries, LegendEntry{Label: "unknown", Color: colorUnknown, Count: n})
+	}
+	return entries
+}
+
+// fill returns the colour for a node, or "" to keep the style's default.
+func (s *colorScale) fill(n *dag.Node) string {
+	if s == nil || n == nil {
+		return ""
+	}
+	return s.colors[s.category(n)]
+}
+
+func licenseCategory(n *dag.Node) string {
+	if risk, ok := n.Meta[security.MetaLicenseRisk].(string); ok && security.LicenseRiskFromString(risk) != "" {
+		return risk
+	}
+	lic, _ := n.Meta["license"].(string)
+	if lic == "" {
+		lic, _ = n.Meta[metadata.RepoLicense].(string)
+	}
+	if lic == "" {
+		return string(security.LicenseRiskUnknown)
+	}
+	return string(security.ClassifyLicense(lic))
+}
+
+func vulnCategory(n *dag.Node) string {
+	sev, _ := n.Meta[security.MetaVulnSeverity].(string)
+	if sev == "" {
+		return "none"
+	}
+	if s := security.SeverityFromString(sev); s != security.SeverityUnknown {
+		return string(s)
+	}
+	// Flagged with an unrecognised severity: treat as the mildest level.
+	return string(security.SeverityLow)
+}
+
+// stalenessCategory buckets a package by age of its last commit, falling
+// back to the last release, using the same thresholds as brittleness.
+func stalenessCategory(n *dag.Node, now time.Time, t feature.BrittleThresholds) string {
+	last := feature.ParseDate(n.Meta[metadata.RepoLastCommit])
+	if last.IsZero() {
+		last = feature.ParseDate(n.Meta[metadata.RepoLastRelease])
+	}
+	if last.IsZero() {
+		return "unknown"
+	}
+	d := feature.DefaultBrittleThresholds()
+	stale, abandoned := cmp.Or(t.Stale, d.Stale), cmp.Or(t.Abandoned, d.Abandoned)
+	switch age := now.Sub(last); {
+	case age <= stale:
+		return "active"
+	case age <= abandoned:
+		return "stale"
+	default:
+		return "abandoned"
+	}
+}
+
+// healthCategory buckets a package by its stored health score.
+func healthCategory(n *dag.Node) string {
+	if score, ok := feature.HealthScore(n); ok {
+		return feature.HealthLevel(score)
+	}
+	return feature.HealthUnknown
+}
+
+func metaCategory(key string) func(n *dag.Node) string {
+	return func(n *dag.Node) string {
+		if v, ok := n.Meta[key].(string); ok && v != "" {
+			return v
+		}
+		return "unknown"
+	}
+}
+
+// legendLayout splits entries into rows that fit width and returns the
+// x offset of each entry together with the total legend height.
+func legendLayout(entries []LegendEntry, width float64) (xs []float64, rows []int, height float64) {
+	if len(entries) == 0 {
+		return nil, nil, 0
+	}
+	x, row := 12.0, 0
+	for _, e := range entries {
+		w := legendEntryWidth(e)
+		if x > 12 && x+w > width-12 {
+			x, row = 12, row+1
+		}
+		xs = append(xs, x)
+		rows = append(rows, row)
+		x += w
+	}
+	return xs, rows, float64(row+1)*legendRowHeight + 2*legendPadY
+}
+
+func legendEntryWidth(e LegendEntry) float64 {
+	return legendSwatch + 6 + float64(len(legendLabel(e)))*legendCharWidth + 16
+}
+
+func legendLabel(e LegendEntry) string {
+	return fmt.Sprintf("%s (%d)", e.Label, e.Count)
+}
+
+// legendHeight returns the vertical space the legend needs at width.
+func legendHeight(r *svgRenderer, width float64) float64 {
+	if r.colors == nil {
+		return 0
+	}
+	_, _, h := legendLayout(r.colors.legend, width)
+	return h
+}
+
+// renderLegend draws the colour legend in a band whose top edge is at y.
+func renderLegend(buf *bytes.Buffer, r *svgRenderer, x, y, width float64) {
+	if r.colors == nil || len(r.colors.legend) == 0 {
+		return
+	}
+	xs, rows, h := legendLayout(r.colors.legend, width)
+	fmt.Fprintf(buf, `  <g class="legend" data-color-by="%s">`+"\n", r.colorBy)
+	fmt.Fprintf(buf, `    <rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="white"/>`+"\n", x, y, width, h)
+	for i, e := range r.colors.legend {
+		cy := y + legendPadY + float64(rows[i])*legendRowHeight + legendRowHeight/2
+		ex := x + xs[i]
+		fmt.Fprintf(buf, `    <rect x="%.1f" y="%.1f" width="%.0f" height="%.0f" fill="%s" stroke="#333" stroke-width="0.5"/>`+"\n",
+			ex, cy-legendSwatch/2, legendSwatch, legendSwatch, e.Color)
+		fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" dominant-baseline="middle" font-family="%s" font-size="11" fill="#555">%s</text>`+"\n",
+			ex+legendSwatch+6, cy, fonts.FallbackFontFamily, styles.EscapeXML(legendLabel(e)))
+	}
+	buf.WriteString("  </g>\n")
+}
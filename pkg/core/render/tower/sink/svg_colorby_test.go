@@ -0,0 +1,199 @@
+package sink
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+	"github.com/stacktower-io/stacktower/pkg/security"
+)
+
+func TestParseColorBy(t *testing.T) {
+	for _, name := range []string{"license", "language", "owner", "vuln", "staleness", "freshness", "health"} {
+		c, err := ParseColorBy(name)
+		if err != nil || c.String() != name {
+			t.Errorf("ParseColorBy(%q) = %v, %v", name, c, err)
+		}
+	}
+	if c, err := ParseColorBy(""); err != nil || c != ColorByNone {
+		t.Errorf("ParseColorBy(\"\") = %v, %v; want none", c, err)
+	}
+	if _, err := ParseColorBy("stars"); err == nil {
+		t.Error("ParseColorBy(\"stars\") should fail")
+	}
+}
+
+func TestNewColorScale_License(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "a", Meta: dag.Metadata{"license": "MIT"}})
+	g.AddNode(dag.Node{ID: "b", Meta: dag.Metadata{security.MetaLicenseRisk: "copyleft"}})
+	g.AddNode(dag.Node{ID: "c", Meta: dag.Metadata{"license": "Apache-2.0"}})
+	g.AddNode(dag.Node{ID: "d"})
+
+	s := newColorScale(ColorByLicense, g, nil, feature.BrittleThresholds{})
+	var got []string
+	for _, e := range s.legend {
+		got = append(got, fmt.Sprintf("%s=%d", e.Label, e.Count))
+	}
+	want := "permissive=2 copyleft=1 unknown=1"
+	if strings.Join(got, " ") != want {
+		t.Errorf("legend = %v, want %s", got, want)
+	}
+
+	a, _ := g.Node("a")
+	c, _ := g.Node("c")
+	if s.fill(a) == "" || s.fill(a) != s.fill(c) {
+		t.Errorf("packages in the same family should share a colour: %q vs %q", s.fill(a), s.fill(c))
+	}
+}
+
+func TestNewColorScale_CategoricalFoldsTail(t *testing.T) {
+	g := dag.New(nil)
+	for i := range maxCategories + 2 {
+		g.AddNode(dag.Node{ID: fmt.Sprintf("p%d", i), Meta: dag.Metadata{metadata.RepoOwner: fmt.Sprintf("org%02d", i)}})
+	}
+	g.AddNode(dag.Node{ID: "extra", Meta: dag.Metadata{metadata.RepoOwner: "org00"}})
+	g.AddNode(dag.Node{ID: "anon"})
+
+	s := newColorScale(ColorByOwner, g, nil, feature.BrittleThresholds{})
+	if first := s.legend[0]; first.Label != "org00" || first.Count != 2 {
+		t.Errorf("most common owner should come first, got %+v", first)
+	}
+	if n := len(s.legend); n != maxCategories+2 {
+		t.Fatalf("legend has %d entries, want %d (palette + other + unknown)", n, maxCategories+2)
+	}
+	if other := s.legend[maxCategories]; other.Label != "other" || other.Count != 2 || other.Color != colorOther {
+		t.Errorf("tail should fold into other, got %+v", other)
+	}
+	if last := s.legend[len(s.legend)-1]; last.Label != "unknown" {
+		t.Errorf("unknown should come last, got %+v", last)
+	}
+}
+
+func TestStalenessCategory(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		meta dag.Metadata
+		want string
+	}{
+		{dag.Metadata{metadata.RepoLastCommit: "2026-01-15"}, "active"},
+		{dag.Metadata{metadata.RepoLastCommit: "2025-01-15"}, "stale"},
+		{dag.Metadata{metadata.RepoLastRelease: "2020-01-15"}, "abandoned"},
+		{nil, "unknown"},
+	}
+	for _, tt := range tests {
+		if got := stalenessCategory(&dag.Node{Meta: tt.meta}, now, feature.BrittleThresholds{}); got != tt.want {
+			t.Errorf("stalenessCategory(%v) = %q, want %q", tt.meta, got, tt.want)
+		}
+	}
+}
+
+func TestStalenessCategory_Thresholds(t *testing.T) {
+	now := time.Now()
+	n := &dag.Node{Meta: dag.Metadata{metadata.RepoLastCommit: now.AddDate(0, -8, 0).Format("2006-01-02")}}
+	strict := feature.BrittleThresholds{Stale: 90 * 24 * time.Hour, Abandoned: 180 * 24 * time.Hour}
+	if got := stalenessCategory(n, now, strict); got != "abandoned" {
+		t.Errorf("stalenessCategory with six-month threshold = %q, want abandoned", got)
+	}
+}
+
+func TestColorScale_Freshness(t *testing.T) {
+	recent := time.Now().AddDate(0, -1, 0).Format("2006-01-02")
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "fresh", Meta: dag.Metadata{metadata.RepoLastRelease: recent}})
+	g.AddNode(dag.Node{ID: "behind", Meta: dag.Metadata{metadata.RepoLastRelease: recent, deps.MetaVersionsBehind: 30}})
+	g.AddNode(dag.Node{ID: "none"})
+
+	s := newColorScale(ColorByFreshness, g, nil, feature.BrittleThresholds{})
+	var labels []string
+	for _, e := range s.legend {
+		labels = append(labels, e.Label)
+	}
+	if got := strings.Join(labels, ","); got != "fresh,outdated,unknown" {
+		t.Errorf("legend = %s, want fresh,outdated,unknown", got)
+	}
+	fresh, _ := g.Node("fresh")
+	behind, _ := g.Node("behind")
+	if s.fill(fresh) == s.fill(behind) {
+		t.Error("fresh and outdated packages should differ in colour")
+	}
+}
+
+func TestColorScale_Health(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "good", Meta: dag.Metadata{feature.MetaHealthScore: 91}})
+	g.AddNode(dag.Node{ID: "poor", Meta: dag.Metadata{feature.MetaHealthScore: float64(12)}})
+	g.AddNode(dag.Node{ID: "none"})
+
+	s := newColorScale(ColorByHealth, g, nil, feature.BrittleThresholds{})
+	var labels []string
+	for _, e := range s.legend {
+		labels = append(labels, e.Label)
+	}
+	if got := strings.Join(labels, ","); got != "good,poor,unknown" {
+		t.Errorf("legend = %s, want good,poor,unknown", got)
+	}
+}
+
+func TestRenderSVG_ColorByLegend(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Row: 0, Meta: dag.Metadata{metadata.RepoLanguage: "Go"}})
+	g.AddNode(dag.Node{ID: "lib", Row: 1, Meta: dag.Metadata{metadata.RepoLanguage: "C"}})
+	g.AddEdge(dag.Edge{From: "app", To: "lib"})
+	l := layout.Build(g, 200, 100)
+
+	plain := string(RenderSVG(l, WithGraph(g)))
+	svg := string(RenderSVG(l, WithGraph(g), WithColorBy(ColorByLanguage)))
+
+	if strings.Contains(plain, `class="legend"`) {
+		t.Error("legend should only be drawn when colouring is enabled")
+	}
+	if !strings.Contains(svg, `<g class="legend" data-color-by="language">`) ||
+		!strings.Contains(svg, ">C (1)</text>") || !strings.Contains(svg, ">Go (1)</text>") {
+		t.Errorf("legend missing or wrong:\n%s", svg)
+	}
+	for _, c := range styles.PalettePastel[:2] {
+		if !strings.Contains(svg, `fill="`+c+`" stroke="#333" stroke-width="1"/>`) {
+			t.Errorf("expected a block filled with %s", c)
+		}
+	}
+	if !strings.Contains(svg, `height="174"`) {
+		t.Error("legend should add its band to the SVG height")
+	}
+}
+
+func TestNewColorScale_Palette(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "a", Meta: dag.Metadata{security.MetaVulnSeverity: "critical"}})
+	g.AddNode(dag.Node{ID: "b", Meta: dag.Metadata{security.MetaVulnSeverity: "low"}})
+	g.AddNode(dag.Node{ID: "c"})
+
+	s := newColorScale(ColorByVuln, g, styles.PaletteViridis, feature.BrittleThresholds{})
+	want := map[string]string{
+		"critical": styles.PaletteViridis[0],
+		"low":      styles.PaletteViridis[3],
+		"none":     colorNone,
+	}
+	for label, color := range want {
+		if s.colors[label] != color {
+			t.Errorf("colors[%q] = %q, want %q", label, s.colors[label], color)
+		}
+	}
+
+	// A short brand palette folds everything past its length into "other".
+	g = dag.New(nil)
+	for _, lang := range []string{"Go", "Go", "Rust", "C"} {
+		g.AddNode(dag.Node{ID: fmt.Sprint(g.NodeCount()), Meta: dag.Metadata{metadata.RepoLanguage: lang}})
+	}
+	s = newColorScale(ColorByLanguage, g, styles.Palette{"#111111", "#222222"}, feature.BrittleThresholds{})
+	if s.colors["Go"] != "#111111" || s.colors["C"] != "#222222" || s.colors["Rust"] != colorOther {
+		t.Errorf("unexpected brand colours: %v", s.colors)
+	}
+}
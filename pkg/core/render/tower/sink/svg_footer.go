@@ -0,0 +1,129 @@
+package sink
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+	"github.com/stacktower-io/stacktower/pkg/fonts"
+)
+
+const footerHeight = 28.0 // Space reserved at the bottom for the provenance footer
+
+// GraphResolvedAtKey is the graph-level metadata key holding the RFC 3339
+// timestamp at which dependencies were resolved.
+const GraphResolvedAtKey = "resolved_at"
+
+// Footer is provenance information stamped below the tower so that images
+// pasted into docs can be traced back to what they show.
+//
+// Zero fields are omitted. When rendering with [WithGraph], Project defaults
+// to the root package, ResolvedAt to the graph's [GraphResolvedAtKey]
+// metadata, and the counts to the graph's packages and dependencies.
+type Footer struct {
+	Project      string    // Project or package name
+	ResolvedAt   time.Time // When dependencies were resolved
+	Version      string    // Stacktower version that produced the image
+	Packages     int       // Number of packages in the tower
+	Dependencies int       // Number of dependency edges
+	Note         string    // Free-form trailing text, e.g. a commit SHA or team
+}
+
+// WithFooter stamps a provenance footer below the tower.
+func WithFooter(f Footer) SVGOption {
+	return func(r *svgRenderer) { r.footer = &f }
+}
+
+// WithBranding replaces the "stacktower.io" watermark text and link with
+// custom branding, e.g. an internal platform name. An empty url keeps the
+// text unlinked.
+func WithBranding(text, url string) SVGOption {
+	return func(r *svgRenderer) { r.brandText, r.brandURL, r.branded = text, url, true }
+}
+
+// resolveFooter fills footer defaults from the graph.
+func resolveFooter(f Footer, g *dag.DAG) Footer {
+	if g == nil {
+		return f
+	}
+	if f.Project == "" {
+		f.Project = rootPackage(g)
+	}
+	if f.ResolvedAt.IsZero() {
+		if s, ok := g.Meta()[GraphResolvedAtKey].(string); ok {
+			f.ResolvedAt, _ = time.Parse(time.RFC3339, s)
+		}
+	}
+	if f.Packages == 0 && f.Dependencies == 0 {
+		for _, n := range g.Nodes() {
+			if !n.IsSynthetic() {
+				f.Packages++
+			}
+		}
+		// Every original edge has exactly one segment leaving a real node,
+		// so this ignores the extra hops introduced by subdividers.
+		for _, e := range g.EdgesIter() {
+			if n, ok := g.Node(e.From); ok && !n.IsSynthetic() {
+				f.Dependencies++
+			}
+		}
+	}
+	return f
+}
+
+// rootPackage returns the single top-row package, or "" if there are several.
+func rootPackage(g *dag.DAG) string {
+	var root string
+	for _, n := range g.NodesInRow(0) {
+		if n.IsSynthetic() {
+			continue
+		}
+		if root != "" {
+			return ""
+		}
+		root = n.ID
+	}
+	return root
+}
+
+// String formats the footer as a single " · "-separated line.
+func (f Footer) String() string {
+	var parts []string
+	if f.Project != "" {
+		parts = append(parts, f.Project)
+	}
+	if !f.ResolvedAt.IsZero() {
+		parts = append(parts, "resolved "+f.ResolvedAt.UTC().Format("2006-01-02 15:04 UTC"))
+	}
+	if f.Packages > 0 {
+		parts = append(parts, fmt.Sprintf("%d packages, %d dependencies", f.Packages, f.Dependencies))
+	}
+	if f.Version != "" {
+		parts = append(parts, "stacktower "+f.Version)
+	}
+	if f.Note != "" {
+		parts = append(parts, f.Note)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// renderFooter draws the footer line in a band of footerHeight whose top
+// edge is at y.
+func renderFooter(buf *bytes.Buffer, r *svgRenderer, x, y, width float64) {
+	if r.footer == nil {
+		return
+	}
+	text := resolveFooter(*r.footer, r.graph).String()
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(buf, `  <g class="footer">
+    <rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="white"/>
+    <line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#ddd" stroke-width="1"/>
+    <text x="%.1f" y="%.1f" dominant-baseline="middle" font-family="%s" font-size="11" fill="#888">%s</text>
+  </g>
+`, x, y, width, footerHeight, x+12, y, x+width-12, y, x+12, y+footerHeight/2, fonts.FallbackFontFamily, styles.EscapeXML(text))
+}
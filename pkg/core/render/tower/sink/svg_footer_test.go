@@ -0,0 +1,78 @@
+package sink
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
+)
+
+func TestFooter_String(t *testing.T) {
+	f := Footer{
+		Project:      "flask",
+		ResolvedAt:   time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC),
+		Version:      "v1.4.0",
+		Packages:     12,
+		Dependencies: 20,
+		Note:         "abc123",
+	}
+	want := "flask · resolved 2026-03-01 09:30 UTC · 12 packages, 20 dependencies · stacktower v1.4.0 · abc123"
+	if got := f.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := (Footer{}).String(); got != "" {
+		t.Errorf("empty footer String() = %q, want empty", got)
+	}
+}
+
+func TestResolveFooter_DefaultsFromGraph(t *testing.T) {
+	g := dag.New(dag.Metadata{GraphResolvedAtKey: "2026-03-01T09:30:00Z"})
+	g.AddNode(dag.Node{ID: "app", Row: 0})
+	g.AddNode(dag.Node{ID: "app_sub_1", Row: 1, Kind: dag.NodeKindSubdivider, MasterID: "app"})
+	g.AddNode(dag.Node{ID: "lib", Row: 1})
+	g.AddNode(dag.Node{ID: "core", Row: 2})
+	g.AddEdge(dag.Edge{From: "app", To: "lib"})
+	g.AddEdge(dag.Edge{From: "app", To: "app_sub_1"})
+	g.AddEdge(dag.Edge{From: "app_sub_1", To: "core"})
+	g.AddEdge(dag.Edge{From: "lib", To: "core"})
+
+	f := resolveFooter(Footer{}, g)
+	if f.Project != "app" {
+		t.Errorf("Project = %q, want root package", f.Project)
+	}
+	if f.ResolvedAt.IsZero() {
+		t.Error("ResolvedAt should come from graph metadata")
+	}
+	if f.Packages != 3 || f.Dependencies != 3 {
+		t.Errorf("counts = %d packages, %d deps; want 3, 3", f.Packages, f.Dependencies)
+	}
+
+	// Explicit values win.
+	f = resolveFooter(Footer{Project: "custom", Packages: 1}, g)
+	if f.Project != "custom" || f.Packages != 1 {
+		t.Errorf("explicit fields overridden: %+v", f)
+	}
+}
+
+func TestRenderSVG_FooterAndBranding(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Row: 0})
+	g.AddNode(dag.Node{ID: "lib", Row: 1})
+	g.AddEdge(dag.Edge{From: "app", To: "lib"})
+	l := layout.Build(g, 200, 100)
+
+	plain := string(RenderSVG(l, WithGraph(g)))
+	svg := string(RenderSVG(l, WithGraph(g), WithFooter(Footer{Version: "v9"}), WithBranding("Acme Platform", "")))
+
+	if !strings.Contains(svg, `class="footer"`) || !strings.Contains(svg, "app · 2 packages, 1 dependencies · stacktower v9") {
+		t.Errorf("footer missing or wrong:\n%s", svg)
+	}
+	if !strings.Contains(plain, `height="140"`) || !strings.Contains(svg, `height="168"`) {
+		t.Error("footer should add its band to the SVG height")
+	}
+	if strings.Contains(svg, "stacktower.io</text>") || !strings.Contains(svg, ">Acme Platform</text>") {
+		t.Error("branding should replace the default watermark text")
+	}
+}
@@ -0,0 +1,93 @@
+package sink
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+)
+
+const (
+	defaultHighlightColor = "#f59e0b" // amber — readable on both white and grey blocks
+	dimmedOpacity         = 0.25
+	highlightFillOpacity  = 0.35
+	highlightStrokeWidth  = 3.0
+)
+
+// WithHighlight emphasizes the blocks for the given package IDs: they are
+// drawn with an accent overlay while every other block, label, and edge is
+// dimmed. Subdivider blocks are matched through their master package, so a
+// highlighted package stays highlighted along its whole column.
+func WithHighlight(ids ...string) SVGOption {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return WithHighlightFunc(func(id string) bool { return set[id] })
+}
+
+// WithHighlightFunc is like [WithHighlight] but selects packages with a
+// predicate, e.g. to match a name prefix or a metadata value.
+func WithHighlightFunc(match func(id string) bool) SVGOption {
+	return func(r *svgRenderer) { r.highlight = match }
+}
+
+// WithHighlightColor sets the accent color used by [WithHighlight]
+// (default amber #f59e0b).
+func WithHighlightColor(color string) SVGOption {
+	return func(r *svgRenderer) { r.highlightColor = color }
+}
+
+// isHighlighted reports whether the block belongs to a highlighted package.
+func (r *svgRenderer) isHighlighted(id string) bool {
+	if r.highlight == nil {
+		return false
+	}
+	if r.highlight(id) {
+		return true
+	}
+	if r.graph != nil {
+		if n, ok := r.graph.Node(id); ok && n.MasterID != "" {
+			return r.highlight(n.MasterID)
+		}
+	}
+	return false
+}
+
+// emphasize runs fn, wrapping its output in a dimmed group when a highlight
+// is active and the element is not part of it. Nothing is emitted if fn
+// writes nothing (e.g. a block without flags).
+func (r *svgRenderer) emphasize(buf *bytes.Buffer, highlighted bool, fn func()) {
+	if r.highlight == nil || highlighted {
+		fn()
+		return
+	}
+	start := buf.Len()
+	fn()
+	if buf.Len() == start {
+		return
+	}
+	inner := bytes.Clone(buf.Bytes()[start:])
+	buf.Truncate(start)
+	fmt.Fprintf(buf, `  <g class="dimmed" opacity="%.2f">`+"\n", dimmedOpacity)
+	buf.Write(inner)
+	buf.WriteString("  </g>\n")
+}
+
+// renderHighlightAccents draws the accent overlay on top of highlighted blocks.
+func renderHighlightAccents(buf *bytes.Buffer, r *svgRenderer, blocks []styles.Block) {
+	if r.highlight == nil {
+		return
+	}
+	color := r.highlightColor
+	if color == "" {
+		color = defaultHighlightColor
+	}
+	for _, b := range blocks {
+		if !r.isHighlighted(b.ID) {
+			continue
+		}
+		fmt.Fprintf(buf, `  <rect class="highlight-accent" data-block="%s" x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s" fill-opacity="%.2f" stroke="%s" stroke-width="%.1f" pointer-events="none"/>`+"\n",
+			styles.EscapeXML(b.ID), b.X, b.Y, b.W, b.H, styles.EscapeXML(color), highlightFillOpacity, styles.EscapeXML(color), highlightStrokeWidth)
+	}
+}
@@ -0,0 +1,84 @@
+package sink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
+)
+
+func highlightGraph() *dag.DAG {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Row: 0})
+	g.AddNode(dag.Node{ID: "lodash", Row: 1})
+	g.AddNode(dag.Node{ID: "chalk", Row: 1})
+	g.AddEdge(dag.Edge{From: "app", To: "lodash"})
+	g.AddEdge(dag.Edge{From: "app", To: "chalk"})
+	return g
+}
+
+func TestRenderSVG_Highlight(t *testing.T) {
+	g := highlightGraph()
+	l := layout.Build(g, 400, 300)
+
+	svg := string(RenderSVG(l, WithGraph(g), WithHighlight("lodash")))
+
+	if got := strings.Count(svg, `class="highlight-accent"`); got != 1 {
+		t.Errorf("highlight accents = %d, want 1", got)
+	}
+	if !strings.Contains(svg, `data-block="lodash"`) {
+		t.Error("accent should be drawn on lodash")
+	}
+	if !strings.Contains(svg, defaultHighlightColor) {
+		t.Error("default accent color should be used")
+	}
+	// app and chalk: block + text each
+	if got := strings.Count(svg, `class="dimmed"`); got != 4 {
+		t.Errorf("dimmed groups = %d, want 4", got)
+	}
+}
+
+func TestRenderSVG_HighlightFuncAndColor(t *testing.T) {
+	g := highlightGraph()
+	l := layout.Build(g, 400, 300)
+
+	svg := string(RenderSVG(l, WithGraph(g),
+		WithHighlightFunc(func(id string) bool { return strings.HasPrefix(id, "l") || id == "chalk" }),
+		WithHighlightColor("#ff00ff"),
+	))
+
+	if got := strings.Count(svg, `class="highlight-accent"`); got != 2 {
+		t.Errorf("highlight accents = %d, want 2", got)
+	}
+	if !strings.Contains(svg, `stroke="#ff00ff"`) {
+		t.Error("custom accent color should be used")
+	}
+}
+
+func TestRenderSVG_HighlightSubdivider(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Row: 0})
+	g.AddNode(dag.Node{ID: "lodash_sub_1", Row: 1, Kind: dag.NodeKindSubdivider, MasterID: "lodash"})
+	g.AddNode(dag.Node{ID: "lodash", Row: 2})
+	g.AddEdge(dag.Edge{From: "app", To: "lodash_sub_1"})
+	g.AddEdge(dag.Edge{From: "lodash_sub_1", To: "lodash"})
+	l := layout.Build(g, 400, 300)
+
+	svg := string(RenderSVG(l, WithGraph(g), WithHighlight("lodash")))
+
+	if !strings.Contains(svg, `data-block="lodash_sub_1"`) {
+		t.Error("subdivider should be highlighted through its master")
+	}
+}
+
+func TestRenderSVG_NoHighlight(t *testing.T) {
+	g := highlightGraph()
+	l := layout.Build(g, 400, 300)
+
+	svg := string(RenderSVG(l, WithGraph(g)))
+
+	if strings.Contains(svg, "dimmed") || strings.Contains(svg, "highlight-accent") {
+		t.Error("nothing should be dimmed or highlighted without WithHighlight")
+	}
+}
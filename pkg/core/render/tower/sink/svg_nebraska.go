@@ -3,6 +3,7 @@ package sink
 import (
 	"bytes"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
@@ -34,10 +35,14 @@ const nebraskaJS = `
       el.addEventListener('mouseleave', clearHighlight);
     });`
 
-func renderNebraskaPanel(buf *bytes.Buffer, frameWidth, frameHeight float64, rankings []feature.NebraskaRanking) {
+func renderNebraskaPanel(buf *bytes.Buffer, frameWidth, frameHeight float64, rankings []feature.NebraskaRanking, bus feature.BusFactorReport) {
 	numEntries := min(len(rankings), 6)
 	padding := 30.0
 	isLandscape := frameWidth > frameHeight
+	subject := "Guy"
+	if len(rankings) > 0 && rankings[0].Org {
+		subject = "Org"
+	}
 
 	if isLandscape {
 		// Panel on the right side: 2 columns, 3 rows
@@ -47,12 +52,13 @@ func renderNebraskaPanel(buf *bytes.Buffer, frameWidth, frameHeight float64, ran
 
 		// Title at top of panel
 		titleY := watermarkMargin + nebraskaTitleY
-		fmt.Fprintf(buf, `  <text x="%.1f" y="%.1f" text-anchor="middle" font-family="%s" font-size="24" fill="#333" font-weight="bold">Nebraska Guy</text>`+"\n",
-			centerX, titleY, fonts.FallbackFontFamily)
+		fmt.Fprintf(buf, `  <text x="%.1f" y="%.1f" text-anchor="middle" font-family="%s" font-size="24" fill="#333" font-weight="bold">Nebraska %s</text>`+"\n",
+			centerX, titleY, fonts.FallbackFontFamily, subject)
 		fmt.Fprintf(buf, `  <text x="%.1f" y="%.1f" text-anchor="middle" font-family="%s" font-size="24" fill="#333" font-weight="bold">Ranking</text>`+"\n",
 			centerX, titleY+28, fonts.FallbackFontFamily)
 		fmt.Fprintf(buf, `  <path d="M %.1f %.1f q 30 2 60 -1 t 65 2" fill="none" stroke="#333" stroke-width="2.5" stroke-linecap="round"/>`+"\n",
 			centerX-63, titleY+28+nebraskaUnderlineY)
+		renderBusFactorLine(buf, bus, centerX, titleY+62)
 
 		// 2 columns, 3 rows grid with margins
 		cols := 2
@@ -74,10 +80,11 @@ func renderNebraskaPanel(buf *bytes.Buffer, frameWidth, frameHeight float64, ran
 		panelY := frameHeight + watermarkMargin + nebraskaPanelPadding
 		centerX := frameWidth / 2
 
-		fmt.Fprintf(buf, `  <text x="%.1f" y="%.1f" text-anchor="middle" font-family="%s" font-size="30" fill="#333" font-weight="bold">Nebraska Guy Ranking</text>`+"\n",
-			centerX, panelY+nebraskaTitleY, fonts.FallbackFontFamily)
+		fmt.Fprintf(buf, `  <text x="%.1f" y="%.1f" text-anchor="middle" font-family="%s" font-size="30" fill="#333" font-weight="bold">Nebraska %s Ranking</text>`+"\n",
+			centerX, panelY+nebraskaTitleY, fonts.FallbackFontFamily, subject)
 		fmt.Fprintf(buf, `  <path d="M %.1f %.1f q 60 4 120 -1 t 135 3" fill="none" stroke="#333" stroke-width="2.5" stroke-linecap="round"/>`+"\n",
 			centerX-128, panelY+nebraskaTitleY+nebraskaUnderlineY)
+		renderBusFactorLine(buf, bus, centerX, panelY+nebraskaTitleY+34)
 
 		// 3 columns, 2 rows grid with margins
 		cols := 3
@@ -98,6 +105,28 @@ func renderNebraskaPanel(buf *bytes.Buffer, frameWidth, frameHeight float64, ran
 
 const maxDisplayedPackages = 3
 
+// renderBusFactorLine writes the tower bus factor under the panel title,
+// highlighting the key people on hover. Nothing is drawn without
+// contributor data.
+func renderBusFactorLine(buf *bytes.Buffer, bus feature.BusFactorReport, centerX, y float64) {
+	if bus.TowerBusFactor == 0 {
+		return
+	}
+	var pkgs []string
+	key := make(map[string]bool, len(bus.KeyPeople))
+	for _, p := range bus.KeyPeople {
+		key[p] = true
+	}
+	for _, p := range bus.Packages {
+		if slices.ContainsFunc(p.KeyPeople, func(person string) bool { return key[person] }) {
+			pkgs = append(pkgs, p.Package)
+		}
+	}
+	fmt.Fprintf(buf, `  <text class="bus-factor maintainer-link" data-packages="%s" x="%.1f" y="%.1f" text-anchor="middle" font-family="%s" font-size="14" fill="#666"><title>%s</title>Tower bus factor: %d</text>`+"\n",
+		styles.EscapeXML(strings.Join(pkgs, ",")), centerX, y, fonts.FallbackFontFamily,
+		styles.EscapeXML("Key people: @"+strings.Join(bus.KeyPeople, ", @")), bus.TowerBusFactor)
+}
+
 func renderNebraskaEntry(buf *bytes.Buffer, r feature.NebraskaRanking, idx int, x, y, width float64) {
 	allPkgIDs := make([]string, len(r.Packages))
 	for j, p := range r.Packages {
@@ -107,11 +136,16 @@ func renderNebraskaEntry(buf *bytes.Buffer, r feature.NebraskaRanking, idx int,
 	displayed := min(len(r.Packages), maxDisplayedPackages)
 	centerX := x + width/2
 
-	// Maintainer name with link
+	// Maintainer name with link; organizations also show their share of
+	// the tower, which is what a corporate risk review asks about.
+	name := "@" + r.Maintainer
+	if r.Org && r.Share > 0 {
+		name += fmt.Sprintf(" (%.0f%%)", r.Share*100)
+	}
 	fmt.Fprintf(buf, `  <a href="https://github.com/%s" target="_blank" class="maintainer-link" data-packages="%s">`+"\n",
 		r.Maintainer, styles.EscapeXML(strings.Join(allPkgIDs, ",")))
-	fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" text-anchor="middle" font-family="%s" font-size="18" fill="#333" font-weight="bold">#%d @%s</text>`+"\n",
-		centerX, y+20, fonts.FallbackFontFamily, idx+1, styles.EscapeXML(r.Maintainer))
+	fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" text-anchor="middle" font-family="%s" font-size="18" fill="#333" font-weight="bold">#%d %s</text>`+"\n",
+		centerX, y+20, fonts.FallbackFontFamily, idx+1, styles.EscapeXML(name))
 	buf.WriteString("  </a>\n")
 
 	// Package names
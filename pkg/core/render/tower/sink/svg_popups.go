@@ -3,8 +3,11 @@ package sink
 import (
 	"bytes"
 	"fmt"
+	"strings"
+	"text/template"
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
 	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
@@ -52,9 +55,13 @@ func extractPopupData(n *dag.Node) *styles.PopupData {
 	p.LastRelease, _ = n.Meta[metadata.RepoLastRelease].(string)
 	p.Archived, _ = n.Meta[metadata.RepoArchived].(bool)
 
-	// Prefer GitHub description ("repo_description"), fall back to registry
-	// description ("description") which is always set by the resolver.
-	p.Description, _ = n.Meta[metadata.RepoDescription].(string)
+	// Prefer the trimmed summary ("summary") set during parse, fall back to
+	// the GitHub description ("repo_description") and then to the registry
+	// description ("description") for graphs parsed without it.
+	p.Description, _ = n.Meta[metadata.Summary].(string)
+	if p.Description == "" {
+		p.Description, _ = n.Meta[metadata.RepoDescription].(string)
+	}
 	if p.Description == "" {
 		p.Description, _ = n.Meta["description"].(string)
 	}
@@ -67,10 +74,123 @@ func extractPopupData(n *dag.Node) *styles.PopupData {
 	p.License, _ = n.Meta["license"].(string)
 	p.LicenseRisk, _ = n.Meta[security.MetaLicenseRisk].(string)
 	p.VulnSeverity, _ = n.Meta[security.MetaVulnSeverity].(string)
+
+	if bf := feature.NodeBusFactor(n); bf.BusFactor > 0 {
+		p.BusFactor = bf.BusFactor
+		p.Fields = append(p.Fields, styles.PopupField{Label: "bus factor", Value: formatBusFactor(bf)})
+	}
+	if score, ok := feature.HealthScore(n); ok {
+		p.Fields = append(p.Fields, styles.PopupField{Label: "health", Value: fmt.Sprintf("%d/100 (%s)", score, feature.HealthLevel(score))})
+	}
+	if s := feature.Successor(n); s != "" {
+		p.Fields = append(p.Fields, styles.PopupField{Label: "successor", Value: strings.TrimPrefix(s, "https://")})
+	}
+	if latest, _ := n.Meta[deps.MetaLatestVersion].(string); latest != "" {
+		if behind := feature.AsInt(n.Meta[deps.MetaVersionsBehind]); behind > 0 {
+			p.Fields = append(p.Fields, styles.PopupField{Label: "latest", Value: fmt.Sprintf("%s (%d versions behind)", latest, behind)})
+		}
+	}
 	return p
 }
 
+// formatBusFactor renders a bus factor for popups, e.g. "1 (@alice, 92% of commits)".
+func formatBusFactor(bf feature.PackageBusFactor) string {
+	if bf.Estimated {
+		return fmt.Sprintf("~%d (%d maintainers)", bf.BusFactor, bf.Contributors)
+	}
+	return fmt.Sprintf("%d (@%s, %.0f%% of commits)", bf.BusFactor, bf.Top, bf.TopShare*100)
+}
+
+// formatWeight renders a direct dependency's share of the tower for popups,
+// e.g. "12 packages, 34% of tower (8 only via this)".
+func formatWeight(w feature.DirectWeight) string {
+	s := fmt.Sprintf("%d packages, %.0f%% of tower", w.Transitive+1, w.Share*100)
+	if w.Exclusive > 0 && w.Exclusive < w.Transitive+1 {
+		s += fmt.Sprintf(" (%d only via this)", w.Exclusive)
+	}
+	return s
+}
+
 func renderPopupScript(buf *bytes.Buffer) {
 	fmt.Fprintf(buf, "  <style>%s\n  </style>\n", popupCSS)
 	fmt.Fprintf(buf, "  <script type=\"text/javascript\"><![CDATA[%s\n  ]]></script>\n", popupJS)
 }
+
+// PopupTemplateData is the value a popup template is executed with.
+type PopupTemplateData struct {
+	ID                string         // Node ID
+	Row               int            // Tower row
+	Meta              map[string]any // Full node metadata, including custom keys
+	*styles.PopupData                // Default popup fields (Description, Stars, License, ...)
+}
+
+// WithPopupFields adds the given metadata keys to every popup as
+// "key: value" rows, so internal fields such as "owning_team" or "sla_tier"
+// can be surfaced without a custom template. Keys missing on a node are
+// skipped. Requires [WithPopups].
+func WithPopupFields(keys ...string) SVGOption {
+	return func(r *svgRenderer) { r.popupFields = append(r.popupFields, keys...) }
+}
+
+// WithPopupTemplate replaces the popup body with the output of tmpl,
+// executed with a [PopupTemplateData]. Each output line becomes one popup
+// line; nodes for which the template fails keep the default description.
+// Requires [WithPopups].
+//
+//	tmpl, err := sink.ParsePopupTemplate(
+//	    "{{.ID}} — owned by {{meta . \"owning_team\"}}\nSLA: {{meta . \"sla_tier\"}}")
+//	sink.RenderSVG(l, sink.WithGraph(g), sink.WithPopups(), sink.WithPopupTemplate(tmpl))
+func WithPopupTemplate(tmpl *template.Template) SVGOption {
+	return func(r *svgRenderer) { r.popupTemplate = tmpl }
+}
+
+// ParsePopupTemplate parses popup template text. Besides the standard
+// template functions it provides meta, which looks up a metadata key and
+// yields "" when it is missing: {{meta . "owning_team"}}.
+func ParsePopupTemplate(text string) (*template.Template, error) {
+	return template.New("popup").Funcs(popupTemplateFuncs).Parse(text)
+}
+
+var popupTemplateFuncs = template.FuncMap{
+	"meta": func(d PopupTemplateData, key string) string {
+		if v, ok := d.Meta[key]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	},
+}
+
+// popupData builds the popup for n, applying custom fields and template.
+func (r *svgRenderer) popupData(n *dag.Node) *styles.PopupData {
+	p := extractPopupData(n)
+	if p == nil {
+		return nil
+	}
+	p.Brittle = feature.IsBrittleWith(n, r.brittle)
+	if w, ok := r.weights[n.ID]; ok {
+		p.Fields = append(p.Fields, styles.PopupField{Label: "weight", Value: formatWeight(w)})
+	}
+	for _, key := range r.popupFields {
+		v, ok := n.Meta[key]
+		if !ok || v == nil {
+			continue
+		}
+		if s := fmt.Sprint(v); s != "" {
+			p.Fields = append(p.Fields, styles.PopupField{Label: key, Value: s})
+		}
+	}
+	if r.popupTemplate != nil {
+		var out strings.Builder
+		data := PopupTemplateData{ID: n.ID, Row: n.Row, Meta: n.Meta, PopupData: p}
+		if err := r.popupTemplate.Execute(&out, data); err != nil {
+			r.logger.Warn("popup template failed", "block", n.ID, "err", err)
+		} else {
+			for line := range strings.SplitSeq(strings.TrimSpace(out.String()), "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					p.Lines = append(p.Lines, line)
+				}
+			}
+		}
+	}
+	return p
+}
@@ -0,0 +1,56 @@
+package sink
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+)
+
+const (
+	suspectMarkerSize = 12.0
+	suspectMarkerPad  = 3.0 // Inset from the block's bottom-left corner
+	suspectColor      = "#f59e0b"
+)
+
+// WithSuspicious draws a warning triangle in the bottom-left corner of each
+// flagged package's block. Hovering the marker lists why the package was
+// flagged. Pass the result of [feature.Suspicious].
+func WithSuspicious(suspects []feature.Suspicion) SVGOption {
+	return func(r *svgRenderer) {
+		r.suspects = make(map[string][]string, len(suspects))
+		for _, s := range suspects {
+			r.suspects[s.Package] = s.Details
+		}
+	}
+}
+
+// renderSuspectMarkers draws the markers registered by [WithSuspicious].
+// Like vulnerability badges, only the block carrying the package's own ID
+// is marked.
+func renderSuspectMarkers(buf *bytes.Buffer, r *svgRenderer, blocks []styles.Block) {
+	for _, b := range blocks {
+		details, ok := r.suspects[b.ID]
+		if !ok {
+			continue
+		}
+		if b.W < suspectMarkerSize+2*suspectMarkerPad || b.H < suspectMarkerSize+2*suspectMarkerPad {
+			continue
+		}
+		x := b.X + suspectMarkerPad
+		y := b.Y + b.H - suspectMarkerPad - suspectMarkerSize
+		s := suspectMarkerSize
+
+		r.emphasize(buf, r.isHighlighted(b.ID), func() {
+			fmt.Fprintf(buf, `  <g class="suspect-marker" data-block="%s" pointer-events="all">`+"\n", styles.EscapeXML(b.ID))
+			fmt.Fprintf(buf, `    <title>%s</title>`+"\n", styles.EscapeXML("suspicious: "+strings.Join(details, "\n")))
+			fmt.Fprintf(buf, `    <path d="M%.2f %.2f L%.2f %.2f L%.2f %.2f Z" fill="%s" stroke="white" stroke-width="1" stroke-linejoin="round"/>`+"\n",
+				x+s/2, y, x+s, y+s, x, y+s, suspectColor)
+			fmt.Fprintf(buf, `    <text x="%.2f" y="%.2f" text-anchor="middle" font-family="Helvetica,Arial,sans-serif" font-size="%.0f" font-weight="bold" fill="#1f2937">!</text>`+"\n",
+				x+s/2, y+s-1.5, s*0.7)
+			buf.WriteString("  </g>\n")
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package sink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
+)
+
+func TestRenderSVG_SuspectMarkers(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Row: 0})
+	g.AddNode(dag.Node{ID: "reqeusts", Row: 1})
+	g.AddEdge(dag.Edge{From: "app", To: "reqeusts"})
+	l := layout.Build(g, 400, 200)
+
+	suspects := []feature.Suspicion{{
+		Package:   "reqeusts",
+		Reasons:   []string{feature.SuspicionTyposquat},
+		Resembles: "requests",
+		Details:   []string{"name is one edit away from requests"},
+	}}
+	svg := string(RenderSVG(l, WithGraph(g), WithSuspicious(suspects)))
+
+	if !strings.Contains(svg, `<g class="suspect-marker" data-block="reqeusts"`) {
+		t.Errorf("expected a marker on reqeusts:\n%s", svg)
+	}
+	if !strings.Contains(svg, "suspicious: name is one edit away from requests</title>") {
+		t.Errorf("marker tooltip should give the reason:\n%s", svg)
+	}
+	if strings.Contains(svg, `class="suspect-marker" data-block="app"`) {
+		t.Error("unflagged packages should not get a marker")
+	}
+	if strings.Contains(string(RenderSVG(l, WithGraph(g))), `class="suspect-marker"`) {
+		t.Error("markers should only be drawn with WithSuspicious")
+	}
+}
@@ -1,11 +1,15 @@
 package sink
 
 import (
+	"bytes"
+	"log/slog"
 	"strings"
 	"testing"
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
 )
 
 func TestRenderSVG_Simple(t *testing.T) {
@@ -181,3 +185,168 @@ func TestExtractPopupData_FallsBackToNodeIDDescription(t *testing.T) {
 		t.Fatalf("popup description = %q, want %q", p.Description, "stacktower")
 	}
 }
+
+func TestExtractPopupData_PrefersSummary(t *testing.T) {
+	n := &dag.Node{ID: "left-pad", Meta: dag.Metadata{"summary": "String left pad."}}
+	if p := extractPopupData(n); p.Description != "String left pad." {
+		t.Fatalf("popup description = %q, want the summary", p.Description)
+	}
+	n.Meta["repo_description"] = "A long description that the summary trims."
+	if p := extractPopupData(n); p.Description != "String left pad." {
+		t.Fatalf("popup description = %q, want the summary over repo_description", p.Description)
+	}
+}
+
+func TestPopupData_FieldsAndTemplate(t *testing.T) {
+	n := &dag.Node{ID: "billing", Row: 2, Meta: dag.Metadata{
+		"description": "Billing service",
+		"owning_team": "payments",
+		"sla_tier":    1,
+	}}
+
+	tmpl, err := ParsePopupTemplate("{{.ID}} — {{.Description}}\n\nowned by {{meta . \"owning_team\"}}{{meta . \"missing\"}}")
+	if err != nil {
+		t.Fatalf("ParsePopupTemplate: %v", err)
+	}
+	r := newSVGRenderer(WithPopups(), WithPopupFields("sla_tier", "missing"), WithPopupTemplate(tmpl))
+	p := r.popupData(n)
+
+	wantLines := []string{"billing — Billing service", "owned by payments"}
+	if strings.Join(p.Lines, "|") != strings.Join(wantLines, "|") {
+		t.Errorf("Lines = %q, want %q", p.Lines, wantLines)
+	}
+	if len(p.Fields) != 1 || p.Fields[0].Label != "sla_tier" || p.Fields[0].Value != "1" {
+		t.Errorf("Fields = %+v, want [sla_tier: 1]", p.Fields)
+	}
+}
+
+func TestPopupData_TemplateErrorLogged(t *testing.T) {
+	tmpl, err := ParsePopupTemplate("{{.Missing}}")
+	if err != nil {
+		t.Fatalf("ParsePopupTemplate: %v", err)
+	}
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	r := newSVGRenderer(WithPopups(), WithPopupTemplate(tmpl), WithLogger(logger))
+
+	p := r.popupData(&dag.Node{ID: "billing", Meta: dag.Metadata{"description": "Billing service"}})
+	if len(p.Lines) != 0 {
+		t.Errorf("Lines = %q, want none for a failing template", p.Lines)
+	}
+	if got := buf.String(); !strings.Contains(got, "popup template failed") || !strings.Contains(got, "block=billing") {
+		t.Errorf("expected a warning for the failing template, got %q", got)
+	}
+}
+
+func TestPopupData_Weight(t *testing.T) {
+	g := dag.New(nil)
+	for _, id := range []string{"app", "web", "cli", "router", "colors"} {
+		_ = g.AddNode(dag.Node{ID: id, Meta: dag.Metadata{"description": id}})
+	}
+	_ = g.AddEdge(dag.Edge{From: "app", To: "web"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "cli"})
+	_ = g.AddEdge(dag.Edge{From: "web", To: "router"})
+	_ = g.AddEdge(dag.Edge{From: "web", To: "colors"})
+	_ = g.AddEdge(dag.Edge{From: "cli", To: "colors"})
+
+	r := newSVGRenderer(WithGraph(g), WithPopups())
+	web, _ := g.Node("web")
+	p := r.popupData(web)
+	if len(p.Fields) != 1 || p.Fields[0].Label != "weight" || p.Fields[0].Value != "3 packages, 75% of tower (2 only via this)" {
+		t.Errorf("Fields = %+v, want weight row", p.Fields)
+	}
+	router, _ := g.Node("router")
+	if p := r.popupData(router); len(p.Fields) != 0 {
+		t.Errorf("transitive package Fields = %+v, want none", p.Fields)
+	}
+}
+
+func TestExtractPopupData_BusFactor(t *testing.T) {
+	n := &dag.Node{ID: "left-pad", Meta: dag.Metadata{
+		"repo_maintainers":   []string{"azer", "helper"},
+		"repo_contributions": []int{46, 4},
+	}}
+	p := extractPopupData(n)
+	if p.BusFactor != 1 {
+		t.Errorf("BusFactor = %d, want 1", p.BusFactor)
+	}
+	if len(p.Fields) != 1 || p.Fields[0].Label != "bus factor" || p.Fields[0].Value != "1 (@azer, 92% of commits)" {
+		t.Errorf("Fields = %+v, want a bus factor row", p.Fields)
+	}
+}
+
+func TestRenderSVG_NebraskaBusFactor(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Row: 0})
+	g.AddNode(dag.Node{ID: "lib", Row: 1, Meta: dag.Metadata{"repo_maintainers": []string{"solo"}}})
+	g.AddEdge(dag.Edge{From: "app", To: "lib"})
+	l := layout.Build(g, 400, 200)
+	rankings := []feature.NebraskaRanking{{Maintainer: "solo", Score: 1, Packages: []feature.PackageRole{{Package: "lib"}}}}
+
+	svg := string(RenderSVG(l, WithGraph(g), WithNebraska(rankings)))
+	if !strings.Contains(svg, `data-packages="lib"`) || !strings.Contains(svg, "<title>Key people: @solo</title>Tower bus factor: 1</text>") {
+		t.Errorf("Nebraska panel should show the tower bus factor:\n%s", svg)
+	}
+}
+
+func TestRenderSVG_NebraskaByOrg(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Row: 0})
+	g.AddNode(dag.Node{ID: "lib", Row: 1})
+	g.AddEdge(dag.Edge{From: "app", To: "lib"})
+	l := layout.Build(g, 400, 200)
+	rankings := []feature.NebraskaRanking{{Maintainer: "acme", Score: 1, Share: 0.75, Org: true, Packages: []feature.PackageRole{{Package: "lib"}}}}
+
+	svg := string(RenderSVG(l, WithNebraska(rankings)))
+	if !strings.Contains(svg, ">Nebraska Org</text>") {
+		t.Error("org rankings should retitle the panel")
+	}
+	if !strings.Contains(svg, ">#1 @acme (75%)</text>") {
+		t.Errorf("org entry should show its share of the tower:\n%s", svg)
+	}
+}
+
+func TestParsePopupTemplate_Invalid(t *testing.T) {
+	if _, err := ParsePopupTemplate("{{.ID"); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestRenderSVG_ThemedCanvas(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "A", Row: 0})
+	l := layout.Build(g, 100, 100)
+
+	plain := string(RenderSVG(l))
+	if strings.Contains(plain, `class="canvas"`) {
+		t.Error("simple style should not paint a canvas")
+	}
+
+	svg := string(RenderSVG(l, WithStyle(styles.Blueprint{})))
+	if !strings.Contains(svg, `<rect class="canvas" x="0" y="0" width="100.0" height="140.0" fill="url(#blueprint-grid)"/>`) {
+		t.Errorf("blueprint canvas missing:\n%s", svg)
+	}
+	if !strings.Contains(svg, `fill="#ffffff">stacktower.io</text>`) {
+		t.Error("watermark should use the style's foreground color")
+	}
+}
+
+func TestRenderSVG_Icons(t *testing.T) {
+	const icon = "data:image/png;base64,AAAA"
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Row: 0, Meta: dag.Metadata{"icon": icon}})
+	g.AddNode(dag.Node{ID: "lib", Row: 1})
+	g.AddEdge(dag.Edge{From: "app", To: "lib"})
+	l := layout.Build(g, 400, 300)
+
+	if svg := string(RenderSVG(l, WithGraph(g))); strings.Contains(svg, "block-icon") {
+		t.Error("icons should only be drawn with WithIcons")
+	}
+	svg := string(RenderSVG(l, WithGraph(g), WithIcons()))
+	if n := strings.Count(svg, `class="block-icon"`); n != 1 {
+		t.Errorf("got %d icons, want 1 (only app has one)", n)
+	}
+	if !strings.Contains(svg, `data-block="app"`) || !strings.Contains(svg, `href="`+icon+`"`) {
+		t.Errorf("icon missing for app:\n%s", svg)
+	}
+}
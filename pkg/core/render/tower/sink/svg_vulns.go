@@ -0,0 +1,85 @@
+package sink
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+	"github.com/stacktower-io/stacktower/pkg/security"
+)
+
+const (
+	vulnBadgeH    = 12.0
+	vulnBadgePad  = 3.0 // Inset from the block's bottom-right corner
+	vulnBadgeMinW = 12.0
+	vulnBadgeFont = 8.0
+)
+
+// vulnBadge summarizes the advisories of one package.
+type vulnBadge struct {
+	severity security.Severity
+	lines    []string
+}
+
+// WithVulnerabilities draws a severity badge in the bottom-right corner of
+// each affected package's block, showing the number of advisories in the
+// colour of the worst one. Hovering the badge lists the advisories and the
+// versions that fix them. Pass the result of [feature.Vulnerabilities].
+func WithVulnerabilities(vulns []feature.Vulnerability) SVGOption {
+	return func(r *svgRenderer) {
+		r.vulns = make(map[string]*vulnBadge)
+		for _, v := range vulns {
+			b := r.vulns[v.Package]
+			if b == nil {
+				// Vulnerabilities come ranked, so the first one is the worst.
+				b = &vulnBadge{severity: security.SeverityFromString(v.Severity)}
+				r.vulns[v.Package] = b
+			}
+			b.lines = append(b.lines, vulnBadgeLine(v))
+		}
+	}
+}
+
+func vulnBadgeLine(v feature.Vulnerability) string {
+	id := v.ID
+	if id == "" {
+		id = "advisory"
+	}
+	line := fmt.Sprintf("%s (%s)", id, v.Severity)
+	if v.FixedVersion != "" {
+		line += ": fixed in " + v.FixedVersion
+	}
+	return line
+}
+
+// renderVulnBadges draws the badges registered by [WithVulnerabilities].
+// Only the block carrying the package's own ID gets one, so a package split
+// across rows is badged once.
+func renderVulnBadges(buf *bytes.Buffer, r *svgRenderer, blocks []styles.Block) {
+	for _, b := range blocks {
+		badge := r.vulns[b.ID]
+		if badge == nil {
+			continue
+		}
+		count := fmt.Sprint(len(badge.lines))
+		w := max(vulnBadgeMinW, float64(len(count))*vulnBadgeFont*0.6+6)
+		if b.W < w+2*vulnBadgePad || b.H < vulnBadgeH+2*vulnBadgePad {
+			continue
+		}
+		x := b.X + b.W - vulnBadgePad - w
+		y := b.Y + b.H - vulnBadgePad - vulnBadgeH
+
+		r.emphasize(buf, r.isHighlighted(b.ID), func() {
+			fmt.Fprintf(buf, `  <g class="vuln-badge vuln-badge-%s" data-block="%s" pointer-events="all">`+"\n",
+				badge.severity, styles.EscapeXML(b.ID))
+			fmt.Fprintf(buf, `    <title>%s</title>`+"\n", styles.EscapeXML(strings.Join(badge.lines, "\n")))
+			fmt.Fprintf(buf, `    <rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" rx="%.1f" fill="%s" stroke="white" stroke-width="1"/>`+"\n",
+				x, y, w, vulnBadgeH, vulnBadgeH/2, badge.severity.Color())
+			fmt.Fprintf(buf, `    <text x="%.2f" y="%.2f" text-anchor="middle" dominant-baseline="central" font-family="Helvetica,Arial,sans-serif" font-size="%.0f" font-weight="bold" fill="%s">%s</text>`+"\n",
+				x+w/2, y+vulnBadgeH/2, vulnBadgeFont, badge.severity.TextColor(), count)
+			buf.WriteString("  </g>\n")
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package sink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
+)
+
+func TestRenderSVG_VulnerabilityBadges(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Row: 0})
+	g.AddNode(dag.Node{ID: "lib", Row: 1})
+	g.AddEdge(dag.Edge{From: "app", To: "lib"})
+	l := layout.Build(g, 400, 200)
+
+	vulns := []feature.Vulnerability{
+		{Package: "lib", ID: "GHSA-1", Severity: "high", FixedVersion: "2.0.1"},
+		{Package: "lib", ID: "GHSA-2", Severity: "low"},
+	}
+	svg := string(RenderSVG(l, WithGraph(g), WithVulnerabilities(vulns)))
+
+	if !strings.Contains(svg, `<g class="vuln-badge vuln-badge-high" data-block="lib"`) {
+		t.Errorf("expected a high badge on lib:\n%s", svg)
+	}
+	if !strings.Contains(svg, "GHSA-1 (high): fixed in 2.0.1&#xA;GHSA-2 (low)</title>") {
+		t.Errorf("badge tooltip should list the advisories:\n%s", svg)
+	}
+	if !strings.Contains(svg, `font-weight="bold" fill="#ffffff">2</text>`) {
+		t.Errorf("badge should show the advisory count:\n%s", svg)
+	}
+	if strings.Contains(svg, `data-block="app" pointer-events`) {
+		t.Error("unaffected packages should not get a badge")
+	}
+	if strings.Contains(string(RenderSVG(l, WithGraph(g))), "vuln-badge\"") {
+		t.Error("badges should only be drawn with WithVulnerabilities")
+	}
+}
@@ -0,0 +1,295 @@
+package sink
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/stacktower-io/stacktower/pkg/core/render"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+	"github.com/stacktower-io/stacktower/pkg/fonts"
+)
+
+const (
+	defaultTileWidth   = 1200.0
+	defaultTileHeight  = 900.0
+	defaultTileOverlap = 40.0
+	rowLabelGutter     = 56.0
+	maxTiles           = 400 // Tiles are enlarged to stay under this count
+)
+
+// Tile is one page of a tiled tower rendering.
+type Tile struct {
+	Row, Col   int     // Grid position (0-based, row-major from the top-left)
+	X, Y, W, H float64 // Region of the layout frame covered, including overlap
+	SVG        []byte  // Standalone SVG document for this tile
+}
+
+// TileOption configures tiled rendering.
+type TileOption func(*tileRenderer)
+
+type tileRenderer struct {
+	ctx           context.Context
+	width, height float64
+	overlap       float64
+	rowLabels     bool
+	svgOpts       []SVGOption
+}
+
+// WithTileSize sets the size of each tile in layout units (default 1200x900).
+func WithTileSize(w, h float64) TileOption {
+	return func(r *tileRenderer) { r.width, r.height = w, h }
+}
+
+// WithTileOverlap sets how far each tile extends into its neighbours so that
+// blocks cut at a page boundary remain readable on both pages (default 40).
+func WithTileOverlap(px float64) TileOption {
+	return func(r *tileRenderer) { r.overlap = px }
+}
+
+// WithRowLabels controls whether each tile gets a left gutter labelling the
+// tower rows it shows (default true).
+func WithRowLabels(on bool) TileOption {
+	return func(r *tileRenderer) { r.rowLabels = on }
+}
+
+// WithTileSVGOptions passes options through to the underlying SVG renderer.
+func WithTileSVGOptions(opts ...SVGOption) TileOption {
+	return func(r *tileRenderer) { r.svgOpts = opts }
+}
+
+// WithTileContext stops the PDF conversion of [RenderTiledPDF] when ctx is
+// done.
+func WithTileContext(ctx context.Context) TileOption {
+	return func(r *tileRenderer) { r.ctx = ctx }
+}
+
+func newTileRenderer(opts ...TileOption) tileRenderer {
+	r := tileRenderer{
+		ctx:       context.Background(),
+		width:     defaultTileWidth,
+		height:    defaultTileHeight,
+		overlap:   defaultTileOverlap,
+		rowLabels: true,
+	}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	if r.width <= 0 {
+		r.width = defaultTileWidth
+	}
+	if r.height <= 0 {
+		r.height = defaultTileHeight
+	}
+	r.overlap = max(0, r.overlap)
+	return r
+}
+
+// RenderSVGTiles splits a large tower into a grid of standalone SVG pages.
+// Tiles are returned in row-major order starting at the top-left. Each tile
+// contains only the blocks that intersect its region and, unless disabled,
+// a gutter with row labels so pages can be matched up when printed.
+//
+// A layout that fits in a single tile yields exactly one tile.
+func RenderSVGTiles(l layout.Layout, opts ...TileOption) []Tile {
+	tr := newTileRenderer(opts...)
+	r := newSVGRenderer(tr.svgOpts...)
+
+	tiles := tileGrid(l.FrameWidth, l.FrameHeight, tr)
+	rows := rowBounds(l)
+	for i := range tiles {
+		tiles[i].SVG = renderTile(l, &r, tiles[i], len(tiles), i, rows, tr.rowLabels)
+	}
+	return tiles
+}
+
+// RenderSVGOverview renders the full tower with the tile boundaries drawn on
+// top and numbered, serving as a table of contents for a tiled export.
+func RenderSVGOverview(l layout.Layout, tiles []Tile, opts ...SVGOption) []byte {
+	opts = append(slices.Clone(opts), withOverlay(func(buf *bytes.Buffer) {
+		renderTileGrid(buf, tiles)
+	}))
+	return RenderSVG(l, opts...)
+}
+
+// RenderTiledPDF renders an overview page followed by one page per tile as a
+// single multi-page PDF.
+// Requires librsvg: brew install librsvg (macOS), apt install librsvg2-bin (Linux).
+func RenderTiledPDF(l layout.Layout, opts ...TileOption) ([]byte, error) {
+	tr := newTileRenderer(opts...)
+	tiles := RenderSVGTiles(l, opts...)
+	pages := make([][]byte, 0, len(tiles)+1)
+	if len(tiles) > 1 {
+		pages = append(pages, RenderSVGOverview(l, tiles, tr.svgOpts...))
+	}
+	for _, t := range tiles {
+		pages = append(pages, t.SVG)
+	}
+	return render.ToPDFPagesContext(tr.ctx, pages)
+}
+
+// tileGrid computes tile regions covering the frame. Tiles step by the
+// configured size and are widened by the overlap on every inner edge. When
+// the grid would exceed maxTiles, tiles are scaled up (keeping their aspect
+// ratio) until it fits.
+func tileGrid(frameW, frameH float64, tr tileRenderer) []Tile {
+	gridCols := max(1, math.Ceil(frameW/tr.width))
+	gridRows := max(1, math.Ceil(frameH/tr.height))
+	for gridCols*gridRows > maxTiles {
+		scale := max(1.01, math.Sqrt(gridCols*gridRows/maxTiles))
+		tr.width, tr.height = tr.width*scale, tr.height*scale
+		gridCols = max(1, math.Ceil(frameW/tr.width))
+		gridRows = max(1, math.Ceil(frameH/tr.height))
+	}
+	cols, rows := int(gridCols), int(gridRows)
+
+	tiles := make([]Tile, 0, cols*rows)
+	for row := range rows {
+		for col := range cols {
+			x0 := max(0, float64(col)*tr.width-tr.overlap)
+			y0 := max(0, float64(row)*tr.height-tr.overlap)
+			x1 := min(frameW, float64(col+1)*tr.width+tr.overlap)
+			y1 := min(frameH, float64(row+1)*tr.height+tr.overlap)
+			tiles = append(tiles, Tile{
+				Row: row, Col: col,
+				X: x0, Y: y0, W: x1 - x0, H: y1 - y0,
+			})
+		}
+	}
+	return tiles
+}
+
+type rowSpan struct{ top, bottom float64 }
+
+// rowBounds returns the vertical extent of every row in the layout.
+func rowBounds(l layout.Layout) map[int]rowSpan {
+	spans := make(map[int]rowSpan, len(l.RowOrders))
+	for row, ids := range l.RowOrders {
+		for _, id := range ids {
+			b, ok := l.Blocks[id]
+			if !ok {
+				continue
+			}
+			s, seen := spans[row]
+			if !seen {
+				s = rowSpan{top: b.Bottom, bottom: b.Top}
+			}
+			s.top = min(s.top, b.Bottom)
+			s.bottom = max(s.bottom, b.Top)
+			spans[row] = s
+		}
+	}
+	return spans
+}
+
+func intersects(b layout.Block, t Tile) bool {
+	return b.Right > t.X && b.Left < t.X+t.W && b.Top > t.Y && b.Bottom < t.Y+t.H
+}
+
+func renderTile(l layout.Layout, r *svgRenderer, t Tile, total, idx int, rows map[int]rowSpan, rowLabels bool) []byte {
+	sub := l
+	sub.Blocks = make(map[string]layout.Block)
+	for id, b := range l.Blocks {
+		if intersects(b, t) {
+			sub.Blocks[id] = b
+		}
+	}
+
+	blocks := buildBlocks(sub, r)
+	slices.SortFunc(blocks, func(a, b styles.Block) int { return cmp.Compare(a.ID, b.ID) })
+
+	var edges []styles.Edge
+	if r.showEdges {
+		// Edges are built from the full layout so that connections leaving
+		// the tile are still drawn up to the page boundary.
+		for _, e := range r.edges(l) {
+			if _, ok := sub.Blocks[e.FromID]; ok {
+				edges = append(edges, e)
+			} else if _, ok := sub.Blocks[e.ToID]; ok {
+				edges = append(edges, e)
+			}
+		}
+	}
+
+	gutter := 0.0
+	if rowLabels {
+		gutter = rowLabelGutter
+	}
+	vbX, vbY := t.X-gutter, t.Y
+	vbW, vbH := t.W+gutter, t.H+watermarkMargin
+	legendH := legendHeight(r, vbW)
+	vbH += legendH
+	if r.footer != nil {
+		vbH += footerHeight
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(blocks)*500+8192))
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%.1f %.1f %.1f %.1f" width="%.0f" height="%.0f">`+"\n",
+		vbX, vbY, vbW, vbH, vbW, vbH)
+	fmt.Fprintf(buf, `  <rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="white"/>`+"\n", vbX, vbY, vbW, vbH)
+
+	r.style.RenderDefs(buf)
+	if bg, _ := themeColors(r.style); bg != "" {
+		fmt.Fprintf(buf, `  <rect class="canvas" x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`+"\n", vbX, vbY, vbW, vbH, bg)
+	}
+	renderContent(buf, r, blocks, edges)
+	renderBlockInteraction(buf)
+
+	if rowLabels {
+		renderRowLabels(buf, t, rows)
+	}
+	renderLegend(buf, r, vbX, t.Y+t.H+watermarkMargin, vbW)
+	renderFooter(buf, r, vbX, t.Y+t.H+watermarkMargin+legendH, vbW)
+	fmt.Fprintf(buf, `  <text x="%.1f" y="%.1f" font-family="%s" font-size="14" fill="#666">Tile %d of %d · row %d, column %d</text>`+"\n",
+		vbX+8, vbY+24, fonts.FallbackFontFamily, idx+1, total, t.Row+1, t.Col+1)
+
+	if r.popups {
+		for _, b := range blocks {
+			r.style.RenderPopup(buf, b)
+		}
+		renderPopupScript(buf)
+	}
+
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
+
+// renderRowLabels draws a gutter left of the tile labelling each visible row.
+// The gutter is opaque so blocks cut by the tile edge don't bleed into it.
+func renderRowLabels(buf *bytes.Buffer, t Tile, rows map[int]rowSpan) {
+	fmt.Fprintf(buf, `  <rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="white" stroke="#ccc" stroke-width="1"/>`+"\n",
+		t.X-rowLabelGutter, t.Y+watermarkMargin, rowLabelGutter, t.H)
+
+	ids := make([]int, 0, len(rows))
+	for row := range rows {
+		ids = append(ids, row)
+	}
+	slices.Sort(ids)
+
+	for _, row := range ids {
+		s := rows[row]
+		cy := (s.top + s.bottom) / 2
+		if cy < t.Y || cy > t.Y+t.H {
+			continue
+		}
+		fmt.Fprintf(buf, `  <text class="row-label" x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="middle" font-family="%s" font-size="12" fill="#666">row %d</text>`+"\n",
+			t.X-rowLabelGutter/2, cy+watermarkMargin, fonts.FallbackFontFamily, row)
+	}
+}
+
+// renderTileGrid draws numbered tile outlines in layout coordinates.
+func renderTileGrid(buf *bytes.Buffer, tiles []Tile) {
+	if len(tiles) < 2 {
+		return
+	}
+	for i, t := range tiles {
+		fmt.Fprintf(buf, `    <rect class="tile-outline" x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="none" stroke="#2563eb" stroke-width="2" stroke-dasharray="10,6"/>`+"\n",
+			t.X, t.Y, t.W, t.H)
+		fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" font-family="%s" font-size="20" font-weight="bold" fill="#2563eb">%d</text>`+"\n",
+			t.X+10, t.Y+26, fonts.FallbackFontFamily, i+1)
+	}
+}
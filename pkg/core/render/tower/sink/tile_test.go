@@ -0,0 +1,110 @@
+package sink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
+)
+
+func buildWideGraph() *dag.DAG {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Row: 0})
+	for _, id := range []string{"a", "b", "c", "d"} {
+		g.AddNode(dag.Node{ID: id, Row: 1})
+		g.AddEdge(dag.Edge{From: "app", To: id})
+	}
+	return g
+}
+
+func TestRenderSVGTiles_SingleTileWhenFits(t *testing.T) {
+	g := buildWideGraph()
+	l := layout.Build(g, 400, 300)
+
+	tiles := RenderSVGTiles(l, WithTileSize(1000, 1000))
+	if len(tiles) != 1 {
+		t.Fatalf("expected 1 tile, got %d", len(tiles))
+	}
+	for _, id := range []string{"app", "a", "b", "c", "d"} {
+		if !strings.Contains(string(tiles[0].SVG), ">"+id+"</text>") {
+			t.Errorf("single tile should contain label %s", id)
+		}
+	}
+}
+
+func TestRenderSVGTiles_Grid(t *testing.T) {
+	g := buildWideGraph()
+	l := layout.Build(g, 800, 600)
+
+	tiles := RenderSVGTiles(l, WithTileSize(400, 300), WithTileOverlap(10))
+	if len(tiles) != 4 {
+		t.Fatalf("expected 2x2 = 4 tiles, got %d", len(tiles))
+	}
+
+	last := tiles[len(tiles)-1]
+	if last.Row != 1 || last.Col != 1 {
+		t.Errorf("last tile position = (%d,%d), want (1,1)", last.Row, last.Col)
+	}
+	if tiles[0].X != 0 || tiles[0].W != 410 {
+		t.Errorf("first tile x=%.0f w=%.0f, want x=0 w=410 (overlap on inner edge only)", tiles[0].X, tiles[0].W)
+	}
+
+	for i, tile := range tiles {
+		svg := string(tile.SVG)
+		if !strings.HasPrefix(svg, "<svg") || !strings.HasSuffix(svg, "</svg>\n") {
+			t.Errorf("tile %d is not a standalone SVG document", i)
+		}
+		if !strings.Contains(svg, "Tile ") {
+			t.Errorf("tile %d missing page header", i)
+		}
+	}
+	if !strings.Contains(string(tiles[0].SVG), `class="row-label"`) {
+		t.Error("tiles should include row labels by default")
+	}
+}
+
+func TestTileGrid_CapsTileCount(t *testing.T) {
+	tr := newTileRenderer(WithTileSize(0.05, 0.05))
+	tiles := tileGrid(800, 600, tr)
+	if len(tiles) == 0 || len(tiles) > maxTiles {
+		t.Fatalf("got %d tiles, want between 1 and %d", len(tiles), maxTiles)
+	}
+	last := tiles[len(tiles)-1]
+	if last.X+last.W < 800 || last.Y+last.H < 600 {
+		t.Errorf("last tile ends at (%.1f,%.1f), want the grid to cover the 800x600 frame", last.X+last.W, last.Y+last.H)
+	}
+}
+
+func TestRenderSVGTiles_OnlyIntersectingBlocks(t *testing.T) {
+	g := buildWideGraph()
+	l := layout.Build(g, 800, 200)
+
+	tiles := RenderSVGTiles(l, WithTileSize(400, 200), WithTileOverlap(0), WithRowLabels(false))
+	if len(tiles) != 2 {
+		t.Fatalf("expected 2 tiles, got %d", len(tiles))
+	}
+	for _, tile := range tiles {
+		for id, b := range l.Blocks {
+			present := strings.Contains(string(tile.SVG), `id="block-`+id+`"`)
+			if present != intersects(b, tile) {
+				t.Errorf("tile (%d,%d): block %s present=%v, intersects=%v", tile.Row, tile.Col, id, present, intersects(b, tile))
+			}
+		}
+		if strings.Contains(string(tile.SVG), `class="row-label"`) {
+			t.Error("row labels should be omitted when disabled")
+		}
+	}
+}
+
+func TestRenderSVGOverview_DrawsTileOutlines(t *testing.T) {
+	g := buildWideGraph()
+	l := layout.Build(g, 800, 600)
+
+	tiles := RenderSVGTiles(l, WithTileSize(400, 300))
+	svg := string(RenderSVGOverview(l, tiles, WithGraph(g)))
+
+	if got := strings.Count(svg, `class="tile-outline"`); got != len(tiles) {
+		t.Errorf("overview has %d tile outlines, want %d", got, len(tiles))
+	}
+}
@@ -0,0 +1,119 @@
+package styles
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/security"
+)
+
+const (
+	blueprintPaper     = "#0b3d91"
+	blueprintGridMinor = "#2f5fb0"
+	blueprintGridMajor = "#4a78c4"
+	blueprintInk       = "#ffffff"
+	blueprintFont      = `'Stardos Stencil','Allerta Stencil','Courier New',monospace`
+
+	blueprintGridStep  = 10.0
+	blueprintInset     = 4.0  // Offset of the inner dashed outline
+	blueprintMinDetail = 24.0 // Smallest block side that still gets inner detail
+)
+
+// Blueprint renders the tower as an engineering drawing: white line work on
+// a dark blue gridded sheet with stencil lettering. Useful for architecture
+// documentation where a technical look is preferred.
+type Blueprint struct{}
+
+// Compile-time check that Blueprint paints its own canvas.
+var _ Themed = Blueprint{}
+
+// Background implements [Themed] with the grid pattern from RenderDefs.
+func (Blueprint) Background() string { return "url(#blueprint-grid)" }
+
+// Foreground implements [Themed].
+func (Blueprint) Foreground() string { return blueprintInk }
+
+func (Blueprint) RenderDefs(buf *bytes.Buffer) {
+	major := blueprintGridStep * 5
+	fmt.Fprintf(buf, `  <defs>
+    <pattern id="blueprint-grid-minor" width="%.0f" height="%.0f" patternUnits="userSpaceOnUse">
+      <path d="M %.0f 0 L 0 0 0 %.0f" fill="none" stroke="%s" stroke-width="0.5"/>
+    </pattern>
+    <pattern id="blueprint-grid" width="%.0f" height="%.0f" patternUnits="userSpaceOnUse">
+      <rect width="%.0f" height="%.0f" fill="%s"/>
+      <rect width="%.0f" height="%.0f" fill="url(#blueprint-grid-minor)"/>
+      <path d="M %.0f 0 L 0 0 0 %.0f" fill="none" stroke="%s" stroke-width="1"/>
+    </pattern>
+  </defs>
+`,
+		blueprintGridStep, blueprintGridStep, blueprintGridStep, blueprintGridStep, blueprintGridMinor,
+		major, major, major, major, blueprintPaper, major, major, major, major, blueprintGridMajor)
+}
+
+func (Blueprint) RenderBlock(buf *bytes.Buffer, b Block) {
+	WrapURL(buf, b.URL, func() {
+		class := BlockClass(b)
+		fmt.Fprintf(buf, `<rect id="block-%s" class="%s" x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s" fill-opacity="0.6" stroke="%s" stroke-width="1.5"/>`,
+			EscapeXML(b.ID), class, b.X, b.Y, b.W, b.H, cmp.Or(b.Fill, blueprintPaper), blueprintInk)
+		if b.W >= blueprintMinDetail && b.H >= blueprintMinDetail {
+			fmt.Fprintf(buf, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="none" stroke="%s" stroke-width="0.75" stroke-dasharray="4,3" stroke-opacity="0.6" pointer-events="none"/>`,
+				b.X+blueprintInset, b.Y+blueprintInset, b.W-2*blueprintInset, b.H-2*blueprintInset, blueprintInk)
+		}
+	})
+	buf.WriteByte('\n')
+}
+
+func (Blueprint) RenderFlags(buf *bytes.Buffer, b Block) {
+	slotIdx := 0
+	licenseRisk := security.LicenseRiskFromString(b.LicenseRisk)
+	if licenseRisk == security.LicenseRiskCopyleft || licenseRisk == security.LicenseRiskWeakCopyleft {
+		licenseTooltip := b.LicenseRisk
+		if b.License != "" {
+			licenseTooltip = fmt.Sprintf("%s (%s)", b.License, b.LicenseRisk)
+		}
+		renderFlag(buf, b, "license-flag license-"+b.LicenseRisk, licenseRisk.IconColor(), blueprintInk, licenseTooltip, slotIdx)
+		slotIdx++
+	}
+	if b.VulnSeverity != "" {
+		renderFlag(buf, b, "vuln-flag vuln-flag-"+b.VulnSeverity, simpleVulnFlagColor, blueprintInk, "vuln: "+b.VulnSeverity, slotIdx)
+	}
+}
+
+func (Blueprint) RenderEdge(buf *bytes.Buffer, e Edge) {
+	if e.Path != "" {
+		fmt.Fprintf(buf, `  <path class="edge" d="%s" fill="none" stroke="%s" stroke-width="1.2" stroke-dasharray="10,3,2,3"/>`+"\n", e.Path, blueprintInk)
+		return
+	}
+	fmt.Fprintf(buf, `  <line class="edge" x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s" stroke-width="1.2" stroke-dasharray="10,3,2,3"/>`+"\n",
+		e.X1, e.Y1, e.X2, e.Y2, blueprintInk)
+}
+
+// RenderSubdivider draws pass-through column segments translucent with a
+// dashed outline.
+func (Blueprint) RenderSubdivider(buf *bytes.Buffer, b Block) {
+	renderTranslucentRect(buf, b, 0, cmp.Or(b.Fill, blueprintPaper), blueprintInk, 1.5)
+}
+
+// RenderAuxiliary draws separator beams hatched, like a section cut.
+func (Blueprint) RenderAuxiliary(buf *bytes.Buffer, b Block) {
+	renderHatchedRect(buf, b, 0, blueprintPaper, blueprintInk, 1.5)
+}
+
+func (Blueprint) RenderText(buf *bytes.Buffer, b Block) {
+	b.Label = strings.ToUpper(b.Label)
+	fit := FitLabel(b)
+	textW, textH := fit.Extent(textWidthRatio, textHeightRatio)
+
+	fmt.Fprintf(buf, `  <g class="block-text" data-block="%s">`+"\n", EscapeXML(b.ID))
+	WrapURL(buf, b.URL, func() {
+		// Knock out the grid and edges behind the label, as on a real drawing.
+		fmt.Fprintf(buf, `    <rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"/>`+"\n",
+			b.CX-textW/2, b.CY-textH/2, textW, textH, blueprintPaper)
+		WriteLabel(buf, fit, b.Label, b.CX, b.CY, fmt.Sprintf(` font-family="%s" letter-spacing="1" fill="%s"`, blueprintFont, blueprintInk))
+	})
+	buf.WriteString("  </g>\n")
+}
+
+func (Blueprint) RenderPopup(*bytes.Buffer, Block) {}
@@ -0,0 +1,63 @@
+package styles
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBlueprint_RenderDefs(t *testing.T) {
+	var buf bytes.Buffer
+	Blueprint{}.RenderDefs(&buf)
+	if !strings.Contains(buf.String(), `<pattern id="blueprint-grid"`) {
+		t.Errorf("RenderDefs() missing grid pattern: %s", buf.String())
+	}
+	if got := (Blueprint{}).Background(); got != "url(#blueprint-grid)" {
+		t.Errorf("Background() = %q, want grid pattern reference", got)
+	}
+}
+
+func TestBlueprint_RenderBlock(t *testing.T) {
+	tests := []struct {
+		name       string
+		block      Block
+		wantDetail bool
+	}{
+		{"large block gets inner outline", Block{ID: "a", W: 100, H: 50}, true},
+		{"tiny block stays plain", Block{ID: "b", W: 20, H: 10}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			Blueprint{}.RenderBlock(&buf, tt.block)
+			out := buf.String()
+			if !strings.Contains(out, `stroke="#ffffff"`) {
+				t.Errorf("block should use white line work: %s", out)
+			}
+			if got := strings.Contains(out, "stroke-dasharray"); got != tt.wantDetail {
+				t.Errorf("inner outline = %v, want %v: %s", got, tt.wantDetail, out)
+			}
+		})
+	}
+}
+
+func TestBlueprint_RenderText(t *testing.T) {
+	var buf bytes.Buffer
+	Blueprint{}.RenderText(&buf, Block{ID: "requests", Label: "requests", W: 200, H: 40, CX: 100, CY: 20})
+	out := buf.String()
+	if !strings.Contains(out, ">REQUESTS</text>") {
+		t.Errorf("labels should be stencilled in capitals: %s", out)
+	}
+	if !strings.Contains(out, "Stencil") {
+		t.Errorf("labels should use a stencil font: %s", out)
+	}
+}
+
+func TestBlueprint_RenderFlags(t *testing.T) {
+	var buf bytes.Buffer
+	Blueprint{}.RenderFlags(&buf, Block{ID: "x", W: 100, H: 50, VulnSeverity: "high"})
+	out := buf.String()
+	if !strings.Contains(out, `class="vuln-flag vuln-flag-high"`) || !strings.Contains(out, `stroke="#ffffff"`) {
+		t.Errorf("vuln flag should render with a white pole: %s", out)
+	}
+}
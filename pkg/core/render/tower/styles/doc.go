@@ -7,6 +7,9 @@
 //
 //   - [Style]: The interface that all styles implement
 //   - [Simple]: A clean, minimal style with solid colors
+//   - [Blueprint]: An engineering-drawing look for architecture docs
+//   - [Isometric]: 3D bricks with depth shading for talks and posters
+//   - [Theme]: A style declared in a YAML/JSON theme file ([FromConfig])
 //   - [handdrawn]: An XKCD-inspired hand-drawn aesthetic (in subpackage)
 //
 // # The Style Interface
@@ -27,11 +30,30 @@
 //   - Solid fill colors with subtle gradients
 //   - Clean rectangular blocks
 //   - Standard sans-serif fonts
+//   - Risk states: brittle packages get a pale red fill and dashed red
+//     outline, vulnerable ones a thick outline in their severity colour
+//   - First-party packages get a pale blue fill
 //
 // Usage:
 //
 //	svg := sink.RenderSVG(layout, sink.WithStyle(styles.Simple{}))
 //
+// # Blueprint Style
+//
+// [Blueprint] renders white line work and stencil lettering on a dark blue
+// gridded sheet. It implements [Themed] so the sink paints the grid behind
+// the tower and draws the watermark in white:
+//
+//	svg := sink.RenderSVG(layout, sink.WithStyle(styles.Blueprint{}))
+//
+// # Isometric Style
+//
+// [Isometric] draws each block as a brick with a lit top face and a shaded
+// side face. The extrusion stays inside the block's rectangle, so it works
+// with any layout without extra spacing:
+//
+//	svg := sink.RenderSVG(layout, sink.WithStyle(styles.Isometric{Depth: 10}))
+//
 // # Hand-Drawn Style
 //
 // The [handdrawn] subpackage provides the signature XKCD-inspired aesthetic:
@@ -47,6 +69,37 @@
 //	style := handdrawn.New(42)  // Seed for consistent wobbly lines
 //	svg := sink.RenderSVG(layout, sink.WithStyle(style))
 //
+// # Theme Files
+//
+// [Config] declares colours, fonts, stroke widths, corner radius, popup
+// styling and a canvas texture. [LoadConfig] or [ParseConfig] read it from
+// YAML or JSON, and [FromConfig] validates it into a [Theme]:
+//
+//	cfg, err := styles.LoadConfig("stacktower-theme.yaml")
+//	theme, err := styles.FromConfig(cfg)
+//	svg := sink.RenderSVG(layout, sink.WithStyle(theme))
+//
+// # Palettes
+//
+// A [Palette] is an ordered list of fill colours. [PaletteOkabeIto] is
+// colorblind-safe, [PaletteViridis] is perceptually uniform, and
+// [PalettePastel] is the soft default; [ParsePalette] also accepts a
+// comma-separated hex list for brand colours. Palettes feed the sink's
+// colour-by-metadata mode and the bricks of [Isometric]. When a block has a
+// [Block.Fill], styles pick label colours with [ContrastText] so text stays
+// readable on dark fills.
+//
+// # Labels
+//
+// [FitLabel] lays a block's label out so it never overflows: it shrinks the
+// font, wraps at a separator onto two lines, and as a last resort ellipsizes
+// the name. [WriteLabel] writes the result as <text>, with the full name in a
+// <title> tooltip when it was shortened. Every built-in style uses both, so
+// custom styles should too:
+//
+//	fit := styles.FitLabel(b)
+//	styles.WriteLabel(buf, fit, b.Label, b.CX, b.CY, ` fill="#333"`)
+//
 // # Block Data
 //
 // Styles receive [Block] structs containing all information needed for rendering:
@@ -56,7 +109,21 @@
 //   - CX, CY: Center coordinates for text placement
 //   - URL: Optional link target
 //   - Popup: Metadata for hover popups
-//   - Brittle: Flag for visual warning treatment
+//   - Brittle, VulnSeverity: Risk states every style should make visible;
+//     [BlockClass] gives the matching CSS classes
+//   - Internal: First-party package, marked with the "internal" class
+//   - Kind: Whether the block is a package or a synthetic subdivider or
+//     auxiliary block (see below)
+//
+// # Synthetic Blocks
+//
+// Layout inserts two kinds of blocks that are not real packages: subdividers,
+// the beams that split a long edge across rows, and auxiliary blocks, the
+// columns that carry a dependency past rows it does not touch. A style can
+// draw them differently by implementing [SubdividerRenderer] or
+// [AuxiliaryRenderer]; [RenderShape] dispatches on [Block.Kind] and falls back
+// to RenderBlock otherwise. The built-ins draw subdividers translucent and
+// auxiliary blocks hatched, using [HatchPath] for the stripes.
 //
 // # Creating Custom Styles
 //
@@ -77,5 +144,20 @@
 //	        b.X, b.Y, b.W, b.H, s.Color)
 //	}
 //
+// # Registering Styles
+//
+// Styles are resolved by name through a registry, which is what --style and
+// pipeline.Options.Style use. The built-ins register themselves; another
+// module can add its own from an init function and is picked up by any
+// program that imports it:
+//
+//	func init() {
+//	    styles.Register("mystyle", func(o styles.Options) styles.Style {
+//	        return MyStyle{Color: o.Palette.Color(0)}
+//	    })
+//	}
+//
+// [New] builds a registered style and [Names] lists them.
+//
 // [handdrawn]: github.com/stacktower-io/stacktower/pkg/core/render/tower/styles/handdrawn
 package styles
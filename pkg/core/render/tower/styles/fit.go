@@ -0,0 +1,163 @@
+package styles
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	wrapHeightRatio = 0.5 // Each of two wrapped lines gets half the single-line height
+	wrapSeparators  = "-_./@:"
+	lineSpacingEm   = 1.1 // Distance between wrapped lines, in em
+)
+
+// LabelFit is a block label sized, wrapped and shortened to fit its block.
+type LabelFit struct {
+	Lines     []string // One line, or two when the label wrapped at a separator
+	Size      float64  // Font size in pixels
+	Rotated   bool     // Text runs bottom-to-top (tall, narrow blocks)
+	Truncated bool     // The label was ellipsized; the full name goes in a <title>
+}
+
+// TextWidth estimates the rendered width of s at the given font size.
+func TextWidth(s string, size float64) float64 {
+	return float64(utf8.RuneCountInString(s)) * size * fontCharWidth
+}
+
+// FitLabel lays out b.Label inside the block. It tries, in order: one line
+// shrunk down to the minimum font size, two lines split at a separator
+// ("-", "_", ".", "/", "@", ":"), and finally one ellipsized line at the
+// minimum size. Text is rotated on tall, narrow blocks, or whenever that
+// avoids wrapping or ellipsizing.
+func FitLabel(b Block) LabelFit {
+	n := max(1, utf8.RuneCountInString(b.Label))
+	across := fitLabelIn(b.Label, b.W, b.H, false)
+	along := fitLabelIn(b.Label, b.H*rotateSizeDampen, b.W, true)
+	if shouldRotate(b.W, b.H, n) {
+		across, along = along, across
+	}
+	if along.betterThan(across) {
+		return along
+	}
+	return across
+}
+
+// betterThan reports whether f reads better than g: a single full line
+// beats a wrapped one, which beats an ellipsized one, and among ellipsized
+// fits the one showing more of the name wins.
+func (f LabelFit) betterThan(g LabelFit) bool {
+	if f.quality() != g.quality() {
+		return f.quality() > g.quality()
+	}
+	return f.Truncated && utf8.RuneCountInString(f.Lines[0]) > utf8.RuneCountInString(g.Lines[0])
+}
+
+func (f LabelFit) quality() int {
+	switch {
+	case f.Truncated:
+		return 0
+	case len(f.Lines) > 1:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// fitLabelIn fits label into an availW × availH box in reading direction.
+func fitLabelIn(label string, availW, availH float64, rotated bool) LabelFit {
+	n := max(1, utf8.RuneCountInString(label))
+	maxW := availW * fontWidthRatio
+	maxSize := min(fontSizeMax, availH*fontHeightRatio)
+
+	if byWidth := maxW / (float64(n) * fontCharWidth); byWidth >= fontSizeMin {
+		return LabelFit{Lines: []string{label}, Size: max(fontSizeMin, min(maxSize, byWidth)), Rotated: rotated}
+	}
+
+	if first, second, ok := splitLabel(label); ok {
+		longest := max(utf8.RuneCountInString(first), utf8.RuneCountInString(second))
+		size := min(maxSize*wrapHeightRatio, maxW/(float64(longest)*fontCharWidth))
+		if size >= fontSizeMin {
+			return LabelFit{Lines: []string{first, second}, Size: size, Rotated: rotated}
+		}
+	}
+
+	maxChars := max(3, int(maxW/(fontSizeMin*fontCharWidth)))
+	return LabelFit{
+		Lines:     []string{ellipsize(label, maxChars)},
+		Size:      fontSizeMin,
+		Rotated:   rotated,
+		Truncated: utf8.RuneCountInString(label) > maxChars,
+	}
+}
+
+// Extent returns the size of the label's bounding box in block coordinates
+// (swapped when rotated). charRatio and lineRatio are the style's glyph
+// width and line height as fractions of the font size.
+func (f LabelFit) Extent(charRatio, lineRatio float64) (w, h float64) {
+	longest := 0
+	for _, line := range f.Lines {
+		longest = max(longest, utf8.RuneCountInString(line))
+	}
+	w = float64(longest) * f.Size * charRatio
+	h = float64(len(f.Lines)) * f.Size * lineRatio
+	if f.Rotated {
+		return h, w
+	}
+	return w, h
+}
+
+// WriteLabel writes a fitted label as a <text> element centred on (cx, cy).
+// attrs holds the style's presentation attributes, each with a leading
+// space (e.g. ` font-family="serif" fill="#333"`). Wrapped labels become
+// two <tspan>s; ellipsized labels carry full as a <title> tooltip.
+func WriteLabel(buf *bytes.Buffer, fit LabelFit, full string, cx, cy float64, attrs string) {
+	transform := ""
+	if fit.Rotated {
+		transform = fmt.Sprintf(` transform="rotate(-90 %.2f %.2f)"`, cx, cy)
+	}
+	fmt.Fprintf(buf, `    <text x="%.2f" y="%.2f" text-anchor="middle" dominant-baseline="middle" font-size="%.1f"%s%s>`,
+		cx, cy, fit.Size, attrs, transform)
+	if fit.Truncated {
+		fmt.Fprintf(buf, "<title>%s</title>", EscapeXML(full))
+	}
+	if len(fit.Lines) == 1 {
+		buf.WriteString(EscapeXML(fit.Lines[0]))
+	} else {
+		dy := -lineSpacingEm * float64(len(fit.Lines)-1) / 2
+		for _, line := range fit.Lines {
+			fmt.Fprintf(buf, `<tspan x="%.2f" dy="%.2fem">%s</tspan>`, cx, dy, EscapeXML(line))
+			dy = lineSpacingEm
+		}
+	}
+	buf.WriteString("</text>\n")
+}
+
+// splitLabel breaks a label into two lines after the separator closest to
+// its middle, keeping the separator on the first line.
+func splitLabel(label string) (first, second string, ok bool) {
+	runes := []rune(label)
+	best, bestLongest := -1, len(runes)
+	for i, r := range runes[:max(0, len(runes)-1)] {
+		if i == 0 || !strings.ContainsRune(wrapSeparators, r) {
+			continue
+		}
+		if longest := max(i+1, len(runes)-i-1); longest < bestLongest {
+			best, bestLongest = i, longest
+		}
+	}
+	if best < 0 {
+		return "", "", false
+	}
+	return string(runes[:best+1]), string(runes[best+1:]), true
+}
+
+// ellipsize shortens s to at most maxChars runes, ending in "…".
+func ellipsize(s string, maxChars int) string {
+	runes := []rune(s)
+	if len(runes) <= maxChars {
+		return s
+	}
+	return string(runes[:maxChars-1]) + "…"
+}
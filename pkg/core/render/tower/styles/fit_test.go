@@ -0,0 +1,76 @@
+package styles
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFitLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		block     Block
+		lines     []string
+		truncated bool
+	}{
+		{"fits on one line", Block{Label: "flask", W: 200, H: 50}, []string{"flask"}, false},
+		{"wraps at separator", Block{Label: "typing-extensions", W: 60, H: 60}, []string{"typing-", "extensions"}, false},
+		{"ellipsized without separator", Block{Label: "supercalifragilistic", W: 40, H: 20}, []string{"superc…"}, true},
+		{"too short to wrap", Block{Label: "typing-extensions", W: 40, H: 20}, []string{"typing…"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fit := FitLabel(tt.block)
+			if strings.Join(fit.Lines, "|") != strings.Join(tt.lines, "|") || fit.Truncated != tt.truncated {
+				t.Errorf("FitLabel() = %+v, want lines %q truncated=%v", fit, tt.lines, tt.truncated)
+			}
+			if fit.Size < fontSizeMin || fit.Size > fontSizeMax {
+				t.Errorf("font size %v outside [%v, %v]", fit.Size, fontSizeMin, fontSizeMax)
+			}
+			if w, _ := fit.Extent(fontCharWidth, 1); !fit.Rotated && w > tt.block.W {
+				t.Errorf("label is %.1fpx wide in a %.1fpx block", w, tt.block.W)
+			}
+		})
+	}
+}
+
+func TestSplitLabel(t *testing.T) {
+	tests := []struct {
+		in, first, second string
+		ok                bool
+	}{
+		{"typing-extensions", "typing-", "extensions", true},
+		{"@babel/core", "@babel/", "core", true},
+		{"a.b.c.d", "a.b.", "c.d", true},
+		{"requests", "", "", false},
+		{"trailing-", "", "", false},
+	}
+	for _, tt := range tests {
+		first, second, ok := splitLabel(tt.in)
+		if first != tt.first || second != tt.second || ok != tt.ok {
+			t.Errorf("splitLabel(%q) = %q, %q, %v", tt.in, first, second, ok)
+		}
+	}
+}
+
+func TestWriteLabel(t *testing.T) {
+	var buf bytes.Buffer
+	WriteLabel(&buf, LabelFit{Lines: []string{"typing-", "extensions"}, Size: 10}, "typing-extensions", 50, 20, ` fill="#333"`)
+	out := buf.String()
+	if !strings.Contains(out, `<tspan x="50.00" dy="-0.55em">typing-</tspan><tspan x="50.00" dy="1.10em">extensions</tspan>`) {
+		t.Errorf("wrapped label should use tspans: %s", out)
+	}
+	if strings.Contains(out, "<title>") {
+		t.Error("untruncated label needs no title")
+	}
+
+	buf.Reset()
+	WriteLabel(&buf, LabelFit{Lines: []string{"super…"}, Size: 8, Truncated: true, Rotated: true}, "supercalifragilistic", 50, 20, "")
+	out = buf.String()
+	if !strings.Contains(out, "<title>supercalifragilistic</title>super…</text>") {
+		t.Errorf("truncated label should carry the full name: %s", out)
+	}
+	if !strings.Contains(out, `transform="rotate(-90 50.00 20.00)"`) {
+		t.Errorf("rotated label should be rotated: %s", out)
+	}
+}
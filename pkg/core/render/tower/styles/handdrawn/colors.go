@@ -9,6 +9,8 @@ import (
 const (
 	greyMin = 160
 	greyMax = 250
+
+	internalFill = "#c9d9ec" // pencil blue for first-party packages
 )
 
 func greyForID(id string) string {
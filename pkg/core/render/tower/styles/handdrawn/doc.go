@@ -25,6 +25,22 @@
 // This means the same graph rendered twice with the same seed will look
 // identical, which is important for caching and reproducibility.
 //
+// # Custom Assets
+//
+// [NewWithOptions] swaps the built-in assets and tunes the wobble. Large
+// jitter can make labels on small blocks hard to read; lower it, or let the
+// style dampen it on small blocks only:
+//
+//	font, _ := os.ReadFile("Caveat.woff2")
+//	style := handdrawn.NewWithOptions(handdrawn.Options{
+//	    Seed:        42,
+//	    Wobble:      2.5,  // pixels; negative draws straight outlines
+//	    DampenSmall: true, // less wobble on blocks under 60px
+//	    Font:        font,
+//	    FontFamily:  "Caveat",
+//	    Texture:     "data:image/png;base64,...",
+//	})
+//
 // # Colors
 //
 // The color scheme mimics hand-colored technical drawings:
@@ -32,6 +48,7 @@
 //   - Muted pastels for block fills
 //   - Dark outlines (not pure black)
 //   - Red/orange tints for brittle packages
+//   - Pencil blue for first-party packages
 //   - Paper-like background texture
 //
 // See [colors.go] for the full palette.
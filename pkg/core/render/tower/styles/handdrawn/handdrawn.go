@@ -2,11 +2,11 @@ package handdrawn
 
 import (
 	"bytes"
+	"cmp"
 	"fmt"
 	"strings"
 
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
-	"github.com/stacktower-io/stacktower/pkg/fonts"
 	"github.com/stacktower-io/stacktower/pkg/security"
 )
 
@@ -21,6 +21,9 @@ const (
 	flagSlotGap = 4.0  // horizontal gap between adjacent flags
 
 	vulnFlagColor = "#c2410c" // dark orange — all vulnerability severities use this uniform colour
+
+	subdividerOpacity = 0.45 // pass-through columns let the paper show through
+	auxHatchSpacing   = 8.0  // gap between pencil hatch lines on separator beams
 )
 
 const (
@@ -39,30 +42,38 @@ const (
 )
 
 // HandDrawn implements a casual, hand-drawn visual style with wobbly
-// lines and xkcd Script typography (embedded in the binary).
-type HandDrawn struct{ seed uint64 }
+// lines and xkcd Script typography (embedded in the binary). Use
+// [NewWithOptions] to swap in other assets.
+type HandDrawn struct {
+	seed uint64
+	opts Options
+}
 
 // New creates a new HandDrawn style with the given seed forReproducible
 // line wobbling.
-func New(seed uint64) *HandDrawn { return &HandDrawn{seed: seed} }
+func New(seed uint64) *HandDrawn { return NewWithOptions(Options{Seed: seed}) }
+
+// DefaultSeed is the wobble seed used when the registry is asked for the
+// "handdrawn" style without one.
+const DefaultSeed = 42
+
+func init() {
+	styles.Register("handdrawn", func(o styles.Options) styles.Style {
+		return New(cmp.Or(o.Seed, DefaultSeed))
+	})
+}
 
 func (h *HandDrawn) RenderDefs(buf *bytes.Buffer) {
 	buf.WriteString(`  <defs>
     <style>
-      @font-face {
-        font-family: 'xkcd Script';
-        src: url('data:font/woff;base64,`)
-	buf.WriteString(fonts.XKCDScriptWOFFBase64())
-	buf.WriteString(`') format('woff');
-        font-weight: normal;
-        font-style: normal;
-      }
-      .license-flag, .vuln-flag { transition: opacity 0.15s ease; }
+`)
+	h.writeFontFace(buf)
+	buf.WriteString(`      .license-flag, .vuln-flag { transition: opacity 0.15s ease; }
       .license-flag.highlight, .vuln-flag.highlight { opacity: 0.7; }
     </style>
     <pattern id="brittleTexture" patternUnits="userSpaceOnUse" width="200" height="200">
       <image href="`)
-	buf.WriteString(getBrittleTextureDataURI())
+	buf.WriteString(styles.EscapeXML(h.texture()))
 	buf.WriteString(`" x="0" y="0" width="200" height="200" preserveAspectRatio="xMidYMid slice" opacity="0.6"/>
     </pattern>
   </defs>
@@ -70,19 +81,16 @@ func (h *HandDrawn) RenderDefs(buf *bytes.Buffer) {
 }
 
 func (h *HandDrawn) RenderBlock(buf *bytes.Buffer, b styles.Block) {
-	fill := greyForID(b.ID)
+	fill := cmp.Or(b.Fill, greyForID(b.ID))
+	if b.Fill == "" && b.Internal {
+		fill = internalFill
+	}
 
 	rot := rotationFor(b.ID, b.W, b.H)
-	path := wobbledRect(b.X, b.Y, b.W, b.H, h.seed, b.ID)
+	path := jitteredRect(b.X, b.Y, b.W, b.H, h.seed, b.ID, h.wobbleFor(b.W, b.H))
 
 	styles.WrapURL(buf, b.URL, func() {
-		class := "block"
-		if b.Brittle {
-			class = "block brittle"
-		}
-		if b.VulnSeverity != "" {
-			class += " vuln vuln-" + b.VulnSeverity
-		}
+		class := styles.BlockClass(b)
 		fmt.Fprintf(buf, `<path id="block-%s" class="%s" d="%s" fill="%s" stroke="#333" stroke-width="2" stroke-linejoin="round" transform="rotate(%.3f %.2f %.2f)"/>`,
 			styles.EscapeXML(b.ID), class, path, fill, rot, b.CX, b.CY)
 	})
@@ -94,6 +102,28 @@ func (h *HandDrawn) RenderBlock(buf *bytes.Buffer, b styles.Block) {
 	}
 }
 
+// RenderSubdivider sketches pass-through column segments translucent with a
+// dashed outline.
+func (h *HandDrawn) RenderSubdivider(buf *bytes.Buffer, b styles.Block) {
+	rot := rotationFor(b.ID, b.W, b.H)
+	path := jitteredRect(b.X, b.Y, b.W, b.H, h.seed, b.ID, h.wobbleFor(b.W, b.H))
+	styles.WrapURL(buf, b.URL, func() {
+		fmt.Fprintf(buf, `<path id="block-%s" class="%s" d="%s" fill="%s" fill-opacity="%g" stroke="#333" stroke-width="2" stroke-dasharray="6,4" stroke-linejoin="round" transform="rotate(%.3f %.2f %.2f)"/>`,
+			styles.EscapeXML(b.ID), styles.BlockClass(b), path, cmp.Or(b.Fill, greyForID(b.ID)), subdividerOpacity, rot, b.CX, b.CY)
+	})
+	buf.WriteByte('\n')
+}
+
+// RenderAuxiliary sketches separator beams with pencil hatching.
+func (h *HandDrawn) RenderAuxiliary(buf *bytes.Buffer, b styles.Block) {
+	rot := rotationFor(b.ID, b.W, b.H)
+	path := jitteredRect(b.X, b.Y, b.W, b.H, h.seed, b.ID, h.wobbleFor(b.W, b.H))
+	fmt.Fprintf(buf, `<path id="block-%s" class="%s" d="%s" fill="white" stroke="#333" stroke-width="2" stroke-linejoin="round" transform="rotate(%.3f %.2f %.2f)"/>`+"\n",
+		styles.EscapeXML(b.ID), styles.BlockClass(b), path, rot, b.CX, b.CY)
+	fmt.Fprintf(buf, `  <path class="block-hatch" d="%s" stroke="#333" stroke-width="1.2" stroke-opacity="0.5" stroke-linecap="round" pointer-events="none" transform="rotate(%.3f %.2f %.2f)"/>`+"\n",
+		styles.HatchPath(b.X, b.Y, b.W, b.H, auxHatchSpacing), rot, b.CX, b.CY)
+}
+
 func (h *HandDrawn) RenderFlags(buf *bytes.Buffer, b styles.Block) {
 	rot := rotationFor(b.ID, b.W, b.H)
 	slotIdx := 0
@@ -138,39 +168,23 @@ func renderFlag(buf *bytes.Buffer, b styles.Block, cssClass, color, tooltip stri
 }
 
 func (h *HandDrawn) RenderEdge(buf *bytes.Buffer, e styles.Edge) {
-	path := curvedEdge(e.X1, e.Y1, e.X2, e.Y2)
+	path := e.Path
+	if path == "" {
+		path = curvedEdge(e.X1, e.Y1, e.X2, e.Y2)
+	}
 	fmt.Fprintf(buf, `  <path class="edge" d="%s" fill="none" stroke="#333" stroke-width="2.5" stroke-dasharray="8,5" stroke-linecap="round"/>`+"\n", path)
 }
 
 func (h *HandDrawn) RenderText(buf *bytes.Buffer, b styles.Block) {
-	rotate := styles.ShouldRotate(b)
-	size := styles.FontSize(b)
-	if rotate {
-		size = styles.FontSizeRotated(b)
-	}
-
-	bgFill := greyForID(b.ID)
-	textFill := "#333"
-
-	label := styles.TruncateLabel(b, rotate)
-
-	textW, textH := float64(len(label))*size*textWidthRatio, size*textHeightRatio
-	if rotate {
-		textW, textH = textH, textW
-	}
+	fit := styles.FitLabel(b)
+	bgFill := cmp.Or(b.Fill, greyForID(b.ID))
+	textW, textH := fit.Extent(textWidthRatio, textHeightRatio)
 
 	fmt.Fprintf(buf, `  <g class="block-text" data-block="%s">`+"\n", styles.EscapeXML(b.ID))
 	styles.WrapURL(buf, b.URL, func() {
 		fmt.Fprintf(buf, `    <rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"/>`+"\n",
 			b.CX-textW/2, b.CY-textH/2, textW, textH, bgFill)
-
-		if rotate {
-			fmt.Fprintf(buf, `    <text x="%.2f" y="%.2f" text-anchor="middle" dominant-baseline="middle" font-family="%s" font-size="%.1f" fill="%s" transform="rotate(-90 %.2f %.2f)">%s</text>`+"\n",
-				b.CX, b.CY, fonts.FallbackFontFamily, size, textFill, b.CX, b.CY, styles.EscapeXML(label))
-		} else {
-			fmt.Fprintf(buf, `    <text x="%.2f" y="%.2f" text-anchor="middle" dominant-baseline="middle" font-family="%s" font-size="%.1f" fill="%s">%s</text>`+"\n",
-				b.CX, b.CY, fonts.FallbackFontFamily, size, textFill, styles.EscapeXML(label))
-		}
+		styles.WriteLabel(buf, fit, b.Label, b.CX, b.CY, fmt.Sprintf(` font-family="%s" fill="%s"`, h.fontStack(), styles.ContrastText(bgFill)))
 	})
 	buf.WriteString("  </g>\n")
 }
@@ -182,6 +196,15 @@ func (h *HandDrawn) RenderPopup(buf *bytes.Buffer, b styles.Block) {
 	}
 
 	descLines := wrapText(p.Description, charsPerLine)
+	if len(p.Lines) > 0 {
+		descLines = descLines[:0]
+		for _, line := range p.Lines {
+			descLines = append(descLines, wrapText(line, charsPerLine)...)
+		}
+	}
+	for _, f := range p.Fields {
+		descLines = append(descLines, wrapText(f.Label+": "+f.Value, charsPerLine)...)
+	}
 	numDescLines := max(1, len(descLines))
 
 	hasStats := p.Stars > 0 || p.LastCommit != "" || p.LastRelease != ""
@@ -208,7 +231,7 @@ func (h *HandDrawn) RenderPopup(buf *bytes.Buffer, b styles.Block) {
 	}
 
 	height := float64(numDescLines+statsRows+licenseRows+vulnRows)*popupLineHeight + popupPadding
-	path := wobbledRect(0, 0, popupWidth, height, h.seed, b.ID+"_popup")
+	path := jitteredRect(0, 0, popupWidth, height, h.seed, b.ID+"_popup", h.wobbleFor(popupWidth, height))
 
 	fmt.Fprintf(buf, `  <g class="popup" data-for="%s" visibility="hidden">`+"\n", styles.EscapeXML(b.ID))
 	fmt.Fprintf(buf, `    <path d="%s" fill="white" stroke="#333" stroke-width="1.5" stroke-linejoin="round"/>`+"\n", path)
@@ -216,7 +239,7 @@ func (h *HandDrawn) RenderPopup(buf *bytes.Buffer, b styles.Block) {
 	textY := popupTextStartY
 	for _, line := range descLines {
 		fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" font-family="%s" font-size="%.0f" fill="#444">%s</text>`+"\n",
-			popupTextX, textY, fonts.FallbackFontFamily, popupTextSize, styles.EscapeXML(line))
+			popupTextX, textY, h.fontStack(), popupTextSize, styles.EscapeXML(line))
 		textY += popupLineHeight
 	}
 
@@ -233,18 +256,18 @@ func (h *HandDrawn) RenderPopup(buf *bytes.Buffer, b styles.Block) {
 
 		if p.LastCommit != "" {
 			fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" text-anchor="end" font-family="%s" font-size="%.0f" fill="#444">%slast commit: %s</text>`+"\n",
-				dateRightX, rightY, fonts.FallbackFontFamily, popupTextSize, warnPrefix, p.LastCommit)
+				dateRightX, rightY, h.fontStack(), popupTextSize, warnPrefix, p.LastCommit)
 			rightY += popupLineHeight * dateLineSpacing
 		}
 		if p.LastRelease != "" && p.LastRelease != "0001-01-01" {
 			fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" text-anchor="end" font-family="%s" font-size="%.0f" fill="#444">%slast release: %s</text>`+"\n",
-				dateRightX, rightY, fonts.FallbackFontFamily, popupTextSize, warnPrefix, p.LastRelease)
+				dateRightX, rightY, h.fontStack(), popupTextSize, warnPrefix, p.LastRelease)
 		}
 
 		if p.Stars > 0 {
 			starsCenterY := statsStartY + (popupLineHeight*float64(statsRows))/2 - popupStarShift
 			fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="middle" font-family="%s" font-size="%.0f" fill="#222" font-weight="bold">★ %s</text>`+"\n",
-				leftCenterX, starsCenterY, fonts.FallbackFontFamily, popupStarSize, formatNumber(p.Stars))
+				leftCenterX, starsCenterY, h.fontStack(), popupStarSize, formatNumber(p.Stars))
 		}
 		textY += popupLineHeight * float64(statsRows)
 	}
@@ -314,7 +337,7 @@ func (h *HandDrawn) RenderPopup(buf *bytes.Buffer, b styles.Block) {
 			fmt.Fprintf(buf, `    <rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="%.1f" ry="%.1f" fill="%s"/>`+"\n",
 				x, badgeY, w, badgeH, badgeR, badgeR, bd.bg)
 			fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="middle" font-family="%s" font-size="%.0f" fill="%s" font-weight="600">%s</text>`+"\n",
-				x+w/2, badgeY+badgeH/2, fonts.FallbackFontFamily, badgeFontSize, bd.fg, styles.EscapeXML(bd.label))
+				x+w/2, badgeY+badgeH/2, h.fontStack(), badgeFontSize, bd.fg, styles.EscapeXML(bd.label))
 			x += w + badgeGap
 		}
 	}
@@ -175,6 +175,31 @@ func TestHandDrawn_RenderPopup_Nil(t *testing.T) {
 	}
 }
 
+func TestHandDrawn_RenderPopup_CustomContent(t *testing.T) {
+	h := New(42)
+	block := styles.Block{
+		ID: "svc",
+		Popup: &styles.PopupData{
+			Description: "default description",
+			Lines:       []string{"owned by payments"},
+			Fields:      []styles.PopupField{{Label: "sla_tier", Value: "1"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	h.RenderPopup(&buf, block)
+	output := buf.String()
+
+	if strings.Contains(output, "default description") {
+		t.Errorf("template lines should replace the description: %s", output)
+	}
+	for _, want := range []string{"owned by payments", "sla_tier: 1"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("RenderPopup() missing %q: %s", want, output)
+		}
+	}
+}
+
 func TestHandDrawn_RenderPopup(t *testing.T) {
 	h := New(42)
 	block := styles.Block{
@@ -282,3 +307,13 @@ func TestWrapText(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisteredWithDefaultSeed(t *testing.T) {
+	s, err := styles.New("handdrawn", styles.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h, ok := s.(*HandDrawn); !ok || h.seed != DefaultSeed {
+		t.Errorf("styles.New(handdrawn) = %#v, want *HandDrawn with seed %d", s, DefaultSeed)
+	}
+}
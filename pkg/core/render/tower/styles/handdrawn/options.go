@@ -0,0 +1,126 @@
+package handdrawn
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/fonts"
+)
+
+// DefaultWobble is the maximum outline jitter, in pixels, used when
+// [Options.Wobble] is zero.
+const DefaultWobble = wobble
+
+// smallBlockSize is the shorter side below which [Options.DampenSmall]
+// scales the wobble down linearly.
+const smallBlockSize = 60.0
+
+// customFontFamily names an embedded [Options.Font] that has no
+// [Options.FontFamily].
+const customFontFamily = "stacktower-handdrawn"
+
+// Options replaces the hand-drawn style's built-in assets and tunes its
+// wobble. The zero value reproduces [New] with seed 0.
+type Options struct {
+	// Seed makes the wobble reproducible; the same seed gives the same lines.
+	Seed uint64
+	// Wobble is the maximum jitter of outline points in pixels. Zero uses
+	// DefaultWobble; a negative value draws straight outlines.
+	Wobble float64
+	// DampenSmall scales the wobble down on blocks whose shorter side is
+	// under 60px, where full jitter eats into the label.
+	DampenSmall bool
+	// Texture is the image (URL or data URI) tiled over brittle blocks.
+	// Empty uses the built-in paper texture.
+	Texture string
+	// Font is a WOFF, WOFF2, TrueType or OpenType file embedded in the SVG
+	// for labels and popups. Empty uses the built-in xkcd Script.
+	Font []byte
+	// FontFamily names the label font. With Font it names the embedded
+	// face; without, it selects an installed font such as "Caveat".
+	FontFamily string
+}
+
+// NewWithOptions creates a HandDrawn style with custom assets and wobble.
+func NewWithOptions(opts Options) *HandDrawn {
+	return &HandDrawn{seed: opts.Seed, opts: opts}
+}
+
+// wobbleFor returns the outline jitter for a w×h shape.
+func (h *HandDrawn) wobbleFor(w, ht float64) float64 {
+	amount := cmp.Or(h.opts.Wobble, DefaultWobble)
+	if amount < 0 {
+		return 0
+	}
+	if side := min(w, ht); h.opts.DampenSmall && side < smallBlockSize {
+		amount *= max(0, side) / smallBlockSize
+	}
+	return amount
+}
+
+// fontStack returns the CSS font-family list for labels and popups.
+func (h *HandDrawn) fontStack() string {
+	switch {
+	case len(h.opts.Font) > 0:
+		return fmt.Sprintf("'%s', sans-serif", cssString(cmp.Or(h.opts.FontFamily, customFontFamily)))
+	case h.opts.FontFamily != "":
+		return fmt.Sprintf("'%s', %s", cssString(h.opts.FontFamily), fonts.FallbackFontFamily)
+	default:
+		return fonts.FallbackFontFamily
+	}
+}
+
+// writeFontFace writes the @font-face rule for the embedded font. An
+// installed FontFamily without Font data needs none.
+func (h *HandDrawn) writeFontFace(buf *bytes.Buffer) {
+	family, mime, format, data := "xkcd Script", "font/woff", "woff", fonts.XKCDScriptWOFFBase64()
+	switch {
+	case len(h.opts.Font) > 0:
+		family = cmp.Or(h.opts.FontFamily, customFontFamily)
+		mime, format = fontFormat(h.opts.Font)
+		data = base64.StdEncoding.EncodeToString(h.opts.Font)
+	case h.opts.FontFamily != "":
+		return
+	}
+	fmt.Fprintf(buf, `      @font-face {
+        font-family: '%s';
+        src: url('data:%s;base64,`, cssString(family), mime)
+	buf.WriteString(data)
+	fmt.Fprintf(buf, `') format('%s');
+        font-weight: normal;
+        font-style: normal;
+      }
+`, format)
+}
+
+// texture returns the brittle texture image reference.
+func (h *HandDrawn) texture() string {
+	if h.opts.Texture != "" {
+		return h.opts.Texture
+	}
+	return getBrittleTextureDataURI()
+}
+
+// fontFormat sniffs a font file's MIME type and CSS format() name from its
+// magic bytes, defaulting to TrueType.
+func fontFormat(data []byte) (mime, format string) {
+	switch {
+	case bytes.HasPrefix(data, []byte("wOF2")):
+		return "font/woff2", "woff2"
+	case bytes.HasPrefix(data, []byte("wOFF")):
+		return "font/woff", "woff"
+	case bytes.HasPrefix(data, []byte("OTTO")):
+		return "font/otf", "opentype"
+	default:
+		return "font/ttf", "truetype"
+	}
+}
+
+// cssString strips characters that would end a quoted CSS string or the
+// surrounding XML attribute.
+func cssString(s string) string {
+	return strings.NewReplacer(`'`, "", `"`, "", `\`, "", "<", "", ">", "", "&", "").Replace(s)
+}
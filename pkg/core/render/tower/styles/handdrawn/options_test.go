@@ -0,0 +1,77 @@
+package handdrawn
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+)
+
+func TestOptions_Wobble(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		w, h float64
+		want float64
+	}{
+		{"default", Options{}, 100, 100, DefaultWobble},
+		{"custom", Options{Wobble: 1.5}, 100, 100, 1.5},
+		{"straight", Options{Wobble: -1}, 100, 100, 0},
+		{"dampened small block", Options{DampenSmall: true}, 200, 30, DefaultWobble / 2},
+		{"large block not dampened", Options{DampenSmall: true}, 200, 80, DefaultWobble},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewWithOptions(tt.opts).wobbleFor(tt.w, tt.h); got != tt.want {
+				t.Errorf("wobbleFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptions_StraightOutline(t *testing.T) {
+	var buf bytes.Buffer
+	NewWithOptions(Options{Wobble: -1}).RenderBlock(&buf, styles.Block{ID: "a", X: 0, Y: 0, W: 100, H: 100, CX: 50, CY: 50})
+	// Without jitter every outline point sits on the rectangle's edges.
+	if !strings.Contains(buf.String(), "Q 0.00 0.00 16.67 0.00") {
+		t.Errorf("expected an unjittered outline: %s", buf.String())
+	}
+}
+
+func TestOptions_Assets(t *testing.T) {
+	font := append([]byte("wOF2"), 1, 2, 3)
+	h := NewWithOptions(Options{Font: font, FontFamily: "Caveat", Texture: "https://example.com/paper.png"})
+
+	var defs bytes.Buffer
+	h.RenderDefs(&defs)
+	out := defs.String()
+	if !strings.Contains(out, "font-family: 'Caveat';") || !strings.Contains(out, "data:font/woff2;base64,") ||
+		!strings.Contains(out, "format('woff2')") {
+		t.Errorf("custom font not embedded: %s", out)
+	}
+	if strings.Contains(out, "xkcd Script") {
+		t.Error("built-in font should be replaced")
+	}
+	if !strings.Contains(out, `href="https://example.com/paper.png"`) {
+		t.Error("custom texture not used")
+	}
+
+	var text bytes.Buffer
+	h.RenderText(&text, styles.Block{ID: "a", Label: "a", W: 100, H: 40, CX: 50, CY: 20})
+	if !strings.Contains(text.String(), `font-family="'Caveat', sans-serif"`) {
+		t.Errorf("labels should use the custom font: %s", text.String())
+	}
+}
+
+func TestOptions_InstalledFont(t *testing.T) {
+	h := NewWithOptions(Options{FontFamily: "Caveat"})
+	var defs bytes.Buffer
+	h.RenderDefs(&defs)
+	if strings.Contains(defs.String(), "@font-face") {
+		t.Error("an installed font needs no @font-face")
+	}
+	if stack := h.fontStack(); !strings.HasPrefix(stack, "'Caveat', 'xkcd Script'") {
+		t.Errorf("fontStack() = %q, want Caveat first with the usual fallbacks", stack)
+	}
+}
@@ -16,13 +16,18 @@ const (
 )
 
 func wobbledRect(x, y, w, h float64, seed uint64, id string) string {
+	return jitteredRect(x, y, w, h, seed, id, wobble)
+}
+
+// jitteredRect is wobbledRect with an explicit maximum jitter in pixels.
+func jitteredRect(x, y, w, h float64, seed uint64, id string, amount float64) string {
 	rng := newRNG(hash(id, seed))
 
 	numH := max(minSegs, int(w*segDensity))
 	numV := max(minSegs, int(h*segDensity))
 
 	jitter := func(v float64) float64 {
-		return v + (rng.next()*2-1)*wobble
+		return v + (rng.next()*2-1)*amount
 	}
 
 	pts := make([][2]float64, 0, (numH+numV)*2)
@@ -0,0 +1,38 @@
+package styles
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderIcon(t *testing.T) {
+	const icon = "data:image/png;base64,AAAA"
+	tests := []struct {
+		name  string
+		block Block
+		want  string
+	}{
+		{"drawn in corner", Block{ID: "a", W: 200, H: 90, Icon: icon}, `x="3.00" y="3.00" width="20.00" height="20.00"`},
+		{"scaled to block", Block{ID: "a", W: 200, H: 30, Icon: icon}, `width="10.00" height="10.00"`},
+		{"too small", Block{ID: "a", W: 20, H: 20, Icon: icon}, ""},
+		{"no icon", Block{ID: "a", W: 200, H: 90}, ""},
+		{"remote URL rejected", Block{ID: "a", W: 200, H: 90, Icon: "https://example.com/a.png"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			RenderIcon(&buf, tt.block)
+			out := buf.String()
+			if tt.want == "" {
+				if out != "" {
+					t.Errorf("RenderIcon() = %q, want nothing", out)
+				}
+				return
+			}
+			if !strings.Contains(out, tt.want) || !strings.Contains(out, `href="`+icon+`"`) {
+				t.Errorf("RenderIcon() = %q, want %s", out, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package styles
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+
+	"github.com/stacktower-io/stacktower/pkg/security"
+)
+
+const (
+	isometricDepthRatio = 0.25 // Depth as a fraction of the block's shorter side
+	isometricMaxDepth   = 14.0
+	isometricTopShade   = 1.25 // Top faces catch the light
+	isometricSideShade  = 0.7  // Side faces fall into shadow
+	isometricStroke     = "#2b2b2b"
+	isometricFont       = `'Helvetica Neue',Helvetica,Arial,sans-serif`
+)
+
+// isometricPalette holds the default front-face colours. Each block picks
+// one deterministically from its ID so renders are stable.
+var isometricPalette = Palette{
+	"#b5523b", // brick red
+	"#c8744a", // terracotta
+	"#d19a5b", // sandstone
+	"#8e6f5e", // clay
+	"#6f8fa6", // slate blue
+	"#7d9a6b", // moss
+	"#a78bb5", // lavender
+	"#c9b27c", // ochre
+}
+
+// Isometric renders every block as a 3D brick with a lit top face and a
+// shaded side face, so the tower reads as a physical stack. Intended for
+// talks and posters rather than dense diagrams.
+//
+// The extrusion is drawn inside each block's own rectangle so neighbouring
+// blocks never overlap. Depth defaults to a quarter of the block's shorter
+// side, capped at 14 units. Palette replaces the default earth tones, e.g.
+// with [PaletteOkabeIto] or brand colours.
+type Isometric struct {
+	Depth   float64 // Fixed extrusion depth; zero picks one per block
+	Palette Palette // Brick colours; nil uses a terracotta/slate mix
+}
+
+// depth returns the extrusion depth for b, leaving enough front face for a
+// label on small blocks.
+func (s Isometric) depth(b Block) float64 {
+	d := s.Depth
+	if d <= 0 {
+		d = min(isometricMaxDepth, min(b.W, b.H)*isometricDepthRatio)
+	}
+	return max(0, min(d, b.W/2, b.H/2))
+}
+
+// front returns b shrunk to its front face, with the centre moved to match.
+func (s Isometric) front(b Block) Block {
+	d := s.depth(b)
+	b.Y += d
+	b.W -= d
+	b.H -= d
+	b.CX -= d / 2
+	b.CY += d / 2
+	return b
+}
+
+func (Isometric) RenderDefs(*bytes.Buffer) {}
+
+func (s Isometric) RenderBlock(buf *bytes.Buffer, b Block) {
+	d := s.depth(b)
+	f := s.front(b)
+	base := cmp.Or(b.Fill, s.color(b.ID))
+	WrapURL(buf, b.URL, func() {
+		class := BlockClass(b)
+		// Stroke is set on the group so the hover highlight thickens every face.
+		fmt.Fprintf(buf, `<g id="block-%s" class="%s" stroke="%s" stroke-width="1" stroke-linejoin="round">`, EscapeXML(b.ID), class, isometricStroke)
+		// Top face: parallelogram from the front's top edge back and to the right.
+		fmt.Fprintf(buf, `<path class="face-top" d="M%.2f %.2f L%.2f %.2f L%.2f %.2f L%.2f %.2f Z" fill="%s"/>`,
+			f.X, f.Y, f.X+d, b.Y, b.X+b.W, b.Y, f.X+f.W, f.Y, shadeHex(base, isometricTopShade))
+		// Side face: parallelogram from the front's right edge back and up.
+		fmt.Fprintf(buf, `<path class="face-side" d="M%.2f %.2f L%.2f %.2f L%.2f %.2f L%.2f %.2f Z" fill="%s"/>`,
+			f.X+f.W, f.Y, b.X+b.W, b.Y, b.X+b.W, b.Y+b.H-d, f.X+f.W, f.Y+f.H, shadeHex(base, isometricSideShade))
+		fmt.Fprintf(buf, `<rect class="face-front" x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"/>`,
+			f.X, f.Y, f.W, f.H, base)
+		buf.WriteString(`</g>`)
+	})
+	buf.WriteByte('\n')
+}
+
+func (s Isometric) RenderFlags(buf *bytes.Buffer, b Block) {
+	f := s.front(b)
+	slotIdx := 0
+	licenseRisk := security.LicenseRiskFromString(b.LicenseRisk)
+	if licenseRisk == security.LicenseRiskCopyleft || licenseRisk == security.LicenseRiskWeakCopyleft {
+		licenseTooltip := b.LicenseRisk
+		if b.License != "" {
+			licenseTooltip = fmt.Sprintf("%s (%s)", b.License, b.LicenseRisk)
+		}
+		renderFlag(buf, f, "license-flag license-"+b.LicenseRisk, licenseRisk.IconColor(), isometricStroke, licenseTooltip, slotIdx)
+		slotIdx++
+	}
+	if b.VulnSeverity != "" {
+		renderFlag(buf, f, "vuln-flag vuln-flag-"+b.VulnSeverity, simpleVulnFlagColor, isometricStroke, "vuln: "+b.VulnSeverity, slotIdx)
+	}
+}
+
+func (Isometric) RenderEdge(buf *bytes.Buffer, e Edge) {
+	if e.Path != "" {
+		fmt.Fprintf(buf, `  <path class="edge" d="%s" fill="none" stroke="%s" stroke-width="1.5" stroke-dasharray="6,4"/>`+"\n", e.Path, isometricStroke)
+		return
+	}
+	fmt.Fprintf(buf, `  <line class="edge" x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s" stroke-width="1.5" stroke-dasharray="6,4"/>`+"\n",
+		e.X1, e.Y1, e.X2, e.Y2, isometricStroke)
+}
+
+func (s Isometric) RenderText(buf *bytes.Buffer, b Block) {
+	f := s.front(b)
+	fit := FitLabel(f)
+
+	fmt.Fprintf(buf, `  <g class="block-text" data-block="%s">`+"\n", EscapeXML(b.ID))
+	WrapURL(buf, b.URL, func() {
+		// Labels are printed onto the brick, so no knock-out background.
+		WriteLabel(buf, fit, b.Label, f.CX, f.CY, fmt.Sprintf(` font-family="%s" font-weight="bold" fill="white" stroke="%s" stroke-width="0.6" paint-order="stroke"`, isometricFont, isometricStroke))
+	})
+	buf.WriteString("  </g>\n")
+}
+
+// RenderSubdivider draws pass-through column segments as translucent bricks.
+func (s Isometric) RenderSubdivider(buf *bytes.Buffer, b Block) {
+	fmt.Fprintf(buf, `<g opacity="%g">`, subdividerOpacity)
+	s.RenderBlock(buf, b)
+	buf.WriteString("</g>\n")
+}
+
+// RenderAuxiliary draws separator beams as bricks with a hatched front face.
+func (s Isometric) RenderAuxiliary(buf *bytes.Buffer, b Block) {
+	s.RenderBlock(buf, b)
+	f := s.front(b)
+	fmt.Fprintf(buf, `  <path class="block-hatch" d="%s" stroke="%s" stroke-width="0.75" stroke-opacity="0.6" pointer-events="none"/>`+"\n",
+		HatchPath(f.X, f.Y, f.W, f.H, hatchSpacing), isometricStroke)
+}
+
+func (Isometric) RenderPopup(*bytes.Buffer, Block) {}
+
+// color picks a stable palette colour for a block ID.
+func (s Isometric) color(id string) string {
+	if len(s.Palette) > 0 {
+		return s.Palette.Pick(id)
+	}
+	return isometricPalette.Pick(id)
+}
+
+// shadeHex scales each channel of a #rrggbb colour by factor, clamping to
+// the valid range. Malformed input is returned unchanged.
+func shadeHex(hex string, factor float64) string {
+	var r, g, b int
+	if _, err := fmt.Sscanf(hex, "#%02x%02x%02x", &r, &g, &b); err != nil {
+		return hex
+	}
+	scale := func(c int) int { return max(0, min(255, int(float64(c)*factor+0.5))) }
+	return fmt.Sprintf("#%02x%02x%02x", scale(r), scale(g), scale(b))
+}
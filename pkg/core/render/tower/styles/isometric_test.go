@@ -0,0 +1,89 @@
+package styles
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestIsometric_RenderBlock(t *testing.T) {
+	var buf bytes.Buffer
+	Isometric{}.RenderBlock(&buf, Block{ID: "a", X: 0, Y: 0, W: 100, H: 40})
+	out := buf.String()
+	for _, face := range []string{`class="face-top"`, `class="face-side"`, `class="face-front"`} {
+		if !strings.Contains(out, face) {
+			t.Errorf("RenderBlock() missing %s: %s", face, out)
+		}
+	}
+	// Default depth is a quarter of the shorter side: 40 * 0.25 = 10.
+	if !strings.Contains(out, `x="0.00" y="10.00" width="90.00" height="30.00"`) {
+		t.Errorf("front face should be inset by the depth: %s", out)
+	}
+}
+
+func TestIsometric_Depth(t *testing.T) {
+	tests := []struct {
+		name  string
+		style Isometric
+		block Block
+		want  float64
+	}{
+		{"auto", Isometric{}, Block{W: 100, H: 40}, 10},
+		{"auto capped", Isometric{}, Block{W: 400, H: 200}, isometricMaxDepth},
+		{"fixed", Isometric{Depth: 6}, Block{W: 100, H: 40}, 6},
+		{"fixed clamped to half block", Isometric{Depth: 30}, Block{W: 100, H: 20}, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.style.depth(tt.block); got != tt.want {
+				t.Errorf("depth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsometric_StableColor(t *testing.T) {
+	if (Isometric{}).color("requests") != (Isometric{}).color("requests") {
+		t.Error("colour should be deterministic per ID")
+	}
+	custom := Isometric{Palette: Palette{"#123456"}}
+	if got := custom.color("requests"); got != "#123456" {
+		t.Errorf("color() = %q, want custom palette colour", got)
+	}
+	var a, b bytes.Buffer
+	Isometric{}.RenderBlock(&a, Block{ID: "requests", W: 100, H: 40})
+	Isometric{}.RenderBlock(&b, Block{ID: "requests", W: 100, H: 40})
+	if a.String() != b.String() {
+		t.Error("RenderBlock() should be deterministic")
+	}
+}
+
+func TestShadeHex(t *testing.T) {
+	tests := []struct {
+		in     string
+		factor float64
+		want   string
+	}{
+		{"#808080", 0.5, "#404040"},
+		{"#c0c0c0", 2, "#ffffff"},
+		{"not-a-colour", 0.5, "not-a-colour"},
+	}
+	for _, tt := range tests {
+		if got := shadeHex(tt.in, tt.factor); got != tt.want {
+			t.Errorf("shadeHex(%q, %v) = %q, want %q", tt.in, tt.factor, got, tt.want)
+		}
+	}
+}
+
+func TestIsometric_RenderText(t *testing.T) {
+	var buf bytes.Buffer
+	Isometric{}.RenderText(&buf, Block{ID: "flask", Label: "flask", X: 0, Y: 0, W: 200, H: 40, CX: 100, CY: 20})
+	out := buf.String()
+	// Label sits on the front face: centre shifted by half the depth (10/2).
+	if !strings.Contains(out, `x="95.00" y="25.00"`) {
+		t.Errorf("label should be centred on the front face: %s", out)
+	}
+	if !strings.Contains(out, ">flask</text>") {
+		t.Errorf("missing label: %s", out)
+	}
+}
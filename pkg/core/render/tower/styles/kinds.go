@@ -0,0 +1,89 @@
+package styles
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// BlockKind says what a block stands for. Layout transforms add synthetic
+// blocks next to the real packages, and styles should draw them so they are
+// not mistaken for dependencies.
+type BlockKind int
+
+const (
+	// BlockPackage is a real package from the dependency graph.
+	BlockPackage BlockKind = iota
+	// BlockSubdivider is one segment of a pass-through column, inserted
+	// where a dependency spans several rows.
+	BlockSubdivider
+	// BlockAuxiliary is a separator beam inserted to resolve overlapping
+	// spans. It has no label.
+	BlockAuxiliary
+)
+
+const (
+	subdividerOpacity = 0.45 // Pass-through columns let the canvas show through
+	hatchSpacing      = 6.0  // Distance between separator beam hatch lines
+)
+
+// SubdividerRenderer is implemented by styles that draw pass-through column
+// segments differently from packages, e.g. translucent.
+type SubdividerRenderer interface {
+	RenderSubdivider(buf *bytes.Buffer, b Block)
+}
+
+// AuxiliaryRenderer is implemented by styles that draw separator beams
+// differently from packages, e.g. hatched.
+type AuxiliaryRenderer interface {
+	RenderAuxiliary(buf *bytes.Buffer, b Block)
+}
+
+// RenderShape draws a block's shape with the hook matching its kind. Styles
+// without the hook fall back to RenderBlock.
+func RenderShape(buf *bytes.Buffer, s Style, b Block) {
+	switch b.Kind {
+	case BlockSubdivider:
+		if r, ok := s.(SubdividerRenderer); ok {
+			r.RenderSubdivider(buf, b)
+			return
+		}
+	case BlockAuxiliary:
+		if r, ok := s.(AuxiliaryRenderer); ok {
+			r.RenderAuxiliary(buf, b)
+			return
+		}
+	}
+	s.RenderBlock(buf, b)
+}
+
+// HatchPath returns SVG path data for 45° hatch lines spaced spacing apart
+// and clipped to the rectangle, so hatching needs no <pattern> in defs.
+func HatchPath(x, y, w, h, spacing float64) string {
+	var d strings.Builder
+	for t := spacing; t < w+h; t += spacing {
+		// Each line runs along u+v = t in block-local coordinates.
+		u0, u1 := max(0, t-h), min(w, t)
+		fmt.Fprintf(&d, "M%.2f %.2f L%.2f %.2f ", x+u0, y+t-u0, x+u1, y+t-u1)
+	}
+	return strings.TrimSpace(d.String())
+}
+
+// renderTranslucentRect draws a subdivider as a see-through, dash-outlined
+// rectangle in the style's colours.
+func renderTranslucentRect(buf *bytes.Buffer, b Block, radius float64, fill, stroke string, strokeWidth float64) {
+	WrapURL(buf, b.URL, func() {
+		fmt.Fprintf(buf, `<rect id="block-%s" class="%s" x="%.2f" y="%.2f" width="%.2f" height="%.2f" rx="%.1f" ry="%.1f" fill="%s" fill-opacity="%g" stroke="%s" stroke-width="%g" stroke-dasharray="3,3"/>`,
+			EscapeXML(b.ID), BlockClass(b), b.X, b.Y, b.W, b.H, radius, radius, fill, subdividerOpacity, stroke, strokeWidth)
+	})
+	buf.WriteByte('\n')
+}
+
+// renderHatchedRect draws a separator beam as an outlined rectangle filled
+// with diagonal hatching in the stroke colour.
+func renderHatchedRect(buf *bytes.Buffer, b Block, radius float64, fill, stroke string, strokeWidth float64) {
+	fmt.Fprintf(buf, `<rect id="block-%s" class="%s" x="%.2f" y="%.2f" width="%.2f" height="%.2f" rx="%.1f" ry="%.1f" fill="%s" stroke="%s" stroke-width="%g"/>`+"\n",
+		EscapeXML(b.ID), BlockClass(b), b.X, b.Y, b.W, b.H, radius, radius, fill, stroke, strokeWidth)
+	fmt.Fprintf(buf, `  <path class="block-hatch" d="%s" stroke="%s" stroke-width="0.75" stroke-opacity="0.6" pointer-events="none"/>`+"\n",
+		HatchPath(b.X, b.Y, b.W, b.H, hatchSpacing), stroke)
+}
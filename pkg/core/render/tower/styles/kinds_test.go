@@ -0,0 +1,83 @@
+package styles
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRenderShape_Hooks(t *testing.T) {
+	tests := []struct {
+		kind BlockKind
+		want []string
+	}{
+		{BlockPackage, []string{`class="block"`}},
+		{BlockSubdivider, []string{`class="block subdivider"`, `fill-opacity="0.45"`, `stroke-dasharray="3,3"`}},
+		{BlockAuxiliary, []string{`class="block auxiliary"`, `class="block-hatch"`}},
+	}
+	for _, s := range []Style{Simple{}, Blueprint{}, mustTheme(t)} {
+		for _, tt := range tests {
+			var buf bytes.Buffer
+			RenderShape(&buf, s, Block{ID: "x", W: 60, H: 40, Kind: tt.kind})
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("%T kind %d: missing %s in %s", s, tt.kind, want, buf.String())
+				}
+			}
+		}
+	}
+}
+
+func TestIsometric_RenderSubdivider(t *testing.T) {
+	var buf bytes.Buffer
+	RenderShape(&buf, Isometric{}, Block{ID: "x", W: 60, H: 40, Kind: BlockSubdivider})
+	if out := buf.String(); !strings.HasPrefix(out, `<g opacity="0.45">`) || !strings.Contains(out, `class="face-front"`) {
+		t.Errorf("subdivider bricks should be faded as a whole: %s", out)
+	}
+}
+
+func TestRenderShape_FallsBackToRenderBlock(t *testing.T) {
+	var buf bytes.Buffer
+	RenderShape(&buf, bareStyle{}, Block{ID: "x", Kind: BlockAuxiliary})
+	if buf.String() != "block" {
+		t.Errorf("styles without hooks should get RenderBlock, got %q", buf.String())
+	}
+}
+
+func TestHatchPath_StaysInside(t *testing.T) {
+	d := HatchPath(10, 20, 30, 12, 6)
+	if d == "" {
+		t.Fatal("expected hatch lines")
+	}
+	for _, seg := range strings.Split(d, "M")[1:] {
+		var x0, y0, x1, y1 float64
+		if _, err := fmt.Sscanf(seg, "%f %f L%f %f", &x0, &y0, &x1, &y1); err != nil {
+			t.Fatalf("bad segment %q: %v", seg, err)
+		}
+		for _, p := range [][2]float64{{x0, y0}, {x1, y1}} {
+			if p[0] < 10 || p[0] > 40 || p[1] < 20 || p[1] > 32 {
+				t.Errorf("hatch point %v outside the block", p)
+			}
+		}
+	}
+}
+
+// bareStyle implements only Style, with no per-kind hooks.
+type bareStyle struct{}
+
+func (bareStyle) RenderDefs(*bytes.Buffer)               {}
+func (bareStyle) RenderBlock(buf *bytes.Buffer, _ Block) { buf.WriteString("block") }
+func (bareStyle) RenderFlags(*bytes.Buffer, Block)       {}
+func (bareStyle) RenderEdge(*bytes.Buffer, Edge)         {}
+func (bareStyle) RenderText(*bytes.Buffer, Block)        {}
+func (bareStyle) RenderPopup(*bytes.Buffer, Block)       {}
+
+func mustTheme(t *testing.T) *Theme {
+	t.Helper()
+	theme, err := FromConfig(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return theme
+}
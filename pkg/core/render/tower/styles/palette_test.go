@@ -0,0 +1,78 @@
+package styles
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParsePalette(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Palette
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"okabe-ito", PaletteOkabeIto, false},
+		{"Viridis", PaletteViridis, false},
+		{"#FF0000, #00ff00", Palette{"#ff0000", "#00ff00"}, false},
+		{"#ff0000,red", nil, true},
+		{"#fff", nil, true},
+		{"rainbow", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePalette(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePalette(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParsePalette(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPalette_ColorAndPick(t *testing.T) {
+	p := Palette{"#111111", "#222222"}
+	if got := p.Color(3); got != "#222222" {
+		t.Errorf("Color(3) = %q, want wrap-around", got)
+	}
+	if got := (Palette{}).Color(0); got != "" {
+		t.Errorf("empty Color() = %q, want empty", got)
+	}
+	if p.Pick("flask") != p.Pick("flask") || !slices.Contains(p, p.Pick("flask")) {
+		t.Error("Pick() should be deterministic and drawn from the palette")
+	}
+}
+
+func TestContrastText(t *testing.T) {
+	tests := map[string]string{
+		"#ffffff": "#333",
+		"#fde725": "#333",
+		"#440154": "white",
+		"#0072b2": "white",
+		"white":   "#333",
+	}
+	for fill, want := range tests {
+		if got := ContrastText(fill); got != want {
+			t.Errorf("ContrastText(%q) = %q, want %q", fill, got, want)
+		}
+	}
+}
+
+func TestLighten(t *testing.T) {
+	tests := []struct {
+		in   string
+		f    float64
+		want string
+	}{
+		{"#000000", 0.5, "#808080"},
+		{"#ff0000", 0, "#ff0000"},
+		{"#123456", 1, "#ffffff"},
+		{"red", 0.5, "red"},
+	}
+	for _, tt := range tests {
+		if got := Lighten(tt.in, tt.f); got != tt.want {
+			t.Errorf("Lighten(%q, %v) = %q, want %q", tt.in, tt.f, got, tt.want)
+		}
+	}
+}
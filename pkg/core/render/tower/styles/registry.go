@@ -0,0 +1,76 @@
+package styles
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Factory builds a style for one render. Styles are registered by name with
+// [Register] and looked up with [New].
+type Factory func(Options) Style
+
+// Options carries the per-render settings passed to a [Factory]. Styles use
+// the fields that make sense for them and ignore the rest.
+type Options struct {
+	Seed    uint64  // Seed for styles with random jitter (0 = the style's default)
+	Palette Palette // Colours chosen by the user, e.g. --palette (nil = the style's default)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+func init() {
+	Register("simple", func(Options) Style { return Simple{} })
+	Register("blueprint", func(Options) Style { return Blueprint{} })
+	Register("isometric", func(o Options) Style { return Isometric{Palette: o.Palette} })
+}
+
+// Register makes a style available under name, e.g. for --style. It is
+// meant to be called from an init function; like database/sql.Register it
+// panics if name is empty, factory is nil, or the name is already taken.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if name == "" || factory == nil {
+		panic("styles: Register called with empty name or nil factory")
+	}
+	if _, dup := registry[name]; dup {
+		panic("styles: Register called twice for style " + name)
+	}
+	registry[name] = factory
+}
+
+// New builds the style registered under name.
+func New(name string, opts Options) (Style, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown style %q (registered: %s)", name, strings.Join(Names(), ", "))
+	}
+	return factory(opts), nil
+}
+
+// Registered reports whether a style is registered under name.
+func Registered(name string) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	_, ok := registry[name]
+	return ok
+}
+
+// Names returns the names of all registered styles, sorted.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
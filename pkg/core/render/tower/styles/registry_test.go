@@ -0,0 +1,59 @@
+package styles
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+type testStyle struct{ Simple }
+
+func TestRegistry_BuiltIns(t *testing.T) {
+	for _, name := range []string{"simple", "blueprint", "isometric"} {
+		if !Registered(name) {
+			t.Errorf("%s should be registered", name)
+		}
+	}
+	s, err := New("isometric", Options{Palette: Palette{"#123456"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if iso, ok := s.(Isometric); !ok || iso.Palette[0] != "#123456" {
+		t.Errorf("New(isometric) = %#v, want an Isometric with the given palette", s)
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	Register("test-custom", func(Options) Style { return testStyle{} })
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(registry, "test-custom")
+		registryMu.Unlock()
+	})
+
+	if !slices.Contains(Names(), "test-custom") {
+		t.Errorf("Names() = %v, want test-custom", Names())
+	}
+	s, err := New("test-custom", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	s.RenderBlock(&buf, Block{ID: "a", W: 10, H: 10})
+	if buf.Len() == 0 {
+		t.Error("custom style should render")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a duplicate name should panic")
+		}
+	}()
+	Register("test-custom", func(Options) Style { return testStyle{} })
+}
+
+func TestRegistry_Unknown(t *testing.T) {
+	if _, err := New("nope", Options{}); err == nil {
+		t.Error("New(nope) should fail")
+	}
+}
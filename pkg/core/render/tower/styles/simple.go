@@ -20,17 +20,57 @@ func (Simple) RenderDefs(*bytes.Buffer) {}
 
 func (Simple) RenderBlock(buf *bytes.Buffer, b Block) {
 	radius := min(maxCornerRadius, b.W/cornerRatioDivisor, b.H/cornerRatioDivisor)
+	stroke, width, dash := simpleRiskStroke(b)
 	WrapURL(buf, b.URL, func() {
-		class := "block"
-		if b.VulnSeverity != "" {
-			class += " vuln vuln-" + b.VulnSeverity
-		}
-		fmt.Fprintf(buf, `<rect id="block-%s" class="%s" x="%.2f" y="%.2f" width="%.2f" height="%.2f" rx="%.1f" ry="%.1f" fill="%s" stroke="#333" stroke-width="1"/>`,
-			EscapeXML(b.ID), class, b.X, b.Y, b.W, b.H, radius, radius, "white")
+		fmt.Fprintf(buf, `<rect id="block-%s" class="%s" x="%.2f" y="%.2f" width="%.2f" height="%.2f" rx="%.1f" ry="%.1f" fill="%s" stroke="%s" stroke-width="%g"%s/>`,
+			EscapeXML(b.ID), BlockClass(b), b.X, b.Y, b.W, b.H, radius, radius, simpleFill(b), stroke, width, dash)
 	})
 	buf.WriteByte('\n')
 }
 
+// RenderSubdivider draws pass-through column segments translucent with a
+// dashed outline.
+func (Simple) RenderSubdivider(buf *bytes.Buffer, b Block) {
+	radius := min(maxCornerRadius, b.W/cornerRatioDivisor, b.H/cornerRatioDivisor)
+	renderTranslucentRect(buf, b, radius, simpleFill(b), "#333", 1)
+}
+
+// RenderAuxiliary draws separator beams hatched.
+func (Simple) RenderAuxiliary(buf *bytes.Buffer, b Block) {
+	radius := min(maxCornerRadius, b.W/cornerRatioDivisor, b.H/cornerRatioDivisor)
+	renderHatchedRect(buf, b, radius, "white", "#333", 1)
+}
+
+// simpleFill returns the block fill: the colouring strategy's choice if
+// set, a pale red tint for brittle packages, a pale blue one for
+// first-party packages, and white otherwise.
+func simpleFill(b Block) string {
+	switch {
+	case b.Fill != "":
+		return b.Fill
+	case b.Brittle:
+		return simpleBrittleFill
+	case b.Internal:
+		return simpleInternalFill
+	}
+	return "white"
+}
+
+// simpleRiskStroke returns the outline for a block. Vulnerable packages get
+// a thick outline in their severity colour; brittle packages get a dashed
+// one, so the two states stay distinguishable when both apply and when
+// colour is unavailable.
+func simpleRiskStroke(b Block) (color string, width float64, dash string) {
+	color, width = "#333", 1
+	if b.Brittle {
+		color, width, dash = simpleBrittleStroke, 2, ` stroke-dasharray="5,3"`
+	}
+	if b.VulnSeverity != "" {
+		color, width = security.SeverityFromString(b.VulnSeverity).Color(), 2.5
+	}
+	return color, width, dash
+}
+
 func (Simple) RenderFlags(buf *bytes.Buffer, b Block) {
 	slotIdx := 0
 	licenseRisk := security.LicenseRiskFromString(b.LicenseRisk)
@@ -39,11 +79,11 @@ func (Simple) RenderFlags(buf *bytes.Buffer, b Block) {
 		if b.License != "" {
 			licenseTooltip = fmt.Sprintf("%s (%s)", b.License, b.LicenseRisk)
 		}
-		renderSimpleFlag(buf, b, "license-flag license-"+b.LicenseRisk, licenseRisk.IconColor(), licenseTooltip, slotIdx)
+		renderFlag(buf, b, "license-flag license-"+b.LicenseRisk, licenseRisk.IconColor(), "#333", licenseTooltip, slotIdx)
 		slotIdx++
 	}
 	if b.VulnSeverity != "" {
-		renderSimpleFlag(buf, b, "vuln-flag vuln-flag-"+b.VulnSeverity, simpleVulnFlagColor, "vuln: "+b.VulnSeverity, slotIdx)
+		renderFlag(buf, b, "vuln-flag vuln-flag-"+b.VulnSeverity, simpleVulnFlagColor, "#333", "vuln: "+b.VulnSeverity, slotIdx)
 	}
 }
 
@@ -57,11 +97,14 @@ const (
 	simpleFlagGap   = 3.0 // horizontal gap between adjacent flags
 
 	simpleVulnFlagColor = "#c2410c" // dark orange — all vulnerability severities
+	simpleBrittleStroke = "#b91c1c" // red-700 dashed outline for brittle packages
+	simpleBrittleFill   = "#fee2e2" // red-100 tint behind brittle packages
+	simpleInternalFill  = "#dbeafe" // blue-100 tint behind first-party packages
 )
 
-// renderSimpleFlag draws a pennant flag anchored at the top of the block.
+// renderFlag draws a pennant flag anchored at the top of the block.
 // slotIdx controls horizontal placement: 0 = rightmost, 1 = next slot left, etc.
-func renderSimpleFlag(buf *bytes.Buffer, b Block, cssClass, color, tooltip string, slotIdx int) {
+func renderFlag(buf *bytes.Buffer, b Block, cssClass, color, poleColor, tooltip string, slotIdx int) {
 	poleX := b.X + b.W - simpleFlagPadX - float64(slotIdx)*(simpleFlagW+simpleFlagGap)
 	poleTopY := b.Y + simpleFlagPadY
 	poleBotY := poleTopY + simpleFlagPoleH
@@ -70,8 +113,8 @@ func renderSimpleFlag(buf *bytes.Buffer, b Block, cssClass, color, tooltip strin
 		cssClass, EscapeXML(b.ID))
 	fmt.Fprintf(buf, `    <title>%s</title>`+"\n", EscapeXML(tooltip))
 	// Pole
-	fmt.Fprintf(buf, `    <line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#333" stroke-width="%.1f" stroke-linecap="round"/>`+"\n",
-		poleX, poleTopY, poleX, poleBotY, simpleFlagPoleW)
+	fmt.Fprintf(buf, `    <line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s" stroke-width="%.1f" stroke-linecap="round"/>`+"\n",
+		poleX, poleTopY, poleX, poleBotY, poleColor, simpleFlagPoleW)
 	// Pennant triangle: tip points leftward into the block
 	fmt.Fprintf(buf, `    <path d="M%.2f %.2f L%.2f %.2f L%.2f %.2f Z" fill="%s"/>`+"\n",
 		poleX, poleTopY,
@@ -82,35 +125,24 @@ func renderSimpleFlag(buf *bytes.Buffer, b Block, cssClass, color, tooltip strin
 }
 
 func (Simple) RenderEdge(buf *bytes.Buffer, e Edge) {
+	if e.Path != "" {
+		fmt.Fprintf(buf, `  <path class="edge" d="%s" fill="none" stroke="#333" stroke-width="1.5" stroke-dasharray="6,4"/>`+"\n", e.Path)
+		return
+	}
 	fmt.Fprintf(buf, `  <line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#333" stroke-width="1.5" stroke-dasharray="6,4"/>`+"\n",
 		e.X1, e.Y1, e.X2, e.Y2)
 }
 
 func (Simple) RenderText(buf *bytes.Buffer, b Block) {
-	rotate := ShouldRotate(b)
-	size := FontSize(b)
-	if rotate {
-		size = FontSizeRotated(b)
-	}
-	label := TruncateLabel(b, rotate)
-
-	textW, textH := float64(len(label))*size*textWidthRatio, size*textHeightRatio
-	if rotate {
-		textW, textH = textH, textW
-	}
+	fit := FitLabel(b)
+	bgFill := simpleFill(b)
+	textW, textH := fit.Extent(textWidthRatio, textHeightRatio)
 
 	fmt.Fprintf(buf, `  <g class="block-text" data-block="%s">`+"\n", EscapeXML(b.ID))
 	WrapURL(buf, b.URL, func() {
 		fmt.Fprintf(buf, `    <rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"/>`+"\n",
-			b.CX-textW/2, b.CY-textH/2, textW, textH, "white")
-
-		if rotate {
-			fmt.Fprintf(buf, `    <text x="%.2f" y="%.2f" text-anchor="middle" dominant-baseline="middle" font-family="Times,serif" font-size="%.1f" fill="%s" transform="rotate(-90 %.2f %.2f)">%s</text>`+"\n",
-				b.CX, b.CY, size, "#333", b.CX, b.CY, EscapeXML(label))
-		} else {
-			fmt.Fprintf(buf, `    <text x="%.2f" y="%.2f" text-anchor="middle" dominant-baseline="middle" font-family="Times,serif" font-size="%.1f" fill="%s">%s</text>`+"\n",
-				b.CX, b.CY, size, "#333", EscapeXML(label))
-		}
+			b.CX-textW/2, b.CY-textH/2, textW, textH, bgFill)
+		WriteLabel(buf, fit, b.Label, b.CX, b.CY, fmt.Sprintf(` font-family="Times,serif" fill="%s"`, ContrastText(bgFill)))
 	})
 	buf.WriteString("  </g>\n")
 }
@@ -83,6 +83,72 @@ func TestSimpleRenderBlock(t *testing.T) {
 	}
 }
 
+func TestSimpleRenderBlock_RiskStates(t *testing.T) {
+	tests := []struct {
+		name     string
+		block    Block
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "healthy",
+			block:    Block{ID: "ok", W: 100, H: 50},
+			contains: []string{`class="block"`, `fill="white" stroke="#333" stroke-width="1"/>`},
+			excludes: []string{"stroke-dasharray"},
+		},
+		{
+			name:     "brittle",
+			block:    Block{ID: "old", W: 100, H: 50, Brittle: true},
+			contains: []string{`class="block brittle"`, `fill="#fee2e2"`, `stroke="#b91c1c" stroke-width="2" stroke-dasharray="5,3"`},
+		},
+		{
+			name:     "vulnerable",
+			block:    Block{ID: "cve", W: 100, H: 50, VulnSeverity: "critical"},
+			contains: []string{`class="block vuln vuln-critical"`, `fill="white" stroke="#dc2626" stroke-width="2.5"/>`},
+		},
+		{
+			name:     "brittle and vulnerable keep both cues",
+			block:    Block{ID: "both", W: 100, H: 50, Brittle: true, VulnSeverity: "high"},
+			contains: []string{`class="block brittle vuln vuln-high"`, `stroke="#ea580c" stroke-width="2.5" stroke-dasharray="5,3"`},
+		},
+		{
+			name:     "internal",
+			block:    Block{ID: "ours", W: 100, H: 50, Internal: true},
+			contains: []string{`class="block internal"`, `fill="#dbeafe" stroke="#333" stroke-width="1"/>`},
+		},
+		{
+			name:     "colour-by fill wins over brittle tint",
+			block:    Block{ID: "fill", W: 100, H: 50, Brittle: true, Fill: "#8dd3c7"},
+			contains: []string{`fill="#8dd3c7"`, `stroke-dasharray="5,3"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			Simple{}.RenderBlock(&buf, tt.block)
+			out := buf.String()
+			for _, want := range tt.contains {
+				if !strings.Contains(out, want) {
+					t.Errorf("RenderBlock() missing %q\nGot: %s", want, out)
+				}
+			}
+			for _, bad := range tt.excludes {
+				if strings.Contains(out, bad) {
+					t.Errorf("RenderBlock() should not contain %q\nGot: %s", bad, out)
+				}
+			}
+		})
+	}
+}
+
+func TestSimpleRenderText_BrittleBackground(t *testing.T) {
+	var buf bytes.Buffer
+	Simple{}.RenderText(&buf, Block{ID: "old", Label: "old", W: 100, H: 50, CX: 50, CY: 25, Brittle: true})
+	if !strings.Contains(buf.String(), `fill="#fee2e2"/>`) {
+		t.Errorf("label background should match the brittle tint\nGot: %s", buf.String())
+	}
+}
+
 func TestSimpleRenderBlockCornerRadius(t *testing.T) {
 	s := Simple{}
 
@@ -130,6 +196,19 @@ func TestSimpleRenderEdge(t *testing.T) {
 	}
 }
 
+func TestSimpleRenderEdge_Path(t *testing.T) {
+	var buf bytes.Buffer
+	Simple{}.RenderEdge(&buf, Edge{FromID: "a", ToID: "b", Path: "M 0 0 V 10 H 20 V 30"})
+	output := buf.String()
+
+	if !strings.Contains(output, `<path class="edge" d="M 0 0 V 10 H 20 V 30"`) {
+		t.Errorf("routed edge should be drawn as a path\nGot: %s", output)
+	}
+	if strings.Contains(output, "<line") {
+		t.Errorf("routed edge should not fall back to a line\nGot: %s", output)
+	}
+}
+
 func TestSimpleRenderText(t *testing.T) {
 	s := Simple{}
 
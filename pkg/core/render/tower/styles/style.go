@@ -7,7 +7,10 @@ import "bytes"
 type Style interface {
 	// RenderDefs writes SVG <defs> content (filters, patterns, gradients).
 	RenderDefs(buf *bytes.Buffer)
-	// RenderBlock writes the SVG for a single block shape.
+	// RenderBlock writes the SVG for a single block shape. The shape carries
+	// id="block-<ID>" and the classes from [BlockClass], and should make
+	// Block.Brittle and Block.VulnSeverity visible without relying on
+	// colour alone, so risk survives --color-by fills and greyscale prints.
 	RenderBlock(buf *bytes.Buffer, b Block)
 	// RenderFlags writes the SVG for a block's security flags (license, vuln).
 	// Flags are rendered separately so they can be drawn on top of all blocks.
@@ -20,6 +23,40 @@ type Style interface {
 	RenderPopup(buf *bytes.Buffer, b Block)
 }
 
+// Themed is implemented by styles that draw on a colored canvas instead of
+// plain white. The sink paints the tower frame with Background (any SVG
+// paint, including url(#id) references to RenderDefs patterns) and draws
+// the watermark in Foreground.
+type Themed interface {
+	Background() string
+	Foreground() string
+}
+
+// BlockClass returns the CSS classes for a block's shape: "block", plus
+// "subdivider" or "auxiliary" for synthetic blocks, "internal" for
+// first-party packages and "brittle" and "vuln vuln-<severity>" for
+// at-risk packages. Highlighting scripts and theme stylesheets select on
+// these.
+func BlockClass(b Block) string {
+	class := "block"
+	switch b.Kind {
+	case BlockSubdivider:
+		class += " subdivider"
+	case BlockAuxiliary:
+		class += " auxiliary"
+	}
+	if b.Internal {
+		class += " internal"
+	}
+	if b.Brittle {
+		class += " brittle"
+	}
+	if b.VulnSeverity != "" {
+		class += " vuln vuln-" + b.VulnSeverity
+	}
+	return class
+}
+
 // Block contains all data needed to render a single tower block.
 type Block struct {
 	ID           string     // Node identifier
@@ -29,26 +66,43 @@ type Block struct {
 	URL          string     // Optional link target
 	Popup        *PopupData // Hover popup content (nil if disabled)
 	Brittle      bool       // Whether to apply brittle/warning styling
+	Internal     bool       // First-party package rather than an external dependency
 	VulnSeverity string     // Indicates the maximum vulnerability severity for this package
 	License      string     // License name (e.g., "MIT", "GPL-3.0")
 	LicenseRisk  string     // License risk classification ("copyleft","weak-copyleft","unknown","")
+	Fill         string     // Fill colour chosen by a colouring strategy; styles use their own when empty
+	Icon         string     // Package icon as a data URI (empty if icons are disabled)
+	Kind         BlockKind  // Package, or a synthetic subdivider/auxiliary block
 }
 
 // PopupData holds metadata displayed in hover popups.
+//
+// When Lines is set (from a popup template) it replaces Description as the
+// popup body. Fields are extra metadata rows shown below the body.
 type PopupData struct {
-	Description  string // Package description
-	Stars        int    // GitHub stars (0 if unknown)
-	LastCommit   string // Last commit date
-	LastRelease  string // Last release date
-	Archived     bool   // Repository archived flag
-	Brittle      bool   // Package flagged as brittle
-	License      string // License name (e.g., "MIT", "GPL-3.0")
-	LicenseRisk  string // License risk classification
-	VulnSeverity string // Maximum vulnerability severity (e.g., "critical", "high")
+	Description  string       // Package description
+	Stars        int          // GitHub stars (0 if unknown)
+	LastCommit   string       // Last commit date
+	LastRelease  string       // Last release date
+	Archived     bool         // Repository archived flag
+	Brittle      bool         // Package flagged as brittle
+	License      string       // License name (e.g., "MIT", "GPL-3.0")
+	LicenseRisk  string       // License risk classification
+	VulnSeverity string       // Maximum vulnerability severity (e.g., "critical", "high")
+	BusFactor    int          // Contributors behind half the commits (0 if unknown)
+	Lines        []string     // Custom body lines; overrides Description when non-empty
+	Fields       []PopupField // Additional metadata rows, in display order
 }
 
 // Edge contains positioning data for rendering a dependency edge.
 type Edge struct {
 	FromID, ToID   string  // Connected node IDs
 	X1, Y1, X2, Y2 float64 // Line coordinates
+	Path           string  // Routed SVG path data; when set, styles draw it instead of the straight line
+}
+
+// PopupField is one labelled metadata row in a hover popup.
+type PopupField struct {
+	Label string
+	Value string
 }
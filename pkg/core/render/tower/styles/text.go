@@ -25,10 +25,12 @@ func fontSizeFor(availWidth, availHeight float64, textLen int) float64 {
 	return max(fontSizeMin, min(fontSizeMax, min(byHeight, byWidth)))
 }
 
-func ShouldRotate(b Block) bool {
-	horizSize := fontSizeFor(b.W, b.H, len(b.ID))
-	rotSize := fontSizeFor(b.H, b.W, len(b.ID))
-	if len(b.ID) > 10 {
+func ShouldRotate(b Block) bool { return shouldRotate(b.W, b.H, len(b.ID)) }
+
+func shouldRotate(w, h float64, textLen int) bool {
+	horizSize := fontSizeFor(w, h, textLen)
+	rotSize := fontSizeFor(h, w, textLen)
+	if textLen > 10 {
 		return rotSize*1.1 >= horizSize
 	}
 	return rotSize > horizSize
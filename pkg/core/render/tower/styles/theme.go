@@ -0,0 +1,401 @@
+package styles
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/stacktower-io/stacktower/pkg/security"
+)
+
+const (
+	themeTextureID       = "theme-texture"
+	themeDefaultFont     = "Times,serif"
+	themeDefaultInk      = "#333"
+	themePopupWidth      = 260.0
+	themePopupFontSize   = 12.0
+	themePopupPadding    = 10.0
+	themePopupLineHeight = 1.5 // Line height as a multiple of the font size
+)
+
+// Config declares a style without writing Go. It is usually loaded from a
+// stacktower-theme.yaml (or .json) file with [LoadConfig] and turned into a
+// [Style] with [FromConfig]. Every field is optional; unset fields fall back
+// to the look of [Simple].
+//
+//	name: acme
+//	background: "#f8fafc"
+//	texture: dots
+//	palette: okabe-ito
+//	block: {stroke: "#0f172a", stroke_width: 1.5, corner_radius: 4}
+//	text: {font: "Inter, sans-serif", weight: "600"}
+//	edge: {color: "#64748b", dash: "4,2"}
+//	popup: {background: "#0f172a", color: "#f8fafc"}
+type Config struct {
+	Name       string      `yaml:"name" json:"name,omitempty"`
+	Background string      `yaml:"background" json:"background,omitempty"` // Canvas colour; empty leaves the page white
+	Foreground string      `yaml:"foreground" json:"foreground,omitempty"` // Watermark colour on the canvas
+	Texture    string      `yaml:"texture" json:"texture,omitempty"`       // Canvas texture: none, grid, dots, lines
+	Palette    string      `yaml:"palette" json:"palette,omitempty"`       // Block colours picked per package (see ParsePalette)
+	Block      BlockConfig `yaml:"block" json:"block,omitempty"`
+	Text       TextConfig  `yaml:"text" json:"text,omitempty"`
+	Edge       EdgeConfig  `yaml:"edge" json:"edge,omitempty"`
+	Popup      PopupConfig `yaml:"popup" json:"popup,omitempty"`
+}
+
+// BlockConfig styles block shapes.
+type BlockConfig struct {
+	Fill         string   `yaml:"fill" json:"fill,omitempty"`                   // Fill when no palette or colouring applies
+	Stroke       string   `yaml:"stroke" json:"stroke,omitempty"`               // Outline colour
+	StrokeWidth  float64  `yaml:"stroke_width" json:"stroke_width,omitempty"`   // Outline width
+	CornerRadius *float64 `yaml:"corner_radius" json:"corner_radius,omitempty"` // Fixed radius; unset scales with the block like Simple
+}
+
+// TextConfig styles block labels.
+type TextConfig struct {
+	Font       string `yaml:"font" json:"font,omitempty"`             // CSS font-family list
+	Color      string `yaml:"color" json:"color,omitempty"`           // Label colour; unset picks one that contrasts with the fill
+	Weight     string `yaml:"weight" json:"weight,omitempty"`         // CSS font-weight, e.g. "bold" or "600"
+	Background string `yaml:"background" json:"background,omitempty"` // Label plate colour; "none" disables it, unset matches the fill
+	Uppercase  bool   `yaml:"uppercase" json:"uppercase,omitempty"`
+}
+
+// EdgeConfig styles dependency edges.
+type EdgeConfig struct {
+	Color string  `yaml:"color" json:"color,omitempty"`
+	Width float64 `yaml:"width" json:"width,omitempty"`
+	Dash  string  `yaml:"dash" json:"dash,omitempty"` // SVG dash array; "none" for solid lines
+}
+
+// PopupConfig styles hover popups.
+type PopupConfig struct {
+	Background string  `yaml:"background" json:"background,omitempty"`
+	Border     string  `yaml:"border" json:"border,omitempty"`
+	Color      string  `yaml:"color" json:"color,omitempty"`
+	Font       string  `yaml:"font" json:"font,omitempty"`
+	FontSize   float64 `yaml:"font_size" json:"font_size,omitempty"`
+	Width      float64 `yaml:"width" json:"width,omitempty"`
+}
+
+// validTextures lists the canvas textures a theme may request.
+var validTextures = map[string]bool{"": true, "none": true, "grid": true, "dots": true, "lines": true}
+
+// ParseConfig decodes a theme from YAML or JSON (JSON is valid YAML).
+// Unknown keys are rejected so typos don't silently fall back to defaults.
+func ParseConfig(data []byte) (Config, error) {
+	var cfg Config
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return Config{}, fmt.Errorf("parse theme: %w", err)
+	}
+	return cfg, nil
+}
+
+// LoadConfig reads and decodes a theme file.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	return ParseConfig(data)
+}
+
+// Theme is a [Style] built from a [Config] by [FromConfig].
+type Theme struct {
+	cfg     Config
+	palette Palette
+}
+
+// Compile-time check that Theme can paint its own canvas.
+var _ Themed = (*Theme)(nil)
+
+// FromConfig validates cfg and returns a style that renders it.
+func FromConfig(cfg Config) (*Theme, error) {
+	if !validTextures[strings.ToLower(cfg.Texture)] {
+		return nil, fmt.Errorf("unknown texture %q (want none, grid, dots, or lines)", cfg.Texture)
+	}
+	palette, err := ParsePalette(cfg.Palette)
+	if err != nil {
+		return nil, err
+	}
+	if r := cfg.Block.CornerRadius; r != nil && *r < 0 {
+		return nil, fmt.Errorf("corner_radius must not be negative, got %v", *r)
+	}
+	if cfg.Block.StrokeWidth < 0 || cfg.Edge.Width < 0 {
+		return nil, errors.New("stroke widths must not be negative")
+	}
+	if err := validateColors(cfg); err != nil {
+		return nil, err
+	}
+	cfg.Texture = strings.ToLower(cfg.Texture)
+	return &Theme{cfg: cfg, palette: palette}, nil
+}
+
+// namedColor matches CSS colour keywords such as "white" or "slategray".
+// Anything with punctuation is rejected so a theme can't break out of the
+// attribute it is written into.
+var namedColor = regexp.MustCompile(`^[a-zA-Z]+$`)
+
+// shortHexColor matches the #rgb shorthand.
+var shortHexColor = regexp.MustCompile(`^#[0-9a-fA-F]{3}$`)
+
+// validateColors checks that every colour in cfg is a hex or named colour.
+// Empty values are allowed and fall back to defaults.
+func validateColors(cfg Config) error {
+	colors := []struct{ field, value string }{
+		{"background", cfg.Background},
+		{"foreground", cfg.Foreground},
+		{"block.fill", cfg.Block.Fill},
+		{"block.stroke", cfg.Block.Stroke},
+		{"text.color", cfg.Text.Color},
+		{"text.background", cfg.Text.Background},
+		{"edge.color", cfg.Edge.Color},
+		{"popup.background", cfg.Popup.Background},
+		{"popup.border", cfg.Popup.Border},
+		{"popup.color", cfg.Popup.Color},
+	}
+	for _, c := range colors {
+		if c.value != "" && !hexColor.MatchString(c.value) && !shortHexColor.MatchString(c.value) && !namedColor.MatchString(c.value) {
+			return fmt.Errorf("invalid %s colour %q (want #rgb, #rrggbb, or a colour name)", c.field, c.value)
+		}
+	}
+	return nil
+}
+
+// Config returns the configuration the theme was built from.
+func (t *Theme) Config() Config { return t.cfg }
+
+// Background implements [Themed]. Themes without a background or texture
+// leave the page white.
+func (t *Theme) Background() string {
+	if t.hasTexture() {
+		return "url(#" + themeTextureID + ")"
+	}
+	return t.cfg.Background
+}
+
+// Foreground implements [Themed].
+func (t *Theme) Foreground() string { return cmp.Or(t.cfg.Foreground, "#000") }
+
+func (t *Theme) hasTexture() bool { return t.cfg.Texture != "" && t.cfg.Texture != "none" }
+
+func (t *Theme) RenderDefs(buf *bytes.Buffer) {
+	if !t.hasTexture() {
+		return
+	}
+	bg := cmp.Or(t.cfg.Background, "white")
+	ink := shadeHex(bg, 0.85)
+	if ink == bg {
+		ink = "#ddd" // Named or malformed colours can't be shaded
+	}
+	ink = EscapeXML(ink)
+	var mark string
+	switch t.cfg.Texture {
+	case "grid":
+		mark = fmt.Sprintf(`<path d="M 16 0 L 0 0 0 16" fill="none" stroke="%s" stroke-width="0.75"/>`, ink)
+	case "dots":
+		mark = fmt.Sprintf(`<circle cx="8" cy="8" r="1.2" fill="%s"/>`, ink)
+	case "lines":
+		mark = fmt.Sprintf(`<path d="M 0 16 L 16 0" stroke="%s" stroke-width="0.75"/>`, ink)
+	}
+	fmt.Fprintf(buf, `  <defs>
+    <pattern id="%s" width="16" height="16" patternUnits="userSpaceOnUse">
+      <rect width="16" height="16" fill="%s"/>
+      %s
+    </pattern>
+  </defs>
+`, themeTextureID, EscapeXML(bg), mark)
+}
+
+// fill resolves a block's colour: colouring strategy, then palette, then
+// the configured fill, then white.
+func (t *Theme) fill(b Block) string {
+	if b.Fill != "" {
+		return b.Fill
+	}
+	if len(t.palette) > 0 {
+		return t.palette.Pick(b.ID)
+	}
+	return cmp.Or(t.cfg.Block.Fill, "white")
+}
+
+func (t *Theme) stroke() string { return cmp.Or(t.cfg.Block.Stroke, themeDefaultInk) }
+
+func (t *Theme) radius(b Block) float64 {
+	if r := t.cfg.Block.CornerRadius; r != nil {
+		return min(*r, b.W/2, b.H/2)
+	}
+	return min(maxCornerRadius, b.W/cornerRatioDivisor, b.H/cornerRatioDivisor)
+}
+
+func (t *Theme) strokeWidth() float64 { return cmp.Or(t.cfg.Block.StrokeWidth, 1) }
+
+func (t *Theme) RenderBlock(buf *bytes.Buffer, b Block) {
+	radius := t.radius(b)
+	WrapURL(buf, b.URL, func() {
+		class := BlockClass(b)
+		fmt.Fprintf(buf, `<rect id="block-%s" class="%s" x="%.2f" y="%.2f" width="%.2f" height="%.2f" rx="%.1f" ry="%.1f" fill="%s" stroke="%s" stroke-width="%.1f"/>`,
+			EscapeXML(b.ID), class, b.X, b.Y, b.W, b.H, radius, radius, EscapeXML(t.fill(b)), EscapeXML(t.stroke()), t.strokeWidth())
+	})
+	buf.WriteByte('\n')
+}
+
+// RenderSubdivider draws pass-through column segments translucent with a
+// dashed outline.
+func (t *Theme) RenderSubdivider(buf *bytes.Buffer, b Block) {
+	renderTranslucentRect(buf, b, t.radius(b), EscapeXML(t.fill(b)), EscapeXML(t.stroke()), t.strokeWidth())
+}
+
+// RenderAuxiliary draws separator beams hatched in the theme's stroke colour.
+func (t *Theme) RenderAuxiliary(buf *bytes.Buffer, b Block) {
+	renderHatchedRect(buf, b, t.radius(b), EscapeXML(cmp.Or(t.cfg.Block.Fill, "white")), EscapeXML(t.stroke()), t.strokeWidth())
+}
+
+func (t *Theme) RenderFlags(buf *bytes.Buffer, b Block) {
+	slotIdx := 0
+	licenseRisk := security.LicenseRiskFromString(b.LicenseRisk)
+	if licenseRisk == security.LicenseRiskCopyleft || licenseRisk == security.LicenseRiskWeakCopyleft {
+		licenseTooltip := b.LicenseRisk
+		if b.License != "" {
+			licenseTooltip = fmt.Sprintf("%s (%s)", b.License, b.LicenseRisk)
+		}
+		renderFlag(buf, b, "license-flag license-"+b.LicenseRisk, licenseRisk.IconColor(), EscapeXML(t.stroke()), licenseTooltip, slotIdx)
+		slotIdx++
+	}
+	if b.VulnSeverity != "" {
+		renderFlag(buf, b, "vuln-flag vuln-flag-"+b.VulnSeverity, simpleVulnFlagColor, EscapeXML(t.stroke()), "vuln: "+b.VulnSeverity, slotIdx)
+	}
+}
+
+func (t *Theme) RenderEdge(buf *bytes.Buffer, e Edge) {
+	color := EscapeXML(cmp.Or(t.cfg.Edge.Color, themeDefaultInk))
+	width := cmp.Or(t.cfg.Edge.Width, 1.5)
+	dash := ""
+	if d := cmp.Or(t.cfg.Edge.Dash, "6,4"); d != "none" {
+		dash = fmt.Sprintf(` stroke-dasharray="%s"`, EscapeXML(d))
+	}
+	if e.Path != "" {
+		fmt.Fprintf(buf, `  <path class="edge" d="%s" fill="none" stroke="%s" stroke-width="%.1f"%s/>`+"\n", e.Path, color, width, dash)
+		return
+	}
+	fmt.Fprintf(buf, `  <line class="edge" x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s" stroke-width="%.1f"%s/>`+"\n",
+		e.X1, e.Y1, e.X2, e.Y2, color, width, dash)
+}
+
+func (t *Theme) RenderText(buf *bytes.Buffer, b Block) {
+	if t.cfg.Text.Uppercase {
+		b.Label = strings.ToUpper(b.Label)
+	}
+	fit := FitLabel(b)
+
+	plate := EscapeXML(cmp.Or(t.cfg.Text.Background, t.fill(b)))
+	color := EscapeXML(cmp.Or(t.cfg.Text.Color, ContrastText(t.fill(b))))
+	weight := ""
+	if t.cfg.Text.Weight != "" {
+		weight = fmt.Sprintf(` font-weight="%s"`, EscapeXML(t.cfg.Text.Weight))
+	}
+
+	textW, textH := fit.Extent(textWidthRatio, textHeightRatio)
+
+	fmt.Fprintf(buf, `  <g class="block-text" data-block="%s">`+"\n", EscapeXML(b.ID))
+	WrapURL(buf, b.URL, func() {
+		if plate != "none" {
+			fmt.Fprintf(buf, `    <rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"/>`+"\n",
+				b.CX-textW/2, b.CY-textH/2, textW, textH, plate)
+		}
+		WriteLabel(buf, fit, b.Label, b.CX, b.CY, fmt.Sprintf(` font-family="%s"%s fill="%s"`,
+			EscapeXML(cmp.Or(t.cfg.Text.Font, themeDefaultFont)), weight, color))
+	})
+	buf.WriteString("  </g>\n")
+}
+
+// RenderPopup draws a plain card with the popup body, metadata fields and a
+// summary line. The sink positions it on hover.
+func (t *Theme) RenderPopup(buf *bytes.Buffer, b Block) {
+	p := b.Popup
+	if p == nil {
+		return
+	}
+	pc := t.cfg.Popup
+	width := cmp.Or(pc.Width, themePopupWidth)
+	fontSize := cmp.Or(pc.FontSize, themePopupFontSize)
+	lineH := fontSize * themePopupLineHeight
+	charsPerLine := max(10, int((width-2*themePopupPadding)/(fontSize*textWidthRatio)))
+
+	var lines []string
+	body := p.Lines
+	if len(body) == 0 {
+		body = []string{p.Description}
+	}
+	for _, line := range body {
+		lines = append(lines, wrapWords(line, charsPerLine)...)
+	}
+	for _, f := range p.Fields {
+		lines = append(lines, wrapWords(f.Label+": "+f.Value, charsPerLine)...)
+	}
+	if summary := popupSummary(p); summary != "" {
+		lines = append(lines, wrapWords(summary, charsPerLine)...)
+	}
+	height := float64(len(lines))*lineH + 2*themePopupPadding
+
+	fmt.Fprintf(buf, `  <g class="popup" data-for="%s" visibility="hidden">`+"\n", EscapeXML(b.ID))
+	fmt.Fprintf(buf, `    <rect width="%.1f" height="%.1f" rx="6" ry="6" fill="%s" stroke="%s" stroke-width="1"/>`+"\n",
+		width, height, EscapeXML(cmp.Or(pc.Background, "white")), EscapeXML(cmp.Or(pc.Border, t.stroke())))
+	y := themePopupPadding + lineH/2
+	for _, line := range lines {
+		fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" dominant-baseline="middle" font-family="%s" font-size="%.0f" fill="%s">%s</text>`+"\n",
+			themePopupPadding, y, EscapeXML(cmp.Or(pc.Font, t.cfg.Text.Font, themeDefaultFont)), fontSize, EscapeXML(cmp.Or(pc.Color, themeDefaultInk)), EscapeXML(line))
+		y += lineH
+	}
+	buf.WriteString("  </g>\n")
+}
+
+// popupSummary joins the popup's stats and risk indicators into one line.
+func popupSummary(p *PopupData) string {
+	var parts []string
+	if p.Stars > 0 {
+		parts = append(parts, fmt.Sprintf("★ %d", p.Stars))
+	}
+	if p.LastCommit != "" {
+		parts = append(parts, "last commit "+p.LastCommit)
+	}
+	if p.License != "" {
+		parts = append(parts, p.License)
+	}
+	if p.VulnSeverity != "" {
+		parts = append(parts, "vuln: "+p.VulnSeverity)
+	}
+	if p.Archived {
+		parts = append(parts, "archived")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// wrapWords breaks s into lines of at most width characters at spaces.
+// Words longer than width get a line of their own.
+func wrapWords(s string, width int) []string {
+	var lines []string
+	var cur strings.Builder
+	for _, word := range strings.Fields(s) {
+		if cur.Len() > 0 && cur.Len()+1+len(word) > width {
+			lines = append(lines, cur.String())
+			cur.Reset()
+		}
+		if cur.Len() > 0 {
+			cur.WriteByte(' ')
+		}
+		cur.WriteString(word)
+	}
+	if cur.Len() > 0 {
+		lines = append(lines, cur.String())
+	}
+	return lines
+}
@@ -0,0 +1,134 @@
+package styles
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testTheme = `
+name: acme
+background: "#f8fafc"
+texture: dots
+block:
+  stroke: "#0f172a"
+  stroke_width: 2
+  corner_radius: 0
+text:
+  font: "Inter, sans-serif"
+  uppercase: true
+edge:
+  color: "#64748b"
+  dash: none
+popup:
+  background: "#0f172a"
+  color: "#f8fafc"
+`
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig([]byte(testTheme))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	if cfg.Name != "acme" || cfg.Block.StrokeWidth != 2 || cfg.Block.CornerRadius == nil || *cfg.Block.CornerRadius != 0 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	// JSON is accepted too.
+	cfg, err = ParseConfig([]byte(`{"palette": "viridis", "edge": {"width": 3}}`))
+	if err != nil || cfg.Palette != "viridis" || cfg.Edge.Width != 3 {
+		t.Errorf("ParseConfig(JSON) = %+v, %v", cfg, err)
+	}
+
+	if _, err := ParseConfig([]byte("blok:\n  fill: red\n")); err == nil {
+		t.Error("ParseConfig() should reject unknown keys")
+	}
+	if _, err := ParseConfig(nil); err != nil {
+		t.Errorf("ParseConfig(empty) error = %v", err)
+	}
+}
+
+func TestFromConfig_Invalid(t *testing.T) {
+	neg := -1.0
+	tests := []Config{
+		{Texture: "marble"},
+		{Palette: "neon"},
+		{Block: BlockConfig{CornerRadius: &neg}},
+		{Edge: EdgeConfig{Width: -2}},
+		{Block: BlockConfig{Stroke: `"/><script>alert(1)</script><x a="`}},
+		{Background: "#12345g"},
+		{Popup: PopupConfig{Color: "red;"}},
+	}
+	for _, cfg := range tests {
+		if _, err := FromConfig(cfg); err == nil {
+			t.Errorf("FromConfig(%+v) should fail", cfg)
+		}
+	}
+}
+
+func TestFromConfig_Colors(t *testing.T) {
+	cfg := Config{Background: "#fff", Block: BlockConfig{Fill: "#AABBCC", Stroke: "navy"}, Text: TextConfig{Background: "none"}}
+	if _, err := FromConfig(cfg); err != nil {
+		t.Errorf("FromConfig(%+v) error = %v", cfg, err)
+	}
+}
+
+func TestTheme_Render(t *testing.T) {
+	cfg, _ := ParseConfig([]byte(testTheme))
+	theme, err := FromConfig(cfg)
+	if err != nil {
+		t.Fatalf("FromConfig() error = %v", err)
+	}
+	if got := theme.Background(); got != "url(#theme-texture)" {
+		t.Errorf("Background() = %q, want texture reference", got)
+	}
+
+	var buf bytes.Buffer
+	theme.RenderDefs(&buf)
+	theme.RenderBlock(&buf, Block{ID: "flask", W: 100, H: 40})
+	theme.RenderEdge(&buf, Edge{X2: 10, Y2: 10})
+	theme.RenderText(&buf, Block{ID: "flask", Label: "flask", W: 100, H: 40, CX: 50, CY: 20})
+	out := buf.String()
+
+	for _, want := range []string{
+		`<pattern id="theme-texture"`,
+		`rx="0.0" ry="0.0" fill="white" stroke="#0f172a" stroke-width="2.0"`,
+		`stroke="#64748b" stroke-width="1.5"/>`,
+		`font-family="Inter, sans-serif"`,
+		`>FLASK</text>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestTheme_FillPrecedence(t *testing.T) {
+	theme, _ := FromConfig(Config{Palette: "#123456", Block: BlockConfig{Fill: "#eeeeee"}})
+	if got := theme.fill(Block{ID: "a"}); got != "#123456" {
+		t.Errorf("palette should win over block fill, got %q", got)
+	}
+	if got := theme.fill(Block{ID: "a", Fill: "#ff0000"}); got != "#ff0000" {
+		t.Errorf("colouring strategy should win over palette, got %q", got)
+	}
+	plain, _ := FromConfig(Config{})
+	if got := plain.fill(Block{ID: "a"}); got != "white" {
+		t.Errorf("default fill = %q, want white", got)
+	}
+}
+
+func TestTheme_RenderPopup(t *testing.T) {
+	theme, _ := FromConfig(Config{Popup: PopupConfig{Background: "#111111", Color: "#eeeeee"}})
+	var buf bytes.Buffer
+	theme.RenderPopup(&buf, Block{ID: "flask", Popup: &PopupData{
+		Description: "A simple framework for building complex web applications.",
+		Stars:       68000,
+		Fields:      []PopupField{{Label: "team", Value: "web"}},
+	}})
+	out := buf.String()
+	for _, want := range []string{`class="popup" data-for="flask"`, `fill="#111111"`, `fill="#eeeeee">team: web</text>`, "★ 68000"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("popup missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package transform
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
@@ -30,6 +33,9 @@ func MergeSubdividers(l layout.Layout, g *dag.DAG) layout.Layout {
 			// to match RowOrders.
 			if len(subgroups) > 1 && !group.containsMaster {
 				key = fmt.Sprintf("%s@%.0f", master, b.Left)
+				if _, taken := blocks[key]; taken {
+					key = fmt.Sprintf("%s@%.0f-%.0f", master, b.Left, b.Right)
+				}
 			}
 			blocks[key] = b
 		}
@@ -58,6 +64,8 @@ type positionGroup struct {
 	containsMaster bool
 }
 
+// groupByPosition groups members by their rounded horizontal extent, left
+// to right, so merged block IDs do not depend on map iteration order.
 func groupByPosition(l layout.Layout, g *dag.DAG, members []string) []positionGroup {
 	type pos struct{ l, r int }
 	groups := make(map[pos]*positionGroup)
@@ -76,9 +84,12 @@ func groupByPosition(l layout.Layout, g *dag.DAG, members []string) []positionGr
 		}
 	}
 
+	keys := slices.SortedFunc(maps.Keys(groups), func(a, b pos) int {
+		return cmp.Or(cmp.Compare(a.l, b.l), cmp.Compare(a.r, b.r))
+	})
 	result := make([]positionGroup, 0, len(groups))
-	for _, grp := range groups {
-		result = append(result, *grp)
+	for _, k := range keys {
+		result = append(result, *groups[k])
 	}
 	return result
 }
@@ -226,3 +226,33 @@ func TestMergeSubdividers_InconsistentPositions_MergesSubgroups(t *testing.T) {
 		t.Error("expected merged subdivider block at position 50-80")
 	}
 }
+
+func TestMergeSubdividers_StableKeys(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "a", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "a_sub_1", Row: 1, Kind: dag.NodeKindSubdivider, MasterID: "a"})
+	_ = g.AddNode(dag.Node{ID: "a_sub_2", Row: 2, Kind: dag.NodeKindSubdivider, MasterID: "a"})
+
+	// Both subdivider groups start at x=50, so the second needs its right
+	// edge in the key as well.
+	layout := layout.Layout{
+		Blocks: map[string]layout.Block{
+			"a":       {NodeID: "a", Left: 10, Right: 40, Bottom: 50, Top: 75},
+			"a_sub_1": {NodeID: "a_sub_1", Left: 50, Right: 80, Bottom: 25, Top: 50},
+			"a_sub_2": {NodeID: "a_sub_2", Left: 50, Right: 90, Bottom: 0, Top: 25},
+		},
+	}
+
+	want := map[string]float64{"a": 40, "a@50": 80, "a@50-90": 90} // key → right edge
+	for range 20 {
+		merged := MergeSubdividers(layout, g)
+		if len(merged.Blocks) != len(want) {
+			t.Fatalf("got %d blocks, want %d", len(merged.Blocks), len(want))
+		}
+		for key, right := range want {
+			if b, ok := merged.Blocks[key]; !ok || b.Right != right {
+				t.Fatalf("block %q = %+v, want right edge %.0f", key, b, right)
+			}
+		}
+	}
+}
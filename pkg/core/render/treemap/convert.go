@@ -0,0 +1,18 @@
+package treemap
+
+import (
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+// Export creates a serializable treemap layout holding the nodes and edges of
+// g; see [graph.ExportGraphLayout].
+func Export(g *dag.DAG, width, height float64, style string) graph.Layout {
+	return graph.ExportGraphLayout(g, graph.VizTypeTreemap, width, height, style)
+}
+
+// Parse rebuilds the graph stored in a serialized treemap layout; see
+// [graph.ParseGraphLayout].
+func Parse(layout graph.Layout) (*dag.DAG, error) {
+	return graph.ParseGraphLayout(layout, graph.VizTypeTreemap)
+}
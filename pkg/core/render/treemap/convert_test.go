@@ -0,0 +1,15 @@
+package treemap
+
+import (
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+func TestExportParse(t *testing.T) {
+	if l := Export(weightedGraph(), 800, 600, "simple"); l.VizType != graph.VizTypeTreemap {
+		t.Errorf("Export() viz_type = %q, want %q", l.VizType, graph.VizTypeTreemap)
+	} else if _, err := Parse(l); err != nil {
+		t.Errorf("Parse() error: %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+// Package treemap draws dependency graphs as nested, squarified treemaps.
+//
+// The root package fills the map and every package's rectangle holds the
+// rectangles of the packages below it. Area encodes a weight chosen with
+// [Options.Weight]:
+//
+//   - [WeightDeps]: transitive dependency count (the default)
+//   - [WeightDownloads]: registry download counts
+//   - [WeightSize]: unpacked install size
+//
+// so the same graph can be read as "what pulls in the most packages", "what
+// is most used" or "what takes up the most disk". Like a sunburst, a
+// treemap keeps a fixed size however many packages the graph has; small
+// rectangles lose their labels and keep their tooltips.
+//
+// # Architecture
+//
+// Like nodelink and sunburst, a treemap is computed from the graph at
+// render time:
+//
+//	Treemap: DAG → Build() → Map → RenderSVG() → SVG
+//
+// [Build] reduces the graph to a breadth-first spanning tree: a package
+// required by several others is drawn once, under the parent closest to
+// the root, and its tooltip counts the other dependents. Siblings are laid
+// out with the squarified algorithm, which keeps rectangles close to square
+// so their areas are easy to compare.
+//
+// # Usage
+//
+//	svg, err := treemap.RenderSVG(g, treemap.Options{Weight: treemap.WeightDownloads})
+//
+// Rectangles under each direct dependency share one palette colour, fading
+// with depth; vulnerable packages are filled dark orange and brittle
+// packages (see feature.IsBrittle) outlined in red.
+package treemap
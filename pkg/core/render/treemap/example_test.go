@@ -0,0 +1,26 @@
+package treemap_test
+
+import (
+	"fmt"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/treemap"
+)
+
+func ExampleBuild() {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "web", Meta: dag.Metadata{"downloads": 3000}})
+	_ = g.AddNode(dag.Node{ID: "db", Meta: dag.Metadata{"downloads": 1000}})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "web"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "db"})
+
+	m, _ := treemap.Build(g, treemap.Options{Width: 400, Height: 216, Weight: treemap.WeightDownloads})
+	for _, r := range m.Rects {
+		fmt.Printf("%s: %.0fx%.0f (%.0f%%)\n", r.ID, r.W, r.H, 100*r.Weight/m.Total)
+	}
+	// Output:
+	// app: 400x216 (100%)
+	// web: 297x198 (75%)
+	// db: 99x198 (25%)
+}
@@ -0,0 +1,80 @@
+package treemap
+
+// box is an axis-aligned rectangle in pixels.
+type box struct{ x, y, w, h float64 }
+
+// squarify divides b among weights with the squarified treemap algorithm
+// (Bruls, Huizing and van Wijk, 2000): items are laid out in rows along the
+// shorter side of the remaining space, and a row grows only while that
+// keeps its worst aspect ratio from getting worse. weights should be sorted
+// in decreasing order, except that trailing zeros are allowed; the boxes
+// come back in the same order, with zero weights getting empty boxes.
+func squarify(weights []float64, b box) []box {
+	out := make([]box, len(weights))
+	var total float64
+	for _, w := range weights {
+		total += max(w, 0)
+	}
+	if total <= 0 || b.w <= 0 || b.h <= 0 {
+		return out
+	}
+	scale := b.w * b.h / total
+	areas := make([]float64, len(weights))
+	for i, w := range weights {
+		areas[i] = max(w, 0) * scale
+	}
+
+	for i := 0; i < len(areas); {
+		if areas[i] <= 0 {
+			out[i] = box{b.x, b.y, 0, 0}
+			i++
+			continue
+		}
+		side := min(b.w, b.h)
+		j := i + 1
+		for j < len(areas) && areas[j] > 0 && worst(areas[i:j+1], side) <= worst(areas[i:j], side) {
+			j++
+		}
+
+		var row float64
+		for _, a := range areas[i:j] {
+			row += a
+		}
+		if b.w >= b.h {
+			// Wide space: the row is a column on the left.
+			thick := row / b.h
+			y := b.y
+			for k := i; k < j; k++ {
+				h := areas[k] / thick
+				out[k] = box{b.x, y, thick, h}
+				y += h
+			}
+			b.x, b.w = b.x+thick, b.w-thick
+		} else {
+			// Tall space: the row runs along the top.
+			thick := row / b.w
+			x := b.x
+			for k := i; k < j; k++ {
+				w := areas[k] / thick
+				out[k] = box{x, b.y, w, thick}
+				x += w
+			}
+			b.y, b.h = b.y+thick, b.h-thick
+		}
+		i = j
+	}
+	return out
+}
+
+// worst returns the largest aspect ratio (long side over short side) among
+// the given areas laid out as one row along a side of the given length.
+func worst(row []float64, side float64) float64 {
+	var sum, lo, hi float64
+	lo = row[0]
+	for _, a := range row {
+		sum += a
+		lo, hi = min(lo, a), max(hi, a)
+	}
+	s2, side2 := sum*sum, side*side
+	return max(side2*hi/s2, s2/(side2*lo))
+}
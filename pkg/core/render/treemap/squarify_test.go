@@ -0,0 +1,40 @@
+package treemap
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSquarify(t *testing.T) {
+	// The example from Bruls, Huizing and van Wijk: a 6x4 rectangle.
+	weights := []float64{6, 6, 4, 3, 2, 2, 1}
+	boxes := squarify(weights, box{0, 0, 6, 4})
+
+	var sum float64
+	for i, b := range boxes {
+		if math.Abs(b.w*b.h-weights[i]) > 1e-9 {
+			t.Errorf("box %d = %+v, area %v, want %v", i, b, b.w*b.h, weights[i])
+		}
+		if b.x < 0 || b.y < 0 || b.x+b.w > 6+1e-9 || b.y+b.h > 4+1e-9 {
+			t.Errorf("box %d = %+v outside the rectangle", i, b)
+		}
+		sum += b.w * b.h
+	}
+	if math.Abs(sum-24) > 1e-9 {
+		t.Errorf("boxes cover %v, want 24", sum)
+	}
+	// The first row holds the two largest items stacked on the left.
+	if b := boxes[0]; b.x != 0 || b.y != 0 || b.w != 3 || b.h != 2 {
+		t.Errorf("first box = %+v, want {0 0 3 2}", b)
+	}
+}
+
+func TestSquarify_Degenerate(t *testing.T) {
+	if got := squarify([]float64{0, 0}, box{0, 0, 10, 10}); got[0] != (box{}) || got[1] != (box{}) {
+		t.Errorf("squarify(zero weights) = %+v", got)
+	}
+	got := squarify([]float64{5, 0}, box{1, 2, 10, 4})
+	if got[0] != (box{1, 2, 10, 4}) || got[1].w != 0 || got[1].h != 0 {
+		t.Errorf("squarify() = %+v, want the whole box for the only weight", got)
+	}
+}
@@ -0,0 +1,163 @@
+package treemap
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+	"github.com/stacktower-io/stacktower/pkg/security"
+)
+
+const (
+	fontSize = 11.0
+
+	// Risk colours match the nodelink diagram and the sunburst.
+	vulnFill      = "#c2410c"
+	brittleStroke = "#b91c1c"
+
+	// lightenPerLevel is how much each level below a direct dependency
+	// fades its colour toward white, up to maxLighten.
+	lightenPerLevel = 0.18
+	maxLighten      = 0.7
+)
+
+// RenderSVG computes the treemap for g with [Build] and draws it as SVG.
+// Each rectangle carries a tooltip with the package's weight and its share
+// of the map; vulnerable packages are filled dark orange and brittle ones
+// outlined red, as in the other visualizations. Groups are labelled in
+// their header strip, leaves in their center where the name fits.
+func RenderSVG(g *dag.DAG, opts Options) ([]byte, error) {
+	m, err := Build(g, opts)
+	if err != nil {
+		return nil, err
+	}
+	palette := styles.Palette(opts.Palette)
+	if len(palette) == 0 {
+		palette = styles.PalettePastel
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %.0f %.0f" width="%.0f" height="%.0f">`+"\n",
+		m.Width, m.Height, m.Width, m.Height)
+	fmt.Fprintf(&buf, `  <style>text { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; font-size: %.0fpx; pointer-events: none; } .cell:hover > rect { opacity: 0.8; }</style>`+"\n", fontSize)
+	for _, r := range m.Rects {
+		if r.W < 1 || r.H < 1 {
+			continue
+		}
+		n, _ := g.Node(r.ID)
+		fill, stroke, strokeW := rectColors(r, n, palette)
+
+		fmt.Fprintf(&buf, `  <g class="cell" data-id="%s">`+"\n", styles.EscapeXML(r.ID))
+		fmt.Fprintf(&buf, "    <title>%s</title>\n", styles.EscapeXML(tooltip(r, n, m)))
+		fmt.Fprintf(&buf, `    <rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s" stroke="%s" stroke-width="%s"/>`+"\n",
+			r.X, r.Y, r.W, r.H, fill, stroke, strokeW)
+		writeLabel(&buf, r, styles.ContrastText(fill))
+		buf.WriteString("  </g>\n")
+	}
+	buf.WriteString("</svg>\n")
+	return buf.Bytes(), nil
+}
+
+// rectColors returns the fill, stroke and stroke width of a rectangle.
+func rectColors(r Rect, n *dag.Node, palette styles.Palette) (fill, stroke, width string) {
+	fill, stroke, width = "#f6f8fa", "#ffffff", "1"
+	if r.Branch >= 0 {
+		fill = styles.Lighten(palette.Color(r.Branch), min(float64(r.Depth-1)*lightenPerLevel, maxLighten))
+	}
+	if n == nil {
+		return fill, stroke, width
+	}
+	if sev, _ := n.Meta[security.MetaVulnSeverity].(string); sev != "" {
+		fill = vulnFill
+	}
+	if feature.IsBrittle(n) {
+		stroke, width = brittleStroke, "2.5"
+	}
+	return fill, stroke, width
+}
+
+// tooltip describes a rectangle: its package, its weight and share of the
+// map, the other packages that depend on it, and its risk signals.
+func tooltip(r Rect, n *dag.Node, m Map) string {
+	lines := []string{r.ID}
+	switch m.Weight {
+	case WeightDeps:
+		if below := int(r.Weight) - 1; below > 0 {
+			lines = append(lines, fmt.Sprintf("%d packages below", below))
+		}
+	case WeightDownloads:
+		lines = append(lines, fmt.Sprintf("%s downloads (%s with dependencies)", formatCount(r.Own), formatCount(r.Weight)))
+	case WeightSize:
+		lines = append(lines, fmt.Sprintf("%s installed (%s with dependencies)", formatBytes(r.Own), formatBytes(r.Weight)))
+	}
+	if m.Total > 0 {
+		lines = append(lines, fmt.Sprintf("%.1f%% of the map", 100*r.Weight/m.Total))
+	}
+	if r.Parents > 1 {
+		lines = append(lines, fmt.Sprintf("also required by %d other packages", r.Parents-1))
+	}
+	if n != nil {
+		if score, ok := feature.HealthScore(n); ok {
+			lines = append(lines, fmt.Sprintf("health: %d/100 (%s)", score, feature.HealthLevel(score)))
+		}
+		if sev, _ := n.Meta[security.MetaVulnSeverity].(string); sev != "" {
+			lines = append(lines, "vulnerability: "+sev)
+		}
+		if feature.IsBrittle(n) {
+			lines = append(lines, "brittle: may be unmaintained")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeLabel draws the package name in a group's header strip or a leaf's
+// center, and leaves it to the tooltip when it does not fit.
+func writeLabel(buf *bytes.Buffer, r Rect, color string) {
+	width := styles.TextWidth(r.ID, fontSize)
+	if r.Group {
+		if width <= r.W-2*inset && headerHeight <= r.H {
+			fmt.Fprintf(buf, `    <text x="%.2f" y="%.2f" dominant-baseline="central" fill="%s">%s</text>`+"\n",
+				r.X+inset+1, r.Y+headerHeight/2, color, styles.EscapeXML(r.ID))
+		}
+		return
+	}
+	var rotate string
+	switch {
+	case width <= r.W-4 && r.H >= fontSize+2:
+	case width <= r.H-4 && r.W >= fontSize+2:
+		rotate = fmt.Sprintf(` transform="rotate(-90 %.2f %.2f)"`, r.X+r.W/2, r.Y+r.H/2)
+	default:
+		return
+	}
+	fmt.Fprintf(buf, `    <text x="%.2f" y="%.2f" text-anchor="middle" dominant-baseline="central"%s fill="%s">%s</text>`+"\n",
+		r.X+r.W/2, r.Y+r.H/2, rotate, color, styles.EscapeXML(r.ID))
+}
+
+// formatCount abbreviates a count: 950, 12.3k, 4.5M, 1.2B.
+func formatCount(v float64) string {
+	switch {
+	case v >= 1e9:
+		return fmt.Sprintf("%.1fB", v/1e9)
+	case v >= 1e6:
+		return fmt.Sprintf("%.1fM", v/1e6)
+	case v >= 1e3:
+		return fmt.Sprintf("%.1fk", v/1e3)
+	}
+	return fmt.Sprintf("%.0f", v)
+}
+
+// formatBytes abbreviates a byte size: 512 B, 3.4 KB, 12.0 MB, 1.1 GB.
+func formatBytes(v float64) string {
+	switch {
+	case v >= 1<<30:
+		return fmt.Sprintf("%.1f GB", v/(1<<30))
+	case v >= 1<<20:
+		return fmt.Sprintf("%.1f MB", v/(1<<20))
+	case v >= 1<<10:
+		return fmt.Sprintf("%.1f KB", v/(1<<10))
+	}
+	return fmt.Sprintf("%.0f B", v)
+}
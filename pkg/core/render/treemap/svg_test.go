@@ -0,0 +1,60 @@
+package treemap
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestRenderSVG(t *testing.T) {
+	g := weightedGraph()
+	n, _ := g.Node("http")
+	n.Meta["vuln_severity"] = "high"
+
+	svg, err := RenderSVG(g, Options{Width: 600, Height: 400})
+	if err != nil {
+		t.Fatalf("RenderSVG() error: %v", err)
+	}
+	if err := xml.Unmarshal(svg, new(struct{})); err != nil {
+		t.Fatalf("RenderSVG() produced invalid XML: %v", err)
+	}
+	out := string(svg)
+	for _, want := range []string{
+		`viewBox="0 0 600 400"`,
+		`data-id="tls"`,
+		`fill="#c2410c"`,
+		"also required by 1 other packages",
+		"3 packages below",
+		">web</text>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderSVG() missing %s", want)
+		}
+	}
+	if got := strings.Count(out, `class="cell"`); got != 7 {
+		t.Errorf("RenderSVG() drew %d cells, want 7", got)
+	}
+
+	bare := dag.New(nil)
+	bare.AddNode(dag.Node{ID: "web"})
+	if _, err := RenderSVG(bare, Options{Weight: WeightSize}); err == nil {
+		t.Error("RenderSVG() accepted size weight on a graph without sizes")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	counts := map[float64]string{950: "950", 12_300: "12.3k", 4_500_000: "4.5M", 1.2e9: "1.2B"}
+	for v, want := range counts {
+		if got := formatCount(v); got != want {
+			t.Errorf("formatCount(%v) = %q, want %q", v, got, want)
+		}
+	}
+	sizes := map[float64]string{512: "512 B", 3482: "3.4 KB", 12 << 20: "12.0 MB", 1.1 * (1 << 30): "1.1 GB"}
+	for v, want := range sizes {
+		if got := formatBytes(v); got != want {
+			t.Errorf("formatBytes(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
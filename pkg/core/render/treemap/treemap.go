@@ -0,0 +1,272 @@
+package treemap
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+)
+
+// Weights select what a rectangle's area encodes.
+const (
+	// WeightDeps sizes each package by how many packages sit below it,
+	// itself included. Every graph supports it.
+	WeightDeps = "deps"
+
+	// WeightDownloads sizes packages by their "downloads" metadata, as
+	// reported by the registry during enrichment.
+	WeightDownloads = "downloads"
+
+	// WeightSize sizes packages by their unpacked install size
+	// ([deps.MetaInstallSize]).
+	WeightSize = "size"
+)
+
+// ValidWeights lists the supported values of [Options.Weight].
+var ValidWeights = map[string]bool{
+	WeightDeps:      true,
+	WeightDownloads: true,
+	WeightSize:      true,
+}
+
+// weightKeys maps metadata-backed weights to the node metadata they read.
+var weightKeys = map[string]string{
+	WeightDownloads: "downloads",
+	WeightSize:      deps.MetaInstallSize,
+}
+
+const (
+	defaultWidth  = 1200.0
+	defaultHeight = 800.0
+
+	// headerHeight is the strip at the top of a group that holds its label;
+	// its children are laid out below it.
+	headerHeight = 16.0
+
+	// inset separates nested rectangles from their group's border.
+	inset = 2.0
+)
+
+// Options configures the treemap layout and rendering.
+type Options struct {
+	// Width and Height are the size of the chart in pixels (default 1200x800).
+	Width  float64
+	Height float64
+
+	// Weight selects what area encodes: [WeightDeps] (default),
+	// [WeightDownloads] or [WeightSize].
+	Weight string
+
+	// MaxDepth limits how many levels are nested below the root; deeper
+	// packages still count toward the area of their ancestors. 0 nests
+	// every level that fits.
+	MaxDepth int
+
+	// Palette colours the rectangles: each direct dependency takes the next
+	// colour and its subtree lighter shades of it. Nil uses
+	// [styles.PalettePastel].
+	Palette []string
+}
+
+// Rect is one package placed on the map. X, Y, W and H are in pixels;
+// Depth is the nesting level, with the root at 0.
+type Rect struct {
+	ID     string  `json:"id"`
+	Parent string  `json:"parent,omitempty"`
+	Depth  int     `json:"depth"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	W      float64 `json:"width"`
+	H      float64 `json:"height"`
+
+	// Own is the package's own weight; Weight adds everything placed under
+	// it and determines the area.
+	Own    float64 `json:"own"`
+	Weight float64 `json:"weight"`
+
+	// Parents counts the packages that depend on this one. A package with
+	// several parents is drawn once, under the first parent reached from
+	// the root.
+	Parents int `json:"parents"`
+
+	// Branch is the index of the direct dependency the rectangle sits
+	// under, or -1 for the root.
+	Branch int `json:"branch"`
+
+	// Group reports whether the package's dependencies are nested inside
+	// it, below its header strip.
+	Group bool `json:"group,omitempty"`
+}
+
+// Map is a computed treemap: the rectangles in drawing order, outermost
+// first.
+type Map struct {
+	// Root is the package filling the map, or "" when the graph has several
+	// roots and the map stands for all of them.
+	Root   string  `json:"root"`
+	Weight string  `json:"weight"`
+	Rects  []Rect  `json:"rects"`
+	Total  float64 `json:"total"` // weight of the whole map
+
+	// Width and Height are the size of the map in pixels.
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Build lays the dependency graph out as a squarified treemap. The graph
+// is reduced to a spanning tree by breadth-first search from the root, so
+// every package appears once, at its shortest distance from the root; a
+// package's rectangle holds the rectangles of the packages below it, and
+// its area is proportional to its own weight plus theirs. Subdividers and
+// other synthetic nodes are walked through and never drawn.
+//
+// Returns an error for an unknown weight, or when no package carries the
+// metadata the weight reads (for example, downloads on an unenriched graph).
+func Build(g *dag.DAG, opts Options) (Map, error) {
+	weight := cmp.Or(opts.Weight, WeightDeps)
+	if !ValidWeights[weight] {
+		return Map{}, fmt.Errorf("invalid treemap weight: %q (must be one of: deps, downloads, size)", weight)
+	}
+	width, height := cmp.Or(opts.Width, defaultWidth), cmp.Or(opts.Height, defaultHeight)
+	root, children, parentOf := spanningTree(g)
+	if root == "" && len(children[""]) == 0 {
+		return Map{}, fmt.Errorf("treemap: graph has no root package")
+	}
+
+	own := make(map[string]float64, len(parentOf)+1)
+	total := make(map[string]float64, len(parentOf)+1)
+	var sum func(string) float64
+	sum = func(id string) float64 {
+		if id != "" {
+			own[id] = ownWeight(g, id, weight)
+		}
+		s := own[id]
+		for _, c := range children[id] {
+			s += sum(c)
+		}
+		total[id] = s
+		return s
+	}
+	if sum(root) <= 0 {
+		return Map{}, fmt.Errorf("treemap: no package has %q metadata (enrich the graph first)", weightKeys[weight])
+	}
+	for id := range children {
+		slices.SortFunc(children[id], func(a, b string) int {
+			return cmp.Or(cmp.Compare(total[b], total[a]), cmp.Compare(a, b))
+		})
+	}
+
+	m := Map{Root: root, Weight: weight, Total: total[root], Width: width, Height: height}
+	depth := map[string]int{root: 0}
+	var place func(id string, b box, branch int)
+	place = func(id string, b box, branch int) {
+		d := depth[id]
+		r := Rect{
+			ID: id, Parent: parentOf[id], Depth: d,
+			X: b.x, Y: b.y, W: b.w, H: b.h,
+			Own: own[id], Weight: total[id],
+			Parents: len(g.RealParents(id)), Branch: branch,
+		}
+		inner := box{b.x + inset, b.y + headerHeight, b.w - 2*inset, b.h - headerHeight - inset}
+		if id == "" {
+			// The center of a multi-root graph is not drawn; its roots
+			// share the whole map.
+			inner = b
+		} else {
+			r.Group = len(children[id]) > 0 && (opts.MaxDepth <= 0 || d < opts.MaxDepth) &&
+				inner.w >= 2*headerHeight && inner.h >= headerHeight
+			m.Rects = append(m.Rects, r)
+			if !r.Group {
+				return
+			}
+		}
+
+		// The package's own weight takes its share of the inner area last,
+		// as blank space after its dependencies.
+		weights := make([]float64, 0, len(children[id])+1)
+		for _, c := range children[id] {
+			weights = append(weights, total[c])
+		}
+		weights = append(weights, own[id])
+		boxes := squarify(weights, inner)
+		for i, c := range children[id] {
+			if total[c] <= 0 {
+				continue
+			}
+			depth[c] = d + 1
+			cb := branch
+			if d == 0 {
+				cb = i
+			}
+			place(c, boxes[i], cb)
+		}
+	}
+	place(root, box{0, 0, width, height}, -1)
+	return m, nil
+}
+
+// ownWeight returns the weight a package contributes by itself.
+func ownWeight(g *dag.DAG, id, weight string) float64 {
+	if weight == WeightDeps {
+		return 1
+	}
+	n, ok := g.Node(id)
+	if !ok {
+		return 0
+	}
+	switch v := n.Meta[weightKeys[weight]].(type) {
+	case int64:
+		return float64(v)
+	default:
+		return float64(feature.AsInt(v))
+	}
+}
+
+// spanningTree reduces g to a breadth-first spanning tree from its root.
+// It returns the root, each package's children and each package's parent.
+// When the graph has several roots, the root is "" and its children are
+// the real roots.
+func spanningTree(g *dag.DAG) (string, map[string][]string, map[string]string) {
+	var roots []string
+	for _, n := range g.Nodes() {
+		if !n.IsSynthetic() && g.InDegree(n.ID) == 0 {
+			roots = append(roots, n.ID)
+		}
+	}
+	slices.Sort(roots)
+
+	root := ""
+	if len(roots) == 1 {
+		root, roots = roots[0], nil
+	}
+	children := make(map[string][]string)
+	parentOf := make(map[string]string)
+	seen := map[string]bool{root: true}
+	queue := []string{root}
+	if len(roots) > 0 {
+		queue = queue[:0]
+		for _, r := range roots {
+			seen[r] = true
+			parentOf[r] = root
+			children[root] = append(children[root], r)
+			queue = append(queue, r)
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, c := range g.RealChildren(id) {
+			if seen[c] {
+				continue
+			}
+			seen[c] = true
+			parentOf[c] = id
+			children[id] = append(children[id], c)
+			queue = append(queue, c)
+		}
+	}
+	return root, children, parentOf
+}
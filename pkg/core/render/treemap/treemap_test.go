@@ -0,0 +1,190 @@
+package treemap
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+)
+
+// weightedGraph is an enriched project: http is shared by api and ui, and
+// the packages carry downloads and install sizes, some of them zero (tls
+// downloads, ui size) or missing (web, docs).
+//
+//	web ─┬─ api ─┬─ json
+//	     │       └─ http ── tls
+//	     ├─ ui ──── http
+//	     └─ docs
+func weightedGraph() *dag.DAG {
+	g := dag.New(nil)
+	for id, meta := range map[string]dag.Metadata{
+		"web":  nil,
+		"api":  {"downloads": 500, deps.MetaInstallSize: int64(20_000)},
+		"ui":   {"downloads": 1500.0, deps.MetaInstallSize: int64(0)},
+		"docs": nil,
+		"json": {"downloads": 2000, deps.MetaInstallSize: int64(8_000)},
+		"http": {"downloads": 4000, deps.MetaInstallSize: int64(30_000)},
+		"tls":  {"downloads": 0, deps.MetaInstallSize: int64(12_000)},
+	} {
+		g.AddNode(dag.Node{ID: id, Meta: meta})
+	}
+	for _, e := range [][2]string{
+		{"web", "api"}, {"web", "ui"}, {"web", "docs"},
+		{"api", "json"}, {"api", "http"}, {"ui", "http"}, {"http", "tls"},
+	} {
+		g.AddEdge(dag.Edge{From: e[0], To: e[1]})
+	}
+	return g
+}
+
+func rectsByID(m Map) map[string]Rect {
+	out := make(map[string]Rect, len(m.Rects))
+	for _, r := range m.Rects {
+		out[r.ID] = r
+	}
+	return out
+}
+
+func area(r Rect) float64 { return r.W * r.H }
+
+func TestBuild(t *testing.T) {
+	m, err := Build(weightedGraph(), Options{Width: 800, Height: 600})
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	if m.Root != "web" || m.Total != 7 || m.Weight != WeightDeps || len(m.Rects) != 7 {
+		t.Fatalf("Build() = root %q, total %v, weight %q, %d rects", m.Root, m.Total, m.Weight, len(m.Rects))
+	}
+	rects := rectsByID(m)
+
+	// http is drawn once, under the first parent reached.
+	if got := rects["http"]; got.Parent != "api" || got.Parents != 2 || got.Depth != 2 {
+		t.Errorf("http = %+v, want under api with 2 parents at depth 2", got)
+	}
+	// api carries json, http and tls, so it gets four times ui's area.
+	api, ui := rects["api"], rects["ui"]
+	if api.Weight != 4 || ui.Weight != 1 || math.Abs(area(api)/area(ui)-4) > 1e-6 {
+		t.Errorf("api = %+v, ui = %+v", api, ui)
+	}
+	// Siblings are ordered by weight, then by name.
+	if api.Branch != 0 || rects["docs"].Branch != 1 || ui.Branch != 2 || rects["tls"].Branch != 0 || rects["web"].Branch != -1 {
+		t.Error("Build() assigned wrong branches")
+	}
+	if !rects["web"].Group || !api.Group || !rects["http"].Group || ui.Group {
+		t.Error("Build() grouped the wrong packages")
+	}
+	// Children nest inside their parent, below its header.
+	for _, r := range m.Rects {
+		if r.Parent == "" {
+			continue
+		}
+		p := rects[r.Parent]
+		if r.X < p.X || r.Y < p.Y+headerHeight || r.X+r.W > p.X+p.W+1e-9 || r.Y+r.H > p.Y+p.H+1e-9 {
+			t.Errorf("%s %+v not inside %s %+v", r.ID, r, p.ID, p)
+		}
+	}
+}
+
+func TestBuild_MaxDepth(t *testing.T) {
+	m, err := Build(weightedGraph(), Options{MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	if len(m.Rects) != 4 || rectsByID(m)["api"].Group {
+		t.Errorf("Build(MaxDepth: 1) = %+v, want web, api, ui and docs only", m.Rects)
+	}
+}
+
+func TestBuild_Weights(t *testing.T) {
+	g := weightedGraph()
+
+	m, err := Build(g, Options{Weight: WeightDownloads})
+	if err != nil {
+		t.Fatalf("Build(downloads) error: %v", err)
+	}
+	rects := rectsByID(m)
+	// The float download count of ui counts like an integer one.
+	api, ui := rects["api"], rects["ui"]
+	if m.Total != 8000 || api.Own != 500 || api.Weight != 6500 || ui.Weight != 1500 {
+		t.Errorf("Build(downloads) = total %v, api %+v, ui %+v", m.Total, api, ui)
+	}
+	if math.Abs(area(api)/area(ui)-6500.0/1500) > 1e-6 {
+		t.Errorf("Build(downloads) areas api %v, ui %v, want ratio 6500:1500", area(api), area(ui))
+	}
+	for _, id := range []string{"tls", "docs"} {
+		if _, ok := rects[id]; ok {
+			t.Errorf("Build(downloads) drew %s, which has no downloads", id)
+		}
+	}
+	if len(m.Rects) != 5 {
+		t.Errorf("Build(downloads) drew %d rects, want 5", len(m.Rects))
+	}
+
+	m, err = Build(g, Options{Weight: WeightSize})
+	if err != nil {
+		t.Fatalf("Build(size) error: %v", err)
+	}
+	rects = rectsByID(m)
+	if m.Total != 70_000 || rects["api"].Own != 20_000 || rects["api"].Weight != 70_000 || rects["http"].Weight != 42_000 {
+		t.Errorf("Build(size) = total %v, api %+v, http %+v", m.Total, rects["api"], rects["http"])
+	}
+	if _, ok := rects["ui"]; ok {
+		t.Error("Build(size) drew ui, whose install size is zero")
+	}
+	if _, ok := rects["tls"]; !ok {
+		t.Error("Build(size) left out tls, which has a size but no downloads")
+	}
+
+	bare := dag.New(nil)
+	bare.AddNode(dag.Node{ID: "web"})
+	if _, err := Build(bare, Options{Weight: WeightDownloads}); err == nil {
+		t.Error("Build() accepted downloads weight on a graph without downloads")
+	}
+	if _, err := Build(g, Options{Weight: "stars"}); err == nil {
+		t.Error("Build() accepted an unknown weight")
+	}
+}
+
+func TestBuild_MultipleRoots(t *testing.T) {
+	g := dag.New(nil)
+	for _, id := range []string{"x", "y", "z"} {
+		g.AddNode(dag.Node{ID: id})
+	}
+	g.AddEdge(dag.Edge{From: "x", To: "z"})
+	g.AddEdge(dag.Edge{From: "y", To: "z"})
+
+	m, err := Build(g, Options{Width: 300, Height: 100})
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	rects := rectsByID(m)
+	if m.Root != "" || m.Total != 3 || len(m.Rects) != 3 {
+		t.Fatalf("Build() = %+v", m)
+	}
+	if x, y := rects["x"], rects["y"]; x.Depth != 1 || y.Depth != 1 || x.Branch != 0 || y.Branch != 1 {
+		t.Errorf("roots = %+v, %+v", x, y)
+	}
+	if _, err := Build(dag.New(nil), Options{}); err == nil {
+		t.Error("Build() accepted an empty graph")
+	}
+}
+
+func TestBuild_SkipsSubdividers(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app"})
+	g.AddNode(dag.Node{ID: "lib"})
+	g.AddNode(dag.Node{ID: "lib_sub_1", Kind: dag.NodeKindSubdivider, MasterID: "lib"})
+	g.AddNode(dag.Node{ID: "deep"})
+	g.AddEdge(dag.Edge{From: "app", To: "lib"})
+	g.AddEdge(dag.Edge{From: "app", To: "lib_sub_1"})
+	g.AddEdge(dag.Edge{From: "lib_sub_1", To: "deep"})
+
+	m, err := Build(g, Options{})
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	if got := rectsByID(m); len(got) != 3 || got["deep"].Parent != "app" {
+		t.Errorf("Build() = %+v, want subdivider walked through", m.Rects)
+	}
+}
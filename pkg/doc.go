@@ -29,7 +29,12 @@
 //
 // # Quick Start
 //
-// Resolve dependencies and render a tower visualization:
+// The [stacktower] package wraps the whole pipeline in one call per step:
+//
+//	g, _ := stacktower.Resolve(ctx, "python", "fastapi")
+//	svg, _ := stacktower.Render(ctx, g)
+//
+// The same steps with the underlying packages:
 //
 //	import (
 //	    "context"
@@ -109,6 +114,18 @@
 // [pipeline] - Complete visualization pipeline (parse → layout → render) used
 // by CLI and API. Ensures consistent behavior across all entry points.
 //
+// [stacktower] - High-level Resolve, Parse and Render functions with
+// functional options, for embedding Stacktower in other programs.
+//
+// [server] - HTTP API running the pipeline as asynchronous jobs, served by
+// "stacktower serve".
+//
+// [monitor] - Change detection between resolutions of a project (new
+// vulnerabilities, brittle packages, crossings) and webhook notification.
+//
+// [store] - Persistent storage of graphs, layouts and artifacts by job or
+// project ID, on the filesystem, S3 or SQLite.
+//
 // # Common Workflows
 //
 // Parse a manifest file:
@@ -165,6 +182,10 @@
 // [render/nodelink]: https://pkg.go.dev/github.com/stacktower-io/stacktower/pkg/core/render/nodelink
 // [graph]: https://pkg.go.dev/github.com/stacktower-io/stacktower/pkg/graph
 // [pipeline]: https://pkg.go.dev/github.com/stacktower-io/stacktower/pkg/pipeline
+// [stacktower]: https://pkg.go.dev/github.com/stacktower-io/stacktower/pkg/stacktower
+// [server]: https://pkg.go.dev/github.com/stacktower-io/stacktower/pkg/server
+// [monitor]: https://pkg.go.dev/github.com/stacktower-io/stacktower/pkg/monitor
+// [store]: https://pkg.go.dev/github.com/stacktower-io/stacktower/pkg/store
 // [cache]: https://pkg.go.dev/github.com/stacktower-io/stacktower/pkg/cache
 // [security]: https://pkg.go.dev/github.com/stacktower-io/stacktower/pkg/security
 //
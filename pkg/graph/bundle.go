@@ -0,0 +1,337 @@
+package graph
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+// =============================================================================
+// Bundle - Several Graphs in One File
+// =============================================================================
+
+// Bundle keeps several named graphs together with their layouts and the
+// settings they were rendered with, so batch runs and servers can store and
+// ship related artifacts as one file.
+//
+// A bundle is written either as a single JSON document ([WriteBundle]):
+//
+//	{
+//	  "schema_version": 1,
+//	  "graphs": [
+//	    {"name": "api", "graph": {...}, "layout": {...}, "settings": {...}}
+//	  ]
+//	}
+//
+// or as a zip archive ([WriteBundleZip]) holding a manifest.json that lists
+// the entries in order, and api/graph.json, api/layout.json and
+// api/settings.json for each of them. [ReadBundle] reads both.
+type Bundle struct {
+	Entries []BundleEntry
+}
+
+// BundleEntry is one graph of a [Bundle].
+type BundleEntry struct {
+	// Name identifies the entry, unique within the bundle. It names the
+	// entry's directory in zip archives, so it must not contain slashes.
+	Name string
+
+	// Graph is the dependency graph (required).
+	Graph *dag.DAG
+
+	// Layout is the computed layout of Graph, if any.
+	Layout *Layout
+
+	// Settings holds the render settings as a JSON object, such as
+	// encoded pipeline options, if any. The bundle does not interpret it.
+	Settings json.RawMessage
+}
+
+// Entry returns the entry with the given name.
+func (b *Bundle) Entry(name string) (*BundleEntry, bool) {
+	for i := range b.Entries {
+		if b.Entries[i].Name == name {
+			return &b.Entries[i], true
+		}
+	}
+	return nil, false
+}
+
+// Add appends an entry for g under name and returns it, so its layout and
+// settings can be filled in. Names are checked when the bundle is written.
+func (b *Bundle) Add(name string, g *dag.DAG) *BundleEntry {
+	b.Entries = append(b.Entries, BundleEntry{Name: name, Graph: g})
+	return &b.Entries[len(b.Entries)-1]
+}
+
+// bundleDoc is the JSON form of a bundle. In zip archives, the graph,
+// layout and settings fields are left out and stored as files instead.
+type bundleDoc struct {
+	SchemaVersion int              `json:"schema_version,omitempty"`
+	Graphs        []bundleEntryDoc `json:"graphs"`
+}
+
+type bundleEntryDoc struct {
+	Name     string          `json:"name"`
+	Graph    json.RawMessage `json:"graph,omitempty"`
+	Layout   json.RawMessage `json:"layout,omitempty"`
+	Settings json.RawMessage `json:"settings,omitempty"`
+}
+
+// Files of an entry in a zip archive, under the entry's directory.
+const (
+	bundleManifest     = "manifest.json"
+	bundleGraphFile    = "graph.json"
+	bundleLayoutFile   = "layout.json"
+	bundleSettingsFile = "settings.json"
+)
+
+// zipMagic starts every zip archive.
+var zipMagic = []byte("PK\x03\x04")
+
+// WriteBundle writes b as a single JSON document. It fails if an entry has
+// no graph, or a name that is empty, repeated or contains a slash.
+func WriteBundle(b *Bundle, w io.Writer) error {
+	doc, err := encodeBundle(b)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal bundle: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// WriteBundleZip writes b as a zip archive, with the same checks as
+// [WriteBundle]. Each graph, layout and settings document is its own file,
+// so tools can extract one without reading the rest.
+func WriteBundleZip(b *Bundle, w io.Writer) error {
+	doc, err := encodeBundle(b)
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(w)
+	add := func(name string, data []byte) error {
+		if len(data) == 0 {
+			return nil
+		}
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	}
+
+	manifest := bundleDoc{SchemaVersion: doc.SchemaVersion, Graphs: make([]bundleEntryDoc, len(doc.Graphs))}
+	for i, e := range doc.Graphs {
+		manifest.Graphs[i] = bundleEntryDoc{Name: e.Name}
+		for _, file := range []struct {
+			name string
+			data []byte
+		}{
+			{bundleGraphFile, e.Graph},
+			{bundleLayoutFile, e.Layout},
+			{bundleSettingsFile, e.Settings},
+		} {
+			if err := add(e.Name+"/"+file.name, file.data); err != nil {
+				return fmt.Errorf("write bundle entry %s: %w", e.Name, err)
+			}
+		}
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal bundle manifest: %w", err)
+	}
+	if err := add(bundleManifest, data); err != nil {
+		return fmt.Errorf("write bundle manifest: %w", err)
+	}
+	return zw.Close()
+}
+
+// encodeBundle checks b and encodes each entry.
+func encodeBundle(b *Bundle) (bundleDoc, error) {
+	doc := bundleDoc{SchemaVersion: SchemaVersion, Graphs: make([]bundleEntryDoc, len(b.Entries))}
+	seen := make(map[string]bool, len(b.Entries))
+	for i, e := range b.Entries {
+		if err := checkBundleName(e.Name); err != nil {
+			return bundleDoc{}, err
+		}
+		if seen[e.Name] {
+			return bundleDoc{}, fmt.Errorf("bundle entry %q appears twice", e.Name)
+		}
+		seen[e.Name] = true
+		if e.Graph == nil {
+			return bundleDoc{}, fmt.Errorf("bundle entry %q has no graph", e.Name)
+		}
+
+		var err error
+		out := bundleEntryDoc{Name: e.Name, Settings: e.Settings}
+		if out.Graph, err = MarshalGraph(e.Graph); err != nil {
+			return bundleDoc{}, fmt.Errorf("bundle entry %s: %w", e.Name, err)
+		}
+		if e.Layout != nil {
+			if out.Layout, err = MarshalLayout(*e.Layout); err != nil {
+				return bundleDoc{}, fmt.Errorf("bundle entry %s: marshal layout: %w", e.Name, err)
+			}
+		}
+		if len(out.Settings) > 0 && !json.Valid(out.Settings) {
+			return bundleDoc{}, fmt.Errorf("bundle entry %s: settings are not valid JSON", e.Name)
+		}
+		doc.Graphs[i] = out
+	}
+	return doc, nil
+}
+
+// checkBundleName reports names that cannot be used as a zip directory.
+func checkBundleName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid bundle entry name %q", name)
+	}
+	return nil
+}
+
+// ReadBundle reads a bundle written by [WriteBundle] or [WriteBundleZip],
+// telling them apart by content. JSON bundles may be gzip- or
+// zstd-compressed.
+func ReadBundle(r io.Reader) (*Bundle, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read bundle: %w", err)
+	}
+	if bytes.HasPrefix(data, zipMagic) {
+		return readBundleZip(data)
+	}
+
+	dr, err := Decompress(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	var doc bundleDoc
+	if err := json.NewDecoder(dr).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode bundle: %w", err)
+	}
+	return decodeBundle(doc)
+}
+
+// readBundleZip reads a bundle from the bytes of a zip archive.
+func readBundleZip(data []byte) (*Bundle, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open bundle archive: %w", err)
+	}
+	read := func(name string) ([]byte, error) {
+		f, err := zr.Open(name)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	}
+
+	manifest, err := read(bundleManifest)
+	if err != nil {
+		return nil, fmt.Errorf("read bundle manifest: %w", err)
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("bundle archive has no %s", bundleManifest)
+	}
+	var doc bundleDoc
+	if err := json.Unmarshal(manifest, &doc); err != nil {
+		return nil, fmt.Errorf("decode bundle manifest: %w", err)
+	}
+	for i := range doc.Graphs {
+		e := &doc.Graphs[i]
+		if err := checkBundleName(e.Name); err != nil {
+			return nil, err
+		}
+		for _, file := range []struct {
+			name string
+			dst  *json.RawMessage
+		}{
+			{bundleGraphFile, &e.Graph},
+			{bundleLayoutFile, &e.Layout},
+			{bundleSettingsFile, &e.Settings},
+		} {
+			if *file.dst, err = read(e.Name + "/" + file.name); err != nil {
+				return nil, fmt.Errorf("read bundle entry %s: %w", e.Name, err)
+			}
+		}
+	}
+	return decodeBundle(doc)
+}
+
+// decodeBundle converts a bundle document with every entry filled in.
+func decodeBundle(doc bundleDoc) (*Bundle, error) {
+	if err := checkSchemaVersion(doc.SchemaVersion); err != nil {
+		return nil, err
+	}
+	b := &Bundle{Entries: make([]BundleEntry, len(doc.Graphs))}
+	seen := make(map[string]bool, len(doc.Graphs))
+	for i, e := range doc.Graphs {
+		if seen[e.Name] {
+			return nil, fmt.Errorf("bundle entry %q appears twice", e.Name)
+		}
+		seen[e.Name] = true
+		if len(e.Graph) == 0 {
+			return nil, fmt.Errorf("bundle entry %q has no graph", e.Name)
+		}
+		g, err := ReadGraph(bytes.NewReader(e.Graph))
+		if err != nil {
+			return nil, fmt.Errorf("bundle entry %s: %w", e.Name, err)
+		}
+		entry := BundleEntry{Name: e.Name, Graph: g, Settings: e.Settings}
+		if len(e.Layout) > 0 {
+			l, err := UnmarshalLayout(e.Layout)
+			if err != nil {
+				return nil, fmt.Errorf("bundle entry %s: %w", e.Name, err)
+			}
+			entry.Layout = &l
+		}
+		b.Entries[i] = entry
+	}
+	return b, nil
+}
+
+// WriteBundleFile writes b to path: as a zip archive when path ends in
+// ".zip", otherwise as JSON, compressed when path ends in ".gz" or ".zst".
+func WriteBundleFile(b *Bundle, path string) error {
+	var buf bytes.Buffer
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		if err := WriteBundleZip(b, &buf); err != nil {
+			return err
+		}
+	} else {
+		w, flush := compressWriter(&buf, path)
+		if err := WriteBundle(b, w); err != nil {
+			return err
+		}
+		if err := flush(); err != nil {
+			return fmt.Errorf("compress %s: %w", path, err)
+		}
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// ReadBundleFile reads a bundle written by [WriteBundleFile].
+func ReadBundleFile(path string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+	return ReadBundle(f)
+}
@@ -0,0 +1,162 @@
+package graph
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func bundleGraph(ids ...string) *dag.DAG {
+	g := dag.New(nil)
+	for _, id := range ids {
+		_ = g.AddNode(dag.Node{ID: id})
+	}
+	for i := 1; i < len(ids); i++ {
+		_ = g.AddEdge(dag.Edge{From: ids[i-1], To: ids[i]})
+	}
+	return g
+}
+
+func testBundle() *Bundle {
+	var b Bundle
+	api := b.Add("api", bundleGraph("api", "http", "net"))
+	api.Layout = &Layout{VizType: VizTypeTower, Width: 800, Height: 600, Blocks: []Block{{ID: "api", Width: 800, Height: 100}}}
+	api.Settings = json.RawMessage(`{"style":"simple","seed":7}`)
+	b.Add("worker", bundleGraph("worker", "queue"))
+	return &b
+}
+
+func checkBundle(t *testing.T, got *Bundle) {
+	t.Helper()
+	if len(got.Entries) != 2 || got.Entries[0].Name != "api" || got.Entries[1].Name != "worker" {
+		t.Fatalf("entries = %+v, want api and worker in order", got.Entries)
+	}
+	api, _ := got.Entry("api")
+	if api.Graph.NodeCount() != 3 || api.Graph.EdgeCount() != 2 {
+		t.Errorf("api graph has %d nodes, %d edges", api.Graph.NodeCount(), api.Graph.EdgeCount())
+	}
+	if api.Layout == nil || len(api.Layout.Blocks) != 1 || api.Layout.Width != 800 {
+		t.Errorf("api layout = %+v", api.Layout)
+	}
+	var settings map[string]any
+	if err := json.Unmarshal(api.Settings, &settings); err != nil || settings["style"] != "simple" {
+		t.Errorf("api settings = %s (%v)", api.Settings, err)
+	}
+	worker, ok := got.Entry("worker")
+	if !ok || worker.Layout != nil || worker.Settings != nil {
+		t.Errorf("worker = %+v, want a graph only", worker)
+	}
+	if _, ok := got.Entry("missing"); ok {
+		t.Error("Entry(missing) found an entry")
+	}
+}
+
+func TestBundle_JSONRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteBundle(testBundle(), &buf); err != nil {
+		t.Fatalf("WriteBundle: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"schema_version": 1`) {
+		t.Errorf("bundle is not versioned:\n%s", buf.String())
+	}
+	got, err := ReadBundle(&buf)
+	if err != nil {
+		t.Fatalf("ReadBundle: %v", err)
+	}
+	checkBundle(t, got)
+}
+
+func TestBundle_ZipRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteBundleZip(testBundle(), &buf); err != nil {
+		t.Fatalf("WriteBundleZip: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	want := "api/graph.json api/layout.json api/settings.json worker/graph.json manifest.json"
+	if strings.Join(names, " ") != want {
+		t.Errorf("archive files = %v, want %s", names, want)
+	}
+
+	got, err := ReadBundle(&buf)
+	if err != nil {
+		t.Fatalf("ReadBundle: %v", err)
+	}
+	checkBundle(t, got)
+}
+
+func TestBundle_Files(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"out.json", "out.json.gz", "out.zip"} {
+		path := filepath.Join(dir, name)
+		if err := WriteBundleFile(testBundle(), path); err != nil {
+			t.Fatalf("WriteBundleFile(%s): %v", name, err)
+		}
+		got, err := ReadBundleFile(path)
+		if err != nil {
+			t.Fatalf("ReadBundleFile(%s): %v", name, err)
+		}
+		checkBundle(t, got)
+	}
+}
+
+func TestWriteBundle_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []BundleEntry
+	}{
+		{"empty name", []BundleEntry{{Graph: bundleGraph("a")}}},
+		{"slash", []BundleEntry{{Name: "a/b", Graph: bundleGraph("a")}}},
+		{"dot dot", []BundleEntry{{Name: "..", Graph: bundleGraph("a")}}},
+		{"duplicate", []BundleEntry{{Name: "a", Graph: bundleGraph("a")}, {Name: "a", Graph: bundleGraph("b")}}},
+		{"no graph", []BundleEntry{{Name: "a"}}},
+		{"bad settings", []BundleEntry{{Name: "a", Graph: bundleGraph("a"), Settings: json.RawMessage(`{`)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := WriteBundle(&Bundle{Entries: tt.entries}, &bytes.Buffer{}); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestReadBundle_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"malformed", `{"graphs": [`},
+		{"newer schema", `{"schema_version": 2, "graphs": []}`},
+		{"missing graph", `{"graphs": [{"name": "a"}]}`},
+		{"duplicate", `{"graphs": [{"name": "a", "graph": {"nodes": []}}, {"name": "a", "graph": {"nodes": []}}]}`},
+		{"bad layout", `{"graphs": [{"name": "a", "graph": {"nodes": []}, "layout": {"viz_type": "tower"}}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadBundle(strings.NewReader(tt.doc)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	_, _ = zw.Create("api/graph.json")
+	_ = zw.Close()
+	if _, err := ReadBundle(&buf); err == nil || !strings.Contains(err.Error(), "manifest.json") {
+		t.Errorf("archive without manifest: err = %v", err)
+	}
+}
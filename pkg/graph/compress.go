@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Magic numbers of the compression formats [Decompress] recognizes.
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Decompress returns a reader of the uncompressed content of r. gzip and
+// zstd input is unwrapped transparently and anything else is returned as
+// is, so callers can pass files whether or not they were compressed.
+func Decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("gzip: %w", err)
+		}
+		return zr, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		// A single-threaded decoder runs synchronously, so the reader needs
+		// no Close to release goroutines.
+		zr, err := zstd.NewReader(br, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, fmt.Errorf("zstd: %w", err)
+		}
+		return zr, nil
+	}
+	return br, nil
+}
+
+// TrimCompressionExt strips a ".gz" or ".zst" suffix from path, giving the
+// name of the uncompressed file.
+func TrimCompressionExt(path string) string {
+	for _, ext := range []string{".gz", ".zst"} {
+		if strings.HasSuffix(path, ext) {
+			return strings.TrimSuffix(path, ext)
+		}
+	}
+	return path
+}
+
+// compressWriter wraps w in a gzip writer when path ends in ".gz" and in a
+// zstd writer when it ends in ".zst". The returned close function flushes
+// the compressed stream; it does not close w.
+func compressWriter(w io.Writer, path string) (io.Writer, func() error) {
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		zw := gzip.NewWriter(w)
+		return zw, zw.Close
+	case strings.HasSuffix(path, ".zst"):
+		// NewWriter only fails on invalid options.
+		zw, _ := zstd.NewWriter(w)
+		return zw, zw.Close
+	}
+	return w, func() error { return nil }
+}
@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestDecompress(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(`{"nodes": []}`))
+	zw.Close()
+
+	zst, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zstdData := zst.EncodeAll([]byte(`{"nodes": []}`), nil)
+
+	tests := []struct {
+		name    string
+		input   []byte
+		want    string
+		wantErr error
+	}{
+		{"Plain", []byte(`{"nodes": []}`), `{"nodes": []}`, nil},
+		{"Gzip", gz.Bytes(), `{"nodes": []}`, nil},
+		{"Short", []byte("{"), "{", nil},
+		{"Empty", nil, "", nil},
+		{"Zstd", zstdData, `{"nodes": []}`, nil},
+		{"BadGzip", []byte{0x1f, 0x8b, 0x00}, "", io.ErrUnexpectedEOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Decompress(bytes.NewReader(tt.input))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decompress: %v", err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteGraphFile_Compressed(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "lib"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "lib"})
+
+	for _, tt := range []struct {
+		ext   string
+		magic []byte
+	}{
+		{".gz", gzipMagic},
+		{".zst", zstdMagic},
+	} {
+		t.Run(tt.ext, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "graph.json"+tt.ext)
+			if err := WriteGraphFile(g, path); err != nil {
+				t.Fatalf("WriteGraphFile: %v", err)
+			}
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.HasPrefix(raw, tt.magic) {
+				t.Fatalf("file does not start with the %s magic: % x", tt.ext, raw[:min(len(raw), 4)])
+			}
+
+			back, err := ReadGraphFile(path)
+			if err != nil {
+				t.Fatalf("ReadGraphFile: %v", err)
+			}
+			if back.NodeCount() != 2 || back.EdgeCount() != 1 {
+				t.Errorf("got %d nodes, %d edges; want 2, 1", back.NodeCount(), back.EdgeCount())
+			}
+		})
+	}
+}
+
+func TestTrimCompressionExt(t *testing.T) {
+	for in, want := range map[string]string{
+		"graph.json":     "graph.json",
+		"graph.json.gz":  "graph.json",
+		"graph.json.zst": "graph.json",
+	} {
+		if got := TrimCompressionExt(in); got != want {
+			t.Errorf("TrimCompressionExt(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+// ImportCSV reads a dependency graph from an edge list, one dependency per
+// row, and an optional node list. It is the quickest way to feed
+// spreadsheets and SQL query results to the renderer.
+//
+// The edge list has the columns from,to followed by any number of edge
+// metadata columns (such as constraint). The node list has an id column
+// followed by node metadata columns; a "label" column sets the display
+// label. Nodes only named in the edge list are created without metadata,
+// and repeated edges are read once. nodes may be nil.
+//
+// Both lists may start with a header row, recognized by its first cells
+// (from,to or source,target for edges; id, name or node for nodes), which
+// names the metadata columns. Without one, extra columns are named col3,
+// col4, and so on. Cells are separated by commas, tabs or semicolons,
+// whichever the first line uses most; lines starting with # are comments.
+//
+// Metadata columns holding only whole numbers are read as int, and those
+// holding only true and false as bool; all others stay strings, so version
+// columns keep values such as "2.0" intact. Empty cells are skipped.
+func ImportCSV(edges, nodes io.Reader) (*dag.DAG, error) {
+	g := dag.New(nil)
+	if nodes != nil {
+		t, err := readCSVTable(nodes, []string{"id", "name", "node"}, 1)
+		if err != nil {
+			return nil, fmt.Errorf("read nodes: %w", err)
+		}
+		for i, row := range t.rows {
+			n := dag.Node{ID: row[0], Meta: dag.Metadata{}}
+			t.meta(row, 1, func(name string, v any) { setNodeAttr(&n, name, v) })
+			if err := g.AddNode(n); err != nil {
+				return nil, fmt.Errorf("nodes line %d: add node %q: %w", t.lines[i], row[0], err)
+			}
+		}
+	}
+
+	t, err := readCSVTable(edges, []string{"from", "source"}, 2)
+	if err != nil {
+		return nil, fmt.Errorf("read edges: %w", err)
+	}
+	seen := make(map[[2]string]bool, len(t.rows))
+	for i, row := range t.rows {
+		from, to := row[0], row[1]
+		if to == "" {
+			return nil, fmt.Errorf("edges line %d: missing target for %q", t.lines[i], from)
+		}
+		if seen[[2]string{from, to}] {
+			continue
+		}
+		seen[[2]string{from, to}] = true
+		for _, id := range []string{from, to} {
+			if _, ok := g.Node(id); !ok {
+				_ = g.AddNode(dag.Node{ID: id})
+			}
+		}
+		e := dag.Edge{From: from, To: to, Meta: dag.Metadata{}}
+		t.meta(row, 2, func(name string, v any) { e.Meta[name] = v })
+		if err := g.AddEdge(e); err != nil {
+			return nil, fmt.Errorf("edges line %d: %w", t.lines[i], err)
+		}
+	}
+	return g, nil
+}
+
+// csvTable is a parsed CSV list: its column names, the data rows with
+// their line numbers, and the inferred type of each column.
+type csvTable struct {
+	header []string
+	rows   [][]string
+	lines  []int
+	types  []string
+}
+
+// readCSVTable parses a CSV list whose first column is required and whose
+// first keys columns identify the row. A first row whose first cell is one
+// of headerNames is taken as the header.
+func readCSVTable(r io.Reader, headerNames []string, keys int) (*csvTable, error) {
+	br := bufio.NewReader(r)
+	first, _ := br.Peek(4096)
+	if i := bytes.IndexByte(first, '\n'); i >= 0 {
+		first = first[:i]
+	}
+	cr := csv.NewReader(br)
+	cr.Comma = sniffDelimiter(first)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	t := &csvTable{}
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		for i := range rec {
+			rec[i] = strings.TrimSpace(rec[i])
+		}
+		if len(rec) == 1 && rec[0] == "" {
+			continue
+		}
+		if t.header == nil && len(t.rows) == 0 && containsFold(headerNames, rec[0]) {
+			t.header = rec
+			continue
+		}
+		if rec[0] == "" {
+			return nil, fmt.Errorf("line %d: empty %s", line, headerNames[0])
+		}
+		for len(rec) < keys {
+			rec = append(rec, "")
+		}
+		t.rows = append(t.rows, rec)
+		t.lines = append(t.lines, line)
+	}
+	t.inferTypes()
+	return t, nil
+}
+
+// sniffDelimiter picks the separator the first line uses most, preferring
+// commas.
+func sniffDelimiter(line []byte) rune {
+	best, count := ',', bytes.Count(line, []byte{','})
+	for _, d := range []rune{'\t', ';'} {
+		if n := bytes.Count(line, []byte(string(d))); n > count {
+			best, count = d, n
+		}
+	}
+	return best
+}
+
+// name returns the name of column i: its header, or col<i+1> without one.
+func (t *csvTable) name(i int) string {
+	if i < len(t.header) && t.header[i] != "" {
+		return t.header[i]
+	}
+	return "col" + strconv.Itoa(i+1)
+}
+
+// inferTypes types each column as long when every non-empty cell is a
+// whole number, boolean when every one is true or false, and string
+// otherwise.
+func (t *csvTable) inferTypes() {
+	for _, row := range t.rows {
+		for len(t.types) < len(row) {
+			t.types = append(t.types, "")
+		}
+		for i, cell := range row {
+			if cell == "" || t.types[i] == attrString {
+				continue
+			}
+			typ := attrString
+			if _, err := strconv.Atoi(cell); err == nil {
+				typ = attrLong
+			} else if cell == "true" || cell == "false" {
+				typ = attrBoolean
+			}
+			if t.types[i] != "" && t.types[i] != typ {
+				typ = attrString
+			}
+			t.types[i] = typ
+		}
+	}
+}
+
+// meta passes the typed, non-empty cells of row from column start on to set.
+func (t *csvTable) meta(row []string, start int, set func(string, any)) {
+	for i := start; i < len(row); i++ {
+		if row[i] == "" {
+			continue
+		}
+		v, err := decodeAttr(t.types[i], false, row[i])
+		if err != nil {
+			v = row[i]
+		}
+		set(t.name(i), v)
+	}
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportCSV(t *testing.T) {
+	edges := `from,to,constraint,optional
+app,web,>=2.0,false
+app,db,,true
+# joins repeat rows
+web,db,^1.4,false
+web,db,^1.4,false
+`
+	nodes := `id,label,version,repo_stars
+app,My App,1.0,
+web,,2.3.1,1200
+`
+	g, err := ImportCSV(strings.NewReader(edges), strings.NewReader(nodes))
+	if err != nil {
+		t.Fatalf("ImportCSV: %v", err)
+	}
+	if g.NodeCount() != 3 || g.EdgeCount() != 3 {
+		t.Fatalf("got %d nodes, %d edges; want 3, 3", g.NodeCount(), g.EdgeCount())
+	}
+	app, _ := g.Node("app")
+	if app.Meta[metaLabel] != "My App" || app.Meta["version"] != "1.0" {
+		t.Errorf("app meta = %v, want label My App and version \"1.0\"", app.Meta)
+	}
+	web, _ := g.Node("web")
+	if web.Meta["repo_stars"] != 1200 {
+		t.Errorf("web repo_stars = %#v, want int 1200", web.Meta["repo_stars"])
+	}
+	if db, _ := g.Node("db"); len(db.Meta) != 0 {
+		t.Errorf("db meta = %v, want none: it is only named in the edge list", db.Meta)
+	}
+	e := g.Edges()[0]
+	if e.Meta["constraint"] != ">=2.0" || e.Meta["optional"] != false {
+		t.Errorf("edge meta = %v", e.Meta)
+	}
+	if _, ok := g.Edges()[1].Meta["constraint"]; ok {
+		t.Error("empty cells should be skipped")
+	}
+}
+
+func TestImportCSV_NoHeader(t *testing.T) {
+	g, err := ImportCSV(strings.NewReader("app\tweb\tx\napp\tdb\n"), nil)
+	if err != nil {
+		t.Fatalf("ImportCSV: %v", err)
+	}
+	if !reflect.DeepEqual(g.Children("app"), []string{"web", "db"}) {
+		t.Errorf("children of app = %v, want [web db]", g.Children("app"))
+	}
+	if got := g.Edges()[0].Meta["col3"]; got != "x" {
+		t.Errorf("col3 = %v, want x", got)
+	}
+}
+
+func TestImportCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		edges string
+		nodes string
+		want  string
+	}{
+		{"MissingTarget", "from,to\napp\n", "", "edges line 2: missing target"},
+		{"EmptySource", "from,to\n,web\n", "", "line 2: empty from"},
+		{"DuplicateNode", "app,web\n", "id\napp\napp\n", "nodes line 3"},
+		{"BadQuote", "app,\"web\n", "", "read edges"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var nodes io.Reader
+			if tt.nodes != "" {
+				nodes = strings.NewReader(tt.nodes)
+			}
+			_, err := ImportCSV(strings.NewReader(tt.edges), nodes)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSniffDelimiter(t *testing.T) {
+	tests := map[string]rune{
+		"a,b,c":    ',',
+		"a\tb\tc":  '\t',
+		"a;b;c":    ';',
+		"a":        ',',
+		"a,b;c\td": ',',
+		"a;b;c,d":  ';',
+	}
+	for line, want := range tests {
+		if got := sniffDelimiter([]byte(line)); got != want {
+			t.Errorf("sniffDelimiter(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
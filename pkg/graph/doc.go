@@ -27,8 +27,13 @@
 //
 //	graph.VizTypeTower      // "tower"
 //	graph.VizTypeNodelink   // "nodelink"
+//	graph.VizTypeSunburst   // "sunburst"
+//	graph.VizTypeTreemap    // "treemap"
+//	graph.VizTypeDSM        // "dsm"
 //	graph.StyleSimple       // "simple"
 //	graph.StyleHanddrawn    // "handdrawn"
+//	graph.StyleBlueprint    // "blueprint"
+//	graph.StyleIsometric    // "isometric"
 //
 // # Graph Serialization
 //
@@ -39,6 +44,10 @@
 //	  "edges": [{"from": "app", "to": "lib-a"}]
 //	}
 //
+// Nodes and edges carry a "meta" object. Edge metadata holds the
+// well-known kind, weight and label keys (see dag.EdgeMetaKind) next to any
+// of your own; the version constraint is written as "constraint".
+//
 // Common operations:
 //
 //	g, _ := graph.ReadGraphFile("deps.json")    // File → DAG
@@ -46,6 +55,63 @@
 //	data, _ := graph.MarshalGraph(dag)          // DAG → []byte
 //	parsed, _ := graph.UnmarshalGraph(data)     // []byte → Graph
 //
+// ReadGraph and WriteGraph stream nodes and edges rather than building the
+// whole document, and gzip and zstd input is decompressed transparently;
+// files ending in .gz or .zst are written compressed.
+//
+// [ExportJSON] writes part of a graph, as selected by [ExportOptions]: only
+// some metadata keys, without bulky metadata or URLs, without synthetic
+// nodes, anonymized, or without indentation. [Project] applies the same selection for
+// other formats:
+//
+//	graph.ExportJSON(dag, w, graph.ExportOptions{StripURLs: true, Compact: true})
+//
+// Output is deterministic: nodes are sorted by ID, edges grouped by source
+// node, and keys written in a fixed order. ExportOptions.Canonical also
+// sorts edges by target and leaves < and > unescaped, so equal graphs
+// export to equal bytes regardless of how they were built.
+//
+// A [Bundle] keeps several named graphs with their layouts and render
+// settings in one JSON document or zip archive, as batch renders write it:
+//
+//	b, _ := graph.ReadBundleFile("services.zip")
+//	api, _ := b.Entry("api")                     // api.Graph, api.Layout
+//
+// # Schemas and Validation
+//
+// Both formats are specified by JSON Schemas embedded in the package
+// ([GraphSchema], [LayoutSchema]) and published under schema/. Documents
+// carry a schema_version ([SchemaVersion]); documents from a newer version
+// fail with [ErrNewerSchema]. [Validate] checks a document and returns
+// [ValidationErrors] locating each problem by JSON Pointer:
+//
+//	if err := graph.Validate(data); err != nil {
+//	    fmt.Println(err) // /edges/3/to: unknown node "reqeusts"
+//	}
+//
+// [Migrate] upgrades documents written by older versions, and node-link
+// JSON from D3 or NetworkX, to the current version. [ReadGraph] and
+// [UnmarshalLayout] apply it automatically.
+//
+// # GraphML and GEXF
+//
+// Graphs also round-trip through GraphML and GEXF, for yEd, Gephi,
+// Cytoscape, NetworkX and other graph tooling:
+//
+//	graph.ExportGraphML(dag, w)                 // DAG → GraphML
+//	g, _ := graph.ImportGraphML(r)              // GraphML → DAG
+//	graph.ExportGEXF(dag, w)                    // DAG → GEXF 1.3
+//	g, _ := graph.ImportGEXF(r)                 // GEXF → DAG
+//	format, ok := graph.DetectInterchange(data) // "graphml" or "gexf"
+//
+// Metadata maps to typed attributes (string, boolean, long, double); values
+// neither format can type, such as lists, are JSON-encoded strings.
+//
+// Spreadsheets and SQL query results come in as CSV edge lists, with an
+// optional node list for package metadata:
+//
+//	g, _ := graph.ImportCSV(edges, nodes)       // from,to[,meta...] → DAG
+//
 // # Layout Serialization
 //
 // Layouts are discriminated by VizType:
@@ -79,6 +145,16 @@
 //	repo_archived     Archived flag
 //	description       Popup content
 //
+// Well-known keys fall into namespaces with fixed types, checked by
+// [Validate]; keys starting with [CustomPrefix] ("x-") hold custom data and
+// are never interpreted. Typed accessors save consumers from asserting map
+// values:
+//
+//	stars := node.Repo().Stars                 // or graph.RepoOf(dagNode.Meta)
+//	if s := node.Metrics().Scorecard; s != nil { ... }
+//	severity := node.Security().VulnSeverity
+//	owner := node.Custom()["owner"]            // meta["x-owner"]
+//
 // # Concurrency
 //
 // All functions are safe for concurrent reads but not concurrent writes.
@@ -29,6 +29,7 @@ func ExampleWriteGraph() {
 	// Output:
 	// JSON output:
 	// {
+	//   "schema_version": 1,
 	//   "nodes": [
 	//     {
 	//       "id": "app"
@@ -176,3 +177,29 @@ func ExampleReadGraph_withMetadata() {
 	// Version: 1.0.0
 	// Stars: 70000
 }
+
+func ExampleExportGraphML() {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "lib", Meta: dag.Metadata{"repo_stars": 120}})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "lib", Meta: dag.Metadata{"constraint": ">=1.0"}})
+
+	var buf bytes.Buffer
+	if err := graph.ExportGraphML(g, &buf); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Read it back: metadata keeps its type.
+	back, err := graph.ImportGraphML(&buf)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	lib, _ := back.Node("lib")
+	fmt.Printf("stars: %v (%T)\n", lib.Meta["repo_stars"], lib.Meta["repo_stars"])
+	fmt.Println("constraint:", back.Edges()[0].Meta["constraint"])
+	// Output:
+	// stars: 120 (int)
+	// constraint: >=1.0
+}
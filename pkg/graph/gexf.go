@@ -0,0 +1,212 @@
+package graph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+const gexfNS = "http://gexf.net/1.3"
+
+type gexfDoc struct {
+	XMLName xml.Name  `xml:"gexf"`
+	XMLNS   string    `xml:"xmlns,attr,omitempty"`
+	Version string    `xml:"version,attr,omitempty"`
+	Meta    *gexfMeta `xml:"meta"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfMeta struct {
+	Creator string `xml:"creator,omitempty"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string           `xml:"defaultedgetype,attr,omitempty"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class string     `xml:"class,attr"`
+	Attrs []gexfAttr `xml:"attribute"`
+}
+
+type gexfAttr struct {
+	ID      string  `xml:"id,attr"`
+	Title   string  `xml:"title,attr"`
+	Type    string  `xml:"type,attr"`
+	Default *string `xml:"default"`
+}
+
+type gexfNode struct {
+	ID     string      `xml:"id,attr"`
+	Label  string      `xml:"label,attr,omitempty"`
+	Values []gexfValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	ID     string      `xml:"id,attr"`
+	Source string      `xml:"source,attr"`
+	Target string      `xml:"target,attr"`
+	Values []gexfValue `xml:"attvalues>attvalue"`
+}
+
+type gexfValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// ExportGEXF writes g as a GEXF 1.3 document for Gephi.
+//
+// Node labels use GEXF's native label attribute; node and edge metadata
+// become typed attributes as in [ExportGraphML], with "kind", "master_id"
+// and "row" kept as node attributes. GEXF has no graph-level attributes,
+// so graph metadata is not written; use GraphML or the JSON format when it
+// matters.
+func ExportGEXF(g *dag.DAG, w io.Writer) error {
+	nodes := sortedNodes(g)
+	edges := g.Edges()
+
+	nodeMaps := make([]map[string]any, len(nodes))
+	for i, n := range nodes {
+		nodeMaps[i] = nodeAttrs(n)
+	}
+	edgeMaps := make([]map[string]any, len(edges))
+	for i, e := range edges {
+		edgeMaps[i] = e.Meta
+	}
+	nodeDefs := collectAttrs("n", nodeMaps)
+	edgeDefs := collectAttrs("e", edgeMaps)
+
+	doc := gexfDoc{
+		XMLNS:   gexfNS,
+		Version: "1.3",
+		Meta:    &gexfMeta{Creator: "stacktower"},
+		Graph:   gexfGraph{DefaultEdgeType: "directed"},
+	}
+	for _, set := range []struct {
+		class string
+		defs  []attrDef
+	}{{"node", nodeDefs}, {"edge", edgeDefs}} {
+		if len(set.defs) == 0 {
+			continue
+		}
+		attrs := gexfAttributes{Class: set.class}
+		for _, d := range set.defs {
+			attrs.Attrs = append(attrs.Attrs, gexfAttr{ID: d.id, Title: d.name, Type: d.typ})
+		}
+		doc.Graph.Attributes = append(doc.Graph.Attributes, attrs)
+	}
+
+	for i, n := range nodes {
+		values, err := gexfValues(nodeDefs, nodeMaps[i])
+		if err != nil {
+			return fmt.Errorf("node %s: %w", n.ID, err)
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{ID: n.ID, Label: nodeLabel(n), Values: values})
+	}
+	for i, e := range edges {
+		values, err := gexfValues(edgeDefs, edgeMaps[i])
+		if err != nil {
+			return fmt.Errorf("edge %s→%s: %w", e.From, e.To, err)
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
+			ID: fmt.Sprintf("e%d", i), Source: e.From, Target: e.To, Values: values,
+		})
+	}
+	return writeXML(w, doc)
+}
+
+// ImportGEXF reads a GEXF document into a DAG. Edges are read as directed
+// from source to target whatever their declared type.
+//
+// Attribute values are typed as in [ImportGraphML]: integer and long as
+// int, float and double as float64, boolean as bool, everything else as a
+// string. Attribute defaults apply to elements without a value. Dynamic
+// attributes (spells, start and end) and viz elements are ignored.
+func ImportGEXF(r io.Reader) (*dag.DAG, error) {
+	var doc gexfDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode gexf: %w", err)
+	}
+	attrs := make(map[string]map[string]gexfAttr)
+	for _, set := range doc.Graph.Attributes {
+		class := strings.ToLower(set.Class)
+		if attrs[class] == nil {
+			attrs[class] = make(map[string]gexfAttr)
+		}
+		for _, a := range set.Attrs {
+			if a.Title == "" {
+				a.Title = a.ID
+			}
+			attrs[class][a.ID] = a
+		}
+	}
+	values := func(class string, vals []gexfValue, set func(string, any)) error {
+		seen := make(map[string]bool, len(vals))
+		for _, av := range vals {
+			a, ok := attrs[class][av.For]
+			if !ok {
+				continue
+			}
+			v, err := decodeAttr(a.Type, strings.HasSuffix(a.ID, jsonSuffix), av.Value)
+			if err != nil {
+				return fmt.Errorf("attribute %s: %w", a.Title, err)
+			}
+			set(a.Title, v)
+			seen[a.ID] = true
+		}
+		for id, a := range attrs[class] {
+			if seen[id] || a.Default == nil {
+				continue
+			}
+			v, err := decodeAttr(a.Type, strings.HasSuffix(a.ID, jsonSuffix), *a.Default)
+			if err != nil {
+				return fmt.Errorf("attribute %s default: %w", a.Title, err)
+			}
+			set(a.Title, v)
+		}
+		return nil
+	}
+
+	d := dag.New(nil)
+	for _, gn := range doc.Graph.Nodes {
+		n := dag.Node{ID: gn.ID, Meta: dag.Metadata{}}
+		if gn.Label != "" {
+			setNodeAttr(&n, attrLabel, gn.Label)
+		}
+		if err := values("node", gn.Values, func(name string, v any) { setNodeAttr(&n, name, v) }); err != nil {
+			return nil, fmt.Errorf("node %s: %w", gn.ID, err)
+		}
+		if err := d.AddNode(n); err != nil {
+			return nil, fmt.Errorf("add node %s: %w", gn.ID, err)
+		}
+	}
+	for _, ge := range doc.Graph.Edges {
+		e := dag.Edge{From: ge.Source, To: ge.Target, Meta: dag.Metadata{}}
+		if err := values("edge", ge.Values, func(name string, v any) { e.Meta[name] = v }); err != nil {
+			return nil, fmt.Errorf("edge %s→%s: %w", ge.Source, ge.Target, err)
+		}
+		if err := d.AddEdge(e); err != nil {
+			return nil, fmt.Errorf("add edge %s→%s: %w", ge.Source, ge.Target, err)
+		}
+	}
+	return d, nil
+}
+
+// gexfValues encodes the attribute values of one element in attribute order.
+func gexfValues(defs []attrDef, m map[string]any) ([]gexfValue, error) {
+	data, err := graphmlValues(defs, m)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]gexfValue, len(data))
+	for i, d := range data {
+		out[i] = gexfValue{For: d.Key, Value: d.Value}
+	}
+	return out, nil
+}
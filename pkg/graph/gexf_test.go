@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGEXF_RoundTrip(t *testing.T) {
+	g := interchangeGraph()
+	var buf bytes.Buffer
+	if err := ExportGEXF(g, &buf); err != nil {
+		t.Fatalf("ExportGEXF: %v", err)
+	}
+	got, err := ImportGEXF(&buf)
+	if err != nil {
+		t.Fatalf("ImportGEXF: %v", err)
+	}
+	assertInterchangeRoundTrip(t, g, got, false)
+	if len(got.Meta()) != 0 {
+		t.Errorf("graph meta = %v, want none: GEXF has no graph attributes", got.Meta())
+	}
+}
+
+func TestExportGEXF_Document(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ExportGEXF(interchangeGraph(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`<gexf xmlns="http://gexf.net/1.3" version="1.3">`,
+		`<creator>stacktower</creator>`,
+		`<graph defaultedgetype="directed">`,
+		`<attributes class="node">`,
+		`<attributes class="edge">`,
+		`title="repo_stars" type="long"`,
+		`title="kind" type="string"`,
+		`<node id="app" label="My App">`,
+		`<node id="lib" label="lib">`,
+		`<edge id="e0" source="app" target="lib_sub_1">`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `title="label"`) {
+		t.Error("labels should use the native label attribute, not a declared one")
+	}
+}
+
+func TestImportGEXF_Gephi(t *testing.T) {
+	// Shaped like a Gephi export: integer and float attributes, defaults,
+	// viz elements and an undirected edge.
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://gexf.net/1.3" xmlns:viz="http://gexf.net/1.3/viz" version="1.3">
+  <graph defaultedgetype="undirected" mode="static">
+    <attributes class="node" mode="static">
+      <attribute id="0" title="modularity_class" type="integer"/>
+      <attribute id="1" title="pagerank" type="float"><default>0.1</default></attribute>
+    </attributes>
+    <nodes>
+      <node id="a" label="Alpha">
+        <attvalues><attvalue for="0" value="2"/><attvalue for="1" value="0.4"/></attvalues>
+        <viz:size value="10.0"/>
+      </node>
+      <node id="b" label="b"/>
+    </nodes>
+    <edges>
+      <edge id="0" source="a" target="b" type="undirected" weight="1.0"/>
+    </edges>
+  </graph>
+</gexf>`
+	g, err := ImportGEXF(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("ImportGEXF: %v", err)
+	}
+	a, _ := g.Node("a")
+	b, _ := g.Node("b")
+	if a.Meta[metaLabel] != "Alpha" || a.Meta["modularity_class"] != 2 || a.Meta["pagerank"] != 0.4 {
+		t.Errorf("a meta = %v", a.Meta)
+	}
+	if _, ok := b.Meta[metaLabel]; ok {
+		t.Error("a label equal to the ID should not be stored")
+	}
+	if b.Meta["pagerank"] != 0.1 {
+		t.Errorf("b pagerank = %v, want the default 0.1", b.Meta["pagerank"])
+	}
+	if g.EdgeCount() != 1 {
+		t.Errorf("edges = %d, want 1", g.EdgeCount())
+	}
+}
+
+func TestImportGEXF_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"Malformed", `<gexf><graph>`, "decode gexf"},
+		{"BadValue", `<gexf><graph><attributes class="node"><attribute id="0" title="n" type="integer"/></attributes><nodes><node id="a"><attvalues><attvalue for="0" value="x"/></attvalues></node></nodes></graph></gexf>`, "node a"},
+		{"DanglingEdge", `<gexf><graph><nodes><node id="a"/></nodes><edges><edge id="0" source="a" target="missing"/></edges></graph></gexf>`, "add edge a→missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportGEXF(strings.NewReader(tt.doc))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -1,11 +1,14 @@
 package graph
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 )
@@ -18,31 +21,46 @@ import (
 // Nodes are sorted by ID for deterministic output.
 func MarshalGraph(g *dag.DAG) ([]byte, error) {
 	var buf bytes.Buffer
-	if err := writeGraphTo(g, &buf); err != nil {
+	if err := writeGraphTo(g, &buf, ExportOptions{}); err != nil {
 		return nil, err
 	}
 	return buf.Bytes(), nil
 }
 
-// WriteGraphFile writes a DAG to a JSON file.
-// The file is created with 0644 permissions.
+// WriteGraphFile writes a DAG to a JSON file, compressed when path ends in
+// ".gz" or ".zst". The file is created with 0644 permissions.
 func WriteGraphFile(g *dag.DAG, path string) error {
+	return ExportJSONFile(g, path, ExportOptions{})
+}
+
+// ExportJSONFile is [ExportJSON] to a file, compressed when path ends in
+// ".gz" or ".zst". The file is created with 0644 permissions.
+func ExportJSONFile(g *dag.DAG, path string, opts ExportOptions) error {
 	f, err := os.Create(path)
 	if err != nil {
 		return fmt.Errorf("create %s: %w", path, err)
 	}
 	defer f.Close()
-	return writeGraphTo(g, f)
+	w, flush := compressWriter(f, path)
+	if err := ExportJSON(g, w, opts); err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return fmt.Errorf("compress %s: %w", path, err)
+	}
+	return f.Close()
 }
 
-// WriteGraph writes a DAG as JSON to an io.Writer.
-// Use MarshalGraph for in-memory serialization or WriteGraphFile for files.
+// WriteGraph writes a DAG as JSON to an io.Writer, streaming one node and
+// edge at a time. Use MarshalGraph for in-memory serialization or
+// WriteGraphFile for files.
 func WriteGraph(g *dag.DAG, w io.Writer) error {
-	return writeGraphTo(g, w)
+	return writeGraphTo(g, w, ExportOptions{})
 }
 
-// ReadGraphFile reads a JSON file and returns the decoded DAG.
-// Returns validation errors for malformed graphs or DAG constraint violations.
+// ReadGraphFile reads a JSON file, compressed or not, and returns the
+// decoded DAG. Returns validation errors for malformed graphs or DAG
+// constraint violations.
 func ReadGraphFile(path string) (*dag.DAG, error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -52,8 +70,10 @@ func ReadGraphFile(path string) (*dag.DAG, error) {
 	return readGraphFrom(f)
 }
 
-// ReadGraph decodes a JSON graph from an io.Reader into a DAG.
-// Use ReadGraphFile for files or pass bytes.NewReader for in-memory data.
+// ReadGraph decodes a JSON graph from an io.Reader into a DAG, streaming
+// nodes and edges so large graphs need little memory beyond the DAG itself.
+// gzip- and zstd-compressed input is decompressed transparently. Use
+// ReadGraphFile for files or pass bytes.NewReader for in-memory data.
 func ReadGraph(r io.Reader) (*dag.DAG, error) {
 	return readGraphFrom(r)
 }
@@ -62,20 +82,238 @@ func ReadGraph(r io.Reader) (*dag.DAG, error) {
 // Internal Implementation
 // =============================================================================
 
-func writeGraphTo(g *dag.DAG, w io.Writer) error {
-	out := FromDAG(g)
-	enc := json.NewEncoder(w)
-	enc.SetIndent("", "  ")
-	if err := enc.Encode(out); err != nil {
-		return fmt.Errorf("encode: %w", err)
+// writeGraphTo streams g as JSON, one node and edge at a time, so the whole
+// document is never held in memory. Only the formatting options of opts are
+// used. By default the output is byte-for-byte what encoding FromDAG(g) with
+// a two-space indent would produce.
+func writeGraphTo(g *dag.DAG, w io.Writer, opts ExportOptions) error {
+	compact := opts.Compact
+	bw := bufio.NewWriter(w)
+	write := func(s string) { bw.WriteString(s) }
+	// nl returns a newline and indentation for the given nesting depth.
+	nl := func(depth int) string {
+		if compact {
+			return ""
+		}
+		return "\n" + strings.Repeat("  ", depth)
 	}
-	return nil
+	space := " "
+	if compact {
+		space = ""
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(!opts.Canonical)
+	element := func(v any, depth int) error {
+		buf.Reset()
+		if !compact {
+			enc.SetIndent(strings.Repeat("  ", depth), "  ")
+		}
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("encode: %w", err)
+		}
+		bw.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+		return nil
+	}
+	list := func(name string, n int, each func(i int) error) error {
+		write(`"` + name + `":` + space + "[")
+		for i := range n {
+			if i > 0 {
+				write(",")
+			}
+			write(nl(2))
+			if err := each(i); err != nil {
+				return err
+			}
+		}
+		if n > 0 {
+			write(nl(1))
+		}
+		write("]")
+		return nil
+	}
+
+	write("{" + nl(1) + `"schema_version":` + space + strconv.Itoa(SchemaVersion) + "," + nl(1))
+	if meta := g.Meta(); len(meta) > 0 {
+		write(`"meta":` + space)
+		if err := element(map[string]any(meta), 1); err != nil {
+			return err
+		}
+		write("," + nl(1))
+	}
+
+	nodes := sortedNodes(g)
+	if err := list("nodes", len(nodes), func(i int) error {
+		return element(nodeFromDAG(nodes[i]), 2)
+	}); err != nil {
+		return err
+	}
+	write("," + nl(1))
+
+	edges := sortedEdges(g, opts.Canonical)
+	if err := list("edges", len(edges), func(i int) error {
+		return element(edgeFromDAG(&edges[i]), 2)
+	}); err != nil {
+		return err
+	}
+	write(nl(0) + "}\n")
+	return bw.Flush()
 }
 
+// readGraphFrom decodes a graph token by token, adding each node and edge
+// to the DAG as it is read, so only the DAG itself is held in memory.
+// Compressed input is unwrapped first. Edges listed before the nodes are
+// held back until the nodes are in.
 func readGraphFrom(r io.Reader) (*dag.DAG, error) {
-	var data Graph
-	if err := json.NewDecoder(r).Decode(&data); err != nil {
+	r, err := Decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+
+	d := dag.New(nil)
+	var (
+		pending   []Edge
+		nodesRead bool
+		version   int
+	)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("decode: %w", err)
+		}
+		key, _ := tok.(string)
+		if version < SchemaVersion {
+			key = migrateKey(version, key)
+		}
+		switch strings.ToLower(key) {
+		case "schema_version":
+			if err := dec.Decode(&version); err != nil {
+				return nil, fmt.Errorf("decode schema_version: %w", err)
+			}
+			if err := checkSchemaVersion(version); err != nil {
+				return nil, err
+			}
+		case "meta":
+			var meta map[string]any
+			if err := dec.Decode(&meta); err != nil {
+				return nil, fmt.Errorf("decode meta: %w", err)
+			}
+			for k, v := range meta {
+				d.Meta()[k] = v
+			}
+		case "nodes":
+			err = decodeArray(dec, func() error {
+				var nj Node
+				if err := decodeElement(dec, version, "nodes", &nj); err != nil {
+					return err
+				}
+				if err := d.AddNode(nodeToDAG(nj)); err != nil {
+					return fmt.Errorf("add node %s: %w", nj.ID, err)
+				}
+				return nil
+			})
+			nodesRead = true
+		case "edges":
+			err = decodeArray(dec, func() error {
+				var ej Edge
+				if err := decodeElement(dec, version, "edges", &ej); err != nil {
+					return err
+				}
+				if !nodesRead {
+					pending = append(pending, ej)
+					return nil
+				}
+				return addEdge(d, ej)
+			})
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
 		return nil, fmt.Errorf("decode: %w", err)
 	}
-	return ToDAG(data)
+	for _, ej := range pending {
+		if err := addEdge(d, ej); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// decodeElement decodes the next node or edge into v, first migrating it
+// from version when that is older than SchemaVersion. Documents carry
+// their schema_version first, so current ones take the direct path.
+func decodeElement(dec *json.Decoder, version int, field string, v any) error {
+	if version >= SchemaVersion {
+		if err := dec.Decode(v); err != nil {
+			return fmt.Errorf("decode: %w", err)
+		}
+		return nil
+	}
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	migrateElement(version, field, obj)
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	return nil
+}
+
+// addEdge adds a decoded edge to d.
+func addEdge(d *dag.DAG, ej Edge) error {
+	if err := d.AddEdge(edgeToDAG(ej)); err != nil {
+		return fmt.Errorf("add edge %s→%s: %w", ej.From, ej.To, err)
+	}
+	return nil
+}
+
+// expectDelim reads the next token and checks that it is delim.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("expected %v, got %v", delim, tok)
+	}
+	return nil
+}
+
+// decodeArray calls each for every element of the JSON array at the
+// decoder's position, with the decoder positioned at the element. A null
+// array has no elements.
+func decodeArray(dec *json.Decoder, each func() error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("decode: expected array, got %v", tok)
+	}
+	for dec.More() {
+		if err := each(); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	return nil
 }
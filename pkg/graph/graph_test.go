@@ -3,6 +3,7 @@ package graph
 import (
 	"bytes"
 	"encoding/json"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
@@ -154,6 +155,54 @@ func TestReadGraph(t *testing.T) {
 			input:   `{invalid json}`,
 			wantErr: true,
 		},
+		{
+			name: "EdgesBeforeNodes",
+			input: `{
+				"edges": [{"from": "A", "to": "B", "constraint": ">=1"}],
+				"meta": {"language": "go"},
+				"nodes": [{"id": "A"}, {"id": "B"}]
+			}`,
+			wantNodes: 2,
+			wantEdges: 1,
+			check: func(t *testing.T, g *dag.DAG) {
+				if g.Meta()["language"] != "go" {
+					t.Errorf("language = %v, want go", g.Meta()["language"])
+				}
+				if c := g.Edges()[0].Meta["constraint"]; c != ">=1" {
+					t.Errorf("constraint = %v, want >=1", c)
+				}
+			},
+		},
+		{
+			name: "EdgeMeta",
+			input: `{
+				"nodes": [{"id": "A"}, {"id": "B"}],
+				"edges": [{"from": "A", "to": "B", "constraint": ">=1", "meta": {"kind": "dev", "weight": 2.5}}]
+			}`,
+			wantNodes: 2,
+			wantEdges: 1,
+			check: func(t *testing.T, g *dag.DAG) {
+				e := g.Edges()[0]
+				if e.Meta[dag.EdgeMetaKind] != "dev" || e.Weight() != 2.5 || e.Meta[dag.EdgeMetaConstraint] != ">=1" {
+					t.Errorf("edge meta = %v", e.Meta)
+				}
+			},
+		},
+		{
+			name:      "UnknownKeysAndNullArrays",
+			input:     `{"version": 2, "extra": {"a": [1, 2]}, "nodes": [{"id": "A"}], "edges": null}`,
+			wantNodes: 1,
+		},
+		{
+			name:    "NotAnObject",
+			input:   `[{"id": "A"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "NodesNotAnArray",
+			input:   `{"nodes": "A"}`,
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -209,6 +258,32 @@ func TestReadGraphFile(t *testing.T) {
 	}
 }
 
+func TestWriteGraph_EdgeMetaRoundTrip(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "a"})
+	g.AddNode(dag.Node{ID: "b"})
+	g.AddEdge(dag.Edge{From: "a", To: "b", Meta: dag.Metadata{
+		dag.EdgeMetaConstraint: "^2",
+		dag.EdgeMetaLabel:      "calls 40x",
+		"x-owner":              "team-a",
+	}})
+
+	var buf bytes.Buffer
+	if err := WriteGraph(g, &buf); err != nil {
+		t.Fatalf("WriteGraph: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"constraint": "^2"`) {
+		t.Errorf("constraint should stay a top-level edge field:\n%s", buf.String())
+	}
+	got, err := ReadGraph(&buf)
+	if err != nil {
+		t.Fatalf("ReadGraph: %v", err)
+	}
+	if e := got.Edges()[0]; !maps.Equal(e.Meta, g.Edges()[0].Meta) {
+		t.Errorf("edge meta = %v, want %v", e.Meta, g.Edges()[0].Meta)
+	}
+}
+
 func TestReadGraphFileNotFound(t *testing.T) {
 	_, err := ReadGraphFile("nonexistent.json")
 	if err == nil {
@@ -469,3 +544,38 @@ func jsonString(s string) string {
 	data, _ := json.Marshal(s)
 	return string(data)
 }
+
+// FuzzReadGraph feeds arbitrary input to the JSON importer, seeded with the
+// example graphs. Malformed input must produce an error, never a panic, and
+// every graph that reads back must survive a write and re-read unchanged
+// in size.
+func FuzzReadGraph(f *testing.F) {
+	files, _ := filepath.Glob(filepath.Join("..", "..", "examples", "test", "*.json"))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add([]byte(`{"nodes": [{"id": "a"}, {"id": "b", "row": 1}], "edges": [{"from": "a", "to": "b"}]}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g, err := ReadGraph(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		var buf bytes.Buffer
+		if err := WriteGraph(g, &buf); err != nil {
+			t.Fatalf("WriteGraph: %v", err)
+		}
+		got, err := ReadGraph(&buf)
+		if err != nil {
+			t.Fatalf("re-reading written graph: %v", err)
+		}
+		if got.NodeCount() != g.NodeCount() || got.EdgeCount() != g.EdgeCount() {
+			t.Fatalf("round trip changed size: %d nodes, %d edges; want %d, %d",
+				got.NodeCount(), got.EdgeCount(), g.NodeCount(), g.EdgeCount())
+		}
+	})
+}
@@ -0,0 +1,214 @@
+package graph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+const graphmlNS = "http://graphml.graphdrawing.org/xmlns"
+
+type graphmlDoc struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr,omitempty"`
+	Keys    []graphmlKey `xml:"key"`
+	Graph   graphmlGraph `xml:"graph"`
+}
+
+type graphmlKey struct {
+	ID      string  `xml:"id,attr"`
+	For     string  `xml:"for,attr"`
+	Name    string  `xml:"attr.name,attr"`
+	Type    string  `xml:"attr.type,attr"`
+	Default *string `xml:"default"`
+}
+
+type graphmlGraph struct {
+	ID          string        `xml:"id,attr,omitempty"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Data        []graphmlData `xml:"data"`
+	Nodes       []graphmlNode `xml:"node"`
+	Edges       []graphmlEdge `xml:"edge"`
+}
+
+type graphmlNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphmlData `xml:"data"`
+}
+
+type graphmlEdge struct {
+	ID     string        `xml:"id,attr,omitempty"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphmlData `xml:"data"`
+}
+
+type graphmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ExportGraphML writes g as a GraphML document for tools such as yEd,
+// Gephi, Cytoscape and NetworkX.
+//
+// Node, edge and graph metadata become typed <key> attributes: strings,
+// booleans, whole numbers as long and other numbers as double. Values no
+// GraphML type can hold, such as maintainer lists, are stored as JSON
+// strings under keys whose id ends in "-json", which [ImportGraphML] decodes
+// again. Every node also gets a "label" (its display label) and, when set,
+// "kind", "master_id" and "row", so subdivided graphs round-trip.
+func ExportGraphML(g *dag.DAG, w io.Writer) error {
+	nodes := sortedNodes(g)
+	edges := g.Edges()
+
+	nodeMaps := make([]map[string]any, len(nodes))
+	for i, n := range nodes {
+		nodeMaps[i] = nodeAttrs(n)
+		nodeMaps[i][attrLabel] = nodeLabel(n)
+	}
+	edgeMaps := make([]map[string]any, len(edges))
+	for i, e := range edges {
+		edgeMaps[i] = e.Meta
+	}
+	nodeDefs := collectAttrs("n", nodeMaps)
+	edgeDefs := collectAttrs("e", edgeMaps)
+	graphDefs := collectAttrs("g", []map[string]any{g.Meta()})
+
+	doc := graphmlDoc{XMLNS: graphmlNS, Graph: graphmlGraph{EdgeDefault: "directed"}}
+	for _, set := range []struct {
+		domain string
+		defs   []attrDef
+	}{{"graph", graphDefs}, {"node", nodeDefs}, {"edge", edgeDefs}} {
+		for _, d := range set.defs {
+			doc.Keys = append(doc.Keys, graphmlKey{ID: d.id, For: set.domain, Name: d.name, Type: d.typ})
+		}
+	}
+
+	var err error
+	if doc.Graph.Data, err = graphmlValues(graphDefs, g.Meta()); err != nil {
+		return err
+	}
+	for i, n := range nodes {
+		data, err := graphmlValues(nodeDefs, nodeMaps[i])
+		if err != nil {
+			return fmt.Errorf("node %s: %w", n.ID, err)
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphmlNode{ID: n.ID, Data: data})
+	}
+	for i, e := range edges {
+		data, err := graphmlValues(edgeDefs, edgeMaps[i])
+		if err != nil {
+			return fmt.Errorf("edge %s→%s: %w", e.From, e.To, err)
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, graphmlEdge{
+			ID: fmt.Sprintf("e%d", i), Source: e.From, Target: e.To, Data: data,
+		})
+	}
+	return writeXML(w, doc)
+}
+
+// ImportGraphML reads a GraphML document into a DAG. Edges are read as
+// directed from source to target whatever the document's edgedefault.
+//
+// Typed keys become metadata of the matching Go type (int for int and
+// long, float64 for float and double, bool, string) and key defaults apply
+// to elements without a value. The "label", "kind", "master_id" and "row"
+// node keys written by [ExportGraphML] restore the node's fields. Only the
+// first graph is read; nested graphs and hyperedges are ignored.
+func ImportGraphML(r io.Reader) (*dag.DAG, error) {
+	var doc graphmlDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode graphml: %w", err)
+	}
+	keys := make(map[string]graphmlKey, len(doc.Keys))
+	for i, k := range doc.Keys {
+		if k.Name == "" {
+			doc.Keys[i].Name = k.ID
+		}
+		keys[k.ID] = doc.Keys[i]
+	}
+	values := func(domain string, data []graphmlData, set func(string, any)) error {
+		seen := make(map[string]bool, len(data))
+		for _, d := range data {
+			k, ok := keys[d.Key]
+			if !ok {
+				continue
+			}
+			v, err := decodeAttr(k.Type, strings.HasSuffix(k.ID, jsonSuffix), d.Value)
+			if err != nil {
+				return fmt.Errorf("key %s: %w", k.Name, err)
+			}
+			set(k.Name, v)
+			seen[k.ID] = true
+		}
+		for _, k := range doc.Keys {
+			if seen[k.ID] || k.Default == nil || (k.For != domain && k.For != "all") {
+				continue
+			}
+			v, err := decodeAttr(k.Type, strings.HasSuffix(k.ID, jsonSuffix), *k.Default)
+			if err != nil {
+				return fmt.Errorf("key %s default: %w", k.Name, err)
+			}
+			set(k.Name, v)
+		}
+		return nil
+	}
+
+	d := dag.New(nil)
+	if err := values("graph", doc.Graph.Data, func(name string, v any) { d.Meta()[name] = v }); err != nil {
+		return nil, fmt.Errorf("graph: %w", err)
+	}
+	for _, gn := range doc.Graph.Nodes {
+		n := dag.Node{ID: gn.ID, Meta: dag.Metadata{}}
+		if err := values("node", gn.Data, func(name string, v any) { setNodeAttr(&n, name, v) }); err != nil {
+			return nil, fmt.Errorf("node %s: %w", gn.ID, err)
+		}
+		if err := d.AddNode(n); err != nil {
+			return nil, fmt.Errorf("add node %s: %w", gn.ID, err)
+		}
+	}
+	for _, ge := range doc.Graph.Edges {
+		e := dag.Edge{From: ge.Source, To: ge.Target, Meta: dag.Metadata{}}
+		if err := values("edge", ge.Data, func(name string, v any) { e.Meta[name] = v }); err != nil {
+			return nil, fmt.Errorf("edge %s→%s: %w", ge.Source, ge.Target, err)
+		}
+		if err := d.AddEdge(e); err != nil {
+			return nil, fmt.Errorf("add edge %s→%s: %w", ge.Source, ge.Target, err)
+		}
+	}
+	return d, nil
+}
+
+// graphmlValues encodes the attributes of one element in key order.
+func graphmlValues(defs []attrDef, m map[string]any) ([]graphmlData, error) {
+	var out []graphmlData
+	for _, def := range defs {
+		v, ok := m[def.name]
+		if !ok || v == nil {
+			continue
+		}
+		s, err := encodeAttr(def, v)
+		if err != nil {
+			return nil, fmt.Errorf("encode %s: %w", def.name, err)
+		}
+		out = append(out, graphmlData{Key: def.id, Value: s})
+	}
+	return out, nil
+}
+
+// writeXML writes doc as an indented XML document with a declaration.
+func writeXML(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,160 @@
+package graph
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+// interchangeGraph builds a graph exercising every attribute type, the
+// structural node fields and edge metadata.
+func interchangeGraph() *dag.DAG {
+	g := dag.New(nil)
+	g.Meta()["language"] = "python"
+	g.Meta()["runtime_version"] = "3.12"
+	_ = g.AddNode(dag.Node{ID: "app", Meta: dag.Metadata{
+		metaLabel: "My App", "version": "1.0.0", "repo_stars": 1200,
+	}})
+	_ = g.AddNode(dag.Node{ID: "lib", Row: 2, Meta: dag.Metadata{
+		"version": "2.1", "repo_stars": 37, "repo_archived": true,
+		"score":            0.75,
+		"repo_maintainers": []any{"alice", "bob"},
+	}})
+	_ = g.AddNode(dag.Node{ID: "lib_sub_1", Row: 1, Kind: dag.NodeKindSubdivider, MasterID: "lib"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "lib_sub_1", Meta: dag.Metadata{"constraint": ">=2"}})
+	_ = g.AddEdge(dag.Edge{From: "lib_sub_1", To: "lib"})
+	return g
+}
+
+func TestGraphML_RoundTrip(t *testing.T) {
+	g := interchangeGraph()
+	var buf bytes.Buffer
+	if err := ExportGraphML(g, &buf); err != nil {
+		t.Fatalf("ExportGraphML: %v", err)
+	}
+	got, err := ImportGraphML(&buf)
+	if err != nil {
+		t.Fatalf("ImportGraphML: %v", err)
+	}
+	assertInterchangeRoundTrip(t, g, got, true)
+}
+
+func TestExportGraphML_Keys(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ExportGraphML(interchangeGraph(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`,
+		`<graph edgedefault="directed">`,
+		`for="node" attr.name="label" attr.type="string"`,
+		`for="node" attr.name="repo_stars" attr.type="long"`,
+		`for="node" attr.name="repo_archived" attr.type="boolean"`,
+		`for="node" attr.name="score" attr.type="double"`,
+		`for="node" attr.name="repo_maintainers" attr.type="string"`,
+		`for="edge" attr.name="constraint" attr.type="string"`,
+		`for="graph" attr.name="language" attr.type="string"`,
+		`<edge id="e0" source="app" target="lib_sub_1">`,
+		`>My App</data>`,
+		`>[&#34;alice&#34;,&#34;bob&#34;]</data>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n%s", want, out)
+		}
+	}
+}
+
+func TestImportGraphML_ThirdParty(t *testing.T) {
+	// Shaped like a yEd / NetworkX export: key defaults, int and float
+	// types, a data key without attr.name, and undirected edgedefault.
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="weight" attr.type="float"><default>1.5</default></key>
+  <key id="d1" for="node" attr.name="count" attr.type="int"/>
+  <key id="d2" for="edge" attr.name="kind" attr.type="string"/>
+  <key id="nodegraphics" for="node" yfiles.type="nodegraphics"/>
+  <graph id="G" edgedefault="undirected">
+    <node id="a"><data key="d0">2.5</data><data key="d1">3</data><data key="nodegraphics"></data></node>
+    <node id="b"/>
+    <edge source="a" target="b"><data key="d2">runtime</data></edge>
+  </graph>
+</graphml>`
+	g, err := ImportGraphML(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("ImportGraphML: %v", err)
+	}
+	a, _ := g.Node("a")
+	b, _ := g.Node("b")
+	if a.Meta["weight"] != 2.5 || a.Meta["count"] != 3 {
+		t.Errorf("a meta = %v, want weight 2.5 and count 3", a.Meta)
+	}
+	if b.Meta["weight"] != 1.5 {
+		t.Errorf("b weight = %v, want the key default 1.5", b.Meta["weight"])
+	}
+	if !reflect.DeepEqual(g.Children("a"), []string{"b"}) {
+		t.Errorf("children of a = %v, want [b]", g.Children("a"))
+	}
+	if e := g.Edges()[0]; e.Meta["kind"] != "runtime" {
+		t.Errorf("edge meta = %v, want kind runtime", e.Meta)
+	}
+}
+
+func TestImportGraphML_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"Malformed", `<graphml><graph>`, "decode graphml"},
+		{"BadValue", `<graphml><key id="d0" for="node" attr.name="n" attr.type="long"/><graph><node id="a"><data key="d0">x</data></node></graph></graphml>`, "node a"},
+		{"DanglingEdge", `<graphml><graph><node id="a"/><edge source="a" target="missing"/></graph></graphml>`, "add edge a→missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportGraphML(strings.NewReader(tt.doc))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// assertInterchangeRoundTrip checks that got carries the nodes, edges and
+// metadata of want, with graph metadata only when withGraphMeta is set.
+func assertInterchangeRoundTrip(t *testing.T, want, got *dag.DAG, withGraphMeta bool) {
+	t.Helper()
+	if got.NodeCount() != want.NodeCount() || got.EdgeCount() != want.EdgeCount() {
+		t.Fatalf("got %d nodes, %d edges; want %d, %d",
+			got.NodeCount(), got.EdgeCount(), want.NodeCount(), want.EdgeCount())
+	}
+	for _, wn := range want.Nodes() {
+		gn, ok := got.Node(wn.ID)
+		if !ok {
+			t.Errorf("node %s missing", wn.ID)
+			continue
+		}
+		if gn.Row != wn.Row || gn.Kind != wn.Kind || gn.MasterID != wn.MasterID {
+			t.Errorf("node %s = row %d kind %v master %q; want row %d kind %v master %q",
+				wn.ID, gn.Row, gn.Kind, gn.MasterID, wn.Row, wn.Kind, wn.MasterID)
+		}
+		if len(gn.Meta)+len(wn.Meta) > 0 && !reflect.DeepEqual(gn.Meta, wn.Meta) {
+			t.Errorf("node %s meta = %#v, want %#v", wn.ID, gn.Meta, wn.Meta)
+		}
+	}
+	for i, we := range want.Edges() {
+		ge := got.Edges()[i]
+		if ge.From != we.From || ge.To != we.To {
+			t.Errorf("edge %d = %s→%s, want %s→%s", i, ge.From, ge.To, we.From, we.To)
+		}
+		if len(ge.Meta)+len(we.Meta) > 0 && !reflect.DeepEqual(ge.Meta, we.Meta) {
+			t.Errorf("edge %d meta = %v, want %v", i, ge.Meta, we.Meta)
+		}
+	}
+	if withGraphMeta && !reflect.DeepEqual(got.Meta(), want.Meta()) {
+		t.Errorf("graph meta = %v, want %v", got.Meta(), want.Meta())
+	}
+}
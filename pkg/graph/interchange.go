@@ -0,0 +1,258 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+// =============================================================================
+// Interchange Formats (GraphML, GEXF)
+// =============================================================================
+
+// Interchange formats recognized by [DetectInterchange].
+const (
+	FormatGraphML = "graphml"
+	FormatGEXF    = "gexf"
+)
+
+// Attribute types shared by GraphML and GEXF.
+const (
+	attrString  = "string"
+	attrBoolean = "boolean"
+	attrLong    = "long"
+	attrDouble  = "double"
+)
+
+// Node attributes carrying [dag.Node] fields rather than metadata.
+const (
+	attrLabel    = "label"
+	attrKind     = "kind"
+	attrMasterID = "master_id"
+	attrRow      = "row"
+)
+
+// jsonSuffix marks attribute ids whose values are JSON-encoded, for
+// metadata such as maintainer lists that neither format can type.
+const jsonSuffix = "-json"
+
+// DetectInterchange reports whether data is a GraphML or GEXF document,
+// returning [FormatGraphML] or [FormatGEXF]. It only reads up to the root
+// element, so callers can fall back to other formats cheaply.
+func DetectInterchange(data []byte) (string, bool) {
+	trimmed := bytes.TrimSpace(data)
+	if !bytes.HasPrefix(trimmed, []byte("<")) {
+		return "", false
+	}
+	dec := xml.NewDecoder(bytes.NewReader(trimmed))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", false
+		}
+		if el, ok := tok.(xml.StartElement); ok {
+			switch el.Name.Local {
+			case FormatGraphML, FormatGEXF:
+				return el.Name.Local, true
+			}
+			return "", false
+		}
+	}
+}
+
+// attrDef declares one typed attribute of nodes, edges or the graph.
+type attrDef struct {
+	id   string
+	name string
+	typ  string
+	json bool
+}
+
+// collectAttrs declares an attribute for every key in maps, typed from the
+// values it takes. ids are prefix followed by a sequence number; keys are
+// sorted so the output is deterministic.
+func collectAttrs(prefix string, maps []map[string]any) []attrDef {
+	values := make(map[string][]any)
+	for _, m := range maps {
+		for k, v := range m {
+			if v != nil {
+				values[k] = append(values[k], v)
+			}
+		}
+	}
+	names := make([]string, 0, len(values))
+	for k := range values {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+
+	defs := make([]attrDef, len(names))
+	for i, name := range names {
+		typ, asJSON := attrType(values[name])
+		defs[i] = attrDef{id: prefix + strconv.Itoa(i), name: name, typ: typ, json: asJSON}
+		if asJSON {
+			defs[i].id += jsonSuffix
+		}
+	}
+	return defs
+}
+
+// attrType picks the narrowest attribute type holding every value: boolean,
+// long for whole numbers, double, or string. Anything else, including a
+// mix of types, is stored as a JSON-encoded string.
+func attrType(values []any) (typ string, asJSON bool) {
+	kinds := make(map[string]bool)
+	for _, v := range values {
+		switch v := v.(type) {
+		case string:
+			kinds[attrString] = true
+		case bool:
+			kinds[attrBoolean] = true
+		case int, int32, int64:
+			kinds[attrLong] = true
+		case float32:
+			kinds[attrDouble] = true
+		case float64:
+			if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+				kinds[attrLong] = true
+			} else {
+				kinds[attrDouble] = true
+			}
+		default:
+			return attrString, true
+		}
+	}
+	if kinds[attrLong] && kinds[attrDouble] {
+		delete(kinds, attrLong)
+	}
+	if len(kinds) != 1 {
+		return attrString, true
+	}
+	for k := range kinds {
+		typ = k
+	}
+	return typ, false
+}
+
+// encodeAttr formats a value for an attribute declared by def.
+func encodeAttr(def attrDef, v any) (string, error) {
+	if def.json {
+		b, err := json.Marshal(v)
+		return string(b), err
+	}
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	}
+	return fmt.Sprint(v), nil
+}
+
+// decodeAttr parses an attribute value back into the Go type the graph
+// JSON format would produce for it. Unknown types are kept as strings.
+func decodeAttr(typ string, asJSON bool, s string) (any, error) {
+	if asJSON {
+		var v any
+		err := json.Unmarshal([]byte(s), &v)
+		return v, err
+	}
+	switch strings.ToLower(typ) {
+	case attrBoolean:
+		return strconv.ParseBool(s)
+	case "int", "integer", attrLong:
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		return int(n), err
+	case "float", attrDouble:
+		return strconv.ParseFloat(strings.TrimSpace(s), 64)
+	}
+	return s, nil
+}
+
+// nodeAttrs returns the attributes written for a node: its metadata plus
+// the structural fields, with the display label under "label".
+func nodeAttrs(n *dag.Node) map[string]any {
+	m := make(map[string]any, len(n.Meta)+3)
+	for k, v := range n.Meta {
+		if k != metaLabel {
+			m[k] = v
+		}
+	}
+	if kind := dagKindToString(n.Kind); kind != "" {
+		m[attrKind] = kind
+	}
+	if n.MasterID != "" {
+		m[attrMasterID] = n.MasterID
+	}
+	if n.Row != 0 {
+		m[attrRow] = n.Row
+	}
+	return m
+}
+
+// setNodeAttr stores an imported attribute on a node, restoring the
+// structural fields written by [nodeAttrs].
+func setNodeAttr(n *dag.Node, name string, v any) {
+	switch name {
+	case attrLabel:
+		if s, ok := v.(string); ok && s != n.ID {
+			n.Meta[metaLabel] = s
+		}
+		return
+	case attrKind:
+		if s, ok := v.(string); ok {
+			n.Kind = stringToDAGKind(s)
+			return
+		}
+	case attrMasterID:
+		if s, ok := v.(string); ok {
+			n.MasterID = s
+			return
+		}
+	case attrRow:
+		if r, ok := v.(int); ok {
+			n.Row = r
+			return
+		}
+	}
+	n.Meta[name] = v
+}
+
+// nodeLabel returns the display label of a node.
+func nodeLabel(n *dag.Node) string {
+	if label, ok := n.Meta[metaLabel].(string); ok && label != "" {
+		return label
+	}
+	return n.ID
+}
+
+// sortedNodes returns the nodes of g sorted by ID.
+func sortedNodes(g *dag.DAG) []*dag.Node {
+	nodes := g.Nodes()
+	slices.SortFunc(nodes, func(a, b *dag.Node) int { return strings.Compare(a.ID, b.ID) })
+	return nodes
+}
+
+// sortedEdges returns the edges of g grouped by source node in ID order,
+// keeping each node's dependencies in the order they were added. With full
+// set, each group is sorted by target as well, so the order no longer
+// depends on how the graph was built.
+func sortedEdges(g *dag.DAG, full bool) []dag.Edge {
+	edges := g.Edges()
+	slices.SortStableFunc(edges, func(a, b dag.Edge) int {
+		if c := strings.Compare(a.From, b.From); c != 0 || !full {
+			return c
+		}
+		return strings.Compare(a.To, b.To)
+	})
+	return edges
+}
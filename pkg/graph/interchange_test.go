@@ -0,0 +1,80 @@
+package graph
+
+import "testing"
+
+func TestDetectInterchange(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		want   string
+		wantOK bool
+	}{
+		{"GraphML", `<?xml version="1.0"?><graphml xmlns="http://graphml.graphdrawing.org/xmlns"></graphml>`, FormatGraphML, true},
+		{"GEXF", "\n  <!-- exported --><gexf version=\"1.3\"></gexf>", FormatGEXF, true},
+		{"JSON", `{"nodes": []}`, "", false},
+		{"OtherXML", `<project><modelVersion>4.0.0</modelVersion></project>`, "", false},
+		{"Empty", ``, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DetectInterchange([]byte(tt.data))
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("DetectInterchange = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestAttrType(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []any
+		wantType string
+		wantJSON bool
+	}{
+		{"Strings", []any{"a", "b"}, attrString, false},
+		{"Bools", []any{true, false}, attrBoolean, false},
+		{"Ints", []any{1, int64(2)}, attrLong, false},
+		{"WholeFloats", []any{3.0, 4}, attrLong, false},
+		{"Doubles", []any{0.5, 2}, attrDouble, false},
+		{"Mixed", []any{"a", 1}, attrString, true},
+		{"List", []any{[]any{"a"}}, attrString, true},
+		{"Map", []any{map[string]any{"k": 1}}, attrString, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ, asJSON := attrType(tt.values)
+			if typ != tt.wantType || asJSON != tt.wantJSON {
+				t.Errorf("attrType = %q, %v; want %q, %v", typ, asJSON, tt.wantType, tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestDecodeAttr(t *testing.T) {
+	tests := []struct {
+		typ    string
+		asJSON bool
+		in     string
+		want   any
+	}{
+		{"long", false, "42", 42},
+		{"integer", false, " 7 ", 7},
+		{"double", false, "0.25", 0.25},
+		{"float", false, "1", 1.0},
+		{"boolean", false, "true", true},
+		{"string", false, "x", "x"},
+		{"liststring", false, "[a|b]", "[a|b]"},
+		{"string", true, `"quoted"`, "quoted"},
+	}
+	for _, tt := range tests {
+		got, err := decodeAttr(tt.typ, tt.asJSON, tt.in)
+		if err != nil {
+			t.Errorf("decodeAttr(%q, %q): %v", tt.typ, tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("decodeAttr(%q, %q) = %#v, want %#v", tt.typ, tt.in, got, tt.want)
+		}
+	}
+}
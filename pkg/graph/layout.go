@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
 )
 
 // =============================================================================
@@ -23,6 +25,15 @@ import (
 //	  - DOT: Graphviz DOT string for rendering
 //	  - Engine: Graphviz layout engine (e.g., "dot")
 //
+//	Sunburst ("sunburst"):
+//	  - Nodes and Edges only; the chart is computed at render time
+//
+//	Treemap ("treemap"):
+//	  - Nodes and Edges only; the map is computed at render time
+//
+//	DSM ("dsm"):
+//	  - Nodes and Edges only; the matrix is computed at render time
+//
 // Shared fields (both types):
 //   - Width, Height: frame dimensions
 //   - Style: visual style ("handdrawn", "simple")
@@ -35,6 +46,9 @@ import (
 // (pkg/core/render/tower/layout.Layout) optimized for computation.
 // Use Export()/Parse() methods to convert between them.
 type Layout struct {
+	// Format version (see SchemaVersion); set by MarshalLayout
+	SchemaVersion int `json:"schema_version,omitempty" bson:"schema_version,omitempty"`
+
 	// Discriminator
 	VizType string `json:"viz_type" bson:"viz_type"`
 
@@ -68,6 +82,15 @@ func (l *Layout) IsTower() bool { return l.VizType == VizTypeTower }
 // IsNodelink returns true if this is a nodelink layout.
 func (l *Layout) IsNodelink() bool { return l.VizType == VizTypeNodelink }
 
+// IsSunburst returns true if this is a sunburst layout.
+func (l *Layout) IsSunburst() bool { return l.VizType == VizTypeSunburst }
+
+// IsTreemap returns true if this is a treemap layout.
+func (l *Layout) IsTreemap() bool { return l.VizType == VizTypeTreemap }
+
+// IsDSM returns true if this is a design structure matrix layout.
+func (l *Layout) IsDSM() bool { return l.VizType == VizTypeDSM }
+
 // =============================================================================
 // Block - Tower Visualization Element
 // =============================================================================
@@ -107,6 +130,8 @@ type BlockMeta struct {
 type NebraskaRanking struct {
 	Maintainer string            `json:"maintainer" bson:"maintainer"`
 	Score      float64           `json:"score" bson:"score"`
+	Share      float64           `json:"share,omitempty" bson:"share,omitempty"` // Fraction of the tower's total score
+	Org        bool              `json:"org,omitempty" bson:"org,omitempty"`     // Maintainer is an organization
 	Packages   []NebraskaPackage `json:"packages" bson:"packages"`
 }
 
@@ -117,22 +142,77 @@ type NebraskaPackage struct {
 	URL     string `json:"url,omitempty" bson:"url,omitempty"`
 }
 
+// =============================================================================
+// Graph Layouts - Sunburst, Treemap, DSM
+// =============================================================================
+
+// ExportGraphLayout creates a serializable layout of vizType that holds the
+// nodes and edges of g.
+//
+// Sunburst, treemap and DSM layouts carry no positions: the chart is
+// recomputed from the graph structure at render time, so the layout holds
+// the nodes and edges needed to do so.
+func ExportGraphLayout(g *dag.DAG, vizType string, width, height float64, style string) Layout {
+	result := Layout{
+		VizType: vizType,
+		Width:   width,
+		Height:  height,
+		Style:   style,
+	}
+	if g != nil {
+		serialized := FromDAG(g)
+		result.Nodes = serialized.Nodes
+		result.Edges = serialized.Edges
+	}
+	return result
+}
+
+// ParseGraphLayout rebuilds the graph stored in a layout written by
+// [ExportGraphLayout].
+//
+// Returns an error if the layout is not of vizType or has no nodes.
+func ParseGraphLayout(layout Layout, vizType string) (*dag.DAG, error) {
+	if layout.VizType != "" && layout.VizType != vizType {
+		return nil, fmt.Errorf("invalid viz_type for %s layout: %q", vizType, layout.VizType)
+	}
+	if len(layout.Nodes) == 0 {
+		return nil, fmt.Errorf("%s layout must contain nodes", vizType)
+	}
+	return ToDAG(Graph{Nodes: layout.Nodes, Edges: layout.Edges})
+}
+
 // =============================================================================
 // Layout Serialization API
 // =============================================================================
 
-// MarshalLayout serializes a Layout to pretty-printed JSON bytes.
+// MarshalLayout serializes a Layout to pretty-printed JSON bytes, stamped
+// with the current SchemaVersion.
 func MarshalLayout(l Layout) ([]byte, error) {
+	l.SchemaVersion = SchemaVersion
 	return json.MarshalIndent(l, "", "  ")
 }
 
-// UnmarshalLayout deserializes JSON bytes into a Layout.
-// Validates that required fields are present for the viz type.
+// UnmarshalLayout deserializes JSON bytes into a Layout, migrating layouts
+// written by older versions (see Migrate). Validates that required fields
+// are present for the viz type.
 func UnmarshalLayout(data []byte) (Layout, error) {
 	var l Layout
 	if err := json.Unmarshal(data, &l); err != nil {
 		return Layout{}, fmt.Errorf("unmarshal layout: %w", err)
 	}
+	if l.SchemaVersion < SchemaVersion {
+		migrated, err := Migrate(data)
+		if err != nil {
+			return Layout{}, fmt.Errorf("migrate layout: %w", err)
+		}
+		l = Layout{}
+		if err := json.Unmarshal(migrated, &l); err != nil {
+			return Layout{}, fmt.Errorf("unmarshal layout: %w", err)
+		}
+	}
+	if err := checkSchemaVersion(l.SchemaVersion); err != nil {
+		return Layout{}, err
+	}
 
 	if l.VizType == "" {
 		l.VizType = VizTypeTower
@@ -144,6 +224,15 @@ func UnmarshalLayout(data []byte) (Layout, error) {
 	if l.IsNodelink() && l.DOT == "" {
 		return Layout{}, fmt.Errorf("nodelink layout must contain DOT string")
 	}
+	if l.IsSunburst() && len(l.Nodes) == 0 {
+		return Layout{}, fmt.Errorf("sunburst layout must contain nodes")
+	}
+	if l.IsTreemap() && len(l.Nodes) == 0 {
+		return Layout{}, fmt.Errorf("treemap layout must contain nodes")
+	}
+	if l.IsDSM() && len(l.Nodes) == 0 {
+		return Layout{}, fmt.Errorf("dsm layout must contain nodes")
+	}
 
 	return l, nil
 }
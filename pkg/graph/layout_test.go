@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestGraphLayout(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app"})
+	g.AddNode(dag.Node{ID: "lib", Meta: dag.Metadata{"version": "1.2.0"}})
+	g.AddNode(dag.Node{ID: "util"})
+	g.AddEdge(dag.Edge{From: "app", To: "lib"})
+	g.AddEdge(dag.Edge{From: "lib", To: "util"})
+
+	exported := ExportGraphLayout(g, VizTypeTreemap, 800, 600, "simple")
+	if exported.VizType != VizTypeTreemap || exported.Width != 800 || exported.Height != 600 || exported.Style != "simple" {
+		t.Fatalf("ExportGraphLayout() = %+v", exported)
+	}
+
+	tests := []struct {
+		name      string
+		layout    Layout
+		vizType   string
+		wantNodes int
+		wantEdges int
+		wantErr   string
+	}{
+		{
+			name:      "RoundTrip",
+			layout:    exported,
+			vizType:   VizTypeTreemap,
+			wantNodes: 3,
+			wantEdges: 2,
+		},
+		{
+			name:    "NilGraph",
+			layout:  ExportGraphLayout(nil, VizTypeSunburst, 800, 600, ""),
+			vizType: VizTypeSunburst,
+			wantErr: "sunburst layout must contain nodes",
+		},
+		{
+			name:    "WrongVizType",
+			layout:  ExportGraphLayout(g, VizTypeTower, 800, 600, ""),
+			vizType: VizTypeDSM,
+			wantErr: `invalid viz_type for dsm layout: "tower"`,
+		},
+		{
+			name:      "EmptyVizType",
+			layout:    Layout{Nodes: exported.Nodes, Edges: exported.Edges},
+			vizType:   VizTypeDSM,
+			wantNodes: 3,
+			wantEdges: 2,
+		},
+		{
+			name:    "NoNodes",
+			layout:  Layout{VizType: VizTypeDSM},
+			vizType: VizTypeDSM,
+			wantErr: "dsm layout must contain nodes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGraphLayout(tt.layout, tt.vizType)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseGraphLayout() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseGraphLayout() error: %v", err)
+			}
+			if got.NodeCount() != tt.wantNodes || got.EdgeCount() != tt.wantEdges {
+				t.Errorf("ParseGraphLayout() = %d nodes, %d edges; want %d, %d",
+					got.NodeCount(), got.EdgeCount(), tt.wantNodes, tt.wantEdges)
+			}
+			if n, ok := got.Node("lib"); !ok || n.Meta["version"] != "1.2.0" {
+				t.Error("ParseGraphLayout() dropped node metadata")
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package graph
+
+import (
+	"cmp"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+)
+
+// =============================================================================
+// Typed Node Metadata
+// =============================================================================
+
+// Node metadata stays a flat JSON object on the wire, but its keys fall into
+// namespaces with fixed types, declared in the graph schema:
+//
+//   - repo: repository data from metadata providers (repo_stars, ...)
+//   - metrics: numeric measures (downloads, install_size, health_score, ...)
+//   - security: vulnerabilities and licensing (vuln_severity, license, ...)
+//   - custom: keys starting with [CustomPrefix], reserved for users and
+//     other tools and never interpreted by stacktower
+//
+// Remaining keys carry registry data such as version and description. The
+// accessors below read each namespace into a struct, tolerating the numeric
+// types a JSON round trip produces, so consumers need not type-assert map
+// values.
+
+// CustomPrefix starts metadata keys holding custom data. Such keys are
+// passed through unchanged and will never collide with well-known keys.
+const CustomPrefix = "x-"
+
+// RepoMeta is the repo namespace of node metadata.
+type RepoMeta struct {
+	URL           string   // repo_url
+	Owner         string   // repo_owner
+	Description   string   // repo_description
+	Stars         int      // repo_stars
+	Archived      bool     // repo_archived
+	Language      string   // repo_language
+	Topics        []string // repo_topics
+	Maintainers   []string // repo_maintainers
+	Contributions []int    // repo_contributions, parallel to Maintainers
+	LastCommit    string   // repo_last_commit (YYYY-MM-DD)
+	LastRelease   string   // repo_last_release (YYYY-MM-DD)
+	Created       string   // repo_created (YYYY-MM-DD)
+	License       string   // repo_license
+	Successor     string   // repo_successor
+}
+
+// MetricsMeta is the metrics namespace of node metadata. Pointer fields
+// are nil when the metric is absent, since zero is a meaningful value.
+type MetricsMeta struct {
+	Downloads      int      // downloads
+	InstallSize    int64    // install_size, in bytes
+	LatestVersion  string   // latest_version
+	VersionsBehind *int     // versions_behind
+	HealthScore    *float64 // health_score (0–100)
+	Scorecard      *float64 // scorecard (0–10)
+}
+
+// SecurityMeta is the security namespace of node metadata.
+type SecurityMeta struct {
+	VulnSeverity string        // vuln_severity
+	Findings     []VulnFinding // vuln_findings
+	License      string        // license
+	LicenseRisk  string        // license_risk
+	LicenseText  string        // license_text
+}
+
+// VulnFinding is one advisory listed under vuln_findings.
+type VulnFinding struct {
+	ID       string
+	Severity string
+	Summary  string
+	Fixed    string // First fixed version, empty when none is known
+}
+
+// Security metadata keys, matching the security package's Meta* constants
+// (which cannot be imported here without a cycle).
+const (
+	metaVulnSeverity = "vuln_severity"
+	metaVulnFindings = "vuln_findings"
+	metaLicense      = "license"
+	metaLicenseRisk  = "license_risk"
+	metaLicenseText  = "license_text"
+)
+
+// RepoOf reads the repo namespace of node metadata.
+func RepoOf(meta map[string]any) RepoMeta {
+	return RepoMeta{
+		URL:           metaString(meta, metadata.RepoURL),
+		Owner:         metaString(meta, metadata.RepoOwner),
+		Description:   metaString(meta, metadata.RepoDescription),
+		Stars:         int(metaInt(meta, metadata.RepoStars)),
+		Archived:      metaBool(meta, metadata.RepoArchived),
+		Language:      metaString(meta, metadata.RepoLanguage),
+		Topics:        metaStrings(meta, metadata.RepoTopics),
+		Maintainers:   metaStrings(meta, metadata.RepoMaintainers),
+		Contributions: metaInts(meta, metadata.RepoContributions),
+		LastCommit:    metaString(meta, metadata.RepoLastCommit),
+		LastRelease:   metaString(meta, metadata.RepoLastRelease),
+		Created:       metaString(meta, metadata.RepoCreated),
+		License:       metaString(meta, metadata.RepoLicense),
+		Successor:     metaString(meta, metadata.RepoSuccessor),
+	}
+}
+
+// MetricsOf reads the metrics namespace of node metadata.
+func MetricsOf(meta map[string]any) MetricsMeta {
+	m := MetricsMeta{
+		Downloads:     int(metaInt(meta, "downloads")),
+		InstallSize:   metaInt(meta, deps.MetaInstallSize),
+		LatestVersion: metaString(meta, deps.MetaLatestVersion),
+	}
+	if _, ok := meta[deps.MetaVersionsBehind]; ok {
+		n := int(metaInt(meta, deps.MetaVersionsBehind))
+		m.VersionsBehind = &n
+	}
+	m.HealthScore = metaFloat(meta, feature.MetaHealthScore)
+	m.Scorecard = metaFloat(meta, metadata.Scorecard)
+	return m
+}
+
+// SecurityOf reads the security namespace of node metadata.
+func SecurityOf(meta map[string]any) SecurityMeta {
+	s := SecurityMeta{
+		VulnSeverity: metaString(meta, metaVulnSeverity),
+		License:      metaString(meta, metaLicense),
+		LicenseRisk:  metaString(meta, metaLicenseRisk),
+		LicenseText:  metaString(meta, metaLicenseText),
+	}
+	findings, _ := meta[metaVulnFindings].([]any)
+	for _, f := range findings {
+		fm, ok := f.(map[string]any)
+		if !ok {
+			continue
+		}
+		s.Findings = append(s.Findings, VulnFinding{
+			ID:       metaString(fm, "id"),
+			Severity: metaString(fm, "severity"),
+			Summary:  metaString(fm, "summary"),
+			Fixed:    metaString(fm, "fixed"),
+		})
+	}
+	return s
+}
+
+// CustomOf returns the custom metadata, keyed without [CustomPrefix], or
+// nil when there is none.
+func CustomOf(meta map[string]any) map[string]any {
+	var out map[string]any
+	for k, v := range meta {
+		if name, ok := strings.CutPrefix(k, CustomPrefix); ok {
+			if out == nil {
+				out = make(map[string]any)
+			}
+			out[name] = v
+		}
+	}
+	return out
+}
+
+// SetCustom stores custom metadata under key, adding [CustomPrefix] if key
+// lacks it.
+func SetCustom(meta map[string]any, key string, v any) {
+	if !strings.HasPrefix(key, CustomPrefix) {
+		key = CustomPrefix + key
+	}
+	meta[key] = v
+}
+
+// Repo returns the node's repo metadata.
+func (n *Node) Repo() RepoMeta { return RepoOf(n.Meta) }
+
+// Metrics returns the node's metrics metadata.
+func (n *Node) Metrics() MetricsMeta { return MetricsOf(n.Meta) }
+
+// Security returns the node's security metadata, falling back to the
+// license and severity fields promoted to the node itself.
+func (n *Node) Security() SecurityMeta {
+	s := SecurityOf(n.Meta)
+	s.VulnSeverity = cmp.Or(s.VulnSeverity, n.VulnSeverity)
+	s.License = cmp.Or(s.License, n.License)
+	s.LicenseRisk = cmp.Or(s.LicenseRisk, n.LicenseRisk)
+	s.LicenseText = cmp.Or(s.LicenseText, n.LicenseText)
+	return s
+}
+
+// Custom returns the node's custom metadata (see [CustomOf]).
+func (n *Node) Custom() map[string]any { return CustomOf(n.Meta) }
+
+func metaString(meta map[string]any, key string) string {
+	s, _ := meta[key].(string)
+	return s
+}
+
+func metaBool(meta map[string]any, key string) bool {
+	b, _ := meta[key].(bool)
+	return b
+}
+
+func metaInt(meta map[string]any, key string) int64 {
+	return asInt64(meta[key])
+}
+
+// asInt64 reads an integer stored natively or decoded from JSON.
+func asInt64(v any) int64 {
+	switch v := v.(type) {
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// metaFloat reads a number, returning nil when key is absent or not a number.
+func metaFloat(meta map[string]any, key string) *float64 {
+	var f float64
+	switch v := meta[key].(type) {
+	case float64:
+		f = v
+	case int:
+		f = float64(v)
+	case int64:
+		f = float64(v)
+	default:
+		return nil
+	}
+	return &f
+}
+
+func metaStrings(meta map[string]any, key string) []string {
+	switch v := meta[key].(type) {
+	case []string:
+		return v
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func metaInts(meta map[string]any, key string) []int {
+	switch v := meta[key].(type) {
+	case []int:
+		return v
+	case []any:
+		out := make([]int, 0, len(v))
+		for _, item := range v {
+			out = append(out, int(asInt64(item)))
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNode_TypedMeta(t *testing.T) {
+	// Shaped like a node read back from JSON: numbers are float64 and lists
+	// are []any.
+	n := Node{
+		ID:      "requests",
+		License: "Apache-2.0",
+		Meta: map[string]any{
+			"repo_stars":         52000.0,
+			"repo_archived":      false,
+			"repo_maintainers":   []any{"alice", "bob"},
+			"repo_contributions": []any{120.0, 30.0},
+			"repo_last_commit":   "2024-05-01",
+			"downloads":          1000000.0,
+			"versions_behind":    0.0,
+			"scorecard":          7.5,
+			"vuln_severity":      "high",
+			"vuln_findings": []any{
+				map[string]any{"id": "GHSA-1", "severity": "high", "summary": "leak", "fixed": "2.32.0"},
+			},
+			"x-team":  "platform",
+			"version": "2.31.0",
+		},
+	}
+
+	repo := n.Repo()
+	want := RepoMeta{
+		Stars:         52000,
+		Maintainers:   []string{"alice", "bob"},
+		Contributions: []int{120, 30},
+		LastCommit:    "2024-05-01",
+	}
+	if !reflect.DeepEqual(repo, want) {
+		t.Errorf("Repo() = %+v, want %+v", repo, want)
+	}
+
+	m := n.Metrics()
+	if m.Downloads != 1000000 || m.VersionsBehind == nil || *m.VersionsBehind != 0 {
+		t.Errorf("Metrics() = %+v, want 1000000 downloads, 0 versions behind", m)
+	}
+	if m.Scorecard == nil || *m.Scorecard != 7.5 || m.HealthScore != nil {
+		t.Errorf("Metrics() scores = %v, %v; want scorecard 7.5 and no health score", m.Scorecard, m.HealthScore)
+	}
+
+	s := n.Security()
+	if s.VulnSeverity != "high" || s.License != "Apache-2.0" {
+		t.Errorf("Security() = %+v, want high severity and the promoted license", s)
+	}
+	if want := []VulnFinding{{ID: "GHSA-1", Severity: "high", Summary: "leak", Fixed: "2.32.0"}}; !reflect.DeepEqual(s.Findings, want) {
+		t.Errorf("Security().Findings = %+v, want %+v", s.Findings, want)
+	}
+
+	if got := n.Custom(); !reflect.DeepEqual(got, map[string]any{"team": "platform"}) {
+		t.Errorf("Custom() = %v, want team", got)
+	}
+}
+
+func TestTypedMeta_Empty(t *testing.T) {
+	var n Node
+	if r := n.Repo(); !reflect.DeepEqual(r, RepoMeta{}) {
+		t.Errorf("Repo() of empty node = %+v", r)
+	}
+	if m := n.Metrics(); m.VersionsBehind != nil || m.Scorecard != nil {
+		t.Errorf("Metrics() of empty node = %+v", m)
+	}
+	if c := n.Custom(); c != nil {
+		t.Errorf("Custom() of empty node = %v, want nil", c)
+	}
+}
+
+func TestSetCustom(t *testing.T) {
+	meta := map[string]any{}
+	SetCustom(meta, "owner", "payments")
+	SetCustom(meta, "x-tier", 1)
+	if !reflect.DeepEqual(meta, map[string]any{"x-owner": "payments", "x-tier": 1}) {
+		t.Errorf("meta = %v", meta)
+	}
+}
+
+func TestValidate_MetaTypes(t *testing.T) {
+	doc := `{"nodes": [{"id": "a", "meta": {"repo_stars": "many", "x-stars": "many", "downloads": -1}}]}`
+	err := Validate([]byte(doc))
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	msg := err.Error()
+	for _, want := range []string{"/nodes/0/meta/repo_stars", "/nodes/0/meta/downloads"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("errors %q missing %s", msg, want)
+		}
+	}
+	if strings.Contains(msg, "x-stars") {
+		t.Errorf("custom key validated: %q", msg)
+	}
+}
@@ -0,0 +1,248 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Schema Migration
+// =============================================================================
+
+// migration upgrades documents of schema version from to from+1. Each part
+// is optional: key renames top-level keys, doc edits the top-level object,
+// and node and edge edit each element of the nodes and edges arrays. Nodes
+// and edges are migrated one at a time so [ReadGraph] can apply them while
+// streaming.
+type migration struct {
+	from int
+	key  func(string) string
+	doc  func(map[string]any)
+	node func(map[string]any)
+	edge func(map[string]any)
+}
+
+// migrations lists the upgrade steps in order. Version 0 stands for
+// documents without a schema_version: graphs written before versioning and
+// node-link JSON from other tools.
+//
+// To change the format incompatibly, bump SchemaVersion, update the
+// schemas, and append a step from the previous version here.
+var migrations = []migration{
+	{
+		from: 0,
+		key:  migrateKeyV0,
+		doc:  migrateDocV0,
+		node: migrateNodeV0,
+		edge: migrateEdgeV0,
+	},
+}
+
+// Migrate upgrades a graph or layout JSON document written by an older
+// version of stacktower, or a node-link document from another tool, to
+// the current [SchemaVersion], so cached graphs and pipelines keep working
+// as the format evolves. Current documents are returned unchanged;
+// documents from a newer version fail with [ErrNewerSchema].
+//
+// Unversioned documents are normalized: keys are matched regardless of
+// case and spelling (ID, MasterID), "links" with "source" and "target"
+// (as written by D3 and NetworkX) become "edges" with "from" and "to",
+// nodes given only a "name" use it as their id, null arrays become empty,
+// and layouts without a viz_type are towers.
+func Migrate(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+	version, err := docVersion(doc)
+	if err != nil {
+		return nil, err
+	}
+	if version == SchemaVersion {
+		return data, nil
+	}
+
+	for _, m := range migrations[version:] {
+		if m.key != nil {
+			renamed := make(map[string]any, len(doc))
+			for k, v := range doc {
+				renamed[m.key(k)] = v
+			}
+			doc = renamed
+		}
+		if m.doc != nil {
+			m.doc(doc)
+		}
+		for field, fn := range map[string]func(map[string]any){"nodes": m.node, "edges": m.edge} {
+			items, _ := doc[field].([]any)
+			for _, item := range items {
+				if obj, ok := item.(map[string]any); ok && fn != nil {
+					fn(obj)
+				}
+			}
+		}
+	}
+	doc["schema_version"] = SchemaVersion
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// docVersion returns a document's schema_version, 0 when absent.
+func docVersion(doc map[string]any) (int, error) {
+	raw, ok := doc["schema_version"]
+	if !ok {
+		return 0, nil
+	}
+	n, ok := raw.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("schema_version must be a number, got %v", raw)
+	}
+	v, err := n.Int64()
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid schema_version %s", n)
+	}
+	if err := checkSchemaVersion(int(v)); err != nil {
+		return 0, err
+	}
+	return int(v), nil
+}
+
+// migrateElement upgrades one node or edge of a version document to the
+// current version. field is "nodes" or "edges".
+func migrateElement(version int, field string, obj map[string]any) {
+	for _, m := range migrations[version:] {
+		fn := m.node
+		if field == "edges" {
+			fn = m.edge
+		}
+		if fn != nil {
+			fn(obj)
+		}
+	}
+}
+
+// migrateKey upgrades a top-level key of a version document.
+func migrateKey(version int, key string) string {
+	for _, m := range migrations[version:] {
+		if m.key != nil {
+			key = m.key(key)
+		}
+	}
+	return key
+}
+
+// Canonical keys by their folded spelling, for version 0 documents whose
+// keys may follow Go field names ("MasterID") rather than the JSON tags.
+var (
+	topKeys  = foldedKeys(reflect.TypeFor[Layout](), reflect.TypeFor[Graph]())
+	nodeKeys = foldedKeys(reflect.TypeFor[Node]())
+	edgeKeys = foldedKeys(reflect.TypeFor[Edge]())
+)
+
+// foldedKeys maps the folded JSON name and Go name of every field of the
+// given struct types to the JSON name.
+func foldedKeys(types ...reflect.Type) map[string]string {
+	keys := make(map[string]string)
+	for _, t := range types {
+		for i := range t.NumField() {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			keys[foldKey(name)] = name
+			keys[foldKey(f.Name)] = name
+		}
+	}
+	return keys
+}
+
+// foldKey folds case and drops separators: "master_id", "MasterID" and
+// "masterId" all fold to "masterid".
+func foldKey(k string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(k))
+}
+
+// canonicalKeys renames the keys of obj to their canonical spelling,
+// keeping an existing canonical key over a differently spelled duplicate.
+func canonicalKeys(obj map[string]any, canon map[string]string) {
+	for k, v := range obj {
+		name, ok := canon[foldKey(k)]
+		if !ok || name == k {
+			continue
+		}
+		delete(obj, k)
+		if _, exists := obj[name]; !exists {
+			obj[name] = v
+		}
+	}
+}
+
+// renameKey moves obj[from] to obj[to] unless to is already set.
+func renameKey(obj map[string]any, from, to string) {
+	if v, ok := obj[from]; ok {
+		delete(obj, from)
+		if _, exists := obj[to]; !exists {
+			obj[to] = v
+		}
+	}
+}
+
+func migrateKeyV0(k string) string {
+	if foldKey(k) == "links" {
+		return "edges"
+	}
+	if name, ok := topKeys[foldKey(k)]; ok {
+		return name
+	}
+	return k
+}
+
+func migrateDocV0(doc map[string]any) {
+	for _, field := range []string{"nodes", "edges"} {
+		if v, ok := doc[field]; ok && v == nil {
+			doc[field] = []any{}
+		}
+	}
+	_, isLayout := doc["blocks"]
+	if _, hasDOT := doc["dot"]; hasDOT {
+		isLayout = true
+	}
+	if _, ok := doc["viz_type"]; isLayout && !ok {
+		doc["viz_type"] = VizTypeTower
+	}
+}
+
+func migrateNodeV0(n map[string]any) {
+	canonicalKeys(n, nodeKeys)
+	if _, ok := n["id"]; !ok {
+		renameKey(n, "name", "id")
+	}
+	n["id"] = idString(n["id"])
+	if kind, ok := n["kind"].(string); ok {
+		n["kind"] = strings.ToLower(kind)
+	}
+}
+
+func migrateEdgeV0(e map[string]any) {
+	renameKey(e, "source", "from")
+	renameKey(e, "target", "to")
+	canonicalKeys(e, edgeKeys)
+	e["from"], e["to"] = idString(e["from"]), idString(e["to"])
+}
+
+// idString converts numeric node IDs, which other tools write, to strings.
+func idString(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return v
+}
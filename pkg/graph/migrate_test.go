@@ -0,0 +1,132 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{
+			name: "GoFieldNames",
+			doc:  `{"Nodes":[{"ID":"a","Row":1,"Kind":"Subdivider","MasterID":"b"}],"Edges":[{"From":"a","To":"b"}]}`,
+			want: `{"edges":[{"from":"a","to":"b"}],"nodes":[{"id":"a","kind":"subdivider","master_id":"b","row":1}],"schema_version":1}`,
+		},
+		{
+			name: "NodeLink",
+			doc:  `{"directed":true,"nodes":[{"id":1},{"id":2}],"links":[{"source":1,"target":2,"weight":3}]}`,
+			want: `{"directed":true,"edges":[{"from":"1","to":"2","weight":3}],"nodes":[{"id":"1"},{"id":"2"}],"schema_version":1}`,
+		},
+		{
+			name: "NameAsID",
+			doc:  `{"nodes":[{"name":"a"},{"id":"b","name":"B"}],"edges":null}`,
+			want: `{"edges":[],"nodes":[{"id":"a"},{"id":"b","name":"B"}],"schema_version":1}`,
+		},
+		{
+			name: "LayoutWithoutVizType",
+			doc:  `{"width":100,"height":50,"blocks":[{"id":"a","x":0,"y":0,"width":10,"height":10}]}`,
+			want: `{"blocks":[{"height":10,"id":"a","width":10,"x":0,"y":0}],"height":50,"schema_version":1,"viz_type":"tower","width":100}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Migrate([]byte(tt.doc))
+			if err != nil {
+				t.Fatalf("Migrate: %v", err)
+			}
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, got); err != nil {
+				t.Fatalf("Migrate output is not JSON: %v", err)
+			}
+			if compact.String() != tt.want {
+				t.Errorf("Migrate =\n%s\nwant\n%s", compact.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrate_Current(t *testing.T) {
+	doc := []byte(`{"schema_version": 1, "nodes": [{"id": "a"}], "edges": []}`)
+	got, err := Migrate(doc)
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if !bytes.Equal(got, doc) {
+		t.Errorf("Migrate changed a current document:\n%s", got)
+	}
+}
+
+func TestMigrate_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want error
+	}{
+		{"Newer", `{"schema_version": 99, "nodes": []}`, ErrNewerSchema},
+		{"NotObject", `[1, 2]`, nil},
+		{"BadVersion", `{"schema_version": "one"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Migrate([]byte(tt.doc))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrate_Validates(t *testing.T) {
+	doc := `{"Nodes":[{"name":"app"},{"ID":"lib","MasterID":""}],"links":[{"source":"app","target":"lib"}]}`
+	migrated, err := Migrate([]byte(doc))
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if err := Validate(migrated); err != nil {
+		t.Errorf("Validate(migrated): %v", err)
+	}
+}
+
+func TestReadGraph_Migrates(t *testing.T) {
+	doc := `{
+		"links": [{"source": 1, "target": 2, "Constraint": ">=2"}],
+		"nodes": [{"id": 1, "Meta": {"version": "1.0"}}, {"name": 2}]
+	}`
+	g, err := ReadGraph(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("ReadGraph: %v", err)
+	}
+	if !reflect.DeepEqual(g.Children("1"), []string{"2"}) {
+		t.Errorf("children of 1 = %v, want [2]", g.Children("1"))
+	}
+	if n, _ := g.Node("1"); n.Meta["version"] != "1.0" {
+		t.Errorf("node 1 meta = %v, want version 1.0", n.Meta)
+	}
+	if e := g.Edges()[0]; !reflect.DeepEqual(e.Meta, dag.Metadata{"constraint": ">=2"}) {
+		t.Errorf("edge meta = %v, want constraint >=2", e.Meta)
+	}
+}
+
+func TestUnmarshalLayout_Migrates(t *testing.T) {
+	l, err := UnmarshalLayout([]byte(`{"Width":100,"Height":50,"blocks":[{"id":"a","width":10,"height":10}]}`))
+	if err != nil {
+		t.Fatalf("UnmarshalLayout: %v", err)
+	}
+	if l.VizType != VizTypeTower || l.Width != 100 || l.SchemaVersion != SchemaVersion {
+		t.Errorf("layout = viz %q width %v version %d; want tower, 100, %d",
+			l.VizType, l.Width, l.SchemaVersion, SchemaVersion)
+	}
+}
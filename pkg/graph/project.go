@@ -0,0 +1,227 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
+)
+
+// =============================================================================
+// Partial Export
+// =============================================================================
+
+// ExportOptions selects what part of a graph [ExportJSON] writes. The zero
+// value exports everything, exactly as [WriteGraph] does.
+type ExportOptions struct {
+	// MetaKeys, when non-empty, keeps only these node metadata keys. The
+	// fields promoted from metadata (url, license, vuln_severity, ...)
+	// follow their keys, so leaving out repo_url also drops url.
+	MetaKeys []string
+
+	// StripHeavy drops bulky metadata a viewer rarely needs: license
+	// texts, advisory lists, embedded icons, descriptions, topics and
+	// contribution counts.
+	StripHeavy bool
+
+	// StripURLs drops repository, homepage and icon URLs, for publishing
+	// graphs of internal code without revealing where it lives.
+	StripURLs bool
+
+	// ExcludeSynthetic drops subdivider and auxiliary nodes. Edges through
+	// subdividers are reconnected to the node they stand in for, so the
+	// result is the graph before layout transforms.
+	ExcludeSynthetic bool
+
+	// Compact writes JSON without indentation.
+	Compact bool
+
+	// Anonymize renames nodes to pkg1, pkg2, ... in graph order and drops
+	// everything that names a package or the people behind it: display
+	// labels of nodes and edges, URLs, descriptions, authors, owners,
+	// maintainers, package URLs and lists of other packages. Versions, licenses, counts and
+	// scores stay, so a graph of internal code can be shared, for example
+	// in a bug report, with its shape intact. Implies StripHeavy and
+	// StripURLs.
+	Anonymize bool
+
+	// Canonical orders edges by source and then target node, rather than
+	// keeping each node's dependencies in the order they were resolved, and
+	// writes characters such as < and > unescaped. Combined with the fixed
+	// node and key order of all output, equal graphs always export to equal
+	// bytes, so graphs committed to version control diff minimally.
+	Canonical bool
+}
+
+// heavyMetaKeys are the keys dropped by ExportOptions.StripHeavy.
+var heavyMetaKeys = []string{
+	metaLicenseText,
+	metaVulnFindings,
+	metadata.Icon,
+	metadata.RepoDescription,
+	metadata.RepoTopics,
+	metadata.RepoContributions,
+	"description",
+	metadata.Summary,
+}
+
+// urlMetaKeys are the keys dropped by ExportOptions.StripURLs.
+var urlMetaKeys = []string{
+	metadata.RepoURL,
+	metadata.RepoSuccessor,
+	metadata.HomePage,
+	metadata.IconURL,
+}
+
+// identifyingMetaKeys are the keys dropped by ExportOptions.Anonymize, on
+// top of the heavy and URL keys.
+var identifyingMetaKeys = []string{
+	metadata.RepoOwner,
+	metadata.RepoMaintainers,
+	"author",
+	"purl",
+	"commit",
+	"bundled",
+	"collapsed",
+	"replacement",
+}
+
+// identifyingGraphMetaKeys are the graph-level keys dropped by
+// ExportOptions.Anonymize.
+var identifyingGraphMetaKeys = []string{"exclude", "internal"}
+
+// ExportJSON writes g as graph JSON, keeping only what opts selects. Use it
+// to publish graphs without internal metadata or to shrink payloads for a
+// web frontend; the output reads back with [ReadGraph] like any graph.
+func ExportJSON(g *dag.DAG, w io.Writer, opts ExportOptions) error {
+	if len(opts.MetaKeys) > 0 || opts.StripHeavy || opts.StripURLs || opts.ExcludeSynthetic || opts.Anonymize {
+		g = Project(g, opts)
+	}
+	return writeGraphTo(g, w, opts)
+}
+
+// Project returns a copy of g reduced as opts selects, for exporting to
+// formats other than JSON. The formatting options Compact and Canonical are
+// ignored. g is not modified.
+func Project(g *dag.DAG, opts ExportOptions) *dag.DAG {
+	out := dag.New(nil)
+	for k, v := range g.Meta() {
+		out.Meta()[k] = v
+	}
+	for _, n := range g.Nodes() {
+		if opts.ExcludeSynthetic && n.IsSynthetic() {
+			continue
+		}
+		p := *n
+		p.Meta = projectMeta(n.Meta, opts)
+		_ = out.AddNode(p)
+	}
+
+	for _, e := range g.Edges() {
+		if _, ok := out.Node(e.From); !ok {
+			continue
+		}
+		if _, ok := out.Node(e.To); ok {
+			_ = out.AddEdge(e)
+			continue
+		}
+		for _, to := range realTargets(g, e.To) {
+			if !slices.Contains(out.Children(e.From), to) {
+				_ = out.AddEdge(dag.Edge{From: e.From, To: to, Meta: e.Meta})
+			}
+		}
+	}
+	if opts.Anonymize {
+		return anonymize(out)
+	}
+	return out
+}
+
+// anonymize returns a copy of g with every node renamed: regular nodes to
+// pkg1, pkg2, ... and synthetic nodes to syn1, syn2, ..., in graph order.
+// Metadata is expected to be stripped already.
+func anonymize(g *dag.DAG) *dag.DAG {
+	names := make(map[string]string, g.NodeCount())
+	var regular, synthetic int
+	for _, n := range g.Nodes() {
+		if n.IsSynthetic() {
+			synthetic++
+			names[n.ID] = fmt.Sprintf("syn%d", synthetic)
+		} else {
+			regular++
+			names[n.ID] = fmt.Sprintf("pkg%d", regular)
+		}
+	}
+
+	out := dag.New(nil)
+	for k, v := range g.Meta() {
+		if !slices.Contains(identifyingGraphMetaKeys, k) {
+			out.Meta()[k] = v
+		}
+	}
+	for _, n := range g.Nodes() {
+		a := *n
+		a.ID = names[n.ID]
+		if n.MasterID != "" {
+			a.MasterID = names[n.MasterID]
+		}
+		_ = out.AddNode(a)
+	}
+	for _, e := range g.Edges() {
+		meta := e.Meta
+		if _, ok := meta[dag.EdgeMetaLabel]; ok {
+			meta = maps.Clone(meta)
+			delete(meta, dag.EdgeMetaLabel)
+		}
+		_ = out.AddEdge(dag.Edge{From: names[e.From], To: names[e.To], Meta: meta})
+	}
+	return out
+}
+
+// realTargets follows subdivider chains from id to the regular nodes they
+// lead to. Auxiliary nodes are dead ends.
+func realTargets(g *dag.DAG, id string) []string {
+	n, ok := g.Node(id)
+	if !ok {
+		return nil
+	}
+	if !n.IsSynthetic() {
+		return []string{id}
+	}
+	if n.Kind != dag.NodeKindSubdivider {
+		return nil
+	}
+	var out []string
+	for _, c := range g.Children(id) {
+		out = append(out, realTargets(g, c)...)
+	}
+	return out
+}
+
+// projectMeta returns the metadata opts keeps, including the stored display
+// label unless opts anonymizes.
+func projectMeta(meta dag.Metadata, opts ExportOptions) dag.Metadata {
+	if meta == nil {
+		return nil
+	}
+	out := make(dag.Metadata, len(meta))
+	for k, v := range meta {
+		switch {
+		case opts.Anonymize && (k == metaLabel ||
+			slices.Contains(identifyingMetaKeys, k) ||
+			slices.Contains(heavyMetaKeys, k) ||
+			slices.Contains(urlMetaKeys, k)):
+			continue
+		case k == metaLabel:
+		case len(opts.MetaKeys) > 0 && !slices.Contains(opts.MetaKeys, k),
+			opts.StripHeavy && slices.Contains(heavyMetaKeys, k),
+			opts.StripURLs && slices.Contains(urlMetaKeys, k):
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
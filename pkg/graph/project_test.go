@@ -0,0 +1,197 @@
+package graph
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+// projectionGraph builds app → lib through a subdivider, with an auxiliary
+// separator and metadata of every kind ExportOptions can strip.
+func projectionGraph() *dag.DAG {
+	g := dag.New(nil)
+	g.Meta()["language"] = "python"
+	_ = g.AddNode(dag.Node{ID: "app", Meta: dag.Metadata{
+		metaLabel: "App", "version": "1.0", "repo_url": "https://git.internal/app",
+		"description": "The app", "license_text": "All rights reserved",
+	}})
+	_ = g.AddNode(dag.Node{ID: "lib", Row: 2, Meta: dag.Metadata{"version": "2.0", "homepage": "https://lib.dev"}})
+	_ = g.AddNode(dag.Node{ID: "lib_sub_1", Row: 1, Kind: dag.NodeKindSubdivider, MasterID: "lib"})
+	_ = g.AddNode(dag.Node{ID: "sep", Row: 1, Kind: dag.NodeKindAuxiliary})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "lib_sub_1", Meta: dag.Metadata{"constraint": ">=2"}})
+	_ = g.AddEdge(dag.Edge{From: "lib_sub_1", To: "lib"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "sep"})
+	return g
+}
+
+func TestExportJSON_ZeroOptions(t *testing.T) {
+	g := projectionGraph()
+	var got bytes.Buffer
+	if err := ExportJSON(g, &got, ExportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want, err := MarshalGraph(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("ExportJSON with zero options differs from MarshalGraph:\n%s\nwant\n%s", got.Bytes(), want)
+	}
+}
+
+func TestProject(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     ExportOptions
+		node     string
+		wantMeta dag.Metadata
+	}{
+		{"MetaKeys", ExportOptions{MetaKeys: []string{"version"}}, "app",
+			dag.Metadata{metaLabel: "App", "version": "1.0"}},
+		{"StripHeavy", ExportOptions{StripHeavy: true}, "app",
+			dag.Metadata{metaLabel: "App", "version": "1.0", "repo_url": "https://git.internal/app"}},
+		{"StripURLs", ExportOptions{StripURLs: true}, "lib",
+			dag.Metadata{"version": "2.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := projectionGraph()
+			p := Project(g, tt.opts)
+			n, _ := p.Node(tt.node)
+			if !reflect.DeepEqual(n.Meta, tt.wantMeta) {
+				t.Errorf("meta = %v, want %v", n.Meta, tt.wantMeta)
+			}
+			if orig, _ := g.Node(tt.node); len(orig.Meta) <= len(tt.wantMeta) {
+				t.Errorf("Project modified the input graph: %v", orig.Meta)
+			}
+		})
+	}
+}
+
+func TestProject_ExcludeSynthetic(t *testing.T) {
+	p := Project(projectionGraph(), ExportOptions{ExcludeSynthetic: true})
+	if p.NodeCount() != 2 {
+		t.Errorf("got %d nodes, want app and lib", p.NodeCount())
+	}
+	edges := p.Edges()
+	if len(edges) != 1 || edges[0].From != "app" || edges[0].To != "lib" {
+		t.Fatalf("edges = %v, want app→lib", edges)
+	}
+	if edges[0].Meta["constraint"] != ">=2" {
+		t.Errorf("reconnected edge lost its constraint: %v", edges[0].Meta)
+	}
+}
+
+func TestExportJSON_Compact(t *testing.T) {
+	var buf bytes.Buffer
+	opts := ExportOptions{Compact: true, StripURLs: true}
+	if err := ExportJSON(projectionGraph(), &buf, opts); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.Count(out, "\n") != 1 || !strings.HasPrefix(out, `{"schema_version":1,"meta":{"language":"python"},"nodes":[{`) {
+		t.Errorf("not compact:\n%s", out)
+	}
+	if strings.Contains(out, "internal") || strings.Contains(out, `"url"`) {
+		t.Errorf("URL leaked:\n%s", out)
+	}
+	g, err := ReadGraph(&buf)
+	if err != nil {
+		t.Fatalf("ReadGraph(compact): %v", err)
+	}
+	if g.NodeCount() != 4 {
+		t.Errorf("read back %d nodes, want 4", g.NodeCount())
+	}
+}
+
+func TestExportJSON_Canonical(t *testing.T) {
+	// The same graph built in two orders, as concurrent resolution may do.
+	build := func(edges [][2]string) *dag.DAG {
+		g := dag.New(nil)
+		for _, id := range []string{"c", "a", "b"} {
+			_ = g.AddNode(dag.Node{ID: id})
+		}
+		for _, e := range edges {
+			_ = g.AddEdge(dag.Edge{From: e[0], To: e[1], Meta: dag.Metadata{"constraint": "<3"}})
+		}
+		return g
+	}
+	g1 := build([][2]string{{"a", "c"}, {"a", "b"}, {"b", "c"}})
+	g2 := build([][2]string{{"b", "c"}, {"a", "b"}, {"a", "c"}})
+
+	export := func(g *dag.DAG, opts ExportOptions) string {
+		var buf bytes.Buffer
+		if err := ExportJSON(g, &buf, opts); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	canonical := ExportOptions{Canonical: true}
+	if a, b := export(g1, canonical), export(g2, canonical); a != b {
+		t.Errorf("canonical output depends on build order:\n%s\n%s", a, b)
+	}
+	out := export(g1, canonical)
+	if !strings.Contains(out, `"constraint": "<3"`) {
+		t.Errorf("canonical output escapes <:\n%s", out)
+	}
+	if strings.Index(out, `"to": "b"`) > strings.Index(out, `"to": "c"`) {
+		t.Errorf("edges of a not sorted by target:\n%s", out)
+	}
+
+	// Without Canonical, edges are grouped by source but keep their
+	// dependency order within a node.
+	def := export(g2, ExportOptions{})
+	if strings.Index(def, `"from": "a"`) > strings.Index(def, `"from": "b"`) {
+		t.Errorf("edges not grouped by source:\n%s", def)
+	}
+}
+
+func TestProject_Anonymize(t *testing.T) {
+	g := projectionGraph()
+	g.Meta()["exclude"] = []string{"internal-*"}
+	app, _ := g.Node("app")
+	app.Meta["author"] = "Jane Doe"
+	app.Meta["license"] = "MIT"
+	g.RemoveEdge("app", "sep")
+	_ = g.AddEdge(dag.Edge{From: "app", To: "sep", Meta: dag.Metadata{dag.EdgeMetaLabel: "calls app.run", dag.EdgeMetaWeight: 2.0}})
+
+	p := Project(g, ExportOptions{Anonymize: true})
+
+	ids := dag.NodeIDs(p.Nodes())
+	for _, id := range ids {
+		if strings.Contains(id, "app") || strings.Contains(id, "lib") || id == "sep" {
+			t.Errorf("node %q keeps its original name", id)
+		}
+	}
+	if p.NodeCount() != 4 || len(p.Edges()) != 3 {
+		t.Fatalf("anonymizing changed the shape: %d nodes, %d edges", p.NodeCount(), len(p.Edges()))
+	}
+
+	pkg1, ok := p.Node("pkg1")
+	if !ok {
+		t.Fatalf("nodes = %v, want pkg1 for app", ids)
+	}
+	if want := (dag.Metadata{"version": "1.0", "license": "MIT"}); !reflect.DeepEqual(pkg1.Meta, want) {
+		t.Errorf("meta = %v, want %v", pkg1.Meta, want)
+	}
+	sub, _ := p.Node("syn1")
+	if sub.MasterID != "pkg2" {
+		t.Errorf("subdivider master = %q, want pkg2", sub.MasterID)
+	}
+	if !slices.Equal(p.Children("pkg1"), []string{"syn1", "syn2"}) {
+		t.Errorf("children of pkg1 = %v", p.Children("pkg1"))
+	}
+	if e, _ := p.Edge("pkg1", "syn2"); !reflect.DeepEqual(e.Meta, dag.Metadata{dag.EdgeMetaWeight: 2.0}) {
+		t.Errorf("edge meta = %v, want the weight without the label", e.Meta)
+	}
+	if p.Meta()["language"] != "python" {
+		t.Error("graph metadata should be kept")
+	}
+	if _, ok := p.Meta()["exclude"]; ok {
+		t.Error("exclude patterns name packages and should be dropped")
+	}
+}
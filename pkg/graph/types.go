@@ -2,8 +2,8 @@ package graph
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"slices"
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
@@ -17,14 +17,36 @@ import (
 const (
 	VizTypeTower    = "tower"
 	VizTypeNodelink = "nodelink"
+	VizTypeSunburst = "sunburst"
+	VizTypeTreemap  = "treemap"
+	VizTypeDSM      = "dsm"
 )
 
 // Visual styles for rendering.
 const (
 	StyleSimple    = "simple"
 	StyleHanddrawn = "handdrawn"
+	StyleBlueprint = "blueprint"
+	StyleIsometric = "isometric"
 )
 
+// SchemaVersion is the version of the graph and layout JSON formats this
+// package writes, recorded in their schema_version field. It only changes
+// on incompatible format changes; documents without the field are version 1.
+const SchemaVersion = 1
+
+// ErrNewerSchema is returned for documents whose schema_version is newer
+// than [SchemaVersion], written by a later release of stacktower.
+var ErrNewerSchema = errors.New("document uses a newer schema version")
+
+// checkSchemaVersion rejects documents this package cannot read.
+func checkSchemaVersion(v int) error {
+	if v > SchemaVersion {
+		return fmt.Errorf("%w: schema_version %d, this build reads up to %d", ErrNewerSchema, v, SchemaVersion)
+	}
+	return nil
+}
+
 // ProjectRootNodeID is the node ID used for the root of manifest-based graphs.
 const ProjectRootNodeID = "__project__"
 
@@ -51,9 +73,10 @@ const (
 // The format is human-readable and designed for round-trip fidelity:
 // import → transform → export → re-import produces identical results.
 type Graph struct {
-	Meta  map[string]any `json:"meta,omitempty" bson:"meta,omitempty"` // Graph-level metadata (runtime version, dependency scope, etc.)
-	Nodes []Node         `json:"nodes" bson:"nodes"`
-	Edges []Edge         `json:"edges" bson:"edges"`
+	SchemaVersion int            `json:"schema_version,omitempty" bson:"schema_version,omitempty"` // Format version (see SchemaVersion)
+	Meta          map[string]any `json:"meta,omitempty" bson:"meta,omitempty"`                     // Graph-level metadata (runtime version, dependency scope, etc.)
+	Nodes         []Node         `json:"nodes" bson:"nodes"`
+	Edges         []Edge         `json:"edges" bson:"edges"`
 }
 
 // =============================================================================
@@ -95,11 +118,14 @@ func (n *Node) DisplayLabel() string {
 // Edge - Directed Dependency
 // =============================================================================
 
-// Edge represents a directed edge in the dependency graph.
+// Edge represents a directed edge in the dependency graph. Meta holds the
+// remaining edge metadata, such as the well-known kind, weight and label
+// keys (see dag.EdgeMetaKind); the constraint is promoted to its own field.
 type Edge struct {
-	From       string `json:"from" bson:"from"`
-	To         string `json:"to" bson:"to"`
-	Constraint string `json:"constraint,omitempty" bson:"constraint,omitempty"` // Version constraint (e.g., "^4.17.0", ">=2.0")
+	From       string         `json:"from" bson:"from"`
+	To         string         `json:"to" bson:"to"`
+	Constraint string         `json:"constraint,omitempty" bson:"constraint,omitempty"` // Version constraint (e.g., "^4.17.0", ">=2.0")
+	Meta       map[string]any `json:"meta,omitempty" bson:"meta,omitempty"`
 }
 
 // =============================================================================
@@ -107,23 +133,16 @@ type Edge struct {
 // =============================================================================
 
 // FromDAG converts a DAG to its serialization format.
-// Nodes are sorted by ID for deterministic output.
+// Nodes are sorted by ID and edges by source node for deterministic output.
 // Extracts repository URL and computes brittle flag from metadata.
 func FromDAG(g *dag.DAG) Graph {
-	nodes := g.Nodes()
-	slices.SortFunc(nodes, func(a, b *dag.Node) int {
-		if a.ID < b.ID {
-			return -1
-		}
-		if a.ID > b.ID {
-			return 1
-		}
-		return 0
-	})
+	nodes := sortedNodes(g)
+	edges := sortedEdges(g, false)
 
 	out := Graph{
-		Nodes: make([]Node, len(nodes)),
-		Edges: make([]Edge, len(g.EdgesIter())),
+		SchemaVersion: SchemaVersion,
+		Nodes:         make([]Node, len(nodes)),
+		Edges:         make([]Edge, len(edges)),
 	}
 
 	// Include graph-level metadata if present
@@ -138,7 +157,7 @@ func FromDAG(g *dag.DAG) Graph {
 		out.Nodes[i] = nodeFromDAG(n)
 	}
 
-	for i, e := range g.EdgesIter() {
+	for i, e := range edges {
 		out.Edges[i] = edgeFromDAG(&e)
 	}
 
@@ -146,13 +165,19 @@ func FromDAG(g *dag.DAG) Graph {
 }
 
 // edgeFromDAG converts a dag.Edge to a serialization Edge.
-// Extracts constraint from edge metadata if present.
+// Extracts constraint from edge metadata if present and keeps the rest of
+// the metadata in Meta.
 func edgeFromDAG(e *dag.Edge) Edge {
 	edge := Edge{From: e.From, To: e.To}
-	if e.Meta != nil {
-		if constraint, ok := e.Meta["constraint"].(string); ok {
+	for k, v := range e.Meta {
+		if constraint, ok := v.(string); ok && k == dag.EdgeMetaConstraint {
 			edge.Constraint = constraint
+			continue
+		}
+		if edge.Meta == nil {
+			edge.Meta = make(map[string]any, len(e.Meta))
 		}
+		edge.Meta[k] = v
 	}
 	return edge
 }
@@ -162,45 +187,19 @@ func edgeFromDAG(e *dag.Edge) Edge {
 // Label is stored in metadata for round-trip fidelity when non-empty.
 // Constraint is stored in edge metadata for round-trip fidelity when non-empty.
 func ToDAG(gj Graph) (*dag.DAG, error) {
+	if err := checkSchemaVersion(gj.SchemaVersion); err != nil {
+		return nil, err
+	}
 	d := dag.New(nil)
 
 	for _, nj := range gj.Nodes {
-		n := dag.Node{
-			ID:       nj.ID,
-			Row:      nj.Row,
-			Meta:     copyMeta(nj.Meta),
-			Kind:     stringToDAGKind(nj.Kind),
-			MasterID: nj.MasterID,
-		}
-		if n.Meta == nil {
-			n.Meta = dag.Metadata{}
-		}
-		// Store label in metadata for round-trip fidelity
-		if nj.Label != "" {
-			n.Meta[metaLabel] = nj.Label
-		}
-		// Store license data in metadata for round-trip fidelity
-		if nj.License != "" {
-			n.Meta["license"] = nj.License
-		}
-		if nj.LicenseText != "" {
-			n.Meta["license_text"] = nj.LicenseText
-		}
-		if nj.LicenseRisk != "" {
-			n.Meta["license_risk"] = nj.LicenseRisk
-		}
-		if err := d.AddNode(n); err != nil {
+		if err := d.AddNode(nodeToDAG(nj)); err != nil {
 			return nil, fmt.Errorf("add node %s: %w", nj.ID, err)
 		}
 	}
 
 	for _, ej := range gj.Edges {
-		edge := dag.Edge{From: ej.From, To: ej.To}
-		// Store constraint in edge metadata for round-trip fidelity
-		if ej.Constraint != "" {
-			edge.Meta = dag.Metadata{"constraint": ej.Constraint}
-		}
-		if err := d.AddEdge(edge); err != nil {
+		if err := d.AddEdge(edgeToDAG(ej)); err != nil {
 			return nil, fmt.Errorf("add edge %s→%s: %w", ej.From, ej.To, err)
 		}
 	}
@@ -215,6 +214,50 @@ func ToDAG(gj Graph) (*dag.DAG, error) {
 	return d, nil
 }
 
+// nodeToDAG converts a serialization Node to a dag.Node, the inverse of
+// nodeFromDAG.
+func nodeToDAG(nj Node) dag.Node {
+	n := dag.Node{
+		ID:       nj.ID,
+		Row:      nj.Row,
+		Meta:     copyMeta(nj.Meta),
+		Kind:     stringToDAGKind(nj.Kind),
+		MasterID: nj.MasterID,
+	}
+	if n.Meta == nil {
+		n.Meta = dag.Metadata{}
+	}
+	// Store label in metadata for round-trip fidelity
+	if nj.Label != "" {
+		n.Meta[metaLabel] = nj.Label
+	}
+	// Store license data in metadata for round-trip fidelity
+	if nj.License != "" {
+		n.Meta[metaLicense] = nj.License
+	}
+	if nj.LicenseText != "" {
+		n.Meta[metaLicenseText] = nj.LicenseText
+	}
+	if nj.LicenseRisk != "" {
+		n.Meta[metaLicenseRisk] = nj.LicenseRisk
+	}
+	return n
+}
+
+// edgeToDAG converts a serialization Edge to a dag.Edge, the inverse of
+// edgeFromDAG.
+func edgeToDAG(ej Edge) dag.Edge {
+	edge := dag.Edge{From: ej.From, To: ej.To, Meta: copyMeta(ej.Meta)}
+	// Store constraint in edge metadata for round-trip fidelity
+	if ej.Constraint != "" {
+		if edge.Meta == nil {
+			edge.Meta = dag.Metadata{}
+		}
+		edge.Meta[dag.EdgeMetaConstraint] = ej.Constraint
+	}
+	return edge
+}
+
 // copyMeta creates a shallow copy of metadata to avoid mutation.
 func copyMeta(m map[string]any) map[string]any {
 	if m == nil {
@@ -267,20 +310,20 @@ func nodeFromDAG(n *dag.Node) Node {
 			node.Label = label
 		}
 		// Propagate vulnerability severity (key matches security.MetaVulnSeverity)
-		if vs, ok := n.Meta["vuln_severity"].(string); ok {
+		if vs, ok := n.Meta[metaVulnSeverity].(string); ok {
 			node.VulnSeverity = vs
 		}
 		// Propagate license risk (key matches security.MetaLicenseRisk)
-		if lr, ok := n.Meta["license_risk"].(string); ok && lr != "permissive" {
+		if lr, ok := n.Meta[metaLicenseRisk].(string); ok && lr != "permissive" {
 			node.LicenseRisk = lr
 		}
 		// Propagate license identifier (key matches security.MetaLicense)
-		if lic, ok := n.Meta["license"].(string); ok {
+		if lic, ok := n.Meta[metaLicense].(string); ok {
 			node.License = lic
 		}
 		// Propagate license text for custom/non-standard licenses (key matches security.MetaLicenseText)
 		// Only include if license_risk indicates it needs review (proprietary/unknown)
-		if licText, ok := n.Meta["license_text"].(string); ok && licText != "" {
+		if licText, ok := n.Meta[metaLicenseText].(string); ok && licText != "" {
 			// Only include text for non-standard licenses to keep payloads manageable
 			if node.LicenseRisk == "proprietary" || node.LicenseRisk == "unknown" || node.LicenseRisk == "copyleft" {
 				node.LicenseText = licText
@@ -0,0 +1,411 @@
+package graph
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// =============================================================================
+// JSON Schemas and Validation
+// =============================================================================
+
+//go:embed schema/graph.schema.json
+var graphSchema []byte
+
+//go:embed schema/layout.schema.json
+var layoutSchema []byte
+
+// GraphSchema returns the JSON Schema (draft 2020-12) of the graph format
+// at [SchemaVersion].
+func GraphSchema() []byte { return slices.Clone(graphSchema) }
+
+// LayoutSchema returns the JSON Schema (draft 2020-12) of the layout
+// format at [SchemaVersion].
+func LayoutSchema() []byte { return slices.Clone(layoutSchema) }
+
+// ValidationError is one problem found by [Validate]: where it is, as a
+// JSON Pointer such as "/nodes/3/id", and what is wrong there.
+type ValidationError struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Reason
+	}
+	return e.Path + ": " + e.Reason
+}
+
+// ValidationErrors lists every problem found in a document.
+type ValidationErrors []ValidationError
+
+// maxListedErrors caps how many problems Error spells out.
+const maxListedErrors = 10
+
+func (e ValidationErrors) Error() string {
+	lines := make([]string, 0, min(len(e), maxListedErrors)+1)
+	for i, ve := range e {
+		if i == maxListedErrors {
+			lines = append(lines, fmt.Sprintf("and %d more", len(e)-i))
+			break
+		}
+		lines = append(lines, ve.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Validate checks a graph or layout JSON document against its schema, and
+// a graph's edges against its nodes. A document with a viz_type, blocks or
+// dot field is checked as a layout, anything else as a graph.
+//
+// It returns nil for a valid document and [ValidationErrors] otherwise,
+// each error naming the JSON Pointer path of the problem, so a hand-written
+// or generated file can be fixed without guesswork. Unlike [ReadGraph],
+// which ignores unknown fields, Validate reports them: they are usually
+// typos.
+func Validate(data []byte) error {
+	doc, err := decodeForValidation(data)
+	if err != nil {
+		return err
+	}
+	if obj, ok := doc.(map[string]any); ok {
+		for _, key := range []string{"viz_type", "blocks", "dot"} {
+			if _, ok := obj[key]; ok {
+				return validateDoc(doc, layoutSchema, nil)
+			}
+		}
+	}
+	return validateDoc(doc, graphSchema, checkGraphRefs)
+}
+
+// ValidateGraph checks a graph JSON document as [Validate] does.
+func ValidateGraph(data []byte) error {
+	doc, err := decodeForValidation(data)
+	if err != nil {
+		return err
+	}
+	return validateDoc(doc, graphSchema, checkGraphRefs)
+}
+
+// ValidateLayout checks a layout JSON document as [Validate] does.
+func ValidateLayout(data []byte) error {
+	doc, err := decodeForValidation(data)
+	if err != nil {
+		return err
+	}
+	return validateDoc(doc, layoutSchema, nil)
+}
+
+// decodeForValidation parses data, keeping numbers exact so integers can
+// be told from fractions. Syntax errors are reported with their line and
+// column.
+func decodeForValidation(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		var syntax *json.SyntaxError
+		if errors.As(err, &syntax) {
+			line, col := position(data, syntax.Offset)
+			return nil, ValidationErrors{{Reason: fmt.Sprintf("invalid JSON at line %d, column %d: %v", line, col, err)}}
+		}
+		return nil, ValidationErrors{{Reason: "invalid JSON: " + err.Error()}}
+	}
+	return doc, nil
+}
+
+// position converts the offset of a syntax error, which counts the
+// offending byte, to the 1-based line and column of that byte.
+func position(data []byte, offset int64) (line, col int) {
+	at := int(min(max(offset-1, 0), int64(len(data))))
+	before := data[:at]
+	line = bytes.Count(before, []byte{'\n'}) + 1
+	col = at - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
+
+// validateDoc checks doc against schema and then runs the extra checks.
+func validateDoc(doc any, schema []byte, extra func(any) ValidationErrors) error {
+	var root map[string]any
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return fmt.Errorf("parse embedded schema: %w", err)
+	}
+	v := &schemaValidator{root: root}
+	v.check(root, doc, "")
+	if extra != nil && len(v.errs) == 0 {
+		v.errs = append(v.errs, extra(doc)...)
+	}
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// checkGraphRefs reports duplicate node IDs and edges naming unknown nodes,
+// which the schema cannot express.
+func checkGraphRefs(doc any) ValidationErrors {
+	obj, _ := doc.(map[string]any)
+	nodes, _ := obj["nodes"].([]any)
+	edges, _ := obj["edges"].([]any)
+
+	var errs ValidationErrors
+	ids := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		id, _ := n.(map[string]any)["id"].(string)
+		if first, dup := ids[id]; dup {
+			errs = append(errs, ValidationError{
+				Path:   fmt.Sprintf("/nodes/%d/id", i),
+				Reason: fmt.Sprintf("duplicate node ID %q (first at /nodes/%d)", id, first),
+			})
+			continue
+		}
+		ids[id] = i
+	}
+	for i, e := range edges {
+		edge, _ := e.(map[string]any)
+		for _, end := range []string{"from", "to"} {
+			id, _ := edge[end].(string)
+			if _, ok := ids[id]; !ok {
+				errs = append(errs, ValidationError{
+					Path:   fmt.Sprintf("/edges/%d/%s", i, end),
+					Reason: fmt.Sprintf("unknown node %q", id),
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// schemaValidator checks JSON values against the subset of JSON Schema the
+// embedded schemas use: type, enum, const, minimum, maximum, minLength,
+// minItems, required, properties, additionalProperties, items, allOf,
+// if/then and local $ref.
+type schemaValidator struct {
+	root map[string]any
+	errs ValidationErrors
+}
+
+func (v *schemaValidator) fail(path, format string, args ...any) {
+	v.errs = append(v.errs, ValidationError{Path: path, Reason: fmt.Sprintf(format, args...)})
+}
+
+// matches reports whether val satisfies s without recording errors.
+func (v *schemaValidator) matches(s map[string]any, val any) bool {
+	probe := &schemaValidator{root: v.root}
+	probe.check(s, val, "")
+	return len(probe.errs) == 0
+}
+
+func (v *schemaValidator) check(s map[string]any, val any, path string) {
+	if ref, ok := s["$ref"].(string); ok {
+		target, ok := v.resolve(ref)
+		if !ok {
+			v.fail(path, "schema reference %s not found", ref)
+			return
+		}
+		v.check(target, val, path)
+	}
+	if t, ok := s["type"]; ok && !typeMatches(t, val) {
+		v.fail(path, "expected %s, got %s", typeNames(t), jsonType(val))
+		return
+	}
+	if enum, ok := s["enum"].([]any); ok && !slices.ContainsFunc(enum, func(e any) bool { return jsonEqual(e, val) }) {
+		v.fail(path, "must be one of %s, got %s", enumList(enum), jsonText(val))
+	}
+	if c, ok := s["const"]; ok && !jsonEqual(c, val) {
+		v.fail(path, "must be %s, got %s", jsonText(c), jsonText(val))
+	}
+
+	switch val := val.(type) {
+	case json.Number:
+		f, _ := val.Float64()
+		if m, ok := s["minimum"].(float64); ok && f < m {
+			v.fail(path, "must be at least %s, got %s", strconv.FormatFloat(m, 'f', -1, 64), val)
+		}
+		if m, ok := s["maximum"].(float64); ok && f > m {
+			v.fail(path, "must be at most %s, got %s", strconv.FormatFloat(m, 'f', -1, 64), val)
+		}
+	case string:
+		if m, ok := s["minLength"].(float64); ok && float64(utf8.RuneCountInString(val)) < m {
+			if m == 1 {
+				v.fail(path, "must not be empty")
+			} else {
+				v.fail(path, "must be at least %.0f characters", m)
+			}
+		}
+	case []any:
+		if m, ok := s["minItems"].(float64); ok && float64(len(val)) < m {
+			if m == 1 {
+				v.fail(path, "must not be empty")
+			} else {
+				v.fail(path, "must have at least %.0f items, got %d", m, len(val))
+			}
+		}
+		if items, ok := s["items"].(map[string]any); ok {
+			for i, item := range val {
+				v.check(items, item, path+"/"+strconv.Itoa(i))
+			}
+		}
+	case map[string]any:
+		v.checkObject(s, val, path)
+	}
+
+	if all, ok := s["allOf"].([]any); ok {
+		for _, sub := range all {
+			if sub, ok := sub.(map[string]any); ok {
+				v.check(sub, val, path)
+			}
+		}
+	}
+	if cond, ok := s["if"].(map[string]any); ok && v.matches(cond, val) {
+		if then, ok := s["then"].(map[string]any); ok {
+			v.check(then, val, path)
+		}
+	}
+}
+
+func (v *schemaValidator) checkObject(s map[string]any, obj map[string]any, path string) {
+	if required, ok := s["required"].([]any); ok {
+		for _, r := range required {
+			if name, _ := r.(string); name != "" {
+				if _, ok := obj[name]; !ok {
+					v.fail(path, "missing required field %q", name)
+				}
+			}
+		}
+	}
+	props, _ := s["properties"].(map[string]any)
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		child := path + "/" + escapePointer(k)
+		if ps, ok := props[k].(map[string]any); ok {
+			v.check(ps, obj[k], child)
+			continue
+		}
+		switch extra := s["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				v.fail(child, "unknown field %q", k)
+			}
+		case map[string]any:
+			v.check(extra, obj[k], child)
+		}
+	}
+}
+
+// resolve looks up a local reference such as "#/$defs/node".
+func (v *schemaValidator) resolve(ref string) (map[string]any, bool) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, false
+	}
+	var cur any = v.root
+	for _, part := range strings.Split(ref[2:], "/") {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		cur = obj[part]
+	}
+	s, ok := cur.(map[string]any)
+	return s, ok
+}
+
+// typeMatches reports whether val has the schema type t, a name or a list
+// of names.
+func typeMatches(t, val any) bool {
+	switch t := t.(type) {
+	case string:
+		got := jsonType(val)
+		return got == t || (t == "number" && got == "integer")
+	case []any:
+		return slices.ContainsFunc(t, func(name any) bool { return typeMatches(name, val) })
+	}
+	return true
+}
+
+// typeNames renders a schema type for messages: "string" or "array or null".
+func typeNames(t any) string {
+	if list, ok := t.([]any); ok {
+		names := make([]string, len(list))
+		for i, n := range list {
+			names[i] = fmt.Sprint(n)
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+// jsonType names the JSON type of a decoded value, telling integers from
+// other numbers.
+func jsonType(val any) string {
+	switch val := val.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			return "integer"
+		}
+		if f, err := val.Float64(); err == nil && f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", val)
+}
+
+// jsonEqual compares a schema value with a document value, treating
+// numbers by value.
+func jsonEqual(schemaVal, val any) bool {
+	if n, ok := val.(json.Number); ok {
+		f, _ := n.Float64()
+		return schemaVal == f
+	}
+	return reflect.DeepEqual(schemaVal, val)
+}
+
+// jsonText renders a value as JSON for messages.
+func jsonText(val any) string {
+	b, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprint(val)
+	}
+	return string(b)
+}
+
+// enumList renders the non-empty enum values for messages.
+func enumList(enum []any) string {
+	var parts []string
+	for _, e := range enum {
+		if e != "" {
+			parts = append(parts, jsonText(e))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// escapePointer escapes a key for use in a JSON Pointer.
+func escapePointer(k string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(k, "~", "~0"), "/", "~1")
+}
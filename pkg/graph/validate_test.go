@@ -0,0 +1,252 @@
+package graph
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestValidate_Graph(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want ValidationErrors
+	}{
+		{
+			name: "Valid",
+			doc: `{"schema_version": 1, "meta": {"language": "go"},
+				"nodes": [{"id": "app", "row": 0, "meta": {"stars": 3}}, {"id": "lib", "kind": "auxiliary"}],
+				"edges": [{"from": "app", "to": "lib", "constraint": "^1", "meta": {"kind": "dev", "weight": 2, "x-call-count": 40}}]}`,
+		},
+		{
+			name: "Legacy",
+			doc:  `{"nodes": [{"id": "app"}], "edges": []}`,
+		},
+		{
+			name: "Typo",
+			doc:  `{"nodes": [{"id": "app"}, {"id": "lib"}], "edges": [{"from": "app", "too": "lib"}]}`,
+			want: ValidationErrors{
+				{"/edges/0", `missing required field "to"`},
+				{"/edges/0/too", `unknown field "too"`},
+			},
+		},
+		{
+			name: "WrongTypes",
+			doc:  `{"nodes": [{"id": "", "row": 1.5, "kind": "virtual", "brittle": "yes"}]}`,
+			want: ValidationErrors{
+				{"/nodes/0/brittle", "expected boolean, got string"},
+				{"/nodes/0/id", "must not be empty"},
+				{"/nodes/0/kind", `must be one of "subdivider", "auxiliary", got "virtual"`},
+				{"/nodes/0/row", "expected integer, got number"},
+			},
+		},
+		{
+			name: "EdgeMeta",
+			doc:  `{"nodes": [{"id": "a"}, {"id": "b"}], "edges": [{"from": "a", "to": "b", "meta": {"kind": 1, "weight": -2}}]}`,
+			want: ValidationErrors{
+				{"/edges/0/meta/kind", "expected string, got integer"},
+				{"/edges/0/meta/weight", "must be at least 0, got -2"},
+			},
+		},
+		{
+			name: "NegativeRow",
+			doc:  `{"nodes": [{"id": "a", "row": -1}]}`,
+			want: ValidationErrors{{"/nodes/0/row", "must be at least 0, got -1"}},
+		},
+		{
+			name: "MissingNodes",
+			doc:  `{"edges": []}`,
+			want: ValidationErrors{{"", `missing required field "nodes"`}},
+		},
+		{
+			name: "NotAnObject",
+			doc:  `[1, 2]`,
+			want: ValidationErrors{{"", "expected object, got array"}},
+		},
+		{
+			name: "NewerSchema",
+			doc:  `{"schema_version": 2, "nodes": []}`,
+			want: ValidationErrors{{"/schema_version", "must be at most 1, got 2"}},
+		},
+		{
+			name: "References",
+			doc:  `{"nodes": [{"id": "a"}, {"id": "b"}, {"id": "a"}], "edges": [{"from": "a", "to": "c"}]}`,
+			want: ValidationErrors{
+				{"/nodes/2/id", `duplicate node ID "a" (first at /nodes/0)`},
+				{"/edges/0/to", `unknown node "c"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate([]byte(tt.doc))
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			var got ValidationErrors
+			if !errors.As(err, &got) {
+				t.Fatalf("err = %v, want ValidationErrors", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_Layout(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want ValidationErrors
+	}{
+		{
+			name: "Tower",
+			doc:  `{"viz_type": "tower", "width": 100, "height": 50, "blocks": [{"id": "a", "label": "a", "x": 0, "y": 0, "width": 10, "height": 10}], "rows": {"0": ["a"]}}`,
+		},
+		{
+			name: "TowerByDefault",
+			doc:  `{"width": 100, "height": 50, "blocks": []}`,
+			want: ValidationErrors{{"/blocks", "must not be empty"}},
+		},
+		{
+			name: "NodelinkWithoutDOT",
+			doc:  `{"viz_type": "nodelink", "width": 1, "height": 1}`,
+			want: ValidationErrors{{"", `missing required field "dot"`}},
+		},
+		{
+			name: "Treemap",
+			doc:  `{"viz_type": "treemap", "width": 1, "height": 1, "nodes": [{"id": "a"}]}`,
+		},
+		{
+			name: "UnknownType",
+			doc:  `{"viz_type": "pie", "width": 1, "height": 1}`,
+			want: ValidationErrors{{"/viz_type", `must be one of "tower", "nodelink", "sunburst", "treemap", "dsm", got "pie"`}},
+		},
+		{
+			name: "BadBlock",
+			doc:  `{"viz_type": "tower", "width": 1, "height": 1, "blocks": [{"id": "a", "x": "0", "y": 0, "width": -1, "height": 1}]}`,
+			want: ValidationErrors{
+				{"/blocks/0/width", "must be at least 0, got -1"},
+				{"/blocks/0/x", "expected number, got string"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate([]byte(tt.doc))
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				if err := ValidateLayout([]byte(tt.doc)); err != nil {
+					t.Fatalf("ValidateLayout: %v", err)
+				}
+				return
+			}
+			var got ValidationErrors
+			if !errors.As(err, &got) {
+				t.Fatalf("err = %v, want ValidationErrors", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_SyntaxError(t *testing.T) {
+	err := Validate([]byte("{\n  \"nodes\": [\n    {\"id\": \"a\",}\n  ]\n}"))
+	var got ValidationErrors
+	if !errors.As(err, &got) || len(got) != 1 {
+		t.Fatalf("err = %v, want one ValidationError", err)
+	}
+	if want := "invalid JSON at line 3, column 16"; got[0].Reason[:len(want)] != want {
+		t.Errorf("reason = %q, want prefix %q", got[0].Reason, want)
+	}
+}
+
+func TestValidate_WrittenDocuments(t *testing.T) {
+	g := dag.New(nil)
+	g.Meta()["language"] = "python"
+	_ = g.AddNode(dag.Node{ID: "app", Meta: dag.Metadata{metaLabel: "App", "repo_stars": 4}})
+	_ = g.AddNode(dag.Node{ID: "lib", Row: 1, Meta: dag.Metadata{"license": "MIT"}})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "lib", Meta: dag.Metadata{"constraint": ">=1"}})
+	data, err := MarshalGraph(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateGraph(data); err != nil {
+		t.Errorf("written graph does not validate:\n%v", err)
+	}
+
+	layout, err := MarshalLayout(Layout{
+		VizType: VizTypeTower, Width: 100, Height: 40, Style: StyleSimple,
+		Blocks: []Block{{ID: "app", Label: "App", Width: 100, Height: 20, Meta: &BlockMeta{Stars: 4}}},
+		Nodes:  []Node{{ID: "app"}}, Rows: map[int][]string{0: {"app"}},
+		Nebraska: []NebraskaRanking{{Maintainer: "ann", Score: 1, Packages: []NebraskaPackage{{Package: "app", Role: "owner"}}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateLayout(layout); err != nil {
+		t.Errorf("written layout does not validate:\n%v", err)
+	}
+}
+
+func TestValidate_Examples(t *testing.T) {
+	real, _ := filepath.Glob("../../examples/real/*.json")
+	synthetic, _ := filepath.Glob("../../examples/test/*.json")
+	files := append(real, synthetic...)
+	if len(files) == 0 {
+		t.Skip("no example graphs")
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Validate(data); err != nil {
+			t.Errorf("%s:\n%v", f, err)
+		}
+	}
+}
+
+func TestSchemaVersion_MatchesSchemas(t *testing.T) {
+	for name, schema := range map[string][]byte{"graph": GraphSchema(), "layout": LayoutSchema()} {
+		var s struct {
+			Properties struct {
+				SchemaVersion struct {
+					Maximum int `json:"maximum"`
+				} `json:"schema_version"`
+			} `json:"properties"`
+		}
+		if err := json.Unmarshal(schema, &s); err != nil {
+			t.Fatalf("%s schema: %v", name, err)
+		}
+		if s.Properties.SchemaVersion.Maximum != SchemaVersion {
+			t.Errorf("%s schema accepts schema_version up to %d, want SchemaVersion %d",
+				name, s.Properties.SchemaVersion.Maximum, SchemaVersion)
+		}
+	}
+}
+
+func TestReadGraph_NewerSchema(t *testing.T) {
+	_, err := ReadGraph(strings.NewReader(`{"schema_version": 99, "nodes": []}`))
+	if !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("ReadGraph err = %v, want ErrNewerSchema", err)
+	}
+	_, err = UnmarshalLayout([]byte(`{"schema_version": 99, "viz_type": "dsm", "nodes": [{"id": "a"}]}`))
+	if !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("UnmarshalLayout err = %v, want ErrNewerSchema", err)
+	}
+}
@@ -44,6 +44,7 @@ type Client struct {
 	group          singleflight.Group // deduplicates concurrent in-flight requests
 	limiter        *rate.Limiter      // proactive token-bucket rate limiter (nil = no limit)
 	circuitBreaker *CircuitBreaker    // circuit breaker for rate limit protection
+	observe        func(http.Header)  // called with the headers of every response (nil = none)
 }
 
 // NewClient creates a Client with the given cache and default headers.
@@ -94,6 +95,13 @@ func NewClientWithRateLimit(c cache.Cache, namespace string, ttl time.Duration,
 	return client
 }
 
+// ObserveResponses registers fn to be called with the headers of every
+// response the client receives, whatever its status, for instance to track
+// the quota an API reports in them. Call it before using the client.
+func (c *Client) ObserveResponses(fn func(http.Header)) {
+	c.observe = fn
+}
+
 // registryName returns the registry identifier from the namespace (e.g., "pypi:" -> "pypi").
 func (c *Client) registryName() string {
 	return strings.TrimSuffix(c.namespace, ":")
@@ -117,6 +125,10 @@ func (c *Client) registryName() string {
 //  2. If cache miss or refresh=true: calls fetch with automatic retry on [RetryableError]
 //  3. Concurrent fetches for the same key are deduplicated (only one HTTP call)
 //  4. On successful fetch: stores result in cache (ignoring cache write errors)
+//  5. On failed fetch: falls back to the expired entry, if the cache is a
+//     [cache.StaleReader] that still has one, and reports it with
+//     [cache.MarkStale]. Packages the registry reports missing
+//     ([ErrNotFound]) do not fall back.
 //
 // The fetch function should populate v and return nil on success, or return an error.
 // Network errors should be wrapped with [Retryable] to enable retry.
@@ -160,6 +172,11 @@ func (c *Client) Cached(ctx context.Context, key string, refresh bool, v any, fe
 		return data, nil
 	})
 	if err != nil {
+		if data, ok := c.staleFallback(ctx, cacheKey, err); ok && json.Unmarshal(data, v) == nil {
+			slog.Debug("fetch failed, using stale cache entry", "key", key, "error", err)
+			cache.MarkStale(ctx)
+			return nil
+		}
 		return err
 	}
 
@@ -175,6 +192,17 @@ func (c *Client) Cached(ctx context.Context, key string, refresh bool, v any, fe
 	return nil
 }
 
+// staleFallback returns the expired cache entry for cacheKey when a fetch
+// failed for any reason but the resource being gone or the caller giving up.
+func (c *Client) staleFallback(ctx context.Context, cacheKey string, err error) ([]byte, bool) {
+	sr, ok := c.cache.(cache.StaleReader)
+	if !ok || errors.Is(err, ErrNotFound) || ctx.Err() != nil {
+		return nil, false
+	}
+	data, hit, err := sr.GetStale(ctx, cacheKey)
+	return data, hit && err == nil
+}
+
 // Get performs an HTTP GET request and JSON-decodes the response into v.
 // It uses the client's default headers and handles retries automatically.
 //
@@ -362,6 +390,9 @@ func (c *Client) doRequestWithBody(ctx context.Context, method, reqURL string, b
 	}
 
 	observability.HTTP().OnResponse(ctx, method, host, path, resp.StatusCode, time.Since(start))
+	if c.observe != nil {
+		c.observe(resp.Header)
+	}
 
 	if err := checkResponse(resp); err != nil {
 		resp.Body.Close()
@@ -389,6 +420,14 @@ func checkResponse(resp *http.Response) error {
 		return nil
 	case resp.StatusCode == http.StatusNotFound:
 		return ErrNotFound
+	case resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0":
+		// GitHub answers 403, not 429, once the hourly quota is used up.
+		// Waiting for the reset would stall the run, so this is not retried.
+		retryAfter := 0
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			retryAfter = max(0, int(time.Until(time.Unix(reset, 0)).Seconds()))
+		}
+		return &RateLimitedError{RetryAfter: retryAfter}
 	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
 		return ErrUnauthorized
 	case resp.StatusCode == http.StatusTooManyRequests:
@@ -170,6 +170,25 @@ func TestClientGet404(t *testing.T) {
 	}
 }
 
+func TestClientObserveResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "41")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(nil, "test:", time.Hour, nil)
+	client.http = server.Client()
+	var remaining string
+	client.ObserveResponses(func(h http.Header) { remaining = h.Get("X-RateLimit-Remaining") })
+
+	var resp map[string]string
+	_ = client.Get(context.Background(), server.URL, &resp)
+	if remaining != "41" {
+		t.Errorf("observed X-RateLimit-Remaining = %q, want the headers of failed responses too", remaining)
+	}
+}
+
 func TestClientGet500(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -278,6 +297,36 @@ func TestClientCachedFetchError(t *testing.T) {
 	}
 }
 
+func TestClientCachedStaleFallback(t *testing.T) {
+	c, _ := cache.NewFileCache(t.TempDir())
+	defer c.Close()
+	client := NewClient(c, "test:", time.Nanosecond, nil)
+
+	var value string
+	if err := client.Cached(context.Background(), "pkg", false, &value, func() error {
+		value = "last known good"
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+
+	ctx, tracker := cache.TrackStale(context.Background())
+	value = ""
+	err := client.Cached(ctx, "pkg", false, &value, func() error {
+		return errors.New("registry down")
+	})
+	if err != nil || value != "last known good" || !tracker.Used() {
+		t.Errorf("Cached() = %q, %v, stale=%v; want the expired entry, marked stale", value, err, tracker.Used())
+	}
+
+	ctx, tracker = cache.TrackStale(context.Background())
+	err = client.Cached(ctx, "pkg", false, &value, func() error { return ErrNotFound })
+	if !errors.Is(err, ErrNotFound) || tracker.Used() {
+		t.Errorf("Cached() = %v, stale=%v; a missing package should not fall back", err, tracker.Used())
+	}
+}
+
 func TestCheckResponse(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -333,6 +382,12 @@ func TestCheckResponse(t *testing.T) {
 			wantErr:  true,
 			wantType: ErrUnauthorized,
 		},
+		{
+			name:    "403 with the quota used up",
+			code:    403,
+			headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1"},
+			wantErr: true,
+		},
 		{
 			name:    "429 Rate Limited without Retry-After",
 			code:    429,
@@ -371,6 +426,11 @@ func TestCheckResponse(t *testing.T) {
 						t.Errorf("checkResponse() error should be RetryableError, got %T", err)
 					}
 				}
+				if tt.headers["X-RateLimit-Remaining"] == "0" {
+					if !IsRateLimitedError(err) || errors.Is(err, ErrUnauthorized) || cache.IsRetryable(err) {
+						t.Errorf("checkResponse() error = %v, want a rate limit error not retried", err)
+					}
+				}
 				// Check Retry-After parsing for 429
 				if tt.code == 429 {
 					var rateLimitErr *RateLimitedError
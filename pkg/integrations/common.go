@@ -26,15 +26,17 @@ const UserAgent = "stacktower/1.0"
 //   - GitHub GraphQL batches can take time (20s)
 //   - OSV batch queries scale with size (30s)
 var DefaultTimeouts = map[string]time.Duration{
-	"pypi":      10 * time.Second,
-	"npm":       10 * time.Second,
-	"crates":    10 * time.Second,
-	"rubygems":  10 * time.Second,
-	"packagist": 10 * time.Second,
-	"maven":     30 * time.Second,
-	"goproxy":   15 * time.Second,
-	"github":    20 * time.Second,
-	"osv":       30 * time.Second,
+	"pypi":         10 * time.Second,
+	"npm":          10 * time.Second,
+	"crates":       10 * time.Second,
+	"crates-index": 10 * time.Second,
+	"rubygems":     10 * time.Second,
+	"packagist":    10 * time.Second,
+	"maven":        30 * time.Second,
+	"nuget":        10 * time.Second,
+	"goproxy":      15 * time.Second,
+	"github":       20 * time.Second,
+	"osv":          30 * time.Second,
 }
 
 // Sentinel errors - re-exported from cache for API consistency.
@@ -66,11 +68,13 @@ type RepoMetrics struct {
 	SizeKB        int           `json:"size_kb,omitempty"`          // Repository size in kilobytes. 0 means not available or very small.
 	LastCommitAt  *time.Time    `json:"last_commit_at,omitempty"`   // Date of most recent commit. Nil if not available.
 	LastReleaseAt *time.Time    `json:"last_release_at,omitempty"`  // Date of most recent release. Nil if no releases or not available.
+	CreatedAt     *time.Time    `json:"created_at,omitempty"`       // Date the repository was created. Nil if not available.
 	License       string        `json:"license,omitempty"`          // SPDX license identifier (e.g., "MIT", "Apache-2.0"). Empty if not detected.
 	Contributors  []Contributor `json:"top_contributors,omitempty"` // Top contributors by commit count (typically top 5). Nil or empty if not available.
 	Language      string        `json:"language,omitempty"`         // Primary repository language (e.g., "Go", "Python"). Empty if not detected.
 	Topics        []string      `json:"topics,omitempty"`           // Repository topic tags. Nil or empty if none.
 	Archived      bool          `json:"archived"`                   // Whether the repository is archived. False means active or unknown.
+	Successor     string        `json:"successor,omitempty"`        // URL of a maintained fork of an archived repository. Empty if none was found.
 }
 
 // Contributor represents a repository contributor with their contribution count.
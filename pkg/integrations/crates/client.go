@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/stacktower-io/stacktower/pkg/cache"
@@ -45,7 +47,14 @@ type CrateInfo struct {
 // Note: crates.io requires a User-Agent header; this client sets one automatically.
 type Client struct {
 	*integrations.Client
-	baseURL string
+	baseURL  string
+	index    *integrations.Client // Sparse index client; nil to resolve from the API
+	indexURL string
+}
+
+// userAgent identifies the client as crates.io policy asks.
+var userAgent = map[string]string{
+	"User-Agent": "stacktower/1.0 (https://github.com/stacktower-io/stacktower)",
 }
 
 // NewClient creates a crates.io client with the given cache backend.
@@ -55,14 +64,12 @@ type Client struct {
 //   - cacheTTL: How long responses are cached (typical: 1-24 hours)
 //
 // The client includes a User-Agent header as required by crates.io API policy.
+// Use [NewIndexClient] to resolve from the sparse index instead.
 // The returned Client is safe for concurrent use.
 func NewClient(backend cache.Cache, cacheTTL time.Duration) *Client {
-	headers := map[string]string{
-		"User-Agent": "stacktower/1.0 (https://github.com/stacktower-io/stacktower)",
-	}
 	rl := integrations.DefaultRateLimits["crates"]
 	return &Client{
-		Client:  integrations.NewClientWithRateLimit(backend, "crates:", cacheTTL, headers, rl.RequestsPerSecond, rl.Burst),
+		Client:  integrations.NewClientWithRateLimit(backend, "crates:", cacheTTL, userAgent, rl.RequestsPerSecond, rl.Burst),
 		baseURL: "https://crates.io/api/v1",
 	}
 }
@@ -87,6 +94,9 @@ func NewClient(backend cache.Cache, cacheTTL time.Duration) *Client {
 // The returned CrateInfo pointer is never nil if err is nil.
 // This method is safe for concurrent use.
 func (c *Client) FetchCrate(ctx context.Context, crate string, refresh bool) (*CrateInfo, error) {
+	if c.index != nil {
+		return c.fetchIndexed(ctx, crate, "", refresh)
+	}
 	key := crate
 
 	var info CrateInfo
@@ -113,6 +123,9 @@ func (c *Client) FetchCrate(ctx context.Context, crate string, refresh bool) (*C
 //
 // This method is safe for concurrent use.
 func (c *Client) FetchCrateVersion(ctx context.Context, crate, version string, refresh bool) (*CrateInfo, error) {
+	if c.index != nil {
+		return c.fetchIndexed(ctx, crate, version, refresh)
+	}
 	key := crate + "@" + version
 
 	var info CrateInfo
@@ -214,6 +227,15 @@ func (c *Client) fetchDeps(ctx context.Context, crate, version string) ([]Depend
 // ListVersions returns all non-yanked versions for a crate, sorted semantically
 // from oldest to newest.
 func (c *Client) ListVersions(ctx context.Context, crate string, refresh bool) ([]string, error) {
+	if c.index != nil {
+		result, err := c.indexVersions(ctx, crate, refresh)
+		if err != nil {
+			return nil, err
+		}
+		versions := slices.Collect(maps.Keys(result))
+		integrations.SortVersions(versions)
+		return versions, nil
+	}
 	key := crate + ":versions"
 
 	var versions []string
@@ -255,6 +277,9 @@ func (c *Client) ListVersions(ctx context.Context, crate string, refresh bool) (
 // ListVersionsWithConstraints returns all versions and their rust_version (MSRV).
 // Returns a map of version -> rust_version (empty string if not specified).
 func (c *Client) ListVersionsWithConstraints(ctx context.Context, crate string, refresh bool) (map[string]string, error) {
+	if c.index != nil {
+		return c.indexVersions(ctx, crate, refresh)
+	}
 	key := crate + ":version_constraints"
 
 	var result map[string]string
@@ -284,15 +309,17 @@ func (c *Client) ListVersionsWithConstraints(ctx context.Context, crate string,
 }
 
 type crateResponse struct {
-	Crate struct {
-		Name        string `json:"name"`
-		MaxVersion  string `json:"max_version"`
-		Description string `json:"description"`
-		License     string `json:"license"`
-		Repository  string `json:"repository"`
-		HomePage    string `json:"homepage"`
-		Downloads   int    `json:"downloads"`
-	} `json:"crate"`
+	Crate crateMeta `json:"crate"`
+}
+
+type crateMeta struct {
+	Name        string `json:"name"`
+	MaxVersion  string `json:"max_version"`
+	Description string `json:"description"`
+	License     string `json:"license"`
+	Repository  string `json:"repository"`
+	HomePage    string `json:"homepage"`
+	Downloads   int    `json:"downloads"`
 }
 
 type depsResponse struct {
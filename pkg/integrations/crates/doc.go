@@ -36,6 +36,17 @@
 // Responses are cached to reduce load on crates.io. The cache TTL is set
 // when creating the client. Pass refresh=true to bypass the cache.
 //
+// # Sparse Index
+//
+// [NewIndexClient] returns a client that reads versions and dependencies
+// from the sparse index (https://index.crates.io) instead of the API. The
+// index holds one file per crate listing every published version with its
+// dependency requirements, yanked flag and rust_version, and is served
+// from a CDN without the API's 1 request per second policy. The latest
+// version is the highest stable one that is not yanked. Descriptions,
+// licenses and URLs still come from the API, once per crate, and are left
+// empty when that request fails.
+//
 // # Dependency Filtering
 //
 // Only "normal" dependencies are included. Development dependencies,
@@ -0,0 +1,222 @@
+package crates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+// NewIndexClient creates a crates.io client that resolves crates from the
+// sparse index (https://index.crates.io) instead of the API.
+//
+// The index lists every published version of a crate in one file, with the
+// dependencies and requirements of each, so resolution needs one request
+// per crate. The index is served from a CDN without the API's strict rate
+// limit. Descriptions, licenses and repository URLs are not in the index;
+// they come from one API request per crate, and are left empty when that
+// request fails.
+//
+// The returned Client is safe for concurrent use.
+func NewIndexClient(backend cache.Cache, cacheTTL time.Duration) *Client {
+	c := NewClient(backend, cacheTTL)
+	rl := integrations.DefaultRateLimits["crates-index"]
+	c.index = integrations.NewClientWithRateLimit(backend, "crates-index:", cacheTTL, userAgent, rl.RequestsPerSecond, rl.Burst)
+	c.indexURL = "https://index.crates.io"
+	return c
+}
+
+// indexEntry is one line of a sparse index file: a published version.
+type indexEntry struct {
+	Name        string     `json:"name"`
+	Vers        string     `json:"vers"`
+	Deps        []indexDep `json:"deps"`
+	Yanked      bool       `json:"yanked"`
+	RustVersion string     `json:"rust_version"`
+}
+
+type indexDep struct {
+	Name     string `json:"name"`
+	Req      string `json:"req"`
+	Optional bool   `json:"optional"`
+	Kind     string `json:"kind"`    // "normal" (or empty), "dev" or "build"
+	Package  string `json:"package"` // Crate name when the dependency is renamed
+}
+
+// dependencies returns the normal, non-optional dependencies of a version,
+// like the API's dependencies endpoint.
+func (e indexEntry) dependencies() []Dependency {
+	var deps []Dependency
+	for _, d := range e.Deps {
+		if (d.Kind != "" && d.Kind != "normal") || d.Optional {
+			continue
+		}
+		name := d.Name
+		if d.Package != "" {
+			name = d.Package
+		}
+		deps = append(deps, Dependency{Name: name, Constraint: d.Req})
+	}
+	return deps
+}
+
+// indexPath returns the path of a crate's file in the index: crates with
+// one to three characters live under "1/", "2/" and "3/{first}/", others
+// under the first two pairs of characters, as in "se/rd/serde".
+func indexPath(crate string) string {
+	crate = strings.ToLower(crate)
+	switch len(crate) {
+	case 1, 2:
+		return fmt.Sprintf("%d/%s", len(crate), crate)
+	case 3:
+		return fmt.Sprintf("3/%s/%s", crate[:1], crate)
+	}
+	return fmt.Sprintf("%s/%s/%s", crate[:2], crate[2:4], crate)
+}
+
+// indexEntries returns the versions of a crate in the index, in publish
+// order.
+func (c *Client) indexEntries(ctx context.Context, crate string, refresh bool) ([]indexEntry, error) {
+	var entries []indexEntry
+	err := c.index.Cached(ctx, crate, refresh, &entries, func() error {
+		text, err := c.index.GetText(ctx, c.indexURL+"/"+indexPath(crate))
+		if err != nil {
+			if errors.Is(err, integrations.ErrNotFound) {
+				return fmt.Errorf("%w: crate %s", err, crate)
+			}
+			return err
+		}
+		entries, err = parseIndex(text)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// parseIndex parses a sparse index file, which holds one JSON object per
+// line.
+func parseIndex(text string) ([]indexEntry, error) {
+	var entries []indexEntry
+	for line := range strings.Lines(text) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var e indexEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return nil, fmt.Errorf("parse index entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// latestIndexEntry returns the newest version that is not yanked,
+// preferring stable releases. When every version is yanked, it returns
+// the one published last.
+func latestIndexEntry(entries []indexEntry) indexEntry {
+	var stable, pre []string
+	byVersion := make(map[string]indexEntry, len(entries))
+	for _, e := range entries {
+		byVersion[e.Vers] = e
+		switch {
+		case e.Yanked:
+		case strings.Contains(e.Vers, "-"):
+			pre = append(pre, e.Vers)
+		default:
+			stable = append(stable, e.Vers)
+		}
+	}
+	for _, vs := range [][]string{stable, pre} {
+		if len(vs) > 0 {
+			integrations.SortVersions(vs)
+			return byVersion[vs[len(vs)-1]]
+		}
+	}
+	return entries[len(entries)-1]
+}
+
+// fetchIndexed builds a CrateInfo from the index, for version or for the
+// latest one when version is empty. Exact versions may be yanked, as Cargo
+// allows for locked versions.
+func (c *Client) fetchIndexed(ctx context.Context, crate, version string, refresh bool) (*CrateInfo, error) {
+	entries, err := c.indexEntries(ctx, crate, refresh)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no versions found for %s", crate)
+	}
+
+	var entry indexEntry
+	if version == "" {
+		entry = latestIndexEntry(entries)
+	} else {
+		found := false
+		for _, e := range entries {
+			if e.Vers == version {
+				entry, found = e, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: crate %s version %s", integrations.ErrNotFound, crate, version)
+		}
+	}
+
+	info := &CrateInfo{
+		Name:         entry.Name,
+		Version:      entry.Vers,
+		Dependencies: entry.dependencies(),
+		MSRV:         entry.RustVersion,
+	}
+	meta, err := c.fetchCrateMeta(ctx, crate, refresh)
+	if err != nil {
+		slog.Debug("crates: failed to fetch crate metadata", "crate", crate, "error", err)
+		return info, nil
+	}
+	info.Description = meta.Description
+	info.License = meta.License
+	info.Repository = meta.Repository
+	info.HomePage = meta.HomePage
+	info.Downloads = meta.Downloads
+	return info, nil
+}
+
+// fetchCrateMeta returns the crate-level metadata the index lacks.
+func (c *Client) fetchCrateMeta(ctx context.Context, crate string, refresh bool) (crateMeta, error) {
+	var meta crateMeta
+	err := c.Cached(ctx, crate+":meta", refresh, &meta, func() error {
+		var data crateResponse
+		if err := c.Get(ctx, fmt.Sprintf("%s/crates/%s", c.baseURL, crate), &data); err != nil {
+			return err
+		}
+		meta = data.Crate
+		return nil
+	})
+	return meta, err
+}
+
+// indexVersions returns the rust_version of every version in the index
+// that is not yanked.
+func (c *Client) indexVersions(ctx context.Context, crate string, refresh bool) (map[string]string, error) {
+	entries, err := c.indexEntries(ctx, crate, refresh)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(entries))
+	for _, e := range entries {
+		if !e.Yanked {
+			result[e.Vers] = e.RustVersion
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,158 @@
+package crates
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+const serdeIndex = `{"name":"serde","vers":"1.0.0","deps":[],"cksum":"x","features":{},"yanked":false}
+{"name":"serde","vers":"1.0.2","deps":[{"name":"serde_derive","req":"^1.0.2","features":[],"optional":true,"default_features":true,"target":null,"kind":"normal"}],"cksum":"x","features":{},"yanked":false,"rust_version":"1.31"}
+{"name":"serde","vers":"1.0.10","deps":[{"name":"derive","package":"serde_derive","req":"=1.0.10","features":[],"optional":false,"default_features":true,"target":null,"kind":null},{"name":"serde_test","req":"^1.0","features":[],"optional":false,"default_features":true,"target":null,"kind":"dev"}],"cksum":"x","features":{},"yanked":false,"rust_version":"1.56"}
+{"name":"serde","vers":"1.0.11","deps":[],"cksum":"x","features":{},"yanked":true}
+{"name":"serde","vers":"2.0.0-alpha.1","deps":[],"cksum":"x","features":{},"yanked":false}
+`
+
+func TestIndexPath(t *testing.T) {
+	tests := []struct{ crate, want string }{
+		{"a", "1/a"},
+		{"io", "2/io"},
+		{"syn", "3/s/syn"},
+		{"serde", "se/rd/serde"},
+		{"Tokio", "to/ki/tokio"},
+	}
+	for _, tt := range tests {
+		if got := indexPath(tt.crate); got != tt.want {
+			t.Errorf("indexPath(%q) = %q, want %q", tt.crate, got, tt.want)
+		}
+	}
+}
+
+func TestIndexClient_FetchCrate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index/se/rd/serde":
+			_, _ = w.Write([]byte(serdeIndex))
+		case "/api/crates/serde":
+			_, _ = w.Write([]byte(`{"crate":{"name":"serde","max_version":"2.0.0-alpha.1","description":"A serialization framework","license":"MIT OR Apache-2.0","repository":"https://github.com/serde-rs/serde","downloads":42}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := testIndexClient(t, server.URL)
+	ctx := context.Background()
+
+	info, err := c.FetchCrate(ctx, "serde", false)
+	if err != nil {
+		t.Fatalf("FetchCrate error: %v", err)
+	}
+	// 1.0.11 is yanked and 2.0.0-alpha.1 a prerelease
+	if info.Version != "1.0.10" {
+		t.Errorf("Version = %q, want 1.0.10", info.Version)
+	}
+	if want := []Dependency{{Name: "serde_derive", Constraint: "=1.0.10"}}; !slices.Equal(info.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want %v", info.Dependencies, want)
+	}
+	if info.MSRV != "1.56" {
+		t.Errorf("MSRV = %q, want 1.56", info.MSRV)
+	}
+	if info.Repository != "https://github.com/serde-rs/serde" || info.Downloads != 42 {
+		t.Errorf("crate metadata should come from the API, got %+v", info)
+	}
+
+	info, err = c.FetchCrateVersion(ctx, "serde", "1.0.2", false)
+	if err != nil {
+		t.Fatalf("FetchCrateVersion error: %v", err)
+	}
+	if len(info.Dependencies) != 0 {
+		t.Errorf("optional dependencies should be skipped, got %v", info.Dependencies)
+	}
+
+	// Locked versions may be yanked
+	if _, err := c.FetchCrateVersion(ctx, "serde", "1.0.11", false); err != nil {
+		t.Errorf("FetchCrateVersion of a yanked version: %v", err)
+	}
+	if _, err := c.FetchCrateVersion(ctx, "serde", "9.9.9", false); !errors.Is(err, integrations.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing version, got %v", err)
+	}
+}
+
+func TestIndexClient_MetadataFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/index/se/rd/serde" {
+			_, _ = w.Write([]byte(serdeIndex))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	info, err := testIndexClient(t, server.URL).FetchCrate(context.Background(), "serde", false)
+	if err != nil {
+		t.Fatalf("FetchCrate should not fail without API metadata: %v", err)
+	}
+	if info.Version != "1.0.10" || info.Description != "" {
+		t.Errorf("unexpected info: %+v", info)
+	}
+}
+
+func TestIndexClient_ListVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/index/se/rd/serde" {
+			_, _ = w.Write([]byte(serdeIndex))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c := testIndexClient(t, server.URL)
+	ctx := context.Background()
+
+	versions, err := c.ListVersions(ctx, "serde", false)
+	if err != nil {
+		t.Fatalf("ListVersions error: %v", err)
+	}
+	if want := []string{"1.0.0", "1.0.2", "1.0.10", "2.0.0-alpha.1"}; !slices.Equal(versions, want) {
+		t.Errorf("ListVersions = %v, want %v", versions, want)
+	}
+
+	constraints, err := c.ListVersionsWithConstraints(ctx, "serde", false)
+	if err != nil {
+		t.Fatalf("ListVersionsWithConstraints error: %v", err)
+	}
+	if constraints["1.0.2"] != "1.31" || constraints["1.0.0"] != "" {
+		t.Errorf("unexpected constraints: %v", constraints)
+	}
+	if _, ok := constraints["1.0.11"]; ok {
+		t.Error("yanked versions should be left out")
+	}
+
+	if _, err := c.ListVersions(ctx, "missing", false); !errors.Is(err, integrations.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing crate, got %v", err)
+	}
+}
+
+func TestLatestIndexEntry_AllYanked(t *testing.T) {
+	entries := []indexEntry{{Vers: "0.2.0", Yanked: true}, {Vers: "0.1.0", Yanked: true}}
+	if got := latestIndexEntry(entries); got.Vers != "0.1.0" {
+		t.Errorf("latestIndexEntry() = %s, want the last published 0.1.0", got.Vers)
+	}
+}
+
+func testIndexClient(t *testing.T, serverURL string) *Client {
+	t.Helper()
+	c := testClient(t, serverURL+"/api")
+	c.index = integrations.NewClient(cache.NewNullCache(), "crates-index:", time.Hour, userAgent)
+	c.indexURL = serverURL + "/index"
+	return c
+}
@@ -0,0 +1,67 @@
+package crates
+
+import (
+	"context"
+
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+// Registry adapts a [Client] to [integrations.RegistryClient].
+type Registry struct {
+	client *Client
+}
+
+// NewRegistry returns c as an [integrations.RegistryClient].
+func NewRegistry(c *Client) *Registry {
+	return &Registry{client: c}
+}
+
+var (
+	_ integrations.RegistryClient          = (*Registry)(nil)
+	_ integrations.RuntimeConstraintLister = (*Registry)(nil)
+)
+
+// Name returns "crates".
+func (r *Registry) Name() string { return "crates" }
+
+// FetchPackage implements [integrations.RegistryClient]. The runtime
+// constraint is the crate's minimum supported Rust version.
+func (r *Registry) FetchPackage(ctx context.Context, name, version string, opts integrations.FetchOptions) (*integrations.PackageInfo, error) {
+	var (
+		cr  *CrateInfo
+		err error
+	)
+	if version == "" {
+		cr, err = r.client.FetchCrate(ctx, name, opts.Refresh)
+	} else {
+		cr, err = r.client.FetchCrateVersion(ctx, name, version, opts.Refresh)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info := &integrations.PackageInfo{
+		Name:              cr.Name,
+		Version:           cr.Version,
+		Description:       cr.Description,
+		License:           cr.License,
+		Repository:        cr.Repository,
+		HomePage:          cr.HomePage,
+		Downloads:         cr.Downloads,
+		RuntimeConstraint: cr.MSRV,
+	}
+	for _, d := range cr.Dependencies {
+		info.Dependencies = append(info.Dependencies, integrations.Dependency{Name: d.Name, Constraint: d.Constraint})
+	}
+	return info, nil
+}
+
+// ListVersions implements [integrations.RegistryClient].
+func (r *Registry) ListVersions(ctx context.Context, name string, opts integrations.FetchOptions) ([]string, error) {
+	return r.client.ListVersions(ctx, name, opts.Refresh)
+}
+
+// ListVersionsWithConstraints implements [integrations.RuntimeConstraintLister].
+func (r *Registry) ListVersionsWithConstraints(ctx context.Context, name string, opts integrations.FetchOptions) (map[string]string, error) {
+	return r.client.ListVersionsWithConstraints(ctx, name, opts.Refresh)
+}
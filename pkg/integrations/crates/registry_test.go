@@ -0,0 +1,54 @@
+package crates
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+func TestRegistry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/index/se/rd/serde" {
+			_, _ = w.Write([]byte(serdeIndex))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	r := NewRegistry(testIndexClient(t, server.URL))
+	ctx := context.Background()
+	if r.Name() != "crates" {
+		t.Errorf("Name() = %q, want crates", r.Name())
+	}
+
+	info, err := r.FetchPackage(ctx, "serde", "", integrations.FetchOptions{})
+	if err != nil {
+		t.Fatalf("FetchPackage error: %v", err)
+	}
+	if info.Version != "1.0.10" || info.RuntimeConstraint != "1.56" {
+		t.Errorf("unexpected latest: %+v", info)
+	}
+	if len(info.Dependencies) != 1 || info.Dependencies[0] != (integrations.Dependency{Name: "serde_derive", Constraint: "=1.0.10"}) {
+		t.Errorf("unexpected dependencies: %v", info.Dependencies)
+	}
+
+	info, err = r.FetchPackage(ctx, "serde", "1.0.2", integrations.FetchOptions{})
+	if err != nil {
+		t.Fatalf("FetchPackage(1.0.2) error: %v", err)
+	}
+	if info.Version != "1.0.2" || info.RuntimeConstraint != "1.31" {
+		t.Errorf("unexpected 1.0.2: %+v", info)
+	}
+
+	constraints, err := r.ListVersionsWithConstraints(ctx, "serde", integrations.FetchOptions{})
+	if err != nil {
+		t.Fatalf("ListVersionsWithConstraints error: %v", err)
+	}
+	if constraints["1.0.10"] != "1.56" {
+		t.Errorf("unexpected constraints: %v", constraints)
+	}
+}
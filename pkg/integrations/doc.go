@@ -12,6 +12,7 @@
 //   - [packagist]: PHP Composer packages
 //   - [maven]: Java Maven Central
 //   - [goproxy]: Go Module Proxy
+//   - [nuget]: .NET NuGet packages
 //   - [github]: GitHub API for metadata enrichment
 //   - [gitlab]: GitLab API for metadata enrichment
 //
@@ -27,6 +28,19 @@
 //   - Response caching (file-based, configurable TTL)
 //   - API-specific parsing and normalization
 //
+// # Registry Interface
+//
+// Each registry subpackage also provides a NewRegistry function that wraps
+// its Client in a [RegistryClient], which returns a shared [PackageInfo]
+// for every registry:
+//
+//	var reg integrations.RegistryClient = crates.NewRegistry(client)
+//	info, err := reg.FetchPackage(ctx, "serde", "", integrations.FetchOptions{})
+//
+// Code that works across registries, such as the fetchers and URL
+// providers in [deps], depends on this interface rather than on each
+// Client.
+//
 // # Shared Infrastructure
 //
 // The [Client] type provides shared HTTP functionality used by all registry
@@ -40,7 +54,8 @@
 //  2. Define response structs matching the API schema
 //  3. Implement a Client with FetchPackage method
 //  4. Use [NewClient] for HTTP with caching
-//  5. Wire into [deps] as a new language
+//  5. Implement [RegistryClient] with a NewRegistry adapter
+//  6. Wire into [deps] as a new language
 //
 // [pypi]: github.com/stacktower-io/stacktower/pkg/integrations/pypi
 // [npm]: github.com/stacktower-io/stacktower/pkg/integrations/npm
@@ -49,6 +64,7 @@
 // [packagist]: github.com/stacktower-io/stacktower/pkg/integrations/packagist
 // [maven]: github.com/stacktower-io/stacktower/pkg/integrations/maven
 // [goproxy]: github.com/stacktower-io/stacktower/pkg/integrations/goproxy
+// [nuget]: github.com/stacktower-io/stacktower/pkg/integrations/nuget
 // [github]: github.com/stacktower-io/stacktower/pkg/integrations/github
 // [gitlab]: github.com/stacktower-io/stacktower/pkg/integrations/gitlab
 // [cache.Cache]: github.com/stacktower-io/stacktower/pkg/cache.Cache
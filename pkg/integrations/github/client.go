@@ -2,9 +2,11 @@ package github
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,6 +23,9 @@ var repoURLPattern = regexp.MustCompile(`https?://github\.com/([^/]+)/([^/]+?)(?
 type Client struct {
 	*integrations.Client
 	baseURL string
+
+	quotaMu sync.Mutex
+	quotas  map[string]Quota // by resource, from the latest responses
 }
 
 // NewClient creates a GitHub API client with optional authentication and proactive rate limiting.
@@ -35,6 +40,8 @@ type Client struct {
 //   - Authenticated ("github"): 5,000 requests/hour per token (~1.4 req/s)
 //
 // Authentication is strongly recommended for production use to avoid rate limiting.
+// The client tracks the quota left from the X-RateLimit-* headers of its
+// responses; see [Client.Quota].
 // The returned Client is safe for concurrent use.
 func NewClient(backend cache.Cache, token string, cacheTTL time.Duration) *Client {
 	headers := map[string]string{"Accept": "application/vnd.github.v3+json"}
@@ -47,10 +54,12 @@ func NewClient(backend cache.Cache, token string, cacheTTL time.Duration) *Clien
 		rl = integrations.DefaultRateLimits["github_unauth"]
 	}
 
-	return &Client{
+	c := &Client{
 		Client:  integrations.NewClientWithRateLimit(backend, "github:", cacheTTL, headers, rl.RequestsPerSecond, rl.Burst),
 		baseURL: "https://api.github.com",
 	}
+	c.ObserveResponses(c.recordQuota)
+	return c
 }
 
 // Fetch retrieves repository metrics (stars, maintainers, activity) from GitHub.
@@ -89,6 +98,34 @@ func (c *Client) Fetch(ctx context.Context, owner, repo string, refresh bool) (*
 	return &m, nil
 }
 
+// FetchReadme returns the README of a repository as text, or "" if the
+// repository has none. Like [Client.Fetch], results are cached unless
+// refresh is set.
+func (c *Client) FetchReadme(ctx context.Context, owner, repo string, refresh bool) (string, error) {
+	var text string
+	err := c.Cached(ctx, "readme:"+owner+"/"+repo, refresh, &text, func() error {
+		var data readmeResponse
+		url := fmt.Sprintf("%s/repos/%s/%s/readme", c.baseURL, owner, repo)
+		if err := c.Get(ctx, url, &data); err != nil {
+			if errors.Is(err, integrations.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		if data.Encoding != "base64" {
+			text = data.Content
+			return nil
+		}
+		raw, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(data.Content, "\n", ""))
+		if err != nil {
+			return fmt.Errorf("decode readme of %s/%s: %w", owner, repo, err)
+		}
+		text = string(raw)
+		return nil
+	})
+	return text, err
+}
+
 func (c *Client) fetchMetrics(ctx context.Context, owner, repo string, m *integrations.RepoMetrics) error {
 	data, err := c.fetchRepo(ctx, owner, repo)
 	if err != nil {
@@ -109,10 +146,12 @@ func (c *Client) fetchMetrics(ctx context.Context, owner, repo string, m *integr
 	if data.PushedAt != nil {
 		m.LastCommitAt = data.PushedAt
 	}
+	m.CreatedAt = data.CreatedAt
 
-	// Fetch release and contributors in parallel (both are optional/best-effort)
+	// Fetch release, contributors and, for archived repos, a maintained fork
+	// in parallel (all are optional/best-effort)
 	var wg sync.WaitGroup
-	wg.Add(2)
+	wg.Add(3)
 	go func() {
 		defer wg.Done()
 		if rel, err := c.fetchRelease(ctx, owner, repo); err == nil {
@@ -125,6 +164,12 @@ func (c *Client) fetchMetrics(ctx context.Context, owner, repo string, m *integr
 			m.Contributors = contribs
 		}
 	}()
+	go func() {
+		defer wg.Done()
+		if data.Archived {
+			m.Successor = c.fetchSuccessor(ctx, owner, repo, data.PushedAt)
+		}
+	}()
 	wg.Wait()
 
 	return nil
@@ -170,6 +215,43 @@ func (c *Client) fetchContributors(ctx context.Context, owner, repo string) ([]i
 	return result, nil
 }
 
+// fetchSuccessor looks for a maintained fork of an archived repository: the
+// most-starred fork that is not archived itself, has at least
+// minSuccessorStars stars, and was pushed to within successorActiveWindow
+// and after the upstream's last push. Returns the fork's URL, or "" if none
+// qualifies or the lookup fails.
+func (c *Client) fetchSuccessor(ctx context.Context, owner, repo string, upstreamPushed *time.Time) string {
+	var forks []forkResponse
+	url := fmt.Sprintf("%s/repos/%s/%s/forks?sort=stargazers&per_page=10", c.baseURL, owner, repo)
+	if err := c.Get(ctx, url, &forks); err != nil {
+		return ""
+	}
+	return pickSuccessor(forks, upstreamPushed, time.Now())
+}
+
+func pickSuccessor(forks []forkResponse, upstreamPushed *time.Time, now time.Time) string {
+	var best *forkResponse
+	for i := range forks {
+		f := &forks[i]
+		if f.Archived || f.Stars < minSuccessorStars || f.PushedAt == nil {
+			continue
+		}
+		if now.Sub(*f.PushedAt) > successorActiveWindow {
+			continue
+		}
+		if upstreamPushed != nil && !f.PushedAt.After(*upstreamPushed) {
+			continue
+		}
+		if best == nil || f.Stars > best.Stars {
+			best = f
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.HTMLURL
+}
+
 // ExtractURL extracts GitHub repository owner and name from package URLs.
 //
 // This function searches through urls map and homepage for GitHub URLs.
@@ -194,6 +276,7 @@ type repoResponse struct {
 	Stars       int        `json:"stargazers_count"`
 	Size        int        `json:"size"`
 	PushedAt    *time.Time `json:"pushed_at"`
+	CreatedAt   *time.Time `json:"created_at"`
 	License     struct {
 		SPDXID string `json:"spdx_id"`
 	} `json:"license"`
@@ -202,6 +285,25 @@ type repoResponse struct {
 	Archived bool     `json:"archived"`
 }
 
+// Thresholds a fork must meet to be suggested as an archived repo's successor.
+const (
+	minSuccessorStars     = 5
+	successorActiveWindow = 365 * 24 * time.Hour
+)
+
+type forkResponse struct {
+	FullName string     `json:"full_name"`
+	HTMLURL  string     `json:"html_url"`
+	Stars    int        `json:"stargazers_count"`
+	PushedAt *time.Time `json:"pushed_at"`
+	Archived bool       `json:"archived"`
+}
+
+type readmeResponse struct {
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
 type releaseResponse struct {
 	PublishedAt time.Time `json:"published_at"`
 }
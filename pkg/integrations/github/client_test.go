@@ -166,8 +166,68 @@ func testClient(t *testing.T, serverURL, token string) *Client {
 	if token != "" {
 		headers["Authorization"] = "Bearer " + token
 	}
-	return &Client{
+	c := &Client{
 		Client:  integrations.NewClient(cache.NewNullCache(), "github:", time.Hour, headers),
 		baseURL: serverURL,
 	}
+	c.ObserveResponses(c.recordQuota)
+	return c
+}
+
+func TestClient_Fetch_ArchivedSuccessor(t *testing.T) {
+	recent := time.Now().Add(-30 * 24 * time.Hour)
+	archivedAt := time.Now().Add(-400 * 24 * time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/repos/owner/repo":
+			json.NewEncoder(w).Encode(repoResponse{Stars: 900, Archived: true, PushedAt: &archivedAt})
+		case "/repos/owner/repo/forks":
+			json.NewEncoder(w).Encode([]forkResponse{
+				{HTMLURL: "https://github.com/dead/repo", Stars: 50, PushedAt: &archivedAt},
+				{HTMLURL: "https://github.com/frozen/repo", Stars: 40, PushedAt: &recent, Archived: true},
+				{HTMLURL: "https://github.com/community/repo", Stars: 30, PushedAt: &recent},
+				{HTMLURL: "https://github.com/tiny/repo", Stars: 2, PushedAt: &recent},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := testClient(t, server.URL, "")
+
+	metrics, err := c.Fetch(context.Background(), "owner", "repo", true)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if metrics.Successor != "https://github.com/community/repo" {
+		t.Errorf("successor = %q, want the maintained community fork", metrics.Successor)
+	}
+}
+
+func TestClient_FetchReadme(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/pallets/flask/readme" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"content":  "IyBGbGFzawoKQSBt\naWNyb2ZyYW1ld29yay4K\n",
+			"encoding": "base64",
+		})
+	}))
+	defer server.Close()
+
+	c := testClient(t, server.URL, "")
+	text, err := c.FetchReadme(context.Background(), "pallets", "flask", false)
+	if err != nil || text != "# Flask\n\nA microframework.\n" {
+		t.Errorf("FetchReadme() = %q, %v", text, err)
+	}
+
+	text, err = c.FetchReadme(context.Background(), "pallets", "missing", false)
+	if err != nil || text != "" {
+		t.Errorf("FetchReadme() without a README = %q, %v; want no error", text, err)
+	}
 }
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"path"
 	"sort"
 	"strings"
 	"sync"
@@ -373,7 +374,7 @@ func (c *ContentClient) DetectManifests(ctx context.Context, owner, repo, ref st
 	var manifests []ManifestFile
 	for _, item := range items {
 		if item.Type == "file" {
-			if lang, ok := patterns[item.Name]; ok {
+			if lang, ok := matchManifest(patterns, item.Name); ok {
 				manifests = append(manifests, ManifestFile{
 					Path:     item.Path,
 					Language: lang,
@@ -386,6 +387,23 @@ func (c *ContentClient) DetectManifests(ctx context.Context, owner, repo, ref st
 	return manifests, nil
 }
 
+// matchManifest returns the language of a manifest filename. Patterns are
+// filenames or glob patterns like "*.csproj"; an exact filename wins.
+func matchManifest(patterns map[string]string, name string) (string, bool) {
+	if lang, ok := patterns[name]; ok {
+		return lang, true
+	}
+	for pattern, lang := range patterns {
+		if !strings.ContainsAny(pattern, "*?[") {
+			continue
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
 // setHeaders sets common headers for GitHub API requests.
 func (c *ContentClient) setHeaders(req *http.Request) {
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
@@ -752,7 +770,7 @@ func (c *ContentClient) DetectManifestsRecursive(ctx context.Context, owner, rep
 			name = entry.Path[idx+1:]
 		}
 
-		if lang, ok := patterns[name]; ok {
+		if lang, ok := matchManifest(patterns, name); ok {
 			manifests = append(manifests, ManifestFile{
 				Path:     entry.Path,
 				Language: lang,
@@ -854,7 +872,7 @@ func DetectLanguageFromManifest(path string) string {
 	}
 
 	switch name {
-	case "package.json", "package-lock.json":
+	case "package.json", "package-lock.json", "yarn.lock", "pnpm-lock.yaml":
 		return "javascript"
 	case "requirements.txt", "setup.py", "pyproject.toml", "Pipfile":
 		return "python"
@@ -868,6 +886,12 @@ func DetectLanguageFromManifest(path string) string {
 		return "php"
 	case "pom.xml", "build.gradle":
 		return "java"
+	case "packages.config":
+		return "dotnet"
+	}
+	switch {
+	case strings.HasSuffix(name, ".csproj"), strings.HasSuffix(name, ".fsproj"), strings.HasSuffix(name, ".vbproj"):
+		return "dotnet"
 	default:
 		return ""
 	}
@@ -10,9 +10,10 @@ import (
 	"github.com/stacktower-io/stacktower/pkg/integrations"
 )
 
-// maxReposPerQuery is the maximum repos in a single GraphQL request.
+// MaxReposPerQuery is the maximum repos in a single GraphQL request, each
+// of which costs one point of the GraphQL rate limit.
 // GitHub GraphQL has a node limit; 100 repos is safe and well within it.
-const maxReposPerQuery = 100
+const MaxReposPerQuery = 100
 
 // RepoID identifies a GitHub repository for batch fetching.
 type RepoID struct {
@@ -42,6 +43,7 @@ type graphqlRepo struct {
 	Description    string `json:"description"`
 	IsArchived     bool   `json:"isArchived"`
 	PushedAt       string `json:"pushedAt"`
+	CreatedAt      string `json:"createdAt"`
 	LicenseInfo    *struct {
 		SpdxID string `json:"spdxId"`
 	} `json:"licenseInfo"`
@@ -66,6 +68,7 @@ const repoFragment = `
     description
     isArchived
     pushedAt
+    createdAt
     licenseInfo { spdxId }
     primaryLanguage { name }
     repositoryTopics(first: 10) { nodes { topic { name } } }
@@ -89,9 +92,9 @@ func buildGraphQLQuery(repos []RepoID) string {
 func (c *Client) FetchBatch(ctx context.Context, repos []RepoID, refresh bool) (map[string]*integrations.RepoMetrics, error) {
 	result := make(map[string]*integrations.RepoMetrics, len(repos))
 
-	// Process in batches of maxReposPerQuery
-	for start := 0; start < len(repos); start += maxReposPerQuery {
-		end := start + maxReposPerQuery
+	// Process in batches of MaxReposPerQuery
+	for start := 0; start < len(repos); start += MaxReposPerQuery {
+		end := start + MaxReposPerQuery
 		if end > len(repos) {
 			end = len(repos)
 		}
@@ -156,6 +159,11 @@ func graphqlRepoToMetrics(repo RepoID, data graphqlRepo) *integrations.RepoMetri
 			m.LastCommitAt = &t
 		}
 	}
+	if data.CreatedAt != "" {
+		if t, err := time.Parse(time.RFC3339, data.CreatedAt); err == nil {
+			m.CreatedAt = &t
+		}
+	}
 	if data.LatestRelease != nil && data.LatestRelease.PublishedAt != "" {
 		if t, err := time.Parse(time.RFC3339, data.LatestRelease.PublishedAt); err == nil {
 			m.LastReleaseAt = &t
@@ -205,3 +213,31 @@ func (c *Client) FetchContributorsBatch(ctx context.Context, repos []RepoID) map
 	wg.Wait()
 	return result
 }
+
+// FetchSuccessors looks up a maintained fork for every archived repository in
+// metrics (as returned by [Client.FetchBatch]) and records it in
+// RepoMetrics.Successor. Like contributors, forks come from the REST API;
+// lookups that fail or find no suitable fork leave Successor empty.
+func (c *Client) FetchSuccessors(ctx context.Context, metrics map[string]*integrations.RepoMetrics) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 10)
+
+	for key, m := range metrics {
+		if !m.Archived {
+			continue
+		}
+		owner, repo, ok := strings.Cut(key, "/")
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(m *integrations.RepoMetrics) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			m.Successor = c.fetchSuccessor(ctx, owner, repo, m.LastCommitAt)
+		}(m)
+	}
+
+	wg.Wait()
+}
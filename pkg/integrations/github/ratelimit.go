@@ -0,0 +1,98 @@
+package github
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Resources of the GitHub API with rate limits of their own.
+const (
+	ResourceCore    = "core"    // the REST API
+	ResourceGraphQL = "graphql" // the GraphQL API, counted in query points
+)
+
+// Quota is the state of one of the GitHub API rate limits.
+type Quota struct {
+	Resource  string    // ResourceCore or ResourceGraphQL
+	Limit     int       // requests (or points) per window
+	Remaining int       // requests (or points) left in the window
+	Reset     time.Time // when the window ends and Remaining goes back to Limit
+}
+
+// parseQuota reads the X-RateLimit-* headers GitHub sends with every
+// response, reporting whether there were any.
+func parseQuota(h http.Header) (Quota, bool) {
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return Quota{}, false
+	}
+	q := Quota{Resource: cmp.Or(h.Get("X-RateLimit-Resource"), ResourceCore), Remaining: remaining}
+	q.Limit, _ = strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		q.Reset = time.Unix(reset, 0)
+	}
+	return q, true
+}
+
+// recordQuota keeps the quota the headers of a response report. Responses
+// to concurrent requests arrive out of order, so within one window the
+// lowest Remaining wins.
+func (c *Client) recordQuota(h http.Header) {
+	q, ok := parseQuota(h)
+	if !ok {
+		return
+	}
+	c.setQuota(q)
+}
+
+func (c *Client) setQuota(q Quota) {
+	c.quotaMu.Lock()
+	defer c.quotaMu.Unlock()
+	if c.quotas == nil {
+		c.quotas = make(map[string]Quota)
+	}
+	if prev, ok := c.quotas[q.Resource]; ok && prev.Reset.Equal(q.Reset) && prev.Remaining < q.Remaining {
+		return
+	}
+	c.quotas[q.Resource] = q
+}
+
+// Quota returns the latest known state of the rate limit of resource,
+// reporting whether it is known. A quota whose window has ended is
+// unknown again.
+func (c *Client) Quota(resource string) (Quota, bool) {
+	c.quotaMu.Lock()
+	defer c.quotaMu.Unlock()
+	q, ok := c.quotas[resource]
+	if !ok || (!q.Reset.IsZero() && time.Now().After(q.Reset)) {
+		return Quota{}, false
+	}
+	return q, true
+}
+
+type rateLimitResponse struct {
+	Resources map[string]struct {
+		Limit     int   `json:"limit"`
+		Remaining int   `json:"remaining"`
+		Reset     int64 `json:"reset"`
+	} `json:"resources"`
+}
+
+// FetchQuota reads the state of the REST and GraphQL rate limits from the
+// /rate_limit endpoint, which does not count against them, so that
+// [Client.Quota] knows them before the first metered request.
+func (c *Client) FetchQuota(ctx context.Context) error {
+	var data rateLimitResponse
+	if err := c.Get(ctx, c.baseURL+"/rate_limit", &data); err != nil {
+		return err
+	}
+	for _, resource := range []string{ResourceCore, ResourceGraphQL} {
+		if r, ok := data.Resources[resource]; ok {
+			c.setQuota(Quota{Resource: resource, Limit: r.Limit, Remaining: r.Remaining, Reset: time.Unix(r.Reset, 0)})
+		}
+	}
+	return nil
+}
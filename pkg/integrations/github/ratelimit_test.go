@@ -0,0 +1,88 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseQuota(t *testing.T) {
+	h := http.Header{}
+	if _, ok := parseQuota(h); ok {
+		t.Error("parseQuota() found a quota without headers")
+	}
+
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	h.Set("X-RateLimit-Limit", "5000")
+	h.Set("X-RateLimit-Remaining", "42")
+	h.Set("X-RateLimit-Reset", fmt.Sprint(reset.Unix()))
+	q, ok := parseQuota(h)
+	if !ok || q.Resource != ResourceCore || q.Limit != 5000 || q.Remaining != 42 || !q.Reset.Equal(reset) {
+		t.Errorf("parseQuota() = %+v, %v", q, ok)
+	}
+
+	h.Set("X-RateLimit-Resource", "graphql")
+	if q, _ := parseQuota(h); q.Resource != ResourceGraphQL {
+		t.Errorf("resource = %q, want graphql", q.Resource)
+	}
+}
+
+func TestClient_QuotaFromResponses(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	remaining := []string{"30", "29", "31"} // the last response answers an earlier request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", remaining[0])
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset))
+		remaining = remaining[1:]
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	c := testClient(t, server.URL, "")
+	if _, ok := c.Quota(ResourceCore); ok {
+		t.Error("quota known before any response")
+	}
+	for range 3 {
+		_, _ = c.fetchRepo(context.Background(), "owner", "repo")
+	}
+	if q, ok := c.Quota(ResourceCore); !ok || q.Remaining != 29 {
+		t.Errorf("Quota() = %+v, %v; want the lowest remaining of the window", q, ok)
+	}
+}
+
+func TestClient_QuotaExpires(t *testing.T) {
+	c := testClient(t, "http://unused", "")
+	c.setQuota(Quota{Resource: ResourceCore, Remaining: 0, Reset: time.Now().Add(-time.Minute)})
+	if _, ok := c.Quota(ResourceCore); ok {
+		t.Error("quota of an ended window still known")
+	}
+}
+
+func TestClient_FetchQuota(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rate_limit" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"resources": {
+			"core": {"limit": 60, "remaining": 12, "reset": %d},
+			"graphql": {"limit": 5000, "remaining": 4999, "reset": %d}
+		}}`, reset, reset)
+	}))
+	defer server.Close()
+
+	c := testClient(t, server.URL, "")
+	if err := c.FetchQuota(context.Background()); err != nil {
+		t.Fatalf("FetchQuota() error = %v", err)
+	}
+	if q, ok := c.Quota(ResourceCore); !ok || q.Limit != 60 || q.Remaining != 12 {
+		t.Errorf("core quota = %+v, %v", q, ok)
+	}
+	if q, ok := c.Quota(ResourceGraphQL); !ok || q.Remaining != 4999 {
+		t.Errorf("graphql quota = %+v, %v", q, ok)
+	}
+}
@@ -21,6 +21,27 @@ type Dependency struct {
 	Constraint string // Version requirement from go.mod (e.g., "v1.2.3")
 }
 
+// Module identifies a module, at a version when Version is set.
+type Module struct {
+	Path    string // Module path, or a directory for a local replacement
+	Version string // Version (e.g., "v1.2.3"), empty when not pinned
+}
+
+func (m Module) String() string {
+	if m.Version == "" {
+		return m.Path
+	}
+	return m.Path + "@" + m.Version
+}
+
+// Replacement is a replace directive of a go.mod file. It replaces Old, at
+// every version when Old.Version is empty, with New, which is a local
+// directory when New.Version is empty.
+type Replacement struct {
+	Old Module
+	New Module
+}
+
 // ModuleInfo holds metadata for a Go module from the Go module proxy.
 //
 // Dependencies include only direct dependencies; indirect dependencies are stored separately
@@ -33,13 +54,15 @@ type Dependency struct {
 // Zero values: All string fields are empty, Dependencies is nil.
 // This struct is safe for concurrent reads after construction.
 type ModuleInfo struct {
-	Path                 string       // Module path (e.g., "github.com/spf13/cobra", never empty in valid info)
-	Version              string       // Latest version from @latest endpoint (e.g., "v1.8.0", never empty in valid info)
-	Dependencies         []Dependency // Direct dependencies with versions (nil or empty if none or no go.mod)
-	IndirectDependencies []Dependency // Indirect dependencies (marked with "// indirect" in go.mod)
-	GoVersion            string       // Go version from go.mod (e.g., "1.21", may be empty for old modules)
-	License              string       // License identifier if detectable (may be empty)
-	Repository           string       // Canonical source repository URL if discoverable (may be empty)
+	Path                 string        // Module path (e.g., "github.com/spf13/cobra", never empty in valid info)
+	Version              string        // Latest version from @latest endpoint (e.g., "v1.8.0", never empty in valid info)
+	Dependencies         []Dependency  // Direct dependencies with versions (nil or empty if none or no go.mod)
+	IndirectDependencies []Dependency  // Indirect dependencies (marked with "// indirect" in go.mod)
+	Replace              []Replacement // Replace directives; Go only applies those of the main module
+	Exclude              []Module      // Exclude directives; Go only applies those of the main module
+	GoVersion            string        // Go version from go.mod (e.g., "1.21", may be empty for old modules)
+	License              string        // License identifier if detectable (may be empty)
+	Repository           string        // Canonical source repository URL if discoverable (may be empty)
 }
 
 // Client provides access to the Go module proxy API.
@@ -177,6 +200,8 @@ func (c *Client) fetch(ctx context.Context, mod, version string, info *ModuleInf
 		info.Dependencies = goModResult.directDeps
 		info.IndirectDependencies = goModResult.indirectDeps
 		info.GoVersion = goModResult.goVersion
+		info.Replace = goModResult.replace
+		info.Exclude = goModResult.exclude
 	}
 
 	return nil
@@ -347,6 +372,8 @@ type goModParseResult struct {
 	goVersion    string
 	directDeps   []Dependency
 	indirectDeps []Dependency
+	replace      []Replacement
+	exclude      []Module
 }
 
 func (c *Client) fetchGoMod(ctx context.Context, mod, version string) (*goModParseResult, error) {
@@ -360,12 +387,12 @@ func (c *Client) fetchGoMod(ctx context.Context, mod, version string) (*goModPar
 }
 
 // parseGoModComplete parses a go.mod file and returns both direct and indirect
-// dependencies along with the go version directive.
+// dependencies along with the go version, replace and exclude directives.
 func parseGoModComplete(r io.Reader) (*goModParseResult, error) {
 	result := &goModParseResult{}
 	seenDirect := make(map[string]bool)
 	seenIndirect := make(map[string]bool)
-	inRequire := false
+	block := "" // directive of the enclosing ( ... ) block, if any
 
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
@@ -382,39 +409,48 @@ func parseGoModComplete(r io.Reader) (*goModParseResult, error) {
 			continue
 		}
 
-		// Handle require block
-		if strings.HasPrefix(line, "require (") || line == "require(" {
-			inRequire = true
+		// Handle blocks such as require ( ... ) and replace ( ... )
+		if verb, ok := strings.CutSuffix(line, "("); ok && block == "" {
+			block = strings.TrimSpace(verb)
 			continue
 		}
-		if inRequire && line == ")" {
-			inRequire = false
+		if block != "" && line == ")" {
+			block = ""
 			continue
 		}
 
-		// Single-line require
-		if strings.HasPrefix(line, "require ") && !strings.Contains(line, "(") {
-			line = strings.TrimPrefix(line, "require ")
-		} else if !inRequire {
-			continue
+		verb, rest := block, line
+		if block == "" {
+			verb, rest, _ = strings.Cut(line, " ")
+			rest = strings.TrimSpace(rest)
 		}
 
-		// Parse module path and version from require line
-		// Format: module/path v1.2.3 [// indirect]
-		dep, isIndirect := parseRequireLineComplete(line)
-		if dep.Name == "" {
-			continue
-		}
+		switch verb {
+		case "require":
+			// Format: module/path v1.2.3 [// indirect]
+			dep, isIndirect := parseRequireLineComplete(rest)
+			if dep.Name == "" {
+				continue
+			}
 
-		if isIndirect {
-			if !seenIndirect[dep.Name] {
-				seenIndirect[dep.Name] = true
-				result.indirectDeps = append(result.indirectDeps, dep)
+			if isIndirect {
+				if !seenIndirect[dep.Name] {
+					seenIndirect[dep.Name] = true
+					result.indirectDeps = append(result.indirectDeps, dep)
+				}
+			} else {
+				if !seenDirect[dep.Name] {
+					seenDirect[dep.Name] = true
+					result.directDeps = append(result.directDeps, dep)
+				}
 			}
-		} else {
-			if !seenDirect[dep.Name] {
-				seenDirect[dep.Name] = true
-				result.directDeps = append(result.directDeps, dep)
+		case "replace":
+			if rep, ok := parseReplaceLine(rest); ok {
+				result.replace = append(result.replace, rep)
+			}
+		case "exclude":
+			if fields := strings.Fields(stripComment(rest)); len(fields) == 2 {
+				result.exclude = append(result.exclude, Module{Path: strings.Trim(fields[0], `"`), Version: fields[1]})
 			}
 		}
 	}
@@ -422,6 +458,45 @@ func parseGoModComplete(r io.Reader) (*goModParseResult, error) {
 	return result, scanner.Err()
 }
 
+// parseReplaceLine parses the arguments of a replace directive:
+// "old [version] => new [version]".
+func parseReplaceLine(line string) (Replacement, bool) {
+	oldPart, newPart, ok := strings.Cut(stripComment(line), "=>")
+	if !ok {
+		return Replacement{}, false
+	}
+	oldMod, ok := parseModuleFields(oldPart)
+	if !ok {
+		return Replacement{}, false
+	}
+	newMod, ok := parseModuleFields(newPart)
+	if !ok {
+		return Replacement{}, false
+	}
+	return Replacement{Old: oldMod, New: newMod}, true
+}
+
+// parseModuleFields parses "path [version]" on either side of a replace
+// directive.
+func parseModuleFields(s string) (Module, bool) {
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+		return Module{Path: strings.Trim(fields[0], `"`)}, true
+	case 2:
+		return Module{Path: strings.Trim(fields[0], `"`), Version: fields[1]}, true
+	}
+	return Module{}, false
+}
+
+// stripComment removes a trailing // comment from a go.mod line.
+func stripComment(line string) string {
+	if idx := strings.Index(line, "//"); idx != -1 {
+		line = line[:idx]
+	}
+	return strings.TrimSpace(line)
+}
+
 // parseRequireLineComplete parses a require line and returns a Dependency
 // along with a flag indicating if it's an indirect dependency.
 func parseRequireLineComplete(line string) (Dependency, bool) {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -113,6 +114,92 @@ func TestParseRequireLineComplete(t *testing.T) {
 	}
 }
 
+func TestParseGoModComplete_ReplaceExclude(t *testing.T) {
+	content := `module github.com/example/myapp
+
+go 1.21
+
+require (
+	github.com/pkg/errors v0.9.1
+	golang.org/x/net v0.10.0
+)
+
+replace github.com/pkg/errors => github.com/fork/errors v0.9.2
+
+replace (
+	golang.org/x/net v0.10.0 => golang.org/x/net v0.12.0 // security fix
+	example.com/local => ../local
+)
+
+exclude golang.org/x/text v0.3.0
+
+exclude (
+	golang.org/x/sys v0.1.0
+)
+
+retract (
+	v1.0.0 // published by mistake
+)
+`
+
+	result, err := parseGoModComplete(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("parseGoModComplete failed: %v", err)
+	}
+
+	if len(result.directDeps) != 2 {
+		t.Errorf("expected 2 direct deps, got %d: %v", len(result.directDeps), result.directDeps)
+	}
+
+	wantReplace := []Replacement{
+		{Old: Module{Path: "github.com/pkg/errors"}, New: Module{Path: "github.com/fork/errors", Version: "v0.9.2"}},
+		{Old: Module{Path: "golang.org/x/net", Version: "v0.10.0"}, New: Module{Path: "golang.org/x/net", Version: "v0.12.0"}},
+		{Old: Module{Path: "example.com/local"}, New: Module{Path: "../local"}},
+	}
+	if !slices.Equal(result.replace, wantReplace) {
+		t.Errorf("replace = %v, want %v", result.replace, wantReplace)
+	}
+
+	wantExclude := []Module{
+		{Path: "golang.org/x/text", Version: "v0.3.0"},
+		{Path: "golang.org/x/sys", Version: "v0.1.0"},
+	}
+	if !slices.Equal(result.exclude, wantExclude) {
+		t.Errorf("exclude = %v, want %v", result.exclude, wantExclude)
+	}
+}
+
+func TestParseReplaceLine(t *testing.T) {
+	tests := []struct {
+		line   string
+		want   Replacement
+		wantOK bool
+	}{
+		{"a.com/x => b.com/x v1.0.0", Replacement{Old: Module{Path: "a.com/x"}, New: Module{Path: "b.com/x", Version: "v1.0.0"}}, true},
+		{"a.com/x v1.0.0 => ./x", Replacement{Old: Module{Path: "a.com/x", Version: "v1.0.0"}, New: Module{Path: "./x"}}, true},
+		{"a.com/x v1.0.0", Replacement{}, false},
+		{"=> b.com/x v1.0.0", Replacement{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, ok := parseReplaceLine(tt.line)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseReplaceLine(%q) = %v, %v, want %v, %v", tt.line, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestModule_String(t *testing.T) {
+	if got := (Module{Path: "a.com/x", Version: "v1.0.0"}).String(); got != "a.com/x@v1.0.0" {
+		t.Errorf("String() = %q, want a.com/x@v1.0.0", got)
+	}
+	if got := (Module{Path: "../x"}).String(); got != "../x" {
+		t.Errorf("String() = %q, want ../x", got)
+	}
+}
+
 func TestClient_FetchModule(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
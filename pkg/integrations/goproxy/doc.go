@@ -35,8 +35,16 @@
 //
 // # Dependency Filtering
 //
-// Only direct dependencies are included. Indirect dependencies (marked with
-// "// indirect" comment) are filtered out.
+// Direct dependencies are listed in Dependencies. Indirect dependencies
+// (marked with an "// indirect" comment) are kept apart in
+// IndirectDependencies.
+//
+// # Replace and Exclude Directives
+//
+// The replace and exclude directives of go.mod are returned as Replace and
+// Exclude. The client does not apply them: Go only honors those of the main
+// module, so it is up to the resolver to apply the root module's directives
+// across the graph.
 //
 // # Two-Phase Fetch
 //
@@ -0,0 +1,57 @@
+package goproxy
+
+import (
+	"context"
+
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+// Registry adapts a [Client] to [integrations.RegistryClient].
+type Registry struct {
+	client *Client
+}
+
+// NewRegistry returns c as an [integrations.RegistryClient].
+func NewRegistry(c *Client) *Registry {
+	return &Registry{client: c}
+}
+
+var _ integrations.RegistryClient = (*Registry)(nil)
+
+// Name returns "goproxy".
+func (r *Registry) Name() string { return "goproxy" }
+
+// FetchPackage implements [integrations.RegistryClient]. Dependencies are
+// the direct requirements of the go.mod file, and the runtime constraint is
+// its go directive.
+func (r *Registry) FetchPackage(ctx context.Context, name, version string, opts integrations.FetchOptions) (*integrations.PackageInfo, error) {
+	var (
+		m   *ModuleInfo
+		err error
+	)
+	if version == "" {
+		m, err = r.client.FetchModule(ctx, name, opts.Refresh)
+	} else {
+		m, err = r.client.FetchModuleVersion(ctx, name, version, opts.Refresh)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info := &integrations.PackageInfo{
+		Name:              m.Path,
+		Version:           m.Version,
+		License:           m.License,
+		Repository:        m.Repository,
+		RuntimeConstraint: m.GoVersion,
+	}
+	for _, d := range m.Dependencies {
+		info.Dependencies = append(info.Dependencies, integrations.Dependency{Name: d.Name, Constraint: d.Constraint})
+	}
+	return info, nil
+}
+
+// ListVersions implements [integrations.RegistryClient].
+func (r *Registry) ListVersions(ctx context.Context, name string, opts integrations.FetchOptions) ([]string, error) {
+	return r.client.ListVersions(ctx, name, opts.Refresh)
+}
@@ -1,11 +1,13 @@
 package maven
 
 import (
+	"cmp"
 	"context"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,14 +18,16 @@ import (
 // Dependency represents a Maven dependency with version information.
 type Dependency struct {
 	Name       string // Maven coordinate (groupId:artifactId)
-	Constraint string // Version from POM (may contain Maven properties or ranges)
+	Constraint string // Version or version range from the effective POM; empty when unknown
 }
 
 // ArtifactInfo holds metadata for a Java artifact from Maven Central.
 //
 // Artifacts are identified by "groupId:artifactId" coordinates.
-// Dependencies include only compile-scope dependencies; test, provided, and optional deps are excluded.
-// Dependencies with unresolved Maven properties (${...}) are skipped.
+// Dependencies include only compile and runtime dependencies; test, provided, and optional deps are excluded.
+// They are read from the effective POM: versions may come from parent POMs, properties,
+// or imported BOMs, and dependencies whose coordinates stay unresolved are skipped.
+// For artifacts published with Gradle Module Metadata, they come from the runtime variant instead.
 //
 // Zero values: All string fields are empty, Dependencies is nil.
 // This struct is safe for concurrent reads after construction.
@@ -51,7 +55,8 @@ func (a *ArtifactInfo) Coordinate() string {
 // All methods are safe for concurrent use by multiple goroutines.
 type Client struct {
 	*integrations.Client
-	baseURL string
+	baseURL string // search API
+	repoURL string // repository root, holding POMs and maven-metadata.xml
 }
 
 // NewClient creates a Maven Central client with the given cache backend.
@@ -66,6 +71,7 @@ func NewClient(backend cache.Cache, cacheTTL time.Duration) *Client {
 	return &Client{
 		Client:  integrations.NewClientWithRateLimit(backend, "maven:", cacheTTL, nil, rl.RequestsPerSecond, rl.Burst),
 		baseURL: "https://search.maven.org/solrsearch/select",
+		repoURL: "https://repo1.maven.org/maven2",
 	}
 }
 
@@ -78,9 +84,10 @@ func NewClient(backend cache.Cache, cacheTTL time.Duration) *Client {
 // If refresh is true, the cache is bypassed and a fresh API call is made.
 // If refresh is false, cached data is returned if available and not expired.
 //
-// This method performs two API calls:
-//  1. Maven Central Search API to find the latest version
-//  2. Direct POM fetch to extract dependencies
+// The latest version is the newest release listed in the artifact's
+// maven-metadata.xml, or the search API's latest version when the
+// metadata is unavailable. Dependencies come from the effective POM, with
+// parents, properties and imported BOMs resolved.
 //
 // POM fetch failures are silently ignored; Dependencies will be empty/nil if it fails.
 //
@@ -146,27 +153,11 @@ func (c *Client) fetch(ctx context.Context, groupID, artifactID, version string,
 	targetVersion := version
 
 	if targetVersion == "" {
-		// Get the latest version from search API
-		query := fmt.Sprintf("g:%q AND a:%q", groupID, artifactID)
-		url := fmt.Sprintf("%s?q=%s&rows=1&wt=json", c.baseURL, integrations.URLEncode(query))
-
-		var searchResp searchResponse
-		if err := c.Get(ctx, url, &searchResp); err != nil {
-			if errors.Is(err, integrations.ErrNotFound) {
-				return fmt.Errorf("%w: maven artifact %s:%s", err, groupID, artifactID)
-			}
+		v, err := c.latestVersion(ctx, groupID, artifactID)
+		if err != nil {
 			return err
 		}
-
-		if searchResp.Response.NumFound == 0 {
-			return fmt.Errorf("%w: maven artifact %s:%s", integrations.ErrNotFound, groupID, artifactID)
-		}
-
-		doc := searchResp.Response.Docs[0]
-		targetVersion = doc.LatestVersion
-		if targetVersion == "" {
-			targetVersion = doc.Version
-		}
+		targetVersion = v
 	}
 
 	// Fetch POM to get dependencies, URLs, and license
@@ -196,36 +187,79 @@ func (c *Client) ListVersions(ctx context.Context, coordinate string, refresh bo
 
 	var versions []string
 	err = c.Cached(ctx, key, refresh, &versions, func() error {
-		// Fetch from maven-metadata.xml which contains all versions
-		groupPath := strings.ReplaceAll(groupID, ".", "/")
-		metadataURL := fmt.Sprintf("https://repo1.maven.org/maven2/%s/%s/maven-metadata.xml",
-			groupPath, artifactID)
-
-		body, err := c.GetText(ctx, metadataURL)
-		if err != nil {
+		md, err := c.fetchMetadata(ctx, groupID, artifactID)
+		if err != nil || len(md.Versioning.Versions) == 0 {
 			// Fallback to search API if metadata not available
 			return c.listVersionsFromSearch(ctx, groupID, artifactID, &versions)
 		}
+		versions = md.Versioning.Versions
+		slices.SortStableFunc(versions, CompareVersions)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
 
-		// Parse maven-metadata.xml
-		var metadata struct {
-			Versioning struct {
-				Versions []string `xml:"versions>version"`
-			} `xml:"versioning"`
+// latestVersion returns the newest release of an artifact: the newest
+// stable version in its metadata, else its newest non-snapshot version,
+// else what the search API reports.
+func (c *Client) latestVersion(ctx context.Context, groupID, artifactID string) (string, error) {
+	if md, err := c.fetchMetadata(ctx, groupID, artifactID); err == nil {
+		var latest, latestStable string
+		for _, v := range md.Versioning.Versions {
+			if strings.HasSuffix(strings.ToUpper(v), "-SNAPSHOT") {
+				continue
+			}
+			if latest == "" || CompareVersions(v, latest) > 0 {
+				latest = v
+			}
+			if IsStableVersion(v) && (latestStable == "" || CompareVersions(v, latestStable) > 0) {
+				latestStable = v
+			}
 		}
-		if err := xml.Unmarshal([]byte(body), &metadata); err != nil {
-			return c.listVersionsFromSearch(ctx, groupID, artifactID, &versions)
+		if v := cmp.Or(latestStable, latest, md.Versioning.Release); v != "" {
+			return v, nil
 		}
+	}
 
-		versions = metadata.Versioning.Versions
-		// Sort versions semantically (oldest to newest)
-		integrations.SortVersions(versions)
-		return nil
-	})
+	query := fmt.Sprintf("g:%q AND a:%q", groupID, artifactID)
+	url := fmt.Sprintf("%s?q=%s&rows=1&wt=json", c.baseURL, integrations.URLEncode(query))
+
+	var searchResp searchResponse
+	if err := c.Get(ctx, url, &searchResp); err != nil {
+		if errors.Is(err, integrations.ErrNotFound) {
+			return "", fmt.Errorf("%w: maven artifact %s:%s", err, groupID, artifactID)
+		}
+		return "", err
+	}
+	if searchResp.Response.NumFound == 0 || len(searchResp.Response.Docs) == 0 {
+		return "", fmt.Errorf("%w: maven artifact %s:%s", integrations.ErrNotFound, groupID, artifactID)
+	}
+	doc := searchResp.Response.Docs[0]
+	return cmp.Or(doc.LatestVersion, doc.Version), nil
+}
+
+// mavenMetadata is the part of maven-metadata.xml the client reads.
+type mavenMetadata struct {
+	Versioning struct {
+		Release  string   `xml:"release"`
+		Versions []string `xml:"versions>version"`
+	} `xml:"versioning"`
+}
+
+func (c *Client) fetchMetadata(ctx context.Context, groupID, artifactID string) (*mavenMetadata, error) {
+	body, err := c.GetText(ctx, fmt.Sprintf("%s/%s/%s/maven-metadata.xml",
+		c.repoURL, strings.ReplaceAll(groupID, ".", "/"), artifactID))
 	if err != nil {
 		return nil, err
 	}
-	return versions, nil
+	var md mavenMetadata
+	if err := xml.Unmarshal([]byte(body), &md); err != nil {
+		return nil, err
+	}
+	return &md, nil
 }
 
 func (c *Client) listVersionsFromSearch(ctx context.Context, groupID, artifactID string, versions *[]string) error {
@@ -256,19 +290,28 @@ type pomInfo struct {
 }
 
 func (c *Client) fetchPOMDeps(ctx context.Context, groupID, artifactID, version string) *pomInfo {
-	groupPath := strings.ReplaceAll(groupID, ".", "/")
-	pomURL := fmt.Sprintf("https://repo1.maven.org/maven2/%s/%s/%s/%s-%s.pom",
-		groupPath, artifactID, version, artifactID, version)
-
-	pom, err := c.fetchPOM(ctx, pomURL)
+	raw, err := c.fetchPOM(ctx, groupID, artifactID, version)
 	if err != nil {
 		slog.Debug("maven: failed to fetch POM", "group", groupID, "artifact", artifactID, "version", version, "error", err)
 		return &pomInfo{}
 	}
+	b := modelBuilder{ctx: ctx, client: c}
+	pom := b.effective(raw, "", 0)
+	deps := extractDeps(pom)
+
+	// Gradle Module Metadata lists the dependencies of the runtime variant
+	// exactly, where the POM Gradle derives from it loses detail
+	if raw.GradleMetadata {
+		if mdeps, err := c.fetchModuleDeps(ctx, groupID, artifactID, version); err == nil {
+			deps = mdeps
+		} else {
+			slog.Debug("maven: failed to read Gradle module metadata", "group", groupID, "artifact", artifactID, "version", version, "error", err)
+		}
+	}
 
 	repo, home := extractURLs(pom)
 	info := &pomInfo{
-		Dependencies: extractDeps(pom),
+		Dependencies: deps,
 		Repository:   repo,
 		HomePage:     home,
 	}
@@ -323,39 +366,57 @@ func isRepoURL(url string) bool {
 	return false
 }
 
-func (c *Client) fetchPOM(ctx context.Context, url string) (*pomProject, error) {
-	text, err := c.GetText(ctx, url)
-	if err != nil {
-		return nil, err
-	}
+// fetchPOM returns the POM of one artifact version as published. Released
+// POMs never change, and parents and BOMs are shared by many artifacts, so
+// they are cached without refresh.
+func (c *Client) fetchPOM(ctx context.Context, groupID, artifactID, version string) (*pomProject, error) {
+	key := "pom:" + groupID + ":" + artifactID + "@" + version
 
 	var pom pomProject
-	if err := xml.Unmarshal([]byte(text), &pom); err != nil {
+	err := c.Cached(ctx, key, false, &pom, func() error {
+		text, err := c.GetText(ctx, fmt.Sprintf("%s/%s/%s/%s/%s-%s.pom",
+			c.repoURL, strings.ReplaceAll(groupID, ".", "/"), artifactID, version, artifactID, version))
+		if err != nil {
+			return err
+		}
+		if err := xml.Unmarshal([]byte(text), &pom); err != nil {
+			return err
+		}
+		pom.GradleMetadata = strings.Contains(text, gradleMetadataMarker)
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &pom, nil
 }
 
+// extractDeps returns the runtime dependencies of an effective POM.
 func extractDeps(pom *pomProject) []Dependency {
 	var deps []Dependency
 	seen := make(map[string]bool)
 
 	for _, dep := range pom.Dependencies {
-		if dep.Scope == "test" || dep.Scope == "provided" || dep.Optional == "true" {
+		switch dep.Scope {
+		case "test", "provided", "system", "import":
 			continue
 		}
-		// Skip dependencies with unresolved properties in groupId or artifactId
-		if strings.HasPrefix(dep.GroupID, "${") || strings.HasPrefix(dep.ArtifactID, "${") {
+		if dep.Optional == "true" {
+			continue
+		}
+		// Skip dependencies whose coordinates reference undefined properties
+		if strings.Contains(dep.GroupID, "${") || strings.Contains(dep.ArtifactID, "${") {
 			continue
 		}
 		coord := dep.GroupID + ":" + dep.ArtifactID
 		if !seen[coord] {
 			seen[coord] = true
-			// Include version as constraint (may contain properties like ${version})
-			deps = append(deps, Dependency{
-				Name:       coord,
-				Constraint: dep.Version,
-			})
+			d := Dependency{Name: coord, Constraint: dep.Version}
+			// A version left uninterpolated constrains nothing
+			if strings.Contains(d.Constraint, "${") {
+				d.Constraint = ""
+			}
+			deps = append(deps, d)
 		}
 	}
 	return deps
@@ -382,34 +443,3 @@ type searchDoc struct {
 	Version       string `json:"v"`
 	LatestVersion string `json:"latestVersion"`
 }
-
-type pomProject struct {
-	GroupID      string          `xml:"groupId"`
-	ArtifactID   string          `xml:"artifactId"`
-	Version      string          `xml:"version"`
-	Name         string          `xml:"name"`
-	Description  string          `xml:"description"`
-	URL          string          `xml:"url"`
-	SCM          *pomSCM         `xml:"scm"`
-	Licenses     []pomLicense    `xml:"licenses>license"`
-	Dependencies []pomDependency `xml:"dependencies>dependency"`
-}
-
-type pomSCM struct {
-	Connection          string `xml:"connection"`
-	DeveloperConnection string `xml:"developerConnection"`
-	URL                 string `xml:"url"`
-}
-
-type pomLicense struct {
-	Name string `xml:"name"`
-	URL  string `xml:"url"`
-}
-
-type pomDependency struct {
-	GroupID    string `xml:"groupId"`
-	ArtifactID string `xml:"artifactId"`
-	Version    string `xml:"version"`
-	Scope      string `xml:"scope"`
-	Optional   string `xml:"optional"`
-}
@@ -77,7 +77,7 @@ func TestClient_FetchArtifact(t *testing.T) {
 	}))
 	defer server.Close()
 
-	c := testClient(t, server.URL+"/solrsearch/select")
+	c := testClient(t, server.URL)
 
 	info, err := c.FetchArtifact(context.Background(), "org.example:mylib", true)
 	if err != nil {
@@ -96,6 +96,9 @@ func TestClient_FetchArtifact(t *testing.T) {
 	if info.Coordinate() != "org.example:mylib" {
 		t.Errorf("expected coordinate org.example:mylib, got %s", info.Coordinate())
 	}
+	if len(info.Dependencies) != 1 || info.Dependencies[0].Name != "com.google.guava:guava" {
+		t.Errorf("expected guava as the only dependency, got %v", info.Dependencies)
+	}
 }
 
 func TestClient_FetchArtifact_NotFound(t *testing.T) {
@@ -137,10 +140,13 @@ func TestExtractDeps(t *testing.T) {
 	}
 }
 
+// testClient returns a client for a test server that serves the search API
+// under /solrsearch/select and the repository under /maven2.
 func testClient(t *testing.T, serverURL string) *Client {
 	t.Helper()
 	return &Client{
 		Client:  integrations.NewClient(cache.NewNullCache(), "maven:", time.Hour, nil),
-		baseURL: serverURL,
+		baseURL: serverURL + "/solrsearch/select",
+		repoURL: serverURL + "/maven2",
 	}
 }
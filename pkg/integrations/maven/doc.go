@@ -30,7 +30,7 @@
 // [FetchArtifact] returns an [ArtifactInfo] containing:
 //
 //   - GroupID, ArtifactID: Artifact identity
-//   - Version: Latest version from Maven Central search
+//   - Version: Newest release from maven-metadata.xml
 //   - Dependencies: Compile-scope dependencies from POM
 //   - Description: Project description from POM
 //   - URL: Project homepage/repository URL from POM (e.g., GitHub URL)
@@ -42,13 +42,31 @@
 //
 // # Dependency Filtering
 //
-// Only compile-scope dependencies are included. Test, provided, and optional
-// dependencies are filtered out. Dependencies with unresolved Maven properties
-// (${...}) are skipped.
+// Only compile and runtime dependencies are included. Test, provided, and
+// optional dependencies are filtered out.
 //
-// # Two-Phase Fetch
+// # Effective POMs
 //
-// The client performs two requests:
-//  1. Maven Central Search API to find the latest version
-//  2. Direct POM fetch from repo1.maven.org for dependencies
+// Dependencies are read from the effective POM, the model Maven itself
+// builds: parent POMs are merged in, ${...} properties are interpolated
+// (including inherited ones and project.version), BOMs imported in
+// dependencyManagement are applied, and managed versions fill in the
+// dependencies that declare none. Parents and BOMs are cached, since many
+// artifacts share them. [ReadPOM] does the same for a local pom.xml.
+//
+// # Gradle Module Metadata
+//
+// Libraries built with Gradle often publish a .module file next to the
+// POM, describing variants with their own dependencies. When the POM says
+// so, the client reads the dependencies of the JVM runtime variant from it
+// instead, preferring the standard JVM over Android variants, following
+// Kotlin Multiplatform's available-at redirects and leaving out platforms.
+// The POM remains the fallback when the .module file cannot be read.
+//
+// # Versions
+//
+// [CompareVersions] orders versions like Maven: numerically, with
+// qualifiers ranked alpha < beta < milestone < rc < snapshot < release.
+// The latest version of an artifact is its newest stable release in
+// maven-metadata.xml; the search API is the fallback.
 package maven
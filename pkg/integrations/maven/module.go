@@ -0,0 +1,159 @@
+package maven
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// gradleMetadataMarker is the comment Gradle writes into the POMs it
+// publishes next to Gradle Module Metadata. Like Gradle itself, the client
+// only asks for a .module file when the POM carries it.
+const gradleMetadataMarker = "published-with-gradle-metadata"
+
+// errNoRuntimeVariant reports a .module file without a variant usable on
+// the JVM at runtime.
+var errNoRuntimeVariant = errors.New("no java runtime variant")
+
+// gradleModule is a Gradle Module Metadata (.module) file: the variants of
+// a component, each with its own attributes and dependencies.
+type gradleModule struct {
+	FormatVersion string          `json:"formatVersion"`
+	Variants      []gradleVariant `json:"variants"`
+}
+
+type gradleVariant struct {
+	Name         string             `json:"name"`
+	Attributes   map[string]any     `json:"attributes"`
+	Dependencies []gradleDependency `json:"dependencies"`
+	AvailableAt  *gradleAvailableAt `json:"available-at"`
+}
+
+type gradleDependency struct {
+	Group      string         `json:"group"`
+	Module     string         `json:"module"`
+	Version    *gradleVersion `json:"version"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+type gradleVersion struct {
+	Strictly string `json:"strictly"`
+	Requires string `json:"requires"`
+	Prefers  string `json:"prefers"`
+}
+
+// gradleAvailableAt points a variant at another module, as Kotlin
+// Multiplatform libraries do for their per-platform artifacts.
+type gradleAvailableAt struct {
+	Group   string `json:"group"`
+	Module  string `json:"module"`
+	Version string `json:"version"`
+}
+
+// fetchModuleDeps returns the runtime dependencies of an artifact version
+// from its Gradle Module Metadata.
+func (c *Client) fetchModuleDeps(ctx context.Context, groupID, artifactID, version string) ([]Dependency, error) {
+	url := fmt.Sprintf("%s/%s/%s/%s/%s-%s.module",
+		c.repoURL, strings.ReplaceAll(groupID, ".", "/"), artifactID, version, artifactID, version)
+
+	var m gradleModule
+	if err := c.Get(ctx, url, &m); err != nil {
+		return nil, err
+	}
+	v := runtimeVariant(m.Variants)
+	if v == nil {
+		return nil, errNoRuntimeVariant
+	}
+	return v.deps(), nil
+}
+
+// runtimeVariant picks the variant a plain JVM consumer gets at runtime:
+// a library variant for java-runtime usage, preferring the standard JVM
+// over Android ones. It returns nil when there is none.
+func runtimeVariant(variants []gradleVariant) *gradleVariant {
+	var (
+		best      *gradleVariant
+		bestScore = -1
+	)
+	for i := range variants {
+		v := &variants[i]
+		if attribute(v.Attributes, "org.gradle.usage") != "java-runtime" {
+			continue
+		}
+		switch attribute(v.Attributes, "org.gradle.category") {
+		case "", "library":
+		default:
+			continue
+		}
+		score := 0
+		switch attribute(v.Attributes, "org.gradle.jvm.environment") {
+		case "standard-jvm":
+			score += 2
+		case "":
+			score++
+		}
+		switch attribute(v.Attributes, "org.jetbrains.kotlin.platform.type") {
+		case "", "jvm":
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = v, score
+		}
+	}
+	return best
+}
+
+// deps returns the dependencies of a variant. Platforms, Gradle's BOMs,
+// only constrain versions and are left out.
+func (v *gradleVariant) deps() []Dependency {
+	if at := v.AvailableAt; at != nil {
+		return []Dependency{{Name: at.Group + ":" + at.Module, Constraint: at.Version}}
+	}
+
+	var deps []Dependency
+	seen := make(map[string]bool)
+	for _, d := range v.Dependencies {
+		switch attribute(d.Attributes, "org.gradle.category") {
+		case "platform", "enforced-platform":
+			continue
+		}
+		coord := d.Group + ":" + d.Module
+		if !seen[coord] {
+			seen[coord] = true
+			deps = append(deps, Dependency{Name: coord, Constraint: gradleConstraint(d.Version)})
+		}
+	}
+	return deps
+}
+
+// gradleConstraint converts a Gradle rich version to a Maven version or
+// range: the strict version if any, else the required one. Prefix versions
+// like "1.2.+" become ranges; "+" and "latest.release" constrain nothing.
+func gradleConstraint(v *gradleVersion) string {
+	if v == nil {
+		return ""
+	}
+	s := cmp.Or(v.Strictly, v.Requires, v.Prefers)
+	switch {
+	case s == "+" || strings.HasPrefix(s, "latest."):
+		return ""
+	case strings.HasSuffix(s, ".+"):
+		prefix := strings.TrimSuffix(s, ".+")
+		i := strings.LastIndex(prefix, ".") + 1
+		n, err := strconv.Atoi(prefix[i:])
+		if err != nil {
+			return ""
+		}
+		return "[" + prefix + "," + prefix[:i] + strconv.Itoa(n+1) + ")"
+	}
+	return s
+}
+
+// attribute returns a string attribute of a variant or dependency.
+func attribute(attrs map[string]any, name string) string {
+	s, _ := attrs[name].(string)
+	return s
+}
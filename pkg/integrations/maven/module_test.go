@@ -0,0 +1,137 @@
+package maven
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+const gradlePOM = `<?xml version="1.0" encoding="UTF-8"?>
+<project>
+  <!-- This module was also published with a richer model, Gradle metadata,  -->
+  <!-- which should be used instead. Do not delete the following line which  -->
+  <!-- is to indicate to Gradle or any Gradle module metadata file consumer  -->
+  <!-- that they should prefer consuming it instead. -->
+  <!-- do_not_remove: published-with-gradle-metadata -->
+  <groupId>org.example</groupId>
+  <artifactId>lib</artifactId>
+  <version>1.0</version>
+  <dependencies>
+    <dependency>
+      <groupId>org.example</groupId>
+      <artifactId>pom-only</artifactId>
+      <version>1.0</version>
+    </dependency>
+  </dependencies>
+</project>`
+
+func TestClient_FetchArtifactVersion_GradleModule(t *testing.T) {
+	server := testRepo(t, map[string]string{
+		"/maven2/org/example/lib/1.0/lib-1.0.pom": gradlePOM,
+		"/maven2/org/example/lib/1.0/lib-1.0.module": `{
+  "formatVersion": "1.1",
+  "component": {"group": "org.example", "module": "lib", "version": "1.0"},
+  "variants": [
+    {
+      "name": "apiElements",
+      "attributes": {"org.gradle.category": "library", "org.gradle.usage": "java-api"},
+      "dependencies": [{"group": "org.example", "module": "api-only", "version": {"requires": "1.0"}}]
+    },
+    {
+      "name": "androidRuntimeElements",
+      "attributes": {"org.gradle.category": "library", "org.gradle.usage": "java-runtime", "org.gradle.jvm.environment": "android"},
+      "dependencies": [{"group": "org.example", "module": "android-only", "version": {"requires": "1.0"}}]
+    },
+    {
+      "name": "jreRuntimeElements",
+      "attributes": {"org.gradle.category": "library", "org.gradle.usage": "java-runtime", "org.gradle.jvm.environment": "standard-jvm", "org.gradle.jvm.version": 8},
+      "dependencies": [
+        {"group": "org.example", "module": "bom", "version": {"requires": "2.0"}, "attributes": {"org.gradle.category": "platform"}},
+        {"group": "org.example", "module": "strict", "version": {"strictly": "[1.0,2.0)", "requires": "1.5"}},
+        {"group": "org.example", "module": "dynamic", "version": {"requires": "3.1.+"}},
+        {"group": "org.example", "module": "unversioned"}
+      ]
+    },
+    {
+      "name": "javadocElements",
+      "attributes": {"org.gradle.category": "documentation", "org.gradle.usage": "java-runtime"}
+    }
+  ]
+}`,
+	})
+
+	info, err := testClient(t, server.URL).FetchArtifactVersion(context.Background(), "org.example:lib", "1.0", true)
+	if err != nil {
+		t.Fatalf("FetchArtifactVersion failed: %v", err)
+	}
+	want := []Dependency{
+		{Name: "org.example:strict", Constraint: "[1.0,2.0)"},
+		{Name: "org.example:dynamic", Constraint: "[3.1,3.2)"},
+		{Name: "org.example:unversioned"},
+	}
+	if !slices.Equal(info.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want %v", info.Dependencies, want)
+	}
+}
+
+func TestClient_FetchArtifactVersion_GradleModuleAvailableAt(t *testing.T) {
+	server := testRepo(t, map[string]string{
+		"/maven2/org/example/lib/1.0/lib-1.0.pom": gradlePOM,
+		"/maven2/org/example/lib/1.0/lib-1.0.module": `{
+  "formatVersion": "1.1",
+  "variants": [
+    {
+      "name": "jvmRuntimeElements-published",
+      "attributes": {"org.gradle.category": "library", "org.gradle.usage": "java-runtime", "org.jetbrains.kotlin.platform.type": "jvm"},
+      "available-at": {"url": "../../lib-jvm/1.0/lib-jvm-1.0.module", "group": "org.example", "module": "lib-jvm", "version": "1.0"}
+    }
+  ]
+}`,
+	})
+
+	info, err := testClient(t, server.URL).FetchArtifactVersion(context.Background(), "org.example:lib", "1.0", true)
+	if err != nil {
+		t.Fatalf("FetchArtifactVersion failed: %v", err)
+	}
+	want := []Dependency{{Name: "org.example:lib-jvm", Constraint: "1.0"}}
+	if !slices.Equal(info.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want %v", info.Dependencies, want)
+	}
+}
+
+func TestClient_FetchArtifactVersion_GradleModuleMissing(t *testing.T) {
+	server := testRepo(t, map[string]string{
+		"/maven2/org/example/lib/1.0/lib-1.0.pom": gradlePOM,
+	})
+
+	info, err := testClient(t, server.URL).FetchArtifactVersion(context.Background(), "org.example:lib", "1.0", true)
+	if err != nil {
+		t.Fatalf("FetchArtifactVersion failed: %v", err)
+	}
+	want := []Dependency{{Name: "org.example:pom-only", Constraint: "1.0"}}
+	if !slices.Equal(info.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want the POM's %v", info.Dependencies, want)
+	}
+}
+
+func TestGradleConstraint(t *testing.T) {
+	tests := []struct {
+		version *gradleVersion
+		want    string
+	}{
+		{nil, ""},
+		{&gradleVersion{Requires: "1.0"}, "1.0"},
+		{&gradleVersion{Prefers: "1.0"}, "1.0"},
+		{&gradleVersion{Strictly: "[1.0,2.0)", Requires: "1.5"}, "[1.0,2.0)"},
+		{&gradleVersion{Requires: "1.+"}, "[1,2)"},
+		{&gradleVersion{Requires: "1.9.+"}, "[1.9,1.10)"},
+		{&gradleVersion{Requires: "+"}, ""},
+		{&gradleVersion{Requires: "latest.release"}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := gradleConstraint(tt.version); got != tt.want {
+			t.Errorf("gradleConstraint(%+v) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
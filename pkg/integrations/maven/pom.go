@@ -0,0 +1,374 @@
+package maven
+
+import (
+	"cmp"
+	"context"
+	"encoding/xml"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// maxPOMDepth bounds the chain of parents and BOM imports followed from
+// one POM, which guards against cycles in broken metadata.
+const maxPOMDepth = 16
+
+// maxInterpolationPasses bounds the rounds of property substitution, for
+// properties defined in terms of other properties.
+const maxInterpolationPasses = 10
+
+var propertyRE = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// POM is the effective model of a pom.xml, as Maven sees it after parent
+// inheritance, property interpolation and dependency management.
+type POM struct {
+	GroupID      string
+	ArtifactID   string
+	Version      string
+	Properties   map[string]string // interpolated, including inherited ones
+	Dependencies []Dependency      // as in [ArtifactInfo.Dependencies]
+}
+
+// ReadPOM reads the pom.xml at path and builds its effective model.
+//
+// A parent is read from its relativePath (../pom.xml by default) when the
+// POM found there is the declared parent, as in a multi-module build;
+// otherwise it is fetched from the repository, like the BOMs imported in
+// dependencyManagement. With a nil client only local parents are read and
+// BOM imports are skipped.
+func ReadPOM(ctx context.Context, c *Client, path string) (*POM, error) {
+	pom, err := readPOMFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b := modelBuilder{ctx: ctx, client: c}
+	m := b.effective(pom, filepath.Dir(path), 0)
+	props := m.properties()
+	for k, v := range props {
+		props[k] = interpolate(v, props)
+	}
+	return &POM{
+		GroupID:      m.GroupID,
+		ArtifactID:   m.ArtifactID,
+		Version:      m.Version,
+		Properties:   props,
+		Dependencies: extractDeps(m),
+	}, nil
+}
+
+// modelBuilder builds effective POMs.
+type modelBuilder struct {
+	ctx    context.Context
+	client *Client // nil reads local parents only
+}
+
+// effective returns the effective model of pom. dir is the directory of
+// its file, or "" for POMs from the repository. pom itself is not changed.
+func (b *modelBuilder) effective(pom *pomProject, dir string, depth int) *pomProject {
+	m := b.inherit(pom, dir, depth)
+
+	props := m.properties()
+	interp := func(s string) string { return interpolate(s, props) }
+	m.GroupID, m.Version, m.URL = interp(m.GroupID), interp(m.Version), interp(m.URL)
+	if m.SCM != nil {
+		scm := *m.SCM
+		scm.URL, scm.Connection, scm.DeveloperConnection = interp(scm.URL), interp(scm.Connection), interp(scm.DeveloperConnection)
+		m.SCM = &scm
+	}
+	m.Dependencies = interpolateDeps(m.Dependencies, props)
+	m.DependencyManagement = b.importBOMs(interpolateDeps(m.DependencyManagement, props), depth)
+
+	managed := make(map[string]pomDependency, len(m.DependencyManagement))
+	for _, d := range m.DependencyManagement {
+		managed[d.key()] = d
+	}
+	for i, d := range m.Dependencies {
+		if md, ok := managed[d.key()]; ok {
+			m.Dependencies[i].Version = cmp.Or(d.Version, md.Version)
+			m.Dependencies[i].Scope = cmp.Or(d.Scope, md.Scope)
+		}
+	}
+	return m
+}
+
+// inherit returns a copy of pom merged with its parents, before
+// interpolation: properties a child overrides apply to what it inherits.
+func (b *modelBuilder) inherit(pom *pomProject, dir string, depth int) *pomProject {
+	m := *pom
+	m.Properties = maps.Clone(pom.Properties)
+	if pom.Parent == nil {
+		return &m
+	}
+	m.GroupID = cmp.Or(m.GroupID, pom.Parent.GroupID)
+	m.Version = cmp.Or(m.Version, pom.Parent.Version)
+	if depth >= maxPOMDepth {
+		return &m
+	}
+	parent, parentDir := b.loadParent(pom.Parent, dir)
+	if parent == nil {
+		return &m
+	}
+	p := b.inherit(parent, parentDir, depth+1)
+
+	m.URL = cmp.Or(m.URL, p.URL)
+	if m.SCM == nil {
+		m.SCM = p.SCM
+	}
+	if len(m.Licenses) == 0 {
+		m.Licenses = p.Licenses
+	}
+	props := maps.Clone(p.Properties)
+	if props == nil {
+		props = pomProperties{}
+	}
+	maps.Copy(props, m.Properties)
+	m.Properties = props
+	m.DependencyManagement = mergeDeps(m.DependencyManagement, p.DependencyManagement)
+	m.Dependencies = mergeDeps(m.Dependencies, p.Dependencies)
+	return &m
+}
+
+// loadParent finds the parent POM, first at its relative path when the
+// child is a local file, then in the repository. It returns the POM and
+// its directory, or nil when the parent is unavailable.
+func (b *modelBuilder) loadParent(parent *pomParent, dir string) (*pomProject, string) {
+	if dir != "" && (parent.RelativePath == nil || *parent.RelativePath != "") {
+		rel := "../pom.xml"
+		if parent.RelativePath != nil {
+			rel = *parent.RelativePath
+		}
+		path := filepath.Join(dir, rel)
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, "pom.xml")
+		}
+		if pom, err := readPOMFile(path); err == nil && pom.ArtifactID == parent.ArtifactID &&
+			cmp.Or(pom.GroupID, pom.parentGroupID()) == parent.GroupID {
+			return pom, filepath.Dir(path)
+		}
+	}
+	if b.client == nil || strings.Contains(parent.GroupID+parent.ArtifactID+parent.Version, "${") {
+		return nil, ""
+	}
+	pom, err := b.client.fetchPOM(b.ctx, parent.GroupID, parent.ArtifactID, parent.Version)
+	if err != nil {
+		slog.Debug("maven: failed to fetch parent POM", "group", parent.GroupID, "artifact", parent.ArtifactID, "version", parent.Version, "error", err)
+		return nil, ""
+	}
+	return pom, ""
+}
+
+// importBOMs replaces the BOM imports in managed (scope import, type pom)
+// with the entries the BOMs manage. Entries already in managed win, and
+// earlier imports win over later ones, as in Maven.
+func (b *modelBuilder) importBOMs(managed []pomDependency, depth int) []pomDependency {
+	var (
+		out     = make([]pomDependency, 0, len(managed))
+		imports []pomDependency
+	)
+	for _, d := range managed {
+		if d.Scope == "import" && d.Type == "pom" {
+			imports = append(imports, d)
+		} else {
+			out = append(out, d)
+		}
+	}
+	if len(imports) == 0 || b.client == nil || depth >= maxPOMDepth {
+		return out
+	}
+
+	seen := make(map[string]bool, len(out))
+	for _, d := range out {
+		seen[d.key()] = true
+	}
+	for _, imp := range imports {
+		if imp.Version == "" || strings.Contains(imp.GroupID+imp.ArtifactID+imp.Version, "${") {
+			continue
+		}
+		bom, err := b.client.fetchPOM(b.ctx, imp.GroupID, imp.ArtifactID, imp.Version)
+		if err != nil {
+			slog.Debug("maven: failed to fetch BOM", "group", imp.GroupID, "artifact", imp.ArtifactID, "version", imp.Version, "error", err)
+			continue
+		}
+		for _, d := range b.effective(bom, "", depth+1).DependencyManagement {
+			if k := d.key(); !seen[k] {
+				seen[k] = true
+				out = append(out, d)
+			}
+		}
+	}
+	return out
+}
+
+// mergeDeps returns the child's entries followed by the parent's entries
+// the child does not redeclare.
+func mergeDeps(child, parent []pomDependency) []pomDependency {
+	if len(parent) == 0 {
+		return child
+	}
+	declared := make(map[string]bool, len(child))
+	for _, d := range child {
+		declared[d.key()] = true
+	}
+	merged := slices.Clip(child)
+	for _, d := range parent {
+		if !declared[d.key()] {
+			merged = append(merged, d)
+		}
+	}
+	return merged
+}
+
+// interpolateDeps returns a copy of deps with properties substituted.
+func interpolateDeps(deps []pomDependency, props map[string]string) []pomDependency {
+	out := make([]pomDependency, len(deps))
+	for i, d := range deps {
+		out[i] = pomDependency{
+			GroupID:    interpolate(d.GroupID, props),
+			ArtifactID: interpolate(d.ArtifactID, props),
+			Version:    interpolate(d.Version, props),
+			Scope:      interpolate(d.Scope, props),
+			Type:       interpolate(d.Type, props),
+			Classifier: interpolate(d.Classifier, props),
+			Optional:   interpolate(d.Optional, props),
+		}
+	}
+	return out
+}
+
+// interpolate substitutes ${name} references in s. References to unknown
+// properties are left in place.
+func interpolate(s string, props map[string]string) string {
+	for range maxInterpolationPasses {
+		if !strings.Contains(s, "${") {
+			return s
+		}
+		next := propertyRE.ReplaceAllStringFunc(s, func(ref string) string {
+			if v, ok := props[ref[2:len(ref)-1]]; ok {
+				return v
+			}
+			return ref
+		})
+		if next == s {
+			break
+		}
+		s = next
+	}
+	return s
+}
+
+func readPOMFile(path string) (*pomProject, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var pom pomProject
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return nil, err
+	}
+	return &pom, nil
+}
+
+type pomProject struct {
+	GroupID              string          `xml:"groupId"`
+	ArtifactID           string          `xml:"artifactId"`
+	Version              string          `xml:"version"`
+	Name                 string          `xml:"name"`
+	Description          string          `xml:"description"`
+	URL                  string          `xml:"url"`
+	Parent               *pomParent      `xml:"parent"`
+	Properties           pomProperties   `xml:"properties"`
+	SCM                  *pomSCM         `xml:"scm"`
+	Licenses             []pomLicense    `xml:"licenses>license"`
+	DependencyManagement []pomDependency `xml:"dependencyManagement>dependencies>dependency"`
+	Dependencies         []pomDependency `xml:"dependencies>dependency"`
+
+	// GradleMetadata is set when the POM says it was published with
+	// Gradle Module Metadata.
+	GradleMetadata bool `xml:"-"`
+}
+
+// properties returns the POM's properties together with the project.*
+// values they may refer to.
+func (p *pomProject) properties() map[string]string {
+	props := make(map[string]string, len(p.Properties)+8)
+	maps.Copy(props, p.Properties)
+	for _, prefix := range []string{"project.", "pom."} {
+		props[prefix+"groupId"] = p.GroupID
+		props[prefix+"artifactId"] = p.ArtifactID
+		props[prefix+"version"] = p.Version
+	}
+	if p.Parent != nil {
+		props["project.parent.groupId"] = p.Parent.GroupID
+		props["project.parent.artifactId"] = p.Parent.ArtifactID
+		props["project.parent.version"] = p.Parent.Version
+	}
+	return props
+}
+
+func (p *pomProject) parentGroupID() string {
+	if p.Parent == nil {
+		return ""
+	}
+	return p.Parent.GroupID
+}
+
+type pomParent struct {
+	GroupID      string  `xml:"groupId"`
+	ArtifactID   string  `xml:"artifactId"`
+	Version      string  `xml:"version"`
+	RelativePath *string `xml:"relativePath"` // nil when absent, "" when disabled
+}
+
+// pomProperties holds the free-form <properties> of a POM.
+type pomProperties map[string]string
+
+func (p *pomProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*p = pomProperties{}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			var v string
+			if err := d.DecodeElement(&v, &t); err != nil {
+				return err
+			}
+			(*p)[t.Name.Local] = strings.TrimSpace(v)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+type pomSCM struct {
+	Connection          string `xml:"connection"`
+	DeveloperConnection string `xml:"developerConnection"`
+	URL                 string `xml:"url"`
+}
+
+type pomLicense struct {
+	Name string `xml:"name"`
+	URL  string `xml:"url"`
+}
+
+type pomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+	Type       string `xml:"type"`
+	Classifier string `xml:"classifier"`
+	Optional   string `xml:"optional"`
+}
+
+// key identifies the artifact a dependency refers to, as dependency
+// management matches them.
+func (d pomDependency) key() string {
+	return d.GroupID + ":" + d.ArtifactID + ":" + cmp.Or(d.Type, "jar") + ":" + d.Classifier
+}
@@ -0,0 +1,251 @@
+package maven
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// testRepo serves POMs and metadata files under /maven2, keyed by their
+// path in the repository.
+func testRepo(t *testing.T, files map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_FetchArtifact_EffectivePOM(t *testing.T) {
+	server := testRepo(t, map[string]string{
+		"/maven2/org/example/app/maven-metadata.xml": `<metadata>
+  <versioning>
+    <release>1.9</release>
+    <versions>
+      <version>1.9</version>
+      <version>1.10</version>
+      <version>1.11-RC1</version>
+      <version>2.0-SNAPSHOT</version>
+    </versions>
+  </versioning>
+</metadata>`,
+		"/maven2/org/example/app/1.10/app-1.10.pom": `<project>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <version>1.10</version>
+  <properties>
+    <slf4j.version>2.0.9</slf4j.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>${project.groupId}</groupId>
+      <artifactId>core</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>org.example</groupId>
+      <artifactId>unknown</artifactId>
+      <version>${undefined.version}</version>
+    </dependency>
+  </dependencies>
+</project>`,
+		"/maven2/org/example/parent/1/parent-1.pom": `<project>
+  <groupId>org.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1</version>
+  <url>https://example.org</url>
+  <scm><url>https://github.com/example/app</url></scm>
+  <properties>
+    <slf4j.version>1.7.36</slf4j.version>
+    <bom.version>3</bom.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.example</groupId>
+        <artifactId>bom</artifactId>
+        <version>${bom.version}</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>${slf4j.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13.2</version>
+      <scope>test</scope>
+    </dependency>
+  </dependencies>
+</project>`,
+		"/maven2/org/example/bom/3/bom-3.pom": `<project>
+  <groupId>org.example</groupId>
+  <artifactId>bom</artifactId>
+  <version>3</version>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.google.guava</groupId>
+        <artifactId>guava</artifactId>
+        <version>[32.0,34.0)</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`,
+	})
+
+	info, err := testClient(t, server.URL).FetchArtifact(context.Background(), "org.example:app", true)
+	if err != nil {
+		t.Fatalf("FetchArtifact failed: %v", err)
+	}
+
+	if info.Version != "1.10" {
+		t.Errorf("Version = %q, want the newest release 1.10", info.Version)
+	}
+	want := []Dependency{
+		{Name: "com.google.guava:guava", Constraint: "[32.0,34.0)"}, // from the BOM
+		{Name: "org.example:core", Constraint: "1.10"},              // project.version
+		{Name: "org.example:unknown"},                               // undefined property
+		{Name: "org.slf4j:slf4j-api", Constraint: "2.0.9"},          // child property wins
+	}
+	if !slices.Equal(info.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want %v", info.Dependencies, want)
+	}
+	if info.Repository != "https://github.com/example/app" {
+		t.Errorf("Repository = %q, want it inherited from the parent", info.Repository)
+	}
+}
+
+func TestClient_ListVersions_MavenOrder(t *testing.T) {
+	server := testRepo(t, map[string]string{
+		"/maven2/org/example/app/maven-metadata.xml": `<metadata><versioning><versions>
+  <version>10.0</version>
+  <version>2.0</version>
+  <version>2.0-RC1</version>
+  <version>1.0</version>
+</versions></versioning></metadata>`,
+	})
+
+	versions, err := testClient(t, server.URL).ListVersions(context.Background(), "org.example:app", true)
+	if err != nil {
+		t.Fatalf("ListVersions failed: %v", err)
+	}
+	want := []string{"1.0", "2.0-RC1", "2.0", "10.0"}
+	if !slices.Equal(versions, want) {
+		t.Errorf("ListVersions = %v, want %v", versions, want)
+	}
+}
+
+func TestReadPOM_LocalParent(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(dir, "build", "pom.xml"), `<project>
+  <groupId>com.example</groupId>
+  <artifactId>build</artifactId>
+  <version>${revision}</version>
+  <properties>
+    <revision>4.2.0</revision>
+    <jackson.version>2.17.0</jackson.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-databind</artifactId>
+        <version>${jackson.version}</version>
+        <scope>runtime</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`)
+	write(filepath.Join(dir, "app", "pom.xml"), `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>build</artifactId>
+    <version>${revision}</version>
+    <relativePath>../build</relativePath>
+  </parent>
+  <artifactId>app</artifactId>
+  <properties>
+    <jackson.version>2.17.1</jackson.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+    </dependency>
+  </dependencies>
+</project>`)
+
+	pom, err := ReadPOM(context.Background(), nil, filepath.Join(dir, "app", "pom.xml"))
+	if err != nil {
+		t.Fatalf("ReadPOM failed: %v", err)
+	}
+
+	if pom.GroupID != "com.example" || pom.ArtifactID != "app" || pom.Version != "4.2.0" {
+		t.Errorf("coordinates = %s:%s:%s, want com.example:app:4.2.0", pom.GroupID, pom.ArtifactID, pom.Version)
+	}
+	if got := pom.Properties["jackson.version"]; got != "2.17.1" {
+		t.Errorf("jackson.version = %q, want the child's 2.17.1", got)
+	}
+	want := []Dependency{{Name: "com.fasterxml.jackson.core:jackson-databind", Constraint: "2.17.1"}}
+	if !slices.Equal(pom.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want %v", pom.Dependencies, want)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	props := map[string]string{
+		"a":    "1",
+		"b":    "${a}.2",
+		"loop": "${loop}",
+	}
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"${a}", "1"},
+		{"${b}.3", "1.2.3"},
+		{"${missing}", "${missing}"},
+		{"${loop}", "${loop}"},
+	}
+
+	for _, tt := range tests {
+		if got := interpolate(tt.in, props); got != tt.want {
+			t.Errorf("interpolate(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package maven
+
+import (
+	"context"
+
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+// Registry adapts a [Client] to [integrations.RegistryClient].
+type Registry struct {
+	client *Client
+}
+
+// NewRegistry returns c as an [integrations.RegistryClient].
+func NewRegistry(c *Client) *Registry {
+	return &Registry{client: c}
+}
+
+var _ integrations.RegistryClient = (*Registry)(nil)
+
+// Name returns "maven".
+func (r *Registry) Name() string { return "maven" }
+
+// FetchPackage implements [integrations.RegistryClient]. Names are
+// "groupId:artifactId" coordinates.
+func (r *Registry) FetchPackage(ctx context.Context, name, version string, opts integrations.FetchOptions) (*integrations.PackageInfo, error) {
+	var (
+		a   *ArtifactInfo
+		err error
+	)
+	if version == "" {
+		a, err = r.client.FetchArtifact(ctx, name, opts.Refresh)
+	} else {
+		a, err = r.client.FetchArtifactVersion(ctx, name, version, opts.Refresh)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info := &integrations.PackageInfo{
+		Name:        a.GroupID + ":" + a.ArtifactID,
+		Version:     a.Version,
+		Description: a.Description,
+		License:     a.License,
+		Repository:  a.Repository,
+		HomePage:    a.HomePage,
+	}
+	for _, d := range a.Dependencies {
+		info.Dependencies = append(info.Dependencies, integrations.Dependency{Name: d.Name, Constraint: d.Constraint})
+	}
+	return info, nil
+}
+
+// ListVersions implements [integrations.RegistryClient].
+func (r *Registry) ListVersions(ctx context.Context, name string, opts integrations.FetchOptions) ([]string, error) {
+	return r.client.ListVersions(ctx, name, opts.Refresh)
+}
@@ -0,0 +1,54 @@
+package maven
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+func TestRegistry_FetchPackage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/solrsearch/select":
+			resp := searchResponse{}
+			resp.Response.NumFound = 1
+			resp.Response.Docs = []searchDoc{{GroupID: "org.example", ArtifactID: "mylib", LatestVersion: "1.0.0"}}
+			_ = json.NewEncoder(w).Encode(resp)
+		case "/maven2/org/example/mylib/1.0.0/mylib-1.0.0.pom":
+			_, _ = w.Write([]byte(`<project>
+  <groupId>org.example</groupId>
+  <artifactId>mylib</artifactId>
+  <version>1.0.0</version>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <version>31.0</version>
+    </dependency>
+  </dependencies>
+</project>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	r := NewRegistry(testClient(t, server.URL))
+	info, err := r.FetchPackage(context.Background(), "org.example:mylib", "", integrations.FetchOptions{})
+	if err != nil {
+		t.Fatalf("FetchPackage error: %v", err)
+	}
+	if info.Name != "org.example:mylib" {
+		t.Errorf("Name = %q, want the coordinate org.example:mylib", info.Name)
+	}
+	if info.Version != "1.0.0" {
+		t.Errorf("unexpected info: %+v", info)
+	}
+	if len(info.Dependencies) != 1 || info.Dependencies[0].Name != "com.google.guava:guava" {
+		t.Errorf("unexpected dependencies: %v", info.Dependencies)
+	}
+}
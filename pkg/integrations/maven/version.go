@@ -0,0 +1,195 @@
+package maven
+
+import (
+	"cmp"
+	"strings"
+)
+
+// Qualifier ranks, in Maven's order. Qualifiers Maven does not know, like
+// "jre" or "android", sort after all of them, alphabetically.
+const (
+	rankAlpha = iota
+	rankBeta
+	rankMilestone
+	rankRC
+	rankSnapshot
+	rankRelease // no qualifier, "ga", "final" or "release"
+	rankSP
+	rankUnknown
+)
+
+var qualifierRanks = map[string]int{
+	"alpha":     rankAlpha,
+	"beta":      rankBeta,
+	"milestone": rankMilestone,
+	"rc":        rankRC,
+	"cr":        rankRC,
+	"snapshot":  rankSnapshot,
+	"":          rankRelease,
+	"ga":        rankRelease,
+	"final":     rankRelease,
+	"release":   rankRelease,
+	"sp":        rankSP,
+}
+
+// versionItem is one component of a Maven version: a number or a qualifier.
+type versionItem struct {
+	numeric bool
+	value   string // digits without leading zeros, or a lowercase qualifier
+}
+
+func (it versionItem) rank() int {
+	if r, ok := qualifierRanks[it.value]; ok {
+		return r
+	}
+	return rankUnknown
+}
+
+// isNull reports whether the item compares equal to a missing one, like
+// the trailing zero of "1.0" or the "Final" of "1.0.Final".
+func (it versionItem) isNull() bool {
+	if it.numeric {
+		return it.value == ""
+	}
+	return it.rank() == rankRelease
+}
+
+// CompareVersions compares two Maven versions the way Maven's
+// ComparableVersion does, returning -1, 0 or +1. Numbers compare
+// numerically, so "10.0" is newer than "2.0", and qualifiers compare by
+// maturity: 1.0-alpha < 1.0-beta < 1.0-M1 < 1.0-RC1 < 1.0-SNAPSHOT < 1.0
+// = 1.0.0 = 1.0.Final < 1.0-sp1. Unknown qualifiers sort after the known
+// ones and, since a number beats a qualifier, before 1.0.1.
+func CompareVersions(a, b string) int {
+	ia, ib := parseVersionItems(a), parseVersionItems(b)
+	for i := range max(len(ia), len(ib)) {
+		var c int
+		switch {
+		case i >= len(ia):
+			c = -compareToNull(ib[i])
+		case i >= len(ib):
+			c = compareToNull(ia[i])
+		default:
+			c = compareItems(ia[i], ib[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// IsStableVersion reports whether v is a release: it has no alpha, beta,
+// milestone, release-candidate or snapshot qualifier.
+func IsStableVersion(v string) bool {
+	for _, it := range parseVersionItems(v) {
+		if !it.numeric && it.rank() < rankRelease {
+			return false
+		}
+	}
+	return true
+}
+
+// parseVersionItems splits v at dots, hyphens and transitions between
+// digits and letters, and drops the null items that end each run of
+// numbers, so that "1.0.0-beta" and "1-beta" parse the same.
+func parseVersionItems(v string) []versionItem {
+	var (
+		items  []versionItem
+		tokens = splitVersion(strings.ToLower(strings.TrimSpace(v)))
+	)
+	for i, tok := range tokens {
+		if tok.numeric {
+			items = append(items, versionItem{numeric: true, value: strings.TrimLeft(tok.text, "0")})
+			continue
+		}
+		q := tok.text
+		// "a1", "b2" and "m3" are short for alpha, beta and milestone, but
+		// only when a number follows right away.
+		if len(q) == 1 && i+1 < len(tokens) && tokens[i+1].numeric && tokens[i+1].joined {
+			switch q {
+			case "a":
+				q = "alpha"
+			case "b":
+				q = "beta"
+			case "m":
+				q = "milestone"
+			}
+		}
+		items = trimNulls(items)
+		items = append(items, versionItem{value: q})
+	}
+	return trimNulls(items)
+}
+
+// trimNulls drops trailing null items.
+func trimNulls(items []versionItem) []versionItem {
+	for len(items) > 0 && items[len(items)-1].isNull() {
+		items = items[:len(items)-1]
+	}
+	return items
+}
+
+type versionToken struct {
+	text    string
+	numeric bool
+	joined  bool // follows the previous token without a separator
+}
+
+func splitVersion(v string) []versionToken {
+	var (
+		tokens []versionToken
+		start  int
+		joined bool
+	)
+	isDigit := func(c byte) bool { return c >= '0' && c <= '9' }
+	emit := func(end int) {
+		text := v[start:end]
+		tokens = append(tokens, versionToken{
+			text:    text,
+			numeric: text == "" || isDigit(text[0]),
+			joined:  joined,
+		})
+	}
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; {
+		case c == '.' || c == '-' || c == '_':
+			emit(i)
+			start, joined = i+1, false
+		case i > start && isDigit(c) != isDigit(v[i-1]):
+			emit(i)
+			start, joined = i, true
+		}
+	}
+	if v != "" {
+		emit(len(v))
+	}
+	return tokens
+}
+
+func compareItems(a, b versionItem) int {
+	switch {
+	case a.numeric && b.numeric:
+		return cmp.Or(cmp.Compare(len(a.value), len(b.value)), strings.Compare(a.value, b.value))
+	case a.numeric:
+		return 1
+	case b.numeric:
+		return -1
+	}
+	ra, rb := a.rank(), b.rank()
+	if ra == rankUnknown && rb == rankUnknown {
+		return strings.Compare(a.value, b.value)
+	}
+	return cmp.Compare(ra, rb)
+}
+
+// compareToNull compares an item with a missing one.
+func compareToNull(it versionItem) int {
+	if it.numeric {
+		if it.value == "" {
+			return 0
+		}
+		return 1
+	}
+	return cmp.Compare(it.rank(), rankRelease)
+}
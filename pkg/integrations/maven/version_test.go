@@ -0,0 +1,79 @@
+package maven
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1", "1.0.0", 0},
+		{"1.0.Final", "1.0", 0},
+		{"1.0-GA", "1", 0},
+		{"10.0", "2.0", 1},
+		{"1.10", "1.9", 1},
+		{"1.0.1", "1.0", 1},
+		{"1.0-alpha-1", "1.0-beta-1", -1},
+		{"1.0-a1", "1.0-alpha-1", 0},
+		{"1.0-beta", "1.0-M1", -1},
+		{"1.0-M1", "1.0-RC1", -1},
+		{"1.0-RC1", "1.0-CR1", 0},
+		{"1.0-RC1", "1.0-RC2", -1},
+		{"1.0-RC2", "1.0-RC10", -1},
+		{"1.0-RC1", "1.0-SNAPSHOT", -1},
+		{"1.0-SNAPSHOT", "1.0", -1},
+		{"1.0", "1.0-sp1", -1},
+		{"1.0-sp1", "1.0-jre", -1},
+		{"33.4.8-android", "33.4.8-jre", -1},
+		{"1.0-jre", "1.0.1", -1},
+		{"2.0.0-beta", "2-beta", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			if got := CompareVersions(tt.a, tt.b); got != tt.want {
+				t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := CompareVersions(tt.b, tt.a); got != -tt.want {
+				t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareVersions_Sort(t *testing.T) {
+	versions := []string{"2.0", "10.0", "1.0-RC1", "1.0", "1.0-SNAPSHOT", "1.0-alpha", "1.1"}
+	slices.SortStableFunc(versions, CompareVersions)
+	want := []string{"1.0-alpha", "1.0-RC1", "1.0-SNAPSHOT", "1.0", "1.1", "2.0", "10.0"}
+	if !slices.Equal(versions, want) {
+		t.Errorf("sorted = %v, want %v", versions, want)
+	}
+}
+
+func TestIsStableVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"1.0", true},
+		{"32.1.3-jre", true},
+		{"5.3.0.RELEASE", true},
+		{"1.0-sp1", true},
+		{"1.0-M1", false},
+		{"1.0-RC1", false},
+		{"2.0.0-beta-3", false},
+		{"1.0-SNAPSHOT", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := IsStableVersion(tt.version); got != tt.want {
+				t.Errorf("IsStableVersion(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package npm
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
 	"time"
@@ -16,13 +18,33 @@ import (
 // Dependency represents a package dependency with version constraint.
 type Dependency struct {
 	Name       string // Package name
-	Constraint string // Version constraint (e.g., "^4.17.0", ">=2.0.0"), empty for no constraint
+	Constraint string // Version constraint (e.g., "^4.17.0", ">=2.0.0") or dist-tag (e.g., "next"), empty for no constraint
+	Alias      string // Name the dependent installs it under, for "npm:" aliases (empty otherwise)
+}
+
+// ParseAlias parses an alias specifier like "npm:string-width@^4.2.0" or
+// "npm:@babel/core", which installs one package under another's name. It
+// returns the real package name and its constraint, and false for
+// ordinary specifiers.
+func ParseAlias(spec string) (name, constraint string, ok bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(spec), "npm:")
+	if !ok || rest == "" {
+		return "", "", false
+	}
+	// Skip the leading @ of scoped names when looking for the version
+	if i := strings.LastIndex(rest, "@"); i > 0 {
+		return strings.ToLower(rest[:i]), rest[i+1:], true
+	}
+	return strings.ToLower(rest), "", true
 }
 
 // PackageInfo holds metadata for a JavaScript/TypeScript package from npm.
 //
-// The Version field always contains the "latest" dist-tag version.
-// Dependencies include only runtime "dependencies", not devDependencies or peerDependencies.
+// The Version field contains the "latest" dist-tag version, or the version requested.
+// Dependencies include only runtime "dependencies", not devDependencies or peerDependencies,
+// with aliases resolved to the packages they install. Bundled dependencies ship
+// inside the package tarball: they are listed in Bundled instead, since
+// installing the package does not fetch them from the registry.
 //
 // Zero values: All string fields are empty, Dependencies is nil.
 // This struct is safe for concurrent reads after construction.
@@ -30,6 +52,7 @@ type PackageInfo struct {
 	Name         string       // Package name as published (e.g., "@scope/package", never empty in valid info)
 	Version      string       // Latest version tag (e.g., "4.18.2", never empty in valid info)
 	Dependencies []Dependency // Runtime dependencies with version constraints (nil or empty if none)
+	Bundled      []string     // Names of the bundled dependencies, sorted (nil if none)
 	Repository   string       // Normalized repository URL (empty if not provided)
 	HomePage     string       // Homepage URL (may be empty)
 	Description  string       // Package description (may be empty)
@@ -37,6 +60,7 @@ type PackageInfo struct {
 	LicenseText  string       // Full license text for custom licenses (may be empty)
 	Author       string       // Author name (may be empty)
 	RequiredNode string       // Node.js version constraint from engines.node (e.g., ">=18", may be empty)
+	UnpackedSize int64        // Size of the unpacked tarball in bytes from dist.unpackedSize (0 if not published)
 }
 
 // Client provides access to the npm package registry API.
@@ -96,8 +120,9 @@ func (c *Client) FetchPackage(ctx context.Context, pkg string, refresh bool) (*P
 
 // FetchPackageVersion retrieves metadata for a specific version of an npm package.
 //
-// The pkg parameter is normalized to lowercase. The version must be an exact version
-// string (e.g., "4.17.21").
+// The pkg parameter is normalized to lowercase. The version is an exact version
+// string (e.g., "4.17.21") or a dist-tag (e.g., "next"); the returned Version is
+// always the exact one.
 //
 // If refresh is true, the cache is bypassed and a fresh API call is made.
 //
@@ -122,78 +147,133 @@ func (c *Client) FetchPackageVersion(ctx context.Context, pkg, version string, r
 }
 
 func (c *Client) fetch(ctx context.Context, pkg, version string, info *PackageInfo) error {
-	var url string
 	if version != "" {
-		// Use abbreviated endpoint for specific version
-		url = c.baseURL + "/" + pkg + "/" + version
-	} else {
-		url = c.baseURL + "/" + pkg
-	}
-
-	if version != "" {
-		// Fetch specific version directly
+		// Fetch specific version directly; the registry also resolves
+		// dist-tags here
 		var v versionDetails
-		if err := c.Get(ctx, url, &v); err != nil {
+		if err := c.Get(ctx, c.packageURL(pkg)+"/"+version, &v); err != nil {
 			if errors.Is(err, integrations.ErrNotFound) {
 				return fmt.Errorf("%w: npm package %s version %s", err, pkg, version)
 			}
 			return err
 		}
-		license, licenseText := extractLicense(v.License)
-		*info = PackageInfo{
-			Name:         pkg,
-			Version:      version,
-			Description:  v.Description,
-			License:      license,
-			LicenseText:  licenseText,
-			Author:       extractField(v.Author, "name"),
-			Repository:   integrations.NormalizeRepoURL(extractField(v.Repository, "url")),
-			HomePage:     v.HomePage,
-			Dependencies: extractDeps(v.Dependencies),
-			RequiredNode: v.Engines.Node,
-		}
+		*info = packageInfo(pkg, cmp.Or(v.Version, version), v)
 		return nil
 	}
 
 	// Fetch latest version from full package data
 	var data registryResponse
-	if err := c.Get(ctx, url, &data); err != nil {
+	if err := c.Get(ctx, c.packageURL(pkg), &data); err != nil {
 		if errors.Is(err, integrations.ErrNotFound) {
 			return fmt.Errorf("%w: npm package %s", err, pkg)
 		}
 		return err
 	}
 
-	latest := data.DistTags.Latest
+	latest := data.DistTags["latest"]
 	v, ok := data.Versions[latest]
 	if !ok {
 		return fmt.Errorf("version %s not found", latest)
 	}
+	*info = packageInfo(data.Name, latest, v)
+	return nil
+}
+
+// FetchDistTags returns the dist-tags of a package, mapping tags like
+// "latest", "next" or "beta" to versions.
+func (c *Client) FetchDistTags(ctx context.Context, pkg string, refresh bool) (map[string]string, error) {
+	pkg = strings.ToLower(strings.TrimSpace(pkg))
+	key := pkg + ":dist-tags"
+
+	var tags map[string]string
+	err := c.Cached(ctx, key, refresh, &tags, func() error {
+		var data registryResponse
+		if err := c.Get(ctx, c.packageURL(pkg), &data); err != nil {
+			if errors.Is(err, integrations.ErrNotFound) {
+				return fmt.Errorf("%w: npm package %s", err, pkg)
+			}
+			return err
+		}
+		tags = data.DistTags
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
 
+// packageURL returns the registry URL of a package document. Scoped names
+// keep their slash, which the registry accepts as is.
+func (c *Client) packageURL(pkg string) string {
+	return c.baseURL + "/" + pkg
+}
+
+func packageInfo(name, version string, v versionDetails) PackageInfo {
 	license, licenseText := extractLicense(v.License)
-	*info = PackageInfo{
-		Name:         data.Name,
-		Version:      latest,
+	bundled := bundledNames(v)
+	return PackageInfo{
+		Name:         name,
+		Version:      version,
 		Description:  v.Description,
 		License:      license,
 		LicenseText:  licenseText,
 		Author:       extractField(v.Author, "name"),
 		Repository:   integrations.NormalizeRepoURL(extractField(v.Repository, "url")),
 		HomePage:     v.HomePage,
-		Dependencies: extractDeps(v.Dependencies),
+		Dependencies: extractDeps(v.Dependencies, bundled),
+		Bundled:      bundled,
 		RequiredNode: v.Engines.Node,
+		UnpackedSize: v.Dist.UnpackedSize,
 	}
-	return nil
 }
 
-// extractDeps converts a dependencies map to a slice of Dependency structs.
-func extractDeps(deps map[string]string) []Dependency {
+// bundledNames returns the sorted names in bundleDependencies (or its
+// bundledDependencies spelling), which is a list of names or true for all
+// dependencies.
+func bundledNames(v versionDetails) []string {
+	var names []string
+	for _, field := range []any{v.BundleDependencies, v.BundledDependencies} {
+		switch val := field.(type) {
+		case bool:
+			if val {
+				names = append(names, slices.Collect(maps.Keys(v.Dependencies))...)
+			}
+		case []any:
+			for _, n := range val {
+				if s, ok := n.(string); ok && s != "" {
+					names = append(names, s)
+				}
+			}
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// extractDeps converts a dependencies map to a slice of Dependency structs,
+// leaving out the bundled ones. Aliases become dependencies on the package
+// they install; when a package is also required under its own name or
+// another alias, the first of those by installed name is kept.
+func extractDeps(deps map[string]string, bundled []string) []Dependency {
 	if len(deps) == 0 {
 		return nil
 	}
 	result := make([]Dependency, 0, len(deps))
-	for name, constraint := range deps {
-		result = append(result, Dependency{Name: name, Constraint: constraint})
+	seen := make(map[string]bool, len(deps))
+	for _, name := range slices.Sorted(maps.Keys(deps)) {
+		if slices.Contains(bundled, name) {
+			continue
+		}
+		d := Dependency{Name: name, Constraint: deps[name]}
+		if real, constraint, ok := ParseAlias(d.Constraint); ok {
+			d = Dependency{Name: real, Constraint: constraint, Alias: name}
+		}
+		if _, ok := deps[d.Name]; (ok && d.Alias != "") || seen[d.Name] {
+			continue
+		}
+		seen[d.Name] = true
+		result = append(result, d)
 	}
 	// Sort for consistent ordering
 	slices.SortFunc(result, func(a, b Dependency) int {
@@ -237,10 +317,8 @@ func (c *Client) ListVersions(ctx context.Context, pkg string, refresh bool) ([]
 
 	var versions []string
 	err := c.Cached(ctx, key, refresh, &versions, func() error {
-		url := c.baseURL + "/" + pkg
-
 		var data registryResponse
-		if err := c.Get(ctx, url, &data); err != nil {
+		if err := c.Get(ctx, c.packageURL(pkg), &data); err != nil {
 			if errors.Is(err, integrations.ErrNotFound) {
 				return fmt.Errorf("%w: npm package %s", err, pkg)
 			}
@@ -271,10 +349,8 @@ func (c *Client) ListVersionsWithConstraints(ctx context.Context, pkg string, re
 
 	var result map[string]string
 	err := c.Cached(ctx, key, refresh, &result, func() error {
-		url := c.baseURL + "/" + pkg
-
 		var data registryResponse
-		if err := c.Get(ctx, url, &data); err != nil {
+		if err := c.Get(ctx, c.packageURL(pkg), &data); err != nil {
 			if errors.Is(err, integrations.ErrNotFound) {
 				return fmt.Errorf("%w: npm package %s", err, pkg)
 			}
@@ -307,15 +383,12 @@ func extractField(v any, field string) string {
 
 type registryResponse struct {
 	Name     string                    `json:"name"`
-	DistTags distTags                  `json:"dist-tags"`
+	DistTags map[string]string         `json:"dist-tags"`
 	Versions map[string]versionDetails `json:"versions"`
 }
 
-type distTags struct {
-	Latest string `json:"latest"`
-}
-
 type versionDetails struct {
+	Version      string            `json:"version"`
 	Description  string            `json:"description"`
 	License      any               `json:"license"`
 	Author       any               `json:"author"`
@@ -323,6 +396,14 @@ type versionDetails struct {
 	HomePage     string            `json:"homepage"`
 	Dependencies map[string]string `json:"dependencies"`
 	Engines      packageEngines    `json:"engines"`
+	Dist         packageDist       `json:"dist"`
+
+	BundleDependencies  any `json:"bundleDependencies"`  // list of names, or true for all
+	BundledDependencies any `json:"bundledDependencies"` // older spelling
+}
+
+type packageDist struct {
+	UnpackedSize int64 `json:"unpackedSize"`
 }
 
 type packageEngines struct {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 	"time"
 
@@ -23,8 +24,8 @@ func TestNewClient(t *testing.T) {
 func TestClient_FetchPackage(t *testing.T) {
 	response := registryResponse{
 		Name: "express",
-		DistTags: distTags{
-			Latest: "4.18.0",
+		DistTags: map[string]string{
+			"latest": "4.18.0",
 		},
 		Versions: map[string]versionDetails{
 			"4.18.0": {
@@ -131,6 +132,7 @@ func TestClient_FetchPackageVersion(t *testing.T) {
 			_ = json.NewEncoder(w).Encode(map[string]any{
 				"description": "Express specific version",
 				"engines":     map[string]any{"node": ">=18"},
+				"dist":        map[string]any{"unpackedSize": 220_000},
 				"dependencies": map[string]string{
 					"qs": "^6.13.0",
 				},
@@ -153,6 +155,136 @@ func TestClient_FetchPackageVersion(t *testing.T) {
 	if info.RequiredNode != ">=18" {
 		t.Fatalf("expected required node >=18, got %s", info.RequiredNode)
 	}
+	if info.UnpackedSize != 220_000 {
+		t.Fatalf("expected unpacked size 220000, got %d", info.UnpackedSize)
+	}
+}
+
+func TestClient_FetchPackageVersion_DistTag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/@scope/pkg/next" {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"version": "2.0.0-rc.1",
+				"dependencies": map[string]string{
+					"left-pad":  "^1.3.0",
+					"sw":        "npm:string-width@^4.2.0",
+					"vendored":  "^1.0.0",
+					"vendored2": "^2.0.0",
+				},
+				"bundleDependencies": []string{"vendored", "vendored2"},
+			})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c := testClient(t, server.URL)
+
+	info, err := c.FetchPackageVersion(context.Background(), "@scope/pkg", "next", true)
+	if err != nil {
+		t.Fatalf("FetchPackageVersion failed: %v", err)
+	}
+	if info.Version != "2.0.0-rc.1" {
+		t.Errorf("Version = %q, want the tagged 2.0.0-rc.1", info.Version)
+	}
+	wantDeps := []Dependency{
+		{Name: "left-pad", Constraint: "^1.3.0"},
+		{Name: "string-width", Constraint: "^4.2.0", Alias: "sw"},
+	}
+	if !slices.Equal(info.Dependencies, wantDeps) {
+		t.Errorf("Dependencies = %v, want %v", info.Dependencies, wantDeps)
+	}
+	if want := []string{"vendored", "vendored2"}; !slices.Equal(info.Bundled, want) {
+		t.Errorf("Bundled = %v, want %v", info.Bundled, want)
+	}
+}
+
+func TestClient_FetchDistTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/react" {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"name":      "react",
+				"dist-tags": map[string]string{"latest": "18.3.1", "next": "19.0.0-rc.1"},
+			})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	tags, err := testClient(t, server.URL).FetchDistTags(context.Background(), "React", true)
+	if err != nil {
+		t.Fatalf("FetchDistTags failed: %v", err)
+	}
+	if tags["latest"] != "18.3.1" || tags["next"] != "19.0.0-rc.1" {
+		t.Errorf("FetchDistTags = %v", tags)
+	}
+}
+
+func TestParseAlias(t *testing.T) {
+	tests := []struct {
+		spec          string
+		name, version string
+		ok            bool
+	}{
+		{"npm:string-width@^4.2.0", "string-width", "^4.2.0", true},
+		{"npm:@babel/core@7.0.0", "@babel/core", "7.0.0", true},
+		{"npm:@babel/core", "@babel/core", "", true},
+		{"npm:Lodash@latest", "lodash", "latest", true},
+		{"^1.0.0", "", "", false},
+		{"npm:", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			name, version, ok := ParseAlias(tt.spec)
+			if name != tt.name || version != tt.version || ok != tt.ok {
+				t.Errorf("ParseAlias(%q) = %q, %q, %v, want %q, %q, %v",
+					tt.spec, name, version, ok, tt.name, tt.version, tt.ok)
+			}
+		})
+	}
+}
+
+func TestExtractDeps(t *testing.T) {
+	deps := map[string]string{
+		"a":        "^1.0.0",
+		"a-legacy": "npm:a@^0.9.0", // a is also required directly
+		"b1":       "npm:b@^1.0.0",
+		"b2":       "npm:b@^2.0.0", // b is already required as b1
+		"bundled":  "^3.0.0",
+	}
+	got := extractDeps(deps, []string{"bundled"})
+	want := []Dependency{
+		{Name: "a", Constraint: "^1.0.0"},
+		{Name: "b", Constraint: "^1.0.0", Alias: "b1"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("extractDeps = %v, want %v", got, want)
+	}
+}
+
+func TestBundledNames(t *testing.T) {
+	deps := map[string]string{"b": "^1.0.0", "a": "^1.0.0"}
+	tests := []struct {
+		name string
+		v    versionDetails
+		want []string
+	}{
+		{"none", versionDetails{Dependencies: deps}, nil},
+		{"true", versionDetails{Dependencies: deps, BundleDependencies: true}, []string{"a", "b"}},
+		{"false", versionDetails{Dependencies: deps, BundleDependencies: false}, nil},
+		{"list", versionDetails{Dependencies: deps, BundledDependencies: []any{"b"}}, []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bundledNames(tt.v); !slices.Equal(got, tt.want) {
+				t.Errorf("bundledNames = %v, want %v", got, tt.want)
+			}
+		})
+	}
 }
 
 func TestClient_ListVersionsWithConstraints(t *testing.T) {
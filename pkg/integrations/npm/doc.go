@@ -38,5 +38,15 @@
 // # Version Selection
 //
 // The client fetches the version tagged as "latest" in dist-tags.
+// [Client.FetchPackageVersion] also accepts other dist-tags, like "next" or
+// "beta", and [Client.FetchDistTags] lists them all.
 // devDependencies, peerDependencies, and optionalDependencies are not included.
+//
+// # Aliases and Bundled Dependencies
+//
+// Alias dependencies ("sw": "npm:string-width@^4.2.0") are reported under the
+// package they install, with the installed name in [Dependency.Alias]; see
+// [ParseAlias]. Dependencies listed in bundleDependencies ship inside the
+// package tarball, so they are left out of Dependencies and listed in
+// [PackageInfo.Bundled] instead.
 package npm
@@ -0,0 +1,71 @@
+package npm
+
+import (
+	"context"
+
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+// Registry adapts a [Client] to [integrations.RegistryClient].
+type Registry struct {
+	client *Client
+}
+
+// NewRegistry returns c as an [integrations.RegistryClient].
+func NewRegistry(c *Client) *Registry {
+	return &Registry{client: c}
+}
+
+var (
+	_ integrations.RegistryClient          = (*Registry)(nil)
+	_ integrations.RuntimeConstraintLister = (*Registry)(nil)
+)
+
+// Name returns "npm".
+func (r *Registry) Name() string { return "npm" }
+
+// FetchPackage implements [integrations.RegistryClient]. Aliased
+// dependencies are listed under the package they install, and the runtime
+// constraint is engines.node.
+func (r *Registry) FetchPackage(ctx context.Context, name, version string, opts integrations.FetchOptions) (*integrations.PackageInfo, error) {
+	var (
+		p   *PackageInfo
+		err error
+	)
+	if version == "" {
+		p, err = r.client.FetchPackage(ctx, name, opts.Refresh)
+	} else {
+		p, err = r.client.FetchPackageVersion(ctx, name, version, opts.Refresh)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info := &integrations.PackageInfo{
+		Name:              p.Name,
+		Version:           p.Version,
+		Description:       p.Description,
+		License:           p.License,
+		LicenseText:       p.LicenseText,
+		Author:            p.Author,
+		Repository:        p.Repository,
+		HomePage:          p.HomePage,
+		InstallSize:       p.UnpackedSize,
+		RuntimeConstraint: p.RequiredNode,
+		Bundled:           p.Bundled,
+	}
+	for _, d := range p.Dependencies {
+		info.Dependencies = append(info.Dependencies, integrations.Dependency{Name: d.Name, Constraint: d.Constraint})
+	}
+	return info, nil
+}
+
+// ListVersions implements [integrations.RegistryClient].
+func (r *Registry) ListVersions(ctx context.Context, name string, opts integrations.FetchOptions) ([]string, error) {
+	return r.client.ListVersions(ctx, name, opts.Refresh)
+}
+
+// ListVersionsWithConstraints implements [integrations.RuntimeConstraintLister].
+func (r *Registry) ListVersionsWithConstraints(ctx context.Context, name string, opts integrations.FetchOptions) (map[string]string, error) {
+	return r.client.ListVersionsWithConstraints(ctx, name, opts.Refresh)
+}
@@ -0,0 +1,255 @@
+package nuget
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+// Dependency represents a NuGet package dependency with its version range.
+type Dependency struct {
+	Name       string // Package ID (e.g., "Microsoft.Extensions.Logging.Abstractions")
+	Constraint string // Version range (e.g., "6.0.0", "[1.0,2.0)"; empty for any version)
+}
+
+// DependencyGroup lists the dependencies of a package on one target
+// framework. Packages built for several frameworks have one group each.
+type DependencyGroup struct {
+	TargetFramework string       // Framework as written in the .nuspec (e.g., ".NETStandard2.0"); empty for all frameworks
+	Dependencies    []Dependency // Dependencies on that framework (nil if none)
+}
+
+// PackageInfo holds metadata for a NuGet package version, read from its
+// .nuspec.
+//
+// Zero values: All string fields are empty, DependencyGroups is nil.
+// This struct is safe for concurrent reads after construction.
+type PackageInfo struct {
+	ID               string            // Package ID as published (e.g., "Newtonsoft.Json", never empty in valid info)
+	Version          string            // Normalized version (e.g., "13.0.3", never empty in valid info)
+	Description      string            // Package description (may be empty)
+	License          string            // SPDX license expression (may be empty, e.g. for license files)
+	Authors          string            // Comma-separated authors (may be empty)
+	Repository       string            // Normalized source repository URL (may be empty)
+	HomePage         string            // Project URL (may be empty)
+	DependencyGroups []DependencyGroup // Dependencies per target framework (nil if none)
+}
+
+// Dependencies returns the dependencies of the group [SelectGroup] picks
+// for target, or nil when no group fits.
+func (p *PackageInfo) Dependencies(target string) []Dependency {
+	if g := SelectGroup(p.DependencyGroups, target); g != nil {
+		return g.Dependencies
+	}
+	return nil
+}
+
+// Client provides access to the NuGet package registry through the
+// package content ("flat container") resource of the NuGet V3 API.
+// It handles HTTP requests with caching and automatic retries.
+//
+// All methods are safe for concurrent use by multiple goroutines.
+type Client struct {
+	*integrations.Client
+	baseURL string // flat container root
+}
+
+// NewClient creates a NuGet client for nuget.org with the given cache
+// backend.
+//
+// Parameters:
+//   - backend: Cache backend for HTTP response caching (use cache.NewNullCache() for no caching)
+//   - cacheTTL: How long responses are cached (typical: 1-24 hours)
+//
+// The returned Client is safe for concurrent use.
+func NewClient(backend cache.Cache, cacheTTL time.Duration) *Client {
+	rl := integrations.DefaultRateLimits["nuget"]
+	return &Client{
+		Client:  integrations.NewClientWithRateLimit(backend, "nuget:", cacheTTL, nil, rl.RequestsPerSecond, rl.Burst),
+		baseURL: "https://api.nuget.org/v3-flatcontainer",
+	}
+}
+
+// FetchPackage retrieves metadata for the latest stable version of a
+// package, or the latest prerelease when there is no stable one.
+//
+// Package IDs are case-insensitive. If refresh is true, the cache is
+// bypassed.
+//
+// Returns:
+//   - PackageInfo populated with metadata on success
+//   - [integrations.ErrNotFound] if the package doesn't exist
+//   - [integrations.ErrNetwork] for HTTP failures (timeout, 5xx, etc.)
+//
+// This method is safe for concurrent use.
+func (c *Client) FetchPackage(ctx context.Context, id string, refresh bool) (*PackageInfo, error) {
+	versions, err := c.ListVersions(ctx, id, refresh)
+	if err != nil {
+		return nil, err
+	}
+	return c.FetchPackageVersion(ctx, id, latest(versions), refresh)
+}
+
+// FetchPackageVersion retrieves metadata for a specific version of a
+// package. The version need not be normalized: "1.0" finds "1.0.0".
+//
+// Returns [integrations.ErrNotFound] if the package or version doesn't
+// exist. This method is safe for concurrent use.
+func (c *Client) FetchPackageVersion(ctx context.Context, id, version string, refresh bool) (*PackageInfo, error) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	version = strings.ToLower(NormalizeVersion(version))
+	key := id + "@" + version
+
+	var info PackageInfo
+	err := c.Cached(ctx, key, refresh, &info, func() error {
+		body, err := c.GetText(ctx, fmt.Sprintf("%s/%s/%s/%s.nuspec", c.baseURL, id, version, id))
+		if err != nil {
+			if errors.Is(err, integrations.ErrNotFound) {
+				return fmt.Errorf("%w: nuget package %s version %s", err, id, version)
+			}
+			return err
+		}
+		return parseNuspec(body, &info)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// ListVersions returns the published versions of a package, normalized
+// and sorted from oldest to newest. Unlisted versions are included, as
+// the flat container does not tell them apart.
+func (c *Client) ListVersions(ctx context.Context, id string, refresh bool) ([]string, error) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	key := id + ":versions"
+
+	var versions []string
+	err := c.Cached(ctx, key, refresh, &versions, func() error {
+		var data struct {
+			Versions []string `json:"versions"`
+		}
+		if err := c.Get(ctx, fmt.Sprintf("%s/%s/index.json", c.baseURL, id), &data); err != nil {
+			if errors.Is(err, integrations.ErrNotFound) {
+				return fmt.Errorf("%w: nuget package %s", err, id)
+			}
+			return err
+		}
+		if len(data.Versions) == 0 {
+			return fmt.Errorf("no versions found for %s", id)
+		}
+		versions = data.Versions
+		slices.SortFunc(versions, CompareVersions)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// latest returns the newest stable version of versions, sorted oldest
+// first, or the newest version when all are prereleases.
+func latest(versions []string) string {
+	for _, v := range slices.Backward(versions) {
+		if !IsPrerelease(v) {
+			return v
+		}
+	}
+	return versions[len(versions)-1]
+}
+
+// nuspec is the part of a .nuspec file the client reads.
+type nuspec struct {
+	Metadata struct {
+		ID          string `xml:"id"`
+		Version     string `xml:"version"`
+		Description string `xml:"description"`
+		Authors     string `xml:"authors"`
+		ProjectURL  string `xml:"projectUrl"`
+		LicenseURL  string `xml:"licenseUrl"`
+		License     struct {
+			Type  string `xml:"type,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"license"`
+		Repository struct {
+			URL string `xml:"url,attr"`
+		} `xml:"repository"`
+		Dependencies struct {
+			Groups []struct {
+				TargetFramework string             `xml:"targetFramework,attr"`
+				Dependencies    []nuspecDependency `xml:"dependency"`
+			} `xml:"group"`
+			// Dependencies of old packages, listed without groups
+			Dependencies []nuspecDependency `xml:"dependency"`
+		} `xml:"dependencies"`
+	} `xml:"metadata"`
+}
+
+type nuspecDependency struct {
+	ID      string `xml:"id,attr"`
+	Version string `xml:"version,attr"`
+}
+
+// licenseURLPrefix starts the license URLs nuget.org generates for
+// license expressions.
+const licenseURLPrefix = "https://licenses.nuget.org/"
+
+// parseNuspec reads a .nuspec document into info.
+func parseNuspec(body string, info *PackageInfo) error {
+	var spec nuspec
+	if err := xml.Unmarshal([]byte(body), &spec); err != nil {
+		return fmt.Errorf("decode nuspec: %w", err)
+	}
+	md := spec.Metadata
+	if md.ID == "" {
+		return fmt.Errorf("decode nuspec: no package id")
+	}
+
+	license := ""
+	if md.License.Type == "expression" {
+		license = strings.TrimSpace(md.License.Value)
+	} else if l, ok := strings.CutPrefix(md.LicenseURL, licenseURLPrefix); ok {
+		license = l
+	}
+
+	*info = PackageInfo{
+		ID:          md.ID,
+		Version:     NormalizeVersion(md.Version),
+		Description: strings.TrimSpace(md.Description),
+		License:     license,
+		Authors:     strings.TrimSpace(md.Authors),
+		Repository:  integrations.NormalizeRepoURL(md.Repository.URL),
+		HomePage:    strings.TrimSpace(md.ProjectURL),
+	}
+	for _, g := range md.Dependencies.Groups {
+		info.DependencyGroups = append(info.DependencyGroups, DependencyGroup{
+			TargetFramework: g.TargetFramework,
+			Dependencies:    convertDeps(g.Dependencies),
+		})
+	}
+	if len(md.Dependencies.Dependencies) > 0 {
+		info.DependencyGroups = append(info.DependencyGroups, DependencyGroup{
+			Dependencies: convertDeps(md.Dependencies.Dependencies),
+		})
+	}
+	return nil
+}
+
+func convertDeps(in []nuspecDependency) []Dependency {
+	var out []Dependency
+	for _, d := range in {
+		if d.ID == "" {
+			continue
+		}
+		out = append(out, Dependency{Name: d.ID, Constraint: strings.TrimSpace(d.Version)})
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+//go:build integration
+
+package nuget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/cache"
+)
+
+func TestFetchPackage_Integration(t *testing.T) {
+	client := NewClient(cache.NewNullCache(), time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name    string
+		id      string
+		wantErr bool
+	}{
+		{"newtonsoft", "Newtonsoft.Json", false},
+		{"logging", "Microsoft.Extensions.Logging", false},
+		{"nonexistent", "This.Package.Should.Not.Exist.12345", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg, err := client.FetchPackage(ctx, tt.id, false)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FetchPackage(%q) error = %v, wantErr %v", tt.id, err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (pkg.ID == "" || pkg.Version == "") {
+				t.Errorf("FetchPackage(%q) = %+v, want an ID and version", tt.id, pkg)
+			}
+		})
+	}
+}
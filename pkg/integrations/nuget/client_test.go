@@ -0,0 +1,150 @@
+package nuget
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+const testNuspec = `<?xml version="1.0" encoding="utf-8"?>
+<package xmlns="http://schemas.microsoft.com/packaging/2013/05/nuspec.xsd">
+  <metadata minClientVersion="2.12">
+    <id>Serilog.Sinks.Console</id>
+    <version>5.0.1</version>
+    <authors>Serilog Contributors</authors>
+    <license type="expression">Apache-2.0</license>
+    <licenseUrl>https://licenses.nuget.org/Apache-2.0</licenseUrl>
+    <projectUrl>https://serilog.net/</projectUrl>
+    <description>A Serilog sink that writes log events to the console.</description>
+    <repository type="git" url="https://github.com/serilog/serilog-sinks-console.git" />
+    <dependencies>
+      <group targetFramework=".NETFramework4.6.2">
+        <dependency id="Serilog" version="3.1.1" exclude="Build,Analyzers" />
+        <dependency id="System.Memory" version="4.5.5" />
+      </group>
+      <group targetFramework="net6.0">
+        <dependency id="Serilog" version="[3.1.1, 4.0.0)" />
+      </group>
+    </dependencies>
+  </metadata>
+</package>`
+
+func testClient(t *testing.T, serverURL string) *Client {
+	t.Helper()
+	return &Client{
+		Client:  integrations.NewClient(cache.NewNullCache(), "nuget:", time.Hour, nil),
+		baseURL: serverURL,
+	}
+}
+
+func testServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/serilog.sinks.console/index.json":
+			_, _ = w.Write([]byte(`{"versions":["4.1.0","5.0.1","5.0.0","6.0.0-dev-00946"]}`))
+		case "/serilog.sinks.console/5.0.1/serilog.sinks.console.nuspec":
+			_, _ = w.Write([]byte(testNuspec))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_FetchPackage(t *testing.T) {
+	c := testClient(t, testServer(t).URL)
+
+	pkg, err := c.FetchPackage(context.Background(), "Serilog.Sinks.Console", false)
+	if err != nil {
+		t.Fatalf("FetchPackage: %v", err)
+	}
+	if pkg.ID != "Serilog.Sinks.Console" || pkg.Version != "5.0.1" {
+		t.Errorf("identity = %s %s, want the latest stable version", pkg.ID, pkg.Version)
+	}
+	if pkg.License != "Apache-2.0" || pkg.Authors != "Serilog Contributors" || pkg.HomePage != "https://serilog.net/" {
+		t.Errorf("metadata = %+v", pkg)
+	}
+	if pkg.Repository != "https://github.com/serilog/serilog-sinks-console" {
+		t.Errorf("Repository = %q", pkg.Repository)
+	}
+	if len(pkg.DependencyGroups) != 2 {
+		t.Fatalf("DependencyGroups = %+v, want two groups", pkg.DependencyGroups)
+	}
+
+	want := []Dependency{{Name: "Serilog", Constraint: "[3.1.1, 4.0.0)"}}
+	if got := pkg.Dependencies("net8.0"); !slices.Equal(got, want) {
+		t.Errorf("Dependencies(net8.0) = %v, want %v", got, want)
+	}
+	if got := pkg.Dependencies("net48"); len(got) != 2 {
+		t.Errorf("Dependencies(net48) = %v, want the .NET Framework group", got)
+	}
+}
+
+func TestClient_FetchPackageVersion_Normalizes(t *testing.T) {
+	c := testClient(t, testServer(t).URL)
+
+	pkg, err := c.FetchPackageVersion(context.Background(), "serilog.sinks.console", "5.0.1.0", false)
+	if err != nil || pkg.Version != "5.0.1" {
+		t.Errorf("FetchPackageVersion = %+v, %v", pkg, err)
+	}
+	_, err = c.FetchPackageVersion(context.Background(), "serilog.sinks.console", "9.9.9", false)
+	if !errors.Is(err, integrations.ErrNotFound) {
+		t.Errorf("missing version: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestClient_ListVersions(t *testing.T) {
+	c := testClient(t, testServer(t).URL)
+
+	versions, err := c.ListVersions(context.Background(), "Serilog.Sinks.Console", false)
+	if err != nil {
+		t.Fatalf("ListVersions: %v", err)
+	}
+	want := []string{"4.1.0", "5.0.0", "5.0.1", "6.0.0-dev-00946"}
+	if !slices.Equal(versions, want) {
+		t.Errorf("ListVersions = %v, want %v", versions, want)
+	}
+	if _, err := c.ListVersions(context.Background(), "missing", false); !errors.Is(err, integrations.ErrNotFound) {
+		t.Errorf("missing package: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRegistry_FetchPackage(t *testing.T) {
+	r := NewRegistry(testClient(t, testServer(t).URL), "net48")
+
+	info, err := r.FetchPackage(context.Background(), "Serilog.Sinks.Console", "", integrations.FetchOptions{})
+	if err != nil {
+		t.Fatalf("FetchPackage: %v", err)
+	}
+	if info.Name != "Serilog.Sinks.Console" || len(info.Dependencies) != 2 || info.License != "Apache-2.0" {
+		t.Errorf("info = %+v", info)
+	}
+}
+
+func TestParseNuspec_Ungrouped(t *testing.T) {
+	var info PackageInfo
+	err := parseNuspec(`<package><metadata><id>Old</id><version>1.0</version>
+		<licenseUrl>https://licenses.nuget.org/MIT</licenseUrl>
+		<dependencies><dependency id="Dep" version="2.0" /></dependencies></metadata></package>`, &info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != "1.0.0" || info.License != "MIT" {
+		t.Errorf("info = %+v", info)
+	}
+	if got := info.Dependencies("net8.0"); len(got) != 1 || got[0].Name != "Dep" {
+		t.Errorf("Dependencies = %v, want the ungrouped dependency", got)
+	}
+	if err := parseNuspec(`<package><metadata></metadata></package>`, &info); err == nil {
+		t.Error("a nuspec without an id should fail")
+	}
+}
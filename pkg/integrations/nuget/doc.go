@@ -0,0 +1,53 @@
+// Package nuget provides an HTTP client for the NuGet V3 API.
+//
+// # Overview
+//
+// This package fetches package metadata from nuget.org
+// (https://www.nuget.org), the package registry of .NET, through its
+// package content ("flat container") resource: one version list per
+// package and one .nuspec per version.
+//
+// # Usage
+//
+//	client := nuget.NewClient(cache.NewNullCache(), 24*time.Hour)
+//
+//	pkg, err := client.FetchPackage(ctx, "Newtonsoft.Json", false)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	fmt.Println(pkg.ID, pkg.Version)
+//	fmt.Println("Dependencies:", pkg.Dependencies("net8.0"))
+//
+// # PackageInfo
+//
+// [Client.FetchPackage] returns a [PackageInfo] containing:
+//
+//   - ID, Version: Package identity (latest stable version)
+//   - DependencyGroups: Dependencies per target framework
+//   - Description, Authors: Package metadata
+//   - License: SPDX expression, when the package declares one
+//   - Repository, HomePage: URLs for enrichment
+//
+// # Target Frameworks
+//
+// Packages list their dependencies once per target framework, and
+// different frameworks often need different dependencies: a package may
+// depend on System.Memory on .NET Standard 2.0 and on nothing on .NET 8.
+// [SelectGroup] picks the group NuGet would use for a target framework,
+// preferring the target's own family (.NET, .NET Framework), then .NET
+// Standard, then groups without a framework. Without a target,
+// [DefaultFramework] is used.
+//
+// # Versions
+//
+// NuGet versions have up to four numbers and an optional prerelease
+// label. [CompareVersions] orders them, and [NormalizeVersion] produces
+// the form the registry lists and addresses them by ("1.0" becomes
+// "1.0.0"). Package IDs are case-insensitive.
+//
+// # Caching
+//
+// Responses are cached to reduce load on nuget.org. The cache TTL is set
+// when creating the client. Pass refresh=true to bypass the cache.
+package nuget
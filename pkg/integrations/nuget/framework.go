@@ -0,0 +1,213 @@
+package nuget
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Framework families, the lines of .NET whose versions compare with each
+// other.
+const (
+	FamilyAny       = ""             // Dependency groups without a target framework
+	FamilyNet       = "net"          // .NET 5 and later, and .NET Core ("netcoreapp")
+	FamilyStandard  = "netstandard"  // .NET Standard
+	FamilyFramework = "netframework" // .NET Framework ("net472", ".NETFramework4.7.2")
+	FamilyOther     = "other"        // Xamarin, UWP, portable profiles, ...
+)
+
+// DefaultFramework is the target framework dependency groups are chosen
+// for when none is given: the current long-term support release.
+const DefaultFramework = "net8.0"
+
+// Framework is a parsed target framework.
+type Framework struct {
+	Family  string // One of the Family constants
+	Version []int  // Version numbers, e.g. [4 7 2] for net472; nil for FamilyAny
+	Name    string // Lowercase moniker, to tell FamilyOther frameworks apart
+}
+
+// ParseFramework parses a target framework moniker, either in the short
+// form of project files ("net8.0", "netstandard2.0", "net472",
+// "net6.0-windows") or in the long form of .nuspec files
+// (".NETStandard2.0", ".NETFramework4.7.2"). A bare version such as "8.0"
+// is read as "net8.0", so runtime versions can name a framework. Platform
+// suffixes are ignored.
+func ParseFramework(s string) Framework {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return Framework{}
+	}
+	if s[0] >= '0' && s[0] <= '9' {
+		s = "net" + s
+	}
+	if i := strings.IndexByte(s, '-'); i > 0 {
+		s = s[:i]
+	}
+	other := Framework{Family: FamilyOther, Name: s}
+
+	family, rest := "", ""
+	for _, p := range []struct{ prefix, family string }{
+		{".netstandard", FamilyStandard},
+		{"netstandard", FamilyStandard},
+		{".netcoreapp", FamilyNet},
+		{"netcoreapp", FamilyNet},
+		{".netframework", FamilyFramework},
+		{"net", ""},
+	} {
+		if r, ok := strings.CutPrefix(s, p.prefix); ok {
+			family, rest = p.family, r
+			break
+		}
+	}
+	if rest == "" {
+		return other
+	}
+
+	var version []int
+	if family == "" && !strings.Contains(rest, ".") {
+		// "net472": one digit per number, .NET Framework only
+		for _, c := range rest {
+			if c < '0' || c > '9' {
+				return other
+			}
+			version = append(version, int(c-'0'))
+		}
+		family = FamilyFramework
+	} else {
+		for _, n := range strings.Split(rest, ".") {
+			v, err := strconv.Atoi(n)
+			if err != nil {
+				return other
+			}
+			version = append(version, v)
+		}
+	}
+	if family == "" {
+		family = FamilyFramework
+		if version[0] >= 5 {
+			family = FamilyNet
+		}
+	}
+	return Framework{Family: family, Version: version, Name: s}
+}
+
+// atLeast reports whether f's version is at least v.
+func (f Framework) atLeast(v ...int) bool {
+	return compareFrameworkVersions(f.Version, v) >= 0
+}
+
+// maxStandard returns the newest .NET Standard version f implements, or
+// nil when it implements none.
+func (f Framework) maxStandard() []int {
+	switch f.Family {
+	case FamilyStandard:
+		return f.Version
+	case FamilyNet:
+		switch {
+		case f.atLeast(3, 0):
+			return []int{2, 1}
+		case f.atLeast(2, 0):
+			return []int{2, 0}
+		default:
+			return []int{1, 6}
+		}
+	case FamilyFramework:
+		switch {
+		case f.atLeast(4, 6, 1):
+			return []int{2, 0}
+		case f.atLeast(4, 6):
+			return []int{1, 3}
+		case f.atLeast(4, 5, 1):
+			return []int{1, 2}
+		case f.atLeast(4, 5):
+			return []int{1, 1}
+		}
+	}
+	return nil
+}
+
+// Compatibility ranks of a dependency group for a target, best last.
+const (
+	compatNone = iota
+	compatFallback
+	compatAny
+	compatStandard
+	compatSame
+)
+
+// compatibility ranks how well a package built for f runs on target. It
+// follows NuGet's nearest-framework rule in broad strokes: the same
+// family beats .NET Standard, which beats framework-agnostic groups. .NET
+// targets fall back to .NET Framework 4.6.1+ builds, as NuGet's asset
+// target fallback does.
+func (f Framework) compatibility(target Framework) int {
+	switch {
+	case f.Family == FamilyAny:
+		return compatAny
+	case f.Family == target.Family && f.Family == FamilyOther:
+		if f.Name == target.Name {
+			return compatSame
+		}
+	case f.Family == target.Family:
+		if compareFrameworkVersions(f.Version, target.Version) <= 0 {
+			return compatSame
+		}
+	case f.Family == FamilyStandard:
+		if std := target.maxStandard(); std != nil && compareFrameworkVersions(f.Version, std) <= 0 {
+			return compatStandard
+		}
+	case f.Family == FamilyFramework && target.Family == FamilyNet:
+		if f.atLeast(4, 6, 1) {
+			return compatFallback
+		}
+	}
+	return compatNone
+}
+
+// compareFrameworkVersions compares version numbers, missing ones being
+// zero.
+func compareFrameworkVersions(a, b []int) int {
+	for i := range max(len(a), len(b)) {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// SelectGroup returns the dependency group NuGet would use for a project
+// targeting target (see [ParseFramework]; empty means
+// [DefaultFramework]): among the compatible groups, the one of the
+// nearest family with the highest version. It returns nil when no group
+// is compatible.
+func SelectGroup(groups []DependencyGroup, target string) *DependencyGroup {
+	if target == "" {
+		target = DefaultFramework
+	}
+	t := ParseFramework(target)
+
+	var best *DependencyGroup
+	var bestFramework Framework
+	bestRank := compatNone
+	for i := range groups {
+		f := ParseFramework(groups[i].TargetFramework)
+		rank := f.compatibility(t)
+		if rank == compatNone || rank < bestRank {
+			continue
+		}
+		if rank > bestRank || compareFrameworkVersions(f.Version, bestFramework.Version) > 0 {
+			best, bestFramework, bestRank = &groups[i], f, rank
+		}
+	}
+	return best
+}
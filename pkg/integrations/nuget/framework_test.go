@@ -0,0 +1,73 @@
+package nuget
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseFramework(t *testing.T) {
+	tests := []struct {
+		in      string
+		family  string
+		version []int
+	}{
+		{"", FamilyAny, nil},
+		{"net8.0", FamilyNet, []int{8, 0}},
+		{"8.0", FamilyNet, []int{8, 0}},
+		{"net6.0-windows7.0", FamilyNet, []int{6, 0}},
+		{"netcoreapp3.1", FamilyNet, []int{3, 1}},
+		{".NETCoreApp3.1", FamilyNet, []int{3, 1}},
+		{"netstandard2.0", FamilyStandard, []int{2, 0}},
+		{".NETStandard2.0", FamilyStandard, []int{2, 0}},
+		{"net472", FamilyFramework, []int{4, 7, 2}},
+		{".NETFramework4.6.1", FamilyFramework, []int{4, 6, 1}},
+		{"MonoAndroid10", FamilyOther, nil},
+	}
+	for _, tt := range tests {
+		f := ParseFramework(tt.in)
+		if f.Family != tt.family || !slices.Equal(f.Version, tt.version) {
+			t.Errorf("ParseFramework(%q) = %+v, want %s %v", tt.in, f, tt.family, tt.version)
+		}
+	}
+}
+
+func TestSelectGroup(t *testing.T) {
+	groups := []DependencyGroup{
+		{TargetFramework: ".NETFramework4.6.2"},
+		{TargetFramework: ".NETStandard2.0"},
+		{TargetFramework: ".NETStandard2.1"},
+		{TargetFramework: "net6.0"},
+		{TargetFramework: "net8.0"},
+	}
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"", "net8.0"},
+		{"net9.0", "net8.0"},
+		{"net7.0", "net6.0"},
+		{"netcoreapp3.1", ".NETStandard2.1"},
+		{"netcoreapp2.1", ".NETStandard2.0"},
+		{"net48", ".NETFramework4.6.2"},
+		{"net461", ".NETStandard2.0"},
+		{"netstandard2.0", ".NETStandard2.0"},
+		{"net45", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if g := SelectGroup(groups, tt.target); g != nil {
+			got = g.TargetFramework
+		}
+		if got != tt.want {
+			t.Errorf("SelectGroup(%q) = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+
+	anyGroup := []DependencyGroup{{TargetFramework: "net462"}, {}}
+	if g := SelectGroup(anyGroup, "net8.0"); g == nil || g.TargetFramework != "" {
+		t.Errorf("SelectGroup should prefer the framework-agnostic group over a fallback, got %+v", g)
+	}
+	if g := SelectGroup([]DependencyGroup{{TargetFramework: "net472"}}, "net8.0"); g == nil {
+		t.Error("SelectGroup should fall back to .NET Framework 4.6.1+ builds")
+	}
+}
@@ -0,0 +1,60 @@
+package nuget
+
+import (
+	"context"
+
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+// Registry adapts a [Client] to [integrations.RegistryClient].
+type Registry struct {
+	client    *Client
+	framework string
+}
+
+// NewRegistry returns c as an [integrations.RegistryClient] whose
+// dependencies are those for target framework (see [SelectGroup]; empty
+// means [DefaultFramework]).
+func NewRegistry(c *Client, framework string) *Registry {
+	return &Registry{client: c, framework: framework}
+}
+
+var _ integrations.RegistryClient = (*Registry)(nil)
+
+// Name returns "nuget".
+func (r *Registry) Name() string { return "nuget" }
+
+// FetchPackage implements [integrations.RegistryClient].
+func (r *Registry) FetchPackage(ctx context.Context, name, version string, opts integrations.FetchOptions) (*integrations.PackageInfo, error) {
+	var (
+		p   *PackageInfo
+		err error
+	)
+	if version == "" {
+		p, err = r.client.FetchPackage(ctx, name, opts.Refresh)
+	} else {
+		p, err = r.client.FetchPackageVersion(ctx, name, version, opts.Refresh)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info := &integrations.PackageInfo{
+		Name:        p.ID,
+		Version:     p.Version,
+		Description: p.Description,
+		License:     p.License,
+		Author:      p.Authors,
+		Repository:  p.Repository,
+		HomePage:    p.HomePage,
+	}
+	for _, d := range p.Dependencies(r.framework) {
+		info.Dependencies = append(info.Dependencies, integrations.Dependency{Name: d.Name, Constraint: d.Constraint})
+	}
+	return info, nil
+}
+
+// ListVersions implements [integrations.RegistryClient].
+func (r *Registry) ListVersions(ctx context.Context, name string, opts integrations.FetchOptions) ([]string, error) {
+	return r.client.ListVersions(ctx, name, opts.Refresh)
+}
@@ -0,0 +1,134 @@
+package nuget
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+)
+
+// version is a parsed NuGet version: up to four numbers and an optional
+// prerelease label.
+type version struct {
+	parts      [4]int
+	count      int      // number of parts given (1-4)
+	prerelease []string // dot-separated label identifiers, lowercase
+}
+
+// parseVersion parses a NuGet version such as "13.0.3", "1.0" or
+// "6.0.0-preview.7.21377.19+build". Build metadata is dropped.
+func parseVersion(s string) (version, bool) {
+	var v version
+	s = strings.TrimSpace(s)
+	s, _, _ = strings.Cut(s, "+")
+	s, label, hasLabel := strings.Cut(s, "-")
+	if hasLabel {
+		if label == "" {
+			return v, false
+		}
+		v.prerelease = strings.Split(strings.ToLower(label), ".")
+	}
+	nums := strings.Split(s, ".")
+	if len(nums) > 4 {
+		return v, false
+	}
+	for i, n := range nums {
+		p, err := strconv.Atoi(n)
+		if err != nil || p < 0 {
+			return v, false
+		}
+		v.parts[i] = p
+	}
+	v.count = len(nums)
+	return v, true
+}
+
+// ValidVersion reports whether s is a NuGet version.
+func ValidVersion(s string) bool {
+	_, ok := parseVersion(s)
+	return ok
+}
+
+// NormalizeVersion returns the normalized form of a NuGet version, the
+// one the registry lists and addresses packages by: at least three
+// numbers without leading zeros, a fourth only when it is not zero, a
+// lowercase prerelease label and no build metadata. "1.0" becomes
+// "1.0.0", "1.0.0.0" becomes "1.0.0". Invalid versions are returned
+// unchanged.
+func NormalizeVersion(s string) string {
+	v, ok := parseVersion(s)
+	if !ok {
+		return s
+	}
+	n := 3
+	if v.parts[3] != 0 {
+		n = 4
+	}
+	nums := make([]string, n)
+	for i := range n {
+		nums[i] = strconv.Itoa(v.parts[i])
+	}
+	out := strings.Join(nums, ".")
+	if len(v.prerelease) > 0 {
+		out += "-" + strings.Join(v.prerelease, ".")
+	}
+	return out
+}
+
+// IsPrerelease reports whether s has a prerelease label.
+func IsPrerelease(s string) bool {
+	v, ok := parseVersion(s)
+	return ok && len(v.prerelease) > 0
+}
+
+// CompareVersions compares two NuGet versions, returning -1, 0 or +1.
+// Missing numbers are zero, so "1.0" equals "1.0.0.0". A prerelease sorts
+// before its release, and labels compare like SemVer 2.0 but without
+// regard to case: numeric identifiers numerically and before
+// alphanumeric ones. Invalid versions sort before valid ones, and
+// lexically among themselves.
+func CompareVersions(a, b string) int {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	switch {
+	case !okA && !okB:
+		return strings.Compare(a, b)
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+	for i := range va.parts {
+		if c := cmp.Compare(va.parts[i], vb.parts[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(va.prerelease) == 0 && len(vb.prerelease) == 0:
+		return 0
+	case len(va.prerelease) == 0:
+		return 1
+	case len(vb.prerelease) == 0:
+		return -1
+	}
+	for i := range min(len(va.prerelease), len(vb.prerelease)) {
+		if c := compareLabel(va.prerelease[i], vb.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(va.prerelease), len(vb.prerelease))
+}
+
+// compareLabel compares two prerelease identifiers.
+func compareLabel(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,58 @@
+package nuget
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := map[string]string{
+		"1.0":              "1.0.0",
+		"1.0.0.0":          "1.0.0",
+		"1.0.0.1":          "1.0.0.1",
+		"01.02.03":         "1.2.3",
+		"1.0.0-Beta.1":     "1.0.0-beta.1",
+		"1.0.0+sha.abc":    "1.0.0",
+		"1.0.0-rc.1+build": "1.0.0-rc.1",
+		"not-a-version":    "not-a-version",
+	}
+	for in, want := range tests {
+		if got := NormalizeVersion(in); got != want {
+			t.Errorf("NormalizeVersion(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.0.1",
+		"1.0.1",
+		"2.0.0",
+		"10.0.0",
+	}
+	shuffled := slices.Clone(ordered)
+	slices.Reverse(shuffled)
+	slices.SortFunc(shuffled, CompareVersions)
+	if !slices.Equal(shuffled, ordered) {
+		t.Errorf("sorted = %v, want %v", shuffled, ordered)
+	}
+
+	for _, eq := range [][2]string{{"1.0", "1.0.0.0"}, {"1.0.0-BETA", "1.0.0-beta"}, {"1.0.0+a", "1.0.0+b"}} {
+		if c := CompareVersions(eq[0], eq[1]); c != 0 {
+			t.Errorf("CompareVersions(%q, %q) = %d, want 0", eq[0], eq[1], c)
+		}
+	}
+}
+
+func TestIsPrerelease(t *testing.T) {
+	if !IsPrerelease("6.0.0-preview.7") || IsPrerelease("6.0.0") || IsPrerelease("garbage") {
+		t.Error("IsPrerelease misclassified a version")
+	}
+}
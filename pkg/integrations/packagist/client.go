@@ -1,10 +1,12 @@
 package packagist
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"strconv"
 	"strings"
@@ -38,6 +40,10 @@ type PackageInfo struct {
 	License      string       // License identifier (may be empty, only first license if multiple)
 	Author       string       // First author name (may be empty)
 	RequiredPHP  string       // PHP version constraint from require.php (e.g., ">=8.1", may be empty)
+	Replaces     []Dependency // Packages this one replaces, filtered like Dependencies (nil if none)
+	Provides     []Dependency // Packages this one provides, often virtual ones such as "psr/log-implementation" (nil if none)
+	Abandoned    bool         // Whether the maintainers marked the package abandoned
+	Replacement  string       // Package suggested instead of an abandoned one (may be empty)
 }
 
 // Client provides access to the Packagist package registry API.
@@ -46,8 +52,8 @@ type PackageInfo struct {
 // All methods are safe for concurrent use by multiple goroutines.
 type Client struct {
 	*integrations.Client
-	baseURL     string // P2 metadata API (repo.packagist.org)
-	registryURL string // Full package API (packagist.org) — includes require for all versions
+	baseURL     string // Metadata v2 API (repo.packagist.org)
+	registryURL string // Packagist API (packagist.org), which lists the providers of virtual packages
 }
 
 // NewClient creates a Packagist client with the given cache backend.
@@ -128,19 +134,11 @@ func (c *Client) FetchPackageVersion(ctx context.Context, pkg, version string, r
 }
 
 func (c *Client) fetch(ctx context.Context, pkg, version string, info *PackageInfo) error {
-	var data p2Response
-	if err := c.Get(ctx, fmt.Sprintf("%s/p2/%s.json", c.baseURL, pkg), &data); err != nil {
-		if errors.Is(err, integrations.ErrNotFound) {
-			return fmt.Errorf("%w: packagist package %s", err, pkg)
-		}
+	versions, err := c.fetchVersions(ctx, pkg)
+	if err != nil {
 		return err
 	}
 
-	versions, ok := data.Packages[pkg]
-	if !ok || len(versions) == 0 {
-		return fmt.Errorf("no versions found for %s", pkg)
-	}
-
 	var v p2Version
 	if version != "" {
 		// Find specific version
@@ -180,10 +178,76 @@ func (c *Client) fetch(ctx context.Context, pkg, version string, info *PackageIn
 		HomePage:     v.Homepage,
 		Dependencies: filterDeps(v.Require),
 		RequiredPHP:  requiredPHP,
+		Replaces:     filterDeps(v.Replace),
+		Provides:     filterDeps(v.Provide),
+		Abandoned:    v.Abandoned,
+		Replacement:  v.Replacement,
 	}
 	return nil
 }
 
+// fetchVersions returns the versions of a package from the metadata v2
+// API, newest first, with minified entries expanded.
+func (c *Client) fetchVersions(ctx context.Context, pkg string) ([]p2Version, error) {
+	var data p2RawResponse
+	if err := c.Get(ctx, fmt.Sprintf("%s/p2/%s.json", c.baseURL, pkg), &data); err != nil {
+		if errors.Is(err, integrations.ErrNotFound) {
+			return nil, fmt.Errorf("%w: packagist package %s", err, pkg)
+		}
+		return nil, err
+	}
+
+	entries, ok := data.Packages[pkg]
+	if !ok || len(entries) == 0 {
+		return nil, fmt.Errorf("no versions found for %s", pkg)
+	}
+	if data.Minified != "" {
+		entries = expandMinified(entries)
+	}
+
+	versions := make([]p2Version, 0, len(entries))
+	for _, e := range entries {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		var v p2Version
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", pkg, err)
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+// unsetMarker is the value minified metadata gives a field that an entry
+// drops from the one before it.
+const unsetMarker = `"__unset"`
+
+// expandMinified undoes Composer's metadata minification ("composer/2.0"):
+// each version lists only the fields that differ from the version before
+// it, and "__unset" removes a field.
+func expandMinified(entries []map[string]json.RawMessage) []map[string]json.RawMessage {
+	expanded := make([]map[string]json.RawMessage, 0, len(entries))
+	var prev map[string]json.RawMessage
+	for _, e := range entries {
+		cur := maps.Clone(prev)
+		if cur == nil {
+			cur = make(map[string]json.RawMessage, len(e))
+		}
+		for k, v := range e {
+			if string(v) == unsetMarker {
+				delete(cur, k)
+			} else {
+				cur[k] = v
+			}
+		}
+		expanded = append(expanded, cur)
+		prev = cur
+	}
+	return expanded
+}
+
 func filterDeps(require map[string]string) []Dependency {
 	var deps []Dependency
 	for name, constraint := range require {
@@ -212,19 +276,11 @@ func (c *Client) ListVersions(ctx context.Context, pkg string, refresh bool) ([]
 
 	var versions []string
 	err := c.Cached(ctx, key, refresh, &versions, func() error {
-		var data p2Response
-		if err := c.Get(ctx, fmt.Sprintf("%s/p2/%s.json", c.baseURL, pkg), &data); err != nil {
-			if errors.Is(err, integrations.ErrNotFound) {
-				return fmt.Errorf("%w: packagist package %s", err, pkg)
-			}
+		pkgVersions, err := c.fetchVersions(ctx, pkg)
+		if err != nil {
 			return err
 		}
 
-		pkgVersions, ok := data.Packages[pkg]
-		if !ok || len(pkgVersions) == 0 {
-			return fmt.Errorf("no versions found for %s", pkg)
-		}
-
 		versions = make([]string, 0, len(pkgVersions))
 		for _, v := range pkgVersions {
 			versions = append(versions, v.Version)
@@ -242,37 +298,80 @@ func (c *Client) ListVersions(ctx context.Context, pkg string, refresh bool) ([]
 
 // ListVersionsWithConstraints returns all versions and their PHP runtime constraints.
 // Returns a map of version -> require.php (empty string if not specified).
-//
-// Uses the full Packagist API (packagist.org/packages/) instead of the P2 API
-// because P2 returns minimal stubs (no require field) for most versions.
 func (c *Client) ListVersionsWithConstraints(ctx context.Context, pkg string, refresh bool) (map[string]string, error) {
 	pkg = strings.ToLower(strings.TrimSpace(pkg))
 	key := pkg + ":version_constraints"
 
 	var result map[string]string
 	err := c.Cached(ctx, key, refresh, &result, func() error {
-		var data fullPackageResponse
-		if err := c.Get(ctx, fmt.Sprintf("%s/packages/%s.json", c.registryURL, pkg), &data); err != nil {
+		versions, err := c.fetchVersions(ctx, pkg)
+		if err != nil {
+			return err
+		}
+
+		result = make(map[string]string, len(versions))
+		for _, v := range versions {
+			result[v.Version] = strings.TrimSpace(v.Require["php"])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Providers returns the packages that provide or replace a package, most
+// downloaded first. Virtual packages, such as "psr/log-implementation",
+// exist only through their providers; real packages may have providers
+// too.
+//
+// Returns [integrations.ErrNotFound] if Packagist knows no such package.
+func (c *Client) Providers(ctx context.Context, pkg string, refresh bool) ([]string, error) {
+	pkg = strings.ToLower(strings.TrimSpace(pkg))
+	key := pkg + ":providers"
+
+	var providers []string
+	err := c.Cached(ctx, key, refresh, &providers, func() error {
+		var data providersResponse
+		if err := c.Get(ctx, fmt.Sprintf("%s/providers/%s.json", c.registryURL, pkg), &data); err != nil {
 			if errors.Is(err, integrations.ErrNotFound) {
 				return fmt.Errorf("%w: packagist package %s", err, pkg)
 			}
 			return err
 		}
 
-		if len(data.Package.Versions) == 0 {
-			return fmt.Errorf("no versions found for %s", pkg)
-		}
-
-		result = make(map[string]string, len(data.Package.Versions))
-		for ver, meta := range data.Package.Versions {
-			result[ver] = strings.TrimSpace(meta.Require["php"])
+		slices.SortStableFunc(data.Providers, func(a, b provider) int {
+			return cmp.Compare(b.Downloads, a.Downloads)
+		})
+		providers = make([]string, len(data.Providers))
+		for i, p := range data.Providers {
+			providers[i] = strings.ToLower(p.Name)
 		}
 		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
-	return result, nil
+	return providers, nil
+}
+
+// IsVirtual reports whether a package is virtual: it has no releases of
+// its own, only packages that provide it. Composer installs a virtual
+// requirement through whichever required package provides it.
+func (c *Client) IsVirtual(ctx context.Context, pkg string, refresh bool) (bool, error) {
+	_, err := c.FetchPackage(ctx, pkg, refresh)
+	if err == nil || !errors.Is(err, integrations.ErrNotFound) {
+		return false, err
+	}
+	providers, err := c.Providers(ctx, pkg, refresh)
+	if err != nil {
+		if errors.Is(err, integrations.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return len(providers) > 0, nil
 }
 
 func latestStable(versions []p2Version) p2Version {
@@ -288,21 +387,22 @@ func latestStable(versions []p2Version) p2Version {
 	return versions[0]
 }
 
-type p2Response struct {
-	Packages map[string][]p2Version `json:"packages"`
+// p2RawResponse is the response from repo.packagist.org/p2/{name}.json.
+// Version entries are kept raw, since minified entries only make sense
+// after expansion.
+type p2RawResponse struct {
+	Minified string                                  `json:"minified"`
+	Packages map[string][]map[string]json.RawMessage `json:"packages"`
 }
 
-// fullPackageResponse is the response from packagist.org/packages/{name}.json.
-// Unlike the P2 API, this endpoint returns complete metadata (including require)
-// for every version.
-type fullPackageResponse struct {
-	Package struct {
-		Versions map[string]fullVersion `json:"versions"`
-	} `json:"package"`
+// providersResponse is the response from packagist.org/providers/{name}.json.
+type providersResponse struct {
+	Providers []provider `json:"providers"`
 }
 
-type fullVersion struct {
-	Require map[string]string `json:"require"`
+type provider struct {
+	Name      string `json:"name"`
+	Downloads int    `json:"downloads"`
 }
 
 type p2Version struct {
@@ -312,12 +412,18 @@ type p2Version struct {
 	Homepage    string            `json:"homepage"`
 	License     []string          `json:"license"`
 	Require     map[string]string `json:"require"`
+	Replace     map[string]string `json:"replace,omitempty"`
+	Provide     map[string]string `json:"provide,omitempty"`
 	Source      struct {
 		URL string `json:"url"`
 	} `json:"source"`
 	Authors []struct {
 		Name string `json:"name"`
 	} `json:"authors"`
+	// Abandoned is "abandoned" in the API, which is true or the name of a
+	// replacement package.
+	Abandoned   bool   `json:"-"`
+	Replacement string `json:"-"`
 }
 
 func (v *p2Version) UnmarshalJSON(b []byte) error {
@@ -328,6 +434,9 @@ func (v *p2Version) UnmarshalJSON(b []byte) error {
 		Homepage    string          `json:"homepage"`
 		License     json.RawMessage `json:"license"`
 		Require     json.RawMessage `json:"require"`
+		Replace     json.RawMessage `json:"replace"`
+		Provide     json.RawMessage `json:"provide"`
+		Abandoned   json.RawMessage `json:"abandoned"`
 		Source      struct {
 			URL string `json:"url"`
 		} `json:"source"`
@@ -357,22 +466,41 @@ func (v *p2Version) UnmarshalJSON(b []byte) error {
 		}
 	}
 
-	if len(r.Require) > 0 && string(r.Require) != "null" {
-		v.Require = make(map[string]string)
-		if err := json.Unmarshal(r.Require, &v.Require); err != nil {
-			var anyObj map[string]any
-			if json.Unmarshal(r.Require, &anyObj) == nil {
-				for k, val := range anyObj {
-					if s, ok := val.(string); ok {
-						v.Require[k] = s
-					}
-				}
-			}
+	v.Require = decodeLinks(r.Require)
+	v.Replace = decodeLinks(r.Replace)
+	v.Provide = decodeLinks(r.Provide)
+
+	if len(r.Abandoned) > 0 {
+		var replacement string
+		if json.Unmarshal(r.Abandoned, &replacement) == nil {
+			v.Abandoned, v.Replacement = true, strings.ToLower(replacement)
+		} else {
+			_ = json.Unmarshal(r.Abandoned, &v.Abandoned)
 		}
 	}
 	return nil
 }
 
+// decodeLinks decodes a package link map such as "require", skipping
+// constraints that are not strings. Minified metadata writes empty maps as
+// "__unset" or as an empty array, which decode to nil.
+func decodeLinks(raw json.RawMessage) map[string]string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	var anyObj map[string]any
+	if json.Unmarshal(raw, &anyObj) != nil {
+		return nil
+	}
+	links := make(map[string]string, len(anyObj))
+	for k, val := range anyObj {
+		if s, ok := val.(string); ok {
+			links[k] = s
+		}
+	}
+	return links
+}
+
 func composerVersionsEquivalent(a, b string) bool {
 	normalize := func(v string) string {
 		v = strings.TrimSpace(strings.TrimPrefix(v, "v"))
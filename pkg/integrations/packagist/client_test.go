@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -146,15 +147,15 @@ func TestFetchPackageVersion_Success(t *testing.T) {
 }
 
 func TestListVersionsWithConstraints(t *testing.T) {
-	payload := fullPackageResponse{}
-	payload.Package.Versions = map[string]fullVersion{
-		"1.2.4": {Require: map[string]string{"php": "^8.2", "vendor/dep": "^2.0"}},
-		"1.2.3": {Require: map[string]string{"php": "^8.1", "vendor/dep": "^1.0"}},
-		"1.0.0": {Require: map[string]string{"vendor/dep": "^1.0"}},
-	}
+	// Minified: 1.2.3 inherits vendor/dep from 1.2.4, and 1.0.0 drops php
+	payload := `{"minified": "composer/2.0", "packages": {"vendor/package": [
+		{"name": "vendor/package", "version": "1.2.4", "require": {"php": "^8.2", "vendor/dep": "^2.0"}},
+		{"version": "1.2.3", "require": {"php": "^8.1", "vendor/dep": "^1.0"}},
+		{"version": "1.0.0", "require": {"vendor/dep": "^1.0"}}
+	]}}`
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/packages/vendor/package.json" {
-			_ = json.NewEncoder(w).Encode(payload)
+		if r.URL.Path == "/p2/vendor/package.json" {
+			_, _ = w.Write([]byte(payload))
 			return
 		}
 		w.WriteHeader(http.StatusNotFound)
@@ -175,6 +176,152 @@ func TestListVersionsWithConstraints(t *testing.T) {
 	}
 }
 
+func TestFetchPackageVersion_Minified(t *testing.T) {
+	payload := `{"minified": "composer/2.0", "packages": {"vendor/package": [
+		{"name": "vendor/package", "version": "2.0.0", "description": "d",
+		 "require": {"php": ">=8.1", "vendor/dep": "^2.0"}, "provide": {"psr/log-implementation": "3.0.0"}},
+		{"version": "1.5.0", "require": {"php": ">=7.4", "vendor/dep": "^1.0"}, "provide": "__unset"},
+		{"version": "1.0.0", "description": "__unset", "replace": {"vendor/old": "self.version"}}
+	]}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(payload))
+	}))
+	defer server.Close()
+
+	c := testClient(t, server.URL)
+
+	info, err := c.FetchPackageVersion(context.Background(), "vendor/package", "1.0.0", true)
+	if err != nil {
+		t.Fatalf("FetchPackageVersion error: %v", err)
+	}
+	if info.Name != "vendor/package" {
+		t.Errorf("name should be inherited, got %q", info.Name)
+	}
+	if info.Description != "" {
+		t.Errorf("description should be unset, got %q", info.Description)
+	}
+	if info.RequiredPHP != ">=7.4" {
+		t.Errorf("RequiredPHP = %q, want >=7.4 inherited from 1.5.0", info.RequiredPHP)
+	}
+	if len(info.Dependencies) != 1 || info.Dependencies[0].Constraint != "^1.0" {
+		t.Errorf("unexpected dependencies: %#v", info.Dependencies)
+	}
+	if len(info.Provides) != 0 {
+		t.Errorf("provide should be unset, got %#v", info.Provides)
+	}
+	if len(info.Replaces) != 1 || info.Replaces[0].Name != "vendor/old" {
+		t.Errorf("unexpected replaces: %#v", info.Replaces)
+	}
+
+	info, err = c.FetchPackageVersion(context.Background(), "vendor/package", "2.0.0", true)
+	if err != nil {
+		t.Fatalf("FetchPackageVersion error: %v", err)
+	}
+	if len(info.Provides) != 1 || info.Provides[0].Name != "psr/log-implementation" {
+		t.Errorf("unexpected provides: %#v", info.Provides)
+	}
+}
+
+func TestFetchPackage_Abandoned(t *testing.T) {
+	payloads := map[string]string{
+		"/p2/old/replaced.json": `{"packages": {"old/replaced": [{"name": "old/replaced", "version": "1.0.0", "abandoned": "New/Package"}]}}`,
+		"/p2/old/gone.json":     `{"packages": {"old/gone": [{"name": "old/gone", "version": "1.0.0", "abandoned": true}]}}`,
+		"/p2/kept/alive.json":   `{"packages": {"kept/alive": [{"name": "kept/alive", "version": "1.0.0", "abandoned": false}]}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(payloads[r.URL.Path]))
+	}))
+	defer server.Close()
+
+	c := testClient(t, server.URL)
+
+	tests := []struct {
+		pkg         string
+		abandoned   bool
+		replacement string
+	}{
+		{"old/replaced", true, "new/package"},
+		{"old/gone", true, ""},
+		{"kept/alive", false, ""},
+	}
+	for _, tt := range tests {
+		info, err := c.FetchPackage(context.Background(), tt.pkg, true)
+		if err != nil {
+			t.Fatalf("FetchPackage(%s) error: %v", tt.pkg, err)
+		}
+		if info.Abandoned != tt.abandoned || info.Replacement != tt.replacement {
+			t.Errorf("%s: Abandoned = %v, Replacement = %q; want %v, %q",
+				tt.pkg, info.Abandoned, info.Replacement, tt.abandoned, tt.replacement)
+		}
+	}
+}
+
+func TestProviders_IsVirtual(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/providers/psr/log-implementation.json":
+			_, _ = w.Write([]byte(`{"providers": [
+				{"name": "small/logger", "downloads": 10},
+				{"name": "Monolog/Monolog", "downloads": 1000}
+			]}`))
+		case "/p2/monolog/monolog.json":
+			_, _ = w.Write([]byte(`{"packages": {"monolog/monolog": [{"name": "monolog/monolog", "version": "3.0.0"}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := testClient(t, server.URL)
+	ctx := context.Background()
+
+	providers, err := c.Providers(ctx, "psr/log-implementation", true)
+	if err != nil {
+		t.Fatalf("Providers error: %v", err)
+	}
+	if want := []string{"monolog/monolog", "small/logger"}; !slices.Equal(providers, want) {
+		t.Errorf("Providers = %v, want %v", providers, want)
+	}
+
+	tests := []struct {
+		pkg  string
+		want bool
+	}{
+		{"psr/log-implementation", true},
+		{"monolog/monolog", false},
+		{"missing/pkg", false},
+	}
+	for _, tt := range tests {
+		got, err := c.IsVirtual(ctx, tt.pkg, true)
+		if err != nil {
+			t.Fatalf("IsVirtual(%s) error: %v", tt.pkg, err)
+		}
+		if got != tt.want {
+			t.Errorf("IsVirtual(%s) = %v, want %v", tt.pkg, got, tt.want)
+		}
+	}
+}
+
+func TestExpandMinified(t *testing.T) {
+	entries := []map[string]json.RawMessage{
+		{"name": json.RawMessage(`"a/b"`), "version": json.RawMessage(`"2.0"`), "homepage": json.RawMessage(`"h"`)},
+		{"version": json.RawMessage(`"1.0"`), "homepage": json.RawMessage(unsetMarker)},
+	}
+	got := expandMinified(entries)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(got))
+	}
+	if string(got[1]["name"]) != `"a/b"` || string(got[1]["version"]) != `"1.0"` {
+		t.Errorf("unexpected second entry: %v", got[1])
+	}
+	if _, ok := got[1]["homepage"]; ok {
+		t.Error("homepage should be unset in the second entry")
+	}
+	if string(got[0]["homepage"]) != `"h"` {
+		t.Error("expanding should not modify earlier entries")
+	}
+}
+
 func TestNormalizeName(t *testing.T) {
 	if got := strings.ToLower(strings.TrimSpace("  VenDor/PackAge  ")); got != "vendor/package" {
 		t.Errorf("normalizeName unexpected: %q", got)
@@ -297,6 +444,11 @@ func TestComposerVersionsEquivalent(t *testing.T) {
 	}
 }
 
+// p2Response is an unminified metadata v2 response.
+type p2Response struct {
+	Packages map[string][]p2Version `json:"packages"`
+}
+
 func testClient(t *testing.T, serverURL string) *Client {
 	t.Helper()
 	return &Client{
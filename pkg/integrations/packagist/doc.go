@@ -29,6 +29,24 @@
 //   - Description: Package description
 //   - License, Author: Package metadata
 //   - Repository, HomePage: URLs for enrichment
+//   - Replaces, Provides: Packages this one stands in for
+//   - Abandoned, Replacement: Whether the package is abandoned, and what
+//     to use instead
+//
+// # Metadata v2
+//
+// Package metadata comes from the Composer v2 metadata API
+// (repo.packagist.org/p2/{name}.json). Its responses are minified: each
+// version lists only the fields that changed since the version before it.
+// The client expands them, so every version carries its full "require".
+//
+// # Virtual Packages
+//
+// Packages can "provide" or "replace" others. Virtual packages such as
+// "psr/log-implementation" only exist that way: they have no releases, and
+// requiring one means requiring some package that provides it.
+// [Client.Providers] lists the packages providing one, and
+// [Client.IsVirtual] tells virtual packages apart from missing ones.
 //
 // # Caching
 //
@@ -0,0 +1,69 @@
+package packagist
+
+import (
+	"context"
+
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+// Registry adapts a [Client] to [integrations.RegistryClient].
+type Registry struct {
+	client *Client
+}
+
+// NewRegistry returns c as an [integrations.RegistryClient].
+func NewRegistry(c *Client) *Registry {
+	return &Registry{client: c}
+}
+
+var (
+	_ integrations.RegistryClient          = (*Registry)(nil)
+	_ integrations.RuntimeConstraintLister = (*Registry)(nil)
+)
+
+// Name returns "packagist".
+func (r *Registry) Name() string { return "packagist" }
+
+// FetchPackage implements [integrations.RegistryClient]. The runtime
+// constraint is the PHP requirement.
+func (r *Registry) FetchPackage(ctx context.Context, name, version string, opts integrations.FetchOptions) (*integrations.PackageInfo, error) {
+	var (
+		p   *PackageInfo
+		err error
+	)
+	if version == "" {
+		p, err = r.client.FetchPackage(ctx, name, opts.Refresh)
+	} else {
+		p, err = r.client.FetchPackageVersion(ctx, name, version, opts.Refresh)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info := &integrations.PackageInfo{
+		Name:              p.Name,
+		Version:           p.Version,
+		Description:       p.Description,
+		License:           p.License,
+		Author:            p.Author,
+		Repository:        p.Repository,
+		HomePage:          p.HomePage,
+		RuntimeConstraint: p.RequiredPHP,
+		Abandoned:         p.Abandoned,
+		Replacement:       p.Replacement,
+	}
+	for _, d := range p.Dependencies {
+		info.Dependencies = append(info.Dependencies, integrations.Dependency{Name: d.Name, Constraint: d.Constraint})
+	}
+	return info, nil
+}
+
+// ListVersions implements [integrations.RegistryClient].
+func (r *Registry) ListVersions(ctx context.Context, name string, opts integrations.FetchOptions) ([]string, error) {
+	return r.client.ListVersions(ctx, name, opts.Refresh)
+}
+
+// ListVersionsWithConstraints implements [integrations.RuntimeConstraintLister].
+func (r *Registry) ListVersionsWithConstraints(ctx context.Context, name string, opts integrations.FetchOptions) (map[string]string, error) {
+	return r.client.ListVersionsWithConstraints(ctx, name, opts.Refresh)
+}
@@ -0,0 +1,56 @@
+package packagist
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+func TestRegistry(t *testing.T) {
+	payload := `{"minified": "composer/2.0", "packages": {"old/package": [
+		{"name": "old/package", "version": "2.0.0", "license": ["MIT"], "abandoned": "new/package",
+		 "require": {"php": ">=8.1", "vendor/dep": "^2.0"}},
+		{"version": "1.0.0", "require": {"php": ">=7.4", "vendor/dep": "^1.0"}}
+	]}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/p2/old/package.json" {
+			_, _ = w.Write([]byte(payload))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	r := NewRegistry(testClient(t, server.URL))
+	ctx := context.Background()
+
+	info, err := r.FetchPackage(ctx, "old/package", "", integrations.FetchOptions{})
+	if err != nil {
+		t.Fatalf("FetchPackage error: %v", err)
+	}
+	if info.Version != "2.0.0" || info.RuntimeConstraint != ">=8.1" || info.License != "MIT" {
+		t.Errorf("unexpected latest: %+v", info)
+	}
+	if !info.Abandoned || info.Replacement != "new/package" {
+		t.Errorf("abandoned flag should carry over, got %v %q", info.Abandoned, info.Replacement)
+	}
+
+	info, err = r.FetchPackage(ctx, "old/package", "1.0.0", integrations.FetchOptions{})
+	if err != nil {
+		t.Fatalf("FetchPackage(1.0.0) error: %v", err)
+	}
+	if len(info.Dependencies) != 1 || info.Dependencies[0].Constraint != "^1.0" {
+		t.Errorf("unexpected dependencies: %v", info.Dependencies)
+	}
+
+	versions, err := r.ListVersions(ctx, "old/package", integrations.FetchOptions{})
+	if err != nil {
+		t.Fatalf("ListVersions error: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Errorf("ListVersions = %v, want 2 versions", versions)
+	}
+}
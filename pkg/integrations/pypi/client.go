@@ -1,6 +1,7 @@
 package pypi
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -20,9 +21,9 @@ var (
 	// Handles: ==1.0, >=1.0,<2.0, ~=1.4, etc.
 	constraintRE = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*\s*(\[.*?\])?\s*((?:[<>=!~]=?|===?)[^;]+)?`)
 	markerRE     = regexp.MustCompile(`;\s*(.+)`)
-	skipRE       = regexp.MustCompile(`extra|dev|test`)
-	// pythonVersionRE matches python_version markers like: python_version < "3.11"
-	pythonVersionRE = regexp.MustCompile(`python_version\s*([<>=!]+)\s*["'](\d+(?:\.\d+)?)["']`)
+	// finalReleaseRE matches PEP 440 final and post releases, leaving out
+	// pre- and dev releases
+	finalReleaseRE = regexp.MustCompile(`^\d+(?:\.\d+)*(?:\.post\d+)?$`)
 )
 
 // DefaultPythonVersion is the default assumed Python version for marker evaluation.
@@ -54,6 +55,8 @@ type PackageInfo struct {
 	License        string            // License name or expression (may be empty)
 	LicenseText    string            // Full raw license text for custom/proprietary licenses (may be empty)
 	Author         string            // Author name (may be empty)
+	Yanked         bool              // Whether the release was yanked (PEP 592); only exact pins select it
+	YankedReason   string            // Why the release was yanked (may be empty)
 }
 
 // Client provides access to the PyPI package registry API.
@@ -63,7 +66,9 @@ type PackageInfo struct {
 type Client struct {
 	*integrations.Client
 	baseURL       string
+	simpleURL     string // JSON simple index (PEP 691), for releases the JSON API lacks metadata for
 	pythonVersion string // Target Python version for marker evaluation
+	platform      string // Target platform for marker evaluation
 }
 
 // NewClient creates a PyPI client with the given cache backend.
@@ -72,9 +77,10 @@ type Client struct {
 //   - backend: Cache backend for HTTP response caching (use storage.NullBackend{} for no caching)
 //   - cacheTTL: How long responses are cached (typical: 1-24 hours)
 //   - pythonVersion: Target Python version for marker evaluation (e.g., "3.11"); empty uses default
+//   - platform: Target platform for marker evaluation ("linux", "darwin" or "windows"); empty uses default
 //
 // The returned Client is safe for concurrent use.
-func NewClient(backend cache.Cache, cacheTTL time.Duration, pythonVersion string) *Client {
+func NewClient(backend cache.Cache, cacheTTL time.Duration, pythonVersion, platform string) *Client {
 	rl := integrations.DefaultRateLimits["pypi"]
 	pv := pythonVersion
 	if pv == "" {
@@ -83,10 +89,18 @@ func NewClient(backend cache.Cache, cacheTTL time.Duration, pythonVersion string
 	return &Client{
 		Client:        integrations.NewClientWithRateLimit(backend, "pypi:", cacheTTL, nil, rl.RequestsPerSecond, rl.Burst),
 		baseURL:       "https://pypi.org/pypi",
+		simpleURL:     "https://pypi.org/simple",
 		pythonVersion: pv,
+		platform:      cmp.Or(platform, DefaultPlatform),
 	}
 }
 
+// envKey returns the cache key suffix for the marker environment, since
+// dependency lists depend on it.
+func (c *Client) envKey() string {
+	return ":py" + c.pythonVersion + "-" + c.platform
+}
+
 // FetchPackage retrieves metadata for a Python package from PyPI (latest version).
 //
 // The pkg parameter is normalized automatically (case-insensitive, underscores→hyphens).
@@ -101,11 +115,14 @@ func NewClient(backend cache.Cache, cacheTTL time.Duration, pythonVersion string
 //   - [integrations.ErrNetwork] for HTTP failures (timeout, 5xx, etc.)
 //   - Other errors for JSON decoding failures
 //
+// When the latest release was yanked, the newest stable release that was
+// not is returned instead.
+//
 // The returned PackageInfo pointer is never nil if err is nil.
 // This method is safe for concurrent use.
 func (c *Client) FetchPackage(ctx context.Context, pkg string, refresh bool) (*PackageInfo, error) {
 	pkg = integrations.NormalizePkgName(pkg)
-	key := pkg
+	key := pkg + c.envKey()
 
 	var info PackageInfo
 	err := c.Cached(ctx, key, refresh, &info, func() error {
@@ -120,7 +137,7 @@ func (c *Client) FetchPackage(ctx context.Context, pkg string, refresh bool) (*P
 // FetchPackageVersion retrieves metadata for a specific version of a Python package.
 //
 // The pkg parameter is normalized automatically. The version must be an exact version
-// string (e.g., "2.31.0").
+// string (e.g., "2.31.0"). Yanked versions are returned with Yanked set.
 //
 // If refresh is true, the cache is bypassed and a fresh API call is made.
 //
@@ -132,7 +149,7 @@ func (c *Client) FetchPackage(ctx context.Context, pkg string, refresh bool) (*P
 // This method is safe for concurrent use.
 func (c *Client) FetchPackageVersion(ctx context.Context, pkg, version string, refresh bool) (*PackageInfo, error) {
 	pkg = integrations.NormalizePkgName(pkg)
-	key := pkg + "@" + version
+	key := pkg + "@" + version + c.envKey()
 
 	var info PackageInfo
 	err := c.Cached(ctx, key, refresh, &info, func() error {
@@ -163,6 +180,21 @@ func (c *Client) fetch(ctx context.Context, pkg, version string, info *PackageIn
 		return err
 	}
 
+	if version == "" && data.Info.Yanked {
+		if v := latestUnyanked(data.Releases); v != "" && v != data.Info.Version {
+			return c.fetch(ctx, pkg, v, info)
+		}
+	}
+
+	requires := data.Info.RequiresDist
+	if requires == nil {
+		// The JSON API only reads metadata from the first file uploaded;
+		// wheels listed in the simple index may still declare dependencies
+		if r, err := c.fetchSimpleRequires(ctx, pkg, data.Info.Version); err == nil {
+			requires = r
+		}
+	}
+
 	urls := make(map[string]string, len(data.Info.ProjectURLs))
 	for k, v := range data.Info.ProjectURLs {
 		if s, ok := v.(string); ok {
@@ -185,27 +217,55 @@ func (c *Client) fetch(ctx context.Context, pkg, version string, info *PackageIn
 		Summary:        data.Info.Summary,
 		License:        licenseType,
 		LicenseText:    licenseText,
-		Dependencies:   c.extractDeps(data.Info.RequiresDist),
+		Dependencies:   c.extractDeps(requires),
 		ProjectURLs:    urls,
 		HomePage:       data.Info.HomePage,
 		Author:         data.Info.Author,
+		Yanked:         data.Info.Yanked,
+		YankedReason:   data.Info.YankedReason,
 	}
 	return nil
 }
 
+// latestUnyanked returns the newest stable release with files that were
+// not all yanked, or "" if there is none.
+func latestUnyanked(releases map[string][]apiReleaseFile) string {
+	var versions []string
+	for v, files := range releases {
+		if !isYanked(files) && finalReleaseRE.MatchString(v) {
+			versions = append(versions, v)
+		}
+	}
+	if len(versions) == 0 {
+		return ""
+	}
+	integrations.SortVersions(versions)
+	return versions[len(versions)-1]
+}
+
+// isYanked reports whether every file of a release was yanked, which
+// yanks the release. Releases without files are not yanked.
+func isYanked(files []apiReleaseFile) bool {
+	for _, f := range files {
+		if !f.Yanked {
+			return false
+		}
+	}
+	return len(files) > 0
+}
+
+// extractDeps returns the runtime dependencies among requires_dist entries,
+// skipping those whose environment markers do not hold for the client's
+// Python version and platform. Dependencies of extras never hold, since no
+// extra is requested. Markers that fail to parse keep their dependency.
 func (c *Client) extractDeps(requires []string) []Dependency {
+	env := NewEnvironment(c.pythonVersion, c.platform)
 	seen := make(map[string]bool)
 	var deps []Dependency
 	for _, req := range requires {
 		// Check markers
 		if m := markerRE.FindStringSubmatch(req); len(m) > 1 {
-			marker := m[1]
-			// Skip extras, dev, and test dependencies
-			if skipRE.MatchString(marker) {
-				continue
-			}
-			// Skip dependencies with python_version markers that don't match target
-			if !evaluatePythonVersionMarker(marker, c.pythonVersion) {
+			if ok, err := env.Evaluate(m[1]); err == nil && !ok {
 				continue
 			}
 		}
@@ -235,57 +295,6 @@ func (c *Client) extractDeps(requires []string) []Dependency {
 	return deps
 }
 
-// evaluatePythonVersionMarker checks if a marker's python_version condition
-// is satisfied by the target Python version. Returns true if the dependency
-// should be included (marker is satisfied or has no python_version condition).
-func evaluatePythonVersionMarker(marker string, pythonVersion string) bool {
-	matches := pythonVersionRE.FindAllStringSubmatch(marker, -1)
-	if len(matches) == 0 {
-		// No python_version in marker, include the dependency
-		return true
-	}
-
-	targetParts := parseVersionParts(pythonVersion)
-
-	for _, m := range matches {
-		if len(m) < 3 {
-			continue
-		}
-		op := m[1]
-		verStr := m[2]
-		verParts := parseVersionParts(verStr)
-
-		cmp := compareVersionParts(targetParts, verParts)
-
-		var satisfied bool
-		switch op {
-		case "<":
-			satisfied = cmp < 0
-		case "<=":
-			satisfied = cmp <= 0
-		case ">":
-			satisfied = cmp > 0
-		case ">=":
-			satisfied = cmp >= 0
-		case "==":
-			satisfied = cmp == 0
-		case "!=":
-			satisfied = cmp != 0
-		default:
-			// Unknown operator, include to be safe
-			satisfied = true
-		}
-
-		// For "and" conditions (default), all must be satisfied
-		// Most markers use "and" implicitly, so if any condition fails, skip
-		if !satisfied {
-			return false
-		}
-	}
-
-	return true
-}
-
 // parseVersionParts splits a version string like "3.11" into [3, 11].
 func parseVersionParts(v string) []int {
 	parts := strings.Split(v, ".")
@@ -343,25 +352,30 @@ type apiResponse struct {
 // apiReleaseFile represents a single release file (wheel, sdist) from PyPI
 type apiReleaseFile struct {
 	RequiresPython string `json:"requires_python"` // Python version constraint for this release
+	Yanked         bool   `json:"yanked"`          // Whether the file was yanked (PEP 592)
 }
 
 type apiInfo struct {
-	Name              string         `json:"name"`
-	Version           string         `json:"version"`
-	Summary           string         `json:"summary"`
-	License           string         `json:"license"`
-	LicenseExpression string         `json:"license_expression"` // SPDX license expression (newer PyPI field)
-	Classifiers       []string       `json:"classifiers"`
-	RequiresDist      []string       `json:"requires_dist"`
-	RequiresPython    string         `json:"requires_python"` // Python version constraint (e.g., ">=3.8")
-	ProjectURLs       map[string]any `json:"project_urls"`
-	HomePage          string         `json:"home_page"`
-	Author            string         `json:"author"`
+	Name              string   `json:"name"`
+	Version           string   `json:"version"`
+	Summary           string   `json:"summary"`
+	License           string   `json:"license"`
+	LicenseExpression string   `json:"license_expression"` // SPDX license expression (newer PyPI field)
+	Classifiers       []string `json:"classifiers"`
+	RequiresDist      []string `json:"requires_dist"` // null when PyPI has no metadata for it
+
+	RequiresPython string         `json:"requires_python"` // Python version constraint (e.g., ">=3.8")
+	ProjectURLs    map[string]any `json:"project_urls"`
+	HomePage       string         `json:"home_page"`
+	Author         string         `json:"author"`
+	Yanked         bool           `json:"yanked"`
+	YankedReason   string         `json:"yanked_reason"`
 }
 
 // ListVersions returns all available versions for a package.
 // Versions are sorted lexicographically (not by PEP 440), so "1.10.0" sorts before "1.2.0".
-// Pre-release versions are included in the list.
+// Pre-release versions are included in the list; yanked releases are not, as
+// PEP 592 has installers only select them for exact pins.
 // Callers that need proper version ordering should sort the result with a PEP 440 comparator.
 func (c *Client) ListVersions(ctx context.Context, pkg string, refresh bool) ([]string, error) {
 	pkg = integrations.NormalizePkgName(pkg)
@@ -381,8 +395,10 @@ func (c *Client) ListVersions(ctx context.Context, pkg string, refresh bool) ([]
 
 		// Extract version strings from releases
 		versions = make([]string, 0, len(data.Releases))
-		for v := range data.Releases {
-			versions = append(versions, v)
+		for v, files := range data.Releases {
+			if !isYanked(files) {
+				versions = append(versions, v)
+			}
 		}
 
 		// Sort versions semantically (oldest to newest)
@@ -417,6 +433,9 @@ func (c *Client) ListVersionsWithConstraints(ctx context.Context, pkg string, re
 		// Extract requires_python from each release's files
 		result = make(map[string]string, len(data.Releases))
 		for version, files := range data.Releases {
+			if isYanked(files) {
+				continue
+			}
 			// All files for a version should have the same requires_python,
 			// so just take the first non-empty one
 			for _, f := range files {
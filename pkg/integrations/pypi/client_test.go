@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 	"time"
 
@@ -94,6 +95,98 @@ func TestExtractDeps_FiltersMarkers(t *testing.T) {
 	}
 }
 
+func TestExtractDeps_Platform(t *testing.T) {
+	requires := []string{
+		"colorama; sys_platform == 'win32'",
+		"uvloop; sys_platform != 'win32' and platform_python_implementation == 'CPython'",
+		"appnope; platform_system == 'Darwin'",
+	}
+	tests := []struct {
+		platform string
+		want     []string
+	}{
+		{"linux", []string{"uvloop"}},
+		{"darwin", []string{"uvloop", "appnope"}},
+		{"windows", []string{"colorama"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			c := &Client{pythonVersion: DefaultPythonVersion, platform: tt.platform}
+			var got []string
+			for _, d := range c.extractDeps(requires) {
+				got = append(got, d.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("extractDeps on %s = %v, want %v", tt.platform, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_FetchPackage_SkipsYankedLatest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/demo/json":
+			json.NewEncoder(w).Encode(map[string]any{
+				"info": map[string]any{"name": "demo", "version": "2.0.0", "yanked": true, "requires_dist": []string{}},
+				"releases": map[string]any{
+					"1.0.0":   []map[string]any{{"yanked": false}},
+					"1.1.0":   []map[string]any{{"yanked": false}},
+					"2.0.0":   []map[string]any{{"yanked": true}},
+					"2.1.0b1": []map[string]any{{"yanked": false}},
+				},
+			})
+		case "/demo/1.1.0/json":
+			json.NewEncoder(w).Encode(map[string]any{
+				"info": map[string]any{"name": "demo", "version": "1.1.0", "requires_dist": []string{"idna"}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := testClient(t, server.URL)
+
+	info, err := c.FetchPackage(context.Background(), "demo", true)
+	if err != nil {
+		t.Fatalf("FetchPackage failed: %v", err)
+	}
+	if info.Version != "1.1.0" || info.Yanked {
+		t.Errorf("got %s (yanked %v), want the newest unyanked 1.1.0", info.Version, info.Yanked)
+	}
+
+	versions, err := c.ListVersions(context.Background(), "demo", true)
+	if err != nil {
+		t.Fatalf("ListVersions failed: %v", err)
+	}
+	if slices.Contains(versions, "2.0.0") || len(versions) != 3 {
+		t.Errorf("ListVersions = %v, want the yanked 2.0.0 left out", versions)
+	}
+}
+
+func TestClient_FetchPackageVersion_Yanked(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/demo/2.0.0/json" {
+			json.NewEncoder(w).Encode(map[string]any{
+				"info": map[string]any{"name": "demo", "version": "2.0.0", "yanked": true, "yanked_reason": "broken wheel", "requires_dist": []string{}},
+			})
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	info, err := testClient(t, server.URL).FetchPackageVersion(context.Background(), "demo", "2.0.0", true)
+	if err != nil {
+		t.Fatalf("FetchPackageVersion failed: %v", err)
+	}
+	if !info.Yanked || info.YankedReason != "broken wheel" {
+		t.Errorf("Yanked = %v, YankedReason = %q", info.Yanked, info.YankedReason)
+	}
+}
+
 func TestExtractDeps_ExtractsConstraints(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -194,11 +287,13 @@ func testClient(t *testing.T, serverURL string) *Client {
 	return &Client{
 		Client:        integrations.NewClient(cache.NewNullCache(), "pypi:", time.Hour, nil),
 		baseURL:       serverURL,
+		simpleURL:     serverURL + "/simple",
 		pythonVersion: DefaultPythonVersion,
+		platform:      DefaultPlatform,
 	}
 }
 
 // testExtractClient returns a minimal client for testing extractDeps.
 func testExtractClient() *Client {
-	return &Client{pythonVersion: DefaultPythonVersion}
+	return &Client{pythonVersion: DefaultPythonVersion, platform: DefaultPlatform}
 }
@@ -38,11 +38,24 @@
 //
 // # Dependency Filtering
 //
-// Dependencies are extracted from requires_dist, filtering out:
+// Dependencies are extracted from requires_dist. Their PEP 508 environment
+// markers are evaluated against an [Environment] for the client's target
+// Python version and platform (Linux by default), which leaves out:
 //
-//   - Optional extras (extra markers)
-//   - Development dependencies (dev markers)
-//   - Test dependencies (test markers)
+//   - Optional extras (extra markers), including dev and test extras
+//   - Dependencies for other Python versions (python_version markers)
+//   - Dependencies for other platforms (sys_platform, os_name, ... markers)
 //
 // Package names are normalized following PEP 503.
+//
+// The JSON API leaves requires_dist null when the first file uploaded for a
+// release did not declare it. The client then reads the metadata of one of
+// the release's wheels, found through the JSON simple index (PEP 691).
+//
+// # Yanked Releases
+//
+// Yanked releases (PEP 592) are left out of [Client.ListVersions], and
+// [Client.FetchPackage] falls back to the newest release that was not
+// yanked. [Client.FetchPackageVersion] still returns them, with Yanked set,
+// since exact pins may select them.
 package pypi
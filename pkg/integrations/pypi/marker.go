@@ -0,0 +1,347 @@
+package pypi
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultPlatform is the default target platform for marker evaluation.
+const DefaultPlatform = "linux"
+
+// platformEnvironments maps target platforms to the marker variables that
+// describe them, as Python reports them on that platform.
+var platformEnvironments = map[string]Environment{
+	"linux": {
+		"sys_platform":     "linux",
+		"platform_system":  "Linux",
+		"os_name":          "posix",
+		"platform_machine": "x86_64",
+	},
+	"darwin": {
+		"sys_platform":     "darwin",
+		"platform_system":  "Darwin",
+		"os_name":          "posix",
+		"platform_machine": "arm64",
+	},
+	"windows": {
+		"sys_platform":     "win32",
+		"platform_system":  "Windows",
+		"os_name":          "nt",
+		"platform_machine": "AMD64",
+	},
+}
+
+// platformAliases maps other common platform spellings to the keys of
+// platformEnvironments.
+var platformAliases = map[string]string{
+	"macos": "darwin",
+	"mac":   "darwin",
+	"osx":   "darwin",
+	"win32": "windows",
+	"win":   "windows",
+}
+
+// Environment holds the values of PEP 508 environment marker variables, such
+// as "python_version" or "sys_platform", for the interpreter dependencies are
+// resolved for.
+type Environment map[string]string
+
+// NewEnvironment returns the marker environment of CPython at pythonVersion
+// (e.g., "3.11") on platform: "linux", "darwin" or "windows". Empty values
+// use [DefaultPythonVersion] and [DefaultPlatform]. An unknown platform is
+// taken as the value of sys_platform.
+func NewEnvironment(pythonVersion, platform string) Environment {
+	full := cmp.Or(pythonVersion, DefaultPythonVersion)
+	parts := strings.SplitN(full, ".", 3)
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+	}
+	full = strings.Join(parts, ".")
+
+	env := Environment{
+		"python_version":                 parts[0] + "." + parts[1],
+		"python_full_version":            full,
+		"implementation_name":            "cpython",
+		"implementation_version":         full,
+		"platform_python_implementation": "CPython",
+		"platform_release":               "",
+		"platform_version":               "",
+		"extra":                          "",
+	}
+
+	platform = strings.ToLower(cmp.Or(platform, DefaultPlatform))
+	if alias, ok := platformAliases[platform]; ok {
+		platform = alias
+	}
+	if vars, ok := platformEnvironments[platform]; ok {
+		for k, v := range vars {
+			env[k] = v
+		}
+	} else {
+		env["sys_platform"] = platform
+		env["platform_system"] = ""
+		env["os_name"] = ""
+		env["platform_machine"] = ""
+	}
+	return env
+}
+
+// Evaluate reports whether a PEP 508 marker, such as
+// `python_version < "3.11" and sys_platform != "win32"`, holds in the
+// environment. Markers that name an extra hold only for that extra, so they
+// are false here.
+func (env Environment) Evaluate(marker string) (bool, error) {
+	tokens, err := tokenizeMarker(marker)
+	if err != nil {
+		return false, err
+	}
+	p := markerParser{env: env, tokens: tokens}
+	ok, err := p.or()
+	if err != nil {
+		return false, err
+	}
+	if p.pos < len(p.tokens) {
+		return false, fmt.Errorf("marker %q: unexpected %q", marker, p.tokens[p.pos].val)
+	}
+	return ok, nil
+}
+
+type markerTokenKind int
+
+const (
+	markerString markerTokenKind = iota
+	markerIdent
+	markerOp
+	markerLParen
+	markerRParen
+)
+
+type markerToken struct {
+	kind markerTokenKind
+	val  string
+}
+
+// markerTokenRE matches one token of a marker: a quoted string, an
+// operator, a parenthesis or a word.
+var markerTokenRE = regexp.MustCompile(`^(?:'([^']*)'|"([^"]*)"|(===|==|!=|<=|>=|~=|<|>)|([()])|([A-Za-z_][A-Za-z0-9_.]*))`)
+
+func tokenizeMarker(marker string) ([]markerToken, error) {
+	var tokens []markerToken
+	s := strings.TrimSpace(marker)
+	for s != "" {
+		m := markerTokenRE.FindStringSubmatchIndex(s)
+		if m == nil {
+			return nil, fmt.Errorf("marker %q: unexpected %q", marker, s)
+		}
+		switch {
+		case m[2] >= 0:
+			tokens = append(tokens, markerToken{markerString, s[m[2]:m[3]]})
+		case m[4] >= 0:
+			tokens = append(tokens, markerToken{markerString, s[m[4]:m[5]]})
+		case m[6] >= 0:
+			tokens = append(tokens, markerToken{markerOp, s[m[6]:m[7]]})
+		case m[8] >= 0 && s[m[8]] == '(':
+			tokens = append(tokens, markerToken{markerLParen, "("})
+		case m[8] >= 0:
+			tokens = append(tokens, markerToken{markerRParen, ")"})
+		default:
+			tokens = append(tokens, markerToken{markerIdent, s[m[10]:m[11]]})
+		}
+		s = strings.TrimSpace(s[m[1]:])
+	}
+	return tokens, nil
+}
+
+// markerParser evaluates tokens by recursive descent over the PEP 508
+// grammar: or-expressions of and-expressions of comparisons.
+type markerParser struct {
+	env    Environment
+	tokens []markerToken
+	pos    int
+}
+
+func (p *markerParser) peek() (markerToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return markerToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *markerParser) word(w string) bool {
+	t, ok := p.peek()
+	return ok && t.kind == markerIdent && t.val == w
+}
+
+func (p *markerParser) or() (bool, error) {
+	v, err := p.and()
+	for err == nil && p.word("or") {
+		p.pos++
+		var r bool
+		r, err = p.and()
+		v = v || r
+	}
+	return v, err
+}
+
+func (p *markerParser) and() (bool, error) {
+	v, err := p.atom()
+	for err == nil && p.word("and") {
+		p.pos++
+		var r bool
+		r, err = p.atom()
+		v = v && r
+	}
+	return v, err
+}
+
+func (p *markerParser) atom() (bool, error) {
+	if t, ok := p.peek(); ok && t.kind == markerLParen {
+		p.pos++
+		v, err := p.or()
+		if err != nil {
+			return false, err
+		}
+		if t, ok := p.peek(); !ok || t.kind != markerRParen {
+			return false, fmt.Errorf("marker: missing )")
+		}
+		p.pos++
+		return v, nil
+	}
+
+	lhs, lhsVar, err := p.value()
+	if err != nil {
+		return false, err
+	}
+	op, err := p.op()
+	if err != nil {
+		return false, err
+	}
+	rhs, rhsVar, err := p.value()
+	if err != nil {
+		return false, err
+	}
+	if lhsVar == "extra" || rhsVar == "extra" {
+		lhs, rhs = normalizeExtra(lhs), normalizeExtra(rhs)
+	}
+	return compareMarker(lhs, op, rhs), nil
+}
+
+// value returns the next string or variable value, and the variable name
+// if it was one.
+func (p *markerParser) value() (val, name string, err error) {
+	t, ok := p.peek()
+	if !ok {
+		return "", "", fmt.Errorf("marker: missing value")
+	}
+	p.pos++
+	switch t.kind {
+	case markerString:
+		return t.val, "", nil
+	case markerIdent:
+		v, ok := p.env[t.val]
+		if !ok {
+			return "", "", fmt.Errorf("marker: unknown variable %q", t.val)
+		}
+		return v, t.val, nil
+	}
+	return "", "", fmt.Errorf("marker: unexpected %q", t.val)
+}
+
+func (p *markerParser) op() (string, error) {
+	t, ok := p.peek()
+	switch {
+	case !ok:
+		return "", fmt.Errorf("marker: missing operator")
+	case t.kind == markerOp, t.kind == markerIdent && t.val == "in":
+		p.pos++
+		return t.val, nil
+	case t.kind == markerIdent && t.val == "not":
+		p.pos++
+		if !p.word("in") {
+			return "", fmt.Errorf("marker: expected \"in\" after \"not\"")
+		}
+		p.pos++
+		return "not in", nil
+	}
+	return "", fmt.Errorf("marker: unexpected %q", t.val)
+}
+
+// normalizeExtra normalizes extra names like package names (PEP 685).
+func normalizeExtra(s string) string {
+	return strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(s))
+}
+
+// markerVersionRE matches the versions markers compare: release numbers,
+// optionally ending in a ".*" wildcard.
+var markerVersionRE = regexp.MustCompile(`^\d+(?:\.\d+)*(?:\.\*)?$`)
+
+// compareMarker applies a marker operator. Like PEP 508, it compares as
+// versions when both sides are versions and as strings otherwise.
+func compareMarker(lhs, op, rhs string) bool {
+	switch op {
+	case "in":
+		return strings.Contains(rhs, lhs)
+	case "not in":
+		return !strings.Contains(rhs, lhs)
+	case "===":
+		return lhs == rhs
+	}
+
+	if markerVersionRE.MatchString(lhs) && markerVersionRE.MatchString(rhs) && !strings.HasSuffix(lhs, "*") {
+		if prefix, ok := strings.CutSuffix(rhs, ".*"); ok {
+			// Wildcards only make sense for equality: "3.*" matches any 3.x
+			l, r := parseVersionParts(lhs), parseVersionParts(prefix)
+			match := len(l) >= len(r) && compareVersionParts(l[:len(r)], r) == 0
+			switch op {
+			case "==":
+				return match
+			case "!=":
+				return !match
+			}
+			return false
+		}
+		l, r := parseVersionParts(lhs), parseVersionParts(rhs)
+		c := compareVersionParts(l, r)
+		switch op {
+		case "==":
+			return c == 0
+		case "!=":
+			return c != 0
+		case "<":
+			return c < 0
+		case "<=":
+			return c <= 0
+		case ">":
+			return c > 0
+		case ">=":
+			return c >= 0
+		case "~=":
+			// Compatible release: ~=3.8 is >=3.8 with the same major version
+			if len(r) < 2 {
+				return false
+			}
+			prefix := r[:len(r)-1]
+			return c >= 0 && len(l) >= len(prefix) && compareVersionParts(l[:len(prefix)], prefix) == 0
+		}
+		return false
+	}
+
+	c := strings.Compare(lhs, rhs)
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package pypi
+
+import "testing"
+
+func TestEnvironment_Evaluate(t *testing.T) {
+	linux := NewEnvironment("3.11", "linux")
+	windows := NewEnvironment("3.8", "windows")
+
+	tests := []struct {
+		env    Environment
+		marker string
+		want   bool
+	}{
+		{linux, `python_version < "3.11"`, false},
+		{linux, `python_version >= "3.8"`, true},
+		{linux, `python_version == "3.11"`, true},
+		{linux, `python_version == "3.*"`, true},
+		{linux, `python_version != "3.*"`, false},
+		{linux, `python_full_version >= "3.11.0"`, true},
+		{linux, `python_version ~= "3.9"`, true},
+		{linux, `python_version ~= "2.7"`, false},
+		{linux, `"3.10" < python_version`, true},
+		{linux, `sys_platform == "linux"`, true},
+		{linux, `sys_platform == "win32" or os_name == "nt"`, false},
+		{windows, `sys_platform == "win32" or os_name == "nt"`, true},
+		{windows, `platform_system == "Windows" and python_version < "3.9"`, true},
+		{windows, `platform_system == "Windows" and python_version < "3.8"`, false},
+		{linux, `(sys_platform == "darwin" or sys_platform == "linux") and python_version >= "3.10"`, true},
+		{linux, `sys_platform == "darwin" or (sys_platform == "linux" and python_version < "3.10")`, false},
+		{linux, `"linux" in sys_platform`, true},
+		{linux, `"win" not in sys_platform`, true},
+		{linux, `platform_machine == "x86_64"`, true},
+		{linux, `implementation_name == "cpython"`, true},
+		{linux, `extra == "test"`, false},
+		{linux, `extra != "test"`, true},
+		{linux, `python_version >= "3.8" and extra == "socks"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.marker, func(t *testing.T) {
+			got, err := tt.env.Evaluate(tt.marker)
+			if err != nil {
+				t.Fatalf("Evaluate(%q) failed: %v", tt.marker, err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate(%q) on %s = %v, want %v", tt.marker, tt.env["sys_platform"], got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvironment_Evaluate_Invalid(t *testing.T) {
+	env := NewEnvironment("", "")
+	for _, marker := range []string{
+		`python_version <`,
+		`unknown_var == "1"`,
+		`(python_version == "3.11"`,
+		`python_version == "3.11" trailing`,
+		`python_version not "3.11"`,
+	} {
+		if _, err := env.Evaluate(marker); err == nil {
+			t.Errorf("Evaluate(%q) succeeded, want an error", marker)
+		}
+	}
+}
+
+func TestNewEnvironment(t *testing.T) {
+	env := NewEnvironment("3.12", "macos")
+	if env["python_version"] != "3.12" || env["python_full_version"] != "3.12.0" {
+		t.Errorf("python versions = %q, %q", env["python_version"], env["python_full_version"])
+	}
+	if env["sys_platform"] != "darwin" || env["platform_system"] != "Darwin" {
+		t.Errorf("platform = %q, %q, want darwin", env["sys_platform"], env["platform_system"])
+	}
+
+	env = NewEnvironment("3.10.4", "")
+	if env["python_version"] != "3.10" || env["sys_platform"] != DefaultPlatform {
+		t.Errorf("defaults = %q on %q", env["python_version"], env["sys_platform"])
+	}
+}
@@ -0,0 +1,120 @@
+package pypi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+// simpleJSONType is the PEP 691 media type of the JSON simple index.
+const simpleJSONType = "application/vnd.pypi.simple.v1+json"
+
+// errNoCoreMetadata reports a release without a wheel whose metadata the
+// simple index serves.
+var errNoCoreMetadata = errors.New("no wheel with core metadata")
+
+// simpleProject is a project page of the JSON simple index.
+type simpleProject struct {
+	Name  string       `json:"name"`
+	Files []simpleFile `json:"files"`
+}
+
+type simpleFile struct {
+	Filename string `json:"filename"`
+	URL      string `json:"url"`
+	// CoreMetadata is false, true or the hashes of the file's metadata,
+	// which PEP 658 serves next to it with a ".metadata" suffix.
+	CoreMetadata any `json:"core-metadata"`
+	// DistInfoMetadata is the name CoreMetadata had before PEP 714.
+	DistInfoMetadata any `json:"dist-info-metadata"`
+}
+
+func (f simpleFile) hasMetadata() bool {
+	for _, v := range []any{f.CoreMetadata, f.DistInfoMetadata} {
+		switch v := v.(type) {
+		case bool:
+			if v {
+				return true
+			}
+		case map[string]any:
+			return true
+		}
+	}
+	return false
+}
+
+// fetchSimpleRequires returns the Requires-Dist entries of a release from
+// the metadata of one of its wheels, found through the JSON simple index
+// (PEP 691). This fills in for the JSON API, which leaves requires_dist
+// null when the first file uploaded for the release did not declare it.
+func (c *Client) fetchSimpleRequires(ctx context.Context, pkg, version string) ([]string, error) {
+	pageURL := c.simpleURL + "/" + pkg + "/"
+
+	var project simpleProject
+	if err := c.GetWithHeaders(ctx, pageURL, map[string]string{"Accept": simpleJSONType}, &project); err != nil {
+		if errors.Is(err, integrations.ErrNotFound) {
+			return nil, fmt.Errorf("%w: pypi package %s", err, pkg)
+		}
+		return nil, err
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range project.Files {
+		if wheelVersion(f.Filename) != version || !f.hasMetadata() {
+			continue
+		}
+		ref, err := url.Parse(f.URL)
+		if err != nil {
+			continue
+		}
+		// File URLs may be relative to the project page
+		u := base.ResolveReference(ref)
+		u.Fragment = ""
+		text, err := c.GetText(ctx, u.String()+".metadata")
+		if err != nil {
+			return nil, err
+		}
+		return parseRequiresDist(text), nil
+	}
+	return nil, errNoCoreMetadata
+}
+
+// wheelVersion returns the version in a wheel filename, which is
+// "{name}-{version}(-{build})?-{python}-{abi}-{platform}.whl", or "" for
+// other files.
+func wheelVersion(filename string) string {
+	name, ok := strings.CutSuffix(filename, ".whl")
+	if !ok {
+		return ""
+	}
+	parts := strings.Split(name, "-")
+	if len(parts) < 5 {
+		return ""
+	}
+	return parts[1]
+}
+
+// parseRequiresDist returns the Requires-Dist fields of core metadata,
+// which uses email header syntax. The result is empty but not nil when
+// there are none.
+func parseRequiresDist(metadata string) []string {
+	requires := []string{}
+	for line := range strings.Lines(metadata) {
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break // the description body follows the headers
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(key, "Requires-Dist") {
+			requires = append(requires, strings.TrimSpace(value))
+		}
+	}
+	return requires
+}
@@ -0,0 +1,67 @@
+package pypi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestClient_FetchPackage_SimpleIndexFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/demo/json":
+			// The sdist was uploaded first, so PyPI has no requires_dist
+			json.NewEncoder(w).Encode(map[string]any{
+				"info": map[string]any{"name": "demo", "version": "1.0", "requires_dist": nil},
+			})
+		case "/simple/demo/":
+			if r.Header.Get("Accept") != simpleJSONType {
+				http.Error(w, "want JSON", http.StatusNotAcceptable)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{
+				"name": "demo",
+				"files": []map[string]any{
+					{"filename": "demo-1.0.tar.gz", "url": "../../files/demo-1.0.tar.gz"},
+					{"filename": "demo-0.9-py3-none-any.whl", "url": "../../files/demo-0.9-py3-none-any.whl", "core-metadata": true},
+					{"filename": "demo-1.0-py3-none-any.whl", "url": "../../files/demo-1.0-py3-none-any.whl#sha256=abc", "core-metadata": map[string]string{"sha256": "def"}},
+				},
+			})
+		case "/files/demo-1.0-py3-none-any.whl.metadata":
+			w.Write([]byte("Metadata-Version: 2.1\nName: demo\nVersion: 1.0\nRequires-Dist: idna>=2.5\nRequires-Dist: pytest ; extra == 'test'\n\nRequires-Dist: not-a-header\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	info, err := testClient(t, server.URL).FetchPackage(context.Background(), "demo", true)
+	if err != nil {
+		t.Fatalf("FetchPackage failed: %v", err)
+	}
+	want := []Dependency{{Name: "idna", Constraint: ">=2.5"}}
+	if !slices.Equal(info.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want %v", info.Dependencies, want)
+	}
+}
+
+func TestWheelVersion(t *testing.T) {
+	tests := []struct {
+		filename, want string
+	}{
+		{"requests-2.31.0-py3-none-any.whl", "2.31.0"},
+		{"numpy-1.26.4-cp311-cp311-manylinux_2_17_x86_64.manylinux2014_x86_64.whl", "1.26.4"},
+		{"pkg-1.0-1-py3-none-any.whl", "1.0"},
+		{"requests-2.31.0.tar.gz", ""},
+		{"broken.whl", ""},
+	}
+
+	for _, tt := range tests {
+		if got := wheelVersion(tt.filename); got != tt.want {
+			t.Errorf("wheelVersion(%q) = %q, want %q", tt.filename, got, tt.want)
+		}
+	}
+}
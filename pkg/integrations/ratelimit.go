@@ -36,10 +36,12 @@ type RateLimit = BurstLimit
 var DefaultRateLimits = map[string]BurstLimit{
 	"pypi":          {RequestsPerSecond: 50, Burst: 30},
 	"npm":           {RequestsPerSecond: 50, Burst: 30},
-	"crates":        {RequestsPerSecond: 5, Burst: 10}, // strict 1/s policy
+	"crates":        {RequestsPerSecond: 5, Burst: 10},  // strict 1/s policy
+	"crates-index":  {RequestsPerSecond: 50, Burst: 30}, // CDN-backed sparse index
 	"rubygems":      {RequestsPerSecond: 30, Burst: 20},
 	"packagist":     {RequestsPerSecond: 30, Burst: 20},
 	"maven":         {RequestsPerSecond: 30, Burst: 20},
+	"nuget":         {RequestsPerSecond: 50, Burst: 30},   // CDN-backed
 	"goproxy":       {RequestsPerSecond: 50, Burst: 30},   // CDN-backed
 	"github":        {RequestsPerSecond: 10, Burst: 50},   // 5000/hour limit, higher burst for parallel enrichment
 	"github_unauth": {RequestsPerSecond: 0.015, Burst: 5}, // 60/hour limit
@@ -0,0 +1,66 @@
+package integrations
+
+import "context"
+
+// RegistryClient is the interface every package registry integration
+// implements, so callers can fetch packages from any registry the same way.
+// Each registry package provides one through its NewRegistry function,
+// which wraps the registry's own Client.
+//
+// Implementations must be safe for concurrent use.
+type RegistryClient interface {
+	// Name returns the registry identifier (e.g., "pypi", "npm").
+	Name() string
+
+	// FetchPackage retrieves a package at version, or at its latest
+	// version when version is empty. It returns an error wrapping
+	// [ErrNotFound] when the package or version does not exist.
+	FetchPackage(ctx context.Context, name, version string, opts FetchOptions) (*PackageInfo, error)
+
+	// ListVersions returns the versions of a package, oldest first.
+	ListVersions(ctx context.Context, name string, opts FetchOptions) ([]string, error)
+}
+
+// RuntimeConstraintLister is implemented by registry clients that can list
+// the runtime constraint of every version of a package in one request.
+type RuntimeConstraintLister interface {
+	// ListVersionsWithConstraints returns the runtime constraint of every
+	// version (e.g., ">=3.8" for Python); empty when a version has none.
+	ListVersionsWithConstraints(ctx context.Context, name string, opts FetchOptions) (map[string]string, error)
+}
+
+// FetchOptions configures a [RegistryClient] request.
+type FetchOptions struct {
+	// Refresh bypasses the cache and fetches fresh data from the registry.
+	Refresh bool
+}
+
+// PackageInfo is the registry metadata of a package version, in the form
+// every [RegistryClient] returns it. Fields a registry does not publish are
+// left empty.
+type PackageInfo struct {
+	Name              string            // Package name as the registry spells it (never empty in valid info)
+	Version           string            // Version string (never empty in valid info)
+	Dependencies      []Dependency      // Runtime dependencies with constraints (nil if none)
+	Description       string            // Short description (may be empty)
+	License           string            // License identifier or expression (may be empty)
+	LicenseText       string            // Full text of a custom license (may be empty)
+	Author            string            // Primary author (may be empty)
+	Repository        string            // Source repository URL (may be empty)
+	HomePage          string            // Homepage URL (may be empty)
+	ProjectURLs       map[string]string // Other URLs the registry lists, by label (may be nil)
+	Downloads         int               // Download count (0 if unavailable)
+	InstallSize       int64             // Unpacked size in bytes (0 if unavailable)
+	RuntimeConstraint string            // Runtime requirement, such as ">=3.8" or a bare MSRV "1.70" (may be empty)
+	Bundled           []string          // Dependencies shipped inside the package, sorted (nil if none)
+	Yanked            bool              // Whether the release was withdrawn from the registry
+	Abandoned         bool              // Whether the maintainers gave the package up
+	Replacement       string            // Package suggested instead of an abandoned one (may be empty)
+}
+
+// Dependency is a dependency of a [PackageInfo] with its version constraint
+// in the registry's own syntax.
+type Dependency struct {
+	Name       string // Package name
+	Constraint string // Version constraint (e.g., "^1.0"), empty for any version
+}
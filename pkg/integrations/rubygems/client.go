@@ -1,9 +1,11 @@
 package rubygems
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -29,6 +31,7 @@ type Dependency struct {
 type GemInfo struct {
 	Name                string       // Gem name, normalized lowercase (e.g., "rails", never empty in valid info)
 	Version             string       // Current version (e.g., "7.1.2", never empty in valid info)
+	Platform            string       // Platform the gem was built for ("ruby" for pure-Ruby gems, may be empty)
 	Dependencies        []Dependency // Runtime dependencies with version requirements (nil or empty if none)
 	SourceCodeURI       string       // Source code repository URL (may be empty)
 	HomepageURI         string       // Homepage URL (may be empty)
@@ -46,6 +49,7 @@ type GemInfo struct {
 type Client struct {
 	*integrations.Client
 	baseURL string
+	v2URL   string
 }
 
 // NewClient creates a RubyGems client with the given cache backend.
@@ -60,6 +64,7 @@ func NewClient(backend cache.Cache, cacheTTL time.Duration) *Client {
 	return &Client{
 		Client:  integrations.NewClientWithRateLimit(backend, "rubygems:", cacheTTL, nil, rl.RequestsPerSecond, rl.Burst),
 		baseURL: "https://rubygems.org/api/v1",
+		v2URL:   "https://rubygems.org/api/v2",
 	}
 }
 
@@ -96,7 +101,9 @@ func (c *Client) FetchGem(ctx context.Context, gem string, refresh bool) (*GemIn
 // FetchGemVersion retrieves metadata for a specific version of a Ruby gem.
 //
 // The gem parameter is normalized to lowercase. The version must be an exact version
-// string (e.g., "7.1.2").
+// string (e.g., "7.1.2"). It may end in a platform as in Gemfile.lock files
+// (e.g., "1.15.4-x86_64-linux"), which selects the gem built for that
+// platform; native gems often have other dependencies than the pure-Ruby one.
 //
 // If refresh is true, the cache is bypassed and a fresh API call is made.
 //
@@ -153,12 +160,22 @@ func (c *Client) fetch(ctx context.Context, gem, version string, info *GemInfo)
 
 // fetchVersionFromV2API uses the RubyGems V2 API to get version-specific info with dependencies and metadata.
 // This API provides full gem metadata including source_code_uri, homepage_uri, dependencies, and ruby_version.
+//
+// Registries without the V2 API, such as some private gem servers, are
+// served from the versions list instead.
 func (c *Client) fetchVersionFromV2API(ctx context.Context, gem, version string, info *GemInfo) error {
-	url := fmt.Sprintf("https://rubygems.org/api/v2/rubygems/%s/versions/%s.json", gem, version)
+	number, platform := SplitPlatform(version)
+	endpoint := fmt.Sprintf("%s/rubygems/%s/versions/%s.json", c.v2URL, gem, number)
+	if platform != "" && platform != RubyPlatform {
+		endpoint += "?platform=" + url.QueryEscape(platform)
+	}
 
 	var data gemV2Response
-	if err := c.Get(ctx, url, &data); err != nil {
+	if err := c.Get(ctx, endpoint, &data); err != nil {
 		if errors.Is(err, integrations.ErrNotFound) {
+			if c.fetchVersionFromList(ctx, gem, number, platform, info) {
+				return nil
+			}
 			return fmt.Errorf("%w: gem %s version %s", err, gem, version)
 		}
 		return err
@@ -167,6 +184,7 @@ func (c *Client) fetchVersionFromV2API(ctx context.Context, gem, version string,
 	*info = GemInfo{
 		Name:                data.Name,
 		Version:             data.Version,
+		Platform:            data.Platform,
 		Description:         data.Info,
 		License:             strings.Join(data.Licenses, ", "),
 		SourceCodeURI:       data.SourceCodeURI,
@@ -179,10 +197,46 @@ func (c *Client) fetchVersionFromV2API(ctx context.Context, gem, version string,
 	return nil
 }
 
+// fetchVersionFromList fills info from the entry of the versions list for
+// number and platform, and reports whether there was one.
+func (c *Client) fetchVersionFromList(ctx context.Context, gem, number, platform string, info *GemInfo) bool {
+	entries, err := c.versionEntries(ctx, gem, false)
+	if err != nil {
+		return false
+	}
+	for _, v := range entries {
+		if v.Number != number || !samePlatform(v.Platform, platform) {
+			continue
+		}
+		*info = GemInfo{
+			Name:                gem,
+			Version:             v.Number,
+			Platform:            v.Platform,
+			Description:         v.Description,
+			License:             strings.Join(v.Licenses, ", "),
+			SourceCodeURI:       v.Metadata.SourceCodeURI,
+			HomepageURI:         v.Metadata.HomepageURI,
+			Downloads:           v.Downloads,
+			Authors:             v.Authors,
+			Dependencies:        runtimeDeps(v.Dependencies.Runtime),
+			RequiredRubyVersion: v.RequiredRubyVersion,
+		}
+		return true
+	}
+	return false
+}
+
+// samePlatform reports whether two platforms are the same, with an empty
+// platform standing for the pure-Ruby one.
+func samePlatform(a, b string) bool {
+	return cmp.Or(a, RubyPlatform) == cmp.Or(b, RubyPlatform)
+}
+
 // gemV2Response represents the JSON response from RubyGems V2 API for a specific version.
 type gemV2Response struct {
 	Name          string   `json:"name"`
 	Version       string   `json:"version"`
+	Platform      string   `json:"platform"`
 	Info          string   `json:"info"`
 	Authors       string   `json:"authors"`
 	Licenses      []string `json:"licenses"`
@@ -212,66 +266,90 @@ func runtimeDeps(deps []dependency) []Dependency {
 	return result
 }
 
-// ListVersions returns all available versions for a gem, sorted from oldest to newest.
-func (c *Client) ListVersions(ctx context.Context, gem string, refresh bool) ([]string, error) {
-	gem = strings.ToLower(strings.TrimSpace(gem))
-	key := gem + ":versions"
+// versionEntries returns the versions list of a gem, which has an entry
+// for each platform a version was published for.
+func (c *Client) versionEntries(ctx context.Context, gem string, refresh bool) ([]gemVersionResponse, error) {
+	key := gem + ":version_entries"
 
-	var versions []string
-	err := c.Cached(ctx, key, refresh, &versions, func() error {
+	var entries []gemVersionResponse
+	err := c.Cached(ctx, key, refresh, &entries, func() error {
 		url := fmt.Sprintf("%s/versions/%s.json", c.baseURL, gem)
-
-		var versionsResp []gemVersionResponse
-		if err := c.Get(ctx, url, &versionsResp); err != nil {
+		if err := c.Get(ctx, url, &entries); err != nil {
 			if errors.Is(err, integrations.ErrNotFound) {
 				return fmt.Errorf("%w: gem %s", err, gem)
 			}
 			return err
 		}
-
-		versions = make([]string, 0, len(versionsResp))
-		for _, v := range versionsResp {
-			versions = append(versions, v.Number)
-		}
-
-		// Sort versions semantically (oldest to newest)
-		integrations.SortVersions(versions)
 		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
+	return entries, nil
+}
+
+// ListVersions returns all available versions for a gem, sorted from oldest to newest.
+// Versions published for several platforms are listed once.
+func (c *Client) ListVersions(ctx context.Context, gem string, refresh bool) ([]string, error) {
+	gem = strings.ToLower(strings.TrimSpace(gem))
+
+	entries, err := c.versionEntries(ctx, gem, refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(entries))
+	versions := make([]string, 0, len(entries))
+	for _, v := range entries {
+		if !seen[v.Number] {
+			seen[v.Number] = true
+			versions = append(versions, v.Number)
+		}
+	}
+
+	// Sort versions semantically (oldest to newest)
+	integrations.SortVersions(versions)
 	return versions, nil
 }
 
 // ListVersionsWithConstraints returns all versions and their Ruby runtime constraints.
 // Returns a map of version -> required_ruby_version (empty string if not specified).
+// The constraint of the pure-Ruby gem wins when a version was published for
+// several platforms.
 func (c *Client) ListVersionsWithConstraints(ctx context.Context, gem string, refresh bool) (map[string]string, error) {
 	gem = strings.ToLower(strings.TrimSpace(gem))
-	key := gem + ":version_constraints"
-
-	var result map[string]string
-	err := c.Cached(ctx, key, refresh, &result, func() error {
-		url := fmt.Sprintf("%s/versions/%s.json", c.baseURL, gem)
 
-		var versionsResp []gemVersionResponse
-		if err := c.Get(ctx, url, &versionsResp); err != nil {
-			if errors.Is(err, integrations.ErrNotFound) {
-				return fmt.Errorf("%w: gem %s", err, gem)
-			}
-			return err
-		}
+	entries, err := c.versionEntries(ctx, gem, refresh)
+	if err != nil {
+		return nil, err
+	}
 
-		result = make(map[string]string, len(versionsResp))
-		for _, v := range versionsResp {
+	result := make(map[string]string, len(entries))
+	for _, v := range entries {
+		if _, ok := result[v.Number]; !ok || samePlatform(v.Platform, RubyPlatform) {
 			result[v.Number] = strings.TrimSpace(v.RequiredRubyVersion)
 		}
-		return nil
-	})
+	}
+	return result, nil
+}
+
+// Platforms returns the platforms a gem version was published for, such as
+// "ruby" and "x86_64-linux".
+func (c *Client) Platforms(ctx context.Context, gem, version string, refresh bool) ([]string, error) {
+	gem = strings.ToLower(strings.TrimSpace(gem))
+
+	entries, err := c.versionEntries(ctx, gem, refresh)
 	if err != nil {
 		return nil, err
 	}
-	return result, nil
+
+	var platforms []string
+	for _, v := range entries {
+		if v.Number == version {
+			platforms = append(platforms, cmp.Or(v.Platform, RubyPlatform))
+		}
+	}
+	return platforms, nil
 }
 
 type gemResponse struct {
@@ -291,6 +369,7 @@ type gemResponse struct {
 
 type gemVersionResponse struct {
 	Number              string   `json:"number"`
+	Platform            string   `json:"platform"`
 	Description         string   `json:"description"`
 	Licenses            []string `json:"licenses"`
 	Downloads           int      `json:"downloads_count"`
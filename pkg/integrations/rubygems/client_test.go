@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -108,6 +109,91 @@ func TestClient_FetchGemVersion(t *testing.T) {
 	}
 }
 
+func TestClient_FetchGemVersion_Platform(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/rubygems/nokogiri/versions/1.15.4.json" {
+			http.NotFound(w, r)
+			return
+		}
+		resp := gemV2Response{Name: "nokogiri", Version: "1.15.4", Platform: "ruby"}
+		resp.Dependencies.Runtime = []dependency{
+			{Name: "mini_portile2", Requirements: "~> 2.8.2"},
+			{Name: "racc", Requirements: "~> 1.4"},
+		}
+		if p := r.URL.Query().Get("platform"); p != "" {
+			// Native gems ship precompiled and need no build tools
+			resp.Platform = p
+			resp.Dependencies.Runtime = resp.Dependencies.Runtime[1:]
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	c := testClient(t, server.URL)
+
+	info, err := c.FetchGemVersion(context.Background(), "nokogiri", "1.15.4", true)
+	if err != nil {
+		t.Fatalf("FetchGemVersion failed: %v", err)
+	}
+	if info.Platform != "ruby" || len(info.Dependencies) != 2 {
+		t.Errorf("pure-Ruby gem = %s with %v, want ruby with 2 deps", info.Platform, info.Dependencies)
+	}
+
+	info, err = c.FetchGemVersion(context.Background(), "nokogiri", "1.15.4-x86_64-linux", true)
+	if err != nil {
+		t.Fatalf("FetchGemVersion failed: %v", err)
+	}
+	if info.Version != "1.15.4" {
+		t.Errorf("expected version 1.15.4, got %s", info.Version)
+	}
+	if info.Platform != "x86_64-linux" || len(info.Dependencies) != 1 || info.Dependencies[0].Name != "racc" {
+		t.Errorf("native gem = %s with %v, want x86_64-linux with racc only", info.Platform, info.Dependencies)
+	}
+}
+
+func TestClient_Platforms(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/versions/nokogiri.json" {
+			_ = json.NewEncoder(w).Encode([]gemVersionResponse{
+				{Number: "1.15.4", Platform: "x86_64-linux", RequiredRubyVersion: ">= 3.0, < 3.3.dev"},
+				{Number: "1.15.4", Platform: "arm64-darwin", RequiredRubyVersion: ">= 3.0, < 3.3.dev"},
+				{Number: "1.15.4", Platform: "ruby", RequiredRubyVersion: ">= 2.7.0"},
+				{Number: "1.15.3", Platform: "ruby"},
+			})
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	c := testClient(t, server.URL)
+	ctx := context.Background()
+
+	platforms, err := c.Platforms(ctx, "nokogiri", "1.15.4", true)
+	if err != nil {
+		t.Fatalf("Platforms failed: %v", err)
+	}
+	if want := []string{"x86_64-linux", "arm64-darwin", "ruby"}; !slices.Equal(platforms, want) {
+		t.Errorf("Platforms() = %v, want %v", platforms, want)
+	}
+
+	versions, err := c.ListVersions(ctx, "nokogiri", false)
+	if err != nil {
+		t.Fatalf("ListVersions failed: %v", err)
+	}
+	if want := []string{"1.15.3", "1.15.4"}; !slices.Equal(versions, want) {
+		t.Errorf("ListVersions() = %v, want %v", versions, want)
+	}
+
+	constraints, err := c.ListVersionsWithConstraints(ctx, "nokogiri", false)
+	if err != nil {
+		t.Fatalf("ListVersionsWithConstraints failed: %v", err)
+	}
+	if got := constraints["1.15.4"]; got != ">= 2.7.0" {
+		t.Errorf("constraint of 1.15.4 = %q, want the pure-Ruby gem's >= 2.7.0", got)
+	}
+}
+
 func TestClient_ListVersionsWithConstraints(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/versions/rails.json" {
@@ -190,5 +276,6 @@ func testClient(t *testing.T, serverURL string) *Client {
 	return &Client{
 		Client:  integrations.NewClient(cache.NewNullCache(), "rubygems:", time.Hour, nil),
 		baseURL: serverURL,
+		v2URL:   serverURL + "/v2",
 	}
 }
@@ -41,4 +41,16 @@
 //
 // Only runtime dependencies are included. Development dependencies are
 // filtered out. Gem names are normalized to lowercase.
+//
+// # Versions and Platforms
+//
+// [Client.FetchGemVersion] reads a specific version from the V2 API
+// (/api/v2/rubygems/NAME/versions/VERSION.json), falling back to the
+// versions list for registries without it. Gems may be published once per
+// platform: besides the pure-Ruby gem ("ruby"), native gems such as
+// "x86_64-linux" often have other dependencies. Versions written as in
+// Gemfile.lock files, like "1.15.4-x86_64-linux", select the gem of that
+// platform, and [Client.Platforms] lists the platforms of a version.
+// [Platform], [MatchPlatform] and [BestPlatform] map target platforms to
+// the gems that run on them.
 package rubygems
@@ -0,0 +1,97 @@
+package rubygems
+
+import "strings"
+
+// RubyPlatform is the platform of pure-Ruby gems, which run everywhere.
+const RubyPlatform = "ruby"
+
+// osPlatforms maps target operating systems to the RubyGems platform of
+// native gems built for them, matching the machines other ecosystems assume
+// for the same names.
+var osPlatforms = map[string]string{
+	"linux":   "x86_64-linux",
+	"darwin":  "arm64-darwin",
+	"macos":   "arm64-darwin",
+	"windows": "x64-mingw-ucrt",
+	"win32":   "x64-mingw-ucrt",
+}
+
+// cpuAliases maps other spellings of a CPU to the one RubyGems uses most.
+var cpuAliases = map[string]string{
+	"x64":     "x86_64",
+	"amd64":   "x86_64",
+	"aarch64": "arm64",
+}
+
+// Platform returns the RubyGems platform for a target platform. The
+// operating systems "linux", "darwin" and "windows" stand for
+// "x86_64-linux", "arm64-darwin" and "x64-mingw-ucrt"; RubyGems platforms
+// such as "aarch64-linux" are returned as is. It returns "" for an empty
+// target, which only pure-Ruby gems are resolved for.
+func Platform(target string) string {
+	target = strings.ToLower(strings.TrimSpace(target))
+	if p, ok := osPlatforms[target]; ok {
+		return p
+	}
+	return target
+}
+
+// SplitPlatform splits a version from a Gemfile.lock, such as
+// "1.15.4-x86_64-linux", into the version number and the platform, like
+// Bundler does. The platform is empty for pure-Ruby gems.
+func SplitPlatform(version string) (number, platform string) {
+	number, platform, _ = strings.Cut(version, "-")
+	return number, platform
+}
+
+// MatchPlatform reports whether a gem built for platform runs on target.
+// Pure-Ruby gems run everywhere; native ones need the same operating system
+// and CPU, or a "universal" CPU. OS versions, as in "arm64-darwin-22", are
+// ignored.
+func MatchPlatform(platform, target string) bool {
+	if platform == "" || platform == RubyPlatform {
+		return true
+	}
+	pcpu, pos := splitPlatform(platform)
+	tcpu, tos := splitPlatform(target)
+	if pos != tos {
+		return false
+	}
+	return pcpu == tcpu || pcpu == "" || tcpu == "" || pcpu == "universal"
+}
+
+// BestPlatform picks the platform to use on target among those a gem
+// version was published for: a native one matching target, else the
+// pure-Ruby one. It returns "" when none runs on target. An empty target
+// only accepts the pure-Ruby platform.
+func BestPlatform(platforms []string, target string) string {
+	best := ""
+	for _, p := range platforms {
+		if p == "" {
+			p = RubyPlatform
+		}
+		switch {
+		case p == RubyPlatform:
+			if best == "" {
+				best = p
+			}
+		case target != "" && MatchPlatform(p, target):
+			return p
+		}
+	}
+	return best
+}
+
+// splitPlatform returns the CPU and operating system of a platform such as
+// "x86_64-linux-gnu". Single-word platforms such as "java" have no CPU.
+func splitPlatform(platform string) (cpu, os string) {
+	parts := strings.Split(strings.ToLower(platform), "-")
+	if len(parts) == 1 {
+		return "", parts[0]
+	}
+	cpu = parts[0]
+	if alias, ok := cpuAliases[cpu]; ok {
+		cpu = alias
+	}
+	return cpu, parts[1]
+}
@@ -0,0 +1,80 @@
+package rubygems
+
+import "testing"
+
+func TestPlatform(t *testing.T) {
+	tests := map[string]string{
+		"":              "",
+		"linux":         "x86_64-linux",
+		"Darwin":        "arm64-darwin",
+		"windows":       "x64-mingw-ucrt",
+		"aarch64-linux": "aarch64-linux",
+	}
+	for target, want := range tests {
+		if got := Platform(target); got != want {
+			t.Errorf("Platform(%q) = %q, want %q", target, got, want)
+		}
+	}
+}
+
+func TestSplitPlatform(t *testing.T) {
+	tests := []struct {
+		version, number, platform string
+	}{
+		{"1.15.4", "1.15.4", ""},
+		{"1.15.4-x86_64-linux", "1.15.4", "x86_64-linux"},
+		{"1.15.4-arm64-darwin", "1.15.4", "arm64-darwin"},
+		{"9.4.3.0-java", "9.4.3.0", "java"},
+	}
+	for _, tt := range tests {
+		number, platform := SplitPlatform(tt.version)
+		if number != tt.number || platform != tt.platform {
+			t.Errorf("SplitPlatform(%q) = %q, %q, want %q, %q", tt.version, number, platform, tt.number, tt.platform)
+		}
+	}
+}
+
+func TestMatchPlatform(t *testing.T) {
+	tests := []struct {
+		platform, target string
+		want             bool
+	}{
+		{"ruby", "x86_64-linux", true},
+		{"", "arm64-darwin", true},
+		{"x86_64-linux", "x86_64-linux", true},
+		{"x86_64-linux-gnu", "x86_64-linux", true},
+		{"arm64-darwin-22", "arm64-darwin", true},
+		{"universal-darwin", "arm64-darwin", true},
+		{"x64-mingw-ucrt", "x64-mingw-ucrt", true},
+		{"aarch64-linux", "arm64-linux", true},
+		{"x86_64-linux", "aarch64-linux", false},
+		{"x86_64-darwin", "x86_64-linux", false},
+		{"java", "x86_64-linux", false},
+	}
+	for _, tt := range tests {
+		if got := MatchPlatform(tt.platform, tt.target); got != tt.want {
+			t.Errorf("MatchPlatform(%q, %q) = %v, want %v", tt.platform, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestBestPlatform(t *testing.T) {
+	platforms := []string{"ruby", "x86_64-linux", "arm64-darwin"}
+	tests := []struct {
+		platforms []string
+		target    string
+		want      string
+	}{
+		{platforms, "x86_64-linux", "x86_64-linux"},
+		{platforms, "arm64-darwin", "arm64-darwin"},
+		{platforms, "x64-mingw-ucrt", "ruby"},
+		{platforms, "", "ruby"},
+		{[]string{"x86_64-linux"}, "", ""},
+		{[]string{"x86_64-linux"}, "arm64-darwin", ""},
+	}
+	for _, tt := range tests {
+		if got := BestPlatform(tt.platforms, tt.target); got != tt.want {
+			t.Errorf("BestPlatform(%v, %q) = %q, want %q", tt.platforms, tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package rubygems
+
+import (
+	"context"
+
+	"github.com/stacktower-io/stacktower/pkg/integrations"
+)
+
+// Registry adapts a [Client] to [integrations.RegistryClient].
+type Registry struct {
+	client *Client
+}
+
+// NewRegistry returns c as an [integrations.RegistryClient].
+func NewRegistry(c *Client) *Registry {
+	return &Registry{client: c}
+}
+
+var (
+	_ integrations.RegistryClient          = (*Registry)(nil)
+	_ integrations.RuntimeConstraintLister = (*Registry)(nil)
+)
+
+// Name returns "rubygems".
+func (r *Registry) Name() string { return "rubygems" }
+
+// FetchPackage implements [integrations.RegistryClient]. The runtime
+// constraint is the required Ruby version.
+func (r *Registry) FetchPackage(ctx context.Context, name, version string, opts integrations.FetchOptions) (*integrations.PackageInfo, error) {
+	var (
+		g   *GemInfo
+		err error
+	)
+	if version == "" {
+		g, err = r.client.FetchGem(ctx, name, opts.Refresh)
+	} else {
+		g, err = r.client.FetchGemVersion(ctx, name, version, opts.Refresh)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info := &integrations.PackageInfo{
+		Name:              g.Name,
+		Version:           g.Version,
+		Description:       g.Description,
+		License:           g.License,
+		Author:            g.Authors,
+		Repository:        g.SourceCodeURI,
+		HomePage:          g.HomepageURI,
+		Downloads:         g.Downloads,
+		RuntimeConstraint: g.RequiredRubyVersion,
+	}
+	for _, d := range g.Dependencies {
+		info.Dependencies = append(info.Dependencies, integrations.Dependency{Name: d.Name, Constraint: d.Constraint})
+	}
+	return info, nil
+}
+
+// ListVersions implements [integrations.RegistryClient].
+func (r *Registry) ListVersions(ctx context.Context, name string, opts integrations.FetchOptions) ([]string, error) {
+	return r.client.ListVersions(ctx, name, opts.Refresh)
+}
+
+// ListVersionsWithConstraints implements [integrations.RuntimeConstraintLister].
+func (r *Registry) ListVersionsWithConstraints(ctx context.Context, name string, opts integrations.FetchOptions) (map[string]string, error) {
+	return r.client.ListVersionsWithConstraints(ctx, name, opts.Refresh)
+}
@@ -0,0 +1,27 @@
+// Package monitor detects material changes in a project's dependencies and
+// reports them to webhooks.
+//
+// A [Snapshot] measures a resolved graph: its brittle packages and the edge
+// crossings of its tower. [Detect] compares two snapshots of the same
+// project; the resulting [Changes] are material when a vulnerability
+// appeared, a package became brittle, or the crossing count moved:
+//
+//	before, _ := monitor.Take(oldGraph, checkedAt)
+//	after, _ := monitor.Take(newGraph, time.Now())
+//	if c := monitor.Detect(before, after); c.Material(monitor.Options{}) {
+//	    _ = hook.Send(ctx, nil, monitor.Event{Type: monitor.EventGraphChanged, Project: "api", Changes: c})
+//	}
+//
+// Plain version bumps are reported in Changes for context but are not
+// material on their own.
+//
+// # Webhooks
+//
+// Generic webhooks receive the [Event] as JSON. With a secret, the body is
+// signed with HMAC-SHA256 in the X-Stacktower-Signature header
+// ("sha256=<hex>"), so receivers can verify the sender. Slack webhooks
+// receive a message summarizing the changes.
+//
+// The server package uses this to re-resolve registered projects on a
+// schedule; see "stacktower serve".
+package monitor
@@ -0,0 +1,141 @@
+package monitor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/dag/transform"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/ordering"
+)
+
+// =============================================================================
+// Snapshots
+// =============================================================================
+
+// Snapshot is a resolved dependency graph together with the measures
+// changes are detected on.
+type Snapshot struct {
+	Graph     *dag.DAG
+	TakenAt   time.Time
+	Crossings int      // Edge crossings of the tower, ordered by the barycentric heuristic
+	Brittle   []string // Brittle packages, sorted
+}
+
+// Take measures g. The crossing count uses a heuristic ordering rather than
+// the optimal search, so it is cheap and stable across runs of the same
+// graph; it tracks how tangled the tower is, not its exact crossings.
+func Take(g *dag.DAG, now time.Time) (Snapshot, error) {
+	s := Snapshot{Graph: g, TakenAt: now}
+
+	work := g.Clone()
+	// Separators resolve crossings by adding blocks; count what they hide.
+	if _, err := transform.NormalizeWithOptions(work, transform.NormalizeOptions{SkipSeparators: true}); err != nil {
+		return Snapshot{}, fmt.Errorf("normalize graph: %w", err)
+	}
+	s.Crossings = dag.CountCrossings(work, ordering.Barycentric{}.OrderRows(work))
+
+	for _, n := range g.Nodes() {
+		if !n.IsSynthetic() && feature.IsBrittle(n) {
+			s.Brittle = append(s.Brittle, n.ID)
+		}
+	}
+	slices.Sort(s.Brittle)
+	return s, nil
+}
+
+// =============================================================================
+// Change Detection
+// =============================================================================
+
+// Changes describes how a project's dependencies changed between two
+// snapshots.
+type Changes struct {
+	NewVulns        []Vuln   `json:"new_vulns,omitempty"`   // Packages that gained or changed a vulnerability
+	NewBrittle      []string `json:"new_brittle,omitempty"` // Packages that became brittle
+	CrossingsBefore int      `json:"crossings_before"`
+	CrossingsAfter  int      `json:"crossings_after"`
+
+	// Structural changes, reported for context.
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Updated []string `json:"updated,omitempty"` // "name old → new"
+}
+
+// Vuln is a package with a new or changed vulnerability severity.
+type Vuln struct {
+	Package     string `json:"package"`
+	Version     string `json:"version,omitempty"`
+	Severity    string `json:"severity"`
+	WasSeverity string `json:"was_severity,omitempty"` // Empty when the package was not vulnerable before
+}
+
+// Options sets what counts as a material change.
+type Options struct {
+	// CrossingThreshold is the smallest change in crossing count that is
+	// material (0 = any change).
+	CrossingThreshold int
+}
+
+// Detect compares two snapshots of the same project.
+func Detect(before, after Snapshot) Changes {
+	d := dag.Diff(before.Graph, after.Graph)
+	c := Changes{
+		CrossingsBefore: before.Crossings,
+		CrossingsAfter:  after.Crossings,
+	}
+	for _, v := range d.NewVulns {
+		c.NewVulns = append(c.NewVulns, Vuln{Package: v.ID, Version: v.Version, Severity: v.Severity, WasSeverity: v.WasSeverity})
+	}
+	for _, id := range after.Brittle {
+		if _, ok := slices.BinarySearch(before.Brittle, id); !ok {
+			c.NewBrittle = append(c.NewBrittle, id)
+		}
+	}
+	for _, e := range d.Added {
+		c.Added = append(c.Added, e.ID)
+	}
+	for _, e := range d.Removed {
+		c.Removed = append(c.Removed, e.ID)
+	}
+	for _, u := range d.Updated {
+		c.Updated = append(c.Updated, fmt.Sprintf("%s %s → %s", u.ID, u.OldVersion, u.NewVersion))
+	}
+	return c
+}
+
+// Material reports whether the changes warrant a notification: a new
+// vulnerability, a newly brittle package, or a crossing count that moved
+// by at least the threshold. Version bumps alone are not material.
+func (c Changes) Material(opts Options) bool {
+	delta := c.CrossingsAfter - c.CrossingsBefore
+	if delta < 0 {
+		delta = -delta
+	}
+	return len(c.NewVulns) > 0 || len(c.NewBrittle) > 0 || (delta > 0 && delta >= opts.CrossingThreshold)
+}
+
+// Summary describes the changes in one line per kind, for messages.
+func (c Changes) Summary() []string {
+	var lines []string
+	if len(c.NewVulns) > 0 {
+		vulns := make([]string, len(c.NewVulns))
+		for i, v := range c.NewVulns {
+			vulns[i] = fmt.Sprintf("%s (%s)", v.Package, v.Severity)
+		}
+		lines = append(lines, "New vulnerabilities: "+strings.Join(vulns, ", "))
+	}
+	if len(c.NewBrittle) > 0 {
+		lines = append(lines, "Newly brittle: "+strings.Join(c.NewBrittle, ", "))
+	}
+	if c.CrossingsAfter != c.CrossingsBefore {
+		lines = append(lines, fmt.Sprintf("Crossings: %d → %d", c.CrossingsBefore, c.CrossingsAfter))
+	}
+	if n := len(c.Added) + len(c.Removed) + len(c.Updated); n > 0 {
+		lines = append(lines, fmt.Sprintf("Packages: %d added, %d removed, %d updated", len(c.Added), len(c.Removed), len(c.Updated)))
+	}
+	return lines
+}
@@ -0,0 +1,95 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+// crossed builds app → {a, b} → {x, y} with both second-row nodes
+// depending on both third-row nodes, which cannot be drawn without a
+// crossing, plus optional extra node metadata.
+func crossed(meta map[string]dag.Metadata) *dag.DAG {
+	g := dag.New(nil)
+	for _, id := range []string{"app", "a", "b", "x", "y"} {
+		_ = g.AddNode(dag.Node{ID: id, Meta: meta[id]})
+	}
+	for _, e := range [][2]string{{"app", "a"}, {"app", "b"}, {"a", "x"}, {"a", "y"}, {"b", "x"}, {"b", "y"}} {
+		_ = g.AddEdge(dag.Edge{From: e[0], To: e[1]})
+	}
+	return g
+}
+
+func take(t *testing.T, g *dag.DAG) Snapshot {
+	t.Helper()
+	s, err := Take(g, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestTake(t *testing.T) {
+	s := take(t, crossed(map[string]dag.Metadata{"x": {"repo_archived": true}}))
+	if s.Crossings != 1 {
+		t.Errorf("Crossings = %d, want 1", s.Crossings)
+	}
+	if len(s.Brittle) != 1 || s.Brittle[0] != "x" {
+		t.Errorf("Brittle = %v, want [x]", s.Brittle)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	before := take(t, crossed(map[string]dag.Metadata{"a": {"version": "1.0"}, "y": {"version": "2.0"}}))
+	after := take(t, crossed(map[string]dag.Metadata{
+		"a": {"version": "1.1"},
+		"x": {"repo_archived": true},
+		"y": {"vuln_severity": "high", "version": "2.0"},
+	}))
+
+	c := Detect(before, after)
+	if len(c.NewVulns) != 1 || c.NewVulns[0] != (Vuln{Package: "y", Version: "2.0", Severity: "high"}) {
+		t.Errorf("NewVulns = %+v", c.NewVulns)
+	}
+	if len(c.NewBrittle) != 1 || c.NewBrittle[0] != "x" {
+		t.Errorf("NewBrittle = %v, want [x]", c.NewBrittle)
+	}
+	if len(c.Updated) != 1 || c.Updated[0] != "a 1.0 → 1.1" {
+		t.Errorf("Updated = %v", c.Updated)
+	}
+	if !c.Material(Options{}) {
+		t.Error("changes not material")
+	}
+
+	summary := strings.Join(c.Summary(), "\n")
+	for _, want := range []string{"y (high)", "Newly brittle: x", "1 updated"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary %q missing %q", summary, want)
+		}
+	}
+}
+
+func TestChanges_Material(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes Changes
+		opts    Options
+		want    bool
+	}{
+		{"nothing", Changes{CrossingsBefore: 3, CrossingsAfter: 3}, Options{}, false},
+		{"updates only", Changes{Updated: []string{"a 1 → 2"}, Added: []string{"b"}}, Options{}, false},
+		{"crossings", Changes{CrossingsBefore: 3, CrossingsAfter: 1}, Options{}, true},
+		{"crossings below threshold", Changes{CrossingsBefore: 3, CrossingsAfter: 4}, Options{CrossingThreshold: 5}, false},
+		{"crossings at threshold", Changes{CrossingsBefore: 3, CrossingsAfter: 8}, Options{CrossingThreshold: 5}, true},
+		{"brittle", Changes{NewBrittle: []string{"x"}}, Options{CrossingThreshold: 5}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.changes.Material(tt.opts); got != tt.want {
+				t.Errorf("Material() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
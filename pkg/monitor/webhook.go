@@ -0,0 +1,183 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// =============================================================================
+// Webhooks
+// =============================================================================
+
+// Webhook formats.
+const (
+	FormatGeneric = "generic" // JSON-encoded Event
+	FormatSlack   = "slack"   // Slack incoming webhook message
+)
+
+// EventGraphChanged is the type of events sent on material changes.
+const EventGraphChanged = "graph.changed"
+
+// SignatureHeader carries the HMAC-SHA256 of generic payloads, as
+// "sha256=<hex>", when the webhook has a secret.
+const SignatureHeader = "X-Stacktower-Signature"
+
+// sendTimeout bounds a delivery when no client is given.
+const sendTimeout = 10 * time.Second
+
+// Webhook is a notification target.
+type Webhook struct {
+	URL    string `json:"url"`
+	Format string `json:"format,omitempty"` // generic (default) or slack
+	Secret string `json:"secret,omitempty"` // Key signing generic payloads
+}
+
+// ErrPrivateDestination is returned for webhooks that would reach a
+// loopback, link-local, private or otherwise non-public address.
+var ErrPrivateDestination = errors.New("webhook destination is not a public address")
+
+// nonPublic are the special-purpose IPv4 ranges that netip does not
+// classify: "this network", shared CGNAT space, benchmarking and reserved.
+var nonPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// Event is the payload of generic webhooks.
+type Event struct {
+	Type      string    `json:"type"`
+	ProjectID string    `json:"project_id"`
+	Project   string    `json:"project"`
+	CheckedAt time.Time `json:"checked_at"`
+	Changes   Changes   `json:"changes"`
+}
+
+// Validate checks that w has an HTTP(S) URL and a known format.
+func (w Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook url %q: must be an http or https URL", w.URL)
+	}
+	switch w.Format {
+	case "", FormatGeneric, FormatSlack:
+		return nil
+	}
+	return fmt.Errorf("invalid webhook format %q (must be one of: generic, slack)", w.Format)
+}
+
+// CheckDestination rejects webhooks whose URL names localhost or a
+// non-public IP address, such as 127.0.0.1 or the 169.254.169.254 cloud
+// metadata endpoint. Host names are not resolved here; [PublicClient]
+// checks the address of every connection it makes.
+func (w Webhook) CheckDestination() error {
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook url %q: %w", w.URL, err)
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s", ErrPrivateDestination, host)
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !isPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateDestination, host)
+	}
+	return nil
+}
+
+// PublicClient returns an HTTP client that only connects to public
+// addresses. The check runs on the resolved address of each connection,
+// redirects included, so a host name cannot be pointed at the internal
+// network after registration. Proxies from the environment are not used,
+// as they would connect on the client's behalf.
+func PublicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !isPublic(addr.Addr()) {
+				return fmt.Errorf("%w: %s", ErrPrivateDestination, addr.Addr())
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// isPublic reports whether ip is a globally routable unicast address.
+func isPublic(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, p := range nonPublic {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// Send delivers e to the webhook. A nil client uses [PublicClient] with a
+// short timeout. Non-2xx responses are errors.
+func (w Webhook) Send(ctx context.Context, client *http.Client, e Event) error {
+	body, err := w.payload(e)
+	if err != nil {
+		return err
+	}
+	if client == nil {
+		client = PublicClient(sendTimeout)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Secret != "" && w.Format != FormatSlack {
+		mac := hmac.New(sha256.New, []byte(w.Secret))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("send webhook: %s returned %s", w.URL, resp.Status)
+	}
+	return nil
+}
+
+func (w Webhook) payload(e Event) ([]byte, error) {
+	if w.Format != FormatSlack {
+		return json.Marshal(e)
+	}
+	text := fmt.Sprintf("*Dependencies of %s changed*\n%s", e.Project, strings.Join(e.Changes.Summary(), "\n"))
+	return json.Marshal(map[string]string{"text": text})
+}
@@ -0,0 +1,136 @@
+package monitor
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type received struct {
+	body      []byte
+	signature string
+}
+
+func receiver(t *testing.T, status int) (*httptest.Server, chan received) {
+	t.Helper()
+	ch := make(chan received, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ch <- received{body, r.Header.Get(SignatureHeader)}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(ts.Close)
+	return ts, ch
+}
+
+var testEvent = Event{
+	Type:    EventGraphChanged,
+	Project: "api",
+	Changes: Changes{NewBrittle: []string{"left-pad"}, CrossingsBefore: 2, CrossingsAfter: 2},
+}
+
+func TestWebhook_Generic(t *testing.T) {
+	ts, ch := receiver(t, http.StatusNoContent)
+	hook := Webhook{URL: ts.URL, Secret: "s3cret"}
+	if err := hook.Send(context.Background(), ts.Client(), testEvent); err != nil {
+		t.Fatal(err)
+	}
+	got := <-ch
+
+	var e Event
+	if err := json.Unmarshal(got.body, &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != EventGraphChanged || e.Changes.NewBrittle[0] != "left-pad" {
+		t.Errorf("event = %+v", e)
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(got.body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); got.signature != want {
+		t.Errorf("signature = %q, want %q", got.signature, want)
+	}
+}
+
+func TestWebhook_Slack(t *testing.T) {
+	ts, ch := receiver(t, http.StatusOK)
+	hook := Webhook{URL: ts.URL, Format: FormatSlack, Secret: "unused"}
+	if err := hook.Send(context.Background(), ts.Client(), testEvent); err != nil {
+		t.Fatal(err)
+	}
+	got := <-ch
+
+	var msg map[string]string
+	if err := json.Unmarshal(got.body, &msg); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(msg["text"], "api") || !strings.Contains(msg["text"], "Newly brittle: left-pad") {
+		t.Errorf("text = %q", msg["text"])
+	}
+	if got.signature != "" {
+		t.Error("slack payload signed")
+	}
+}
+
+func TestWebhook_Send_ErrorStatus(t *testing.T) {
+	ts, _ := receiver(t, http.StatusInternalServerError)
+	err := Webhook{URL: ts.URL}.Send(context.Background(), ts.Client(), testEvent)
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("err = %v, want 500 error", err)
+	}
+}
+
+func TestWebhook_CheckDestination(t *testing.T) {
+	for _, url := range []string{
+		"http://localhost:8080/hook",
+		"http://api.localhost/hook",
+		"http://127.0.0.1/hook",
+		"http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.1/hook",
+		"http://100.64.0.1/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		if err := (Webhook{URL: url}).CheckDestination(); !errors.Is(err, ErrPrivateDestination) {
+			t.Errorf("%s: err = %v, want ErrPrivateDestination", url, err)
+		}
+	}
+	for _, url := range []string{"https://hooks.slack.com/services/T/B/X", "http://93.184.216.34/hook"} {
+		if err := (Webhook{URL: url}).CheckDestination(); err != nil {
+			t.Errorf("%s: %v", url, err)
+		}
+	}
+}
+
+func TestWebhook_Send_RefusesPrivateAddress(t *testing.T) {
+	ts, got := receiver(t, http.StatusOK)
+	err := Webhook{URL: ts.URL}.Send(context.Background(), nil, testEvent)
+	if !errors.Is(err, ErrPrivateDestination) {
+		t.Fatalf("err = %v, want ErrPrivateDestination", err)
+	}
+	select {
+	case <-got:
+		t.Error("webhook delivered to a loopback address")
+	default:
+	}
+}
+
+func TestWebhook_Validate(t *testing.T) {
+	for _, hook := range []Webhook{{URL: "ftp://x"}, {URL: "/relative"}, {URL: "https://x", Format: "teams"}} {
+		if hook.Validate() == nil {
+			t.Errorf("%+v: expected error", hook)
+		}
+	}
+	if err := (Webhook{URL: "https://hooks.slack.com/services/T/B/X", Format: FormatSlack}).Validate(); err != nil {
+		t.Error(err)
+	}
+}
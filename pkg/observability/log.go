@@ -0,0 +1,47 @@
+package observability
+
+import "log/slog"
+
+// =============================================================================
+// Logging
+// =============================================================================
+
+// Logger is the structured logger used by the resolvers, the orderers, the
+// layout and the sinks. Each method takes a message followed by
+// alternating keys and values:
+//
+//	logger.Warn("enrich failed", "package", name, "err", err)
+//
+// [*slog.Logger] satisfies it, so embedding applications can route
+// Stacktower's logs into their own handler. Loggers with a different
+// signature, such as charmbracelet/log, can be wrapped with [slog.New] when
+// they implement [slog.Handler].
+//
+// Implementations must be safe for concurrent use: resolvers log from
+// their worker goroutines.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// NoopLogger discards all log records.
+type NoopLogger struct{}
+
+func (NoopLogger) Debug(string, ...any) {}
+func (NoopLogger) Info(string, ...any)  {}
+func (NoopLogger) Warn(string, ...any)  {}
+func (NoopLogger) Error(string, ...any) {}
+
+// LoggerOrNoop returns l, or a [NoopLogger] when l is nil, so callers can
+// log without nil checks.
+func LoggerOrNoop(l Logger) Logger {
+	if l == nil {
+		return NoopLogger{}
+	}
+	return l
+}
+
+// Compile-time check that slog's logger fits the interface.
+var _ Logger = (*slog.Logger)(nil)
@@ -0,0 +1,21 @@
+package observability
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLoggerOrNoop(t *testing.T) {
+	if _, ok := LoggerOrNoop(nil).(NoopLogger); !ok {
+		t.Error("LoggerOrNoop(nil) should return a NoopLogger")
+	}
+
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, nil))
+	LoggerOrNoop(l).Warn("fetch failed", "package", "requests")
+	if !strings.Contains(buf.String(), `msg="fetch failed" package=requests`) {
+		t.Errorf("LoggerOrNoop(l) did not log through l: %q", buf.String())
+	}
+}
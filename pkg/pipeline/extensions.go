@@ -0,0 +1,73 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+)
+
+// Sink renders a graph into an output format the pipeline does not know,
+// such as one added by a plugin.
+type Sink interface {
+	// Format is the name of the output format, used with --format and as
+	// the file extension.
+	Format() string
+
+	// Render produces the artifact from the graph as laid out and its SVG
+	// rendering with the same options. It should stop when ctx is done.
+	Render(ctx context.Context, g *dag.DAG, svg []byte, opts Options) ([]byte, error)
+}
+
+var (
+	extMu     sync.RWMutex
+	sinks     = map[string]Sink{}
+	providers []deps.MetadataProvider
+)
+
+// RegisterSink adds an output format. It fails when the format is already
+// taken, by a built-in format or an earlier sink.
+func RegisterSink(s Sink) error {
+	extMu.Lock()
+	defer extMu.Unlock()
+	format := s.Format()
+	if ValidFormats[format] {
+		return fmt.Errorf("format %q is already registered", format)
+	}
+	sinks[format] = s
+	ValidFormats[format] = true
+	return nil
+}
+
+// RegisterMetadataProvider adds a metadata provider that enriches every
+// parse, next to GitHub. Like GitHub, it is skipped when enrichment is off.
+func RegisterMetadataProvider(p deps.MetadataProvider) {
+	extMu.Lock()
+	defer extMu.Unlock()
+	providers = append(providers, p)
+}
+
+// registeredProviders returns the providers added by RegisterMetadataProvider.
+func registeredProviders() []deps.MetadataProvider {
+	extMu.RLock()
+	defer extMu.RUnlock()
+	return slices.Clone(providers)
+}
+
+// splitSinkFormats separates the formats of registered sinks from the
+// built-in ones.
+func splitSinkFormats(formats []string) (builtin []string, external []Sink) {
+	extMu.RLock()
+	defer extMu.RUnlock()
+	for _, f := range formats {
+		if s, ok := sinks[f]; ok {
+			external = append(external, s)
+		} else {
+			builtin = append(builtin, f)
+		}
+	}
+	return builtin, external
+}
@@ -0,0 +1,55 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+// upperSink renders the SVG in upper case.
+type upperSink struct{ format string }
+
+func (s upperSink) Format() string { return s.format }
+
+func (s upperSink) Render(_ context.Context, _ *dag.DAG, svg []byte, _ Options) ([]byte, error) {
+	return bytes.ToUpper(svg), nil
+}
+
+func TestRegisterSink(t *testing.T) {
+	if err := RegisterSink(upperSink{format: FormatSVG}); err == nil {
+		t.Error("RegisterSink(svg) accepted a built-in format")
+	}
+	if err := RegisterSink(upperSink{format: "test-upper"}); err != nil {
+		t.Fatalf("RegisterSink() error: %v", err)
+	}
+	if err := RegisterSink(upperSink{format: "test-upper"}); err == nil {
+		t.Error("RegisterSink() accepted a duplicate format")
+	}
+	if err := ValidateFormats([]string{"test-upper"}); err != nil {
+		t.Errorf("ValidateFormats() rejected a registered sink: %v", err)
+	}
+
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "lib"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "lib"})
+
+	opts := Options{VizType: graph.VizTypeSunburst, Width: 400, Height: 400, Formats: []string{"test-upper"}}
+	l, err := GenerateLayout(context.Background(), g, opts)
+	if err != nil {
+		t.Fatalf("GenerateLayout() error: %v", err)
+	}
+	out, err := RenderFromLayout(context.Background(), l, g, opts)
+	if err != nil {
+		t.Fatalf("RenderFromLayout() error: %v", err)
+	}
+	if _, ok := out[FormatSVG]; ok {
+		t.Error("SVG produced for the sink was returned although not requested")
+	}
+	if !bytes.Contains(out["test-upper"], []byte("<SVG")) {
+		t.Errorf("sink output = %.80q, want the upper-cased SVG", out["test-upper"])
+	}
+}
@@ -1,11 +1,15 @@
 package pipeline
 
 import (
+	"context"
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/dsm"
 	"github.com/stacktower-io/stacktower/pkg/core/render/nodelink"
+	"github.com/stacktower-io/stacktower/pkg/core/render/sunburst"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/transform"
+	"github.com/stacktower-io/stacktower/pkg/core/render/treemap"
 	"github.com/stacktower-io/stacktower/pkg/graph"
 )
 
@@ -20,11 +24,23 @@ import (
 //   - Graph structure (nodes, edges, rows)
 //   - Nebraska rankings (maintainer data)
 //   - Visualization-specific data (blocks for tower, DOT for nodelink)
-func GenerateLayout(g *dag.DAG, opts Options) (graph.Layout, error) {
+//
+// Cancelling ctx stops the row ordering of towers, which then keeps the
+// best ordering found so far, and returns ctx's error.
+func GenerateLayout(ctx context.Context, g *dag.DAG, opts Options) (graph.Layout, error) {
 	if opts.IsNodelink() {
 		return generateNodelinkLayout(g, opts)
 	}
-	return generateTowerLayout(g, opts)
+	if opts.IsSunburst() {
+		return generateSunburstLayout(g, opts), nil
+	}
+	if opts.IsTreemap() {
+		return generateTreemapLayout(g, opts), nil
+	}
+	if opts.IsDSM() {
+		return generateDSMLayout(g, opts), nil
+	}
+	return generateTowerLayout(ctx, g, opts)
 }
 
 // =============================================================================
@@ -36,7 +52,7 @@ func GenerateLayout(g *dag.DAG, opts Options) (graph.Layout, error) {
 //
 // Note: Nebraska rankings are ALWAYS computed and stored, regardless of opts.Nebraska.
 // The opts.Nebraska flag only controls whether the ranking panel is rendered in the SVG.
-func generateTowerLayout(g *dag.DAG, opts Options) (graph.Layout, error) {
+func generateTowerLayout(ctx context.Context, g *dag.DAG, opts Options) (graph.Layout, error) {
 	// Ensure graph has row assignments
 	workGraph := g
 	if g.MaxRow() == 0 && g.EdgeCount() > 0 {
@@ -45,13 +61,17 @@ func generateTowerLayout(g *dag.DAG, opts Options) (graph.Layout, error) {
 	}
 
 	// Build layout options
-	var layoutOpts []layout.Option
-	if opts.Orderer != nil {
-		layoutOpts = append(layoutOpts, layout.WithOrderer(opts.Orderer))
+	layoutOpts := []layout.Option{
+		layout.WithContext(ctx),
+		layout.WithLogger(opts.Logger),
+		layout.WithOrderer(opts.orderer()),
 	}
 
 	// Compute base layout
 	l := layout.Build(workGraph, opts.Width, opts.Height, layoutOpts...)
+	if err := ctx.Err(); err != nil {
+		return graph.Layout{}, err
+	}
 
 	// Apply transforms
 	if opts.Merge {
@@ -68,7 +88,7 @@ func generateTowerLayout(g *dag.DAG, opts Options) (graph.Layout, error) {
 	l.Merged = opts.Merge
 
 	// Compute Nebraska rankings
-	l.Nebraska = feature.RankNebraska(workGraph, 10)
+	l.Nebraska = feature.RankNebraskaWith(workGraph, 10, opts.nebraskaScoring())
 
 	// Export to serialization format
 	return l.Export(workGraph)
@@ -85,16 +105,16 @@ func generateTowerLayout(g *dag.DAG, opts Options) (graph.Layout, error) {
 // The opts.Nebraska flag only controls whether the ranking panel is rendered in the SVG.
 func generateNodelinkLayout(g *dag.DAG, opts Options) (graph.Layout, error) {
 	// Generate DOT representation
-	dot := nodelink.ToDOT(g, nodelink.Options{Detailed: false})
+	dot := nodelink.ToDOT(g, opts.nodelinkOptions())
 
 	// Build base layout
-	result, err := nodelink.Export(dot, g, nodelink.Options{Detailed: false}, opts.Width, opts.Height, opts.Style)
+	result, err := nodelink.Export(dot, g, opts.nodelinkOptions(), opts.Width, opts.Height, opts.Style)
 	if err != nil {
 		return result, err
 	}
 
 	// Add Nebraska rankings
-	result.Nebraska = exportNebraska(feature.RankNebraska(g, 10))
+	result.Nebraska = exportNebraska(feature.RankNebraskaWith(g, 10, opts.nebraskaScoring()))
 
 	return result, nil
 }
@@ -118,8 +138,47 @@ func exportNebraska(rankings []feature.NebraskaRanking) []graph.NebraskaRanking
 		result[i] = graph.NebraskaRanking{
 			Maintainer: r.Maintainer,
 			Score:      r.Score,
+			Share:      r.Share,
+			Org:        r.Org,
 			Packages:   pkgs,
 		}
 	}
 	return result
 }
+
+// =============================================================================
+// Sunburst
+// =============================================================================
+
+// generateSunburstLayout generates a sunburst layout: the graph structure
+// and Nebraska rankings, from which the chart is computed at render time.
+func generateSunburstLayout(g *dag.DAG, opts Options) graph.Layout {
+	result := sunburst.Export(g, opts.Width, opts.Height, opts.Style)
+	result.Nebraska = exportNebraska(feature.RankNebraskaWith(g, 10, opts.nebraskaScoring()))
+	return result
+}
+
+// =============================================================================
+// Treemap
+// =============================================================================
+
+// generateTreemapLayout generates a treemap layout: the graph structure and
+// Nebraska rankings, from which the map is computed at render time.
+func generateTreemapLayout(g *dag.DAG, opts Options) graph.Layout {
+	result := treemap.Export(g, opts.Width, opts.Height, opts.Style)
+	result.Nebraska = exportNebraska(feature.RankNebraskaWith(g, 10, opts.nebraskaScoring()))
+	return result
+}
+
+// =============================================================================
+// DSM
+// =============================================================================
+
+// generateDSMLayout generates a design structure matrix layout: the graph
+// structure and Nebraska rankings, from which the matrix is computed at
+// render time.
+func generateDSMLayout(g *dag.DAG, opts Options) graph.Layout {
+	result := dsm.Export(g, opts.Width, opts.Height, opts.Style)
+	result.Nebraska = exportNebraska(feature.RankNebraskaWith(g, 10, opts.nebraskaScoring()))
+	return result
+}
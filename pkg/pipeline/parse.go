@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/stacktower-io/stacktower/pkg/buildinfo"
 	"github.com/stacktower-io/stacktower/pkg/cache"
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
@@ -14,6 +16,23 @@ import (
 	"github.com/stacktower-io/stacktower/pkg/core/deps/metadata"
 )
 
+// GraphEnrichmentKey is the graph metadata key set to EnrichmentPartial
+// when metadata providers skipped packages, for instance to stay within
+// the GitHub rate limits.
+const (
+	GraphEnrichmentKey = "enrichment"
+	EnrichmentPartial  = "partial"
+)
+
+// GraphStaleKey is the graph metadata key set to true when some packages
+// were resolved from expired cache entries because their registry could not
+// be reached.
+const GraphStaleKey = "stale"
+
+// GraphTruncatedKey is the graph metadata key set to true when
+// Options.MaxDuration ran out before every package was expanded.
+const GraphTruncatedKey = "truncated"
+
 // ParseResult contains the parsed dependency graph and metadata.
 type ParseResult struct {
 	Graph          *dag.DAG
@@ -28,11 +47,16 @@ func Parse(ctx context.Context, c cache.Cache, opts Options) (*ParseResult, erro
 		return nil, fmt.Errorf("unsupported language: %s", opts.Language)
 	}
 
+	if opts.Manifest == "" {
+		opts.Package, opts.Version = splitPackageSpec(opts.Package, opts.Version)
+	}
 	resolveOpts := buildResolveOptions(ctx, c, opts)
 
 	var g *dag.DAG
 	var runtimeVersion string
 	var runtimeSource string
+	var manifestType string
+	source := deps.SourceRegistry
 	var err error
 
 	if opts.Manifest != "" {
@@ -42,6 +66,10 @@ func Parse(ctx context.Context, c cache.Cache, opts Options) (*ParseResult, erro
 			return nil, err
 		}
 		g = manifestResult.Graph
+		manifestType = manifestResult.Type
+		if manifestResult.IncludesTransitive {
+			source = deps.SourceLockfile
+		}
 
 		// Determine runtime version: CLI > manifest > default
 		if opts.RuntimeVersion != "" {
@@ -106,14 +134,70 @@ func Parse(ctx context.Context, c cache.Cache, opts Options) (*ParseResult, erro
 	// the raw graph to be stored and reused for different viz types
 	// (tower needs normalized graph, nodelink needs raw graph).
 
+	deps.MarkSource(g, source)
 	filteredGraph := deps.FilterPrereleaseNodes(g, opts.IncludePrerelease)
 
+	// Icons use the repo owner found by GitHub enrichment, so they need it on.
+	if opts.Icons && opts.ShouldEnrich() {
+		icons := metadata.NewIcons(c, deps.DefaultCacheTTL)
+		icons.EnrichGraph(ctx, filteredGraph, opts.Workers, opts.Refresh)
+	}
+
+	// Summaries give popups a description for every package: the one
+	// enrichment found, else the first paragraph of the repository's README.
+	if opts.ShouldEnrich() {
+		summaries := metadata.NewSummaries(c, githubToken(opts), deps.DefaultCacheTTL)
+		summaries.EnrichGraph(ctx, filteredGraph, opts.Workers, opts.Refresh)
+	}
+
 	// Store parse options in graph metadata so they persist through caching
 	filteredGraph.Meta()["runtime_version"] = runtimeVersion
 	filteredGraph.Meta()["runtime_source"] = runtimeSource
 	filteredGraph.Meta()["language"] = opts.Language
 	filteredGraph.Meta()["dependency_scope"] = opts.DependencyScope
 	filteredGraph.Meta()["include_prerelease"] = opts.IncludePrerelease
+	filteredGraph.Meta()["resolved_at"] = time.Now().UTC().Format(time.RFC3339)
+
+	// Record provenance so exported graphs say how they were produced
+	filteredGraph.Meta()["registry"] = lang.DefaultRegistry
+	filteredGraph.Meta()["stacktower_version"] = buildinfo.Version
+	limits := resolveOpts.WithDefaults()
+	filteredGraph.Meta()["max_depth"] = limits.MaxDepth
+	filteredGraph.Meta()["max_nodes"] = limits.MaxNodes
+	if manifestType != "" {
+		filteredGraph.Meta()["manifest_type"] = manifestType
+	}
+
+	// Providers short of API quota enrich the most depended-upon packages
+	// and skip the rest; the graph says so, and is not cached.
+	if skipped := deps.SkippedPackages(resolveOpts.MetadataProviders); len(skipped) > 0 {
+		filteredGraph.Meta()[GraphEnrichmentKey] = EnrichmentPartial
+		limits.Logger.Warn("enrichment is partial: GitHub rate limit nearly exhausted",
+			"skipped", len(skipped),
+			"hint", "retry once the rate limit resets, or set a GitHub token for a higher limit")
+		limits.Logger.Debug("packages skipped by enrichment", "packages", skipped)
+	}
+
+	// Packages whose registry failed are resolved from the last cached copy
+	// rather than dropped; the graph says so, and is not cached.
+	if stale := deps.StalePackages(filteredGraph); len(stale) > 0 {
+		filteredGraph.Meta()[GraphStaleKey] = true
+		limits.Logger.Warn("registry unreachable: using expired cached data",
+			"packages", len(stale),
+			"hint", "retry once the registry is back for up-to-date versions")
+		limits.Logger.Debug("packages resolved from stale data", "packages", stale)
+	}
+
+	// A crawl cut short by MaxDuration still returns what it found; the
+	// graph says so, and is not cached.
+	if truncated := deps.TruncatedPackages(filteredGraph); len(truncated) > 0 {
+		filteredGraph.Meta()[GraphTruncatedKey] = true
+		limits.Logger.Warn("resolution stopped at the time limit: graph is incomplete",
+			"max_duration", opts.MaxDuration,
+			"truncated", len(truncated),
+			"hint", "raise --max-duration for the full graph")
+		limits.Logger.Debug("packages whose dependencies were not followed", "packages", truncated)
+	}
 
 	return &ParseResult{
 		Graph:          filteredGraph,
@@ -122,6 +206,16 @@ func Parse(ctx context.Context, c cache.Cache, opts Options) (*ParseResult, erro
 	}, nil
 }
 
+// splitPackageSpec splits a "name@version" package into its name and
+// version, which takes precedence over version, so that the name can be
+// normalized and the version cached and validated on its own.
+func splitPackageSpec(pkg, version string) (string, string) {
+	if name, v := deps.ParsePackageSpec(pkg); v != "" {
+		return name, v
+	}
+	return pkg, version
+}
+
 // buildResolveOptions creates deps.Options from pipeline options.
 func buildResolveOptions(ctx context.Context, c cache.Cache, opts Options) deps.Options {
 	resolveOpts := deps.Options{
@@ -129,47 +223,63 @@ func buildResolveOptions(ctx context.Context, c cache.Cache, opts Options) deps.
 		Version:           opts.Version,
 		MaxDepth:          opts.MaxDepth,
 		MaxNodes:          opts.MaxNodes,
+		MaxDuration:       opts.MaxDuration,
 		Workers:           opts.Workers,
 		Refresh:           opts.Refresh,
 		CacheTTL:          deps.DefaultCacheTTL,
 		DependencyScope:   opts.DependencyScope,
 		IncludePrerelease: opts.IncludePrerelease,
 		RuntimeVersion:    opts.RuntimeVersion,
+		Platform:          opts.Platform,
+		IncludeIndirect:   opts.IncludeIndirect,
+		SparseIndex:       opts.SparseIndex,
 	}
 
-	// Set up logger callback
-	if opts.Logger != nil {
-		resolveOpts.Logger = func(format string, args ...any) {
-			opts.Logger.Warnf(format, args...)
-		}
-	}
+	resolveOpts.Logger = opts.Logger
 
 	// Set up metadata providers and URLProvider for enrichment.
 	// GitHub enrichment works both with and without a token:
 	// - With token: uses authenticated rate limits (5000 req/hr)
 	// - Without token: uses unauthenticated rate limits (60 req/hr per IP)
 	// This ensures public/anonymous requests still get GitHub metadata.
-	token := opts.GitHubToken
-	if token == "" {
-		token = os.Getenv("GITHUB_TOKEN")
-	}
+	token := githubToken(opts)
 	if opts.ShouldEnrich() {
 		var ghOpts []metadata.GitHubOption
 		if opts.FetchContributors {
 			ghOpts = append(ghOpts, metadata.WithContributors())
 		}
 		gh := metadata.NewGitHub(c, token, deps.DefaultCacheTTL, ghOpts...)
-		resolveOpts.MetadataProviders = []deps.MetadataProvider{gh}
+		resolveOpts.MetadataProviders = append([]deps.MetadataProvider{gh}, registeredProviders()...)
+		resolveOpts.EnrichFilter = deps.EnrichFilter{MaxDepth: opts.EnrichDepth, MinDependents: opts.EnrichMinDependents}
 
 		// Set up URLProvider for manifest enrichment.
 		// This enables GitHub enrichment for lock files and other manifests
 		// that don't include repository URLs directly.
 		resolveOpts.URLProvider = deps.NewURLProvider(opts.Language, c, deps.DefaultCacheTTL)
+
+		// Dependencies vendored or installed next to the manifest are read
+		// from disk, without registry or GitHub calls.
+		if opts.LocalMetadata && opts.ManifestPath != "" {
+			local := metadata.NewLocal(filepath.Dir(opts.ManifestPath))
+			resolveOpts.MetadataProviders = []deps.MetadataProvider{metadata.LocalFirst(local, resolveOpts.MetadataProviders...)}
+			if resolveOpts.URLProvider != nil {
+				resolveOpts.URLProvider = metadata.LocalURLs(local, resolveOpts.URLProvider)
+			}
+		}
 	}
 
 	return resolveOpts
 }
 
+// githubToken returns the GitHub token of opts, falling back to the
+// GITHUB_TOKEN environment variable.
+func githubToken(opts Options) string {
+	if opts.GitHubToken != "" {
+		return opts.GitHubToken
+	}
+	return os.Getenv("GITHUB_TOKEN")
+}
+
 // resolvePackage resolves dependencies from a package registry.
 func resolvePackage(ctx context.Context, c cache.Cache, lang *deps.Language, pkg string, opts deps.Options) (*dag.DAG, error) {
 	resolver, err := lang.Resolver(c, opts)
@@ -222,6 +332,13 @@ func parseManifestWithResult(ctx context.Context, c cache.Cache, lang *deps.Lang
 
 	manifestName := filepath.Base(opts.ManifestFilename)
 	parser, ok := lang.Manifest(manifestName, resolver)
+	if !ok && opts.Manifest != "" {
+		// Names no alias covers, like requirements-dev.txt, may still be
+		// recognized by their content.
+		if c := deps.DetectManifests(manifestName, []byte(opts.Manifest), []*deps.Language{lang}); len(c) > 0 {
+			parser, ok = lang.Manifest(c[0].Type, resolver)
+		}
+	}
 	if !ok {
 		return nil, fmt.Errorf("no parser for manifest: %s", opts.ManifestFilename)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/stacktower-io/stacktower/pkg/buildinfo"
 	"github.com/stacktower-io/stacktower/pkg/cache"
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
 )
@@ -45,6 +46,27 @@ func TestBuildResolveOptions_SkipEnrichDisablesProviders(t *testing.T) {
 	}
 }
 
+func TestBuildResolveOptions_LocalMetadata(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	opts := buildResolveOptions(context.Background(), cache.NewNullCache(), Options{
+		Language:      "javascript",
+		ManifestPath:  "project/package.json",
+		LocalMetadata: true,
+	})
+	if len(opts.MetadataProviders) != 1 || opts.MetadataProviders[0].Name() != "local" {
+		t.Fatalf("providers = %v, want the github provider behind the local one", opts.MetadataProviders)
+	}
+	if opts.URLProvider == nil {
+		t.Fatal("URLProvider should still be set")
+	}
+
+	// Without a manifest on disk there is nothing vendored to read
+	opts = buildResolveOptions(context.Background(), cache.NewNullCache(), Options{LocalMetadata: true})
+	if opts.MetadataProviders[0].Name() != "github" {
+		t.Errorf("provider = %q, want github", opts.MetadataProviders[0].Name())
+	}
+}
+
 func TestBuildResolveOptions_DependencyScope(t *testing.T) {
 	opts := buildResolveOptions(context.Background(), cache.NewNullCache(), Options{
 		DependencyScope: deps.DependencyScopeAll,
@@ -53,3 +75,43 @@ func TestBuildResolveOptions_DependencyScope(t *testing.T) {
 		t.Fatalf("dependency scope = %q, want %q", opts.DependencyScope, deps.DependencyScopeAll)
 	}
 }
+
+func TestParse_RecordsProvenance(t *testing.T) {
+	lock := `version = 3
+
+[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = ["serde"]
+
+[[package]]
+name = "serde"
+version = "1.0.200"
+`
+	result, err := Parse(context.Background(), cache.NewNullCache(), Options{
+		Language:         "rust",
+		Manifest:         lock,
+		ManifestFilename: "Cargo.lock",
+		SkipEnrich:       true,
+	})
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	meta := result.Graph.Meta()
+	if meta["registry"] != "crates" || meta["manifest_type"] != "Cargo.lock" {
+		t.Errorf("registry = %v, manifest_type = %v", meta["registry"], meta["manifest_type"])
+	}
+	if meta["stacktower_version"] != buildinfo.Version {
+		t.Errorf("stacktower_version = %v, want %s", meta["stacktower_version"], buildinfo.Version)
+	}
+	if meta["max_depth"] != deps.DefaultMaxDepth || meta["max_nodes"] != deps.DefaultMaxNodes {
+		t.Errorf("max_depth = %v, max_nodes = %v, want the defaults", meta["max_depth"], meta["max_nodes"])
+	}
+	if _, ok := meta["resolved_at"].(string); !ok {
+		t.Error("resolved_at should be recorded")
+	}
+	if n, ok := result.Graph.Node("serde"); !ok || n.Meta[deps.MetaSource] != deps.SourceLockfile {
+		t.Errorf("serde should come from the lock file, got %v", n)
+	}
+}
@@ -41,20 +41,37 @@
 //
 //	// Render with existing layout
 //	artifacts, err := runner.Render(ctx, layout, g, renderOpts)
+//
+// # Provenance
+//
+// Parse records how a graph was produced in its graph-level metadata, so
+// exported JSON is self-describing: the language, registry, stacktower
+// version, resolution time, runtime version and its source, dependency
+// scope, depth and node limits, manifest type and exclude patterns. Each
+// node's "source" says whether its version came from the registry, a lock
+// file or an SBOM.
 package pipeline
 
 import (
+	"cmp"
+	"encoding/json"
 	"fmt"
-	"io"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
-	"github.com/charmbracelet/log"
-
 	"github.com/stacktower-io/stacktower/pkg/cache"
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/core/render/nodelink"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/ordering"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/sink"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
+	"github.com/stacktower-io/stacktower/pkg/core/render/treemap"
 	"github.com/stacktower-io/stacktower/pkg/graph"
+	"github.com/stacktower-io/stacktower/pkg/observability"
 )
 
 // =============================================================================
@@ -83,6 +100,11 @@ const (
 
 	// DefaultOrdering is the default ordering algorithm.
 	DefaultOrdering = "optimal"
+
+	// MinTileSize is the smallest tile width or height accepted for tiled
+	// output. Smaller tiles would split a tower into an unbounded number of
+	// pages.
+	MinTileSize = 100.0
 )
 
 // DefaultVizType is the default visualization type.
@@ -97,6 +119,8 @@ const (
 	FormatPNG  = "png"
 	FormatPDF  = "pdf"
 	FormatJSON = "json"
+	FormatPPTX = "pptx"
+	FormatHTML = "html" // Interactive page; towers and nodelink only
 )
 
 // ValidFormats is the set of supported output formats.
@@ -105,18 +129,42 @@ var ValidFormats = map[string]bool{
 	FormatPNG:  true,
 	FormatPDF:  true,
 	FormatJSON: true,
+	FormatPPTX: true,
+	FormatHTML: true,
 }
 
-// ValidStyles is the set of supported visual styles.
-var ValidStyles = map[string]bool{
-	graph.StyleSimple:    true,
-	graph.StyleHanddrawn: true,
+// ValidEdgeRoutings is the set of supported tower edge routing modes.
+var ValidEdgeRoutings = map[string]bool{
+	"straight":   true,
+	"orthogonal": true,
+	"curved":     true,
+}
+
+// ValidEngines is the set of supported nodelink rendering engines.
+var ValidEngines = map[string]bool{
+	nodelink.EngineDot:     true,
+	nodelink.EngineLayered: true,
+}
+
+// ValidColorBy is the set of metadata fields towers can be coloured by.
+var ValidColorBy = map[string]bool{
+	"none":      true,
+	"license":   true,
+	"language":  true,
+	"owner":     true,
+	"vuln":      true,
+	"staleness": true,
+	"freshness": true,
+	"health":    true,
 }
 
 // ValidVizTypes is the set of supported visualization types.
 var ValidVizTypes = map[string]bool{
 	graph.VizTypeTower:    true,
 	graph.VizTypeNodelink: true,
+	graph.VizTypeSunburst: true,
+	graph.VizTypeTreemap:  true,
+	graph.VizTypeDSM:      true,
 }
 
 // =============================================================================
@@ -127,54 +175,100 @@ var ValidVizTypes = map[string]bool{
 // This struct supports JSON serialization for API requests.
 type Options struct {
 	// Parse options
-	Language          string `json:"language"`
-	Package           string `json:"package,omitempty"`
-	Version           string `json:"version,omitempty"` // Specific package version (e.g., "2.31.0")
-	Manifest          string `json:"manifest,omitempty"`
-	ManifestFilename  string `json:"manifest_filename,omitempty"`
-	ManifestPath      string `json:"manifest_path,omitempty"` // Optional on-disk path used when parser needs workspace context
-	Owner             string `json:"owner,omitempty"`         // GitHub owner (user/org)
-	Repo              string `json:"repo,omitempty"`          // GitHub repository name
-	Ref               string `json:"ref,omitempty"`           // Git ref (branch/tag)
-	Path              string `json:"path,omitempty"`          // Path within repo
-	RootName          string `json:"root_name,omitempty"`     // Custom name for root node (replaces __project__)
-	MaxDepth          int    `json:"max_depth,omitempty"`
-	MaxNodes          int    `json:"max_nodes,omitempty"`
-	Workers           int    `json:"workers,omitempty"`            // Concurrent fetch workers (0 = default 20)
-	SkipEnrich        bool   `json:"skip_enrich,omitempty"`        // Skip metadata enrichment (default: false = enrich)
-	FetchContributors bool   `json:"fetch_contributors,omitempty"` // Fetch GitHub contributors (slower, enables Nebraska rankings)
-	Refresh           bool   `json:"refresh,omitempty"`
-	DependencyScope   string `json:"dependency_scope,omitempty"`   // Dependency scope policy: prod_only (default) or all
-	IncludePrerelease bool   `json:"include_prerelease,omitempty"` // Include prerelease versions (alpha/beta/rc/dev/etc.)
-	RuntimeVersion    string `json:"runtime_version,omitempty"`    // Target runtime version for marker evaluation (e.g., "3.11" for Python)
+	Language            string   `json:"language"`
+	Package             string   `json:"package,omitempty"` // May name a version, as in "requests@2.31.0"
+	Version             string   `json:"version,omitempty"` // Specific package version (e.g., "2.31.0")
+	Manifest            string   `json:"manifest,omitempty"`
+	ManifestFilename    string   `json:"manifest_filename,omitempty"`
+	ManifestPath        string   `json:"manifest_path,omitempty"` // Optional on-disk path used when parser needs workspace context
+	Owner               string   `json:"owner,omitempty"`         // GitHub owner (user/org)
+	Repo                string   `json:"repo,omitempty"`          // GitHub repository name
+	Ref                 string   `json:"ref,omitempty"`           // Git ref (branch/tag)
+	Path                string   `json:"path,omitempty"`          // Path within repo
+	RootName            string   `json:"root_name,omitempty"`     // Custom name for root node (replaces __project__)
+	MaxDepth            int      `json:"max_depth,omitempty"`
+	MaxNodes            int      `json:"max_nodes,omitempty"`
+	Workers             int      `json:"workers,omitempty"`               // Concurrent fetch workers (0 = default 20)
+	SkipEnrich          bool     `json:"skip_enrich,omitempty"`           // Skip metadata enrichment (default: false = enrich)
+	FetchContributors   bool     `json:"fetch_contributors,omitempty"`    // Fetch GitHub contributors (slower, enables Nebraska rankings)
+	EnrichDepth         int      `json:"enrich_depth,omitempty"`          // Enrich only packages up to this depth (0 = no depth cap)
+	EnrichMinDependents int      `json:"enrich_min_dependents,omitempty"` // Also enrich packages with at least this many dependents (0 = off)
+	LocalMetadata       bool     `json:"local_metadata,omitempty"`        // Read metadata of dependencies vendored next to ManifestPath from disk
+	Refresh             bool     `json:"refresh,omitempty"`
+	DependencyScope     string   `json:"dependency_scope,omitempty"`   // Dependency scope policy: prod_only (default) or all
+	IncludePrerelease   bool     `json:"include_prerelease,omitempty"` // Include prerelease versions (alpha/beta/rc/dev/etc.)
+	RuntimeVersion      string   `json:"runtime_version,omitempty"`    // Target runtime version for marker evaluation (e.g., "3.11" for Python)
+	Platform            string   `json:"platform,omitempty"`           // Target platform for marker evaluation (linux, darwin, windows)
+	IncludeIndirect     bool     `json:"include_indirect,omitempty"`   // Follow "// indirect" requirements of go.mod files
+	SparseIndex         bool     `json:"sparse_index,omitempty"`       // Resolve crates from the sparse crates.io index
+	Icons               bool     `json:"icons,omitempty"`              // Fetch package icons during parse and draw them on blocks
+	Exclude             []string `json:"exclude,omitempty"`            // Glob patterns of packages to drop, with deps only they pull in
+	Internal            []string `json:"internal,omitempty"`           // Glob patterns of first-party packages, marked deps.MetaInternal
+
+	// MaxDuration is a wall-clock budget for resolution (0 = none). When it
+	// runs out, the graph found so far is returned, with the packages left
+	// unexpanded marked deps.MetaTruncated.
+	MaxDuration time.Duration `json:"max_duration,omitempty"`
 
 	// Layout options
 	VizType   string  `json:"viz_type,omitempty"`
 	Width     float64 `json:"width,omitempty"`
 	Height    float64 `json:"height,omitempty"`
-	Normalize bool    `json:"normalize,omitempty"` // Apply graph normalization during layout
+	Normalize bool    `json:"normalize,omitempty"`  // Apply graph normalization during layout
+	FixedRows bool    `json:"fixed_rows,omitempty"` // Keep node rows from the input graph as hard constraints when normalizing
 	Ordering  string  `json:"ordering,omitempty"`
 	Merge     bool    `json:"merge,omitempty"`
 	Randomize bool    `json:"randomize,omitempty"`
-	Seed      uint64  `json:"seed,omitempty"`
+	Seed      uint64  `json:"seed,omitempty"`       // Seeds ordering restarts, Randomize and the handdrawn style
+	Restarts  int     `json:"restarts,omitempty"`   // Extra barycentric ordering runs from seeded shuffled orders
+	ClusterBy string  `json:"cluster_by,omitempty"` // Group nodelink nodes into boxes by metadata: owner, language, or any key
+
+	EdgeLabels bool `json:"edge_labels,omitempty"` // Label nodelink edges with their version constraints
+	EdgeKinds  bool `json:"edge_kinds,omitempty"`  // Draw dev, optional and peer nodelink edges dashed or dotted
+
+	NebraskaScoring *feature.ScoringConfig `json:"nebraska_scoring,omitempty"` // Custom Nebraska weights (nil = defaults)
+	Health          *feature.HealthConfig  `json:"health,omitempty"`           // Custom health score weights (nil = defaults)
 
 	// Render options
-	Formats    []string `json:"formats,omitempty"`
-	Style      string   `json:"style,omitempty"`
-	ShowEdges  bool     `json:"show_edges,omitempty"`
-	Nebraska   bool     `json:"nebraska,omitempty"` // Show Nebraska ranking panel in SVG (data is always computed)
-	Popups     bool     `json:"popups,omitempty"`
-	FlagsOnTop bool     `json:"flags_on_top,omitempty"` // Render security flags (license/vuln) on top of all blocks
+	Formats       []string `json:"formats,omitempty"`
+	Style         string   `json:"style,omitempty"`
+	ShowEdges     bool     `json:"show_edges,omitempty"`
+	Nebraska      bool     `json:"nebraska,omitempty"` // Show Nebraska ranking panel in SVG (data is always computed)
+	Popups        bool     `json:"popups,omitempty"`
+	PopupFields   []string `json:"popup_fields,omitempty"`   // Extra metadata keys shown in popups
+	PopupTemplate string   `json:"popup_template,omitempty"` // Go text/template for the popup body (see sink.PopupTemplateData)
+	FlagsOnTop    bool     `json:"flags_on_top,omitempty"`   // Render security flags (license/vuln) on top of all blocks
+	TileWidth     float64  `json:"tile_width,omitempty"`     // Split tower output into pages of this width (0 = single page)
+	TileHeight    float64  `json:"tile_height,omitempty"`    // Split tower output into pages of this height (0 = single page)
+	Highlight     []string `json:"highlight,omitempty"`      // Package IDs to emphasize; all other blocks are dimmed
+
+	Footer     bool   `json:"footer,omitempty"`      // Stamp a provenance footer (project, resolve time, version, stats)
+	FooterNote string `json:"footer_note,omitempty"` // Extra text appended to the footer
+	Branding   string `json:"branding,omitempty"`    // Replace the stacktower.io watermark with this text
+
+	EdgeRouting  string `json:"edge_routing,omitempty"`  // Edge routing: straight (default), orthogonal, curved
+	EdgeBundling int    `json:"edge_bundling,omitempty"` // Bundle edges of blocks with at least this many dependencies (0 = off)
+	Engine       string `json:"engine,omitempty"`        // Nodelink engine: dot (default, Graphviz) or layered (pure Go)
+	Rings        int    `json:"rings,omitempty"`         // Sunburst rings drawn around the center (0 = all)
+	Weight       string `json:"weight,omitempty"`        // Treemap area: deps (default), downloads, size
+
+	ColorBy string `json:"color_by,omitempty"` // Fill blocks by metadata: license, language, owner, vuln, staleness, freshness, health
+	Palette string `json:"palette,omitempty"`  // Palette name (okabe-ito, viridis, pastel) or comma-separated hex colours
+	Theme   string `json:"theme,omitempty"`    // Theme file contents (YAML or JSON, see styles.Config); overrides Style
+
+	StaleAfterDays     int `json:"stale_after_days,omitempty"`     // Days without commits before a small package is brittle (0 = 365)
+	AbandonedAfterDays int `json:"abandoned_after_days,omitempty"` // Days without commits before any package is brittle (0 = 730)
 
 	// Security options
 	SecurityScan bool `json:"security_scan,omitempty"` // Run vulnerability scan during parse
 	ShowVulns    bool `json:"show_vulns,omitempty"`    // Include vulnerability data in rendered output
 	ShowLicenses bool `json:"show_licenses,omitempty"` // Analyze and show license compliance data in rendered output
+	Suspicious   bool `json:"suspicious,omitempty"`    // Mark likely typosquats, very young and repo-mismatched packages
 
 	// Runtime options (not serialized)
-	Logger      *log.Logger      `json:"-"`
-	GitHubToken string           `json:"-"`
-	Orderer     ordering.Orderer `json:"-"`
+	Logger      observability.Logger `json:"-"`
+	GitHubToken string               `json:"-"`
+	Orderer     ordering.Orderer     `json:"-"`
 
 	// validated tracks whether ValidateAndSetDefaults has been called.
 	validated bool `json:"-"`
@@ -232,7 +326,8 @@ type CacheInfo struct {
 // ValidateFormat checks that a format is valid.
 func ValidateFormat(format string) error {
 	if !ValidFormats[format] {
-		return fmt.Errorf("invalid format: %q (must be one of: svg, png, pdf, json)", format)
+		formats := slices.Sorted(maps.Keys(ValidFormats))
+		return fmt.Errorf("invalid format: %q (must be one of: %s)", format, strings.Join(formats, ", "))
 	}
 	return nil
 }
@@ -247,10 +342,102 @@ func ValidateFormats(formats []string) error {
 	return nil
 }
 
-// ValidateStyle checks that a style is valid.
+// ValidateStyle checks that a style is registered: one of the built-ins or
+// a style added with [styles.Register] by an imported package.
 func ValidateStyle(style string) error {
-	if !ValidStyles[style] {
-		return fmt.Errorf("invalid style: %q (must be one of: simple, handdrawn)", style)
+	if !styles.Registered(style) {
+		return fmt.Errorf("invalid style: %q (must be one of: %s)", style, strings.Join(styles.Names(), ", "))
+	}
+	return nil
+}
+
+// ValidateEdgeRouting checks that an edge routing mode is valid.
+// The empty string selects the default (straight).
+func ValidateEdgeRouting(routing string) error {
+	if routing != "" && !ValidEdgeRoutings[routing] {
+		return fmt.Errorf("invalid edge routing: %q (must be one of: straight, orthogonal, curved)", routing)
+	}
+	return nil
+}
+
+// ValidateEngine checks that a nodelink engine is valid.
+// The empty string selects the default (dot).
+func ValidateEngine(engine string) error {
+	if engine != "" && !ValidEngines[engine] {
+		return fmt.Errorf("invalid engine: %q (must be one of: dot, layered)", engine)
+	}
+	return nil
+}
+
+// ValidateWeight checks that a treemap weight is valid.
+// The empty string sizes packages by dependency count.
+func ValidateWeight(weight string) error {
+	if weight != "" && !treemap.ValidWeights[weight] {
+		return fmt.Errorf("invalid weight: %q (must be one of: deps, downloads, size)", weight)
+	}
+	return nil
+}
+
+// ValidateTileSize checks that a tile size is either unset (0x0, a single
+// page) or at least [MinTileSize] in both dimensions.
+func ValidateTileSize(width, height float64) error {
+	if width == 0 && height == 0 {
+		return nil
+	}
+	if !(width >= MinTileSize && height >= MinTileSize) {
+		return fmt.Errorf("invalid tile size %gx%g (both sides must be at least %g)", width, height, MinTileSize)
+	}
+	return nil
+}
+
+// ValidateColorBy checks that a color-by field is valid.
+// The empty string keeps the style's own colours.
+func ValidateColorBy(colorBy string) error {
+	if colorBy != "" && !ValidColorBy[colorBy] {
+		return fmt.Errorf("invalid color-by: %q (must be one of: license, language, owner, vuln, staleness, freshness, health, none)", colorBy)
+	}
+	return nil
+}
+
+// ValidatePalette checks that a palette is a known name or a list of
+// #rrggbb colours. The empty string selects the default colours.
+func ValidatePalette(palette string) error {
+	if _, err := styles.ParsePalette(palette); err != nil {
+		return fmt.Errorf("invalid palette: %w", err)
+	}
+	return nil
+}
+
+// ValidateTheme checks that theme file contents describe a valid style.
+// The empty string keeps the named style.
+func ValidateTheme(text string) error {
+	if _, err := parseTheme(text); err != nil {
+		return fmt.Errorf("invalid theme: %w", err)
+	}
+	return nil
+}
+
+// parseTheme builds a style from theme file contents, or returns nil for
+// an empty theme.
+func parseTheme(text string) (*styles.Theme, error) {
+	if text == "" {
+		return nil, nil
+	}
+	cfg, err := styles.ParseConfig([]byte(text))
+	if err != nil {
+		return nil, err
+	}
+	return styles.FromConfig(cfg)
+}
+
+// ValidatePopupTemplate checks that a popup template parses.
+// The empty string selects the default popup.
+func ValidatePopupTemplate(text string) error {
+	if text == "" {
+		return nil
+	}
+	if _, err := sink.ParsePopupTemplate(text); err != nil {
+		return fmt.Errorf("invalid popup template: %w", err)
 	}
 	return nil
 }
@@ -258,7 +445,7 @@ func ValidateStyle(style string) error {
 // ValidateVizType checks that a visualization type is valid.
 func ValidateVizType(vizType string) error {
 	if !ValidVizTypes[vizType] {
-		return fmt.Errorf("invalid viz_type: %q (must be one of: tower, nodelink)", vizType)
+		return fmt.Errorf("invalid viz_type: %q (must be one of: tower, nodelink, sunburst, treemap, dsm)", vizType)
 	}
 	return nil
 }
@@ -290,6 +477,9 @@ func (o *Options) ValidateForParse() error {
 	if o.Package == "" && o.Manifest == "" {
 		return fmt.Errorf("package or manifest is required")
 	}
+	if o.Manifest == "" {
+		o.Package, o.Version = splitPackageSpec(o.Package, o.Version)
+	}
 	if o.Manifest != "" && o.ManifestFilename == "" {
 		return fmt.Errorf("manifest_filename is required")
 	}
@@ -301,16 +491,25 @@ func (o *Options) ValidateForParse() error {
 	if o.MaxNodes == 0 {
 		o.MaxNodes = DefaultMaxNodes
 	}
+	if o.MaxDuration < 0 {
+		return fmt.Errorf("max_duration must not be negative")
+	}
 	if o.DependencyScope == "" {
 		o.DependencyScope = deps.DependencyScopeProdOnly
 	}
 	if o.DependencyScope != deps.DependencyScopeProdOnly && o.DependencyScope != deps.DependencyScopeAll {
 		return fmt.Errorf("invalid dependency_scope: %q (must be one of: %s, %s)", o.DependencyScope, deps.DependencyScopeProdOnly, deps.DependencyScopeAll)
 	}
+	if err := deps.ValidateExcludes(o.Exclude); err != nil {
+		return err
+	}
+	if err := deps.ValidateInternal(o.Internal); err != nil {
+		return err
+	}
 
 	// Logger default
 	if o.Logger == nil {
-		o.Logger = log.NewWithOptions(io.Discard, log.Options{})
+		o.Logger = observability.NoopLogger{}
 	}
 
 	return nil
@@ -331,13 +530,23 @@ func (o *Options) SetLayoutDefaults() {
 		o.Seed = DefaultSeed
 	}
 	if o.Logger == nil {
-		o.Logger = log.NewWithOptions(io.Discard, log.Options{})
+		o.Logger = observability.NoopLogger{}
 	}
 }
 
 // ValidateForLayout validates and sets defaults for layout computation.
 func (o *Options) ValidateForLayout() error {
 	o.SetLayoutDefaults()
+	if o.NebraskaScoring != nil {
+		if err := o.NebraskaScoring.Validate(); err != nil {
+			return fmt.Errorf("invalid nebraska_scoring: %w", err)
+		}
+	}
+	if o.Health != nil {
+		if err := o.Health.Validate(); err != nil {
+			return fmt.Errorf("invalid health: %w", err)
+		}
+	}
 	return ValidateVizType(o.VizType)
 }
 
@@ -350,7 +559,7 @@ func (o *Options) SetRenderDefaults() {
 		o.Style = DefaultStyle
 	}
 	if o.Logger == nil {
-		o.Logger = log.NewWithOptions(io.Discard, log.Options{})
+		o.Logger = observability.NoopLogger{}
 	}
 }
 
@@ -424,6 +633,18 @@ func (o *Options) ValidateForRender() error {
 	if err := ValidateFormats(o.Formats); err != nil {
 		return err
 	}
+	if err := ValidateEngine(o.Engine); err != nil {
+		return err
+	}
+	if err := ValidateWeight(o.Weight); err != nil {
+		return err
+	}
+	if err := ValidateTileSize(o.TileWidth, o.TileHeight); err != nil {
+		return err
+	}
+	if !o.IsTower() && !o.IsNodelink() && slices.Contains(o.Formats, FormatHTML) {
+		return fmt.Errorf("format %q is only supported for tower and nodelink visualizations", FormatHTML)
+	}
 	return ValidateStyle(o.Style)
 }
 
@@ -437,12 +658,50 @@ func (o *Options) IsNodelink() bool {
 	return o.VizType == graph.VizTypeNodelink
 }
 
+// IsSunburst returns true if this is a sunburst visualization.
+func (o *Options) IsSunburst() bool {
+	return o.VizType == graph.VizTypeSunburst
+}
+
+// IsTreemap returns true if this is a treemap visualization.
+func (o *Options) IsTreemap() bool {
+	return o.VizType == graph.VizTypeTreemap
+}
+
+// IsDSM returns true if this is a design structure matrix visualization.
+func (o *Options) IsDSM() bool {
+	return o.VizType == graph.VizTypeDSM
+}
+
+// IsTiled returns true if tower output should be split across multiple pages.
+func (o *Options) IsTiled() bool {
+	return o.IsTower() && o.TileWidth > 0 && o.TileHeight > 0
+}
+
 // NeedsOptimalOrderer returns true if the ordering algorithm requires the optimal orderer.
 // This is true when ordering is "optimal" (the default) or empty.
 func (o *Options) NeedsOptimalOrderer() bool {
 	return o.Ordering == DefaultOrdering || o.Ordering == ""
 }
 
+// orderer returns the tower row orderer: opts.Orderer when set, otherwise
+// the orderer named by Ordering, seeded with Seed.
+func (o *Options) orderer() ordering.Orderer {
+	if o.Orderer != nil {
+		return o.Orderer
+	}
+	seed := cmp.Or(o.Seed, DefaultSeed)
+	if o.NeedsOptimalOrderer() {
+		return ordering.OptimalSearch{
+			Timeout:  ordering.DefaultTimeoutOptimal,
+			Restarts: o.Restarts,
+			Seed:     seed,
+			Logger:   o.Logger,
+		}
+	}
+	return ordering.Barycentric{Restarts: o.Restarts, Seed: seed}
+}
+
 // ShouldEnrich returns whether metadata enrichment should be performed.
 func (o *Options) ShouldEnrich() bool {
 	return !o.SkipEnrich
@@ -450,30 +709,123 @@ func (o *Options) ShouldEnrich() bool {
 
 // LayoutKeyOpts returns cache key options for layout computation.
 func (o *Options) LayoutKeyOpts() cache.LayoutKeyOpts {
-	return cache.LayoutKeyOpts{
+	key := cache.LayoutKeyOpts{
 		VizType:   o.VizType,
 		Width:     o.Width,
 		Height:    o.Height,
 		Normalize: o.Normalize,
+		FixedRows: o.FixedRows,
 		Ordering:  o.Ordering,
 		Merge:     o.Merge,
 		Randomize: o.Randomize,
 		Seed:      o.Seed,
+		Restarts:  o.Restarts,
+		ClusterBy: o.ClusterBy,
+
+		NebraskaScoring: o.nebraskaScoringKey(),
+		Health:          o.healthKey(),
+	}
+	// Nodelink styling is baked into the DOT layout, unlike tower colours
+	// which are applied at render time.
+	if o.IsNodelink() {
+		key.ColorBy = o.ColorBy
+		key.EdgeLabels = o.EdgeLabels
+		key.EdgeKinds = o.EdgeKinds
+	}
+	return key
+}
+
+// nodelinkOptions returns the DOT generation options for nodelink layouts.
+func (o *Options) nodelinkOptions() nodelink.Options {
+	return nodelink.Options{
+		ClusterBy:     o.ClusterBy,
+		ColorByHealth: o.ColorBy == "health",
+		EdgeLabels:    o.EdgeLabels,
+		EdgeKinds:     o.EdgeKinds,
+	}
+}
+
+// nebraskaScoring returns the configured Nebraska weights or the defaults.
+func (o *Options) nebraskaScoring() feature.ScoringConfig {
+	if o.NebraskaScoring == nil {
+		return feature.DefaultScoring()
 	}
+	return *o.NebraskaScoring
+}
+
+// nebraskaScoringKey fingerprints custom Nebraska weights for the layout
+// cache key; the defaults hash to the empty string so existing keys survive.
+func (o *Options) nebraskaScoringKey() string {
+	if o.NebraskaScoring == nil {
+		return ""
+	}
+	data, _ := json.Marshal(o.NebraskaScoring)
+	return string(data)
+}
+
+// healthConfig returns the configured health weights; the zero config means
+// the defaults.
+func (o *Options) healthConfig() feature.HealthConfig {
+	if o.Health == nil {
+		return feature.HealthConfig{}
+	}
+	return *o.Health
+}
+
+// healthKey fingerprints custom health weights for the layout cache key,
+// like nebraskaScoringKey.
+func (o *Options) healthKey() string {
+	if o.Health == nil {
+		return ""
+	}
+	data, _ := json.Marshal(o.Health)
+	return string(data)
 }
 
 // ArtifactKeyOpts returns cache key options for artifact rendering.
 func (o *Options) ArtifactKeyOpts(format string) cache.ArtifactKeyOpts {
 	return cache.ArtifactKeyOpts{
-		Format:       format,
-		Style:        o.Style,
-		ShowEdges:    o.ShowEdges,
-		Popups:       o.Popups,
-		Nebraska:     o.Nebraska,
-		Merge:        o.Merge,
-		Normalize:    o.Normalize,
-		ShowVulns:    o.ShowVulns,
-		ShowLicenses: o.ShowLicenses,
-		FlagsOnTop:   o.FlagsOnTop,
+		Format:        format,
+		Style:         o.Style,
+		ShowEdges:     o.ShowEdges,
+		Popups:        o.Popups,
+		Nebraska:      o.Nebraska,
+		Merge:         o.Merge,
+		Normalize:     o.Normalize,
+		FixedRows:     o.FixedRows,
+		ShowVulns:     o.ShowVulns,
+		ShowLicenses:  o.ShowLicenses,
+		Suspicious:    o.Suspicious,
+		FlagsOnTop:    o.FlagsOnTop,
+		TileWidth:     o.TileWidth,
+		TileHeight:    o.TileHeight,
+		Highlight:     o.Highlight,
+		EdgeRouting:   o.EdgeRouting,
+		EdgeBundling:  o.EdgeBundling,
+		Engine:        o.Engine,
+		Rings:         o.Rings,
+		Weight:        o.Weight,
+		ColorBy:       o.ColorBy,
+		Palette:       o.Palette,
+		Theme:         o.Theme,
+		Icons:         o.Icons,
+		PopupFields:   o.PopupFields,
+		PopupTemplate: o.PopupTemplate,
+		Footer:        o.Footer,
+		FooterNote:    o.FooterNote,
+		Branding:      o.Branding,
+
+		StaleAfterDays:     o.StaleAfterDays,
+		AbandonedAfterDays: o.AbandonedAfterDays,
+	}
+}
+
+// brittleThresholds converts the day-based brittleness options; zero fields
+// keep the defaults.
+func (o *Options) brittleThresholds() feature.BrittleThresholds {
+	const day = 24 * time.Hour
+	return feature.BrittleThresholds{
+		Stale:     time.Duration(o.StaleAfterDays) * day,
+		Abandoned: time.Duration(o.AbandonedAfterDays) * day,
 	}
 }
@@ -1,9 +1,18 @@
 package pipeline
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/core/render/nodelink"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/ordering"
+	"github.com/stacktower-io/stacktower/pkg/graph"
 )
 
 func TestValidateFormat(t *testing.T) {
@@ -15,6 +24,8 @@ func TestValidateFormat(t *testing.T) {
 		{"png", false},
 		{"pdf", false},
 		{"json", false},
+		{"pptx", false},
+		{"html", false},
 		{"invalid", true},
 		{"SVG", true}, // case-sensitive
 		{"", true},
@@ -26,6 +37,13 @@ func TestValidateFormat(t *testing.T) {
 			t.Errorf("ValidateFormat(%q) error = %v, wantErr %v", tt.format, err, tt.wantErr)
 		}
 	}
+
+	err := ValidateFormat("invalid")
+	for _, f := range []string{FormatSVG, FormatPPTX, FormatHTML} {
+		if err == nil || !strings.Contains(err.Error(), f) {
+			t.Errorf("ValidateFormat error %v does not list %q", err, f)
+		}
+	}
 }
 
 func TestValidateFormats(t *testing.T) {
@@ -62,6 +80,132 @@ func TestValidateStyle(t *testing.T) {
 	}
 }
 
+func TestValidateEdgeRouting(t *testing.T) {
+	tests := []struct {
+		routing string
+		wantErr bool
+	}{
+		{"", false},
+		{"straight", false},
+		{"orthogonal", false},
+		{"curved", false},
+		{"spline", true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateEdgeRouting(tt.routing)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateEdgeRouting(%q) error = %v, wantErr %v", tt.routing, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateEngine(t *testing.T) {
+	tests := []struct {
+		engine  string
+		wantErr bool
+	}{
+		{"", false},
+		{"dot", false},
+		{"layered", false},
+		{"neato", true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateEngine(tt.engine)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateEngine(%q) error = %v, wantErr %v", tt.engine, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateWeight(t *testing.T) {
+	for weight, wantErr := range map[string]bool{"": false, "deps": false, "downloads": false, "size": false, "stars": true} {
+		if err := ValidateWeight(weight); (err != nil) != wantErr {
+			t.Errorf("ValidateWeight(%q) error = %v, wantErr %v", weight, err, wantErr)
+		}
+	}
+}
+
+func TestValidateTileSize(t *testing.T) {
+	tests := []struct {
+		w, h    float64
+		wantErr bool
+	}{
+		{0, 0, false},
+		{1200, 900, false},
+		{100, 100, false},
+		{0.05, 0.05, true},
+		{1200, 0, true},
+		{-1200, 900, true},
+	}
+	for _, tt := range tests {
+		if err := ValidateTileSize(tt.w, tt.h); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateTileSize(%g, %g) error = %v, wantErr %v", tt.w, tt.h, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateColorBy(t *testing.T) {
+	tests := []struct {
+		colorBy string
+		wantErr bool
+	}{
+		{"", false},
+		{"none", false},
+		{"license", false},
+		{"staleness", false},
+		{"stars", true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateColorBy(tt.colorBy)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateColorBy(%q) error = %v, wantErr %v", tt.colorBy, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidatePalette(t *testing.T) {
+	tests := []struct {
+		palette string
+		wantErr bool
+	}{
+		{"", false},
+		{"okabe-ito", false},
+		{"#112233,#445566", false},
+		{"#12345", true},
+		{"neon", true},
+	}
+
+	for _, tt := range tests {
+		err := ValidatePalette(tt.palette)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidatePalette(%q) error = %v, wantErr %v", tt.palette, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateTheme(t *testing.T) {
+	tests := []struct {
+		theme   string
+		wantErr bool
+	}{
+		{"", false},
+		{"background: \"#000000\"\ntexture: grid\n", false},
+		{`{"block": {"corner_radius": 4}}`, false},
+		{"texture: marble\n", true},
+		{"colour: red\n", true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateTheme(tt.theme)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateTheme(%q) error = %v, wantErr %v", tt.theme, err, tt.wantErr)
+		}
+	}
+}
+
 func TestValidateVizType(t *testing.T) {
 	tests := []struct {
 		vizType string
@@ -103,6 +247,16 @@ func TestOptionsDefaults(t *testing.T) {
 	}
 }
 
+func TestOptionsValidateForParse_PackageSpec(t *testing.T) {
+	opts := Options{Language: "javascript", Package: "@angular/core@17.0.0", Version: "16.0.0"}
+	if err := opts.ValidateForParse(); err != nil {
+		t.Fatalf("ValidateForParse() error = %v", err)
+	}
+	if opts.Package != "@angular/core" || opts.Version != "17.0.0" {
+		t.Errorf("package, version = %q, %q; want the specifier split", opts.Package, opts.Version)
+	}
+}
+
 func TestOptionsValidateForParse(t *testing.T) {
 	// Missing language
 	opts := Options{Package: "requests"}
@@ -128,11 +282,40 @@ func TestOptionsValidateForParse(t *testing.T) {
 		t.Errorf("Valid manifest options should pass: %v", err)
 	}
 
+	// Negative time budget
+	opts = Options{Language: "python", Package: "requests", MaxDuration: -time.Second}
+	if err := opts.ValidateForParse(); err == nil {
+		t.Error("Negative max_duration should fail")
+	}
+
 	// Invalid dependency scope
 	opts = Options{Language: "python", Package: "requests", DependencyScope: "invalid"}
 	if err := opts.ValidateForParse(); err == nil {
 		t.Error("Invalid dependency_scope should fail")
 	}
+
+	// Invalid exclude pattern
+	opts = Options{Language: "python", Package: "requests", Exclude: []string{"types-["}}
+	if err := opts.ValidateForParse(); err == nil {
+		t.Error("Invalid exclude pattern should fail")
+	}
+}
+
+func TestOptionsValidateForRender_HTML(t *testing.T) {
+	opts := Options{VizType: "nodelink", Formats: []string{"svg", "html"}}
+	if err := opts.ValidateForRender(); err != nil {
+		t.Errorf("HTML for nodelink should pass: %v", err)
+	}
+
+	opts = Options{Formats: []string{"html"}}
+	if err := opts.ValidateForRender(); err != nil {
+		t.Errorf("HTML for towers should pass: %v", err)
+	}
+
+	opts = Options{VizType: "sunburst", Formats: []string{"html"}}
+	if err := opts.ValidateForRender(); err == nil {
+		t.Error("HTML for sunbursts should fail")
+	}
 }
 
 func TestOptionsIsTower(t *testing.T) {
@@ -193,6 +376,26 @@ func TestOptionsNeedsOptimalOrderer(t *testing.T) {
 	}
 }
 
+func TestOptionsOrderer(t *testing.T) {
+	opts := Options{Ordering: "barycentric", Seed: 7, Restarts: 3}
+	want := ordering.Barycentric{Restarts: 3, Seed: 7}
+	if got := opts.orderer(); got != want {
+		t.Errorf("barycentric orderer = %#v, want %#v", got, want)
+	}
+
+	opts.Ordering = ""
+	search, ok := opts.orderer().(ordering.OptimalSearch)
+	if !ok || search.Seed != 7 || search.Restarts != 3 {
+		t.Errorf("default orderer = %#v, want a seeded OptimalSearch", opts.orderer())
+	}
+
+	custom := ordering.Barycentric{Passes: 2}
+	opts.Orderer = custom
+	if got := opts.orderer(); got != custom {
+		t.Errorf("orderer with Orderer set = %#v, want %#v", got, custom)
+	}
+}
+
 func TestOptionsValidateAndSetDefaultsIdempotent(t *testing.T) {
 	opts := Options{
 		Language: "python",
@@ -253,3 +456,169 @@ func TestSetRenderDefaults(t *testing.T) {
 		t.Errorf("Style should be %s, got %s", DefaultStyle, opts.Style)
 	}
 }
+
+func TestNebraskaScoringLayoutKey(t *testing.T) {
+	base := Options{}
+	if key := base.LayoutKeyOpts().NebraskaScoring; key != "" {
+		t.Errorf("default scoring should not change the layout key, got %q", key)
+	}
+
+	custom := Options{NebraskaScoring: &feature.ScoringConfig{DepthCurve: feature.DepthLog}}
+	if custom.LayoutKeyOpts().NebraskaScoring == "" {
+		t.Error("custom scoring should change the layout key")
+	}
+	if err := custom.ValidateForLayout(); err != nil {
+		t.Errorf("ValidateForLayout() error = %v", err)
+	}
+
+	bad := Options{NebraskaScoring: &feature.ScoringConfig{DepthCurve: "cubic"}}
+	if err := bad.ValidateForLayout(); err == nil {
+		t.Error("expected error for unknown depth curve")
+	}
+}
+
+func TestRenderNodelink_Engine(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "lib", Row: 1})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "lib"})
+	opts := Options{VizType: graph.VizTypeNodelink, Formats: []string{FormatSVG}}
+	layout, err := nodelink.Export(nodelink.ToDOT(g, opts.nodelinkOptions()), g, opts.nodelinkOptions(), 0, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts.Engine = nodelink.EngineLayered
+	out, err := RenderNodelink(context.Background(), layout, opts)
+	if err != nil {
+		t.Fatalf("layered engine: %v", err)
+	}
+	if svg := string(out[FormatSVG]); strings.Contains(svg, "graphviz") || !strings.Contains(svg, "<title>lib</title>") {
+		t.Errorf("layered engine did not draw the graph itself:\n%s", svg)
+	}
+
+	// Graphviz failures fall back to the layered engine.
+	opts.Engine = ""
+	layout.DOT = "digraph {"
+	if out, err = RenderNodelink(context.Background(), layout, opts); err != nil {
+		t.Fatalf("fallback: %v", err)
+	}
+	if !strings.Contains(string(out[FormatSVG]), "<title>app</title>") {
+		t.Error("fallback did not render the graph")
+	}
+}
+
+func TestRenderFromLayout_Sunburst(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "lib"})
+	_ = g.AddNode(dag.Node{ID: "util"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "lib"})
+	_ = g.AddEdge(dag.Edge{From: "lib", To: "util"})
+
+	opts := Options{VizType: graph.VizTypeSunburst, Width: 500, Height: 400, Rings: 1, Formats: []string{FormatSVG, FormatJSON}}
+	l, err := GenerateLayout(context.Background(), g, opts)
+	if err != nil {
+		t.Fatalf("GenerateLayout() error: %v", err)
+	}
+	out, err := RenderFromLayout(context.Background(), l, nil, opts)
+	if err != nil {
+		t.Fatalf("RenderFromLayout() error: %v", err)
+	}
+	svg := string(out[FormatSVG])
+	if !strings.Contains(svg, `viewBox="0 0 400 400"`) || !strings.Contains(svg, `data-id="lib"`) || strings.Contains(svg, `data-id="util"`) {
+		t.Errorf("sunburst SVG not sized to the frame or not limited to one ring:\n%s", svg)
+	}
+	parsed, err := graph.UnmarshalLayout(out[FormatJSON])
+	if err != nil || !parsed.IsSunburst() || len(parsed.Nodes) != 3 {
+		t.Errorf("sunburst JSON = %+v, %v", parsed, err)
+	}
+}
+
+func TestRenderFromLayout_Treemap(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "lib", Meta: dag.Metadata{"downloads": 5000}})
+	_ = g.AddNode(dag.Node{ID: "util", Meta: dag.Metadata{"downloads": 2000}})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "lib"})
+	_ = g.AddEdge(dag.Edge{From: "lib", To: "util"})
+
+	opts := Options{VizType: graph.VizTypeTreemap, Width: 500, Height: 400, Weight: "downloads", Formats: []string{FormatSVG, FormatJSON}}
+	l, err := GenerateLayout(context.Background(), g, opts)
+	if err != nil {
+		t.Fatalf("GenerateLayout() error: %v", err)
+	}
+	out, err := RenderFromLayout(context.Background(), l, nil, opts)
+	if err != nil {
+		t.Fatalf("RenderFromLayout() error: %v", err)
+	}
+	svg := string(out[FormatSVG])
+	if !strings.Contains(svg, `viewBox="0 0 500 400"`) || !strings.Contains(svg, `data-id="util"`) || !strings.Contains(svg, "5.0k downloads") {
+		t.Errorf("treemap SVG not sized to the frame or not weighted by downloads:\n%s", svg)
+	}
+	parsed, err := graph.UnmarshalLayout(out[FormatJSON])
+	if err != nil || !parsed.IsTreemap() || len(parsed.Nodes) != 3 {
+		t.Errorf("treemap JSON = %+v, %v", parsed, err)
+	}
+}
+
+func TestRenderFromLayout_DSM(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "lib"})
+	_ = g.AddNode(dag.Node{ID: "util"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "lib"})
+	_ = g.AddEdge(dag.Edge{From: "lib", To: "util"})
+	_ = g.AddEdge(dag.Edge{From: "util", To: "lib"})
+
+	opts := Options{VizType: graph.VizTypeDSM, Formats: []string{FormatSVG, FormatJSON}}
+	l, err := GenerateLayout(context.Background(), g, opts)
+	if err != nil {
+		t.Fatalf("GenerateLayout() error: %v", err)
+	}
+	out, err := RenderFromLayout(context.Background(), l, nil, opts)
+	if err != nil {
+		t.Fatalf("RenderFromLayout() error: %v", err)
+	}
+	if svg := string(out[FormatSVG]); !strings.Contains(svg, `data-from="util" data-to="lib"`) || !strings.Contains(svg, "cycle (2)") {
+		t.Errorf("DSM SVG missing the lib-util cycle:\n%s", svg)
+	}
+	parsed, err := graph.UnmarshalLayout(out[FormatJSON])
+	if err != nil || !parsed.IsDSM() || len(parsed.Edges) != 3 {
+		t.Errorf("DSM JSON = %+v, %v", parsed, err)
+	}
+}
+
+func TestGenerateLayout_Cancelled(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "lib", Row: 1})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "lib"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	opts := Options{VizType: graph.VizTypeTower, Width: 400, Height: 300, Formats: []string{FormatSVG}}
+	if _, err := GenerateLayout(ctx, g, opts); !errors.Is(err, context.Canceled) {
+		t.Errorf("GenerateLayout() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestRenderFromLayout_Cancelled(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "lib"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "lib"})
+
+	opts := Options{VizType: graph.VizTypeSunburst, Width: 400, Height: 400, Formats: []string{FormatSVG, FormatPNG}}
+	l, err := GenerateLayout(context.Background(), g, opts)
+	if err != nil {
+		t.Fatalf("GenerateLayout() error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := RenderFromLayout(ctx, l, g, opts); !errors.Is(err, context.Canceled) {
+		t.Errorf("RenderFromLayout() error = %v, want context.Canceled", err)
+	}
+}
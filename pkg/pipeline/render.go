@@ -1,30 +1,92 @@
 package pipeline
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"time"
 
+	"github.com/stacktower-io/stacktower/pkg/buildinfo"
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	corerender "github.com/stacktower-io/stacktower/pkg/core/render"
+	"github.com/stacktower-io/stacktower/pkg/core/render/dsm"
 	"github.com/stacktower-io/stacktower/pkg/core/render/nodelink"
+	"github.com/stacktower-io/stacktower/pkg/core/render/sunburst"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/layout"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/sink"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles"
-	"github.com/stacktower-io/stacktower/pkg/core/render/tower/styles/handdrawn"
+	_ "github.com/stacktower-io/stacktower/pkg/core/render/tower/styles/handdrawn" // registers the "handdrawn" style
+	"github.com/stacktower-io/stacktower/pkg/core/render/treemap"
 	"github.com/stacktower-io/stacktower/pkg/graph"
+	"github.com/stacktower-io/stacktower/pkg/observability"
 )
 
-// Render generates output artifacts in the requested formats.
-func Render(l layout.Layout, g *dag.DAG, opts Options) (map[string][]byte, error) {
+// Render generates output artifacts in the requested formats. It stops
+// early when ctx is done, including in the middle of a PNG or PDF
+// conversion.
+func Render(ctx context.Context, l layout.Layout, g *dag.DAG, opts Options) (map[string][]byte, error) {
+	return renderWithSinks(ctx, g, opts, func(o Options) (map[string][]byte, error) {
+		return renderBuiltin(ctx, l, g, o)
+	})
+}
+
+// renderWithSinks calls render for the built-in formats in opts and
+// renders the formats of registered sinks from its SVG, which is produced
+// for them even when it was not requested.
+func renderWithSinks(ctx context.Context, g *dag.DAG, opts Options, render func(Options) (map[string][]byte, error)) (map[string][]byte, error) {
+	builtin, external := splitSinkFormats(opts.Formats)
+	if len(external) == 0 {
+		return render(opts)
+	}
+
+	o := opts
+	o.Formats = builtin
+	if !slices.Contains(builtin, FormatSVG) {
+		o.Formats = append(slices.Clone(builtin), FormatSVG)
+	}
+	artifacts, err := render(o)
+	if err != nil {
+		return nil, err
+	}
+	svg := artifacts[FormatSVG]
+	if !slices.Contains(builtin, FormatSVG) {
+		delete(artifacts, FormatSVG)
+	}
+	for _, s := range external {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		data, err := s.Render(ctx, g, svg, opts)
+		if err != nil {
+			return nil, fmt.Errorf("render %s: %w", s.Format(), err)
+		}
+		artifacts[s.Format()] = data
+	}
+	return artifacts, nil
+}
+
+// renderBuiltin generates the artifacts of the built-in formats.
+func renderBuiltin(ctx context.Context, l layout.Layout, g *dag.DAG, opts Options) (map[string][]byte, error) {
 	if opts.IsNodelink() {
 		// For nodelink, generate DOT graph on-demand
-		return renderNodelinkFromGraph(g, opts)
+		return renderNodelinkFromGraph(ctx, g, opts)
 	}
-	return renderTower(l, g, opts)
+	if opts.IsSunburst() {
+		return renderSunburst(ctx, g, sunburst.Export(g, opts.Width, opts.Height, opts.Style), opts)
+	}
+	if opts.IsTreemap() {
+		return renderTreemap(ctx, g, treemap.Export(g, opts.Width, opts.Height, opts.Style), opts)
+	}
+	if opts.IsDSM() {
+		return renderDSM(ctx, g, dsm.Export(g, opts.Width, opts.Height, opts.Style), opts)
+	}
+	return renderTower(ctx, l, g, opts)
 }
 
 // RenderNodelink generates nodelink outputs from a layout.
 // The layout must be a nodelink layout (VizType = "nodelink") with a DOT string.
-func RenderNodelink(layout graph.Layout, opts Options) (map[string][]byte, error) {
+func RenderNodelink(ctx context.Context, layout graph.Layout, opts Options) (map[string][]byte, error) {
 	if layout.DOT == "" {
 		return nil, fmt.Errorf("nodelink layout missing DOT string")
 	}
@@ -32,7 +94,7 @@ func RenderNodelink(layout graph.Layout, opts Options) (map[string][]byte, error
 	artifacts := make(map[string][]byte)
 	needsSVG := false
 	for _, format := range opts.Formats {
-		if format == FormatSVG || format == FormatPNG || format == FormatPDF {
+		if format == FormatSVG || format == FormatPNG || format == FormatPDF || format == FormatPPTX || format == FormatHTML {
 			needsSVG = true
 			break
 		}
@@ -41,13 +103,16 @@ func RenderNodelink(layout graph.Layout, opts Options) (map[string][]byte, error
 	var svgData []byte
 	if needsSVG {
 		var err error
-		svgData, err = nodelink.RenderSVG(layout.DOT)
+		svgData, err = nodelinkSVG(layout, opts)
 		if err != nil {
 			return nil, fmt.Errorf("render %s: %w", FormatSVG, err)
 		}
 	}
 
 	for _, format := range opts.Formats {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var data []byte
 		var err error
 
@@ -55,9 +120,13 @@ func RenderNodelink(layout graph.Layout, opts Options) (map[string][]byte, error
 		case FormatSVG:
 			data = svgData
 		case FormatPNG:
-			data, err = corerender.ToPNG(svgData, 2.0)
+			data, err = corerender.ToPNGContext(ctx, svgData, 2.0)
 		case FormatPDF:
-			data, err = corerender.ToPDF(svgData)
+			data, err = corerender.ToPDFContext(ctx, svgData)
+		case FormatPPTX:
+			data, err = svgPPTX(ctx, svgData, opts)
+		case FormatHTML:
+			data, err = nodelink.RenderHTML(svgData, deckTitle(opts))
 		case FormatJSON:
 			data, err = graph.MarshalLayout(layout)
 		default:
@@ -73,24 +142,136 @@ func RenderNodelink(layout graph.Layout, opts Options) (map[string][]byte, error
 	return artifacts, nil
 }
 
+// nodelinkSVG draws a nodelink layout with the configured engine. When
+// Graphviz fails, the pure-Go layered engine takes over, so nodelink output
+// works without a usable Graphviz runtime.
+func nodelinkSVG(layout graph.Layout, opts Options) ([]byte, error) {
+	if opts.Engine != nodelink.EngineLayered {
+		svg, err := nodelink.RenderSVG(layout.DOT)
+		if err == nil || len(layout.Nodes) == 0 {
+			return svg, err
+		}
+		observability.LoggerOrNoop(opts.Logger).Warn("graphviz failed, falling back to the layered engine", "err", err)
+	}
+	g, err := graph.ToDAG(graph.Graph{Nodes: layout.Nodes, Edges: layout.Edges})
+	if err != nil {
+		return nil, fmt.Errorf("rebuild graph for layered engine: %w", err)
+	}
+	return nodelink.RenderLayeredSVG(g, opts.nodelinkOptions())
+}
+
+// svgPPTX wraps a rendered nodelink, sunburst, treemap or matrix SVG in a
+// single-slide deck.
+func svgPPTX(ctx context.Context, svg []byte, opts Options) ([]byte, error) {
+	png, err := corerender.ToPNGContext(ctx, svg, 2.0)
+	if err != nil {
+		return nil, err
+	}
+	return corerender.ToPPTX([]corerender.Slide{{Title: deckTitle(opts), PNG: png, SVG: svg}})
+}
+
+// deckTitle returns the slide title for PPTX exports and the page title
+// for HTML.
+func deckTitle(opts Options) string {
+	if opts.Package != "" {
+		return opts.Package
+	}
+	return opts.ManifestFilename
+}
+
 // renderNodelinkFromGraph generates nodelink outputs directly from a graph.
 // This generates the DOT graph on-demand instead of requiring a pre-computed layout.
-func renderNodelinkFromGraph(g *dag.DAG, opts Options) (map[string][]byte, error) {
+func renderNodelinkFromGraph(ctx context.Context, g *dag.DAG, opts Options) (map[string][]byte, error) {
 	// Generate DOT graph
-	dot := nodelink.ToDOT(g, nodelink.Options{Detailed: false})
+	dot := nodelink.ToDOT(g, opts.nodelinkOptions())
 
 	// Build layout
-	layout, err := nodelink.Export(dot, g, nodelink.Options{Detailed: false}, opts.Width, opts.Height, opts.Style)
+	layout, err := nodelink.Export(dot, g, opts.nodelinkOptions(), opts.Width, opts.Height, opts.Style)
 	if err != nil {
 		return nil, fmt.Errorf("generate nodelink layout: %w", err)
 	}
 
 	// Render using the layout
-	return RenderNodelink(layout, opts)
+	return RenderNodelink(ctx, layout, opts)
+}
+
+// renderSunburst generates sunburst outputs. The chart is sized to fit the
+// frame and the JSON format returns the layout.
+func renderSunburst(ctx context.Context, g *dag.DAG, l graph.Layout, opts Options) (map[string][]byte, error) {
+	palette, err := styles.ParsePalette(opts.Palette)
+	if err != nil {
+		return nil, err
+	}
+	size := opts.Width
+	if opts.Height > 0 && (size <= 0 || opts.Height < size) {
+		size = opts.Height
+	}
+	sopts := sunburst.Options{Size: size, MaxDepth: opts.Rings, Palette: palette}
+	return renderChart(ctx, l, opts, func() ([]byte, error) { return sunburst.RenderSVG(g, sopts) })
+}
+
+// renderTreemap generates treemap outputs. The map fills the frame and the
+// JSON format returns the layout.
+func renderTreemap(ctx context.Context, g *dag.DAG, l graph.Layout, opts Options) (map[string][]byte, error) {
+	palette, err := styles.ParsePalette(opts.Palette)
+	if err != nil {
+		return nil, err
+	}
+	topts := treemap.Options{Width: opts.Width, Height: opts.Height, Weight: opts.Weight, Palette: palette}
+	return renderChart(ctx, l, opts, func() ([]byte, error) { return treemap.RenderSVG(g, topts) })
+}
+
+// renderDSM generates design structure matrix outputs. The matrix sizes
+// itself from the package count and the JSON format returns the layout.
+func renderDSM(ctx context.Context, g *dag.DAG, l graph.Layout, opts Options) (map[string][]byte, error) {
+	return renderChart(ctx, l, opts, func() ([]byte, error) { return dsm.RenderSVG(g, dsm.Options{}) })
+}
+
+// renderChart converts a chart drawn by draw, such as a sunburst, a treemap
+// or a matrix, to the requested formats. draw runs only when a format needs
+// the SVG; JSON returns the layout l.
+func renderChart(ctx context.Context, l graph.Layout, opts Options, draw func() ([]byte, error)) (map[string][]byte, error) {
+	var svgData []byte
+	if slices.ContainsFunc(opts.Formats, func(f string) bool { return f != FormatJSON }) {
+		var err error
+		if svgData, err = draw(); err != nil {
+			return nil, fmt.Errorf("render %s: %w", FormatSVG, err)
+		}
+	}
+
+	artifacts := make(map[string][]byte)
+	for _, format := range opts.Formats {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var data []byte
+		var err error
+
+		switch format {
+		case FormatSVG:
+			data = svgData
+		case FormatPNG:
+			data, err = corerender.ToPNGContext(ctx, svgData, 2.0)
+		case FormatPDF:
+			data, err = corerender.ToPDFContext(ctx, svgData)
+		case FormatPPTX:
+			data, err = svgPPTX(ctx, svgData, opts)
+		case FormatJSON:
+			data, err = graph.MarshalLayout(l)
+		default:
+			return nil, fmt.Errorf("unsupported %s format: %s", l.VizType, format)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("render %s: %w", format, err)
+		}
+		artifacts[format] = data
+	}
+	return artifacts, nil
 }
 
 // renderTower generates tower outputs.
-func renderTower(l layout.Layout, g *dag.DAG, opts Options) (map[string][]byte, error) {
+func renderTower(ctx context.Context, l layout.Layout, g *dag.DAG, opts Options) (map[string][]byte, error) {
 	opts = applyLayoutMetadata(opts, l)
 
 	svgOpts := buildSVGOptions(g, l, opts)
@@ -108,6 +289,9 @@ func renderTower(l layout.Layout, g *dag.DAG, opts Options) (map[string][]byte,
 	}
 
 	for _, format := range opts.Formats {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var data []byte
 		var err error
 
@@ -115,9 +299,26 @@ func renderTower(l layout.Layout, g *dag.DAG, opts Options) (map[string][]byte,
 		case FormatSVG:
 			data = svgData
 		case FormatPNG:
-			data, err = corerender.ToPNG(svgData, 2.0)
+			data, err = corerender.ToPNGContext(ctx, svgData, 2.0)
 		case FormatPDF:
-			data, err = corerender.ToPDF(svgData)
+			if opts.IsTiled() {
+				tileOpts := append(tileOptions(svgOpts, opts), sink.WithTileContext(ctx))
+				data, err = sink.RenderTiledPDF(l, tileOpts...)
+			} else {
+				data, err = corerender.ToPDFContext(ctx, svgData)
+			}
+		case FormatPPTX:
+			pptxOpts := []sink.PPTXOption{
+				sink.WithPPTXContext(ctx),
+				sink.WithPPTXSVGOptions(svgOpts...),
+				sink.WithPPTXTitle(deckTitle(opts)),
+			}
+			if opts.IsTiled() {
+				pptxOpts = append(pptxOpts, sink.WithPPTXTiles(tileOptions(svgOpts, opts)...))
+			}
+			data, err = sink.RenderPPTX(l, pptxOpts...)
+		case FormatHTML:
+			data, err = sink.RenderHTML(l, sink.WithHTMLSVGOptions(svgOpts...), sink.WithHTMLTitle(deckTitle(opts)))
 		case FormatJSON:
 			var exported graph.Layout
 			exported, err = l.Export(g)
@@ -138,6 +339,32 @@ func renderTower(l layout.Layout, g *dag.DAG, opts Options) (map[string][]byte,
 	return artifacts, nil
 }
 
+// RenderTiles splits a tower layout into page-sized SVG tiles according to
+// opts.TileWidth and opts.TileHeight. The returned overview SVG shows the full
+// tower with numbered tile outlines.
+func RenderTiles(graphLayout graph.Layout, g *dag.DAG, opts Options) (overview []byte, tiles []sink.Tile, err error) {
+	if !opts.IsTiled() {
+		return nil, nil, fmt.Errorf("tiling requires a tower layout with tile_width and tile_height set")
+	}
+	l, err := layout.Parse(graphLayout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("convert layout: %w", err)
+	}
+	opts = applyLayoutMetadata(opts, l)
+	svgOpts := buildSVGOptions(g, l, opts)
+
+	tiles = sink.RenderSVGTiles(l, tileOptions(svgOpts, opts)...)
+	overview = sink.RenderSVGOverview(l, tiles, svgOpts...)
+	return overview, tiles, nil
+}
+
+func tileOptions(svgOpts []sink.SVGOption, opts Options) []sink.TileOption {
+	return []sink.TileOption{
+		sink.WithTileSize(opts.TileWidth, opts.TileHeight),
+		sink.WithTileSVGOptions(svgOpts...),
+	}
+}
+
 // applyLayoutMetadata applies layout metadata to options if not already set.
 // This ensures that serialized layouts preserve their original rendering settings.
 func applyLayoutMetadata(opts Options, l layout.Layout) Options {
@@ -163,26 +390,80 @@ func buildSVGOptions(g *dag.DAG, l layout.Layout, opts Options) []sink.SVGOption
 	}
 	if opts.ShowEdges {
 		svgOpts = append(svgOpts, sink.WithEdges())
+		if routing, err := sink.ParseEdgeRouting(opts.EdgeRouting); err == nil {
+			svgOpts = append(svgOpts, sink.WithEdgeRouting(routing))
+		}
+		if opts.EdgeBundling > 0 {
+			svgOpts = append(svgOpts, sink.WithEdgeBundling(opts.EdgeBundling))
+		}
 	}
 	if opts.Merge {
 		svgOpts = append(svgOpts, sink.WithMerged())
 	}
-
-	// Apply visual style
-	switch opts.Style {
-	case graph.StyleHanddrawn:
-		seed := opts.Seed
-		if seed == 0 {
-			seed = 42
+	// Vulnerability data is stripped in PrepareGraph unless ShowVulns is set,
+	// so badges only appear for scanned graphs the caller wants them for.
+	if opts.ShowVulns && g != nil {
+		if vulns := feature.Vulnerabilities(g); len(vulns) > 0 {
+			svgOpts = append(svgOpts, sink.WithVulnerabilities(vulns))
+		}
+	}
+	if opts.Suspicious && g != nil {
+		if suspects := feature.Suspicious(g, time.Now()); len(suspects) > 0 {
+			svgOpts = append(svgOpts, sink.WithSuspicious(suspects))
 		}
-		svgOpts = append(svgOpts, sink.WithStyle(handdrawn.New(seed)))
-	case graph.StyleSimple:
-		svgOpts = append(svgOpts, sink.WithStyle(styles.Simple{}))
+	}
+	if opts.StaleAfterDays > 0 || opts.AbandonedAfterDays > 0 {
+		svgOpts = append(svgOpts, sink.WithBrittleThresholds(opts.brittleThresholds()))
+	}
+	palette, _ := styles.ParsePalette(opts.Palette)
+	if colorBy, err := sink.ParseColorBy(opts.ColorBy); err == nil && colorBy != sink.ColorByNone {
+		svgOpts = append(svgOpts, sink.WithColorBy(colorBy), sink.WithPalette(palette))
 	}
 
-	// Popups only for handdrawn style (simple doesn't support them yet)
-	if opts.Style == graph.StyleHanddrawn && opts.Popups && g != nil {
+	// Apply visual style from the registry (built-ins plus any registered
+	// by imported packages); unknown names keep the sink's default.
+	if style, err := styles.New(opts.Style, styles.Options{Seed: opts.Seed, Palette: palette}); err == nil {
+		svgOpts = append(svgOpts, sink.WithStyle(style))
+	}
+	// A theme file replaces the named style. Invalid themes are rejected by
+	// ValidateTheme up front; keep the named style if one slips through.
+	theme, _ := parseTheme(opts.Theme)
+	if theme != nil {
+		svgOpts = append(svgOpts, sink.WithStyle(theme))
+	}
+
+	if len(opts.Highlight) > 0 {
+		svgOpts = append(svgOpts, sink.WithHighlight(opts.Highlight...))
+	}
+
+	if opts.Icons {
+		svgOpts = append(svgOpts, sink.WithIcons())
+	}
+
+	// Popups only for handdrawn and theme files (simple doesn't support them yet)
+	if (opts.Style == graph.StyleHanddrawn || theme != nil) && opts.Popups && g != nil {
 		svgOpts = append(svgOpts, sink.WithPopups())
+		if len(opts.PopupFields) > 0 {
+			svgOpts = append(svgOpts, sink.WithPopupFields(opts.PopupFields...))
+		}
+		if opts.PopupTemplate != "" {
+			// Invalid templates are rejected by ValidatePopupTemplate up front;
+			// fall back to the default popup if one slips through.
+			if tmpl, err := sink.ParsePopupTemplate(opts.PopupTemplate); err == nil {
+				svgOpts = append(svgOpts, sink.WithPopupTemplate(tmpl))
+			}
+		}
+	}
+
+	if opts.Footer {
+		svgOpts = append(svgOpts, sink.WithFooter(sink.Footer{
+			Project: opts.Package,
+			Version: buildinfo.Version,
+			Note:    opts.FooterNote,
+		}))
+	}
+	if opts.Branding != "" {
+		svgOpts = append(svgOpts, sink.WithBranding(opts.Branding, ""))
 	}
 
 	// Nebraska guy ranking panel - only rendered if opts.Nebraska is true.
@@ -193,14 +474,14 @@ func buildSVGOptions(g *dag.DAG, l layout.Layout, opts Options) []sink.SVGOption
 	}
 
 	// Security flags rendering position
-	svgOpts = append(svgOpts, sink.WithFlagsOnTop(opts.FlagsOnTop))
+	svgOpts = append(svgOpts, sink.WithFlagsOnTop(opts.FlagsOnTop), sink.WithLogger(opts.Logger))
 
 	return svgOpts
 }
 
 // RenderFromLayoutData renders output from serialized layout data.
 // This is useful when the layout was computed elsewhere (e.g., cached).
-func RenderFromLayoutData(layoutData []byte, g *dag.DAG, opts Options) (map[string][]byte, error) {
+func RenderFromLayoutData(ctx context.Context, layoutData []byte, g *dag.DAG, opts Options) (map[string][]byte, error) {
 	// Parse layout
 	parsed, err := graph.UnmarshalLayout(layoutData)
 	if err != nil {
@@ -208,15 +489,45 @@ func RenderFromLayoutData(layoutData []byte, g *dag.DAG, opts Options) (map[stri
 	}
 
 	// Dispatch based on viz type
-	return RenderFromLayout(parsed, g, opts)
+	return RenderFromLayout(ctx, parsed, g, opts)
 }
 
 // RenderFromLayout renders output from a graph.Layout.
 // This is the preferred entry point when you have a graph.Layout.
-func RenderFromLayout(graphLayout graph.Layout, g *dag.DAG, opts Options) (map[string][]byte, error) {
+func RenderFromLayout(ctx context.Context, graphLayout graph.Layout, g *dag.DAG, opts Options) (map[string][]byte, error) {
+	return renderWithSinks(ctx, g, opts, func(o Options) (map[string][]byte, error) {
+		return renderFromLayout(ctx, graphLayout, g, o)
+	})
+}
+
+func renderFromLayout(ctx context.Context, graphLayout graph.Layout, g *dag.DAG, opts Options) (map[string][]byte, error) {
 	if graphLayout.IsNodelink() {
 		opts.VizType = graph.VizTypeNodelink
-		return RenderNodelink(graphLayout, opts)
+		return RenderNodelink(ctx, graphLayout, opts)
+	}
+	if graphLayout.IsSunburst() {
+		opts.VizType = graph.VizTypeSunburst
+		sg, err := sunburst.Parse(graphLayout)
+		if err != nil {
+			return nil, fmt.Errorf("convert layout: %w", err)
+		}
+		return renderSunburst(ctx, sg, graphLayout, opts)
+	}
+	if graphLayout.IsTreemap() {
+		opts.VizType = graph.VizTypeTreemap
+		tg, err := treemap.Parse(graphLayout)
+		if err != nil {
+			return nil, fmt.Errorf("convert layout: %w", err)
+		}
+		return renderTreemap(ctx, tg, graphLayout, opts)
+	}
+	if graphLayout.IsDSM() {
+		opts.VizType = graph.VizTypeDSM
+		dg, err := dsm.Parse(graphLayout)
+		if err != nil {
+			return nil, fmt.Errorf("convert layout: %w", err)
+		}
+		return renderDSM(ctx, dg, graphLayout, opts)
 	}
 
 	// Convert to internal tower layout
@@ -226,5 +537,5 @@ func RenderFromLayout(graphLayout graph.Layout, g *dag.DAG, opts Options) (map[s
 	}
 
 	// renderTower applies layout metadata via applyLayoutMetadata
-	return renderTower(l, g, opts)
+	return renderTower(ctx, l, g, opts)
 }
@@ -4,14 +4,16 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"log/slog"
+	"slices"
 	"time"
 
-	"github.com/charmbracelet/log"
-
 	"github.com/stacktower-io/stacktower/pkg/cache"
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	dagtransform "github.com/stacktower-io/stacktower/pkg/core/dag/transform"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
 	"github.com/stacktower-io/stacktower/pkg/core/deps/languages"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
 	"github.com/stacktower-io/stacktower/pkg/core/render/tower/ordering"
 	"github.com/stacktower-io/stacktower/pkg/graph"
 	"github.com/stacktower-io/stacktower/pkg/observability"
@@ -27,7 +29,7 @@ import (
 type Runner struct {
 	Cache   cache.Cache
 	Keyer   cache.Keyer
-	Logger  *log.Logger
+	Logger  observability.Logger
 	Scanner security.Scanner // Optional vulnerability scanner (nil = scanning disabled)
 
 	// Hooks provides optional per-runner observability hooks.
@@ -47,13 +49,14 @@ type RunnerHooks struct {
 // NewRunner creates a runner with the given cache and keyer.
 // If keyer is nil, a DefaultKeyer is used.
 // If cache is nil, a NullCache is used (caching disabled).
-func NewRunner(c cache.Cache, keyer cache.Keyer, logger *log.Logger) *Runner {
+// If logger is nil, [slog.Default] is used.
+func NewRunner(c cache.Cache, keyer cache.Keyer, logger observability.Logger) *Runner {
 	return NewRunnerWithScanner(c, keyer, logger, nil)
 }
 
 // NewRunnerWithScanner creates a runner with an optional security scanner.
 // If scanner is nil, security scanning is unavailable even when opts.SecurityScan is true.
-func NewRunnerWithScanner(c cache.Cache, keyer cache.Keyer, logger *log.Logger, scanner security.Scanner) *Runner {
+func NewRunnerWithScanner(c cache.Cache, keyer cache.Keyer, logger observability.Logger, scanner security.Scanner) *Runner {
 	if keyer == nil {
 		keyer = cache.NewDefaultKeyer()
 	}
@@ -61,7 +64,7 @@ func NewRunnerWithScanner(c cache.Cache, keyer cache.Keyer, logger *log.Logger,
 		c = cache.NewNullCache()
 	}
 	if logger == nil {
-		logger = log.Default()
+		logger = slog.Default()
 	}
 	return &Runner{
 		Cache:   c,
@@ -166,6 +169,18 @@ func (r *Runner) ParseWithCacheInfo(ctx context.Context, opts Options) (*ParseRe
 	start := time.Now()
 
 	result, err := r.parseWithCache(ctx, opts)
+	if err == nil {
+		// Excludes and the internal classification apply after the cache,
+		// so one cached graph serves them all.
+		result.Graph = deps.ExcludePackages(result.Graph, opts.Exclude)
+		if len(opts.Exclude) > 0 {
+			result.Graph.Meta()["exclude"] = slices.Clone(opts.Exclude)
+		}
+		deps.ClassifyPackages(result.Graph, opts.Internal)
+		if len(opts.Internal) > 0 {
+			result.Graph.Meta()["internal"] = slices.Clone(opts.Internal)
+		}
+	}
 
 	nodeCountVal := 0
 	if result != nil {
@@ -185,13 +200,22 @@ func (r *Runner) parseWithCache(ctx context.Context, opts Options) (*ParseResult
 	}
 	enriched := opts.ShouldEnrich()
 	cacheKey := r.Keyer.GraphKey(opts.Language, pkgOrManifest, cache.GraphKeyOpts{
-		MaxDepth:          opts.MaxDepth,
-		MaxNodes:          opts.MaxNodes,
-		Enriched:          enriched,
-		SecurityScan:      opts.SecurityScan,
-		IncludePrerelease: opts.IncludePrerelease,
-		DependencyScope:   opts.DependencyScope,
-		RuntimeVersion:    opts.RuntimeVersion,
+		MaxDepth:            opts.MaxDepth,
+		MaxNodes:            opts.MaxNodes,
+		Enriched:            enriched,
+		SecurityScan:        opts.SecurityScan,
+		IncludePrerelease:   opts.IncludePrerelease,
+		DependencyScope:     opts.DependencyScope,
+		RuntimeVersion:      opts.RuntimeVersion,
+		Platform:            opts.Platform,
+		IncludeIndirect:     opts.IncludeIndirect,
+		SparseIndex:         opts.SparseIndex,
+		Icons:               opts.Icons && enriched,
+		EnrichDepth:         opts.EnrichDepth,
+		EnrichMinDependents: opts.EnrichMinDependents,
+		LocalMetadata:       opts.LocalMetadata && enriched,
+		StaleAfterDays:      opts.StaleAfterDays,
+		AbandonedAfterDays:  opts.AbandonedAfterDays,
 	})
 
 	if !opts.Refresh {
@@ -231,8 +255,14 @@ func (r *Runner) parseWithCache(ctx context.Context, opts Options) (*ParseResult
 			r.Logger.Warn("security scan failed", "err", scanErr)
 		}
 	}
+	// Score after the scan so known vulnerabilities count against health.
+	feature.AnnotateHealth(parseResult.Graph, opts.healthConfig(), time.Now(), opts.brittleThresholds())
 
-	if !opts.Refresh {
+	// Partly enriched graphs, graphs resolved from stale registry data and
+	// graphs cut short by the time limit are not cached, so the next run
+	// fills the gaps.
+	meta := parseResult.Graph.Meta()
+	if !opts.Refresh && meta[GraphEnrichmentKey] != EnrichmentPartial && meta[GraphStaleKey] != true && meta[GraphTruncatedKey] != true {
 		if data, err := graph.MarshalGraph(parseResult.Graph); err == nil {
 			r.setCacheWithWarning(ctx, cacheKey, data, cache.TTLGraph, "parse")
 		}
@@ -305,7 +335,7 @@ func (r *Runner) GenerateLayoutWithCacheInfo(ctx context.Context, g *dag.DAG, op
 		}
 	}
 
-	layout, err := GenerateLayout(g, opts)
+	layout, err := GenerateLayout(ctx, g, opts)
 	r.pipelineHooksCtx(ctx).OnLayoutComplete(ctx, opts.VizType, time.Since(start), err)
 	if err != nil {
 		return graph.Layout{}, false, err
@@ -360,7 +390,7 @@ func (r *Runner) RenderWithCacheInfo(ctx context.Context, layout graph.Layout, g
 		return artifacts, true, nil
 	}
 
-	rendered, err := RenderFromLayout(layout, g, opts)
+	rendered, err := RenderFromLayout(ctx, layout, g, opts)
 	r.pipelineHooksCtx(ctx).OnRenderComplete(ctx, opts.Formats, time.Since(start), err)
 	if err != nil {
 		return nil, false, err
@@ -388,8 +418,8 @@ func (r *Runner) Render(ctx context.Context, layout graph.Layout, g *dag.DAG, op
 // When opts.ShowLicenses is true, license risk analysis is run and annotated.
 // When opts.ShowLicenses is false, any existing license risk metadata is stripped.
 func (r *Runner) PrepareGraph(g *dag.DAG, opts Options) (*dag.DAG, error) {
-	normalize := opts.Normalize && !opts.IsNodelink()
-	needsClone := normalize || !opts.ShowVulns || opts.ShowLicenses
+	normalize := opts.Normalize && opts.IsTower()
+	needsClone := normalize || !opts.ShowVulns || opts.ShowLicenses || opts.Health != nil
 
 	if !needsClone {
 		return g, nil
@@ -397,6 +427,11 @@ func (r *Runner) PrepareGraph(g *dag.DAG, opts Options) (*dag.DAG, error) {
 
 	workGraph := g.Clone()
 
+	// Rescore with custom weights before vulnerability data may be stripped.
+	if opts.Health != nil {
+		feature.AnnotateHealth(workGraph, *opts.Health, time.Now(), opts.brittleThresholds())
+	}
+
 	if !opts.ShowVulns {
 		security.StripVulnData(workGraph)
 	}
@@ -419,7 +454,7 @@ func (r *Runner) PrepareGraph(g *dag.DAG, opts Options) (*dag.DAG, error) {
 	}
 
 	if normalize {
-		if _, err := dagtransform.Normalize(workGraph); err != nil {
+		if _, err := dagtransform.NormalizeWithOptions(workGraph, dagtransform.NormalizeOptions{FixedRows: opts.FixedRows}); err != nil {
 			return nil, fmt.Errorf("normalize graph: %w", err)
 		}
 		r.Logger.Debug("normalized graph",
@@ -484,6 +519,7 @@ func (r *Runner) pipelineHooksCtx(ctx context.Context) observability.PipelineHoo
 func (r *Runner) NewOptimalOrderer(timeout time.Duration) *OrdererWithHooks {
 	return &OrdererWithHooks{
 		Timeout: timeout,
+		Logger:  r.Logger,
 		hooks:   r.pipelineHooksCtx(context.Background()),
 	}
 }
@@ -491,6 +527,9 @@ func (r *Runner) NewOptimalOrderer(timeout time.Duration) *OrdererWithHooks {
 // OrdererWithHooks wraps ordering.OptimalSearch with hooks-based progress reporting.
 type OrdererWithHooks struct {
 	Timeout   time.Duration
+	Restarts  int                  // see ordering.OptimalSearch
+	Seed      uint64               // see ordering.OptimalSearch
+	Logger    observability.Logger // receives the search's outcome; nil discards it
 	hooks     observability.PipelineHooks
 	startTime time.Time
 	rowCount  int
@@ -498,20 +537,29 @@ type OrdererWithHooks struct {
 
 // OrderRows implements ordering.Orderer.
 func (o *OrdererWithHooks) OrderRows(g *dag.DAG) map[int][]string {
+	return o.OrderRowsContext(context.Background(), g)
+}
+
+// OrderRowsContext implements ordering.ContextOrderer. The search stops
+// with the best ordering found so far when ctx is done.
+func (o *OrdererWithHooks) OrderRowsContext(ctx context.Context, g *dag.DAG) map[int][]string {
 	o.startTime = time.Now()
 	o.rowCount = g.RowCount()
 
-	o.hooks.OnOrderingStart(context.Background(), "optimal", o.rowCount)
+	o.hooks.OnOrderingStart(ctx, "optimal", o.rowCount)
 
 	search := ordering.OptimalSearch{
 		Timeout:  o.Timeout,
+		Restarts: o.Restarts,
+		Seed:     o.Seed,
 		Progress: o.onProgress,
+		Logger:   o.Logger,
 	}
 
-	result := search.OrderRows(g)
+	result := search.OrderRowsContext(ctx, g)
 	crossings := dag.CountCrossings(g, result)
 
-	o.hooks.OnOrderingComplete(context.Background(), crossings, time.Since(o.startTime))
+	o.hooks.OnOrderingComplete(ctx, crossings, time.Since(o.startTime))
 
 	return result
 }
@@ -4,13 +4,13 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"log/slog"
 	"strings"
 	"testing"
 	"time"
 
-	"github.com/charmbracelet/log"
-
 	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
 )
 
 type failingSetCache struct {
@@ -26,9 +26,23 @@ func (c *failingSetCache) Close() error                         { return nil }
 
 var _ cache.Cache = (*failingSetCache)(nil)
 
+// mapCache is an in-memory cache.Cache.
+type mapCache map[string][]byte
+
+func (c mapCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	data, ok := c[key]
+	return data, ok, nil
+}
+func (c mapCache) Set(_ context.Context, key string, data []byte, _ time.Duration) error {
+	c[key] = data
+	return nil
+}
+func (c mapCache) Delete(_ context.Context, key string) error { delete(c, key); return nil }
+func (c mapCache) Close() error                               { return nil }
+
 func TestSetCacheWithWarningLogsFailure(t *testing.T) {
 	var out bytes.Buffer
-	logger := log.NewWithOptions(&out, log.Options{Level: log.DebugLevel})
+	logger := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
 	runner := NewRunner(&failingSetCache{setErr: errors.New("disk full")}, nil, logger)
 
 	runner.setCacheWithWarning(context.Background(), "graph:key", []byte("data"), time.Minute, "parse")
@@ -41,3 +55,56 @@ func TestSetCacheWithWarningLogsFailure(t *testing.T) {
 		t.Fatalf("expected stage field, got %q", got)
 	}
 }
+
+func TestPrepareGraph_FixedRows(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "core", Row: 2})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "core"})
+
+	runner := NewRunner(cache.NewNullCache(), nil, slog.New(slog.DiscardHandler))
+	opts := Options{VizType: DefaultVizType, Normalize: true, ShowVulns: true, FixedRows: true}
+	work, err := runner.PrepareGraph(g, opts)
+	if err != nil {
+		t.Fatalf("PrepareGraph() error = %v", err)
+	}
+	if n, _ := work.Node("core"); n.Row != 2 {
+		t.Errorf("core row = %d, want the fixed row 2", n.Row)
+	}
+
+	_ = g.AddNode(dag.Node{ID: "top", Row: 3})
+	_ = g.AddEdge(dag.Edge{From: "top", To: "core"})
+	if _, err := runner.PrepareGraph(g, opts); err == nil {
+		t.Error("expected an error for rows that cannot be kept")
+	}
+}
+
+func TestParseWithCacheInfo_BrittleThresholdsInKey(t *testing.T) {
+	runner := NewRunner(mapCache{}, nil, slog.New(slog.DiscardHandler))
+	opts := Options{
+		Language:         "rust",
+		Manifest:         "version = 3\n\n[[package]]\nname = \"app\"\nversion = \"0.1.0\"\n",
+		ManifestFilename: "Cargo.lock",
+		SkipEnrich:       true,
+	}
+	parse := func(staleAfterDays int) bool {
+		t.Helper()
+		opts.StaleAfterDays = staleAfterDays
+		result, err := runner.ParseWithCacheInfo(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("ParseWithCacheInfo() error = %v", err)
+		}
+		return result.CacheHit
+	}
+
+	if parse(30) {
+		t.Fatal("first parse should miss the cache")
+	}
+	// Health is scored before caching, so other thresholds need another graph
+	if parse(90) {
+		t.Error("parse with other brittleness thresholds reused the cached graph")
+	}
+	if !parse(30) {
+		t.Error("parse with the same thresholds should hit the cache")
+	}
+}
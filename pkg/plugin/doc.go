@@ -0,0 +1,45 @@
+// Package plugin runs external executables that add languages, manifest
+// parsers, metadata providers and output formats to Stacktower, so third
+// parties can extend it without forking.
+//
+// # Discovery
+//
+// A plugin is an executable whose name starts with [Prefix], such as
+// stacktower-plugin-cobol. [Discover] looks for them in the given
+// directories (the CLI passes ~/.config/stacktower/plugins, then every
+// directory on PATH); the first plugin with a given name wins.
+//
+// # Protocol
+//
+// Stacktower runs the plugin with a command as its only argument, writes
+// the JSON request to its stdin, and reads the response from its stdout.
+// A non-zero exit status is an error; the plugin's stderr becomes the
+// error message. Version 1 of the protocol has these commands:
+//
+//	describe        no request; responds with a [Manifest]
+//	resolve         [ResolveRequest]; responds with a graph JSON document
+//	parse-manifest  [ManifestRequest]; responds with a [ManifestResponse]
+//	enrich          [EnrichRequest]; responds with an [EnrichResponse]
+//	render          [RenderRequest]; responds with the raw artifact bytes
+//
+// Plugins only need to implement the commands for the capabilities their
+// manifest declares. For example, a plugin adding a language:
+//
+//	$ stacktower-plugin-cobol describe
+//	{"protocol": 1, "name": "cobol", "version": "0.1.0",
+//	 "languages": [{"name": "cobol", "registry": "copyhub",
+//	                "manifests": {"copybook.lock": "copybook-lock"}}]}
+//
+// A language may share a manifest filename with a built-in one when it
+// lists Markers, text that files of its own contain; see
+// [deps.DetectManifests].
+//
+// Graphs are exchanged in the same JSON format as `stacktower parse`
+// writes, so plugins can reuse any tooling that produces it.
+//
+// # Registration
+//
+// [Register] adds the capabilities of discovered plugins to
+// [languages.All] and the [pipeline] extension points, after which the CLI
+// treats them like built-in ones.
+package plugin
@@ -0,0 +1,194 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+// ResolveRequest asks a plugin to resolve a package from its registry.
+type ResolveRequest struct {
+	Language          string `json:"language"`
+	Package           string `json:"package"`
+	Version           string `json:"version,omitempty"`
+	MaxDepth          int    `json:"max_depth"`
+	MaxNodes          int    `json:"max_nodes"`
+	DependencyScope   string `json:"dependency_scope,omitempty"`
+	IncludePrerelease bool   `json:"include_prerelease,omitempty"`
+	RuntimeVersion    string `json:"runtime_version,omitempty"`
+	Refresh           bool   `json:"refresh,omitempty"`
+}
+
+// ManifestRequest asks a plugin to parse a manifest file.
+type ManifestRequest struct {
+	Language          string `json:"language"`
+	Type              string `json:"type"` // manifest type, a value of Language.Manifests
+	Path              string `json:"path"` // absolute path of the manifest
+	MaxDepth          int    `json:"max_depth"`
+	MaxNodes          int    `json:"max_nodes"`
+	DependencyScope   string `json:"dependency_scope,omitempty"`
+	IncludePrerelease bool   `json:"include_prerelease,omitempty"`
+	RuntimeVersion    string `json:"runtime_version,omitempty"`
+}
+
+// ManifestResponse is a parsed manifest.
+type ManifestResponse struct {
+	Graph              json.RawMessage `json:"graph"`
+	RootPackage        string          `json:"root_package,omitempty"`
+	IncludesTransitive bool            `json:"includes_transitive,omitempty"`
+	RuntimeVersion     string          `json:"runtime_version,omitempty"`
+	RuntimeConstraint  string          `json:"runtime_constraint,omitempty"`
+}
+
+// languagesOf returns the languages a plugin adds.
+func languagesOf(p *Plugin) []*deps.Language {
+	langs := make([]*deps.Language, 0, len(p.Languages))
+	for _, l := range p.Languages {
+		registry := l.Registry
+		if registry == "" {
+			registry = l.Name
+		}
+		lang := &deps.Language{
+			Name:                  l.Name,
+			DefaultRegistry:       registry,
+			DefaultRuntimeVersion: l.RuntimeVersion,
+			NewResolver: func(cache.Cache, deps.Options) (deps.Resolver, error) {
+				return &resolver{plugin: p, language: l.Name, registry: registry}, nil
+			},
+		}
+		if len(l.Manifests) > 0 {
+			lang.ManifestAliases = maps.Clone(l.Manifests)
+			lang.ManifestTypes = slices.Sorted(maps.Values(l.Manifests))
+			lang.ManifestTypes = slices.Compact(lang.ManifestTypes)
+			lang.NewManifest = func(name string, _ deps.Resolver) deps.ManifestParser {
+				if !slices.Contains(lang.ManifestTypes, name) {
+					return nil
+				}
+				return &manifestParser{plugin: p, language: l.Name, typ: name, files: l.Manifests}
+			}
+			lang.ManifestParsers = func(deps.Resolver) []deps.ManifestParser {
+				parsers := make([]deps.ManifestParser, len(lang.ManifestTypes))
+				for i, typ := range lang.ManifestTypes {
+					parsers[i] = &manifestParser{plugin: p, language: l.Name, typ: typ, files: l.Manifests}
+				}
+				return parsers
+			}
+		}
+		if len(l.Markers) > 0 {
+			lang.SniffManifest = markerSniffer(l.Manifests, l.Markers)
+		}
+		langs = append(langs, lang)
+	}
+	return langs
+}
+
+// markerSniffer returns a [deps.ManifestSniffer] that is confident about
+// marked manifests holding their marker and doubtful about those that do
+// not, so that a built-in language reading the same filename wins them.
+func markerSniffer(files, markers map[string]string) deps.ManifestSniffer {
+	return func(filename string, content []byte) (string, float64) {
+		marker, ok := markers[filename]
+		if !ok || files[filename] == "" {
+			return "", 0
+		}
+		if bytes.Contains(content, []byte(marker)) {
+			return files[filename], 0.9
+		}
+		return files[filename], 0.1
+	}
+}
+
+// resolver resolves registry packages through a plugin.
+type resolver struct {
+	plugin   *Plugin
+	language string
+	registry string
+}
+
+func (r *resolver) Name() string { return r.registry }
+
+func (r *resolver) Resolve(ctx context.Context, pkg string, opts deps.Options) (*dag.DAG, error) {
+	pkg, opts = deps.WithPackageSpec(pkg, opts.WithDefaults())
+	out, err := r.plugin.run(ctx, "resolve", ResolveRequest{
+		Language:          r.language,
+		Package:           pkg,
+		Version:           opts.Version,
+		MaxDepth:          opts.MaxDepth,
+		MaxNodes:          opts.MaxNodes,
+		DependencyScope:   opts.DependencyScope,
+		IncludePrerelease: opts.IncludePrerelease,
+		RuntimeVersion:    opts.RuntimeVersion,
+		Refresh:           opts.Refresh,
+	})
+	if err != nil {
+		return nil, err
+	}
+	g, err := graph.ReadGraph(bytes.NewReader(out))
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: resolve: invalid graph: %w", r.plugin.Name, err)
+	}
+	deps.EnrichGraph(ctx, g, "", opts)
+	return g, nil
+}
+
+// manifestParser parses one manifest type through a plugin.
+type manifestParser struct {
+	plugin   *Plugin
+	language string
+	typ      string
+	files    map[string]string // filename → manifest type
+}
+
+func (m *manifestParser) Type() string { return m.typ }
+
+func (m *manifestParser) Supports(filename string) bool {
+	return m.files[filepath.Base(filename)] == m.typ
+}
+
+// IncludesTransitive reports false: the plugin says per parse whether its
+// graph is complete, in ManifestResponse.IncludesTransitive.
+func (m *manifestParser) IncludesTransitive() bool { return false }
+
+func (m *manifestParser) Parse(path string, opts deps.Options) (*deps.ManifestResult, error) {
+	opts = opts.WithDefaults()
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	var resp ManifestResponse
+	err = m.plugin.call(opts.Ctx, "parse-manifest", ManifestRequest{
+		Language:          m.language,
+		Type:              m.typ,
+		Path:              abs,
+		MaxDepth:          opts.MaxDepth,
+		MaxNodes:          opts.MaxNodes,
+		DependencyScope:   opts.DependencyScope,
+		IncludePrerelease: opts.IncludePrerelease,
+		RuntimeVersion:    opts.RuntimeVersion,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	g, err := graph.ReadGraph(bytes.NewReader(resp.Graph))
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: parse-manifest: invalid graph: %w", m.plugin.Name, err)
+	}
+	deps.EnrichGraph(opts.Ctx, g, filepath.Base(path), opts)
+	return &deps.ManifestResult{
+		Graph:              g,
+		Type:               m.typ,
+		IncludesTransitive: resp.IncludesTransitive,
+		RootPackage:        resp.RootPackage,
+		RuntimeVersion:     resp.RuntimeVersion,
+		RuntimeConstraint:  resp.RuntimeConstraint,
+	}, nil
+}
@@ -0,0 +1,167 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Prefix starts the file name of every plugin executable.
+const Prefix = "stacktower-plugin-"
+
+// ProtocolVersion is the version of the plugin protocol this package speaks.
+const ProtocolVersion = 1
+
+// Timeouts for plugin calls. CallTimeout caps enrichment and rendering
+// within the caller's context; resolution and parsing follow the caller's
+// context alone.
+const (
+	DescribeTimeout = 5 * time.Second
+	CallTimeout     = 5 * time.Minute
+)
+
+// Manifest is a plugin's answer to the describe command: who it is and
+// what it adds.
+type Manifest struct {
+	Protocol  int        `json:"protocol"`
+	Name      string     `json:"name"`
+	Version   string     `json:"version,omitempty"`
+	Languages []Language `json:"languages,omitempty"`
+	Providers []string   `json:"providers,omitempty"` // metadata provider names
+	Sinks     []string   `json:"sinks,omitempty"`     // output formats
+}
+
+// Language describes a language ecosystem added by a plugin.
+type Language struct {
+	Name           string `json:"name"`
+	Registry       string `json:"registry,omitempty"` // defaults to the language name
+	RuntimeVersion string `json:"runtime_version,omitempty"`
+
+	// Manifests maps manifest filenames to manifest types, like
+	// {"copybook.lock": "copybook-lock"}.
+	Manifests map[string]string `json:"manifests,omitempty"`
+
+	// Markers maps manifest filenames to text their content must hold to
+	// be this language's, like {"package.json": "\"deno\""}. A marked
+	// filename may be one a built-in language also reads; detection then
+	// picks the plugin only for files that contain the marker.
+	Markers map[string]string `json:"markers,omitempty"`
+}
+
+// Plugin is a discovered plugin executable and its manifest.
+type Plugin struct {
+	Path string
+	Manifest
+}
+
+// Discover finds the plugins in dirs and asks each for its manifest.
+// Plugins that fail to describe themselves are reported in the returned
+// errors and skipped, so one broken plugin does not disable the rest.
+func Discover(dirs []string) ([]*Plugin, []error) {
+	var (
+		plugins []*Plugin
+		errs    []error
+		seen    = map[string]bool{}
+	)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue // missing and unreadable directories are common on PATH
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if !strings.HasPrefix(name, Prefix) || seen[name] || !isExecutable(filepath.Join(dir, name)) {
+				continue
+			}
+			seen[name] = true
+			p, err := Describe(filepath.Join(dir, name))
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			plugins = append(plugins, p)
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins, errs
+}
+
+// Describe runs the describe command of the plugin at path.
+func Describe(path string) (*Plugin, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DescribeTimeout)
+	defer cancel()
+
+	p := &Plugin{Path: path}
+	if err := p.call(ctx, "describe", nil, &p.Manifest); err != nil {
+		return nil, err
+	}
+	if p.Protocol != ProtocolVersion {
+		return nil, fmt.Errorf("plugin %s: unsupported protocol %d (want %d)", filepath.Base(path), p.Protocol, ProtocolVersion)
+	}
+	if p.Name == "" {
+		return nil, fmt.Errorf("plugin %s: manifest has no name", filepath.Base(path))
+	}
+	return p, nil
+}
+
+// call runs command with req as JSON on stdin and decodes the JSON
+// response into resp.
+func (p *Plugin) call(ctx context.Context, command string, req, resp any) error {
+	out, err := p.run(ctx, command, req)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(out, resp); err != nil {
+		return fmt.Errorf("plugin %s: %s: invalid response: %w", p.label(), command, err)
+	}
+	return nil
+}
+
+// run runs command with req as JSON on stdin and returns its stdout.
+func (p *Plugin) run(ctx context.Context, command string, req any) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, p.Path, command)
+	if req != nil {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: %s: encode request: %w", p.label(), command, err)
+		}
+		cmd.Stdin = bytes.NewReader(data)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("plugin %s: %s: %w", p.label(), command, ctx.Err())
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("plugin %s: %s: %s", p.label(), command, msg)
+		}
+		return nil, fmt.Errorf("plugin %s: %s: %w", p.label(), command, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// label names the plugin in errors, before and after its manifest is read.
+func (p *Plugin) label() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return filepath.Base(p.Path)
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return info.Mode()&0o111 != 0
+}
@@ -0,0 +1,174 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+)
+
+const fakePlugin = `#!/bin/sh
+case "$1" in
+describe)
+	echo '{"protocol": 1, "name": "demo", "version": "0.1.0",
+	  "languages": [{"name": "cobol", "registry": "copyhub", "manifests": {"copybook.lock": "copybook-lock"}}],
+	  "providers": ["demo-meta"], "sinks": ["txt"]}' ;;
+resolve)
+	cat >/dev/null
+	echo '{"nodes": [{"id": "app"}, {"id": "lib"}], "edges": [{"from": "app", "to": "lib"}]}' ;;
+parse-manifest)
+	cat >/dev/null
+	echo '{"graph": {"nodes": [{"id": "__project__"}, {"id": "lib"}], "edges": [{"from": "__project__", "to": "lib"}]},
+	  "root_package": "myapp", "includes_transitive": true}' ;;
+enrich)
+	cat >/dev/null
+	echo '{"metadata": {"lib": {"demo_score": 5}}}' ;;
+render)
+	grep -o '"format":"[a-z]*"' ;;
+*)
+	echo "unknown command $1" >&2
+	exit 1 ;;
+esac
+`
+
+// writePlugin writes an executable plugin script into dir.
+func writePlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func setupPlugins(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts need a POSIX shell")
+	}
+	dir := t.TempDir()
+	writePlugin(t, dir, Prefix+"demo", fakePlugin)
+	writePlugin(t, dir, Prefix+"old", "#!/bin/sh\necho '{\"protocol\": 0, \"name\": \"old\"}'\n")
+	if err := os.WriteFile(filepath.Join(dir, Prefix+"notexec"), []byte("#!/bin/sh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writePlugin(t, dir, "unrelated", "#!/bin/sh\nexit 1\n")
+	return dir
+}
+
+func TestDiscover(t *testing.T) {
+	dir := setupPlugins(t)
+	plugins, errs := Discover([]string{filepath.Join(dir, "missing"), dir})
+
+	if len(plugins) != 1 || plugins[0].Name != "demo" || plugins[0].Version != "0.1.0" {
+		t.Fatalf("Discover() = %+v, want the demo plugin", plugins)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "unsupported protocol 0") {
+		t.Errorf("Discover() errors = %v, want one for the old protocol", errs)
+	}
+}
+
+func TestPluginLanguage(t *testing.T) {
+	dir := setupPlugins(t)
+	p, err := Describe(filepath.Join(dir, Prefix+"demo"))
+	if err != nil {
+		t.Fatalf("Describe() error: %v", err)
+	}
+	langs := languagesOf(p)
+	if len(langs) != 1 || langs[0].Name != "cobol" || langs[0].DefaultRegistry != "copyhub" {
+		t.Fatalf("languagesOf() = %+v", langs)
+	}
+	lang := langs[0]
+
+	res, err := lang.Resolver(nil, deps.Options{})
+	if err != nil {
+		t.Fatalf("Resolver() error: %v", err)
+	}
+	g, err := res.Resolve(context.Background(), "app", deps.Options{})
+	if err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+	if g.NodeCount() != 2 || g.EdgeCount() != 1 {
+		t.Errorf("Resolve() = %d nodes, %d edges; want 2, 1", g.NodeCount(), g.EdgeCount())
+	}
+
+	parser, ok := lang.Manifest("copybook-lock", res)
+	if !ok || !parser.Supports("some/dir/copybook.lock") || parser.Supports("package.json") {
+		t.Fatalf("Manifest(copybook-lock) = %v, %v", parser, ok)
+	}
+	result, err := parser.Parse(filepath.Join(dir, "copybook.lock"), deps.Options{})
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if result.RootPackage != "myapp" || !result.IncludesTransitive || result.Graph.NodeCount() != 2 {
+		t.Errorf("Parse() = %+v", result)
+	}
+}
+
+func TestMarkerSniffer(t *testing.T) {
+	sniff := markerSniffer(
+		map[string]string{"deno.json": "deno", "package.json": "package"},
+		map[string]string{"package.json": `"deno"`},
+	)
+	tests := []struct {
+		filename, content string
+		wantType          string
+		wantConfidence    float64
+	}{
+		{"package.json", `{"deno": {"tasks": {}}}`, "package", 0.9},
+		{"package.json", `{"dependencies": {}}`, "package", 0.1},
+		{"deno.json", `{}`, "", 0},
+	}
+	for _, tt := range tests {
+		typ, confidence := sniff(tt.filename, []byte(tt.content))
+		if typ != tt.wantType || confidence != tt.wantConfidence {
+			t.Errorf("sniff(%s, %s) = %q, %v; want %q, %v", tt.filename, tt.content, typ, confidence, tt.wantType, tt.wantConfidence)
+		}
+	}
+}
+
+func TestPluginProviderAndSink(t *testing.T) {
+	dir := setupPlugins(t)
+	p, err := Describe(filepath.Join(dir, Prefix+"demo"))
+	if err != nil {
+		t.Fatalf("Describe() error: %v", err)
+	}
+
+	providers := providersOf(p)
+	if len(providers) != 1 || providers[0].Name() != "demo-meta" {
+		t.Fatalf("providersOf() = %v", providers)
+	}
+	meta, err := providers[0].Enrich(context.Background(), &deps.PackageRef{Name: "lib"}, false)
+	if err != nil || meta["demo_score"] != float64(5) {
+		t.Errorf("Enrich() = %v, %v", meta, err)
+	}
+
+	sinks := sinksOf(p)
+	if len(sinks) != 1 || sinks[0].Format() != "txt" {
+		t.Fatalf("sinksOf() = %v", sinks)
+	}
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	out, err := sinks[0].Render(context.Background(), g, []byte("<svg/>"), pipeline.Options{})
+	if err != nil || strings.TrimSpace(string(out)) != `"format":"txt"` {
+		t.Errorf("Render() = %q, %v", out, err)
+	}
+}
+
+func TestPluginErrorUsesStderr(t *testing.T) {
+	dir := setupPlugins(t)
+	p, err := Describe(filepath.Join(dir, Prefix+"demo"))
+	if err != nil {
+		t.Fatalf("Describe() error: %v", err)
+	}
+	_, err = p.run(context.Background(), "bogus", nil)
+	if err == nil || !strings.Contains(err.Error(), "plugin demo: bogus: unknown command bogus") {
+		t.Errorf("run(bogus) error = %v", err)
+	}
+}
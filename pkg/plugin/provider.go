@@ -0,0 +1,75 @@
+package plugin
+
+import (
+	"context"
+
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+)
+
+// EnrichRequest asks a plugin for metadata on a batch of packages.
+type EnrichRequest struct {
+	Provider string          `json:"provider"`
+	Packages []EnrichPackage `json:"packages"`
+	Refresh  bool            `json:"refresh,omitempty"`
+}
+
+// EnrichPackage identifies a package to enrich, as [deps.PackageRef] does.
+type EnrichPackage struct {
+	Name        string            `json:"name"`
+	Version     string            `json:"version,omitempty"`
+	ProjectURLs map[string]string `json:"project_urls,omitempty"`
+	HomePage    string            `json:"home_page,omitempty"`
+	Manifest    string            `json:"manifest,omitempty"`
+}
+
+// EnrichResponse maps package names to the metadata to merge into their
+// nodes. Packages the plugin knows nothing about are left out.
+type EnrichResponse struct {
+	Metadata map[string]map[string]any `json:"metadata"`
+}
+
+// providersOf returns the metadata providers a plugin adds.
+func providersOf(p *Plugin) []deps.MetadataProvider {
+	providers := make([]deps.MetadataProvider, len(p.Providers))
+	for i, name := range p.Providers {
+		providers[i] = &provider{plugin: p, name: name}
+	}
+	return providers
+}
+
+// provider enriches packages through a plugin, one call per batch.
+type provider struct {
+	plugin *Plugin
+	name   string
+}
+
+func (e *provider) Name() string { return e.name }
+
+func (e *provider) Enrich(ctx context.Context, pkg *deps.PackageRef, refresh bool) (map[string]any, error) {
+	meta, err := e.EnrichBatch(ctx, []*deps.PackageRef{pkg}, refresh)
+	if err != nil {
+		return nil, err
+	}
+	return meta[pkg.Name], nil
+}
+
+func (e *provider) EnrichBatch(ctx context.Context, pkgs []*deps.PackageRef, refresh bool) (map[string]map[string]any, error) {
+	ctx, cancel := context.WithTimeout(ctx, CallTimeout)
+	defer cancel()
+
+	req := EnrichRequest{Provider: e.name, Packages: make([]EnrichPackage, len(pkgs)), Refresh: refresh}
+	for i, pkg := range pkgs {
+		req.Packages[i] = EnrichPackage{
+			Name:        pkg.Name,
+			Version:     pkg.Version,
+			ProjectURLs: pkg.ProjectURLs,
+			HomePage:    pkg.HomePage,
+			Manifest:    pkg.ManifestFile,
+		}
+	}
+	var resp EnrichResponse
+	if err := e.plugin.call(ctx, "enrich", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Metadata, nil
+}
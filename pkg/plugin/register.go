@@ -0,0 +1,32 @@
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/stacktower-io/stacktower/pkg/core/deps/languages"
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+)
+
+// Register adds the languages, metadata providers and output formats of
+// plugins to the CLI's registries. A capability that clashes with a
+// built-in one or an earlier plugin is skipped and reported, and the rest
+// are still registered.
+func Register(plugins []*Plugin) []error {
+	var errs []error
+	for _, p := range plugins {
+		for _, lang := range languagesOf(p) {
+			if err := languages.Register(lang); err != nil {
+				errs = append(errs, fmt.Errorf("plugin %s: %w", p.Name, err))
+			}
+		}
+		for _, prov := range providersOf(p) {
+			pipeline.RegisterMetadataProvider(prov)
+		}
+		for _, s := range sinksOf(p) {
+			if err := pipeline.RegisterSink(s); err != nil {
+				errs = append(errs, fmt.Errorf("plugin %s: %w", p.Name, err))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,50 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+)
+
+// RenderRequest asks a plugin to render a graph into its output format.
+type RenderRequest struct {
+	Format  string           `json:"format"`
+	Graph   json.RawMessage  `json:"graph"`
+	SVG     string           `json:"svg"` // the built-in SVG rendering with the same options
+	Options pipeline.Options `json:"options"`
+}
+
+// sinksOf returns the output formats a plugin adds.
+func sinksOf(p *Plugin) []pipeline.Sink {
+	sinks := make([]pipeline.Sink, len(p.Sinks))
+	for i, format := range p.Sinks {
+		sinks[i] = &sink{plugin: p, format: format}
+	}
+	return sinks
+}
+
+// sink renders an output format through a plugin.
+type sink struct {
+	plugin *Plugin
+	format string
+}
+
+func (s *sink) Format() string { return s.format }
+
+func (s *sink) Render(ctx context.Context, g *dag.DAG, svg []byte, opts pipeline.Options) ([]byte, error) {
+	data, err := graph.MarshalGraph(g)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, CallTimeout)
+	defer cancel()
+	return s.plugin.run(ctx, "render", RenderRequest{
+		Format:  s.format,
+		Graph:   data,
+		SVG:     string(svg),
+		Options: opts,
+	})
+}
@@ -0,0 +1,22 @@
+// Package policy evaluates dependency graphs against CI thresholds.
+//
+// A [Policy] is a small set of rules, usually loaded from YAML with [Parse]:
+//
+//	max_brittle: 3          # at most three brittle dependencies
+//	fail_on_vuln: critical  # no critical advisories (use high to fail on high too)
+//	min_health: 40          # every scored dependency has a health score of 40+
+//	deny_licenses: [GPL-*, AGPL-*]
+//	deny_license_risk: [proprietary]
+//	max_depth: 8            # no dependency chain longer than eight
+//	max_nodes: 300          # at most 300 direct and transitive dependencies
+//
+// [Evaluate] checks each configured rule against a graph and returns a
+// [Result] listing every rule with its verdict and the packages that broke
+// it. Rules that are not configured are not evaluated, so an empty policy
+// always passes.
+//
+// Rules only consider dependencies: the root package, the synthetic
+// "__project__" node and subdividers are skipped. Vulnerability
+// rules need a graph parsed with --security-scan, and health rules use the
+// score stored by parse, falling back to scoring with the default weights.
+package policy
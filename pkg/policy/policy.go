@@ -0,0 +1,377 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/render/tower/feature"
+	"github.com/stacktower-io/stacktower/pkg/security"
+)
+
+// Rule names, as reported in [RuleResult.Rule].
+const (
+	RuleMaxBrittle      = "max_brittle"
+	RuleFailOnVuln      = "fail_on_vuln"
+	RuleMinHealth       = "min_health"
+	RuleDenyLicenses    = "deny_licenses"
+	RuleDenyLicenseRisk = "deny_license_risk"
+	RuleMaxDepth        = "max_depth"
+	RuleMaxNodes        = "max_nodes"
+)
+
+// Policy is a set of CI thresholds. Zero fields disable their rule.
+type Policy struct {
+	// MaxBrittle is the most brittle dependencies allowed (see
+	// [feature.IsBrittle]). Nil disables the rule; 0 allows none.
+	MaxBrittle *int `json:"max_brittle,omitempty" yaml:"max_brittle,omitempty"`
+
+	// FailOnVuln is the lowest advisory severity that fails the check:
+	// "critical", "high", "medium" or "low".
+	FailOnVuln string `json:"fail_on_vuln,omitempty" yaml:"fail_on_vuln,omitempty"`
+
+	// MinHealth is the lowest health score (1–100) a dependency may have.
+	// Dependencies without a score are not checked.
+	MinHealth int `json:"min_health,omitempty" yaml:"min_health,omitempty"`
+
+	// DenyLicenses lists forbidden SPDX identifiers. Entries may use
+	// shell-style globs such as "GPL-*"; matching ignores case.
+	DenyLicenses []string `json:"deny_licenses,omitempty" yaml:"deny_licenses,omitempty"`
+
+	// DenyLicenseRisk lists forbidden license risk categories:
+	// "copyleft", "weak-copyleft", "proprietary" or "unknown".
+	DenyLicenseRisk []string `json:"deny_license_risk,omitempty" yaml:"deny_license_risk,omitempty"`
+
+	// MaxDepth is the longest dependency chain allowed below the root.
+	MaxDepth int `json:"max_depth,omitempty" yaml:"max_depth,omitempty"`
+
+	// MaxNodes is the most dependencies, direct and transitive, allowed.
+	MaxNodes int `json:"max_nodes,omitempty" yaml:"max_nodes,omitempty"`
+}
+
+// Parse reads a YAML (or JSON) policy and validates it.
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse policy: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Validate reports thresholds and names that cannot be evaluated.
+func (p Policy) Validate() error {
+	if p.MaxBrittle != nil && *p.MaxBrittle < 0 {
+		return fmt.Errorf("max_brittle must not be negative, got %d", *p.MaxBrittle)
+	}
+	if p.FailOnVuln != "" && security.SeverityFromString(p.FailOnVuln).Weight() == 0 {
+		return fmt.Errorf("invalid fail_on_vuln %q (want critical, high, medium, or low)", p.FailOnVuln)
+	}
+	if p.MinHealth < 0 || p.MinHealth > 100 {
+		return fmt.Errorf("min_health must be between 0 and 100, got %d", p.MinHealth)
+	}
+	for _, l := range p.DenyLicenses {
+		if _, err := path.Match(strings.ToLower(l), ""); err != nil {
+			return fmt.Errorf("invalid deny_licenses pattern %q: %w", l, err)
+		}
+	}
+	for _, r := range p.DenyLicenseRisk {
+		if risk := security.LicenseRiskFromString(r); risk == "" || risk == security.LicenseRiskPermissive {
+			return fmt.Errorf("invalid deny_license_risk %q (want copyleft, weak-copyleft, proprietary, or unknown)", r)
+		}
+	}
+	if p.MaxDepth < 0 {
+		return fmt.Errorf("max_depth must not be negative, got %d", p.MaxDepth)
+	}
+	if p.MaxNodes < 0 {
+		return fmt.Errorf("max_nodes must not be negative, got %d", p.MaxNodes)
+	}
+	return nil
+}
+
+// IsEmpty reports whether the policy has no rules.
+func (p Policy) IsEmpty() bool {
+	return p.MaxBrittle == nil && p.FailOnVuln == "" && p.MinHealth == 0 &&
+		len(p.DenyLicenses) == 0 && len(p.DenyLicenseRisk) == 0 &&
+		p.MaxDepth == 0 && p.MaxNodes == 0
+}
+
+// Result is the outcome of evaluating a policy.
+type Result struct {
+	Passed  bool         `json:"passed"`
+	Summary Summary      `json:"summary"`
+	Rules   []RuleResult `json:"rules"`
+}
+
+// Summary describes the evaluated graph, whichever rules are configured.
+type Summary struct {
+	Root         string `json:"root,omitempty"`
+	Dependencies int    `json:"dependencies"`
+	Direct       int    `json:"direct"`
+	MaxDepth     int    `json:"max_depth"`
+	Brittle      int    `json:"brittle"`
+	Vulnerable   int    `json:"vulnerable"`
+}
+
+// Failed returns the rules that did not pass.
+func (r Result) Failed() []RuleResult {
+	var out []RuleResult
+	for _, rr := range r.Rules {
+		if !rr.Passed {
+			out = append(out, rr)
+		}
+	}
+	return out
+}
+
+// RuleResult is the verdict for one rule.
+type RuleResult struct {
+	Rule   string `json:"rule"`
+	Passed bool   `json:"passed"`
+	// Limit is the configured threshold and Actual what the graph has, both
+	// in human-readable form (e.g. "≤ 3" and "5 brittle").
+	Limit      string      `json:"limit"`
+	Actual     string      `json:"actual"`
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+// Violation is one package breaking a rule.
+type Violation struct {
+	Package string `json:"package"`
+	Detail  string `json:"detail"`
+}
+
+// Evaluate checks every configured rule of p against g. now anchors the
+// health score for packages that do not carry one.
+func Evaluate(g *dag.DAG, p Policy, now time.Time) Result {
+	nodes := dependencies(g)
+	depth := depths(g)
+	res := Result{Passed: true, Summary: summarize(g, nodes, depth)}
+	add := func(rr RuleResult) {
+		rr.Passed = len(rr.Violations) == 0
+		res.Passed = res.Passed && rr.Passed
+		res.Rules = append(res.Rules, rr)
+	}
+
+	if p.MaxBrittle != nil {
+		var brittle []Violation
+		for _, n := range nodes {
+			if feature.IsBrittle(n) {
+				brittle = append(brittle, Violation{Package: n.ID, Detail: "brittle"})
+			}
+		}
+		rr := RuleResult{
+			Rule:   RuleMaxBrittle,
+			Limit:  fmt.Sprintf("≤ %d", *p.MaxBrittle),
+			Actual: fmt.Sprintf("%d brittle", len(brittle)),
+		}
+		if len(brittle) > *p.MaxBrittle {
+			rr.Violations = brittle
+		}
+		add(rr)
+	}
+
+	if p.FailOnVuln != "" {
+		limit := security.SeverityFromString(p.FailOnVuln)
+		rr := RuleResult{Rule: RuleFailOnVuln, Limit: "below " + limit.String()}
+		for _, n := range nodes {
+			sev, _ := n.Meta[security.MetaVulnSeverity].(string)
+			if s := security.SeverityFromString(sev); sev != "" && s.Weight() >= limit.Weight() {
+				rr.Violations = append(rr.Violations, Violation{Package: n.ID, Detail: s.String() + " vulnerability"})
+			}
+		}
+		rr.Actual = fmt.Sprintf("%d at or above %s", len(rr.Violations), limit)
+		add(rr)
+	}
+
+	if p.MinHealth > 0 {
+		rr := RuleResult{Rule: RuleMinHealth, Limit: fmt.Sprintf("≥ %d", p.MinHealth)}
+		lowest, scored := 100, 0
+		for _, n := range nodes {
+			score, ok := feature.HealthScore(n)
+			if !ok {
+				h := feature.NodeHealth(n, feature.HealthConfig{}, now, feature.BrittleThresholds{})
+				score, ok = h.Score, h.Level != feature.HealthUnknown
+			}
+			if !ok {
+				continue
+			}
+			scored++
+			lowest = min(lowest, score)
+			if score < p.MinHealth {
+				rr.Violations = append(rr.Violations, Violation{Package: n.ID, Detail: fmt.Sprintf("health %d", score)})
+			}
+		}
+		rr.Actual = "no scored packages"
+		if scored > 0 {
+			rr.Actual = fmt.Sprintf("lowest %d", lowest)
+		}
+		add(rr)
+	}
+
+	if len(p.DenyLicenses) > 0 {
+		rr := RuleResult{Rule: RuleDenyLicenses, Limit: "none of " + strings.Join(p.DenyLicenses, ", ")}
+		for _, n := range nodes {
+			lic, _ := n.Meta[security.MetaLicense].(string)
+			if lic != "" && licenseDenied(lic, p.DenyLicenses) {
+				rr.Violations = append(rr.Violations, Violation{Package: n.ID, Detail: lic})
+			}
+		}
+		rr.Actual = fmt.Sprintf("%d denied", len(rr.Violations))
+		add(rr)
+	}
+
+	if len(p.DenyLicenseRisk) > 0 {
+		rr := RuleResult{Rule: RuleDenyLicenseRisk, Limit: "none " + strings.Join(p.DenyLicenseRisk, ", ")}
+		denied := make([]security.LicenseRisk, len(p.DenyLicenseRisk))
+		for i, r := range p.DenyLicenseRisk {
+			denied[i] = security.LicenseRiskFromString(r)
+		}
+		for _, n := range nodes {
+			lic, _ := n.Meta[security.MetaLicense].(string)
+			if risk := security.ClassifyLicense(lic); slices.Contains(denied, risk) {
+				detail := string(risk)
+				if lic != "" {
+					detail = lic + " (" + detail + ")"
+				}
+				rr.Violations = append(rr.Violations, Violation{Package: n.ID, Detail: detail})
+			}
+		}
+		rr.Actual = fmt.Sprintf("%d denied", len(rr.Violations))
+		add(rr)
+	}
+
+	if p.MaxDepth > 0 {
+		rr := RuleResult{
+			Rule:   RuleMaxDepth,
+			Limit:  fmt.Sprintf("≤ %d", p.MaxDepth),
+			Actual: fmt.Sprintf("depth %d", res.Summary.MaxDepth),
+		}
+		for _, n := range nodes {
+			if d := depth[n.ID]; d > p.MaxDepth {
+				rr.Violations = append(rr.Violations, Violation{Package: n.ID, Detail: fmt.Sprintf("depth %d", d)})
+			}
+		}
+		add(rr)
+	}
+
+	if p.MaxNodes > 0 {
+		rr := RuleResult{
+			Rule:   RuleMaxNodes,
+			Limit:  fmt.Sprintf("≤ %d", p.MaxNodes),
+			Actual: fmt.Sprintf("%d dependencies", len(nodes)),
+		}
+		if len(nodes) > p.MaxNodes {
+			rr.Violations = []Violation{{Package: dag.FindRoot(g), Detail: rr.Actual}}
+		}
+		add(rr)
+	}
+
+	return res
+}
+
+// summarize counts what [Summary] reports about g.
+func summarize(g *dag.DAG, nodes []*dag.Node, depth map[string]int) Summary {
+	s := Summary{Root: dag.FindRoot(g), Dependencies: len(nodes)}
+	if s.Root != "" {
+		s.Direct = len(g.Children(s.Root))
+	}
+	for _, n := range nodes {
+		s.MaxDepth = max(s.MaxDepth, depth[n.ID])
+		if feature.IsBrittle(n) {
+			s.Brittle++
+		}
+		if sev, _ := n.Meta[security.MetaVulnSeverity].(string); sev != "" {
+			s.Vulnerable++
+		}
+	}
+	return s
+}
+
+// depths returns the length of the longest path from the root to every
+// reachable node, which is how deep the node sits in a tower.
+func depths(g *dag.DAG) map[string]int {
+	root := dag.FindRoot(g)
+	if root == "" {
+		return nil
+	}
+	depth := map[string]int{root: 0}
+	queue := []string{root}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, child := range g.Children(id) {
+			if d, ok := depth[child]; !ok || depth[id]+1 > d {
+				depth[child] = depth[id] + 1
+				queue = append(queue, child)
+			}
+		}
+	}
+	return depth
+}
+
+// dependencies returns the graph's real dependencies sorted by ID, skipping
+// the root, the project marker and synthetic nodes as stats does.
+func dependencies(g *dag.DAG) []*dag.Node {
+	root := dag.FindRoot(g)
+	var out []*dag.Node
+	for _, n := range g.Nodes() {
+		if n.IsSynthetic() || n.ID == root || n.ID == "__project__" {
+			continue
+		}
+		out = append(out, n)
+	}
+	slices.SortFunc(out, func(a, b *dag.Node) int { return strings.Compare(a.ID, b.ID) })
+	return out
+}
+
+// licenseDenied reports whether a license expression is forbidden. In an
+// "A OR B" expression every alternative must be denied, since the consumer
+// may pick the other; in "A AND B" one denied term is enough.
+func licenseDenied(expr string, patterns []string) bool {
+	expr = strings.NewReplacer("(", " ", ")", " ").Replace(expr)
+	for _, alt := range splitFold(expr, " or ") {
+		denied := false
+		for _, term := range splitFold(alt, " and ") {
+			if matchAny(strings.TrimSpace(term), patterns) {
+				denied = true
+				break
+			}
+		}
+		if !denied {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAny(license string, patterns []string) bool {
+	license = strings.ToLower(license)
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.ToLower(p), license); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// splitFold splits s around a lowercase separator, ignoring case.
+func splitFold(s, sep string) []string {
+	var out []string
+	lower := strings.ToLower(s)
+	for {
+		i := strings.Index(lower, sep)
+		if i < 0 {
+			return append(out, s)
+		}
+		out = append(out, s[:i])
+		s, lower = s[i+len(sep):], lower[i+len(sep):]
+	}
+}
@@ -0,0 +1,136 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func testGraph() *dag.DAG {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "archived", Row: 1, Meta: dag.Metadata{
+		"repo_archived": true, "license": "MIT", "health_score": 20,
+	}})
+	_ = g.AddNode(dag.Node{ID: "vulnerable", Row: 1, Meta: dag.Metadata{
+		"vuln_severity": "high", "license": "GPL-3.0-only", "health_score": 70,
+	}})
+	_ = g.AddNode(dag.Node{ID: "dual", Row: 2, Meta: dag.Metadata{
+		"license": "GPL-2.0-only OR MIT", "health_score": 90,
+	}})
+	_ = g.AddNode(dag.Node{ID: "closed", Row: 2, Meta: dag.Metadata{"license": "BUSL-1.1"}})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "archived"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "vulnerable"})
+	_ = g.AddEdge(dag.Edge{From: "archived", To: "dual"})
+	_ = g.AddEdge(dag.Edge{From: "vulnerable", To: "closed"})
+	return g
+}
+
+func TestParse(t *testing.T) {
+	p, err := Parse([]byte("max_brittle: 0\nfail_on_vuln: high\nmin_health: 40\ndeny_licenses: [GPL-*]\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if p.MaxBrittle == nil || *p.MaxBrittle != 0 || p.FailOnVuln != "high" || p.MinHealth != 40 || len(p.DenyLicenses) != 1 {
+		t.Errorf("Parse = %+v", p)
+	}
+
+	for _, bad := range []string{
+		"max_brittle: -1",
+		"fail_on_vuln: severe",
+		"min_health: 101",
+		"deny_licenses: ['[']",
+		"deny_license_risk: [permissive]",
+		"max_depth: -2",
+		"max_nodes: -1",
+		"max_brittle: [",
+	} {
+		if _, err := Parse([]byte(bad)); err == nil {
+			t.Errorf("Parse(%q) should fail", bad)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	zero, two := 0, 2
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name       string
+		policy     Policy
+		passed     bool
+		violations []string
+	}{
+		{"empty", Policy{}, true, nil},
+		{"brittle within limit", Policy{MaxBrittle: &two}, true, nil},
+		{"brittle over limit", Policy{MaxBrittle: &zero}, false, []string{"archived"}},
+		{"critical only", Policy{FailOnVuln: "critical"}, true, nil},
+		{"high and above", Policy{FailOnVuln: "high"}, false, []string{"vulnerable"}},
+		{"min health", Policy{MinHealth: 50}, false, []string{"archived"}},
+		{"deny GPL", Policy{DenyLicenses: []string{"gpl-*"}}, false, []string{"vulnerable"}},
+		{"deny GPL and MIT", Policy{DenyLicenses: []string{"GPL-*", "MIT"}}, false, []string{"archived", "dual", "vulnerable"}},
+		{"deny proprietary", Policy{DenyLicenseRisk: []string{"proprietary"}}, false, []string{"closed"}},
+		{"depth within limit", Policy{MaxDepth: 2}, true, nil},
+		{"depth over limit", Policy{MaxDepth: 1}, false, []string{"closed", "dual"}},
+		{"nodes within limit", Policy{MaxNodes: 4}, true, nil},
+		{"nodes over limit", Policy{MaxNodes: 3}, false, []string{"app"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := Evaluate(testGraph(), tc.policy, now)
+			if res.Passed != tc.passed {
+				t.Errorf("Passed = %v, want %v", res.Passed, tc.passed)
+			}
+			var got []string
+			for _, rr := range res.Rules {
+				for _, v := range rr.Violations {
+					got = append(got, v.Package)
+				}
+			}
+			if len(got) != len(tc.violations) {
+				t.Fatalf("violations = %v, want %v", got, tc.violations)
+			}
+			for i := range got {
+				if got[i] != tc.violations[i] {
+					t.Errorf("violations = %v, want %v", got, tc.violations)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluate_Summary(t *testing.T) {
+	got := Evaluate(testGraph(), Policy{}, time.Now()).Summary
+	want := Summary{Root: "app", Dependencies: 4, Direct: 2, MaxDepth: 2, Brittle: 1, Vulnerable: 1}
+	if got != want {
+		t.Errorf("Summary = %+v, want %+v", got, want)
+	}
+}
+
+func TestEvaluate_OnlyConfiguredRules(t *testing.T) {
+	res := Evaluate(testGraph(), Policy{FailOnVuln: "low", MinHealth: 10}, time.Now())
+	if len(res.Rules) != 2 || res.Rules[0].Rule != RuleFailOnVuln || res.Rules[1].Rule != RuleMinHealth {
+		t.Fatalf("rules = %+v", res.Rules)
+	}
+	if len(res.Failed()) != 1 || res.Failed()[0].Rule != RuleFailOnVuln {
+		t.Errorf("Failed() = %+v", res.Failed())
+	}
+}
+
+func TestLicenseDenied(t *testing.T) {
+	deny := []string{"GPL-*"}
+	cases := map[string]bool{
+		"GPL-3.0-only":                   true,
+		"MIT":                            false,
+		"MIT OR GPL-3.0-only":            false,
+		"(GPL-2.0-only OR GPL-3.0-only)": true,
+		"MIT AND GPL-2.0-only":           true,
+		"mit and gpl-2.0-only":           true,
+	}
+	for expr, want := range cases {
+		if got := licenseDenied(expr, deny); got != want {
+			t.Errorf("licenseDenied(%q) = %v, want %v", expr, got, want)
+		}
+	}
+}
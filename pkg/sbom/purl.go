@@ -51,6 +51,8 @@ func purlTypeFromLanguage(language string) string {
 		return "composer"
 	case "java":
 		return "maven"
+	case "dotnet":
+		return "nuget"
 	default:
 		return ""
 	}
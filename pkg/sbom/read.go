@@ -0,0 +1,422 @@
+package sbom
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/dag/transform"
+)
+
+// ErrNotSBOM is returned by [Read] when the input is neither a CycloneDX nor
+// an SPDX document.
+var ErrNotSBOM = errors.New("not a CycloneDX or SPDX document")
+
+// projectRoot is the node added when an SBOM does not name the component it
+// describes, matching the sentinel used for manifest parses.
+const projectRoot = "__project__"
+
+// Detect reports which SBOM format data is in: CycloneDX JSON or XML, or SPDX
+// JSON. It only sniffs the document, so callers can fall back to other
+// formats cheaply.
+func Detect(data []byte) (Format, bool) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("<")) {
+		dec := xml.NewDecoder(bytes.NewReader(trimmed))
+		for {
+			tok, err := dec.Token()
+			if err != nil {
+				return "", false
+			}
+			if el, ok := tok.(xml.StartElement); ok {
+				return FormatCycloneDX, el.Name.Local == "bom"
+			}
+		}
+	}
+	var probe struct {
+		BOMFormat   string `json:"bomFormat"`
+		SPDXVersion string `json:"spdxVersion"`
+	}
+	if json.Unmarshal(trimmed, &probe) != nil {
+		return "", false
+	}
+	switch {
+	case probe.BOMFormat == "CycloneDX":
+		return FormatCycloneDX, true
+	case strings.HasPrefix(probe.SPDXVersion, "SPDX-"):
+		return FormatSPDX, true
+	}
+	return "", false
+}
+
+// Read decodes a CycloneDX (JSON or XML) or SPDX (JSON) document into a
+// dependency graph, so SBOMs produced by tools like syft or trivy can be
+// rendered without re-resolving.
+//
+// Components become nodes with "version", "license", "purl" and, where
+// known, "repo_url" metadata; the graph's "language" is inferred from the
+// package URLs. The described component is the root. Components the SBOM
+// lists without any dependency relationship are attached to the root, and
+// dependency cycles are broken, so the result is always a single DAG.
+func Read(r io.Reader) (*dag.DAG, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read sbom: %w", err)
+	}
+	format, ok := Detect(data)
+	if !ok {
+		return nil, ErrNotSBOM
+	}
+	var b *builder
+	switch format {
+	case FormatSPDX:
+		b, err = readSPDX(data)
+	default:
+		b, err = readCycloneDX(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", format, err)
+	}
+	return b.build()
+}
+
+// =============================================================================
+// CycloneDX
+// =============================================================================
+
+type cdxInBOM struct {
+	Metadata struct {
+		Component *cdxInComponent `json:"component" xml:"component"`
+	} `json:"metadata" xml:"metadata"`
+	Components   []cdxInComponent  `json:"components" xml:"components>component"`
+	Dependencies []cdxInDependency `json:"dependencies" xml:"dependencies>dependency"`
+}
+
+type cdxInComponent struct {
+	BOMRef      string           `json:"bom-ref" xml:"bom-ref,attr"`
+	Group       string           `json:"group" xml:"group"`
+	Name        string           `json:"name" xml:"name"`
+	Version     string           `json:"version" xml:"version"`
+	PURL        string           `json:"purl" xml:"purl"`
+	LicensesJS  []cdxInLicense   `json:"licenses" xml:"-"`
+	LicensesXML cdxInLicensesXML `json:"-" xml:"licenses"`
+	ExtRefs     []cdxExtRef      `json:"externalReferences" xml:"externalReferences>reference"`
+	Components  []cdxInComponent `json:"components" xml:"components>component"`
+}
+
+type cdxInLicense struct {
+	License    *cdxInLicenseDetail `json:"license"`
+	Expression string              `json:"expression"`
+}
+
+type cdxInLicenseDetail struct {
+	ID   string `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name"`
+
+	// Nested accepts the doubled <license><license> wrapping written by
+	// GenerateCycloneDX alongside the schema form.
+	Nested *cdxInLicenseDetail `json:"-" xml:"license"`
+}
+
+type cdxInLicensesXML struct {
+	License    []cdxInLicenseDetail `xml:"license"`
+	Expression string               `xml:"expression"`
+}
+
+type cdxInDependency struct {
+	Ref       string            `json:"ref" xml:"ref,attr"`
+	DependsOn []string          `json:"dependsOn" xml:"-"`
+	Nested    []cdxInDependency `json:"-" xml:"dependency"`
+	Text      string            `json:"-" xml:",chardata"` // <dependency>ref</dependency>, as GenerateCycloneDX writes it
+}
+
+func readCycloneDX(data []byte) (*builder, error) {
+	var bom cdxInBOM
+	var err error
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		err = xml.Unmarshal(data, &bom)
+	} else {
+		err = json.Unmarshal(data, &bom)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	b := newBuilder()
+	if c := bom.Metadata.Component; c != nil {
+		b.root = b.add(c.ref(), c.id(), c.Version, c.license(), c.PURL, c.repoURL())
+	}
+	var walk func([]cdxInComponent)
+	walk = func(cs []cdxInComponent) {
+		for _, c := range cs {
+			b.add(c.ref(), c.id(), c.Version, c.license(), c.PURL, c.repoURL())
+			walk(c.Components)
+		}
+	}
+	walk(bom.Components)
+
+	for _, d := range bom.Dependencies {
+		for _, to := range d.DependsOn {
+			b.link(d.Ref, to)
+		}
+		for _, to := range d.Nested {
+			b.link(d.Ref, cmp.Or(to.Ref, strings.TrimSpace(to.Text)))
+		}
+	}
+	return b, nil
+}
+
+// ref is the key dependencies use to point at the component.
+func (c cdxInComponent) ref() string {
+	if c.BOMRef != "" {
+		return c.BOMRef
+	}
+	return c.id()
+}
+
+// id is the package name as the registry knows it: Maven components join
+// group and artifact with ":" and npm scopes are prefixed to the name.
+func (c cdxInComponent) id() string {
+	switch {
+	case c.Group == "":
+		return c.Name
+	case strings.HasPrefix(c.Group, "@"):
+		return c.Group + "/" + c.Name
+	case strings.HasPrefix(c.PURL, "pkg:npm/"):
+		return "@" + c.Group + "/" + c.Name
+	default:
+		return c.Group + ":" + c.Name
+	}
+}
+
+func (c cdxInComponent) license() string {
+	for _, l := range c.LicensesJS {
+		if l.Expression != "" {
+			return l.Expression
+		}
+		if l.License != nil {
+			if s := cmp.Or(l.License.ID, l.License.Name); s != "" {
+				return s
+			}
+		}
+	}
+	if c.LicensesXML.Expression != "" {
+		return c.LicensesXML.Expression
+	}
+	for _, l := range c.LicensesXML.License {
+		if l.Nested != nil {
+			l = *l.Nested
+		}
+		if s := cmp.Or(l.ID, l.Name); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func (c cdxInComponent) repoURL() string {
+	for _, r := range c.ExtRefs {
+		if r.Type == "vcs" {
+			return r.URL
+		}
+	}
+	return ""
+}
+
+// =============================================================================
+// SPDX
+// =============================================================================
+
+type spdxInDocument struct {
+	DocumentDescribes []string           `json:"documentDescribes"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+func readSPDX(data []byte) (*builder, error) {
+	var doc spdxInDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	b := newBuilder()
+	for _, p := range doc.Packages {
+		license := p.LicenseConcluded
+		if !spdxAsserted(license) {
+			license = p.LicenseDeclared
+		}
+		if !spdxAsserted(license) {
+			license = ""
+		}
+		purl := ""
+		for _, ref := range p.ExternalRefs {
+			if ref.ReferenceType == "purl" {
+				purl = ref.ReferenceLocator
+				break
+			}
+		}
+		b.add(p.SPDXID, p.Name, p.VersionInfo, license, purl, p.Homepage)
+	}
+
+	describes := doc.DocumentDescribes
+	for _, rel := range doc.Relationships {
+		switch typ := rel.Type; {
+		case typ == "DESCRIBES" && rel.Element == "SPDXRef-DOCUMENT":
+			describes = append(describes, rel.Related)
+		case typ == "DESCRIBED_BY" && rel.Related == "SPDXRef-DOCUMENT":
+			describes = append(describes, rel.Element)
+		case typ == "DEPENDS_ON" || typ == "CONTAINS":
+			b.link(rel.Element, rel.Related)
+		case strings.HasSuffix(typ, "DEPENDENCY_OF") || typ == "CONTAINED_BY":
+			b.link(rel.Related, rel.Element)
+		}
+	}
+	for _, ref := range describes {
+		if id, ok := b.ids[ref]; ok {
+			b.root = id
+			break
+		}
+	}
+	return b, nil
+}
+
+// spdxAsserted reports whether an SPDX license field holds an actual value.
+func spdxAsserted(s string) bool {
+	return s != "" && s != "NOASSERTION" && s != "NONE"
+}
+
+// =============================================================================
+// Graph Construction
+// =============================================================================
+
+// builder collects the components and relationships of an SBOM before
+// turning them into a DAG, resolving document references to node IDs.
+type builder struct {
+	ids   map[string]string // Document reference -> node ID
+	nodes []dag.Node
+	edges [][2]string
+	root  string
+	taken map[string]bool
+}
+
+func newBuilder() *builder {
+	return &builder{ids: make(map[string]string), taken: make(map[string]bool)}
+}
+
+// add registers a component and returns its node ID. A name seen before
+// with another version gets "name@version" so both stay visible.
+func (b *builder) add(ref, name, version, license, purl, repoURL string) string {
+	if id, ok := b.ids[ref]; ok {
+		return id
+	}
+	if name == "" {
+		name = ref
+	}
+	id := name
+	if b.taken[id] && version != "" {
+		id = name + "@" + version
+	}
+	if b.taken[id] {
+		b.ids[ref] = id
+		return id
+	}
+	b.taken[id] = true
+	b.ids[ref] = id
+
+	meta := dag.Metadata{"source": "sbom"}
+	for k, v := range map[string]string{"version": version, "license": license, "purl": purl, "repo_url": repoURL} {
+		if v != "" {
+			meta[k] = v
+		}
+	}
+	b.nodes = append(b.nodes, dag.Node{ID: id, Meta: meta})
+	return id
+}
+
+func (b *builder) link(from, to string) {
+	b.edges = append(b.edges, [2]string{from, to})
+}
+
+func (b *builder) build() (*dag.DAG, error) {
+	if len(b.nodes) == 0 {
+		return nil, errors.New("sbom lists no components")
+	}
+	g := dag.New(nil)
+	if lang := languageFromPURLs(b.nodes); lang != "" {
+		g.Meta()["language"] = lang
+	}
+	for _, n := range b.nodes {
+		if err := g.AddNode(n); err != nil {
+			return nil, err
+		}
+	}
+	if b.root == "" {
+		b.root = projectRoot
+		if err := g.AddNode(dag.Node{ID: projectRoot}); err != nil {
+			return nil, err
+		}
+	}
+
+	seen := make(map[[2]string]bool)
+	for _, e := range b.edges {
+		from, okFrom := b.ids[e[0]]
+		to, okTo := b.ids[e[1]]
+		if !okFrom || !okTo || from == to || seen[[2]string{from, to}] {
+			continue
+		}
+		seen[[2]string{from, to}] = true
+		if err := g.AddEdge(dag.Edge{From: from, To: to}); err != nil {
+			return nil, err
+		}
+	}
+	transform.BreakCycles(g)
+
+	// Hang everything the SBOM left unconnected off the root.
+	for _, n := range g.Nodes() {
+		if n.ID != b.root && g.InDegree(n.ID) == 0 {
+			if err := g.AddEdge(dag.Edge{From: b.root, To: n.ID}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	transform.BreakCycles(g)
+	return g, nil
+}
+
+// languageFromPURLs infers the graph language from the most common package
+// URL type, the inverse of [BuildPURL].
+func languageFromPURLs(nodes []dag.Node) string {
+	counts := make(map[string]int)
+	best := ""
+	for _, n := range nodes {
+		purl, _ := n.Meta["purl"].(string)
+		typ, _, ok := strings.Cut(strings.TrimPrefix(purl, "pkg:"), "/")
+		if !ok {
+			continue
+		}
+		lang := languageFromPURLType(strings.ToLower(typ))
+		if lang == "" {
+			continue
+		}
+		counts[lang]++
+		if counts[lang] > counts[best] || (counts[lang] == counts[best] && lang < best) {
+			best = lang
+		}
+	}
+	return best
+}
+
+func languageFromPURLType(typ string) string {
+	for _, lang := range []string{"python", "javascript", "rust", "go", "ruby", "php", "java", "dotnet"} {
+		if purlTypeFromLanguage(lang) == typ {
+			return lang
+		}
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package sbom
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestRead_RoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		gen  func(*dag.DAG, Options) ([]byte, error)
+		enc  Encoding
+	}{
+		{"cyclonedx json", GenerateCycloneDX, EncodingJSON},
+		{"cyclonedx xml", GenerateCycloneDX, EncodingXML},
+		{"spdx", GenerateSPDX, EncodingJSON},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.gen(buildTestGraph(), Options{Encoding: tt.enc, Language: "python"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			g, err := Read(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+
+			if ids := dag.NodeIDs(g.Nodes()); !slices.Equal(sorted(ids), []string{"flask", "markupsafe", "werkzeug"}) {
+				t.Errorf("nodes = %v", ids)
+			}
+			if !slices.Equal(g.Children("flask"), []string{"werkzeug"}) || !slices.Equal(g.Children("werkzeug"), []string{"markupsafe"}) {
+				t.Errorf("edges lost: %v", g.Edges())
+			}
+			if g.Meta()["language"] != "python" {
+				t.Errorf("language = %v, want python", g.Meta()["language"])
+			}
+			w, _ := g.Node("werkzeug")
+			if w.Meta["version"] != "3.1.0" || w.Meta["license"] != "BSD-3-Clause" || w.Meta["purl"] != "pkg:pypi/werkzeug@3.1.0" {
+				t.Errorf("werkzeug metadata = %v", w.Meta)
+			}
+			if w.Meta["source"] != "sbom" {
+				t.Errorf("source = %v, want sbom", w.Meta["source"])
+			}
+		})
+	}
+}
+
+func TestRead_CycloneDXWithoutDependencies(t *testing.T) {
+	doc := `{
+	  "bomFormat": "CycloneDX",
+	  "components": [
+	    {"bom-ref": "a", "group": "org.slf4j", "name": "slf4j-api", "version": "2.0.9", "purl": "pkg:maven/org.slf4j/slf4j-api@2.0.9",
+	     "licenses": [{"expression": "MIT"}]},
+	    {"bom-ref": "b", "group": "com.google.guava", "name": "guava", "version": "33.0.0", "purl": "pkg:maven/com.google.guava/guava@33.0.0"},
+	    {"bom-ref": "c", "group": "com.google.guava", "name": "guava", "version": "31.1", "purl": "pkg:maven/com.google.guava/guava@31.1"}
+	  ]
+	}`
+	g, err := Read(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"com.google.guava:guava", "com.google.guava:guava@31.1", "org.slf4j:slf4j-api"}
+	if got := sorted(g.Children(projectRoot)); !slices.Equal(got, want) {
+		t.Errorf("unconnected components should hang off the project root: %v", got)
+	}
+	if g.Meta()["language"] != "java" {
+		t.Errorf("language = %v, want java", g.Meta()["language"])
+	}
+	if n, _ := g.Node("org.slf4j:slf4j-api"); n.Meta["license"] != "MIT" {
+		t.Errorf("license expression lost: %v", n.Meta)
+	}
+}
+
+func TestRead_SPDXRelationships(t *testing.T) {
+	doc := `{
+	  "spdxVersion": "SPDX-2.3",
+	  "packages": [
+	    {"SPDXID": "SPDXRef-app", "name": "app", "licenseConcluded": "NOASSERTION"},
+	    {"SPDXID": "SPDXRef-lodash", "name": "lodash", "versionInfo": "4.17.21", "licenseDeclared": "MIT"},
+	    {"SPDXID": "SPDXRef-debug", "name": "debug", "versionInfo": "4.3.4"},
+	    {"SPDXID": "SPDXRef-ms", "name": "ms", "versionInfo": "2.1.2"}
+	  ],
+	  "relationships": [
+	    {"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-app"},
+	    {"spdxElementId": "SPDXRef-app", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-lodash"},
+	    {"spdxElementId": "SPDXRef-debug", "relationshipType": "DEV_DEPENDENCY_OF", "relatedSpdxElement": "SPDXRef-app"},
+	    {"spdxElementId": "SPDXRef-debug", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-ms"},
+	    {"spdxElementId": "SPDXRef-ms", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-debug"}
+	  ]
+	}`
+	g, err := Read(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sorted(g.Children("app")); !slices.Equal(got, []string{"debug", "lodash"}) {
+		t.Errorf("app children = %v", got)
+	}
+	if g.InDegree("app") != 0 {
+		t.Error("the described package should be the root")
+	}
+	if g.InDegree("debug") != 1 || !slices.Equal(g.Children("ms"), nil) {
+		t.Errorf("the debug <-> ms cycle should be broken: %v", g.Edges())
+	}
+	if app, _ := g.Node("app"); app.Meta["license"] != nil {
+		t.Errorf("NOASSERTION is not a license: %v", app.Meta)
+	}
+}
+
+func TestRead_NotSBOM(t *testing.T) {
+	for _, doc := range []string{`{"nodes": [], "edges": []}`, `<svg/>`, `not json`} {
+		if _, err := Read(strings.NewReader(doc)); !errors.Is(err, ErrNotSBOM) {
+			t.Errorf("Read(%q) = %v, want ErrNotSBOM", doc, err)
+		}
+	}
+}
+
+func sorted(s []string) []string {
+	s = slices.Clone(s)
+	slices.Sort(s)
+	return s
+}
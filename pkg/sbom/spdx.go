@@ -3,6 +3,7 @@ package sbom
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -32,9 +33,12 @@ type spdxPackage struct {
 	Name             string            `json:"name"`
 	VersionInfo      string            `json:"versionInfo,omitempty"`
 	DownloadLocation string            `json:"downloadLocation"`
+	Homepage         string            `json:"homepage,omitempty"`
+	Summary          string            `json:"summary,omitempty"`
 	FilesAnalyzed    bool              `json:"filesAnalyzed"`
 	LicenseConcluded string            `json:"licenseConcluded,omitempty"`
 	LicenseDeclared  string            `json:"licenseDeclared,omitempty"`
+	LicenseComments  string            `json:"licenseComments,omitempty"`
 	CopyrightText    string            `json:"copyrightText"`
 	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
 }
@@ -52,6 +56,12 @@ type spdxRelationship struct {
 }
 
 // GenerateSPDX builds an SPDX 2.3 SBOM from a DAG.
+//
+// Every package carries the version, license and repository the graph
+// recorded for it: the repository becomes the download location and
+// homepage, and the package summary or description the SPDX summary.
+// Licenses that are not valid SPDX expressions, such as "BSD License",
+// are declared NOASSERTION and kept verbatim in the license comments.
 func GenerateSPDX(g *dag.DAG, opts Options) ([]byte, error) {
 	language := opts.Language
 	if language == "" {
@@ -61,10 +71,6 @@ func GenerateSPDX(g *dag.DAG, opts Options) ([]byte, error) {
 	}
 
 	root := dag.FindRoot(g)
-	rootVersion := ""
-	if n, ok := g.Node(root); ok && n.Meta != nil {
-		rootVersion, _ = n.Meta["version"].(string)
-	}
 
 	toolCreator := "Tool: stacktower"
 	if opts.ToolVersion != "" {
@@ -83,60 +89,29 @@ func GenerateSPDX(g *dag.DAG, opts Options) ([]byte, error) {
 		},
 	}
 
-	// Root package
-	doc.Packages = append(doc.Packages, spdxPackage{
-		SPDXID:           spdxID(root),
-		Name:             root,
-		VersionInfo:      rootVersion,
-		DownloadLocation: "NOASSERTION",
-		CopyrightText:    "NOASSERTION",
-	})
+	// Root package first, then its dependencies
+	ids := newSPDXIDs()
+	if n, ok := g.Node(root); ok {
+		doc.Packages = append(doc.Packages, newSPDXPackage(n, ids.assign(root), language))
+	} else {
+		doc.Packages = append(doc.Packages, spdxPackage{
+			SPDXID:           ids.assign(root),
+			Name:             root,
+			DownloadLocation: "NOASSERTION",
+			CopyrightText:    "NOASSERTION",
+		})
+	}
 	doc.Relationships = append(doc.Relationships, spdxRelationship{
 		Element: "SPDXRef-DOCUMENT",
 		Type:    "DESCRIBES",
-		Related: spdxID(root),
+		Related: ids.of[root],
 	})
 
-	// Dependency packages
 	for _, n := range g.Nodes() {
 		if n.IsSynthetic() || n.ID == "__project__" || n.ID == root {
 			continue
 		}
-
-		version := ""
-		license := ""
-		if n.Meta != nil {
-			version, _ = n.Meta["version"].(string)
-			license, _ = n.Meta["license"].(string)
-		}
-
-		pkg := spdxPackage{
-			SPDXID:           spdxID(n.ID),
-			Name:             n.ID,
-			VersionInfo:      version,
-			DownloadLocation: "NOASSERTION",
-			FilesAnalyzed:    false,
-			CopyrightText:    "NOASSERTION",
-		}
-
-		if license != "" {
-			pkg.LicenseConcluded = license
-			pkg.LicenseDeclared = license
-		} else {
-			pkg.LicenseConcluded = "NOASSERTION"
-			pkg.LicenseDeclared = "NOASSERTION"
-		}
-
-		purl := BuildPURL(language, n.ID, version)
-		if purl != "" {
-			pkg.ExternalRefs = []spdxExternalRef{{
-				ReferenceCategory: "PACKAGE-MANAGER",
-				ReferenceType:     "purl",
-				ReferenceLocator:  purl,
-			}}
-		}
-
-		doc.Packages = append(doc.Packages, pkg)
+		doc.Packages = append(doc.Packages, newSPDXPackage(n, ids.assign(n.ID), language))
 	}
 
 	// Relationships from edges
@@ -150,9 +125,9 @@ func GenerateSPDX(g *dag.DAG, opts Options) ([]byte, error) {
 				continue
 			}
 			doc.Relationships = append(doc.Relationships, spdxRelationship{
-				Element: spdxID(n.ID),
+				Element: ids.of[n.ID],
 				Type:    "DEPENDS_ON",
-				Related: spdxID(child),
+				Related: ids.of[child],
 			})
 		}
 	}
@@ -160,6 +135,108 @@ func GenerateSPDX(g *dag.DAG, opts Options) ([]byte, error) {
 	return json.MarshalIndent(doc, "", "  ")
 }
 
+// newSPDXPackage describes a node as an SPDX package.
+func newSPDXPackage(n *dag.Node, id, language string) spdxPackage {
+	meta := func(key string) string {
+		v, _ := n.Meta[key].(string)
+		return strings.TrimSpace(v)
+	}
+	version := meta("version")
+
+	pkg := spdxPackage{
+		SPDXID:           id,
+		Name:             n.ID,
+		VersionInfo:      version,
+		DownloadLocation: "NOASSERTION",
+		FilesAnalyzed:    false,
+		LicenseConcluded: "NOASSERTION",
+		LicenseDeclared:  "NOASSERTION",
+		CopyrightText:    "NOASSERTION",
+	}
+
+	if repoURL := meta("repo_url"); repoURL != "" {
+		pkg.DownloadLocation = "git+" + strings.TrimSuffix(repoURL, ".git") + ".git"
+		pkg.Homepage = repoURL
+	}
+
+	for _, key := range []string{"summary", "repo_description", "description"} {
+		if v := meta(key); v != "" {
+			pkg.Summary = v
+			break
+		}
+	}
+
+	switch license := meta("license"); {
+	case license == "":
+	case isSPDXExpression(license):
+		pkg.LicenseConcluded = license
+		pkg.LicenseDeclared = license
+	default:
+		pkg.LicenseComments = "License as published: " + license
+	}
+
+	if purl := BuildPURL(language, n.ID, version); purl != "" {
+		pkg.ExternalRefs = []spdxExternalRef{{
+			ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType:     "purl",
+			ReferenceLocator:  purl,
+		}}
+	}
+	return pkg
+}
+
+// isSPDXExpression reports whether s is syntactically an SPDX license
+// expression: license identifiers joined by AND, OR and WITH, optionally
+// grouped with parentheses. It does not check identifiers against the
+// SPDX license list.
+func isSPDXExpression(s string) bool {
+	s = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(s)
+	depth := 0
+	wantID := true
+	for _, tok := range strings.Fields(s) {
+		switch {
+		case tok == "(" && wantID:
+			depth++
+		case tok == ")" && !wantID && depth > 0:
+			depth--
+		case (tok == "AND" || tok == "OR" || tok == "WITH") && !wantID:
+			wantID = true
+		case wantID && spdxLicenseID.MatchString(tok):
+			wantID = false
+		default:
+			return false
+		}
+	}
+	return !wantID && depth == 0
+}
+
+var spdxLicenseID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.\-]*\+?$`)
+
+// spdxIDs hands out SPDX identifiers, keeping them unique when different
+// package names map to the same identifier.
+type spdxIDs struct {
+	of    map[string]string // Node ID -> SPDX ID
+	taken map[string]bool
+}
+
+func newSPDXIDs() *spdxIDs {
+	return &spdxIDs{of: make(map[string]string), taken: make(map[string]bool)}
+}
+
+// assign returns the identifier of a node, allocating one on first use.
+func (s *spdxIDs) assign(name string) string {
+	if id, ok := s.of[name]; ok {
+		return id
+	}
+	id := spdxID(name)
+	for i := 2; s.taken[id]; i++ {
+		id = fmt.Sprintf("%s-%d", spdxID(name), i)
+	}
+	s.taken[id] = true
+	s.of[name] = id
+	return id
+}
+
 // spdxID converts a package name to a valid SPDX identifier.
 func spdxID(name string) string {
 	safe := strings.Map(func(r rune) rune {
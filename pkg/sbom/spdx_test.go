@@ -2,6 +2,7 @@ package sbom
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
@@ -86,3 +87,82 @@ func TestSPDXID(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateSPDX_RepoMetadata(t *testing.T) {
+	g := dag.New(dag.Metadata{"language": "python"})
+	g.AddNode(dag.Node{ID: "flask", Meta: dag.Metadata{
+		"version":  "3.1.0",
+		"license":  "BSD-3-Clause",
+		"repo_url": "https://github.com/pallets/flask",
+		"summary":  "A simple framework for building web applications.",
+	}})
+	g.AddNode(dag.Node{ID: "click", Meta: dag.Metadata{"version": "8.1.7", "license": "BSD License"}})
+	g.AddNode(dag.Node{ID: "a_b", Meta: dag.Metadata{"version": "1.0"}})
+	g.AddNode(dag.Node{ID: "a-b", Meta: dag.Metadata{"version": "1.0"}})
+	g.AddEdge(dag.Edge{From: "flask", To: "click"})
+	g.AddEdge(dag.Edge{From: "flask", To: "a_b"})
+	g.AddEdge(dag.Edge{From: "flask", To: "a-b"})
+
+	data, err := GenerateSPDX(g, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc spdxDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	pkgs := make(map[string]spdxPackage)
+	ids := make(map[string]bool)
+	for _, p := range doc.Packages {
+		pkgs[p.Name] = p
+		if ids[p.SPDXID] {
+			t.Errorf("duplicate SPDXID %s", p.SPDXID)
+		}
+		ids[p.SPDXID] = true
+	}
+
+	flask := pkgs["flask"]
+	if flask.DownloadLocation != "git+https://github.com/pallets/flask.git" {
+		t.Errorf("flask downloadLocation = %q", flask.DownloadLocation)
+	}
+	if flask.Homepage != "https://github.com/pallets/flask" {
+		t.Errorf("flask homepage = %q", flask.Homepage)
+	}
+	if flask.Summary == "" || flask.LicenseDeclared != "BSD-3-Clause" {
+		t.Errorf("root package lost its metadata: %+v", flask)
+	}
+
+	click := pkgs["click"]
+	if click.LicenseDeclared != "NOASSERTION" || !strings.Contains(click.LicenseComments, "BSD License") {
+		t.Errorf("click license = %q, comments %q", click.LicenseDeclared, click.LicenseComments)
+	}
+
+	for _, rel := range doc.Relationships {
+		if !ids[rel.Related] {
+			t.Errorf("relationship %s %s points at unknown %s", rel.Element, rel.Type, rel.Related)
+		}
+	}
+}
+
+func TestIsSPDXExpression(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"MIT", true},
+		{"Apache-2.0 OR MIT", true},
+		{"(MIT OR Apache-2.0) AND BSD-3-Clause", true},
+		{"GPL-2.0+ WITH Classpath-exception-2.0", true},
+		{"LicenseRef-custom", true},
+		{"BSD License", false},
+		{"MIT OR", false},
+		{"(MIT", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isSPDXExpression(tt.expr); got != tt.want {
+			t.Errorf("isSPDXExpression(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
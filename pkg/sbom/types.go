@@ -1,5 +1,6 @@
 // Package sbom generates standards-compliant Software Bill of Materials
-// from Stacktower dependency graphs.
+// from Stacktower dependency graphs, and reads CycloneDX and SPDX documents
+// back into graphs with [Read].
 package sbom
 
 import "github.com/stacktower-io/stacktower/pkg/security"
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stacktower-io/stacktower/pkg/core/dag"
 	"github.com/stacktower-io/stacktower/pkg/graph"
+	"github.com/stacktower-io/stacktower/pkg/integrations"
 )
 
 // Scanner analyzes dependencies for known vulnerabilities.
@@ -94,6 +95,11 @@ type Finding struct {
 // vulnerability severity for a given package. The value is a Severity string.
 const MetaVulnSeverity = "vuln_severity"
 
+// MetaVulnFindings is the dag.Node.Meta key holding the advisories that
+// affect a package, as a list of maps with "id", "severity", "summary" and
+// "fixed" keys. Plain maps keep the value identical after a JSON round trip.
+const MetaVulnFindings = "vuln_findings"
+
 // =============================================================================
 // Graph Integration
 // =============================================================================
@@ -122,12 +128,22 @@ func AnnotateGraph(g *dag.DAG, report *Report) {
 	if len(severities) == 0 {
 		return
 	}
+	findings := make(map[string][]any)
+	for _, f := range report.Findings {
+		findings[f.Package] = append(findings[f.Package], map[string]any{
+			"id":       f.ID,
+			"severity": string(f.Severity),
+			"summary":  f.Summary,
+			"fixed":    f.FixedVersion(),
+		})
+	}
 	for _, n := range g.Nodes() {
 		if sev, ok := severities[n.ID]; ok {
 			if n.Meta == nil {
 				n.Meta = dag.Metadata{}
 			}
 			n.Meta[MetaVulnSeverity] = string(sev)
+			n.Meta[MetaVulnFindings] = findings[n.ID]
 		}
 	}
 }
@@ -149,6 +165,8 @@ func EcosystemFromLanguage(language string) string {
 		return "RubyGems"
 	case "php":
 		return "Packagist"
+	case "dotnet":
+		return "NuGet"
 	default:
 		return language
 	}
@@ -251,8 +269,27 @@ func StripVulnData(g *dag.DAG) {
 	for _, n := range g.Nodes() {
 		if n.Meta != nil {
 			delete(n.Meta, MetaVulnSeverity)
+			delete(n.Meta, MetaVulnFindings)
+		}
+	}
+}
+
+// FixedVersion returns the version to upgrade to: the lowest fix version
+// above the affected version, or the lowest fix when the affected version is
+// unknown. It returns "" when no fix has been published.
+func (f Finding) FixedVersion() string {
+	current := integrations.ParseSemver(f.Version)
+	best := integrations.SemanticVersion{}
+	for _, v := range f.FixVersions {
+		sv := integrations.ParseSemver(v)
+		if f.Version != "" && current.Valid && sv.Compare(current) <= 0 {
+			continue
+		}
+		if best.Original == "" || sv.Compare(best) < 0 {
+			best = sv
 		}
 	}
+	return best.Original
 }
 
 // =============================================================================
@@ -0,0 +1,57 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+)
+
+func TestFinding_FixedVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		fixes   []string
+		want    string
+	}{
+		{"1.4.0", []string{"2.0.1", "1.4.2", "1.3.9"}, "1.4.2"},
+		{"", []string{"2.0.1", "1.4.2"}, "1.4.2"},
+		{"3.0.0", []string{"1.4.2"}, ""},
+		{"1.0.0", nil, ""},
+	}
+	for _, tt := range tests {
+		f := Finding{Version: tt.version, FixVersions: tt.fixes}
+		if got := f.FixedVersion(); got != tt.want {
+			t.Errorf("FixedVersion(%q, %v) = %q, want %q", tt.version, tt.fixes, got, tt.want)
+		}
+	}
+}
+
+func TestAnnotateGraph_Findings(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "lib"})
+	_ = g.AddNode(dag.Node{ID: "safe"})
+
+	report := NewReport(2)
+	report.AddFinding(Finding{ID: "GHSA-1", Package: "lib", Version: "1.0.0", Severity: SeverityHigh, FixVersions: []string{"1.0.3"}})
+	report.AddFinding(Finding{ID: "GHSA-2", Package: "lib", Version: "1.0.0", Severity: SeverityLow})
+	AnnotateGraph(g, report)
+
+	lib, _ := g.Node("lib")
+	if lib.Meta[MetaVulnSeverity] != "high" {
+		t.Errorf("severity = %v, want high", lib.Meta[MetaVulnSeverity])
+	}
+	findings, _ := lib.Meta[MetaVulnFindings].([]any)
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %v", lib.Meta[MetaVulnFindings])
+	}
+	if first := findings[0].(map[string]any); first["id"] != "GHSA-1" || first["fixed"] != "1.0.3" {
+		t.Errorf("unexpected finding %v", first)
+	}
+	if safe, _ := g.Node("safe"); len(safe.Meta) != 0 {
+		t.Errorf("unaffected nodes should be left alone: %v", safe.Meta)
+	}
+
+	StripVulnData(g)
+	if _, ok := lib.Meta[MetaVulnFindings]; ok {
+		t.Error("StripVulnData should remove findings")
+	}
+}
@@ -0,0 +1,95 @@
+// Package server exposes the parse → layout → export pipeline as an HTTP
+// API, so teams can run Stacktower as a shared internal service.
+//
+// # Jobs
+//
+// Pipeline runs can take minutes, so every run is an asynchronous job. A
+// submission is validated, queued and answered at once with 202 Accepted,
+// the job's state and a Location header. Clients poll the job until its
+// status is final and then download its artifacts:
+//
+//	POST /api/v1/parse  {"language": "python", "package": "requests"}
+//	→ 202 {"id": "A", "kind": "parse", "status": "queued", ...}
+//
+//	GET /api/v1/jobs/A
+//	→ 200 {"id": "A", "status": "succeeded", "artifacts": [{"name": "graph.json", "url": ...}]}
+//
+//	GET /api/v1/jobs/A/artifacts/graph.json
+//
+// Jobs chain: a layout or export job can start from the graph of another
+// job (graph_job) and an export job from its layout (layout_job). The
+// earlier job may still be running, so a client can submit a whole chain
+// without waiting:
+//
+//	POST /api/v1/layout {"graph_job": "A", "viz_type": "nodelink"}  → job B
+//	POST /api/v1/export {"layout_job": "B", "formats": ["svg", "html"]}  → job C
+//
+// Without an input, layout and export jobs parse first, and a graph can
+// also be posted inline under "graph". Request bodies otherwise carry
+// [pipeline.Options] in their JSON form; unset options take the
+// [pipeline.PresetAPI] defaults.
+//
+// # Routes
+//
+//	POST   /api/v1/parse                        graph.json
+//	POST   /api/v1/layout                       layout.json
+//	POST   /api/v1/export                       <viz_type>.<format> per format
+//	GET    /api/v1/jobs                         all jobs, newest first
+//	GET    /api/v1/jobs/{id}                    job status
+//	DELETE /api/v1/jobs/{id}                    cancel and discard a job
+//	GET    /api/v1/jobs/{id}/artifacts/{name}   download an artifact
+//	POST   /api/v1/projects                     register a monitored project
+//	GET    /api/v1/projects                     all projects
+//	GET    /api/v1/projects/{id}                project and its last check
+//	DELETE /api/v1/projects/{id}                stop monitoring a project
+//	POST   /api/v1/projects/{id}/check          check a project now
+//	GET    /api/v1/openapi.json                 OpenAPI 3.1 description
+//	GET    /healthz                             liveness probe
+//
+// Errors are JSON objects with the codes of package errors:
+//
+//	{"error": {"code": "INVALID_INPUT", "message": "invalid parse options: language is required"}}
+//
+// # Monitoring
+//
+// Registered projects are re-resolved on a schedule (every 24 hours by
+// default), bypassing the cache. Each check is compared with the previous
+// one, and when the dependencies changed materially — a new
+// vulnerability, a newly brittle package, a change in crossing count — the
+// project's webhooks are notified:
+//
+//	POST /api/v1/projects
+//	{"name": "api", "language": "python", "manifest": "...", "manifest_filename": "poetry.lock",
+//	 "interval": "6h", "crossing_threshold": 5,
+//	 "webhooks": [{"url": "https://hooks.slack.com/services/...", "format": "slack"},
+//	              {"url": "https://ci.internal/hook", "secret": "..."}]}
+//
+// Webhooks must reach public addresses: localhost and private, loopback
+// or link-local destinations are refused at registration and when
+// connecting, unless Options.AllowPrivateWebhooks is set.
+// See package monitor for the change detection and webhook payloads.
+// Projects, like jobs, are kept in memory and must be registered again
+// after a restart.
+//
+// # Storage
+//
+// With Options.Store (see package store), the artifacts of succeeded jobs
+// are also written under "jobs/<id>" and stay downloadable after the job
+// expired, and the latest graph of each project is kept under
+// "projects/<id>". Checks compare with the previous graph held in memory,
+// not with the stored one. Deleting a job or project deletes its stored
+// objects.
+//
+// # Deployment
+//
+// Jobs and artifacts are kept in memory until Options.JobTTL after they
+// finish, so a server is a single process; put several behind a load
+// balancer only with sticky routing. Options.Workers bounds concurrent
+// runs. Set Options.Token to require a bearer token. The server never
+// reads files on behalf of clients: manifests are sent as contents and
+// manifest_path is rejected.
+//
+// The stacktower serve command runs a Server with the CLI's cache:
+//
+//	STACKTOWER_API_TOKEN=secret stacktower serve --addr :8080
+package server
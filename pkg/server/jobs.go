@@ -0,0 +1,246 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/errors"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+// =============================================================================
+// Jobs
+// =============================================================================
+
+// Kind names the pipeline stage a job runs up to.
+type Kind string
+
+// Job kinds, one per submission endpoint.
+const (
+	KindParse  Kind = "parse"
+	KindLayout Kind = "layout"
+	KindExport Kind = "export"
+)
+
+// Status is the lifecycle state of a job.
+type Status string
+
+// Job states. Queued jobs wait for the jobs they build on and for a free
+// worker; succeeded, failed and canceled are final.
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCanceled  Status = "canceled"
+)
+
+// Job is the state of a job as returned by the status endpoints.
+type Job struct {
+	ID         string     `json:"id"`
+	Kind       Kind       `json:"kind"`
+	Status     Status     `json:"status"`
+	Error      *Error     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Artifacts  []Artifact `json:"artifacts,omitempty"` // Set once the job succeeded
+}
+
+// Artifact describes one output of a succeeded job.
+type Artifact struct {
+	Name        string `json:"name"` // graph.json, layout.json or <viz_type>.<format>
+	ContentType string `json:"content_type"`
+	Size        int    `json:"size"`
+	URL         string `json:"url"` // Path to download the artifact from
+}
+
+// Error is the machine-readable error of a failed job or request.
+type Error struct {
+	Code    errors.Code `json:"code"`
+	Message string      `json:"message"`
+}
+
+// Final reports whether the job has stopped.
+func (j Job) Final() bool {
+	return j.Status == StatusSucceeded || j.Status == StatusFailed || j.Status == StatusCanceled
+}
+
+// job is a submitted job together with its results.
+type job struct {
+	mu        sync.Mutex
+	view      Job
+	artifacts map[string][]byte
+
+	// Results later jobs can build on.
+	graph     *dag.DAG      // graph the job parsed or was given
+	workGraph *dag.DAG      // graph as prepared for layout
+	layout    *graph.Layout // computed layout
+	vizType   string        // visualization the job was submitted for
+
+	cancel context.CancelFunc
+	done   chan struct{} // closed when the job reaches a final state
+}
+
+// snapshot returns a copy of the job's public state.
+func (j *job) snapshot() Job {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	v := j.view
+	v.Artifacts = slices.Clone(v.Artifacts)
+	return v
+}
+
+// start marks the job as running.
+func (j *job) start() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := time.Now()
+	j.view.Status = StatusRunning
+	j.view.StartedAt = &now
+}
+
+// finish records the outcome of the job and wakes up jobs waiting on it.
+func (j *job) finish(status Status, jobErr *Error) {
+	j.mu.Lock()
+	now := time.Now()
+	j.view.Status = status
+	j.view.Error = jobErr
+	j.view.FinishedAt = &now
+	if status != StatusSucceeded {
+		j.artifacts = nil
+		j.view.Artifacts = nil
+	}
+	j.mu.Unlock()
+	close(j.done)
+}
+
+// artifact returns the named artifact of a succeeded job.
+func (j *job) artifact(name string) (Artifact, []byte, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, a := range j.view.Artifacts {
+		if a.Name == name {
+			return a, j.artifacts[name], true
+		}
+	}
+	return Artifact{}, nil, false
+}
+
+// jobStore holds jobs in memory until they expire.
+type jobStore struct {
+	mu      sync.Mutex
+	jobs    map[string]*job
+	ttl     time.Duration
+	maxJobs int
+}
+
+func newJobStore(ttl time.Duration, maxJobs int) *jobStore {
+	return &jobStore{jobs: make(map[string]*job), ttl: ttl, maxJobs: maxJobs}
+}
+
+// add registers a new queued job. It fails when the store is full of jobs
+// that have not finished yet.
+func (s *jobStore) add(kind Kind, vizType string, cancel context.CancelFunc) (*job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expireLocked(time.Now())
+	if len(s.jobs) >= s.maxJobs && !s.evictOldestLocked() {
+		return nil, errors.New(errors.ErrCodeRateLimited, "too many jobs in progress, retry later")
+	}
+
+	j := &job{
+		view: Job{
+			ID:        rand.Text(),
+			Kind:      kind,
+			Status:    StatusQueued,
+			CreatedAt: time.Now(),
+		},
+		vizType: vizType,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	s.jobs[j.view.ID] = j
+	return j, nil
+}
+
+// get returns the job with the given ID.
+func (s *jobStore) get(id string) (*job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireLocked(time.Now())
+	j, ok := s.jobs[id]
+	return j, ok
+}
+
+// remove deletes a job, canceling it if it is still in progress.
+func (s *jobStore) remove(id string) bool {
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	delete(s.jobs, id)
+	s.mu.Unlock()
+	if ok {
+		j.cancel()
+	}
+	return ok
+}
+
+// list returns all jobs, newest first.
+func (s *jobStore) list() []Job {
+	s.mu.Lock()
+	s.expireLocked(time.Now())
+	jobs := make([]*job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j)
+	}
+	s.mu.Unlock()
+
+	out := make([]Job, len(jobs))
+	for i, j := range jobs {
+		out[i] = j.snapshot()
+	}
+	slices.SortFunc(out, func(a, b Job) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return out
+}
+
+// cancelAll cancels every job in progress.
+func (s *jobStore) cancelAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		j.cancel()
+	}
+}
+
+// expireLocked drops jobs that finished more than the TTL ago.
+func (s *jobStore) expireLocked(now time.Time) {
+	for id, j := range s.jobs {
+		v := j.snapshot()
+		if v.FinishedAt != nil && now.Sub(*v.FinishedAt) > s.ttl {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// evictOldestLocked drops the finished job that finished first, reporting
+// whether there was one.
+func (s *jobStore) evictOldestLocked() bool {
+	var oldest string
+	var oldestAt time.Time
+	for id, j := range s.jobs {
+		v := j.snapshot()
+		if v.FinishedAt != nil && (oldest == "" || v.FinishedAt.Before(oldestAt)) {
+			oldest, oldestAt = id, *v.FinishedAt
+		}
+	}
+	if oldest == "" {
+		return false
+	}
+	delete(s.jobs, oldest)
+	return true
+}
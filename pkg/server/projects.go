@@ -0,0 +1,396 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/errors"
+	"github.com/stacktower-io/stacktower/pkg/monitor"
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+	"github.com/stacktower-io/stacktower/pkg/store"
+)
+
+// =============================================================================
+// Monitored Projects
+// =============================================================================
+
+// Project scheduling limits.
+const (
+	DefaultInterval     = 24 * time.Hour
+	DefaultMaxProjects  = 100
+	DefaultScheduleTick = time.Minute
+	minInterval         = time.Minute
+)
+
+// webhookTimeout bounds a webhook delivery with the default
+// Options.WebhookClient.
+const webhookTimeout = 10 * time.Second
+
+// ProjectRequest is the body of POST /api/v1/projects. It takes the parse
+// options of the project, which is re-resolved on every check.
+type ProjectRequest struct {
+	pipeline.Options
+
+	Name     string            `json:"name"`
+	Interval string            `json:"interval,omitempty"` // Time between checks, e.g. "6h" (default 24h)
+	Webhooks []monitor.Webhook `json:"webhooks,omitempty"` // Notified on material changes
+
+	// CrossingThreshold is the smallest change in crossing count that
+	// triggers a notification (0 = any change).
+	CrossingThreshold int `json:"crossing_threshold,omitempty"`
+}
+
+// Project is the state of a monitored project as returned by the project
+// endpoints. Webhook URLs and secrets are never returned.
+type Project struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Interval  string    `json:"interval"`
+	Webhooks  int       `json:"webhooks"`
+	CreatedAt time.Time `json:"created_at"`
+	NextCheck time.Time `json:"next_check"`
+	LastCheck *Check    `json:"last_check,omitempty"`
+}
+
+// Check is the outcome of one re-resolution of a project.
+type Check struct {
+	At        time.Time        `json:"at"`
+	Error     *Error           `json:"error,omitempty"`
+	Nodes     int              `json:"nodes"`
+	Crossings int              `json:"crossings"`
+	Brittle   int              `json:"brittle"`
+	Changes   *monitor.Changes `json:"changes,omitempty"` // Nil on the first check
+	Notified  bool             `json:"notified"`          // Whether the changes were material
+
+	WebhookErrors []string `json:"webhook_errors,omitempty"`
+}
+
+// project is a registered project and its last snapshot.
+type project struct {
+	mu       sync.Mutex
+	view     Project
+	opts     pipeline.Options
+	interval time.Duration
+	hooks    []monitor.Webhook
+	detect   monitor.Options
+	last     *monitor.Snapshot
+	running  bool
+}
+
+func (p *project) snapshot() Project {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.view
+}
+
+// projects holds the registered projects.
+type projects struct {
+	mu   sync.Mutex
+	byID map[string]*project
+	max  int
+}
+
+func (ps *projects) add(p *project) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if len(ps.byID) >= ps.max {
+		return errors.New(errors.ErrCodeRateLimited, "too many projects registered (limit %d)", ps.max)
+	}
+	ps.byID[p.view.ID] = p
+	return nil
+}
+
+func (ps *projects) get(id string) (*project, bool) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	p, ok := ps.byID[id]
+	return p, ok
+}
+
+func (ps *projects) remove(id string) bool {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	_, ok := ps.byID[id]
+	delete(ps.byID, id)
+	return ok
+}
+
+// all returns the registered projects in registration order.
+func (ps *projects) all() []*project {
+	ps.mu.Lock()
+	out := make([]*project, 0, len(ps.byID))
+	for _, p := range ps.byID {
+		out = append(out, p)
+	}
+	ps.mu.Unlock()
+	slices.SortFunc(out, func(a, b *project) int { return a.view.CreatedAt.Compare(b.view.CreatedAt) })
+	return out
+}
+
+// =============================================================================
+// Handlers
+// =============================================================================
+
+func (s *Server) handleAddProject(w http.ResponseWriter, r *http.Request) {
+	var req ProjectRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.opts.MaxRequestBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, errors.Wrap(errors.ErrCodeInvalidInput, err, "invalid request body"))
+		return
+	}
+	p, err := s.newProject(req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.projects.add(p); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.wake()
+
+	v := p.snapshot()
+	w.Header().Set("Location", projectPath(v.ID))
+	writeJSON(w, http.StatusCreated, v)
+}
+
+func (s *Server) handleListProjects(w http.ResponseWriter, r *http.Request) {
+	all := s.projects.all()
+	out := make([]Project, len(all))
+	for i, p := range all {
+		out[i] = p.snapshot()
+	}
+	writeJSON(w, http.StatusOK, map[string][]Project{"projects": out})
+}
+
+func (s *Server) handleGetProject(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.projects.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, projectNotFound(r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, p.snapshot())
+}
+
+func (s *Server) handleDeleteProject(w http.ResponseWriter, r *http.Request) {
+	if !s.projects.remove(r.PathValue("id")) {
+		writeError(w, projectNotFound(r.PathValue("id")))
+		return
+	}
+	if s.opts.Store != nil {
+		if err := s.opts.Store.Delete(r.Context(), projectStoreID(r.PathValue("id"))); err != nil {
+			s.logger.Warn("failed to delete stored project", "project", r.PathValue("id"), "error", err)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleCheckProject schedules a check of the project right away.
+func (s *Server) handleCheckProject(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.projects.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, projectNotFound(r.PathValue("id")))
+		return
+	}
+	p.mu.Lock()
+	p.view.NextCheck = time.Now()
+	p.mu.Unlock()
+	s.wake()
+	writeJSON(w, http.StatusAccepted, p.snapshot())
+}
+
+// newProject validates a registration.
+func (s *Server) newProject(req ProjectRequest) (*project, error) {
+	if req.Name == "" {
+		return nil, errors.New(errors.ErrCodeInvalidInput, "name is required")
+	}
+	if req.ManifestPath != "" {
+		return nil, errors.New(errors.ErrCodeInvalidInput, "manifest_path is not accepted by the server; send the manifest contents")
+	}
+	opts := req.Options
+	opts.Logger = s.runner.Logger
+	if err := opts.ValidateForParse(); err != nil {
+		return nil, errors.Wrap(errors.ErrCodeInvalidInput, err, "invalid parse options")
+	}
+	if opts.Manifest != "" {
+		if err := errors.ValidateManifestFilename(opts.ManifestFilename); err != nil {
+			return nil, err
+		}
+	} else if err := errors.ValidatePackageName(opts.Package); err != nil {
+		return nil, err
+	}
+
+	interval := DefaultInterval
+	if req.Interval != "" {
+		d, err := time.ParseDuration(req.Interval)
+		if err != nil || d < minInterval {
+			return nil, errors.New(errors.ErrCodeInvalidInput, "invalid interval %q: must be a duration of at least %s", req.Interval, minInterval)
+		}
+		interval = d
+	}
+	for _, h := range req.Webhooks {
+		if err := h.Validate(); err != nil {
+			return nil, errors.Wrap(errors.ErrCodeInvalidInput, err, "invalid webhook")
+		}
+		if s.opts.AllowPrivateWebhooks {
+			continue
+		}
+		if err := h.CheckDestination(); err != nil {
+			return nil, errors.Wrap(errors.ErrCodeInvalidInput, err, "invalid webhook")
+		}
+	}
+
+	now := time.Now()
+	return &project{
+		view: Project{
+			ID:        rand.Text(),
+			Name:      req.Name,
+			Interval:  interval.String(),
+			Webhooks:  len(req.Webhooks),
+			CreatedAt: now,
+			NextCheck: now,
+		},
+		opts:     opts,
+		interval: interval,
+		hooks:    req.Webhooks,
+		detect:   monitor.Options{CrossingThreshold: req.CrossingThreshold},
+	}, nil
+}
+
+// =============================================================================
+// Scheduling
+// =============================================================================
+
+// wake makes the scheduler look for due projects now.
+func (s *Server) wake() {
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// schedule starts checks of due projects until the server is closed.
+func (s *Server) schedule() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.opts.ScheduleTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wakeup:
+		}
+		now := time.Now()
+		for _, p := range s.projects.all() {
+			p.mu.Lock()
+			due := !p.running && !now.Before(p.view.NextCheck)
+			if due {
+				p.running = true
+			}
+			p.mu.Unlock()
+			if due {
+				s.wg.Add(1)
+				go func() {
+					defer s.wg.Done()
+					s.check(s.ctx, p)
+				}()
+			}
+		}
+	}
+}
+
+// check re-resolves a project, compares it with the previous check and
+// notifies the project's webhooks of material changes.
+func (s *Server) check(ctx context.Context, p *project) {
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		return
+	}
+
+	opts := p.opts
+	opts.Refresh = true
+	c := Check{}
+	res, err := s.runner.ParseWithCacheInfo(ctx, opts)
+	c.At = time.Now()
+	var snap monitor.Snapshot
+	if err == nil {
+		snap, err = monitor.Take(res.Graph, c.At)
+	}
+
+	p.mu.Lock()
+	id, name, prev := p.view.ID, p.view.Name, p.last
+	p.mu.Unlock()
+
+	if err != nil {
+		c.Error = &Error{Code: errors.GetCode(err), Message: errors.UserMessage(err)}
+		if c.Error.Code == "" {
+			c.Error.Code = errors.ErrCodeInternal
+		}
+		s.logger.Warn("project check failed", "project", name, "error", err)
+	} else {
+		if s.opts.Store != nil {
+			if err := store.PutGraph(ctx, s.opts.Store, projectStoreID(id), snap.Graph); err != nil {
+				s.logger.Warn("failed to store project graph", "project", name, "error", err)
+			}
+		}
+		c.Nodes, c.Crossings, c.Brittle = snap.Graph.NodeCount(), snap.Crossings, len(snap.Brittle)
+		if prev != nil {
+			changes := monitor.Detect(*prev, snap)
+			c.Changes = &changes
+			if changes.Material(p.detect) {
+				c.Notified = true
+				c.WebhookErrors = s.notify(ctx, p, changes, c.At)
+			}
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err == nil {
+		p.last = &snap
+	}
+	p.view.LastCheck = &c
+	p.view.NextCheck = c.At.Add(p.interval)
+	p.running = false
+}
+
+// notify sends changes to every webhook of p and returns the failures.
+func (s *Server) notify(ctx context.Context, p *project, changes monitor.Changes, at time.Time) []string {
+	e := monitor.Event{
+		Type:      monitor.EventGraphChanged,
+		ProjectID: p.view.ID,
+		Project:   p.view.Name,
+		CheckedAt: at,
+		Changes:   changes,
+	}
+	var failures []string
+	for _, h := range p.hooks {
+		if err := h.Send(ctx, s.opts.WebhookClient, e); err != nil {
+			s.logger.Warn("webhook failed", "project", e.Project, "error", err)
+			failures = append(failures, err.Error())
+		}
+	}
+	return failures
+}
+
+// projectStoreID is the store ID of a project's latest graph.
+func projectStoreID(id string) string {
+	return "projects/" + id
+}
+
+func projectPath(id string) string {
+	return APIPrefix + "/projects/" + id
+}
+
+func projectNotFound(id string) error {
+	return errors.New(errors.ErrCodeNotFound, "project %s not found", id)
+}
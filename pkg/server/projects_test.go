@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/monitor"
+	"github.com/stacktower-io/stacktower/pkg/store"
+)
+
+// projectRequest registers the example poetry.lock, which parses offline.
+func projectRequest(t *testing.T, extra string) string {
+	t.Helper()
+	data, err := os.ReadFile("../../examples/manifest/poetry.lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest, _ := json.Marshal(string(data))
+	return `{"name": "example", "language": "python", "manifest_filename": "poetry.lock", "skip_enrich": true, "manifest": ` + string(manifest) + extra + `}`
+}
+
+// awaitCheck polls a project until it has been checked after since.
+func awaitCheck(t *testing.T, ts *httptest.Server, id string, since time.Time) Check {
+	t.Helper()
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		_, data := do(t, ts, http.MethodGet, projectPath(id), "")
+		var p Project
+		if err := json.Unmarshal(data, &p); err != nil {
+			t.Fatal(err)
+		}
+		if p.LastCheck != nil && p.LastCheck.At.After(since) {
+			return *p.LastCheck
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("project %s was not checked", id)
+	return Check{}
+}
+
+func TestServer_ProjectNotifiesOnChange(t *testing.T) {
+	events := make(chan monitor.Event, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e monitor.Event
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &e)
+		events <- e
+	}))
+	defer hook.Close()
+
+	s := New(newTestRunner(), Options{AllowPrivateWebhooks: true})
+	ts := httptest.NewServer(s)
+	defer func() {
+		ts.Close()
+		s.Close()
+	}()
+
+	start := time.Now()
+	resp, data := do(t, ts, http.MethodPost, "/api/v1/projects", projectRequest(t, `, "interval": "1h", "webhooks": [{"url": "`+hook.URL+`"}]`))
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("register: status %d: %s", resp.StatusCode, data)
+	}
+	var p Project
+	_ = json.Unmarshal(data, &p)
+	if p.Interval != "1h0m0s" || p.Webhooks != 1 || strings.Contains(string(data), hook.URL) {
+		t.Errorf("project = %s", data)
+	}
+
+	// The first check only records a baseline.
+	first := awaitCheck(t, ts, p.ID, start)
+	if first.Error != nil || first.Nodes == 0 || first.Changes != nil || first.Notified {
+		t.Fatalf("first check = %+v", first)
+	}
+
+	// Pretend the previous check saw a more tangled graph.
+	proj, _ := s.projects.get(p.ID)
+	proj.mu.Lock()
+	proj.last = &monitor.Snapshot{Graph: proj.last.Graph, Crossings: proj.last.Crossings + 3}
+	proj.mu.Unlock()
+
+	now := time.Now()
+	if resp, _ := do(t, ts, http.MethodPost, projectPath(p.ID)+"/check", ""); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("check: status %d", resp.StatusCode)
+	}
+	second := awaitCheck(t, ts, p.ID, now)
+	if !second.Notified || second.Changes == nil || len(second.WebhookErrors) > 0 {
+		t.Fatalf("second check = %+v", second)
+	}
+
+	select {
+	case e := <-events:
+		if e.Type != monitor.EventGraphChanged || e.ProjectID != p.ID || e.Changes.CrossingsBefore != e.Changes.CrossingsAfter+3 {
+			t.Errorf("event = %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not called")
+	}
+}
+
+func TestNew_DefaultWebhookClient(t *testing.T) {
+	hook := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer hook.Close()
+
+	s := New(newTestRunner(), Options{})
+	defer s.Close()
+	if s.opts.WebhookClient == nil {
+		t.Fatal("New() left WebhookClient unset, so every delivery builds its own client")
+	}
+	err := monitor.Webhook{URL: hook.URL}.Send(context.Background(), s.opts.WebhookClient, monitor.Event{})
+	if !errors.Is(err, monitor.ErrPrivateDestination) {
+		t.Errorf("Send() to a loopback address error = %v, want %v", err, monitor.ErrPrivateDestination)
+	}
+}
+
+func TestServer_ProjectStore(t *testing.T) {
+	st, err := store.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := newTestServer(t, Options{Store: st})
+
+	start := time.Now()
+	resp, data := do(t, ts, http.MethodPost, "/api/v1/projects", projectRequest(t, ""))
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("register: status %d: %s", resp.StatusCode, data)
+	}
+	var p Project
+	_ = json.Unmarshal(data, &p)
+	if c := awaitCheck(t, ts, p.ID, start); c.Error != nil {
+		t.Fatalf("check = %+v", c)
+	}
+
+	g, err := store.GetGraph(context.Background(), st, projectStoreID(p.ID))
+	if err != nil || g.NodeCount() == 0 {
+		t.Fatalf("stored graph = %v, %v", g, err)
+	}
+	if resp, _ := do(t, ts, http.MethodDelete, projectPath(p.ID), ""); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("delete: status %d", resp.StatusCode)
+	}
+	if names, _ := st.List(context.Background(), projectStoreID(p.ID)); len(names) != 0 {
+		t.Errorf("stored objects after delete = %v", names)
+	}
+}
+
+func TestServer_ProjectBadRequests(t *testing.T) {
+	ts := newTestServer(t, Options{})
+	tests := []struct{ name, body string }{
+		{"no name", `{"language": "python", "package": "requests"}`},
+		{"short interval", `{"name": "x", "language": "python", "package": "requests", "interval": "1s"}`},
+		{"bad webhook", `{"name": "x", "language": "python", "package": "requests", "webhooks": [{"url": "file:///etc/passwd"}]}`},
+		{"private webhook", `{"name": "x", "language": "python", "package": "requests", "webhooks": [{"url": "http://169.254.169.254/latest/meta-data/"}]}`},
+		{"loopback webhook", `{"name": "x", "language": "python", "package": "requests", "webhooks": [{"url": "http://localhost:8080/hook"}]}`},
+		{"no package", `{"name": "x", "language": "python"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp, data := do(t, ts, http.MethodPost, "/api/v1/projects", tt.body); resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400: %s", resp.StatusCode, data)
+			}
+		})
+	}
+
+	if resp, _ := do(t, ts, http.MethodDelete, projectPath("nope"), ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("delete unknown project: status %d, want 404", resp.StatusCode)
+	}
+}
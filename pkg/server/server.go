@@ -0,0 +1,650 @@
+package server
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/errors"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+	"github.com/stacktower-io/stacktower/pkg/monitor"
+	"github.com/stacktower-io/stacktower/pkg/observability"
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+	"github.com/stacktower-io/stacktower/pkg/store"
+)
+
+//go:embed openapi.json
+var openAPI []byte
+
+// APIPrefix is the path prefix of all API routes.
+const APIPrefix = "/api/v1"
+
+// Default server limits.
+const (
+	DefaultWorkers         = 4
+	DefaultJobTTL          = time.Hour
+	DefaultMaxJobs         = 1000
+	DefaultOrderTimeout    = 30 * time.Second
+	DefaultMaxRequestBytes = 32 << 20
+)
+
+// Options configures a [Server]. The zero value selects the defaults.
+type Options struct {
+	Workers         int           // Jobs run at the same time (0 = DefaultWorkers)
+	JobTTL          time.Duration // How long finished jobs and their artifacts are kept (0 = DefaultJobTTL)
+	MaxJobs         int           // Jobs kept at once, in progress or finished (0 = DefaultMaxJobs)
+	OrderTimeout    time.Duration // Time limit of the optimal tower ordering search (0 = DefaultOrderTimeout)
+	MaxRequestBytes int64         // Largest accepted request body (0 = DefaultMaxRequestBytes)
+	MaxProjects     int           // Monitored projects kept at once (0 = DefaultMaxProjects)
+	ScheduleTick    time.Duration // How often the scheduler looks for due project checks (0 = DefaultScheduleTick)
+
+	// WebhookClient delivers project notifications. Nil uses one
+	// [monitor.PublicClient] with a short timeout, or a plain client when
+	// AllowPrivateWebhooks is set.
+	WebhookClient *http.Client
+
+	// AllowPrivateWebhooks accepts webhooks to localhost and to loopback,
+	// link-local and private addresses, for development and tests. Off by
+	// default: any API client can register webhooks, and the server must
+	// not post to its own network on their behalf.
+	AllowPrivateWebhooks bool
+
+	// Token, when set, is required as a bearer token on all API routes.
+	// /healthz stays open for load balancer probes.
+	Token string
+
+	// Logger receives one line per finished job. Nil uses the runner's logger.
+	Logger observability.Logger
+
+	// Store, when set, persists the artifacts of succeeded jobs under
+	// "jobs/<id>" and the latest graph of each project under
+	// "projects/<id>". Artifacts stay downloadable from the store after the
+	// job expired. Projects themselves are not persisted, so their stored
+	// graphs are not read back. The server does not close the store.
+	Store store.Store
+}
+
+// Request is the body of the job submission endpoints. It takes every
+// pipeline option plus at most one input to start from; without an input
+// the job parses the package or manifest named in the options.
+type Request struct {
+	pipeline.Options
+
+	// Graph is a graph JSON document, as produced by a parse job.
+	Graph json.RawMessage `json:"graph,omitempty"`
+
+	// GraphJob starts from the graph of an earlier job. The job may still
+	// be in progress; the new job then waits for it.
+	GraphJob string `json:"graph_job,omitempty"`
+
+	// LayoutJob starts from the layout of an earlier layout or export job,
+	// skipping layout. Export jobs only.
+	LayoutJob string `json:"layout_job,omitempty"`
+}
+
+// Server runs pipeline jobs submitted over HTTP. Jobs run asynchronously:
+// a submission returns a job ID at once, which clients poll until the job
+// finishes and then download its artifacts. Jobs and artifacts live in
+// memory and expire after Options.JobTTL.
+//
+// Registered projects are re-resolved on a schedule, and their webhooks
+// are notified when the dependencies change materially (see package
+// monitor).
+//
+// Server implements [http.Handler]; see the package documentation for the
+// routes.
+type Server struct {
+	runner *pipeline.Runner
+	opts   Options
+	logger observability.Logger
+	jobs   *jobStore
+	slots  chan struct{}
+	mux    *http.ServeMux
+
+	projects *projects
+	wakeup   chan struct{} // signals the scheduler to look for due checks
+
+	ctx  context.Context // parent of all job contexts, canceled by Close
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+// New returns a server that runs jobs with runner.
+func New(runner *pipeline.Runner, opts Options) *Server {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultWorkers
+	}
+	if opts.JobTTL <= 0 {
+		opts.JobTTL = DefaultJobTTL
+	}
+	if opts.MaxJobs <= 0 {
+		opts.MaxJobs = DefaultMaxJobs
+	}
+	if opts.OrderTimeout <= 0 {
+		opts.OrderTimeout = DefaultOrderTimeout
+	}
+	if opts.MaxRequestBytes <= 0 {
+		opts.MaxRequestBytes = DefaultMaxRequestBytes
+	}
+	if opts.MaxProjects <= 0 {
+		opts.MaxProjects = DefaultMaxProjects
+	}
+	if opts.ScheduleTick <= 0 {
+		opts.ScheduleTick = DefaultScheduleTick
+	}
+	if opts.WebhookClient == nil {
+		// One client for all deliveries, so connections are reused
+		if opts.AllowPrivateWebhooks {
+			opts.WebhookClient = &http.Client{Timeout: webhookTimeout}
+		} else {
+			opts.WebhookClient = monitor.PublicClient(webhookTimeout)
+		}
+	}
+
+	s := &Server{
+		runner: runner,
+		opts:   opts,
+		logger: opts.Logger,
+		jobs:   newJobStore(opts.JobTTL, opts.MaxJobs),
+		slots:  make(chan struct{}, opts.Workers),
+		mux:    http.NewServeMux(),
+
+		projects: &projects{byID: make(map[string]*project), max: opts.MaxProjects},
+		wakeup:   make(chan struct{}, 1),
+	}
+	if s.logger == nil {
+		s.logger = runner.Logger
+	}
+	s.ctx, s.stop = context.WithCancel(context.Background())
+
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.HandleFunc("GET "+APIPrefix+"/openapi.json", s.handleOpenAPI)
+	s.mux.HandleFunc("POST "+APIPrefix+"/parse", s.handleSubmit(KindParse))
+	s.mux.HandleFunc("POST "+APIPrefix+"/layout", s.handleSubmit(KindLayout))
+	s.mux.HandleFunc("POST "+APIPrefix+"/export", s.handleSubmit(KindExport))
+	s.mux.HandleFunc("GET "+APIPrefix+"/jobs", s.handleListJobs)
+	s.mux.HandleFunc("GET "+APIPrefix+"/jobs/{id}", s.handleGetJob)
+	s.mux.HandleFunc("DELETE "+APIPrefix+"/jobs/{id}", s.handleDeleteJob)
+	s.mux.HandleFunc("GET "+APIPrefix+"/jobs/{id}/artifacts/{name}", s.handleGetArtifact)
+	s.mux.HandleFunc("POST "+APIPrefix+"/projects", s.handleAddProject)
+	s.mux.HandleFunc("GET "+APIPrefix+"/projects", s.handleListProjects)
+	s.mux.HandleFunc("GET "+APIPrefix+"/projects/{id}", s.handleGetProject)
+	s.mux.HandleFunc("DELETE "+APIPrefix+"/projects/{id}", s.handleDeleteProject)
+	s.mux.HandleFunc("POST "+APIPrefix+"/projects/{id}/check", s.handleCheckProject)
+
+	s.wg.Add(1)
+	go s.schedule()
+	return s
+}
+
+// ServeHTTP implements [http.Handler].
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Token != "" && strings.HasPrefix(r.URL.Path, APIPrefix+"/") && !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="stacktower"`)
+		writeError(w, errors.New(errors.ErrCodeUnauthorized, "missing or invalid bearer token"))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// Close stops the scheduler, cancels all jobs and checks in progress and
+// waits for them to stop.
+func (s *Server) Close() error {
+	s.stop()
+	s.jobs.cancelAll()
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) == 1
+}
+
+// =============================================================================
+// Handlers
+// =============================================================================
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPI)
+}
+
+func (s *Server) handleSubmit(kind Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := s.plan(kind, w, r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		ctx, cancel := context.WithCancel(s.ctx)
+		j, err := s.jobs.add(kind, p.opts.VizType, cancel)
+		if err != nil {
+			cancel()
+			writeError(w, err)
+			return
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer cancel()
+			s.run(ctx, j, p)
+		}()
+
+		v := j.snapshot()
+		w.Header().Set("Location", jobPath(v.ID))
+		writeJSON(w, http.StatusAccepted, v)
+	}
+}
+
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string][]Job{"jobs": s.jobs.list()})
+}
+
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	j, ok := s.jobs.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, jobNotFound(r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, j.snapshot())
+}
+
+func (s *Server) handleDeleteJob(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	removed := s.jobs.remove(id)
+	if s.opts.Store != nil {
+		// Malformed IDs make List fail; nothing can be stored under them.
+		if names, err := s.opts.Store.List(r.Context(), jobStoreID(id)); err == nil && len(names) > 0 {
+			if err := s.opts.Store.Delete(r.Context(), jobStoreID(id)); err != nil {
+				writeError(w, errors.Wrap(errors.ErrCodeInternal, err, "failed to delete stored artifacts"))
+				return
+			}
+			removed = true
+		}
+	}
+	if !removed {
+		writeError(w, jobNotFound(id))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleGetArtifact(w http.ResponseWriter, r *http.Request) {
+	id, name := r.PathValue("id"), r.PathValue("name")
+	j, ok := s.jobs.get(id)
+	if !ok {
+		s.serveStoredArtifact(w, r, id, name)
+		return
+	}
+	a, data, ok := j.artifact(name)
+	if !ok {
+		writeError(w, errors.New(errors.ErrCodeNotFound, "job %s has no artifact %q", id, name))
+		return
+	}
+	serveArtifact(w, r, a, data)
+}
+
+// serveStoredArtifact serves an artifact of a job that is no longer in
+// memory from the store.
+func (s *Server) serveStoredArtifact(w http.ResponseWriter, r *http.Request, id, name string) {
+	if s.opts.Store == nil {
+		writeError(w, jobNotFound(id))
+		return
+	}
+	data, err := s.opts.Store.Get(r.Context(), jobStoreID(id), name)
+	if err != nil {
+		if !stderrors.Is(err, store.ErrNotFound) {
+			s.logger.Warn("failed to read stored artifact", "id", id, "name", name, "error", err)
+		}
+		writeError(w, errors.New(errors.ErrCodeNotFound, "job %s has no artifact %q", id, name))
+		return
+	}
+	serveArtifact(w, r, Artifact{Name: name, ContentType: contentType(name), Size: len(data)}, data)
+}
+
+func serveArtifact(w http.ResponseWriter, r *http.Request, a Artifact, data []byte) {
+	w.Header().Set("Content-Type", a.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", a.Name))
+	http.ServeContent(w, r, a.Name, time.Time{}, bytes.NewReader(data))
+}
+
+// =============================================================================
+// Job Planning
+// =============================================================================
+
+// plan is a validated job submission.
+type plan struct {
+	kind       Kind
+	opts       pipeline.Options
+	graph      *dag.DAG // inline input graph
+	from       *job     // earlier job to build on
+	fromLayout bool     // build on from's layout rather than its graph
+}
+
+// plan decodes and validates a submission, so that bad requests fail with
+// 400 before a job is created.
+func (s *Server) plan(kind Kind, w http.ResponseWriter, r *http.Request) (*plan, error) {
+	var req Request
+	req.ApplyPreset(pipeline.PresetAPI)
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.opts.MaxRequestBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrCodeInvalidInput, err, "invalid request body")
+	}
+
+	p := &plan{kind: kind, opts: req.Options}
+	p.opts.Logger = s.runner.Logger
+	// The server must never read its own files on behalf of a client.
+	if p.opts.ManifestPath != "" {
+		return nil, errors.New(errors.ErrCodeInvalidInput, "manifest_path is not accepted by the server; send the manifest contents")
+	}
+
+	inputs := 0
+	for _, set := range []bool{req.Graph != nil, req.GraphJob != "", req.LayoutJob != ""} {
+		if set {
+			inputs++
+		}
+	}
+	if inputs > 1 {
+		return nil, errors.New(errors.ErrCodeInvalidInput, "graph, graph_job and layout_job are mutually exclusive")
+	}
+
+	switch {
+	case kind == KindParse && inputs > 0:
+		return nil, errors.New(errors.ErrCodeInvalidInput, "parse jobs take a package or manifest, not a graph")
+	case kind == KindLayout && req.LayoutJob != "":
+		return nil, errors.New(errors.ErrCodeInvalidInput, "layout jobs cannot start from a layout")
+	case req.Graph != nil:
+		g, err := graph.ReadGraph(bytes.NewReader(req.Graph))
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrCodeInvalidInput, err, "invalid graph")
+		}
+		p.graph = g
+	case req.GraphJob != "" || req.LayoutJob != "":
+		id := req.GraphJob + req.LayoutJob
+		from, ok := s.jobs.get(id)
+		if !ok {
+			return nil, jobNotFound(id)
+		}
+		if req.LayoutJob != "" && from.snapshot().Kind == KindParse {
+			return nil, errors.New(errors.ErrCodeInvalidInput, "layout_job %s is a parse job", id)
+		}
+		p.from, p.fromLayout = from, req.LayoutJob != ""
+		if p.fromLayout {
+			// The layout fixes the visualization; only its rendering can vary.
+			p.opts.VizType = from.vizType
+		}
+	default:
+		if err := p.opts.ValidateForParse(); err != nil {
+			return nil, errors.Wrap(errors.ErrCodeInvalidInput, err, "invalid parse options")
+		}
+		if p.opts.Package != "" {
+			if err := errors.ValidatePackageName(p.opts.Package); err != nil {
+				return nil, err
+			}
+		}
+		if p.opts.Manifest != "" {
+			if err := errors.ValidateManifestFilename(p.opts.ManifestFilename); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var err error
+	switch kind {
+	case KindLayout:
+		err = p.opts.ValidateForLayout()
+	case KindExport:
+		if err = p.opts.ValidateForLayout(); err == nil {
+			err = p.opts.ValidateForRender()
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeInvalidInput, err, "invalid options")
+	}
+	return p, nil
+}
+
+// =============================================================================
+// Job Execution
+// =============================================================================
+
+// run executes a planned job and records its outcome.
+func (s *Server) run(ctx context.Context, j *job, p *plan) {
+	err := s.wait(ctx, p)
+	if err == nil {
+		select {
+		case s.slots <- struct{}{}:
+			defer func() { <-s.slots }()
+			j.start()
+			err = s.execute(ctx, j, p)
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+
+	v := j.snapshot()
+	switch {
+	case err == nil:
+		j.finish(StatusSucceeded, nil)
+	case ctx.Err() != nil:
+		j.finish(StatusCanceled, &Error{Code: errors.ErrCodeInternal, Message: "job canceled"})
+	default:
+		code := errors.GetCode(err)
+		if code == "" {
+			code = errors.ErrCodeInternal
+		}
+		j.finish(StatusFailed, &Error{Code: code, Message: errors.UserMessage(err)})
+	}
+
+	final := j.snapshot()
+	s.logger.Info("job finished", "id", v.ID, "kind", v.Kind, "status", final.Status,
+		"duration", final.FinishedAt.Sub(v.CreatedAt).Round(time.Millisecond))
+}
+
+// wait blocks until the job p builds on has finished and checks it succeeded.
+func (s *Server) wait(ctx context.Context, p *plan) error {
+	if p.from == nil {
+		return nil
+	}
+	select {
+	case <-p.from.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if v := p.from.snapshot(); v.Status != StatusSucceeded {
+		return errors.New(errors.ErrCodeInvalidInput, "job %s did not succeed (%s)", v.ID, v.Status)
+	}
+	return nil
+}
+
+// execute runs the pipeline stages of a job and stores their outputs.
+// Artifacts cover every stage the job ran, so an export job that parsed
+// also offers graph.json.
+func (s *Server) execute(ctx context.Context, j *job, p *plan) error {
+	opts := p.opts
+	artifacts := make(map[string][]byte)
+
+	g := p.graph
+	var workGraph *dag.DAG
+	var layout *graph.Layout
+	switch {
+	case p.fromLayout:
+		g, workGraph, layout = p.from.graph, p.from.workGraph, p.from.layout
+	case p.from != nil:
+		g = p.from.graph
+	case g == nil:
+		res, err := s.runner.ParseWithCacheInfo(ctx, opts)
+		if err != nil {
+			return err
+		}
+		g = res.Graph
+		data, err := graph.MarshalGraph(g)
+		if err != nil {
+			return err
+		}
+		artifacts[graphArtifact] = data
+	}
+
+	if p.kind != KindParse && layout == nil {
+		var err error
+		if workGraph, err = s.runner.PrepareGraph(g, opts); err != nil {
+			return err
+		}
+		if opts.IsTower() && opts.NeedsOptimalOrderer() {
+			orderer := s.runner.NewOptimalOrderer(s.opts.OrderTimeout)
+			orderer.Restarts, orderer.Seed = opts.Restarts, cmp.Or(opts.Seed, pipeline.DefaultSeed)
+			opts.Orderer = orderer
+		}
+		l, _, err := s.runner.GenerateLayoutWithCacheInfo(ctx, workGraph, opts)
+		if err != nil {
+			return err
+		}
+		data, err := graph.MarshalLayout(l)
+		if err != nil {
+			return err
+		}
+		layout = &l
+		artifacts[layoutArtifact] = data
+	}
+
+	if p.kind == KindExport {
+		rendered, _, err := s.runner.RenderWithCacheInfo(ctx, *layout, workGraph, opts)
+		if err != nil {
+			return err
+		}
+		for format, data := range rendered {
+			artifacts[opts.VizType+"."+format] = data
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.persist(ctx, jobStoreID(j.view.ID), artifacts)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.graph, j.workGraph, j.layout = g, workGraph, layout
+	j.artifacts = artifacts
+	for _, name := range sortedKeys(artifacts) {
+		j.view.Artifacts = append(j.view.Artifacts, Artifact{
+			Name:        name,
+			ContentType: contentType(name),
+			Size:        len(artifacts[name]),
+			URL:         jobPath(j.view.ID) + "/artifacts/" + name,
+		})
+	}
+	return nil
+}
+
+// persist writes objects to the store under id, if there is one. Failures
+// are logged: the results are still served from memory.
+func (s *Server) persist(ctx context.Context, id string, objects map[string][]byte) {
+	if s.opts.Store == nil {
+		return
+	}
+	for _, name := range sortedKeys(objects) {
+		if err := s.opts.Store.Put(ctx, id, name, objects[name]); err != nil {
+			s.logger.Warn("failed to store artifact", "id", id, "name", name, "error", err)
+		}
+	}
+}
+
+// =============================================================================
+// Responses
+// =============================================================================
+
+// Artifact names of the graph and layout stages.
+const (
+	graphArtifact  = "graph.json"
+	layoutArtifact = "layout.json"
+)
+
+// contentTypes maps artifact extensions to their media types.
+var contentTypes = map[string]string{
+	pipeline.FormatSVG:  "image/svg+xml",
+	pipeline.FormatPNG:  "image/png",
+	pipeline.FormatPDF:  "application/pdf",
+	pipeline.FormatJSON: "application/json",
+	pipeline.FormatPPTX: "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	pipeline.FormatHTML: "text/html; charset=utf-8",
+}
+
+// contentType returns the media type of an artifact from its extension,
+// falling back to application/octet-stream.
+func contentType(name string) string {
+	if ct, ok := contentTypes[strings.TrimPrefix(path.Ext(name), ".")]; ok {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+// jobStoreID is the store ID of a job's artifacts.
+func jobStoreID(id string) string {
+	return "jobs/" + id
+}
+
+func jobPath(id string) string {
+	return APIPrefix + "/jobs/" + id
+}
+
+func jobNotFound(id string) error {
+	return errors.New(errors.ErrCodeNotFound, "job %s not found", id)
+}
+
+func sortedKeys(m map[string][]byte) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// statusOf maps error codes to HTTP status codes.
+func statusOf(code errors.Code) int {
+	switch {
+	case strings.HasPrefix(string(code), "INVALID_"):
+		return http.StatusBadRequest
+	case code == errors.ErrCodeNotFound:
+		return http.StatusNotFound
+	case code == errors.ErrCodeUnauthorized:
+		return http.StatusUnauthorized
+	case code == errors.ErrCodeRateLimited:
+		return http.StatusTooManyRequests
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// writeError writes err as {"error": {"code": ..., "message": ...}}.
+func writeError(w http.ResponseWriter, err error) {
+	code := errors.GetCode(err)
+	if code == "" {
+		code = errors.ErrCodeInternal
+	}
+	writeJSON(w, statusOf(code), map[string]Error{"error": {Code: code, Message: errors.UserMessage(err)}})
+}
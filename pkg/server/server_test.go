@@ -0,0 +1,323 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+	"github.com/stacktower-io/stacktower/pkg/store"
+)
+
+func newTestRunner() *pipeline.Runner {
+	return pipeline.NewRunner(cache.NewNullCache(), nil, nil)
+}
+
+func newTestServer(t *testing.T, opts Options) *httptest.Server {
+	t.Helper()
+	s := New(newTestRunner(), opts)
+	ts := httptest.NewServer(s)
+	t.Cleanup(func() {
+		ts.Close()
+		s.Close()
+	})
+	return ts
+}
+
+// inlineRequest returns a request body laying out the diamond example graph.
+func inlineRequest(t *testing.T, extra string) string {
+	t.Helper()
+	data, err := os.ReadFile("../../examples/test/diamond.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf(`{"graph": %s, "ordering": "barycentric"%s}`, data, extra)
+}
+
+func do(t *testing.T, ts *httptest.Server, method, path, body string) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, data
+}
+
+// submit posts a job and returns its ID.
+func submit(t *testing.T, ts *httptest.Server, path, body string) string {
+	t.Helper()
+	resp, data := do(t, ts, http.MethodPost, path, body)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("POST %s: status %d: %s", path, resp.StatusCode, data)
+	}
+	var j Job
+	if err := json.Unmarshal(data, &j); err != nil {
+		t.Fatal(err)
+	}
+	if loc := resp.Header.Get("Location"); loc != jobPath(j.ID) {
+		t.Errorf("Location = %q, want %q", loc, jobPath(j.ID))
+	}
+	return j.ID
+}
+
+// await polls a job until it is final.
+func await(t *testing.T, ts *httptest.Server, id string) Job {
+	t.Helper()
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		resp, data := do(t, ts, http.MethodGet, jobPath(id), "")
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET job: status %d: %s", resp.StatusCode, data)
+		}
+		var j Job
+		if err := json.Unmarshal(data, &j); err != nil {
+			t.Fatal(err)
+		}
+		if j.Final() {
+			return j
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return Job{}
+}
+
+func artifactNames(j Job) []string {
+	var names []string
+	for _, a := range j.Artifacts {
+		names = append(names, a.Name)
+	}
+	return names
+}
+
+func TestServer_LayoutThenExport(t *testing.T) {
+	ts := newTestServer(t, Options{})
+
+	layoutID := submit(t, ts, "/api/v1/layout", inlineRequest(t, ""))
+	// Chained before the layout has finished.
+	exportID := submit(t, ts, "/api/v1/export", `{"layout_job": "`+layoutID+`", "formats": ["svg", "json"]}`)
+
+	lj := await(t, ts, layoutID)
+	if lj.Status != StatusSucceeded {
+		t.Fatalf("layout job %s: %+v", lj.Status, lj.Error)
+	}
+	if got := strings.Join(artifactNames(lj), ","); got != "layout.json" {
+		t.Errorf("layout artifacts = %s, want layout.json", got)
+	}
+
+	ej := await(t, ts, exportID)
+	if ej.Status != StatusSucceeded {
+		t.Fatalf("export job %s: %+v", ej.Status, ej.Error)
+	}
+	if got := strings.Join(artifactNames(ej), ","); got != "tower.json,tower.svg" {
+		t.Errorf("export artifacts = %s, want tower.json,tower.svg", got)
+	}
+
+	resp, data := do(t, ts, http.MethodGet, ej.Artifacts[1].URL, "")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "image/svg+xml" {
+		t.Fatalf("artifact: status %d, type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(string(data), "<svg") || len(data) != ej.Artifacts[1].Size {
+		t.Errorf("artifact is not the SVG of size %d", ej.Artifacts[1].Size)
+	}
+}
+
+func TestServer_ExportInheritsVizType(t *testing.T) {
+	ts := newTestServer(t, Options{})
+	layoutID := submit(t, ts, "/api/v1/layout", inlineRequest(t, `, "viz_type": "treemap"`))
+	ej := await(t, ts, submit(t, ts, "/api/v1/export", `{"layout_job": "`+layoutID+`"}`))
+	if got := strings.Join(artifactNames(ej), ","); got != "treemap.svg" {
+		t.Errorf("artifacts = %s (%+v), want treemap.svg", got, ej.Error)
+	}
+}
+
+func TestServer_BadRequests(t *testing.T) {
+	ts := newTestServer(t, Options{})
+	tests := []struct {
+		name, path, body string
+		status           int
+		code             string
+	}{
+		{"malformed", "/api/v1/parse", `{`, http.StatusBadRequest, "INVALID_INPUT"},
+		{"unknown field", "/api/v1/parse", `{"langauge": "python"}`, http.StatusBadRequest, "INVALID_INPUT"},
+		{"no language", "/api/v1/parse", `{"package": "requests"}`, http.StatusBadRequest, "INVALID_INPUT"},
+		{"manifest path", "/api/v1/parse", `{"language": "python", "manifest_path": "/etc/passwd"}`, http.StatusBadRequest, "INVALID_INPUT"},
+		{"bad filename", "/api/v1/parse", `{"language": "python", "manifest": "x", "manifest_filename": "../x"}`, http.StatusBadRequest, "INVALID_MANIFEST"},
+		{"parse with graph", "/api/v1/parse", `{"graph_job": "x"}`, http.StatusBadRequest, "INVALID_INPUT"},
+		{"unknown job", "/api/v1/export", `{"layout_job": "nope"}`, http.StatusNotFound, "NOT_FOUND"},
+		{"bad format", "/api/v1/export", inlineRequest(t, `, "formats": ["gif"]`), http.StatusBadRequest, "INVALID_INPUT"},
+		{"bad graph", "/api/v1/layout", `{"graph": {"nodes": 1}}`, http.StatusBadRequest, "INVALID_INPUT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, data := do(t, ts, http.MethodPost, tt.path, tt.body)
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d: %s", resp.StatusCode, tt.status, data)
+			}
+			var body struct{ Error Error }
+			if err := json.Unmarshal(data, &body); err != nil {
+				t.Fatal(err)
+			}
+			if string(body.Error.Code) != tt.code {
+				t.Errorf("code = %s, want %s", body.Error.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestServer_FailedDependency(t *testing.T) {
+	ts := newTestServer(t, Options{})
+	// A malformed manifest passes validation but fails when parsed.
+	parseID := submit(t, ts, "/api/v1/parse", `{"language": "python", "manifest": "not toml [", "manifest_filename": "pyproject.toml", "skip_enrich": true}`)
+	layoutID := submit(t, ts, "/api/v1/layout", `{"graph_job": "`+parseID+`"}`)
+
+	if j := await(t, ts, parseID); j.Status != StatusFailed || j.Error == nil {
+		t.Fatalf("parse job = %s, want failed", j.Status)
+	}
+	j := await(t, ts, layoutID)
+	if j.Status != StatusFailed || !strings.Contains(j.Error.Message, parseID) {
+		t.Errorf("layout job = %s %+v, want failed naming the parse job", j.Status, j.Error)
+	}
+}
+
+func TestServer_DeleteAndList(t *testing.T) {
+	ts := newTestServer(t, Options{})
+	id := submit(t, ts, "/api/v1/layout", inlineRequest(t, ""))
+
+	resp, data := do(t, ts, http.MethodGet, "/api/v1/jobs", "")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(data), id) {
+		t.Fatalf("list: status %d: %s", resp.StatusCode, data)
+	}
+
+	if resp, _ := do(t, ts, http.MethodDelete, jobPath(id), ""); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("delete: status %d", resp.StatusCode)
+	}
+	if resp, _ := do(t, ts, http.MethodGet, jobPath(id), ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("get deleted job: status %d, want 404", resp.StatusCode)
+	}
+	if resp, _ := do(t, ts, http.MethodGet, jobPath(id)+"/artifacts/layout.json", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("get artifact of deleted job: status %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestServer_Store(t *testing.T) {
+	st, err := store.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(newTestRunner(), Options{Store: st})
+	ts := httptest.NewServer(s)
+	defer func() {
+		ts.Close()
+		s.Close()
+	}()
+
+	id := submit(t, ts, "/api/v1/layout", inlineRequest(t, ""))
+	if j := await(t, ts, id); j.Status != StatusSucceeded {
+		t.Fatalf("layout job %s: %+v", j.Status, j.Error)
+	}
+	if _, err := store.GetLayout(context.Background(), st, jobStoreID(id)); err != nil {
+		t.Fatalf("layout was not stored: %v", err)
+	}
+
+	// Once the job has left memory, its artifacts come from the store.
+	s.jobs.remove(id)
+	resp, data := do(t, ts, http.MethodGet, jobPath(id)+"/artifacts/layout.json", "")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" || !strings.Contains(string(data), "blocks") {
+		t.Fatalf("stored artifact: status %d: %s", resp.StatusCode, data)
+	}
+	if resp, _ := do(t, ts, http.MethodGet, jobPath(id)+"/artifacts/tower.svg", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("missing stored artifact: status %d, want 404", resp.StatusCode)
+	}
+
+	if resp, _ := do(t, ts, http.MethodDelete, jobPath(id), ""); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("delete stored job: status %d", resp.StatusCode)
+	}
+	if names, _ := st.List(context.Background(), jobStoreID(id)); len(names) != 0 {
+		t.Errorf("stored artifacts after delete = %v", names)
+	}
+	if resp, _ := do(t, ts, http.MethodDelete, jobPath(id), ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("delete deleted job: status %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestServer_Auth(t *testing.T) {
+	ts := newTestServer(t, Options{Token: "secret"})
+
+	if resp, _ := do(t, ts, http.MethodGet, "/api/v1/jobs", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("no token: status %d, want 401", resp.StatusCode)
+	}
+	if resp, _ := do(t, ts, http.MethodGet, "/healthz", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("healthz: status %d, want 200", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/openapi.json", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var doc map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil || doc["openapi"] != "3.1.0" {
+		t.Errorf("openapi.json: status %d, err %v", resp.StatusCode, err)
+	}
+}
+
+func TestContentType(t *testing.T) {
+	for name, want := range map[string]string{
+		"tower.svg":  "image/svg+xml",
+		"graph.json": "application/json",
+		"notes.txt":  "application/octet-stream",
+		"README":     "application/octet-stream",
+		"":           "application/octet-stream",
+	} {
+		if got := contentType(name); got != want {
+			t.Errorf("contentType(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestStore_Limits(t *testing.T) {
+	s := newJobStore(time.Minute, 2)
+	a, _ := s.add(KindParse, "", func() {})
+	if _, err := s.add(KindParse, "", func() {}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.add(KindParse, "", func() {}); err == nil {
+		t.Fatal("store accepted a third job in progress")
+	}
+
+	a.finish(StatusSucceeded, nil)
+	if _, err := s.add(KindParse, "", func() {}); err != nil {
+		t.Fatalf("finished job not evicted: %v", err)
+	}
+	if _, ok := s.get(a.view.ID); ok {
+		t.Error("evicted job still listed")
+	}
+
+	s.ttl = 0
+	b, _ := s.get(s.list()[0].ID)
+	b.finish(StatusFailed, nil)
+	time.Sleep(time.Millisecond)
+	if len(s.list()) != 1 {
+		t.Errorf("expired job kept: %d jobs", len(s.list()))
+	}
+}
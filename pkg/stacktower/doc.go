@@ -0,0 +1,23 @@
+// Package stacktower is the high-level Go API for Stacktower: resolve a
+// package or parse a manifest into a dependency graph, and render the graph
+// as a tower, in one call each.
+//
+// It wraps [pipeline.Runner] with the same defaults as the stacktower CLI,
+// so embedding programs don't need to wire languages, caches, layouts and
+// sinks by hand. Behaviour is adjusted with functional options:
+//
+//	g, err := stacktower.Resolve(ctx, "python", "fastapi", stacktower.WithMaxDepth(3))
+//	if err != nil {
+//	    return err
+//	}
+//	svg, err := stacktower.Render(ctx, g, stacktower.WithStyle("simple"))
+//
+// Manifest files are detected by name:
+//
+//	g, err := stacktower.Parse(ctx, "poetry.lock")
+//	png, err := stacktower.Render(ctx, g, stacktower.WithFormat("png"))
+//
+// Nothing is cached and GitHub enrichment is off unless asked for with
+// [WithCache] and [WithEnrichment]. For options without a dedicated helper,
+// [WithOptions] edits the underlying [pipeline.Options] directly.
+package stacktower
@@ -0,0 +1,39 @@
+package stacktower_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/stacktower"
+)
+
+func ExampleResolve() {
+	ctx := context.Background()
+
+	g, err := stacktower.Resolve(ctx, "python", "requests", stacktower.WithMaxDepth(2))
+	if err != nil {
+		log.Fatal(err)
+	}
+	svg, err := stacktower.Render(ctx, g)
+	if err != nil {
+		log.Fatal(err)
+	}
+	_ = os.WriteFile("requests.svg", svg, 0o644)
+}
+
+func ExampleRender() {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "lib"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "lib"})
+
+	svg, err := stacktower.Render(context.Background(), g, stacktower.WithStyle("simple"), stacktower.WithSize(400, 300))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(svg) > 0)
+	// Output: true
+}
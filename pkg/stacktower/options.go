@@ -0,0 +1,187 @@
+package stacktower
+
+import (
+	"github.com/stacktower-io/stacktower/pkg/cache"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/observability"
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+	"github.com/stacktower-io/stacktower/pkg/security"
+)
+
+// Option configures Resolve, Parse and Render.
+type Option func(*config)
+
+// config is what the options build: pipeline options plus the runner's
+// dependencies.
+type config struct {
+	opts    pipeline.Options
+	cache   cache.Cache
+	logger  observability.Logger
+	scanner security.Scanner
+}
+
+// newConfig applies opts over the CLI's defaults.
+func newConfig(opts []Option) *config {
+	cfg := &config{
+		opts:   pipeline.Options{SkipEnrich: true},
+		logger: observability.NoopLogger{},
+	}
+	cfg.opts.ApplyPreset(pipeline.PresetCLI)
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// runner creates a pipeline runner from the configuration. It is not
+// closed after use, as that would close a cache owned by the caller.
+func (c *config) runner() *pipeline.Runner {
+	return pipeline.NewRunnerWithScanner(c.cache, nil, c.logger, c.scanner)
+}
+
+// WithLanguage sets the language of a manifest for Parse, when it cannot be
+// told from the file name.
+func WithLanguage(name string) Option {
+	return func(c *config) { c.opts.Language = name }
+}
+
+// WithVersion pins the version of the package Resolve starts from.
+func WithVersion(version string) Option {
+	return func(c *config) { c.opts.Version = version }
+}
+
+// WithMaxDepth limits how many levels of dependencies are resolved.
+func WithMaxDepth(depth int) Option {
+	return func(c *config) { c.opts.MaxDepth = depth }
+}
+
+// WithMaxNodes limits how many packages are resolved.
+func WithMaxNodes(nodes int) Option {
+	return func(c *config) { c.opts.MaxNodes = nodes }
+}
+
+// WithRuntimeVersion sets the runtime version used to evaluate environment
+// markers, such as "3.11" for Python.
+func WithRuntimeVersion(version string) Option {
+	return func(c *config) { c.opts.RuntimeVersion = version }
+}
+
+// WithPlatform sets the platform used to evaluate environment markers:
+// "linux" (the default), "darwin" or "windows".
+func WithPlatform(platform string) Option {
+	return func(c *config) { c.opts.Platform = platform }
+}
+
+// WithIndirect follows the requirements go.mod files mark "// indirect",
+// so Go graphs also show the modules reached only through them.
+func WithIndirect() Option {
+	return func(c *config) { c.opts.IncludeIndirect = true }
+}
+
+// WithSparseIndex resolves Rust crates from the sparse crates.io index
+// instead of its API, which is faster and far less rate-limited.
+func WithSparseIndex() Option {
+	return func(c *config) { c.opts.SparseIndex = true }
+}
+
+// WithDevDependencies includes development and test dependencies.
+func WithDevDependencies() Option {
+	return func(c *config) { c.opts.DependencyScope = deps.DependencyScopeAll }
+}
+
+// WithPrerelease lets resolution pick prerelease versions.
+func WithPrerelease() Option {
+	return func(c *config) { c.opts.IncludePrerelease = true }
+}
+
+// WithExclude drops packages matching the glob patterns, together with the
+// dependencies only they pull in.
+func WithExclude(patterns ...string) Option {
+	return func(c *config) { c.opts.Exclude = append(c.opts.Exclude, patterns...) }
+}
+
+// WithInternal marks packages matching the glob patterns as first-party
+// code, on top of those the lock file shows come from the project itself.
+func WithInternal(patterns ...string) Option {
+	return func(c *config) { c.opts.Internal = append(c.opts.Internal, patterns...) }
+}
+
+// WithEnrichment adds GitHub metadata (stars, maintainers, activity) to
+// packages. An empty token falls back to GITHUB_TOKEN, then to
+// unauthenticated requests.
+func WithEnrichment(githubToken string) Option {
+	return func(c *config) {
+		c.opts.SkipEnrich = false
+		c.opts.GitHubToken = githubToken
+	}
+}
+
+// WithSecurityScan scans resolved packages for known vulnerabilities with
+// scanner, such as [security.NewOSVScanner].
+func WithSecurityScan(scanner security.Scanner) Option {
+	return func(c *config) {
+		c.scanner = scanner
+		c.opts.SecurityScan = scanner != nil
+	}
+}
+
+// WithCache caches registry responses, graphs, layouts and renders.
+func WithCache(c cache.Cache) Option {
+	return func(cfg *config) { cfg.cache = c }
+}
+
+// WithLogger receives the pipeline's progress and warnings as structured
+// records, which are discarded by default. A *slog.Logger routes them
+// into any slog handler.
+func WithLogger(logger observability.Logger) Option {
+	return func(c *config) {
+		c.logger = logger
+		c.opts.Logger = logger
+	}
+}
+
+// WithVizType selects the visualization: tower (default), nodelink,
+// sunburst, treemap or dsm.
+func WithVizType(vizType string) Option {
+	return func(c *config) { c.opts.VizType = vizType }
+}
+
+// WithStyle selects the visual style, such as "handdrawn" (default) or
+// "simple".
+func WithStyle(style string) Option {
+	return func(c *config) { c.opts.Style = style }
+}
+
+// WithSize sets the frame size in pixels.
+func WithSize(width, height float64) Option {
+	return func(c *config) {
+		c.opts.Width = width
+		c.opts.Height = height
+	}
+}
+
+// WithFormat sets the output format of Render: svg (default), png, pdf,
+// json or pptx.
+func WithFormat(format string) Option {
+	return func(c *config) { c.opts.Formats = []string{format} }
+}
+
+// WithSeed sets the seed of ordering restarts, the hand-drawn style and
+// width randomization. Renders with the same inputs and seed are
+// byte-identical, unless the ordering search times out.
+func WithSeed(seed uint64) Option {
+	return func(c *config) { c.opts.Seed = seed }
+}
+
+// WithRestarts adds n barycentric ordering runs from shuffled starting
+// orders, seeded by [WithSeed], which can remove crossings the first run
+// leaves at the cost of one run each.
+func WithRestarts(n int) Option {
+	return func(c *config) { c.opts.Restarts = n }
+}
+
+// WithOptions edits the underlying pipeline options, for settings without
+// a dedicated option. It runs in order with the other options.
+func WithOptions(edit func(*pipeline.Options)) Option {
+	return func(c *config) { edit(&c.opts) }
+}
@@ -0,0 +1,87 @@
+package stacktower
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/core/deps"
+	"github.com/stacktower-io/stacktower/pkg/core/deps/languages"
+)
+
+// Resolve fetches the dependency graph of a package from the registry of
+// language, such as "python", "rust" or "javascript". The package may name
+// a version, as in "fastapi@0.110.0"; see also [WithVersion].
+func Resolve(ctx context.Context, language, pkg string, opts ...Option) (*dag.DAG, error) {
+	cfg := newConfig(opts)
+	lang := languages.Find(language)
+	if lang == nil {
+		return nil, fmt.Errorf("unsupported language: %s", language)
+	}
+	cfg.opts.Language = lang.Name
+	pkg, version := deps.ParsePackageSpec(pkg)
+	if version != "" {
+		cfg.opts.Version = version
+	}
+	cfg.opts.Package = pkg
+	if lang.NormalizeName != nil {
+		cfg.opts.Package = lang.NormalizeName(pkg)
+	}
+	return parse(ctx, cfg)
+}
+
+// Parse reads the dependency graph from a manifest or lock file, such as
+// poetry.lock or package.json. The language is detected from the file name
+// and content (see [deps.DetectManifests]) unless set with [WithLanguage].
+func Parse(ctx context.Context, path string, opts ...Option) (*dag.DAG, error) {
+	cfg := newConfig(opts)
+	filename := filepath.Base(path)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.opts.Language == "" {
+		candidates := deps.DetectManifests(filename, content, languages.All)
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("unsupported manifest file: %s", filename)
+		}
+		cfg.opts.Language = candidates[0].Language.Name
+	}
+	cfg.opts.Manifest = string(content)
+	cfg.opts.ManifestFilename = filename
+	cfg.opts.ManifestPath = path
+	return parse(ctx, cfg)
+}
+
+func parse(ctx context.Context, cfg *config) (*dag.DAG, error) {
+	return cfg.runner().Parse(ctx, cfg.opts)
+}
+
+// Render lays out g and renders it in one format, SVG unless set with
+// [WithFormat]. PDF needs rsvg-convert on PATH.
+func Render(ctx context.Context, g *dag.DAG, opts ...Option) ([]byte, error) {
+	cfg := newConfig(opts)
+	if len(cfg.opts.Formats) != 1 {
+		return nil, fmt.Errorf("render one format at a time, got %d", len(cfg.opts.Formats))
+	}
+	if err := cfg.opts.ValidateForRender(); err != nil {
+		return nil, err
+	}
+
+	runner := cfg.runner()
+	workGraph, err := runner.PrepareGraph(g, cfg.opts)
+	if err != nil {
+		return nil, err
+	}
+	layout, err := runner.GenerateLayout(ctx, workGraph, cfg.opts)
+	if err != nil {
+		return nil, fmt.Errorf("layout: %w", err)
+	}
+	artifacts, err := runner.Render(ctx, layout, workGraph, cfg.opts)
+	if err != nil {
+		return nil, fmt.Errorf("render: %w", err)
+	}
+	return artifacts[cfg.opts.Formats[0]], nil
+}
@@ -0,0 +1,105 @@
+package stacktower
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+	"github.com/stacktower-io/stacktower/pkg/pipeline"
+)
+
+func TestParse(t *testing.T) {
+	g, err := Parse(context.Background(), "../../examples/manifest/poetry.lock", WithExclude("pytest*"))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if g.NodeCount() == 0 {
+		t.Fatal("Parse() returned an empty graph")
+	}
+	if lang, _ := g.Meta()["language"].(string); lang != "python" {
+		t.Errorf("language = %q, want python", lang)
+	}
+	for _, n := range g.Nodes() {
+		if strings.HasPrefix(n.ID, "pytest") {
+			t.Errorf("excluded package %s in graph", n.ID)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	dir := t.TempDir()
+	unknown := filepath.Join(dir, "deps.txt")
+	if err := os.WriteFile(unknown, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Parse(context.Background(), unknown); err == nil || !strings.Contains(err.Error(), "unsupported manifest") {
+		t.Errorf("Parse(deps.txt) error = %v", err)
+	}
+	if _, err := Parse(context.Background(), filepath.Join(dir, "poetry.lock")); err == nil {
+		t.Error("Parse() of a missing file succeeded")
+	}
+	if _, err := Resolve(context.Background(), "cobol", "app"); err == nil || !strings.Contains(err.Error(), "unsupported language") {
+		t.Errorf("Resolve(cobol) error = %v", err)
+	}
+}
+
+func testGraph() *dag.DAG {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "web"})
+	_ = g.AddNode(dag.Node{ID: "db"})
+	_ = g.AddNode(dag.Node{ID: "log"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "web"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "db"})
+	_ = g.AddEdge(dag.Edge{From: "web", To: "log"})
+	_ = g.AddEdge(dag.Edge{From: "db", To: "log"})
+	return g
+}
+
+func TestRender(t *testing.T) {
+	ctx := context.Background()
+	g := testGraph()
+
+	svg, err := Render(ctx, g)
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(svg), []byte("<svg")) || !bytes.Contains(svg, []byte(`"web"`)) {
+		t.Errorf("Render() did not produce a tower SVG:\n%.200s", svg)
+	}
+
+	again, err := Render(ctx, g)
+	if err != nil || !bytes.Equal(svg, again) {
+		t.Error("Render() is not reproducible with the default seed")
+	}
+
+	seeded := []Option{WithSeed(7), WithRestarts(4), WithOptions(func(o *pipeline.Options) { o.Ordering = "barycentric" })}
+	first, err := Render(ctx, g, seeded...)
+	if err != nil {
+		t.Fatalf("Render(seeded) error: %v", err)
+	}
+	if second, err := Render(ctx, g, seeded...); err != nil || !bytes.Equal(first, second) {
+		t.Error("Render() is not reproducible with a seed and ordering restarts")
+	}
+
+	data, err := Render(ctx, g, WithVizType(graph.VizTypeSunburst), WithFormat(pipeline.FormatJSON))
+	if err != nil {
+		t.Fatalf("Render(sunburst, json) error: %v", err)
+	}
+	l, err := graph.UnmarshalLayout(data)
+	if err != nil || !l.IsSunburst() {
+		t.Errorf("Render(sunburst, json) = %v, %v", l.VizType, err)
+	}
+
+	if _, err := Render(ctx, g, WithFormat("gif")); err == nil {
+		t.Error("Render() accepted an unknown format")
+	}
+	if _, err := Render(ctx, g, WithOptions(func(o *pipeline.Options) { o.Formats = []string{"svg", "json"} })); err == nil {
+		t.Error("Render() accepted two formats")
+	}
+}
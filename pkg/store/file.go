@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// FileStore keeps each ID as a directory under a root directory, with one
+// file per object: jobs/A/graph.json.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a file store in dir, creating it if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Put writes the object to a temporary file and renames it into place,
+// so readers never see a partial object.
+func (s *FileStore) Put(ctx context.Context, id, name string, data []byte) error {
+	if err := checkKey(id, name); err != nil {
+		return err
+	}
+	dir := s.path(id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+name+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
+
+// Get reads an object.
+func (s *FileStore) Get(ctx context.Context, id, name string) ([]byte, error) {
+	if err := checkKey(id, name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(s.path(id), name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s/%s: %w", id, name, ErrNotFound)
+	}
+	return data, err
+}
+
+// List returns the files directly in the directory of id.
+func (s *FileStore) List(ctx context.Context, id string) ([]string, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(s.path(id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && e.Name()[0] != '.' {
+			names = append(names, e.Name())
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// Delete removes the directory of id with everything in it.
+func (s *FileStore) Delete(ctx context.Context, id string) error {
+	if err := checkID(id); err != nil {
+		return err
+	}
+	return os.RemoveAll(s.path(id))
+}
+
+// Close does nothing for file stores.
+func (s *FileStore) Close() error {
+	return nil
+}
+
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(id))
+}
+
+// Ensure FileStore implements Store.
+var _ Store = (*FileStore)(nil)
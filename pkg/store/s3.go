@@ -0,0 +1,307 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// S3Config configures an [S3Store].
+type S3Config struct {
+	Bucket string // Bucket name (required)
+	Prefix string // Key prefix of all objects, without slashes at the ends (may be empty)
+
+	// Endpoint is the base URL of the service, e.g. "http://localhost:9000"
+	// for MinIO. Empty uses AWS: https://s3.<region>.amazonaws.com.
+	Endpoint string
+
+	Region       string // Signing region (default "us-east-1")
+	AccessKey    string // Access key ID (required)
+	SecretKey    string // Secret access key (required)
+	SessionToken string // Session token of temporary credentials (may be empty)
+
+	// Client sends the requests. Nil uses a client with a 30s timeout.
+	Client *http.Client
+}
+
+// S3Store keeps objects in an S3-compatible bucket under
+// <prefix>/<id>/<name>. Requests are signed with AWS Signature Version 4
+// and address the bucket by path, which every S3-compatible service
+// accepts.
+type S3Store struct {
+	cfg  S3Config
+	base *url.URL // endpoint with the bucket path
+}
+
+// s3Timeout bounds each request of an S3Store with the default client.
+const s3Timeout = 30 * time.Second
+
+// NewS3Store returns a store on the bucket of cfg. It does not contact
+// the service.
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 store: bucket is required")
+	}
+	if cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("s3 store: credentials are required (set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: s3Timeout}
+	}
+	cfg.Prefix = strings.Trim(cfg.Prefix, "/")
+	base, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/") + "/" + cfg.Bucket)
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("s3 store: invalid endpoint %q", cfg.Endpoint)
+	}
+	return &S3Store{cfg: cfg, base: base}, nil
+}
+
+// Put uploads an object.
+func (s *S3Store) Put(ctx context.Context, id, name string, data []byte) error {
+	if err := checkKey(id, name); err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodPut, s.key(id, name), nil, data)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get downloads an object.
+func (s *S3Store) Get(ctx context.Context, id, name string) ([]byte, error) {
+	if err := checkKey(id, name); err != nil {
+		return nil, err
+	}
+	resp, err := s.do(ctx, http.MethodGet, s.key(id, name), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// List lists the objects directly under the prefix of id.
+func (s *S3Store) List(ctx context.Context, id string) ([]string, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+	prefix := s.key(id, "")
+	keys, err := s.list(ctx, prefix, "/")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = strings.TrimPrefix(k, prefix)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// Delete removes every object under the prefix of id, one request each.
+func (s *S3Store) Delete(ctx context.Context, id string) error {
+	if err := checkID(id); err != nil {
+		return err
+	}
+	keys, err := s.list(ctx, s.key(id, ""), "")
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		resp, err := s.do(ctx, http.MethodDelete, k, nil, nil)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+	return nil
+}
+
+// Close does nothing for S3 stores.
+func (s *S3Store) Close() error {
+	return nil
+}
+
+// key returns the object key of name in id; with an empty name, the
+// prefix of the objects of id.
+func (s *S3Store) key(id, name string) string {
+	key := id + "/" + name
+	if s.cfg.Prefix != "" {
+		key = s.cfg.Prefix + "/" + key
+	}
+	return key
+}
+
+// listResult is the response of ListObjectsV2.
+type listResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// list returns the keys starting with prefix, following continuation
+// tokens. A delimiter leaves out keys below the next one.
+func (s *S3Store) list(ctx context.Context, prefix, delimiter string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		q := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if delimiter != "" {
+			q.Set("delimiter", delimiter)
+		}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		resp, err := s.do(ctx, http.MethodGet, "", q, nil)
+		if err != nil {
+			return nil, err
+		}
+		var res listResult
+		err = xml.NewDecoder(resp.Body).Decode(&res)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("s3 list %s: %w", prefix, err)
+		}
+		for _, c := range res.Contents {
+			keys = append(keys, c.Key)
+		}
+		if !res.IsTruncated || res.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = res.NextContinuationToken
+	}
+}
+
+// do sends a signed request for key (the bucket itself when empty) and
+// checks its status. A 404 becomes [ErrNotFound].
+func (s *S3Store) do(ctx context.Context, method, key string, query url.Values, body []byte) (*http.Response, error) {
+	u := *s.base
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = awsEscape(u.Path, false)
+	u.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, body, time.Now().UTC())
+
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s %s: %w", method, key, err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound && key != "":
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %w", key, ErrNotFound)
+	case resp.StatusCode >= 300:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3 %s %s: %s: %s", method, key, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// sign adds the AWS Signature Version 4 headers to req.
+func (s *S3Store) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := amzDate[:8]
+	payload := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payload)
+	signed := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := []string{req.URL.Host, payload, amzDate}
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+		signed = append(signed, "x-amz-security-token")
+		values = append(values, s.cfg.SessionToken)
+	}
+	var headers strings.Builder
+	for i, h := range signed {
+		headers.WriteString(h + ":" + values[i] + "\n")
+	}
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		headers.String(),
+		strings.Join(signed, ";"),
+		payload,
+	}, "\n")
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+
+	key := []byte("AWS4" + s.cfg.SecretKey)
+	for _, part := range []string{date, s.cfg.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, scope, strings.Join(signed, ";"), hex.EncodeToString(hmacSHA256(key, toSign))))
+}
+
+// canonicalQuery encodes q sorted by key, escaped as SigV4 requires.
+func canonicalQuery(q url.Values) string {
+	var parts []string
+	for _, k := range slices.Sorted(maps.Keys(q)) {
+		for _, v := range q[k] {
+			parts = append(parts, awsEscape(k, true)+"="+awsEscape(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsEscape percent-encodes every byte but the unreserved characters, and
+// slashes unless escapeSlash is set.
+func awsEscape(s string, escapeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !escapeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// Ensure S3Store implements Store.
+var _ Store = (*S3Store)(nil)
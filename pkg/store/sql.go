@@ -0,0 +1,110 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// SQLStore keeps objects in one table of an SQLite database:
+//
+//	CREATE TABLE stacktower_objects (
+//	  id   TEXT NOT NULL,
+//	  name TEXT NOT NULL,
+//	  data BLOB NOT NULL,
+//	  PRIMARY KEY (id, name)
+//	)
+//
+// It works through database/sql, so the program chooses the driver
+// (modernc.org/sqlite, github.com/mattn/go-sqlite3, ...). The statements
+// are plain SQL that PostgreSQL accepts too, given a driver that takes "?"
+// placeholders.
+type SQLStore struct {
+	db *sql.DB
+}
+
+// sqlSchema creates the object table if it does not exist.
+const sqlSchema = `CREATE TABLE IF NOT EXISTS stacktower_objects (
+	id   TEXT NOT NULL,
+	name TEXT NOT NULL,
+	data BLOB NOT NULL,
+	PRIMARY KEY (id, name)
+)`
+
+// NewSQLStore returns a store on db, creating its table if needed. The
+// store takes ownership of db and closes it on Close.
+func NewSQLStore(ctx context.Context, db *sql.DB) (*SQLStore, error) {
+	if _, err := db.ExecContext(ctx, sqlSchema); err != nil {
+		return nil, fmt.Errorf("create store table: %w", err)
+	}
+	return &SQLStore{db: db}, nil
+}
+
+// Put inserts or replaces an object.
+func (s *SQLStore) Put(ctx context.Context, id, name string, data []byte) error {
+	if err := checkKey(id, name); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO stacktower_objects (id, name, data) VALUES (?, ?, ?)
+		 ON CONFLICT (id, name) DO UPDATE SET data = excluded.data`,
+		id, name, data)
+	return err
+}
+
+// Get reads an object.
+func (s *SQLStore) Get(ctx context.Context, id, name string) ([]byte, error) {
+	if err := checkKey(id, name); err != nil {
+		return nil, err
+	}
+	var data []byte
+	err := s.db.QueryRowContext(ctx,
+		`SELECT data FROM stacktower_objects WHERE id = ? AND name = ?`,
+		id, name).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s/%s: %w", id, name, ErrNotFound)
+	}
+	return data, err
+}
+
+// List returns the names stored under id.
+func (s *SQLStore) List(ctx context.Context, id string) ([]string, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT name FROM stacktower_objects WHERE id = ? ORDER BY name`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// Delete removes the rows of id and of the IDs below it.
+func (s *SQLStore) Delete(ctx context.Context, id string) error {
+	if err := checkID(id); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM stacktower_objects WHERE id = ? OR substr(id, 1, ?) = ?`,
+		id, len(id)+1, id+"/")
+	return err
+}
+
+// Close closes the database.
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+// Ensure SQLStore implements Store.
+var _ Store = (*SQLStore)(nil)
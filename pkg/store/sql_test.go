@@ -0,0 +1,109 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// memDriver is a database/sql driver that runs exactly the statements of
+// SQLStore against a map, so the store can be tested without SQLite.
+type memDriver struct {
+	mu   sync.Mutex
+	rows map[[2]string][]byte
+}
+
+func init() {
+	sql.Register("stacktower-mem", &memDriver{rows: map[[2]string][]byte{}})
+}
+
+func (d *memDriver) Open(string) (driver.Conn, error) { return memConn{d}, nil }
+
+type memConn struct{ d *memDriver }
+
+func (c memConn) Prepare(query string) (driver.Stmt, error) {
+	return memStmt{c.d, strings.Join(strings.Fields(query), " ")}, nil
+}
+func (c memConn) Close() error              { return nil }
+func (c memConn) Begin() (driver.Tx, error) { return nil, fmt.Errorf("transactions not supported") }
+
+type memStmt struct {
+	d     *memDriver
+	query string
+}
+
+func (s memStmt) Close() error  { return nil }
+func (s memStmt) NumInput() int { return -1 }
+
+func (s memStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS stacktower_objects"):
+	case strings.HasPrefix(s.query, "INSERT INTO stacktower_objects"):
+		s.d.rows[[2]string{args[0].(string), args[1].(string)}] = args[2].([]byte)
+	case strings.HasPrefix(s.query, "DELETE FROM stacktower_objects WHERE id = ? OR substr(id, 1, ?) = ?"):
+		for k := range s.d.rows {
+			if k[0] == args[0] || strings.HasPrefix(k[0], args[2].(string)) && int64(len(args[2].(string))) == args[1] {
+				delete(s.d.rows, k)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unexpected statement %q", s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s memStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	var rows [][]driver.Value
+	switch s.query {
+	case "SELECT data FROM stacktower_objects WHERE id = ? AND name = ?":
+		if data, ok := s.d.rows[[2]string{args[0].(string), args[1].(string)}]; ok {
+			rows = append(rows, []driver.Value{data})
+		}
+	case "SELECT name FROM stacktower_objects WHERE id = ? ORDER BY name":
+		for k := range s.d.rows {
+			if k[0] == args[0] {
+				rows = append(rows, []driver.Value{k[1]})
+			}
+		}
+		slices.SortFunc(rows, func(a, b []driver.Value) int { return strings.Compare(a[0].(string), b[0].(string)) })
+	default:
+		return nil, fmt.Errorf("unexpected query %q", s.query)
+	}
+	return &memRows{rows: rows}, nil
+}
+
+type memRows struct{ rows [][]driver.Value }
+
+func (r *memRows) Columns() []string { return []string{"value"} }
+func (r *memRows) Close() error      { return nil }
+func (r *memRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestSQLStore(t *testing.T) {
+	db, err := sql.Open("stacktower-mem", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := NewSQLStore(context.Background(), db)
+	if err != nil {
+		t.Fatalf("NewSQLStore: %v", err)
+	}
+	defer st.Close()
+	testStore(t, st)
+}
@@ -0,0 +1,195 @@
+// Package store persists the graphs, layouts and rendered artifacts of
+// server jobs and monitored projects, so they outlive the process that
+// produced them.
+//
+// A [Store] holds named objects grouped by an ID, such as "jobs/<job id>"
+// or "projects/<project id>". Objects are opaque bytes; [PutGraph],
+// [GetGraph], [PutLayout] and [GetLayout] store graphs and layouts in
+// their JSON formats under [GraphObject] and [LayoutObject].
+//
+// Three backends are provided:
+//
+//   - [FileStore] keeps each ID as a directory of files
+//   - [S3Store] keeps objects in an S3-compatible bucket (AWS, MinIO, R2)
+//   - [SQLStore] keeps objects in an SQLite table through database/sql
+//
+// [Open] selects one from a URL, as the serve command's --store flag does:
+//
+//	st, _ := store.Open("s3://renders/stacktower")
+//	defer st.Close()
+//	_ = store.PutGraph(ctx, st, "jobs/"+id, g)
+package store
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+// ErrNotFound is returned by [Store.Get] when the object does not exist.
+var ErrNotFound = errors.New("not found")
+
+// Object names of graphs and layouts, as the server names job artifacts.
+const (
+	GraphObject  = "graph.json"
+	LayoutObject = "layout.json"
+)
+
+// Store persists named objects grouped by ID.
+// All methods must be safe for concurrent use.
+type Store interface {
+	// Put stores data as the object name of id, replacing any previous one.
+	Put(ctx context.Context, id, name string, data []byte) error
+
+	// Get returns the object name of id, or an error wrapping
+	// [ErrNotFound] when it does not exist.
+	Get(ctx context.Context, id, name string) ([]byte, error)
+
+	// List returns the names of the objects of id, sorted. An unknown id
+	// has no objects.
+	List(ctx context.Context, id string) ([]string, error)
+
+	// Delete removes every object of id and of the IDs below it
+	// ("jobs/A/x" for "jobs/A"). Not finding any is not an error.
+	Delete(ctx context.Context, id string) error
+
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+// Open returns the store a URL names:
+//
+//	file:///var/lib/stacktower   FileStore in a directory (a plain path works too)
+//	s3://bucket/prefix           S3Store, configured from the AWS_* environment
+//	sqlite:///var/lib/st.db      SQLStore on an SQLite database
+//
+// SQLite needs a database/sql driver registered as "sqlite", such as
+// modernc.org/sqlite, which this package does not import.
+func Open(rawURL string) (Store, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || len(u.Scheme) == 1 {
+		// A plain path; one-letter schemes are Windows drive letters.
+		return NewFileStore(rawURL)
+	}
+	switch u.Scheme {
+	case "file":
+		return NewFileStore(u.Host + u.Path)
+	case "s3":
+		if u.Host == "" {
+			return nil, fmt.Errorf("store URL %s has no bucket", rawURL)
+		}
+		cfg := S3ConfigFromEnv()
+		cfg.Bucket, cfg.Prefix = u.Host, strings.Trim(u.Path, "/")
+		return NewS3Store(cfg)
+	case "sqlite":
+		db, err := sql.Open("sqlite", u.Host+u.Path)
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", rawURL, err)
+		}
+		st, err := NewSQLStore(context.Background(), db)
+		if err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+		return st, nil
+	}
+	return nil, fmt.Errorf("unsupported store URL %s: use a path, file://, s3:// or sqlite://", rawURL)
+}
+
+// S3ConfigFromEnv reads the S3 credentials and endpoint from the standard
+// AWS variables: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY,
+// AWS_SESSION_TOKEN, AWS_REGION (or AWS_DEFAULT_REGION) and
+// AWS_ENDPOINT_URL_S3 (or AWS_ENDPOINT_URL) for S3-compatible services.
+func S3ConfigFromEnv() S3Config {
+	return S3Config{
+		Endpoint:     firstEnv("AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"),
+		Region:       firstEnv("AWS_REGION", "AWS_DEFAULT_REGION"),
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+func firstEnv(keys ...string) string {
+	for _, k := range keys {
+		if v := os.Getenv(k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// PutGraph stores g as the graph object of id.
+func PutGraph(ctx context.Context, s Store, id string, g *dag.DAG) error {
+	data, err := graph.MarshalGraph(g)
+	if err != nil {
+		return err
+	}
+	return s.Put(ctx, id, GraphObject, data)
+}
+
+// GetGraph reads the graph object of id.
+func GetGraph(ctx context.Context, s Store, id string) (*dag.DAG, error) {
+	data, err := s.Get(ctx, id, GraphObject)
+	if err != nil {
+		return nil, err
+	}
+	return graph.ReadGraph(bytes.NewReader(data))
+}
+
+// PutLayout stores l as the layout object of id.
+func PutLayout(ctx context.Context, s Store, id string, l graph.Layout) error {
+	data, err := graph.MarshalLayout(l)
+	if err != nil {
+		return err
+	}
+	return s.Put(ctx, id, LayoutObject, data)
+}
+
+// GetLayout reads the layout object of id.
+func GetLayout(ctx context.Context, s Store, id string) (graph.Layout, error) {
+	data, err := s.Get(ctx, id, LayoutObject)
+	if err != nil {
+		return graph.Layout{}, err
+	}
+	return graph.UnmarshalLayout(data)
+}
+
+// checkID reports IDs that are not slash-separated lists of plain names,
+// so no backend can be led outside its directory, prefix or ID.
+func checkID(id string) error {
+	if id == "" {
+		return errors.New("empty store ID")
+	}
+	for seg := range strings.SplitSeq(id, "/") {
+		if err := checkName(seg); err != nil {
+			return fmt.Errorf("invalid store ID %q", id)
+		}
+	}
+	return nil
+}
+
+// checkName reports object names that are empty, relative or contain a
+// path separator.
+func checkName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\\\x00") {
+		return fmt.Errorf("invalid object name %q", name)
+	}
+	return nil
+}
+
+// checkKey checks the ID and object name of a Put or Get.
+func checkKey(id, name string) error {
+	if err := checkID(id); err != nil {
+		return err
+	}
+	return checkName(name)
+}
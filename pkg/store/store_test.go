@@ -0,0 +1,137 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stacktower-io/stacktower/pkg/core/dag"
+	"github.com/stacktower-io/stacktower/pkg/graph"
+)
+
+// testStore checks the behavior every Store shares.
+func testStore(t *testing.T, st Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := st.Get(ctx, "jobs/A", "graph.json"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of a missing object: err = %v, want ErrNotFound", err)
+	}
+	if names, err := st.List(ctx, "jobs/A"); err != nil || len(names) != 0 {
+		t.Errorf("List of an unknown ID = %v, %v", names, err)
+	}
+
+	for _, obj := range []struct{ id, name, data string }{
+		{"jobs/A", "tower.svg", "<svg/>"},
+		{"jobs/A", "graph.json", "old"},
+		{"jobs/A", "graph.json", "new"},
+		{"jobs/A/x", "nested.json", "{}"},
+		{"jobs/AB", "graph.json", "other"},
+	} {
+		if err := st.Put(ctx, obj.id, obj.name, []byte(obj.data)); err != nil {
+			t.Fatalf("Put(%s, %s): %v", obj.id, obj.name, err)
+		}
+	}
+
+	if data, err := st.Get(ctx, "jobs/A", "graph.json"); err != nil || string(data) != "new" {
+		t.Errorf("Get = %q, %v; want the replaced object", data, err)
+	}
+	names, err := st.List(ctx, "jobs/A")
+	if err != nil || !slices.Equal(names, []string{"graph.json", "tower.svg"}) {
+		t.Errorf("List = %v, %v; want graph.json and tower.svg", names, err)
+	}
+
+	if err := st.Delete(ctx, "jobs/A"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	for _, id := range []string{"jobs/A", "jobs/A/x"} {
+		if names, _ := st.List(ctx, id); len(names) != 0 {
+			t.Errorf("List(%s) after Delete = %v", id, names)
+		}
+	}
+	if data, err := st.Get(ctx, "jobs/AB", "graph.json"); err != nil || string(data) != "other" {
+		t.Errorf("Delete(jobs/A) touched jobs/AB: %q, %v", data, err)
+	}
+	if err := st.Delete(ctx, "jobs/missing"); err != nil {
+		t.Errorf("Delete of an unknown ID: %v", err)
+	}
+
+	for _, key := range [][2]string{{"", "a"}, {"jobs/../x", "a"}, {"jobs//A", "a"}, {"jobs/A", ""}, {"jobs/A", "../a"}, {"jobs/A", `a\b`}} {
+		if err := st.Put(ctx, key[0], key[1], nil); err == nil {
+			t.Errorf("Put(%q, %q) should fail", key[0], key[1])
+		}
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	st, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, st)
+}
+
+func TestGraphAndLayout(t *testing.T) {
+	st, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "app"})
+	_ = g.AddNode(dag.Node{ID: "lib"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "lib"})
+	if err := PutGraph(ctx, st, "projects/P", g); err != nil {
+		t.Fatalf("PutGraph: %v", err)
+	}
+	got, err := GetGraph(ctx, st, "projects/P")
+	if err != nil || got.NodeCount() != 2 || got.EdgeCount() != 1 {
+		t.Errorf("GetGraph = %v, %v", got, err)
+	}
+
+	l := graph.Layout{VizType: graph.VizTypeTower, Width: 400, Height: 300, Blocks: []graph.Block{{ID: "app", Width: 400, Height: 100}}}
+	if err := PutLayout(ctx, st, "projects/P", l); err != nil {
+		t.Fatalf("PutLayout: %v", err)
+	}
+	gotLayout, err := GetLayout(ctx, st, "projects/P")
+	if err != nil || gotLayout.Width != 400 {
+		t.Errorf("GetLayout = %+v, %v", gotLayout, err)
+	}
+	if _, err := GetLayout(ctx, st, "projects/Q"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetLayout of a missing layout: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	for _, u := range []string{dir, "file://" + filepath.ToSlash(dir)} {
+		st, err := Open(u)
+		if err != nil {
+			t.Fatalf("Open(%s): %v", u, err)
+		}
+		if _, ok := st.(*FileStore); !ok {
+			t.Errorf("Open(%s) = %T, want a FileStore", u, st)
+		}
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	st, err := Open("s3://renders/team/")
+	if err != nil {
+		t.Fatalf("Open(s3): %v", err)
+	}
+	s3, ok := st.(*S3Store)
+	if !ok || s3.cfg.Bucket != "renders" || s3.cfg.Prefix != "team" || s3.base.String() != "https://s3.eu-west-1.amazonaws.com/renders" {
+		t.Errorf("Open(s3) = %+v", st)
+	}
+
+	for _, u := range []string{"s3://", "ftp://host/x", "sqlite:///tmp/none.db"} {
+		if _, err := Open(u); err == nil {
+			t.Errorf("Open(%s) should fail", u)
+		}
+	}
+}